@@ -112,6 +112,12 @@ func ldapConnection(config *v3.ActiveDirectoryConfig) (*ldapv3.Conn, error) {
 	}
 
 	servers := config.Servers
+	if config.ServerDiscoveryDomain != "" {
+		servers, err = ldap.DiscoverServers(context.Background(), config.ServerDiscoveryDomain, config.ServerDiscoverySite)
+		if err != nil {
+			return nil, err
+		}
+	}
 	TLS := config.TLS
 	port := config.Port
 	connectionTimeout := config.ConnectionTimeout
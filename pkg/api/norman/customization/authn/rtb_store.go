@@ -7,7 +7,9 @@
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/store/transform"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/convert"
 	"github.com/rancher/norman/types/values"
+	"github.com/rancher/rancher/pkg/auth/provenance"
 	"github.com/rancher/rancher/pkg/auth/providers"
 	"github.com/rancher/rancher/pkg/auth/requests"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
@@ -60,6 +62,15 @@ type Store struct {
 }
 
 func (s *Store) Create(apiContext *types.APIContext, schema *types.Schema, data map[string]interface{}) (map[string]interface{}, error) {
+	source, err := provenance.APISource(convert.ToString(values.GetValueN(data, "labels", provenance.SourceLabel)))
+	if err != nil {
+		return nil, httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+	}
+	values.PutValue(data, source, "labels", provenance.SourceLabel)
+	if creator := apiContext.Request.Header.Get("Impersonate-User"); creator != "" {
+		values.PutValue(data, creator, "annotations", provenance.CreatorAnnotation)
+	}
+
 	if principalID, ok := data[client.ClusterRoleTemplateBindingFieldUserPrincipalID].(string); ok && principalID != "" && !strings.HasPrefix(principalID, "local://") {
 		token, err := s.auth.TokenFromRequest(apiContext.Request)
 		if err != nil {
@@ -1,10 +1,13 @@
 package globalrole
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/rancher/rancher/pkg/features"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"k8s.io/apimachinery/pkg/api/errors"
 )
@@ -14,6 +17,12 @@ type Wrapper struct {
 }
 
 func (w Wrapper) Validator(request *types.APIContext, schema *types.Schema, data map[string]interface{}) error {
+	// The CRTBs created with the namespace selector would grant nothing while the aggregated role templates are enabled.
+	if data[client.GlobalRoleFieldInheritedClusterRolesNamespaceSelector] != nil && features.AggregatedRoleTemplates.Enabled() {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("inheritedClusterRolesNamespaceSelector is not supported while the %s feature is enabled",
+			features.AggregatedRoleTemplates.Name()))
+	}
+
 	if request.Method != http.MethodPut {
 		return nil
 	}
@@ -0,0 +1,34 @@
+package globalrole
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/features"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorNamespaceSelector(t *testing.T) {
+	defer features.AggregatedRoleTemplates.Set(features.AggregatedRoleTemplates.Enabled())
+
+	w := Wrapper{}
+	request := &types.APIContext{Method: http.MethodPost}
+	data := func() map[string]interface{} {
+		return map[string]interface{}{
+			"inheritedClusterRoles":                  []interface{}{"cluster-member"},
+			"inheritedClusterRolesNamespaceSelector": map[string]interface{}{},
+		}
+	}
+
+	features.AggregatedRoleTemplates.Set(false)
+	require.NoError(t, w.Validator(request, nil, data()))
+
+	features.AggregatedRoleTemplates.Set(true)
+	err := w.Validator(request, nil, data())
+	require.Error(t, err)
+	assert.Equal(t, httperror.InvalidBodyContent.Code, err.(*httperror.APIError).Code.Code)
+	require.NoError(t, w.Validator(request, nil, map[string]interface{}{"inheritedClusterRoles": []interface{}{"cluster-member"}}))
+}
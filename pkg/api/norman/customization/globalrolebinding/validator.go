@@ -5,6 +5,7 @@
 
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/auth/bindingpolicy"
 )
 
 func Validator(request *types.APIContext, schema *types.Schema, data map[string]interface{}) error {
@@ -19,5 +20,10 @@ func Validator(request *types.APIContext, schema *types.Schema, data map[string]
 		return httperror.NewAPIError(httperror.InvalidBodyContent, "must contain field [groupPrincipalId] "+
 			"OR field [userId]")
 	}
-	return nil
+
+	binding := bindingpolicy.Binding{Kind: "GlobalRoleBinding"}
+	binding.GlobalRoleName, _ = data["globalRoleId"].(string)
+	binding.UserName, _ = data["userId"].(string)
+	binding.GroupPrincipalName, _ = data["groupPrincipalId"].(string)
+	return bindingpolicy.Enforce(request, binding)
 }
@@ -0,0 +1,194 @@
+package roletemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/clustermanager"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/rbac"
+	"github.com/rancher/rancher/pkg/features"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/project"
+	"github.com/rancher/rancher/pkg/types/config"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RenderAction renders the ClusterRoles, ClusterRoleBindings and RoleBindings a binding of a role template creates in
+// a downstream cluster, so that changes can be reviewed before saving them.
+const RenderAction = "render"
+
+// RenderHandler handles the render action of the role templates.
+type RenderHandler struct {
+	RoleTemplates mgmtcontrollers.RoleTemplateCache
+	Clusters      mgmtcontrollers.ClusterCache
+	Projects      mgmtcontrollers.ProjectCache
+	// ProjectNamespaces returns the names of the namespaces of the project, in the <cluster>:<project> format.
+	ProjectNamespaces func(projectID string) ([]string, error)
+}
+
+// NewRenderHandler returns the handler of the render action, reading the namespaces of the projects from their cluster.
+func NewRenderHandler(management *config.ScaledContext) *RenderHandler {
+	clusterManager := management.ClientGetter.(*clustermanager.Manager)
+	return &RenderHandler{
+		RoleTemplates: management.Wrangler.Mgmt.RoleTemplate().Cache(),
+		Clusters:      management.Wrangler.Mgmt.Cluster().Cache(),
+		Projects:      management.Wrangler.Mgmt.Project().Cache(),
+		ProjectNamespaces: func(projectID string) ([]string, error) {
+			clusterName, _, _ := strings.Cut(projectID, ":")
+			userContext, err := clusterManager.UserContextNoControllers(clusterName)
+			if err != nil {
+				return nil, err
+			}
+			namespaces, err := userContext.Core.Namespaces("").List(metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, namespace := range namespaces.Items {
+				if namespace.DeletionTimestamp == nil && namespace.Annotations[project.ProjectIDAnnotation] == projectID {
+					names = append(names, namespace.Name)
+				}
+			}
+			return names, nil
+		},
+	}
+}
+
+// Formatter adds the render action to the role templates of the users allowed to update them.
+func (h *RenderHandler) Formatter(apiContext *types.APIContext, resource *types.RawResource) {
+	if canRender(apiContext, resource.Values) {
+		resource.AddAction(apiContext, RenderAction)
+	}
+}
+
+func (h *RenderHandler) ActionHandler(actionName string, action *types.Action, apiContext *types.APIContext) error {
+	if actionName != RenderAction {
+		return httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("invalid action %s", actionName))
+	}
+	if !canRender(apiContext, nil) {
+		return httperror.NewAPIError(httperror.PermissionDenied, "Not Allowed")
+	}
+
+	rt, err := h.RoleTemplates.Get(apiContext.ID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("role template %s not found", apiContext.ID))
+		}
+		return httperror.WrapAPIError(err, httperror.ServerError, "failed to get role template")
+	}
+
+	input := &apiv3.RoleTemplateRenderInput{}
+	if err := json.NewDecoder(apiContext.Request.Body).Decode(input); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "invalid render input")
+	}
+	rt = withChanges(rt, input)
+
+	projectID, err := h.target(rt, input)
+	if err != nil {
+		return err
+	}
+	subject, err := renderSubject(apiContext, input)
+	if err != nil {
+		return err
+	}
+
+	var namespaces []string
+	if projectID != "" {
+		if namespaces, err = h.ProjectNamespaces(projectID); err != nil {
+			return httperror.WrapAPIError(err, httperror.ServerError, "failed to list the namespaces of the project")
+		}
+	}
+
+	output, err := rbac.RenderRoleTemplate(h.RoleTemplates.Get, rt, projectID, namespaces, subject)
+	if err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+	}
+	if features.AggregatedRoleTemplates.Enabled() {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("the %s feature is enabled, the objects created in the cluster are aggregated differently", features.AggregatedRoleTemplates.Name()))
+	}
+
+	apiContext.WriteResponse(http.StatusOK, output)
+	return nil
+}
+
+// withChanges returns a copy of the role template with the rules, inherited role templates and excluded rules of the
+// input, if set.
+func withChanges(rt *apiv3.RoleTemplate, input *apiv3.RoleTemplateRenderInput) *apiv3.RoleTemplate {
+	rt = rt.DeepCopy()
+	if input.Rules != nil {
+		rt.Rules = input.Rules
+	}
+	if input.ExternalRules != nil {
+		rt.ExternalRules = input.ExternalRules
+	}
+	if input.RoleTemplateNames != nil {
+		rt.RoleTemplateNames = input.RoleTemplateNames
+	}
+	if input.ExcludedRules != nil {
+		rt.ExcludedRules = input.ExcludedRules
+	}
+	return rt
+}
+
+// target checks the cluster or project of the input, which must match the context of the role template, and returns
+// the project, if any.
+func (h *RenderHandler) target(rt *apiv3.RoleTemplate, input *apiv3.RoleTemplateRenderInput) (string, error) {
+	if (input.ClusterID == "") == (input.ProjectID == "") {
+		return "", httperror.NewAPIError(httperror.InvalidBodyContent, "either clusterId or projectId is required")
+	}
+	if input.ProjectID == "" {
+		if rt.Context == "project" {
+			return "", httperror.NewAPIError(httperror.InvalidBodyContent, "projectId is required for a project role template")
+		}
+		if _, err := h.Clusters.Get(input.ClusterID); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", httperror.NewAPIError(httperror.InvalidReference, fmt.Sprintf("cluster %s not found", input.ClusterID))
+			}
+			return "", httperror.WrapAPIError(err, httperror.ServerError, "failed to get cluster")
+		}
+		return "", nil
+	}
+
+	if rt.Context == "cluster" {
+		return "", httperror.NewAPIError(httperror.InvalidBodyContent, "clusterId is required for a cluster role template")
+	}
+	clusterName, projectName, found := strings.Cut(input.ProjectID, ":")
+	if !found || clusterName == "" || projectName == "" {
+		return "", httperror.NewAPIError(httperror.InvalidBodyContent, "invalid projectId, must be <cluster>:<project>")
+	}
+	if _, err := h.Projects.Get(clusterName, projectName); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", httperror.NewAPIError(httperror.InvalidReference, fmt.Sprintf("project %s not found", input.ProjectID))
+		}
+		return "", httperror.WrapAPIError(err, httperror.ServerError, "failed to get project")
+	}
+	return input.ProjectID, nil
+}
+
+// renderSubject returns the subject of the rendered bindings: the user or group of the input, or the caller.
+func renderSubject(apiContext *types.APIContext, input *apiv3.RoleTemplateRenderInput) (rbacv1.Subject, error) {
+	switch {
+	case input.UserID != "" && input.GroupPrincipalID != "":
+		return rbacv1.Subject{}, httperror.NewAPIError(httperror.InvalidBodyContent, "only one of userId and groupPrincipalId can be set")
+	case input.GroupPrincipalID != "":
+		return rbacv1.Subject{Kind: "Group", Name: input.GroupPrincipalID, APIGroup: rbacv1.GroupName}, nil
+	case input.UserID != "":
+		return rbacv1.Subject{Kind: "User", Name: input.UserID, APIGroup: rbacv1.GroupName}, nil
+	default:
+		return rbacv1.Subject{Kind: "User", Name: apiContext.Request.Header.Get("Impersonate-User"), APIGroup: rbacv1.GroupName}, nil
+	}
+}
+
+// canRender returns true if the user can update the role templates, as rendering one reveals the namespaces of the
+// projects it's rendered for.
+func canRender(apiContext *types.APIContext, values map[string]interface{}) bool {
+	return apiContext.AccessControl.CanDo(v3.RoleTemplateGroupVersionKind.Group, v3.RoleTemplateResource.Name, "update", apiContext, values, apiContext.Schema) == nil
+}
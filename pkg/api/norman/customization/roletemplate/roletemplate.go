@@ -4,11 +4,15 @@
 	"fmt"
 	"net/http"
 
+	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
 	"github.com/rancher/norman/types/convert"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
 	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -32,7 +36,7 @@ func (w Wrapper) Validator(request *types.APIContext, schema *types.Schema, data
 	}
 
 	if request.Method != http.MethodPut {
-		return nil
+		return w.validateExcludedRules(&apiv3.RoleTemplate{}, data)
 	}
 	rt, err := w.RoleTemplateLister.Get("", request.ID)
 	if err != nil {
@@ -49,6 +53,35 @@ func (w Wrapper) Validator(request *types.APIContext, schema *types.Schema, data
 		}
 
 	}
+	return w.validateExcludedRules(rt.DeepCopy(), data)
+}
+
+// validateExcludedRules refuses the excluded rules of the role template, updated with the data, that only exclude part
+// of a wildcard rule it grants or inherits, since such a rule is kept as is.
+func (w Wrapper) validateExcludedRules(rt *apiv3.RoleTemplate, data map[string]interface{}) error {
+	for field, rules := range map[string]*[]rbacv1.PolicyRule{
+		client.RoleTemplateFieldRules:         &rt.Rules,
+		client.RoleTemplateFieldExternalRules: &rt.ExternalRules,
+		client.RoleTemplateFieldExcludedRules: &rt.ExcludedRules,
+	} {
+		if value, ok := data[field]; ok {
+			*rules = nil
+			if err := convert.ToObj(value, rules); err != nil {
+				return httperror.WrapAPIError(err, httperror.InvalidBodyContent, "invalid "+field)
+			}
+		}
+	}
+	if value, ok := data[client.RoleTemplateFieldRoleTemplateIDs]; ok {
+		rt.RoleTemplateNames = convert.ToStringSlice(value)
+	}
+	if value, ok := data[client.RoleTemplateFieldExternal]; ok {
+		rt.External = convert.ToBool(value)
+	}
+	if err := pkgrbac.CheckRuleExclusions(rt, func(name string) (*apiv3.RoleTemplate, error) {
+		return w.RoleTemplateLister.Get("", name)
+	}); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+	}
 	return nil
 }
 
@@ -1,12 +1,18 @@
 package roletemplate
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -81,3 +87,51 @@ func TestDelete(t *testing.T) {
 	assert.Equal(resource.Links["remove"], "")
 	assert.Equal(resource.Links["update"], "/test/link")
 }
+
+// TestValidateExcludedRules confirms that the exclusions of part of an inherited wildcard rule are refused.
+func TestValidateExcludedRules(t *testing.T) {
+	roleTemplates := map[string]*v3.RoleTemplate{
+		"wildcard": {
+			ObjectMeta: v1.ObjectMeta{Name: "wildcard"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+		},
+		"pods": {
+			ObjectMeta:        v1.ObjectMeta{Name: "pods"},
+			RoleTemplateNames: []string{"wildcard"},
+		},
+	}
+	w := Wrapper{RoleTemplateLister: &fakes.RoleTemplateListerMock{
+		GetFunc: func(_, name string) (*v3.RoleTemplate, error) {
+			if rt, ok := roleTemplates[name]; ok {
+				return rt, nil
+			}
+			return nil, apierrors.NewNotFound(v3.RoleTemplateGroupVersionResource.GroupResource(), name)
+		},
+	}}
+	validate := func(method, id string, data map[string]interface{}) error {
+		request := &types.APIContext{Method: method, ID: id, Request: httptest.NewRequest(method, "/v3/roletemplates", nil)}
+		return w.Validator(request, nil, data)
+	}
+	excludedSecrets := []interface{}{
+		map[string]interface{}{"apiGroups": []interface{}{""}, "resources": []interface{}{"secrets"}, "verbs": []interface{}{"*"}},
+	}
+
+	err := validate(http.MethodPost, "", map[string]interface{}{
+		"roleTemplateIds": []interface{}{"wildcard"},
+		"excludedRules":   excludedSecrets,
+	})
+	require.Error(t, err)
+	assert.True(t, httperror.IsAPIError(err))
+
+	// The inherited rules of the updated role template are checked against the excluded rules set by the update.
+	err = validate(http.MethodPut, "pods", map[string]interface{}{"excludedRules": excludedSecrets})
+	require.Error(t, err)
+
+	err = validate(http.MethodPost, "", map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{"apiGroups": []interface{}{""}, "resources": []interface{}{"pods", "secrets"}, "verbs": []interface{}{"get"}},
+		},
+		"excludedRules": excludedSecrets,
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,414 @@
+package roletemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/rbac"
+	"github.com/rancher/rancher/pkg/features"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// SimulateAction lists the existing bindings whose permissions a change of a role template would change, with their
+	// subjects and the rules they'd gain and lose in each cluster, so that changes can be reviewed before saving them.
+	SimulateAction = "simulate"
+
+	roleTemplateKind = "RoleTemplate"
+	globalRoleKind   = "GlobalRole"
+	crtbKind         = "ClusterRoleTemplateBinding"
+	prtbKind         = "ProjectRoleTemplateBinding"
+	grbKind          = "GlobalRoleBinding"
+
+	localCluster = "local"
+)
+
+// SimulateHandler handles the simulate action of the role templates.
+type SimulateHandler struct {
+	RoleTemplates mgmtcontrollers.RoleTemplateCache
+	GlobalRoles   mgmtcontrollers.GlobalRoleCache
+	Clusters      mgmtcontrollers.ClusterCache
+	CRTBs         mgmtcontrollers.ClusterRoleTemplateBindingCache
+	PRTBs         mgmtcontrollers.ProjectRoleTemplateBindingCache
+	GRBs          mgmtcontrollers.GlobalRoleBindingCache
+}
+
+// NewSimulateHandler returns the handler of the simulate action.
+func NewSimulateHandler(management *config.ScaledContext) *SimulateHandler {
+	mgmt := management.Wrangler.Mgmt
+	return &SimulateHandler{
+		RoleTemplates: mgmt.RoleTemplate().Cache(),
+		GlobalRoles:   mgmt.GlobalRole().Cache(),
+		Clusters:      mgmt.Cluster().Cache(),
+		CRTBs:         mgmt.ClusterRoleTemplateBinding().Cache(),
+		PRTBs:         mgmt.ProjectRoleTemplateBinding().Cache(),
+		GRBs:          mgmt.GlobalRoleBinding().Cache(),
+	}
+}
+
+// Formatter adds the simulate action to the role templates of the users allowed to update them and to list all the
+// bindings.
+func (h *SimulateHandler) Formatter(apiContext *types.APIContext, resource *types.RawResource) {
+	if canSimulate(apiContext, resource.Values) {
+		resource.AddAction(apiContext, SimulateAction)
+	}
+}
+
+func (h *SimulateHandler) ActionHandler(actionName string, action *types.Action, apiContext *types.APIContext) error {
+	if actionName != SimulateAction {
+		return httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("invalid action %s", actionName))
+	}
+	if !canSimulate(apiContext, nil) {
+		return httperror.NewAPIError(httperror.PermissionDenied, "Not Allowed")
+	}
+
+	rt, err := h.RoleTemplates.Get(apiContext.ID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("role template %s not found", apiContext.ID))
+		}
+		return httperror.WrapAPIError(err, httperror.ServerError, "failed to get role template")
+	}
+
+	input := &apiv3.RoleTemplateSimulateInput{}
+	if err := json.NewDecoder(apiContext.Request.Body).Decode(input); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "invalid simulate input")
+	}
+	edited := withChanges(rt, &apiv3.RoleTemplateRenderInput{
+		Rules:             input.Rules,
+		ExternalRules:     input.ExternalRules,
+		RoleTemplateNames: input.RoleTemplateNames,
+		ExcludedRules:     input.ExcludedRules,
+	})
+
+	output, err := h.simulate(rt, edited)
+	if err != nil {
+		return err
+	}
+	if features.AggregatedRoleTemplates.Enabled() {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("the %s feature is enabled, the objects created in the clusters are aggregated differently", features.AggregatedRoleTemplates.Name()))
+	}
+
+	apiContext.WriteResponse(http.StatusOK, output)
+	return nil
+}
+
+// simulate returns the bindings whose permissions change when the role template is replaced by the edited one. The
+// role templates inheriting it, and the global roles inheriting those in the downstream clusters, change too.
+func (h *SimulateHandler) simulate(rt, edited *apiv3.RoleTemplate) (*apiv3.RoleTemplateSimulateOutput, error) {
+	output := &apiv3.RoleTemplateSimulateOutput{
+		RoleTemplates: []apiv3.SimulatedRoleTemplate{},
+		Clusters:      []apiv3.SimulatedCluster{},
+	}
+	getBefore := h.RoleTemplates.Get
+	getAfter := func(name string) (*apiv3.RoleTemplate, error) {
+		if name == edited.Name {
+			return edited, nil
+		}
+		return getBefore(name)
+	}
+
+	roleTemplates, err := h.RoleTemplates.List(labels.Everything())
+	if err != nil {
+		return nil, httperror.WrapAPIError(err, httperror.ServerError, "failed to list role templates")
+	}
+	byName := map[string]*apiv3.RoleTemplate{}
+	inheritedBy := map[string][]string{}
+	for _, roleTemplate := range roleTemplates {
+		byName[roleTemplate.Name] = roleTemplate
+		for _, name := range roleTemplate.RoleTemplateNames {
+			inheritedBy[name] = append(inheritedBy[name], roleTemplate.Name)
+		}
+	}
+	byName[rt.Name] = rt
+	affected := sets.New(rt.Name)
+	for queue := []string{rt.Name}; len(queue) > 0; queue = queue[1:] {
+		for _, name := range inheritedBy[queue[0]] {
+			if !affected.Has(name) {
+				affected.Insert(name)
+				queue = append(queue, name)
+			}
+		}
+	}
+
+	// deltas are the rules gained and lost by the changed role templates and global roles, by kind and name.
+	deltas := map[string]ruleDelta{}
+	for _, name := range sets.List(affected) {
+		before, after := byName[name], byName[name]
+		if name == edited.Name {
+			after = edited
+		}
+		beforeRules, err := rbac.RoleTemplateRules(getBefore, before)
+		if err != nil {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("failed to gather the rules of role template %s: %v", name, err))
+			continue
+		}
+		afterRules, err := rbac.RoleTemplateRules(getAfter, after)
+		if err != nil {
+			return nil, httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+		}
+		if after.External && len(after.ExternalRules) == 0 {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("the rules of external role template %s are those of the existing ClusterRole %s of each cluster", after.Name, after.Name))
+		}
+		if delta := newRuleDelta(beforeRules, afterRules); !delta.empty() {
+			deltas[roleKey(roleTemplateKind, name)] = delta
+			output.RoleTemplates = append(output.RoleTemplates, delta.roleTemplate(roleTemplateKind, name))
+		}
+	}
+	if len(deltas) == 0 {
+		return output, nil
+	}
+
+	globalRoles, err := h.GlobalRoles.List(labels.Everything())
+	if err != nil {
+		return nil, httperror.WrapAPIError(err, httperror.ServerError, "failed to list global roles")
+	}
+	sort.Slice(globalRoles, func(i, j int) bool { return globalRoles[i].Name < globalRoles[j].Name })
+	for _, globalRole := range globalRoles {
+		if !inheritsChanged(globalRole.InheritedClusterRoles, deltas) {
+			continue
+		}
+		var beforeRules, afterRules []rbacv1.PolicyRule
+		for _, name := range globalRole.InheritedClusterRoles {
+			roleTemplate, ok := byName[name]
+			if !ok {
+				continue
+			}
+			before, err := rbac.RoleTemplateRules(getBefore, roleTemplate)
+			if err != nil {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("failed to gather the rules of global role %s: %v", globalRole.Name, err))
+				continue
+			}
+			if name == edited.Name {
+				roleTemplate = edited
+			}
+			after, err := rbac.RoleTemplateRules(getAfter, roleTemplate)
+			if err != nil {
+				return nil, httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+			}
+			beforeRules = append(beforeRules, before...)
+			afterRules = append(afterRules, after...)
+		}
+		if delta := newRuleDelta(beforeRules, afterRules); !delta.empty() {
+			deltas[roleKey(globalRoleKind, globalRole.Name)] = delta
+			output.RoleTemplates = append(output.RoleTemplates, delta.roleTemplate(globalRoleKind, globalRole.Name))
+		}
+	}
+
+	clusters := map[string]*simulatedCluster{}
+	bind := func(clusterName string, binding apiv3.SimulatedBinding, delta ruleDelta) {
+		c, ok := clusters[clusterName]
+		if !ok {
+			c = &simulatedCluster{subjects: sets.New[string]()}
+			clusters[clusterName] = c
+		}
+		c.bindings = append(c.bindings, binding)
+		c.subjects.Insert(binding.SubjectName)
+		c.added = append(c.added, delta.added...)
+		c.removed = append(c.removed, delta.removed...)
+	}
+
+	crtbs, err := h.CRTBs.List("", labels.Everything())
+	if err != nil {
+		return nil, httperror.WrapAPIError(err, httperror.ServerError, "failed to list cluster role template bindings")
+	}
+	for _, crtb := range crtbs {
+		delta, ok := deltas[roleKey(roleTemplateKind, crtb.RoleTemplateName)]
+		if !ok || crtb.DeletionTimestamp != nil {
+			continue
+		}
+		kind, name := subject(crtb.UserName, crtb.UserPrincipalName, crtb.GroupPrincipalName, crtb.GroupName, "")
+		clusterName := crtb.ClusterName
+		if clusterName == "" {
+			clusterName = crtb.Namespace
+		}
+		bind(clusterName, apiv3.SimulatedBinding{
+			Kind:        crtbKind,
+			Name:        crtb.Namespace + ":" + crtb.Name,
+			RoleName:    crtb.RoleTemplateName,
+			SubjectKind: kind,
+			SubjectName: name,
+		}, delta)
+	}
+
+	prtbs, err := h.PRTBs.List("", labels.Everything())
+	if err != nil {
+		return nil, httperror.WrapAPIError(err, httperror.ServerError, "failed to list project role template bindings")
+	}
+	for _, prtb := range prtbs {
+		delta, ok := deltas[roleKey(roleTemplateKind, prtb.RoleTemplateName)]
+		if !ok || prtb.DeletionTimestamp != nil {
+			continue
+		}
+		clusterName, _, _ := strings.Cut(prtb.ProjectName, ":")
+		kind, name := subject(prtb.UserName, prtb.UserPrincipalName, prtb.GroupPrincipalName, prtb.GroupName, prtb.ServiceAccount)
+		bind(clusterName, apiv3.SimulatedBinding{
+			Kind:        prtbKind,
+			Name:        prtb.Namespace + ":" + prtb.Name,
+			RoleName:    prtb.RoleTemplateName,
+			SubjectKind: kind,
+			SubjectName: name,
+		}, delta)
+	}
+
+	grbs, err := h.GRBs.List(labels.Everything())
+	if err != nil {
+		return nil, httperror.WrapAPIError(err, httperror.ServerError, "failed to list global role bindings")
+	}
+	var downstream []string
+	for _, grb := range grbs {
+		delta, ok := deltas[roleKey(globalRoleKind, grb.GlobalRoleName)]
+		if !ok || grb.DeletionTimestamp != nil {
+			continue
+		}
+		if downstream == nil {
+			if downstream, err = h.downstreamClusters(); err != nil {
+				return nil, err
+			}
+		}
+		kind, name := subject(grb.UserName, grb.UserPrincipalName, grb.GroupPrincipalName, "", "")
+		for _, clusterName := range downstream {
+			bind(clusterName, apiv3.SimulatedBinding{
+				Kind:        grbKind,
+				Name:        grb.Name,
+				RoleName:    grb.GlobalRoleName,
+				SubjectKind: kind,
+				SubjectName: name,
+			}, delta)
+		}
+	}
+
+	subjects := sets.New[string]()
+	groups := false
+	for _, clusterName := range sets.List(sets.KeySet(clusters)) {
+		c := clusters[clusterName]
+		sort.Slice(c.bindings, func(i, j int) bool {
+			if c.bindings[i].Kind != c.bindings[j].Kind {
+				return c.bindings[i].Kind < c.bindings[j].Kind
+			}
+			return c.bindings[i].Name < c.bindings[j].Name
+		})
+		for _, binding := range c.bindings {
+			subjects.Insert(binding.SubjectKind + ":" + binding.SubjectName)
+			groups = groups || binding.SubjectKind == rbacv1.GroupKind
+		}
+		// The rules gained and lost by the bindings of the cluster are merged, one at a time.
+		added, _ := rbac.RuleDelta(nil, c.added)
+		removed, _ := rbac.RuleDelta(nil, c.removed)
+		output.Clusters = append(output.Clusters, apiv3.SimulatedCluster{
+			ClusterName:  clusterName,
+			Bindings:     c.bindings,
+			Subjects:     sets.List(c.subjects),
+			AddedRules:   added,
+			RemovedRules: removed,
+		})
+	}
+	output.SubjectCount = subjects.Len()
+	if groups {
+		output.Warnings = append(output.Warnings, "the permissions of the members of the groups change too, they aren't listed")
+	}
+	return output, nil
+}
+
+// downstreamClusters returns the names of the clusters the inherited cluster roles of the global roles apply to, all
+// but the local cluster.
+func (h *SimulateHandler) downstreamClusters() ([]string, error) {
+	clusters, err := h.Clusters.List(labels.Everything())
+	if err != nil {
+		return nil, httperror.WrapAPIError(err, httperror.ServerError, "failed to list clusters")
+	}
+	names := []string{}
+	for _, cluster := range clusters {
+		if cluster.Name != localCluster && cluster.DeletionTimestamp == nil {
+			names = append(names, cluster.Name)
+		}
+	}
+	return names, nil
+}
+
+// ruleDelta is the rules gained and lost by a role.
+type ruleDelta struct {
+	added   []rbacv1.PolicyRule
+	removed []rbacv1.PolicyRule
+}
+
+func newRuleDelta(before, after []rbacv1.PolicyRule) ruleDelta {
+	added, removed := rbac.RuleDelta(before, after)
+	return ruleDelta{added: added, removed: removed}
+}
+
+func (d ruleDelta) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0
+}
+
+func (d ruleDelta) roleTemplate(kind, name string) apiv3.SimulatedRoleTemplate {
+	return apiv3.SimulatedRoleTemplate{
+		Kind:         kind,
+		Name:         name,
+		AddedRules:   d.added,
+		RemovedRules: d.removed,
+	}
+}
+
+// simulatedCluster accumulates the bindings of a cluster whose permissions change.
+type simulatedCluster struct {
+	bindings []apiv3.SimulatedBinding
+	subjects sets.Set[string]
+	added    []rbacv1.PolicyRule
+	removed  []rbacv1.PolicyRule
+}
+
+func inheritsChanged(roleTemplateNames []string, deltas map[string]ruleDelta) bool {
+	for _, name := range roleTemplateNames {
+		if _, ok := deltas[roleKey(roleTemplateKind, name)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func roleKey(kind, name string) string {
+	return kind + ":" + name
+}
+
+// subject returns the kind and name of the subject of a binding.
+func subject(userName, userPrincipalName, groupPrincipalName, groupName, serviceAccount string) (string, string) {
+	switch {
+	case userName != "":
+		return rbacv1.UserKind, userName
+	case userPrincipalName != "":
+		return rbacv1.UserKind, userPrincipalName
+	case groupPrincipalName != "":
+		return rbacv1.GroupKind, groupPrincipalName
+	case groupName != "":
+		return rbacv1.GroupKind, groupName
+	default:
+		return rbacv1.ServiceAccountKind, serviceAccount
+	}
+}
+
+// canSimulate returns true if the user can update the role templates and list all their bindings, as the simulation
+// reveals the bindings of every cluster and project.
+func canSimulate(apiContext *types.APIContext, values map[string]interface{}) bool {
+	if !canRender(apiContext, values) {
+		return false
+	}
+	for _, resource := range []string{v3.ClusterRoleTemplateBindingResource.Name, v3.ProjectRoleTemplateBindingResource.Name, v3.GlobalRoleBindingResource.Name} {
+		if apiContext.AccessControl.CanDo(v3.RoleTemplateGroupVersionKind.Group, resource, "list", apiContext, nil, apiContext.Schema) != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package roletemplate
+
+import (
+	"testing"
+
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var getPods = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}
+
+// newSimulateHandler returns a handler where pod-reader is inherited by the dev role template, itself inherited by
+// the ops global role in the downstream clusters c-a and c-b, and:
+// - crtb-alice binds pod-reader to u-alice in c-a,
+// - prtb-devs binds dev to the devs group in a project of c-b,
+// - grb-bob binds ops to u-bob,
+// - the other bindings grant unchanged roles, or are being deleted.
+func newSimulateHandler(t *testing.T) *SimulateHandler {
+	ctrl := gomock.NewController(t)
+	roleTemplates := []*apiv3.RoleTemplate{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-reader"}, Rules: []rbacv1.PolicyRule{getPods}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "dev"}, RoleTemplateNames: []string{"pod-reader"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}, Rules: []rbacv1.PolicyRule{getPods}},
+	}
+	roleTemplateCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.RoleTemplate](ctrl)
+	roleTemplateCache.EXPECT().List(gomock.Any()).Return(roleTemplates, nil).AnyTimes()
+	roleTemplateCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*apiv3.RoleTemplate, error) {
+		for _, rt := range roleTemplates {
+			if rt.Name == name {
+				return rt, nil
+			}
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "roletemplates"}, name)
+	}).AnyTimes()
+
+	globalRoleCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.GlobalRole](ctrl)
+	globalRoleCache.EXPECT().List(gomock.Any()).Return([]*apiv3.GlobalRole{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ops"}, InheritedClusterRoles: []string{"dev"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "auditor"}, InheritedClusterRoles: []string{"unrelated"}},
+	}, nil).AnyTimes()
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.Cluster](ctrl)
+	clusterCache.EXPECT().List(gomock.Any()).Return([]*apiv3.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "local"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-b"}},
+	}, nil).AnyTimes()
+
+	crtbCache := fake.NewMockCacheInterface[*apiv3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("", gomock.Any()).Return([]*apiv3.ClusterRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-alice", Namespace: "c-a"}, ClusterName: "c-a", UserName: "u-alice", RoleTemplateName: "pod-reader"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-unrelated", Namespace: "c-a"}, ClusterName: "c-a", UserName: "u-carol", RoleTemplateName: "unrelated"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-deleting", Namespace: "c-b", DeletionTimestamp: &metav1.Time{}}, ClusterName: "c-b", UserName: "u-carol", RoleTemplateName: "pod-reader"},
+	}, nil).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*apiv3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", gomock.Any()).Return([]*apiv3.ProjectRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-devs", Namespace: "c-b-p-xyz"}, ProjectName: "c-b:p-xyz", GroupPrincipalName: "github_team://devs", RoleTemplateName: "dev"},
+	}, nil).AnyTimes()
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*apiv3.GlobalRoleBinding](ctrl)
+	grbCache.EXPECT().List(gomock.Any()).Return([]*apiv3.GlobalRoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-bob"}, UserName: "u-bob", GlobalRoleName: "ops"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-carol"}, UserName: "u-carol", GlobalRoleName: "auditor"},
+	}, nil).AnyTimes()
+
+	return &SimulateHandler{
+		RoleTemplates: roleTemplateCache,
+		GlobalRoles:   globalRoleCache,
+		Clusters:      clusterCache,
+		CRTBs:         crtbCache,
+		PRTBs:         prtbCache,
+		GRBs:          grbCache,
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	h := newSimulateHandler(t)
+	rt, err := h.RoleTemplates.Get("pod-reader")
+	require.NoError(t, err)
+	edited := withChanges(rt, &apiv3.RoleTemplateRenderInput{
+		Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "delete"}}},
+	})
+
+	output, err := h.simulate(rt, edited)
+	require.NoError(t, err)
+
+	deletePods := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"delete"}}}
+	assert.Equal(t, []apiv3.SimulatedRoleTemplate{
+		{Kind: "RoleTemplate", Name: "dev", AddedRules: deletePods},
+		{Kind: "RoleTemplate", Name: "pod-reader", AddedRules: deletePods},
+		{Kind: "GlobalRole", Name: "ops", AddedRules: deletePods},
+	}, output.RoleTemplates)
+	assert.Equal(t, []apiv3.SimulatedCluster{
+		{
+			ClusterName: "c-a",
+			Bindings: []apiv3.SimulatedBinding{
+				{Kind: "ClusterRoleTemplateBinding", Name: "c-a:crtb-alice", RoleName: "pod-reader", SubjectKind: "User", SubjectName: "u-alice"},
+				{Kind: "GlobalRoleBinding", Name: "grb-bob", RoleName: "ops", SubjectKind: "User", SubjectName: "u-bob"},
+			},
+			Subjects:   []string{"u-alice", "u-bob"},
+			AddedRules: deletePods,
+		},
+		{
+			ClusterName: "c-b",
+			Bindings: []apiv3.SimulatedBinding{
+				{Kind: "GlobalRoleBinding", Name: "grb-bob", RoleName: "ops", SubjectKind: "User", SubjectName: "u-bob"},
+				{Kind: "ProjectRoleTemplateBinding", Name: "c-b-p-xyz:prtb-devs", RoleName: "dev", SubjectKind: "Group", SubjectName: "github_team://devs"},
+			},
+			Subjects:   []string{"github_team://devs", "u-bob"},
+			AddedRules: deletePods,
+		},
+	}, output.Clusters)
+	assert.Equal(t, 3, output.SubjectCount)
+	assert.Len(t, output.Warnings, 1, "the members of the group aren't listed")
+}
+
+func TestSimulateWithoutChange(t *testing.T) {
+	t.Parallel()
+	h := newSimulateHandler(t)
+	rt, err := h.RoleTemplates.Get("pod-reader")
+	require.NoError(t, err)
+	edited := withChanges(rt, &apiv3.RoleTemplateRenderInput{
+		Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"Pods"}, Verbs: []string{"GET"}}},
+	})
+
+	output, err := h.simulate(rt, edited)
+	require.NoError(t, err)
+
+	assert.Empty(t, output.RoleTemplates)
+	assert.Empty(t, output.Clusters)
+	assert.Zero(t, output.SubjectCount)
+}
+
+func TestSimulateInvalidChange(t *testing.T) {
+	t.Parallel()
+	h := newSimulateHandler(t)
+	rt, err := h.RoleTemplates.Get("pod-reader")
+	require.NoError(t, err)
+	edited := withChanges(rt, &apiv3.RoleTemplateRenderInput{RoleTemplateNames: []string{"missing"}})
+
+	_, err = h.simulate(rt, edited)
+	assert.Error(t, err)
+}
@@ -3,16 +3,30 @@
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/auth/bindingpolicy"
+	"github.com/rancher/rancher/pkg/auth/principalscope"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/rancher/rancher/pkg/features"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ref"
 	"github.com/rancher/rancher/pkg/types/config"
 )
 
 func NewPRTBValidator(management *config.ScaledContext) types.Validator {
-	return newValidator(management, client.ProjectRoleTemplateBindingFieldRoleTemplateID, "project")
+	validator := &validator{
+		roleTemplateLister: management.Management.RoleTemplates("").Controller().Lister(),
+		userCache:          management.Wrangler.Mgmt.User().Cache(),
+		scoper:             principalscope.NewScoper(management.Wrangler),
+		field:              client.ProjectRoleTemplateBindingFieldRoleTemplateID,
+		context:            "project",
+	}
+
+	return validator.validator
 }
 
 func NewCRTBValidator(management *config.ScaledContext) types.Validator {
@@ -31,11 +45,22 @@ func newValidator(management *config.ScaledContext, field string, context string
 
 type validator struct {
 	roleTemplateLister v3.RoleTemplateLister
-	field              string
-	context            string
+	// userCache and scoper are only set for the project bindings, whose targets are restricted to the member search
+	// scope of the project.
+	userCache mgmtcontrollers.UserCache
+	scoper    *principalscope.Scoper
+	field     string
+	context   string
 }
 
 func (v *validator) validator(request *types.APIContext, schema *types.Schema, data map[string]interface{}) error {
+	if err := validateExpiration(request, data); err != nil {
+		return err
+	}
+	if err := validateNamespaceSelector(data); err != nil {
+		return err
+	}
+
 	roleTemplateName := data[v.field]
 	if roleTemplateName == nil && request.Method == http.MethodPut {
 		return nil
@@ -68,6 +93,69 @@ func (v *validator) validator(request *types.APIContext, schema *types.Schema, d
 			"OR a group [groupId]/[groupPrincipalId]")
 	}
 
+	if v.scoper != nil && principalscope.Restricted(request) {
+		if err := v.validateScope(data); err != nil {
+			return err
+		}
+	}
+
+	return bindingpolicy.Enforce(request, v.policyBinding(data))
+}
+
+// policyBinding returns the binding sent to the binding policy.
+func (v *validator) policyBinding(data map[string]interface{}) bindingpolicy.Binding {
+	binding := bindingpolicy.Binding{Kind: "ClusterRoleTemplateBinding"}
+	binding.ClusterName, _ = data["clusterId"].(string)
+	if v.context == "project" {
+		binding.Kind = "ProjectRoleTemplateBinding"
+		projectID, _ := data["projectId"].(string)
+		binding.ClusterName, binding.ProjectName = ref.Parse(projectID)
+	}
+	binding.RoleTemplateName, _ = data[v.field].(string)
+	binding.UserName, _ = data["userId"].(string)
+	binding.UserPrincipalName, _ = data["userPrincipalId"].(string)
+	binding.GroupName, _ = data["groupId"].(string)
+	binding.GroupPrincipalName, _ = data["groupPrincipalId"].(string)
+	return binding
+}
+
+// validateScope validates that the target of the project binding is in the member search scope of the project. A user
+// is in the scope if one of their principals is.
+func (v *validator) validateScope(data map[string]interface{}) error {
+	projectID, _ := data["projectId"].(string)
+	var principalIDs []string
+	for _, field := range []string{"userPrincipalId", "groupPrincipalId"} {
+		if principalID, _ := data[field].(string); principalID != "" {
+			principalIDs = append(principalIDs, principalID)
+		}
+	}
+	if userID, _ := data["userId"].(string); userID != "" && len(principalIDs) == 0 {
+		user, err := v.userCache.Get(userID)
+		if err != nil {
+			return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Error getting user: %v", err))
+		}
+		principalIDs = user.PrincipalIDs
+	}
+	if len(principalIDs) == 0 {
+		// The group is given by its ID, which isn't a principal of any auth provider, and is only out of the scope of
+		// the projects with a scope of their own.
+		scope, err := v.scoper.Scope(projectID, "")
+		if err != nil {
+			return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Error checking the member search scope: %v", err))
+		}
+		if scope != nil {
+			return httperror.NewAPIError(httperror.PermissionDenied, fmt.Sprintf("target is not in the member search scope of project %s", projectID))
+		}
+		return nil
+	}
+
+	allowed, err := v.scoper.Allowed(projectID, principalIDs...)
+	if err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Error checking the member search scope: %v", err))
+	}
+	if !allowed {
+		return httperror.NewAPIError(httperror.PermissionDenied, fmt.Sprintf("target is not in the member search scope of project %s", projectID))
+	}
 	return nil
 }
 
@@ -88,3 +176,33 @@ func (v *validator) validateRoleTemplateBinding(obj interface{}) (*v3.RoleTempla
 
 	return roleTemplate, nil
 }
+
+// validateNamespaceSelector refuses the namespace selector of the cluster bindings while the aggregated role templates
+// are enabled, as the bindings scoped to namespaces would grant nothing.
+func validateNamespaceSelector(data map[string]interface{}) error {
+	if data[client.ClusterRoleTemplateBindingFieldNamespaceSelector] != nil && features.AggregatedRoleTemplates.Enabled() {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("namespaceSelector is not supported while the %s feature is enabled",
+			features.AggregatedRoleTemplates.Name()))
+	}
+	return nil
+}
+
+// validateExpiration validates the optional expiration of the bindings granting temporary access.
+func validateExpiration(request *types.APIContext, data map[string]interface{}) error {
+	if ttl, _ := data["ttl"].(string); ttl != "" {
+		duration, err := time.ParseDuration(ttl)
+		if err != nil || duration <= 0 {
+			return httperror.NewAPIError(httperror.InvalidFormat, fmt.Sprintf("ttl [%s] must be a positive duration like 8h", ttl))
+		}
+	}
+	if expiresAt, _ := data["expiresAt"].(string); expiresAt != "" {
+		t, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return httperror.NewAPIError(httperror.InvalidFormat, fmt.Sprintf("expiresAt [%s] must be a RFC 3339 date", expiresAt))
+		}
+		if request.Method == http.MethodPost && !t.After(time.Now()) {
+			return httperror.NewAPIError(httperror.InvalidBodyContent, "expiresAt must be in the future")
+		}
+	}
+	return nil
+}
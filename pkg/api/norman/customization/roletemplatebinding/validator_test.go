@@ -0,0 +1,28 @@
+package roletemplatebinding
+
+import (
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/features"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNamespaceSelector(t *testing.T) {
+	defer features.AggregatedRoleTemplates.Set(features.AggregatedRoleTemplates.Enabled())
+
+	data := map[string]interface{}{
+		"roleTemplateId":    "cluster-member",
+		"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"team": "platform"}},
+	}
+
+	features.AggregatedRoleTemplates.Set(false)
+	require.NoError(t, validateNamespaceSelector(data))
+
+	features.AggregatedRoleTemplates.Set(true)
+	err := validateNamespaceSelector(data)
+	require.Error(t, err)
+	assert.Equal(t, httperror.InvalidBodyContent.Code, err.(*httperror.APIError).Code.Code)
+	require.NoError(t, validateNamespaceSelector(map[string]interface{}{"roleTemplateId": "cluster-member"}))
+}
@@ -397,9 +397,21 @@ func RoleTemplate(schemas *types.Schemas, management *config.ScaledContext) {
 	rt := roletemplate.Wrapper{
 		RoleTemplateLister: management.Management.RoleTemplates("").Controller().Lister(),
 	}
+	renderHandler := roletemplate.NewRenderHandler(management)
+	simulateHandler := roletemplate.NewSimulateHandler(management)
 	schema := schemas.Schema(&managementschema.Version, client.RoleTemplateType)
-	schema.Formatter = rt.Formatter
+	schema.Formatter = func(apiContext *types.APIContext, resource *types.RawResource) {
+		rt.Formatter(apiContext, resource)
+		renderHandler.Formatter(apiContext, resource)
+		simulateHandler.Formatter(apiContext, resource)
+	}
 	schema.Validator = rt.Validator
+	schema.ActionHandler = func(actionName string, action *types.Action, apiContext *types.APIContext) error {
+		if actionName == roletemplate.SimulateAction {
+			return simulateHandler.ActionHandler(actionName, action, apiContext)
+		}
+		return renderHandler.ActionHandler(actionName, action, apiContext)
+	}
 	schema.Store = rtStore.Wrap(schema.Store, management.Management.RoleTemplates("").Controller().Lister())
 }
 
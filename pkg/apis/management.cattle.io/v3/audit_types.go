@@ -0,0 +1,67 @@
+package v3
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// The levels of the AuditPolicy rules.
+const (
+	// AuditLevelNone doesn't log the requests.
+	AuditLevelNone = "None"
+	// AuditLevelMetadata logs the metadata of the requests: the user, the URI, the headers and the response code.
+	AuditLevelMetadata = "Metadata"
+	// AuditLevelRequest logs the metadata and the request bodies.
+	AuditLevelRequest = "Request"
+	// AuditLevelRequestResponse logs the metadata, the request bodies and the response bodies.
+	AuditLevelRequestResponse = "RequestResponse"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuditPolicy sets the audit level of the API requests by resource, verb, user and user group. The changes are applied
+// by the audit log without restarting Rancher. The rules of all the AuditPolicies are evaluated in the order of the
+// names of the policies, and the level of the first rule matching a request applies. The requests which no rule
+// matches are logged at the level of the audit-level flag.
+type AuditPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the rules of the policy.
+	Spec AuditPolicySpec `json:"spec"`
+}
+
+// AuditPolicySpec is the specification of an AuditPolicy.
+type AuditPolicySpec struct {
+	// Rules are the rules of the policy, evaluated in order.
+	// +optional
+	Rules []AuditPolicyRule `json:"rules,omitempty"`
+}
+
+// AuditPolicyRule sets the audit level of the requests it matches. The empty lists match all the requests.
+type AuditPolicyRule struct {
+	// Level is the audit level of the matching requests.
+	// +kubebuilder:validation:Enum=None;Metadata;Request;RequestResponse
+	Level string `json:"level"`
+
+	// Resources are the plural names of the resources of the requests, e.g. clusters or secrets, matched ignoring the
+	// case, or * for all of them. The requests which aren't about resources only match the rules without resources.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// Verbs are the verbs of the requests, e.g. get, list, watch, create, update, patch or delete, or * for all of them.
+	// +optional
+	Verbs []string `json:"verbs,omitempty"`
+
+	// Users are the names of the users making the requests, e.g. user-abcde.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// UserGroups are the groups of the users making the requests, e.g. system:authenticated. The rule matches the
+	// requests of the users in any of them.
+	// +optional
+	UserGroups []string `json:"userGroups,omitempty"`
+}
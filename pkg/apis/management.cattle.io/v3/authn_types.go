@@ -19,6 +19,20 @@
 	// AuthConfigOKTAPasswordMigrated is applied when an Okta password has been
 	// moved to a Secret.
 	AuthConfigOKTAPasswordMigrated condition.Cond = "OktaPasswordMigrated"
+
+	// AuthConfigConditionReachable is True when the background probe of an enabled
+	// AuthConfig reached every endpoint of its identity service.
+	AuthConfigConditionReachable condition.Cond = "Reachable"
+	// AuthConfigConditionCredentialsValid is False when a certificate or secret of
+	// an enabled AuthConfig has expired.
+	AuthConfigConditionCredentialsValid condition.Cond = "CredentialsValid"
+	// AuthConfigConditionMetadataValid is False when the metadata of the identity
+	// service of an enabled AuthConfig is past its validUntil.
+	AuthConfigConditionMetadataValid condition.Cond = "MetadataValid"
+	// AuthConfigConditionSLOMet is False when the requests to the identity service
+	// of an enabled AuthConfig have missed the objectives of the auth-provider-slo
+	// settings for longer than auth-provider-slo-breach-minutes.
+	AuthConfigConditionSLOMet condition.Cond = "SLOMet"
 )
 
 // +genclient
@@ -45,6 +59,28 @@ type Token struct {
 	Current            bool              `json:"current"`
 	ClusterName        string            `json:"clusterName,omitempty" norman:"noupdate,type=reference[cluster]"`
 	Enabled            *bool             `json:"enabled,omitempty" norman:"default=true"`
+	// Scope restricts the requests the token can authenticate, on top of the permissions of its user.
+	Scope *TokenScope `json:"scope,omitempty" norman:"noupdate"`
+	// AllowedCIDRs are the networks the token can be used from. It can be used from anywhere if empty.
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty" norman:"noupdate"`
+}
+
+// TokenScope restricts a token to some clusters, projects, API groups and verbs.
+// Each empty list places no restriction.
+type TokenScope struct {
+	// Clusters are the IDs of the clusters the token can access.
+	Clusters []string `json:"clusters,omitempty"`
+	// Projects are the IDs of the projects the token can access, in the <cluster>:<project> format.
+	// The projects of the clusters listed in Clusters can be accessed too. Outside of its clusters and projects, a token
+	// scoped to some can only read the roots and schemas of the Rancher and Steve APIs, and the clusters and projects.
+	Projects []string `json:"projects,omitempty"`
+	// APIGroups are the API groups the token can access, with "" for the core group and "*" for all of them.
+	// The Rancher API (/v3) is the management.cattle.io group.
+	APIGroups []string `json:"apiGroups,omitempty"`
+	// Verbs are the verbs the token can use, like get, list, watch, create, update, patch and delete, or "*" for all of them.
+	Verbs []string `json:"verbs,omitempty"`
+	// ReadOnly restricts the token to the get, list and watch verbs.
+	ReadOnly bool `json:"readOnly,omitempty"`
 }
 
 // Implement the TokenAccessor interface
@@ -101,6 +137,7 @@ type User struct {
 	DisplayName        string     `json:"displayName,omitempty"`
 	Description        string     `json:"description"`
 	Username           string     `json:"username,omitempty"`
+	Email              string     `json:"email,omitempty"`
 	Password           string     `json:"password,omitempty" norman:"writeOnly,noupdate"`
 	MustChangePassword bool       `json:"mustChangePassword,omitempty"`
 	PrincipalIDs       []string   `json:"principalIds,omitempty" norman:"type=array[reference[principal]]"`
@@ -181,6 +218,192 @@ type Group struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	DisplayName string `json:"displayName,omitempty"`
+	// PrincipalID is the ID of the group principal, for groups synced from an auth provider.
+	PrincipalID string `json:"principalId,omitempty"`
+	// Provider is the name of the auth provider the group was synced from.
+	Provider string `json:"provider,omitempty"`
+	// MemberCount is the number of users known to be members of the group, as of their last login or refresh.
+	MemberCount int `json:"memberCount,omitempty"`
+	// LastSync is the last time the group was synced from the auth provider.
+	LastSync *metav1.Time `json:"lastSync,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="PRINCIPAL",type="string",JSONPath=".principalId"
+// +kubebuilder:printcolumn:name="DISPLAY-NAME",type="string",JSONPath=".displayName"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".lastSync"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PrincipalMetadata caches the display metadata of a principal bound to roles, so that the principals of the bindings
+// can be shown without looking them up from their auth provider, even while it's unavailable. It's refreshed by the
+// principal metadata sync.
+type PrincipalMetadata struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// PrincipalID is the ID of the principal, e.g. openldap_user://uid=alice,ou=people,dc=example,dc=com.
+	PrincipalID string `json:"principalId"`
+
+	// DisplayName is the display name of the principal.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// LoginName is the login name of the principal, for users.
+	// +optional
+	LoginName string `json:"loginName,omitempty"`
+
+	// PrincipalType is the type of the principal, user or group.
+	// +optional
+	PrincipalType string `json:"principalType,omitempty"`
+
+	// Provider is the name of the auth provider of the principal.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// ProfilePicture is the URL of the avatar of the principal.
+	// +optional
+	ProfilePicture string `json:"profilePicture,omitempty"`
+
+	// ProfileURL is the URL of the profile of the principal.
+	// +optional
+	ProfileURL string `json:"profileURL,omitempty"`
+
+	// LastSync is the last time the metadata was resolved from the auth provider.
+	// +optional
+	LastSync *metav1.Time `json:"lastSync,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".type"
+// +kubebuilder:printcolumn:name="USER",type="string",JSONPath=".userName"
+// +kubebuilder:printcolumn:name="PROVIDER",type="string",JSONPath=".provider"
+// +kubebuilder:printcolumn:name="SOURCE",type="string",JSONPath=".sourceIP"
+// +kubebuilder:printcolumn:name="REASON",type="string",JSONPath=".reason"
+// +kubebuilder:printcolumn:name="TIME",type="date",JSONPath=".time"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuthEvent records an authentication event: a login, a logout, the creation or revocation of a token, a change of
+// the second factors of a user, a change of the groups of a user found by a refresh, or a change of an auth config.
+// Events are labeled with their type, user and provider so that they can be queried with label selectors, and are
+// removed once older than the auth-event-retention-hours setting.
+type AuthEvent struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Type is the type of the event, e.g. LoginSucceeded, LoginFailed, Logout, TokenCreated, TokenRevoked,
+	// MFAChanged, GroupsRefreshed, AuthConfigChanged, PrincipalsSearched, PrincipalResolved, ProviderSLOBreached or
+	// ProviderSLORecovered.
+	Type string `json:"type"`
+
+	// Time is when the event happened.
+	Time metav1.Time `json:"time"`
+
+	// UserName is the name of the user the event is about, if known.
+	// +optional
+	UserName string `json:"userName,omitempty"`
+
+	// PrincipalID is the principal the event is about, e.g. the username of a failed login of an unknown user.
+	// +optional
+	PrincipalID string `json:"principalId,omitempty"`
+
+	// Provider is the name of the auth provider of the event.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// SourceIP is the address of the client of the request the event comes from.
+	// +optional
+	SourceIP string `json:"sourceIP,omitempty"`
+
+	// Reason is why the event happened, e.g. InvalidCredentials for a failed login.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Actor is the user who caused the event, when it isn't the user the event is about, e.g. the administrator
+	// resetting the second factors of a user.
+	// +optional
+	Actor string `json:"actor,omitempty"`
+
+	// Target is the object the event is about, e.g. the name of a token or an auth config, or the query of a search
+	// of principals.
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// Detail describes the event.
+	// +optional
+	Detail string `json:"detail,omitempty"`
+}
+
+// The severities of the SecurityEvents.
+const (
+	SecurityEventSeverityCritical = "Critical"
+	SecurityEventSeverityHigh     = "High"
+	SecurityEventSeverityMedium   = "Medium"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".type"
+// +kubebuilder:printcolumn:name="SEVERITY",type="string",JSONPath=".severity"
+// +kubebuilder:printcolumn:name="USER",type="string",JSONPath=".userName"
+// +kubebuilder:printcolumn:name="ACTOR",type="string",JSONPath=".actor"
+// +kubebuilder:printcolumn:name="TARGET",type="string",JSONPath=".target"
+// +kubebuilder:printcolumn:name="TIME",type="date",JSONPath=".time"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecurityEvent records a high-signal security finding, for the alerting pipelines to watch and route: the binding of
+// a user or group to an administrator global role, a change of an auth config, the disabling of the second factors of
+// a user, the activation of a break glass account, or the creation of a token living longer than 30 days. Events are
+// labeled with their type, severity and user so that they can be selected with label selectors, and are removed once
+// older than the security-event-retention-hours setting.
+type SecurityEvent struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Type is the type of the event: AdminBindingCreated, AuthConfigChanged, MFADisabled, BreakGlassUsed or
+	// LongLivedTokenCreated.
+	Type string `json:"type"`
+
+	// Severity is how urgently the event should be looked at: Critical, High or Medium.
+	// +kubebuilder:validation:Enum=Critical;High;Medium
+	Severity string `json:"severity"`
+
+	// Time is when the event happened.
+	Time metav1.Time `json:"time"`
+
+	// UserName is the name of the user the event is about, if any, e.g. the user bound to the administrator role.
+	// +optional
+	UserName string `json:"userName,omitempty"`
+
+	// Actor is the user who caused the event, when known and it isn't the user the event is about.
+	// +optional
+	Actor string `json:"actor,omitempty"`
+
+	// Target is the object the event is about, e.g. the name of a global role binding, an auth config, a break glass
+	// account or a token.
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// SourceIP is the address of the client of the request the event comes from, if any.
+	// +optional
+	SourceIP string `json:"sourceIP,omitempty"`
+
+	// Detail describes the event.
+	// +optional
+	Detail string `json:"detail,omitempty"`
 }
 
 // +genclient
@@ -219,6 +442,11 @@ type Principal struct {
 type SearchPrincipalsInput struct {
 	Name          string `json:"name" norman:"type=string,required,notnullable"`
 	PrincipalType string `json:"principalType,omitempty" norman:"type=enum,options=user|group"`
+	// ProjectID is the project the principals are searched to be added to, as <cluster>:<project>. The principals are
+	// then restricted to the member search scope of the project.
+	ProjectID string `json:"projectId,omitempty" norman:"type=reference[project]"`
+	// ClusterID is the cluster the principals are searched to be added to.
+	ClusterID string `json:"clusterId,omitempty" norman:"type=reference[cluster]"`
 }
 
 type ChangePasswordInput struct {
@@ -230,6 +458,133 @@ type SetPasswordInput struct {
 	NewPassword string `json:"newPassword" norman:"type=string,required"`
 }
 
+// LinkPrincipalInput holds the credentials used to verify the principal of an auth provider linked to the current user.
+// Providers with a basic login use Username and Password, providers with an OAuth flow use the authorization Code.
+type LinkPrincipalInput struct {
+	Provider string `json:"provider" norman:"type=string,required"`
+	Username string `json:"username,omitempty" norman:"type=string"`
+	Password string `json:"password,omitempty" norman:"type=string"`
+	Code     string `json:"code,omitempty" norman:"type=string"`
+}
+
+type UnlinkPrincipalInput struct {
+	PrincipalID string `json:"principalId" norman:"type=string,required"`
+}
+
+// DuplicateUsers are users sharing the same login across the principals of different auth providers.
+type DuplicateUsers struct {
+	Login   string   `json:"login"`
+	UserIDs []string `json:"userIds"`
+}
+
+type DetectDuplicateUsersOutput struct {
+	Duplicates []DuplicateUsers `json:"duplicates,omitempty"`
+}
+
+// MergeUsersInput selects the user merged into the target user. With DryRun the resources to move are only reported.
+type MergeUsersInput struct {
+	SourceUserID string `json:"sourceUserId" norman:"type=reference[user],required"`
+	TargetUserID string `json:"targetUserId" norman:"type=reference[user],required"`
+	DryRun       bool   `json:"dryRun,omitempty"`
+}
+
+// MergeUsersOutput lists the resources moved from the source user to the target user, or which would be moved in dry-run mode.
+// Namespaced resources are listed as namespace:name.
+type MergeUsersOutput struct {
+	DryRun                      bool     `json:"dryRun,omitempty"`
+	PrincipalIDs                []string `json:"principalIds,omitempty"`
+	Tokens                      []string `json:"tokens,omitempty"`
+	GlobalRoleBindings          []string `json:"globalRoleBindings,omitempty"`
+	ClusterRoleTemplateBindings []string `json:"clusterRoleTemplateBindings,omitempty"`
+	ProjectRoleTemplateBindings []string `json:"projectRoleTemplateBindings,omitempty"`
+	Clusters                    []string `json:"clusters,omitempty"`
+	Projects                    []string `json:"projects,omitempty"`
+}
+
+// UserDataExport is what Rancher stores about a user. Secrets, like the password hash, the keys of tokens and the
+// second factors, are left out.
+type UserDataExport struct {
+	ExportedAt                  string                         `json:"exportedAt"`
+	User                        User                           `json:"user"`
+	LastLogin                   string                         `json:"lastLogin,omitempty"`
+	GroupPrincipals             []string                       `json:"groupPrincipals,omitempty"`
+	ExtraByProvider             map[string]map[string][]string `json:"extraByProvider,omitempty"`
+	Tokens                      []Token                        `json:"tokens,omitempty"`
+	TokenUsage                  []TokenUsageHistory            `json:"tokenUsage,omitempty"`
+	GlobalRoleBindings          []GlobalRoleBinding            `json:"globalRoleBindings,omitempty"`
+	ClusterRoleTemplateBindings []ClusterRoleTemplateBinding   `json:"clusterRoleTemplateBindings,omitempty"`
+	ProjectRoleTemplateBindings []ProjectRoleTemplateBinding   `json:"projectRoleTemplateBindings,omitempty"`
+}
+
+// TokenUsageHistory is the audit trail of the uses of a token.
+type TokenUsageHistory struct {
+	TokenName string            `json:"tokenName"`
+	Events    []TokenUsageEvent `json:"events,omitempty"`
+}
+
+type TokenUsageEvent struct {
+	Time          string `json:"time"`
+	SourceIP      string `json:"sourceIp,omitempty"`
+	UserAgent     string `json:"userAgent,omitempty"`
+	EndpointClass string `json:"endpointClass,omitempty"`
+}
+
+// EraseUserDataInput erases the personal data of a user. With DryRun the resources to change are only reported.
+type EraseUserDataInput struct {
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// EraseUserDataOutput lists the resources deleted, or recreated without the principal of the user, to erase the
+// personal data of the user, or which would be in dry-run mode. Namespaced resources are listed as namespace:name.
+type EraseUserDataOutput struct {
+	DryRun                      bool     `json:"dryRun,omitempty"`
+	PrincipalIDs                []string `json:"principalIds,omitempty"`
+	Tokens                      []string `json:"tokens,omitempty"`
+	Secrets                     []string `json:"secrets,omitempty"`
+	GlobalRoleBindings          []string `json:"globalRoleBindings,omitempty"`
+	ClusterRoleTemplateBindings []string `json:"clusterRoleTemplateBindings,omitempty"`
+	ProjectRoleTemplateBindings []string `json:"projectRoleTemplateBindings,omitempty"`
+}
+
+// LoginAsInput requests a token acting as a user, for an administrator to debug the permissions of the user.
+// TTLMinutes defaults to, and can't exceed, the auth-login-as-max-ttl-minutes setting.
+type LoginAsInput struct {
+	TTLMinutes int64  `json:"ttlMinutes,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// LoginAsOutput is the token acting as the user, and when it expires in RFC 3339 format.
+type LoginAsOutput struct {
+	Token     string `json:"token"`
+	TokenName string `json:"tokenName"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// GroupMembersInput requests a page of the members of a group principal, as resolved by its auth provider.
+type GroupMembersInput struct {
+	PrincipalID string `json:"principalId" norman:"required"`
+	Limit       int    `json:"limit,omitempty"`
+	Continue    string `json:"continue,omitempty"`
+}
+
+// GroupMembersOutput is a page of the members of a group principal. Continue is empty on the last page.
+type GroupMembersOutput struct {
+	PrincipalID string            `json:"principalId"`
+	Members     []GroupMemberInfo `json:"members"`
+	Continue    string            `json:"continue,omitempty"`
+}
+
+// GroupMemberInfo is a member of a group principal and the Rancher user of the member, if any.
+// A member is active if its user is enabled and wasn't flagged as inactive.
+type GroupMemberInfo struct {
+	PrincipalID string       `json:"principalId"`
+	DisplayName string       `json:"displayName,omitempty"`
+	LoginName   string       `json:"loginName,omitempty"`
+	UserID      string       `json:"userId,omitempty"`
+	Active      bool         `json:"active"`
+	LastLogin   *metav1.Time `json:"lastLogin,omitempty"`
+}
+
 // +genclient
 // +kubebuilder:skipversion
 // +genclient:nonNamespaced
@@ -253,6 +608,133 @@ type AuthConfig struct {
 
 type AuthConfigStatus struct {
 	Conditions []AuthConfigConditions `json:"conditions"`
+
+	// Connectivity of the identity service of the provider, probed in the background while it's enabled.
+	Connectivity *AuthConfigConnectivity `json:"connectivity,omitempty"`
+
+	// SLO is the compliance of the requests to the identity service of the provider with the latency and error rate
+	// objectives of the auth-provider-slo settings, while it's enabled.
+	SLO *AuthConfigSLO `json:"slo,omitempty"`
+}
+
+// AuthConfigRevision is a recorded change of the configuration of an auth provider.
+type AuthConfigRevision struct {
+	// Revision number, increasing with each change of the auth config.
+	Revision int64 `json:"revision"`
+
+	// ID of the user that made the change, empty if it's unknown.
+	Author string `json:"author,omitempty"`
+
+	// Time the change was recorded at.
+	Timestamp string `json:"timestamp"`
+
+	// Revision the change rolled the auth config back to, if it was a rollback.
+	RolledBackTo int64 `json:"rolledBackTo,omitempty"`
+
+	// Changes to the configuration of the previous revision. Secrets aren't versioned and never show up.
+	Diff []AuthConfigFieldChange `json:"diff,omitempty"`
+}
+
+// AuthConfigFieldChange is a change of a field of an auth config.
+type AuthConfigFieldChange struct {
+	// Path of the field, with nested fields separated by dots.
+	Field string `json:"field"`
+
+	// JSON encoded value before the change, empty if the field was added.
+	Old string `json:"old,omitempty"`
+
+	// JSON encoded value after the change, empty if the field was removed.
+	New string `json:"new,omitempty"`
+}
+
+// AuthConfigRevisionsOutput is the output of the revisions action of an auth config.
+type AuthConfigRevisionsOutput struct {
+	Revisions []AuthConfigRevision `json:"revisions"`
+}
+
+// AuthConfigRollbackInput is the input of the rollback action of an auth config.
+type AuthConfigRollbackInput struct {
+	// Revision to roll the configuration back to.
+	Revision int64 `json:"revision" norman:"required"`
+}
+
+// AuthConfigConnectivity holds the results of the last background probe of an auth provider.
+type AuthConfigConnectivity struct {
+	// Last time the provider was probed.
+	LastProbeTime string `json:"lastProbeTime,omitempty"`
+
+	// Endpoints of the identity service and whether they could be reached.
+	Endpoints []AuthConfigEndpointStatus `json:"endpoints,omitempty"`
+
+	// Certificates and secrets of the provider that expire.
+	Credentials []AuthConfigCredentialStatus `json:"credentials,omitempty"`
+
+	// Last time the metadata of the identity service, like the OIDC discovery document and JWKS, was fetched.
+	MetadataRefreshTime string `json:"metadataRefreshTime,omitempty"`
+
+	// Time the metadata of the identity service stops being valid, like the validUntil of SAML IdP metadata.
+	MetadataValidUntil string `json:"metadataValidUntil,omitempty"`
+
+	// Last time the group memberships of users were synced with the identity service.
+	LastSyncTime string `json:"lastSyncTime,omitempty"`
+
+	// Error of the last probe, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// AuthConfigEndpointStatus is the reachability of an endpoint of an identity service.
+type AuthConfigEndpointStatus struct {
+	// Name of the endpoint, e.g. issuer or jwks.
+	Name string `json:"name"`
+
+	// URL or address of the endpoint.
+	URL string `json:"url"`
+
+	// Reachable is true if the endpoint answered.
+	Reachable bool `json:"reachable"`
+
+	// Error the endpoint couldn't be reached with.
+	Error string `json:"error,omitempty"`
+}
+
+// AuthConfigCredentialStatus is the expiry of a certificate or secret of an auth provider.
+type AuthConfigCredentialStatus struct {
+	// Name of the credential, e.g. certificate or spCert.
+	Name string `json:"name"`
+
+	// Subject of the certificate, empty for secrets.
+	Subject string `json:"subject,omitempty"`
+
+	// Time the credential expires at.
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// AuthConfigSLO is the compliance of the requests to an auth provider with its service level objective, over the
+// rolling window of the auth-provider-slo-window-minutes setting. A request meets the objective if it succeeded, or
+// failed on the client's side, e.g. for invalid credentials, within the latency of auth-provider-slo-latency-ms.
+type AuthConfigSLO struct {
+	// Last time the compliance was evaluated.
+	LastEvaluationTime string `json:"lastEvaluationTime,omitempty"`
+
+	// Requests to the provider in the window, by all the replicas.
+	Requests int64 `json:"requests"`
+
+	// Requests of the window which failed on the provider's side.
+	Errors int64 `json:"errors,omitempty"`
+
+	// Requests of the window which succeeded, but slower than the latency objective.
+	Slow int64 `json:"slow,omitempty"`
+
+	// Percentage of the requests of the window which met the objective, e.g. 99.5. Empty when there were too few
+	// requests to tell.
+	Compliance string `json:"compliance,omitempty"`
+
+	// Time the compliance fell below the target, empty while it meets it.
+	BelowTargetSince string `json:"belowTargetSince,omitempty"`
+
+	// Time the SLO was reported breached, once the compliance stayed below the target for
+	// auth-provider-slo-breach-minutes. Empty while it isn't breached.
+	BreachedSince string `json:"breachedSince,omitempty"`
 }
 
 type AuthConfigConditions struct {
@@ -358,9 +840,13 @@ type AzureADConfig struct {
 	DeviceAuthEndpoint    string `json:"deviceAuthEndpoint,omitempty"`
 	TenantID              string `json:"tenantId,omitempty" norman:"required,notnullable"`
 	ApplicationID         string `json:"applicationId,omitempty" norman:"required,notnullable"`
-	ApplicationSecret     string `json:"applicationSecret,omitempty" norman:"required,type=password"`
+	ApplicationSecret     string `json:"applicationSecret,omitempty" norman:"type=password"`
 	RancherURL            string `json:"rancherUrl,omitempty" norman:"required,notnullable"`
 	GroupMembershipFilter string `json:"groupMembershipFilter,omitempty"`
+
+	// ApplicationCertificate is a PEM bundle with the certificate and private key the application authenticates with,
+	// instead of the application secret. It's used to sign the client assertions sent to Azure AD.
+	ApplicationCertificate string `json:"applicationCertificate,omitempty" norman:"type=password"`
 }
 
 type AzureADConfigTestOutput struct {
@@ -377,7 +863,7 @@ type AzureADConfigApplyInput struct {
 type ActiveDirectoryConfig struct {
 	AuthConfig `json:",inline" mapstructure:",squash"`
 
-	Servers                      []string `json:"servers,omitempty"                     norman:"type=array[string],required"`
+	Servers                      []string `json:"servers,omitempty"                     norman:"type=array[string]"`
 	Port                         int64    `json:"port,omitempty"                        norman:"default=389"`
 	TLS                          bool     `json:"tls,omitempty"                         norman:"default=false"`
 	StartTLS                     bool     `json:"starttls,omitempty"                    norman:"default=false"`
@@ -404,6 +890,14 @@ type ActiveDirectoryConfig struct {
 	GroupMemberMappingAttribute  string   `json:"groupMemberMappingAttribute,omitempty" norman:"default=member,required"`
 	ConnectionTimeout            int64    `json:"connectionTimeout,omitempty"           norman:"default=5000,notnullable,required"`
 	NestedGroupMembershipEnabled *bool    `json:"nestedGroupMembershipEnabled,omitempty" norman:"default=false"`
+	// BindMethod is how Rancher binds to the domain controllers. With "ntlm", binds carry the channel binding token of
+	// TLS connections, and connections without TLS are signed and sealed, as required by domain controllers
+	// enforcing LDAP channel binding or signing.
+	BindMethod string `json:"bindMethod,omitempty" norman:"type=enum,options=simple|ntlm,default=simple"`
+	// ServerDiscoveryDomain is the DNS domain whose SRV records list the domain controllers, used instead of the servers.
+	ServerDiscoveryDomain string `json:"serverDiscoveryDomain,omitempty"`
+	// ServerDiscoverySite is the Active Directory site whose domain controllers are preferred when discovering them.
+	ServerDiscoverySite string `json:"serverDiscoverySite,omitempty"`
 }
 
 func (c *ActiveDirectoryConfig) GetUserSearchAttributes(searchAttributes ...string) []string {
@@ -540,6 +1034,14 @@ type SamlConfig struct {
 	UIDField           string `json:"uidField"           norman:"required"`
 	RancherAPIHost     string `json:"rancherApiHost"     norman:"required"`
 	EntityID           string `json:"entityID"`
+
+	// ArtifactBindingEnabled asks the IdP to send assertions with the HTTP-Artifact binding. The artifacts are
+	// resolved with the SOAP artifact resolution service of the IdP metadata, over TLS authenticated with the SP
+	// certificate and key.
+	ArtifactBindingEnabled bool `json:"artifactBindingEnabled,omitempty"`
+	// ArtifactResolutionCACerts are PEM encoded CA certificates trusted for the artifact resolution service, in
+	// addition to the system ones.
+	ArtifactResolutionCACerts string `json:"artifactResolutionCaCerts,omitempty"`
 }
 
 type SamlConfigTestInput struct {
@@ -564,6 +1066,18 @@ type PingConfig struct {
 
 type ADFSConfig struct {
 	SamlConfig `json:",inline" mapstructure:",squash"`
+	// WSFedConfig enables signing in and out with WS-Federation instead of SAML.
+	WSFedConfig WSFedFields `json:"wsFedConfig,omitempty"`
+}
+
+// WSFedFields holds the configuration used to sign in and out with the WS-Federation passive requestor profile.
+// The realm of Rancher is the entity ID of the SAML configuration, and the tokens are validated with the signing
+// certificates of the IdP metadata.
+type WSFedFields struct {
+	// Enabled signs users in and out with WS-Federation.
+	Enabled bool `json:"enabled,omitempty"`
+	// Endpoint is the passive WS-Federation endpoint of the IdP, e.g. https://adfs.example.com/adfs/ls/.
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 type KeyCloakConfig struct {
@@ -573,11 +1087,41 @@ type KeyCloakConfig struct {
 type OKTAConfig struct {
 	SamlConfig     `json:",inline" mapstructure:",squash"`
 	OpenLdapConfig LdapFields `json:"openLdapConfig"`
+	// OktaAPIConfig enables searching users and groups using the Okta API when no LDAP is configured.
+	OktaAPIConfig OktaAPIFields `json:"oktaApiConfig,omitempty"`
+}
+
+// OktaAPIFields holds the configuration used to search principals using the Okta Users and Groups API.
+type OktaAPIFields struct {
+	// OrgURL is the URL of the Okta organization, e.g. https://example.okta.com.
+	OrgURL string `json:"orgUrl,omitempty"`
+	// APIToken is an Okta API token with read access to users and groups.
+	APIToken string `json:"apiToken,omitempty" norman:"type=password"`
+	// UserIDAttribute is the Okta user profile attribute released as the UID in SAML assertions.
+	// It's used to build user principal IDs, and defaults to "login".
+	UserIDAttribute string `json:"userIdAttribute,omitempty" norman:"default=login"`
+	// SearchLimit is the maximum number of users and groups returned by a search.
+	SearchLimit int64 `json:"searchLimit,omitempty" norman:"default=100"`
 }
 
 type ShibbolethConfig struct {
 	SamlConfig     `json:",inline" mapstructure:",squash"`
 	OpenLdapConfig LdapFields `json:"openLdapConfig"`
+	// EntitlementMappings map the values of entitlement and affiliation attributes to group principals.
+	EntitlementMappings []ShibbolethEntitlementMapping `json:"entitlementMappings,omitempty"`
+}
+
+// ShibbolethEntitlementMapping maps the values of an attribute released by the IdP, like eduPersonEntitlement URNs
+// or eduPersonScopedAffiliation values, to group principals.
+type ShibbolethEntitlementMapping struct {
+	// Attribute is the name or OID of the attribute the mapping applies to, e.g. eduPersonEntitlement.
+	Attribute string `json:"attribute" norman:"required"`
+	// Pattern is the regular expression the values of the attribute are matched against.
+	// The whole value must match, e.g. urn:mace:example.org:rancher:(.+).
+	Pattern string `json:"pattern" norman:"required"`
+	// Group is the name of the group principal of the matching values. It can reference the submatches of the
+	// pattern, e.g. $1, and defaults to the value itself.
+	Group string `json:"group,omitempty"`
 }
 
 type AuthSystemImages struct {
@@ -619,6 +1163,15 @@ type OIDCApplyInput struct {
 
 type KeyCloakOIDCConfig struct {
 	OIDCConfig `json:",inline" mapstructure:",squash"`
+
+	// RealmRolesAsGroups when true maps the roles in the realm_access.roles claim to group principals.
+	RealmRolesAsGroups bool `json:"realmRolesAsGroups,omitempty"`
+	// ClientRolesAsGroups is a list of client IDs whose resource_access.<client>.roles claim is mapped to group principals.
+	ClientRolesAsGroups []string `json:"clientRolesAsGroups,omitempty"`
+	// RoleGroupPrefix is prepended to the name of every group principal derived from a role.
+	// Client roles are additionally qualified with the client ID, e.g. <prefix><client>:<role>.
+	// It's required to map roles to groups, since it tells the groups derived from roles apart from the keycloak groups.
+	RoleGroupPrefix string `json:"roleGroupPrefix,omitempty"`
 }
 
 // +genclient
@@ -651,3 +1204,294 @@ type GenericOIDCTestOutput struct {
 type GenericOIDCApplyInput struct {
 	OIDCApplyInput `json:",inline" mapstructure:",squash"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExternalAuthConfig holds the configuration of the external authentication webhook provider, which delegates
+// credential validation and principal lookups to an HTTPS endpoint operated outside of Rancher.
+type ExternalAuthConfig struct {
+	AuthConfig `json:",inline" mapstructure:",squash"`
+
+	// Endpoint is the HTTPS URL of the webhook that every request is POSTed to.
+	Endpoint string `json:"endpoint,omitempty" norman:"required"`
+	// Certificate is an optional PEM encoded CA bundle used to verify the webhook's serving certificate.
+	Certificate string `json:"certificate,omitempty"`
+	// ClientCert is an optional PEM encoded certificate presented to the webhook for mutual TLS.
+	ClientCert string `json:"clientCert,omitempty"`
+	// ClientKey is the PEM encoded private key of ClientCert.
+	ClientKey string `json:"clientKey,omitempty" norman:"type=password"`
+	// HMACSecret is the shared secret used to sign the body of every request sent to the webhook.
+	HMACSecret string `json:"hmacSecret,omitempty" norman:"type=password,required"`
+	// TimeoutSeconds is the timeout of a single request to the webhook.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" norman:"default=10,notnullable"`
+}
+
+// ExternalAuthTestAndApplyInput is the input of the testAndApply action of the external authentication provider.
+// The credentials are validated against the webhook before the configuration is saved.
+type ExternalAuthTestAndApplyInput struct {
+	ExternalAuthConfig ExternalAuthConfig `json:"externalAuthConfig,omitempty"`
+	Username           string             `json:"username"`
+	Password           string             `json:"password" norman:"type=password,required"`
+	Enabled            bool               `json:"enabled,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KerberosConfig holds the configuration of the Kerberos SPNEGO provider, which authenticates users of domain-joined
+// machines with the Negotiate tokens sent by their browsers and maps them to users and groups of a directory provider.
+type KerberosConfig struct {
+	AuthConfig `json:",inline" mapstructure:",squash"`
+
+	// Keytab is the base64 encoded keytab holding the keys of ServicePrincipalName.
+	Keytab string `json:"keytab,omitempty" norman:"type=password,required"`
+	// ServicePrincipalName is the principal of Rancher in the keytab, e.g. HTTP/rancher.example.com.
+	ServicePrincipalName string `json:"servicePrincipalName,omitempty" norman:"required"`
+	// Realms restricts the realms users are accepted from. Users of any realm are accepted if empty.
+	Realms []string `json:"realms,omitempty"`
+	// DirectoryProvider is the name of the provider used to look up the users and their groups.
+	// The provider must be configured, but doesn't need to be enabled.
+	DirectoryProvider string `json:"directoryProvider,omitempty" norman:"type=enum,options=activedirectory|openldap|freeipa,required"`
+	// MaxClockSkewSeconds is the maximum allowed difference between the clocks of the client and Rancher.
+	MaxClockSkewSeconds int64 `json:"maxClockSkewSeconds,omitempty" norman:"default=300"`
+}
+
+// KerberosTestAndApplyInput is the input of the testAndApply action of the Kerberos provider.
+// The request must be authenticated with a Negotiate token, which is validated before the configuration is saved.
+type KerberosTestAndApplyInput struct {
+	KerberosConfig KerberosConfig `json:"kerberosConfig,omitempty"`
+	Enabled        bool           `json:"enabled,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClientCertConfig holds the configuration of the X.509 client certificate provider, which authenticates users by the
+// certificates they present to the Rancher API, such as the ones issued on smartcards and PIV credentials.
+// Rancher only requests client certificates if the tls-request-client-certificates setting is enabled.
+type ClientCertConfig struct {
+	AuthConfig `json:",inline" mapstructure:",squash"`
+
+	// CACertificates is the PEM encoded bundle of the CAs client certificates must chain up to.
+	CACertificates string `json:"caCertificates,omitempty" norman:"required"`
+	// UsernameField is the field of the certificate users are identified by.
+	UsernameField string `json:"usernameField,omitempty" norman:"type=enum,options=commonName|email|upn,default=commonName"`
+	// GroupField is the subject field whose values are mapped to group principals. No groups are mapped if empty.
+	GroupField string `json:"groupField,omitempty" norman:"type=enum,options=organizationalUnit|organization"`
+	// OCSPEnabled checks the revocation status of certificates with the OCSP responders named in them.
+	OCSPEnabled bool `json:"ocspEnabled,omitempty"`
+	// CRLEnabled checks the revocation status of certificates with the CRLs of their distribution points.
+	// If OCSP is enabled as well, CRLs are only used when the status can't be determined with OCSP.
+	CRLEnabled bool `json:"crlEnabled,omitempty"`
+	// RevocationSoftFail accepts certificates whose revocation status can't be determined.
+	RevocationSoftFail bool `json:"revocationSoftFail,omitempty"`
+	// ClientCertificateHeader is the header a TLS terminating proxy in front of Rancher passes the URL encoded PEM
+	// client certificate in, e.g. ssl-client-cert for ingress-nginx. The proxy must overwrite the header on every request.
+	// The header is only read from the proxies of the auth-trusted-proxy-cidrs setting, as it holds no proof of the
+	// client having the private key: the requests from other addresses must present their certificate to Rancher in
+	// the TLS handshake.
+	ClientCertificateHeader string `json:"clientCertificateHeader,omitempty"`
+}
+
+// ClientCertTestAndApplyInput is the input of the testAndApply action of the X.509 client certificate provider.
+// The client certificate of the request is validated before the configuration is saved.
+type ClientCertTestAndApplyInput struct {
+	ClientCertConfig ClientCertConfig `json:"clientCertConfig,omitempty"`
+	Enabled          bool             `json:"enabled,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="PROVIDER",type="string",JSONPath=".spec.provider"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JITProvisioningPolicy grants roles to users on their first login, based on their group principals and attributes.
+type JITProvisioningPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the desired state of the policy.
+	Spec JITProvisioningPolicySpec `json:"spec"`
+}
+
+// JITProvisioningPolicySpec selects the users the policy applies to and the roles granted to them.
+type JITProvisioningPolicySpec struct {
+	// Provider restricts the policy to users logging in with this auth provider, e.g. openldap.
+	// The policy applies to users of every provider if empty.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// GroupPrincipals selects the users who are members of any of these group principals,
+	// e.g. openldap_group://cn=platform-team,ou=groups,dc=example,dc=com.
+	// +optional
+	GroupPrincipals []string `json:"groupPrincipals,omitempty"`
+
+	// Attributes selects the users having all these attributes, as reported by their auth provider in the user attribute.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// GlobalRoles are the names of the global roles bound to the selected users.
+	// +optional
+	GlobalRoles []string `json:"globalRoles,omitempty"`
+
+	// ClusterRoles are the role templates bound to the selected users in the clusters matching a label selector.
+	// +optional
+	ClusterRoles []JITClusterRole `json:"clusterRoles,omitempty"`
+
+	// ProjectRoles are the role templates bound to the selected users in projects.
+	// +optional
+	ProjectRoles []JITProjectRole `json:"projectRoles,omitempty"`
+}
+
+// JITClusterRole binds a role template in the clusters matching a label selector.
+type JITClusterRole struct {
+	// RoleTemplateName is the name of the cluster role template.
+	// +kubebuilder:validation:Required
+	RoleTemplateName string `json:"roleTemplateName"`
+
+	// ClusterSelector selects the clusters by label. An empty selector selects every cluster.
+	// +optional
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+// JITProjectRole binds a role template in projects.
+type JITProjectRole struct {
+	// RoleTemplateName is the name of the project role template.
+	// +kubebuilder:validation:Required
+	RoleTemplateName string `json:"roleTemplateName"`
+
+	// ProjectNames are the names of the projects in the <cluster>:<project> format.
+	// +kubebuilder:validation:Required
+	ProjectNames []string `json:"projectNames"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="USER",type="string",JSONPath=".spec.userName"
+// +kubebuilder:printcolumn:name="ROLE",type="string",JSONPath=".spec.globalRoleName"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="EXPIRES",type="date",JSONPath=".status.expiresAt"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BreakGlassAccount is an emergency access account, for when the identity provider is down. It manages a local user
+// which stays disabled while its credential is sealed. The holder of the credential activates the account with a
+// reason, which enables the user and grants it a global role until the activation expires. The credential is then
+// burnt, and the account must be sealed again with a new one.
+type BreakGlassAccount struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the user of the account and what it's granted once activated.
+	Spec BreakGlassAccountSpec `json:"spec"`
+
+	// Status is the state of the account and its activations.
+	// +optional
+	Status BreakGlassAccountStatus `json:"status,omitempty"`
+}
+
+// BreakGlassAccountSpec is the local user of a break glass account and the access granted to it.
+type BreakGlassAccountSpec struct {
+	// UserName is the name of the local user managed by the account. Immutable.
+	// +kubebuilder:validation:Required
+	UserName string `json:"userName"`
+
+	// GlobalRoleName is the name of the global role granted to the user while the account is active.
+	// +kubebuilder:validation:Required
+	GlobalRoleName string `json:"globalRoleName"`
+
+	// MaxDuration is the longest the account can be activated for at once, as a duration like "1h".
+	// +kubebuilder:validation:Required
+	MaxDuration string `json:"maxDuration"`
+}
+
+// BreakGlassAccountStatus is the state of a break glass account.
+type BreakGlassAccountStatus struct {
+	// State is one of "Unsealed", until a credential is sealed, "Sealed", "Active" or "Ended", once the activation
+	// expired or was ended.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// SealedBy is the name of the user who sealed the current credential.
+	// +optional
+	SealedBy string `json:"sealedBy,omitempty"`
+
+	// SealedAt is when the current credential was sealed.
+	// +optional
+	SealedAt *metav1.Time `json:"sealedAt,omitempty"`
+
+	// ExpiresAt is when the current activation expires.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// BindingName is the name of the global role binding granting the role of the current activation.
+	// +optional
+	BindingName string `json:"bindingName,omitempty"`
+
+	// Activations are the latest activations of the account, most recent last, as an audit trail.
+	// +optional
+	Activations []BreakGlassActivation `json:"activations,omitempty"`
+}
+
+// BreakGlassActivation is an activation of a break glass account.
+type BreakGlassActivation struct {
+	// Reason is why the account was activated.
+	Reason string `json:"reason"`
+
+	// SourceAddress is the client address the account was activated from.
+	// +optional
+	SourceAddress string `json:"sourceAddress,omitempty"`
+
+	// ActivatedAt is when the account was activated.
+	ActivatedAt metav1.Time `json:"activatedAt"`
+
+	// ExpiresAt is when the activation expires.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+
+	// EndedAt is when the activation ended, at its expiry or earlier if it was ended by an administrator.
+	// +optional
+	EndedAt *metav1.Time `json:"endedAt,omitempty"`
+
+	// EndedBy is the name of the administrator who ended the activation before its expiry.
+	// +optional
+	EndedBy string `json:"endedBy,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CASConfig holds the configuration of the CAS provider, which authenticates users with the service tickets issued by
+// an Apereo CAS server, using the CAS 3.0 protocol.
+type CASConfig struct {
+	AuthConfig `json:",inline" mapstructure:",squash"`
+
+	// ServerURL is the URL prefix of the CAS server, e.g. https://cas.example.edu/cas.
+	ServerURL string `json:"serverUrl,omitempty" norman:"required"`
+	// Certificate is the PEM encoded CA certificate of the CAS server, if it isn't signed by a well-known CA.
+	Certificate string `json:"certificate,omitempty"`
+	// UserNameAttribute is the released attribute users are identified by. The CAS user is used if empty.
+	UserNameAttribute string `json:"userNameAttribute,omitempty"`
+	// DisplayNameAttribute is the released attribute holding the display name of users.
+	DisplayNameAttribute string `json:"displayNameAttribute,omitempty"`
+	// GroupsAttribute is the released attribute whose values are mapped to group principals.
+	GroupsAttribute string `json:"groupsAttribute,omitempty"`
+}
+
+type CASConfigTestOutput struct {
+	RedirectURL string `json:"redirectUrl"`
+}
+
+// CASConfigApplyInput is the input of the testAndApply action of the CAS provider.
+// The ticket is validated for the service before the configuration is saved.
+type CASConfigApplyInput struct {
+	CASConfig CASConfig `json:"casConfig,omitempty"`
+	Ticket    string    `json:"ticket,omitempty"`
+	Service   string    `json:"service,omitempty"`
+	Enabled   bool      `json:"enabled,omitempty"`
+}
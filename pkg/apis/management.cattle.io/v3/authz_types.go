@@ -111,12 +111,65 @@ type ProjectSpec struct {
 	// See https://kubernetes.io/docs/concepts/policy/limit-range/ for more details.
 	// +optional
 	ContainerDefaultResourceLimit *ContainerResourceLimit `json:"containerDefaultResourceLimit,omitempty"`
+
+	// ParentProject is the name of the parent project of the project, in the same cluster. The members of the parent
+	// project are members of the project too, and the project inherits the NamespaceDefaultResourceQuota and the
+	// ContainerDefaultResourceLimit of its parent when it has none.
+	// +optional
+	ParentProject string `json:"parentProject,omitempty"`
+
+	// DirectoryAttributeMemberships are rules granting a role in the project to the users of an authentication
+	// provider whose directory attribute holds a value. The memberships are granted and revoked as the attributes of the
+	// users change in the directory.
+	// +optional
+	DirectoryAttributeMemberships []DirectoryAttributeMembership `json:"directoryAttributeMemberships,omitempty"`
+
+	// MemberSearchScope restricts the principals the members of the project who aren't admins can search and add to
+	// the project. It overrides the principal-search-scopes setting of the auth providers.
+	// +optional
+	MemberSearchScope *PrincipalSearchScope `json:"memberSearchScope,omitempty"`
 }
 
 func (p *ProjectSpec) ObjClusterName() string {
 	return p.ClusterName
 }
 
+// DirectoryAttributeMembership grants a role in a project to the users whose directory attribute holds a value,
+// e.g. departmentNumber=4200.
+type DirectoryAttributeMembership struct {
+	// Provider is the name of the authentication provider whose users are searched, e.g. openldap or activedirectory.
+	// +kubebuilder:validation:Required
+	Provider string `json:"provider"`
+
+	// Attribute is the name of the directory attribute of the users.
+	// +kubebuilder:validation:Required
+	Attribute string `json:"attribute"`
+
+	// Value is the value the attribute of the users must hold.
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
+
+	// RoleTemplateName is the name of the project role template granted to the users.
+	// +kubebuilder:validation:Required
+	RoleTemplateName string `json:"roleTemplateName"`
+}
+
+// PrincipalSearchScope designates the groups and organizational units whose principals can be searched and added as
+// members. A principal is in the scope if it's one of the groups, a member of one of them, or if its distinguished name
+// is in one of the organizational units.
+type PrincipalSearchScope struct {
+	// GroupPrincipals are the designated groups, e.g. openldap_group://cn=team-a,ou=groups,dc=example,dc=com. Their
+	// members are only in the scope when their auth provider can list the members of groups.
+	// +optional
+	GroupPrincipals []string `json:"groupPrincipals,omitempty"`
+
+	// SearchBases are the distinguished names of the designated organizational units, e.g.
+	// ou=team-a,dc=example,dc=com. They only apply to the directory providers, whose principals are named by their
+	// distinguished name.
+	// +optional
+	SearchBases []string `json:"searchBases,omitempty"`
+}
+
 // +genclient
 // +genclient:nonNamespaced
 // +kubebuilder:resource:scope=Cluster
@@ -158,6 +211,12 @@ type GlobalRole struct {
 	// +optional
 	InheritedClusterRoles []string `json:"inheritedClusterRoles,omitempty"`
 
+	// InheritedClusterRolesNamespaceSelector scopes the InheritedClusterRoles to the namespaces matching it in each
+	// downstream cluster, instead of granting them cluster-wide. An empty selector matches every namespace.
+	// It isn't supported while the aggregated-roletemplates feature is enabled, the API refuses it.
+	// +optional
+	InheritedClusterRolesNamespaceSelector *metav1.LabelSelector `json:"inheritedClusterRolesNamespaceSelector,omitempty"`
+
 	// NamespacedRules are the rules that are active in each namespace of this GlobalRole.
 	// These are applied to the local cluster only.
 	// * has no special meaning in the keys - these keys are read as raw strings
@@ -170,6 +229,12 @@ type GlobalRole struct {
 	// +optional
 	InheritedFleetWorkspacePermissions *FleetWorkspacePermission `json:"inheritedFleetWorkspacePermissions,omitempty"`
 
+	// DefaultPodSecurityAdmissionConfigurationTemplateName is the name of the PodSecurityAdmissionConfigurationTemplate
+	// applied to the clusters created by the users bound to this GlobalRole. The clusters are reverted to it when
+	// they're changed to another template, so that the provisioners can't relax it.
+	// +optional
+	DefaultPodSecurityAdmissionConfigurationTemplateName string `json:"defaultPodSecurityAdmissionConfigurationTemplateName,omitempty"`
+
 	// Status is the most recently observed status of the GlobalRole.
 	// +optional
 	Status GlobalRoleStatus `json:"status,omitempty"`
@@ -352,13 +417,114 @@ type RoleTemplate struct {
 	// +optional
 	RoleTemplateNames []string `json:"roleTemplateNames,omitempty" norman:"type=array[reference[roleTemplate]]"`
 
+	// ExcludedRules are subtracted from the rules this RoleTemplate grants, including the inherited ones, so that a
+	// RoleTemplate can inherit another one without some of its resources or verbs. A RoleTemplate excluding rules is
+	// rendered into a single ClusterRole holding the remaining rules, instead of inheriting the ClusterRoles of the
+	// inherited RoleTemplates. A wildcard verb is narrowed to the standard verbs, but a wildcard API group, resource or
+	// non-resource URL, or a rule without resource names, is only excluded by a matching wildcard, or no resource names.
+	// The API refuses the exclusions matching only part of such a rule, which would be kept as is.
+	// ExcludedRules are ignored for external RoleTemplates.
+	// +optional
+	ExcludedRules []rbacv1.PolicyRule `json:"excludedRules,omitempty"`
+
 	// Administrative field is deprecated and no longer used.
 	// +optional
 	Administrative bool `json:"administrative,omitempty"`
 }
 
+// RoleTemplateRenderInput selects the cluster, or the project, to render a role template for, and the subject of the
+// rendered bindings, which defaults to the caller. Rules, ExternalRules, RoleTemplateNames and ExcludedRules replace
+// those of the role template when set, so that changes can be reviewed before saving them.
+type RoleTemplateRenderInput struct {
+	ClusterID         string              `json:"clusterId,omitempty" norman:"type=reference[cluster]"`
+	ProjectID         string              `json:"projectId,omitempty" norman:"type=reference[project]"`
+	UserID            string              `json:"userId,omitempty" norman:"type=reference[user]"`
+	GroupPrincipalID  string              `json:"groupPrincipalId,omitempty" norman:"type=reference[principal]"`
+	Rules             []rbacv1.PolicyRule `json:"rules,omitempty"`
+	ExternalRules     []rbacv1.PolicyRule `json:"externalRules,omitempty"`
+	RoleTemplateNames []string            `json:"roleTemplateNames,omitempty"`
+	ExcludedRules     []rbacv1.PolicyRule `json:"excludedRules,omitempty"`
+}
+
+// RoleTemplateRenderOutput holds the ClusterRoles, ClusterRoleBindings and RoleBindings created in a downstream cluster
+// for a binding of a role template, including those of its inherited role templates.
+type RoleTemplateRenderOutput struct {
+	ClusterRoles        []RenderedRole    `json:"clusterRoles"`
+	ClusterRoleBindings []RenderedBinding `json:"clusterRoleBindings"`
+	RoleBindings        []RenderedBinding `json:"roleBindings"`
+	Warnings            []string          `json:"warnings,omitempty"`
+}
+
+// RenderedRole is a rendered ClusterRole. The rules of external roles are those of existing ClusterRoles, which are
+// not created.
+type RenderedRole struct {
+	Name     string              `json:"name"`
+	External bool                `json:"external,omitempty"`
+	Rules    []rbacv1.PolicyRule `json:"rules"`
+}
+
+// RenderedBinding is a rendered ClusterRoleBinding, or RoleBinding when it has a namespace, of a ClusterRole.
+type RenderedBinding struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace,omitempty"`
+	RoleName    string `json:"roleName"`
+	SubjectKind string `json:"subjectKind"`
+	SubjectName string `json:"subjectName"`
+}
+
+// RoleTemplateSimulateInput holds the changes of a role template to simulate. Rules, ExternalRules, RoleTemplateNames
+// and ExcludedRules replace those of the role template when set.
+type RoleTemplateSimulateInput struct {
+	Rules             []rbacv1.PolicyRule `json:"rules,omitempty"`
+	ExternalRules     []rbacv1.PolicyRule `json:"externalRules,omitempty"`
+	RoleTemplateNames []string            `json:"roleTemplateNames,omitempty"`
+	ExcludedRules     []rbacv1.PolicyRule `json:"excludedRules,omitempty"`
+}
+
+// RoleTemplateSimulateOutput holds the existing bindings whose permissions a change of a role template would change,
+// by cluster, with the subjects they're granted to and the rules they'd gain and lose.
+type RoleTemplateSimulateOutput struct {
+	// RoleTemplates are the role templates whose rules change: the changed one, and those inheriting it.
+	RoleTemplates []SimulatedRoleTemplate `json:"roleTemplates"`
+	// Clusters are the clusters where existing bindings grant the role templates whose rules change.
+	Clusters []SimulatedCluster `json:"clusters"`
+	// SubjectCount is the number of distinct subjects of the bindings, across all the clusters.
+	SubjectCount int      `json:"subjectCount"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// SimulatedRoleTemplate is a role template, or a global role inheriting it in the downstream clusters, whose rules
+// change, with the rules it would gain and lose.
+type SimulatedRoleTemplate struct {
+	Kind         string              `json:"kind"`
+	Name         string              `json:"name"`
+	AddedRules   []rbacv1.PolicyRule `json:"addedRules,omitempty"`
+	RemovedRules []rbacv1.PolicyRule `json:"removedRules,omitempty"`
+}
+
+// SimulatedCluster holds the bindings of a cluster whose permissions change, their subjects, and the rules they'd gain
+// and lose altogether.
+type SimulatedCluster struct {
+	ClusterName  string              `json:"clusterName"`
+	Bindings     []SimulatedBinding  `json:"bindings"`
+	Subjects     []string            `json:"subjects"`
+	AddedRules   []rbacv1.PolicyRule `json:"addedRules,omitempty"`
+	RemovedRules []rbacv1.PolicyRule `json:"removedRules,omitempty"`
+}
+
+// SimulatedBinding is a ClusterRoleTemplateBinding, ProjectRoleTemplateBinding or GlobalRoleBinding whose permissions
+// change. The name of the namespaced bindings is <namespace>:<name>.
+type SimulatedBinding struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	RoleName    string `json:"roleName"`
+	SubjectKind string `json:"subjectKind"`
+	SubjectName string `json:"subjectName"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
 
 // ProjectRoleTemplateBinding is the object representing membership of a subject in a project with permissions
 // specified by a given role template.
@@ -395,6 +561,45 @@ type ProjectRoleTemplateBinding struct {
 	// Deprecated.
 	// +optional
 	ServiceAccount string `json:"serviceAccount,omitempty" norman:"nocreate,noupdate"`
+
+	// ExpiresAt is the time after which the binding is removed, granting temporary access to the project.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// TTL is how long the binding is kept after its creation, as a duration like "8h". It's an alternative to
+	// ExpiresAt, the earliest expiration applying when both are set.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// NamespaceSelector restricts the binding to the namespaces of the project matching it, instead of all of them.
+	// Namespaces joining or leaving the project, or whose labels change, are bound or unbound accordingly.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Status is the most recently observed status of the ProjectRoleTemplateBinding.
+	// +optional
+	Status ProjectRoleTemplateBindingStatus `json:"status,omitempty"`
+}
+
+// ProjectRoleTemplateBindingStatus represents the most recently observed status of the ProjectRoleTemplateBinding.
+type ProjectRoleTemplateBindingStatus struct {
+	// ObservedGenerationRemote is the most recent generation (metadata.generation in PRTB)
+	// observed by the controller of the downstream cluster. Populated by the system.
+	// +optional
+	ObservedGenerationRemote int64 `json:"observedGenerationRemote,omitempty"`
+
+	// SummaryRemote represents the summary of the resources created in the downstream cluster. One of "Completed" or "Error".
+	// +optional
+	SummaryRemote string `json:"summaryRemote,omitempty"`
+
+	// RemoteConditions is a slice of Condition, indicating the status of backing RBAC objects created in the downstream cluster.
+	// +optional
+	RemoteConditions []metav1.Condition `json:"remoteConditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Conditions summarizes whether the binding took effect: PrincipalResolved tells whether its subject was resolved
+	// to a user or a group, and Rendered whether its RBAC objects were created in the downstream cluster, or why not.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 func (p *ProjectRoleTemplateBinding) ObjClusterName() string {
@@ -445,6 +650,21 @@ type ClusterRoleTemplateBinding struct {
 	// +kubebuilder:validation:Required
 	RoleTemplateName string `json:"roleTemplateName" norman:"required,noupdate,type=reference[roleTemplate]"`
 
+	// ExpiresAt is the time after which the binding is removed, granting temporary access to the cluster.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// TTL is how long the binding is kept after its creation, as a duration like "8h". It's an alternative to
+	// ExpiresAt, the earliest expiration applying when both are set.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// NamespaceSelector scopes the permissions of the role template to the namespaces of the cluster matching it,
+	// binding them in each of these namespaces instead of cluster-wide. Immutable.
+	// It isn't supported while the aggregated-roletemplates feature is enabled, the API refuses it.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty" norman:"noupdate"`
+
 	// Status is the most recently observed status of the ClusterRoleTemplateBinding. BEWARE. This is read from and written to by __two__ controllers.
 	// +optional
 	Status ClusterRoleTemplateBindingStatus `json:"status,omitempty"`
@@ -485,8 +705,689 @@ type ClusterRoleTemplateBindingStatus struct {
 	// RemoteConditions is a slice of Condition, indicating the status of backing RBAC objects created in the downstream cluster.
 	// +optional
 	RemoteConditions []metav1.Condition `json:"remoteConditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// Conditions summarizes whether the binding took effect: PrincipalResolved tells whether its subject was resolved
+	// to a user or a group, and Rendered whether its RBAC objects were created in the downstream cluster, or why not.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 func (c *ClusterRoleTemplateBinding) ObjClusterName() string {
 	return c.ClusterName
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="USER",type="string",JSONPath=".spec.userName"
+// +kubebuilder:printcolumn:name="ROLE",type="string",JSONPath=".spec.roleTemplateName"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AccessRequest is the request of a user for a role in a cluster or a project, for a limited time.
+// Once approved, the role is granted with a role template binding which expires at the end of the requested duration.
+type AccessRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the requested access.
+	Spec AccessRequestSpec `json:"spec"`
+
+	// Status is the decision on the request and the binding granting it.
+	// +optional
+	Status AccessRequestStatus `json:"status,omitempty"`
+}
+
+// AccessRequestSpec is the role a user requests, where, for how long and why.
+type AccessRequestSpec struct {
+	// UserName is the name of the user requesting the access. Immutable.
+	// +kubebuilder:validation:Required
+	UserName string `json:"userName"`
+
+	// ClusterName is the name of the cluster the role is requested in. Either ClusterName or ProjectName is set. Immutable.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// ProjectName is the name of the project the role is requested in, in the <cluster>:<project> format.
+	// Either ClusterName or ProjectName is set. Immutable.
+	// +optional
+	ProjectName string `json:"projectName,omitempty"`
+
+	// RoleTemplateName is the name of the requested role template. Immutable.
+	// +kubebuilder:validation:Required
+	RoleTemplateName string `json:"roleTemplateName"`
+
+	// Justification is why the user needs the access, for the approvers and the audit trail. Immutable.
+	// +kubebuilder:validation:Required
+	Justification string `json:"justification"`
+
+	// Duration is how long the access is granted for once approved, as a duration like "4h". Immutable.
+	// +kubebuilder:validation:Required
+	Duration string `json:"duration"`
+}
+
+// AccessRequestStatus is the decision on an access request.
+type AccessRequestStatus struct {
+	// State is one of "Pending", "Approved" or "Denied".
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// DecidedBy is the name of the user who approved or denied the request.
+	// +optional
+	DecidedBy string `json:"decidedBy,omitempty"`
+
+	// DecidedAt is when the request was approved or denied.
+	// +optional
+	DecidedAt *metav1.Time `json:"decidedAt,omitempty"`
+
+	// Reason is the comment of the approver on the decision.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// BindingName is the namespaced name, as <namespace>:<name>, of the role template binding granting the approved request.
+	// +optional
+	BindingName string `json:"bindingName,omitempty"`
+
+	// ExpiresAt is when the access granted for the approved request expires.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GroupMembershipRule grants roles to directory groups in the clusters and projects matching label selectors.
+// It's reconciled continuously, so the clusters and projects created after the rule are granted too.
+type GroupMembershipRule struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the desired state of the rule.
+	Spec GroupMembershipRuleSpec `json:"spec"`
+}
+
+// GroupMembershipRuleSpec selects the groups granted roles and where.
+type GroupMembershipRuleSpec struct {
+	// GroupPrincipals are the group principals granted the roles,
+	// e.g. openldap_group://cn=platform-team,ou=groups,dc=example,dc=com.
+	// +optional
+	GroupPrincipals []string `json:"groupPrincipals,omitempty"`
+
+	// GroupPrincipalPatterns are regular expressions selecting the group principals granted the roles among the groups
+	// of the users who logged in, e.g. ^openldap_group://cn=team-[a-z]+,ou=groups,dc=example,dc=com$.
+	// +optional
+	GroupPrincipalPatterns []string `json:"groupPrincipalPatterns,omitempty"`
+
+	// ClusterRoles are the role templates bound to the groups in the clusters matching a label selector.
+	// +optional
+	ClusterRoles []GroupMembershipClusterRole `json:"clusterRoles,omitempty"`
+
+	// ProjectRoles are the role templates bound to the groups in the projects matching a label selector.
+	// +optional
+	ProjectRoles []GroupMembershipProjectRole `json:"projectRoles,omitempty"`
+}
+
+// GroupMembershipClusterRole binds a role template in the clusters matching a label selector.
+type GroupMembershipClusterRole struct {
+	// RoleTemplateName is the name of the cluster role template.
+	// +kubebuilder:validation:Required
+	RoleTemplateName string `json:"roleTemplateName"`
+
+	// ClusterSelector selects the clusters by label. An empty selector selects every cluster.
+	// +optional
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+// GroupMembershipProjectRole binds a role template in the projects matching a label selector.
+type GroupMembershipProjectRole struct {
+	// RoleTemplateName is the name of the project role template.
+	// +kubebuilder:validation:Required
+	RoleTemplateName string `json:"roleTemplateName"`
+
+	// ClusterSelector restricts the projects to those of the clusters matching this label selector.
+	// An empty selector selects every cluster.
+	// +optional
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// ProjectSelector selects the projects by label. An empty selector selects every project.
+	// +optional
+	ProjectSelector metav1.LabelSelector `json:"projectSelector,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="AUTO-REPAIR",type="boolean",JSONPath=".spec.autoRepair"
+// +kubebuilder:printcolumn:name="DRIFTS",type="integer",JSONPath=".status.driftCount"
+// +kubebuilder:printcolumn:name="CHECKED",type="date",JSONPath=".status.lastCheckTime"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RBACDriftReport reports the ClusterRoles, ClusterRoleBindings and RoleBindings Rancher manages in a downstream
+// cluster for the role template bindings which were edited or deleted in the cluster. There's one report per cluster,
+// named after it, checked periodically.
+type RBACDriftReport struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the cluster checked and whether the drift is repaired.
+	Spec RBACDriftReportSpec `json:"spec"`
+
+	// Status is the drift found by the last check.
+	// +optional
+	Status RBACDriftReportStatus `json:"status,omitempty"`
+}
+
+// RBACDriftReportSpec is the cluster checked for drift and whether the drift is repaired.
+type RBACDriftReportSpec struct {
+	// ClusterName is the name of the cluster checked. Immutable.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName"`
+
+	// AutoRepair enables repairing the drift found: the edited objects are deleted, and the role template bindings
+	// owning the edited and deleted objects are reconciled again to recreate them.
+	// +optional
+	AutoRepair bool `json:"autoRepair,omitempty"`
+}
+
+// RBACDriftReportStatus is the drift found by the last check of the cluster.
+type RBACDriftReportStatus struct {
+	// LastCheckTime is when the cluster was last checked.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// ObservedGeneration is the generation of the report the last check was made for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// DriftCount is the number of drifted objects found by the last check.
+	// +optional
+	DriftCount int `json:"driftCount,omitempty"`
+
+	// Drifts are the drifted objects found by the last check.
+	// +optional
+	Drifts []RBACDrift `json:"drifts,omitempty"`
+
+	// Error is why the last check failed, if it did.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// RBACDrift is an object of the downstream cluster which differs from the one Rancher manages for a role template
+// binding.
+type RBACDrift struct {
+	// Kind is one of "ClusterRole", "ClusterRoleBinding" or "RoleBinding".
+	Kind string `json:"kind"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the RoleBindings.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Type is "Missing" for the deleted objects and "Modified" for the edited ones.
+	Type string `json:"type"`
+
+	// Message describes the difference.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// BindingName is the namespaced name, as <namespace>:<name>, of a role template binding the object is managed for.
+	BindingName string `json:"bindingName"`
+
+	// Repaired is true when the drift was repaired by the check.
+	// +optional
+	Repaired bool `json:"repaired,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="AUTO-CLEANUP",type="boolean",JSONPath=".spec.autoCleanup"
+// +kubebuilder:printcolumn:name="ORPHANED",type="integer",JSONPath=".status.orphanedCount"
+// +kubebuilder:printcolumn:name="CHECKED",type="date",JSONPath=".status.lastCheckTime"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OrphanedBindingReport reports the ClusterRoleTemplateBindings and ProjectRoleTemplateBindings of a cluster whose
+// subject doesn't exist anymore: their user was deleted, their principal isn't linked to any user, or the auth
+// provider of their principal was removed or disabled. There's one report per cluster, named after it, checked
+// periodically.
+type OrphanedBindingReport struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the cluster checked and which orphaned bindings are removed.
+	Spec OrphanedBindingReportSpec `json:"spec"`
+
+	// Status is the orphaned bindings found by the last check.
+	// +optional
+	Status OrphanedBindingReportStatus `json:"status,omitempty"`
+}
+
+// OrphanedBindingReportSpec is the cluster checked for orphaned bindings and which of them are removed.
+type OrphanedBindingReportSpec struct {
+	// ClusterName is the name of the cluster checked. Immutable.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName"`
+
+	// AutoCleanup enables removing all the orphaned bindings found by the checks. It only takes effect when the
+	// orphaned-binding-auto-cleanup-enabled setting is true.
+	// +optional
+	AutoCleanup bool `json:"autoCleanup,omitempty"`
+
+	// CleanupBindings are the namespaced names, as <namespace>:<name>, of the reported bindings to remove. Editing them
+	// triggers a check right away, removing those which are still orphaned.
+	// +optional
+	CleanupBindings []string `json:"cleanupBindings,omitempty"`
+}
+
+// OrphanedBindingReportStatus is the orphaned bindings found by the last check of the cluster.
+type OrphanedBindingReportStatus struct {
+	// LastCheckTime is when the cluster was last checked.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// ObservedGeneration is the generation of the report the last check was made for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// OrphanedCount is the number of orphaned bindings found by the last check, including the removed ones.
+	// +optional
+	OrphanedCount int `json:"orphanedCount,omitempty"`
+
+	// Bindings are the orphaned bindings found by the last check.
+	// +optional
+	Bindings []OrphanedBinding `json:"bindings,omitempty"`
+
+	// Error is why the last check failed, if it did.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// OrphanedBinding is a role template binding whose subject doesn't exist anymore.
+type OrphanedBinding struct {
+	// Kind is either "ClusterRoleTemplateBinding" or "ProjectRoleTemplateBinding".
+	Kind string `json:"kind"`
+
+	// BindingName is the namespaced name of the binding, as <namespace>:<name>.
+	BindingName string `json:"bindingName"`
+
+	// RoleTemplateName is the name of the role template the binding grants.
+	// +optional
+	RoleTemplateName string `json:"roleTemplateName,omitempty"`
+
+	// Subject is the user name or principal the binding grants the role template to.
+	Subject string `json:"subject"`
+
+	// Reason is "UserNotFound" when the user was deleted, "PrincipalNotFound" when no user is linked to the principal,
+	// "ProviderRemoved" when the auth provider of the principal doesn't exist, and "ProviderDisabled" when it's
+	// disabled.
+	Reason string `json:"reason"`
+
+	// Message describes why the binding is orphaned.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Removed is true when the binding was removed by the check.
+	// +optional
+	Removed bool `json:"removed,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="UNBOUND",type="integer",JSONPath=".status.unboundCount"
+// +kubebuilder:printcolumn:name="IDLE",type="integer",JSONPath=".status.idleCount"
+// +kubebuilder:printcolumn:name="CHECKED",type="date",JSONPath=".status.lastCheckTime"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RoleUsageReport reports which RoleTemplates and GlobalRoles are granted by bindings, and when the subjects of those
+// bindings were last active, so that the unused custom roles can be retired. There's a single report, named default,
+// checked periodically.
+type RoleUsageReport struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is how the usage of the roles is assessed.
+	// +optional
+	Spec RoleUsageReportSpec `json:"spec,omitempty"`
+
+	// Status is the usage of the roles found by the last check.
+	// +optional
+	Status RoleUsageReportStatus `json:"status,omitempty"`
+}
+
+// RoleUsageReportSpec is how the usage of the roles is assessed.
+type RoleUsageReportSpec struct {
+	// IdleDays is how many days without token activity from the subjects of its bindings make a bound role idle.
+	// Defaults to 90.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	IdleDays int `json:"idleDays,omitempty"`
+}
+
+// RoleUsageReportStatus is the usage of the roles found by the last check.
+type RoleUsageReportStatus struct {
+	// LastCheckTime is when the roles were last checked.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// ObservedGeneration is the generation of the report the last check was made for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// UnboundCount is the number of custom roles which aren't granted by any binding nor inherited by any role.
+	// +optional
+	UnboundCount int `json:"unboundCount,omitempty"`
+
+	// IdleCount is the number of custom roles whose bindings' subjects weren't active for idleDays.
+	// +optional
+	IdleCount int `json:"idleCount,omitempty"`
+
+	// Roles are the usage of every RoleTemplate and GlobalRole, found by the last check.
+	// +optional
+	Roles []RoleUsage `json:"roles,omitempty"`
+
+	// Error is why the last check failed, if it did.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// RoleUsage is the usage of a RoleTemplate or a GlobalRole.
+type RoleUsage struct {
+	// Kind is either "RoleTemplate" or "GlobalRole".
+	Kind string `json:"kind"`
+
+	// Name is the name of the role.
+	Name string `json:"name"`
+
+	// DisplayName is the human-readable name of the role.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Builtin is true when the role was created by Rancher.
+	// +optional
+	Builtin bool `json:"builtin,omitempty"`
+
+	// BindingCount is the number of ClusterRoleTemplateBindings, ProjectRoleTemplateBindings or GlobalRoleBindings
+	// granting the role.
+	// +optional
+	BindingCount int `json:"bindingCount,omitempty"`
+
+	// InheritedBy are the roles inheriting the role, as <kind>:<name>. RoleTemplates are inherited by the
+	// RoleTemplates listing them in roleTemplateNames, and by the GlobalRoles listing them in inheritedClusterRoles.
+	// +optional
+	InheritedBy []string `json:"inheritedBy,omitempty"`
+
+	// LastUsedTime is when a token of a subject of the role's bindings, or of the bindings of the roles inheriting it,
+	// was last used. It's kept by the next checks when the tokens are removed.
+	// +optional
+	LastUsedTime *metav1.Time `json:"lastUsedTime,omitempty"`
+
+	// State is "Unbound" when the role isn't granted by any binding nor inherited by any role, "Idle" when it wasn't
+	// used for idleDays, and "Active" otherwise.
+	State string `json:"state"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="DISPLAY-NAME",type="string",JSONPath=".spec.displayName"
+// +kubebuilder:printcolumn:name="CLUSTERS",type="integer",JSONPath=".status.clusterCount"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Organization groups clusters, along with their projects, so that their administration can be delegated to the
+// organization's admins without granting them access to the other clusters. The admins are bound the
+// organization-admin role template in each cluster of the organization, and can read the organization.
+type Organization struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the clusters and admins of the organization.
+	Spec OrganizationSpec `json:"spec"`
+
+	// Status is the clusters the organization currently holds.
+	// +optional
+	Status OrganizationStatus `json:"status,omitempty"`
+}
+
+// OrganizationSpec is the clusters and admins of an organization.
+type OrganizationSpec struct {
+	// DisplayName is the human-readable name of the organization.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Description is a description of the organization.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ClusterNames are the names of the clusters of the organization.
+	// +optional
+	ClusterNames []string `json:"clusterNames,omitempty"`
+
+	// ClusterSelector selects the clusters of the organization by label, on top of ClusterNames. The organization holds
+	// no cluster by label if it's not set, and every cluster if it's empty.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// Admins are the users and groups administering the clusters of the organization.
+	// +optional
+	Admins []OrganizationAdmin `json:"admins,omitempty"`
+}
+
+// OrganizationAdmin is a user or a group administering the clusters of an organization. Exactly one of UserName and
+// GroupPrincipalName is set.
+type OrganizationAdmin struct {
+	// UserName is the name of the user.
+	// +optional
+	UserName string `json:"userName,omitempty"`
+
+	// GroupPrincipalName is the name of the group principal, e.g. openldap_group://cn=platform,ou=groups,dc=example,dc=com.
+	// +optional
+	GroupPrincipalName string `json:"groupPrincipalName,omitempty"`
+}
+
+// OrganizationStatus is the clusters an organization currently holds.
+type OrganizationStatus struct {
+	// ObservedGeneration is the generation of the organization its clusters and admins were last reconciled for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ClusterCount is the number of clusters of the organization.
+	// +optional
+	ClusterCount int `json:"clusterCount,omitempty"`
+
+	// Clusters are the names of the existing clusters of the organization, sorted.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Error is why the admins couldn't be granted access to the clusters, if they couldn't.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="DEADLINE",type="date",JSONPath=".spec.deadline"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RecertificationCampaign is a review of the ClusterRoleTemplateBindings and ProjectRoleTemplateBindings of a cluster
+// and its projects. The reviewers approve or revoke each binding, and the bindings which weren't approved by the
+// deadline are removed. The campaigns are generated periodically, one per cluster.
+type RecertificationCampaign struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the cluster reviewed, by whom and until when.
+	Spec RecertificationCampaignSpec `json:"spec"`
+
+	// Status is the bindings under review and the decisions made on them.
+	// +optional
+	Status RecertificationCampaignStatus `json:"status,omitempty"`
+}
+
+// RecertificationCampaignSpec is the cluster reviewed by a campaign, by whom and until when.
+type RecertificationCampaignSpec struct {
+	// ClusterName is the name of the cluster whose bindings are reviewed. Immutable.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName"`
+
+	// Reviewers are the names of the users deciding on the bindings. They can't decide on their own bindings.
+	// +optional
+	Reviewers []string `json:"reviewers,omitempty"`
+
+	// Deadline is when the bindings which weren't approved are removed.
+	// +kubebuilder:validation:Required
+	Deadline metav1.Time `json:"deadline"`
+}
+
+// RecertificationCampaignStatus is the bindings under review by a campaign and the decisions made on them.
+type RecertificationCampaignStatus struct {
+	// State is "Open" until the deadline, and "Closed" once the bindings which weren't approved were removed.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// ClosedAt is when the campaign was closed.
+	// +optional
+	ClosedAt *metav1.Time `json:"closedAt,omitempty"`
+
+	// Items are the bindings under review, as they were when the campaign was generated.
+	// +optional
+	Items []RecertificationItem `json:"items,omitempty"`
+}
+
+// RecertificationItem is a binding under review and the decision made on it.
+type RecertificationItem struct {
+	// Kind is either "ClusterRoleTemplateBinding" or "ProjectRoleTemplateBinding".
+	Kind string `json:"kind"`
+
+	// BindingName is the namespaced name of the binding, as <namespace>:<name>.
+	BindingName string `json:"bindingName"`
+
+	// ProjectName is the name of the project of a ProjectRoleTemplateBinding, as <cluster>:<project>.
+	// +optional
+	ProjectName string `json:"projectName,omitempty"`
+
+	// RoleTemplateName is the name of the role template the binding grants.
+	RoleTemplateName string `json:"roleTemplateName"`
+
+	// Subject is the user name or principal the binding grants the role template to.
+	Subject string `json:"subject"`
+
+	// Decision is "Approved" or "Revoked" when a reviewer decided on the binding, and "Expired" when the binding was
+	// removed at the deadline without a decision.
+	// +optional
+	Decision string `json:"decision,omitempty"`
+
+	// DecidedBy is the name of the reviewer who decided on the binding.
+	// +optional
+	DecidedBy string `json:"decidedBy,omitempty"`
+
+	// DecidedAt is when the decision was made.
+	// +optional
+	DecidedAt *metav1.Time `json:"decidedAt,omitempty"`
+
+	// Comment is why the reviewer made the decision.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="GROUP",type="string",JSONPath=".spec.groupPrincipalName"
+// +kubebuilder:printcolumn:name="ROLE",type="string",JSONPath=".spec.roleTemplateName"
+// +kubebuilder:printcolumn:name="CLUSTERS",type="integer",JSONPath=".status.clusterCount"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MultiClusterRoleTemplateBinding binds a RoleTemplate to a group in all the clusters matching a label selector. A
+// ClusterRoleTemplateBinding is created in each matching cluster, including the clusters created or relabeled after
+// it, and removed from the clusters which don't match anymore.
+type MultiClusterRoleTemplateBinding struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object metadata; More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the group, the role template and the clusters of the binding.
+	Spec MultiClusterRoleTemplateBindingSpec `json:"spec"`
+
+	// Status is the clusters the binding currently applies to.
+	// +optional
+	Status MultiClusterRoleTemplateBindingStatus `json:"status,omitempty"`
+}
+
+// MultiClusterRoleTemplateBindingSpec is the group, the role template and the clusters of a
+// MultiClusterRoleTemplateBinding.
+type MultiClusterRoleTemplateBindingSpec struct {
+	// GroupPrincipalName is the name of the group principal bound, e.g. openldap_group://cn=platform,ou=groups,dc=example,dc=com.
+	// +kubebuilder:validation:Required
+	GroupPrincipalName string `json:"groupPrincipalName"`
+
+	// RoleTemplateName is the name of the cluster role template bound.
+	// +kubebuilder:validation:Required
+	RoleTemplateName string `json:"roleTemplateName"`
+
+	// ClusterSelector selects the clusters the role template is bound in by label. An empty selector selects every
+	// cluster.
+	// +kubebuilder:validation:Required
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+}
+
+// MultiClusterRoleTemplateBindingStatus is the clusters a MultiClusterRoleTemplateBinding currently applies to.
+type MultiClusterRoleTemplateBindingStatus struct {
+	// ObservedGeneration is the generation of the binding its clusters were last reconciled for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ClusterCount is the number of clusters the binding applies to.
+	// +optional
+	ClusterCount int `json:"clusterCount,omitempty"`
+
+	// Clusters are the names of the existing clusters matching the selector, sorted.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Error is why the role template couldn't be bound in the clusters, if it couldn't.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
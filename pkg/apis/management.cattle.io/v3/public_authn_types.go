@@ -55,6 +55,25 @@ type GenericLogin struct {
 	TTLMillis    int64  `json:"ttl,omitempty"`
 	Description  string `json:"description,omitempty" norman:"type=string,required"`
 	ResponseType string `json:"responseType,omitempty" norman:"type=string,required"` //json or cookie
+	// RefreshToken requests a short-lived token paired with a refresh token, for the json and kubeconfig response types.
+	RefreshToken bool `json:"refreshToken,omitempty"`
+	// TOTPCode is the current code of the TOTP second factor of the user, for the providers with username and password logins.
+	TOTPCode string `json:"totpCode,omitempty"`
+	// WebAuthn is the response of a security key of a local user to a login challenge, as a second factor, or instead
+	// of the password for passkeys.
+	WebAuthn WebAuthnAssertion `json:"webauthn,omitempty"`
+	// CaptchaResponse is the response to the challenge of auth-login-captcha-provider, required after repeated failed
+	// logins with a username and password.
+	CaptchaResponse string `json:"captchaResponse,omitempty"`
+}
+
+// WebAuthnAssertion is the response of an authenticator to a WebAuthn login challenge. The fields are base64url encoded.
+type WebAuthnAssertion struct {
+	CredentialID      string `json:"credentialId,omitempty"`
+	ClientDataJSON    string `json:"clientDataJSON,omitempty"`
+	AuthenticatorData string `json:"authenticatorData,omitempty"`
+	Signature         string `json:"signature,omitempty"`
+	UserHandle        string `json:"userHandle,omitempty"`
 }
 
 type BasicLogin struct {
@@ -251,3 +270,69 @@ type GenericOIDCLogin struct {
 	GenericLogin `json:",inline"`
 	Code         string `json:"code" norman:"type=string,required"`
 }
+
+// +genclient
+// +kubebuilder:skipversion
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ExternalAuthProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	AuthProvider      `json:",inline"`
+}
+
+// +genclient
+// +kubebuilder:skipversion
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type KerberosProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	AuthProvider      `json:",inline"`
+}
+
+// KerberosLogin is the input of the login action of the Kerberos provider.
+// The user is authenticated with the Negotiate token in the Authorization header of the request.
+type KerberosLogin struct {
+	GenericLogin `json:",inline"`
+}
+
+// +genclient
+// +kubebuilder:skipversion
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ClientCertProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	AuthProvider      `json:",inline"`
+}
+
+// ClientCertLogin is the input of the login action of the X.509 client certificate provider.
+// The user is authenticated with the client certificate presented to Rancher.
+type ClientCertLogin struct {
+	GenericLogin `json:",inline"`
+}
+
+// +genclient
+// +kubebuilder:skipversion
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CASProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	AuthProvider      `json:",inline"`
+
+	RedirectURL string `json:"redirectUrl"`
+}
+
+// CASLogin is the input of the login action of the CAS provider.
+// Service is the URL the CAS server redirected the user to with the ticket.
+type CASLogin struct {
+	GenericLogin `json:",inline"`
+	Ticket       string `json:"ticket" norman:"type=string,required"`
+	Service      string `json:"service" norman:"type=string,required"`
+}
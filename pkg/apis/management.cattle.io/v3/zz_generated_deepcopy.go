@@ -2,7 +2,7 @@
 // +build !ignore_autogenerated
 
 /*
-Copyright 2025 Rancher Labs, Inc.
+Copyright 2026 Rancher Labs, Inc.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -38,6 +38,7 @@
 func (in *ADFSConfig) DeepCopyInto(out *ADFSConfig) {
 	*out = *in
 	in.SamlConfig.DeepCopyInto(&out.SamlConfig)
+	out.WSFedConfig = in.WSFedConfig
 	return
 }
 
@@ -286,6 +287,107 @@ func (in *ActiveDirectoryConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessRequest) DeepCopyInto(out *AccessRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessRequest.
+func (in *AccessRequest) DeepCopy() *AccessRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccessRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessRequestList) DeepCopyInto(out *AccessRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AccessRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessRequestList.
+func (in *AccessRequestList) DeepCopy() *AccessRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccessRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessRequestSpec) DeepCopyInto(out *AccessRequestSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessRequestSpec.
+func (in *AccessRequestSpec) DeepCopy() *AccessRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessRequestStatus) DeepCopyInto(out *AccessRequestStatus) {
+	*out = *in
+	if in.DecidedAt != nil {
+		in, out := &in.DecidedAt, &out.DecidedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessRequestStatus.
+func (in *AccessRequestStatus) DeepCopy() *AccessRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ActiveDirectoryProvider) DeepCopyInto(out *ActiveDirectoryProvider) {
 	*out = *in
@@ -458,31 +560,26 @@ func (in *Answer) DeepCopy() *Answer {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthConfig) DeepCopyInto(out *AuthConfig) {
+func (in *AuditPolicy) DeepCopyInto(out *AuditPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.AllowedPrincipalIDs != nil {
-		in, out := &in.AllowedPrincipalIDs, &out.AllowedPrincipalIDs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.Status.DeepCopyInto(&out.Status)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfig.
-func (in *AuthConfig) DeepCopy() *AuthConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditPolicy.
+func (in *AuditPolicy) DeepCopy() *AuditPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthConfig)
+	out := new(AuditPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthConfig) DeepCopyObject() runtime.Object {
+func (in *AuditPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -490,29 +587,13 @@ func (in *AuthConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthConfigConditions) DeepCopyInto(out *AuthConfigConditions) {
-	*out = *in
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigConditions.
-func (in *AuthConfigConditions) DeepCopy() *AuthConfigConditions {
-	if in == nil {
-		return nil
-	}
-	out := new(AuthConfigConditions)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthConfigList) DeepCopyInto(out *AuthConfigList) {
+func (in *AuditPolicyList) DeepCopyInto(out *AuditPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]AuthConfig, len(*in))
+		*out = make([]AuditPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -520,18 +601,18 @@ func (in *AuthConfigList) DeepCopyInto(out *AuthConfigList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigList.
-func (in *AuthConfigList) DeepCopy() *AuthConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditPolicyList.
+func (in *AuditPolicyList) DeepCopy() *AuditPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthConfigList)
+	out := new(AuditPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthConfigList) DeepCopyObject() runtime.Object {
+func (in *AuditPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -539,79 +620,90 @@ func (in *AuthConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthConfigStatus) DeepCopyInto(out *AuthConfigStatus) {
+func (in *AuditPolicyRule) DeepCopyInto(out *AuditPolicyRule) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]AuthConfigConditions, len(*in))
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verbs != nil {
+		in, out := &in.Verbs, &out.Verbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserGroups != nil {
+		in, out := &in.UserGroups, &out.UserGroups
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigStatus.
-func (in *AuthConfigStatus) DeepCopy() *AuthConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditPolicyRule.
+func (in *AuditPolicyRule) DeepCopy() *AuditPolicyRule {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthConfigStatus)
+	out := new(AuditPolicyRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthProvider) DeepCopyInto(out *AuthProvider) {
+func (in *AuditPolicySpec) DeepCopyInto(out *AuditPolicySpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]AuditPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthProvider.
-func (in *AuthProvider) DeepCopy() *AuthProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditPolicySpec.
+func (in *AuditPolicySpec) DeepCopy() *AuditPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthProvider)
+	out := new(AuditPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthProvider) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthProviderList) DeepCopyInto(out *AuthProviderList) {
+func (in *AuthConfig) DeepCopyInto(out *AuthConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]AuthProvider, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.AllowedPrincipalIDs != nil {
+		in, out := &in.AllowedPrincipalIDs, &out.AllowedPrincipalIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthProviderList.
-func (in *AuthProviderList) DeepCopy() *AuthProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfig.
+func (in *AuthConfig) DeepCopy() *AuthConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthProviderList)
+	out := new(AuthConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthProviderList) DeepCopyObject() runtime.Object {
+func (in *AuthConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -619,177 +711,122 @@ func (in *AuthProviderList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthSystemImages) DeepCopyInto(out *AuthSystemImages) {
+func (in *AuthConfigConditions) DeepCopyInto(out *AuthConfigConditions) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthSystemImages.
-func (in *AuthSystemImages) DeepCopy() *AuthSystemImages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigConditions.
+func (in *AuthConfigConditions) DeepCopy() *AuthConfigConditions {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthSystemImages)
+	out := new(AuthConfigConditions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthToken) DeepCopyInto(out *AuthToken) {
+func (in *AuthConfigConnectivity) DeepCopyInto(out *AuthConfigConnectivity) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]AuthConfigEndpointStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]AuthConfigCredentialStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthToken.
-func (in *AuthToken) DeepCopy() *AuthToken {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigConnectivity.
+func (in *AuthConfigConnectivity) DeepCopy() *AuthConfigConnectivity {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthToken)
+	out := new(AuthConfigConnectivity)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthToken) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthTokenList) DeepCopyInto(out *AuthTokenList) {
+func (in *AuthConfigCredentialStatus) DeepCopyInto(out *AuthConfigCredentialStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]AuthToken, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthTokenList.
-func (in *AuthTokenList) DeepCopy() *AuthTokenList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigCredentialStatus.
+func (in *AuthConfigCredentialStatus) DeepCopy() *AuthConfigCredentialStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthTokenList)
+	out := new(AuthConfigCredentialStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthTokenList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureADConfig) DeepCopyInto(out *AzureADConfig) {
+func (in *AuthConfigEndpointStatus) DeepCopyInto(out *AuthConfigEndpointStatus) {
 	*out = *in
-	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADConfig.
-func (in *AzureADConfig) DeepCopy() *AzureADConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigEndpointStatus.
+func (in *AuthConfigEndpointStatus) DeepCopy() *AuthConfigEndpointStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureADConfig)
+	out := new(AuthConfigEndpointStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AzureADConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureADConfigApplyInput) DeepCopyInto(out *AzureADConfigApplyInput) {
+func (in *AuthConfigFieldChange) DeepCopyInto(out *AuthConfigFieldChange) {
 	*out = *in
-	in.Config.DeepCopyInto(&out.Config)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADConfigApplyInput.
-func (in *AzureADConfigApplyInput) DeepCopy() *AzureADConfigApplyInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigFieldChange.
+func (in *AuthConfigFieldChange) DeepCopy() *AuthConfigFieldChange {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureADConfigApplyInput)
+	out := new(AuthConfigFieldChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureADConfigTestOutput) DeepCopyInto(out *AzureADConfigTestOutput) {
+func (in *AuthConfigList) DeepCopyInto(out *AuthConfigList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADConfigTestOutput.
-func (in *AzureADConfigTestOutput) DeepCopy() *AzureADConfigTestOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigList.
+func (in *AuthConfigList) DeepCopy() *AuthConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureADConfigTestOutput)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureADLogin) DeepCopyInto(out *AzureADLogin) {
-	*out = *in
-	out.GenericLogin = in.GenericLogin
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADLogin.
-func (in *AzureADLogin) DeepCopy() *AzureADLogin {
-	if in == nil {
-		return nil
-	}
-	out := new(AzureADLogin)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureADProvider) DeepCopyInto(out *AzureADProvider) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
-	in.OAuthProvider.DeepCopyInto(&out.OAuthProvider)
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADProvider.
-func (in *AzureADProvider) DeepCopy() *AzureADProvider {
-	if in == nil {
-		return nil
-	}
-	out := new(AzureADProvider)
+	out := new(AuthConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AzureADProvider) DeepCopyObject() runtime.Object {
+func (in *AuthConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -797,138 +834,133 @@ func (in *AzureADProvider) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureADProviderList) DeepCopyInto(out *AzureADProviderList) {
+func (in *AuthConfigRevision) DeepCopyInto(out *AuthConfigRevision) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]AzureADProvider, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = make([]AuthConfigFieldChange, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADProviderList.
-func (in *AzureADProviderList) DeepCopy() *AzureADProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigRevision.
+func (in *AuthConfigRevision) DeepCopy() *AuthConfigRevision {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureADProviderList)
+	out := new(AuthConfigRevision)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AzureADProviderList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BasicLogin) DeepCopyInto(out *BasicLogin) {
+func (in *AuthConfigRevisionsOutput) DeepCopyInto(out *AuthConfigRevisionsOutput) {
 	*out = *in
-	out.GenericLogin = in.GenericLogin
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]AuthConfigRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicLogin.
-func (in *BasicLogin) DeepCopy() *BasicLogin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigRevisionsOutput.
+func (in *AuthConfigRevisionsOutput) DeepCopy() *AuthConfigRevisionsOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(BasicLogin)
+	out := new(AuthConfigRevisionsOutput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Capabilities) DeepCopyInto(out *Capabilities) {
+func (in *AuthConfigRollbackInput) DeepCopyInto(out *AuthConfigRollbackInput) {
 	*out = *in
-	in.LoadBalancerCapabilities.DeepCopyInto(&out.LoadBalancerCapabilities)
-	if in.IngressCapabilities != nil {
-		in, out := &in.IngressCapabilities, &out.IngressCapabilities
-		*out = make([]IngressCapabilities, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.TaintSupport != nil {
-		in, out := &in.TaintSupport, &out.TaintSupport
-		*out = new(bool)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Capabilities.
-func (in *Capabilities) DeepCopy() *Capabilities {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigRollbackInput.
+func (in *AuthConfigRollbackInput) DeepCopy() *AuthConfigRollbackInput {
 	if in == nil {
 		return nil
 	}
-	out := new(Capabilities)
+	out := new(AuthConfigRollbackInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CertExpiration) DeepCopyInto(out *CertExpiration) {
+func (in *AuthConfigSLO) DeepCopyInto(out *AuthConfigSLO) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertExpiration.
-func (in *CertExpiration) DeepCopy() *CertExpiration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigSLO.
+func (in *AuthConfigSLO) DeepCopy() *AuthConfigSLO {
 	if in == nil {
 		return nil
 	}
-	out := new(CertExpiration)
+	out := new(AuthConfigSLO)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChangePasswordInput) DeepCopyInto(out *ChangePasswordInput) {
+func (in *AuthConfigStatus) DeepCopyInto(out *AuthConfigStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]AuthConfigConditions, len(*in))
+		copy(*out, *in)
+	}
+	if in.Connectivity != nil {
+		in, out := &in.Connectivity, &out.Connectivity
+		*out = new(AuthConfigConnectivity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(AuthConfigSLO)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangePasswordInput.
-func (in *ChangePasswordInput) DeepCopy() *ChangePasswordInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfigStatus.
+func (in *AuthConfigStatus) DeepCopy() *AuthConfigStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ChangePasswordInput)
+	out := new(AuthConfigStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloudCredential) DeepCopyInto(out *CloudCredential) {
+func (in *AuthEvent) DeepCopyInto(out *AuthEvent) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.Time.DeepCopyInto(&out.Time)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudCredential.
-func (in *CloudCredential) DeepCopy() *CloudCredential {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthEvent.
+func (in *AuthEvent) DeepCopy() *AuthEvent {
 	if in == nil {
 		return nil
 	}
-	out := new(CloudCredential)
+	out := new(AuthEvent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CloudCredential) DeepCopyObject() runtime.Object {
+func (in *AuthEvent) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -936,13 +968,13 @@ func (in *CloudCredential) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloudCredentialList) DeepCopyInto(out *CloudCredentialList) {
+func (in *AuthEventList) DeepCopyInto(out *AuthEventList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CloudCredential, len(*in))
+		*out = make([]AuthEvent, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -950,18 +982,18 @@ func (in *CloudCredentialList) DeepCopyInto(out *CloudCredentialList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudCredentialList.
-func (in *CloudCredentialList) DeepCopy() *CloudCredentialList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthEventList.
+func (in *AuthEventList) DeepCopy() *AuthEventList {
 	if in == nil {
 		return nil
 	}
-	out := new(CloudCredentialList)
+	out := new(AuthEventList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CloudCredentialList) DeepCopyObject() runtime.Object {
+func (in *AuthEventList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -969,48 +1001,58 @@ func (in *CloudCredentialList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloudCredentialSpec) DeepCopyInto(out *CloudCredentialSpec) {
+func (in *AuthProvider) DeepCopyInto(out *AuthProvider) {
 	*out = *in
-	if in.S3CredentialConfig != nil {
-		in, out := &in.S3CredentialConfig, &out.S3CredentialConfig
-		*out = new(S3CredentialConfig)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudCredentialSpec.
-func (in *CloudCredentialSpec) DeepCopy() *CloudCredentialSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthProvider.
+func (in *AuthProvider) DeepCopy() *AuthProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(CloudCredentialSpec)
+	out := new(AuthProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Cluster) DeepCopyInto(out *Cluster) {
+func (in *AuthProviderList) DeepCopyInto(out *AuthProviderList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cluster.
-func (in *Cluster) DeepCopy() *Cluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthProviderList.
+func (in *AuthProviderList) DeepCopy() *AuthProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(Cluster)
+	out := new(AuthProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Cluster) DeepCopyObject() runtime.Object {
+func (in *AuthProviderList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1018,50 +1060,55 @@ func (in *Cluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterComponentStatus) DeepCopyInto(out *ClusterComponentStatus) {
+func (in *AuthSystemImages) DeepCopyInto(out *AuthSystemImages) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]corev1.ComponentCondition, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentStatus.
-func (in *ClusterComponentStatus) DeepCopy() *ClusterComponentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthSystemImages.
+func (in *AuthSystemImages) DeepCopy() *AuthSystemImages {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterComponentStatus)
+	out := new(AuthSystemImages)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
+func (in *AuthToken) DeepCopyInto(out *AuthToken) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
-func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthToken.
+func (in *AuthToken) DeepCopy() *AuthToken {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterCondition)
+	out := new(AuthToken)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthToken) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterList) DeepCopyInto(out *ClusterList) {
+func (in *AuthTokenList) DeepCopyInto(out *AuthTokenList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Cluster, len(*in))
+		*out = make([]AuthToken, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1069,18 +1116,18 @@ func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterList.
-func (in *ClusterList) DeepCopy() *ClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthTokenList.
+func (in *AuthTokenList) DeepCopy() *AuthTokenList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterList)
+	out := new(AuthTokenList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterList) DeepCopyObject() runtime.Object {
+func (in *AuthTokenList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1088,26 +1135,24 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterProxyConfig) DeepCopyInto(out *ClusterProxyConfig) {
+func (in *AzureADConfig) DeepCopyInto(out *AzureADConfig) {
 	*out = *in
-	out.Namespaced = in.Namespaced
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProxyConfig.
-func (in *ClusterProxyConfig) DeepCopy() *ClusterProxyConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADConfig.
+func (in *AzureADConfig) DeepCopy() *AzureADConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterProxyConfig)
+	out := new(AzureADConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterProxyConfig) DeepCopyObject() runtime.Object {
+func (in *AzureADConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1115,94 +1160,77 @@ func (in *ClusterProxyConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterProxyConfigList) DeepCopyInto(out *ClusterProxyConfigList) {
+func (in *AzureADConfigApplyInput) DeepCopyInto(out *AzureADConfigApplyInput) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClusterProxyConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.Config.DeepCopyInto(&out.Config)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProxyConfigList.
-func (in *ClusterProxyConfigList) DeepCopy() *ClusterProxyConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADConfigApplyInput.
+func (in *AzureADConfigApplyInput) DeepCopy() *AzureADConfigApplyInput {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterProxyConfigList)
+	out := new(AzureADConfigApplyInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterProxyConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterRegistrationToken) DeepCopyInto(out *ClusterRegistrationToken) {
+func (in *AzureADConfigTestOutput) DeepCopyInto(out *AzureADConfigTestOutput) {
 	*out = *in
-	out.Namespaced = in.Namespaced
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationToken.
-func (in *ClusterRegistrationToken) DeepCopy() *ClusterRegistrationToken {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADConfigTestOutput.
+func (in *AzureADConfigTestOutput) DeepCopy() *AzureADConfigTestOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterRegistrationToken)
+	out := new(AzureADConfigTestOutput)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterRegistrationToken) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureADLogin) DeepCopyInto(out *AzureADLogin) {
+	*out = *in
+	out.GenericLogin = in.GenericLogin
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADLogin.
+func (in *AzureADLogin) DeepCopy() *AzureADLogin {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(AzureADLogin)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterRegistrationTokenList) DeepCopyInto(out *ClusterRegistrationTokenList) {
+func (in *AzureADProvider) DeepCopyInto(out *AzureADProvider) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClusterRegistrationToken, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	in.OAuthProvider.DeepCopyInto(&out.OAuthProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationTokenList.
-func (in *ClusterRegistrationTokenList) DeepCopy() *ClusterRegistrationTokenList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADProvider.
+func (in *AzureADProvider) DeepCopy() *AzureADProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterRegistrationTokenList)
+	out := new(AzureADProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterRegistrationTokenList) DeepCopyObject() runtime.Object {
+func (in *AzureADProvider) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1210,59 +1238,77 @@ func (in *ClusterRegistrationTokenList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterRegistrationTokenSpec) DeepCopyInto(out *ClusterRegistrationTokenSpec) {
+func (in *AzureADProviderList) DeepCopyInto(out *AzureADProviderList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureADProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationTokenSpec.
-func (in *ClusterRegistrationTokenSpec) DeepCopy() *ClusterRegistrationTokenSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureADProviderList.
+func (in *AzureADProviderList) DeepCopy() *AzureADProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterRegistrationTokenSpec)
+	out := new(AzureADProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureADProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterRegistrationTokenStatus) DeepCopyInto(out *ClusterRegistrationTokenStatus) {
+func (in *BasicLogin) DeepCopyInto(out *BasicLogin) {
 	*out = *in
+	out.GenericLogin = in.GenericLogin
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationTokenStatus.
-func (in *ClusterRegistrationTokenStatus) DeepCopy() *ClusterRegistrationTokenStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicLogin.
+func (in *BasicLogin) DeepCopy() *BasicLogin {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterRegistrationTokenStatus)
+	out := new(BasicLogin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterRoleTemplateBinding) DeepCopyInto(out *ClusterRoleTemplateBinding) {
+func (in *BreakGlassAccount) DeepCopyInto(out *BreakGlassAccount) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRoleTemplateBinding.
-func (in *ClusterRoleTemplateBinding) DeepCopy() *ClusterRoleTemplateBinding {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakGlassAccount.
+func (in *BreakGlassAccount) DeepCopy() *BreakGlassAccount {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterRoleTemplateBinding)
+	out := new(BreakGlassAccount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterRoleTemplateBinding) DeepCopyObject() runtime.Object {
+func (in *BreakGlassAccount) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1270,13 +1316,13 @@ func (in *ClusterRoleTemplateBinding) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterRoleTemplateBindingList) DeepCopyInto(out *ClusterRoleTemplateBindingList) {
+func (in *BreakGlassAccountList) DeepCopyInto(out *BreakGlassAccountList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterRoleTemplateBinding, len(*in))
+		*out = make([]BreakGlassAccount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1284,18 +1330,18 @@ func (in *ClusterRoleTemplateBindingList) DeepCopyInto(out *ClusterRoleTemplateB
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRoleTemplateBindingList.
-func (in *ClusterRoleTemplateBindingList) DeepCopy() *ClusterRoleTemplateBindingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakGlassAccountList.
+func (in *BreakGlassAccountList) DeepCopy() *BreakGlassAccountList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterRoleTemplateBindingList)
+	out := new(BreakGlassAccountList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterRoleTemplateBindingList) DeepCopyObject() runtime.Object {
+func (in *BreakGlassAccountList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1303,18 +1349,35 @@ func (in *ClusterRoleTemplateBindingList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterRoleTemplateBindingStatus) DeepCopyInto(out *ClusterRoleTemplateBindingStatus) {
+func (in *BreakGlassAccountSpec) DeepCopyInto(out *BreakGlassAccountSpec) {
 	*out = *in
-	if in.LocalConditions != nil {
-		in, out := &in.LocalConditions, &out.LocalConditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakGlassAccountSpec.
+func (in *BreakGlassAccountSpec) DeepCopy() *BreakGlassAccountSpec {
+	if in == nil {
+		return nil
 	}
-	if in.RemoteConditions != nil {
-		in, out := &in.RemoteConditions, &out.RemoteConditions
-		*out = make([]metav1.Condition, len(*in))
+	out := new(BreakGlassAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BreakGlassAccountStatus) DeepCopyInto(out *BreakGlassAccountStatus) {
+	*out = *in
+	if in.SealedAt != nil {
+		in, out := &in.SealedAt, &out.SealedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Activations != nil {
+		in, out := &in.Activations, &out.Activations
+		*out = make([]BreakGlassActivation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1322,267 +1385,134 @@ func (in *ClusterRoleTemplateBindingStatus) DeepCopyInto(out *ClusterRoleTemplat
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRoleTemplateBindingStatus.
-func (in *ClusterRoleTemplateBindingStatus) DeepCopy() *ClusterRoleTemplateBindingStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakGlassAccountStatus.
+func (in *BreakGlassAccountStatus) DeepCopy() *BreakGlassAccountStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterRoleTemplateBindingStatus)
+	out := new(BreakGlassAccountStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSecrets) DeepCopyInto(out *ClusterSecrets) {
+func (in *BreakGlassActivation) DeepCopyInto(out *BreakGlassActivation) {
 	*out = *in
+	in.ActivatedAt.DeepCopyInto(&out.ActivatedAt)
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+	if in.EndedAt != nil {
+		in, out := &in.EndedAt, &out.EndedAt
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecrets.
-func (in *ClusterSecrets) DeepCopy() *ClusterSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakGlassActivation.
+func (in *BreakGlassActivation) DeepCopy() *BreakGlassActivation {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSecrets)
+	out := new(BreakGlassActivation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+func (in *CASConfig) DeepCopyInto(out *CASConfig) {
 	*out = *in
-	in.ClusterSpecBase.DeepCopyInto(&out.ClusterSpecBase)
-	if in.K3sConfig != nil {
-		in, out := &in.K3sConfig, &out.K3sConfig
-		*out = new(K3sConfig)
-		**out = **in
-	}
-	if in.Rke2Config != nil {
-		in, out := &in.Rke2Config, &out.Rke2Config
-		*out = new(Rke2Config)
-		**out = **in
-	}
-	if in.ImportedConfig != nil {
-		in, out := &in.ImportedConfig, &out.ImportedConfig
-		*out = new(ImportedConfig)
-		**out = **in
-	}
-	if in.GoogleKubernetesEngineConfig != nil {
-		in, out := &in.GoogleKubernetesEngineConfig, &out.GoogleKubernetesEngineConfig
-		*out = (*in).DeepCopy()
-	}
-	if in.AzureKubernetesServiceConfig != nil {
-		in, out := &in.AzureKubernetesServiceConfig, &out.AzureKubernetesServiceConfig
-		*out = (*in).DeepCopy()
-	}
-	if in.AmazonElasticContainerServiceConfig != nil {
-		in, out := &in.AmazonElasticContainerServiceConfig, &out.AmazonElasticContainerServiceConfig
-		*out = (*in).DeepCopy()
-	}
-	if in.GenericEngineConfig != nil {
-		in, out := &in.GenericEngineConfig, &out.GenericEngineConfig
-		*out = (*in).DeepCopy()
-	}
-	if in.AKSConfig != nil {
-		in, out := &in.AKSConfig, &out.AKSConfig
-		*out = new(v1.AKSClusterConfigSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.EKSConfig != nil {
-		in, out := &in.EKSConfig, &out.EKSConfig
-		*out = new(ekscattleiov1.EKSClusterConfigSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.GKEConfig != nil {
-		in, out := &in.GKEConfig, &out.GKEConfig
-		*out = new(gkecattleiov1.GKEClusterConfigSpec)
-		(*in).DeepCopyInto(*out)
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CASConfig.
+func (in *CASConfig) DeepCopy() *CASConfig {
+	if in == nil {
+		return nil
 	}
-	in.ClusterTemplateAnswers.DeepCopyInto(&out.ClusterTemplateAnswers)
-	if in.ClusterTemplateQuestions != nil {
-		in, out := &in.ClusterTemplateQuestions, &out.ClusterTemplateQuestions
-		*out = make([]Question, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(CASConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CASConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CASConfigApplyInput) DeepCopyInto(out *CASConfigApplyInput) {
+	*out = *in
+	in.CASConfig.DeepCopyInto(&out.CASConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
-func (in *ClusterSpec) DeepCopy() *ClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CASConfigApplyInput.
+func (in *CASConfigApplyInput) DeepCopy() *CASConfigApplyInput {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSpec)
+	out := new(CASConfigApplyInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSpecBase) DeepCopyInto(out *ClusterSpecBase) {
+func (in *CASConfigTestOutput) DeepCopyInto(out *CASConfigTestOutput) {
 	*out = *in
-	if in.AgentEnvVars != nil {
-		in, out := &in.AgentEnvVars, &out.AgentEnvVars
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.RancherKubernetesEngineConfig != nil {
-		in, out := &in.RancherKubernetesEngineConfig, &out.RancherKubernetesEngineConfig
-		*out = new(types.RancherKubernetesEngineConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.EnableNetworkPolicy != nil {
-		in, out := &in.EnableNetworkPolicy, &out.EnableNetworkPolicy
-		*out = new(bool)
-		**out = **in
-	}
-	out.LocalClusterAuthEndpoint = in.LocalClusterAuthEndpoint
-	out.ClusterSecrets = in.ClusterSecrets
-	if in.ClusterAgentDeploymentCustomization != nil {
-		in, out := &in.ClusterAgentDeploymentCustomization, &out.ClusterAgentDeploymentCustomization
-		*out = new(AgentDeploymentCustomization)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.FleetAgentDeploymentCustomization != nil {
-		in, out := &in.FleetAgentDeploymentCustomization, &out.FleetAgentDeploymentCustomization
-		*out = new(AgentDeploymentCustomization)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpecBase.
-func (in *ClusterSpecBase) DeepCopy() *ClusterSpecBase {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CASConfigTestOutput.
+func (in *CASConfigTestOutput) DeepCopy() *CASConfigTestOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSpecBase)
+	out := new(CASConfigTestOutput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+func (in *CASLogin) DeepCopyInto(out *CASLogin) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ClusterCondition, len(*in))
-		copy(*out, *in)
-	}
-	if in.AppliedAgentEnvVars != nil {
-		in, out := &in.AppliedAgentEnvVars, &out.AppliedAgentEnvVars
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.GenericLogin = in.GenericLogin
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CASLogin.
+func (in *CASLogin) DeepCopy() *CASLogin {
+	if in == nil {
+		return nil
 	}
-	if in.AgentFeatures != nil {
-		in, out := &in.AgentFeatures, &out.AgentFeatures
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ComponentStatuses != nil {
-		in, out := &in.ComponentStatuses, &out.ComponentStatuses
-		*out = make([]ClusterComponentStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Capacity != nil {
-		in, out := &in.Capacity, &out.Capacity
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
-	if in.Allocatable != nil {
-		in, out := &in.Allocatable, &out.Allocatable
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
-	in.AppliedSpec.DeepCopyInto(&out.AppliedSpec)
-	if in.FailedSpec != nil {
-		in, out := &in.FailedSpec, &out.FailedSpec
-		*out = new(ClusterSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Requested != nil {
-		in, out := &in.Requested, &out.Requested
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
-	if in.Limits != nil {
-		in, out := &in.Limits, &out.Limits
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
-	if in.Version != nil {
-		in, out := &in.Version, &out.Version
-		*out = new(version.Info)
-		**out = **in
-	}
-	in.Capabilities.DeepCopyInto(&out.Capabilities)
-	if in.CertificatesExpiration != nil {
-		in, out := &in.CertificatesExpiration, &out.CertificatesExpiration
-		*out = make(map[string]CertExpiration, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.AKSStatus.DeepCopyInto(&out.AKSStatus)
-	in.EKSStatus.DeepCopyInto(&out.EKSStatus)
-	in.GKEStatus.DeepCopyInto(&out.GKEStatus)
-	if in.AppliedClusterAgentDeploymentCustomization != nil {
-		in, out := &in.AppliedClusterAgentDeploymentCustomization, &out.AppliedClusterAgentDeploymentCustomization
-		*out = new(AgentDeploymentCustomization)
-		(*in).DeepCopyInto(*out)
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
-func (in *ClusterStatus) DeepCopy() *ClusterStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(ClusterStatus)
+	out := new(CASLogin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplate) DeepCopyInto(out *ClusterTemplate) {
+func (in *CASProvider) DeepCopyInto(out *CASProvider) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplate.
-func (in *ClusterTemplate) DeepCopy() *ClusterTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CASProvider.
+func (in *CASProvider) DeepCopy() *CASProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplate)
+	out := new(CASProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
+func (in *CASProvider) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1590,13 +1520,13 @@ func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+func (in *CASProviderList) DeepCopyInto(out *CASProviderList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplate, len(*in))
+		*out = make([]CASProvider, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1604,18 +1534,18 @@ func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
-func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CASProviderList.
+func (in *CASProviderList) DeepCopy() *CASProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateList)
+	out := new(CASProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
+func (in *CASProviderList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1623,100 +1553,85 @@ func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuestionsOutput) DeepCopyInto(out *ClusterTemplateQuestionsOutput) {
+func (in *Capabilities) DeepCopyInto(out *Capabilities) {
 	*out = *in
-	if in.Questions != nil {
-		in, out := &in.Questions, &out.Questions
-		*out = make([]Question, len(*in))
+	in.LoadBalancerCapabilities.DeepCopyInto(&out.LoadBalancerCapabilities)
+	if in.IngressCapabilities != nil {
+		in, out := &in.IngressCapabilities, &out.IngressCapabilities
+		*out = make([]IngressCapabilities, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TaintSupport != nil {
+		in, out := &in.TaintSupport, &out.TaintSupport
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuestionsOutput.
-func (in *ClusterTemplateQuestionsOutput) DeepCopy() *ClusterTemplateQuestionsOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Capabilities.
+func (in *Capabilities) DeepCopy() *Capabilities {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuestionsOutput)
+	out := new(Capabilities)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateRevision) DeepCopyInto(out *ClusterTemplateRevision) {
+func (in *CertExpiration) DeepCopyInto(out *CertExpiration) {
 	*out = *in
-	out.Namespaced = in.Namespaced
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevision.
-func (in *ClusterTemplateRevision) DeepCopy() *ClusterTemplateRevision {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertExpiration.
+func (in *CertExpiration) DeepCopy() *CertExpiration {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateRevision)
+	out := new(CertExpiration)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateRevision) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateRevisionCondition) DeepCopyInto(out *ClusterTemplateRevisionCondition) {
+func (in *ChangePasswordInput) DeepCopyInto(out *ChangePasswordInput) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionCondition.
-func (in *ClusterTemplateRevisionCondition) DeepCopy() *ClusterTemplateRevisionCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangePasswordInput.
+func (in *ChangePasswordInput) DeepCopy() *ChangePasswordInput {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateRevisionCondition)
+	out := new(ChangePasswordInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateRevisionList) DeepCopyInto(out *ClusterTemplateRevisionList) {
+func (in *ClientCertConfig) DeepCopyInto(out *ClientCertConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplateRevision, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionList.
-func (in *ClusterTemplateRevisionList) DeepCopy() *ClusterTemplateRevisionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertConfig.
+func (in *ClientCertConfig) DeepCopy() *ClientCertConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateRevisionList)
+	out := new(ClientCertConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateRevisionList) DeepCopyObject() runtime.Object {
+func (in *ClientCertConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1724,134 +1639,121 @@ func (in *ClusterTemplateRevisionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateRevisionSpec) DeepCopyInto(out *ClusterTemplateRevisionSpec) {
+func (in *ClientCertLogin) DeepCopyInto(out *ClientCertLogin) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Questions != nil {
-		in, out := &in.Questions, &out.Questions
-		*out = make([]Question, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ClusterConfig != nil {
-		in, out := &in.ClusterConfig, &out.ClusterConfig
-		*out = new(ClusterSpecBase)
-		(*in).DeepCopyInto(*out)
-	}
+	out.GenericLogin = in.GenericLogin
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionSpec.
-func (in *ClusterTemplateRevisionSpec) DeepCopy() *ClusterTemplateRevisionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertLogin.
+func (in *ClientCertLogin) DeepCopy() *ClientCertLogin {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateRevisionSpec)
+	out := new(ClientCertLogin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateRevisionStatus) DeepCopyInto(out *ClusterTemplateRevisionStatus) {
+func (in *ClientCertProvider) DeepCopyInto(out *ClientCertProvider) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ClusterTemplateRevisionCondition, len(*in))
-		copy(*out, *in)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionStatus.
-func (in *ClusterTemplateRevisionStatus) DeepCopy() *ClusterTemplateRevisionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertProvider.
+func (in *ClientCertProvider) DeepCopy() *ClientCertProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateRevisionStatus)
+	out := new(ClientCertProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientCertProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+func (in *ClientCertProviderList) DeepCopyInto(out *ClientCertProviderList) {
 	*out = *in
-	if in.Members != nil {
-		in, out := &in.Members, &out.Members
-		*out = make([]Member, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClientCertProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
-func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertProviderList.
+func (in *ClientCertProviderList) DeepCopy() *ClientCertProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateSpec)
+	out := new(ClientCertProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterUpgradeStrategy) DeepCopyInto(out *ClusterUpgradeStrategy) {
-	*out = *in
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradeStrategy.
-func (in *ClusterUpgradeStrategy) DeepCopy() *ClusterUpgradeStrategy {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientCertProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(ClusterUpgradeStrategy)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComposeCondition) DeepCopyInto(out *ComposeCondition) {
+func (in *ClientCertTestAndApplyInput) DeepCopyInto(out *ClientCertTestAndApplyInput) {
 	*out = *in
+	in.ClientCertConfig.DeepCopyInto(&out.ClientCertConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeCondition.
-func (in *ComposeCondition) DeepCopy() *ComposeCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertTestAndApplyInput.
+func (in *ClientCertTestAndApplyInput) DeepCopy() *ClientCertTestAndApplyInput {
 	if in == nil {
 		return nil
 	}
-	out := new(ComposeCondition)
+	out := new(ClientCertTestAndApplyInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComposeConfig) DeepCopyInto(out *ComposeConfig) {
+func (in *CloudCredential) DeepCopyInto(out *CloudCredential) {
 	*out = *in
+	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeConfig.
-func (in *ComposeConfig) DeepCopy() *ComposeConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudCredential.
+func (in *CloudCredential) DeepCopy() *CloudCredential {
 	if in == nil {
 		return nil
 	}
-	out := new(ComposeConfig)
+	out := new(CloudCredential)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComposeConfig) DeepCopyObject() runtime.Object {
+func (in *CloudCredential) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1859,13 +1761,13 @@ func (in *ComposeConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComposeConfigList) DeepCopyInto(out *ComposeConfigList) {
+func (in *CloudCredentialList) DeepCopyInto(out *CloudCredentialList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ComposeConfig, len(*in))
+		*out = make([]CloudCredential, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1873,18 +1775,18 @@ func (in *ComposeConfigList) DeepCopyInto(out *ComposeConfigList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeConfigList.
-func (in *ComposeConfigList) DeepCopy() *ComposeConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudCredentialList.
+func (in *CloudCredentialList) DeepCopy() *CloudCredentialList {
 	if in == nil {
 		return nil
 	}
-	out := new(ComposeConfigList)
+	out := new(CloudCredentialList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComposeConfigList) DeepCopyObject() runtime.Object {
+func (in *CloudCredentialList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1892,150 +1794,207 @@ func (in *ComposeConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComposeSpec) DeepCopyInto(out *ComposeSpec) {
+func (in *CloudCredentialSpec) DeepCopyInto(out *CloudCredentialSpec) {
 	*out = *in
+	if in.S3CredentialConfig != nil {
+		in, out := &in.S3CredentialConfig, &out.S3CredentialConfig
+		*out = new(S3CredentialConfig)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeSpec.
-func (in *ComposeSpec) DeepCopy() *ComposeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudCredentialSpec.
+func (in *CloudCredentialSpec) DeepCopy() *CloudCredentialSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ComposeSpec)
+	out := new(CloudCredentialSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComposeStatus) DeepCopyInto(out *ComposeStatus) {
+func (in *Cluster) DeepCopyInto(out *Cluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cluster.
+func (in *Cluster) DeepCopy() *Cluster {
+	if in == nil {
+		return nil
+	}
+	out := new(Cluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Cluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterComponentStatus) DeepCopyInto(out *ClusterComponentStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ComposeCondition, len(*in))
+		*out = make([]corev1.ComponentCondition, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeStatus.
-func (in *ComposeStatus) DeepCopy() *ComposeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentStatus.
+func (in *ClusterComponentStatus) DeepCopy() *ClusterComponentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ComposeStatus)
+	out := new(ClusterComponentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
+func (in *ClusterCondition) DeepCopy() *ClusterCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(ClusterCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerResourceLimit) DeepCopyInto(out *ContainerResourceLimit) {
+func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Cluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResourceLimit.
-func (in *ContainerResourceLimit) DeepCopy() *ContainerResourceLimit {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterList.
+func (in *ClusterList) DeepCopy() *ClusterList {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerResourceLimit)
+	out := new(ClusterList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CustomConfig) DeepCopyInto(out *CustomConfig) {
+func (in *ClusterProxyConfig) DeepCopyInto(out *ClusterProxyConfig) {
 	*out = *in
-	if in.Label != nil {
-		in, out := &in.Label, &out.Label
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Taints != nil {
-		in, out := &in.Taints, &out.Taints
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomConfig.
-func (in *CustomConfig) DeepCopy() *CustomConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProxyConfig.
+func (in *ClusterProxyConfig) DeepCopy() *ClusterProxyConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(CustomConfig)
+	out := new(ClusterProxyConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterProxyConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DockerInfo) DeepCopyInto(out *DockerInfo) {
+func (in *ClusterProxyConfigList) DeepCopyInto(out *ClusterProxyConfigList) {
 	*out = *in
-	if in.SecurityOptions != nil {
-		in, out := &in.SecurityOptions, &out.SecurityOptions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterProxyConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerInfo.
-func (in *DockerInfo) DeepCopy() *DockerInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProxyConfigList.
+func (in *ClusterProxyConfigList) DeepCopy() *ClusterProxyConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(DockerInfo)
+	out := new(ClusterProxyConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterProxyConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicSchema) DeepCopyInto(out *DynamicSchema) {
+func (in *ClusterRegistrationToken) DeepCopyInto(out *ClusterRegistrationToken) {
 	*out = *in
+	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicSchema.
-func (in *DynamicSchema) DeepCopy() *DynamicSchema {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationToken.
+func (in *ClusterRegistrationToken) DeepCopy() *ClusterRegistrationToken {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicSchema)
+	out := new(ClusterRegistrationToken)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DynamicSchema) DeepCopyObject() runtime.Object {
+func (in *ClusterRegistrationToken) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2043,13 +2002,13 @@ func (in *DynamicSchema) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicSchemaList) DeepCopyInto(out *DynamicSchemaList) {
+func (in *ClusterRegistrationTokenList) DeepCopyInto(out *ClusterRegistrationTokenList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DynamicSchema, len(*in))
+		*out = make([]ClusterRegistrationToken, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -2057,18 +2016,18 @@ func (in *DynamicSchemaList) DeepCopyInto(out *DynamicSchemaList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicSchemaList.
-func (in *DynamicSchemaList) DeepCopy() *DynamicSchemaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationTokenList.
+func (in *ClusterRegistrationTokenList) DeepCopy() *ClusterRegistrationTokenList {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicSchemaList)
+	out := new(ClusterRegistrationTokenList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DynamicSchemaList) DeepCopyObject() runtime.Object {
+func (in *ClusterRegistrationTokenList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2076,153 +2035,2643 @@ func (in *DynamicSchemaList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicSchemaSpec) DeepCopyInto(out *DynamicSchemaSpec) {
+func (in *ClusterRegistrationTokenSpec) DeepCopyInto(out *ClusterRegistrationTokenSpec) {
 	*out = *in
-	if in.ResourceMethods != nil {
-		in, out := &in.ResourceMethods, &out.ResourceMethods
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.ResourceFields != nil {
-		in, out := &in.ResourceFields, &out.ResourceFields
-		*out = make(map[string]Field, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.ResourceActions != nil {
-		in, out := &in.ResourceActions, &out.ResourceActions
-		*out = make(map[string]Action, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.CollectionMethods != nil {
-		in, out := &in.CollectionMethods, &out.CollectionMethods
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.CollectionFields != nil {
-		in, out := &in.CollectionFields, &out.CollectionFields
-		*out = make(map[string]Field, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.CollectionActions != nil {
-		in, out := &in.CollectionActions, &out.CollectionActions
-		*out = make(map[string]Action, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.CollectionFilters != nil {
-		in, out := &in.CollectionFilters, &out.CollectionFilters
-		*out = make(map[string]Filter, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.IncludeableLinks != nil {
-		in, out := &in.IncludeableLinks, &out.IncludeableLinks
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicSchemaSpec.
-func (in *DynamicSchemaSpec) DeepCopy() *DynamicSchemaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationTokenSpec.
+func (in *ClusterRegistrationTokenSpec) DeepCopy() *ClusterRegistrationTokenSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicSchemaSpec)
+	out := new(ClusterRegistrationTokenSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicSchemaStatus) DeepCopyInto(out *DynamicSchemaStatus) {
+func (in *ClusterRegistrationTokenStatus) DeepCopyInto(out *ClusterRegistrationTokenStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicSchemaStatus.
-func (in *DynamicSchemaStatus) DeepCopy() *DynamicSchemaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationTokenStatus.
+func (in *ClusterRegistrationTokenStatus) DeepCopy() *ClusterRegistrationTokenStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicSchemaStatus)
+	out := new(ClusterRegistrationTokenStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EKSStatus) DeepCopyInto(out *EKSStatus) {
+func (in *ClusterRoleTemplateBinding) DeepCopyInto(out *ClusterRoleTemplateBinding) {
 	*out = *in
-	if in.UpstreamSpec != nil {
-		in, out := &in.UpstreamSpec, &out.UpstreamSpec
-		*out = new(ekscattleiov1.EKSClusterConfigSpec)
-		(*in).DeepCopyInto(*out)
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
 	}
-	if in.Subnets != nil {
-		in, out := &in.Subnets, &out.Subnets
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.SecurityGroups != nil {
-		in, out := &in.SecurityGroups, &out.SecurityGroups
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRoleTemplateBinding.
+func (in *ClusterRoleTemplateBinding) DeepCopy() *ClusterRoleTemplateBinding {
+	if in == nil {
+		return nil
 	}
-	if in.PrivateRequiresTunnel != nil {
-		in, out := &in.PrivateRequiresTunnel, &out.PrivateRequiresTunnel
+	out := new(ClusterRoleTemplateBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRoleTemplateBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRoleTemplateBindingList) DeepCopyInto(out *ClusterRoleTemplateBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterRoleTemplateBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRoleTemplateBindingList.
+func (in *ClusterRoleTemplateBindingList) DeepCopy() *ClusterRoleTemplateBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRoleTemplateBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRoleTemplateBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRoleTemplateBindingStatus) DeepCopyInto(out *ClusterRoleTemplateBindingStatus) {
+	*out = *in
+	if in.LocalConditions != nil {
+		in, out := &in.LocalConditions, &out.LocalConditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemoteConditions != nil {
+		in, out := &in.RemoteConditions, &out.RemoteConditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRoleTemplateBindingStatus.
+func (in *ClusterRoleTemplateBindingStatus) DeepCopy() *ClusterRoleTemplateBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRoleTemplateBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecrets) DeepCopyInto(out *ClusterSecrets) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecrets.
+func (in *ClusterSecrets) DeepCopy() *ClusterSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+	*out = *in
+	in.ClusterSpecBase.DeepCopyInto(&out.ClusterSpecBase)
+	if in.K3sConfig != nil {
+		in, out := &in.K3sConfig, &out.K3sConfig
+		*out = new(K3sConfig)
+		**out = **in
+	}
+	if in.Rke2Config != nil {
+		in, out := &in.Rke2Config, &out.Rke2Config
+		*out = new(Rke2Config)
+		**out = **in
+	}
+	if in.ImportedConfig != nil {
+		in, out := &in.ImportedConfig, &out.ImportedConfig
+		*out = new(ImportedConfig)
+		**out = **in
+	}
+	if in.GoogleKubernetesEngineConfig != nil {
+		in, out := &in.GoogleKubernetesEngineConfig, &out.GoogleKubernetesEngineConfig
+		*out = (*in).DeepCopy()
+	}
+	if in.AzureKubernetesServiceConfig != nil {
+		in, out := &in.AzureKubernetesServiceConfig, &out.AzureKubernetesServiceConfig
+		*out = (*in).DeepCopy()
+	}
+	if in.AmazonElasticContainerServiceConfig != nil {
+		in, out := &in.AmazonElasticContainerServiceConfig, &out.AmazonElasticContainerServiceConfig
+		*out = (*in).DeepCopy()
+	}
+	if in.GenericEngineConfig != nil {
+		in, out := &in.GenericEngineConfig, &out.GenericEngineConfig
+		*out = (*in).DeepCopy()
+	}
+	if in.AKSConfig != nil {
+		in, out := &in.AKSConfig, &out.AKSConfig
+		*out = new(v1.AKSClusterConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EKSConfig != nil {
+		in, out := &in.EKSConfig, &out.EKSConfig
+		*out = new(ekscattleiov1.EKSClusterConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GKEConfig != nil {
+		in, out := &in.GKEConfig, &out.GKEConfig
+		*out = new(gkecattleiov1.GKEClusterConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ClusterTemplateAnswers.DeepCopyInto(&out.ClusterTemplateAnswers)
+	if in.ClusterTemplateQuestions != nil {
+		in, out := &in.ClusterTemplateQuestions, &out.ClusterTemplateQuestions
+		*out = make([]Question, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
+func (in *ClusterSpec) DeepCopy() *ClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSpecBase) DeepCopyInto(out *ClusterSpecBase) {
+	*out = *in
+	if in.AgentEnvVars != nil {
+		in, out := &in.AgentEnvVars, &out.AgentEnvVars
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RancherKubernetesEngineConfig != nil {
+		in, out := &in.RancherKubernetesEngineConfig, &out.RancherKubernetesEngineConfig
+		*out = new(types.RancherKubernetesEngineConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnableNetworkPolicy != nil {
+		in, out := &in.EnableNetworkPolicy, &out.EnableNetworkPolicy
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ManagedLaunchTemplateVersions != nil {
-		in, out := &in.ManagedLaunchTemplateVersions, &out.ManagedLaunchTemplateVersions
-		*out = make(map[string]string, len(*in))
+	out.LocalClusterAuthEndpoint = in.LocalClusterAuthEndpoint
+	out.ClusterSecrets = in.ClusterSecrets
+	if in.ClusterAgentDeploymentCustomization != nil {
+		in, out := &in.ClusterAgentDeploymentCustomization, &out.ClusterAgentDeploymentCustomization
+		*out = new(AgentDeploymentCustomization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FleetAgentDeploymentCustomization != nil {
+		in, out := &in.FleetAgentDeploymentCustomization, &out.FleetAgentDeploymentCustomization
+		*out = new(AgentDeploymentCustomization)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpecBase.
+func (in *ClusterSpecBase) DeepCopy() *ClusterSpecBase {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSpecBase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedAgentEnvVars != nil {
+		in, out := &in.AppliedAgentEnvVars, &out.AppliedAgentEnvVars
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AgentFeatures != nil {
+		in, out := &in.AgentFeatures, &out.AgentFeatures
+		*out = make(map[string]bool, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.ComponentStatuses != nil {
+		in, out := &in.ComponentStatuses, &out.ComponentStatuses
+		*out = make([]ClusterComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	in.AppliedSpec.DeepCopyInto(&out.AppliedSpec)
+	if in.FailedSpec != nil {
+		in, out := &in.FailedSpec, &out.FailedSpec
+		*out = new(ClusterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(version.Info)
+		**out = **in
+	}
+	in.Capabilities.DeepCopyInto(&out.Capabilities)
+	if in.CertificatesExpiration != nil {
+		in, out := &in.CertificatesExpiration, &out.CertificatesExpiration
+		*out = make(map[string]CertExpiration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.AKSStatus.DeepCopyInto(&out.AKSStatus)
+	in.EKSStatus.DeepCopyInto(&out.EKSStatus)
+	in.GKEStatus.DeepCopyInto(&out.GKEStatus)
+	if in.AppliedClusterAgentDeploymentCustomization != nil {
+		in, out := &in.AppliedClusterAgentDeploymentCustomization, &out.AppliedClusterAgentDeploymentCustomization
+		*out = new(AgentDeploymentCustomization)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
+func (in *ClusterStatus) DeepCopy() *ClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplate) DeepCopyInto(out *ClusterTemplate) {
+	*out = *in
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplate.
+func (in *ClusterTemplate) DeepCopy() *ClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
+func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuestionsOutput) DeepCopyInto(out *ClusterTemplateQuestionsOutput) {
+	*out = *in
+	if in.Questions != nil {
+		in, out := &in.Questions, &out.Questions
+		*out = make([]Question, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuestionsOutput.
+func (in *ClusterTemplateQuestionsOutput) DeepCopy() *ClusterTemplateQuestionsOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuestionsOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateRevision) DeepCopyInto(out *ClusterTemplateRevision) {
+	*out = *in
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevision.
+func (in *ClusterTemplateRevision) DeepCopy() *ClusterTemplateRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateRevisionCondition) DeepCopyInto(out *ClusterTemplateRevisionCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionCondition.
+func (in *ClusterTemplateRevisionCondition) DeepCopy() *ClusterTemplateRevisionCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateRevisionCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateRevisionList) DeepCopyInto(out *ClusterTemplateRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionList.
+func (in *ClusterTemplateRevisionList) DeepCopy() *ClusterTemplateRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateRevisionSpec) DeepCopyInto(out *ClusterTemplateRevisionSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Questions != nil {
+		in, out := &in.Questions, &out.Questions
+		*out = make([]Question, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterConfig != nil {
+		in, out := &in.ClusterConfig, &out.ClusterConfig
+		*out = new(ClusterSpecBase)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionSpec.
+func (in *ClusterTemplateRevisionSpec) DeepCopy() *ClusterTemplateRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateRevisionStatus) DeepCopyInto(out *ClusterTemplateRevisionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterTemplateRevisionCondition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionStatus.
+func (in *ClusterTemplateRevisionStatus) DeepCopy() *ClusterTemplateRevisionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateRevisionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]Member, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
+func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradeStrategy) DeepCopyInto(out *ClusterUpgradeStrategy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradeStrategy.
+func (in *ClusterUpgradeStrategy) DeepCopy() *ClusterUpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposeCondition) DeepCopyInto(out *ComposeCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeCondition.
+func (in *ComposeCondition) DeepCopy() *ComposeCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposeCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposeConfig) DeepCopyInto(out *ComposeConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeConfig.
+func (in *ComposeConfig) DeepCopy() *ComposeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComposeConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposeConfigList) DeepCopyInto(out *ComposeConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComposeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeConfigList.
+func (in *ComposeConfigList) DeepCopy() *ComposeConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposeConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComposeConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposeSpec) DeepCopyInto(out *ComposeSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeSpec.
+func (in *ComposeSpec) DeepCopy() *ComposeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposeStatus) DeepCopyInto(out *ComposeStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ComposeCondition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposeStatus.
+func (in *ComposeStatus) DeepCopy() *ComposeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerResourceLimit) DeepCopyInto(out *ContainerResourceLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResourceLimit.
+func (in *ContainerResourceLimit) DeepCopy() *ContainerResourceLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerResourceLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomConfig) DeepCopyInto(out *CustomConfig) {
+	*out = *in
+	if in.Label != nil {
+		in, out := &in.Label, &out.Label
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomConfig.
+func (in *CustomConfig) DeepCopy() *CustomConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DetectDuplicateUsersOutput) DeepCopyInto(out *DetectDuplicateUsersOutput) {
+	*out = *in
+	if in.Duplicates != nil {
+		in, out := &in.Duplicates, &out.Duplicates
+		*out = make([]DuplicateUsers, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DetectDuplicateUsersOutput.
+func (in *DetectDuplicateUsersOutput) DeepCopy() *DetectDuplicateUsersOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(DetectDuplicateUsersOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DirectoryAttributeMembership) DeepCopyInto(out *DirectoryAttributeMembership) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectoryAttributeMembership.
+func (in *DirectoryAttributeMembership) DeepCopy() *DirectoryAttributeMembership {
+	if in == nil {
+		return nil
+	}
+	out := new(DirectoryAttributeMembership)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerInfo) DeepCopyInto(out *DockerInfo) {
+	*out = *in
+	if in.SecurityOptions != nil {
+		in, out := &in.SecurityOptions, &out.SecurityOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerInfo.
+func (in *DockerInfo) DeepCopy() *DockerInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DuplicateUsers) DeepCopyInto(out *DuplicateUsers) {
+	*out = *in
+	if in.UserIDs != nil {
+		in, out := &in.UserIDs, &out.UserIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DuplicateUsers.
+func (in *DuplicateUsers) DeepCopy() *DuplicateUsers {
+	if in == nil {
+		return nil
+	}
+	out := new(DuplicateUsers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicSchema) DeepCopyInto(out *DynamicSchema) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicSchema.
+func (in *DynamicSchema) DeepCopy() *DynamicSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DynamicSchema) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicSchemaList) DeepCopyInto(out *DynamicSchemaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DynamicSchema, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicSchemaList.
+func (in *DynamicSchemaList) DeepCopy() *DynamicSchemaList {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicSchemaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DynamicSchemaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicSchemaSpec) DeepCopyInto(out *DynamicSchemaSpec) {
+	*out = *in
+	if in.ResourceMethods != nil {
+		in, out := &in.ResourceMethods, &out.ResourceMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceFields != nil {
+		in, out := &in.ResourceFields, &out.ResourceFields
+		*out = make(map[string]Field, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ResourceActions != nil {
+		in, out := &in.ResourceActions, &out.ResourceActions
+		*out = make(map[string]Action, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CollectionMethods != nil {
+		in, out := &in.CollectionMethods, &out.CollectionMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CollectionFields != nil {
+		in, out := &in.CollectionFields, &out.CollectionFields
+		*out = make(map[string]Field, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.CollectionActions != nil {
+		in, out := &in.CollectionActions, &out.CollectionActions
+		*out = make(map[string]Action, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CollectionFilters != nil {
+		in, out := &in.CollectionFilters, &out.CollectionFilters
+		*out = make(map[string]Filter, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.IncludeableLinks != nil {
+		in, out := &in.IncludeableLinks, &out.IncludeableLinks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicSchemaSpec.
+func (in *DynamicSchemaSpec) DeepCopy() *DynamicSchemaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicSchemaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicSchemaStatus) DeepCopyInto(out *DynamicSchemaStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicSchemaStatus.
+func (in *DynamicSchemaStatus) DeepCopy() *DynamicSchemaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicSchemaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EKSStatus) DeepCopyInto(out *EKSStatus) {
+	*out = *in
+	if in.UpstreamSpec != nil {
+		in, out := &in.UpstreamSpec, &out.UpstreamSpec
+		*out = new(ekscattleiov1.EKSClusterConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateRequiresTunnel != nil {
+		in, out := &in.PrivateRequiresTunnel, &out.PrivateRequiresTunnel
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManagedLaunchTemplateVersions != nil {
+		in, out := &in.ManagedLaunchTemplateVersions, &out.ManagedLaunchTemplateVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSStatus.
+func (in *EKSStatus) DeepCopy() *EKSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EKSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EraseUserDataInput) DeepCopyInto(out *EraseUserDataInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EraseUserDataInput.
+func (in *EraseUserDataInput) DeepCopy() *EraseUserDataInput {
+	if in == nil {
+		return nil
+	}
+	out := new(EraseUserDataInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EraseUserDataOutput) DeepCopyInto(out *EraseUserDataOutput) {
+	*out = *in
+	if in.PrincipalIDs != nil {
+		in, out := &in.PrincipalIDs, &out.PrincipalIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GlobalRoleBindings != nil {
+		in, out := &in.GlobalRoleBindings, &out.GlobalRoleBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterRoleTemplateBindings != nil {
+		in, out := &in.ClusterRoleTemplateBindings, &out.ClusterRoleTemplateBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProjectRoleTemplateBindings != nil {
+		in, out := &in.ProjectRoleTemplateBindings, &out.ProjectRoleTemplateBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EraseUserDataOutput.
+func (in *EraseUserDataOutput) DeepCopy() *EraseUserDataOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(EraseUserDataOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackup) DeepCopyInto(out *EtcdBackup) {
+	*out = *in
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackup.
+func (in *EtcdBackup) DeepCopy() *EtcdBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EtcdBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupList) DeepCopyInto(out *EtcdBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EtcdBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupList.
+func (in *EtcdBackupList) DeepCopy() *EtcdBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EtcdBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportOutput) DeepCopyInto(out *ExportOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportOutput.
+func (in *ExportOutput) DeepCopy() *ExportOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAuthConfig) DeepCopyInto(out *ExternalAuthConfig) {
+	*out = *in
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAuthConfig.
+func (in *ExternalAuthConfig) DeepCopy() *ExternalAuthConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAuthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExternalAuthConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAuthProvider) DeepCopyInto(out *ExternalAuthProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAuthProvider.
+func (in *ExternalAuthProvider) DeepCopy() *ExternalAuthProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAuthProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExternalAuthProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAuthProviderList) DeepCopyInto(out *ExternalAuthProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ExternalAuthProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAuthProviderList.
+func (in *ExternalAuthProviderList) DeepCopy() *ExternalAuthProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAuthProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExternalAuthProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAuthTestAndApplyInput) DeepCopyInto(out *ExternalAuthTestAndApplyInput) {
+	*out = *in
+	in.ExternalAuthConfig.DeepCopyInto(&out.ExternalAuthConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAuthTestAndApplyInput.
+func (in *ExternalAuthTestAndApplyInput) DeepCopy() *ExternalAuthTestAndApplyInput {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAuthTestAndApplyInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Feature) DeepCopyInto(out *Feature) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Feature.
+func (in *Feature) DeepCopy() *Feature {
+	if in == nil {
+		return nil
+	}
+	out := new(Feature)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Feature) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureList) DeepCopyInto(out *FeatureList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Feature, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureList.
+func (in *FeatureList) DeepCopy() *FeatureList {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FeatureList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureSpec) DeepCopyInto(out *FeatureSpec) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureSpec.
+func (in *FeatureSpec) DeepCopy() *FeatureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureStatus) DeepCopyInto(out *FeatureStatus) {
+	*out = *in
+	if in.LockedValue != nil {
+		in, out := &in.LockedValue, &out.LockedValue
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureStatus.
+func (in *FeatureStatus) DeepCopy() *FeatureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Field) DeepCopyInto(out *Field) {
+	*out = *in
+	in.Default.DeepCopyInto(&out.Default)
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Field.
+func (in *Field) DeepCopy() *Field {
+	if in == nil {
+		return nil
+	}
+	out := new(Field)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Filter) DeepCopyInto(out *Filter) {
+	*out = *in
+	if in.Modifiers != nil {
+		in, out := &in.Modifiers, &out.Modifiers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filter.
+func (in *Filter) DeepCopy() *Filter {
+	if in == nil {
+		return nil
+	}
+	out := new(Filter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetWorkspace) DeepCopyInto(out *FleetWorkspace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetWorkspace.
+func (in *FleetWorkspace) DeepCopy() *FleetWorkspace {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetWorkspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FleetWorkspace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetWorkspaceList) DeepCopyInto(out *FleetWorkspaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FleetWorkspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetWorkspaceList.
+func (in *FleetWorkspaceList) DeepCopy() *FleetWorkspaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetWorkspaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FleetWorkspaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetWorkspacePermission) DeepCopyInto(out *FleetWorkspacePermission) {
+	*out = *in
+	if in.ResourceRules != nil {
+		in, out := &in.ResourceRules, &out.ResourceRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WorkspaceVerbs != nil {
+		in, out := &in.WorkspaceVerbs, &out.WorkspaceVerbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetWorkspacePermission.
+func (in *FleetWorkspacePermission) DeepCopy() *FleetWorkspacePermission {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetWorkspacePermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetWorkspaceStatus) DeepCopyInto(out *FleetWorkspaceStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetWorkspaceStatus.
+func (in *FleetWorkspaceStatus) DeepCopy() *FleetWorkspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetWorkspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreeIpaConfig) DeepCopyInto(out *FreeIpaConfig) {
+	*out = *in
+	in.LdapConfig.DeepCopyInto(&out.LdapConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeIpaConfig.
+func (in *FreeIpaConfig) DeepCopy() *FreeIpaConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FreeIpaConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreeIpaProvider) DeepCopyInto(out *FreeIpaProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeIpaProvider.
+func (in *FreeIpaProvider) DeepCopy() *FreeIpaProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(FreeIpaProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FreeIpaProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreeIpaProviderList) DeepCopyInto(out *FreeIpaProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FreeIpaProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeIpaProviderList.
+func (in *FreeIpaProviderList) DeepCopy() *FreeIpaProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(FreeIpaProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FreeIpaProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreeIpaTestAndApplyInput) DeepCopyInto(out *FreeIpaTestAndApplyInput) {
+	*out = *in
+	in.LdapTestAndApplyInput.DeepCopyInto(&out.LdapTestAndApplyInput)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeIpaTestAndApplyInput.
+func (in *FreeIpaTestAndApplyInput) DeepCopy() *FreeIpaTestAndApplyInput {
+	if in == nil {
+		return nil
+	}
+	out := new(FreeIpaTestAndApplyInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GKEStatus) DeepCopyInto(out *GKEStatus) {
+	*out = *in
+	if in.UpstreamSpec != nil {
+		in, out := &in.UpstreamSpec, &out.UpstreamSpec
+		*out = new(gkecattleiov1.GKEClusterConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrivateRequiresTunnel != nil {
+		in, out := &in.PrivateRequiresTunnel, &out.PrivateRequiresTunnel
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GKEStatus.
+func (in *GKEStatus) DeepCopy() *GKEStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GKEStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenerateKubeConfigOutput) DeepCopyInto(out *GenerateKubeConfigOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerateKubeConfigOutput.
+func (in *GenerateKubeConfigOutput) DeepCopy() *GenerateKubeConfigOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(GenerateKubeConfigOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericLogin) DeepCopyInto(out *GenericLogin) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericLogin.
+func (in *GenericLogin) DeepCopy() *GenericLogin {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericLogin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericOIDCApplyInput) DeepCopyInto(out *GenericOIDCApplyInput) {
+	*out = *in
+	in.OIDCApplyInput.DeepCopyInto(&out.OIDCApplyInput)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCApplyInput.
+func (in *GenericOIDCApplyInput) DeepCopy() *GenericOIDCApplyInput {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericOIDCApplyInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericOIDCConfig) DeepCopyInto(out *GenericOIDCConfig) {
+	*out = *in
+	in.OIDCConfig.DeepCopyInto(&out.OIDCConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCConfig.
+func (in *GenericOIDCConfig) DeepCopy() *GenericOIDCConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericOIDCConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericOIDCLogin) DeepCopyInto(out *GenericOIDCLogin) {
+	*out = *in
+	out.GenericLogin = in.GenericLogin
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCLogin.
+func (in *GenericOIDCLogin) DeepCopy() *GenericOIDCLogin {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericOIDCLogin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericOIDCProvider) DeepCopyInto(out *GenericOIDCProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCProvider.
+func (in *GenericOIDCProvider) DeepCopy() *GenericOIDCProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericOIDCProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GenericOIDCProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericOIDCProviderList) DeepCopyInto(out *GenericOIDCProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GenericOIDCProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCProviderList.
+func (in *GenericOIDCProviderList) DeepCopy() *GenericOIDCProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericOIDCProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GenericOIDCProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericOIDCTestOutput) DeepCopyInto(out *GenericOIDCTestOutput) {
+	*out = *in
+	out.OIDCTestOutput = in.OIDCTestOutput
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCTestOutput.
+func (in *GenericOIDCTestOutput) DeepCopy() *GenericOIDCTestOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericOIDCTestOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubConfig) DeepCopyInto(out *GithubConfig) {
+	*out = *in
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	if in.AdditionalClientIDs != nil {
+		in, out := &in.AdditionalClientIDs, &out.AdditionalClientIDs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HostnameToClientID != nil {
+		in, out := &in.HostnameToClientID, &out.HostnameToClientID
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubConfig.
+func (in *GithubConfig) DeepCopy() *GithubConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GithubConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubConfigApplyInput) DeepCopyInto(out *GithubConfigApplyInput) {
+	*out = *in
+	in.GithubConfig.DeepCopyInto(&out.GithubConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubConfigApplyInput.
+func (in *GithubConfigApplyInput) DeepCopy() *GithubConfigApplyInput {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubConfigApplyInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubConfigList) DeepCopyInto(out *GithubConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GithubConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubConfigList.
+func (in *GithubConfigList) DeepCopy() *GithubConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GithubConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubConfigTestOutput) DeepCopyInto(out *GithubConfigTestOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubConfigTestOutput.
+func (in *GithubConfigTestOutput) DeepCopy() *GithubConfigTestOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubConfigTestOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubLogin) DeepCopyInto(out *GithubLogin) {
+	*out = *in
+	out.GenericLogin = in.GenericLogin
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubLogin.
+func (in *GithubLogin) DeepCopy() *GithubLogin {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubLogin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubProvider) DeepCopyInto(out *GithubProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubProvider.
+func (in *GithubProvider) DeepCopy() *GithubProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GithubProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubProviderList) DeepCopyInto(out *GithubProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GithubProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubProviderList.
+func (in *GithubProviderList) DeepCopy() *GithubProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GithubProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalRole) DeepCopyInto(out *GlobalRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InheritedClusterRoles != nil {
+		in, out := &in.InheritedClusterRoles, &out.InheritedClusterRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InheritedClusterRolesNamespaceSelector != nil {
+		in, out := &in.InheritedClusterRolesNamespaceSelector, &out.InheritedClusterRolesNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespacedRules != nil {
+		in, out := &in.NamespacedRules, &out.NamespacedRules
+		*out = make(map[string][]rbacv1.PolicyRule, len(*in))
+		for key, val := range *in {
+			var outVal []rbacv1.PolicyRule
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]rbacv1.PolicyRule, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.InheritedFleetWorkspacePermissions != nil {
+		in, out := &in.InheritedFleetWorkspacePermissions, &out.InheritedFleetWorkspacePermissions
+		*out = new(FleetWorkspacePermission)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRole.
+func (in *GlobalRole) DeepCopy() *GlobalRole {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlobalRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalRoleBinding) DeepCopyInto(out *GlobalRoleBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleBinding.
+func (in *GlobalRoleBinding) DeepCopy() *GlobalRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlobalRoleBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalRoleBindingList) DeepCopyInto(out *GlobalRoleBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GlobalRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleBindingList.
+func (in *GlobalRoleBindingList) DeepCopy() *GlobalRoleBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalRoleBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlobalRoleBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalRoleBindingStatus) DeepCopyInto(out *GlobalRoleBindingStatus) {
+	*out = *in
+	if in.LocalConditions != nil {
+		in, out := &in.LocalConditions, &out.LocalConditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemoteConditions != nil {
+		in, out := &in.RemoteConditions, &out.RemoteConditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleBindingStatus.
+func (in *GlobalRoleBindingStatus) DeepCopy() *GlobalRoleBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalRoleBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalRoleList) DeepCopyInto(out *GlobalRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GlobalRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleList.
+func (in *GlobalRoleList) DeepCopy() *GlobalRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlobalRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalRoleStatus) DeepCopyInto(out *GlobalRoleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleStatus.
+func (in *GlobalRoleStatus) DeepCopy() *GlobalRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleOAuthProvider) DeepCopyInto(out *GoogleOAuthProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOAuthProvider.
+func (in *GoogleOAuthProvider) DeepCopy() *GoogleOAuthProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleOAuthProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GoogleOAuthProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleOAuthProviderList) DeepCopyInto(out *GoogleOAuthProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GoogleOAuthProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOAuthProviderList.
+func (in *GoogleOAuthProviderList) DeepCopy() *GoogleOAuthProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleOAuthProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GoogleOAuthProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleOauthConfig) DeepCopyInto(out *GoogleOauthConfig) {
+	*out = *in
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthConfig.
+func (in *GoogleOauthConfig) DeepCopy() *GoogleOauthConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleOauthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GoogleOauthConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleOauthConfigApplyInput) DeepCopyInto(out *GoogleOauthConfigApplyInput) {
+	*out = *in
+	in.GoogleOauthConfig.DeepCopyInto(&out.GoogleOauthConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthConfigApplyInput.
+func (in *GoogleOauthConfigApplyInput) DeepCopy() *GoogleOauthConfigApplyInput {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleOauthConfigApplyInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleOauthConfigList) DeepCopyInto(out *GoogleOauthConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GoogleOauthConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthConfigList.
+func (in *GoogleOauthConfigList) DeepCopy() *GoogleOauthConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleOauthConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GoogleOauthConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleOauthConfigTestOutput) DeepCopyInto(out *GoogleOauthConfigTestOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthConfigTestOutput.
+func (in *GoogleOauthConfigTestOutput) DeepCopy() *GoogleOauthConfigTestOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleOauthConfigTestOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleOauthLogin) DeepCopyInto(out *GoogleOauthLogin) {
+	*out = *in
+	out.GenericLogin = in.GenericLogin
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthLogin.
+func (in *GoogleOauthLogin) DeepCopy() *GoogleOauthLogin {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleOauthLogin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Group) DeepCopyInto(out *Group) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.LastSync != nil {
+		in, out := &in.LastSync, &out.LastSync
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Group.
+func (in *Group) DeepCopy() *Group {
+	if in == nil {
+		return nil
+	}
+	out := new(Group)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Group) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupList) DeepCopyInto(out *GroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Group, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupList.
+func (in *GroupList) DeepCopy() *GroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupMember) DeepCopyInto(out *GroupMember) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMember.
+func (in *GroupMember) DeepCopy() *GroupMember {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupMember) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupMemberInfo) DeepCopyInto(out *GroupMemberInfo) {
+	*out = *in
+	if in.LastLogin != nil {
+		in, out := &in.LastLogin, &out.LastLogin
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMemberInfo.
+func (in *GroupMemberInfo) DeepCopy() *GroupMemberInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupMemberInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupMemberList) DeepCopyInto(out *GroupMemberList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GroupMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMemberList.
+func (in *GroupMemberList) DeepCopy() *GroupMemberList {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupMemberList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupMemberList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupMembersInput) DeepCopyInto(out *GroupMembersInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembersInput.
+func (in *GroupMembersInput) DeepCopy() *GroupMembersInput {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupMembersInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupMembersOutput) DeepCopyInto(out *GroupMembersOutput) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]GroupMemberInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembersOutput.
+func (in *GroupMembersOutput) DeepCopy() *GroupMembersOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupMembersOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupMembershipClusterRole) DeepCopyInto(out *GroupMembershipClusterRole) {
+	*out = *in
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembershipClusterRole.
+func (in *GroupMembershipClusterRole) DeepCopy() *GroupMembershipClusterRole {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupMembershipClusterRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupMembershipProjectRole) DeepCopyInto(out *GroupMembershipProjectRole) {
+	*out = *in
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	in.ProjectSelector.DeepCopyInto(&out.ProjectSelector)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSStatus.
-func (in *EKSStatus) DeepCopy() *EKSStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembershipProjectRole.
+func (in *GroupMembershipProjectRole) DeepCopy() *GroupMembershipProjectRole {
 	if in == nil {
 		return nil
 	}
-	out := new(EKSStatus)
+	out := new(GroupMembershipProjectRole)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EtcdBackup) DeepCopyInto(out *EtcdBackup) {
+func (in *GroupMembershipRule) DeepCopyInto(out *GroupMembershipRule) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackup.
-func (in *EtcdBackup) DeepCopy() *EtcdBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembershipRule.
+func (in *GroupMembershipRule) DeepCopy() *GroupMembershipRule {
 	if in == nil {
 		return nil
 	}
-	out := new(EtcdBackup)
+	out := new(GroupMembershipRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EtcdBackup) DeepCopyObject() runtime.Object {
+func (in *GroupMembershipRule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2230,13 +4679,13 @@ func (in *EtcdBackup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EtcdBackupList) DeepCopyInto(out *EtcdBackupList) {
+func (in *GroupMembershipRuleList) DeepCopyInto(out *GroupMembershipRuleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]EtcdBackup, len(*in))
+		*out = make([]GroupMembershipRule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -2244,18 +4693,18 @@ func (in *EtcdBackupList) DeepCopyInto(out *EtcdBackupList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupList.
-func (in *EtcdBackupList) DeepCopy() *EtcdBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembershipRuleList.
+func (in *GroupMembershipRuleList) DeepCopy() *GroupMembershipRuleList {
 	if in == nil {
 		return nil
 	}
-	out := new(EtcdBackupList)
+	out := new(GroupMembershipRuleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EtcdBackupList) DeepCopyObject() runtime.Object {
+func (in *GroupMembershipRuleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2263,43 +4712,173 @@ func (in *EtcdBackupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExportOutput) DeepCopyInto(out *ExportOutput) {
+func (in *GroupMembershipRuleSpec) DeepCopyInto(out *GroupMembershipRuleSpec) {
+	*out = *in
+	if in.GroupPrincipals != nil {
+		in, out := &in.GroupPrincipals, &out.GroupPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupPrincipalPatterns != nil {
+		in, out := &in.GroupPrincipalPatterns, &out.GroupPrincipalPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterRoles != nil {
+		in, out := &in.ClusterRoles, &out.ClusterRoles
+		*out = make([]GroupMembershipClusterRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProjectRoles != nil {
+		in, out := &in.ProjectRoles, &out.ProjectRoles
+		*out = make([]GroupMembershipProjectRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMembershipRuleSpec.
+func (in *GroupMembershipRuleSpec) DeepCopy() *GroupMembershipRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupMembershipRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportClusterYamlInput) DeepCopyInto(out *ImportClusterYamlInput) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportOutput.
-func (in *ExportOutput) DeepCopy() *ExportOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportClusterYamlInput.
+func (in *ImportClusterYamlInput) DeepCopy() *ImportClusterYamlInput {
 	if in == nil {
 		return nil
 	}
-	out := new(ExportOutput)
+	out := new(ImportClusterYamlInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Feature) DeepCopyInto(out *Feature) {
+func (in *ImportYamlOutput) DeepCopyInto(out *ImportYamlOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportYamlOutput.
+func (in *ImportYamlOutput) DeepCopy() *ImportYamlOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportYamlOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportedConfig) DeepCopyInto(out *ImportedConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportedConfig.
+func (in *ImportedConfig) DeepCopy() *ImportedConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportedConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressCapabilities) DeepCopyInto(out *IngressCapabilities) {
+	*out = *in
+	if in.CustomDefaultBackend != nil {
+		in, out := &in.CustomDefaultBackend, &out.CustomDefaultBackend
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressCapabilities.
+func (in *IngressCapabilities) DeepCopy() *IngressCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JITClusterRole) DeepCopyInto(out *JITClusterRole) {
+	*out = *in
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JITClusterRole.
+func (in *JITClusterRole) DeepCopy() *JITClusterRole {
+	if in == nil {
+		return nil
+	}
+	out := new(JITClusterRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JITProjectRole) DeepCopyInto(out *JITProjectRole) {
+	*out = *in
+	if in.ProjectNames != nil {
+		in, out := &in.ProjectNames, &out.ProjectNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JITProjectRole.
+func (in *JITProjectRole) DeepCopy() *JITProjectRole {
+	if in == nil {
+		return nil
+	}
+	out := new(JITProjectRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JITProvisioningPolicy) DeepCopyInto(out *JITProvisioningPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Feature.
-func (in *Feature) DeepCopy() *Feature {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JITProvisioningPolicy.
+func (in *JITProvisioningPolicy) DeepCopy() *JITProvisioningPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(Feature)
+	out := new(JITProvisioningPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Feature) DeepCopyObject() runtime.Object {
+func (in *JITProvisioningPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2307,13 +4886,13 @@ func (in *Feature) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureList) DeepCopyInto(out *FeatureList) {
+func (in *JITProvisioningPolicyList) DeepCopyInto(out *JITProvisioningPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Feature, len(*in))
+		*out = make([]JITProvisioningPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -2321,18 +4900,18 @@ func (in *FeatureList) DeepCopyInto(out *FeatureList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureList.
-func (in *FeatureList) DeepCopy() *FeatureList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JITProvisioningPolicyList.
+func (in *JITProvisioningPolicyList) DeepCopy() *JITProvisioningPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureList)
+	out := new(JITProvisioningPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FeatureList) DeepCopyObject() runtime.Object {
+func (in *JITProvisioningPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2340,111 +4919,137 @@ func (in *FeatureList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureSpec) DeepCopyInto(out *FeatureSpec) {
+func (in *JITProvisioningPolicySpec) DeepCopyInto(out *JITProvisioningPolicySpec) {
 	*out = *in
-	if in.Value != nil {
-		in, out := &in.Value, &out.Value
-		*out = new(bool)
-		**out = **in
+	if in.GroupPrincipals != nil {
+		in, out := &in.GroupPrincipals, &out.GroupPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GlobalRoles != nil {
+		in, out := &in.GlobalRoles, &out.GlobalRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterRoles != nil {
+		in, out := &in.ClusterRoles, &out.ClusterRoles
+		*out = make([]JITClusterRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProjectRoles != nil {
+		in, out := &in.ProjectRoles, &out.ProjectRoles
+		*out = make([]JITProjectRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureSpec.
-func (in *FeatureSpec) DeepCopy() *FeatureSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JITProvisioningPolicySpec.
+func (in *JITProvisioningPolicySpec) DeepCopy() *JITProvisioningPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureSpec)
+	out := new(JITProvisioningPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureStatus) DeepCopyInto(out *FeatureStatus) {
+func (in *K3sConfig) DeepCopyInto(out *K3sConfig) {
 	*out = *in
-	if in.LockedValue != nil {
-		in, out := &in.LockedValue, &out.LockedValue
-		*out = new(bool)
-		**out = **in
-	}
+	out.ClusterUpgradeStrategy = in.ClusterUpgradeStrategy
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureStatus.
-func (in *FeatureStatus) DeepCopy() *FeatureStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K3sConfig.
+func (in *K3sConfig) DeepCopy() *K3sConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureStatus)
+	out := new(K3sConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Field) DeepCopyInto(out *Field) {
+func (in *KerberosConfig) DeepCopyInto(out *KerberosConfig) {
 	*out = *in
-	in.Default.DeepCopyInto(&out.Default)
-	if in.Options != nil {
-		in, out := &in.Options, &out.Options
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Field.
-func (in *Field) DeepCopy() *Field {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KerberosConfig.
+func (in *KerberosConfig) DeepCopy() *KerberosConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Field)
+	out := new(KerberosConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KerberosConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Filter) DeepCopyInto(out *Filter) {
+func (in *KerberosLogin) DeepCopyInto(out *KerberosLogin) {
 	*out = *in
-	if in.Modifiers != nil {
-		in, out := &in.Modifiers, &out.Modifiers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.GenericLogin = in.GenericLogin
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filter.
-func (in *Filter) DeepCopy() *Filter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KerberosLogin.
+func (in *KerberosLogin) DeepCopy() *KerberosLogin {
 	if in == nil {
 		return nil
 	}
-	out := new(Filter)
+	out := new(KerberosLogin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FleetWorkspace) DeepCopyInto(out *FleetWorkspace) {
+func (in *KerberosProvider) DeepCopyInto(out *KerberosProvider) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetWorkspace.
-func (in *FleetWorkspace) DeepCopy() *FleetWorkspace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KerberosProvider.
+func (in *KerberosProvider) DeepCopy() *KerberosProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(FleetWorkspace)
+	out := new(KerberosProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FleetWorkspace) DeepCopyObject() runtime.Object {
+func (in *KerberosProvider) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2452,13 +5057,13 @@ func (in *FleetWorkspace) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FleetWorkspaceList) DeepCopyInto(out *FleetWorkspaceList) {
+func (in *KerberosProviderList) DeepCopyInto(out *KerberosProviderList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]FleetWorkspace, len(*in))
+		*out = make([]KerberosProvider, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -2466,18 +5071,18 @@ func (in *FleetWorkspaceList) DeepCopyInto(out *FleetWorkspaceList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetWorkspaceList.
-func (in *FleetWorkspaceList) DeepCopy() *FleetWorkspaceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KerberosProviderList.
+func (in *KerberosProviderList) DeepCopy() *KerberosProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(FleetWorkspaceList)
+	out := new(KerberosProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FleetWorkspaceList) DeepCopyObject() runtime.Object {
+func (in *KerberosProviderList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2485,101 +5090,69 @@ func (in *FleetWorkspaceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FleetWorkspacePermission) DeepCopyInto(out *FleetWorkspacePermission) {
-	*out = *in
-	if in.ResourceRules != nil {
-		in, out := &in.ResourceRules, &out.ResourceRules
-		*out = make([]rbacv1.PolicyRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.WorkspaceVerbs != nil {
-		in, out := &in.WorkspaceVerbs, &out.WorkspaceVerbs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetWorkspacePermission.
-func (in *FleetWorkspacePermission) DeepCopy() *FleetWorkspacePermission {
-	if in == nil {
-		return nil
-	}
-	out := new(FleetWorkspacePermission)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FleetWorkspaceStatus) DeepCopyInto(out *FleetWorkspaceStatus) {
+func (in *KerberosTestAndApplyInput) DeepCopyInto(out *KerberosTestAndApplyInput) {
 	*out = *in
+	in.KerberosConfig.DeepCopyInto(&out.KerberosConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetWorkspaceStatus.
-func (in *FleetWorkspaceStatus) DeepCopy() *FleetWorkspaceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KerberosTestAndApplyInput.
+func (in *KerberosTestAndApplyInput) DeepCopy() *KerberosTestAndApplyInput {
 	if in == nil {
 		return nil
 	}
-	out := new(FleetWorkspaceStatus)
+	out := new(KerberosTestAndApplyInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FreeIpaConfig) DeepCopyInto(out *FreeIpaConfig) {
+func (in *KeyCloakConfig) DeepCopyInto(out *KeyCloakConfig) {
 	*out = *in
-	in.LdapConfig.DeepCopyInto(&out.LdapConfig)
+	in.SamlConfig.DeepCopyInto(&out.SamlConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeIpaConfig.
-func (in *FreeIpaConfig) DeepCopy() *FreeIpaConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakConfig.
+func (in *KeyCloakConfig) DeepCopy() *KeyCloakConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(FreeIpaConfig)
+	out := new(KeyCloakConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FreeIpaProvider) DeepCopyInto(out *FreeIpaProvider) {
+func (in *KeyCloakOIDCConfig) DeepCopyInto(out *KeyCloakOIDCConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	in.OIDCConfig.DeepCopyInto(&out.OIDCConfig)
+	if in.ClientRolesAsGroups != nil {
+		in, out := &in.ClientRolesAsGroups, &out.ClientRolesAsGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeIpaProvider.
-func (in *FreeIpaProvider) DeepCopy() *FreeIpaProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakOIDCConfig.
+func (in *KeyCloakOIDCConfig) DeepCopy() *KeyCloakOIDCConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(FreeIpaProvider)
+	out := new(KeyCloakOIDCConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FreeIpaProvider) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FreeIpaProviderList) DeepCopyInto(out *FreeIpaProviderList) {
+func (in *KeyCloakOIDCConfigList) DeepCopyInto(out *KeyCloakOIDCConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]FreeIpaProvider, len(*in))
+		*out = make([]KeyCloakOIDCConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -2587,18 +5160,18 @@ func (in *FreeIpaProviderList) DeepCopyInto(out *FreeIpaProviderList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeIpaProviderList.
-func (in *FreeIpaProviderList) DeepCopy() *FreeIpaProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakOIDCConfigList.
+func (in *KeyCloakOIDCConfigList) DeepCopy() *KeyCloakOIDCConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(FreeIpaProviderList)
+	out := new(KeyCloakOIDCConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FreeIpaProviderList) DeepCopyObject() runtime.Object {
+func (in *KeyCloakOIDCConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2606,152 +5179,162 @@ func (in *FreeIpaProviderList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FreeIpaTestAndApplyInput) DeepCopyInto(out *FreeIpaTestAndApplyInput) {
+func (in *KeyCloakOIDCProvider) DeepCopyInto(out *KeyCloakOIDCProvider) {
 	*out = *in
-	in.LdapTestAndApplyInput.DeepCopyInto(&out.LdapTestAndApplyInput)
+	in.OIDCProvider.DeepCopyInto(&out.OIDCProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeIpaTestAndApplyInput.
-func (in *FreeIpaTestAndApplyInput) DeepCopy() *FreeIpaTestAndApplyInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakOIDCProvider.
+func (in *KeyCloakOIDCProvider) DeepCopy() *KeyCloakOIDCProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(FreeIpaTestAndApplyInput)
+	out := new(KeyCloakOIDCProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GKEStatus) DeepCopyInto(out *GKEStatus) {
+func (in *KeyCloakProvider) DeepCopyInto(out *KeyCloakProvider) {
 	*out = *in
-	if in.UpstreamSpec != nil {
-		in, out := &in.UpstreamSpec, &out.UpstreamSpec
-		*out = new(gkecattleiov1.GKEClusterConfigSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PrivateRequiresTunnel != nil {
-		in, out := &in.PrivateRequiresTunnel, &out.PrivateRequiresTunnel
-		*out = new(bool)
-		**out = **in
-	}
+	in.SamlProvider.DeepCopyInto(&out.SamlProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GKEStatus.
-func (in *GKEStatus) DeepCopy() *GKEStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakProvider.
+func (in *KeyCloakProvider) DeepCopy() *KeyCloakProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(GKEStatus)
+	out := new(KeyCloakProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenerateKubeConfigOutput) DeepCopyInto(out *GenerateKubeConfigOutput) {
+func (in *KontainerDriver) DeepCopyInto(out *KontainerDriver) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerateKubeConfigOutput.
-func (in *GenerateKubeConfigOutput) DeepCopy() *GenerateKubeConfigOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KontainerDriver.
+func (in *KontainerDriver) DeepCopy() *KontainerDriver {
 	if in == nil {
 		return nil
 	}
-	out := new(GenerateKubeConfigOutput)
+	out := new(KontainerDriver)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenericLogin) DeepCopyInto(out *GenericLogin) {
-	*out = *in
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericLogin.
-func (in *GenericLogin) DeepCopy() *GenericLogin {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KontainerDriver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(GenericLogin)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenericOIDCApplyInput) DeepCopyInto(out *GenericOIDCApplyInput) {
+func (in *KontainerDriverList) DeepCopyInto(out *KontainerDriverList) {
 	*out = *in
-	in.OIDCApplyInput.DeepCopyInto(&out.OIDCApplyInput)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KontainerDriver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCApplyInput.
-func (in *GenericOIDCApplyInput) DeepCopy() *GenericOIDCApplyInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KontainerDriverList.
+func (in *KontainerDriverList) DeepCopy() *KontainerDriverList {
 	if in == nil {
 		return nil
 	}
-	out := new(GenericOIDCApplyInput)
+	out := new(KontainerDriverList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KontainerDriverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenericOIDCConfig) DeepCopyInto(out *GenericOIDCConfig) {
+func (in *KontainerDriverSpec) DeepCopyInto(out *KontainerDriverSpec) {
 	*out = *in
-	in.OIDCConfig.DeepCopyInto(&out.OIDCConfig)
+	if in.WhitelistDomains != nil {
+		in, out := &in.WhitelistDomains, &out.WhitelistDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCConfig.
-func (in *GenericOIDCConfig) DeepCopy() *GenericOIDCConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KontainerDriverSpec.
+func (in *KontainerDriverSpec) DeepCopy() *KontainerDriverSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GenericOIDCConfig)
+	out := new(KontainerDriverSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenericOIDCLogin) DeepCopyInto(out *GenericOIDCLogin) {
+func (in *KontainerDriverStatus) DeepCopyInto(out *KontainerDriverStatus) {
 	*out = *in
-	out.GenericLogin = in.GenericLogin
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCLogin.
-func (in *GenericOIDCLogin) DeepCopy() *GenericOIDCLogin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KontainerDriverStatus.
+func (in *KontainerDriverStatus) DeepCopy() *KontainerDriverStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GenericOIDCLogin)
+	out := new(KontainerDriverStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenericOIDCProvider) DeepCopyInto(out *GenericOIDCProvider) {
+func (in *LdapConfig) DeepCopyInto(out *LdapConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	in.LdapFields.DeepCopyInto(&out.LdapFields)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCProvider.
-func (in *GenericOIDCProvider) DeepCopy() *GenericOIDCProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapConfig.
+func (in *LdapConfig) DeepCopy() *LdapConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(GenericOIDCProvider)
+	out := new(LdapConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GenericOIDCProvider) DeepCopyObject() runtime.Object {
+func (in *LdapConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2759,13 +5342,13 @@ func (in *GenericOIDCProvider) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenericOIDCProviderList) DeepCopyInto(out *GenericOIDCProviderList) {
+func (in *LdapConfigList) DeepCopyInto(out *LdapConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GenericOIDCProvider, len(*in))
+		*out = make([]LdapConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -2773,18 +5356,18 @@ func (in *GenericOIDCProviderList) DeepCopyInto(out *GenericOIDCProviderList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCProviderList.
-func (in *GenericOIDCProviderList) DeepCopy() *GenericOIDCProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapConfigList.
+func (in *LdapConfigList) DeepCopy() *LdapConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(GenericOIDCProviderList)
+	out := new(LdapConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GenericOIDCProviderList) DeepCopyObject() runtime.Object {
+func (in *LdapConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2792,105 +5375,143 @@ func (in *GenericOIDCProviderList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenericOIDCTestOutput) DeepCopyInto(out *GenericOIDCTestOutput) {
+func (in *LdapFields) DeepCopyInto(out *LdapFields) {
 	*out = *in
-	out.OIDCTestOutput = in.OIDCTestOutput
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericOIDCTestOutput.
-func (in *GenericOIDCTestOutput) DeepCopy() *GenericOIDCTestOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapFields.
+func (in *LdapFields) DeepCopy() *LdapFields {
 	if in == nil {
 		return nil
 	}
-	out := new(GenericOIDCTestOutput)
+	out := new(LdapFields)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GithubConfig) DeepCopyInto(out *GithubConfig) {
+func (in *LdapTestAndApplyInput) DeepCopyInto(out *LdapTestAndApplyInput) {
 	*out = *in
-	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
-	if in.AdditionalClientIDs != nil {
-		in, out := &in.AdditionalClientIDs, &out.AdditionalClientIDs
+	in.LdapConfig.DeepCopyInto(&out.LdapConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapTestAndApplyInput.
+func (in *LdapTestAndApplyInput) DeepCopy() *LdapTestAndApplyInput {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapTestAndApplyInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkPrincipalInput) DeepCopyInto(out *LinkPrincipalInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkPrincipalInput.
+func (in *LinkPrincipalInput) DeepCopy() *LinkPrincipalInput {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkPrincipalInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListOpts) DeepCopyInto(out *ListOpts) {
+	*out = *in
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.HostnameToClientID != nil {
-		in, out := &in.HostnameToClientID, &out.HostnameToClientID
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListOpts.
+func (in *ListOpts) DeepCopy() *ListOpts {
+	if in == nil {
+		return nil
+	}
+	out := new(ListOpts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerCapabilities) DeepCopyInto(out *LoadBalancerCapabilities) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProtocolsSupported != nil {
+		in, out := &in.ProtocolsSupported, &out.ProtocolsSupported
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubConfig.
-func (in *GithubConfig) DeepCopy() *GithubConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerCapabilities.
+func (in *LoadBalancerCapabilities) DeepCopy() *LoadBalancerCapabilities {
 	if in == nil {
 		return nil
 	}
-	out := new(GithubConfig)
+	out := new(LoadBalancerCapabilities)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GithubConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GithubConfigApplyInput) DeepCopyInto(out *GithubConfigApplyInput) {
+func (in *LocalClusterAuthEndpoint) DeepCopyInto(out *LocalClusterAuthEndpoint) {
 	*out = *in
-	in.GithubConfig.DeepCopyInto(&out.GithubConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubConfigApplyInput.
-func (in *GithubConfigApplyInput) DeepCopy() *GithubConfigApplyInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalClusterAuthEndpoint.
+func (in *LocalClusterAuthEndpoint) DeepCopy() *LocalClusterAuthEndpoint {
 	if in == nil {
 		return nil
 	}
-	out := new(GithubConfigApplyInput)
+	out := new(LocalClusterAuthEndpoint)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GithubConfigList) DeepCopyInto(out *GithubConfigList) {
+func (in *LocalConfig) DeepCopyInto(out *LocalConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]GithubConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubConfigList.
-func (in *GithubConfigList) DeepCopy() *GithubConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalConfig.
+func (in *LocalConfig) DeepCopy() *LocalConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(GithubConfigList)
+	out := new(LocalConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GithubConfigList) DeepCopyObject() runtime.Object {
+func (in *LocalConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2898,40 +5519,40 @@ func (in *GithubConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GithubConfigTestOutput) DeepCopyInto(out *GithubConfigTestOutput) {
+func (in *LocalConfigList) DeepCopyInto(out *LocalConfigList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LocalConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubConfigTestOutput.
-func (in *GithubConfigTestOutput) DeepCopy() *GithubConfigTestOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalConfigList.
+func (in *LocalConfigList) DeepCopy() *LocalConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(GithubConfigTestOutput)
+	out := new(LocalConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GithubLogin) DeepCopyInto(out *GithubLogin) {
-	*out = *in
-	out.GenericLogin = in.GenericLogin
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubLogin.
-func (in *GithubLogin) DeepCopy() *GithubLogin {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LocalConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(GithubLogin)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GithubProvider) DeepCopyInto(out *GithubProvider) {
+func (in *LocalProvider) DeepCopyInto(out *LocalProvider) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -2939,18 +5560,18 @@ func (in *GithubProvider) DeepCopyInto(out *GithubProvider) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubProvider.
-func (in *GithubProvider) DeepCopy() *GithubProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalProvider.
+func (in *LocalProvider) DeepCopy() *LocalProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(GithubProvider)
+	out := new(LocalProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GithubProvider) DeepCopyObject() runtime.Object {
+func (in *LocalProvider) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2958,13 +5579,13 @@ func (in *GithubProvider) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GithubProviderList) DeepCopyInto(out *GithubProviderList) {
+func (in *LocalProviderList) DeepCopyInto(out *LocalProviderList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GithubProvider, len(*in))
+		*out = make([]LocalProvider, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -2972,18 +5593,18 @@ func (in *GithubProviderList) DeepCopyInto(out *GithubProviderList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubProviderList.
-func (in *GithubProviderList) DeepCopy() *GithubProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalProviderList.
+func (in *LocalProviderList) DeepCopy() *LocalProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(GithubProviderList)
+	out := new(LocalProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GithubProviderList) DeepCopyObject() runtime.Object {
+func (in *LocalProviderList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2991,87 +5612,59 @@ func (in *GithubProviderList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalRole) DeepCopyInto(out *GlobalRole) {
+func (in *LoginAsInput) DeepCopyInto(out *LoginAsInput) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]rbacv1.PolicyRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InheritedClusterRoles != nil {
-		in, out := &in.InheritedClusterRoles, &out.InheritedClusterRoles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.NamespacedRules != nil {
-		in, out := &in.NamespacedRules, &out.NamespacedRules
-		*out = make(map[string][]rbacv1.PolicyRule, len(*in))
-		for key, val := range *in {
-			var outVal []rbacv1.PolicyRule
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]rbacv1.PolicyRule, len(*in))
-				for i := range *in {
-					(*in)[i].DeepCopyInto(&(*out)[i])
-				}
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.InheritedFleetWorkspacePermissions != nil {
-		in, out := &in.InheritedFleetWorkspacePermissions, &out.InheritedFleetWorkspacePermissions
-		*out = new(FleetWorkspacePermission)
-		(*in).DeepCopyInto(*out)
-	}
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRole.
-func (in *GlobalRole) DeepCopy() *GlobalRole {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginAsInput.
+func (in *LoginAsInput) DeepCopy() *LoginAsInput {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalRole)
+	out := new(LoginAsInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GlobalRole) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginAsOutput) DeepCopyInto(out *LoginAsOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginAsOutput.
+func (in *LoginAsOutput) DeepCopy() *LoginAsOutput {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(LoginAsOutput)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalRoleBinding) DeepCopyInto(out *GlobalRoleBinding) {
+func (in *ManagedChart) DeepCopyInto(out *ManagedChart) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleBinding.
-func (in *GlobalRoleBinding) DeepCopy() *GlobalRoleBinding {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedChart.
+func (in *ManagedChart) DeepCopy() *ManagedChart {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalRoleBinding)
+	out := new(ManagedChart)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GlobalRoleBinding) DeepCopyObject() runtime.Object {
+func (in *ManagedChart) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -3079,13 +5672,13 @@ func (in *GlobalRoleBinding) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalRoleBindingList) DeepCopyInto(out *GlobalRoleBindingList) {
+func (in *ManagedChartList) DeepCopyInto(out *ManagedChartList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GlobalRoleBinding, len(*in))
+		*out = make([]ManagedChart, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -3093,18 +5686,18 @@ func (in *GlobalRoleBindingList) DeepCopyInto(out *GlobalRoleBindingList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleBindingList.
-func (in *GlobalRoleBindingList) DeepCopy() *GlobalRoleBindingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedChartList.
+func (in *ManagedChartList) DeepCopy() *ManagedChartList {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalRoleBindingList)
+	out := new(ManagedChartList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GlobalRoleBindingList) DeepCopyObject() runtime.Object {
+func (in *ManagedChartList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -3112,18 +5705,25 @@ func (in *GlobalRoleBindingList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalRoleBindingStatus) DeepCopyInto(out *GlobalRoleBindingStatus) {
+func (in *ManagedChartSpec) DeepCopyInto(out *ManagedChartSpec) {
 	*out = *in
-	if in.LocalConditions != nil {
-		in, out := &in.LocalConditions, &out.LocalConditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = (*in).DeepCopy()
 	}
-	if in.RemoteConditions != nil {
-		in, out := &in.RemoteConditions, &out.RemoteConditions
-		*out = make([]metav1.Condition, len(*in))
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = new(v1alpha1.DiffOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(v1alpha1.RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]v1alpha1.BundleTarget, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -3131,182 +5731,210 @@ func (in *GlobalRoleBindingStatus) DeepCopyInto(out *GlobalRoleBindingStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleBindingStatus.
-func (in *GlobalRoleBindingStatus) DeepCopy() *GlobalRoleBindingStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedChartSpec.
+func (in *ManagedChartSpec) DeepCopy() *ManagedChartSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalRoleBindingStatus)
+	out := new(ManagedChartSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalRoleList) DeepCopyInto(out *GlobalRoleList) {
+func (in *ManagedChartStatus) DeepCopyInto(out *ManagedChartStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]GlobalRole, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	in.BundleStatus.DeepCopyInto(&out.BundleStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedChartStatus.
+func (in *ManagedChartStatus) DeepCopy() *ManagedChartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedChartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MapDelta) DeepCopyInto(out *MapDelta) {
+	*out = *in
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleList.
-func (in *GlobalRoleList) DeepCopy() *GlobalRoleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapDelta.
+func (in *MapDelta) DeepCopy() *MapDelta {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalRoleList)
+	out := new(MapDelta)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GlobalRoleList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in MapStringInterface) DeepCopyInto(out *MapStringInterface) {
+	{
+		in := &in
+		clone := in.DeepCopy()
+		*out = *clone
+		return
 	}
-	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalRoleStatus) DeepCopyInto(out *GlobalRoleStatus) {
+func (in *Member) DeepCopyInto(out *Member) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalRoleStatus.
-func (in *GlobalRoleStatus) DeepCopy() *GlobalRoleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Member.
+func (in *Member) DeepCopy() *Member {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalRoleStatus)
+	out := new(Member)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleOAuthProvider) DeepCopyInto(out *GoogleOAuthProvider) {
+func (in *MergeUsersInput) DeepCopyInto(out *MergeUsersInput) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOAuthProvider.
-func (in *GoogleOAuthProvider) DeepCopy() *GoogleOAuthProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeUsersInput.
+func (in *MergeUsersInput) DeepCopy() *MergeUsersInput {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleOAuthProvider)
+	out := new(MergeUsersInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GoogleOAuthProvider) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleOAuthProviderList) DeepCopyInto(out *GoogleOAuthProviderList) {
+func (in *MergeUsersOutput) DeepCopyInto(out *MergeUsersOutput) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]GoogleOAuthProvider, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PrincipalIDs != nil {
+		in, out := &in.PrincipalIDs, &out.PrincipalIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GlobalRoleBindings != nil {
+		in, out := &in.GlobalRoleBindings, &out.GlobalRoleBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterRoleTemplateBindings != nil {
+		in, out := &in.ClusterRoleTemplateBindings, &out.ClusterRoleTemplateBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProjectRoleTemplateBindings != nil {
+		in, out := &in.ProjectRoleTemplateBindings, &out.ProjectRoleTemplateBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOAuthProviderList.
-func (in *GoogleOAuthProviderList) DeepCopy() *GoogleOAuthProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeUsersOutput.
+func (in *MergeUsersOutput) DeepCopy() *MergeUsersOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleOAuthProviderList)
+	out := new(MergeUsersOutput)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GoogleOAuthProviderList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleOauthConfig) DeepCopyInto(out *GoogleOauthConfig) {
+func (in *MetadataUpdate) DeepCopyInto(out *MetadataUpdate) {
 	*out = *in
-	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	in.Labels.DeepCopyInto(&out.Labels)
+	in.Annotations.DeepCopyInto(&out.Annotations)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthConfig.
-func (in *GoogleOauthConfig) DeepCopy() *GoogleOauthConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataUpdate.
+func (in *MetadataUpdate) DeepCopy() *MetadataUpdate {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleOauthConfig)
+	out := new(MetadataUpdate)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GoogleOauthConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleOauthConfigApplyInput) DeepCopyInto(out *GoogleOauthConfigApplyInput) {
+func (in *MultiClusterRoleTemplateBinding) DeepCopyInto(out *MultiClusterRoleTemplateBinding) {
 	*out = *in
-	in.GoogleOauthConfig.DeepCopyInto(&out.GoogleOauthConfig)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthConfigApplyInput.
-func (in *GoogleOauthConfigApplyInput) DeepCopy() *GoogleOauthConfigApplyInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterRoleTemplateBinding.
+func (in *MultiClusterRoleTemplateBinding) DeepCopy() *MultiClusterRoleTemplateBinding {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleOauthConfigApplyInput)
+	out := new(MultiClusterRoleTemplateBinding)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MultiClusterRoleTemplateBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleOauthConfigList) DeepCopyInto(out *GoogleOauthConfigList) {
+func (in *MultiClusterRoleTemplateBindingList) DeepCopyInto(out *MultiClusterRoleTemplateBindingList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GoogleOauthConfig, len(*in))
+		*out = make([]MultiClusterRoleTemplateBinding, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -3314,18 +5942,18 @@ func (in *GoogleOauthConfigList) DeepCopyInto(out *GoogleOauthConfigList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthConfigList.
-func (in *GoogleOauthConfigList) DeepCopy() *GoogleOauthConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterRoleTemplateBindingList.
+func (in *MultiClusterRoleTemplateBindingList) DeepCopy() *MultiClusterRoleTemplateBindingList {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleOauthConfigList)
+	out := new(MultiClusterRoleTemplateBindingList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GoogleOauthConfigList) DeepCopyObject() runtime.Object {
+func (in *MultiClusterRoleTemplateBindingList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -3333,58 +5961,83 @@ func (in *GoogleOauthConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleOauthConfigTestOutput) DeepCopyInto(out *GoogleOauthConfigTestOutput) {
+func (in *MultiClusterRoleTemplateBindingSpec) DeepCopyInto(out *MultiClusterRoleTemplateBindingSpec) {
 	*out = *in
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthConfigTestOutput.
-func (in *GoogleOauthConfigTestOutput) DeepCopy() *GoogleOauthConfigTestOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterRoleTemplateBindingSpec.
+func (in *MultiClusterRoleTemplateBindingSpec) DeepCopy() *MultiClusterRoleTemplateBindingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleOauthConfigTestOutput)
+	out := new(MultiClusterRoleTemplateBindingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleOauthLogin) DeepCopyInto(out *GoogleOauthLogin) {
+func (in *MultiClusterRoleTemplateBindingStatus) DeepCopyInto(out *MultiClusterRoleTemplateBindingStatus) {
 	*out = *in
-	out.GenericLogin = in.GenericLogin
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleOauthLogin.
-func (in *GoogleOauthLogin) DeepCopy() *GoogleOauthLogin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterRoleTemplateBindingStatus.
+func (in *MultiClusterRoleTemplateBindingStatus) DeepCopy() *MultiClusterRoleTemplateBindingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleOauthLogin)
+	out := new(MultiClusterRoleTemplateBindingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Group) DeepCopyInto(out *Group) {
+func (in *NamespaceResourceQuota) DeepCopyInto(out *NamespaceResourceQuota) {
+	*out = *in
+	out.Limit = in.Limit
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceResourceQuota.
+func (in *NamespaceResourceQuota) DeepCopy() *NamespaceResourceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceResourceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Node) DeepCopyInto(out *Node) {
 	*out = *in
+	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Group.
-func (in *Group) DeepCopy() *Group {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Node.
+func (in *Node) DeepCopy() *Node {
 	if in == nil {
 		return nil
 	}
-	out := new(Group)
+	out := new(Node)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Group) DeepCopyObject() runtime.Object {
+func (in *Node) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -3392,58 +6045,109 @@ func (in *Group) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GroupList) DeepCopyInto(out *GroupList) {
+func (in *NodeCommonParams) DeepCopyInto(out *NodeCommonParams) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Group, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.EngineOpt != nil {
+		in, out := &in.EngineOpt, &out.EngineOpt
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StorageOpt != nil {
+		in, out := &in.StorageOpt, &out.StorageOpt
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LogOpt != nil {
+		in, out := &in.LogOpt, &out.LogOpt
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EngineInsecureRegistry != nil {
+		in, out := &in.EngineInsecureRegistry, &out.EngineInsecureRegistry
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EngineRegistryMirror != nil {
+		in, out := &in.EngineRegistryMirror, &out.EngineRegistryMirror
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EngineLabel != nil {
+		in, out := &in.EngineLabel, &out.EngineLabel
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EngineEnv != nil {
+		in, out := &in.EngineEnv, &out.EngineEnv
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
+	if in.UseInternalIPAddress != nil {
+		in, out := &in.UseInternalIPAddress, &out.UseInternalIPAddress
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupList.
-func (in *GroupList) DeepCopy() *GroupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCommonParams.
+func (in *NodeCommonParams) DeepCopy() *NodeCommonParams {
 	if in == nil {
 		return nil
 	}
-	out := new(GroupList)
+	out := new(NodeCommonParams)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GroupList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCondition) DeepCopyInto(out *NodeCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCondition.
+func (in *NodeCondition) DeepCopy() *NodeCondition {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(NodeCondition)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GroupMember) DeepCopyInto(out *GroupMember) {
+func (in *NodeDriver) DeepCopyInto(out *NodeDriver) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMember.
-func (in *GroupMember) DeepCopy() *GroupMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriver.
+func (in *NodeDriver) DeepCopy() *NodeDriver {
 	if in == nil {
 		return nil
 	}
-	out := new(GroupMember)
+	out := new(NodeDriver)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GroupMember) DeepCopyObject() runtime.Object {
+func (in *NodeDriver) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -3451,13 +6155,13 @@ func (in *GroupMember) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GroupMemberList) DeepCopyInto(out *GroupMemberList) {
+func (in *NodeDriverList) DeepCopyInto(out *NodeDriverList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GroupMember, len(*in))
+		*out = make([]NodeDriver, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -3465,18 +6169,18 @@ func (in *GroupMemberList) DeepCopyInto(out *GroupMemberList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMemberList.
-func (in *GroupMemberList) DeepCopy() *GroupMemberList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriverList.
+func (in *NodeDriverList) DeepCopy() *NodeDriverList {
 	if in == nil {
 		return nil
 	}
-	out := new(GroupMemberList)
+	out := new(NodeDriverList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GroupMemberList) DeepCopyObject() runtime.Object {
+func (in *NodeDriverList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -3484,133 +6188,138 @@ func (in *GroupMemberList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImportClusterYamlInput) DeepCopyInto(out *ImportClusterYamlInput) {
+func (in *NodeDriverSpec) DeepCopyInto(out *NodeDriverSpec) {
 	*out = *in
+	if in.WhitelistDomains != nil {
+		in, out := &in.WhitelistDomains, &out.WhitelistDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportClusterYamlInput.
-func (in *ImportClusterYamlInput) DeepCopy() *ImportClusterYamlInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriverSpec.
+func (in *NodeDriverSpec) DeepCopy() *NodeDriverSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ImportClusterYamlInput)
+	out := new(NodeDriverSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImportYamlOutput) DeepCopyInto(out *ImportYamlOutput) {
+func (in *NodeDriverStatus) DeepCopyInto(out *NodeDriverStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportYamlOutput.
-func (in *ImportYamlOutput) DeepCopy() *ImportYamlOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriverStatus.
+func (in *NodeDriverStatus) DeepCopy() *NodeDriverStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ImportYamlOutput)
+	out := new(NodeDriverStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImportedConfig) DeepCopyInto(out *ImportedConfig) {
+func (in *NodeList) DeepCopyInto(out *NodeList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Node, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportedConfig.
-func (in *ImportedConfig) DeepCopy() *ImportedConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeList.
+func (in *NodeList) DeepCopy() *NodeList {
 	if in == nil {
 		return nil
 	}
-	out := new(ImportedConfig)
+	out := new(NodeList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressCapabilities) DeepCopyInto(out *IngressCapabilities) {
-	*out = *in
-	if in.CustomDefaultBackend != nil {
-		in, out := &in.CustomDefaultBackend, &out.CustomDefaultBackend
-		*out = new(bool)
-		**out = **in
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressCapabilities.
-func (in *IngressCapabilities) DeepCopy() *IngressCapabilities {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(IngressCapabilities)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *K3sConfig) DeepCopyInto(out *K3sConfig) {
+func (in *NodePlan) DeepCopyInto(out *NodePlan) {
 	*out = *in
-	out.ClusterUpgradeStrategy = in.ClusterUpgradeStrategy
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(types.RKEConfigNodePlan)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K3sConfig.
-func (in *K3sConfig) DeepCopy() *K3sConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePlan.
+func (in *NodePlan) DeepCopy() *NodePlan {
 	if in == nil {
 		return nil
 	}
-	out := new(K3sConfig)
+	out := new(NodePlan)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeyCloakConfig) DeepCopyInto(out *KeyCloakConfig) {
+func (in *NodePool) DeepCopyInto(out *NodePool) {
 	*out = *in
-	in.SamlConfig.DeepCopyInto(&out.SamlConfig)
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakConfig.
-func (in *KeyCloakConfig) DeepCopy() *KeyCloakConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
 	if in == nil {
 		return nil
 	}
-	out := new(KeyCloakConfig)
+	out := new(NodePool)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeyCloakOIDCConfig) DeepCopyInto(out *KeyCloakOIDCConfig) {
-	*out = *in
-	in.OIDCConfig.DeepCopyInto(&out.OIDCConfig)
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakOIDCConfig.
-func (in *KeyCloakOIDCConfig) DeepCopy() *KeyCloakOIDCConfig {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(KeyCloakOIDCConfig)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeyCloakOIDCConfigList) DeepCopyInto(out *KeyCloakOIDCConfigList) {
+func (in *NodePoolList) DeepCopyInto(out *NodePoolList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KeyCloakOIDCConfig, len(*in))
+		*out = make([]NodePool, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -3618,18 +6327,18 @@ func (in *KeyCloakOIDCConfigList) DeepCopyInto(out *KeyCloakOIDCConfigList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakOIDCConfigList.
-func (in *KeyCloakOIDCConfigList) DeepCopy() *KeyCloakOIDCConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolList.
+func (in *NodePoolList) DeepCopy() *NodePoolList {
 	if in == nil {
 		return nil
 	}
-	out := new(KeyCloakOIDCConfigList)
+	out := new(NodePoolList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeyCloakOIDCConfigList) DeepCopyObject() runtime.Object {
+func (in *NodePoolList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -3637,162 +6346,203 @@ func (in *KeyCloakOIDCConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeyCloakOIDCProvider) DeepCopyInto(out *KeyCloakOIDCProvider) {
+func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 	*out = *in
-	in.OIDCProvider.DeepCopyInto(&out.OIDCProvider)
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakOIDCProvider.
-func (in *KeyCloakOIDCProvider) DeepCopy() *KeyCloakOIDCProvider {
-	if in == nil {
-		return nil
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeAnnotations != nil {
+		in, out := &in.NodeAnnotations, &out.NodeAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	out := new(KeyCloakOIDCProvider)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeyCloakProvider) DeepCopyInto(out *KeyCloakProvider) {
-	*out = *in
-	in.SamlProvider.DeepCopyInto(&out.SamlProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCloakProvider.
-func (in *KeyCloakProvider) DeepCopy() *KeyCloakProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpec.
+func (in *NodePoolSpec) DeepCopy() *NodePoolSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeyCloakProvider)
+	out := new(NodePoolSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KontainerDriver) DeepCopyInto(out *KontainerDriver) {
+func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KontainerDriver.
-func (in *KontainerDriver) DeepCopy() *KontainerDriver {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
+func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KontainerDriver)
+	out := new(NodePoolStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KontainerDriver) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KontainerDriverList) DeepCopyInto(out *KontainerDriverList) {
+func (in *NodeSpec) DeepCopyInto(out *NodeSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KontainerDriver, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KontainerDriverList.
-func (in *KontainerDriverList) DeepCopy() *KontainerDriverList {
-	if in == nil {
-		return nil
+	if in.CustomConfig != nil {
+		in, out := &in.CustomConfig, &out.CustomConfig
+		*out = new(CustomConfig)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(KontainerDriverList)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KontainerDriverList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+	in.InternalNodeSpec.DeepCopyInto(&out.InternalNodeSpec)
+	if in.DesiredNodeTaints != nil {
+		in, out := &in.DesiredNodeTaints, &out.DesiredNodeTaints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	return nil
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KontainerDriverSpec) DeepCopyInto(out *KontainerDriverSpec) {
-	*out = *in
-	if in.WhitelistDomains != nil {
-		in, out := &in.WhitelistDomains, &out.WhitelistDomains
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.UpdateTaintsFromAPI != nil {
+		in, out := &in.UpdateTaintsFromAPI, &out.UpdateTaintsFromAPI
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeDrainInput != nil {
+		in, out := &in.NodeDrainInput, &out.NodeDrainInput
+		*out = new(types.NodeDrainInput)
+		(*in).DeepCopyInto(*out)
 	}
+	in.MetadataUpdate.DeepCopyInto(&out.MetadataUpdate)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KontainerDriverSpec.
-func (in *KontainerDriverSpec) DeepCopy() *KontainerDriverSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSpec.
+func (in *NodeSpec) DeepCopy() *NodeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KontainerDriverSpec)
+	out := new(NodeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KontainerDriverStatus) DeepCopyInto(out *KontainerDriverStatus) {
+func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+		*out = make([]NodeCondition, len(*in))
 		copy(*out, *in)
 	}
+	in.InternalNodeStatus.DeepCopyInto(&out.InternalNodeStatus)
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.NodeTemplateSpec != nil {
+		in, out := &in.NodeTemplateSpec, &out.NodeTemplateSpec
+		*out = new(NodeTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeConfig != nil {
+		in, out := &in.NodeConfig, &out.NodeConfig
+		*out = new(types.RKEConfigNode)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeAnnotations != nil {
+		in, out := &in.NodeAnnotations, &out.NodeAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DockerInfo != nil {
+		in, out := &in.DockerInfo, &out.DockerInfo
+		*out = new(DockerInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodePlan != nil {
+		in, out := &in.NodePlan, &out.NodePlan
+		*out = new(NodePlan)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KontainerDriverStatus.
-func (in *KontainerDriverStatus) DeepCopy() *KontainerDriverStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
+func (in *NodeStatus) DeepCopy() *NodeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KontainerDriverStatus)
+	out := new(NodeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LdapConfig) DeepCopyInto(out *LdapConfig) {
+func (in *NodeTemplate) DeepCopyInto(out *NodeTemplate) {
 	*out = *in
-	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
-	in.LdapFields.DeepCopyInto(&out.LdapFields)
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapConfig.
-func (in *LdapConfig) DeepCopy() *LdapConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplate.
+func (in *NodeTemplate) DeepCopy() *NodeTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(LdapConfig)
+	out := new(NodeTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LdapConfig) DeepCopyObject() runtime.Object {
+func (in *NodeTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -3800,220 +6550,178 @@ func (in *LdapConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LdapConfigList) DeepCopyInto(out *LdapConfigList) {
+func (in *NodeTemplateCondition) DeepCopyInto(out *NodeTemplateCondition) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]LdapConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapConfigList.
-func (in *LdapConfigList) DeepCopy() *LdapConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplateCondition.
+func (in *NodeTemplateCondition) DeepCopy() *NodeTemplateCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(LdapConfigList)
+	out := new(NodeTemplateCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LdapConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LdapFields) DeepCopyInto(out *LdapFields) {
+func (in *NodeTemplateList) DeepCopyInto(out *NodeTemplateList) {
 	*out = *in
-	if in.Servers != nil {
-		in, out := &in.Servers, &out.Servers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapFields.
-func (in *LdapFields) DeepCopy() *LdapFields {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplateList.
+func (in *NodeTemplateList) DeepCopy() *NodeTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(LdapFields)
+	out := new(NodeTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LdapTestAndApplyInput) DeepCopyInto(out *LdapTestAndApplyInput) {
-	*out = *in
-	in.LdapConfig.DeepCopyInto(&out.LdapConfig)
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapTestAndApplyInput.
-func (in *LdapTestAndApplyInput) DeepCopy() *LdapTestAndApplyInput {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(LdapTestAndApplyInput)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ListOpts) DeepCopyInto(out *ListOpts) {
+func (in *NodeTemplateSpec) DeepCopyInto(out *NodeTemplateSpec) {
 	*out = *in
-	if in.Filters != nil {
-		in, out := &in.Filters, &out.Filters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.NodeCommonParams.DeepCopyInto(&out.NodeCommonParams)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListOpts.
-func (in *ListOpts) DeepCopy() *ListOpts {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplateSpec.
+func (in *NodeTemplateSpec) DeepCopy() *NodeTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ListOpts)
+	out := new(NodeTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadBalancerCapabilities) DeepCopyInto(out *LoadBalancerCapabilities) {
+func (in *NodeTemplateStatus) DeepCopyInto(out *NodeTemplateStatus) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ProtocolsSupported != nil {
-		in, out := &in.ProtocolsSupported, &out.ProtocolsSupported
-		*out = make([]string, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]NodeTemplateCondition, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerCapabilities.
-func (in *LoadBalancerCapabilities) DeepCopy() *LoadBalancerCapabilities {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplateStatus.
+func (in *NodeTemplateStatus) DeepCopy() *NodeTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadBalancerCapabilities)
+	out := new(NodeTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalClusterAuthEndpoint) DeepCopyInto(out *LocalClusterAuthEndpoint) {
+func (in *OAuthEndpoint) DeepCopyInto(out *OAuthEndpoint) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalClusterAuthEndpoint.
-func (in *LocalClusterAuthEndpoint) DeepCopy() *LocalClusterAuthEndpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuthEndpoint.
+func (in *OAuthEndpoint) DeepCopy() *OAuthEndpoint {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalClusterAuthEndpoint)
+	out := new(OAuthEndpoint)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalConfig) DeepCopyInto(out *LocalConfig) {
+func (in *OAuthProvider) DeepCopyInto(out *OAuthProvider) {
 	*out = *in
-	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.OAuthEndpoint = in.OAuthEndpoint
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalConfig.
-func (in *LocalConfig) DeepCopy() *LocalConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuthProvider.
+func (in *OAuthProvider) DeepCopy() *OAuthProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalConfig)
+	out := new(OAuthProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LocalConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalConfigList) DeepCopyInto(out *LocalConfigList) {
+func (in *OIDCApplyInput) DeepCopyInto(out *OIDCApplyInput) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]LocalConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.OIDCConfig.DeepCopyInto(&out.OIDCConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalConfigList.
-func (in *LocalConfigList) DeepCopy() *LocalConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCApplyInput.
+func (in *OIDCApplyInput) DeepCopy() *OIDCApplyInput {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalConfigList)
+	out := new(OIDCApplyInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LocalConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalProvider) DeepCopyInto(out *LocalProvider) {
+func (in *OIDCConfig) DeepCopyInto(out *OIDCConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
+	if in.GroupSearchEnabled != nil {
+		in, out := &in.GroupSearchEnabled, &out.GroupSearchEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalProvider.
-func (in *LocalProvider) DeepCopy() *LocalProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfig.
+func (in *OIDCConfig) DeepCopy() *OIDCConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalProvider)
+	out := new(OIDCConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LocalProvider) DeepCopyObject() runtime.Object {
+func (in *OIDCConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4021,13 +6729,13 @@ func (in *LocalProvider) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalProviderList) DeepCopyInto(out *LocalProviderList) {
+func (in *OIDCConfigList) DeepCopyInto(out *OIDCConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]LocalProvider, len(*in))
+		*out = make([]OIDCConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -4035,18 +6743,18 @@ func (in *LocalProviderList) DeepCopyInto(out *LocalProviderList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalProviderList.
-func (in *LocalProviderList) DeepCopy() *LocalProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfigList.
+func (in *OIDCConfigList) DeepCopy() *OIDCConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalProviderList)
+	out := new(OIDCConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LocalProviderList) DeepCopyObject() runtime.Object {
+func (in *OIDCConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4054,60 +6762,43 @@ func (in *LocalProviderList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedChart) DeepCopyInto(out *ManagedChart) {
+func (in *OIDCLogin) DeepCopyInto(out *OIDCLogin) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.GenericLogin = in.GenericLogin
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedChart.
-func (in *ManagedChart) DeepCopy() *ManagedChart {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCLogin.
+func (in *OIDCLogin) DeepCopy() *OIDCLogin {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedChart)
+	out := new(OIDCLogin)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManagedChart) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedChartList) DeepCopyInto(out *ManagedChartList) {
+func (in *OIDCProvider) DeepCopyInto(out *OIDCProvider) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ManagedChart, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedChartList.
-func (in *ManagedChartList) DeepCopy() *ManagedChartList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCProvider.
+func (in *OIDCProvider) DeepCopy() *OIDCProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedChartList)
+	out := new(OIDCProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManagedChartList) DeepCopyObject() runtime.Object {
+func (in *OIDCProvider) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4115,25 +6806,13 @@ func (in *ManagedChartList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedChartSpec) DeepCopyInto(out *ManagedChartSpec) {
+func (in *OIDCProviderList) DeepCopyInto(out *OIDCProviderList) {
 	*out = *in
-	if in.Values != nil {
-		in, out := &in.Values, &out.Values
-		*out = (*in).DeepCopy()
-	}
-	if in.Diff != nil {
-		in, out := &in.Diff, &out.Diff
-		*out = new(v1alpha1.DiffOptions)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RolloutStrategy != nil {
-		in, out := &in.RolloutStrategy, &out.RolloutStrategy
-		*out = new(v1alpha1.RolloutStrategy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Targets != nil {
-		in, out := &in.Targets, &out.Targets
-		*out = make([]v1alpha1.BundleTarget, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OIDCProvider, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -4141,147 +6820,130 @@ func (in *ManagedChartSpec) DeepCopyInto(out *ManagedChartSpec) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedChartSpec.
-func (in *ManagedChartSpec) DeepCopy() *ManagedChartSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCProviderList.
+func (in *OIDCProviderList) DeepCopy() *OIDCProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedChartSpec)
+	out := new(OIDCProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OIDCProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedChartStatus) DeepCopyInto(out *ManagedChartStatus) {
+func (in *OIDCTestOutput) DeepCopyInto(out *OIDCTestOutput) {
 	*out = *in
-	in.BundleStatus.DeepCopyInto(&out.BundleStatus)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedChartStatus.
-func (in *ManagedChartStatus) DeepCopy() *ManagedChartStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCTestOutput.
+func (in *OIDCTestOutput) DeepCopy() *OIDCTestOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedChartStatus)
+	out := new(OIDCTestOutput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MapDelta) DeepCopyInto(out *MapDelta) {
+func (in *OKTAConfig) DeepCopyInto(out *OKTAConfig) {
 	*out = *in
-	if in.Add != nil {
-		in, out := &in.Add, &out.Add
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Delete != nil {
-		in, out := &in.Delete, &out.Delete
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	in.SamlConfig.DeepCopyInto(&out.SamlConfig)
+	in.OpenLdapConfig.DeepCopyInto(&out.OpenLdapConfig)
+	out.OktaAPIConfig = in.OktaAPIConfig
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapDelta.
-func (in *MapDelta) DeepCopy() *MapDelta {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OKTAConfig.
+func (in *OKTAConfig) DeepCopy() *OKTAConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MapDelta)
+	out := new(OKTAConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in MapStringInterface) DeepCopyInto(out *MapStringInterface) {
-	{
-		in := &in
-		clone := in.DeepCopy()
-		*out = *clone
-		return
-	}
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Member) DeepCopyInto(out *Member) {
+func (in *OKTAProvider) DeepCopyInto(out *OKTAProvider) {
 	*out = *in
+	in.SamlProvider.DeepCopyInto(&out.SamlProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Member.
-func (in *Member) DeepCopy() *Member {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OKTAProvider.
+func (in *OKTAProvider) DeepCopy() *OKTAProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(Member)
+	out := new(OKTAProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetadataUpdate) DeepCopyInto(out *MetadataUpdate) {
+func (in *OktaAPIFields) DeepCopyInto(out *OktaAPIFields) {
 	*out = *in
-	in.Labels.DeepCopyInto(&out.Labels)
-	in.Annotations.DeepCopyInto(&out.Annotations)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataUpdate.
-func (in *MetadataUpdate) DeepCopy() *MetadataUpdate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OktaAPIFields.
+func (in *OktaAPIFields) DeepCopy() *OktaAPIFields {
 	if in == nil {
 		return nil
 	}
-	out := new(MetadataUpdate)
+	out := new(OktaAPIFields)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceResourceQuota) DeepCopyInto(out *NamespaceResourceQuota) {
+func (in *OpenLdapConfig) DeepCopyInto(out *OpenLdapConfig) {
 	*out = *in
-	out.Limit = in.Limit
+	in.LdapConfig.DeepCopyInto(&out.LdapConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceResourceQuota.
-func (in *NamespaceResourceQuota) DeepCopy() *NamespaceResourceQuota {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLdapConfig.
+func (in *OpenLdapConfig) DeepCopy() *OpenLdapConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceResourceQuota)
+	out := new(OpenLdapConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Node) DeepCopyInto(out *Node) {
+func (in *OpenLdapProvider) DeepCopyInto(out *OpenLdapProvider) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Node.
-func (in *Node) DeepCopy() *Node {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLdapProvider.
+func (in *OpenLdapProvider) DeepCopy() *OpenLdapProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(Node)
+	out := new(OpenLdapProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Node) DeepCopyObject() runtime.Object {
+func (in *OpenLdapProvider) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4289,89 +6951,57 @@ func (in *Node) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeCommonParams) DeepCopyInto(out *NodeCommonParams) {
+func (in *OpenLdapProviderList) DeepCopyInto(out *OpenLdapProviderList) {
 	*out = *in
-	if in.EngineOpt != nil {
-		in, out := &in.EngineOpt, &out.EngineOpt
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.StorageOpt != nil {
-		in, out := &in.StorageOpt, &out.StorageOpt
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.LogOpt != nil {
-		in, out := &in.LogOpt, &out.LogOpt
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.EngineInsecureRegistry != nil {
-		in, out := &in.EngineInsecureRegistry, &out.EngineInsecureRegistry
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.EngineRegistryMirror != nil {
-		in, out := &in.EngineRegistryMirror, &out.EngineRegistryMirror
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.EngineLabel != nil {
-		in, out := &in.EngineLabel, &out.EngineLabel
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.EngineEnv != nil {
-		in, out := &in.EngineEnv, &out.EngineEnv
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenLdapProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.UseInternalIPAddress != nil {
-		in, out := &in.UseInternalIPAddress, &out.UseInternalIPAddress
-		*out = new(bool)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCommonParams.
-func (in *NodeCommonParams) DeepCopy() *NodeCommonParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLdapProviderList.
+func (in *OpenLdapProviderList) DeepCopy() *OpenLdapProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeCommonParams)
+	out := new(OpenLdapProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenLdapProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeCondition) DeepCopyInto(out *NodeCondition) {
+func (in *OpenLdapTestAndApplyInput) DeepCopyInto(out *OpenLdapTestAndApplyInput) {
 	*out = *in
+	in.LdapTestAndApplyInput.DeepCopyInto(&out.LdapTestAndApplyInput)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCondition.
-func (in *NodeCondition) DeepCopy() *NodeCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLdapTestAndApplyInput.
+func (in *OpenLdapTestAndApplyInput) DeepCopy() *OpenLdapTestAndApplyInput {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeCondition)
+	out := new(OpenLdapTestAndApplyInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeDriver) DeepCopyInto(out *NodeDriver) {
+func (in *Organization) DeepCopyInto(out *Organization) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -4380,18 +7010,18 @@ func (in *NodeDriver) DeepCopyInto(out *NodeDriver) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriver.
-func (in *NodeDriver) DeepCopy() *NodeDriver {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Organization.
+func (in *Organization) DeepCopy() *Organization {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeDriver)
+	out := new(Organization)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeDriver) DeepCopyObject() runtime.Object {
+func (in *Organization) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4399,13 +7029,29 @@ func (in *NodeDriver) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeDriverList) DeepCopyInto(out *NodeDriverList) {
+func (in *OrganizationAdmin) DeepCopyInto(out *OrganizationAdmin) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationAdmin.
+func (in *OrganizationAdmin) DeepCopy() *OrganizationAdmin {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationAdmin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationList) DeepCopyInto(out *OrganizationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NodeDriver, len(*in))
+		*out = make([]Organization, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -4413,18 +7059,18 @@ func (in *NodeDriverList) DeepCopyInto(out *NodeDriverList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriverList.
-func (in *NodeDriverList) DeepCopy() *NodeDriverList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationList.
+func (in *OrganizationList) DeepCopy() *OrganizationList {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeDriverList)
+	out := new(OrganizationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeDriverList) DeepCopyObject() runtime.Object {
+func (in *OrganizationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4432,105 +7078,76 @@ func (in *NodeDriverList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeDriverSpec) DeepCopyInto(out *NodeDriverSpec) {
+func (in *OrganizationSpec) DeepCopyInto(out *OrganizationSpec) {
 	*out = *in
-	if in.WhitelistDomains != nil {
-		in, out := &in.WhitelistDomains, &out.WhitelistDomains
+	if in.ClusterNames != nil {
+		in, out := &in.ClusterNames, &out.ClusterNames
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriverSpec.
-func (in *NodeDriverSpec) DeepCopy() *NodeDriverSpec {
-	if in == nil {
-		return nil
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(NodeDriverSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeDriverStatus) DeepCopyInto(out *NodeDriverStatus) {
-	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+	if in.Admins != nil {
+		in, out := &in.Admins, &out.Admins
+		*out = make([]OrganizationAdmin, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriverStatus.
-func (in *NodeDriverStatus) DeepCopy() *NodeDriverStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationSpec.
+func (in *OrganizationSpec) DeepCopy() *OrganizationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeDriverStatus)
+	out := new(OrganizationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeList) DeepCopyInto(out *NodeList) {
+func (in *OrganizationStatus) DeepCopyInto(out *OrganizationStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Node, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeList.
-func (in *NodeList) DeepCopy() *NodeList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationStatus.
+func (in *OrganizationStatus) DeepCopy() *OrganizationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeList)
+	out := new(OrganizationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePlan) DeepCopyInto(out *NodePlan) {
+func (in *OrphanedBinding) DeepCopyInto(out *OrphanedBinding) {
 	*out = *in
-	if in.Plan != nil {
-		in, out := &in.Plan, &out.Plan
-		*out = new(types.RKEConfigNodePlan)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePlan.
-func (in *NodePlan) DeepCopy() *NodePlan {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedBinding.
+func (in *OrphanedBinding) DeepCopy() *OrphanedBinding {
 	if in == nil {
 		return nil
 	}
-	out := new(NodePlan)
+	out := new(OrphanedBinding)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePool) DeepCopyInto(out *NodePool) {
+func (in *OrphanedBindingReport) DeepCopyInto(out *OrphanedBindingReport) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
@@ -4538,18 +7155,18 @@ func (in *NodePool) DeepCopyInto(out *NodePool) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
-func (in *NodePool) DeepCopy() *NodePool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedBindingReport.
+func (in *OrphanedBindingReport) DeepCopy() *OrphanedBindingReport {
 	if in == nil {
 		return nil
 	}
-	out := new(NodePool)
+	out := new(OrphanedBindingReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodePool) DeepCopyObject() runtime.Object {
+func (in *OrphanedBindingReport) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4557,13 +7174,13 @@ func (in *NodePool) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePoolList) DeepCopyInto(out *NodePoolList) {
+func (in *OrphanedBindingReportList) DeepCopyInto(out *OrphanedBindingReportList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NodePool, len(*in))
+		*out = make([]OrphanedBindingReport, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -4571,18 +7188,18 @@ func (in *NodePoolList) DeepCopyInto(out *NodePoolList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolList.
-func (in *NodePoolList) DeepCopy() *NodePoolList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedBindingReportList.
+func (in *OrphanedBindingReportList) DeepCopy() *OrphanedBindingReportList {
 	if in == nil {
 		return nil
 	}
-	out := new(NodePoolList)
+	out := new(OrphanedBindingReportList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodePoolList) DeepCopyObject() runtime.Object {
+func (in *OrphanedBindingReportList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4590,203 +7207,122 @@ func (in *NodePoolList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
-	*out = *in
-	if in.NodeLabels != nil {
-		in, out := &in.NodeLabels, &out.NodeLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.NodeAnnotations != nil {
-		in, out := &in.NodeAnnotations, &out.NodeAnnotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.NodeTaints != nil {
-		in, out := &in.NodeTaints, &out.NodeTaints
-		*out = make([]corev1.Taint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpec.
-func (in *NodePoolSpec) DeepCopy() *NodePoolSpec {
-	if in == nil {
-		return nil
-	}
-	out := new(NodePoolSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
+func (in *OrphanedBindingReportSpec) DeepCopyInto(out *OrphanedBindingReportSpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+	if in.CleanupBindings != nil {
+		in, out := &in.CleanupBindings, &out.CleanupBindings
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
-func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedBindingReportSpec.
+func (in *OrphanedBindingReportSpec) DeepCopy() *OrphanedBindingReportSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodePoolStatus)
+	out := new(OrphanedBindingReportSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeSpec) DeepCopyInto(out *NodeSpec) {
+func (in *OrphanedBindingReportStatus) DeepCopyInto(out *OrphanedBindingReportStatus) {
 	*out = *in
-	if in.CustomConfig != nil {
-		in, out := &in.CustomConfig, &out.CustomConfig
-		*out = new(CustomConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	in.InternalNodeSpec.DeepCopyInto(&out.InternalNodeSpec)
-	if in.DesiredNodeTaints != nil {
-		in, out := &in.DesiredNodeTaints, &out.DesiredNodeTaints
-		*out = make([]corev1.Taint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.UpdateTaintsFromAPI != nil {
-		in, out := &in.UpdateTaintsFromAPI, &out.UpdateTaintsFromAPI
-		*out = new(bool)
-		**out = **in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
 	}
-	if in.NodeDrainInput != nil {
-		in, out := &in.NodeDrainInput, &out.NodeDrainInput
-		*out = new(types.NodeDrainInput)
-		(*in).DeepCopyInto(*out)
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]OrphanedBinding, len(*in))
+		copy(*out, *in)
 	}
-	in.MetadataUpdate.DeepCopyInto(&out.MetadataUpdate)
 	return
 }
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSpec.
-func (in *NodeSpec) DeepCopy() *NodeSpec {
-	if in == nil {
-		return nil
-	}
-	out := new(NodeSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
-	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]NodeCondition, len(*in))
-		copy(*out, *in)
-	}
-	in.InternalNodeStatus.DeepCopyInto(&out.InternalNodeStatus)
-	if in.Requested != nil {
-		in, out := &in.Requested, &out.Requested
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
-	if in.Limits != nil {
-		in, out := &in.Limits, &out.Limits
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
-	if in.NodeTemplateSpec != nil {
-		in, out := &in.NodeTemplateSpec, &out.NodeTemplateSpec
-		*out = new(NodeTemplateSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NodeConfig != nil {
-		in, out := &in.NodeConfig, &out.NodeConfig
-		*out = new(types.RKEConfigNode)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NodeAnnotations != nil {
-		in, out := &in.NodeAnnotations, &out.NodeAnnotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.NodeLabels != nil {
-		in, out := &in.NodeLabels, &out.NodeLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.NodeTaints != nil {
-		in, out := &in.NodeTaints, &out.NodeTaints
-		*out = make([]corev1.Taint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedBindingReportStatus.
+func (in *OrphanedBindingReportStatus) DeepCopy() *OrphanedBindingReportStatus {
+	if in == nil {
+		return nil
 	}
-	if in.DockerInfo != nil {
-		in, out := &in.DockerInfo, &out.DockerInfo
-		*out = new(DockerInfo)
-		(*in).DeepCopyInto(*out)
+	out := new(OrphanedBindingReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PingConfig) DeepCopyInto(out *PingConfig) {
+	*out = *in
+	in.SamlConfig.DeepCopyInto(&out.SamlConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PingConfig.
+func (in *PingConfig) DeepCopy() *PingConfig {
+	if in == nil {
+		return nil
 	}
-	if in.NodePlan != nil {
-		in, out := &in.NodePlan, &out.NodePlan
-		*out = new(NodePlan)
-		(*in).DeepCopyInto(*out)
+	out := new(PingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PingProvider) DeepCopyInto(out *PingProvider) {
+	*out = *in
+	in.SamlProvider.DeepCopyInto(&out.SamlProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PingProvider.
+func (in *PingProvider) DeepCopy() *PingProvider {
+	if in == nil {
+		return nil
 	}
+	out := new(PingProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
-func (in *NodeStatus) DeepCopy() *NodeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
+func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeStatus)
+	out := new(PodDisruptionBudgetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeTemplate) DeepCopyInto(out *NodeTemplate) {
+func (in *PodSecurityAdmissionConfigurationTemplate) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplate) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.Configuration.DeepCopyInto(&out.Configuration)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplate.
-func (in *NodeTemplate) DeepCopy() *NodeTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplate.
+func (in *PodSecurityAdmissionConfigurationTemplate) DeepCopy() *PodSecurityAdmissionConfigurationTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeTemplate)
+	out := new(PodSecurityAdmissionConfigurationTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeTemplate) DeepCopyObject() runtime.Object {
+func (in *PodSecurityAdmissionConfigurationTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4794,29 +7330,60 @@ func (in *NodeTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeTemplateCondition) DeepCopyInto(out *NodeTemplateCondition) {
+func (in *PodSecurityAdmissionConfigurationTemplateDefaults) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplateDefaults) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplateCondition.
-func (in *NodeTemplateCondition) DeepCopy() *NodeTemplateCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplateDefaults.
+func (in *PodSecurityAdmissionConfigurationTemplateDefaults) DeepCopy() *PodSecurityAdmissionConfigurationTemplateDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeTemplateCondition)
+	out := new(PodSecurityAdmissionConfigurationTemplateDefaults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeTemplateList) DeepCopyInto(out *NodeTemplateList) {
+func (in *PodSecurityAdmissionConfigurationTemplateExemptions) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplateExemptions) {
+	*out = *in
+	if in.Usernames != nil {
+		in, out := &in.Usernames, &out.Usernames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuntimeClasses != nil {
+		in, out := &in.RuntimeClasses, &out.RuntimeClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplateExemptions.
+func (in *PodSecurityAdmissionConfigurationTemplateExemptions) DeepCopy() *PodSecurityAdmissionConfigurationTemplateExemptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityAdmissionConfigurationTemplateExemptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurityAdmissionConfigurationTemplateList) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NodeTemplate, len(*in))
+		*out = make([]PodSecurityAdmissionConfigurationTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -4824,18 +7391,18 @@ func (in *NodeTemplateList) DeepCopyInto(out *NodeTemplateList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplateList.
-func (in *NodeTemplateList) DeepCopy() *NodeTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplateList.
+func (in *PodSecurityAdmissionConfigurationTemplateList) DeepCopy() *PodSecurityAdmissionConfigurationTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeTemplateList)
+	out := new(PodSecurityAdmissionConfigurationTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeTemplateList) DeepCopyObject() runtime.Object {
+func (in *PodSecurityAdmissionConfigurationTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4843,129 +7410,173 @@ func (in *NodeTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeTemplateSpec) DeepCopyInto(out *NodeTemplateSpec) {
+func (in *PodSecurityAdmissionConfigurationTemplateSpec) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplateSpec) {
 	*out = *in
-	if in.NodeTaints != nil {
-		in, out := &in.NodeTaints, &out.NodeTaints
-		*out = make([]corev1.Taint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.NodeCommonParams.DeepCopyInto(&out.NodeCommonParams)
+	out.Defaults = in.Defaults
+	in.Exemptions.DeepCopyInto(&out.Exemptions)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplateSpec.
-func (in *NodeTemplateSpec) DeepCopy() *NodeTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplateSpec.
+func (in *PodSecurityAdmissionConfigurationTemplateSpec) DeepCopy() *PodSecurityAdmissionConfigurationTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeTemplateSpec)
+	out := new(PodSecurityAdmissionConfigurationTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeTemplateStatus) DeepCopyInto(out *NodeTemplateStatus) {
+func (in *Preference) DeepCopyInto(out *Preference) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]NodeTemplateCondition, len(*in))
-		copy(*out, *in)
-	}
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTemplateStatus.
-func (in *NodeTemplateStatus) DeepCopy() *NodeTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Preference.
+func (in *Preference) DeepCopy() *Preference {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeTemplateStatus)
+	out := new(Preference)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Preference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OAuthEndpoint) DeepCopyInto(out *OAuthEndpoint) {
+func (in *PreferenceList) DeepCopyInto(out *PreferenceList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Preference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuthEndpoint.
-func (in *OAuthEndpoint) DeepCopy() *OAuthEndpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreferenceList.
+func (in *PreferenceList) DeepCopy() *PreferenceList {
 	if in == nil {
 		return nil
 	}
-	out := new(OAuthEndpoint)
+	out := new(PreferenceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PreferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OAuthProvider) DeepCopyInto(out *OAuthProvider) {
+func (in *Principal) DeepCopyInto(out *Principal) {
 	*out = *in
-	if in.Scopes != nil {
-		in, out := &in.Scopes, &out.Scopes
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.ExtraInfo != nil {
+		in, out := &in.ExtraInfo, &out.ExtraInfo
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	out.OAuthEndpoint = in.OAuthEndpoint
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuthProvider.
-func (in *OAuthProvider) DeepCopy() *OAuthProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Principal.
+func (in *Principal) DeepCopy() *Principal {
 	if in == nil {
 		return nil
 	}
-	out := new(OAuthProvider)
+	out := new(Principal)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Principal) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OIDCApplyInput) DeepCopyInto(out *OIDCApplyInput) {
+func (in *PrincipalList) DeepCopyInto(out *PrincipalList) {
 	*out = *in
-	in.OIDCConfig.DeepCopyInto(&out.OIDCConfig)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Principal, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCApplyInput.
-func (in *OIDCApplyInput) DeepCopy() *OIDCApplyInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrincipalList.
+func (in *PrincipalList) DeepCopy() *PrincipalList {
 	if in == nil {
 		return nil
 	}
-	out := new(OIDCApplyInput)
+	out := new(PrincipalList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PrincipalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OIDCConfig) DeepCopyInto(out *OIDCConfig) {
+func (in *PrincipalMetadata) DeepCopyInto(out *PrincipalMetadata) {
 	*out = *in
-	in.AuthConfig.DeepCopyInto(&out.AuthConfig)
-	if in.GroupSearchEnabled != nil {
-		in, out := &in.GroupSearchEnabled, &out.GroupSearchEnabled
-		*out = new(bool)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.LastSync != nil {
+		in, out := &in.LastSync, &out.LastSync
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfig.
-func (in *OIDCConfig) DeepCopy() *OIDCConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrincipalMetadata.
+func (in *PrincipalMetadata) DeepCopy() *PrincipalMetadata {
 	if in == nil {
 		return nil
 	}
-	out := new(OIDCConfig)
+	out := new(PrincipalMetadata)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OIDCConfig) DeepCopyObject() runtime.Object {
+func (in *PrincipalMetadata) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4973,13 +7584,13 @@ func (in *OIDCConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OIDCConfigList) DeepCopyInto(out *OIDCConfigList) {
+func (in *PrincipalMetadataList) DeepCopyInto(out *PrincipalMetadataList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OIDCConfig, len(*in))
+		*out = make([]PrincipalMetadata, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -4987,18 +7598,18 @@ func (in *OIDCConfigList) DeepCopyInto(out *OIDCConfigList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfigList.
-func (in *OIDCConfigList) DeepCopy() *OIDCConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrincipalMetadataList.
+func (in *PrincipalMetadataList) DeepCopy() *PrincipalMetadataList {
 	if in == nil {
 		return nil
 	}
-	out := new(OIDCConfigList)
+	out := new(PrincipalMetadataList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OIDCConfigList) DeepCopyObject() runtime.Object {
+func (in *PrincipalMetadataList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5006,57 +7617,37 @@ func (in *OIDCConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OIDCLogin) DeepCopyInto(out *OIDCLogin) {
+func (in *PrincipalSearchScope) DeepCopyInto(out *PrincipalSearchScope) {
 	*out = *in
-	out.GenericLogin = in.GenericLogin
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCLogin.
-func (in *OIDCLogin) DeepCopy() *OIDCLogin {
-	if in == nil {
-		return nil
+	if in.GroupPrincipals != nil {
+		in, out := &in.GroupPrincipals, &out.GroupPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchBases != nil {
+		in, out := &in.SearchBases, &out.SearchBases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	out := new(OIDCLogin)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OIDCProvider) DeepCopyInto(out *OIDCProvider) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCProvider.
-func (in *OIDCProvider) DeepCopy() *OIDCProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrincipalSearchScope.
+func (in *PrincipalSearchScope) DeepCopy() *PrincipalSearchScope {
 	if in == nil {
 		return nil
 	}
-	out := new(OIDCProvider)
+	out := new(PrincipalSearchScope)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OIDCProvider) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OIDCProviderList) DeepCopyInto(out *OIDCProviderList) {
+func (in *Principals) DeepCopyInto(out *Principals) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OIDCProvider, len(*in))
+		*out = make([]Principal, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -5064,113 +7655,142 @@ func (in *OIDCProviderList) DeepCopyInto(out *OIDCProviderList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCProviderList.
-func (in *OIDCProviderList) DeepCopy() *OIDCProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Principals.
+func (in *Principals) DeepCopy() *Principals {
 	if in == nil {
 		return nil
 	}
-	out := new(OIDCProviderList)
+	out := new(Principals)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OIDCProviderList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OIDCTestOutput) DeepCopyInto(out *OIDCTestOutput) {
+func (in *PriorityClassSpec) DeepCopyInto(out *PriorityClassSpec) {
 	*out = *in
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(corev1.PreemptionPolicy)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCTestOutput.
-func (in *OIDCTestOutput) DeepCopy() *OIDCTestOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityClassSpec.
+func (in *PriorityClassSpec) DeepCopy() *PriorityClassSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OIDCTestOutput)
+	out := new(PriorityClassSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OKTAConfig) DeepCopyInto(out *OKTAConfig) {
+func (in *Project) DeepCopyInto(out *Project) {
 	*out = *in
-	in.SamlConfig.DeepCopyInto(&out.SamlConfig)
-	in.OpenLdapConfig.DeepCopyInto(&out.OpenLdapConfig)
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OKTAConfig.
-func (in *OKTAConfig) DeepCopy() *OKTAConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
+func (in *Project) DeepCopy() *Project {
 	if in == nil {
 		return nil
 	}
-	out := new(OKTAConfig)
+	out := new(Project)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Project) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OKTAProvider) DeepCopyInto(out *OKTAProvider) {
+func (in *ProjectCondition) DeepCopyInto(out *ProjectCondition) {
 	*out = *in
-	in.SamlProvider.DeepCopyInto(&out.SamlProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OKTAProvider.
-func (in *OKTAProvider) DeepCopy() *OKTAProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectCondition.
+func (in *ProjectCondition) DeepCopy() *ProjectCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(OKTAProvider)
+	out := new(ProjectCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenLdapConfig) DeepCopyInto(out *OpenLdapConfig) {
+func (in *ProjectList) DeepCopyInto(out *ProjectList) {
 	*out = *in
-	in.LdapConfig.DeepCopyInto(&out.LdapConfig)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Project, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLdapConfig.
-func (in *OpenLdapConfig) DeepCopy() *OpenLdapConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
+func (in *ProjectList) DeepCopy() *ProjectList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenLdapConfig)
+	out := new(ProjectList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenLdapProvider) DeepCopyInto(out *OpenLdapProvider) {
+func (in *ProjectNetworkPolicy) DeepCopyInto(out *ProjectNetworkPolicy) {
 	*out = *in
+	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	out.Spec = in.Spec
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ProjectNetworkPolicyStatus)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLdapProvider.
-func (in *OpenLdapProvider) DeepCopy() *OpenLdapProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicy.
+func (in *ProjectNetworkPolicy) DeepCopy() *ProjectNetworkPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenLdapProvider)
+	out := new(ProjectNetworkPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenLdapProvider) DeepCopyObject() runtime.Object {
+func (in *ProjectNetworkPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5178,13 +7798,13 @@ func (in *OpenLdapProvider) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenLdapProviderList) DeepCopyInto(out *OpenLdapProviderList) {
+func (in *ProjectNetworkPolicyList) DeepCopyInto(out *ProjectNetworkPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OpenLdapProvider, len(*in))
+		*out = make([]ProjectNetworkPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -5192,18 +7812,18 @@ func (in *OpenLdapProviderList) DeepCopyInto(out *OpenLdapProviderList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLdapProviderList.
-func (in *OpenLdapProviderList) DeepCopy() *OpenLdapProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicyList.
+func (in *ProjectNetworkPolicyList) DeepCopy() *ProjectNetworkPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenLdapProviderList)
+	out := new(ProjectNetworkPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OpenLdapProviderList) DeepCopyObject() runtime.Object {
+func (in *ProjectNetworkPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5211,93 +7831,119 @@ func (in *OpenLdapProviderList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenLdapTestAndApplyInput) DeepCopyInto(out *OpenLdapTestAndApplyInput) {
+func (in *ProjectNetworkPolicySpec) DeepCopyInto(out *ProjectNetworkPolicySpec) {
 	*out = *in
-	in.LdapTestAndApplyInput.DeepCopyInto(&out.LdapTestAndApplyInput)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLdapTestAndApplyInput.
-func (in *OpenLdapTestAndApplyInput) DeepCopy() *OpenLdapTestAndApplyInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicySpec.
+func (in *ProjectNetworkPolicySpec) DeepCopy() *ProjectNetworkPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenLdapTestAndApplyInput)
+	out := new(ProjectNetworkPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PingConfig) DeepCopyInto(out *PingConfig) {
+func (in *ProjectNetworkPolicyStatus) DeepCopyInto(out *ProjectNetworkPolicyStatus) {
 	*out = *in
-	in.SamlConfig.DeepCopyInto(&out.SamlConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PingConfig.
-func (in *PingConfig) DeepCopy() *PingConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicyStatus.
+func (in *ProjectNetworkPolicyStatus) DeepCopy() *ProjectNetworkPolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PingConfig)
+	out := new(ProjectNetworkPolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PingProvider) DeepCopyInto(out *PingProvider) {
+func (in *ProjectResourceQuota) DeepCopyInto(out *ProjectResourceQuota) {
 	*out = *in
-	in.SamlProvider.DeepCopyInto(&out.SamlProvider)
+	out.Limit = in.Limit
+	out.UsedLimit = in.UsedLimit
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PingProvider.
-func (in *PingProvider) DeepCopy() *PingProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectResourceQuota.
+func (in *ProjectResourceQuota) DeepCopy() *ProjectResourceQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(PingProvider)
+	out := new(ProjectResourceQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
+func (in *ProjectRoleTemplateBinding) DeepCopyInto(out *ProjectRoleTemplateBinding) {
 	*out = *in
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
-func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRoleTemplateBinding.
+func (in *ProjectRoleTemplateBinding) DeepCopy() *ProjectRoleTemplateBinding {
 	if in == nil {
 		return nil
 	}
-	out := new(PodDisruptionBudgetSpec)
+	out := new(ProjectRoleTemplateBinding)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectRoleTemplateBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodSecurityAdmissionConfigurationTemplate) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplate) {
+func (in *ProjectRoleTemplateBindingList) DeepCopyInto(out *ProjectRoleTemplateBindingList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Configuration.DeepCopyInto(&out.Configuration)
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectRoleTemplateBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplate.
-func (in *PodSecurityAdmissionConfigurationTemplate) DeepCopy() *PodSecurityAdmissionConfigurationTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRoleTemplateBindingList.
+func (in *ProjectRoleTemplateBindingList) DeepCopy() *ProjectRoleTemplateBindingList {
 	if in == nil {
 		return nil
 	}
-	out := new(PodSecurityAdmissionConfigurationTemplate)
+	out := new(ProjectRoleTemplateBindingList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PodSecurityAdmissionConfigurationTemplate) DeepCopyObject() runtime.Object {
+func (in *ProjectRoleTemplateBindingList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5305,190 +7951,184 @@ func (in *PodSecurityAdmissionConfigurationTemplate) DeepCopyObject() runtime.Ob
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodSecurityAdmissionConfigurationTemplateDefaults) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplateDefaults) {
+func (in *ProjectRoleTemplateBindingStatus) DeepCopyInto(out *ProjectRoleTemplateBindingStatus) {
 	*out = *in
+	if in.RemoteConditions != nil {
+		in, out := &in.RemoteConditions, &out.RemoteConditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplateDefaults.
-func (in *PodSecurityAdmissionConfigurationTemplateDefaults) DeepCopy() *PodSecurityAdmissionConfigurationTemplateDefaults {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRoleTemplateBindingStatus.
+func (in *ProjectRoleTemplateBindingStatus) DeepCopy() *ProjectRoleTemplateBindingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PodSecurityAdmissionConfigurationTemplateDefaults)
+	out := new(ProjectRoleTemplateBindingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodSecurityAdmissionConfigurationTemplateExemptions) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplateExemptions) {
+func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	*out = *in
-	if in.Usernames != nil {
-		in, out := &in.Usernames, &out.Usernames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = new(ProjectResourceQuota)
+		**out = **in
 	}
-	if in.RuntimeClasses != nil {
-		in, out := &in.RuntimeClasses, &out.RuntimeClasses
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.NamespaceDefaultResourceQuota != nil {
+		in, out := &in.NamespaceDefaultResourceQuota, &out.NamespaceDefaultResourceQuota
+		*out = new(NamespaceResourceQuota)
+		**out = **in
 	}
-	if in.Namespaces != nil {
-		in, out := &in.Namespaces, &out.Namespaces
-		*out = make([]string, len(*in))
+	if in.ContainerDefaultResourceLimit != nil {
+		in, out := &in.ContainerDefaultResourceLimit, &out.ContainerDefaultResourceLimit
+		*out = new(ContainerResourceLimit)
+		**out = **in
+	}
+	if in.DirectoryAttributeMemberships != nil {
+		in, out := &in.DirectoryAttributeMemberships, &out.DirectoryAttributeMemberships
+		*out = make([]DirectoryAttributeMembership, len(*in))
 		copy(*out, *in)
 	}
+	if in.MemberSearchScope != nil {
+		in, out := &in.MemberSearchScope, &out.MemberSearchScope
+		*out = new(PrincipalSearchScope)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplateExemptions.
-func (in *PodSecurityAdmissionConfigurationTemplateExemptions) DeepCopy() *PodSecurityAdmissionConfigurationTemplateExemptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
+func (in *ProjectSpec) DeepCopy() *ProjectSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PodSecurityAdmissionConfigurationTemplateExemptions)
+	out := new(ProjectSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodSecurityAdmissionConfigurationTemplateList) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplateList) {
+func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]PodSecurityAdmissionConfigurationTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ProjectCondition, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplateList.
-func (in *PodSecurityAdmissionConfigurationTemplateList) DeepCopy() *PodSecurityAdmissionConfigurationTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
+func (in *ProjectStatus) DeepCopy() *ProjectStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PodSecurityAdmissionConfigurationTemplateList)
+	out := new(ProjectStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PodSecurityAdmissionConfigurationTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodSecurityAdmissionConfigurationTemplateSpec) DeepCopyInto(out *PodSecurityAdmissionConfigurationTemplateSpec) {
+func (in *PublicEndpoint) DeepCopyInto(out *PublicEndpoint) {
 	*out = *in
-	out.Defaults = in.Defaults
-	in.Exemptions.DeepCopyInto(&out.Exemptions)
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfigurationTemplateSpec.
-func (in *PodSecurityAdmissionConfigurationTemplateSpec) DeepCopy() *PodSecurityAdmissionConfigurationTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicEndpoint.
+func (in *PublicEndpoint) DeepCopy() *PublicEndpoint {
 	if in == nil {
 		return nil
 	}
-	out := new(PodSecurityAdmissionConfigurationTemplateSpec)
+	out := new(PublicEndpoint)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Preference) DeepCopyInto(out *Preference) {
+func (in *Question) DeepCopyInto(out *Question) {
 	*out = *in
-	out.Namespaced = in.Namespaced
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subquestions != nil {
+		in, out := &in.Subquestions, &out.Subquestions
+		*out = make([]SubQuestion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Preference.
-func (in *Preference) DeepCopy() *Preference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Question.
+func (in *Question) DeepCopy() *Question {
 	if in == nil {
 		return nil
 	}
-	out := new(Preference)
+	out := new(Question)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Preference) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PreferenceList) DeepCopyInto(out *PreferenceList) {
+func (in *RBACDrift) DeepCopyInto(out *RBACDrift) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Preference, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreferenceList.
-func (in *PreferenceList) DeepCopy() *PreferenceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDrift.
+func (in *RBACDrift) DeepCopy() *RBACDrift {
 	if in == nil {
 		return nil
 	}
-	out := new(PreferenceList)
+	out := new(RBACDrift)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PreferenceList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Principal) DeepCopyInto(out *Principal) {
+func (in *RBACDriftReport) DeepCopyInto(out *RBACDriftReport) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.ExtraInfo != nil {
-		in, out := &in.ExtraInfo, &out.ExtraInfo
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Principal.
-func (in *Principal) DeepCopy() *Principal {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDriftReport.
+func (in *RBACDriftReport) DeepCopy() *RBACDriftReport {
 	if in == nil {
 		return nil
 	}
-	out := new(Principal)
+	out := new(RBACDriftReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Principal) DeepCopyObject() runtime.Object {
+func (in *RBACDriftReport) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5496,13 +8136,13 @@ func (in *Principal) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PrincipalList) DeepCopyInto(out *PrincipalList) {
+func (in *RBACDriftReportList) DeepCopyInto(out *RBACDriftReportList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Principal, len(*in))
+		*out = make([]RBACDriftReport, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -5510,18 +8150,18 @@ func (in *PrincipalList) DeepCopyInto(out *PrincipalList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrincipalList.
-func (in *PrincipalList) DeepCopy() *PrincipalList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDriftReportList.
+func (in *RBACDriftReportList) DeepCopy() *RBACDriftReportList {
 	if in == nil {
 		return nil
 	}
-	out := new(PrincipalList)
+	out := new(RBACDriftReportList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PrincipalList) DeepCopyObject() runtime.Object {
+func (in *RBACDriftReportList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5529,72 +8169,66 @@ func (in *PrincipalList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Principals) DeepCopyInto(out *Principals) {
+func (in *RBACDriftReportSpec) DeepCopyInto(out *RBACDriftReportSpec) {
 	*out = *in
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Principal, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Principals.
-func (in *Principals) DeepCopy() *Principals {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDriftReportSpec.
+func (in *RBACDriftReportSpec) DeepCopy() *RBACDriftReportSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Principals)
+	out := new(RBACDriftReportSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PriorityClassSpec) DeepCopyInto(out *PriorityClassSpec) {
+func (in *RBACDriftReportStatus) DeepCopyInto(out *RBACDriftReportStatus) {
 	*out = *in
-	if in.PreemptionPolicy != nil {
-		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
-		*out = new(corev1.PreemptionPolicy)
-		**out = **in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]RBACDrift, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityClassSpec.
-func (in *PriorityClassSpec) DeepCopy() *PriorityClassSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDriftReportStatus.
+func (in *RBACDriftReportStatus) DeepCopy() *RBACDriftReportStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PriorityClassSpec)
+	out := new(RBACDriftReportStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Project) DeepCopyInto(out *Project) {
+func (in *RancherUserNotification) DeepCopyInto(out *RancherUserNotification) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
-func (in *Project) DeepCopy() *Project {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RancherUserNotification.
+func (in *RancherUserNotification) DeepCopy() *RancherUserNotification {
 	if in == nil {
 		return nil
 	}
-	out := new(Project)
+	out := new(RancherUserNotification)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Project) DeepCopyObject() runtime.Object {
+func (in *RancherUserNotification) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5602,29 +8236,13 @@ func (in *Project) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectCondition) DeepCopyInto(out *ProjectCondition) {
-	*out = *in
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectCondition.
-func (in *ProjectCondition) DeepCopy() *ProjectCondition {
-	if in == nil {
-		return nil
-	}
-	out := new(ProjectCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+func (in *RancherUserNotificationList) DeepCopyInto(out *RancherUserNotificationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Project, len(*in))
+		*out = make([]RancherUserNotification, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -5632,18 +8250,18 @@ func (in *ProjectList) DeepCopyInto(out *ProjectList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
-func (in *ProjectList) DeepCopy() *ProjectList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RancherUserNotificationList.
+func (in *RancherUserNotificationList) DeepCopy() *RancherUserNotificationList {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectList)
+	out := new(RancherUserNotificationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectList) DeepCopyObject() runtime.Object {
+func (in *RancherUserNotificationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5651,32 +8269,27 @@ func (in *ProjectList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectNetworkPolicy) DeepCopyInto(out *ProjectNetworkPolicy) {
+func (in *RecertificationCampaign) DeepCopyInto(out *RecertificationCampaign) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(ProjectNetworkPolicyStatus)
-		**out = **in
-	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicy.
-func (in *ProjectNetworkPolicy) DeepCopy() *ProjectNetworkPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecertificationCampaign.
+func (in *RecertificationCampaign) DeepCopy() *RecertificationCampaign {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectNetworkPolicy)
+	out := new(RecertificationCampaign)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectNetworkPolicy) DeepCopyObject() runtime.Object {
+func (in *RecertificationCampaign) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5684,13 +8297,13 @@ func (in *ProjectNetworkPolicy) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectNetworkPolicyList) DeepCopyInto(out *ProjectNetworkPolicyList) {
+func (in *RecertificationCampaignList) DeepCopyInto(out *RecertificationCampaignList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ProjectNetworkPolicy, len(*in))
+		*out = make([]RecertificationCampaign, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -5698,18 +8311,18 @@ func (in *ProjectNetworkPolicyList) DeepCopyInto(out *ProjectNetworkPolicyList)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicyList.
-func (in *ProjectNetworkPolicyList) DeepCopy() *ProjectNetworkPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecertificationCampaignList.
+func (in *RecertificationCampaignList) DeepCopy() *RecertificationCampaignList {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectNetworkPolicyList)
+	out := new(RecertificationCampaignList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectNetworkPolicyList) DeepCopyObject() runtime.Object {
+func (in *RecertificationCampaignList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5717,199 +8330,197 @@ func (in *ProjectNetworkPolicyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectNetworkPolicySpec) DeepCopyInto(out *ProjectNetworkPolicySpec) {
+func (in *RecertificationCampaignSpec) DeepCopyInto(out *RecertificationCampaignSpec) {
+	*out = *in
+	if in.Reviewers != nil {
+		in, out := &in.Reviewers, &out.Reviewers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Deadline.DeepCopyInto(&out.Deadline)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecertificationCampaignSpec.
+func (in *RecertificationCampaignSpec) DeepCopy() *RecertificationCampaignSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RecertificationCampaignSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecertificationCampaignStatus) DeepCopyInto(out *RecertificationCampaignStatus) {
 	*out = *in
+	if in.ClosedAt != nil {
+		in, out := &in.ClosedAt, &out.ClosedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RecertificationItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicySpec.
-func (in *ProjectNetworkPolicySpec) DeepCopy() *ProjectNetworkPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecertificationCampaignStatus.
+func (in *RecertificationCampaignStatus) DeepCopy() *RecertificationCampaignStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectNetworkPolicySpec)
+	out := new(RecertificationCampaignStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectNetworkPolicyStatus) DeepCopyInto(out *ProjectNetworkPolicyStatus) {
+func (in *RecertificationItem) DeepCopyInto(out *RecertificationItem) {
 	*out = *in
+	if in.DecidedAt != nil {
+		in, out := &in.DecidedAt, &out.DecidedAt
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicyStatus.
-func (in *ProjectNetworkPolicyStatus) DeepCopy() *ProjectNetworkPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecertificationItem.
+func (in *RecertificationItem) DeepCopy() *RecertificationItem {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectNetworkPolicyStatus)
+	out := new(RecertificationItem)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectResourceQuota) DeepCopyInto(out *ProjectResourceQuota) {
+func (in *RenderedBinding) DeepCopyInto(out *RenderedBinding) {
 	*out = *in
-	out.Limit = in.Limit
-	out.UsedLimit = in.UsedLimit
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectResourceQuota.
-func (in *ProjectResourceQuota) DeepCopy() *ProjectResourceQuota {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenderedBinding.
+func (in *RenderedBinding) DeepCopy() *RenderedBinding {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectResourceQuota)
+	out := new(RenderedBinding)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectRoleTemplateBinding) DeepCopyInto(out *ProjectRoleTemplateBinding) {
+func (in *RenderedRole) DeepCopyInto(out *RenderedRole) {
 	*out = *in
-	out.Namespaced = in.Namespaced
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRoleTemplateBinding.
-func (in *ProjectRoleTemplateBinding) DeepCopy() *ProjectRoleTemplateBinding {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenderedRole.
+func (in *RenderedRole) DeepCopy() *RenderedRole {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectRoleTemplateBinding)
+	out := new(RenderedRole)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectRoleTemplateBinding) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectRoleTemplateBindingList) DeepCopyInto(out *ProjectRoleTemplateBindingList) {
+func (in *ResourceQuotaLimit) DeepCopyInto(out *ResourceQuotaLimit) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ProjectRoleTemplateBinding, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRoleTemplateBindingList.
-func (in *ProjectRoleTemplateBindingList) DeepCopy() *ProjectRoleTemplateBindingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaLimit.
+func (in *ResourceQuotaLimit) DeepCopy() *ResourceQuotaLimit {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectRoleTemplateBindingList)
+	out := new(ResourceQuotaLimit)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectRoleTemplateBindingList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
+func (in *RestoreFromEtcdBackupInput) DeepCopyInto(out *RestoreFromEtcdBackupInput) {
 	*out = *in
-	if in.ResourceQuota != nil {
-		in, out := &in.ResourceQuota, &out.ResourceQuota
-		*out = new(ProjectResourceQuota)
-		**out = **in
-	}
-	if in.NamespaceDefaultResourceQuota != nil {
-		in, out := &in.NamespaceDefaultResourceQuota, &out.NamespaceDefaultResourceQuota
-		*out = new(NamespaceResourceQuota)
-		**out = **in
-	}
-	if in.ContainerDefaultResourceLimit != nil {
-		in, out := &in.ContainerDefaultResourceLimit, &out.ContainerDefaultResourceLimit
-		*out = new(ContainerResourceLimit)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
-func (in *ProjectSpec) DeepCopy() *ProjectSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreFromEtcdBackupInput.
+func (in *RestoreFromEtcdBackupInput) DeepCopy() *RestoreFromEtcdBackupInput {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectSpec)
+	out := new(RestoreFromEtcdBackupInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
+func (in *Rke2Config) DeepCopyInto(out *Rke2Config) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ProjectCondition, len(*in))
-		copy(*out, *in)
-	}
+	out.ClusterUpgradeStrategy = in.ClusterUpgradeStrategy
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
-func (in *ProjectStatus) DeepCopy() *ProjectStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rke2Config.
+func (in *Rke2Config) DeepCopy() *Rke2Config {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectStatus)
+	out := new(Rke2Config)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PublicEndpoint) DeepCopyInto(out *PublicEndpoint) {
+func (in *RkeAddon) DeepCopyInto(out *RkeAddon) {
 	*out = *in
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicEndpoint.
-func (in *PublicEndpoint) DeepCopy() *PublicEndpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeAddon.
+func (in *RkeAddon) DeepCopy() *RkeAddon {
 	if in == nil {
 		return nil
 	}
-	out := new(PublicEndpoint)
+	out := new(RkeAddon)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RkeAddon) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Question) DeepCopyInto(out *Question) {
+func (in *RkeAddonList) DeepCopyInto(out *RkeAddonList) {
 	*out = *in
-	if in.Options != nil {
-		in, out := &in.Options, &out.Options
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Subquestions != nil {
-		in, out := &in.Subquestions, &out.Subquestions
-		*out = make([]SubQuestion, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RkeAddon, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -5917,36 +8528,46 @@ func (in *Question) DeepCopyInto(out *Question) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Question.
-func (in *Question) DeepCopy() *Question {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeAddonList.
+func (in *RkeAddonList) DeepCopy() *RkeAddonList {
 	if in == nil {
 		return nil
 	}
-	out := new(Question)
+	out := new(RkeAddonList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RkeAddonList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RancherUserNotification) DeepCopyInto(out *RancherUserNotification) {
+func (in *RkeK8sServiceOption) DeepCopyInto(out *RkeK8sServiceOption) {
 	*out = *in
+	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.ServiceOptions.DeepCopyInto(&out.ServiceOptions)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RancherUserNotification.
-func (in *RancherUserNotification) DeepCopy() *RancherUserNotification {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeK8sServiceOption.
+func (in *RkeK8sServiceOption) DeepCopy() *RkeK8sServiceOption {
 	if in == nil {
 		return nil
 	}
-	out := new(RancherUserNotification)
+	out := new(RkeK8sServiceOption)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RancherUserNotification) DeepCopyObject() runtime.Object {
+func (in *RkeK8sServiceOption) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5954,13 +8575,13 @@ func (in *RancherUserNotification) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RancherUserNotificationList) DeepCopyInto(out *RancherUserNotificationList) {
+func (in *RkeK8sServiceOptionList) DeepCopyInto(out *RkeK8sServiceOptionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RancherUserNotification, len(*in))
+		*out = make([]RkeK8sServiceOption, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -5968,18 +8589,18 @@ func (in *RancherUserNotificationList) DeepCopyInto(out *RancherUserNotification
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RancherUserNotificationList.
-func (in *RancherUserNotificationList) DeepCopy() *RancherUserNotificationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeK8sServiceOptionList.
+func (in *RkeK8sServiceOptionList) DeepCopy() *RkeK8sServiceOptionList {
 	if in == nil {
 		return nil
 	}
-	out := new(RancherUserNotificationList)
+	out := new(RkeK8sServiceOptionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RancherUserNotificationList) DeepCopyObject() runtime.Object {
+func (in *RkeK8sServiceOptionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -5987,75 +8608,112 @@ func (in *RancherUserNotificationList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceQuotaLimit) DeepCopyInto(out *ResourceQuotaLimit) {
+func (in *RkeK8sSystemImage) DeepCopyInto(out *RkeK8sSystemImage) {
 	*out = *in
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.SystemImages = in.SystemImages
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaLimit.
-func (in *ResourceQuotaLimit) DeepCopy() *ResourceQuotaLimit {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeK8sSystemImage.
+func (in *RkeK8sSystemImage) DeepCopy() *RkeK8sSystemImage {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceQuotaLimit)
+	out := new(RkeK8sSystemImage)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestoreFromEtcdBackupInput) DeepCopyInto(out *RestoreFromEtcdBackupInput) {
-	*out = *in
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreFromEtcdBackupInput.
-func (in *RestoreFromEtcdBackupInput) DeepCopy() *RestoreFromEtcdBackupInput {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RkeK8sSystemImage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(RestoreFromEtcdBackupInput)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Rke2Config) DeepCopyInto(out *Rke2Config) {
+func (in *RkeK8sSystemImageList) DeepCopyInto(out *RkeK8sSystemImageList) {
 	*out = *in
-	out.ClusterUpgradeStrategy = in.ClusterUpgradeStrategy
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RkeK8sSystemImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rke2Config.
-func (in *Rke2Config) DeepCopy() *Rke2Config {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeK8sSystemImageList.
+func (in *RkeK8sSystemImageList) DeepCopy() *RkeK8sSystemImageList {
 	if in == nil {
 		return nil
 	}
-	out := new(Rke2Config)
+	out := new(RkeK8sSystemImageList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RkeK8sSystemImageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RkeAddon) DeepCopyInto(out *RkeAddon) {
+func (in *RoleTemplate) DeepCopyInto(out *RoleTemplate) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalRules != nil {
+		in, out := &in.ExternalRules, &out.ExternalRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleTemplateNames != nil {
+		in, out := &in.RoleTemplateNames, &out.RoleTemplateNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedRules != nil {
+		in, out := &in.ExcludedRules, &out.ExcludedRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeAddon.
-func (in *RkeAddon) DeepCopy() *RkeAddon {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplate.
+func (in *RoleTemplate) DeepCopy() *RoleTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(RkeAddon)
+	out := new(RoleTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RkeAddon) DeepCopyObject() runtime.Object {
+func (in *RoleTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -6063,13 +8721,13 @@ func (in *RkeAddon) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RkeAddonList) DeepCopyInto(out *RkeAddonList) {
+func (in *RoleTemplateList) DeepCopyInto(out *RoleTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RkeAddon, len(*in))
+		*out = make([]RoleTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -6077,18 +8735,18 @@ func (in *RkeAddonList) DeepCopyInto(out *RkeAddonList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeAddonList.
-func (in *RkeAddonList) DeepCopy() *RkeAddonList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateList.
+func (in *RoleTemplateList) DeepCopy() *RoleTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(RkeAddonList)
+	out := new(RoleTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RkeAddonList) DeepCopyObject() runtime.Object {
+func (in *RoleTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -6096,41 +8754,110 @@ func (in *RkeAddonList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RkeK8sServiceOption) DeepCopyInto(out *RkeK8sServiceOption) {
+func (in *RoleTemplateRenderInput) DeepCopyInto(out *RoleTemplateRenderInput) {
 	*out = *in
-	out.Namespaced = in.Namespaced
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.ServiceOptions.DeepCopyInto(&out.ServiceOptions)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalRules != nil {
+		in, out := &in.ExternalRules, &out.ExternalRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleTemplateNames != nil {
+		in, out := &in.RoleTemplateNames, &out.RoleTemplateNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedRules != nil {
+		in, out := &in.ExcludedRules, &out.ExcludedRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeK8sServiceOption.
-func (in *RkeK8sServiceOption) DeepCopy() *RkeK8sServiceOption {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateRenderInput.
+func (in *RoleTemplateRenderInput) DeepCopy() *RoleTemplateRenderInput {
 	if in == nil {
 		return nil
 	}
-	out := new(RkeK8sServiceOption)
+	out := new(RoleTemplateRenderInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RkeK8sServiceOption) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTemplateRenderOutput) DeepCopyInto(out *RoleTemplateRenderOutput) {
+	*out = *in
+	if in.ClusterRoles != nil {
+		in, out := &in.ClusterRoles, &out.ClusterRoles
+		*out = make([]RenderedRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	return nil
+	if in.ClusterRoleBindings != nil {
+		in, out := &in.ClusterRoleBindings, &out.ClusterRoleBindings
+		*out = make([]RenderedBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.RoleBindings != nil {
+		in, out := &in.RoleBindings, &out.RoleBindings
+		*out = make([]RenderedBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateRenderOutput.
+func (in *RoleTemplateRenderOutput) DeepCopy() *RoleTemplateRenderOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTemplateRenderOutput)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RkeK8sServiceOptionList) DeepCopyInto(out *RkeK8sServiceOptionList) {
+func (in *RoleTemplateSimulateInput) DeepCopyInto(out *RoleTemplateSimulateInput) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]RkeK8sServiceOption, len(*in))
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalRules != nil {
+		in, out := &in.ExternalRules, &out.ExternalRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleTemplateNames != nil {
+		in, out := &in.RoleTemplateNames, &out.RoleTemplateNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedRules != nil {
+		in, out := &in.ExcludedRules, &out.ExcludedRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -6138,46 +8865,98 @@ func (in *RkeK8sServiceOptionList) DeepCopyInto(out *RkeK8sServiceOptionList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeK8sServiceOptionList.
-func (in *RkeK8sServiceOptionList) DeepCopy() *RkeK8sServiceOptionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateSimulateInput.
+func (in *RoleTemplateSimulateInput) DeepCopy() *RoleTemplateSimulateInput {
 	if in == nil {
 		return nil
 	}
-	out := new(RkeK8sServiceOptionList)
+	out := new(RoleTemplateSimulateInput)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RkeK8sServiceOptionList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTemplateSimulateOutput) DeepCopyInto(out *RoleTemplateSimulateOutput) {
+	*out = *in
+	if in.RoleTemplates != nil {
+		in, out := &in.RoleTemplates, &out.RoleTemplates
+		*out = make([]SimulatedRoleTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	return nil
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]SimulatedCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateSimulateOutput.
+func (in *RoleTemplateSimulateOutput) DeepCopy() *RoleTemplateSimulateOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTemplateSimulateOutput)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RkeK8sSystemImage) DeepCopyInto(out *RkeK8sSystemImage) {
+func (in *RoleUsage) DeepCopyInto(out *RoleUsage) {
+	*out = *in
+	if in.InheritedBy != nil {
+		in, out := &in.InheritedBy, &out.InheritedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUsedTime != nil {
+		in, out := &in.LastUsedTime, &out.LastUsedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleUsage.
+func (in *RoleUsage) DeepCopy() *RoleUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleUsageReport) DeepCopyInto(out *RoleUsageReport) {
 	*out = *in
-	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.SystemImages = in.SystemImages
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeK8sSystemImage.
-func (in *RkeK8sSystemImage) DeepCopy() *RkeK8sSystemImage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleUsageReport.
+func (in *RoleUsageReport) DeepCopy() *RoleUsageReport {
 	if in == nil {
 		return nil
 	}
-	out := new(RkeK8sSystemImage)
+	out := new(RoleUsageReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RkeK8sSystemImage) DeepCopyObject() runtime.Object {
+func (in *RoleUsageReport) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -6185,13 +8964,13 @@ func (in *RkeK8sSystemImage) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RkeK8sSystemImageList) DeepCopyInto(out *RkeK8sSystemImageList) {
+func (in *RoleUsageReportList) DeepCopyInto(out *RoleUsageReportList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RkeK8sSystemImage, len(*in))
+		*out = make([]RoleUsageReport, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -6199,18 +8978,18 @@ func (in *RkeK8sSystemImageList) DeepCopyInto(out *RkeK8sSystemImageList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RkeK8sSystemImageList.
-func (in *RkeK8sSystemImageList) DeepCopy() *RkeK8sSystemImageList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleUsageReportList.
+func (in *RoleUsageReportList) DeepCopy() *RoleUsageReportList {
 	if in == nil {
 		return nil
 	}
-	out := new(RkeK8sSystemImageList)
+	out := new(RoleUsageReportList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RkeK8sSystemImageList) DeepCopyObject() runtime.Object {
+func (in *RoleUsageReportList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -6218,58 +8997,31 @@ func (in *RkeK8sSystemImageList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RoleTemplate) DeepCopyInto(out *RoleTemplate) {
+func (in *RoleUsageReportSpec) DeepCopyInto(out *RoleUsageReportSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]rbacv1.PolicyRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExternalRules != nil {
-		in, out := &in.ExternalRules, &out.ExternalRules
-		*out = make([]rbacv1.PolicyRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.RoleTemplateNames != nil {
-		in, out := &in.RoleTemplateNames, &out.RoleTemplateNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplate.
-func (in *RoleTemplate) DeepCopy() *RoleTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleUsageReportSpec.
+func (in *RoleUsageReportSpec) DeepCopy() *RoleUsageReportSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RoleTemplate)
+	out := new(RoleUsageReportSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RoleTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RoleTemplateList) DeepCopyInto(out *RoleTemplateList) {
+func (in *RoleUsageReportStatus) DeepCopyInto(out *RoleUsageReportStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]RoleTemplate, len(*in))
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleUsage, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -6277,24 +9029,16 @@ func (in *RoleTemplateList) DeepCopyInto(out *RoleTemplateList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateList.
-func (in *RoleTemplateList) DeepCopy() *RoleTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleUsageReportStatus.
+func (in *RoleUsageReportStatus) DeepCopy() *RoleUsageReportStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RoleTemplateList)
+	out := new(RoleUsageReportStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RoleTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RotateCertificateInput) DeepCopyInto(out *RotateCertificateInput) {
 	*out = *in
@@ -6665,27 +9409,87 @@ func (in *SaveAsTemplateOutput) DeepCopy() *SaveAsTemplateOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(SaveAsTemplateOutput)
-	in.DeepCopyInto(out)
-	return out
+	out := new(SaveAsTemplateOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchPrincipalsInput) DeepCopyInto(out *SearchPrincipalsInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchPrincipalsInput.
+func (in *SearchPrincipalsInput) DeepCopy() *SearchPrincipalsInput {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchPrincipalsInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityEvent) DeepCopyInto(out *SecurityEvent) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityEvent.
+func (in *SecurityEvent) DeepCopy() *SecurityEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityEvent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SearchPrincipalsInput) DeepCopyInto(out *SearchPrincipalsInput) {
+func (in *SecurityEventList) DeepCopyInto(out *SecurityEventList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecurityEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchPrincipalsInput.
-func (in *SearchPrincipalsInput) DeepCopy() *SearchPrincipalsInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityEventList.
+func (in *SecurityEventList) DeepCopy() *SecurityEventList {
 	if in == nil {
 		return nil
 	}
-	out := new(SearchPrincipalsInput)
+	out := new(SecurityEventList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityEventList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SetPasswordInput) DeepCopyInto(out *SetPasswordInput) {
 	*out = *in
@@ -6766,6 +9570,11 @@ func (in *ShibbolethConfig) DeepCopyInto(out *ShibbolethConfig) {
 	*out = *in
 	in.SamlConfig.DeepCopyInto(&out.SamlConfig)
 	in.OpenLdapConfig.DeepCopyInto(&out.OpenLdapConfig)
+	if in.EntitlementMappings != nil {
+		in, out := &in.EntitlementMappings, &out.EntitlementMappings
+		*out = make([]ShibbolethEntitlementMapping, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -6779,6 +9588,22 @@ func (in *ShibbolethConfig) DeepCopy() *ShibbolethConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShibbolethEntitlementMapping) DeepCopyInto(out *ShibbolethEntitlementMapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShibbolethEntitlementMapping.
+func (in *ShibbolethEntitlementMapping) DeepCopy() *ShibbolethEntitlementMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ShibbolethEntitlementMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ShibbolethProvider) DeepCopyInto(out *ShibbolethProvider) {
 	*out = *in
@@ -6796,6 +9621,92 @@ func (in *ShibbolethProvider) DeepCopy() *ShibbolethProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SimulatedBinding) DeepCopyInto(out *SimulatedBinding) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatedBinding.
+func (in *SimulatedBinding) DeepCopy() *SimulatedBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(SimulatedBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SimulatedCluster) DeepCopyInto(out *SimulatedCluster) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]SimulatedBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AddedRules != nil {
+		in, out := &in.AddedRules, &out.AddedRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemovedRules != nil {
+		in, out := &in.RemovedRules, &out.RemovedRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatedCluster.
+func (in *SimulatedCluster) DeepCopy() *SimulatedCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(SimulatedCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SimulatedRoleTemplate) DeepCopyInto(out *SimulatedRoleTemplate) {
+	*out = *in
+	if in.AddedRules != nil {
+		in, out := &in.AddedRules, &out.AddedRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemovedRules != nil {
+		in, out := &in.RemovedRules, &out.RemovedRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatedRoleTemplate.
+func (in *SimulatedRoleTemplate) DeepCopy() *SimulatedRoleTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SimulatedRoleTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubQuestion) DeepCopyInto(out *SubQuestion) {
 	*out = *in
@@ -6850,6 +9761,16 @@ func (in *Token) DeepCopyInto(out *Token) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(TokenScope)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -6904,6 +9825,95 @@ func (in *TokenList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenScope) DeepCopyInto(out *TokenScope) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verbs != nil {
+		in, out := &in.Verbs, &out.Verbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenScope.
+func (in *TokenScope) DeepCopy() *TokenScope {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenUsageEvent) DeepCopyInto(out *TokenUsageEvent) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenUsageEvent.
+func (in *TokenUsageEvent) DeepCopy() *TokenUsageEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenUsageEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenUsageHistory) DeepCopyInto(out *TokenUsageHistory) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]TokenUsageEvent, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenUsageHistory.
+func (in *TokenUsageHistory) DeepCopy() *TokenUsageHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenUsageHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnlinkPrincipalInput) DeepCopyInto(out *UnlinkPrincipalInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnlinkPrincipalInput.
+func (in *UnlinkPrincipalInput) DeepCopy() *UnlinkPrincipalInput {
+	if in == nil {
+		return nil
+	}
+	out := new(UnlinkPrincipalInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -7063,6 +10073,88 @@ func (in *UserCondition) DeepCopy() *UserCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataExport) DeepCopyInto(out *UserDataExport) {
+	*out = *in
+	in.User.DeepCopyInto(&out.User)
+	if in.GroupPrincipals != nil {
+		in, out := &in.GroupPrincipals, &out.GroupPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraByProvider != nil {
+		in, out := &in.ExtraByProvider, &out.ExtraByProvider
+		*out = make(map[string]map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string][]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string][]string, len(*in))
+				for key, val := range *in {
+					var outVal []string
+					if val == nil {
+						(*out)[key] = nil
+					} else {
+						in, out := &val, &outVal
+						*out = make([]string, len(*in))
+						copy(*out, *in)
+					}
+					(*out)[key] = outVal
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]Token, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TokenUsage != nil {
+		in, out := &in.TokenUsage, &out.TokenUsage
+		*out = make([]TokenUsageHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GlobalRoleBindings != nil {
+		in, out := &in.GlobalRoleBindings, &out.GlobalRoleBindings
+		*out = make([]GlobalRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterRoleTemplateBindings != nil {
+		in, out := &in.ClusterRoleTemplateBindings, &out.ClusterRoleTemplateBindings
+		*out = make([]ClusterRoleTemplateBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProjectRoleTemplateBindings != nil {
+		in, out := &in.ProjectRoleTemplateBindings, &out.ProjectRoleTemplateBindings
+		*out = make([]ProjectRoleTemplateBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataExport.
+func (in *UserDataExport) DeepCopy() *UserDataExport {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserList) DeepCopyInto(out *UserList) {
 	*out = *in
@@ -7154,6 +10246,38 @@ func (in *Values) DeepCopy() *Values {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WSFedFields) DeepCopyInto(out *WSFedFields) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WSFedFields.
+func (in *WSFedFields) DeepCopy() *WSFedFields {
+	if in == nil {
+		return nil
+	}
+	out := new(WSFedFields)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebAuthnAssertion) DeepCopyInto(out *WebAuthnAssertion) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebAuthnAssertion.
+func (in *WebAuthnAssertion) DeepCopy() *WebAuthnAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(WebAuthnAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WindowsSystemImages) DeepCopyInto(out *WindowsSystemImages) {
 	*out = *in
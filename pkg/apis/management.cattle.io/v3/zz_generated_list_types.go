@@ -1,5 +1,5 @@
 /*
-Copyright 2025 Rancher Labs, Inc.
+Copyright 2026 Rancher Labs, Inc.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -43,6 +43,23 @@ func NewAPIService(namespace, name string, obj APIService) *APIService {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// AccessRequestList is a list of AccessRequest resources
+type AccessRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AccessRequest `json:"items"`
+}
+
+func NewAccessRequest(namespace, name string, obj AccessRequest) *AccessRequest {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("AccessRequest").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // ActiveDirectoryProviderList is a list of ActiveDirectoryProvider resources
 type ActiveDirectoryProviderList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -60,6 +77,23 @@ func NewActiveDirectoryProvider(namespace, name string, obj ActiveDirectoryProvi
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// AuditPolicyList is a list of AuditPolicy resources
+type AuditPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AuditPolicy `json:"items"`
+}
+
+func NewAuditPolicy(namespace, name string, obj AuditPolicy) *AuditPolicy {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("AuditPolicy").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // AuthConfigList is a list of AuthConfig resources
 type AuthConfigList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -77,6 +111,23 @@ func NewAuthConfig(namespace, name string, obj AuthConfig) *AuthConfig {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// AuthEventList is a list of AuthEvent resources
+type AuthEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AuthEvent `json:"items"`
+}
+
+func NewAuthEvent(namespace, name string, obj AuthEvent) *AuthEvent {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("AuthEvent").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // AuthProviderList is a list of AuthProvider resources
 type AuthProviderList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -128,6 +179,57 @@ func NewAzureADProvider(namespace, name string, obj AzureADProvider) *AzureADPro
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// BreakGlassAccountList is a list of BreakGlassAccount resources
+type BreakGlassAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BreakGlassAccount `json:"items"`
+}
+
+func NewBreakGlassAccount(namespace, name string, obj BreakGlassAccount) *BreakGlassAccount {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("BreakGlassAccount").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CASProviderList is a list of CASProvider resources
+type CASProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CASProvider `json:"items"`
+}
+
+func NewCASProvider(namespace, name string, obj CASProvider) *CASProvider {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("CASProvider").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClientCertProviderList is a list of ClientCertProvider resources
+type ClientCertProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClientCertProvider `json:"items"`
+}
+
+func NewClientCertProvider(namespace, name string, obj ClientCertProvider) *ClientCertProvider {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("ClientCertProvider").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // CloudCredentialList is a list of CloudCredential resources
 type CloudCredentialList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -298,6 +400,23 @@ func NewEtcdBackup(namespace, name string, obj EtcdBackup) *EtcdBackup {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// ExternalAuthProviderList is a list of ExternalAuthProvider resources
+type ExternalAuthProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ExternalAuthProvider `json:"items"`
+}
+
+func NewExternalAuthProvider(namespace, name string, obj ExternalAuthProvider) *ExternalAuthProvider {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("ExternalAuthProvider").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // FeatureList is a list of Feature resources
 type FeatureList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -468,6 +587,57 @@ func NewGroupMember(namespace, name string, obj GroupMember) *GroupMember {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// GroupMembershipRuleList is a list of GroupMembershipRule resources
+type GroupMembershipRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GroupMembershipRule `json:"items"`
+}
+
+func NewGroupMembershipRule(namespace, name string, obj GroupMembershipRule) *GroupMembershipRule {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("GroupMembershipRule").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JITProvisioningPolicyList is a list of JITProvisioningPolicy resources
+type JITProvisioningPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []JITProvisioningPolicy `json:"items"`
+}
+
+func NewJITProvisioningPolicy(namespace, name string, obj JITProvisioningPolicy) *JITProvisioningPolicy {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("JITProvisioningPolicy").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KerberosProviderList is a list of KerberosProvider resources
+type KerberosProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []KerberosProvider `json:"items"`
+}
+
+func NewKerberosProvider(namespace, name string, obj KerberosProvider) *KerberosProvider {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("KerberosProvider").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // KontainerDriverList is a list of KontainerDriver resources
 type KontainerDriverList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -519,6 +689,23 @@ func NewManagedChart(namespace, name string, obj ManagedChart) *ManagedChart {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// MultiClusterRoleTemplateBindingList is a list of MultiClusterRoleTemplateBinding resources
+type MultiClusterRoleTemplateBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []MultiClusterRoleTemplateBinding `json:"items"`
+}
+
+func NewMultiClusterRoleTemplateBinding(namespace, name string, obj MultiClusterRoleTemplateBinding) *MultiClusterRoleTemplateBinding {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("MultiClusterRoleTemplateBinding").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // NodeList is a list of Node resources
 type NodeList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -621,6 +808,40 @@ func NewOpenLdapProvider(namespace, name string, obj OpenLdapProvider) *OpenLdap
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// OrganizationList is a list of Organization resources
+type OrganizationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Organization `json:"items"`
+}
+
+func NewOrganization(namespace, name string, obj Organization) *Organization {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("Organization").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OrphanedBindingReportList is a list of OrphanedBindingReport resources
+type OrphanedBindingReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []OrphanedBindingReport `json:"items"`
+}
+
+func NewOrphanedBindingReport(namespace, name string, obj OrphanedBindingReport) *OrphanedBindingReport {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("OrphanedBindingReport").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // PodSecurityAdmissionConfigurationTemplateList is a list of PodSecurityAdmissionConfigurationTemplate resources
 type PodSecurityAdmissionConfigurationTemplateList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -672,6 +893,23 @@ func NewPrincipal(namespace, name string, obj Principal) *Principal {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// PrincipalMetadataList is a list of PrincipalMetadata resources
+type PrincipalMetadataList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []PrincipalMetadata `json:"items"`
+}
+
+func NewPrincipalMetadata(namespace, name string, obj PrincipalMetadata) *PrincipalMetadata {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("PrincipalMetadata").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // ProjectList is a list of Project resources
 type ProjectList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -723,6 +961,23 @@ func NewProjectRoleTemplateBinding(namespace, name string, obj ProjectRoleTempla
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// RBACDriftReportList is a list of RBACDriftReport resources
+type RBACDriftReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RBACDriftReport `json:"items"`
+}
+
+func NewRBACDriftReport(namespace, name string, obj RBACDriftReport) *RBACDriftReport {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("RBACDriftReport").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // RancherUserNotificationList is a list of RancherUserNotification resources
 type RancherUserNotificationList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -740,6 +995,23 @@ func NewRancherUserNotification(namespace, name string, obj RancherUserNotificat
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// RecertificationCampaignList is a list of RecertificationCampaign resources
+type RecertificationCampaignList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RecertificationCampaign `json:"items"`
+}
+
+func NewRecertificationCampaign(namespace, name string, obj RecertificationCampaign) *RecertificationCampaign {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("RecertificationCampaign").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // RkeAddonList is a list of RkeAddon resources
 type RkeAddonList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -808,6 +1080,23 @@ func NewRoleTemplate(namespace, name string, obj RoleTemplate) *RoleTemplate {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// RoleUsageReportList is a list of RoleUsageReport resources
+type RoleUsageReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RoleUsageReport `json:"items"`
+}
+
+func NewRoleUsageReport(namespace, name string, obj RoleUsageReport) *RoleUsageReport {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("RoleUsageReport").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // SamlProviderList is a list of SamlProvider resources
 type SamlProviderList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -842,6 +1131,23 @@ func NewSamlToken(namespace, name string, obj SamlToken) *SamlToken {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// SecurityEventList is a list of SecurityEvent resources
+type SecurityEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SecurityEvent `json:"items"`
+}
+
+func NewSecurityEvent(namespace, name string, obj SecurityEvent) *SecurityEvent {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("SecurityEvent").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // SettingList is a list of Setting resources
 type SettingList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -1,5 +1,5 @@
 /*
-Copyright 2025 Rancher Labs, Inc.
+Copyright 2026 Rancher Labs, Inc.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -29,11 +29,17 @@
 
 var (
 	APIServiceResourceName                                = "apiservices"
+	AccessRequestResourceName                             = "accessrequests"
 	ActiveDirectoryProviderResourceName                   = "activedirectoryproviders"
+	AuditPolicyResourceName                               = "auditpolicies"
 	AuthConfigResourceName                                = "authconfigs"
+	AuthEventResourceName                                 = "authevents"
 	AuthProviderResourceName                              = "authproviders"
 	AuthTokenResourceName                                 = "authtokens"
 	AzureADProviderResourceName                           = "azureadproviders"
+	BreakGlassAccountResourceName                         = "breakglassaccounts"
+	CASProviderResourceName                               = "casproviders"
+	ClientCertProviderResourceName                        = "clientcertproviders"
 	CloudCredentialResourceName                           = "cloudcredentials"
 	ClusterResourceName                                   = "clusters"
 	ClusterProxyConfigResourceName                        = "clusterproxyconfigs"
@@ -44,6 +50,7 @@
 	ComposeConfigResourceName                             = "composeconfigs"
 	DynamicSchemaResourceName                             = "dynamicschemas"
 	EtcdBackupResourceName                                = "etcdbackups"
+	ExternalAuthProviderResourceName                      = "externalauthproviders"
 	FeatureResourceName                                   = "features"
 	FleetWorkspaceResourceName                            = "fleetworkspaces"
 	FreeIpaProviderResourceName                           = "freeipaproviders"
@@ -54,28 +61,39 @@
 	GoogleOAuthProviderResourceName                       = "googleoauthproviders"
 	GroupResourceName                                     = "groups"
 	GroupMemberResourceName                               = "groupmembers"
+	GroupMembershipRuleResourceName                       = "groupmembershiprules"
+	JITProvisioningPolicyResourceName                     = "jitprovisioningpolicies"
+	KerberosProviderResourceName                          = "kerberosproviders"
 	KontainerDriverResourceName                           = "kontainerdrivers"
 	LocalProviderResourceName                             = "localproviders"
 	ManagedChartResourceName                              = "managedcharts"
+	MultiClusterRoleTemplateBindingResourceName           = "multiclusterroletemplatebindings"
 	NodeResourceName                                      = "nodes"
 	NodeDriverResourceName                                = "nodedrivers"
 	NodePoolResourceName                                  = "nodepools"
 	NodeTemplateResourceName                              = "nodetemplates"
 	OIDCProviderResourceName                              = "oidcproviders"
 	OpenLdapProviderResourceName                          = "openldapproviders"
+	OrganizationResourceName                              = "organizations"
+	OrphanedBindingReportResourceName                     = "orphanedbindingreports"
 	PodSecurityAdmissionConfigurationTemplateResourceName = "podsecurityadmissionconfigurationtemplates"
 	PreferenceResourceName                                = "preferences"
 	PrincipalResourceName                                 = "principals"
+	PrincipalMetadataResourceName                         = "principalmetadatas"
 	ProjectResourceName                                   = "projects"
 	ProjectNetworkPolicyResourceName                      = "projectnetworkpolicies"
 	ProjectRoleTemplateBindingResourceName                = "projectroletemplatebindings"
+	RBACDriftReportResourceName                           = "rbacdriftreports"
 	RancherUserNotificationResourceName                   = "rancherusernotifications"
+	RecertificationCampaignResourceName                   = "recertificationcampaigns"
 	RkeAddonResourceName                                  = "rkeaddons"
 	RkeK8sServiceOptionResourceName                       = "rkek8sserviceoptions"
 	RkeK8sSystemImageResourceName                         = "rkek8ssystemimages"
 	RoleTemplateResourceName                              = "roletemplates"
+	RoleUsageReportResourceName                           = "roleusagereports"
 	SamlProviderResourceName                              = "samlproviders"
 	SamlTokenResourceName                                 = "samltokens"
+	SecurityEventResourceName                             = "securityevents"
 	SettingResourceName                                   = "settings"
 	TokenResourceName                                     = "tokens"
 	UserResourceName                                      = "users"
@@ -105,16 +123,28 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&APIService{},
 		&APIServiceList{},
+		&AccessRequest{},
+		&AccessRequestList{},
 		&ActiveDirectoryProvider{},
 		&ActiveDirectoryProviderList{},
+		&AuditPolicy{},
+		&AuditPolicyList{},
 		&AuthConfig{},
 		&AuthConfigList{},
+		&AuthEvent{},
+		&AuthEventList{},
 		&AuthProvider{},
 		&AuthProviderList{},
 		&AuthToken{},
 		&AuthTokenList{},
 		&AzureADProvider{},
 		&AzureADProviderList{},
+		&BreakGlassAccount{},
+		&BreakGlassAccountList{},
+		&CASProvider{},
+		&CASProviderList{},
+		&ClientCertProvider{},
+		&ClientCertProviderList{},
 		&CloudCredential{},
 		&CloudCredentialList{},
 		&Cluster{},
@@ -135,6 +165,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&DynamicSchemaList{},
 		&EtcdBackup{},
 		&EtcdBackupList{},
+		&ExternalAuthProvider{},
+		&ExternalAuthProviderList{},
 		&Feature{},
 		&FeatureList{},
 		&FleetWorkspace{},
@@ -155,12 +187,20 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&GroupList{},
 		&GroupMember{},
 		&GroupMemberList{},
+		&GroupMembershipRule{},
+		&GroupMembershipRuleList{},
+		&JITProvisioningPolicy{},
+		&JITProvisioningPolicyList{},
+		&KerberosProvider{},
+		&KerberosProviderList{},
 		&KontainerDriver{},
 		&KontainerDriverList{},
 		&LocalProvider{},
 		&LocalProviderList{},
 		&ManagedChart{},
 		&ManagedChartList{},
+		&MultiClusterRoleTemplateBinding{},
+		&MultiClusterRoleTemplateBindingList{},
 		&Node{},
 		&NodeList{},
 		&NodeDriver{},
@@ -173,20 +213,30 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&OIDCProviderList{},
 		&OpenLdapProvider{},
 		&OpenLdapProviderList{},
+		&Organization{},
+		&OrganizationList{},
+		&OrphanedBindingReport{},
+		&OrphanedBindingReportList{},
 		&PodSecurityAdmissionConfigurationTemplate{},
 		&PodSecurityAdmissionConfigurationTemplateList{},
 		&Preference{},
 		&PreferenceList{},
 		&Principal{},
 		&PrincipalList{},
+		&PrincipalMetadata{},
+		&PrincipalMetadataList{},
 		&Project{},
 		&ProjectList{},
 		&ProjectNetworkPolicy{},
 		&ProjectNetworkPolicyList{},
 		&ProjectRoleTemplateBinding{},
 		&ProjectRoleTemplateBindingList{},
+		&RBACDriftReport{},
+		&RBACDriftReportList{},
 		&RancherUserNotification{},
 		&RancherUserNotificationList{},
+		&RecertificationCampaign{},
+		&RecertificationCampaignList{},
 		&RkeAddon{},
 		&RkeAddonList{},
 		&RkeK8sServiceOption{},
@@ -195,10 +245,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&RkeK8sSystemImageList{},
 		&RoleTemplate{},
 		&RoleTemplateList{},
+		&RoleUsageReport{},
+		&RoleUsageReportList{},
 		&SamlProvider{},
 		&SamlProviderList{},
 		&SamlToken{},
 		&SamlTokenList{},
+		&SecurityEvent{},
+		&SecurityEventList{},
 		&Setting{},
 		&SettingList{},
 		&Token{},
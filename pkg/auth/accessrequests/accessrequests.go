@@ -0,0 +1,273 @@
+// Package accessrequests lets users request a role in a cluster or a project for a limited time, with a justification,
+// instead of asking for it in a ticket. Approvers, the users allowed the approve verb on the access requests and allowed
+// to create the role bindings of the cluster or project, approve or deny them. Approved requests are granted with a
+// role template binding expiring at the end of the requested duration. The requests are kept along with the decisions
+// on them, as an audit trail.
+package accessrequests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// StatePending, StateApproved and StateDenied are the states of the access requests.
+	StatePending  = "Pending"
+	StateApproved = "Approved"
+	StateDenied   = "Denied"
+
+	// RequestLabel is set on the role template bindings granting access requests to the name of their request.
+	RequestLabel = "auth.cattle.io/access-request"
+
+	maxJustificationLength = 1024
+	maxReasonLength        = 1024
+)
+
+type createInput struct {
+	ClusterName      string `json:"clusterName,omitempty"`
+	ProjectName      string `json:"projectName,omitempty"`
+	RoleTemplateName string `json:"roleTemplateName"`
+	Justification    string `json:"justification"`
+	Duration         string `json:"duration"`
+}
+
+type decisionInput struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type handler struct {
+	requests             mgmtcontrollers.AccessRequestClient
+	requestCache         mgmtcontrollers.AccessRequestCache
+	clusterCache         mgmtcontrollers.ClusterCache
+	projectCache         mgmtcontrollers.ProjectCache
+	roleTemplateCache    mgmtcontrollers.RoleTemplateCache
+	crtbs                mgmtcontrollers.ClusterRoleTemplateBindingClient
+	crtbCache            mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbs                mgmtcontrollers.ProjectRoleTemplateBindingClient
+	prtbCache            mgmtcontrollers.ProjectRoleTemplateBindingCache
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+	now                  func() time.Time
+}
+
+func newHandler(mgmt *config.ScaledContext) *handler {
+	return &handler{
+		requests:             mgmt.Wrangler.Mgmt.AccessRequest(),
+		requestCache:         mgmt.Wrangler.Mgmt.AccessRequest().Cache(),
+		clusterCache:         mgmt.Wrangler.Mgmt.Cluster().Cache(),
+		projectCache:         mgmt.Wrangler.Mgmt.Project().Cache(),
+		roleTemplateCache:    mgmt.Wrangler.Mgmt.RoleTemplate().Cache(),
+		crtbs:                mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding(),
+		crtbCache:            mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbs:                mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding(),
+		prtbCache:            mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+		now:                  time.Now,
+	}
+}
+
+// State returns the state of the access request, which is pending until it's approved or denied.
+func State(request *v3.AccessRequest) string {
+	if request.Status.State == "" {
+		return StatePending
+	}
+	return request.Status.State
+}
+
+func (in *createInput) validate() error {
+	if (in.ClusterName == "") == (in.ProjectName == "") {
+		return fmt.Errorf("either clusterName or projectName is required")
+	}
+	if in.ProjectName != "" {
+		if clusterName, projectName, found := strings.Cut(in.ProjectName, ":"); !found || clusterName == "" || projectName == "" {
+			return fmt.Errorf("invalid projectName, must be <cluster>:<project>")
+		}
+	}
+	if in.RoleTemplateName == "" {
+		return fmt.Errorf("roleTemplateName is required")
+	}
+	if strings.TrimSpace(in.Justification) == "" || len(in.Justification) > maxJustificationLength {
+		return fmt.Errorf("justification is required and must be at most %d characters", maxJustificationLength)
+	}
+	duration, err := time.ParseDuration(in.Duration)
+	maxHours := settings.AccessRequestMaxDurationHours.GetInt()
+	if err != nil || duration <= 0 || duration > time.Duration(maxHours)*time.Hour {
+		return fmt.Errorf("duration must be a positive duration like 4h, of at most %dh", maxHours)
+	}
+	return nil
+}
+
+// checkTarget returns an error, and its HTTP status, if the role can't be requested in the cluster or project of the
+// input. It must be a role template of their context which isn't locked, and they must exist.
+func (h *handler) checkTarget(in createInput) (int, error) {
+	roleContext := "cluster"
+	if in.ProjectName != "" {
+		roleContext = "project"
+	}
+	roleTemplate, err := h.roleTemplateCache.Get(in.RoleTemplateName)
+	if apierrors.IsNotFound(err) {
+		return http.StatusUnprocessableEntity, fmt.Errorf("role %s not found", in.RoleTemplateName)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if roleTemplate.Context != roleContext || roleTemplate.Locked {
+		return http.StatusUnprocessableEntity, fmt.Errorf("role %s can't be requested in a %s", in.RoleTemplateName, roleContext)
+	}
+
+	if in.ProjectName != "" {
+		clusterName, projectName, _ := strings.Cut(in.ProjectName, ":")
+		_, err = h.projectCache.Get(clusterName, projectName)
+	} else {
+		_, err = h.clusterCache.Get(in.ClusterName)
+	}
+	if apierrors.IsNotFound(err) {
+		return http.StatusUnprocessableEntity, fmt.Errorf("%s %s not found", roleContext, in.ClusterName+in.ProjectName)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// pendingDuplicate returns the pending request of the user for the same role in the same cluster or project, if any.
+func (h *handler) pendingDuplicate(userName string, in createInput) (*v3.AccessRequest, error) {
+	requests, err := h.requestCache.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		if request.Spec.UserName == userName && request.Spec.ClusterName == in.ClusterName &&
+			request.Spec.ProjectName == in.ProjectName && request.Spec.RoleTemplateName == in.RoleTemplateName &&
+			State(request) == StatePending {
+			return request, nil
+		}
+	}
+	return nil, nil
+}
+
+// createRequest creates the pending access request of the user.
+func (h *handler) createRequest(userName string, in createInput) (*v3.AccessRequest, error) {
+	request, err := h.requests.Create(&v3.AccessRequest{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "ar-"},
+		Spec: v3.AccessRequestSpec{
+			UserName:         userName,
+			ClusterName:      in.ClusterName,
+			ProjectName:      in.ProjectName,
+			RoleTemplateName: in.RoleTemplateName,
+			Justification:    in.Justification,
+			Duration:         in.Duration,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	request.Status.State = StatePending
+	updated, err := h.requests.UpdateStatus(request)
+	if err != nil {
+		// The request is pending without a state too.
+		logrus.Errorf("[accessrequests] failed to set the state of access request %s: %v", request.Name, err)
+		return request, nil
+	}
+	return updated, nil
+}
+
+// bindingTarget returns the namespace and the resource of the role template binding granting the request.
+func bindingTarget(request *v3.AccessRequest) (string, string) {
+	if request.Spec.ProjectName != "" {
+		_, projectName, _ := strings.Cut(request.Spec.ProjectName, ":")
+		return projectName, "projectroletemplatebindings"
+	}
+	return request.Spec.ClusterName, "clusterroletemplatebindings"
+}
+
+// grant binds the role of the request to its user until it expires. The binding is named after the request, so that
+// granting it again doesn't bind the role twice.
+func (h *handler) grant(request *v3.AccessRequest, expiresAt metav1.Time) (string, error) {
+	namespace, _ := bindingTarget(request)
+	meta := metav1.ObjectMeta{
+		Name:      request.Name,
+		Namespace: namespace,
+		Labels:    map[string]string{RequestLabel: request.Name},
+	}
+	provenance.Set(&meta, provenance.SourceAccessRequest, provenance.Creator("AccessRequest", request.Name))
+
+	var err error
+	if request.Spec.ProjectName != "" {
+		_, err = h.prtbs.Create(&v3.ProjectRoleTemplateBinding{
+			ObjectMeta:       meta,
+			ProjectName:      request.Spec.ProjectName,
+			UserName:         request.Spec.UserName,
+			RoleTemplateName: request.Spec.RoleTemplateName,
+			ExpiresAt:        &expiresAt,
+		})
+	} else {
+		_, err = h.crtbs.Create(&v3.ClusterRoleTemplateBinding{
+			ObjectMeta:       meta,
+			ClusterName:      request.Spec.ClusterName,
+			UserName:         request.Spec.UserName,
+			RoleTemplateName: request.Spec.RoleTemplateName,
+			ExpiresAt:        &expiresAt,
+		})
+	}
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", err
+	}
+	return namespace + ":" + request.Name, nil
+}
+
+// decide approves or denies the pending request, granting it if approved.
+func (h *handler) decide(request *v3.AccessRequest, approver string, approve bool, reason string) (*v3.AccessRequest, error) {
+	request = request.DeepCopy()
+	now := metav1.NewTime(h.now())
+	request.Status.State = StateDenied
+	request.Status.DecidedBy = approver
+	request.Status.DecidedAt = &now
+	request.Status.Reason = reason
+
+	if approve {
+		// The duration was validated on creation.
+		duration, _ := time.ParseDuration(request.Spec.Duration)
+		expiresAt := metav1.NewTime(now.Add(duration))
+		bindingName, err := h.grant(request, expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("granting access request %s: %w", request.Name, err)
+		}
+		request.Status.State = StateApproved
+		request.Status.BindingName = bindingName
+		request.Status.ExpiresAt = &expiresAt
+	}
+	return h.requests.UpdateStatus(request)
+}
+
+func logEvent(request *v3.AccessRequest, event, actor string) {
+	fields := logrus.Fields{
+		"event":            event,
+		"accessRequest":    request.Name,
+		"user":             request.Spec.UserName,
+		"roleTemplateName": request.Spec.RoleTemplateName,
+		"clusterName":      request.Spec.ClusterName,
+		"projectName":      request.Spec.ProjectName,
+		"duration":         request.Spec.Duration,
+		"actor":            actor,
+	}
+	if request.Status.Reason != "" {
+		fields["reason"] = request.Status.Reason
+	}
+	if request.Status.BindingName != "" {
+		fields["binding"] = request.Status.BindingName
+	}
+	logrus.WithFields(fields).Info("accessrequests: audit")
+}
@@ -0,0 +1,417 @@
+package accessrequests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	// approvers are allowed the approve verb on the access requests, bindingCreators to create the role bindings of
+	// cluster c-abcde and project p-fghij, and binders to bind the role templates. Admins are allowed everything.
+	approvers       map[string]bool
+	bindingCreators map[string]bool
+	binders         map[string]bool
+	admins          map[string]bool
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	attributes := sar.Spec.ResourceAttributes
+	switch {
+	case f.admins[sar.Spec.User]:
+		sar.Status.Allowed = true
+	case attributes.Verb == ApproveVerb && attributes.Resource == v3.AccessRequestResourceName:
+		sar.Status.Allowed = f.approvers[sar.Spec.User]
+	case attributes.Verb == "create" && (attributes.Namespace == "c-abcde" || attributes.Namespace == "p-fghij"):
+		sar.Status.Allowed = f.bindingCreators[sar.Spec.User]
+	case attributes.Verb == "bind" && attributes.Resource == v3.RoleTemplateResourceName:
+		sar.Status.Allowed = f.binders[sar.Spec.User]
+	}
+	return sar, nil
+}
+
+type store struct {
+	requests map[string]*v3.AccessRequest
+	crtbs    map[string]*v3.ClusterRoleTemplateBinding
+	prtbs    map[string]*v3.ProjectRoleTemplateBinding
+}
+
+func setup(t *testing.T, now *time.Time) (*handler, *store) {
+	ctrl := gomock.NewController(t)
+	s := &store{
+		requests: map[string]*v3.AccessRequest{},
+		crtbs:    map[string]*v3.ClusterRoleTemplateBinding{},
+		prtbs:    map[string]*v3.ProjectRoleTemplateBinding{},
+	}
+	notFound := func(resource, name string) error {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: resource}, name)
+	}
+
+	requests := fake.NewMockNonNamespacedClientInterface[*v3.AccessRequest, *v3.AccessRequestList](ctrl)
+	requests.EXPECT().Create(gomock.Any()).DoAndReturn(func(accessRequest *v3.AccessRequest) (*v3.AccessRequest, error) {
+		accessRequest = accessRequest.DeepCopy()
+		accessRequest.Name = fmt.Sprintf("%s%d", accessRequest.GenerateName, len(s.requests)+1)
+		accessRequest.CreationTimestamp = metav1.NewTime(now.Add(time.Duration(len(s.requests)) * time.Second))
+		s.requests[accessRequest.Name] = accessRequest
+		return accessRequest.DeepCopy(), nil
+	}).AnyTimes()
+	requests.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(accessRequest *v3.AccessRequest) (*v3.AccessRequest, error) {
+		s.requests[accessRequest.Name] = accessRequest.DeepCopy()
+		return accessRequest, nil
+	}).AnyTimes()
+	requests.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, _ metav1.GetOptions) (*v3.AccessRequest, error) {
+		if accessRequest, ok := s.requests[name]; ok {
+			return accessRequest.DeepCopy(), nil
+		}
+		return nil, notFound("accessrequests", name)
+	}).AnyTimes()
+	requestCache := fake.NewMockNonNamespacedCacheInterface[*v3.AccessRequest](ctrl)
+	requestCache.EXPECT().List(gomock.Any()).DoAndReturn(func(_ labels.Selector) ([]*v3.AccessRequest, error) {
+		var list []*v3.AccessRequest
+		for _, accessRequest := range s.requests {
+			list = append(list, accessRequest)
+		}
+		return list, nil
+	}).AnyTimes()
+
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	clusterCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.Cluster, error) {
+		if name == "c-abcde" {
+			return &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+		}
+		return nil, notFound("clusters", name)
+	}).AnyTimes()
+	projectCache := fake.NewMockCacheInterface[*v3.Project](ctrl)
+	projectCache.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string) (*v3.Project, error) {
+		if namespace == "c-abcde" && name == "p-fghij" {
+			return &v3.Project{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}, nil
+		}
+		return nil, notFound("projects", name)
+	}).AnyTimes()
+	roleTemplateCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	roleTemplateCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.RoleTemplate, error) {
+		switch name {
+		case "project-member":
+			return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}, Context: "project"}, nil
+		case "cluster-owner":
+			return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}, Context: "cluster"}, nil
+		case "locked":
+			return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}, Context: "cluster", Locked: true}, nil
+		}
+		return nil, notFound("roletemplates", name)
+	}).AnyTimes()
+
+	crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	crtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(binding *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		s.crtbs[binding.Namespace+":"+binding.Name] = binding.DeepCopy()
+		return binding, nil
+	}).AnyTimes()
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace string, _ labels.Selector) ([]*v3.ClusterRoleTemplateBinding, error) {
+		var list []*v3.ClusterRoleTemplateBinding
+		for _, binding := range s.crtbs {
+			if binding.Namespace == namespace {
+				list = append(list, binding)
+			}
+		}
+		return list, nil
+	}).AnyTimes()
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	prtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(binding *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+		s.prtbs[binding.Namespace+":"+binding.Name] = binding.DeepCopy()
+		return binding, nil
+	}).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace string, _ labels.Selector) ([]*v3.ProjectRoleTemplateBinding, error) {
+		var list []*v3.ProjectRoleTemplateBinding
+		for _, binding := range s.prtbs {
+			if binding.Namespace == namespace {
+				list = append(list, binding)
+			}
+		}
+		return list, nil
+	}).AnyTimes()
+
+	h := &handler{
+		requests:          requests,
+		requestCache:      requestCache,
+		clusterCache:      clusterCache,
+		projectCache:      projectCache,
+		roleTemplateCache: roleTemplateCache,
+		crtbs:             crtbs,
+		crtbCache:         crtbCache,
+		prtbs:             prtbs,
+		prtbCache:         prtbCache,
+		subjectAccessReviews: &fakeSubjectAccessReviews{
+			approvers:       map[string]bool{"u-approver": true, "u-member": true, "u-requester": true, "u-holder": true},
+			bindingCreators: map[string]bool{"u-approver": true, "u-requester": true, "u-holder": true},
+			binders:         map[string]bool{"u-approver": true},
+			admins:          map[string]bool{"u-admin": true},
+		},
+		now: func() time.Time { return *now },
+	}
+	return h, s
+}
+
+func serve(h *handler, method, target, userID string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userID}))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeRequest(t *testing.T, rec *httptest.ResponseRecorder) v3.AccessRequest {
+	var accessRequest v3.AccessRequest
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &accessRequest))
+	return accessRequest
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s := setup(t, &now)
+
+	rec := serve(h, http.MethodPost, BasePath, "u-requester", createInput{
+		ClusterName:      "c-abcde",
+		RoleTemplateName: "cluster-owner",
+		Justification:    "INC-1234: the ingress controller is down",
+		Duration:         "4h",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	created := decodeRequest(t, rec)
+	assert.Equal(t, "ar-1", created.Name)
+	assert.Equal(t, "u-requester", created.Spec.UserName)
+	assert.Equal(t, StatePending, created.Status.State)
+	assert.Equal(t, StatePending, s.requests["ar-1"].Status.State)
+
+	// The same role can't be requested twice while pending.
+	rec = serve(h, http.MethodPost, BasePath, "u-requester", createInput{
+		ClusterName:      "c-abcde",
+		RoleTemplateName: "cluster-owner",
+		Justification:    "again",
+		Duration:         "1h",
+	})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestCreateInvalid(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s := setup(t, &now)
+	t.Cleanup(func() { settings.AccessRequestMaxDurationHours.Set(settings.AccessRequestMaxDurationHours.Default) })
+	require.NoError(t, settings.AccessRequestMaxDurationHours.Set("8"))
+
+	valid := createInput{ProjectName: "c-abcde:p-fghij", RoleTemplateName: "project-member", Justification: "release", Duration: "2h"}
+	tests := []struct {
+		name       string
+		modify     func(*createInput)
+		wantStatus int
+	}{
+		{
+			name:       "cluster and project",
+			modify:     func(in *createInput) { in.ClusterName = "c-abcde" },
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid project",
+			modify:     func(in *createInput) { in.ProjectName = "p-fghij" },
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "no justification",
+			modify:     func(in *createInput) { in.Justification = " " },
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "duration too long",
+			modify:     func(in *createInput) { in.Duration = "9h" },
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid duration",
+			modify:     func(in *createInput) { in.Duration = "a day" },
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "cluster role in a project",
+			modify:     func(in *createInput) { in.RoleTemplateName = "cluster-owner" },
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "locked role",
+			modify: func(in *createInput) {
+				in.ProjectName, in.ClusterName, in.RoleTemplateName = "", "c-abcde", "locked"
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "unknown project",
+			modify:     func(in *createInput) { in.ProjectName = "c-abcde:p-other" },
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid
+			tt.modify(&input)
+			rec := serve(h, http.MethodPost, BasePath, "u-requester", input)
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+	assert.Empty(t, s.requests)
+}
+
+func TestApprove(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s := setup(t, &now)
+
+	rec := serve(h, http.MethodPost, BasePath, "u-requester", createInput{
+		ProjectName:      "c-abcde:p-fghij",
+		RoleTemplateName: "project-member",
+		Justification:    "release",
+		Duration:         "2h",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// Requesters can't approve their own requests, and approvers must be able to create the bindings.
+	rec = serve(h, http.MethodPost, BasePath+"/ar-1?action=approve", "u-requester", nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(h, http.MethodPost, BasePath+"/ar-1?action=approve", "u-member", nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, s.prtbs)
+
+	now = now.Add(10 * time.Minute)
+	rec = serve(h, http.MethodPost, BasePath+"/ar-1?action=approve", "u-approver", decisionInput{Reason: "LGTM"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	approved := decodeRequest(t, rec)
+	assert.Equal(t, StateApproved, approved.Status.State)
+	assert.Equal(t, "u-approver", approved.Status.DecidedBy)
+	assert.Equal(t, "LGTM", approved.Status.Reason)
+	assert.Equal(t, "p-fghij:ar-1", approved.Status.BindingName)
+	assert.True(t, now.Add(2*time.Hour).Equal(approved.Status.ExpiresAt.Time))
+
+	binding := s.prtbs["p-fghij:ar-1"]
+	require.NotNil(t, binding)
+	assert.Equal(t, "u-requester", binding.UserName)
+	assert.Equal(t, "c-abcde:p-fghij", binding.ProjectName)
+	assert.Equal(t, "project-member", binding.RoleTemplateName)
+	assert.Equal(t, "ar-1", binding.Labels[RequestLabel])
+	assert.True(t, now.Add(2*time.Hour).Equal(binding.ExpiresAt.Time))
+
+	// Decided requests can't be decided on again.
+	rec = serve(h, http.MethodPost, BasePath+"/ar-1?action=deny", "u-approver", nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestApproveRequiresTheRole(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s := setup(t, &now)
+
+	rec := serve(h, http.MethodPost, BasePath, "u-requester", createInput{
+		ProjectName:      "c-abcde:p-fghij",
+		RoleTemplateName: "project-member",
+		Justification:    "release",
+		Duration:         "2h",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// Approvers who can't bind the role must hold it in the project.
+	rec = serve(h, http.MethodPost, BasePath+"/ar-1?action=approve", "u-holder", nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, s.prtbs)
+
+	s.prtbs["p-fghij:holder"] = &v3.ProjectRoleTemplateBinding{
+		ObjectMeta:       metav1.ObjectMeta{Name: "holder", Namespace: "p-fghij"},
+		UserName:         "u-holder",
+		ProjectName:      "c-abcde:p-fghij",
+		RoleTemplateName: "project-member",
+	}
+	rec = serve(h, http.MethodPost, BasePath+"/ar-1?action=approve", "u-holder", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotNil(t, s.prtbs["p-fghij:ar-1"])
+}
+
+func TestDeny(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s := setup(t, &now)
+
+	rec := serve(h, http.MethodPost, BasePath, "u-requester", createInput{
+		ClusterName:      "c-abcde",
+		RoleTemplateName: "cluster-owner",
+		Justification:    "curious",
+		Duration:         "1h",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = serve(h, http.MethodPost, BasePath+"/ar-1?action=deny", "u-admin", decisionInput{Reason: "not on call"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	denied := decodeRequest(t, rec)
+	assert.Equal(t, StateDenied, denied.Status.State)
+	assert.Equal(t, "u-admin", denied.Status.DecidedBy)
+	assert.Empty(t, denied.Status.BindingName)
+	assert.Empty(t, s.crtbs)
+}
+
+func TestList(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, _ := setup(t, &now)
+
+	for _, userID := range []string{"u-requester", "u-other"} {
+		rec := serve(h, http.MethodPost, BasePath, userID, createInput{
+			ClusterName:      "c-abcde",
+			RoleTemplateName: "cluster-owner",
+			Justification:    "maintenance",
+			Duration:         "1h",
+		})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	rec := serve(h, http.MethodPost, BasePath+"/ar-1?action=deny", "u-admin", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	list := func(userID, query string) []string {
+		rec := serve(h, http.MethodGet, BasePath+query, userID, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var collection struct {
+			Data []v3.AccessRequest `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &collection))
+		var names []string
+		for _, accessRequest := range collection.Data {
+			names = append(names, accessRequest.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"ar-1"}, list("u-requester", ""))
+	assert.Equal(t, []string{"ar-2", "ar-1"}, list("u-admin", ""))
+	assert.Equal(t, []string{"ar-2"}, list("u-admin", "?state=Pending"))
+
+	// Other users' requests are hidden.
+	rec = serve(h, http.MethodGet, BasePath+"/ar-2", "u-requester", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = serve(h, http.MethodGet, BasePath+"/ar-1", "u-requester", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,319 @@
+package accessrequests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// BasePath is the path of the access request endpoints.
+	BasePath = "/v1-access-requests"
+
+	// ApproveAction approves an access request, and DenyAction denies it.
+	ApproveAction = "approve"
+	DenyAction    = "deny"
+
+	// ApproveVerb is the verb approvers must be allowed on the access requests.
+	ApproveVerb = "approve"
+
+	maxBodySize = 64 * 1024
+)
+
+// NewHandler returns the handler of the access request endpoints.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	return newHandler(mgmt).router()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodGet).Path(BasePath).HandlerFunc(h.list)
+	root.Methods(http.MethodPost).Path(BasePath).HandlerFunc(h.create)
+	root.Methods(http.MethodGet).Path(BasePath + "/{name}").HandlerFunc(h.get)
+	root.Methods(http.MethodPost).Path(BasePath+"/{name}").Queries("action", ApproveAction).HandlerFunc(h.approve)
+	root.Methods(http.MethodPost).Path(BasePath+"/{name}").Queries("action", DenyAction).HandlerFunc(h.deny)
+	return root
+}
+
+// list writes the access requests of the caller, or all of them for those allowed to list them, like the approvers,
+// most recent first. They can be filtered by state with the state query parameter.
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	listAll, err := h.authorize(r.Context(), caller, "list", "", v3.AccessRequestResourceName, "")
+	if err != nil {
+		logrus.Errorf("[accessrequests] failed to authorize user %s: %v", caller.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return
+	}
+	all, err := h.requestCache.List(labels.Everything())
+	if err != nil {
+		logrus.Errorf("[accessrequests] failed to list access requests: %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to list access requests")
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	requests := make([]*v3.AccessRequest, 0, len(all))
+	for _, accessRequest := range all {
+		if !listAll && accessRequest.Spec.UserName != caller.GetName() {
+			continue
+		}
+		if state != "" && State(accessRequest) != state {
+			continue
+		}
+		requests = append(requests, accessRequest)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[j].CreationTimestamp.Before(&requests[i].CreationTimestamp)
+	})
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"type": "collection",
+		"data": requests,
+	})
+}
+
+// get writes an access request of the caller, or any of them for those allowed to get them.
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	accessRequest, ok := h.accessRequest(w, r)
+	if !ok {
+		return
+	}
+	if accessRequest.Spec.UserName != caller.GetName() {
+		allowed, err := h.authorize(r.Context(), caller, "get", "", v3.AccessRequestResourceName, accessRequest.Name)
+		if err != nil {
+			logrus.Errorf("[accessrequests] failed to authorize user %s: %v", caller.GetName(), err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+			return
+		}
+		if !allowed {
+			util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("access request %s not found", accessRequest.Name))
+			return
+		}
+	}
+	util.WriteJSON(w, http.StatusOK, accessRequest)
+}
+
+// create creates a pending access request of the caller.
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	var input createInput
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&input); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid access request")
+		return
+	}
+	if err := input.validate(); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if status, err := h.checkTarget(input); err != nil {
+		if status == http.StatusInternalServerError {
+			logrus.Errorf("[accessrequests] failed to check the target of an access request: %v", err)
+			err = errors.New("failed to check the requested role")
+		}
+		util.ReturnHTTPError(w, r, status, err.Error())
+		return
+	}
+
+	duplicate, err := h.pendingDuplicate(caller.GetName(), input)
+	if err != nil {
+		logrus.Errorf("[accessrequests] failed to list access requests: %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to create access request")
+		return
+	}
+	if duplicate != nil {
+		util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("access request %s for the same role is pending", duplicate.Name))
+		return
+	}
+
+	accessRequest, err := h.createRequest(caller.GetName(), input)
+	if err != nil {
+		logrus.Errorf("[accessrequests] failed to create an access request of user %s: %v", caller.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to create access request")
+		return
+	}
+	logEvent(accessRequest, "AccessRequestCreated", caller.GetName())
+	util.WriteJSON(w, http.StatusCreated, accessRequest)
+}
+
+// approve approves a pending access request, binding its role to its user until it expires.
+func (h *handler) approve(w http.ResponseWriter, r *http.Request) {
+	h.decideAndWrite(w, r, true)
+}
+
+// deny denies a pending access request.
+func (h *handler) deny(w http.ResponseWriter, r *http.Request) {
+	h.decideAndWrite(w, r, false)
+}
+
+func (h *handler) decideAndWrite(w http.ResponseWriter, r *http.Request, approve bool) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	accessRequest, ok := h.accessRequest(w, r)
+	if !ok {
+		return
+	}
+	if !h.approver(w, r, caller, accessRequest) {
+		return
+	}
+	if state := State(accessRequest); state != StatePending {
+		util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("access request %s is already %s", accessRequest.Name, state))
+		return
+	}
+
+	var input decisionInput
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid decision")
+		return
+	}
+	if len(input.Reason) > maxReasonLength {
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, fmt.Sprintf("reason must be at most %d characters", maxReasonLength))
+		return
+	}
+
+	decided, err := h.decide(accessRequest, caller.GetName(), approve, input.Reason)
+	if apierrors.IsConflict(err) {
+		util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("access request %s was modified, try again", accessRequest.Name))
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[accessrequests] failed to decide on access request %s: %v", accessRequest.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to decide on access request")
+		return
+	}
+	event := "AccessRequestDenied"
+	if approve {
+		event = "AccessRequestApproved"
+	}
+	logEvent(decided, event, caller.GetName())
+	util.WriteJSON(w, http.StatusOK, decided)
+}
+
+// accessRequest returns the access request of the path. It's read from the API server rather than the cache, so that
+// decisions are made on its latest state.
+func (h *handler) accessRequest(w http.ResponseWriter, r *http.Request) (*v3.AccessRequest, bool) {
+	name := mux.Vars(r)["name"]
+	accessRequest, err := h.requests.Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("access request %s not found", name))
+		return nil, false
+	}
+	if err != nil {
+		logrus.Errorf("[accessrequests] failed to get access request %s: %v", name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get access request")
+		return nil, false
+	}
+	return accessRequest, true
+}
+
+// approver tells whether the caller can decide on the access request. Approvers must be allowed the approve verb on it,
+// to create the role bindings of its cluster or project, and must hold its role there or be allowed to bind it, so that
+// they can't grant access they couldn't give otherwise. Users can't decide on their own requests.
+func (h *handler) approver(w http.ResponseWriter, r *http.Request, caller user.Info, accessRequest *v3.AccessRequest) bool {
+	if accessRequest.Spec.UserName == caller.GetName() {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, "can't decide on your own access request")
+		return false
+	}
+	namespace, bindingResource := bindingTarget(accessRequest)
+	for _, check := range []struct{ verb, namespace, resource, name string }{
+		{ApproveVerb, "", v3.AccessRequestResourceName, accessRequest.Name},
+		{"create", namespace, bindingResource, ""},
+	} {
+		allowed, err := h.authorize(r.Context(), caller, check.verb, check.namespace, check.resource, check.name)
+		if err != nil {
+			logrus.Errorf("[accessrequests] failed to authorize user %s: %v", caller.GetName(), err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+			return false
+		}
+		if !allowed {
+			util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to decide on access request %s", accessRequest.Name))
+			return false
+		}
+	}
+
+	allowed, err := h.canGrantRole(r.Context(), caller, accessRequest)
+	if err != nil {
+		logrus.Errorf("[accessrequests] failed to authorize user %s: %v", caller.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return false
+	}
+	if !allowed {
+		util.ReturnHTTPError(w, r, http.StatusForbidden,
+			fmt.Sprintf("not allowed to bind role template %s of access request %s", accessRequest.Spec.RoleTemplateName, accessRequest.Name))
+		return false
+	}
+	return true
+}
+
+// canGrantRole tells whether the caller has the role template of the access request in the namespace of its binding, or
+// is allowed to bind it.
+func (h *handler) canGrantRole(ctx context.Context, caller user.Info, accessRequest *v3.AccessRequest) (bool, error) {
+	namespace, _ := bindingTarget(accessRequest)
+	roleTemplateName := accessRequest.Spec.RoleTemplateName
+	if accessRequest.Spec.ProjectName != "" {
+		prtbs, err := h.prtbCache.List(namespace, labels.Everything())
+		if err != nil {
+			return false, err
+		}
+		for _, prtb := range prtbs {
+			if prtb.UserName == caller.GetName() && prtb.RoleTemplateName == roleTemplateName {
+				return true, nil
+			}
+		}
+	} else {
+		crtbs, err := h.crtbCache.List(namespace, labels.Everything())
+		if err != nil {
+			return false, err
+		}
+		for _, crtb := range crtbs {
+			if crtb.UserName == caller.GetName() && crtb.RoleTemplateName == roleTemplateName {
+				return true, nil
+			}
+		}
+	}
+	return h.authorize(ctx, caller, "bind", "", v3.RoleTemplateResourceName, roleTemplateName)
+}
+
+// authorize tells whether the user can apply the verb to the resource of the management API group.
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, verb, namespace, resource, name string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:     v3.SchemeGroupVersion.Group,
+		Namespace: namespace,
+		Resource:  resource,
+		Name:      name,
+		Verb:      verb,
+	})
+}
@@ -0,0 +1,332 @@
+// Package activity keeps the recent activity of each user: their auth events, like logins, their notable writes
+// through the API, and the clusters they accessed. It's a feed bounded by the user-activity-max-entries and
+// user-activity-retention-hours settings, stored in a secret per user, that users can read for their own account and
+// admins for any user, e.g. to investigate an incident. The repeated writes to the same path and accesses to the same
+// cluster are aggregated, so that automation doesn't flood the feeds.
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// The kinds of activities.
+const (
+	// KindAuth is an auth event of the user, e.g. a login.
+	KindAuth = "auth"
+	// KindWrite is a successful create, update, patch or delete of the user through the API.
+	KindWrite = "write"
+	// KindClusterAccess is an access of the user to a downstream cluster, through the cluster proxy.
+	KindClusterAccess = "clusterAccess"
+)
+
+const (
+	secretPrefix = "user-activity-"
+	feedKey      = "activity"
+
+	// queueSize is how many activities can wait to be added to the feeds. The activities recorded while the queue is
+	// full are dropped.
+	queueSize     = 1000
+	flushInterval = 10 * time.Second
+	// aggregationWindow is how close the writes to the same path, or the accesses to the same cluster, must be to be
+	// aggregated into one activity.
+	aggregationWindow = time.Hour
+	maxDetailLen      = 256
+)
+
+// SecretName returns the name of the secret holding the activity feed of the user.
+func SecretName(userID string) string {
+	return secretPrefix + userID
+}
+
+// Activity is an entry of the activity feed of a user.
+type Activity struct {
+	Kind string `json:"kind"`
+	// Event is the type of the auth event, for the auth activities.
+	Event string `json:"event,omitempty"`
+	// Time is when the activity happened, the last time for the aggregated activities.
+	Time metav1.Time `json:"time"`
+	// Count is how many times the activity happened within the aggregation window.
+	Count    int    `json:"count,omitempty"`
+	Method   string `json:"method,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Cluster  string `json:"cluster,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	SourceIP string `json:"sourceIp,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// aggregationKey returns the key of the activities aggregated together, or "" if the activity isn't aggregated.
+func (a Activity) aggregationKey() string {
+	if a.Kind == KindAuth {
+		return ""
+	}
+	return a.Kind + "|" + a.Method + "|" + a.Path + "|" + a.Cluster + "|" + a.Detail
+}
+
+// feed is the activity feed of a user, oldest first.
+type feed struct {
+	Activities []Activity `json:"activities,omitempty"`
+}
+
+// add adds the activity to the feed, aggregating it with a previous one if it's repeated.
+func (f *feed) add(a Activity) {
+	if a.Count == 0 {
+		a.Count = 1
+	}
+	if key := a.aggregationKey(); key != "" {
+		for i := len(f.Activities) - 1; i >= 0; i-- {
+			existing := &f.Activities[i]
+			if existing.aggregationKey() != key || abs(a.Time.Sub(existing.Time.Time)) > aggregationWindow {
+				continue
+			}
+			existing.Count += a.Count
+			if a.Time.After(existing.Time.Time) {
+				existing.Time = a.Time
+				existing.SourceIP = a.SourceIP
+			}
+			return
+		}
+	}
+	f.Activities = append(f.Activities, a)
+}
+
+// trim sorts the feed and removes the activities before the cutoff, and the oldest ones beyond the max.
+func (f *feed) trim(cutoff time.Time, max int) {
+	sort.SliceStable(f.Activities, func(i, j int) bool {
+		return f.Activities[i].Time.Before(&f.Activities[j].Time)
+	})
+	first := sort.Search(len(f.Activities), func(i int) bool {
+		return !f.Activities[i].Time.Time.Before(cutoff)
+	})
+	if len(f.Activities)-first > max {
+		first = len(f.Activities) - max
+	}
+	f.Activities = f.Activities[first:]
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+type record struct {
+	userID   string
+	activity Activity
+}
+
+var (
+	queue = make(chan record, queueSize)
+	now   = time.Now
+)
+
+// Record queues the activity to be added to the feed of the user, once Start was called. It doesn't block.
+func Record(userID string, activity Activity) {
+	if userID == "" || maxEntries() <= 0 {
+		return
+	}
+	if activity.Time.IsZero() {
+		activity.Time = metav1.NewTime(now())
+	}
+	if len(activity.Detail) > maxDetailLen {
+		activity.Detail = activity.Detail[:maxDetailLen]
+	}
+	select {
+	case queue <- record{userID: userID, activity: activity}:
+	default:
+		logrus.Debugf("[activity] too many activities waiting to be recorded, dropping %s activity of user %s", activity.Kind, userID)
+	}
+}
+
+// Start adds the recorded activities to the feeds until the context is done. It must run on every replica, as they
+// all serve requests.
+func Start(ctx context.Context, scaledContext *config.ScaledContext) {
+	w := newWriter(scaledContext.Wrangler.Core.Secret(), scaledContext.Wrangler.Mgmt.User().Cache())
+	go w.run(ctx)
+}
+
+// writer adds the recorded activities to the feeds. They are aggregated in memory, and written every flushInterval.
+type writer struct {
+	secrets wcorev1.SecretClient
+	users   mgmtcontrollers.UserCache
+	now     func() time.Time
+	pending map[string]*feed
+}
+
+func newWriter(secrets wcorev1.SecretClient, users mgmtcontrollers.UserCache) *writer {
+	return &writer{
+		secrets: secrets,
+		users:   users,
+		now:     time.Now,
+		pending: map[string]*feed{},
+	}
+}
+
+func (w *writer) run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r := <-queue:
+			w.add(r)
+		case <-ticker.C:
+			w.flush()
+		}
+	}
+}
+
+func (w *writer) add(r record) {
+	f := w.pending[r.userID]
+	if f == nil {
+		f = &feed{}
+		w.pending[r.userID] = f
+	}
+	f.add(r.activity)
+	if len(f.Activities) > 2*maxEntries() {
+		f.trim(time.Time{}, maxEntries())
+	}
+}
+
+func (w *writer) flush() {
+	for userID, f := range w.pending {
+		if err := w.store(userID, f); err != nil {
+			logrus.Errorf("[activity] failed to record the activity of user %s: %v", userID, err)
+		}
+	}
+	w.pending = map[string]*feed{}
+}
+
+// store adds the activities to the feed of the user. The activities of the principals which aren't Rancher users,
+// e.g. the service accounts, aren't recorded.
+func (w *writer) store(userID string, pending *feed) error {
+	user, err := w.users.Get(userID)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	max := maxEntries()
+	if max <= 0 {
+		return nil
+	}
+	cutoff := w.now().Add(-retention())
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := w.secrets.Get(namespace.System, SecretName(userID), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("getting the activity feed: %w", err)
+		}
+		create := apierrors.IsNotFound(err)
+
+		f := &feed{}
+		if !create {
+			if f, err = parseFeed(secret); err != nil {
+				logrus.Warnf("[activity] resetting the invalid activity feed of user %s: %v", userID, err)
+				f = &feed{}
+			}
+		}
+		for _, a := range pending.Activities {
+			f.add(a)
+		}
+		f.trim(cutoff, max)
+
+		data, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		if create {
+			_, err = w.secrets.Create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      SecretName(userID),
+					Namespace: namespace.System,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: mgmtv3.UserGroupVersionKind.GroupVersion().String(),
+						Kind:       mgmtv3.UserGroupVersionKind.Kind,
+						Name:       user.Name,
+						UID:        user.UID,
+					}},
+				},
+				Data: map[string][]byte{feedKey: data},
+			})
+			if apierrors.IsAlreadyExists(err) {
+				// Another replica created it first.
+				return apierrors.NewConflict(corev1.Resource("secrets"), SecretName(userID), err)
+			}
+			return err
+		}
+		secret = secret.DeepCopy()
+		secret.Data = map[string][]byte{feedKey: data}
+		_, err = w.secrets.Update(secret)
+		return err
+	})
+}
+
+func parseFeed(secret *corev1.Secret) (*feed, error) {
+	f := &feed{}
+	if err := json.Unmarshal(secret.Data[feedKey], f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Feed returns the activities of the user within the retention, most recent first.
+func Feed(secrets wcorev1.SecretCache, userID string) ([]Activity, error) {
+	secret, err := secrets.Get(namespace.System, SecretName(userID))
+	if apierrors.IsNotFound(err) {
+		return []Activity{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f, err := parseFeed(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid activity feed: %w", err)
+	}
+	f.trim(now().Add(-retention()), len(f.Activities))
+
+	activities := make([]Activity, 0, len(f.Activities))
+	for i := len(f.Activities) - 1; i >= 0; i-- {
+		activities = append(activities, f.Activities[i])
+	}
+	return activities, nil
+}
+
+// maxEntries returns the user-activity-max-entries setting. The activity isn't recorded if it's invalid.
+func maxEntries() int {
+	max, err := strconv.Atoi(settings.UserActivityMaxEntries.Get())
+	if err != nil {
+		return 0
+	}
+	return max
+}
+
+func retention() time.Duration {
+	hours, err := strconv.ParseInt(settings.UserActivityRetentionHours.Get(), 10, 64)
+	if err != nil || hours <= 0 {
+		logrus.Errorf("[activity] invalid %s setting, using the default", settings.UserActivityRetentionHours.Name)
+		hours, _ = strconv.ParseInt(settings.UserActivityRetentionHours.Default, 10, 64)
+	}
+	return time.Duration(hours) * time.Hour
+}
@@ -0,0 +1,272 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+var start = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func at(d time.Duration) metav1.Time {
+	return metav1.NewTime(start.Add(d))
+}
+
+func TestFeedAdd(t *testing.T) {
+	f := &feed{}
+	f.add(Activity{Kind: KindAuth, Event: "LoginSucceeded", Time: at(0)})
+	f.add(Activity{Kind: KindAuth, Event: "LoginSucceeded", Time: at(time.Minute)})
+	f.add(Activity{Kind: KindClusterAccess, Cluster: "c-1", Time: at(0), SourceIP: "10.0.0.1"})
+	f.add(Activity{Kind: KindClusterAccess, Cluster: "c-1", Time: at(30 * time.Minute), SourceIP: "10.0.0.2"})
+	f.add(Activity{Kind: KindClusterAccess, Cluster: "c-2", Time: at(30 * time.Minute)})
+	f.add(Activity{Kind: KindClusterAccess, Cluster: "c-1", Time: at(2 * time.Hour)})
+
+	// The logins aren't aggregated, the accesses to the same cluster within the window are.
+	require.Len(t, f.Activities, 5)
+	assert.Equal(t, 1, f.Activities[1].Count)
+	assert.Equal(t, 2, f.Activities[2].Count)
+	assert.Equal(t, at(30*time.Minute), f.Activities[2].Time)
+	assert.Equal(t, "10.0.0.2", f.Activities[2].SourceIP)
+	assert.Equal(t, "c-2", f.Activities[3].Cluster)
+	assert.Equal(t, at(2*time.Hour), f.Activities[4].Time)
+}
+
+func TestFeedTrim(t *testing.T) {
+	f := &feed{}
+	for _, d := range []time.Duration{3 * time.Hour, 0, time.Hour, 2 * time.Hour, 4 * time.Hour} {
+		f.add(Activity{Kind: KindAuth, Time: at(d)})
+	}
+
+	f.trim(start.Add(30*time.Minute), 3)
+	require.Len(t, f.Activities, 3)
+	assert.Equal(t, at(2*time.Hour), f.Activities[0].Time)
+	assert.Equal(t, at(4*time.Hour), f.Activities[2].Time)
+
+	f.trim(start.Add(5*time.Hour), 3)
+	assert.Empty(t, f.Activities)
+}
+
+func newTestWriter(t *testing.T) (*writer, map[string]*corev1.Secret) {
+	ctrl := gomock.NewController(t)
+	stored := map[string]*corev1.Secret{}
+	gr := schema.GroupResource{Resource: "secrets"}
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(namespace.System, gomock.Any(), gomock.Any()).DoAndReturn(func(_, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		stored[secret.Name] = secret.DeepCopy()
+		return secret, nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		stored[secret.Name] = secret.DeepCopy()
+		return secret, nil
+	}).AnyTimes()
+
+	users := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	users.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.User, error) {
+		if name == "u-abc" {
+			return &v3.User{ObjectMeta: metav1.ObjectMeta{Name: name, UID: "uid"}}, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "users"}, name)
+	}).AnyTimes()
+
+	w := newWriter(secrets, users)
+	w.now = func() time.Time { return start.Add(time.Hour) }
+	return w, stored
+}
+
+func storedFeed(t *testing.T, secret *corev1.Secret) *feed {
+	f, err := parseFeed(secret)
+	require.NoError(t, err)
+	return f
+}
+
+func TestWriterStore(t *testing.T) {
+	w, stored := newTestWriter(t)
+
+	w.add(record{userID: "u-abc", activity: Activity{Kind: KindAuth, Event: "LoginSucceeded", Time: at(0)}})
+	w.add(record{userID: "u-abc", activity: Activity{Kind: KindWrite, Method: http.MethodPost, Path: "/v3/clusters", Time: at(time.Minute)}})
+	w.add(record{userID: "u-unknown", activity: Activity{Kind: KindAuth, Event: "LoginSucceeded", Time: at(0)}})
+	w.flush()
+
+	// Only the feeds of the Rancher users are stored.
+	require.Len(t, stored, 1)
+	secret := stored[SecretName("u-abc")]
+	require.NotNil(t, secret)
+	assert.Equal(t, "u-abc", secret.OwnerReferences[0].Name)
+	assert.Len(t, storedFeed(t, secret).Activities, 2)
+	assert.Empty(t, w.pending)
+
+	// The next activities are added to the stored feed, and the repeated writes aggregated.
+	w.add(record{userID: "u-abc", activity: Activity{Kind: KindWrite, Method: http.MethodPost, Path: "/v3/clusters", Time: at(2 * time.Minute)}})
+	w.flush()
+	f := storedFeed(t, stored[SecretName("u-abc")])
+	require.Len(t, f.Activities, 2)
+	assert.Equal(t, 2, f.Activities[1].Count)
+}
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	allowed map[string]bool
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	sar.Status.Allowed = f.allowed[sar.Spec.User] && sar.Spec.ResourceAttributes.Resource == v3.AuthEventResourceName
+	return sar, nil
+}
+
+func newTestHandler(t *testing.T, feeds map[string]*feed) *handler {
+	ctrl := gomock.NewController(t)
+	secretCache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+	secretCache.EXPECT().Get(namespace.System, gomock.Any()).DoAndReturn(func(_, name string) (*corev1.Secret, error) {
+		for userID, f := range feeds {
+			if SecretName(userID) == name {
+				data, err := json.Marshal(f)
+				require.NoError(t, err)
+				return &corev1.Secret{Data: map[string][]byte{feedKey: data}}, nil
+			}
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}).AnyTimes()
+	return &handler{
+		secretCache:          secretCache,
+		subjectAccessReviews: &fakeSubjectAccessReviews{allowed: map[string]bool{"u-admin": true}},
+	}
+}
+
+func list(t *testing.T, h *handler, target, userID string) (int, []Activity) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userID}))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil
+	}
+	var response struct {
+		Data []Activity `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec.Code, response.Data
+}
+
+func TestList(t *testing.T) {
+	previous := now
+	now = func() time.Time { return start.Add(time.Hour) }
+	defer func() { now = previous }()
+
+	h := newTestHandler(t, map[string]*feed{
+		"u-abc": {Activities: []Activity{
+			{Kind: KindAuth, Event: "LoginSucceeded", Time: metav1.NewTime(start.Add(-60 * 24 * time.Hour))},
+			{Kind: KindAuth, Event: "LoginSucceeded", Time: at(0)},
+			{Kind: KindClusterAccess, Cluster: "c-1", Time: at(time.Minute)},
+			{Kind: KindWrite, Method: http.MethodPut, Path: "/k8s/clusters/c-2/v1/pods", Cluster: "c-2", Time: at(2 * time.Minute)},
+		}},
+	})
+
+	// The activities beyond the retention aren't listed, the others are, most recent first.
+	code, activities := list(t, h, BasePath, "u-abc")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, activities, 3)
+	assert.Equal(t, KindWrite, activities[0].Kind)
+	assert.Equal(t, KindAuth, activities[2].Kind)
+
+	_, activities = list(t, h, BasePath+"?kind=clusterAccess", "u-abc")
+	require.Len(t, activities, 1)
+	assert.Equal(t, "c-1", activities[0].Cluster)
+
+	_, activities = list(t, h, BasePath+"?cluster=c-2", "u-abc")
+	require.Len(t, activities, 1)
+
+	_, activities = list(t, h, BasePath+"?since="+start.Add(30*time.Second).Format(time.RFC3339)+"&limit=1", "u-abc")
+	require.Len(t, activities, 1)
+	assert.Equal(t, KindWrite, activities[0].Kind)
+
+	code, _ = list(t, h, BasePath+"?since=yesterday", "u-abc")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// The users without a feed have an empty one.
+	code, activities = list(t, h, BasePath, "u-new")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, activities)
+
+	// Only those allowed to list the auth events can get the feeds of other users.
+	code, _ = list(t, h, BasePath+"/users/u-abc", "u-other")
+	assert.Equal(t, http.StatusForbidden, code)
+	code, activities = list(t, h, BasePath+"/users/u-abc", "u-admin")
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, activities, 3)
+}
+
+func drain() []record {
+	var records []record
+	for {
+		select {
+		case r := <-queue:
+			records = append(records, r)
+		default:
+			return records
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	drain()
+	status := http.StatusOK
+	handler := NewMiddleware(func(*http.Request) net.IP { return net.ParseIP("10.0.0.1") })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+	serve := func(method, target, userID string) {
+		req := httptest.NewRequest(method, target, nil)
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userID}))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	serve(http.MethodGet, "/v3/clusters", "u-abc")
+	serve(http.MethodPost, "/v3/clusters/c-1?action=generateKubeconfig&token=secret", "u-abc")
+	serve(http.MethodGet, "/k8s/clusters/c-1/v1/pods", "u-abc")
+	serve(http.MethodPost, "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", "u-abc")
+	serve(http.MethodDelete, "/k8s/clusters/c-1/v1/pods/default/web", "system:serviceaccount:default:bot")
+	status = http.StatusForbidden
+	serve(http.MethodDelete, "/v3/clusters/c-1", "u-abc")
+
+	records := drain()
+	require.Len(t, records, 2)
+	assert.Equal(t, "u-abc", records[0].userID)
+	assert.Equal(t, KindWrite, records[0].activity.Kind)
+	assert.Equal(t, http.MethodPost, records[0].activity.Method)
+	assert.Equal(t, "/v3/clusters/c-1", records[0].activity.Path)
+	assert.Equal(t, "generateKubeconfig", records[0].activity.Detail)
+	assert.Equal(t, "10.0.0.1", records[0].activity.SourceIP)
+	assert.Equal(t, KindClusterAccess, records[1].activity.Kind)
+	assert.Equal(t, "c-1", records[1].activity.Cluster)
+}
+
+func TestClusterOf(t *testing.T) {
+	assert.Equal(t, "c-1", clusterOf("/k8s/clusters/c-1/v1/pods"))
+	assert.Equal(t, "local", clusterOf("/k8s/clusters/local"))
+	assert.Equal(t, "", clusterOf("/v3/clusters/c-1"))
+}
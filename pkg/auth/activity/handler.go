@@ -0,0 +1,137 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// BasePath is the path of the activity feed endpoints.
+const BasePath = "/v1-activity"
+
+type handler struct {
+	secretCache          wcorev1.SecretCache
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewHandler returns the handler of the activity feed endpoints.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	h := &handler{
+		secretCache:          mgmt.Wrangler.Core.Secret().Cache(),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+	}
+	return h.router()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodGet).Path(BasePath).HandlerFunc(h.list)
+	root.Methods(http.MethodGet).Path(BasePath + "/users/{user}").HandlerFunc(h.list)
+	return root
+}
+
+// list writes the activity feed of the caller, or of the user of the path for those allowed to list the auth events,
+// most recent first. It can be filtered with the kind, cluster and since (RFC 3339) query parameters, and limited
+// with the limit query parameter.
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.target(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	var since time.Time
+	if value := query.Get("since"); value != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			util.ReturnHTTPError(w, r, http.StatusBadRequest, "since must be an RFC 3339 time")
+			return
+		}
+	}
+	limit := -1
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			util.ReturnHTTPError(w, r, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+	}
+
+	activities, err := Feed(h.secretCache, userID)
+	if err != nil {
+		logrus.Errorf("[activity] failed to get the activity feed of user %s: %v", userID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get the activity feed")
+		return
+	}
+	filtered := make([]Activity, 0, len(activities))
+	for _, a := range activities {
+		if limit >= 0 && len(filtered) == limit {
+			break
+		}
+		if kind := query.Get("kind"); kind != "" && a.Kind != kind {
+			continue
+		}
+		if cluster := query.Get("cluster"); cluster != "" && a.Cluster != cluster {
+			continue
+		}
+		if a.Time.Time.Before(since) {
+			continue
+		}
+		filtered = append(filtered, a)
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"type": "collection",
+		"data": filtered,
+	})
+}
+
+// target returns the user whose feed is requested: the caller, or the user of the path for those allowed to list
+// the auth events.
+func (h *handler) target(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userInfo, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return "", false
+	}
+
+	userID, ok := mux.Vars(r)["user"]
+	if !ok || userID == userInfo.GetName() {
+		return userInfo.GetName(), true
+	}
+
+	allowed, err := h.authorize(r.Context(), userInfo)
+	if err != nil {
+		logrus.Errorf("[activity] failed to authorize user %s: %v", userInfo.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return "", false
+	}
+	if !allowed {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to get the activity of user %s", userID))
+		return "", false
+	}
+	return userID, true
+}
+
+// authorize tells whether the user can list the auth events, as the activity feeds include them.
+func (h *handler) authorize(ctx context.Context, userInfo user.Info) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    v3.SchemeGroupVersion.Group,
+		Resource: v3.AuthEventResourceName,
+		Verb:     "list",
+	})
+}
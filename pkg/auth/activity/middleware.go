@@ -0,0 +1,112 @@
+package activity
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const clusterProxyPrefix = "/k8s/clusters/"
+
+// NewMiddleware returns the middleware recording the notable writes of the users, and their accesses to the
+// downstream clusters. It must be chained after the authentication filter, as it reads the user from the request
+// context. The client addresses are given by clientIP.
+func NewMiddleware(clientIP func(*http.Request) net.IP) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			userInfo, ok := request.UserFrom(req.Context())
+			// The Kubernetes users, like the service accounts, and the system users have colons in their names, the
+			// Rancher users don't.
+			if !ok || strings.Contains(userInfo.GetName(), ":") {
+				next.ServeHTTP(rw, req)
+				return
+			}
+			cluster := clusterOf(req.URL.Path)
+			write := notableWrite(req)
+			if cluster == "" && !write {
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			sw := &statusWriter{ResponseWriter: rw, status: http.StatusOK}
+			next.ServeHTTP(sw, req)
+			// The requests which failed, e.g. as they weren't allowed, aren't activities.
+			if sw.status >= http.StatusBadRequest {
+				return
+			}
+
+			var source string
+			if ip := clientIP(req); ip != nil {
+				source = ip.String()
+			}
+			if cluster != "" {
+				Record(userInfo.GetName(), Activity{Kind: KindClusterAccess, Cluster: cluster, SourceIP: source})
+			}
+			if write {
+				Record(userInfo.GetName(), Activity{
+					Kind:     KindWrite,
+					Method:   req.Method,
+					Path:     req.URL.Path,
+					Cluster:  cluster,
+					SourceIP: source,
+					// The actions are the only query parameters recorded, the others can be sensitive.
+					Detail: req.URL.Query().Get("action"),
+				})
+			}
+		})
+	}
+}
+
+// clusterOf returns the cluster of the request to the cluster proxy, or "" if it isn't one.
+func clusterOf(path string) string {
+	rest, ok := strings.CutPrefix(path, clusterProxyPrefix)
+	if !ok {
+		return ""
+	}
+	cluster, _, _ := strings.Cut(rest, "/")
+	return cluster
+}
+
+// notableWrite tells whether the request changes something. The access reviews, which the UI creates to know what
+// the users can do, and the requests to the feeds, aren't notable.
+func notableWrite(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+	return !strings.HasSuffix(req.URL.Path, "accessreviews") && !strings.HasPrefix(req.URL.Path, BasePath)
+}
+
+// statusWriter records the status of the response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("upstream ResponseWriter of type %T does not implement http.Hijacker", w.ResponseWriter)
+}
+
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,58 @@
+package group
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+)
+
+type Handler struct {
+	Members *MemberResolver
+}
+
+func (h *Handler) CollectionFormatter(apiContext *types.APIContext, collection *types.GenericCollection) {
+	if canReview := h.userCanReviewMembers(apiContext); canReview {
+		collection.AddAction(apiContext, "members")
+	}
+}
+
+func (h *Handler) Actions(actionName string, action *types.Action, apiContext *types.APIContext) error {
+	switch actionName {
+	case "members":
+		return h.members(apiContext)
+	default:
+		return errors.Errorf("bad action %v", actionName)
+	}
+}
+
+func (h *Handler) members(request *types.APIContext) error {
+	if canReview := h.userCanReviewMembers(request); !canReview {
+		return httperror.NewAPIError(httperror.PermissionDenied, "Not Allowed")
+	}
+
+	input := &apiv3.GroupMembersInput{}
+	if err := json.NewDecoder(request.Request.Body).Decode(input); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "")
+	}
+	if input.PrincipalID == "" {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "must specify principalId")
+	}
+
+	output, err := h.Members.Members(input)
+	if err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, output)
+	return nil
+}
+
+// userCanReviewMembers returns true if the user can list users, the members of groups are matched with users.
+func (h *Handler) userCanReviewMembers(request *types.APIContext) bool {
+	return request.AccessControl.CanDo(v3.UserGroupVersionKind.Group, v3.UserResource.Name, "list", request, nil, request.Schema) == nil
+}
@@ -0,0 +1,106 @@
+package group
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rancher/norman/httperror"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/userretention"
+	wrangmgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	defaultMembersLimit = 100
+	maxMembersLimit     = 1000
+)
+
+// MemberResolver lists the members of group principals from their auth provider and matches them with Rancher users.
+type MemberResolver struct {
+	userCache          wrangmgmtv3.UserCache
+	userAttributeCache wrangmgmtv3.UserAttributeCache
+	enabledProviders   func() []string
+	getMemberLister    func(string) common.GroupMemberLister
+}
+
+func NewMemberResolver(wContext *wrangler.Context) *MemberResolver {
+	return &MemberResolver{
+		userCache:          wContext.Mgmt.User().Cache(),
+		userAttributeCache: wContext.Mgmt.UserAttribute().Cache(),
+		enabledProviders:   providers.EnabledProviders,
+		getMemberLister:    providers.GetGroupMemberLister,
+	}
+}
+
+// Members returns a page of the members of the group principal, with the Rancher user of each member, if any.
+func (r *MemberResolver) Members(input *apiv3.GroupMembersInput) (*apiv3.GroupMembersOutput, error) {
+	limit := input.Limit
+	switch {
+	case limit < 0:
+		return nil, httperror.NewAPIError(httperror.InvalidBodyContent, "limit must not be negative")
+	case limit == 0:
+		limit = defaultMembersLimit
+	case limit > maxMembersLimit:
+		limit = maxMembersLimit
+	}
+
+	providerName := providers.PrincipalProvider(input.PrincipalID)
+	if !slices.Contains(r.enabledProviders(), providerName) {
+		return nil, httperror.NewAPIError(httperror.InvalidOption, fmt.Sprintf("auth provider of %s is not enabled", input.PrincipalID))
+	}
+	lister := r.getMemberLister(providerName)
+	if lister == nil {
+		return nil, httperror.NewAPIError(httperror.InvalidOption, fmt.Sprintf("auth provider %s can't list group members", providerName))
+	}
+
+	principals, next, err := lister.ListGroupMembers(input.PrincipalID, limit, input.Continue)
+	if err != nil {
+		return nil, httperror.WrapAPIError(err, httperror.ServerError, "error listing group members")
+	}
+
+	users, err := r.userCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing users: %w", err)
+	}
+	usersByPrincipal := map[string]*apiv3.User{}
+	for _, user := range users {
+		for _, principalID := range user.PrincipalIDs {
+			usersByPrincipal[principalID] = user
+		}
+	}
+
+	output := &apiv3.GroupMembersOutput{
+		PrincipalID: input.PrincipalID,
+		Members:     make([]apiv3.GroupMemberInfo, 0, len(principals)),
+		Continue:    next,
+	}
+	for _, principal := range principals {
+		member := apiv3.GroupMemberInfo{
+			PrincipalID: principal.Name,
+			DisplayName: principal.DisplayName,
+			LoginName:   principal.LoginName,
+		}
+
+		if user, ok := usersByPrincipal[principal.Name]; ok {
+			member.UserID = user.Name
+			member.Active = (user.Enabled == nil || *user.Enabled) && user.Labels[userretention.InactiveLabelKey] == ""
+
+			attribs, err := r.userAttributeCache.Get(user.Name)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("error getting user attributes of %s: %w", user.Name, err)
+			}
+			if err == nil && attribs.LastLogin != nil {
+				member.LastLogin = attribs.LastLogin.DeepCopy()
+			}
+		}
+
+		output.Members = append(output.Members, member)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,153 @@
+package group
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/userretention"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+)
+
+type fakeMemberLister struct {
+	members map[string][]v3.Principal
+	limits  []int
+}
+
+func (f *fakeMemberLister) ListGroupMembers(principalID string, limit int, continueToken string) ([]v3.Principal, string, error) {
+	f.limits = append(f.limits, limit)
+	members, ok := f.members[principalID]
+	if !ok {
+		return nil, "", fmt.Errorf("group %s not found", principalID)
+	}
+	return common.PagePrincipals(members, limit, continueToken)
+}
+
+func TestMembers(t *testing.T) {
+	const devs = "openldap_group://cn=devs"
+	lastLogin := metav1.NewTime(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	principal := func(uid string) v3.Principal {
+		return v3.Principal{
+			ObjectMeta:  metav1.ObjectMeta{Name: "openldap_user://uid=" + uid},
+			DisplayName: uid,
+			LoginName:   uid,
+		}
+	}
+	lister := &fakeMemberLister{members: map[string][]v3.Principal{
+		devs: {principal("alice"), principal("bob"), principal("carol"), principal("dave")},
+	}}
+
+	ctrl := gomock.NewController(t)
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+
+	userCache.EXPECT().List(labels.Everything()).Return([]*v3.User{
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-alice"},
+			PrincipalIDs: []string{"openldap_user://uid=alice", "local://u-alice"},
+		},
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-bob"},
+			PrincipalIDs: []string{"openldap_user://uid=bob"},
+			Enabled:      pointer.Bool(false),
+		},
+		{
+			ObjectMeta:   metav1.ObjectMeta{Name: "u-carol", Labels: map[string]string{userretention.InactiveLabelKey: "true"}},
+			PrincipalIDs: []string{"openldap_user://uid=carol"},
+		},
+	}, nil).Times(2)
+	userAttributeCache.EXPECT().Get("u-alice").Return(&v3.UserAttribute{LastLogin: &lastLogin}, nil)
+	userAttributeCache.EXPECT().Get("u-bob").Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "u-bob"))
+	userAttributeCache.EXPECT().Get("u-carol").Return(&v3.UserAttribute{}, nil)
+
+	resolver := &MemberResolver{
+		userCache:          userCache,
+		userAttributeCache: userAttributeCache,
+		enabledProviders:   func() []string { return []string{"openldap"} },
+		getMemberLister: func(providerName string) common.GroupMemberLister {
+			if providerName == "openldap" {
+				return lister
+			}
+			return nil
+		},
+	}
+
+	output, err := resolver.Members(&v3.GroupMembersInput{PrincipalID: devs, Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, devs, output.PrincipalID)
+	assert.Equal(t, "3", output.Continue)
+	assert.Equal(t, []v3.GroupMemberInfo{
+		{PrincipalID: "openldap_user://uid=alice", DisplayName: "alice", LoginName: "alice", UserID: "u-alice", Active: true, LastLogin: &lastLogin},
+		{PrincipalID: "openldap_user://uid=bob", DisplayName: "bob", LoginName: "bob", UserID: "u-bob"},
+		{PrincipalID: "openldap_user://uid=carol", DisplayName: "carol", LoginName: "carol", UserID: "u-carol"},
+	}, output.Members)
+
+	output, err = resolver.Members(&v3.GroupMembersInput{PrincipalID: devs, Continue: output.Continue})
+	require.NoError(t, err)
+	assert.Empty(t, output.Continue)
+	assert.Equal(t, []v3.GroupMemberInfo{
+		{PrincipalID: "openldap_user://uid=dave", DisplayName: "dave", LoginName: "dave"},
+	}, output.Members)
+
+	assert.Equal(t, []int{3, defaultMembersLimit}, lister.limits)
+}
+
+func TestMembersErrors(t *testing.T) {
+	resolver := &MemberResolver{
+		enabledProviders: func() []string { return []string{"openldap", "github"} },
+		getMemberLister: func(providerName string) common.GroupMemberLister {
+			if providerName == "openldap" {
+				return &fakeMemberLister{}
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    *v3.GroupMembersInput
+		wantCode httperror.ErrorCode
+	}{
+		{
+			name:     "negative limit",
+			input:    &v3.GroupMembersInput{PrincipalID: "openldap_group://cn=devs", Limit: -1},
+			wantCode: httperror.InvalidBodyContent,
+		},
+		{
+			name:     "provider not enabled",
+			input:    &v3.GroupMembersInput{PrincipalID: "activedirectory_group://CN=devs"},
+			wantCode: httperror.InvalidOption,
+		},
+		{
+			name:     "provider can't list members",
+			input:    &v3.GroupMembersInput{PrincipalID: "github_team://1234"},
+			wantCode: httperror.InvalidOption,
+		},
+		{
+			name:     "group not found",
+			input:    &v3.GroupMembersInput{PrincipalID: "openldap_group://cn=gone"},
+			wantCode: httperror.ServerError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := resolver.Members(test.input)
+			require.Error(t, err)
+			apiErr, ok := err.(*httperror.APIError)
+			require.True(t, ok)
+			assert.Equal(t, test.wantCode, apiErr.Code)
+		})
+	}
+}
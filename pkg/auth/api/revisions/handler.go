@@ -0,0 +1,133 @@
+package revisions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ActionRevisions lists the revisions of an auth config.
+	ActionRevisions = "revisions"
+	// ActionRollback rolls an auth config back to one of its revisions.
+	ActionRollback = "rollback"
+)
+
+// CustomizeSchema records the changes made through the actions of an auth config type and adds the actions
+// for listing its revisions and rolling it back.
+func (h *History) CustomizeSchema(schema *types.Schema) {
+	if schema.ResourceActions == nil {
+		schema.ResourceActions = map[string]types.Action{}
+	}
+	schema.ResourceActions[ActionRevisions] = types.Action{Output: "authConfigRevisionsOutput"}
+	schema.ResourceActions[ActionRollback] = types.Action{Input: "authConfigRollbackInput"}
+
+	formatter := schema.Formatter
+	schema.Formatter = func(apiContext *types.APIContext, resource *types.RawResource) {
+		if formatter != nil {
+			formatter(apiContext, resource)
+		}
+		resource.AddAction(apiContext, ActionRevisions)
+		resource.AddAction(apiContext, ActionRollback)
+	}
+
+	actionHandler := schema.ActionHandler
+	schema.ActionHandler = func(actionName string, action *types.Action, request *types.APIContext) error {
+		switch actionName {
+		case ActionRevisions:
+			return h.revisions(request)
+		case ActionRollback:
+			return h.rollback(request)
+		}
+		if actionHandler == nil {
+			return httperror.NewAPIError(httperror.ActionNotAvailable, "")
+		}
+
+		h.recordBaseline(request.ID)
+		if err := actionHandler(actionName, action, request); err != nil {
+			return err
+		}
+		h.record(request.ID, request.Request.Header.Get("Impersonate-User"))
+		return nil
+	}
+}
+
+// Wrap records the changes of auth configs updated through the store.
+func (h *History) Wrap(store types.Store) types.Store {
+	return &Store{
+		Store:   store,
+		History: h,
+	}
+}
+
+// Store records a revision of an auth config after each update.
+type Store struct {
+	types.Store
+	History *History
+}
+
+func (s *Store) Update(apiContext *types.APIContext, schema *types.Schema, data map[string]interface{}, id string) (map[string]interface{}, error) {
+	s.History.recordBaseline(id)
+	result, err := s.Store.Update(apiContext, schema, data, id)
+	if err != nil {
+		return nil, err
+	}
+	s.History.record(id, apiContext.Request.Header.Get("Impersonate-User"))
+	return result, nil
+}
+
+func (h *History) revisions(request *types.APIContext) error {
+	revisions, err := h.List(request.ID)
+	if err != nil {
+		return err
+	}
+	request.WriteResponse(http.StatusOK, map[string]any{
+		"type":      "authConfigRevisionsOutput",
+		"revisions": revisions,
+	})
+	return nil
+}
+
+func (h *History) rollback(request *types.APIContext) error {
+	if err := request.AccessControl.CanDo(v3.AuthConfigGroupVersionKind.Group, v3.AuthConfigResource.Name, "update", request, nil, request.Schema); err != nil {
+		return err
+	}
+
+	input := &apiv3.AuthConfigRollbackInput{}
+	if err := json.NewDecoder(request.Request.Body).Decode(input); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent,
+			fmt.Sprintf("Failed to parse body: %v", err))
+	}
+	if input.Revision <= 0 {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "revision must be positive")
+	}
+
+	author := request.Request.Header.Get("Impersonate-User")
+	if err := h.Rollback(request.ID, author, input.Revision); err != nil {
+		return httperror.WrapAPIError(err, httperror.ServerError, "failed to roll back the auth config")
+	}
+	logrus.Infof("Auth config %s rolled back to revision %d by %s", request.ID, input.Revision, author)
+
+	request.WriteResponse(http.StatusOK, nil)
+	return nil
+}
+
+// recordBaseline records the configuration of an auth config before its first change. Failures don't block changes,
+// since a broken auth config must always be fixable.
+func (h *History) recordBaseline(name string) {
+	if err := h.RecordBaseline(name); err != nil {
+		logrus.Warnf("[revisions] failed to record the baseline revision of auth config %s: %v", name, err)
+	}
+}
+
+func (h *History) record(name, author string) {
+	if err := h.Record(name, author, 0); err != nil {
+		logrus.Warnf("[revisions] failed to record a revision of auth config %s: %v", name, err)
+	}
+}
@@ -0,0 +1,404 @@
+// Package revisions keeps a history of the changes of auth configs, so that a bad change can be rolled back
+// instead of being reconstructed from memory.
+package revisions
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/api/secrets"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// revisionOfLabel is set on the config maps holding revisions to the name of their auth config.
+	revisionOfLabel = "auth.cattle.io/authconfig-revision-of"
+	revisionKey     = "revision"
+	configKey       = "config"
+
+	defaultHistoryLimit = 20
+	createAttempts      = 5
+)
+
+// preservedFields are the fields of an auth config that aren't part of its configuration.
+var preservedFields = []string{"apiVersion", "kind", "metadata", "status", client.AuthConfigFieldType}
+
+type configMapClient interface {
+	Create(*corev1.ConfigMap) (*corev1.ConfigMap, error)
+	List(namespace string, opts metav1.ListOptions) (*corev1.ConfigMapList, error)
+	Delete(namespace, name string, options *metav1.DeleteOptions) error
+}
+
+type authConfigsClient interface {
+	Get(name string, opts metav1.GetOptions) (runtime.Object, error)
+	Update(name string, o runtime.Object) (runtime.Object, error)
+}
+
+// History records the revisions of auth configs in config maps.
+type History struct {
+	configMaps  configMapClient
+	authConfigs authConfigsClient
+	now         func() time.Time
+}
+
+// NewHistory returns a History that reads and updates auth configs with the given unstructured client.
+func NewHistory(configMaps configMapClient, authConfigs authConfigsClient) *History {
+	return &History{
+		configMaps:  configMaps,
+		authConfigs: authConfigs,
+		now:         time.Now,
+	}
+}
+
+type revision struct {
+	v3.AuthConfigRevision
+	config map[string]any
+}
+
+// RecordBaseline records the current configuration of an auth config if it has no revisions yet,
+// so that the first change made through the API can be rolled back.
+func (h *History) RecordBaseline(name string) error {
+	revisions, err := h.list(name)
+	if err != nil {
+		return err
+	}
+	if len(revisions) > 0 {
+		return nil
+	}
+	return h.Record(name, "", 0)
+}
+
+// Record records the current configuration of an auth config as a new revision made by author,
+// unless it's the same as the configuration of the latest revision.
+func (h *History) Record(name, author string, rolledBackTo int64) error {
+	content, err := h.getContent(name)
+	if err != nil {
+		return err
+	}
+	config, err := snapshot(content)
+	if err != nil {
+		return err
+	}
+	encodedConfig, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		revisions, err := h.list(name)
+		if err != nil {
+			return err
+		}
+
+		next := v3.AuthConfigRevision{
+			Revision:     1,
+			Author:       author,
+			Timestamp:    h.now().UTC().Format(time.RFC3339),
+			RolledBackTo: rolledBackTo,
+		}
+		if len(revisions) > 0 {
+			latest := revisions[0]
+			if reflect.DeepEqual(latest.config, config) {
+				return nil
+			}
+			next.Revision = latest.Revision + 1
+			next.Diff = diff(latest.config, config)
+		} else {
+			next.Diff = diff(nil, config)
+		}
+
+		encodedRevision, err := json.Marshal(next)
+		if err != nil {
+			return err
+		}
+		_, err = h.configMaps.Create(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configMapName(name, next.Revision),
+				Namespace: namespace.GlobalNamespace,
+				Labels:    map[string]string{revisionOfLabel: name},
+			},
+			Data: map[string]string{
+				revisionKey: string(encodedRevision),
+				configKey:   string(encodedConfig),
+			},
+		})
+		if apierrors.IsAlreadyExists(err) && attempt < createAttempts {
+			// Another change was recorded concurrently, diff against it instead.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record revision %d of auth config %s: %w", next.Revision, name, err)
+		}
+
+		h.prune(name, append([]revision{{AuthConfigRevision: next}}, revisions...))
+		return nil
+	}
+}
+
+// List returns the revisions of an auth config, newest first.
+func (h *History) List(name string) ([]v3.AuthConfigRevision, error) {
+	revisions, err := h.list(name)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]v3.AuthConfigRevision, 0, len(revisions))
+	for _, r := range revisions {
+		result = append(result, r.AuthConfigRevision)
+	}
+	return result, nil
+}
+
+// Rollback restores the configuration of an auth config from one of its revisions and records the rollback
+// as a new revision made by author. Whether the auth config is enabled and its secrets are left as they are,
+// since secrets aren't versioned.
+func (h *History) Rollback(name, author string, target int64) error {
+	revisions, err := h.list(name)
+	if err != nil {
+		return err
+	}
+	var config map[string]any
+	for _, r := range revisions {
+		if r.Revision == target {
+			config = r.config
+			break
+		}
+	}
+	if config == nil {
+		return fmt.Errorf("revision %d of auth config %s not found", target, name)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := h.authConfigs.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		// The auth config is updated unstructured, since the AuthConfig type lacks the fields of the provider.
+		u, ok := obj.(runtime.Unstructured)
+		if !ok {
+			return fmt.Errorf("failed to read unstructured data for AuthConfig %s", name)
+		}
+		current := u.UnstructuredContent()
+		u.SetUnstructuredContent(restore(current, config))
+		_, err = h.authConfigs.Update(name, u)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to roll back auth config %s to revision %d: %w", name, target, err)
+	}
+
+	return h.Record(name, author, target)
+}
+
+func (h *History) getContent(name string) (map[string]any, error) {
+	obj, err := h.authConfigs.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	u, ok := obj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("failed to read unstructured data for AuthConfig %s", name)
+	}
+	return u.UnstructuredContent(), nil
+}
+
+// list returns the revisions of an auth config, newest first.
+func (h *History) list(name string) ([]revision, error) {
+	configMaps, err := h.configMaps.List(namespace.GlobalNamespace, metav1.ListOptions{
+		LabelSelector: labels.Set{revisionOfLabel: name}.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the revisions of auth config %s: %w", name, err)
+	}
+
+	revisions := make([]revision, 0, len(configMaps.Items))
+	for _, configMap := range configMaps.Items {
+		var r revision
+		if err := json.Unmarshal([]byte(configMap.Data[revisionKey]), &r.AuthConfigRevision); err != nil {
+			logrus.Warnf("[revisions] skipping invalid revision %s: %v", configMap.Name, err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(configMap.Data[configKey]), &r.config); err != nil {
+			logrus.Warnf("[revisions] skipping invalid revision %s: %v", configMap.Name, err)
+			continue
+		}
+		revisions = append(revisions, r)
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// prune deletes the oldest revisions beyond the history limit.
+func (h *History) prune(name string, revisions []revision) {
+	limit := settings.AuthConfigRevisionHistoryLimit.GetInt()
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	for i := limit; i < len(revisions); i++ {
+		err := h.configMaps.Delete(namespace.GlobalNamespace, configMapName(name, revisions[i].Revision), &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			logrus.Warnf("[revisions] failed to delete revision %d of auth config %s: %v", revisions[i].Revision, name, err)
+		}
+	}
+}
+
+func configMapName(name string, revision int64) string {
+	return "authconfig-" + strings.ToLower(name) + "-" + strconv.FormatInt(revision, 10)
+}
+
+// secretField returns whether a top level field of an auth config of the given type holds a secret.
+func secretField(kind, field string) bool {
+	for _, f := range secrets.TypeToFields[kind] {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot returns the configuration of an auth config, without its metadata, status and secrets.
+func snapshot(content map[string]any) (map[string]any, error) {
+	kind, _ := content[client.AuthConfigFieldType].(string)
+
+	filtered := make(map[string]any, len(content))
+	for field, value := range content {
+		if isPreserved(field) || secretField(kind, field) {
+			continue
+		}
+		filtered[field] = value
+	}
+
+	// Copy through JSON, so that the snapshot compares equal to the ones read back from config maps.
+	data, err := json.Marshal(filtered)
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]any{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	for subField, fields := range secrets.SubTypeToFields[kind] {
+		subConfig, ok := config[subField].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, field := range fields {
+			delete(subConfig, field)
+		}
+	}
+	return config, nil
+}
+
+// restore returns the content of an auth config with the configuration of a snapshot,
+// keeping its metadata, status, secrets and whether it's enabled.
+func restore(current, config map[string]any) map[string]any {
+	kind, _ := current[client.AuthConfigFieldType].(string)
+
+	restored := make(map[string]any, len(config))
+	for field, value := range config {
+		if field == client.AuthConfigFieldEnabled {
+			continue
+		}
+		restored[field] = runtime.DeepCopyJSONValue(value)
+	}
+	for field, value := range current {
+		if isPreserved(field) || secretField(kind, field) || field == client.AuthConfigFieldEnabled {
+			restored[field] = value
+		}
+	}
+	for subField, fields := range secrets.SubTypeToFields[kind] {
+		currentSubConfig, ok := current[subField].(map[string]any)
+		if !ok {
+			continue
+		}
+		subConfig, ok := restored[subField].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, field := range fields {
+			if value, ok := currentSubConfig[field]; ok {
+				subConfig[field] = value
+			}
+		}
+	}
+	return restored
+}
+
+func isPreserved(field string) bool {
+	for _, f := range preservedFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// diff returns the changes between two configurations, with the fields of nested objects flattened.
+func diff(before, after map[string]any) []v3.AuthConfigFieldChange {
+	var changes []v3.AuthConfigFieldChange
+	diffInto(&changes, "", before, after)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+func diffInto(changes *[]v3.AuthConfigFieldChange, prefix string, before, after map[string]any) {
+	fields := map[string]bool{}
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	for field := range fields {
+		path := prefix + field
+		oldValue, hadOld := before[field]
+		newValue, hasNew := after[field]
+
+		oldMap, oldIsMap := oldValue.(map[string]any)
+		newMap, newIsMap := newValue.(map[string]any)
+		if (oldIsMap || !hadOld) && (newIsMap || !hasNew) && (oldIsMap || newIsMap) {
+			diffInto(changes, path+".", oldMap, newMap)
+			continue
+		}
+		if hadOld && hasNew && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		change := v3.AuthConfigFieldChange{Field: path}
+		if hadOld {
+			change.Old = encode(oldValue)
+		}
+		if hasNew {
+			change.New = encode(newValue)
+		}
+		*changes = append(*changes, change)
+	}
+}
+
+func encode(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,171 @@
+package revisions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeConfigMaps struct {
+	objects map[string]*corev1.ConfigMap
+}
+
+func (f *fakeConfigMaps) Create(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	if _, ok := f.objects[configMap.Name]; ok {
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, configMap.Name)
+	}
+	f.objects[configMap.Name] = configMap.DeepCopy()
+	return configMap, nil
+}
+
+func (f *fakeConfigMaps) List(_ string, opts metav1.ListOptions) (*corev1.ConfigMapList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	list := &corev1.ConfigMapList{}
+	for _, configMap := range f.objects {
+		if selector.Matches(labels.Set(configMap.Labels)) {
+			list.Items = append(list.Items, *configMap.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeConfigMaps) Delete(_, name string, _ *metav1.DeleteOptions) error {
+	delete(f.objects, name)
+	return nil
+}
+
+type fakeAuthConfigs struct {
+	objects map[string]*unstructured.Unstructured
+}
+
+func (f *fakeAuthConfigs) Get(name string, _ metav1.GetOptions) (runtime.Object, error) {
+	obj, ok := f.objects[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (f *fakeAuthConfigs) Update(name string, o runtime.Object) (runtime.Object, error) {
+	f.objects[name] = o.(*unstructured.Unstructured).DeepCopy()
+	return o, nil
+}
+
+func newTestHistory() (*History, *fakeConfigMaps, *fakeAuthConfigs) {
+	configMaps := &fakeConfigMaps{objects: map[string]*corev1.ConfigMap{}}
+	authConfigs := &fakeAuthConfigs{objects: map[string]*unstructured.Unstructured{
+		"okta": {Object: map[string]any{
+			"metadata":         map[string]any{"name": "okta"},
+			"type":             "oktaConfig",
+			"enabled":          true,
+			"spKey":            "cattle-global-data:oktaconfig-spkey",
+			"uidField":         "uid",
+			"groupsField":      "groups",
+			"openLdapConfig":   map[string]any{"servers": []any{"ldap.example.com"}, "serviceAccountPassword": "cattle-global-data:oktaconfig-serviceaccountpassword"},
+			"displayNameField": "displayName",
+			"status":           map[string]any{"conditions": []any{}},
+		}},
+	}}
+	history := NewHistory(configMaps, authConfigs)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	history.now = func() time.Time { return now }
+	return history, configMaps, authConfigs
+}
+
+func TestRecord(t *testing.T) {
+	history, configMaps, authConfigs := newTestHistory()
+
+	require.NoError(t, history.RecordBaseline("okta"))
+	require.NoError(t, history.RecordBaseline("okta"))
+	require.Len(t, configMaps.objects, 1)
+	assert.NotContains(t, configMaps.objects["authconfig-okta-1"].Data[configKey], "cattle-global-data", "secrets must not be recorded")
+
+	// Unchanged configurations aren't recorded.
+	require.NoError(t, history.Record("okta", "u-admin", 0))
+	require.Len(t, configMaps.objects, 1)
+
+	content := authConfigs.objects["okta"].Object
+	content["uidField"] = "email"
+	content["spKey"] = "cattle-global-data:oktaconfig-spkey-2"
+	content["openLdapConfig"].(map[string]any)["servers"] = []any{"ldap2.example.com"}
+	delete(content, "displayNameField")
+	require.NoError(t, history.Record("okta", "u-admin", 0))
+
+	revisions, err := history.List("okta")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, v3.AuthConfigRevision{
+		Revision:  2,
+		Author:    "u-admin",
+		Timestamp: "2024-05-01T12:00:00Z",
+		Diff: []v3.AuthConfigFieldChange{
+			{Field: "displayNameField", Old: `"displayName"`},
+			{Field: "openLdapConfig.servers", Old: `["ldap.example.com"]`, New: `["ldap2.example.com"]`},
+			{Field: "uidField", Old: `"uid"`, New: `"email"`},
+		},
+	}, revisions[0])
+	assert.Equal(t, int64(1), revisions[1].Revision)
+	assert.Empty(t, revisions[1].Author)
+}
+
+func TestRecordPrunes(t *testing.T) {
+	history, configMaps, authConfigs := newTestHistory()
+
+	for i := 0; i < defaultHistoryLimit+5; i++ {
+		authConfigs.objects["okta"].Object["uidField"] = int64(i)
+		require.NoError(t, history.Record("okta", "u-admin", 0))
+	}
+
+	assert.Len(t, configMaps.objects, defaultHistoryLimit)
+	assert.NotContains(t, configMaps.objects, "authconfig-okta-5")
+	assert.Contains(t, configMaps.objects, "authconfig-okta-6")
+}
+
+func TestRollback(t *testing.T) {
+	history, _, authConfigs := newTestHistory()
+	require.NoError(t, history.RecordBaseline("okta"))
+
+	content := authConfigs.objects["okta"].Object
+	content["uidField"] = "email"
+	content["enabled"] = false
+	content["spKey"] = "cattle-global-data:oktaconfig-spkey-2"
+	content["openLdapConfig"] = map[string]any{"serviceAccountPassword": "cattle-global-data:oktaconfig-serviceaccountpassword-2"}
+	delete(content, "groupsField")
+	require.NoError(t, history.Record("okta", "u-admin", 0))
+
+	require.NoError(t, history.Rollback("okta", "u-other", 1))
+
+	content = authConfigs.objects["okta"].Object
+	assert.Equal(t, "uid", content["uidField"])
+	assert.Equal(t, "groups", content["groupsField"])
+	assert.Equal(t, false, content["enabled"], "rollbacks must not enable or disable the auth config")
+	assert.Equal(t, "cattle-global-data:oktaconfig-spkey-2", content["spKey"], "secrets must be kept")
+	assert.Equal(t, map[string]any{
+		"servers":                []any{"ldap.example.com"},
+		"serviceAccountPassword": "cattle-global-data:oktaconfig-serviceaccountpassword-2",
+	}, content["openLdapConfig"])
+	assert.Equal(t, map[string]any{"name": "okta"}, content["metadata"])
+
+	revisions, err := history.List("okta")
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, int64(3), revisions[0].Revision)
+	assert.Equal(t, "u-other", revisions[0].Author)
+	assert.Equal(t, int64(1), revisions[0].RolledBackTo)
+
+	assert.Error(t, history.Rollback("okta", "u-other", 10))
+}
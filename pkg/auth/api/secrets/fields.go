@@ -10,7 +10,7 @@
 	TypeToFields = map[string][]string{
 		client.GithubConfigType:          {client.GithubConfigFieldClientSecret},
 		client.ActiveDirectoryConfigType: {client.ActiveDirectoryConfigFieldServiceAccountPassword},
-		client.AzureADConfigType:         {client.AzureADConfigFieldApplicationSecret},
+		client.AzureADConfigType:         {client.AzureADConfigFieldApplicationSecret, client.AzureADConfigFieldApplicationCertificate},
 		client.OpenLdapConfigType:        {client.LdapConfigFieldServiceAccountPassword},
 		client.FreeIpaConfigType:         {client.LdapConfigFieldServiceAccountPassword},
 		client.PingConfigType:            {client.PingConfigFieldSpKey},
@@ -22,6 +22,8 @@
 		client.OIDCConfigType:            {client.OIDCConfigFieldPrivateKey, client.OIDCConfigFieldClientSecret},
 		client.KeyCloakOIDCConfigType:    {client.KeyCloakOIDCConfigFieldPrivateKey, client.KeyCloakOIDCConfigFieldClientSecret},
 		client.GenericOIDCConfigType:     {client.GenericOIDCConfigFieldPrivateKey, client.GenericOIDCConfigFieldClientSecret},
+		client.ExternalAuthConfigType:    {client.ExternalAuthConfigFieldClientKey, client.ExternalAuthConfigFieldHMACSecret},
+		client.KerberosConfigType:        {client.KerberosConfigFieldKeytab},
 	}
 	// SubTypeToFields associates an Auth Config type with a nested map of secret names related to the config.
 	SubTypeToFields = map[string]map[string][]string{
@@ -30,6 +32,7 @@
 		},
 		client.OKTAConfigType: {
 			client.OKTAConfigFieldOpenLdapConfig: {client.LdapConfigFieldServiceAccountPassword},
+			client.OKTAConfigFieldOktaAPIConfig:  {client.OktaAPIFieldsFieldAPIToken},
 		},
 	}
 
@@ -9,11 +9,14 @@
 	"github.com/rancher/norman/store/crd"
 	"github.com/rancher/norman/types"
 	"github.com/rancher/rancher/pkg/api/scheme"
+	"github.com/rancher/rancher/pkg/auth/api/group"
 	"github.com/rancher/rancher/pkg/auth/api/user"
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
 	"github.com/rancher/rancher/pkg/auth/principals"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
 	"github.com/rancher/rancher/pkg/auth/providers"
 	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/tokens"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	exttokenstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
 	managementschema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
@@ -25,6 +28,7 @@ func Setup(ctx context.Context, clusterRouter requests.ClusterRouter, scaledCont
 	providers.SetupAuthConfig(ctx, scaledContext, schemas)
 	user.SetUserStore(schemas.Schema(&managementschema.Version, client.UserType), scaledContext)
 	User(ctx, schemas, scaledContext)
+	Group(schemas, scaledContext)
 }
 
 func User(ctx context.Context, schemas *types.Schemas, management *config.ScaledContext) {
@@ -35,7 +39,13 @@ func User(ctx context.Context, schemas *types.Schemas, management *config.Scaled
 		UserClient:               management.Management.Users(""),
 		GlobalRoleBindingsClient: management.Management.GlobalRoleBindings(""),
 		UserAuthRefresher:        providerrefresh.NewUserAuthRefresher(ctx, management),
+		UserManager:              management.UserManager,
+		UserMerger:               user.NewUserMerger(management.Wrangler),
+		UserData:                 user.NewUserDataManager(management.Wrangler),
+		LoginAs:                  user.NewLoginAsManager(management.Wrangler, tokens.NewManager(ctx, management)),
 		ExtTokenStore:            extTokenStore,
+		PasswordHistory:          passwordpolicy.NewHistory(management.Wrangler.Core.Secret()),
+		GlobalPermissions:        user.NewGlobalPermissions(management.Wrangler),
 	}
 
 	schema.Formatter = handler.UserFormatter
@@ -43,6 +53,16 @@ func User(ctx context.Context, schemas *types.Schemas, management *config.Scaled
 	schema.ActionHandler = handler.Actions
 }
 
+func Group(schemas *types.Schemas, management *config.ScaledContext) {
+	schema := schemas.Schema(&managementschema.Version, client.GroupType)
+	handler := &group.Handler{
+		Members: group.NewMemberResolver(management.Wrangler),
+	}
+
+	schema.CollectionFormatter = handler.CollectionFormatter
+	schema.ActionHandler = handler.Actions
+}
+
 func NewNormanServer(ctx context.Context, clusterRouter requests.ClusterRouter, scaledContext *config.ScaledContext) (http.Handler, error) {
 	schemas, err := newSchemas(ctx, scaledContext)
 	if err != nil {
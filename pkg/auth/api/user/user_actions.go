@@ -1,20 +1,25 @@
 package user
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/pkg/errors"
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/parse"
 	"github.com/rancher/norman/types"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	exttokenstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
 	"github.com/rancher/rancher/pkg/settings"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/rancher/rancher/pkg/user"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,10 +29,23 @@ func (h *Handler) UserFormatter(apiContext *types.APIContext, resource *types.Ra
 	if canRefresh := h.userCanRefresh(apiContext); canRefresh {
 		resource.AddAction(apiContext, "refreshauthprovideraccess")
 	}
+	if canManageData := h.userCanManageData(apiContext); canManageData {
+		resource.AddAction(apiContext, "exportdata")
+		resource.AddAction(apiContext, "erasedata")
+	}
+	if canLoginAs := h.userCanLoginAs(apiContext); canLoginAs {
+		resource.AddAction(apiContext, "loginas")
+	}
 }
 
 func (h *Handler) CollectionFormatter(apiContext *types.APIContext, collection *types.GenericCollection) {
 	collection.AddAction(apiContext, "changepassword")
+	collection.AddAction(apiContext, "linkprincipal")
+	collection.AddAction(apiContext, "unlinkprincipal")
+	if canMerge := h.userCanMerge(apiContext); canMerge {
+		collection.AddAction(apiContext, "detectduplicates")
+		collection.AddAction(apiContext, "mergeusers")
+	}
 	if canRefresh := h.userCanRefresh(apiContext); canRefresh {
 		collection.AddAction(apiContext, "refreshauthprovideraccess")
 	}
@@ -37,7 +55,13 @@ type Handler struct {
 	UserClient               v3.UserInterface
 	GlobalRoleBindingsClient v3.GlobalRoleBindingInterface
 	UserAuthRefresher        providerrefresh.UserAuthRefresher
+	UserManager              user.Manager
+	UserMerger               *UserMerger
+	UserData                 *UserDataManager
+	LoginAs                  *LoginAsManager
 	ExtTokenStore            *exttokenstore.SystemStore
+	PasswordHistory          *passwordpolicy.History
+	GlobalPermissions        *rbac.GlobalPermissionsResolver
 }
 
 func (h *Handler) Actions(actionName string, action *types.Action, apiContext *types.APIContext) error {
@@ -54,6 +78,34 @@ func (h *Handler) Actions(actionName string, action *types.Action, apiContext *t
 		if err := h.refreshAttributes(apiContext); err != nil {
 			return err
 		}
+	case "linkprincipal":
+		if err := h.linkPrincipal(apiContext); err != nil {
+			return err
+		}
+	case "unlinkprincipal":
+		if err := h.unlinkPrincipal(apiContext); err != nil {
+			return err
+		}
+	case "detectduplicates":
+		if err := h.detectDuplicates(apiContext); err != nil {
+			return err
+		}
+	case "mergeusers":
+		if err := h.mergeUsers(apiContext); err != nil {
+			return err
+		}
+	case "exportdata":
+		if err := h.exportData(apiContext); err != nil {
+			return err
+		}
+	case "erasedata":
+		if err := h.eraseData(apiContext); err != nil {
+			return err
+		}
+	case "loginas":
+		if err := h.loginAs(apiContext); err != nil {
+			return err
+		}
 	default:
 		return errors.Errorf("bad action %v", actionName)
 	}
@@ -97,19 +149,25 @@ func (h *Handler) changePassword(request *types.APIContext) error {
 		return err
 	}
 
-	if err := validatePassword(user.Username, currentPass, newPass, settings.PasswordMinLength.GetInt()); err != nil {
+	policy := passwordpolicy.Current()
+	if err := validatePassword(user.Username, currentPass, newPass, policy); err != nil {
 		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPass)); err != nil {
+	if err := passwordhash.Verify(user.Password, currentPass); err != nil {
 		return httperror.NewAPIError(httperror.InvalidBodyContent, "invalid current password")
 	}
 
+	if err := h.checkPasswordHistory(user, newPass, policy); err != nil {
+		return err
+	}
+
 	newPassHash, err := HashPasswordString(newPass)
 	if err != nil {
 		return err
 	}
 
+	previous := user.DeepCopy()
 	user.Password = newPassHash
 	user.MustChangePassword = false
 	user, err = h.UserClient.Update(user)
@@ -117,7 +175,7 @@ func (h *Handler) changePassword(request *types.APIContext) error {
 		return err
 	}
 
-	return nil
+	return h.PasswordHistory.Record(previous, policy)
 }
 
 func (h *Handler) setPassword(request *types.APIContext) error {
@@ -149,10 +207,19 @@ func (h *Handler) setPassword(request *types.APIContext) error {
 	username, _ := usernameInt.(string)
 
 	// passing empty currentPass to validator since, this api call doesn't assume an existing password
-	if err := validatePassword(username, "", newPass, settings.PasswordMinLength.GetInt()); err != nil {
+	policy := passwordpolicy.Current()
+	if err := validatePassword(username, "", newPass, policy); err != nil {
 		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
 	}
 
+	previous, err := h.UserClient.Get(request.ID, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := h.checkPasswordHistory(previous, newPass, policy); err != nil {
+		return err
+	}
+
 	userData[client.UserFieldPassword] = newPass
 	if err := hashPassword(userData); err != nil {
 		return err
@@ -164,6 +231,9 @@ func (h *Handler) setPassword(request *types.APIContext) error {
 	if err != nil {
 		return err
 	}
+	if err := h.PasswordHistory.Record(previous, policy); err != nil {
+		return err
+	}
 
 	request.WriteResponse(http.StatusOK, userData)
 	return nil
@@ -190,11 +260,140 @@ func (h *Handler) userCanRefresh(request *types.APIContext) bool {
 	return request.AccessControl.CanDo(v3.UserGroupVersionKind.Group, v3.UserResource.Name, "create", request, nil, request.Schema) == nil
 }
 
-// validatePassword will ensure a password is at least the minimum required length in runes,
+func (h *Handler) detectDuplicates(request *types.APIContext) error {
+	if canMerge := h.userCanMerge(request); !canMerge {
+		return httperror.NewAPIError(httperror.PermissionDenied, "Not Allowed")
+	}
+
+	output, err := h.UserMerger.DetectDuplicates()
+	if err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, output)
+	return nil
+}
+
+func (h *Handler) mergeUsers(request *types.APIContext) error {
+	if canMerge := h.userCanMerge(request); !canMerge {
+		return httperror.NewAPIError(httperror.PermissionDenied, "Not Allowed")
+	}
+
+	input := &apiv3.MergeUsersInput{}
+	if err := json.NewDecoder(request.Request.Body).Decode(input); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "")
+	}
+	if input.SourceUserID == "" || input.TargetUserID == "" {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "must specify sourceUserId and targetUserId")
+	}
+	callerID := request.Request.Header.Get("Impersonate-User")
+	for _, userID := range []string{input.SourceUserID, input.TargetUserID} {
+		if err := checkNoEscalation(h.GlobalPermissions, callerID, userID); err != nil {
+			return err
+		}
+	}
+
+	output, err := h.UserMerger.Merge(input)
+	if err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, output)
+	return nil
+}
+
+// userCanMerge returns true if the user can delete users, merging users deletes the merged one.
+func (h *Handler) userCanMerge(request *types.APIContext) bool {
+	return request.AccessControl.CanDo(v3.UserGroupVersionKind.Group, v3.UserResource.Name, "delete", request, nil, request.Schema) == nil
+}
+
+func (h *Handler) exportData(request *types.APIContext) error {
+	if canManageData := h.userCanManageData(request); !canManageData {
+		return httperror.NewAPIError(httperror.PermissionDenied, "Not Allowed")
+	}
+
+	output, err := h.UserData.Export(request.ID)
+	if err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, output)
+	return nil
+}
+
+func (h *Handler) eraseData(request *types.APIContext) error {
+	if canManageData := h.userCanManageData(request); !canManageData {
+		return httperror.NewAPIError(httperror.PermissionDenied, "Not Allowed")
+	}
+
+	input := &apiv3.EraseUserDataInput{}
+	if err := json.NewDecoder(request.Request.Body).Decode(input); err != nil && !errors.Is(err, io.EOF) {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "")
+	}
+
+	if err := checkNoEscalation(h.GlobalPermissions, request.Request.Header.Get("Impersonate-User"), request.ID); err != nil {
+		return err
+	}
+
+	output, err := h.UserData.Erase(request.ID, input)
+	if err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, output)
+	return nil
+}
+
+// userCanManageData returns true if the user can delete users, erasing the data of a user is as destructive.
+func (h *Handler) userCanManageData(request *types.APIContext) bool {
+	return request.AccessControl.CanDo(v3.UserGroupVersionKind.Group, v3.UserResource.Name, "delete", request, nil, request.Schema) == nil
+}
+
+func (h *Handler) loginAs(request *types.APIContext) error {
+	if canLoginAs := h.userCanLoginAs(request); !canLoginAs {
+		return httperror.NewAPIError(httperror.PermissionDenied, "Not Allowed")
+	}
+
+	adminID := request.Request.Header.Get("Impersonate-User")
+	if adminID == "" {
+		return errors.New("can't find user")
+	}
+
+	input := &apiv3.LoginAsInput{}
+	if err := json.NewDecoder(request.Request.Body).Decode(input); err != nil && !errors.Is(err, io.EOF) {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "")
+	}
+
+	output, err := h.LoginAs.LoginAs(adminID, request.ID, input)
+	if err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusCreated, output)
+	return nil
+}
+
+// userCanLoginAs returns true if the user can log in as other users, a verb granted by the dedicated global role.
+func (h *Handler) userCanLoginAs(request *types.APIContext) bool {
+	return request.AccessControl.CanDo(v3.UserGroupVersionKind.Group, v3.UserResource.Name, "loginas", request, nil, request.Schema) == nil
+}
+
+// checkPasswordHistory ensures the new password of the user isn't one of their last passwords.
+func (h *Handler) checkPasswordHistory(user *v3.User, pass string, policy passwordpolicy.Policy) error {
+	if err := h.PasswordHistory.Check(user, pass, policy); err != nil {
+		if errors.Is(err, passwordpolicy.ErrReused) {
+			return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// validatePassword will ensure a password meets the requirements of the password policy,
 // that the username and password do not match, and that the new password is not the same as the current password.
-func validatePassword(user string, currentPass string, pass string, minPassLen int) error {
-	if utf8.RuneCountInString(pass) < minPassLen {
-		return errors.Errorf("Password must be at least %v characters", minPassLen)
+func validatePassword(user string, currentPass string, pass string, policy passwordpolicy.Policy) error {
+	if err := policy.Validate(user, pass); err != nil {
+		return err
 	}
 
 	if user == pass {
@@ -2,6 +2,8 @@
 
 import (
 	"testing"
+
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
 )
 
 func TestValidatePassword(t *testing.T) {
@@ -65,7 +67,7 @@ func TestValidatePassword(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validatePassword(tt.username, tt.currentpass, tt.password, 12)
+			err := validatePassword(tt.username, tt.currentpass, tt.password, passwordpolicy.Policy{MinLength: 12})
 			if err != nil && !tt.expectsErr {
 				t.Errorf("Received unexpected error: %v", err)
 			} else if err == nil && tt.expectsErr {
@@ -0,0 +1,396 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/activity"
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/failedlogins"
+	"github.com/rancher/rancher/pkg/auth/loginanomalies"
+	"github.com/rancher/rancher/pkg/auth/mfa"
+	"github.com/rancher/rancher/pkg/auth/notifications"
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/auth/webauthn"
+	wrangmgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
+)
+
+// ErasedAtAnnotation is when the personal data of the user was erased, in RFC 3339 format.
+const ErasedAtAnnotation = "auth.cattle.io/erased-at"
+
+// UserDataManager exports and erases the personal data Rancher stores about users.
+type UserDataManager struct {
+	users              wrangmgmtv3.UserClient
+	userAttributes     wrangmgmtv3.UserAttributeClient
+	tokens             wrangmgmtv3.TokenClient
+	globalRoleBindings wrangmgmtv3.GlobalRoleBindingClient
+	crtbs              wrangmgmtv3.ClusterRoleTemplateBindingClient
+	prtbs              wrangmgmtv3.ProjectRoleTemplateBindingClient
+	secrets            wcorev1.SecretClient
+	now                func() time.Time
+}
+
+func NewUserDataManager(wContext *wrangler.Context) *UserDataManager {
+	return &UserDataManager{
+		users:              wContext.Mgmt.User(),
+		userAttributes:     wContext.Mgmt.UserAttribute(),
+		tokens:             wContext.Mgmt.Token(),
+		globalRoleBindings: wContext.Mgmt.GlobalRoleBinding(),
+		crtbs:              wContext.Mgmt.ClusterRoleTemplateBinding(),
+		prtbs:              wContext.Mgmt.ProjectRoleTemplateBinding(),
+		secrets:            wContext.Core.Secret(),
+		now:                time.Now,
+	}
+}
+
+// Export returns the user, its attributes, the metadata of its tokens with their usage history and its role bindings.
+func (m *UserDataManager) Export(userID string) (*v3.UserDataExport, error) {
+	user, err := m.users.Get(userID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("user %s not found", userID))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	output := &v3.UserDataExport{ExportedAt: m.now().UTC().Format(time.RFC3339)}
+	output.User = *user.DeepCopy()
+	output.User.Password = ""
+
+	attribs, err := m.userAttributes.Get(userID, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		if attribs.LastLogin != nil {
+			output.LastLogin = attribs.LastLogin.UTC().Format(time.RFC3339)
+		}
+		for _, groups := range attribs.GroupPrincipals {
+			for _, group := range groups.Items {
+				output.GroupPrincipals = append(output.GroupPrincipals, group.Name)
+			}
+		}
+		output.ExtraByProvider = attribs.ExtraByProvider
+	}
+
+	tokenList, err := m.tokens.List(metav1.ListOptions{
+		LabelSelector: labels.Set{tokens.UserIDLabel: userID}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokenList.Items {
+		token.Token = ""
+		output.Tokens = append(output.Tokens, token)
+	}
+
+	usageSecrets, err := m.secrets.List(tokens.SecretNamespace, metav1.ListOptions{
+		LabelSelector: labels.Set{tokens.TokenUsageLabel: "true", tokens.UserIDLabel: userID}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range usageSecrets.Items {
+		events, err := tokens.ParseUsageEvents(&secret)
+		if err != nil {
+			logrus.Warnf("Skipping the usage history of secret %s of user %s: %v", secret.Name, userID, err)
+			continue
+		}
+		history := v3.TokenUsageHistory{TokenName: strings.TrimPrefix(secret.Name, tokens.UsageSecretName(""))}
+		for _, event := range events {
+			history.Events = append(history.Events, v3.TokenUsageEvent{
+				Time:          event.Time.UTC().Format(time.RFC3339),
+				SourceIP:      event.SourceIP,
+				UserAgent:     event.UserAgent,
+				EndpointClass: event.EndpointClass,
+			})
+		}
+		output.TokenUsage = append(output.TokenUsage, history)
+	}
+
+	grbs, err := m.globalRoleBindings.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, grb := range grbs.Items {
+		if grb.UserName == userID {
+			output.GlobalRoleBindings = append(output.GlobalRoleBindings, grb)
+		}
+	}
+	crtbs, err := m.crtbs.List("", metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, crtb := range crtbs.Items {
+		if crtb.UserName == userID {
+			output.ClusterRoleTemplateBindings = append(output.ClusterRoleTemplateBindings, crtb)
+		}
+	}
+	prtbs, err := m.prtbs.List("", metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, prtb := range prtbs.Items {
+		if prtb.UserName == userID {
+			output.ProjectRoleTemplateBindings = append(output.ProjectRoleTemplateBindings, prtb)
+		}
+	}
+
+	logrus.Infof("Exported the data of user %s", userID)
+	return output, nil
+}
+
+// Erase scrubs the personal data of the user while keeping the user, so that the resources referencing it remain valid.
+// The user is disabled and loses its name, email, password and the principals of auth providers, its attributes are
+// cleared, its tokens and secrets are deleted and its role bindings are recreated without its principal.
+// In dry-run mode nothing is changed and the resources which would be are returned.
+func (m *UserDataManager) Erase(userID string, input *v3.EraseUserDataInput) (*v3.EraseUserDataOutput, error) {
+	user, err := m.users.Get(userID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("user %s not found", userID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isSystemUser(user) || user.IsDefaultAdmin() {
+		return nil, httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("can't erase the data of user %s", userID))
+	}
+
+	output := &v3.EraseUserDataOutput{DryRun: input.DryRun}
+	var localPrincipalIDs []string
+	for _, principalID := range user.PrincipalIDs {
+		if strings.HasPrefix(principalID, "local://") {
+			localPrincipalIDs = append(localPrincipalIDs, principalID)
+		} else {
+			output.PrincipalIDs = append(output.PrincipalIDs, principalID)
+		}
+	}
+
+	// The tokens go first, so that the user can't keep using Rancher while its data is being erased.
+	if err := m.eraseTokens(user, output); err != nil {
+		return nil, err
+	}
+	if err := m.eraseSecrets(user, output); err != nil {
+		return nil, err
+	}
+	if err := m.eraseGlobalRoleBindings(user, output); err != nil {
+		return nil, err
+	}
+	if err := m.eraseClusterRoleTemplateBindings(user, output); err != nil {
+		return nil, err
+	}
+	if err := m.eraseProjectRoleTemplateBindings(user, output); err != nil {
+		return nil, err
+	}
+	if input.DryRun {
+		return output, nil
+	}
+
+	attribs, err := m.userAttributes.Get(userID, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		attribs.UserName = ""
+		attribs.GroupPrincipals = nil
+		attribs.ExtraByProvider = nil
+		attribs.LastLogin = nil
+		attribs.LastRefresh = ""
+		attribs.NeedsRefresh = false
+		if _, err := m.userAttributes.Update(attribs); err != nil {
+			return nil, fmt.Errorf("failed to clear the attributes of user %s: %w", userID, err)
+		}
+	}
+
+	user.DisplayName = ""
+	user.Description = ""
+	user.Username = ""
+	user.Email = ""
+	user.Password = ""
+	user.MustChangePassword = false
+	user.PrincipalIDs = localPrincipalIDs
+	user.Enabled = pointer.Bool(false)
+	if user.Annotations == nil {
+		user.Annotations = map[string]string{}
+	}
+	delete(user.Annotations, emailverification.PendingAnnotation)
+	delete(user.Annotations, emailverification.VerifiedAnnotation)
+	user.Annotations[ErasedAtAnnotation] = m.now().UTC().Format(time.RFC3339)
+	if _, err := m.users.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to erase user %s: %w", userID, err)
+	}
+
+	logrus.Infof("Erased the personal data of user %s", userID)
+	return output, nil
+}
+
+func (m *UserDataManager) eraseTokens(user *v3.User, output *v3.EraseUserDataOutput) error {
+	list, err := m.tokens.List(metav1.ListOptions{
+		LabelSelector: labels.Set{tokens.UserIDLabel: user.Name}.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, token := range list.Items {
+		output.Tokens = append(output.Tokens, token.Name)
+		if output.DryRun {
+			continue
+		}
+		if err := m.tokens.Delete(token.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete token %s: %w", token.Name, err)
+		}
+	}
+	return nil
+}
+
+// eraseSecrets deletes the secrets of the user: the usage history of its tokens, its refresh tokens, its second
+// factors, its previous passwords, its pending password reset, the devices it logged in from, the fingerprints of its
+// logins, its failed logins and its auth provider secrets.
+func (m *UserDataManager) eraseSecrets(user *v3.User, output *v3.EraseUserDataOutput) error {
+	list, err := m.secrets.List(tokens.SecretNamespace, metav1.ListOptions{
+		LabelSelector: labels.Set{tokens.UserIDLabel: user.Name}.String(),
+	})
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, secret := range list.Items {
+		names = append(names, secret.Name)
+	}
+	for _, name := range []string{
+		mfa.SecretName(user.Name),
+		webauthn.SecretName(user.Name),
+		passwordpolicy.HistorySecretName(user.Name),
+		tokens.PasswordResetSecretName(user.Name),
+		notifications.DevicesSecretName(user.Name),
+		loginanomalies.HistorySecretName(user.Name),
+		activity.SecretName(user.Name),
+		failedlogins.SecretName(user.Name),
+		tokens.ProviderSecretName(user.Name),
+	} {
+		_, err := m.secrets.Get(tokens.SecretNamespace, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+
+	for _, name := range names {
+		output.Secrets = append(output.Secrets, tokens.SecretNamespace+":"+name)
+		if output.DryRun {
+			continue
+		}
+		if err := m.secrets.Delete(tokens.SecretNamespace, name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete secret %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// The subject of role bindings is immutable, the ones naming a principal of an auth provider are recreated with the user only.
+
+func hasExternalPrincipal(userPrincipalName string) bool {
+	return userPrincipalName != "" && !strings.HasPrefix(userPrincipalName, "local://")
+}
+
+func (m *UserDataManager) eraseGlobalRoleBindings(user *v3.User, output *v3.EraseUserDataOutput) error {
+	list, err := m.globalRoleBindings.List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, grb := range list.Items {
+		if grb.UserName != user.Name || !hasExternalPrincipal(grb.UserPrincipalName) {
+			continue
+		}
+		output.GlobalRoleBindings = append(output.GlobalRoleBindings, grb.Name)
+		if output.DryRun {
+			continue
+		}
+		if _, err := m.globalRoleBindings.Create(&v3.GlobalRoleBinding{
+			ObjectMeta:     metav1.ObjectMeta{GenerateName: "grb-", Labels: grb.Labels, Annotations: util.RecreatedAnnotations(grb.Annotations)},
+			UserName:       user.Name,
+			GlobalRoleName: grb.GlobalRoleName,
+		}); err != nil {
+			return fmt.Errorf("failed to bind global role %s to user %s: %w", grb.GlobalRoleName, user.Name, err)
+		}
+		if err := m.globalRoleBindings.Delete(grb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *UserDataManager) eraseClusterRoleTemplateBindings(user *v3.User, output *v3.EraseUserDataOutput) error {
+	list, err := m.crtbs.List("", metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, crtb := range list.Items {
+		if crtb.UserName != user.Name || !hasExternalPrincipal(crtb.UserPrincipalName) {
+			continue
+		}
+		output.ClusterRoleTemplateBindings = append(output.ClusterRoleTemplateBindings, crtb.Namespace+":"+crtb.Name)
+		if output.DryRun {
+			continue
+		}
+		if _, err := m.crtbs.Create(&v3.ClusterRoleTemplateBinding{
+			ObjectMeta:       metav1.ObjectMeta{GenerateName: "crtb-", Namespace: crtb.Namespace, Labels: crtb.Labels, Annotations: util.RecreatedAnnotations(crtb.Annotations)},
+			UserName:         user.Name,
+			ClusterName:      crtb.ClusterName,
+			RoleTemplateName: crtb.RoleTemplateName,
+		}); err != nil {
+			return fmt.Errorf("failed to bind role template %s of cluster %s to user %s: %w", crtb.RoleTemplateName, crtb.ClusterName, user.Name, err)
+		}
+		if err := m.crtbs.Delete(crtb.Namespace, crtb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *UserDataManager) eraseProjectRoleTemplateBindings(user *v3.User, output *v3.EraseUserDataOutput) error {
+	list, err := m.prtbs.List("", metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, prtb := range list.Items {
+		if prtb.UserName != user.Name || !hasExternalPrincipal(prtb.UserPrincipalName) {
+			continue
+		}
+		output.ProjectRoleTemplateBindings = append(output.ProjectRoleTemplateBindings, prtb.Namespace+":"+prtb.Name)
+		if output.DryRun {
+			continue
+		}
+		if _, err := m.prtbs.Create(&v3.ProjectRoleTemplateBinding{
+			ObjectMeta:       metav1.ObjectMeta{GenerateName: "prtb-", Namespace: prtb.Namespace, Labels: prtb.Labels, Annotations: util.RecreatedAnnotations(prtb.Annotations)},
+			UserName:         user.Name,
+			ProjectName:      prtb.ProjectName,
+			RoleTemplateName: prtb.RoleTemplateName,
+		}); err != nil {
+			return fmt.Errorf("failed to bind role template %s of project %s to user %s: %w", prtb.RoleTemplateName, prtb.ProjectName, user.Name, err)
+		}
+		if err := m.prtbs.Delete(prtb.Namespace, prtb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package user
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/mfa"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+)
+
+func TestExportUserData(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	lastLogin := metav1.NewTime(now.Add(-time.Hour))
+
+	ctrl := gomock.NewController(t)
+	users := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+	userAttributes := fake.NewMockNonNamespacedClientInterface[*v3.UserAttribute, *v3.UserAttributeList](ctrl)
+	tokenClient := fake.NewMockNonNamespacedClientInterface[*v3.Token, *v3.TokenList](ctrl)
+	grbs := fake.NewMockNonNamespacedClientInterface[*v3.GlobalRoleBinding, *v3.GlobalRoleBindingList](ctrl)
+	crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+
+	users.EXPECT().Get("u-abcde", gomock.Any()).Return(&v3.User{
+		ObjectMeta:   metav1.ObjectMeta{Name: "u-abcde"},
+		Username:     "jdoe",
+		Password:     "hash",
+		PrincipalIDs: []string{"openldap_user://uid=jdoe", "local://u-abcde"},
+	}, nil)
+	userAttributes.EXPECT().Get("u-abcde", gomock.Any()).Return(&v3.UserAttribute{
+		ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"},
+		LastLogin:  &lastLogin,
+		GroupPrincipals: map[string]v3.Principals{
+			"openldap": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "openldap_group://cn=devs"}}}},
+		},
+	}, nil)
+	tokenClient.EXPECT().List(gomock.Any()).Return(&v3.TokenList{Items: []v3.Token{
+		{ObjectMeta: metav1.ObjectMeta{Name: "token-abcde"}, UserID: "u-abcde", Token: "key"},
+	}}, nil)
+	events, err := json.Marshal([]tokens.UsageEvent{{Time: metav1.NewTime(now), SourceIP: "10.0.0.1", UserAgent: "kubectl", EndpointClass: "k8s"}})
+	require.NoError(t, err)
+	secrets.EXPECT().List(tokens.SecretNamespace, gomock.Any()).Return(&corev1.SecretList{Items: []corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: tokens.UsageSecretName("token-abcde")}, Data: map[string][]byte{"events": events}},
+	}}, nil)
+	grbs.EXPECT().List(gomock.Any()).Return(&v3.GlobalRoleBindingList{Items: []v3.GlobalRoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-abcde"}, UserName: "u-abcde", GlobalRoleName: "user"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-fghij"}, UserName: "u-fghij", GlobalRoleName: "admin"},
+	}}, nil)
+	crtbs.EXPECT().List("", gomock.Any()).Return(&v3.ClusterRoleTemplateBindingList{Items: []v3.ClusterRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-abcde", Namespace: "c-abcde"}, UserName: "u-abcde", ClusterName: "c-abcde", RoleTemplateName: "cluster-member"},
+	}}, nil)
+	prtbs.EXPECT().List("", gomock.Any()).Return(&v3.ProjectRoleTemplateBindingList{}, nil)
+
+	manager := &UserDataManager{
+		users:              users,
+		userAttributes:     userAttributes,
+		tokens:             tokenClient,
+		globalRoleBindings: grbs,
+		crtbs:              crtbs,
+		prtbs:              prtbs,
+		secrets:            secrets,
+		now:                func() time.Time { return now },
+	}
+	output, err := manager.Export("u-abcde")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-10-15T12:00:00Z", output.ExportedAt)
+	assert.Equal(t, "jdoe", output.User.Username)
+	assert.Empty(t, output.User.Password)
+	assert.Equal(t, "2026-10-15T11:00:00Z", output.LastLogin)
+	assert.Equal(t, []string{"openldap_group://cn=devs"}, output.GroupPrincipals)
+	require.Len(t, output.Tokens, 1)
+	assert.Equal(t, "token-abcde", output.Tokens[0].Name)
+	assert.Empty(t, output.Tokens[0].Token)
+	assert.Equal(t, []v3.TokenUsageHistory{{
+		TokenName: "token-abcde",
+		Events:    []v3.TokenUsageEvent{{Time: "2026-10-15T12:00:00Z", SourceIP: "10.0.0.1", UserAgent: "kubectl", EndpointClass: "k8s"}},
+	}}, output.TokenUsage)
+	require.Len(t, output.GlobalRoleBindings, 1)
+	assert.Equal(t, "grb-abcde", output.GlobalRoleBindings[0].Name)
+	require.Len(t, output.ClusterRoleTemplateBindings, 1)
+	assert.Empty(t, output.ProjectRoleTemplateBindings)
+}
+
+func TestEraseUserData(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	notFound := func(name string) error {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "dry run", dryRun: true},
+		{name: "erase"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			users := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+			userAttributes := fake.NewMockNonNamespacedClientInterface[*v3.UserAttribute, *v3.UserAttributeList](ctrl)
+			tokenClient := fake.NewMockNonNamespacedClientInterface[*v3.Token, *v3.TokenList](ctrl)
+			grbs := fake.NewMockNonNamespacedClientInterface[*v3.GlobalRoleBinding, *v3.GlobalRoleBindingList](ctrl)
+			crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+			prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+			secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+
+			users.EXPECT().Get("u-abcde", gomock.Any()).Return(&v3.User{
+				ObjectMeta:   metav1.ObjectMeta{Name: "u-abcde", Annotations: map[string]string{emailverification.VerifiedAnnotation: "jdoe@example.com"}},
+				DisplayName:  "John Doe",
+				Username:     "jdoe",
+				Email:        "jdoe@example.com",
+				Password:     "hash",
+				PrincipalIDs: []string{"openldap_user://uid=jdoe", "local://u-abcde"},
+				Enabled:      pointer.Bool(true),
+			}, nil)
+			tokenClient.EXPECT().List(gomock.Any()).Return(&v3.TokenList{Items: []v3.Token{
+				{ObjectMeta: metav1.ObjectMeta{Name: "token-abcde"}, UserID: "u-abcde"},
+			}}, nil)
+			secrets.EXPECT().List(tokens.SecretNamespace, gomock.Any()).Return(&corev1.SecretList{Items: []corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: tokens.UsageSecretName("token-abcde")}},
+			}}, nil)
+			secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, opts metav1.GetOptions) (*corev1.Secret, error) {
+				if name == mfa.SecretName("u-abcde") {
+					return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}, nil
+				}
+				return nil, notFound(name)
+			}).AnyTimes()
+			grbs.EXPECT().List(gomock.Any()).Return(&v3.GlobalRoleBindingList{Items: []v3.GlobalRoleBinding{
+				{ObjectMeta: metav1.ObjectMeta{Name: "grb-abcde"}, UserName: "u-abcde", GlobalRoleName: "user"},
+			}}, nil)
+			crtbs.EXPECT().List("", gomock.Any()).Return(&v3.ClusterRoleTemplateBindingList{Items: []v3.ClusterRoleTemplateBinding{
+				{ObjectMeta: metav1.ObjectMeta{Name: "crtb-abcde", Namespace: "c-abcde", Annotations: map[string]string{
+					"lifecycle.cattle.io/create.cluster-crtb-sync_c-abcde": "true",
+					"field.cattle.io/creatorId":                            "u-admin",
+				}}, UserName: "u-abcde", UserPrincipalName: "openldap_user://uid=jdoe", ClusterName: "c-abcde", RoleTemplateName: "cluster-member"},
+			}}, nil)
+			prtbs.EXPECT().List("", gomock.Any()).Return(&v3.ProjectRoleTemplateBindingList{}, nil)
+
+			if !tt.dryRun {
+				tokenClient.EXPECT().Delete("token-abcde", gomock.Any()).Return(nil)
+				secrets.EXPECT().Delete(tokens.SecretNamespace, tokens.UsageSecretName("token-abcde"), gomock.Any()).Return(nil)
+				secrets.EXPECT().Delete(tokens.SecretNamespace, mfa.SecretName("u-abcde"), gomock.Any()).Return(nil)
+				crtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+					assert.Equal(t, "u-abcde", crtb.UserName)
+					assert.Empty(t, crtb.UserPrincipalName)
+					assert.Equal(t, "c-abcde", crtb.Namespace)
+					assert.Equal(t, map[string]string{"field.cattle.io/creatorId": "u-admin"}, crtb.Annotations)
+					return crtb, nil
+				})
+				crtbs.EXPECT().Delete("c-abcde", "crtb-abcde", gomock.Any()).Return(nil)
+				userAttributes.EXPECT().Get("u-abcde", gomock.Any()).Return(&v3.UserAttribute{
+					ObjectMeta:      metav1.ObjectMeta{Name: "u-abcde"},
+					UserName:        "jdoe",
+					ExtraByProvider: map[string]map[string][]string{"openldap": {"username": {"jdoe"}}},
+				}, nil)
+				userAttributes.EXPECT().Update(gomock.Any()).DoAndReturn(func(attribs *v3.UserAttribute) (*v3.UserAttribute, error) {
+					assert.Empty(t, attribs.UserName)
+					assert.Empty(t, attribs.ExtraByProvider)
+					return attribs, nil
+				})
+				users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+					assert.Empty(t, user.DisplayName)
+					assert.Empty(t, user.Username)
+					assert.Empty(t, user.Email)
+					assert.Empty(t, user.Password)
+					assert.Equal(t, []string{"local://u-abcde"}, user.PrincipalIDs)
+					assert.False(t, pointer.BoolDeref(user.Enabled, true))
+					assert.NotContains(t, user.Annotations, emailverification.VerifiedAnnotation)
+					assert.Equal(t, "2026-10-15T12:00:00Z", user.Annotations[ErasedAtAnnotation])
+					return user, nil
+				})
+			}
+
+			manager := &UserDataManager{
+				users:              users,
+				userAttributes:     userAttributes,
+				tokens:             tokenClient,
+				globalRoleBindings: grbs,
+				crtbs:              crtbs,
+				prtbs:              prtbs,
+				secrets:            secrets,
+				now:                func() time.Time { return now },
+			}
+			output, err := manager.Erase("u-abcde", &v3.EraseUserDataInput{DryRun: tt.dryRun})
+			require.NoError(t, err)
+			assert.Equal(t, &v3.EraseUserDataOutput{
+				DryRun:       tt.dryRun,
+				PrincipalIDs: []string{"openldap_user://uid=jdoe"},
+				Tokens:       []string{"token-abcde"},
+				Secrets: []string{
+					tokens.SecretNamespace + ":" + tokens.UsageSecretName("token-abcde"),
+					tokens.SecretNamespace + ":" + mfa.SecretName("u-abcde"),
+				},
+				ClusterRoleTemplateBindings: []string{"c-abcde:crtb-abcde"},
+			}, output)
+		})
+	}
+}
+
+func TestEraseDefaultAdminData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	users := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+	users.EXPECT().Get("user-admin", gomock.Any()).Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "user-admin"}, Username: "admin"}, nil)
+
+	manager := &UserDataManager{users: users}
+	_, err := manager.Erase("user-admin", &v3.EraseUserDataInput{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/wrangler"
+)
+
+// globalPermissions compares the global permissions of users.
+type globalPermissions interface {
+	Covers(callerID, targetID string) (bool, error)
+}
+
+// NewGlobalPermissions returns the resolver of the global permissions of the users, compared on the changes to users.
+func NewGlobalPermissions(wContext *wrangler.Context) *rbac.GlobalPermissionsResolver {
+	return rbac.NewGlobalPermissionsResolver(
+		wContext.Mgmt.GlobalRoleBinding().Cache(),
+		wContext.Mgmt.GlobalRole().Cache(),
+		wContext.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		wContext.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		wContext.Mgmt.UserAttribute().Cache(),
+		wContext.Mgmt.RoleTemplate().Cache(),
+		wContext.RBAC.ClusterRole().Cache(),
+	)
+}
+
+// checkNoEscalation refuses the changes of the user callerID to the user targetID when the target holds global, cluster
+// or project permissions the caller isn't granted: setting its password, or merging it into an account of the caller,
+// would grant them to the caller.
+func checkNoEscalation(permissions globalPermissions, callerID, targetID string) error {
+	if callerID == targetID {
+		return nil
+	}
+	covered, err := permissions.Covers(callerID, targetID)
+	if err != nil {
+		return fmt.Errorf("error comparing the global permissions of %s and %s: %w", callerID, targetID, err)
+	}
+	if !covered {
+		return httperror.NewAPIError(httperror.PermissionDenied,
+			fmt.Sprintf("user %s holds permissions that %s isn't granted", targetID, callerID))
+	}
+	return nil
+}
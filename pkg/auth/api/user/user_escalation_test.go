@@ -0,0 +1,65 @@
+package user
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/cache"
+)
+
+// fakeGlobalPermissions covers the users of the callers.
+type fakeGlobalPermissions map[string][]string
+
+func (f fakeGlobalPermissions) Covers(callerID, targetID string) (bool, error) {
+	for _, covered := range f[callerID] {
+		if covered == targetID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeUpdateStore struct {
+	types.Store
+	updated []string
+}
+
+func (f *fakeUpdateStore) Update(_ *types.APIContext, _ *types.Schema, data map[string]interface{}, id string) (map[string]interface{}, error) {
+	f.updated = append(f.updated, id)
+	return data, nil
+}
+
+func TestUserStoreEscalation(t *testing.T) {
+	store := &fakeUpdateStore{}
+	s := &userStore{
+		Store:       store,
+		userIndexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		globalPermissions: fakeGlobalPermissions{
+			"u-authn": {"u-jdoe"},
+		},
+	}
+	apiContext := func(callerID string) *types.APIContext {
+		req := httptest.NewRequest("PUT", "/v3/users", nil)
+		req.Header.Set("Impersonate-User", callerID)
+		return &types.APIContext{Request: req}
+	}
+
+	// An authentication admin can't set the password of an admin, and log in as them.
+	_, err := s.Update(apiContext("u-authn"), nil, map[string]interface{}{client.UserFieldPassword: "hashed"}, "u-admin")
+	require.Error(t, err)
+	assert.True(t, httperror.IsForbidden(err), "expected a forbidden error, got %v", err)
+	_, err = s.Delete(apiContext("u-authn"), nil, "u-admin")
+	assert.True(t, httperror.IsForbidden(err), "expected a forbidden error, got %v", err)
+	assert.Empty(t, store.updated)
+
+	_, err = s.Update(apiContext("u-authn"), nil, map[string]interface{}{client.UserFieldPassword: "hashed"}, "u-jdoe")
+	require.NoError(t, err)
+	_, err = s.Update(apiContext("u-admin"), nil, map[string]interface{}{}, "u-admin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u-jdoe", "u-admin"}, store.updated)
+}
@@ -0,0 +1,144 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/parse"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/slice"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/activedirectory"
+	"github.com/rancher/rancher/pkg/auth/providers/azure"
+	"github.com/rancher/rancher/pkg/auth/providers/externalauth"
+	"github.com/rancher/rancher/pkg/auth/providers/genericoidc"
+	"github.com/rancher/rancher/pkg/auth/providers/github"
+	"github.com/rancher/rancher/pkg/auth/providers/googleoauth"
+	"github.com/rancher/rancher/pkg/auth/providers/keycloakoidc"
+	"github.com/rancher/rancher/pkg/auth/providers/ldap"
+	"github.com/rancher/rancher/pkg/auth/providers/local"
+	"github.com/rancher/rancher/pkg/auth/providers/oidc"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// linkLoginInput returns the login input the provider authenticates the principal to link with.
+// Only providers which can authenticate a user with a single request support linking.
+func linkLoginInput(input *apiv3.LinkPrincipalInput) (interface{}, error) {
+	switch input.Provider {
+	case activedirectory.Name, ldap.OpenLdapName, ldap.FreeIpaName, externalauth.Name:
+		return &apiv3.BasicLogin{Username: input.Username, Password: input.Password}, nil
+	case github.Name:
+		return &apiv3.GithubLogin{Code: input.Code}, nil
+	case azure.Name:
+		return &apiv3.AzureADLogin{Code: input.Code}, nil
+	case googleoauth.Name:
+		return &apiv3.GoogleOauthLogin{Code: input.Code}, nil
+	case oidc.Name, keycloakoidc.Name, genericoidc.Name:
+		return &apiv3.OIDCLogin{Code: input.Code}, nil
+	default:
+		return nil, fmt.Errorf("linking principals of auth provider %s is not supported", input.Provider)
+	}
+}
+
+// linkPrincipal authenticates the current user against another auth provider and adds the authenticated principal
+// to the principals of the current user, so logging in with either provider results in the same Rancher user.
+func (h *Handler) linkPrincipal(request *types.APIContext) error {
+	userID := request.Request.Header.Get("Impersonate-User")
+	if userID == "" {
+		return errors.New("can't find user")
+	}
+
+	body, err := io.ReadAll(request.Request.Body)
+	if err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "")
+	}
+	input := &apiv3.LinkPrincipalInput{}
+	if err := json.Unmarshal(body, input); err != nil || input.Provider == "" {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "must specify provider")
+	}
+
+	loginInput, err := linkLoginInput(input)
+	if err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+	}
+	if disabled, err := providers.IsDisabledProvider(input.Provider); err != nil || disabled {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("authentication provider %s is not enabled", input.Provider))
+	}
+
+	ctx := context.WithValue(request.Request.Context(), util.RequestKey, request.Request)
+	principal, _, _, err := providers.AuthenticateUser(ctx, loginInput, input.Provider)
+	if err != nil {
+		if httperror.IsAPIError(err) {
+			return err
+		}
+		logrus.Errorf("Failed to verify the %s principal to link to user %s: %v", input.Provider, userID, err)
+		return httperror.NewAPIError(httperror.Unauthorized, "failed to verify the principal to link")
+	}
+
+	owner, err := h.UserManager.GetUserByPrincipalID(principal.Name)
+	if err != nil {
+		return err
+	}
+	if owner != nil && owner.Name != userID {
+		return httperror.NewAPIError(httperror.Conflict, fmt.Sprintf("principal %s is already linked to another user", principal.Name))
+	}
+
+	if _, err := h.UserManager.SetPrincipalOnCurrentUserByUserID(userID, principal); err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, nil)
+	return nil
+}
+
+// unlinkPrincipal removes a principal of an external auth provider from the principals of the current user.
+func (h *Handler) unlinkPrincipal(request *types.APIContext) error {
+	actionInput, err := parse.ReadBody(request.Request)
+	if err != nil {
+		return err
+	}
+
+	userID := request.Request.Header.Get("Impersonate-User")
+	if userID == "" {
+		return errors.New("can't find user")
+	}
+
+	principalID, ok := actionInput["principalId"].(string)
+	if !ok || len(principalID) == 0 {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "must specify principalId")
+	}
+	if strings.HasPrefix(principalID, local.Name+"://") {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "can't unlink the local principal of a user")
+	}
+
+	user, err := h.UserClient.Get(userID, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !slice.ContainsString(user.PrincipalIDs, principalID) {
+		return httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("principal %s is not linked to the user", principalID))
+	}
+
+	var principalIDs []string
+	for _, id := range user.PrincipalIDs {
+		if id != principalID {
+			principalIDs = append(principalIDs, id)
+		}
+	}
+	user.PrincipalIDs = principalIDs
+	if _, err := h.UserClient.Update(user); err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, nil)
+	return nil
+}
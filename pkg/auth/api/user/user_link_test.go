@@ -0,0 +1,133 @@
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLinkLoginInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *v3.LinkPrincipalInput
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:  "basic login",
+			input: &v3.LinkPrincipalInput{Provider: "openldap", Username: "jdoe", Password: "secret"},
+			want:  &v3.BasicLogin{Username: "jdoe", Password: "secret"},
+		},
+		{
+			name:  "oauth login",
+			input: &v3.LinkPrincipalInput{Provider: "github", Code: "code"},
+			want:  &v3.GithubLogin{Code: "code"},
+		},
+		{
+			name:  "oidc login",
+			input: &v3.LinkPrincipalInput{Provider: "genericoidc", Code: "code"},
+			want:  &v3.OIDCLogin{Code: "code"},
+		},
+		{
+			name:    "saml isn't supported",
+			input:   &v3.LinkPrincipalInput{Provider: "okta"},
+			wantErr: true,
+		},
+		{
+			name:    "local isn't supported",
+			input:   &v3.LinkPrincipalInput{Provider: "local", Username: "admin", Password: "secret"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := linkLoginInput(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnlinkPrincipal(t *testing.T) {
+	tests := []struct {
+		name           string
+		principalID    string
+		wantErrCode    *httperror.ErrorCode
+		wantPrincipals []string
+	}{
+		{
+			name:           "unlink principal",
+			principalID:    "openldap_user://cn=jdoe",
+			wantPrincipals: []string{"local://u-abcde", "github_user://1234"},
+		},
+		{
+			name:        "principal isn't linked",
+			principalID: "azuread_user://5678",
+			wantErrCode: &httperror.NotFound,
+		},
+		{
+			name:        "local principal",
+			principalID: "local://u-abcde",
+			wantErrCode: &httperror.InvalidBodyContent,
+		},
+		{
+			name:        "missing principal",
+			wantErrCode: &httperror.InvalidBodyContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *v3.User
+			h := &Handler{
+				UserClient: &fakes.UserInterfaceMock{
+					GetFunc: func(name string, opts metav1.GetOptions) (*v3.User, error) {
+						return &v3.User{
+							ObjectMeta:   metav1.ObjectMeta{Name: name},
+							PrincipalIDs: []string{"local://u-abcde", "openldap_user://cn=jdoe", "github_user://1234"},
+						}, nil
+					},
+					UpdateFunc: func(user *v3.User) (*v3.User, error) {
+						updated = user
+						return user, nil
+					},
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v3/users?action=unlinkprincipal", strings.NewReader(`{"principalId":"`+tt.principalID+`"}`))
+			req.Header.Set("Impersonate-User", "u-abcde")
+			err := h.unlinkPrincipal(&types.APIContext{Request: req, ResponseWriter: &fakeResponseWriter{}})
+			if tt.wantErrCode != nil {
+				require.Error(t, err)
+				apiErr, ok := err.(*httperror.APIError)
+				require.True(t, ok)
+				assert.Equal(t, *tt.wantErrCode, apiErr.Code)
+				assert.Nil(t, updated)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, updated)
+			assert.Equal(t, tt.wantPrincipals, updated.PrincipalIDs)
+		})
+	}
+}
+
+type fakeResponseWriter struct {
+	code int
+}
+
+func (f *fakeResponseWriter) Write(apiContext *types.APIContext, code int, obj interface{}) {
+	f.code = code
+}
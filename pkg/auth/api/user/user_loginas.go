@@ -0,0 +1,135 @@
+package user
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	wrangmgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LoginAsGlobalRole is the global role allowing to log in as other users.
+const LoginAsGlobalRole = "users-loginas"
+
+type loginAsTokenCreator interface {
+	NewLoginAsToken(userID string, userPrincipal v3.Principal, adminID string, ttl int64, description string) (v3.Token, string, error)
+}
+
+// LoginAsManager issues short-lived tokens acting as a user to administrators, to debug the permissions of the user.
+// The tokens are listed with the tokens of the user, who can revoke them.
+type LoginAsManager struct {
+	userCache         wrangmgmtv3.UserCache
+	globalPermissions globalPermissions
+	tokens            loginAsTokenCreator
+	enabledProviders  func() []string
+	now               func() time.Time
+}
+
+func NewLoginAsManager(wContext *wrangler.Context, tokens loginAsTokenCreator) *LoginAsManager {
+	return &LoginAsManager{
+		userCache:         wContext.Mgmt.User().Cache(),
+		globalPermissions: NewGlobalPermissions(wContext),
+		tokens:            tokens,
+		enabledProviders:  providers.EnabledProviders,
+		now:               time.Now,
+	}
+}
+
+// LoginAs issues a token acting as the user userID to the administrator adminID.
+func (m *LoginAsManager) LoginAs(adminID, userID string, input *v3.LoginAsInput) (*v3.LoginAsOutput, error) {
+	maxTTL := time.Duration(settings.AuthLoginAsMaxTTLMinutes.GetInt()) * time.Minute
+	if maxTTL <= 0 {
+		return nil, httperror.NewAPIError(httperror.InvalidState, "logging in as users is disabled")
+	}
+	ttl := maxTTL
+	switch {
+	case input.TTLMinutes < 0:
+		return nil, httperror.NewAPIError(httperror.InvalidBodyContent, "ttlMinutes must not be negative")
+	case input.TTLMinutes > 0:
+		ttl = time.Duration(input.TTLMinutes) * time.Minute
+		if ttl > maxTTL {
+			return nil, httperror.NewAPIError(httperror.InvalidBodyContent,
+				fmt.Sprintf("ttlMinutes must not exceed %d", settings.AuthLoginAsMaxTTLMinutes.GetInt()))
+		}
+	}
+
+	if adminID == userID {
+		return nil, httperror.NewAPIError(httperror.InvalidAction, "can't log in as yourself")
+	}
+
+	user, err := m.userCache.Get(userID)
+	if apierrors.IsNotFound(err) {
+		return nil, httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("user %s not found", userID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSystem() {
+		return nil, httperror.NewAPIError(httperror.InvalidAction, "can't log in as a system user")
+	}
+	if user.Enabled != nil && !*user.Enabled {
+		return nil, httperror.NewAPIError(httperror.InvalidState, fmt.Sprintf("user %s is disabled", userID))
+	}
+
+	// Acting as a user holding global, cluster or project permissions the administrator isn't granted, directly or
+	// through the groups of either of them, would escalate privileges.
+	if err := checkNoEscalation(m.globalPermissions, adminID, userID); err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Login as %s by %s", userID, adminID)
+	if input.Reason != "" {
+		description += ": " + input.Reason
+	}
+
+	token, key, err := m.tokens.NewLoginAsToken(userID, m.userPrincipal(user), adminID, ttl.Milliseconds(), description)
+	if err != nil {
+		return nil, fmt.Errorf("error creating login as token: %w", err)
+	}
+	expiresAt := m.now().Add(ttl).UTC().Format(time.RFC3339)
+
+	logrus.WithFields(logrus.Fields{
+		"event":     "LoginAsUser",
+		"admin":     adminID,
+		"user":      userID,
+		"token":     token.Name,
+		"expiresAt": expiresAt,
+		"reason":    input.Reason,
+	}).Info("loginas: audit")
+
+	return &v3.LoginAsOutput{
+		Token:     token.Name + ":" + key,
+		TokenName: token.Name,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// userPrincipal returns the principal of the user for an enabled auth provider, whose groups are those of the user's
+// logins with that provider, or the local principal of the user.
+func (m *LoginAsManager) userPrincipal(user *v3.User) v3.Principal {
+	principalID := "local://" + user.Name
+	enabled := m.enabledProviders()
+	for _, id := range user.PrincipalIDs {
+		if slices.Contains(enabled, providers.PrincipalProvider(id)) {
+			principalID = id
+			break
+		}
+	}
+
+	return v3.Principal{
+		ObjectMeta:    metav1.ObjectMeta{Name: principalID},
+		DisplayName:   user.DisplayName,
+		LoginName:     user.Username,
+		PrincipalType: "user",
+		Me:            true,
+		Provider:      providers.PrincipalProvider(principalID),
+	}
+}
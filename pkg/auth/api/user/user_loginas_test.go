@@ -0,0 +1,232 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+)
+
+type fakeLoginAsTokens struct {
+	userID    string
+	principal v3.Principal
+	adminID   string
+	ttl       int64
+	desc      string
+}
+
+func (f *fakeLoginAsTokens) NewLoginAsToken(userID string, userPrincipal v3.Principal, adminID string, ttl int64, description string) (v3.Token, string, error) {
+	f.userID, f.principal, f.adminID, f.ttl, f.desc = userID, userPrincipal, adminID, ttl, description
+	return v3.Token{ObjectMeta: metav1.ObjectMeta{Name: "token-abcde"}}, "key", nil
+}
+
+func setMaxTTL(t *testing.T, minutes string) {
+	require.NoError(t, settings.AuthLoginAsMaxTTLMinutes.Set(minutes))
+	t.Cleanup(func() {
+		settings.AuthLoginAsMaxTTLMinutes.Set(settings.AuthLoginAsMaxTTLMinutes.Default)
+	})
+}
+
+func TestLoginAs(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	setMaxTTL(t, "30")
+
+	ctrl := gomock.NewController(t)
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+
+	userCache.EXPECT().Get("u-jdoe").Return(&v3.User{
+		ObjectMeta:   metav1.ObjectMeta{Name: "u-jdoe"},
+		DisplayName:  "John Doe",
+		Username:     "jdoe",
+		PrincipalIDs: []string{"local://u-jdoe", "openldap_user://uid=jdoe"},
+	}, nil).Times(2)
+
+	tokens := &fakeLoginAsTokens{}
+	m := &LoginAsManager{
+		userCache:         userCache,
+		globalPermissions: fakeGlobalPermissions{"u-admin": {"u-jdoe"}},
+		tokens:            tokens,
+		enabledProviders:  func() []string { return []string{"openldap"} },
+		now:               func() time.Time { return now },
+	}
+
+	output, err := m.LoginAs("u-admin", "u-jdoe", &v3.LoginAsInput{TTLMinutes: 10, Reason: "ticket 1234"})
+	require.NoError(t, err)
+	assert.Equal(t, &v3.LoginAsOutput{
+		Token:     "token-abcde:key",
+		TokenName: "token-abcde",
+		ExpiresAt: "2026-10-15T12:10:00Z",
+	}, output)
+	assert.Equal(t, "u-jdoe", tokens.userID)
+	assert.Equal(t, "u-admin", tokens.adminID)
+	assert.Equal(t, (10 * time.Minute).Milliseconds(), tokens.ttl)
+	assert.Equal(t, "Login as u-jdoe by u-admin: ticket 1234", tokens.desc)
+	assert.Equal(t, "openldap_user://uid=jdoe", tokens.principal.Name)
+	assert.Equal(t, "openldap", tokens.principal.Provider)
+	assert.Equal(t, "jdoe", tokens.principal.LoginName)
+
+	// Without a time to live the maximum is used, and without an enabled provider the local principal.
+	m.enabledProviders = func() []string { return nil }
+	output, err = m.LoginAs("u-admin", "u-jdoe", &v3.LoginAsInput{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-15T12:30:00Z", output.ExpiresAt)
+	assert.Equal(t, (30 * time.Minute).Milliseconds(), tokens.ttl)
+	assert.Equal(t, "Login as u-jdoe by u-admin", tokens.desc)
+	assert.Equal(t, "local://u-jdoe", tokens.principal.Name)
+	assert.Equal(t, "local", tokens.principal.Provider)
+}
+
+func TestLoginAsErrors(t *testing.T) {
+	setMaxTTL(t, "30")
+
+	ctrl := gomock.NewController(t)
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+
+	userCache.EXPECT().Get("u-gone").Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "u-gone")).AnyTimes()
+	userCache.EXPECT().Get("u-disabled").Return(&v3.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "u-disabled"},
+		Enabled:    pointer.Bool(false),
+	}, nil).AnyTimes()
+	userCache.EXPECT().Get("u-system").Return(&v3.User{
+		ObjectMeta:   metav1.ObjectMeta{Name: "u-system"},
+		PrincipalIDs: []string{"system://local"},
+	}, nil).AnyTimes()
+	userCache.EXPECT().Get("u-other").Return(&v3.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "u-other"},
+	}, nil).AnyTimes()
+
+	m := &LoginAsManager{
+		userCache:         userCache,
+		globalPermissions: fakeGlobalPermissions{},
+		tokens:            &fakeLoginAsTokens{},
+		enabledProviders:  func() []string { return nil },
+		now:               time.Now,
+	}
+
+	tests := []struct {
+		name     string
+		userID   string
+		input    *v3.LoginAsInput
+		wantCode httperror.ErrorCode
+	}{
+		{
+			name:     "negative ttl",
+			userID:   "u-other",
+			input:    &v3.LoginAsInput{TTLMinutes: -1},
+			wantCode: httperror.InvalidBodyContent,
+		},
+		{
+			name:     "ttl above maximum",
+			userID:   "u-other",
+			input:    &v3.LoginAsInput{TTLMinutes: 31},
+			wantCode: httperror.InvalidBodyContent,
+		},
+		{
+			name:     "yourself",
+			userID:   "u-admin",
+			input:    &v3.LoginAsInput{},
+			wantCode: httperror.InvalidAction,
+		},
+		{
+			name:     "user not found",
+			userID:   "u-gone",
+			input:    &v3.LoginAsInput{},
+			wantCode: httperror.NotFound,
+		},
+		{
+			name:     "disabled user",
+			userID:   "u-disabled",
+			input:    &v3.LoginAsInput{},
+			wantCode: httperror.InvalidState,
+		},
+		{
+			name:     "system user",
+			userID:   "u-system",
+			input:    &v3.LoginAsInput{},
+			wantCode: httperror.InvalidAction,
+		},
+		{
+			name:     "privileged user",
+			userID:   "u-other",
+			input:    &v3.LoginAsInput{},
+			wantCode: httperror.PermissionDenied,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := m.LoginAs("u-admin", test.userID, test.input)
+			require.Error(t, err)
+			apiErr, ok := err.(*httperror.APIError)
+			require.True(t, ok)
+			assert.Equal(t, test.wantCode, apiErr.Code)
+		})
+	}
+}
+
+func TestLoginAsClusterOwner(t *testing.T) {
+	setMaxTTL(t, "30")
+
+	ctrl := gomock.NewController(t)
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl)
+	grCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRole](ctrl)
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	rtCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	clusterRoleCache := fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRole](ctrl)
+
+	userCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.User, error) {
+		return &v3.User{ObjectMeta: metav1.ObjectMeta{Name: name}, PrincipalIDs: []string{"local://" + name}}, nil
+	}).AnyTimes()
+	grbCache.EXPECT().List(labels.Everything()).Return([]*v3.GlobalRoleBinding{
+		{UserName: "u-loginas", GlobalRoleName: LoginAsGlobalRole},
+	}, nil).AnyTimes()
+	grCache.EXPECT().Get(LoginAsGlobalRole).Return(&v3.GlobalRole{
+		ObjectMeta: metav1.ObjectMeta{Name: LoginAsGlobalRole},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"management.cattle.io"}, Resources: []string{"users"}, Verbs: []string{"get", "list", "watch", "loginas"}},
+		},
+	}, nil).AnyTimes()
+	crtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ClusterRoleTemplateBinding{
+		{ClusterName: "c-12345", UserName: "u-owner", RoleTemplateName: "cluster-owner"},
+	}, nil).AnyTimes()
+	prtbCache.EXPECT().List("", labels.Everything()).Return(nil, nil).AnyTimes()
+	userAttributeCache.EXPECT().Get(gomock.Any()).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "")).AnyTimes()
+	rtCache.EXPECT().Get("cluster-owner").Return(&v3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-owner"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+	}, nil).AnyTimes()
+
+	m := &LoginAsManager{
+		userCache:         userCache,
+		globalPermissions: rbac.NewGlobalPermissionsResolver(grbCache, grCache, crtbCache, prtbCache, userAttributeCache, rtCache, clusterRoleCache),
+		tokens:            &fakeLoginAsTokens{},
+		enabledProviders:  func() []string { return nil },
+		now:               time.Now,
+	}
+
+	// Logging in as a cluster owner would grant its cluster to the caller, who holds no role in it.
+	_, err := m.LoginAs("u-loginas", "u-owner", &v3.LoginAsInput{})
+	require.Error(t, err)
+	apiErr, ok := err.(*httperror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, httperror.PermissionDenied, apiErr.Code)
+
+	// The users without bindings can be logged in as.
+	_, err = m.LoginAs("u-loginas", "u-jdoe", &v3.LoginAsInput{})
+	require.NoError(t, err)
+}
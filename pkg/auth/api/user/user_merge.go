@@ -0,0 +1,366 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types/slice"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	wrangmgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const creatorIDAnnotation = "field.cattle.io/creatorId"
+
+// UserMerger detects users sharing the same login and merges them.
+type UserMerger struct {
+	users              wrangmgmtv3.UserClient
+	userAttributes     wrangmgmtv3.UserAttributeClient
+	tokens             wrangmgmtv3.TokenClient
+	globalRoleBindings wrangmgmtv3.GlobalRoleBindingClient
+	crtbs              wrangmgmtv3.ClusterRoleTemplateBindingClient
+	prtbs              wrangmgmtv3.ProjectRoleTemplateBindingClient
+	clusters           wrangmgmtv3.ClusterClient
+	projects           wrangmgmtv3.ProjectClient
+}
+
+func NewUserMerger(wContext *wrangler.Context) *UserMerger {
+	return &UserMerger{
+		users:              wContext.Mgmt.User(),
+		userAttributes:     wContext.Mgmt.UserAttribute(),
+		tokens:             wContext.Mgmt.Token(),
+		globalRoleBindings: wContext.Mgmt.GlobalRoleBinding(),
+		crtbs:              wContext.Mgmt.ClusterRoleTemplateBinding(),
+		prtbs:              wContext.Mgmt.ProjectRoleTemplateBinding(),
+		clusters:           wContext.Mgmt.Cluster(),
+		projects:           wContext.Mgmt.Project(),
+	}
+}
+
+// DetectDuplicates returns the users sharing the same local username or the same login name of a principal.
+func (m *UserMerger) DetectDuplicates() (*v3.DetectDuplicateUsersOutput, error) {
+	users, err := m.users.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := m.userAttributes.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v3.DetectDuplicateUsersOutput{Duplicates: findDuplicates(users.Items, attributes.Items)}, nil
+}
+
+func findDuplicates(users []v3.User, attributes []v3.UserAttribute) []v3.DuplicateUsers {
+	logins := map[string][]string{}
+	addLogin := func(login, userID string) {
+		login = strings.ToLower(strings.TrimSpace(login))
+		if login != "" && !slice.ContainsString(logins[login], userID) {
+			logins[login] = append(logins[login], userID)
+		}
+	}
+
+	known := map[string]bool{}
+	for _, user := range users {
+		if isSystemUser(&user) {
+			continue
+		}
+		known[user.Name] = true
+		addLogin(user.Username, user.Name)
+	}
+	for _, attribute := range attributes {
+		if !known[attribute.Name] {
+			continue
+		}
+		for _, extra := range attribute.ExtraByProvider {
+			for _, login := range extra[common.UserAttributeUserName] {
+				addLogin(login, attribute.Name)
+			}
+		}
+	}
+
+	var duplicates []v3.DuplicateUsers
+	for login, userIDs := range logins {
+		if len(userIDs) < 2 {
+			continue
+		}
+		sort.Strings(userIDs)
+		duplicates = append(duplicates, v3.DuplicateUsers{Login: login, UserIDs: userIDs})
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Login < duplicates[j].Login
+	})
+	return duplicates
+}
+
+func isSystemUser(user *v3.User) bool {
+	for _, principalID := range user.PrincipalIDs {
+		if strings.HasPrefix(principalID, "system://") {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge moves the principals, tokens, role bindings and created clusters and projects of the source user to the target user
+// and deletes the source user. In dry-run mode nothing is changed and the resources which would be moved are returned.
+func (m *UserMerger) Merge(input *v3.MergeUsersInput) (*v3.MergeUsersOutput, error) {
+	if input.SourceUserID == input.TargetUserID {
+		return nil, httperror.NewAPIError(httperror.InvalidBodyContent, "can't merge a user into itself")
+	}
+	source, err := m.getUser(input.SourceUserID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := m.getUser(input.TargetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &v3.MergeUsersOutput{DryRun: input.DryRun}
+	var principalIDs []string
+	for _, principalID := range source.PrincipalIDs {
+		if !strings.HasPrefix(principalID, "local://") && !slice.ContainsString(target.PrincipalIDs, principalID) {
+			principalIDs = append(principalIDs, principalID)
+		}
+	}
+	output.PrincipalIDs = principalIDs
+
+	if err := m.mergeTokens(source, target, output); err != nil {
+		return nil, err
+	}
+	if err := m.mergeGlobalRoleBindings(source, target, output); err != nil {
+		return nil, err
+	}
+	if err := m.mergeClusterRoleTemplateBindings(source, target, output); err != nil {
+		return nil, err
+	}
+	if err := m.mergeProjectRoleTemplateBindings(source, target, output); err != nil {
+		return nil, err
+	}
+	if err := m.mergeCreators(source, target, output); err != nil {
+		return nil, err
+	}
+	if input.DryRun {
+		return output, nil
+	}
+
+	// The principals must be removed from the source user before they're added to the target user,
+	// a principal can't belong to two users.
+	source.PrincipalIDs = nil
+	if _, err := m.users.Update(source); err != nil {
+		return nil, fmt.Errorf("failed to remove the principals of user %s: %w", source.Name, err)
+	}
+	target.PrincipalIDs = append(target.PrincipalIDs, principalIDs...)
+	if _, err := m.users.Update(target); err != nil {
+		return nil, fmt.Errorf("failed to add principals to user %s: %w", target.Name, err)
+	}
+	if err := m.users.Delete(source.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to delete user %s: %w", source.Name, err)
+	}
+
+	logrus.Infof("Merged user %s into user %s", source.Name, target.Name)
+	return output, nil
+}
+
+func (m *UserMerger) getUser(userID string) (*v3.User, error) {
+	user, err := m.users.Get(userID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("user %s not found", userID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isSystemUser(user) {
+		return nil, httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("can't merge system user %s", userID))
+	}
+	return user, nil
+}
+
+func (m *UserMerger) mergeTokens(source, target *v3.User, output *v3.MergeUsersOutput) error {
+	list, err := m.tokens.List(metav1.ListOptions{
+		LabelSelector: labels.Set{tokens.UserIDLabel: source.Name}.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, token := range list.Items {
+		output.Tokens = append(output.Tokens, token.Name)
+		if output.DryRun {
+			continue
+		}
+		token.UserID = target.Name
+		token.Labels[tokens.UserIDLabel] = target.Name
+		if _, err := m.tokens.Update(&token); err != nil {
+			return fmt.Errorf("failed to move token %s to user %s: %w", token.Name, target.Name, err)
+		}
+	}
+	return nil
+}
+
+// The subject of role bindings is immutable, they're recreated for the target user unless it already has the same role.
+
+func (m *UserMerger) mergeGlobalRoleBindings(source, target *v3.User, output *v3.MergeUsersOutput) error {
+	list, err := m.globalRoleBindings.List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	roles := map[string]bool{}
+	for _, grb := range list.Items {
+		if grb.UserName == target.Name {
+			roles[grb.GlobalRoleName] = true
+		}
+	}
+
+	for _, grb := range list.Items {
+		if grb.UserName != source.Name {
+			continue
+		}
+		output.GlobalRoleBindings = append(output.GlobalRoleBindings, grb.Name)
+		if output.DryRun {
+			continue
+		}
+		if !roles[grb.GlobalRoleName] {
+			if _, err := m.globalRoleBindings.Create(&v3.GlobalRoleBinding{
+				ObjectMeta:     metav1.ObjectMeta{GenerateName: "grb-", Labels: grb.Labels, Annotations: grb.Annotations},
+				UserName:       target.Name,
+				GlobalRoleName: grb.GlobalRoleName,
+			}); err != nil {
+				return fmt.Errorf("failed to bind global role %s to user %s: %w", grb.GlobalRoleName, target.Name, err)
+			}
+			roles[grb.GlobalRoleName] = true
+		}
+		if err := m.globalRoleBindings.Delete(grb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *UserMerger) mergeClusterRoleTemplateBindings(source, target *v3.User, output *v3.MergeUsersOutput) error {
+	list, err := m.crtbs.List("", metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	roles := map[string]bool{}
+	for _, crtb := range list.Items {
+		if crtb.UserName == target.Name {
+			roles[crtb.ClusterName+":"+crtb.RoleTemplateName] = true
+		}
+	}
+
+	for _, crtb := range list.Items {
+		if crtb.UserName != source.Name {
+			continue
+		}
+		output.ClusterRoleTemplateBindings = append(output.ClusterRoleTemplateBindings, crtb.Namespace+":"+crtb.Name)
+		if output.DryRun {
+			continue
+		}
+		if key := crtb.ClusterName + ":" + crtb.RoleTemplateName; !roles[key] {
+			if _, err := m.crtbs.Create(&v3.ClusterRoleTemplateBinding{
+				ObjectMeta:       metav1.ObjectMeta{GenerateName: "crtb-", Namespace: crtb.Namespace, Labels: crtb.Labels, Annotations: crtb.Annotations},
+				UserName:         target.Name,
+				ClusterName:      crtb.ClusterName,
+				RoleTemplateName: crtb.RoleTemplateName,
+			}); err != nil {
+				return fmt.Errorf("failed to bind role template %s of cluster %s to user %s: %w", crtb.RoleTemplateName, crtb.ClusterName, target.Name, err)
+			}
+			roles[key] = true
+		}
+		if err := m.crtbs.Delete(crtb.Namespace, crtb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *UserMerger) mergeProjectRoleTemplateBindings(source, target *v3.User, output *v3.MergeUsersOutput) error {
+	list, err := m.prtbs.List("", metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	roles := map[string]bool{}
+	for _, prtb := range list.Items {
+		if prtb.UserName == target.Name {
+			roles[prtb.ProjectName+":"+prtb.RoleTemplateName] = true
+		}
+	}
+
+	for _, prtb := range list.Items {
+		if prtb.UserName != source.Name {
+			continue
+		}
+		output.ProjectRoleTemplateBindings = append(output.ProjectRoleTemplateBindings, prtb.Namespace+":"+prtb.Name)
+		if output.DryRun {
+			continue
+		}
+		if key := prtb.ProjectName + ":" + prtb.RoleTemplateName; !roles[key] {
+			if _, err := m.prtbs.Create(&v3.ProjectRoleTemplateBinding{
+				ObjectMeta:       metav1.ObjectMeta{GenerateName: "prtb-", Namespace: prtb.Namespace, Labels: prtb.Labels, Annotations: prtb.Annotations},
+				UserName:         target.Name,
+				ProjectName:      prtb.ProjectName,
+				RoleTemplateName: prtb.RoleTemplateName,
+			}); err != nil {
+				return fmt.Errorf("failed to bind role template %s of project %s to user %s: %w", prtb.RoleTemplateName, prtb.ProjectName, target.Name, err)
+			}
+			roles[key] = true
+		}
+		if err := m.prtbs.Delete(prtb.Namespace, prtb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeCreators makes the target user the creator of the clusters and projects created by the source user.
+func (m *UserMerger) mergeCreators(source, target *v3.User, output *v3.MergeUsersOutput) error {
+	clusters, err := m.clusters.List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, cluster := range clusters.Items {
+		if cluster.Annotations[creatorIDAnnotation] != source.Name {
+			continue
+		}
+		output.Clusters = append(output.Clusters, cluster.Name)
+		if output.DryRun {
+			continue
+		}
+		cluster.Annotations[creatorIDAnnotation] = target.Name
+		if _, err := m.clusters.Update(&cluster); err != nil {
+			return fmt.Errorf("failed to update the creator of cluster %s: %w", cluster.Name, err)
+		}
+	}
+
+	projects, err := m.projects.List("", metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, project := range projects.Items {
+		if project.Annotations[creatorIDAnnotation] != source.Name {
+			continue
+		}
+		output.Projects = append(output.Projects, project.Namespace+":"+project.Name)
+		if output.DryRun {
+			continue
+		}
+		project.Annotations[creatorIDAnnotation] = target.Name
+		if _, err := m.projects.Update(&project); err != nil {
+			return fmt.Errorf("failed to update the creator of project %s: %w", project.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	users := []v3.User{
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-local"}, Username: "JDoe", PrincipalIDs: []string{"local://u-local"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-ldap"}, PrincipalIDs: []string{"openldap_user://uid=jdoe", "local://u-ldap"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-github"}, PrincipalIDs: []string{"github_user://1234", "local://u-github"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-other"}, PrincipalIDs: []string{"github_user://5678", "local://u-other"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-system"}, Username: "jdoe", PrincipalIDs: []string{"system://local"}},
+	}
+	attributes := []v3.UserAttribute{
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-ldap"}, ExtraByProvider: map[string]map[string][]string{
+			"openldap": {"username": {"jdoe"}},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-github"}, ExtraByProvider: map[string]map[string][]string{
+			"github": {"username": {"jdoe"}},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-other"}, ExtraByProvider: map[string]map[string][]string{
+			"github": {"username": {"someone"}},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-deleted"}, ExtraByProvider: map[string]map[string][]string{
+			"github": {"username": {"someone"}},
+		}},
+	}
+
+	duplicates := findDuplicates(users, attributes)
+	assert.Equal(t, []v3.DuplicateUsers{
+		{Login: "jdoe", UserIDs: []string{"u-github", "u-ldap", "u-local"}},
+	}, duplicates)
+}
+
+func TestMerge(t *testing.T) {
+	source := func() *v3.User {
+		return &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-source"}, PrincipalIDs: []string{"openldap_user://uid=jdoe", "local://u-source"}}
+	}
+	target := func() *v3.User {
+		return &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-target"}, PrincipalIDs: []string{"github_user://1234", "local://u-target"}}
+	}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "dry run", dryRun: true},
+		{name: "merge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			users := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+			tokenClient := fake.NewMockNonNamespacedClientInterface[*v3.Token, *v3.TokenList](ctrl)
+			grbs := fake.NewMockNonNamespacedClientInterface[*v3.GlobalRoleBinding, *v3.GlobalRoleBindingList](ctrl)
+			crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+			prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+			clusters := fake.NewMockNonNamespacedClientInterface[*v3.Cluster, *v3.ClusterList](ctrl)
+			projects := fake.NewMockClientInterface[*v3.Project, *v3.ProjectList](ctrl)
+
+			users.EXPECT().Get("u-source", gomock.Any()).Return(source(), nil)
+			users.EXPECT().Get("u-target", gomock.Any()).Return(target(), nil)
+			tokenClient.EXPECT().List(gomock.Any()).Return(&v3.TokenList{Items: []v3.Token{
+				{ObjectMeta: metav1.ObjectMeta{Name: "token-abcde", Labels: map[string]string{tokens.UserIDLabel: "u-source"}}, UserID: "u-source"},
+			}}, nil)
+			grbs.EXPECT().List(gomock.Any()).Return(&v3.GlobalRoleBindingList{Items: []v3.GlobalRoleBinding{
+				{ObjectMeta: metav1.ObjectMeta{Name: "grb-source-user"}, UserName: "u-source", GlobalRoleName: "user"},
+				{ObjectMeta: metav1.ObjectMeta{Name: "grb-source-admin"}, UserName: "u-source", GlobalRoleName: "admin"},
+				{ObjectMeta: metav1.ObjectMeta{Name: "grb-target-user"}, UserName: "u-target", GlobalRoleName: "user"},
+			}}, nil)
+			crtbs.EXPECT().List("", gomock.Any()).Return(&v3.ClusterRoleTemplateBindingList{Items: []v3.ClusterRoleTemplateBinding{
+				{ObjectMeta: metav1.ObjectMeta{Name: "crtb-abcde", Namespace: "c-abcde"}, UserName: "u-source", ClusterName: "c-abcde", RoleTemplateName: "cluster-member"},
+			}}, nil)
+			prtbs.EXPECT().List("", gomock.Any()).Return(&v3.ProjectRoleTemplateBindingList{}, nil)
+			clusters.EXPECT().List(gomock.Any()).Return(&v3.ClusterList{Items: []v3.Cluster{
+				{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", Annotations: map[string]string{creatorIDAnnotation: "u-source"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "c-fghij", Annotations: map[string]string{creatorIDAnnotation: "u-target"}}},
+			}}, nil)
+			projects.EXPECT().List("", gomock.Any()).Return(&v3.ProjectList{}, nil)
+
+			if !tt.dryRun {
+				tokenClient.EXPECT().Update(gomock.Any()).DoAndReturn(func(token *v3.Token) (*v3.Token, error) {
+					assert.Equal(t, "u-target", token.UserID)
+					assert.Equal(t, "u-target", token.Labels[tokens.UserIDLabel])
+					return token, nil
+				})
+				grbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(grb *v3.GlobalRoleBinding) (*v3.GlobalRoleBinding, error) {
+					assert.Equal(t, "u-target", grb.UserName)
+					assert.Equal(t, "admin", grb.GlobalRoleName)
+					return grb, nil
+				})
+				grbs.EXPECT().Delete("grb-source-user", gomock.Any()).Return(nil)
+				grbs.EXPECT().Delete("grb-source-admin", gomock.Any()).Return(nil)
+				crtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+					assert.Equal(t, "u-target", crtb.UserName)
+					assert.Equal(t, "c-abcde", crtb.Namespace)
+					return crtb, nil
+				})
+				crtbs.EXPECT().Delete("c-abcde", "crtb-abcde", gomock.Any()).Return(nil)
+				clusters.EXPECT().Update(gomock.Any()).DoAndReturn(func(cluster *v3.Cluster) (*v3.Cluster, error) {
+					assert.Equal(t, "u-target", cluster.Annotations[creatorIDAnnotation])
+					return cluster, nil
+				})
+				gomock.InOrder(
+					users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+						assert.Equal(t, "u-source", user.Name)
+						assert.Empty(t, user.PrincipalIDs)
+						return user, nil
+					}),
+					users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+						assert.Equal(t, "u-target", user.Name)
+						assert.Equal(t, []string{"github_user://1234", "local://u-target", "openldap_user://uid=jdoe"}, user.PrincipalIDs)
+						return user, nil
+					}),
+					users.EXPECT().Delete("u-source", gomock.Any()).Return(nil),
+				)
+			}
+
+			merger := &UserMerger{
+				users:              users,
+				tokens:             tokenClient,
+				globalRoleBindings: grbs,
+				crtbs:              crtbs,
+				prtbs:              prtbs,
+				clusters:           clusters,
+				projects:           projects,
+			}
+			output, err := merger.Merge(&v3.MergeUsersInput{SourceUserID: "u-source", TargetUserID: "u-target", DryRun: tt.dryRun})
+			require.NoError(t, err)
+			assert.Equal(t, &v3.MergeUsersOutput{
+				DryRun:                      tt.dryRun,
+				PrincipalIDs:                []string{"openldap_user://uid=jdoe"},
+				Tokens:                      []string{"token-abcde"},
+				GlobalRoleBindings:          []string{"grb-source-user", "grb-source-admin"},
+				ClusterRoleTemplateBindings: []string{"c-abcde:crtb-abcde"},
+				Clusters:                    []string{"c-abcde"},
+			}, output)
+		})
+	}
+}
+
+func TestMergeIntoItself(t *testing.T) {
+	merger := &UserMerger{}
+	_, err := merger.Merge(&v3.MergeUsersInput{SourceUserID: "u-abcde", TargetUserID: "u-abcde"})
+	assert.Error(t, err)
+}
@@ -9,13 +9,15 @@
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/store/transform"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/values"
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
-	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/user"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -23,9 +25,11 @@
 
 type userStore struct {
 	types.Store
-	mu          sync.Mutex
-	userIndexer cache.Indexer
-	userManager user.Manager
+	mu                sync.Mutex
+	userIndexer       cache.Indexer
+	userManager       user.Manager
+	emailVerification *emailverification.Manager
+	globalPermissions globalPermissions
 }
 
 func SetUserStore(schema *types.Schema, mgmt *config.ScaledContext) {
@@ -36,10 +40,12 @@ func SetUserStore(schema *types.Schema, mgmt *config.ScaledContext) {
 	userInformer.AddIndexers(userIndexers)
 
 	store := &userStore{
-		Store:       schema.Store,
-		mu:          sync.Mutex{},
-		userIndexer: userInformer.GetIndexer(),
-		userManager: mgmt.UserManager,
+		Store:             schema.Store,
+		mu:                sync.Mutex{},
+		userIndexer:       userInformer.GetIndexer(),
+		userManager:       mgmt.UserManager,
+		emailVerification: emailverification.NewManager(mgmt),
+		globalPermissions: NewGlobalPermissions(mgmt.Wrangler),
 	}
 
 	t := &transform.Store{
@@ -100,11 +106,11 @@ func hashPassword(data map[string]interface{}) error {
 }
 
 func HashPasswordString(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := passwordhash.Hash(password)
 	if err != nil {
 		return "", errors.Wrap(err, "problem encrypting password")
 	}
-	return string(hash), nil
+	return hash, nil
 }
 
 func (s *userStore) Create(apiContext *types.APIContext, schema *types.Schema, data map[string]interface{}) (map[string]interface{}, error) {
@@ -118,7 +124,7 @@ func (s *userStore) Create(apiContext *types.APIContext, schema *types.Schema, d
 		return nil, errors.New("invalid password")
 	}
 
-	if err := validatePassword(username, "", password, settings.PasswordMinLength.GetInt()); err != nil {
+	if err := validatePassword(username, "", password, passwordpolicy.Current()); err != nil {
 		return nil, httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
 	}
 
@@ -126,10 +132,23 @@ func (s *userStore) Create(apiContext *types.APIContext, schema *types.Schema, d
 		return nil, err
 	}
 
+	// The users created with an email address must verify it before logging in, when required.
+	email, _ := data[client.UserFieldEmail].(string)
+	verify := email != "" && emailverification.Required()
+	if anns, ok := data[client.UserFieldAnnotations].(map[string]interface{}); ok {
+		delete(anns, emailverification.VerifiedAnnotation)
+	}
+	if verify {
+		values.PutValue(data, "true", client.UserFieldAnnotations, emailverification.PendingAnnotation)
+	}
+
 	created, err := s.create(apiContext, schema, data)
 	if err != nil {
 		return nil, err
 	}
+	if id, ok := created[types.ResourceFieldID].(string); ok && verify {
+		s.emailVerification.Send(id)
+	}
 
 Tries:
 	for x := 0; x < 3; x++ {
@@ -156,7 +175,8 @@ func (s *userStore) Create(apiContext *types.APIContext, schema *types.Schema, d
 			}
 
 			created[client.UserFieldPrincipalIDs] = append(principalIDs, "local://"+id)
-			created, err = s.Update(apiContext, schema, created, id)
+			// Adding the local principal of the new user isn't a change of the caller to an existing user.
+			created, err = s.Store.Update(apiContext, schema, created, id)
 			if err != nil {
 				if httperror.IsConflict(err) {
 					continue
@@ -208,8 +228,31 @@ func (s *userStore) Update(apiContext *types.APIContext, schema *types.Schema, d
 	if currentUser == id && willBeInactive {
 		return nil, httperror.NewAPIError(httperror.InvalidAction, "You cannot deactivate yourself")
 	}
+	if err := checkNoEscalation(s.globalPermissions, currentUser, id); err != nil {
+		return nil, err
+	}
+
+	// Only the verification links mark the email addresses as verified, and the new addresses must be verified.
+	var previous *v3.User
+	if obj, exists, err := s.userIndexer.GetByKey(id); err == nil && exists {
+		previous, _ = obj.(*v3.User)
+	}
+	if anns, ok := data[client.UserFieldAnnotations].(map[string]interface{}); ok {
+		delete(anns, emailverification.VerifiedAnnotation)
+		if previous != nil && previous.Annotations[emailverification.VerifiedAnnotation] != "" {
+			anns[emailverification.VerifiedAnnotation] = previous.Annotations[emailverification.VerifiedAnnotation]
+		}
+	}
 
-	return s.Store.Update(apiContext, schema, data, id)
+	updated, err := s.Store.Update(apiContext, schema, data, id)
+	if err != nil {
+		return nil, err
+	}
+	if email, ok := data[client.UserFieldEmail].(string); ok && email != "" && emailverification.Required() &&
+		(previous == nil || previous.Email != email) {
+		s.emailVerification.Send(id)
+	}
+	return updated, nil
 }
 
 func (s *userStore) Delete(apiContext *types.APIContext, schema *types.Schema, id string) (map[string]interface{}, error) {
@@ -221,6 +264,9 @@ func (s *userStore) Delete(apiContext *types.APIContext, schema *types.Schema, i
 	if currentUser == id {
 		return nil, httperror.NewAPIError(httperror.InvalidAction, "You cannot delete yourself")
 	}
+	if err := checkNoEscalation(s.globalPermissions, currentUser, id); err != nil {
+		return nil, err
+	}
 
 	return s.Store.Delete(apiContext, schema, id)
 }
@@ -0,0 +1,205 @@
+// Package attributelabels propagates selected attributes of the auth providers as labels and annotations of the users.
+package attributelabels
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	mgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+// ManagedAnnotation lists the label keys set from provider attributes, so that they can be removed
+// once they are no longer mapped or reported.
+const ManagedAnnotation = "auth.cattle.io/attribute-labels"
+
+// Labeler sets labels and annotations on users and user attributes from the attributes reported by the auth providers.
+// Labels hold a sanitized value, suitable for label selectors, while annotations with the same key hold the raw value.
+type Labeler struct {
+	ctx                context.Context
+	users              mgmtcontrollers.UserClient
+	userAttributeCache mgmtcontrollers.UserAttributeCache
+	userAttributes     mgmtcontrollers.UserAttributeClient
+	mappings           func() []Mapping
+}
+
+// NewLabeler creates a new instance of Labeler.
+func NewLabeler(ctx context.Context, mgmt mgmtcontrollers.Interface) *Labeler {
+	return &Labeler{
+		ctx:                ctx,
+		users:              mgmt.User(),
+		userAttributeCache: mgmt.UserAttribute().Cache(),
+		userAttributes:     mgmt.UserAttribute(),
+		mappings:           Mappings,
+	}
+}
+
+// EnsureForAll sets attribute labels for all users.
+func (l *Labeler) EnsureForAll() error {
+	attribsList, err := l.userAttributeCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("attributelabels: error listing user attributes: %w", err)
+	}
+
+	mappings := l.mappings()
+	for _, attribs := range attribsList {
+		if l.ctx.Err() != nil {
+			logrus.Info("attributelabels: context canceled, quitting")
+			break
+		}
+
+		if err := l.ensure(mappings, attribs); err != nil {
+			// Log the error and move on.
+			logrus.Errorf("attributelabels: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// EnsureForAttributes sets attribute labels for the user of the given user attributes.
+func (l *Labeler) EnsureForAttributes(attribs *mgmtv3.UserAttribute) error {
+	if l.ctx.Err() != nil {
+		logrus.Info("attributelabels: context canceled, quitting")
+		return nil
+	}
+
+	return l.ensure(l.mappings(), attribs)
+}
+
+func (l *Labeler) ensure(mappings []Mapping, attribs *mgmtv3.UserAttribute) error {
+	desired := desiredValues(mappings, attribs)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		user, err := l.users.Get(attribs.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) { // The user is no longer, move on.
+				return nil
+			}
+			return err
+		}
+		if user.IsSystem() || !apply(&user.ObjectMeta, desired) {
+			return nil
+		}
+
+		_, err = l.users.Update(user)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error updating user %s: %w", attribs.Name, err)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := l.userAttributes.Get(attribs.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if !apply(&current.ObjectMeta, desired) {
+			return nil
+		}
+
+		_, err = l.userAttributes.Update(current)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error updating user attributes %s: %w", attribs.Name, err)
+	}
+
+	return nil
+}
+
+// desiredValues returns the raw attribute values keyed by label.
+// Providers are looked up in alphabetical order, the first non-empty value wins.
+func desiredValues(mappings []Mapping, attribs *mgmtv3.UserAttribute) map[string]string {
+	providers := make([]string, 0, len(attribs.ExtraByProvider))
+	for provider := range attribs.ExtraByProvider {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	desired := map[string]string{}
+	for _, mapping := range mappings {
+		for _, provider := range providers {
+			values := attribs.ExtraByProvider[provider][mapping.Attribute]
+			if len(values) > 0 && values[0] != "" {
+				desired[mapping.Label] = values[0]
+				break
+			}
+		}
+	}
+
+	return desired
+}
+
+// apply sets the desired labels and annotations on the object and removes the stale ones.
+// It returns true if the object was changed.
+func apply(obj *metav1.ObjectMeta, desired map[string]string) bool {
+	var updated bool
+
+	for _, key := range strings.Split(obj.Annotations[ManagedAnnotation], ",") {
+		if _, ok := desired[key]; key == "" || ok {
+			continue
+		}
+		if _, ok := obj.Labels[key]; ok {
+			delete(obj.Labels, key)
+			updated = true
+		}
+		if _, ok := obj.Annotations[key]; ok {
+			delete(obj.Annotations, key)
+			updated = true
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		keys = append(keys, key)
+
+		if obj.Annotations == nil {
+			obj.Annotations = map[string]string{}
+		}
+		if obj.Annotations[key] != value {
+			obj.Annotations[key] = value
+			updated = true
+		}
+
+		labelValue := LabelValue(value)
+		if labelValue == "" {
+			if _, ok := obj.Labels[key]; ok {
+				delete(obj.Labels, key)
+				updated = true
+			}
+			continue
+		}
+		if obj.Labels == nil {
+			obj.Labels = map[string]string{}
+		}
+		if obj.Labels[key] != labelValue {
+			obj.Labels[key] = labelValue
+			updated = true
+		}
+	}
+	sort.Strings(keys)
+
+	managed := strings.Join(keys, ",")
+	if managed == "" {
+		if _, ok := obj.Annotations[ManagedAnnotation]; ok {
+			delete(obj.Annotations, ManagedAnnotation)
+			updated = true
+		}
+	} else if obj.Annotations[ManagedAnnotation] != managed {
+		obj.Annotations[ManagedAnnotation] = managed
+		updated = true
+	}
+
+	return updated
+}
@@ -0,0 +1,204 @@
+package attributelabels
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseMappings(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []Mapping
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "mappings",
+			value: " department=example.com/department, costCenter = example.com/cost-center,,",
+			want: []Mapping{
+				{Attribute: "department", Label: "example.com/department"},
+				{Attribute: "costCenter", Label: "example.com/cost-center"},
+			},
+		},
+		{
+			name:    "missing label",
+			value:   "department",
+			wantErr: true,
+		},
+		{
+			name:    "invalid label key",
+			value:   "department=example.com/not a label",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate label key",
+			value:   "department=example.com/org,division=example.com/org",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mappings, err := ParseMappings(test.value)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, mappings)
+		})
+	}
+}
+
+func TestExtraInfo(t *testing.T) {
+	require.NoError(t, settings.UserAttributeLabels.Set("department=example.com/department,c=example.com/country,costCenter=example.com/cost-center"))
+	defer settings.UserAttributeLabels.Set("")
+
+	values := map[string][]string{
+		"department": {"Research & Development", "Sales"},
+		"c":          {""},
+		"title":      {"Engineer"},
+	}
+	info := ExtraInfo(func(attribute string) []string { return values[attribute] })
+	assert.Equal(t, map[string]string{"department": "Research & Development"}, info)
+}
+
+func TestLabelValue(t *testing.T) {
+	assert.Equal(t, "Research---Development", LabelValue("Research & Development"))
+	assert.Equal(t, "CC-1234", LabelValue(" CC-1234 "))
+	assert.Equal(t, "", LabelValue("---"))
+	assert.Len(t, LabelValue(strings.Repeat("a", 100)), 63)
+}
+
+func TestEnsureForAttributes(t *testing.T) {
+	userID := "u-abcdef"
+	user := &v3.User{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: userID,
+			Labels: map[string]string{
+				"example.com/division": "Sales",
+				"team":                 "blue",
+			},
+			Annotations: map[string]string{
+				ManagedAnnotation:      "example.com/division",
+				"example.com/division": "Sales",
+			},
+		},
+	}
+	attribs := &v3.UserAttribute{
+		ObjectMeta: metav1.ObjectMeta{Name: userID},
+		ExtraByProvider: map[string]map[string][]string{
+			"openldap": {
+				"department": {"Research & Development"},
+			},
+			"activedirectory": {
+				"department": {""},
+				"costCenter": {"CC-1234"},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+
+	var updatedUser *v3.User
+	users := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+	users.EXPECT().Get(userID, gomock.Any()).Return(user.DeepCopy(), nil)
+	users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+		updatedUser = user
+		return user, nil
+	})
+
+	var updatedAttribs *v3.UserAttribute
+	userAttributes := fake.NewMockNonNamespacedControllerInterface[*v3.UserAttribute, *v3.UserAttributeList](ctrl)
+	userAttributes.EXPECT().Get(userID, gomock.Any()).Return(attribs.DeepCopy(), nil)
+	userAttributes.EXPECT().Update(gomock.Any()).DoAndReturn(func(attribs *v3.UserAttribute) (*v3.UserAttribute, error) {
+		updatedAttribs = attribs
+		return attribs, nil
+	})
+
+	labeler := &Labeler{
+		ctx:            context.Background(),
+		users:          users,
+		userAttributes: userAttributes,
+		mappings: func() []Mapping {
+			return []Mapping{
+				{Attribute: "department", Label: "example.com/department"},
+				{Attribute: "costCenter", Label: "example.com/cost-center"},
+				{Attribute: "c", Label: "example.com/country"},
+			}
+		},
+	}
+
+	err := labeler.EnsureForAttributes(attribs)
+	require.NoError(t, err)
+
+	wantLabels := map[string]string{
+		"example.com/department":  "Research---Development",
+		"example.com/cost-center": "CC-1234",
+	}
+	wantAnnotations := map[string]string{
+		ManagedAnnotation:         "example.com/cost-center,example.com/department",
+		"example.com/department":  "Research & Development",
+		"example.com/cost-center": "CC-1234",
+	}
+
+	require.NotNil(t, updatedUser)
+	assert.Equal(t, map[string]string{
+		"example.com/department":  "Research---Development",
+		"example.com/cost-center": "CC-1234",
+		"team":                    "blue",
+	}, updatedUser.Labels)
+	assert.Equal(t, wantAnnotations, updatedUser.Annotations)
+
+	require.NotNil(t, updatedAttribs)
+	assert.Equal(t, wantLabels, updatedAttribs.Labels)
+	assert.Equal(t, wantAnnotations, updatedAttribs.Annotations)
+}
+
+func TestEnsureForAttributesUnchanged(t *testing.T) {
+	userID := "u-abcdef"
+	meta := metav1.ObjectMeta{
+		Name:        userID,
+		Labels:      map[string]string{"example.com/department": "Sales"},
+		Annotations: map[string]string{ManagedAnnotation: "example.com/department", "example.com/department": "Sales"},
+	}
+	attribs := &v3.UserAttribute{
+		ObjectMeta: meta,
+		ExtraByProvider: map[string]map[string][]string{
+			"openldap": {"department": {"Sales"}},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+
+	users := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+	users.EXPECT().Get(userID, gomock.Any()).Return(&v3.User{ObjectMeta: *meta.DeepCopy()}, nil)
+	users.EXPECT().Update(gomock.Any()).Times(0)
+
+	userAttributes := fake.NewMockNonNamespacedControllerInterface[*v3.UserAttribute, *v3.UserAttributeList](ctrl)
+	userAttributes.EXPECT().Get(userID, gomock.Any()).Return(attribs.DeepCopy(), nil)
+	userAttributes.EXPECT().Update(gomock.Any()).Times(0)
+
+	labeler := &Labeler{
+		ctx:            context.Background(),
+		users:          users,
+		userAttributes: userAttributes,
+		mappings: func() []Mapping {
+			return []Mapping{{Attribute: "department", Label: "example.com/department"}}
+		},
+	}
+
+	err := labeler.EnsureForAttributes(attribs)
+	require.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package attributelabels
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Mapping maps an attribute reported by an auth provider to a label key.
+type Mapping struct {
+	Attribute string
+	Label     string
+}
+
+var invalidLabelValueChars = regexp.MustCompile(`[^-A-Za-z0-9_.]`)
+
+// ParseMappings parses a comma separated list of attribute=label-key mappings.
+func ParseMappings(value string) ([]Mapping, error) {
+	var mappings []Mapping
+	seen := map[string]bool{}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		attribute, label, ok := strings.Cut(entry, "=")
+		attribute, label = strings.TrimSpace(attribute), strings.TrimSpace(label)
+		if !ok || attribute == "" || label == "" {
+			return nil, fmt.Errorf("invalid mapping %q: expected attribute=label-key", entry)
+		}
+		if errs := validation.IsQualifiedName(label); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %q: %s", label, strings.Join(errs, "; "))
+		}
+		if seen[label] {
+			return nil, fmt.Errorf("label key %q is mapped more than once", label)
+		}
+		seen[label] = true
+
+		mappings = append(mappings, Mapping{Attribute: attribute, Label: label})
+	}
+
+	return mappings, nil
+}
+
+// Mappings returns the mappings configured in the user-attribute-labels setting.
+// An invalid setting disables the feature.
+func Mappings() []Mapping {
+	mappings, err := ParseMappings(settings.UserAttributeLabels.Get())
+	if err != nil {
+		logrus.Errorf("attributelabels: error parsing setting %s, attribute labels are disabled: %v", settings.UserAttributeLabels.Name, err)
+		return nil
+	}
+	return mappings
+}
+
+// Attributes returns the names of the provider attributes that are mapped to labels.
+func Attributes() []string {
+	var attributes []string
+	seen := map[string]bool{}
+	for _, mapping := range Mappings() {
+		if !seen[mapping.Attribute] {
+			seen[mapping.Attribute] = true
+			attributes = append(attributes, mapping.Attribute)
+		}
+	}
+	return attributes
+}
+
+// ExtraInfo returns the values of the mapped attributes, to be set as the extra info of a user principal.
+// Only the first value of a multi-valued attribute is kept.
+func ExtraInfo(values func(attribute string) []string) map[string]string {
+	var info map[string]string
+	for _, attribute := range Attributes() {
+		vals := values(attribute)
+		if len(vals) == 0 || vals[0] == "" {
+			continue
+		}
+		if info == nil {
+			info = map[string]string{}
+		}
+		info[attribute] = vals[0]
+	}
+	return info
+}
+
+// LabelValue converts an attribute value to a valid label value.
+// It returns an empty string if nothing valid is left.
+func LabelValue(value string) string {
+	value = invalidLabelValueChars.ReplaceAllString(value, "-")
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	return strings.TrimFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+}
@@ -0,0 +1,221 @@
+// Package attributemembership syncs the project memberships granted by the directory attribute rules of the projects.
+// The users matching a rule are searched on its auth provider, and are granted its role in the project until they no
+// longer match it.
+package attributemembership
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/groupdisplaynames"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// RuleLabel is set on the ProjectRoleTemplateBindings granted by a directory attribute rule to the hash of the rule.
+// Other bindings are left untouched.
+const RuleLabel = "auth.cattle.io/directory-attribute-rule"
+
+// Syncer creates and deletes the ProjectRoleTemplateBindings of the users matching the directory attribute rules of
+// the projects. The bindings of a rule are left as they are while its users can't be searched.
+type Syncer struct {
+	projectCache          mgmtcontrollers.ProjectCache
+	prtbCache             mgmtcontrollers.ProjectRoleTemplateBindingCache
+	prtbs                 mgmtcontrollers.ProjectRoleTemplateBindingClient
+	enabledProviders      func() []string
+	userAttributeSearcher func(providerName string) common.UserAttributeSearcher
+}
+
+// New creates a new instance of Syncer.
+func New(wContext *wrangler.Context) *Syncer {
+	return &Syncer{
+		projectCache:          wContext.Mgmt.Project().Cache(),
+		prtbCache:             wContext.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		prtbs:                 wContext.Mgmt.ProjectRoleTemplateBinding(),
+		enabledProviders:      providers.EnabledProviders,
+		userAttributeSearcher: providers.GetUserAttributeSearcher,
+	}
+}
+
+// RuleHash returns the value of the RuleLabel of the bindings granted by a rule.
+func RuleHash(rule v3.DirectoryAttributeMembership) string {
+	return name.Hex(fmt.Sprintf("%s/%s=%s/%s", rule.Provider, rule.Attribute, rule.Value, rule.RoleTemplateName), 10)
+}
+
+// Run the sync of the project memberships.
+func (s *Syncer) Run(ctx context.Context) error {
+	if ctx.Err() != nil {
+		logrus.Info("attributemembership: context canceled, quitting")
+		return nil
+	}
+
+	startedAt := time.Now()
+
+	enabled := map[string]bool{}
+	for _, providerName := range s.enabledProviders() {
+		enabled[providerName] = true
+	}
+
+	projects, err := s.projectCache.List("", labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing projects: %w", err)
+	}
+
+	logrus.Info("attributemembership: started")
+
+	var granted, revoked, errCount int
+
+	defer func() {
+		logrus.Infof(
+			"attributemembership: finished in %v seconds (projects %d, granted %d, revoked %d, errors %d)",
+			time.Since(startedAt).Seconds(),
+			len(projects), granted, revoked, errCount,
+		)
+	}()
+
+	// Users are searched once per rule, however many projects share it.
+	searched := map[v3.DirectoryAttributeMembership][]v3.Principal{}
+	failed := map[v3.DirectoryAttributeMembership]bool{}
+	search := func(rule v3.DirectoryAttributeMembership) ([]v3.Principal, bool) {
+		searchRule := rule
+		searchRule.RoleTemplateName = ""
+		if failed[searchRule] {
+			return nil, false
+		}
+		if principals, ok := searched[searchRule]; ok {
+			return principals, true
+		}
+		principals, err := s.search(enabled, searchRule)
+		if err != nil {
+			logrus.Errorf("attributemembership: error searching the users with %s=%s on %s: %v", rule.Attribute, rule.Value, rule.Provider, err)
+			failed[searchRule] = true
+			errCount++
+			return nil, false
+		}
+		searched[searchRule] = principals
+		return principals, true
+	}
+
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			logrus.Info("attributemembership: context canceled, quitting")
+			return nil
+		}
+		if project.DeletionTimestamp != nil {
+			continue
+		}
+
+		desired, skipped := desiredBindings(project, search)
+		g, r, errs := s.reconcile(project, desired, skipped)
+		granted += g
+		revoked += r
+		errCount += errs
+	}
+
+	return nil
+}
+
+// search returns the user principals matching the attribute and value of the rule.
+func (s *Syncer) search(enabled map[string]bool, rule v3.DirectoryAttributeMembership) ([]v3.Principal, error) {
+	if !enabled[rule.Provider] {
+		return nil, fmt.Errorf("provider is not enabled")
+	}
+	searcher := s.userAttributeSearcher(rule.Provider)
+	if searcher == nil {
+		return nil, fmt.Errorf("provider can't search its users by attribute")
+	}
+	return searcher.SearchUsersByAttribute(rule.Attribute, rule.Value)
+}
+
+// desiredBindings returns the bindings granted by the rules of the project, by name, and the hashes of the rules whose
+// users couldn't be searched.
+func desiredBindings(project *v3.Project, search func(v3.DirectoryAttributeMembership) ([]v3.Principal, bool)) (map[string]*v3.ProjectRoleTemplateBinding, map[string]bool) {
+	desired := map[string]*v3.ProjectRoleTemplateBinding{}
+	skipped := map[string]bool{}
+	for _, rule := range project.Spec.DirectoryAttributeMemberships {
+		hash := RuleHash(rule)
+		principals, ok := search(rule)
+		if !ok {
+			skipped[hash] = true
+			continue
+		}
+		for _, principal := range principals {
+			binding := &v3.ProjectRoleTemplateBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name.SafeConcatName("dirattr", name.Hex(hash+"/"+principal.Name, 10)),
+					Namespace:   project.Name,
+					Labels:      map[string]string{RuleLabel: hash},
+					Annotations: map[string]string{groupdisplaynames.DisplayNameAnnotation: principal.DisplayName},
+				},
+				ProjectName:       project.Namespace + ":" + project.Name,
+				UserPrincipalName: principal.Name,
+				RoleTemplateName:  rule.RoleTemplateName,
+			}
+			provenance.Set(&binding.ObjectMeta, provenance.SourceAttributeRule, provenance.Creator("Project", project.Name))
+			desired[binding.Name] = binding
+		}
+	}
+	return desired, skipped
+}
+
+// reconcile deletes the bindings of the project granted by rules which no longer grant them, unless the users of the
+// rule couldn't be searched, and creates the missing ones. It returns the number of bindings created and deleted, and
+// of errors.
+func (s *Syncer) reconcile(project *v3.Project, desired map[string]*v3.ProjectRoleTemplateBinding, skipped map[string]bool) (int, int, int) {
+	var granted, revoked, errCount int
+
+	granting, err := labels.NewRequirement(RuleLabel, selection.Exists, nil)
+	if err != nil {
+		logrus.Errorf("attributemembership: error selecting the bindings of project %s: %v", project.Name, err)
+		return 0, 0, 1
+	}
+	existing, err := s.prtbCache.List(project.Name, labels.NewSelector().Add(*granting))
+	if err != nil {
+		logrus.Errorf("attributemembership: error listing the bindings of project %s: %v", project.Name, err)
+		return 0, 0, 1
+	}
+	for _, binding := range existing {
+		if want, ok := desired[binding.Name]; ok && binding.UserPrincipalName == want.UserPrincipalName && binding.RoleTemplateName == want.RoleTemplateName {
+			delete(desired, binding.Name)
+			continue
+		}
+		if skipped[binding.Labels[RuleLabel]] || binding.DeletionTimestamp != nil || !provenance.Owns(binding, provenance.SourceAttributeRule) {
+			delete(desired, binding.Name)
+			continue
+		}
+		if err := s.prtbs.Delete(binding.Namespace, binding.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			logrus.Errorf("attributemembership: error deleting binding %s/%s: %v", binding.Namespace, binding.Name, err)
+			errCount++
+			continue
+		}
+		logrus.Infof("attributemembership: revoked role %s of %s in project %s", binding.RoleTemplateName, binding.UserPrincipalName, binding.ProjectName)
+		revoked++
+	}
+
+	for _, binding := range desired {
+		if _, err := s.prtbs.Create(binding); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// The binding being replaced is still being deleted, it's created on the next run.
+				continue
+			}
+			logrus.Errorf("attributemembership: error creating binding %s/%s: %v", binding.Namespace, binding.Name, err)
+			errCount++
+			continue
+		}
+		logrus.Infof("attributemembership: granted role %s to %s in project %s", binding.RoleTemplateName, binding.UserPrincipalName, binding.ProjectName)
+		granted++
+	}
+
+	return granted, revoked, errCount
+}
@@ -0,0 +1,148 @@
+package attributemembership
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/groupdisplaynames"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type fakeSearcher map[string][]v3.Principal
+
+func (f fakeSearcher) SearchUsersByAttribute(attribute, value string) ([]v3.Principal, error) {
+	principals, ok := f[attribute+"="+value]
+	if !ok {
+		return nil, fmt.Errorf("search failed")
+	}
+	return principals, nil
+}
+
+func TestRun(t *testing.T) {
+	const (
+		alice = "openldap_user://uid=alice"
+		bob   = "openldap_user://uid=bob"
+		carol = "openldap_user://uid=carol"
+	)
+	finance := v3.DirectoryAttributeMembership{Provider: "openldap", Attribute: "departmentNumber", Value: "4200", RoleTemplateName: "project-member"}
+	broken := v3.DirectoryAttributeMembership{Provider: "openldap", Attribute: "departmentNumber", Value: "9999", RoleTemplateName: "project-member"}
+	removed := v3.DirectoryAttributeMembership{Provider: "openldap", Attribute: "departmentNumber", Value: "1000", RoleTemplateName: "project-owner"}
+	disabled := v3.DirectoryAttributeMembership{Provider: "github", Attribute: "company", Value: "example", RoleTemplateName: "project-member"}
+
+	bindingName := func(rule v3.DirectoryAttributeMembership, principalID string) string {
+		return name.SafeConcatName("dirattr", name.Hex(RuleHash(rule)+"/"+principalID, 10))
+	}
+	existingBinding := func(rule v3.DirectoryAttributeMembership, principalID string) *v3.ProjectRoleTemplateBinding {
+		return &v3.ProjectRoleTemplateBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bindingName(rule, principalID),
+				Namespace: "p-finance",
+				Labels:    map[string]string{RuleLabel: RuleHash(rule)},
+			},
+			ProjectName:       "c-abcde:p-finance",
+			UserName:          "u-" + principalID,
+			UserPrincipalName: principalID,
+			RoleTemplateName:  rule.RoleTemplateName,
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	projectCache := fake.NewMockCacheInterface[*v3.Project](ctrl)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+
+	projectCache.EXPECT().List("", labels.Everything()).Return([]*v3.Project{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "p-finance", Namespace: "c-abcde"},
+			Spec: v3.ProjectSpec{
+				ClusterName:                   "c-abcde",
+				DirectoryAttributeMemberships: []v3.DirectoryAttributeMembership{finance, broken, disabled},
+			},
+		},
+	}, nil)
+	prtbCache.EXPECT().List("p-finance", gomock.Any()).Return([]*v3.ProjectRoleTemplateBinding{
+		existingBinding(finance, alice),
+		existingBinding(finance, carol),
+		existingBinding(broken, carol),
+		existingBinding(disabled, carol),
+		existingBinding(removed, carol),
+	}, nil)
+
+	deleted := map[string]bool{}
+	prtbs.EXPECT().Delete("p-finance", gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(_, name string, _ *metav1.DeleteOptions) error {
+		deleted[name] = true
+		return nil
+	})
+	var created *v3.ProjectRoleTemplateBinding
+	prtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(binding *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+		created = binding
+		return binding, nil
+	})
+
+	syncer := &Syncer{
+		projectCache:     projectCache,
+		prtbCache:        prtbCache,
+		prtbs:            prtbs,
+		enabledProviders: func() []string { return []string{"openldap"} },
+		userAttributeSearcher: func(providerName string) common.UserAttributeSearcher {
+			if providerName != "openldap" {
+				return nil
+			}
+			return fakeSearcher{
+				"departmentNumber=4200": {
+					{ObjectMeta: metav1.ObjectMeta{Name: alice}, DisplayName: "Alice"},
+					{ObjectMeta: metav1.ObjectMeta{Name: bob}, DisplayName: "Bob"},
+				},
+			}
+		},
+	}
+
+	err := syncer.Run(context.Background())
+	require.NoError(t, err)
+
+	// Carol left the department and the rule granting the owner role was removed, while the bindings of the rules
+	// whose users couldn't be searched are kept.
+	assert.Equal(t, map[string]bool{
+		bindingName(finance, carol): true,
+		bindingName(removed, carol): true,
+	}, deleted)
+
+	require.NotNil(t, created)
+	assert.Equal(t, bindingName(finance, bob), created.Name)
+	assert.Equal(t, "p-finance", created.Namespace)
+	assert.Equal(t, RuleHash(finance), created.Labels[RuleLabel])
+	assert.Equal(t, "Bob", created.Annotations[groupdisplaynames.DisplayNameAnnotation])
+	assert.Equal(t, provenance.SourceAttributeRule, created.Labels[provenance.SourceLabel])
+	assert.Equal(t, "c-abcde:p-finance", created.ProjectName)
+	assert.Equal(t, bob, created.UserPrincipalName)
+	assert.Equal(t, "project-member", created.RoleTemplateName)
+}
+
+func TestRuleHash(t *testing.T) {
+	rule := v3.DirectoryAttributeMembership{Provider: "openldap", Attribute: "departmentNumber", Value: "4200", RoleTemplateName: "project-member"}
+	assert.Len(t, RuleHash(rule), 10)
+	assert.Equal(t, RuleHash(rule), RuleHash(rule))
+
+	other := rule
+	other.RoleTemplateName = "project-owner"
+	assert.NotEqual(t, RuleHash(rule), RuleHash(other))
+}
+
+func TestRunCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	syncer := &Syncer{}
+	err := syncer.Run(ctx)
+	require.NoError(t, err)
+}
@@ -17,6 +17,7 @@
 
 	"github.com/pborman/uuid"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/auditsinks"
 	"github.com/sirupsen/logrus"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apiserver/pkg/endpoints/request"
@@ -55,7 +56,7 @@
 	}
 	sensitiveRequestHeader  = []string{"Cookie", "Authorization", "X-Api-Tunnel-Params", "X-Api-Tunnel-Token", "X-Api-Auth-Header", "X-Amz-Security-Token"}
 	sensitiveResponseHeader = []string{"Cookie", "Set-Cookie", "X-Api-Set-Cookie-Header"}
-	sensitiveBodyFields     = []string{"credentials", "applicationSecret", "oauthCredential", "serviceAccountCredential", "spKey", "spCert", "certificate", "privateKey"}
+	sensitiveBodyFields     = []string{"credentials", "applicationSecret", "applicationCertificate", "oauthCredential", "serviceAccountCredential", "spKey", "spCert", "certificate", "privateKey"}
 	// ErrUnsupportedEncoding is returned when the response encoding is unsupported
 	ErrUnsupportedEncoding = fmt.Errorf("unsupported encoding")
 	secretBaseType         = regexp.MustCompile(".\"baseType\":\"([A-Za-z]*[S|s]ecret)\".")
@@ -66,6 +67,12 @@ type auditLog struct {
 	writer            *LogWriter
 	reqBody           []byte
 	keysToRedactRegex *regexp.Regexp
+	// requestURI is the request URI before the redaction of its query parameters.
+	requestURI string
+	// policy is the audit-log-redaction-policy setting for the request URI.
+	policy *redactionPolicy
+	// level is the level of the request, from the AuditPolicies or the audit-level flag.
+	level Level
 }
 
 type log struct {
@@ -95,6 +102,12 @@ type User struct {
 	RequestUser string `json:"requestUser,omitempty"`
 	// RequestGroups is the --as-group list
 	RequestGroups []string `json:"requestGroups,omitempty"`
+	// RequestExtra is the --as-user-extra map
+	RequestExtra map[string][]string `json:"requestExtra,omitempty"`
+	// Impersonator is the authenticated user that impersonated the request user
+	Impersonator string `json:"impersonator,omitempty"`
+	// ImpersonatorGroups are the groups of the impersonator
+	ImpersonatorGroups []string `json:"impersonatorGroups,omitempty"`
 }
 
 func getUserInfo(req *http.Request) *User {
@@ -122,9 +135,10 @@ func FromContext(ctx context.Context) (*User, bool) {
 	return u, ok
 }
 
-func newAuditLog(writer *LogWriter, req *http.Request, keysToRedactRegex *regexp.Regexp) (*auditLog, error) {
+func newAuditLog(writer *LogWriter, level Level, req *http.Request, keysToRedactRegex *regexp.Regexp) (*auditLog, error) {
 	auditLog := &auditLog{
 		writer: writer,
+		level:  level,
 		log: &log{
 			AuditID:          k8stypes.UID(uuid.NewRandom().String()),
 			RequestURI:       req.RequestURI,
@@ -133,11 +147,14 @@ func newAuditLog(writer *LogWriter, req *http.Request, keysToRedactRegex *regexp
 			RequestTimestamp: time.Now().Format(time.RFC3339),
 		},
 		keysToRedactRegex: keysToRedactRegex,
+		requestURI:        req.RequestURI,
+		policy:            redactionPolicies.current().forURI(req.RequestURI),
 	}
+	auditLog.log.RequestURI = auditLog.policy.redactURI(req.RequestURI)
 
 	contentType := req.Header.Get("Content-Type")
 	loginReq := isLoginRequest(req.RequestURI)
-	if writer.Level >= LevelRequest || loginReq {
+	if level >= LevelRequest || loginReq {
 		if bodyMethods[req.Method] && strings.HasPrefix(contentType, contentTypeJSON) {
 			reqBody, err := readBodyWithoutLosingContent(req)
 			if err != nil {
@@ -149,7 +166,7 @@ func newAuditLog(writer *LogWriter, req *http.Request, keysToRedactRegex *regexp
 					auditLog.log.UserLoginName = loginName
 				}
 			}
-			if writer.Level >= LevelRequest {
+			if level >= LevelRequest {
 				auditLog.reqBody = reqBody
 			}
 		}
@@ -162,6 +179,8 @@ func (a *auditLog) write(userInfo *User, reqHeaders, resHeaders http.Header, res
 	a.log.ResponseTimestamp = time.Now().Format(time.RFC3339)
 	a.log.RequestHeader = filterOutHeaders(reqHeaders, sensitiveRequestHeader)
 	a.log.ResponseHeader = filterOutHeaders(resHeaders, sensitiveResponseHeader)
+	a.policy.redactHeaders(a.log.RequestHeader)
+	a.policy.redactHeaders(a.log.ResponseHeader)
 	a.log.ResponseCode = resCode
 
 	if a.log.UserLoginName != "" {
@@ -194,6 +213,13 @@ func (a *auditLog) write(userInfo *User, reqHeaders, resHeaders http.Header, res
 		return fmt.Errorf("failed to compact audit log: %w", err)
 	}
 
+	auditsinks.Publish(auditsinks.Entry{
+		Source: auditsinks.SourceAudit,
+		Type:   auditsinks.TypeAPIRequest,
+		Time:   time.Now(),
+		Data:   json.RawMessage(bytes.Clone(compactBuffer.Bytes())),
+	})
+
 	compactBuffer.WriteString("\n")
 
 	_, err = a.writer.Output.Write(compactBuffer.Bytes())
@@ -206,17 +232,17 @@ func (a *auditLog) write(userInfo *User, reqHeaders, resHeaders http.Header, res
 
 // writeRequest attempts to write the API request to the log message.
 func (a *auditLog) writeRequest(buf *bytes.Buffer) {
-	if a.writer.Level < LevelRequest || len(a.reqBody) == 0 {
+	if a.level < LevelRequest || len(a.reqBody) == 0 {
 		return
 	}
 
 	buf.WriteString(`,"requestBody":`)
-	buf.Write(bytes.TrimSuffix(a.redactSensitiveData(a.log.RequestURI, a.reqBody), []byte("\n")))
+	buf.Write(bytes.TrimSuffix(a.redactSensitiveData(a.requestURI, a.reqBody), []byte("\n")))
 }
 
 // writeResponse attempt to write the API response to the log message.
 func (a *auditLog) writeResponse(buf *bytes.Buffer, resHeaders http.Header, resBody []byte) (err error) {
-	if a.writer.Level < LevelRequestResponse || resHeaders.Get("Content-Type") != contentTypeJSON || len(resBody) == 0 {
+	if a.level < LevelRequestResponse || resHeaders.Get("Content-Type") != contentTypeJSON || len(resBody) == 0 {
 		return nil
 	}
 
@@ -238,7 +264,7 @@ func (a *auditLog) writeResponse(buf *bytes.Buffer, resHeaders http.Header, resB
 	}
 
 	buf.WriteString(`,"responseBody":`)
-	buf.Write(bytes.TrimSuffix(a.redactSensitiveData(a.log.RequestURI, resBody), []byte("\n")))
+	buf.Write(bytes.TrimSuffix(a.redactSensitiveData(a.requestURI, resBody), []byte("\n")))
 
 	return nil
 }
@@ -313,7 +339,10 @@ func (a *auditLog) redactSensitiveData(requestURI string, body []byte) []byte {
 	}
 
 	// Redact values for data considered sensitive: passwords, tokens, etc.
-	if !a.redactMap(m) && !changed {
+	changed = a.redactMap(m) || changed
+	// Redact the fields of the audit-log-redaction-policy setting.
+	changed = a.policy.redactBody(m) || changed
+	if !changed {
 		return body
 	}
 
@@ -14,8 +14,10 @@
 	"strings"
 	"testing"
 
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/data/management"
 	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var errAny = errors.New("any error is allowed")
@@ -247,7 +249,7 @@ func (a *AuditTest) TestCompression() {
 	req, err := http.NewRequest(http.MethodGet, "/test", nil)
 	a.Require().NoErrorf(err, "Failed to create request: %v", err)
 
-	auditLog, err := newAuditLog(writer, req, sensitiveRegex)
+	auditLog, err := newAuditLog(writer, writer.Level, req, sensitiveRegex)
 	a.Require().NoErrorf(err, "Failed to create AuditLog: %v", err)
 
 	const testString = "{\"test\":\"response\"}"
@@ -364,7 +366,7 @@ func (a *AuditTest) TestCompression() {
 	for i := range tests {
 		test := tests[i]
 		a.Run(test.name, func() {
-			writer.Level = test.level
+			auditLog.level = test.level
 			auditLog.reqBody = []byte(test.reqBody)
 			// write the test to the audit logger
 			err := auditLog.write(nil, req.Header, test.respHeader, test.returnCode, test.respBody)
@@ -412,7 +414,7 @@ func (a *AuditTest) TestFilterSensitiveHeader() {
 	req, err := http.NewRequest(http.MethodGet, "/test", nil)
 	a.Require().NoErrorf(err, "Failed to create request: %v", err)
 
-	auditLog, err := newAuditLog(writer, req, sensitiveRegex)
+	auditLog, err := newAuditLog(writer, writer.Level, req, sensitiveRegex)
 	a.Require().NoErrorf(err, "Failed to create AuditLog: %v", err)
 
 	tests := []struct {
@@ -481,11 +483,11 @@ func (a *AuditTest) TestFilterSensitiveHeader() {
 			expectedRespHeader: http.Header{"Content-Type": []string{"application/json"}, "Content-Encoding": []string{"none"}},
 		},
 	}
-	writer.Level = LevelMetadata
+	auditLog.level = LevelMetadata
 	for i := range tests {
 		test := tests[i]
 		a.Run(test.name, func() {
-			writer.Level = 1
+			auditLog.level = 1
 			// write the test to the audit logger
 			auditLog.log.RequestHeader = test.reqHeader
 			err := auditLog.write(nil, test.reqHeader, test.respHeader, 0, []byte{})
@@ -501,6 +503,166 @@ func (a *AuditTest) TestFilterSensitiveHeader() {
 	}
 }
 
+func (a *AuditTest) TestParseRedactionPolicy() {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "valid", value: `{"rules":[{"headers":["X-Secret-.*"],"paths":["/spec/clientSecret"],"queryParameters":["code"]},{"uris":["^/v3/tokens"],"action":"keep","headers":["X-Secret-Id"]}]}`},
+		{name: "invalid json", value: `{`, wantErr: true},
+		{name: "invalid action", value: `{"rules":[{"action":"hide","headers":["X-Secret"]}]}`, wantErr: true},
+		{name: "invalid header", value: `{"rules":[{"headers":["("]}]}`, wantErr: true},
+		{name: "invalid uri", value: `{"rules":[{"uris":["("]}]}`, wantErr: true},
+		{name: "relative path", value: `{"rules":[{"paths":["spec/clientSecret"]}]}`, wantErr: true},
+		{name: "invalid path token", value: `{"rules":[{"paths":["/spec/("]}]}`, wantErr: true},
+	}
+	for _, test := range tests {
+		a.Run(test.name, func() {
+			_, err := parseRedactionPolicy(test.value)
+			if test.wantErr {
+				a.Error(err)
+			} else {
+				a.NoError(err)
+			}
+		})
+	}
+}
+
+func (a *AuditTest) TestRedactionPolicy() {
+	policy, err := parseRedactionPolicy(`{"rules":[
+		{"headers":["X-Secret-.*"],"paths":["/spec/clientSecret","/data/[0-9]+/spec/clientSecret"],"queryParameters":["code"]},
+		{"action":"drop","headers":["X-Internal"],"paths":["/metadata/annotations/field\\.cattle\\.io~1.*"],"queryParameters":["state"]},
+		{"uris":["^/v3/tokens"],"action":"keep","headers":["X-Secret-Id"],"paths":["/spec/clientSecret"]}
+	]}`)
+	a.Require().NoError(err)
+
+	a.Run("headers", func() {
+		headers := http.Header{"X-Secret-Key": {"abcd"}, "X-Secret-Id": {"id"}, "X-Internal": {"1"}, "User-Agent": {"useragent1"}}
+		policy.forURI("/v3/settings").redactHeaders(headers)
+		a.Equal(http.Header{"X-Secret-Key": {redacted}, "X-Secret-Id": {redacted}, "User-Agent": {"useragent1"}}, headers)
+	})
+
+	a.Run("headers of an endpoint override", func() {
+		headers := http.Header{"X-Secret-Key": {"abcd"}, "X-Secret-Id": {"id"}}
+		policy.forURI("/v3/tokens/token-abcde").redactHeaders(headers)
+		a.Equal(http.Header{"X-Secret-Key": {redacted}, "X-Secret-Id": {"id"}}, headers)
+	})
+
+	a.Run("query parameters", func() {
+		a.Equal("/v1-saml/callback?code=%5Bredacted%5D&other=1", policy.forURI("/").redactURI("/v1-saml/callback?code=abcd&other=1&state=xyz"))
+		a.Equal("/v3/settings?limit=1", policy.forURI("/").redactURI("/v3/settings?limit=1"))
+		a.Equal("/v3/settings", policy.forURI("/").redactURI("/v3/settings"))
+	})
+
+	a.Run("body", func() {
+		var body map[string]interface{}
+		a.Require().NoError(json.Unmarshal([]byte(`{
+			"metadata":{"annotations":{"field.cattle.io/creatorId":"u-abcde","other":"1"}},
+			"spec":{"clientSecret":"abcd","clientId":"id"},
+			"data":[{"spec":{"clientSecret":"efgh"}}]
+		}`), &body))
+		a.True(policy.forURI("/v3/authconfigs").redactBody(body))
+		want := `{
+			"metadata":{"annotations":{"other":"1"}},
+			"spec":{"clientSecret":"[redacted]","clientId":"id"},
+			"data":[{"spec":{"clientSecret":"[redacted]"}}]
+		}`
+		got, err := json.Marshal(body)
+		a.Require().NoError(err)
+		a.JSONEq(want, string(got))
+	})
+
+	a.Run("body of an endpoint override", func() {
+		body := map[string]interface{}{"spec": map[string]interface{}{"clientSecret": "abcd"}}
+		a.False(policy.forURI("/v3/tokens").redactBody(body))
+		a.Equal("abcd", body["spec"].(map[string]interface{})["clientSecret"])
+	})
+
+	a.Run("no policy", func() {
+		var none *redactionPolicy
+		headers := http.Header{"X-Secret-Key": {"abcd"}}
+		none.forURI("/").redactHeaders(headers)
+		a.Equal(http.Header{"X-Secret-Key": {"abcd"}}, headers)
+		a.Equal("/?code=abcd", none.forURI("/").redactURI("/?code=abcd"))
+		a.False(none.forURI("/").redactBody(map[string]interface{}{"spec": "abcd"}))
+	})
+}
+
+func (a *AuditTest) TestRedactSensitiveDataWithPolicy() {
+	r, err := constructKeyRedactRegex()
+	a.Require().NoError(err, "failed compiling sanitizing regex")
+	policy, err := parseRedactionPolicy(`{"rules":[{"paths":["/spec/clientSecret"]},{"action":"keep","paths":["/password"]}]}`)
+	a.Require().NoError(err)
+	logger := auditLog{
+		keysToRedactRegex: r,
+		policy:            policy.forURI("/v3/authconfigs"),
+	}
+
+	got := logger.redactSensitiveData("/v3/authconfigs", []byte(`{"password":"abcd","spec":{"clientSecret":"efgh","clientId":"id"}}`))
+
+	// The policy doesn't override the built-in redactions.
+	a.JSONEq(fmt.Sprintf(`{"password":"%s","spec":{"clientSecret":"%s","clientId":"id"}}`, redacted, redacted), string(got))
+}
+
+func (a *AuditTest) TestAuditPolicyLevels() {
+	policy := compileAuditPolicies([]*v3.AuditPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "b-default"},
+			Spec: v3.AuditPolicySpec{Rules: []v3.AuditPolicyRule{
+				{Level: v3.AuditLevelRequest, Verbs: []string{"create", "update", "patch", "delete"}},
+				{Level: "Everything"},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a-overrides"},
+			Spec: v3.AuditPolicySpec{Rules: []v3.AuditPolicyRule{
+				{Level: v3.AuditLevelRequestResponse, Resources: []string{"secrets"}, UserGroups: []string{"auditors"}},
+				{Level: v3.AuditLevelMetadata, Resources: []string{"secrets"}},
+				{Level: v3.AuditLevelNone, Resources: []string{"*"}, Verbs: []string{"watch"}},
+				{Level: v3.AuditLevelRequestResponse, Users: []string{"u-abcde"}},
+			}},
+		},
+	})
+	a.Require().Len(policy.rules, 5, "the rule with an invalid level must be skipped")
+
+	user := &User{Name: "u-other", Group: []string{"system:authenticated"}}
+	tests := []struct {
+		name   string
+		method string
+		uri    string
+		user   *User
+		want   Level
+	}{
+		{name: "kube secrets", method: http.MethodGet, uri: "/k8s/clusters/c-abcde/api/v1/namespaces/default/secrets/s", user: user, want: LevelMetadata},
+		{name: "steve secrets of a group", method: http.MethodGet, uri: "/v1/secrets", user: &User{Name: "u-other", Group: []string{"auditors"}}, want: LevelRequestResponse},
+		{name: "norman project secrets", method: http.MethodPost, uri: "/v3/project/c-abcde:p-abcde/secrets", user: user, want: LevelMetadata},
+		{name: "resources ignore the case", method: http.MethodGet, uri: "/v3/Secrets", user: user, want: LevelMetadata},
+		{name: "watch", method: http.MethodGet, uri: "/apis/apps/v1/deployments?watch=true", user: user, want: LevelNull},
+		{name: "subscribe", method: http.MethodGet, uri: "/v1/subscribe", user: user, want: LevelNull},
+		{name: "user", method: http.MethodGet, uri: "/v3/clusters", user: &User{Name: "u-abcde"}, want: LevelRequestResponse},
+		{name: "write of the next policy", method: http.MethodPut, uri: "/v1/management.cattle.io.settings/ui-brand", user: user, want: LevelRequest},
+		{name: "action", method: http.MethodPost, uri: "/v3/clusters/c-abcde?action=generateKubeconfig", user: user, want: LevelRequest},
+		{name: "not a resource", method: http.MethodGet, uri: "/v1-activity?watch=true", user: user, want: LevelMetadata},
+		{name: "default", method: http.MethodGet, uri: "/v3/clusters", user: user, want: LevelMetadata},
+	}
+	for _, test := range tests {
+		a.Run(test.name, func() {
+			req, err := http.NewRequest(test.method, test.uri, nil)
+			a.Require().NoError(err)
+			a.Equal(test.want, policy.levelOf(req, test.user, LevelMetadata))
+		})
+	}
+
+	a.Run("no policy", func() {
+		req, err := http.NewRequest(http.MethodGet, "/v3/secrets", nil)
+		a.Require().NoError(err)
+		a.Nil(compileAuditPolicies(nil))
+		a.Equal(LevelRequest, compileAuditPolicies(nil).levelOf(req, user, LevelRequest))
+	})
+}
+
 // addMeta adds expected log metadata to the expected log message.
 func (a *AuditTest) addMeta(log *log, reqHeader, respHeader http.Header, reqBody, respBody string) string {
 	data := map[string]interface{}{}
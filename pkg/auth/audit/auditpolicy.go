@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+var (
+	auditLevels = map[string]Level{
+		v32.AuditLevelNone:            LevelNull,
+		v32.AuditLevelMetadata:        LevelMetadata,
+		v32.AuditLevelRequest:         LevelRequest,
+		v32.AuditLevelRequestResponse: LevelRequestResponse,
+	}
+
+	kubeRequestInfoFactory = &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("apis", "api"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}
+)
+
+type levelRule struct {
+	level      Level
+	resources  []string
+	verbs      []string
+	users      []string
+	userGroups []string
+}
+
+// levelPolicy is the compiled rules of the AuditPolicies. A nil policy has no rules.
+type levelPolicy struct {
+	rules []levelRule
+}
+
+// levelPolicyCache holds the policy of the AuditPolicies, compiled again when they change.
+type levelPolicyCache struct {
+	mu     sync.RWMutex
+	policy *levelPolicy
+}
+
+var levelPolicies = &levelPolicyCache{}
+
+func (c *levelPolicyCache) current() *levelPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.policy
+}
+
+func (c *levelPolicyCache) set(policy *levelPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.policy = policy
+}
+
+// WatchAuditPolicies applies the AuditPolicies to the audit log as they change, so that the audit levels can be
+// changed without restarting. It must run on every replica, as they all serve requests.
+func WatchAuditPolicies(ctx context.Context, auditPolicies mgmtcontrollers.AuditPolicyController) {
+	auditPolicies.OnChange(ctx, "audit-policies", func(_ string, _ *v32.AuditPolicy) (*v32.AuditPolicy, error) {
+		// All the policies are compiled again, as the order of their rules depends on the other policies.
+		policies, err := auditPolicies.Cache().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		levelPolicies.set(compileAuditPolicies(policies))
+		return nil, nil
+	})
+}
+
+// compileAuditPolicies compiles the rules of the policies, in the order of their names. The rules with an invalid
+// level, which the CRD doesn't allow, are skipped.
+func compileAuditPolicies(policies []*v32.AuditPolicy) *levelPolicy {
+	policies = slices.Clone(policies)
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+
+	compiled := &levelPolicy{}
+	for _, policy := range policies {
+		for i, rule := range policy.Spec.Rules {
+			level, ok := auditLevels[rule.Level]
+			if !ok {
+				logrus.Errorf("[audit] skipping rule %d of AuditPolicy %s: invalid level %q", i, policy.Name, rule.Level)
+				continue
+			}
+			compiled.rules = append(compiled.rules, levelRule{
+				level:      level,
+				resources:  rule.Resources,
+				verbs:      rule.Verbs,
+				users:      rule.Users,
+				userGroups: rule.UserGroups,
+			})
+		}
+	}
+	if len(compiled.rules) == 0 {
+		return nil
+	}
+	return compiled
+}
+
+// levelOf returns the level of the first rule matching the request of the user, or the default level if none does.
+func (p *levelPolicy) levelOf(req *http.Request, user *User, defaultLevel Level) Level {
+	if p == nil {
+		return defaultLevel
+	}
+	resource, verb := requestAttributes(req)
+	for _, rule := range p.rules {
+		if rule.matches(resource, verb, user) {
+			return rule.level
+		}
+	}
+	return defaultLevel
+}
+
+func (r levelRule) matches(resource, verb string, user *User) bool {
+	if len(r.resources) > 0 && (resource == "" || !slices.ContainsFunc(r.resources, func(value string) bool {
+		return value == "*" || strings.EqualFold(value, resource)
+	})) {
+		return false
+	}
+	if len(r.verbs) > 0 && !slices.Contains(r.verbs, "*") && !slices.Contains(r.verbs, verb) {
+		return false
+	}
+	if len(r.users) > 0 && !slices.Contains(r.users, user.Name) {
+		return false
+	}
+	if len(r.userGroups) > 0 && !slices.ContainsFunc(user.Group, func(group string) bool {
+		return slices.Contains(r.userGroups, group)
+	}) {
+		return false
+	}
+	return true
+}
+
+// requestAttributes returns the resource and the verb of a request to the Kubernetes API of a cluster
+// (/k8s/clusters/<cluster>) or of the local cluster, to the Rancher API (/v3 and /v3-public) or to the Steve API (/v1).
+// The resource is "" for the other requests.
+func requestAttributes(req *http.Request) (string, string) {
+	path := req.URL.Path
+	if rest, ok := strings.CutPrefix(path, "/k8s/clusters/"); ok {
+		_, path, _ = strings.Cut(rest, "/")
+		path = "/" + path
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var resource string
+	if len(parts) > 1 {
+		resource = parts[1]
+	}
+
+	switch parts[0] {
+	case "api", "apis":
+		kubeReq := req.Clone(req.Context())
+		kubeReq.URL.Path = path
+		if info, err := kubeRequestInfoFactory.NewRequestInfo(kubeReq); err == nil && info.IsResourceRequest {
+			return info.Resource, info.Verb
+		}
+	case "v3", "v3-public":
+		// Collections are /v3/<type>, /v3/cluster/<cluster>/<type> and /v3/project/<project>/<type>.
+		collection := len(parts) == 2
+		if (resource == "cluster" || resource == "project") && len(parts) > 3 {
+			resource = parts[3]
+			collection = len(parts) == 4
+		}
+		return resource, restVerb(req, collection, resource == "subscribe")
+	case "v1":
+		// Steve types are <group>.<resource>, or <resource> for the core group.
+		if i := strings.LastIndex(resource, "."); i > 0 {
+			resource = resource[i+1:]
+		}
+		return resource, restVerb(req, len(parts) == 2, resource == "subscribe")
+	}
+	return "", restVerb(req, false, false)
+}
+
+// restVerb returns the verb of a request to the Rancher or Steve API.
+func restVerb(req *http.Request, collection, subscribe bool) string {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		if subscribe || req.URL.Query().Get("watch") == "true" {
+			return "watch"
+		}
+		if collection {
+			return "list"
+		}
+		return "get"
+	case http.MethodPost:
+		// Actions change the resource they're run on.
+		if req.URL.Query().Get("action") != "" {
+			return "update"
+		}
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	}
+	return strings.ToLower(req.Method)
+}
@@ -69,11 +69,16 @@ func (h auditHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	user := getUserInfo(req)
+	level := levelPolicies.current().levelOf(req, user, h.auditWriter.Level)
+	if level == LevelNull {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
 
 	context := context.WithValue(req.Context(), userKey, user)
 	req = req.WithContext(context)
 
-	auditLog, err := newAuditLog(h.auditWriter, req, h.sanitizingRegex)
+	auditLog, err := newAuditLog(h.auditWriter, level, req, h.sanitizingRegex)
 	if err != nil {
 		util.ReturnHTTPError(rw, req, http.StatusInternalServerError, err.Error())
 		return
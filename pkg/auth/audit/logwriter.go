@@ -7,6 +7,7 @@
 )
 
 type LogWriter struct {
+	// Level is the level of the requests which no AuditPolicy rule matches.
 	Level  Level
 	Output *lumberjack.Logger
 }
@@ -21,8 +22,10 @@ func (l *LogWriter) Start(ctx context.Context) {
 	}()
 }
 
+// NewLogWriter returns the writer of the audit log, or nil if there is no path. The writer is returned even if the
+// level is LevelNull, as the AuditPolicies can enable the audit log of some requests.
 func NewLogWriter(path string, level Level, maxAge, maxBackup, maxSize int) *LogWriter {
-	if path == "" || level == LevelNull {
+	if path == "" {
 		return nil
 	}
 
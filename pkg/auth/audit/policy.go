@@ -0,0 +1,319 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+)
+
+// The actions of the redaction rules.
+const (
+	// ActionRedact replaces the values with [redacted].
+	ActionRedact = "redact"
+	// ActionDrop removes the headers, fields and query parameters from the audit log.
+	ActionDrop = "drop"
+	// ActionKeep logs the values as they are, overriding the previous rules, e.g. for some endpoints. It doesn't
+	// override the built-in redactions.
+	ActionKeep = "keep"
+)
+
+// RedactionPolicy is the audit-log-redaction-policy setting. It lists the headers, body fields and query parameters
+// redacted or dropped from the audit log, in addition to the built-in ones.
+type RedactionPolicy struct {
+	Rules []RedactionRule `json:"rules,omitempty"`
+}
+
+// RedactionRule is a rule of a RedactionPolicy. When several rules match a value, the last one applies, so that the
+// rules of some endpoints can override the rules of all of them.
+type RedactionRule struct {
+	// URIs are regular expressions matching the request URIs the rule applies to. The rule applies to all of them if
+	// it's empty.
+	URIs []string `json:"uris,omitempty"`
+	// Action is ActionRedact, ActionDrop or ActionKeep, ActionRedact by default.
+	Action string `json:"action,omitempty"`
+	// Headers are regular expressions matching the names of the request and response headers, ignoring the case.
+	Headers []string `json:"headers,omitempty"`
+	// Paths are JSON pointers (RFC 6901) to the fields of the request and response bodies, whose reference tokens are
+	// regular expressions, e.g. /data/.*/spec/clientSecret. The indexes of the arrays are matched as strings.
+	Paths []string `json:"paths,omitempty"`
+	// QueryParameters are regular expressions matching the names of the query parameters of the request URI.
+	QueryParameters []string `json:"queryParameters,omitempty"`
+}
+
+type redactionRule struct {
+	uris            []*regexp.Regexp
+	action          string
+	headers         []*regexp.Regexp
+	paths           [][]*regexp.Regexp
+	queryParameters []*regexp.Regexp
+}
+
+// redactionPolicy is a compiled RedactionPolicy. A nil policy redacts nothing.
+type redactionPolicy struct {
+	rules []redactionRule
+}
+
+// parseRedactionPolicy parses and compiles the audit-log-redaction-policy setting.
+func parseRedactionPolicy(value string) (*redactionPolicy, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var policy RedactionPolicy
+	if err := json.Unmarshal([]byte(value), &policy); err != nil {
+		return nil, err
+	}
+
+	compiled := &redactionPolicy{}
+	for i, rule := range policy.Rules {
+		r := redactionRule{action: rule.Action}
+		switch rule.Action {
+		case "":
+			r.action = ActionRedact
+		case ActionRedact, ActionDrop, ActionKeep:
+		default:
+			return nil, fmt.Errorf("rule %d has an invalid action %q", i, rule.Action)
+		}
+
+		var err error
+		if r.uris, err = compileAll(rule.URIs, "%s"); err != nil {
+			return nil, fmt.Errorf("rule %d has an invalid uri: %w", i, err)
+		}
+		if r.headers, err = compileAll(rule.Headers, "(?i)^(?:%s)$"); err != nil {
+			return nil, fmt.Errorf("rule %d has an invalid header: %w", i, err)
+		}
+		if r.queryParameters, err = compileAll(rule.QueryParameters, "^(?:%s)$"); err != nil {
+			return nil, fmt.Errorf("rule %d has an invalid query parameter: %w", i, err)
+		}
+		for _, path := range rule.Paths {
+			tokens, err := compilePath(path)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d has an invalid path %q: %w", i, path, err)
+			}
+			r.paths = append(r.paths, tokens)
+		}
+		compiled.rules = append(compiled.rules, r)
+	}
+	return compiled, nil
+}
+
+// compileAll compiles the expressions, formatted with the format, e.g. to anchor them so that they match whole values.
+func compileAll(expressions []string, format string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, expression := range expressions {
+		re, err := regexp.Compile(fmt.Sprintf(format, expression))
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// compilePath compiles the reference tokens of the JSON pointer.
+func compilePath(path string) ([]*regexp.Regexp, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("a path must start with /")
+	}
+	var tokens []*regexp.Regexp
+	for _, token := range strings.Split(path[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		re, err := regexp.Compile("^(?:" + token + ")$")
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, re)
+	}
+	return tokens, nil
+}
+
+// forURI returns the policy of the rules applying to the request URI.
+func (p *redactionPolicy) forURI(uri string) *redactionPolicy {
+	if p == nil {
+		return nil
+	}
+	policy := &redactionPolicy{}
+	for _, rule := range p.rules {
+		if len(rule.uris) == 0 || matchesAny(rule.uris, uri) {
+			policy.rules = append(policy.rules, rule)
+		}
+	}
+	if len(policy.rules) == 0 {
+		return nil
+	}
+	return policy
+}
+
+// action returns the action of the last rule matching the value, or "" if none does.
+func (p *redactionPolicy) action(matches func(redactionRule) bool) string {
+	if p == nil {
+		return ""
+	}
+	action := ""
+	for _, rule := range p.rules {
+		if matches(rule) {
+			action = rule.action
+		}
+	}
+	return action
+}
+
+// redactHeaders redacts or drops the headers matching the policy.
+func (p *redactionPolicy) redactHeaders(headers map[string][]string) {
+	for name := range headers {
+		switch p.action(func(r redactionRule) bool { return matchesAny(r.headers, name) }) {
+		case ActionRedact:
+			headers[name] = []string{redacted}
+		case ActionDrop:
+			delete(headers, name)
+		}
+	}
+}
+
+// redactURI redacts or drops the query parameters of the request URI matching the policy.
+func (p *redactionPolicy) redactURI(requestURI string) string {
+	if p == nil {
+		return requestURI
+	}
+	path, rawQuery, found := strings.Cut(requestURI, "?")
+	if !found {
+		return requestURI
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// The query can't be parsed to know which parameters to redact, so it isn't logged.
+		return path + "?" + redacted
+	}
+
+	var changed bool
+	for name, values := range query {
+		switch p.action(func(r redactionRule) bool { return matchesAny(r.queryParameters, name) }) {
+		case ActionRedact:
+			for i := range values {
+				values[i] = redacted
+			}
+			changed = true
+		case ActionDrop:
+			query.Del(name)
+			changed = true
+		}
+	}
+	if !changed {
+		return requestURI
+	}
+	return path + "?" + query.Encode()
+}
+
+// redactBody redacts or drops the fields of the body matching the paths of the policy, and returns whether it
+// changed it.
+func (p *redactionPolicy) redactBody(body map[string]interface{}) bool {
+	if p == nil {
+		return false
+	}
+	for _, rule := range p.rules {
+		if len(rule.paths) > 0 {
+			return p.redactValue(body, nil)
+		}
+	}
+	return false
+}
+
+func (p *redactionPolicy) redactValue(value interface{}, path []string) bool {
+	var changed bool
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := append(path[:len(path):len(path)], key)
+			switch p.pathAction(childPath) {
+			case ActionRedact:
+				v[key] = redacted
+				changed = true
+			case ActionDrop:
+				delete(v, key)
+				changed = true
+			default:
+				changed = p.redactValue(child, childPath) || changed
+			}
+		}
+	case []interface{}:
+		// The dropped elements are redacted instead, not to shift the indexes of the others.
+		for i, child := range v {
+			childPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+			switch p.pathAction(childPath) {
+			case ActionRedact, ActionDrop:
+				v[i] = redacted
+				changed = true
+			default:
+				changed = p.redactValue(child, childPath) || changed
+			}
+		}
+	}
+	return changed
+}
+
+func (p *redactionPolicy) pathAction(path []string) string {
+	return p.action(func(r redactionRule) bool {
+		for _, tokens := range r.paths {
+			if matchesPath(tokens, path) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func matchesPath(tokens []*regexp.Regexp, path []string) bool {
+	if len(tokens) != len(path) {
+		return false
+	}
+	for i, token := range tokens {
+		if !token.MatchString(path[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(expressions []*regexp.Regexp, value string) bool {
+	for _, re := range expressions {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyCache holds the compiled audit-log-redaction-policy setting, compiled again when it changes, so that the
+// policy can be changed without restarting.
+type policyCache struct {
+	mu     sync.Mutex
+	value  string
+	policy *redactionPolicy
+}
+
+var redactionPolicies = &policyCache{}
+
+func (c *policyCache) current() *redactionPolicy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value := settings.AuditLogRedactionPolicy.Get()
+	if value == c.value {
+		return c.policy
+	}
+
+	c.value = value
+	policy, err := parseRedactionPolicy(value)
+	if err != nil {
+		// The previous policy is kept, rather than logging what it redacts.
+		logrus.Errorf("auditLog: invalid %s setting, keeping the previous policy: %v", settings.AuditLogRedactionPolicy.Name, err)
+		return c.policy
+	}
+	c.policy = policy
+	return c.policy
+}
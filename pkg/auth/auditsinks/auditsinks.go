@@ -0,0 +1,359 @@
+// Package auditsinks exports the auth events, the security events and the API audit log to the sinks of the audit-sinks setting, so that
+// they can be consumed by a SIEM without scraping the logs of the pods: syslog servers, with RFC 5424 messages over
+// TLS, HTTP webhooks receiving batches of entries, and Kafka topics, through a Kafka REST proxy. Each sink can be
+// limited to some sources and types of entries. The entries are batched, and the failed batches are retried with a
+// backoff before they are dropped.
+package auditsinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// The sources of the entries.
+const (
+	// SourceAuth is the source of the auth events.
+	SourceAuth = "auth"
+	// SourceAudit is the source of the API audit log.
+	SourceAudit = "audit"
+	// SourceSecurity is the source of the security events.
+	SourceSecurity = "security"
+
+	// TypeAPIRequest is the type of the entries of the API audit log.
+	TypeAPIRequest = "APIRequest"
+)
+
+// The types of sinks.
+const (
+	TypeSyslog  = "syslog"
+	TypeWebhook = "webhook"
+	TypeKafka   = "kafka"
+)
+
+const (
+	// The fields of the audit-sink-<name> secrets. The webhooks and Kafka REST proxies are authenticated with the
+	// token as a bearer token, or else with the username and password, and the syslog servers and all HTTPS
+	// endpoints with the client certificate, if set.
+	TokenField    = "token"
+	UsernameField = "username"
+	PasswordField = "password"
+	CertField     = corev1.TLSCertKey
+	KeyField      = corev1.TLSPrivateKeyKey
+
+	secretPrefix = "audit-sink-"
+
+	defaultBatchSize     = 100
+	defaultFlushInterval = 5 * time.Second
+	defaultMaxRetries    = 5
+	// queueSize is how many entries can wait to be sent to each sink. The entries published while it's full are
+	// dropped.
+	queueSize = 10000
+	// sendTimeout bounds each attempt to send a batch.
+	sendTimeout = 30 * time.Second
+)
+
+// Entry is an entry exported to the sinks.
+type Entry struct {
+	// Source is SourceAuth, SourceAudit or SourceSecurity.
+	Source string `json:"source"`
+	// Type is the type of the auth or security event, or TypeAPIRequest for the audit log.
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	// Data is the auth event, the audit log entry or the security event.
+	Data any `json:"data"`
+}
+
+// Config is a sink of the audit-sinks setting.
+type Config struct {
+	// Name identifies the sink, and its audit-sink-<name> secret.
+	Name string `json:"name"`
+	// Type is TypeSyslog, TypeWebhook or TypeKafka.
+	Type string `json:"type"`
+	// Address is the host:port of the syslog server.
+	Address string `json:"address,omitempty"`
+	// URL is the URL of the webhook, or the base URL of the Kafka REST proxy.
+	URL string `json:"url,omitempty"`
+	// Topic is the Kafka topic the entries are produced to.
+	Topic string `json:"topic,omitempty"`
+	// CACerts are the PEM encoded CA certificates trusted for the sink, in addition to the system ones.
+	CACerts string `json:"caCerts,omitempty"`
+	// BatchSize is how many entries are sent at most at once, 100 by default.
+	BatchSize int `json:"batchSize,omitempty"`
+	// FlushInterval is how long the entries wait for a batch to fill before they are sent, 5s by default.
+	FlushInterval string `json:"flushInterval,omitempty"`
+	// MaxRetries is how many times a failed batch is sent again before it's dropped, 5 by default.
+	MaxRetries *int `json:"maxRetries,omitempty"`
+	// Filter limits the entries sent to the sink.
+	Filter Filter `json:"filter,omitempty"`
+}
+
+// Filter limits the entries sent to a sink to some sources and types. An empty list matches everything.
+type Filter struct {
+	Sources []string `json:"sources,omitempty"`
+	Types   []string `json:"types,omitempty"`
+}
+
+func (f Filter) matches(entry Entry) bool {
+	return matchesAny(f.Sources, entry.Source) && matchesAny(f.Types, entry.Type)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// parseConfigs parses and validates the sinks of the audit-sinks setting.
+func parseConfigs(value string) ([]Config, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var configs []Config
+	if err := json.Unmarshal([]byte(value), &configs); err != nil {
+		return nil, fmt.Errorf("invalid %s setting: %w", settings.AuditSinks.Name, err)
+	}
+	names := map[string]bool{}
+	for _, c := range configs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("invalid %s setting: a sink has no name", settings.AuditSinks.Name)
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("invalid %s setting: sink %s is defined twice", settings.AuditSinks.Name, c.Name)
+		}
+		names[c.Name] = true
+		if c.BatchSize < 0 {
+			return nil, fmt.Errorf("invalid batchSize %d of sink %s", c.BatchSize, c.Name)
+		}
+		if c.FlushInterval != "" {
+			if interval, err := time.ParseDuration(c.FlushInterval); err != nil || interval <= 0 {
+				return nil, fmt.Errorf("invalid flushInterval %q of sink %s", c.FlushInterval, c.Name)
+			}
+		}
+		if c.MaxRetries != nil && *c.MaxRetries < 0 {
+			return nil, fmt.Errorf("invalid maxRetries %d of sink %s", *c.MaxRetries, c.Name)
+		}
+		switch c.Type {
+		case TypeSyslog:
+			if c.Address == "" {
+				return nil, fmt.Errorf("syslog sink %s has no address", c.Name)
+			}
+		case TypeWebhook:
+			if c.URL == "" {
+				return nil, fmt.Errorf("webhook sink %s has no url", c.Name)
+			}
+		case TypeKafka:
+			if c.URL == "" || c.Topic == "" {
+				return nil, fmt.Errorf("kafka sink %s must have a url and a topic", c.Name)
+			}
+		default:
+			return nil, fmt.Errorf("sink %s has an invalid type %q", c.Name, c.Type)
+		}
+	}
+	return configs, nil
+}
+
+// sender sends batches of entries to a sink.
+type sender interface {
+	send(ctx context.Context, entries []Entry) error
+	close()
+}
+
+// credentials returns the audit-sink-<name> secret of the sink, nil if it doesn't exist.
+type credentials func() (*corev1.Secret, error)
+
+func newSender(c Config, secrets v1.SecretLister) (sender, error) {
+	creds := func() (*corev1.Secret, error) {
+		secret, err := secrets.Get(namespace.GlobalNamespace, secretPrefix+c.Name)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting the credentials of sink %s: %w", c.Name, err)
+		}
+		return secret, nil
+	}
+	switch c.Type {
+	case TypeSyslog:
+		return newSyslogSender(c, creds)
+	case TypeWebhook:
+		return newWebhookSender(c, creds)
+	default:
+		return newKafkaSender(c, creds)
+	}
+}
+
+// sink batches the entries of a sink and sends them.
+type sink struct {
+	config        Config
+	sender        sender
+	entries       chan Entry
+	batchSize     int
+	flushInterval time.Duration
+	backoff       wait.Backoff
+	cancel        context.CancelFunc
+}
+
+func newSink(c Config, s sender) *sink {
+	sk := &sink{
+		config:        c,
+		sender:        s,
+		entries:       make(chan Entry, queueSize),
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		backoff: wait.Backoff{
+			Duration: time.Second,
+			Factor:   2,
+			Jitter:   .1,
+			Steps:    defaultMaxRetries + 1,
+			Cap:      time.Minute,
+		},
+	}
+	if c.BatchSize > 0 {
+		sk.batchSize = c.BatchSize
+	}
+	if interval, err := time.ParseDuration(c.FlushInterval); err == nil && interval > 0 {
+		sk.flushInterval = interval
+	}
+	if c.MaxRetries != nil {
+		sk.backoff.Steps = *c.MaxRetries + 1
+	}
+	return sk
+}
+
+// run sends the entries in batches, when a batch is full or has waited for the flush interval, until the context is
+// done.
+func (s *sink) run(ctx context.Context) {
+	defer s.sender.close()
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	var batch []Entry
+	flush := func() {
+		if len(batch) > 0 {
+			s.deliver(ctx, batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// deliver sends the batch, retrying with a backoff, and drops it once the retries are exhausted or it's rejected.
+func (s *sink) deliver(ctx context.Context, batch []Entry) {
+	retriable := func(err error) bool {
+		var permanent *permanentError
+		return ctx.Err() == nil && !errors.As(err, &permanent)
+	}
+	err := retry.OnError(s.backoff, retriable, func() error {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		defer cancel()
+		return s.sender.send(sendCtx, batch)
+	})
+	if err != nil {
+		logrus.Errorf("[auditsinks] dropping %d entries that couldn't be sent to sink %s: %v", len(batch), s.config.Name, err)
+	}
+}
+
+// manager holds the sinks of the audit-sinks setting, rebuilt when it changes.
+var manager = &sinkManager{}
+
+type sinkManager struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	secrets v1.SecretLister
+	value   string
+	sinks   []*sink
+}
+
+// Start exports the published entries to the sinks of the audit-sinks setting until the context is done. It must run
+// on every replica, as they all serve the API.
+func Start(ctx context.Context, scaledContext *config.ScaledContext) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	manager.ctx = ctx
+	manager.secrets = scaledContext.Core.Secrets("").Controller().Lister()
+}
+
+// Publish queues the entry for the sinks it matches. It doesn't block: the entries are dropped for the sinks whose
+// queue is full.
+func Publish(entry Entry) {
+	for _, s := range manager.current() {
+		if !s.config.Filter.matches(entry) {
+			continue
+		}
+		select {
+		case s.entries <- entry:
+		default:
+			logrus.Warnf("[auditsinks] too many entries waiting to be sent to sink %s, dropping entry %s", s.config.Name, entry.Type)
+		}
+	}
+}
+
+// current returns the sinks of the audit-sinks setting, rebuilding them if it changed. It returns none until Start
+// is called.
+func (m *sinkManager) current() []*sink {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ctx == nil || m.ctx.Err() != nil {
+		return nil
+	}
+	value := settings.AuditSinks.Get()
+	if value == m.value {
+		return m.sinks
+	}
+
+	for _, s := range m.sinks {
+		s.cancel()
+	}
+	m.value = value
+	m.sinks = nil
+	configs, err := parseConfigs(value)
+	if err != nil {
+		logrus.Errorf("[auditsinks] %v", err)
+		return nil
+	}
+	for _, c := range configs {
+		snd, err := newSender(c, m.secrets)
+		if err != nil {
+			logrus.Errorf("[auditsinks] failed to configure sink %s: %v", c.Name, err)
+			continue
+		}
+		s := newSink(c, snd)
+		ctx, cancel := context.WithCancel(m.ctx)
+		s.cancel = cancel
+		go s.run(ctx)
+		m.sinks = append(m.sinks, s)
+	}
+	return m.sinks
+}
@@ -0,0 +1,237 @@
+package auditsinks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestParseConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+		wantLen int
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:    "valid",
+			value:   `[{"name":"siem","type":"syslog","address":"siem:6514"},{"name":"hook","type":"webhook","url":"https://hook","batchSize":10,"flushInterval":"1s","maxRetries":0},{"name":"kafka","type":"kafka","url":"https://proxy","topic":"audit"}]`,
+			wantLen: 3,
+		},
+		{
+			name:    "invalid json",
+			value:   `{`,
+			wantErr: "invalid audit-sinks setting",
+		},
+		{
+			name:    "no name",
+			value:   `[{"type":"webhook","url":"https://hook"}]`,
+			wantErr: "has no name",
+		},
+		{
+			name:    "duplicate name",
+			value:   `[{"name":"a","type":"webhook","url":"https://hook"},{"name":"a","type":"webhook","url":"https://hook"}]`,
+			wantErr: "defined twice",
+		},
+		{
+			name:    "syslog without address",
+			value:   `[{"name":"a","type":"syslog"}]`,
+			wantErr: "has no address",
+		},
+		{
+			name:    "kafka without topic",
+			value:   `[{"name":"a","type":"kafka","url":"https://proxy"}]`,
+			wantErr: "must have a url and a topic",
+		},
+		{
+			name:    "invalid type",
+			value:   `[{"name":"a","type":"splunk"}]`,
+			wantErr: "invalid type",
+		},
+		{
+			name:    "invalid flush interval",
+			value:   `[{"name":"a","type":"webhook","url":"https://hook","flushInterval":"soon"}]`,
+			wantErr: "invalid flushInterval",
+		},
+		{
+			name:    "negative retries",
+			value:   `[{"name":"a","type":"webhook","url":"https://hook","maxRetries":-1}]`,
+			wantErr: "invalid maxRetries",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs, err := parseConfigs(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, configs, tt.wantLen)
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	entry := Entry{Source: SourceAuth, Type: "LoginFailed"}
+
+	assert.True(t, Filter{}.matches(entry))
+	assert.True(t, Filter{Sources: []string{SourceAuth}}.matches(entry))
+	assert.False(t, Filter{Sources: []string{SourceAudit}}.matches(entry))
+	assert.True(t, Filter{Sources: []string{SourceAuth}, Types: []string{"LoginSucceeded", "LoginFailed"}}.matches(entry))
+	assert.False(t, Filter{Types: []string{"LoginSucceeded"}}.matches(entry))
+}
+
+func TestFormatSyslog(t *testing.T) {
+	entry := Entry{
+		Source: SourceAuth,
+		Type:   "LoginFailed",
+		Time:   time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC),
+		Data:   map[string]string{"userName": "u-abc12"},
+	}
+
+	msg, err := formatSyslog(entry, "rancher-0")
+	require.NoError(t, err)
+
+	want := `<110>1 2026-01-02T03:04:05.000006Z rancher-0 rancher - LoginFailed - ` +
+		`{"source":"auth","type":"LoginFailed","time":"2026-01-02T03:04:05.000006Z","data":{"userName":"u-abc12"}}`
+	assert.Equal(t, want, string(msg))
+}
+
+func TestMsgID(t *testing.T) {
+	assert.Equal(t, "-", msgID(""))
+	assert.Equal(t, "API_Request", msgID("API Request"))
+	assert.Equal(t, strings.Repeat("a", maxMsgIDLen), msgID(strings.Repeat("a", 40)))
+}
+
+func TestWebhookSender(t *testing.T) {
+	var mu sync.Mutex
+	var received [][]Entry
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entries []Entry
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&entries))
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, entries)
+		auth = append(auth, r.Header.Get("Authorization"))
+		// The first batch fails once, to be retried.
+		if len(received) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	creds := func() (*corev1.Secret, error) {
+		return &corev1.Secret{Data: map[string][]byte{TokenField: []byte("secret-token")}}, nil
+	}
+	snd, err := newWebhookSender(Config{Name: "hook", Type: TypeWebhook, URL: server.URL}, creds)
+	require.NoError(t, err)
+	s := newSink(Config{Name: "hook", BatchSize: 2, FlushInterval: "200ms"}, snd)
+	s.backoff = wait.Backoff{Duration: time.Millisecond, Steps: 3}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.run(ctx)
+	for _, entryType := range []string{"a", "b", "c"} {
+		s.entries <- Entry{Source: SourceAuth, Type: entryType}
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The full batch is sent twice, then the remaining entry once the flush interval passed.
+	assert.Len(t, received[0], 2)
+	assert.Equal(t, received[0], received[1])
+	require.Len(t, received[2], 1)
+	assert.Equal(t, "c", received[2][0].Type)
+	for _, header := range auth {
+		assert.Equal(t, "Bearer secret-token", header)
+	}
+}
+
+func TestWebhookSenderDoesNotRetryRejectedBatches(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	creds := func() (*corev1.Secret, error) { return nil, nil }
+	snd, err := newWebhookSender(Config{Name: "hook", Type: TypeWebhook, URL: server.URL}, creds)
+	require.NoError(t, err)
+	s := newSink(Config{Name: "hook"}, snd)
+	s.backoff = wait.Backoff{Duration: time.Millisecond, Steps: 3}
+
+	s.deliver(context.Background(), []Entry{{Source: SourceAudit, Type: TypeAPIRequest}})
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, calls)
+}
+
+func TestKafkaSender(t *testing.T) {
+	var path, contentType, user, password string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		user, password, _ = r.BasicAuth()
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":1,"error_code":null,"error":null}]}`))
+	}))
+	defer server.Close()
+
+	creds := func() (*corev1.Secret, error) {
+		return &corev1.Secret{Data: map[string][]byte{UsernameField: []byte("rancher"), PasswordField: []byte("pass")}}, nil
+	}
+	snd, err := newKafkaSender(Config{Name: "kafka", Type: TypeKafka, URL: server.URL + "/", Topic: "audit"}, creds)
+	require.NoError(t, err)
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	err = snd.send(context.Background(), []Entry{{Source: SourceAuth, Type: "Logout", Time: at, Data: "u-abc12"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/topics/audit", path)
+	assert.Equal(t, kafkaContentType, contentType)
+	assert.Equal(t, "rancher", user)
+	assert.Equal(t, "pass", password)
+	assert.JSONEq(t, `{"records":[{"key":"Logout","value":{"source":"auth","type":"Logout","time":"2026-01-02T03:04:05Z","data":"u-abc12"}}]}`, string(body))
+}
+
+func TestKafkaSenderFailsOnRecordErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"retriable error"}]}`))
+	}))
+	defer server.Close()
+
+	creds := func() (*corev1.Secret, error) { return nil, nil }
+	snd, err := newKafkaSender(Config{Name: "kafka", Type: TypeKafka, URL: server.URL, Topic: "audit"}, creds)
+	require.NoError(t, err)
+
+	err = snd.send(context.Background(), []Entry{{Source: SourceAuth, Type: "Logout"}})
+	assert.ErrorContains(t, err, "retriable error")
+}
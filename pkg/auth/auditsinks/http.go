@@ -0,0 +1,223 @@
+package auditsinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	kafkaContentType = "application/vnd.kafka.json.v2+json"
+	kafkaAccept      = "application/vnd.kafka.v2+json"
+	// maxErrorBodySize bounds how much of the body of a failed response is read for the error.
+	maxErrorBodySize = 1024
+)
+
+// permanentError is a failure sending a batch that fails again if retried, e.g. a rejected request.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// httpSender posts the batches of entries to a URL.
+type httpSender struct {
+	client *http.Client
+	creds  credentials
+}
+
+func newHTTPSender(rawURL, caCerts string, creds credentials) (*httpSender, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", rawURL, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("invalid url %q: the scheme must be https or http", rawURL)
+	}
+	config, err := tlsConfig(u.Hostname(), caCerts, creds)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	return &httpSender{
+		client: &http.Client{Transport: transport},
+		creds:  creds,
+	}, nil
+}
+
+// post posts the body to the URL, authenticated with the credentials of the sink, and returns the body of the
+// response.
+func (s *httpSender) post(ctx context.Context, url, contentType, accept string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, &permanentError{err: err}
+	}
+	req.Header.Set("Content-Type", contentType)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	secret, err := s.creds()
+	if err != nil {
+		return nil, err
+	}
+	if secret != nil {
+		if token := string(secret.Data[TokenField]); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		} else if username := string(secret.Data[UsernameField]); username != "" {
+			req.SetBasicAuth(username, string(secret.Data[PasswordField]))
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return io.ReadAll(resp.Body)
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("%s responded %d: %s", url, resp.StatusCode, strings.TrimSpace(string(message)))
+	// The requests rejected by the server fail again, unless they were throttled.
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout {
+		return nil, &permanentError{err: err}
+	}
+	return nil, err
+}
+
+func (s *httpSender) close() {
+	s.client.CloseIdleConnections()
+}
+
+// webhookSender posts the batches of entries to a webhook, as a JSON array.
+type webhookSender struct {
+	*httpSender
+	url string
+}
+
+func newWebhookSender(c Config, creds credentials) (sender, error) {
+	s, err := newHTTPSender(c.URL, c.CACerts, creds)
+	if err != nil {
+		return nil, err
+	}
+	return &webhookSender{httpSender: s, url: c.URL}, nil
+}
+
+func (s *webhookSender) send(ctx context.Context, entries []Entry) error {
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	_, err = s.post(ctx, s.url, "application/json", "", body)
+	return err
+}
+
+// kafkaSender produces the entries to a Kafka topic through the v2 API of a Kafka REST proxy, keyed by their type.
+type kafkaSender struct {
+	*httpSender
+	url string
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Entry  `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+func newKafkaSender(c Config, creds credentials) (sender, error) {
+	s, err := newHTTPSender(c.URL, c.CACerts, creds)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaSender{
+		httpSender: s,
+		url:        strings.TrimSuffix(c.URL, "/") + "/topics/" + url.PathEscape(c.Topic),
+	}, nil
+}
+
+func (s *kafkaSender) send(ctx context.Context, entries []Entry) error {
+	request := kafkaProduceRequest{Records: make([]kafkaRecord, 0, len(entries))}
+	for _, entry := range entries {
+		request.Records = append(request.Records, kafkaRecord{Key: entry.Type, Value: entry})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	respBody, err := s.post(ctx, s.url, kafkaContentType, kafkaAccept, body)
+	if err != nil {
+		return err
+	}
+
+	// The proxy reports the records it failed to produce in the offsets of the response.
+	var response kafkaProduceResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil
+	}
+	for _, offset := range response.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("producing to %s: %s", s.url, offset.Error)
+		}
+	}
+	return nil
+}
+
+// tlsConfig returns the TLS configuration verifying the server with the system CAs and caCerts, and presenting the
+// client certificate of the credentials of the sink, if any.
+func tlsConfig(serverName, caCerts string, creds credentials) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+		// The certificate is read on each handshake, so that it can be rotated without reconfiguring the sink.
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			secret, err := creds()
+			if err != nil {
+				return nil, err
+			}
+			if secret == nil || len(secret.Data[CertField]) == 0 {
+				return &tls.Certificate{}, nil
+			}
+			cert, err := tls.X509KeyPair(secret.Data[CertField], secret.Data[KeyField])
+			if err != nil {
+				return nil, fmt.Errorf("invalid client certificate: %w", err)
+			}
+			return &cert, nil
+		},
+	}
+	if caCerts != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(caCerts)) {
+			return nil, errors.New("invalid caCerts: no PEM encoded certificate")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
@@ -0,0 +1,122 @@
+package auditsinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// syslogPriority is the priority of the messages: the log audit facility (13) and the informational severity (6).
+	syslogPriority = 13*8 + 6
+	syslogAppName  = "rancher"
+	// maxMsgIDLen is the maximum length of the MSGID field of RFC 5424.
+	maxMsgIDLen = 32
+	nilValue    = "-"
+)
+
+// syslogSender sends the entries to a syslog server as RFC 5424 messages over TLS, framed with octet counting as
+// specified by RFC 5425. The connection is kept open between batches.
+type syslogSender struct {
+	address   string
+	tlsConfig *tls.Config
+	hostname  string
+	conn      net.Conn
+}
+
+func newSyslogSender(c Config, creds credentials) (sender, error) {
+	host, _, err := net.SplitHostPort(c.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", c.Address, err)
+	}
+	config, err := tlsConfig(host, c.CACerts, creds)
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = nilValue
+	}
+	return &syslogSender{
+		address:   c.Address,
+		tlsConfig: config,
+		hostname:  hostname,
+	}, nil
+}
+
+func (s *syslogSender) send(ctx context.Context, entries []Entry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		msg, err := formatSyslog(entry, s.hostname)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "%d %s", len(msg), msg)
+	}
+
+	if s.conn == nil {
+		dialer := &tls.Dialer{Config: s.tlsConfig}
+		conn, err := dialer.DialContext(ctx, "tcp", s.address)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", s.address, err)
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+	if _, err := s.conn.Write(buf.Bytes()); err != nil {
+		s.close()
+		return fmt.Errorf("writing to %s: %w", s.address, err)
+	}
+	return nil
+}
+
+func (s *syslogSender) close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// formatSyslog formats the entry as an RFC 5424 message, with the type of the entry as MSGID and the entry as JSON as
+// MSG.
+func formatSyslog(entry Entry, hostname string) ([]byte, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("encoding entry %s: %w", entry.Type, err)
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %s %s %s ",
+		syslogPriority,
+		entry.Time.UTC().Format(time.RFC3339Nano),
+		hostname,
+		syslogAppName,
+		nilValue,
+		msgID(entry.Type),
+		nilValue,
+	)
+	return append([]byte(header), data...), nil
+}
+
+// msgID returns the type as an RFC 5424 MSGID: printable US-ASCII, without spaces, at most 32 characters.
+func msgID(entryType string) string {
+	id := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, entryType)
+	if len(id) > maxMsgIDLen {
+		id = id[:maxMsgIDLen]
+	}
+	if id == "" {
+		return nilValue
+	}
+	return id
+}
@@ -0,0 +1,251 @@
+// Package authevents records the authentication events, such as logins, logouts, and the changes of tokens, second
+// factors, groups and auth configs, as AuthEvents. Each event is also logged, so that it's kept even when it can't be
+// stored. The AuthEvents older than the auth-event-retention-hours setting are removed.
+package authevents
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/activity"
+	"github.com/rancher/rancher/pkg/auth/auditsinks"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// The types of the events.
+const (
+	LoginSucceeded    = "LoginSucceeded"
+	LoginFailed       = "LoginFailed"
+	Logout            = "Logout"
+	TokenCreated      = "TokenCreated"
+	TokenRevoked      = "TokenRevoked"
+	MFAChanged        = "MFAChanged"
+	GroupsRefreshed   = "GroupsRefreshed"
+	AuthConfigChanged = "AuthConfigChanged"
+	LoginAnomaly      = "LoginAnomaly"
+	// PrincipalsSearched and PrincipalResolved are the searches and the lookups of principals in the auth
+	// providers, as the enumeration of their directories is security-relevant.
+	PrincipalsSearched = "PrincipalsSearched"
+	PrincipalResolved  = "PrincipalResolved"
+	// ProviderSLOBreached and ProviderSLORecovered are the breaches and recoveries of the service level objectives of
+	// the auth providers.
+	ProviderSLOBreached  = "ProviderSLOBreached"
+	ProviderSLORecovered = "ProviderSLORecovered"
+)
+
+// The reasons of the failed logins and principal lookups.
+const (
+	ReasonProviderDisabled   = "ProviderDisabled"
+	ReasonThrottled          = "Throttled"
+	ReasonInvalidCredentials = "InvalidCredentials"
+	ReasonProviderError      = "ProviderError"
+	ReasonUserDisabled       = "UserDisabled"
+	ReasonSecondFactorFailed = "SecondFactorFailed"
+	ReasonPrincipalNotFound  = "PrincipalNotFound"
+)
+
+const (
+	// TypeLabel, UserLabel and ProviderLabel are set on the AuthEvents to their type, user and provider, so that
+	// they can be queried with label selectors.
+	TypeLabel     = "authevents.cattle.io/type"
+	UserLabel     = "authevents.cattle.io/user"
+	ProviderLabel = "authevents.cattle.io/provider"
+
+	// queueSize is how many events can wait to be stored. The events recorded while the queue is full are only
+	// logged.
+	queueSize     = 1000
+	purgeInterval = time.Hour
+	listPageSize  = 500
+	maxDetailLen  = 1024
+)
+
+// Event is an authentication event.
+type Event struct {
+	// Type is one of the types of events of this package.
+	Type string
+	// UserName is the name of the user the event is about, if known.
+	UserName string
+	// PrincipalID is the principal the event is about, e.g. the username of a failed login.
+	PrincipalID string
+	// Provider is the auth provider of the event.
+	Provider string
+	// SourceIP is the address of the client of the request the event comes from.
+	SourceIP net.IP
+	// Reason is why the event happened, e.g. one of the reasons of the failed logins of this package.
+	Reason string
+	// Actor is the user who caused the event, when it isn't the user the event is about.
+	Actor string
+	// Target is the object the event is about, e.g. the name of a token.
+	Target string
+	// Detail describes the event.
+	Detail string
+}
+
+var (
+	queue = make(chan *v3.AuthEvent, queueSize)
+	now   = time.Now
+)
+
+// Record logs the event, exports it to the audit sinks, adds it to the activity feed of its user, and stores it as an
+// AuthEvent in the background, once Start was called. The searches and lookups of principals aren't added to the
+// activity feeds, as the UI makes many of them, which would push the other activities out of the feeds.
+func Record(event Event) {
+	authEvent := newAuthEvent(event, now())
+
+	fields := logrus.Fields{"event": event.Type}
+	for key, value := range map[string]string{
+		"user":      authEvent.UserName,
+		"principal": authEvent.PrincipalID,
+		"provider":  authEvent.Provider,
+		"source":    authEvent.SourceIP,
+		"reason":    authEvent.Reason,
+		"actor":     authEvent.Actor,
+		"target":    authEvent.Target,
+		"detail":    authEvent.Detail,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	logrus.WithFields(fields).Info("authevents: audit")
+	auditsinks.Publish(auditsinks.Entry{
+		Source: auditsinks.SourceAuth,
+		Type:   event.Type,
+		Time:   authEvent.Time.Time,
+		Data:   authEvent,
+	})
+	if event.Type != PrincipalsSearched && event.Type != PrincipalResolved {
+		activity.Record(authEvent.UserName, activity.Activity{
+			Kind:     activity.KindAuth,
+			Event:    event.Type,
+			Time:     authEvent.Time,
+			Provider: authEvent.Provider,
+			SourceIP: authEvent.SourceIP,
+			Reason:   authEvent.Reason,
+			Detail:   authEvent.Detail,
+		})
+	}
+
+	select {
+	case queue <- authEvent:
+	default:
+		logrus.Warnf("[authevents] too many events waiting to be stored, dropping event %s of user %s", event.Type, event.UserName)
+	}
+}
+
+func newAuthEvent(event Event, at time.Time) *v3.AuthEvent {
+	detail := event.Detail
+	if len(detail) > maxDetailLen {
+		detail = detail[:maxDetailLen]
+	}
+	authEvent := &v3.AuthEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "authevent-",
+			Labels:       map[string]string{},
+		},
+		Type:        event.Type,
+		Time:        metav1.NewTime(at),
+		UserName:    event.UserName,
+		PrincipalID: event.PrincipalID,
+		Provider:    event.Provider,
+		Reason:      event.Reason,
+		Actor:       event.Actor,
+		Target:      event.Target,
+		Detail:      detail,
+	}
+	if event.SourceIP != nil {
+		authEvent.SourceIP = event.SourceIP.String()
+	}
+	for label, value := range map[string]string{
+		TypeLabel:     event.Type,
+		UserLabel:     event.UserName,
+		ProviderLabel: event.Provider,
+	} {
+		// The values that can't be label values, e.g. too long ones, are only in the fields of the event.
+		if value != "" && len(validation.IsValidLabelValue(value)) == 0 {
+			authEvent.Labels[label] = value
+		}
+	}
+	return authEvent
+}
+
+// Start stores the recorded events until the context is done. It must run on every replica, as they all serve logins.
+func Start(ctx context.Context, scaledContext *config.ScaledContext) {
+	go write(ctx, scaledContext.Wrangler.Mgmt.AuthEvent())
+}
+
+func write(ctx context.Context, events mgmtcontrollers.AuthEventClient) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-queue:
+			if _, err := events.Create(event); err != nil {
+				logrus.Errorf("[authevents] failed to store event %s of user %s: %v", event.Type, event.UserName, err)
+			}
+		}
+	}
+}
+
+// StartPurgeDaemon periodically removes the AuthEvents older than the auth-event-retention-hours setting.
+func StartPurgeDaemon(ctx context.Context, scaledContext *config.ScaledContext) {
+	p := &purger{
+		events: scaledContext.Wrangler.Mgmt.AuthEvent(),
+		now:    time.Now,
+	}
+	go wait.JitterUntil(p.purge, purgeInterval, .1, true, ctx.Done())
+}
+
+type purger struct {
+	events mgmtcontrollers.AuthEventClient
+	now    func() time.Time
+}
+
+func (p *purger) purge() {
+	hours, err := strconv.ParseInt(settings.AuthEventRetentionHours.Get(), 10, 64)
+	if err != nil {
+		logrus.Errorf("[authevents] invalid %s setting: %v", settings.AuthEventRetentionHours.Name, err)
+		return
+	}
+	if hours <= 0 {
+		return
+	}
+	cutoff := p.now().Add(-time.Duration(hours) * time.Hour)
+
+	var count int
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		list, err := p.events.List(opts)
+		if err != nil {
+			logrus.Errorf("[authevents] failed to list events: %v", err)
+			return
+		}
+		for _, event := range list.Items {
+			if !event.Time.Time.Before(cutoff) {
+				continue
+			}
+			if err := p.events.Delete(event.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				logrus.Errorf("[authevents] failed to delete event %s: %v", event.Name, err)
+				continue
+			}
+			count++
+		}
+		if list.Continue == "" {
+			break
+		}
+		opts.Continue = list.Continue
+	}
+	if count > 0 {
+		logrus.Infof("[authevents] purged %d events older than %d hours", count, hours)
+	}
+}
@@ -0,0 +1,103 @@
+package authevents
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewAuthEvent(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := newAuthEvent(Event{
+		Type:        LoginFailed,
+		UserName:    "u-abc12",
+		PrincipalID: "openldap_user://uid=alice,ou=people,dc=example,dc=com",
+		Provider:    "openldap",
+		SourceIP:    net.ParseIP("10.0.0.1"),
+		Reason:      ReasonInvalidCredentials,
+	}, at)
+
+	assert.Equal(t, "authevent-", event.GenerateName)
+	assert.Equal(t, LoginFailed, event.Type)
+	assert.Equal(t, at, event.Time.Time)
+	assert.Equal(t, "10.0.0.1", event.SourceIP)
+	assert.Equal(t, ReasonInvalidCredentials, event.Reason)
+	assert.Equal(t, map[string]string{
+		TypeLabel:     LoginFailed,
+		UserLabel:     "u-abc12",
+		ProviderLabel: "openldap",
+	}, event.Labels)
+}
+
+func TestNewAuthEventSkipsInvalidLabelValues(t *testing.T) {
+	event := newAuthEvent(Event{
+		Type:     MFAChanged,
+		UserName: strings.Repeat("u", 64),
+		Detail:   strings.Repeat("d", maxDetailLen+10),
+	}, time.Now())
+
+	assert.Equal(t, map[string]string{TypeLabel: MFAChanged}, event.Labels)
+	assert.Equal(t, strings.Repeat("u", 64), event.UserName)
+	assert.Len(t, event.Detail, maxDetailLen)
+	assert.Empty(t, event.SourceIP)
+}
+
+func TestRecord(t *testing.T) {
+	Record(Event{Type: Logout, UserName: "u-abc12"})
+
+	select {
+	case event := <-queue:
+		assert.Equal(t, Logout, event.Type)
+		assert.Equal(t, "u-abc12", event.UserName)
+	default:
+		t.Fatal("the event wasn't queued")
+	}
+}
+
+func TestPurge(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, settings.AuthEventRetentionHours.Set("24"))
+	defer settings.AuthEventRetentionHours.Set(settings.AuthEventRetentionHours.Default)
+
+	event := func(name string, age time.Duration) v3.AuthEvent {
+		return v3.AuthEvent{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Time:       metav1.NewTime(now.Add(-age)),
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	events := fake.NewMockNonNamespacedClientInterface[*v3.AuthEvent, *v3.AuthEventList](ctrl)
+	events.EXPECT().List(metav1.ListOptions{Limit: listPageSize}).Return(&v3.AuthEventList{
+		ListMeta: metav1.ListMeta{Continue: "next"},
+		Items:    []v3.AuthEvent{event("old", 48*time.Hour), event("recent", time.Hour)},
+	}, nil)
+	events.EXPECT().List(metav1.ListOptions{Limit: listPageSize, Continue: "next"}).Return(&v3.AuthEventList{
+		Items: []v3.AuthEvent{event("older", 72*time.Hour)},
+	}, nil)
+	events.EXPECT().Delete("old", gomock.Any()).Return(nil)
+	events.EXPECT().Delete("older", gomock.Any()).Return(nil)
+
+	p := &purger{events: events, now: func() time.Time { return now }}
+	p.purge()
+}
+
+func TestPurgeKeepsEventsWithoutRetention(t *testing.T) {
+	require.NoError(t, settings.AuthEventRetentionHours.Set("0"))
+	defer settings.AuthEventRetentionHours.Set(settings.AuthEventRetentionHours.Default)
+
+	ctrl := gomock.NewController(t)
+	events := fake.NewMockNonNamespacedClientInterface[*v3.AuthEvent, *v3.AuthEventList](ctrl)
+
+	p := &purger{events: events, now: time.Now}
+	p.purge()
+}
@@ -0,0 +1,167 @@
+// Package bindingpolicy sends the ClusterRoleTemplateBindings, ProjectRoleTemplateBindings and GlobalRoleBindings about
+// to be created to the external policy endpoint of binding-policy-url, which allows or denies them with reasons, so
+// that rules like "no cluster owners outside of group X" are enforced centrally. The endpoint is either an HTTP
+// endpoint reviewing the bindings, or the data API of an Open Policy Agent evaluating a Rego policy.
+package bindingpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// FormatHTTP is the format of the HTTP endpoints, which are sent a Review and respond with a Decision.
+	FormatHTTP = "http"
+	// FormatOPA is the format of the data API of Open Policy Agent, which is sent {"input": Review} and responds with
+	// {"result": Decision}.
+	FormatOPA = "opa"
+
+	policyTimeout = 10 * time.Second
+	// maxDecisionSize bounds the responses of the policy endpoint.
+	maxDecisionSize = 1024 * 1024
+)
+
+// policyClient is the client of the policy endpoint.
+var policyClient = http.DefaultClient
+
+// Binding is a binding about to be created.
+type Binding struct {
+	// Kind is ClusterRoleTemplateBinding, ProjectRoleTemplateBinding or GlobalRoleBinding.
+	Kind               string `json:"kind"`
+	ClusterName        string `json:"clusterName,omitempty"`
+	ProjectName        string `json:"projectName,omitempty"`
+	RoleTemplateName   string `json:"roleTemplateName,omitempty"`
+	GlobalRoleName     string `json:"globalRoleName,omitempty"`
+	UserName           string `json:"userName,omitempty"`
+	UserPrincipalName  string `json:"userPrincipalName,omitempty"`
+	GroupName          string `json:"groupName,omitempty"`
+	GroupPrincipalName string `json:"groupPrincipalName,omitempty"`
+}
+
+// Review is sent to the policy endpoint.
+type Review struct {
+	Binding Binding `json:"binding"`
+	// Requester is the name of the user creating the binding.
+	Requester string `json:"requester"`
+}
+
+// Decision is the response of the policy endpoint.
+type Decision struct {
+	Allowed bool     `json:"allowed"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// DeniedError is returned for the bindings the policy endpoint denies.
+type DeniedError struct {
+	Reasons []string
+}
+
+func (e *DeniedError) Error() string {
+	message := "binding denied by the binding policy"
+	if len(e.Reasons) > 0 {
+		message += ": " + strings.Join(e.Reasons, "; ")
+	}
+	return message
+}
+
+// Enforce returns a permission denied error if the policy endpoint denies the creation of the binding by the user of
+// the request. When the endpoint can't be queried, the binding is denied unless binding-policy-fail-open is "true".
+func Enforce(request *types.APIContext, binding Binding) error {
+	err := Evaluate(request.Request.Context(), request.Request.Header.Get("Impersonate-User"), binding)
+	var denied *DeniedError
+	if errors.As(err, &denied) {
+		return httperror.NewAPIError(httperror.PermissionDenied, denied.Error())
+	}
+	if err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Error checking the binding policy: %v", err))
+	}
+	return nil
+}
+
+// Evaluate returns a DeniedError if the policy endpoint denies the creation of the binding by the requester, or an
+// error if the endpoint can't be queried and binding-policy-fail-open isn't "true". It returns nil without a policy
+// endpoint.
+func Evaluate(ctx context.Context, requester string, binding Binding) error {
+	url := settings.BindingPolicyURL.Get()
+	if url == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, policyTimeout)
+	defer cancel()
+	decision, err := Check(ctx, url, settings.BindingPolicyFormat.Get(), Review{Binding: binding, Requester: requester})
+	if err != nil {
+		if settings.BindingPolicyFailOpen.Get() == "true" {
+			logrus.Warnf("[bindingpolicy] failed to check the %s of %s, allowing it: %v", binding.Kind, requester, err)
+			return nil
+		}
+		return err
+	}
+	if !decision.Allowed {
+		logrus.Infof("[bindingpolicy] denied the %s of %s: %s", binding.Kind, requester, strings.Join(decision.Reasons, "; "))
+		return &DeniedError{Reasons: decision.Reasons}
+	}
+	return nil
+}
+
+// Check sends the review to the policy endpoint of the format, and returns its decision.
+func Check(ctx context.Context, url, format string, review Review) (Decision, error) {
+	var payload any = review
+	switch format {
+	case FormatHTTP, "":
+	case FormatOPA:
+		payload = map[string]any{"input": review}
+	default:
+		return Decision{}, fmt.Errorf("unknown format %q", format)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := policyClient.Do(req)
+	if err != nil {
+		return Decision{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Decision{}, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, maxDecisionSize))
+	if format == FormatOPA {
+		// The result is missing when the policy is undefined, which denies the bindings.
+		var result struct {
+			Result *Decision `json:"result"`
+		}
+		if err := decoder.Decode(&result); err != nil {
+			return Decision{}, fmt.Errorf("decoding the decision: %w", err)
+		}
+		if result.Result == nil {
+			return Decision{Reasons: []string{"the binding policy is undefined"}}, nil
+		}
+		return *result.Result, nil
+	}
+	var decision Decision
+	if err := decoder.Decode(&decision); err != nil {
+		return Decision{}, fmt.Errorf("decoding the decision: %w", err)
+	}
+	return decision, nil
+}
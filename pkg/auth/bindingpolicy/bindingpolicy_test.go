@@ -0,0 +1,121 @@
+package bindingpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const platform = "openldap_group://cn=platform,ou=groups,dc=example,dc=com"
+
+// policyServer allows the cluster owners of the platform group only, with the http format, or with the opa format
+// under /v1/data/rancher/bindings. The other paths of the opa format are undefined.
+func policyServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var review Review
+		opa := r.URL.Path != "/review"
+		if opa {
+			var input struct {
+				Input Review `json:"input"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+			review = input.Input
+		} else {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		}
+
+		switch r.URL.Path {
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case "/v1/data/rancher/undefined":
+			w.Write([]byte(`{}`))
+			return
+		}
+		decision := Decision{Allowed: true}
+		if review.Binding.RoleTemplateName == "cluster-owner" && review.Binding.GroupPrincipalName != platform {
+			decision = Decision{Reasons: []string{"cluster owners must be granted to the platform group", "requested by " + review.Requester}}
+		}
+		if opa {
+			json.NewEncoder(w).Encode(map[string]Decision{"result": decision})
+			return
+		}
+		json.NewEncoder(w).Encode(decision)
+	}))
+}
+
+func TestCheck(t *testing.T) {
+	server := policyServer(t)
+	defer server.Close()
+
+	owner := Review{Binding: Binding{Kind: "ClusterRoleTemplateBinding", ClusterName: "c-abcde", RoleTemplateName: "cluster-owner", UserName: "u-alice"}, Requester: "u-bob"}
+	member := Review{Binding: Binding{Kind: "ClusterRoleTemplateBinding", ClusterName: "c-abcde", RoleTemplateName: "cluster-member", UserName: "u-alice"}, Requester: "u-bob"}
+
+	for _, test := range []struct {
+		format string
+		url    string
+	}{
+		{format: FormatHTTP, url: server.URL + "/review"},
+		{format: FormatOPA, url: server.URL + "/v1/data/rancher/bindings"},
+	} {
+		decision, err := Check(context.Background(), test.url, test.format, owner)
+		require.NoError(t, err)
+		assert.Equal(t, Decision{Reasons: []string{"cluster owners must be granted to the platform group", "requested by u-bob"}}, decision, test.format)
+
+		decision, err = Check(context.Background(), test.url, test.format, member)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed, test.format)
+	}
+
+	// The bindings are denied when the policy is undefined.
+	decision, err := Check(context.Background(), server.URL+"/v1/data/rancher/undefined", FormatOPA, member)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+
+	_, err = Check(context.Background(), server.URL+"/review", "rego", member)
+	assert.Error(t, err)
+}
+
+func TestEnforce(t *testing.T) {
+	defer settings.BindingPolicyURL.Set(settings.BindingPolicyURL.Default)
+	defer settings.BindingPolicyFailOpen.Set(settings.BindingPolicyFailOpen.Default)
+
+	server := policyServer(t)
+	defer server.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/v3/clusterroletemplatebindings", nil)
+	request.Header.Set("Impersonate-User", "u-bob")
+	apiContext := &types.APIContext{Request: request}
+	owner := Binding{Kind: "ClusterRoleTemplateBinding", ClusterName: "c-abcde", RoleTemplateName: "cluster-owner", UserName: "u-alice"}
+
+	// The bindings aren't checked without a policy endpoint.
+	assert.NoError(t, Enforce(apiContext, owner))
+
+	require.NoError(t, settings.BindingPolicyURL.Set(server.URL+"/review"))
+	err := Enforce(apiContext, owner)
+	var apiErr *httperror.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, httperror.PermissionDenied, apiErr.Code)
+	assert.Equal(t, "binding denied by the binding policy: cluster owners must be granted to the platform group; requested by u-bob", apiErr.Message)
+
+	owner.UserName, owner.GroupPrincipalName = "", platform
+	assert.NoError(t, Enforce(apiContext, owner))
+
+	// The bindings are denied while the endpoint is unavailable, unless the policy fails open.
+	require.NoError(t, settings.BindingPolicyURL.Set(server.URL+"/unavailable"))
+	require.True(t, errors.As(Enforce(apiContext, owner), &apiErr))
+	assert.Equal(t, httperror.ServerError, apiErr.Code)
+
+	require.NoError(t, settings.BindingPolicyFailOpen.Set("true"))
+	assert.NoError(t, Enforce(apiContext, owner))
+}
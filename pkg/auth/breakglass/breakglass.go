@@ -0,0 +1,553 @@
+// Package breakglass manages the break glass accounts, the emergency access accounts for when the identity provider
+// is down. An administrator seals a credential for the local user of an account, which is shown once, to be kept
+// offline, and the user stays disabled. The holder of the credential activates the account with a reason, without
+// logging in, which enables the user and grants it the global role of the account for a bounded duration. Once the
+// activation expires, or is ended by an administrator, the user is disabled again, logged out and its credential
+// burnt. The activations are recorded in the status of the accounts, logged as audit events and recorded as
+// SecurityEvents, and the addresses of break-glass-notification-recipients are notified of them.
+package breakglass
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/notifications"
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
+	"github.com/rancher/rancher/pkg/auth/securityevents"
+	"github.com/rancher/rancher/pkg/auth/sessions"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// StateUnsealed, StateSealed, StateActive and StateEnded are the states of the break glass accounts.
+	StateUnsealed = "Unsealed"
+	StateSealed   = "Sealed"
+	StateActive   = "Active"
+	StateEnded    = "Ended"
+
+	// AccountLabel is set on the users and the global role bindings of the break glass accounts to the name of their
+	// account.
+	AccountLabel = "auth.cattle.io/break-glass-account"
+
+	credentialLen   = 32
+	maxReasonLength = 1024
+	// maxActivations is how many activations are kept in the status of an account, the oldest are dropped first.
+	maxActivations = 20
+)
+
+var (
+	// ErrInvalidCredential is returned when the account of the username isn't sealed or the credential doesn't match.
+	// The activations don't tell which, so that they don't reveal the accounts.
+	ErrInvalidCredential = errors.New("invalid break glass username or credential")
+	// ErrActive is returned when sealing an active account.
+	ErrActive = errors.New("break glass account is active")
+	// ErrNotActive is returned when ending an account which isn't active.
+	ErrNotActive = errors.New("break glass account isn't active")
+	// ErrCannotBind is returned when sealing an account whose global role the caller isn't allowed to bind.
+	ErrCannotBind = errors.New("not allowed to bind the global role of the break glass account")
+)
+
+// InvalidInputError is returned for the activations with an invalid reason or duration, and for the accounts which
+// can't be sealed as they are.
+type InvalidInputError struct {
+	err error
+}
+
+func (e *InvalidInputError) Error() string {
+	return e.err.Error()
+}
+
+func (e *InvalidInputError) Unwrap() error {
+	return e.err
+}
+
+// revoker revokes the tokens of a user.
+type revoker interface {
+	LogoutEverywhere(userID, reason string) (int, error)
+}
+
+// Manager seals, activates and ends the break glass accounts.
+type Manager struct {
+	accounts     mgmtcontrollers.BreakGlassAccountClient
+	accountCache mgmtcontrollers.BreakGlassAccountCache
+	users        mgmtcontrollers.UserClient
+	userCache    mgmtcontrollers.UserCache
+	grbs         mgmtcontrollers.GlobalRoleBindingClient
+	grbCache     mgmtcontrollers.GlobalRoleBindingCache
+	crtbCache    mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbCache    mgmtcontrollers.ProjectRoleTemplateBindingCache
+	revoker      revoker
+	mailer       notifications.Mailer
+	now          func() time.Time
+
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewManager returns a Manager of the break glass accounts of the management cluster.
+func NewManager(ctx context.Context, mgmt *config.ScaledContext) *Manager {
+	return &Manager{
+		accounts:     mgmt.Wrangler.Mgmt.BreakGlassAccount(),
+		accountCache: mgmt.Wrangler.Mgmt.BreakGlassAccount().Cache(),
+		users:        mgmt.Wrangler.Mgmt.User(),
+		userCache:    mgmt.Wrangler.Mgmt.User().Cache(),
+		grbs:         mgmt.Wrangler.Mgmt.GlobalRoleBinding(),
+		grbCache:     mgmt.Wrangler.Mgmt.GlobalRoleBinding().Cache(),
+		crtbCache:    mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbCache:    mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		revoker:      sessions.NewRevoker(ctx, mgmt),
+		mailer:       mail.NewSender(mgmt.Core.Secrets("").Controller().Lister()),
+		now:          time.Now,
+
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+	}
+}
+
+// State returns the state of the account, which is unsealed until a credential is sealed.
+func State(account *v3.BreakGlassAccount) string {
+	if account.Status.State == "" {
+		return StateUnsealed
+	}
+	return account.Status.State
+}
+
+// Seal generates a new credential for the local user of the account, disables it and logs it out. The credential is
+// returned once, it's only stored hashed. The global role of the account must be one of
+// break-glass-allowed-global-roles and the caller must be allowed to bind it, as sealing hands it to the holder of the
+// credential. The user must hold no other binding, so that it grants nothing but the global role of the account.
+func (m *Manager) Seal(ctx context.Context, account *v3.BreakGlassAccount, caller k8suser.Info) (string, *v3.BreakGlassAccount, error) {
+	if State(account) == StateActive {
+		return "", nil, ErrActive
+	}
+	// The maximum duration is checked now rather than on activation, so that it can be fixed before an emergency.
+	if maxDuration, err := time.ParseDuration(account.Spec.MaxDuration); err != nil || maxDuration <= 0 {
+		return "", nil, &InvalidInputError{err: fmt.Errorf("invalid maxDuration %q, must be a positive duration like 1h", account.Spec.MaxDuration)}
+	}
+	if !allowedGlobalRole(account.Spec.GlobalRoleName) {
+		return "", nil, &InvalidInputError{err: fmt.Errorf("global role %q isn't allowed for break glass accounts, see %s",
+			account.Spec.GlobalRoleName, settings.BreakGlassAllowedGlobalRoles.Name)}
+	}
+	allowed, err := util.Authorize(ctx, m.subjectAccessReviews, caller, authzv1.ResourceAttributes{
+		Group:    v3.SchemeGroupVersion.Group,
+		Resource: v3.GlobalRoleResourceName,
+		Verb:     "bind",
+		Name:     account.Spec.GlobalRoleName,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if !allowed {
+		return "", nil, ErrCannotBind
+	}
+	actor := caller.GetName()
+
+	user, err := m.users.Get(account.Spec.UserName, metav1.GetOptions{})
+	if err != nil {
+		return "", nil, fmt.Errorf("getting user %s: %w", account.Spec.UserName, err)
+	}
+	if !isLocal(user) {
+		return "", nil, fmt.Errorf("user %s isn't a local user", user.Name)
+	}
+	bindings, err := m.otherBindings(account, user)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(bindings) > 0 {
+		return "", nil, &InvalidInputError{err: fmt.Errorf("user %s holds other bindings: %s", user.Name, strings.Join(bindings, ", "))}
+	}
+
+	raw := make([]byte, credentialLen)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("generating a credential: %w", err)
+	}
+	credential := base64.RawURLEncoding.EncodeToString(raw)
+	hash, err := passwordhash.Hash(credential)
+	if err != nil {
+		return "", nil, err
+	}
+
+	user = user.DeepCopy()
+	user.Password = hash
+	user.MustChangePassword = false
+	user.Enabled = pointer.Bool(false)
+	if user.Labels == nil {
+		user.Labels = map[string]string{}
+	}
+	user.Labels[AccountLabel] = account.Name
+	if _, err := m.users.Update(user); err != nil {
+		return "", nil, fmt.Errorf("sealing the credential of user %s: %w", user.Name, err)
+	}
+	if _, err := m.revoker.LogoutEverywhere(user.Name, "break glass account sealed"); err != nil {
+		logrus.Errorf("[breakglass] failed to log out user %s: %v", user.Name, err)
+	}
+
+	account = account.DeepCopy()
+	now := metav1.NewTime(m.now())
+	account.Status.State = StateSealed
+	account.Status.SealedBy = actor
+	account.Status.SealedAt = &now
+	account.Status.ExpiresAt = nil
+	account.Status.BindingName = ""
+	sealed, err := m.accounts.UpdateStatus(account)
+	if err != nil {
+		return "", nil, err
+	}
+	audit("BreakGlassAccountSealed", sealed, actor, nil)
+	return credential, sealed, nil
+}
+
+// allowedGlobalRole tells whether the global role is one of break-glass-allowed-global-roles.
+func allowedGlobalRole(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, allowed := range strings.Split(settings.BreakGlassAllowedGlobalRoles.Get(), ",") {
+		if strings.TrimSpace(allowed) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// otherBindings returns the global role, cluster and project role template bindings of the user, but the global role
+// binding of the account.
+func (m *Manager) otherBindings(account *v3.BreakGlassAccount, user *v3.User) ([]string, error) {
+	isUser := func(userName, principalName string) bool {
+		return userName == user.Name || (principalName != "" && slices.Contains(user.PrincipalIDs, principalName))
+	}
+	var bindings []string
+	grbs, err := m.grbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing global role bindings: %w", err)
+	}
+	for _, grb := range grbs {
+		if isUser(grb.UserName, grb.UserPrincipalName) && grb.Labels[AccountLabel] != account.Name {
+			bindings = append(bindings, "globalrolebinding "+grb.Name)
+		}
+	}
+	crtbs, err := m.crtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing cluster role template bindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		if isUser(crtb.UserName, crtb.UserPrincipalName) {
+			bindings = append(bindings, "clusterroletemplatebinding "+crtb.Namespace+"/"+crtb.Name)
+		}
+	}
+	prtbs, err := m.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing project role template bindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		if isUser(prtb.UserName, prtb.UserPrincipalName) {
+			bindings = append(bindings, "projectroletemplatebinding "+prtb.Namespace+"/"+prtb.Name)
+		}
+	}
+	return bindings, nil
+}
+
+// Activate enables the user of the account with the username and the credential, and grants it the global role of
+// the account for the duration, the maximum duration of the account if empty.
+func (m *Manager) Activate(username, credential, reason, duration string, source net.IP) (*v3.BreakGlassAccount, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > maxReasonLength {
+		return nil, &InvalidInputError{err: fmt.Errorf("reason is required and must be at most %d characters", maxReasonLength)}
+	}
+
+	account, user, err := m.accountOf(username)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		auditFailure(username, "unknown username", source)
+		return nil, ErrInvalidCredential
+	}
+	if state := State(account); state != StateSealed {
+		auditFailure(username, "account is "+state, source)
+		return nil, ErrInvalidCredential
+	}
+	if err := passwordhash.Verify(user.Password, credential); err != nil {
+		auditFailure(username, "credential mismatch", source)
+		return nil, ErrInvalidCredential
+	}
+
+	maxDuration, err := time.ParseDuration(account.Spec.MaxDuration)
+	if err != nil || maxDuration <= 0 {
+		return nil, fmt.Errorf("invalid maxDuration %q of break glass account %s", account.Spec.MaxDuration, account.Name)
+	}
+	activeFor := maxDuration
+	if duration != "" {
+		activeFor, err = time.ParseDuration(duration)
+		if err != nil || activeFor <= 0 || activeFor > maxDuration {
+			return nil, &InvalidInputError{err: fmt.Errorf("duration must be a positive duration like 1h, of at most %s", account.Spec.MaxDuration)}
+		}
+	}
+
+	now := m.now()
+	expiresAt := metav1.NewTime(now.Add(activeFor))
+	sourceAddress := ""
+	if source != nil {
+		sourceAddress = source.String()
+	}
+	// The account is activated before the role is granted, so that concurrent activations can't both succeed and the
+	// user isn't disabled again as the user of an inactive account.
+	account = account.DeepCopy()
+	account.Status.State = StateActive
+	account.Status.ExpiresAt = &expiresAt
+	account.Status.BindingName = BindingName(account)
+	account.Status.Activations = append(account.Status.Activations, v3.BreakGlassActivation{
+		Reason:        reason,
+		SourceAddress: sourceAddress,
+		ActivatedAt:   metav1.NewTime(now),
+		ExpiresAt:     expiresAt,
+	})
+	if len(account.Status.Activations) > maxActivations {
+		account.Status.Activations = account.Status.Activations[len(account.Status.Activations)-maxActivations:]
+	}
+	activated, err := m.accounts.UpdateStatus(account)
+	if err != nil {
+		return nil, fmt.Errorf("activating break glass account %s: %w", account.Name, err)
+	}
+	audit("BreakGlassAccountActivated", activated, user.Username, source)
+	securityevents.Record(securityevents.Event{
+		Type:     securityevents.BreakGlassUsed,
+		UserName: activated.Spec.UserName,
+		Target:   activated.Name,
+		SourceIP: source,
+		Detail:   fmt.Sprintf("global role %s granted for %s: %s", activated.Spec.GlobalRoleName, activeFor, reason),
+	})
+	m.notify(mail.BreakGlassActivatedMessage, activated, user.Username, now)
+
+	if err := m.grant(activated, user); err != nil {
+		// The grant is retried along with the expiry checks.
+		logrus.Errorf("[breakglass] %v", err)
+	}
+	return activated, nil
+}
+
+// accountOf returns the break glass account whose user has the username, and its user, or nil if there's none.
+// The account and the user are read from the API server, so that the activation is decided on their latest state.
+func (m *Manager) accountOf(username string) (*v3.BreakGlassAccount, *v3.User, error) {
+	accounts, err := m.accountCache.List(labels.Everything())
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing break glass accounts: %w", err)
+	}
+	for _, account := range accounts {
+		cached, err := m.userCache.Get(account.Spec.UserName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting user %s: %w", account.Spec.UserName, err)
+		}
+		if cached.Username != username {
+			continue
+		}
+		user, err := m.users.Get(cached.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting user %s: %w", cached.Name, err)
+		}
+		latest, err := m.accounts.Get(account.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting break glass account %s: %w", account.Name, err)
+		}
+		return latest, user, nil
+	}
+	return nil, nil, nil
+}
+
+// BindingName returns the name of the global role binding granting the role of the account, so that granting it
+// again doesn't bind the role twice.
+func BindingName(account *v3.BreakGlassAccount) string {
+	return name.SafeConcatName("breakglass", account.Name)
+}
+
+// grant binds the global role of the account to its user and enables it.
+func (m *Manager) grant(account *v3.BreakGlassAccount, user *v3.User) error {
+	_, err := m.grbs.Create(&v3.GlobalRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   account.Status.BindingName,
+			Labels: map[string]string{AccountLabel: account.Name},
+		},
+		GlobalRoleName: account.Spec.GlobalRoleName,
+		UserName:       user.Name,
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("granting global role %s to user %s: %w", account.Spec.GlobalRoleName, user.Name, err)
+	}
+
+	if pointer.BoolDeref(user.Enabled, true) {
+		return nil
+	}
+	user = user.DeepCopy()
+	user.Enabled = pointer.Bool(true)
+	if _, err := m.users.Update(user); err != nil {
+		return fmt.Errorf("enabling user %s: %w", user.Name, err)
+	}
+	return nil
+}
+
+// End ends the activation of the account: its global role binding is deleted, and its user is disabled, logged out
+// and its credential burnt. The actor is empty when the activation expired.
+func (m *Manager) End(account *v3.BreakGlassAccount, actor string) (*v3.BreakGlassAccount, error) {
+	if State(account) != StateActive {
+		return nil, ErrNotActive
+	}
+	if account.Status.BindingName != "" {
+		err := m.grbs.Delete(account.Status.BindingName, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("deleting global role binding %s: %w", account.Status.BindingName, err)
+		}
+	}
+
+	username := ""
+	user, err := m.users.Get(account.Spec.UserName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("getting user %s: %w", account.Spec.UserName, err)
+	}
+	if err == nil {
+		if err := m.disable(user, true); err != nil {
+			return nil, err
+		}
+		username = user.Username
+	}
+
+	account = account.DeepCopy()
+	now := metav1.NewTime(m.now())
+	account.Status.State = StateEnded
+	account.Status.ExpiresAt = nil
+	account.Status.BindingName = ""
+	account.Status.SealedBy = ""
+	account.Status.SealedAt = nil
+	if last := len(account.Status.Activations) - 1; last >= 0 {
+		account.Status.Activations[last].EndedAt = &now
+		account.Status.Activations[last].EndedBy = actor
+	}
+	ended, err := m.accounts.UpdateStatus(account)
+	if err != nil {
+		return nil, fmt.Errorf("ending break glass account %s: %w", account.Name, err)
+	}
+
+	event := "BreakGlassAccountEnded"
+	if actor == "" {
+		event = "BreakGlassAccountExpired"
+	}
+	audit(event, ended, actor, nil)
+	m.notify(mail.BreakGlassEndedMessage, ended, username, now.Time)
+	return ended, nil
+}
+
+// disable disables the user and logs it out. Its credential is replaced by a random one if burn is set.
+func (m *Manager) disable(user *v3.User, burn bool) error {
+	user = user.DeepCopy()
+	user.Enabled = pointer.Bool(false)
+	if burn {
+		raw := make([]byte, credentialLen)
+		if _, err := rand.Read(raw); err != nil {
+			return fmt.Errorf("generating a credential: %w", err)
+		}
+		hash, err := passwordhash.Hash(base64.RawURLEncoding.EncodeToString(raw))
+		if err != nil {
+			return err
+		}
+		user.Password = hash
+	}
+	if _, err := m.users.Update(user); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("disabling user %s: %w", user.Name, err)
+	}
+	if _, err := m.revoker.LogoutEverywhere(user.Name, "break glass account inactive"); err != nil {
+		return fmt.Errorf("logging out user %s: %w", user.Name, err)
+	}
+	return nil
+}
+
+// notify emails the message about the account to the addresses of break-glass-notification-recipients.
+func (m *Manager) notify(message string, account *v3.BreakGlassAccount, username string, at time.Time) {
+	if m.mailer == nil || !mail.Configured() {
+		return
+	}
+	data := mail.BreakGlassData{
+		Account:  account.Name,
+		Username: username,
+		Role:     account.Spec.GlobalRoleName,
+		Time:     at.UTC().Format(time.RFC1123),
+	}
+	if last := len(account.Status.Activations) - 1; last >= 0 {
+		activation := account.Status.Activations[last]
+		data.Reason = activation.Reason
+		data.Address = activation.SourceAddress
+		data.ExpiresAt = activation.ExpiresAt.UTC().Format(time.RFC1123)
+	}
+	if data.Address == "" {
+		data.Address = "unknown"
+	}
+	for _, address := range strings.Split(settings.BreakGlassNotificationRecipients.Get(), ",") {
+		if address = strings.TrimSpace(address); address == "" {
+			continue
+		}
+		if err := m.mailer.SendMessage(address, message, data); err != nil {
+			logrus.Errorf("[breakglass] failed to notify %s of break glass account %s: %v", address, account.Name, err)
+		}
+	}
+}
+
+func isLocal(user *v3.User) bool {
+	for _, principalID := range user.PrincipalIDs {
+		if strings.HasPrefix(principalID, "local://") {
+			return true
+		}
+	}
+	return false
+}
+
+func audit(event string, account *v3.BreakGlassAccount, actor string, source net.IP) {
+	fields := logrus.Fields{
+		"event":             event,
+		"breakGlassAccount": account.Name,
+		"user":              account.Spec.UserName,
+		"globalRoleName":    account.Spec.GlobalRoleName,
+		"actor":             actor,
+	}
+	if source != nil {
+		fields["sourceAddress"] = source.String()
+	}
+	if account.Status.ExpiresAt != nil {
+		fields["expiresAt"] = account.Status.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if last := len(account.Status.Activations) - 1; last >= 0 && event == "BreakGlassAccountActivated" {
+		fields["reason"] = account.Status.Activations[last].Reason
+	}
+	logrus.WithFields(fields).Info("breakglass: audit")
+}
+
+func auditFailure(username, reason string, source net.IP) {
+	fields := logrus.Fields{
+		"event":    "BreakGlassActivationFailed",
+		"username": username,
+		"reason":   reason,
+	}
+	if source != nil {
+		fields["sourceAddress"] = source.String()
+	}
+	logrus.WithFields(fields).Warn("breakglass: audit")
+}
@@ -0,0 +1,423 @@
+package breakglass
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/utils/pointer"
+)
+
+var admin = &k8suser.DefaultInfo{Name: "u-admin"}
+
+type fakeRevoker struct {
+	revoked []string
+}
+
+func (f *fakeRevoker) LogoutEverywhere(userID, _ string) (int, error) {
+	f.revoked = append(f.revoked, userID)
+	return 1, nil
+}
+
+// fakeSubjectAccessReviews allows u-admin to bind every global role.
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	attributes := sar.Spec.ResourceAttributes
+	sar.Status.Allowed = sar.Spec.User == "u-admin" && attributes.Verb == "bind" && attributes.Resource == "globalroles"
+	return sar, nil
+}
+
+type mocks struct {
+	accounts     *fake.MockNonNamespacedClientInterface[*v3.BreakGlassAccount, *v3.BreakGlassAccountList]
+	accountCache *fake.MockNonNamespacedCacheInterface[*v3.BreakGlassAccount]
+	users        *fake.MockNonNamespacedClientInterface[*v3.User, *v3.UserList]
+	userCache    *fake.MockNonNamespacedCacheInterface[*v3.User]
+	grbs         *fake.MockNonNamespacedClientInterface[*v3.GlobalRoleBinding, *v3.GlobalRoleBindingList]
+	grbCache     *fake.MockNonNamespacedCacheInterface[*v3.GlobalRoleBinding]
+	crtbCache    *fake.MockCacheInterface[*v3.ClusterRoleTemplateBinding]
+	prtbCache    *fake.MockCacheInterface[*v3.ProjectRoleTemplateBinding]
+	revoker      *fakeRevoker
+}
+
+var now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func newManager(t *testing.T) (*Manager, *mocks) {
+	ctrl := gomock.NewController(t)
+	m := &mocks{
+		accounts:     fake.NewMockNonNamespacedClientInterface[*v3.BreakGlassAccount, *v3.BreakGlassAccountList](ctrl),
+		accountCache: fake.NewMockNonNamespacedCacheInterface[*v3.BreakGlassAccount](ctrl),
+		users:        fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl),
+		userCache:    fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl),
+		grbs:         fake.NewMockNonNamespacedClientInterface[*v3.GlobalRoleBinding, *v3.GlobalRoleBindingList](ctrl),
+		grbCache:     fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl),
+		crtbCache:    fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl),
+		prtbCache:    fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl),
+		revoker:      &fakeRevoker{},
+	}
+	m.accounts.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(account *v3.BreakGlassAccount) (*v3.BreakGlassAccount, error) {
+		return account, nil
+	}).AnyTimes()
+	return &Manager{
+		accounts:     m.accounts,
+		accountCache: m.accountCache,
+		users:        m.users,
+		userCache:    m.userCache,
+		grbs:         m.grbs,
+		grbCache:     m.grbCache,
+		crtbCache:    m.crtbCache,
+		prtbCache:    m.prtbCache,
+		revoker:      m.revoker,
+		now:          func() time.Time { return now },
+
+		subjectAccessReviews: &fakeSubjectAccessReviews{},
+	}, m
+}
+
+func newAccount(state string) *v3.BreakGlassAccount {
+	return &v3.BreakGlassAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "bg-ops"},
+		Spec: v3.BreakGlassAccountSpec{
+			UserName:       "u-ops",
+			GlobalRoleName: "admin",
+			MaxDuration:    "1h",
+		},
+		Status: v3.BreakGlassAccountStatus{State: state},
+	}
+}
+
+func newUser(t *testing.T, credential string, enabled bool) *v3.User {
+	hash, err := passwordhash.Hash(credential)
+	require.NoError(t, err)
+	return &v3.User{
+		ObjectMeta:   metav1.ObjectMeta{Name: "u-ops"},
+		Username:     "breakglass-ops",
+		Password:     hash,
+		PrincipalIDs: []string{"local://u-ops"},
+		Enabled:      pointer.Bool(enabled),
+	}
+}
+
+func TestSeal(t *testing.T) {
+	t.Parallel()
+	manager, m := newManager(t)
+	user := newUser(t, "previous", true)
+	m.users.EXPECT().Get("u-ops", gomock.Any()).Return(user, nil)
+	m.grbCache.EXPECT().List(labels.Everything()).Return([]*v3.GlobalRoleBinding{
+		// The binding of the account and the bindings of other users don't prevent sealing.
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "grb-bg-ops", Labels: map[string]string{AccountLabel: "bg-ops"}},
+			UserName:       "u-ops",
+			GlobalRoleName: "admin",
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-admin"}, UserName: "u-admin", GlobalRoleName: "admin"},
+	}, nil)
+	m.crtbCache.EXPECT().List("", labels.Everything()).Return(nil, nil)
+	m.prtbCache.EXPECT().List("", labels.Everything()).Return(nil, nil)
+	var updated *v3.User
+	m.users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+		updated = user
+		return user, nil
+	})
+
+	credential, sealed, err := manager.Seal(context.Background(), newAccount(StateEnded), admin)
+	require.NoError(t, err)
+
+	require.NotNil(t, updated)
+	assert.NoError(t, passwordhash.Verify(updated.Password, credential))
+	assert.False(t, *updated.Enabled)
+	assert.Equal(t, "bg-ops", updated.Labels[AccountLabel])
+	assert.Equal(t, []string{"u-ops"}, m.revoker.revoked)
+	assert.Equal(t, StateSealed, sealed.Status.State)
+	assert.Equal(t, "u-admin", sealed.Status.SealedBy)
+	assert.Equal(t, metav1.NewTime(now), *sealed.Status.SealedAt)
+}
+
+func TestSealRejected(t *testing.T) {
+	t.Parallel()
+
+	t.Run("active account", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newManager(t)
+		_, _, err := manager.Seal(context.Background(), newAccount(StateActive), admin)
+		assert.ErrorIs(t, err, ErrActive)
+	})
+
+	t.Run("invalid max duration", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newManager(t)
+		account := newAccount("")
+		account.Spec.MaxDuration = "forever"
+		_, _, err := manager.Seal(context.Background(), account, admin)
+		var invalidInput *InvalidInputError
+		assert.ErrorAs(t, err, &invalidInput)
+	})
+
+	t.Run("global role not allowed", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newManager(t)
+		account := newAccount("")
+		account.Spec.GlobalRoleName = "restricted-admin"
+		_, _, err := manager.Seal(context.Background(), account, admin)
+		var invalidInput *InvalidInputError
+		assert.ErrorAs(t, err, &invalidInput)
+	})
+
+	t.Run("caller not allowed to bind the global role", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newManager(t)
+		_, _, err := manager.Seal(context.Background(), newAccount(""), &k8suser.DefaultInfo{Name: "u-operator"})
+		assert.ErrorIs(t, err, ErrCannotBind)
+	})
+
+	t.Run("user holding a global role binding", func(t *testing.T) {
+		t.Parallel()
+		manager, m := newManager(t)
+		m.users.EXPECT().Get("u-ops", gomock.Any()).Return(newUser(t, "previous", true), nil)
+		m.grbCache.EXPECT().List(labels.Everything()).Return([]*v3.GlobalRoleBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "grb-ops"}, UserName: "u-ops", GlobalRoleName: "user"},
+		}, nil)
+		m.crtbCache.EXPECT().List("", labels.Everything()).Return(nil, nil)
+		m.prtbCache.EXPECT().List("", labels.Everything()).Return(nil, nil)
+		_, _, err := manager.Seal(context.Background(), newAccount(""), admin)
+		var invalidInput *InvalidInputError
+		require.ErrorAs(t, err, &invalidInput)
+		assert.ErrorContains(t, err, "globalrolebinding grb-ops")
+	})
+
+	t.Run("user holding a project role template binding", func(t *testing.T) {
+		t.Parallel()
+		manager, m := newManager(t)
+		m.users.EXPECT().Get("u-ops", gomock.Any()).Return(newUser(t, "previous", true), nil)
+		m.grbCache.EXPECT().List(labels.Everything()).Return(nil, nil)
+		m.crtbCache.EXPECT().List("", labels.Everything()).Return(nil, nil)
+		m.prtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ProjectRoleTemplateBinding{
+			{
+				ObjectMeta:        metav1.ObjectMeta{Name: "prtb-ops", Namespace: "p-abcde"},
+				UserPrincipalName: "local://u-ops",
+				RoleTemplateName:  "project-owner",
+			},
+		}, nil)
+		_, _, err := manager.Seal(context.Background(), newAccount(""), admin)
+		var invalidInput *InvalidInputError
+		require.ErrorAs(t, err, &invalidInput)
+		assert.ErrorContains(t, err, "projectroletemplatebinding p-abcde/prtb-ops")
+	})
+
+	t.Run("user of another provider", func(t *testing.T) {
+		t.Parallel()
+		manager, m := newManager(t)
+		user := newUser(t, "previous", true)
+		user.PrincipalIDs = []string{"openldap_user://uid=ops"}
+		m.users.EXPECT().Get("u-ops", gomock.Any()).Return(user, nil)
+		_, _, err := manager.Seal(context.Background(), newAccount(""), admin)
+		assert.Error(t, err)
+	})
+}
+
+func TestActivate(t *testing.T) {
+	t.Parallel()
+	manager, m := newManager(t)
+	account := newAccount(StateSealed)
+	user := newUser(t, "s3cr3t", false)
+	m.accountCache.EXPECT().List(labels.Everything()).Return([]*v3.BreakGlassAccount{account}, nil)
+	m.userCache.EXPECT().Get("u-ops").Return(user, nil)
+	m.users.EXPECT().Get("u-ops", gomock.Any()).Return(user, nil)
+	m.accounts.EXPECT().Get("bg-ops", gomock.Any()).Return(account, nil)
+	var binding *v3.GlobalRoleBinding
+	m.grbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(grb *v3.GlobalRoleBinding) (*v3.GlobalRoleBinding, error) {
+		binding = grb
+		return grb, nil
+	})
+	var updated *v3.User
+	m.users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+		updated = user
+		return user, nil
+	})
+
+	activated, err := manager.Activate("breakglass-ops", "s3cr3t", "IdP outage INC-1234", "30m", net.ParseIP("10.0.0.1"))
+	require.NoError(t, err)
+
+	assert.Equal(t, StateActive, activated.Status.State)
+	assert.Equal(t, metav1.NewTime(now.Add(30*time.Minute)), *activated.Status.ExpiresAt)
+	assert.Equal(t, BindingName(account), activated.Status.BindingName)
+	require.Len(t, activated.Status.Activations, 1)
+	assert.Equal(t, v3.BreakGlassActivation{
+		Reason:        "IdP outage INC-1234",
+		SourceAddress: "10.0.0.1",
+		ActivatedAt:   metav1.NewTime(now),
+		ExpiresAt:     metav1.NewTime(now.Add(30 * time.Minute)),
+	}, activated.Status.Activations[0])
+
+	require.NotNil(t, binding)
+	assert.Equal(t, BindingName(account), binding.Name)
+	assert.Equal(t, "admin", binding.GlobalRoleName)
+	assert.Equal(t, "u-ops", binding.UserName)
+	assert.Equal(t, "bg-ops", binding.Labels[AccountLabel])
+	require.NotNil(t, updated)
+	assert.True(t, *updated.Enabled)
+}
+
+func TestActivateRejected(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		state      string
+		username   string
+		credential string
+		reason     string
+		duration   string
+		wantErr    error
+	}{
+		{
+			name:       "wrong credential",
+			state:      StateSealed,
+			username:   "breakglass-ops",
+			credential: "guess",
+			reason:     "IdP outage",
+			wantErr:    ErrInvalidCredential,
+		},
+		{
+			name:       "unknown username",
+			state:      StateSealed,
+			username:   "root",
+			credential: "s3cr3t",
+			reason:     "IdP outage",
+			wantErr:    ErrInvalidCredential,
+		},
+		{
+			name:       "ended account",
+			state:      StateEnded,
+			username:   "breakglass-ops",
+			credential: "s3cr3t",
+			reason:     "IdP outage",
+			wantErr:    ErrInvalidCredential,
+		},
+		{
+			name:       "missing reason",
+			state:      StateSealed,
+			username:   "breakglass-ops",
+			credential: "s3cr3t",
+			reason:     " ",
+		},
+		{
+			name:       "duration above the maximum",
+			state:      StateSealed,
+			username:   "breakglass-ops",
+			credential: "s3cr3t",
+			reason:     "IdP outage",
+			duration:   "2h",
+		},
+	}
+	user := newUser(t, "s3cr3t", false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			manager, m := newManager(t)
+			account := newAccount(tt.state)
+			m.accountCache.EXPECT().List(labels.Everything()).Return([]*v3.BreakGlassAccount{account}, nil).AnyTimes()
+			m.userCache.EXPECT().Get("u-ops").Return(user, nil).AnyTimes()
+			m.users.EXPECT().Get("u-ops", gomock.Any()).Return(user, nil).AnyTimes()
+			m.accounts.EXPECT().Get("bg-ops", gomock.Any()).Return(account, nil).AnyTimes()
+
+			_, err := manager.Activate(tt.username, tt.credential, tt.reason, tt.duration, nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			var invalidInput *InvalidInputError
+			assert.ErrorAs(t, err, &invalidInput)
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	t.Parallel()
+	manager, m := newManager(t)
+	account := newAccount(StateActive)
+	expiresAt := metav1.NewTime(now.Add(-time.Minute))
+	account.Status.ExpiresAt = &expiresAt
+	account.Status.BindingName = BindingName(account)
+	account.Status.Activations = []v3.BreakGlassActivation{{
+		Reason:      "IdP outage",
+		ActivatedAt: metav1.NewTime(now.Add(-time.Hour)),
+		ExpiresAt:   expiresAt,
+	}}
+	user := newUser(t, "s3cr3t", true)
+	m.grbs.EXPECT().Delete(BindingName(account), gomock.Any()).Return(nil)
+	m.users.EXPECT().Get("u-ops", gomock.Any()).Return(user, nil)
+	var updated *v3.User
+	m.users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+		updated = user
+		return user, nil
+	})
+
+	ended, err := manager.End(account, "u-admin")
+	require.NoError(t, err)
+
+	assert.Equal(t, StateEnded, ended.Status.State)
+	assert.Nil(t, ended.Status.ExpiresAt)
+	assert.Empty(t, ended.Status.BindingName)
+	assert.Equal(t, metav1.NewTime(now), *ended.Status.Activations[0].EndedAt)
+	assert.Equal(t, "u-admin", ended.Status.Activations[0].EndedBy)
+	require.NotNil(t, updated)
+	assert.False(t, *updated.Enabled)
+	assert.ErrorIs(t, passwordhash.Verify(updated.Password, "s3cr3t"), passwordhash.ErrMismatch, "the credential is burnt")
+	assert.Equal(t, []string{"u-ops"}, m.revoker.revoked)
+
+	_, err = manager.End(ended, "u-admin")
+	assert.ErrorIs(t, err, ErrNotActive)
+}
+
+func TestExpire(t *testing.T) {
+	t.Parallel()
+	manager, m := newManager(t)
+	expired := newAccount(StateActive)
+	expired.Status.ExpiresAt = &metav1.Time{Time: now.Add(-time.Second)}
+	expired.Status.BindingName = BindingName(expired)
+	sealed := newAccount(StateSealed)
+	sealed.Name = "bg-dba"
+	sealed.Spec.UserName = "u-dba"
+	active := newAccount(StateActive)
+	active.Name = "bg-net"
+	active.Spec.UserName = "u-net"
+	active.Status.ExpiresAt = &metav1.Time{Time: now.Add(time.Minute)}
+	active.Status.BindingName = BindingName(active)
+
+	m.accountCache.EXPECT().List(labels.Everything()).Return([]*v3.BreakGlassAccount{expired, sealed, active}, nil)
+	for _, account := range []*v3.BreakGlassAccount{expired, sealed, active} {
+		m.accounts.EXPECT().Get(account.Name, gomock.Any()).Return(account, nil)
+	}
+	expiredUser := newUser(t, "s3cr3t", true)
+	sealedUser := expiredUser.DeepCopy()
+	sealedUser.Name = "u-dba"
+	m.users.EXPECT().Get("u-ops", gomock.Any()).Return(expiredUser, nil).Times(2)
+	m.users.EXPECT().Get("u-dba", gomock.Any()).Return(sealedUser, nil)
+	m.users.EXPECT().Get("u-net", gomock.Any()).Return(nil, apierrors.NewNotFound(schema.GroupResource{Resource: "users"}, "u-net"))
+	m.grbs.EXPECT().Delete(BindingName(expired), gomock.Any()).Return(nil)
+	disabled := map[string]bool{}
+	m.users.EXPECT().Update(gomock.Any()).Times(2).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+		disabled[user.Name] = !*user.Enabled
+		return user, nil
+	})
+
+	manager.expire()
+
+	assert.Equal(t, map[string]bool{"u-ops": true, "u-dba": true}, disabled)
+}
@@ -0,0 +1,72 @@
+package breakglass
+
+import (
+	"context"
+	"time"
+
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+)
+
+// expiryCheckInterval bounds how long an activation can outlive its expiry.
+const expiryCheckInterval = 30 * time.Second
+
+// StartExpiry ends the activations of the break glass accounts as they expire, until the context is done. The role of
+// the active accounts is granted again if it was revoked by other means, and the users of the inactive accounts are
+// kept disabled.
+func StartExpiry(ctx context.Context, mgmt *config.ScaledContext) {
+	m := NewManager(ctx, mgmt)
+	go wait.UntilWithContext(ctx, func(context.Context) { m.expire() }, expiryCheckInterval)
+}
+
+func (m *Manager) expire() {
+	accounts, err := m.accountCache.List(labels.Everything())
+	if err != nil {
+		logrus.Errorf("[breakglass] failed to list break glass accounts: %v", err)
+		return
+	}
+	now := m.now()
+	for _, cached := range accounts {
+		// The account is read from the API server, so that an activation isn't undone from a stale cache.
+		account, err := m.accounts.Get(cached.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			logrus.Errorf("[breakglass] failed to get break glass account %s: %v", cached.Name, err)
+			continue
+		}
+		user, err := m.users.Get(account.Spec.UserName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			user = nil
+		} else if err != nil {
+			logrus.Errorf("[breakglass] failed to get user %s: %v", account.Spec.UserName, err)
+			continue
+		}
+
+		switch {
+		case State(account) != StateActive:
+			if user == nil || !pointer.BoolDeref(user.Enabled, true) {
+				continue
+			}
+			if err := m.disable(user, false); err != nil {
+				logrus.Errorf("[breakglass] failed to disable the user of break glass account %s: %v", account.Name, err)
+				continue
+			}
+			audit("BreakGlassUserDisabled", account, "", nil)
+		case account.Status.ExpiresAt == nil || !now.Before(account.Status.ExpiresAt.Time):
+			if _, err := m.End(account, ""); err != nil {
+				logrus.Errorf("[breakglass] failed to end the expired activation of break glass account %s: %v", account.Name, err)
+			}
+		case user != nil:
+			if err := m.grant(account, user); err != nil {
+				logrus.Errorf("[breakglass] %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package breakglass
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// BasePath is the path of the unauthenticated endpoint activating the break glass accounts. The endpoints sealing
+	// and ending them are under it, and require authentication.
+	BasePath = "/v1-break-glass"
+
+	activateAction = "activate"
+	// SealAction seals a new credential for a break glass account, and EndAction ends its activation.
+	SealAction = "seal"
+	EndAction  = "end"
+
+	maxBodySize = 64 * 1024
+)
+
+type activateInput struct {
+	Username   string `json:"username"`
+	Credential string `json:"credential"`
+	Reason     string `json:"reason"`
+	Duration   string `json:"duration,omitempty"`
+}
+
+type activateOutput struct {
+	Account   string       `json:"account"`
+	Role      string       `json:"globalRoleName"`
+	ExpiresAt *metav1.Time `json:"expiresAt"`
+}
+
+type sealOutput struct {
+	Credential string                `json:"credential"`
+	Account    *v3.BreakGlassAccount `json:"account"`
+}
+
+type handler struct {
+	manager              *Manager
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewActivationHandler returns the unauthenticated handler activating the break glass accounts, as the identity
+// provider may be down. The credentials are random and long enough not to be guessed, the failed activations are
+// logged as audit events.
+func NewActivationHandler(ctx context.Context, mgmt *config.ScaledContext) http.Handler {
+	h := &handler{manager: NewManager(ctx, mgmt)}
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(BasePath).Queries("action", activateAction).HandlerFunc(h.activate)
+	return root
+}
+
+// NewHandler returns the handler sealing and ending the break glass accounts.
+func NewHandler(ctx context.Context, mgmt *config.ScaledContext) http.Handler {
+	h := &handler{
+		manager:              NewManager(ctx, mgmt),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+	}
+	return h.router()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(BasePath+"/{name}").Queries("action", SealAction).HandlerFunc(h.seal)
+	root.Methods(http.MethodPost).Path(BasePath+"/{name}").Queries("action", EndAction).HandlerFunc(h.end)
+	return root
+}
+
+// activate activates the break glass account of the username with its credential.
+func (h *handler) activate(w http.ResponseWriter, r *http.Request) {
+	var input activateInput
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&input); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if input.Username == "" || input.Credential == "" {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "username and credential are required")
+		return
+	}
+
+	account, err := h.manager.Activate(input.Username, input.Credential, input.Reason, input.Duration, requests.ClientIP(r))
+	var invalidInput *InvalidInputError
+	switch {
+	case err == nil:
+		util.WriteJSON(w, http.StatusOK, activateOutput{
+			Account:   account.Name,
+			Role:      account.Spec.GlobalRoleName,
+			ExpiresAt: account.Status.ExpiresAt,
+		})
+	case errors.Is(err, ErrInvalidCredential):
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, err.Error())
+	case errors.As(err, &invalidInput):
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case apierrors.IsConflict(err):
+		util.ReturnHTTPError(w, r, http.StatusConflict, "break glass account was modified, try again")
+	default:
+		logrus.Errorf("[breakglass] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to activate the break glass account")
+	}
+}
+
+// seal seals a new credential for the break glass account, and writes it. It's only shown once.
+func (h *handler) seal(w http.ResponseWriter, r *http.Request) {
+	caller, account, ok := h.authorizedAccount(w, r)
+	if !ok {
+		return
+	}
+	credential, sealed, err := h.manager.Seal(r.Context(), account, caller)
+	var invalidInput *InvalidInputError
+	switch {
+	case err == nil:
+		util.WriteJSON(w, http.StatusOK, sealOutput{Credential: credential, Account: sealed})
+	case errors.Is(err, ErrActive):
+		util.ReturnHTTPError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrCannotBind):
+		util.ReturnHTTPError(w, r, http.StatusForbidden, err.Error())
+	case errors.As(err, &invalidInput):
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+	case apierrors.IsConflict(err):
+		util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("break glass account %s was modified, try again", account.Name))
+	default:
+		logrus.Errorf("[breakglass] failed to seal break glass account %s: %v", account.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to seal the break glass account")
+	}
+}
+
+// end ends the activation of the break glass account before it expires.
+func (h *handler) end(w http.ResponseWriter, r *http.Request) {
+	caller, account, ok := h.authorizedAccount(w, r)
+	if !ok {
+		return
+	}
+	ended, err := h.manager.End(account, caller.GetName())
+	switch {
+	case err == nil:
+		util.WriteJSON(w, http.StatusOK, ended)
+	case errors.Is(err, ErrNotActive):
+		util.ReturnHTTPError(w, r, http.StatusConflict, err.Error())
+	case apierrors.IsConflict(err):
+		util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("break glass account %s was modified, try again", account.Name))
+	default:
+		logrus.Errorf("[breakglass] failed to end break glass account %s: %v", account.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to end the break glass account")
+	}
+}
+
+// authorizedAccount returns the caller and the break glass account of the path, if the caller is allowed to update it.
+// The account is read from the API server rather than the cache, so that it's changed from its latest state.
+func (h *handler) authorizedAccount(w http.ResponseWriter, r *http.Request) (user.Info, *v3.BreakGlassAccount, bool) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return nil, nil, false
+	}
+	name := mux.Vars(r)["name"]
+	allowed, err := h.authorize(r.Context(), caller, name)
+	if err != nil {
+		logrus.Errorf("[breakglass] failed to authorize user %s: %v", caller.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return nil, nil, false
+	}
+	if !allowed {
+		util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("break glass account %s not found", name))
+		return nil, nil, false
+	}
+
+	account, err := h.manager.accounts.Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("break glass account %s not found", name))
+		return nil, nil, false
+	}
+	if err != nil {
+		logrus.Errorf("[breakglass] failed to get break glass account %s: %v", name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get the break glass account")
+		return nil, nil, false
+	}
+	return caller, account, true
+}
+
+// authorize tells whether the user can update the break glass account.
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, name string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    v3.SchemeGroupVersion.Group,
+		Resource: v3.BreakGlassAccountResourceName,
+		Name:     name,
+		Verb:     "update",
+	})
+}
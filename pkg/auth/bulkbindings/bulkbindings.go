@@ -0,0 +1,419 @@
+// Package bulkbindings creates and deletes many ClusterRoleTemplateBindings and ProjectRoleTemplateBindings in a single
+// request, so that a team is onboarded or offboarded without a request per member. The items of a batch are all
+// validated before any of them is applied, and the items already applied are rolled back when one fails, so that a
+// batch is applied entirely or not at all.
+package bulkbindings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/bindingpolicy"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	"github.com/rancher/rancher/pkg/ref"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+const (
+	KindClusterRoleTemplateBinding = "ClusterRoleTemplateBinding"
+	KindProjectRoleTemplateBinding = "ProjectRoleTemplateBinding"
+
+	OperationCreate = "create"
+	OperationDelete = "delete"
+
+	// StatusValid is the status of the items of a valid batch on a dry run.
+	StatusValid = "valid"
+	// StatusCreated and StatusDeleted are the statuses of the items applied.
+	StatusCreated = "created"
+	StatusDeleted = "deleted"
+	// StatusFailed is the status of the items which are invalid, or failed to be applied.
+	StatusFailed = "failed"
+	// StatusRolledBack is the status of the items rolled back after another item failed to be applied.
+	StatusRolledBack = "rolledBack"
+	// StatusNotApplied is the status of the items which weren't applied because another item failed.
+	StatusNotApplied = "notApplied"
+
+	// maxItems bounds the items of a batch.
+	maxItems = 500
+)
+
+// Batch holds the bindings to create and delete, which are applied in order.
+type Batch struct {
+	Items []Item `json:"items"`
+}
+
+// Item is a binding to create or delete.
+type Item struct {
+	// Operation is create or delete.
+	Operation string `json:"operation"`
+	// Kind is ClusterRoleTemplateBinding or ProjectRoleTemplateBinding.
+	Kind string `json:"kind"`
+	// Name and Namespace are those of the binding to delete.
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// ClusterName is the cluster of the ClusterRoleTemplateBinding to create.
+	ClusterName string `json:"clusterName,omitempty"`
+	// ProjectName is the project of the ProjectRoleTemplateBinding to create, as <cluster>:<project>.
+	ProjectName        string `json:"projectName,omitempty"`
+	RoleTemplateName   string `json:"roleTemplateName,omitempty"`
+	UserName           string `json:"userName,omitempty"`
+	UserPrincipalName  string `json:"userPrincipalName,omitempty"`
+	GroupName          string `json:"groupName,omitempty"`
+	GroupPrincipalName string `json:"groupPrincipalName,omitempty"`
+	// Source is the source recorded on the binding to create, manual by default, or terraform or scim for the
+	// automation creating bindings through the endpoint.
+	Source string `json:"source,omitempty"`
+}
+
+// Result is the outcome of a batch.
+type Result struct {
+	DryRun bool `json:"dryRun"`
+	// Applied is true if all the items were applied.
+	Applied bool         `json:"applied"`
+	Items   []ItemResult `json:"items"`
+}
+
+// ItemResult is the outcome of an item of a batch. The name of a created binding is generated.
+type ItemResult struct {
+	Index     int    `json:"index"`
+	Operation string `json:"operation"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	// forbidden is true if the caller isn't allowed to apply the item.
+	forbidden bool
+}
+
+// step is a validated item, with the binding to create or the binding to delete.
+type step struct {
+	item Item
+	crtb *v3.ClusterRoleTemplateBinding
+	prtb *v3.ProjectRoleTemplateBinding
+}
+
+func (s *step) namespace() string {
+	if s.crtb != nil {
+		return s.crtb.Namespace
+	}
+	return s.prtb.Namespace
+}
+
+func (s *step) name() string {
+	if s.crtb != nil {
+		return s.crtb.Name
+	}
+	return s.prtb.Name
+}
+
+// errForbidden is wrapped by the errors of the items the caller isn't allowed to apply.
+var errForbidden = errors.New("forbidden")
+
+// validateBatch validates all the items of the batch, and returns their steps. It returns the results of the items
+// and false if one of them is invalid.
+func (h *handler) validateBatch(ctx context.Context, caller user.Info, batch *Batch) ([]*step, []ItemResult, bool) {
+	steps := make([]*step, len(batch.Items))
+	results := make([]ItemResult, len(batch.Items))
+	valid := true
+	deleted := map[string]bool{}
+	for i, item := range batch.Items {
+		results[i] = ItemResult{Index: i, Operation: item.Operation, Kind: item.Kind, Name: item.Name, Namespace: item.Namespace, Status: StatusValid}
+		s, err := h.validate(ctx, caller, item)
+		if err == nil && item.Operation == OperationDelete {
+			key := item.Kind + "/" + item.Namespace + "/" + item.Name
+			if deleted[key] {
+				err = fmt.Errorf("%s %s/%s is deleted twice", item.Kind, item.Namespace, item.Name)
+			}
+			deleted[key] = true
+		}
+		if err != nil {
+			var denied *bindingpolicy.DeniedError
+			results[i].Status = StatusFailed
+			results[i].Error = err.Error()
+			results[i].forbidden = errors.Is(err, errForbidden) || errors.As(err, &denied)
+			valid = false
+			continue
+		}
+		results[i].Namespace = s.namespace()
+		steps[i] = s
+	}
+	return steps, results, valid
+}
+
+// validate validates the item, and checks that the caller is allowed to apply it. The caller must be allowed to create
+// or delete the bindings of the kind in their namespace, and to create a binding, they must have its role template
+// there or be allowed to bind it anywhere, so that they can't grant more permissions than their own.
+func (h *handler) validate(ctx context.Context, caller user.Info, item Item) (*step, error) {
+	var resource string
+	switch item.Kind {
+	case KindClusterRoleTemplateBinding:
+		resource = v3.ClusterRoleTemplateBindingResourceName
+	case KindProjectRoleTemplateBinding:
+		resource = v3.ProjectRoleTemplateBindingResourceName
+	default:
+		return nil, fmt.Errorf("kind must be %s or %s", KindClusterRoleTemplateBinding, KindProjectRoleTemplateBinding)
+	}
+
+	switch item.Operation {
+	case OperationCreate:
+		s, err := h.validateCreate(item, caller.GetName())
+		if err != nil {
+			return nil, err
+		}
+		if err := h.authorizeOrFail(ctx, caller, "create", s.namespace(), resource, ""); err != nil {
+			return nil, err
+		}
+		if err := h.checkRole(ctx, caller, item.Kind, s.namespace(), item.RoleTemplateName); err != nil {
+			return nil, err
+		}
+		if err := bindingpolicy.Evaluate(ctx, caller.GetName(), policyBinding(s)); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case OperationDelete:
+		if item.Name == "" || item.Namespace == "" {
+			return nil, fmt.Errorf("the name and namespace of the binding to delete are required")
+		}
+		if err := h.authorizeOrFail(ctx, caller, "delete", item.Namespace, resource, item.Name); err != nil {
+			return nil, err
+		}
+		s := &step{item: item}
+		var err error
+		if item.Kind == KindClusterRoleTemplateBinding {
+			s.crtb, err = h.crtbCache.Get(item.Namespace, item.Name)
+		} else {
+			s.prtb, err = h.prtbCache.Get(item.Namespace, item.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("operation must be %s or %s", OperationCreate, OperationDelete)
+}
+
+// validateCreate validates the binding to create, and returns its step. The binding records the creator as its
+// provenance.
+func (h *handler) validateCreate(item Item, creator string) (*step, error) {
+	source, err := provenance.APISource(item.Source)
+	if err != nil {
+		return nil, err
+	}
+	hasUser := item.UserName != "" || item.UserPrincipalName != ""
+	hasGroup := item.GroupName != "" || item.GroupPrincipalName != ""
+	if hasUser == hasGroup {
+		return nil, fmt.Errorf("must target a user [userName]/[userPrincipalName] OR a group [groupName]/[groupPrincipalName]")
+	}
+
+	roleTemplate, err := h.roleTemplateCache.Get(item.RoleTemplateName)
+	if err != nil {
+		return nil, fmt.Errorf("getting role template %q: %w", item.RoleTemplateName, err)
+	}
+	if roleTemplate.Locked {
+		return nil, fmt.Errorf("role template %s is locked and cannot be assigned", roleTemplate.Name)
+	}
+
+	s := &step{item: item}
+	switch item.Kind {
+	case KindClusterRoleTemplateBinding:
+		if item.ClusterName == "" {
+			return nil, fmt.Errorf("clusterName is required")
+		}
+		if roleTemplate.Context != "cluster" {
+			return nil, fmt.Errorf("role template %s is not a cluster role template", roleTemplate.Name)
+		}
+		s.crtb = &v3.ClusterRoleTemplateBinding{
+			ObjectMeta:         metav1.ObjectMeta{GenerateName: "crtb-", Namespace: item.ClusterName},
+			ClusterName:        item.ClusterName,
+			RoleTemplateName:   item.RoleTemplateName,
+			UserName:           item.UserName,
+			UserPrincipalName:  item.UserPrincipalName,
+			GroupName:          item.GroupName,
+			GroupPrincipalName: item.GroupPrincipalName,
+		}
+		provenance.Set(&s.crtb.ObjectMeta, source, creator)
+	case KindProjectRoleTemplateBinding:
+		clusterName, projectName := ref.Parse(item.ProjectName)
+		if clusterName == "" || projectName == "" {
+			return nil, fmt.Errorf("projectName must be <cluster>:<project>")
+		}
+		if roleTemplate.Context != "project" {
+			return nil, fmt.Errorf("role template %s is not a project role template", roleTemplate.Name)
+		}
+		s.prtb = &v3.ProjectRoleTemplateBinding{
+			ObjectMeta:         metav1.ObjectMeta{GenerateName: "prtb-", Namespace: projectName},
+			ProjectName:        item.ProjectName,
+			RoleTemplateName:   item.RoleTemplateName,
+			UserName:           item.UserName,
+			UserPrincipalName:  item.UserPrincipalName,
+			GroupName:          item.GroupName,
+			GroupPrincipalName: item.GroupPrincipalName,
+		}
+		provenance.Set(&s.prtb.ObjectMeta, source, creator)
+	}
+	return s, nil
+}
+
+// checkRole returns an error unless the caller has the role template in the namespace of the binding, or is allowed
+// to bind it.
+func (h *handler) checkRole(ctx context.Context, caller user.Info, kind, namespace, roleTemplateName string) error {
+	if kind == KindClusterRoleTemplateBinding {
+		crtbs, err := h.crtbCache.List(namespace, labels.Everything())
+		if err != nil {
+			return err
+		}
+		for _, crtb := range crtbs {
+			if crtb.UserName == caller.GetName() && crtb.RoleTemplateName == roleTemplateName {
+				return nil
+			}
+		}
+	} else {
+		prtbs, err := h.prtbCache.List(namespace, labels.Everything())
+		if err != nil {
+			return err
+		}
+		for _, prtb := range prtbs {
+			if prtb.UserName == caller.GetName() && prtb.RoleTemplateName == roleTemplateName {
+				return nil
+			}
+		}
+	}
+	allowed, err := h.authorize(ctx, caller, "bind", "", v3.RoleTemplateResourceName, roleTemplateName)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: not allowed to bind role template %s", errForbidden, roleTemplateName)
+	}
+	return nil
+}
+
+func (h *handler) authorizeOrFail(ctx context.Context, caller user.Info, verb, namespace, resource, name string) error {
+	allowed, err := h.authorize(ctx, caller, verb, namespace, resource, name)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: not allowed to %s %s in namespace %s", errForbidden, verb, resource, namespace)
+	}
+	return nil
+}
+
+func policyBinding(s *step) bindingpolicy.Binding {
+	item := s.item
+	binding := bindingpolicy.Binding{
+		Kind:               item.Kind,
+		ClusterName:        item.ClusterName,
+		RoleTemplateName:   item.RoleTemplateName,
+		UserName:           item.UserName,
+		UserPrincipalName:  item.UserPrincipalName,
+		GroupName:          item.GroupName,
+		GroupPrincipalName: item.GroupPrincipalName,
+	}
+	if item.Kind == KindProjectRoleTemplateBinding {
+		binding.ClusterName, binding.ProjectName = ref.Parse(item.ProjectName)
+	}
+	return binding
+}
+
+// apply applies the steps in order. When a step fails, the steps already applied are rolled back in reverse order,
+// and the next ones aren't applied. It returns true if all the steps were applied.
+func (h *handler) apply(steps []*step, results []ItemResult) bool {
+	for i, s := range steps {
+		var err error
+		switch s.item.Operation {
+		case OperationCreate:
+			err = h.create(s)
+			results[i].Status = StatusCreated
+		case OperationDelete:
+			err = h.delete(s)
+			results[i].Status = StatusDeleted
+		}
+		results[i].Name = s.name()
+		if err == nil {
+			continue
+		}
+
+		results[i].Status = StatusFailed
+		results[i].Error = err.Error()
+		for j := i + 1; j < len(steps); j++ {
+			results[j].Status = StatusNotApplied
+		}
+		for j := i - 1; j >= 0; j-- {
+			if err := h.rollback(steps[j]); err != nil {
+				results[j].Error = fmt.Sprintf("failed to roll back: %v", err)
+				continue
+			}
+			results[j].Status = StatusRolledBack
+		}
+		return false
+	}
+	return true
+}
+
+// create creates the binding of the step, and sets the generated name on it.
+func (h *handler) create(s *step) error {
+	if s.crtb != nil {
+		created, err := h.crtbs.Create(s.crtb)
+		if err != nil {
+			return err
+		}
+		s.crtb = created
+		return nil
+	}
+	created, err := h.prtbs.Create(s.prtb)
+	if err != nil {
+		return err
+	}
+	s.prtb = created
+	return nil
+}
+
+func (h *handler) delete(s *step) error {
+	if s.crtb != nil {
+		return h.crtbs.Delete(s.crtb.Namespace, s.crtb.Name, &metav1.DeleteOptions{})
+	}
+	return h.prtbs.Delete(s.prtb.Namespace, s.prtb.Name, &metav1.DeleteOptions{})
+}
+
+// rollback deletes the binding created by the step, or creates the binding it deleted again, with the same name.
+func (h *handler) rollback(s *step) error {
+	if s.item.Operation == OperationCreate {
+		if err := h.delete(s); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	var err error
+	if s.crtb != nil {
+		crtb := s.crtb.DeepCopy()
+		crtb.ObjectMeta = recreatedMeta(crtb.ObjectMeta)
+		_, err = h.crtbs.Create(crtb)
+	} else {
+		prtb := s.prtb.DeepCopy()
+		prtb.ObjectMeta = recreatedMeta(prtb.ObjectMeta)
+		_, err = h.prtbs.Create(prtb)
+	}
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// recreatedMeta returns the metadata of a deleted object to create it again.
+func recreatedMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            meta.Name,
+		Namespace:       meta.Namespace,
+		Labels:          meta.Labels,
+		Annotations:     meta.Annotations,
+		OwnerReferences: meta.OwnerReferences,
+	}
+}
@@ -0,0 +1,224 @@
+package bulkbindings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	// allowed are the verbs the users are allowed, as <user>/<verb>/<namespace>/<resource>.
+	allowed map[string]bool
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	attributes := sar.Spec.ResourceAttributes
+	sar.Status.Allowed = f.allowed[sar.Spec.User+"/"+attributes.Verb+"/"+attributes.Namespace+"/"+attributes.Resource]
+	return sar, nil
+}
+
+type mocks struct {
+	crtbs *fake.MockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList]
+	prtbs *fake.MockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList]
+}
+
+// setup returns a handler where u-owner is allowed to manage the bindings of cluster c-abc and project p-xyz, has the
+// project-member role in p-xyz and is allowed to bind cluster-member. u-member is only allowed to create bindings.
+func setup(t *testing.T) (*handler, mocks) {
+	ctrl := gomock.NewController(t)
+	roleTemplateCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	for _, rt := range []*v3.RoleTemplate{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-member"}, Context: "cluster"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "project-member"}, Context: "project"},
+	} {
+		roleTemplateCache.EXPECT().Get(rt.Name).Return(rt, nil).AnyTimes()
+	}
+
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("c-abc", labels.Everything()).Return(nil, nil).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("p-xyz", labels.Everything()).Return([]*v3.ProjectRoleTemplateBinding{
+		{UserName: "u-owner", RoleTemplateName: "project-member"},
+	}, nil).AnyTimes()
+	prtbCache.EXPECT().Get("p-xyz", "prtb-old").Return(&v3.ProjectRoleTemplateBinding{
+		ObjectMeta:       metav1.ObjectMeta{Name: "prtb-old", Namespace: "p-xyz", UID: "uid", ResourceVersion: "42"},
+		ProjectName:      "c-abc:p-xyz",
+		UserName:         "u-leaver",
+		RoleTemplateName: "project-member",
+	}, nil).AnyTimes()
+
+	m := mocks{
+		crtbs: fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl),
+		prtbs: fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl),
+	}
+	h := &handler{
+		roleTemplateCache: roleTemplateCache,
+		crtbCache:         crtbCache,
+		crtbs:             m.crtbs,
+		prtbCache:         prtbCache,
+		prtbs:             m.prtbs,
+		subjectAccessReviews: &fakeSubjectAccessReviews{allowed: map[string]bool{
+			"u-owner/create/c-abc/clusterroletemplatebindings":  true,
+			"u-owner/delete/c-abc/clusterroletemplatebindings":  true,
+			"u-owner/create/p-xyz/projectroletemplatebindings":  true,
+			"u-owner/delete/p-xyz/projectroletemplatebindings":  true,
+			"u-owner/bind//roletemplates":                       true,
+			"u-member/create/p-xyz/projectroletemplatebindings": true,
+		}},
+	}
+	return h, m
+}
+
+func onboarding() Batch {
+	return Batch{Items: []Item{
+		{Operation: OperationCreate, Kind: KindClusterRoleTemplateBinding, ClusterName: "c-abc", RoleTemplateName: "cluster-member", UserName: "u-alice"},
+		{Operation: OperationDelete, Kind: KindProjectRoleTemplateBinding, Namespace: "p-xyz", Name: "prtb-old"},
+		{Operation: OperationCreate, Kind: KindProjectRoleTemplateBinding, ProjectName: "c-abc:p-xyz", RoleTemplateName: "project-member", GroupPrincipalName: "openldap_group://cn=devs"},
+	}}
+}
+
+func post(t *testing.T, h *handler, userName, query string, batch Batch) (int, Result) {
+	body, err := json.Marshal(batch)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, BasePath+query, bytes.NewReader(body))
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userName}))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+
+	var result Result
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	return rec.Code, result
+}
+
+func TestApplyBatch(t *testing.T) {
+	h, m := setup(t)
+	m.crtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		assert.Equal(t, "crtb-", crtb.GenerateName)
+		assert.Equal(t, "c-abc", crtb.Namespace)
+		assert.Equal(t, "c-abc", crtb.ClusterName)
+		assert.Equal(t, "u-alice", crtb.UserName)
+		assert.Equal(t, provenance.SourceManual, crtb.Labels[provenance.SourceLabel])
+		assert.Equal(t, "u-owner", crtb.Annotations[provenance.CreatorAnnotation])
+		crtb = crtb.DeepCopy()
+		crtb.Name = "crtb-generated"
+		return crtb, nil
+	})
+	m.prtbs.EXPECT().Delete("p-xyz", "prtb-old", gomock.Any()).Return(nil)
+	m.prtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(prtb *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+		assert.Equal(t, "p-xyz", prtb.Namespace)
+		assert.Equal(t, "c-abc:p-xyz", prtb.ProjectName)
+		prtb = prtb.DeepCopy()
+		prtb.Name = "prtb-generated"
+		return prtb, nil
+	})
+
+	code, result := post(t, h, "u-owner", "", onboarding())
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, result.Applied)
+	assert.Equal(t, []ItemResult{
+		{Index: 0, Operation: OperationCreate, Kind: KindClusterRoleTemplateBinding, Name: "crtb-generated", Namespace: "c-abc", Status: StatusCreated},
+		{Index: 1, Operation: OperationDelete, Kind: KindProjectRoleTemplateBinding, Name: "prtb-old", Namespace: "p-xyz", Status: StatusDeleted},
+		{Index: 2, Operation: OperationCreate, Kind: KindProjectRoleTemplateBinding, Name: "prtb-generated", Namespace: "p-xyz", Status: StatusCreated},
+	}, result.Items)
+}
+
+func TestApplyBatchDryRun(t *testing.T) {
+	h, _ := setup(t)
+
+	// Nothing is created or deleted on a dry run.
+	code, result := post(t, h, "u-owner", "?dryRun=true", onboarding())
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, result.DryRun)
+	assert.False(t, result.Applied)
+	for _, item := range result.Items {
+		assert.Equal(t, StatusValid, item.Status)
+	}
+}
+
+func TestApplyBatchInvalid(t *testing.T) {
+	h, _ := setup(t)
+
+	// u-member doesn't have the project-member role and isn't allowed to bind it, so nothing is applied.
+	code, result := post(t, h, "u-member", "", Batch{Items: []Item{
+		{Operation: OperationCreate, Kind: KindProjectRoleTemplateBinding, ProjectName: "c-abc:p-xyz", RoleTemplateName: "project-member", UserName: "u-alice"},
+	}})
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.False(t, result.Applied)
+	assert.Equal(t, StatusFailed, result.Items[0].Status)
+	assert.Contains(t, result.Items[0].Error, "not allowed to bind role template project-member")
+
+	batch := onboarding()
+	batch.Items = append(batch.Items,
+		Item{Operation: OperationCreate, Kind: KindClusterRoleTemplateBinding, ClusterName: "c-abc", RoleTemplateName: "project-member", UserName: "u-alice"},
+		Item{Operation: OperationCreate, Kind: KindClusterRoleTemplateBinding, ClusterName: "c-abc", RoleTemplateName: "cluster-member", UserName: "u-alice", GroupName: "g-abcde"},
+		Item{Operation: OperationDelete, Kind: KindProjectRoleTemplateBinding, Namespace: "p-xyz", Name: "prtb-old"},
+		Item{Operation: "update", Kind: KindClusterRoleTemplateBinding},
+		Item{Operation: OperationCreate, Kind: KindClusterRoleTemplateBinding, ClusterName: "c-abc", RoleTemplateName: "cluster-member", UserName: "u-alice", Source: provenance.SourceGroupRule},
+	)
+	code, result = post(t, h, "u-owner", "", batch)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	var statuses []string
+	for _, item := range result.Items {
+		statuses = append(statuses, item.Status)
+	}
+	assert.Equal(t, []string{StatusValid, StatusValid, StatusValid, StatusFailed, StatusFailed, StatusFailed, StatusFailed, StatusFailed}, statuses)
+	assert.Equal(t, "role template project-member is not a cluster role template", result.Items[3].Error)
+	assert.Equal(t, "ProjectRoleTemplateBinding p-xyz/prtb-old is deleted twice", result.Items[5].Error)
+	assert.Contains(t, result.Items[7].Error, "must be one of manual, terraform, scim")
+}
+
+func TestApplyBatchRollback(t *testing.T) {
+	h, m := setup(t)
+	m.crtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		crtb = crtb.DeepCopy()
+		crtb.Name = "crtb-generated"
+		return crtb, nil
+	})
+	m.prtbs.EXPECT().Delete("p-xyz", "prtb-old", gomock.Any()).Return(nil)
+	gomock.InOrder(
+		m.prtbs.EXPECT().Create(gomock.Any()).Return(nil, errors.New("the webhook denied the request")),
+		// The deleted binding is created again with its name, and the created binding is deleted.
+		m.prtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(prtb *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+			assert.Equal(t, metav1.ObjectMeta{Name: "prtb-old", Namespace: "p-xyz"}, prtb.ObjectMeta)
+			assert.Equal(t, "u-leaver", prtb.UserName)
+			return prtb, nil
+		}),
+	)
+	m.crtbs.EXPECT().Delete("c-abc", "crtb-generated", gomock.Any()).Return(nil)
+
+	code, result := post(t, h, "u-owner", "", onboarding())
+	assert.Equal(t, http.StatusConflict, code)
+	assert.False(t, result.Applied)
+	assert.Equal(t, StatusRolledBack, result.Items[0].Status)
+	assert.Equal(t, StatusRolledBack, result.Items[1].Status)
+	assert.Equal(t, StatusFailed, result.Items[2].Status)
+	assert.Equal(t, "the webhook denied the request", result.Items[2].Error)
+}
+
+func TestApplyBatchSize(t *testing.T) {
+	h, _ := setup(t)
+
+	req := httptest.NewRequest(http.MethodPost, BasePath, bytes.NewReader([]byte(`{"items": []}`)))
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: "u-owner"}))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
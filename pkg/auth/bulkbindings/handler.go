@@ -0,0 +1,139 @@
+package bulkbindings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// BasePath is the path of the bulk bindings endpoint. A POST applies the batch of the body, only validating it when
+	// the dryRun query parameter is true.
+	BasePath = "/v1-bulk-bindings"
+
+	maxBodySize = 2 * 1024 * 1024
+)
+
+type handler struct {
+	roleTemplateCache    mgmtv3.RoleTemplateCache
+	crtbCache            mgmtv3.ClusterRoleTemplateBindingCache
+	crtbs                mgmtv3.ClusterRoleTemplateBindingClient
+	prtbCache            mgmtv3.ProjectRoleTemplateBindingCache
+	prtbs                mgmtv3.ProjectRoleTemplateBindingClient
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewHandler returns the handler of the bulk bindings endpoint.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	return newHandler(mgmt).router()
+}
+
+func newHandler(mgmt *config.ScaledContext) *handler {
+	return &handler{
+		roleTemplateCache:    mgmt.Wrangler.Mgmt.RoleTemplate().Cache(),
+		crtbCache:            mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		crtbs:                mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding(),
+		prtbCache:            mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		prtbs:                mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding(),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+	}
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(BasePath).HandlerFunc(h.applyBatch)
+	return root
+}
+
+// applyBatch validates the batch of the body and applies it, unless it's a dry run. It responds 200 if the batch was
+// applied or is valid, 403 if the caller isn't allowed to apply one of its items, 422 if one is invalid, and 409 if
+// one failed to be applied and the others were rolled back.
+func (h *handler) applyBatch(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid dryRun")
+			return
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "failed to read the batch")
+		return
+	}
+	if len(data) > maxBodySize {
+		util.ReturnHTTPError(w, r, http.StatusRequestEntityTooLarge, "batch too large")
+		return
+	}
+	var batch Batch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid batch: %v", err))
+		return
+	}
+	if len(batch.Items) == 0 || len(batch.Items) > maxItems {
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, fmt.Sprintf("a batch must have between 1 and %d items", maxItems))
+		return
+	}
+
+	steps, results, valid := h.validateBatch(r.Context(), caller, &batch)
+	result := Result{DryRun: dryRun, Items: results}
+	if !valid {
+		util.WriteJSON(w, invalidStatus(results), result)
+		return
+	}
+	if dryRun {
+		util.WriteJSON(w, http.StatusOK, result)
+		return
+	}
+
+	result.Applied = h.apply(steps, results)
+	if !result.Applied {
+		logrus.Warnf("[bulkbindings] a batch of %d items of user %s failed and was rolled back", len(steps), caller.GetName())
+		util.WriteJSON(w, http.StatusConflict, result)
+		return
+	}
+	logrus.Infof("[bulkbindings] user %s applied a batch of %d items", caller.GetName(), len(steps))
+	util.WriteJSON(w, http.StatusOK, result)
+}
+
+// invalidStatus returns 403 if one of the items is forbidden, and 422 otherwise.
+func invalidStatus(results []ItemResult) int {
+	for _, result := range results {
+		if result.Status == StatusFailed && result.forbidden {
+			return http.StatusForbidden
+		}
+	}
+	return http.StatusUnprocessableEntity
+}
+
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, verb, namespace, resource, name string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:     v3.SchemeGroupVersion.Group,
+		Namespace: namespace,
+		Resource:  resource,
+		Name:      name,
+		Verb:      verb,
+	})
+}
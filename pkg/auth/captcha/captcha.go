@@ -0,0 +1,187 @@
+// Package captcha verifies the challenges the clients must solve to log in with a username and password after
+// repeated failed logins, to slow down credential stuffing. The challenges are verified by the backend picked with
+// auth-login-captcha-provider: hCaptcha, reCAPTCHA and Cloudflare Turnstile are built in, other backends can be
+// registered.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	HCaptcha  = "hcaptcha"
+	ReCaptcha = "recaptcha"
+	Turnstile = "turnstile"
+
+	// SecretName is the name of the secret of the cattle-global-data namespace holding the secret key of the backend.
+	SecretName = "login-captcha"
+	// SecretKeyField is the field of the secret holding the secret key.
+	SecretKeyField = "secretKey"
+
+	verifyTimeout = 10 * time.Second
+	maxBodySize   = 64 * 1024
+)
+
+var (
+	// RequiredErrorCode is the code of the login errors telling the client to solve a challenge.
+	RequiredErrorCode = httperror.ErrorCode{Code: "CaptchaRequired", Status: http.StatusUnauthorized}
+
+	// ErrRequired is returned for the logins without a valid challenge response.
+	ErrRequired = httperror.NewAPIError(RequiredErrorCode, "a valid challenge response is required")
+
+	// ErrInvalidResponse is wrapped by the errors of the verifiers for the responses which aren't valid. Their other
+	// errors are failures to verify the responses.
+	ErrInvalidResponse = errors.New("invalid challenge response")
+)
+
+// Verifier verifies the response of a client to a challenge of a backend.
+type Verifier interface {
+	// Verify returns an error wrapping ErrInvalidResponse if the response isn't valid. The remote IP is empty when
+	// unknown.
+	Verify(ctx context.Context, secretKey, response, remoteIP string) error
+}
+
+var (
+	verifiersMu sync.RWMutex
+	verifiers   = map[string]Verifier{
+		HCaptcha:  &siteVerifier{url: "https://api.hcaptcha.com/siteverify", client: http.DefaultClient},
+		ReCaptcha: &siteVerifier{url: "https://www.google.com/recaptcha/api/siteverify", client: http.DefaultClient},
+		Turnstile: &siteVerifier{url: "https://challenges.cloudflare.com/turnstile/v0/siteverify", client: http.DefaultClient},
+	}
+)
+
+// Register adds a backend, which is used when auth-login-captcha-provider is set to its name.
+func Register(name string, verifier Verifier) {
+	verifiersMu.Lock()
+	defer verifiersMu.Unlock()
+	verifiers[name] = verifier
+}
+
+func verifier(name string) (Verifier, bool) {
+	verifiersMu.RLock()
+	defer verifiersMu.RUnlock()
+	v, ok := verifiers[name]
+	return v, ok
+}
+
+// Required tells whether a login with the auth provider needs a challenge, after the failures of its username or
+// client address.
+func Required(provider string, failures int) bool {
+	if settings.AuthLoginCaptchaProvider.Get() == "" || failures < settings.AuthLoginCaptchaAfterFailures.GetInt() {
+		return false
+	}
+	for _, name := range strings.Split(settings.AuthLoginCaptchaAuthProviders.Get(), ",") {
+		if strings.TrimSpace(name) == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// Checker verifies the challenge responses with the backend of auth-login-captcha-provider.
+type Checker struct {
+	secretLister v1.SecretLister
+}
+
+// NewChecker returns a Checker reading the secret key of the backend with the lister.
+func NewChecker(secretLister v1.SecretLister) *Checker {
+	return &Checker{secretLister: secretLister}
+}
+
+// Verify checks the response of the client to a challenge. It returns ErrRequired if the response is missing or
+// invalid. The source is the address of the client, nil if unknown.
+func (c *Checker) Verify(ctx context.Context, response string, source net.IP) error {
+	if response == "" {
+		return ErrRequired
+	}
+	backend := settings.AuthLoginCaptchaProvider.Get()
+	v, ok := verifier(backend)
+	if !ok {
+		return fmt.Errorf("unknown captcha provider %q", backend)
+	}
+	secret, err := c.secretLister.Get(common.SecretsNamespace, SecretName)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("the secret key of captcha provider %s is missing, it must be set in secret %s/%s", backend, common.SecretsNamespace, SecretName)
+	}
+	if err != nil {
+		return fmt.Errorf("getting the secret key of captcha provider %s: %w", backend, err)
+	}
+
+	secretKey := string(secret.Data[SecretKeyField])
+	if secretKey == "" {
+		return fmt.Errorf("the secret key of captcha provider %s is empty in secret %s/%s", backend, common.SecretsNamespace, SecretName)
+	}
+
+	var remoteIP string
+	if source != nil {
+		remoteIP = source.String()
+	}
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+	err = v.Verify(ctx, secretKey, response, remoteIP)
+	if errors.Is(err, ErrInvalidResponse) {
+		logrus.Debugf("[captcha] %v", err)
+		return ErrRequired
+	}
+	return err
+}
+
+// siteVerifier verifies the responses with the siteverify API shared by hCaptcha, reCAPTCHA and Turnstile.
+type siteVerifier struct {
+	url    string
+	client *http.Client
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func (s *siteVerifier) Verify(ctx context.Context, secretKey, response, remoteIP string) error {
+	form := url.Values{
+		"secret":   {secretKey},
+		"response": {response},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("verifying the challenge response: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("verifying the challenge response: unexpected status %d from %s", resp.StatusCode, s.url)
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxBodySize)).Decode(&result); err != nil {
+		return fmt.Errorf("verifying the challenge response: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrInvalidResponse, strings.Join(result.ErrorCodes, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	corefakes "github.com/rancher/rancher/pkg/generated/norman/core/v1/fakes"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRequired(t *testing.T) {
+	defer settings.AuthLoginCaptchaProvider.Set(settings.AuthLoginCaptchaProvider.Default)
+	defer settings.AuthLoginCaptchaAuthProviders.Set(settings.AuthLoginCaptchaAuthProviders.Default)
+
+	assert.False(t, Required("local", 10))
+
+	require.NoError(t, settings.AuthLoginCaptchaProvider.Set(Turnstile))
+	assert.False(t, Required("local", 2))
+	assert.True(t, Required("local", 3))
+	assert.False(t, Required("github", 3))
+
+	require.NoError(t, settings.AuthLoginCaptchaAuthProviders.Set("openldap, activedirectory"))
+	assert.False(t, Required("local", 3))
+	assert.True(t, Required("activedirectory", 3))
+}
+
+func TestSiteVerifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "s3cr3t", r.PostForm.Get("secret"))
+		assert.Equal(t, "10.0.0.1", r.PostForm.Get("remoteip"))
+		response := siteVerifyResponse{Success: r.PostForm.Get("response") == "solved"}
+		if !response.Success {
+			response.ErrorCodes = []string{"invalid-input-response"}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	v := &siteVerifier{url: server.URL, client: server.Client()}
+	assert.NoError(t, v.Verify(context.Background(), "s3cr3t", "solved", "10.0.0.1"))
+	err := v.Verify(context.Background(), "s3cr3t", "guessed", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+	assert.ErrorContains(t, err, "invalid-input-response")
+}
+
+type fakeVerifier struct {
+	secretKey string
+	remoteIP  string
+}
+
+func (f *fakeVerifier) Verify(_ context.Context, secretKey, response, remoteIP string) error {
+	f.secretKey, f.remoteIP = secretKey, remoteIP
+	switch response {
+	case "solved":
+		return nil
+	case "unavailable":
+		return errors.New("backend unavailable")
+	}
+	return ErrInvalidResponse
+}
+
+func TestCheckerVerify(t *testing.T) {
+	defer settings.AuthLoginCaptchaProvider.Set(settings.AuthLoginCaptchaProvider.Default)
+	verifier := &fakeVerifier{}
+	Register("test", verifier)
+	require.NoError(t, settings.AuthLoginCaptchaProvider.Set("test"))
+
+	secrets := map[string]*corev1.Secret{}
+	c := NewChecker(&corefakes.SecretListerMock{
+		GetFunc: func(namespace, name string) (*corev1.Secret, error) {
+			if secret, ok := secrets[namespace+"/"+name]; ok {
+				return secret, nil
+			}
+			return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+		},
+	})
+	source := net.ParseIP("10.0.0.1")
+
+	assert.ErrorIs(t, c.Verify(context.Background(), "", source), ErrRequired)
+	// The secret key must be configured.
+	err := c.Verify(context.Background(), "solved", source)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrRequired)
+
+	secrets[common.SecretsNamespace+"/"+SecretName] = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: common.SecretsNamespace},
+		Data:       map[string][]byte{SecretKeyField: []byte("s3cr3t")},
+	}
+	assert.NoError(t, c.Verify(context.Background(), "solved", source))
+	assert.Equal(t, "s3cr3t", verifier.secretKey)
+	assert.Equal(t, "10.0.0.1", verifier.remoteIP)
+	assert.ErrorIs(t, c.Verify(context.Background(), "guessed", nil), ErrRequired)
+	assert.Empty(t, verifier.remoteIP)
+	err = c.Verify(context.Background(), "unavailable", source)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrRequired)
+
+	require.NoError(t, settings.AuthLoginCaptchaProvider.Set("unknown"))
+	assert.Error(t, c.Verify(context.Background(), "solved", source))
+}
@@ -5,9 +5,13 @@
 
 	"github.com/rancher/rancher/pkg/auth/providers/activedirectory"
 	"github.com/rancher/rancher/pkg/auth/providers/azure"
+	"github.com/rancher/rancher/pkg/auth/providers/cas"
+	"github.com/rancher/rancher/pkg/auth/providers/clientcert"
+	"github.com/rancher/rancher/pkg/auth/providers/externalauth"
 	"github.com/rancher/rancher/pkg/auth/providers/genericoidc"
 	"github.com/rancher/rancher/pkg/auth/providers/github"
 	"github.com/rancher/rancher/pkg/auth/providers/googleoauth"
+	"github.com/rancher/rancher/pkg/auth/providers/kerberos"
 	"github.com/rancher/rancher/pkg/auth/providers/keycloakoidc"
 	"github.com/rancher/rancher/pkg/auth/providers/ldap"
 	localprovider "github.com/rancher/rancher/pkg/auth/providers/local"
@@ -79,6 +83,22 @@ func AuthConfigs(management *config.ManagementContext) error {
 		return err
 	}
 
+	if err := addAuthConfig(externalauth.Name, client.ExternalAuthConfigType, false, management); err != nil {
+		return err
+	}
+
+	if err := addAuthConfig(kerberos.Name, client.KerberosConfigType, false, management); err != nil {
+		return err
+	}
+
+	if err := addAuthConfig(clientcert.Name, client.ClientCertConfigType, false, management); err != nil {
+		return err
+	}
+
+	if err := addAuthConfig(cas.Name, client.CASConfigType, false, management); err != nil {
+		return err
+	}
+
 	return addAuthConfig(localprovider.Name, client.LocalConfigType, true, management)
 }
 
@@ -0,0 +1,234 @@
+package deprovisioning
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// MissingSinceAnnotation is set on users the identity provider no longer knows, to the time they were first found missing.
+	MissingSinceAnnotation = "auth.cattle.io/idp-missing-since"
+	// DeactivatedAnnotation is set on users deactivated by the deprovisioning process, so they can be reactivated
+	// if the identity provider knows them again.
+	DeactivatedAnnotation = "auth.cattle.io/idp-deactivated"
+)
+
+// Deprovisioner reconciles users against the identity providers they logged in with,
+// and applies the configured action to users the identity providers no longer know.
+// A user is only considered missing if every identity provider it has a principal of reports it missing;
+// users with principals of providers which can't look up users are left untouched.
+type Deprovisioner struct {
+	userCache        mgmtcontrollers.UserCache
+	users            mgmtcontrollers.UserClient
+	readSettings     func() (settings, error)
+	enabledProviders func() []string
+	getUserLookup    func(string) common.UserLookup
+}
+
+// New creates a new instance of Deprovisioner.
+func New(wContext *wrangler.Context) *Deprovisioner {
+	return &Deprovisioner{
+		userCache:        wContext.Mgmt.User().Cache(),
+		users:            wContext.Mgmt.User(),
+		readSettings:     readSettings,
+		enabledProviders: providers.EnabledProviders,
+		getUserLookup:    providers.GetUserLookup,
+	}
+}
+
+// Run the deprovisioning process.
+func (d *Deprovisioner) Run(ctx context.Context) error {
+	if ctx.Err() != nil {
+		logrus.Info("deprovisioning: context canceled, quitting")
+		return nil
+	}
+
+	startedAt := time.Now()
+
+	settings, err := d.readSettings()
+	if err != nil {
+		return fmt.Errorf("error reading settings: %w, deprovisioning is disabled", err)
+	}
+
+	lookups := map[string]common.UserLookup{}
+	for _, providerName := range d.enabledProviders() {
+		if lookup := d.getUserLookup(providerName); lookup != nil {
+			lookups[providerName] = lookup
+		}
+	}
+	if len(lookups) == 0 {
+		logrus.Info("deprovisioning: nothing to do, no enabled auth provider can look up users")
+		return nil
+	}
+
+	logrus.Infof("deprovisioning: started (idp-deprovisioning-action %s, idp-deprovisioning-grace-period %s)", settings.action, settings.gracePeriod)
+
+	users, err := d.userCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing users: %w", err)
+	}
+
+	var processed, missing, reactivated, deactivated, deleted, errCount int
+	now := time.Now()
+
+	defer func() {
+		logrus.Infof(
+			"deprovisioning: finished in %v seconds (processed %d, missing %d, reactivated %d, deactivated %d, deleted %d, errors %d)",
+			time.Since(startedAt).Seconds(),
+			processed, missing, reactivated, deactivated, deleted, errCount,
+		)
+	}()
+
+	for _, user := range users {
+		if ctx.Err() != nil {
+			logrus.Info("deprovisioning: context canceled, quitting")
+			break
+		}
+
+		if user.IsDefaultAdmin() || user.IsSystem() {
+			continue
+		}
+
+		isMissing, checked, err := userMissing(user, lookups)
+		if err != nil {
+			logrus.Errorf("deprovisioning: error looking up user %s: %v", user.Name, err)
+			errCount++
+			continue
+		}
+		if !checked {
+			continue
+		}
+
+		processed++
+
+		if !isMissing {
+			if _, ok := user.Annotations[MissingSinceAnnotation]; !ok {
+				continue
+			}
+			logrus.Infof("deprovisioning: user %s is known to the identity provider again", user.Name)
+			if err := d.update(user.Name, func(user *v3.User) {
+				delete(user.Annotations, MissingSinceAnnotation)
+				if _, ok := user.Annotations[DeactivatedAnnotation]; ok {
+					delete(user.Annotations, DeactivatedAnnotation)
+					user.Enabled = pointer.Bool(true)
+				}
+			}); err != nil {
+				logrus.Errorf("deprovisioning: error updating user %s: %v", user.Name, err)
+				errCount++
+				continue
+			}
+			if _, ok := user.Annotations[DeactivatedAnnotation]; ok {
+				reactivated++
+			}
+			continue
+		}
+
+		missing++
+
+		missingSince := now
+		if value, ok := user.Annotations[MissingSinceAnnotation]; ok {
+			if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+				missingSince = parsed
+			}
+		}
+		gracePeriodOver := !now.Before(missingSince.Add(settings.gracePeriod))
+
+		logrus.Warnf("deprovisioning: user %s is no longer known to the identity provider since %s", user.Name, missingSince.Format(time.RFC3339))
+
+		if settings.action == ActionDelete && gracePeriodOver {
+			logrus.Infof("deprovisioning: deleting user %s", user.Name)
+			err := d.users.Delete(user.Name, &metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsGone(err) {
+				logrus.Errorf("deprovisioning: error deleting user %s: %v", user.Name, err)
+				errCount++
+				continue
+			}
+			deleted++
+			continue
+		}
+
+		deactivate := settings.action != ActionWarn && gracePeriodOver && pointer.BoolDeref(user.Enabled, true)
+		if _, ok := user.Annotations[MissingSinceAnnotation]; ok && !deactivate {
+			continue
+		}
+		if deactivate {
+			logrus.Infof("deprovisioning: deactivating user %s", user.Name)
+		}
+		if err := d.update(user.Name, func(user *v3.User) {
+			if _, ok := user.Annotations[MissingSinceAnnotation]; !ok {
+				user.Annotations[MissingSinceAnnotation] = missingSince.Format(time.RFC3339)
+			}
+			if deactivate {
+				user.Annotations[DeactivatedAnnotation] = "true"
+				user.Enabled = pointer.Bool(false)
+			}
+		}); err != nil {
+			logrus.Errorf("deprovisioning: error updating user %s: %v", user.Name, err)
+			errCount++
+			continue
+		}
+		if deactivate {
+			deactivated++
+		}
+	}
+
+	return nil
+}
+
+// update applies the mutation to the latest version of the user.
+func (d *Deprovisioner) update(userName string, mutate func(*v3.User)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		user, err := d.users.Get(userName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) { // The user is no longer, move on.
+				return nil
+			}
+			return err
+		}
+		if user.Annotations == nil {
+			user.Annotations = map[string]string{}
+		}
+		mutate(user)
+		_, err = d.users.Update(user)
+		return err
+	})
+}
+
+// userMissing looks up the user principals with the providers they belong to.
+// checked is false if the user has no external principal, or has a principal of a provider which can't look up
+// users, in which case the user can't be deemed missing.
+func userMissing(user *v3.User, lookups map[string]common.UserLookup) (missing bool, checked bool, err error) {
+	for _, principalID := range user.PrincipalIDs {
+		providerName := providers.PrincipalProvider(principalID)
+		if !strings.HasPrefix(principalID, providerName+"_user://") {
+			continue // Local and system principals.
+		}
+		lookup, ok := lookups[providerName]
+		if !ok {
+			return false, false, nil
+		}
+		exists, err := lookup.UserExists(principalID)
+		if err != nil {
+			return false, false, fmt.Errorf("provider %s: %w", providerName, err)
+		}
+		if exists {
+			return false, true, nil
+		}
+		checked = true
+	}
+	return checked, checked, nil
+}
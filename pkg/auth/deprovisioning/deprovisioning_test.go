@@ -0,0 +1,188 @@
+package deprovisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
+)
+
+type fakeUserLookup map[string]bool
+
+func (f fakeUserLookup) UserExists(principalID string) (bool, error) {
+	return f[principalID], nil
+}
+
+func TestUserMissing(t *testing.T) {
+	lookups := map[string]common.UserLookup{
+		"openldap": fakeUserLookup{"openldap_user://uid=known": true},
+		"azuread":  fakeUserLookup{},
+	}
+
+	tests := []struct {
+		name         string
+		principalIDs []string
+		wantMissing  bool
+		wantChecked  bool
+	}{
+		{
+			name:         "local user",
+			principalIDs: []string{"local://u-abcde"},
+		},
+		{
+			name:         "known user",
+			principalIDs: []string{"openldap_user://uid=known", "local://u-abcde"},
+			wantChecked:  true,
+		},
+		{
+			name:         "missing user",
+			principalIDs: []string{"openldap_user://uid=gone", "local://u-abcde"},
+			wantMissing:  true,
+			wantChecked:  true,
+		},
+		{
+			name:         "missing from every provider",
+			principalIDs: []string{"openldap_user://uid=gone", "azuread_user://1234", "local://u-abcde"},
+			wantMissing:  true,
+			wantChecked:  true,
+		},
+		{
+			name:         "known to one of the providers",
+			principalIDs: []string{"azuread_user://1234", "openldap_user://uid=known", "local://u-abcde"},
+			wantChecked:  true,
+		},
+		{
+			name:         "provider can't look up users",
+			principalIDs: []string{"openldap_user://uid=gone", "github_user://1234", "local://u-abcde"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing, checked, err := userMissing(&v3.User{PrincipalIDs: tt.principalIDs}, lookups)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMissing, missing)
+			assert.Equal(t, tt.wantChecked, checked)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	now := time.Now()
+	longAgo := now.Add(-48 * time.Hour).Format(time.RFC3339)
+	lookup := fakeUserLookup{"openldap_user://uid=known": true}
+
+	tests := []struct {
+		name        string
+		action      string
+		user        *v3.User
+		wantUpdate  func(t *testing.T, user *v3.User)
+		wantDeleted bool
+	}{
+		{
+			name:   "warn marks the user missing",
+			action: ActionWarn,
+			user:   &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-gone"}, PrincipalIDs: []string{"openldap_user://uid=gone"}},
+			wantUpdate: func(t *testing.T, user *v3.User) {
+				assert.Contains(t, user.Annotations, MissingSinceAnnotation)
+				assert.Nil(t, user.Enabled)
+			},
+		},
+		{
+			name:   "deactivate waits for the grace period",
+			action: ActionDeactivate,
+			user:   &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-gone"}, PrincipalIDs: []string{"openldap_user://uid=gone"}},
+			wantUpdate: func(t *testing.T, user *v3.User) {
+				assert.Contains(t, user.Annotations, MissingSinceAnnotation)
+				assert.NotContains(t, user.Annotations, DeactivatedAnnotation)
+			},
+		},
+		{
+			name:   "deactivate after the grace period",
+			action: ActionDeactivate,
+			user: &v3.User{
+				ObjectMeta:   metav1.ObjectMeta{Name: "u-gone", Annotations: map[string]string{MissingSinceAnnotation: longAgo}},
+				PrincipalIDs: []string{"openldap_user://uid=gone"},
+			},
+			wantUpdate: func(t *testing.T, user *v3.User) {
+				assert.Equal(t, longAgo, user.Annotations[MissingSinceAnnotation])
+				assert.Equal(t, "true", user.Annotations[DeactivatedAnnotation])
+				assert.False(t, *user.Enabled)
+			},
+		},
+		{
+			name:   "delete after the grace period",
+			action: ActionDelete,
+			user: &v3.User{
+				ObjectMeta:   metav1.ObjectMeta{Name: "u-gone", Annotations: map[string]string{MissingSinceAnnotation: longAgo}},
+				PrincipalIDs: []string{"openldap_user://uid=gone"},
+			},
+			wantDeleted: true,
+		},
+		{
+			name:   "reactivate a user known again",
+			action: ActionDeactivate,
+			user: &v3.User{
+				ObjectMeta: metav1.ObjectMeta{Name: "u-known", Annotations: map[string]string{
+					MissingSinceAnnotation: longAgo,
+					DeactivatedAnnotation:  "true",
+				}},
+				Enabled:      pointer.Bool(false),
+				PrincipalIDs: []string{"openldap_user://uid=known"},
+			},
+			wantUpdate: func(t *testing.T, user *v3.User) {
+				assert.NotContains(t, user.Annotations, MissingSinceAnnotation)
+				assert.NotContains(t, user.Annotations, DeactivatedAnnotation)
+				assert.True(t, *user.Enabled)
+			},
+		},
+		{
+			name:   "known user is left untouched",
+			action: ActionDelete,
+			user:   &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-known"}, PrincipalIDs: []string{"openldap_user://uid=known"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+			users := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+
+			userCache.EXPECT().List(labels.Everything()).Return([]*v3.User{tt.user}, nil)
+			if tt.wantUpdate != nil {
+				users.EXPECT().Get(tt.user.Name, gomock.Any()).Return(tt.user.DeepCopy(), nil)
+				users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+					tt.wantUpdate(t, user)
+					return user, nil
+				})
+			}
+			if tt.wantDeleted {
+				users.EXPECT().Delete(tt.user.Name, gomock.Any()).Return(nil)
+			}
+
+			d := &Deprovisioner{
+				userCache: userCache,
+				users:     users,
+				readSettings: func() (settings, error) {
+					return settings{action: tt.action, gracePeriod: 24 * time.Hour}, nil
+				},
+				enabledProviders: func() []string { return []string{"openldap", "github"} },
+				getUserLookup: func(providerName string) common.UserLookup {
+					if providerName == "openldap" {
+						return lookup
+					}
+					return nil
+				},
+			}
+			require.NoError(t, d.Run(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package deprovisioning
+
+import (
+	"fmt"
+	"time"
+
+	appsettings "github.com/rancher/rancher/pkg/settings"
+)
+
+// Actions applied to users the identity provider no longer knows.
+const (
+	ActionWarn       = "warn"
+	ActionDeactivate = "deactivate"
+	ActionDelete     = "delete"
+)
+
+// settings control the deprovisioning process.
+type settings struct {
+	action      string
+	gracePeriod time.Duration
+}
+
+// readSettings reads and parses deprovisioning settings.
+func readSettings() (settings, error) {
+	var (
+		err    error
+		parsed settings
+	)
+
+	switch value := appsettings.IdPDeprovisioningAction.Get(); value {
+	case "", ActionWarn:
+		parsed.action = ActionWarn
+	case ActionDeactivate, ActionDelete:
+		parsed.action = value
+	default:
+		return settings{}, fmt.Errorf("%s: invalid action %q", appsettings.IdPDeprovisioningAction.Name, value)
+	}
+
+	if value := appsettings.IdPDeprovisioningGracePeriod.Get(); value != "" {
+		parsed.gracePeriod, err = time.ParseDuration(value)
+		if err != nil {
+			return settings{}, fmt.Errorf("%s: %w", appsettings.IdPDeprovisioningGracePeriod.Name, err)
+		}
+	}
+
+	return parsed, nil
+}
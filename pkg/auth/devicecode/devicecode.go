@@ -0,0 +1,227 @@
+// Package devicecode implements the OAuth 2.0 device authorization grant (RFC 8628) for Rancher tokens, so that
+// clients without a browser, like the rancher cli on a headless machine, can log in with a provider that needs one.
+// The client requests a device and a user code, the user approves the user code on the verification page from a
+// browser session of the provider and the client polls for its token with the device code in the meantime.
+package devicecode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/azure"
+	"github.com/rancher/rancher/pkg/auth/providers/genericoidc"
+	"github.com/rancher/rancher/pkg/auth/providers/github"
+	"github.com/rancher/rancher/pkg/auth/providers/keycloakoidc"
+	"github.com/rancher/rancher/pkg/auth/providers/oidc"
+	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/clusterrouter"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// GrantType is the grant_type of the token requests of the device authorization grant.
+	GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	authorizePath = "/v1-device/authorize"
+	tokenPath     = "/v1-device/token"
+	verifyPath    = "/v1-device/verify"
+
+	expiresIn = 10 * time.Minute
+	// interval is the minimum number of seconds clients wait between token requests.
+	interval = 5
+)
+
+// Error codes of the device authorization grant, see RFC 8628 section 3.5.
+const (
+	errAuthorizationPending = "authorization_pending"
+	errSlowDown             = "slow_down"
+	errAccessDenied         = "access_denied"
+	errExpiredToken         = "expired_token"
+	errInvalidGrant         = "invalid_grant"
+	errInvalidRequest       = "invalid_request"
+	errUnsupportedGrantType = "unsupported_grant_type"
+	errServerError          = "server_error"
+)
+
+// Providers are the auth providers users can approve device authorizations with.
+// Their logins go through the browser, which headless clients can't open.
+var Providers = map[string]bool{
+	github.Name:       true,
+	azure.Name:        true,
+	oidc.Name:         true,
+	keycloakoidc.Name: true,
+	genericoidc.Name:  true,
+}
+
+type tokenAuthenticator interface {
+	TokenFromRequest(req *http.Request) (accessor.TokenAccessor, error)
+}
+
+type tokenManager interface {
+	NewLoginToken(userID string, userPrincipal v3.Principal, groupPrincipals []v3.Principal, providerToken string, ttl int64, description string) (v3.Token, string, error)
+}
+
+type handler struct {
+	store              *store
+	auth               tokenAuthenticator
+	tokenMGR           tokenManager
+	isDisabledProvider func(providerName string) (bool, error)
+	serverURL          func() string
+	sessionTTL         func() string
+}
+
+// NewHandler returns the handler of the device authorization, token and verification endpoints.
+func NewHandler(ctx context.Context, mgmt *config.ScaledContext) http.Handler {
+	h := &handler{
+		store: &store{
+			secrets: mgmt.Wrangler.Core.Secret(),
+			now:     time.Now,
+		},
+		auth:               requests.NewAuthenticator(ctx, clusterrouter.GetClusterID, mgmt),
+		tokenMGR:           tokens.NewManager(ctx, mgmt),
+		isDisabledProvider: providers.IsDisabledProvider,
+		serverURL:          settings.ServerURL.Get,
+		sessionTTL:         settings.AuthUserSessionTTLMinutes.Get,
+	}
+
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(authorizePath).HandlerFunc(h.authorize)
+	root.Methods(http.MethodPost).Path(tokenPath).HandlerFunc(h.token)
+	root.Methods(http.MethodGet).Path(verifyPath).HandlerFunc(h.verifyPage)
+	root.Methods(http.MethodPost).Path(verifyPath).HandlerFunc(h.verify)
+	return root
+}
+
+// authorize issues a device and a user code for the provider named in the request.
+func (h *handler) authorize(w http.ResponseWriter, r *http.Request) {
+	provider := r.PostFormValue("provider")
+	if !Providers[provider] {
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidRequest, "provider must be one of github, azuread, oidc, keycloakoidc or genericoidc")
+		return
+	}
+	if disabled, err := h.isDisabledProvider(provider); err != nil || disabled {
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidRequest, "provider "+provider+" is not enabled")
+		return
+	}
+
+	deviceCode, userCode, err := h.store.create(provider, expiresIn)
+	if err != nil {
+		logrus.Errorf("[devicecode] failed to create device authorization: %v", err)
+		util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+
+	verificationURI := h.baseURL(r) + verifyPath
+	util.WriteOAuthJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":               deviceCode,
+		"user_code":                 userCode,
+		"verification_uri":          verificationURI,
+		"verification_uri_complete": verificationURI + "?" + url.Values{"user_code": {userCode}}.Encode(),
+		"expires_in":                int(expiresIn.Seconds()),
+		"interval":                  interval,
+	})
+}
+
+// token returns a Rancher token for an approved device code.
+// Until the device code is approved, denied or expired, clients get an authorization_pending error.
+func (h *handler) token(w http.ResponseWriter, r *http.Request) {
+	if r.PostFormValue("grant_type") != GrantType {
+		util.WriteOAuthError(w, http.StatusBadRequest, errUnsupportedGrantType, "")
+		return
+	}
+
+	auth, err := h.store.getByDeviceCode(r.PostFormValue("device_code"))
+	switch {
+	case errors.Is(err, errNotFound):
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "unknown device code")
+		return
+	case errors.Is(err, errExpired):
+		h.deleteAuthorization(auth)
+		util.WriteOAuthError(w, http.StatusBadRequest, errExpiredToken, "")
+		return
+	case err != nil:
+		logrus.Errorf("[devicecode] failed to get device authorization: %v", err)
+		util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+
+	switch auth.Status {
+	case statusPending:
+		now := h.store.now()
+		tooSoon := now.Sub(auth.LastPolledAt) < interval*time.Second
+		auth.LastPolledAt = now
+		if err := h.store.update(auth); err != nil && !apierrors.IsConflict(err) {
+			logrus.Errorf("[devicecode] failed to update device authorization: %v", err)
+		}
+		if tooSoon {
+			util.WriteOAuthError(w, http.StatusBadRequest, errSlowDown, "")
+			return
+		}
+		util.WriteOAuthError(w, http.StatusBadRequest, errAuthorizationPending, "")
+	case statusDenied:
+		h.deleteAuthorization(auth)
+		util.WriteOAuthError(w, http.StatusBadRequest, errAccessDenied, "")
+	case statusApproved:
+		// Deleting the authorization first makes sure its token is only handed out once.
+		if err := h.store.delete(auth); err != nil {
+			if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+				util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "device code already used")
+				return
+			}
+			logrus.Errorf("[devicecode] failed to delete device authorization: %v", err)
+			util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+			return
+		}
+
+		var ttl int64
+		if minutes, err := strconv.ParseInt(h.sessionTTL(), 10, 64); err == nil {
+			ttl = minutes * 60 * 1000
+		}
+		token, tokenKey, err := h.tokenMGR.NewLoginToken(auth.UserID, auth.UserPrincipal, nil, "", ttl, "Token via device authorization")
+		if err != nil {
+			logrus.Errorf("[devicecode] failed to create token for user %s: %v", auth.UserID, err)
+			util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+			return
+		}
+
+		response := map[string]interface{}{
+			"access_token": token.Name + ":" + tokenKey,
+			"token_type":   "Bearer",
+		}
+		if ttl > 0 {
+			response["expires_in"] = ttl / 1000
+		}
+		util.WriteOAuthJSON(w, http.StatusOK, response)
+	default:
+		util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+	}
+}
+
+func (h *handler) deleteAuthorization(auth *authorization) {
+	if err := h.store.delete(auth); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		logrus.Errorf("[devicecode] failed to delete device authorization: %v", err)
+	}
+}
+
+// baseURL returns the URL users reach Rancher at, which the verification page is served on.
+func (h *handler) baseURL(r *http.Request) string {
+	if serverURL := strings.TrimSuffix(h.serverURL(), "/"); serverURL != "" {
+		return serverURL
+	}
+	return "https://" + util.GetHost(r)
+}
@@ -0,0 +1,297 @@
+package devicecode
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeAuthenticator struct {
+	token accessor.TokenAccessor
+}
+
+func (f *fakeAuthenticator) TokenFromRequest(req *http.Request) (accessor.TokenAccessor, error) {
+	if f.token == nil {
+		return nil, requests.ErrMustAuthenticate
+	}
+	return f.token, nil
+}
+
+type fakeTokenManager struct {
+	userID        string
+	userPrincipal v3.Principal
+	calls         int
+}
+
+func (f *fakeTokenManager) NewLoginToken(userID string, userPrincipal v3.Principal, groupPrincipals []v3.Principal, providerToken string, ttl int64, description string) (v3.Token, string, error) {
+	f.calls++
+	f.userID = userID
+	f.userPrincipal = userPrincipal
+	return v3.Token{ObjectMeta: metav1.ObjectMeta{Name: "token-abcde"}, TTLMillis: ttl}, "secretkey", nil
+}
+
+type testEnv struct {
+	handler  *handler
+	auth     *fakeAuthenticator
+	tokenMGR *fakeTokenManager
+	now      time.Time
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	ctrl := gomock.NewController(t)
+	env := &testEnv{
+		auth:     &fakeAuthenticator{},
+		tokenMGR: &fakeTokenManager{},
+		now:      time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	env.handler = &handler{
+		store: &store{
+			secrets: tokens.NewFakeSecrets(ctrl),
+			now:     func() time.Time { return env.now },
+		},
+		auth:     env.auth,
+		tokenMGR: env.tokenMGR,
+		isDisabledProvider: func(providerName string) (bool, error) {
+			return providerName != "github", nil
+		},
+		serverURL:  func() string { return "https://rancher.example.com/" },
+		sessionTTL: func() string { return "960" },
+	}
+	return env
+}
+
+func (e *testEnv) post(handlerFunc http.HandlerFunc, form url.Values, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	handlerFunc(rec, req)
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func (e *testEnv) authorize(t *testing.T) (string, string) {
+	t.Helper()
+	rec := e.post(e.handler.authorize, url.Values{"provider": {"github"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := decode(t, rec)
+	assert.Equal(t, "https://rancher.example.com/v1-device/verify", body["verification_uri"])
+	assert.Equal(t, "https://rancher.example.com/v1-device/verify?user_code="+url.QueryEscape(body["user_code"].(string)), body["verification_uri_complete"])
+	assert.EqualValues(t, 600, body["expires_in"])
+	assert.EqualValues(t, interval, body["interval"])
+	return body["device_code"].(string), body["user_code"].(string)
+}
+
+func (e *testEnv) poll(t *testing.T, deviceCode string) (int, map[string]interface{}) {
+	t.Helper()
+	rec := e.post(e.handler.token, url.Values{"grant_type": {GrantType}, "device_code": {deviceCode}})
+	return rec.Code, decode(t, rec)
+}
+
+func (e *testEnv) submit(userCode, action string) *httptest.ResponseRecorder {
+	return e.post(e.handler.verify, url.Values{"user_code": {userCode}, "action": {action}, "csrf": {"csrf-value"}},
+		&http.Cookie{Name: tokens.CSRFCookie, Value: "csrf-value"})
+}
+
+func TestAuthorize(t *testing.T) {
+	env := newTestEnv(t)
+
+	deviceCode, userCode := env.authorize(t)
+	assert.Regexp(t, `^[a-z0-9]{10}:[A-Za-z0-9_-]{43}$`, deviceCode)
+	assert.Regexp(t, `^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`, userCode)
+
+	rec := env.post(env.handler.authorize, url.Values{"provider": {"local"}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, errInvalidRequest, decode(t, rec)["error"])
+
+	rec = env.post(env.handler.authorize, url.Values{"provider": {"azuread"}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, decode(t, rec)["error_description"], "not enabled")
+}
+
+func TestApprovedDeviceGetsToken(t *testing.T) {
+	env := newTestEnv(t)
+	deviceCode, userCode := env.authorize(t)
+
+	status, body := env.poll(t, deviceCode)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errAuthorizationPending, body["error"])
+
+	_, body = env.poll(t, deviceCode)
+	assert.Equal(t, errSlowDown, body["error"])
+
+	principal := v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: "github_user://1234"}, Provider: "github"}
+	env.auth.token = &v3.Token{UserID: "u-abcde", AuthProvider: "github", UserPrincipal: principal}
+	rec := env.submit(strings.ToLower(strings.ReplaceAll(userCode, "-", "")), "approve")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Your device is now logged in")
+
+	env.now = env.now.Add(10 * time.Second)
+	status, body = env.poll(t, deviceCode)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "token-abcde:secretkey", body["access_token"])
+	assert.Equal(t, "Bearer", body["token_type"])
+	assert.EqualValues(t, 960*60, body["expires_in"])
+	assert.Equal(t, "u-abcde", env.tokenMGR.userID)
+	assert.Equal(t, principal.Name, env.tokenMGR.userPrincipal.Name)
+
+	status, body = env.poll(t, deviceCode)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errInvalidGrant, body["error"])
+	assert.Equal(t, 1, env.tokenMGR.calls)
+}
+
+func TestDeniedDevice(t *testing.T) {
+	env := newTestEnv(t)
+	deviceCode, userCode := env.authorize(t)
+
+	env.auth.token = &v3.Token{UserID: "u-abcde", AuthProvider: "github"}
+	rec := env.submit(userCode, "deny")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = env.submit(userCode, "approve")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "already been used")
+
+	_, body := env.poll(t, deviceCode)
+	assert.Equal(t, errAccessDenied, body["error"])
+	assert.Zero(t, env.tokenMGR.calls)
+}
+
+func TestExpiredDevice(t *testing.T) {
+	env := newTestEnv(t)
+	deviceCode, userCode := env.authorize(t)
+
+	env.now = env.now.Add(expiresIn)
+	env.auth.token = &v3.Token{UserID: "u-abcde", AuthProvider: "github"}
+	rec := env.submit(userCode, "approve")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid or has expired")
+
+	_, body := env.poll(t, deviceCode)
+	assert.Equal(t, errExpiredToken, body["error"])
+
+	_, body = env.poll(t, deviceCode)
+	assert.Equal(t, errInvalidGrant, body["error"])
+}
+
+func TestToken(t *testing.T) {
+	env := newTestEnv(t)
+	deviceCode, _ := env.authorize(t)
+	id, _, _ := strings.Cut(deviceCode, ":")
+
+	rec := env.post(env.handler.token, url.Values{"grant_type": {"authorization_code"}, "device_code": {deviceCode}})
+	assert.Equal(t, errUnsupportedGrantType, decode(t, rec)["error"])
+
+	for _, code := range []string{"", id, id + ":wrong", "other:" + strings.Repeat("a", 43)} {
+		_, body := env.poll(t, code)
+		assert.Equal(t, errInvalidGrant, body["error"], code)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       accessor.TokenAccessor
+		csrf        string
+		userCode    string
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "not logged in",
+			csrf:        "csrf-value",
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "must be logged in",
+		},
+		{
+			name:        "invalid CSRF token",
+			token:       &v3.Token{UserID: "u-abcde", AuthProvider: "github"},
+			csrf:        "other",
+			wantStatus:  http.StatusForbidden,
+			wantMessage: "Invalid request",
+		},
+		{
+			name:        "derived token",
+			token:       &v3.Token{UserID: "u-abcde", AuthProvider: "github", IsDerived: true},
+			csrf:        "csrf-value",
+			wantStatus:  http.StatusForbidden,
+			wantMessage: "browser session",
+		},
+		{
+			name:        "other provider",
+			token:       &v3.Token{UserID: "u-abcde", AuthProvider: "local"},
+			csrf:        "csrf-value",
+			wantStatus:  http.StatusForbidden,
+			wantMessage: "requested a login with github",
+		},
+		{
+			name:        "unknown code",
+			token:       &v3.Token{UserID: "u-abcde", AuthProvider: "github"},
+			csrf:        "csrf-value",
+			userCode:    "BCDF-GHJK",
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "invalid or has expired",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			deviceCode, userCode := env.authorize(t)
+			if tt.userCode != "" {
+				userCode = tt.userCode
+			}
+
+			env.auth.token = tt.token
+			rec := env.post(env.handler.verify, url.Values{"user_code": {userCode}, "action": {"approve"}, "csrf": {tt.csrf}},
+				&http.Cookie{Name: tokens.CSRFCookie, Value: "csrf-value"})
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantMessage)
+			assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+
+			_, body := env.poll(t, deviceCode)
+			assert.Equal(t, errAuthorizationPending, body["error"])
+		})
+	}
+}
+
+func TestVerifyPage(t *testing.T) {
+	env := newTestEnv(t)
+
+	rec := httptest.NewRecorder()
+	env.handler.verifyPage(rec, httptest.NewRequest(http.MethodGet, "/v1-device/verify?user_code=BCDF-GHJK", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "<form")
+
+	env.auth.token = &v3.Token{UserID: "u-abcde", AuthProvider: "github"}
+	rec = httptest.NewRecorder()
+	env.handler.verifyPage(rec, httptest.NewRequest(http.MethodGet, "/v1-device/verify?user_code=BCDF-GHJK", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `value="BCDF-GHJK"`)
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, tokens.CSRFCookie, cookies[0].Name)
+	assert.Contains(t, rec.Body.String(), `name="csrf" value="`+cookies[0].Value+`"`)
+}
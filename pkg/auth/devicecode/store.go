@@ -0,0 +1,243 @@
+package devicecode
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// userCodeCharset excludes vowels and look-alike characters, as recommended by RFC 8628 section 6.1.
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+	secretPrefix    = "deviceauth-"
+
+	statusPending  = "pending"
+	statusApproved = "approved"
+	statusDenied   = "denied"
+
+	deviceSecretKey   = "deviceSecretHash"
+	providerKey       = "provider"
+	statusKey         = "status"
+	expiresAtKey      = "expiresAt"
+	lastPolledAtKey   = "lastPolledAt"
+	userIDKey         = "userId"
+	userPrincipalKey  = "userPrincipal"
+	userCodeHashLabel = "cattle.io/device-user-code"
+)
+
+var (
+	errNotFound = errors.New("device authorization not found")
+	errExpired  = errors.New("device authorization expired")
+)
+
+// authorization is a pending, approved or denied device authorization request.
+// It's stored in a secret, which holds hashes of the device and user codes rather than the codes themselves.
+type authorization struct {
+	secret *corev1.Secret
+
+	Provider      string
+	Status        string
+	ExpiresAt     time.Time
+	LastPolledAt  time.Time
+	UserID        string
+	UserPrincipal v3.Principal
+}
+
+type store struct {
+	secrets wcorev1.SecretClient
+	now     func() time.Time
+}
+
+// create stores a new pending authorization for the provider and returns its device and user codes.
+func (s *store) create(provider string, expiresIn time.Duration) (string, string, error) {
+	id, err := randomString(10, "bcdfghjklmnpqrstvwxz0123456789")
+	if err != nil {
+		return "", "", err
+	}
+	deviceSecret := make([]byte, 32)
+	if _, err := rand.Read(deviceSecret); err != nil {
+		return "", "", err
+	}
+	deviceSecretValue := base64.RawURLEncoding.EncodeToString(deviceSecret)
+	userCode, err := randomString(userCodeLength, userCodeCharset)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretPrefix + id,
+			Namespace: tokens.SecretNamespace,
+			Labels: map[string]string{
+				tokens.DeviceAuthorizationLabel: "true",
+				userCodeHashLabel:               hashUserCode(userCode),
+			},
+		},
+		Data: map[string][]byte{
+			deviceSecretKey: []byte(hash(deviceSecretValue)),
+			providerKey:     []byte(provider),
+			statusKey:       []byte(statusPending),
+			expiresAtKey:    []byte(s.now().Add(expiresIn).UTC().Format(time.RFC3339)),
+		},
+	}
+	if _, err := s.secrets.Create(secret); err != nil {
+		return "", "", fmt.Errorf("creating device authorization: %w", err)
+	}
+
+	return id + ":" + deviceSecretValue, formatUserCode(userCode), nil
+}
+
+// getByDeviceCode returns the authorization the device code was issued for.
+func (s *store) getByDeviceCode(deviceCode string) (*authorization, error) {
+	id, deviceSecret, ok := strings.Cut(deviceCode, ":")
+	if !ok || id == "" || deviceSecret == "" {
+		return nil, errNotFound
+	}
+	secret, err := s.secrets.Get(tokens.SecretNamespace, secretPrefix+id, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(secret.Data[deviceSecretKey], []byte(hash(deviceSecret))) != 1 {
+		return nil, errNotFound
+	}
+	return s.fromSecret(secret)
+}
+
+// getByUserCode returns the authorization the user code was issued for.
+func (s *store) getByUserCode(userCode string) (*authorization, error) {
+	normalized := normalizeUserCode(userCode)
+	if len(normalized) != userCodeLength {
+		return nil, errNotFound
+	}
+	selector := labels.SelectorFromSet(labels.Set{
+		tokens.DeviceAuthorizationLabel: "true",
+		userCodeHashLabel:               hashUserCode(normalized),
+	})
+	secrets, err := s.secrets.List(tokens.SecretNamespace, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	if len(secrets.Items) != 1 {
+		return nil, errNotFound
+	}
+	return s.fromSecret(&secrets.Items[0])
+}
+
+func (s *store) fromSecret(secret *corev1.Secret) (*authorization, error) {
+	auth := &authorization{
+		secret:   secret,
+		Provider: string(secret.Data[providerKey]),
+		Status:   string(secret.Data[statusKey]),
+		UserID:   string(secret.Data[userIDKey]),
+	}
+	expiresAt, err := time.Parse(time.RFC3339, string(secret.Data[expiresAtKey]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiry of device authorization %s: %w", secret.Name, err)
+	}
+	auth.ExpiresAt = expiresAt
+	if lastPolledAt := string(secret.Data[lastPolledAtKey]); lastPolledAt != "" {
+		auth.LastPolledAt, _ = time.Parse(time.RFC3339Nano, lastPolledAt)
+	}
+	if principal := secret.Data[userPrincipalKey]; len(principal) > 0 {
+		if err := json.Unmarshal(principal, &auth.UserPrincipal); err != nil {
+			return nil, fmt.Errorf("invalid principal of device authorization %s: %w", secret.Name, err)
+		}
+	}
+
+	if !s.now().Before(auth.ExpiresAt) {
+		return auth, errExpired
+	}
+	return auth, nil
+}
+
+// update stores the status, user and poll time of the authorization.
+// Conflicting updates fail, so that an authorization is only approved, denied or redeemed once.
+func (s *store) update(auth *authorization) error {
+	secret := auth.secret.DeepCopy()
+	secret.Data[statusKey] = []byte(auth.Status)
+	if !auth.LastPolledAt.IsZero() {
+		secret.Data[lastPolledAtKey] = []byte(auth.LastPolledAt.UTC().Format(time.RFC3339Nano))
+	}
+	if auth.UserID != "" {
+		principal, err := json.Marshal(auth.UserPrincipal)
+		if err != nil {
+			return err
+		}
+		secret.Data[userIDKey] = []byte(auth.UserID)
+		secret.Data[userPrincipalKey] = principal
+	}
+	updated, err := s.secrets.Update(secret)
+	if err != nil {
+		return err
+	}
+	auth.secret = updated
+	return nil
+}
+
+// delete deletes the authorization, failing if it changed since it was read.
+func (s *store) delete(auth *authorization) error {
+	return s.secrets.Delete(tokens.SecretNamespace, auth.secret.Name, &metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{
+			UID:             &auth.secret.UID,
+			ResourceVersion: &auth.secret.ResourceVersion,
+		},
+	})
+}
+
+func randomString(length int, charset string) (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(charset)))
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(charset[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// formatUserCode splits the user code in two groups of four characters, which are easier to type.
+func formatUserCode(userCode string) string {
+	return userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+}
+
+// normalizeUserCode drops the separators users may or may not type and ignores the case.
+func normalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(userCode))
+}
+
+func hashUserCode(userCode string) string {
+	// Label values are limited to 63 characters.
+	return hash(normalizeUserCode(userCode))[:40]
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
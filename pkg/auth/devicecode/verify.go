@@ -0,0 +1,155 @@
+package devicecode
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var verifyTemplate = template.Must(template.New("verify").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Rancher device login</title>
+</head>
+<body>
+<h1>Rancher device login</h1>
+{{- if .Message}}
+<p>{{.Message}}</p>
+{{- end}}
+{{- if .Login}}
+<p><a href="/">Log in to Rancher</a>, then reload this page.</p>
+{{- end}}
+{{- if .Form}}
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="csrf" value="{{.CSRF}}">
+<label for="user_code">Enter the code displayed on your device:</label>
+<input type="text" id="user_code" name="user_code" value="{{.UserCode}}" autocomplete="off" required>
+<button type="submit" name="action" value="approve">Approve</button>
+<button type="submit" name="action" value="deny">Deny</button>
+</form>
+{{- end}}
+</body>
+</html>
+`))
+
+type verifyPage struct {
+	Message  string
+	Login    bool
+	Form     bool
+	Action   string
+	CSRF     string
+	UserCode string
+}
+
+// verifyPage shows the form users approve or deny the user code of a device with.
+func (h *handler) verifyPage(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.auth.TokenFromRequest(r); err != nil {
+		renderVerifyPage(w, http.StatusUnauthorized, verifyPage{Message: "You must be logged in to approve a device.", Login: true})
+		return
+	}
+
+	csrf, err := ensureCSRFCookie(w, r)
+	if err != nil {
+		logrus.Errorf("[devicecode] failed to generate CSRF token: %v", err)
+		renderVerifyPage(w, http.StatusInternalServerError, verifyPage{Message: "Something went wrong, try again."})
+		return
+	}
+	renderVerifyPage(w, http.StatusOK, verifyPage{Form: true, Action: verifyPath, CSRF: csrf, UserCode: r.URL.Query().Get("user_code")})
+}
+
+// verify approves or denies the device authorization of the submitted user code on behalf of the logged in user.
+func (h *handler) verify(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(tokens.CSRFCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(r.PostFormValue("csrf"))) != 1 {
+		renderVerifyPage(w, http.StatusForbidden, verifyPage{Message: "Invalid request, reload the page and try again."})
+		return
+	}
+
+	token, err := h.auth.TokenFromRequest(r)
+	if err != nil {
+		renderVerifyPage(w, http.StatusUnauthorized, verifyPage{Message: "You must be logged in to approve a device.", Login: true})
+		return
+	}
+	if token.GetIsDerived() {
+		renderVerifyPage(w, http.StatusForbidden, verifyPage{Message: "Devices can only be approved from a browser session."})
+		return
+	}
+
+	auth, err := h.store.getByUserCode(r.PostFormValue("user_code"))
+	if err != nil {
+		if !errors.Is(err, errNotFound) && !errors.Is(err, errExpired) {
+			logrus.Errorf("[devicecode] failed to get device authorization: %v", err)
+		}
+		renderVerifyPage(w, http.StatusBadRequest, verifyPage{Message: "The code is invalid or has expired."})
+		return
+	}
+	if auth.Status != statusPending {
+		renderVerifyPage(w, http.StatusBadRequest, verifyPage{Message: "The code has already been used."})
+		return
+	}
+	if token.GetAuthProvider() != auth.Provider {
+		renderVerifyPage(w, http.StatusForbidden, verifyPage{Message: "The device requested a login with " + auth.Provider + ", log in to Rancher with it to approve the device.", Login: true})
+		return
+	}
+
+	message := "Your device has been denied access."
+	auth.Status = statusDenied
+	if r.PostFormValue("action") == "approve" {
+		message = "Your device is now logged in, you can close this page."
+		auth.Status = statusApproved
+		auth.UserID = token.GetUserID()
+		auth.UserPrincipal = token.GetUserPrincipal()
+	}
+	if err := h.store.update(auth); err != nil {
+		if apierrors.IsConflict(err) {
+			renderVerifyPage(w, http.StatusBadRequest, verifyPage{Message: "The code has already been used."})
+			return
+		}
+		logrus.Errorf("[devicecode] failed to update device authorization: %v", err)
+		renderVerifyPage(w, http.StatusInternalServerError, verifyPage{Message: "Something went wrong, try again."})
+		return
+	}
+
+	renderVerifyPage(w, http.StatusOK, verifyPage{Message: message})
+}
+
+// ensureCSRFCookie returns the CSRF token of the browser, setting one if it doesn't have one yet.
+// The form submits it back, which a cross-site request can't since it can't read the cookie.
+func ensureCSRFCookie(w http.ResponseWriter, r *http.Request) (string, error) {
+	if cookie, err := r.Cookie(tokens.CSRFCookie); err == nil && cookie.Value != "" {
+		return cookie.Value, nil
+	}
+
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	value := hex.EncodeToString(bytes)
+	http.SetCookie(w, &http.Cookie{
+		Name:   tokens.CSRFCookie,
+		Value:  value,
+		Path:   "/",
+		Secure: true,
+	})
+	return value, nil
+}
+
+func renderVerifyPage(w http.ResponseWriter, status int, page verifyPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// The approve button mustn't be clickable from another site.
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+	w.WriteHeader(status)
+	if err := verifyTemplate.Execute(w, page); err != nil {
+		logrus.Errorf("[devicecode] failed to render verification page: %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+// Package effectivepermissions answers "can user X do Y on Z?" at the management layer. The access is evaluated
+// against the GlobalRoleBindings, ClusterRoleTemplateBindings and ProjectRoleTemplateBindings of the user and of the
+// group principals its auth providers reported on its last login, and the answer reports which of these bindings grant
+// the access, if any. It's evaluated from the bindings and roles of the management cluster: the Roles and RoleBindings
+// created directly in the downstream clusters aren't taken into account.
+package effectivepermissions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	normanv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/types/config"
+	k8srbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac"
+)
+
+const (
+	// LocalCluster is the cluster the queries are evaluated in when they don't set one. The GlobalRoles grant their
+	// rules in it.
+	LocalCluster = "local"
+
+	globalRoleBindingKind          = "GlobalRoleBinding"
+	clusterRoleTemplateBindingKind = "ClusterRoleTemplateBinding"
+	projectRoleTemplateBindingKind = "ProjectRoleTemplateBinding"
+)
+
+// kindOrder orders the bindings of the results like they're evaluated.
+var kindOrder = map[string]int{
+	globalRoleBindingKind:          0,
+	clusterRoleTemplateBindingKind: 1,
+	projectRoleTemplateBindingKind: 2,
+}
+
+// Query asks whether a user can apply a verb to a resource in a cluster. The ProjectRoleTemplateBindings are evaluated
+// for the queries setting the project of the namespace, in the <cluster>:<project> format.
+type Query struct {
+	UserID      string `json:"userId,omitempty"`
+	Verb        string `json:"verb"`
+	APIGroup    string `json:"apiGroup,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	ClusterID   string `json:"clusterId,omitempty"`
+	ProjectID   string `json:"projectId,omitempty"`
+}
+
+// Result is the answer to a query. Bindings holds every binding of the user and its groups evaluated, and whether it
+// grants the access.
+type Result struct {
+	Allowed  bool      `json:"allowed"`
+	Reason   string    `json:"reason"`
+	UserID   string    `json:"userId"`
+	Groups   []string  `json:"groups"`
+	Bindings []Binding `json:"bindings"`
+}
+
+// Binding is a binding of the user, or of one of its groups, to a GlobalRole or a RoleTemplate.
+type Binding struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	RoleName  string `json:"roleName"`
+	Subject   string `json:"subject"`
+	Allowed   bool   `json:"allowed"`
+	Error     string `json:"error,omitempty"`
+}
+
+type evaluator struct {
+	userCache          mgmtcontrollers.UserCache
+	userAttributeCache mgmtcontrollers.UserAttributeCache
+	grbCache           mgmtcontrollers.GlobalRoleBindingCache
+	grCache            mgmtcontrollers.GlobalRoleCache
+	grLister           normanv3.GlobalRoleLister
+	crtbCache          mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbCache          mgmtcontrollers.ProjectRoleTemplateBindingCache
+	rtCache            mgmtcontrollers.RoleTemplateCache
+	clusterRoleCache   k8srbacv1.ClusterRoleCache
+}
+
+func newEvaluator(mgmt *config.ScaledContext) *evaluator {
+	return &evaluator{
+		userCache:          mgmt.Wrangler.Mgmt.User().Cache(),
+		userAttributeCache: mgmt.Wrangler.Mgmt.UserAttribute().Cache(),
+		grbCache:           mgmt.Wrangler.Mgmt.GlobalRoleBinding().Cache(),
+		grCache:            mgmt.Wrangler.Mgmt.GlobalRole().Cache(),
+		grLister:           mgmt.Management.GlobalRoles("").Controller().Lister(),
+		crtbCache:          mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbCache:          mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		rtCache:            mgmt.Wrangler.Mgmt.RoleTemplate().Cache(),
+		clusterRoleCache:   mgmt.Wrangler.RBAC.ClusterRole().Cache(),
+	}
+}
+
+func (q *Query) validate() error {
+	if q.Verb == "" || q.Resource == "" {
+		return fmt.Errorf("verb and resource are required")
+	}
+	if q.ProjectID != "" {
+		clusterName, projectName, found := strings.Cut(q.ProjectID, ":")
+		if !found || clusterName == "" || projectName == "" {
+			return fmt.Errorf("invalid projectId, must be <cluster>:<project>")
+		}
+		if q.ClusterID != "" && q.ClusterID != clusterName {
+			return fmt.Errorf("project %s isn't in cluster %s", q.ProjectID, q.ClusterID)
+		}
+		q.ClusterID = clusterName
+	}
+	if q.ClusterID == "" {
+		q.ClusterID = LocalCluster
+	}
+	return nil
+}
+
+// evaluate answers the validated query. Bindings whose roles can't be read are reported with an error, and don't grant
+// the access.
+func (e *evaluator) evaluate(q Query) (*Result, error) {
+	user, err := e.userCache.Get(q.UserID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := e.groups(user.Name)
+	if err != nil {
+		return nil, err
+	}
+	subjectOf := func(userName, groupPrincipalName string) string {
+		if userName != "" && userName == user.Name {
+			return userName
+		}
+		if groupPrincipalName != "" && groups[groupPrincipalName] {
+			return groupPrincipalName
+		}
+		return ""
+	}
+	attributes := authorizer.AttributesRecord{
+		Verb:            q.Verb,
+		APIGroup:        q.APIGroup,
+		Resource:        q.Resource,
+		Subresource:     q.Subresource,
+		Name:            q.Name,
+		Namespace:       q.Namespace,
+		ResourceRequest: true,
+	}
+
+	var bindings []Binding
+	grbs, err := e.grbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing GlobalRoleBindings: %w", err)
+	}
+	for _, grb := range grbs {
+		subject := subjectOf(grb.UserName, grb.GroupPrincipalName)
+		if subject == "" || grb.DeletionTimestamp != nil {
+			continue
+		}
+		binding := Binding{Kind: globalRoleBindingKind, Name: grb.Name, RoleName: grb.GlobalRoleName, Subject: subject}
+		binding.Allowed, err = e.globalRoleAllows(grb.GlobalRoleName, q, attributes)
+		if err != nil {
+			binding.Error = err.Error()
+		}
+		bindings = append(bindings, binding)
+	}
+
+	crtbs, err := e.crtbCache.List(q.ClusterID, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ClusterRoleTemplateBindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		subject := subjectOf(crtb.UserName, crtb.GroupPrincipalName)
+		if subject == "" || crtb.ClusterName != q.ClusterID || crtb.DeletionTimestamp != nil {
+			continue
+		}
+		binding := Binding{Kind: clusterRoleTemplateBindingKind, Name: crtb.Name, Namespace: crtb.Namespace, RoleName: crtb.RoleTemplateName, Subject: subject}
+		if crtb.NamespaceSelector != nil && q.Namespace == "" {
+			// bindings scoped to namespaces don't grant cluster-wide access
+			bindings = append(bindings, binding)
+			continue
+		}
+		binding.Allowed, err = e.roleTemplatesAllow([]string{crtb.RoleTemplateName}, attributes)
+		if err != nil {
+			binding.Error = err.Error()
+		}
+		bindings = append(bindings, binding)
+	}
+
+	if q.ProjectID != "" {
+		_, projectName, _ := strings.Cut(q.ProjectID, ":")
+		prtbs, err := e.prtbCache.List(projectName, labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("listing ProjectRoleTemplateBindings: %w", err)
+		}
+		for _, prtb := range prtbs {
+			subject := subjectOf(prtb.UserName, prtb.GroupPrincipalName)
+			if subject == "" || prtb.ProjectName != q.ProjectID || prtb.DeletionTimestamp != nil {
+				continue
+			}
+			binding := Binding{Kind: projectRoleTemplateBindingKind, Name: prtb.Name, Namespace: prtb.Namespace, RoleName: prtb.RoleTemplateName, Subject: subject}
+			binding.Allowed, err = e.roleTemplatesAllow([]string{prtb.RoleTemplateName}, attributes)
+			if err != nil {
+				binding.Error = err.Error()
+			}
+			bindings = append(bindings, binding)
+		}
+	}
+
+	result := &Result{
+		UserID:   user.Name,
+		Groups:   make([]string, 0, len(groups)),
+		Bindings: bindings,
+	}
+	for group := range groups {
+		result.Groups = append(result.Groups, group)
+	}
+	sort.Strings(result.Groups)
+	if result.Bindings == nil {
+		result.Bindings = []Binding{}
+	}
+	sort.SliceStable(result.Bindings, func(i, j int) bool {
+		if kindOrder[result.Bindings[i].Kind] != kindOrder[result.Bindings[j].Kind] {
+			return kindOrder[result.Bindings[i].Kind] < kindOrder[result.Bindings[j].Kind]
+		}
+		return bindingID(result.Bindings[i]) < bindingID(result.Bindings[j])
+	})
+
+	var granting []string
+	for _, binding := range result.Bindings {
+		if binding.Allowed {
+			granting = append(granting, binding.Kind+" "+bindingID(binding))
+		}
+	}
+	switch {
+	case user.Enabled != nil && !*user.Enabled:
+		result.Reason = fmt.Sprintf("user %s is disabled", user.Name)
+	case len(granting) == 0:
+		result.Reason = "no binding of the user or its groups grants the access"
+	default:
+		result.Allowed = true
+		result.Reason = "granted by " + strings.Join(granting, ", ")
+	}
+	return result, nil
+}
+
+// groups returns the group principals of the user, as reported by its auth providers on its last login.
+func (e *evaluator) groups(userName string) (map[string]bool, error) {
+	groups := map[string]bool{}
+	attribute, err := e.userAttributeCache.Get(userName)
+	if apierrors.IsNotFound(err) {
+		return groups, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting the user attribute of user %s: %w", userName, err)
+	}
+	for _, principals := range attribute.GroupPrincipals {
+		for _, principal := range principals.Items {
+			groups[principal.Name] = true
+		}
+	}
+	return groups, nil
+}
+
+// globalRoleAllows tells whether the global role grants the access. Global roles grant their rules, and their
+// namespaced rules, in the local cluster, and their inherited cluster roles in the other clusters, where admin global
+// roles grant everything. Inherited cluster roles scoped to namespaces don't grant cluster-wide access. The labels of
+// downstream namespaces aren't known here, so they're assumed to match the selector of namespaced queries.
+func (e *evaluator) globalRoleAllows(globalRoleName string, q Query, attributes authorizer.AttributesRecord) (bool, error) {
+	gr, err := e.grCache.Get(globalRoleName)
+	if err != nil {
+		return false, err
+	}
+	if q.ClusterID == LocalCluster {
+		rules := append([]rbacv1.PolicyRule{}, gr.Rules...)
+		if q.Namespace != "" {
+			rules = append(rules, gr.NamespacedRules[q.Namespace]...)
+		}
+		return rbac.RulesAllow(attributes, rules...), nil
+	}
+
+	admin, err := pkgrbac.IsAdminGlobalRole(gr.Name, e.grLister)
+	if err != nil {
+		return false, err
+	}
+	if admin {
+		return true, nil
+	}
+	if gr.InheritedClusterRolesNamespaceSelector != nil && q.Namespace == "" {
+		return false, nil
+	}
+	return e.roleTemplatesAllow(gr.InheritedClusterRoles, attributes)
+}
+
+// roleTemplatesAllow tells whether the role templates, or those they inherit, grant the access.
+func (e *evaluator) roleTemplatesAllow(roleTemplateNames []string, attributes authorizer.AttributesRecord) (bool, error) {
+	for _, roleTemplateName := range roleTemplateNames {
+		rt, err := e.rtCache.Get(roleTemplateName)
+		if err != nil {
+			return false, err
+		}
+		rules, err := pkgrbac.RulesFromTemplate(e.clusterRoleCache, e.rtCache, rt)
+		if err != nil {
+			return false, err
+		}
+		if rbac.RulesAllow(attributes, rules...) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func bindingID(binding Binding) string {
+	if binding.Namespace == "" {
+		return binding.Name
+	}
+	return binding.Namespace + "/" + binding.Name
+}
@@ -0,0 +1,270 @@
+package effectivepermissions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	// admins are allowed everything.
+	admins map[string]bool
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	sar.Status.Allowed = f.admins[sar.Spec.User]
+	return sar, nil
+}
+
+var (
+	podReader = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}}
+	nodeAdmin = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"*"}}
+	disabled  = false
+)
+
+func setup(t *testing.T) *handler {
+	ctrl := gomock.NewController(t)
+	notFound := func(resource, name string) error {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: resource}, name)
+	}
+
+	users := map[string]*v3.User{
+		"u-dev":      {ObjectMeta: metav1.ObjectMeta{Name: "u-dev"}},
+		"u-admin":    {ObjectMeta: metav1.ObjectMeta{Name: "u-admin"}},
+		"u-disabled": {ObjectMeta: metav1.ObjectMeta{Name: "u-disabled"}, Enabled: &disabled},
+		"u-platform": {ObjectMeta: metav1.ObjectMeta{Name: "u-platform"}},
+	}
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.User, error) {
+		if u, ok := users[name]; ok {
+			return u, nil
+		}
+		return nil, notFound("users", name)
+	}).AnyTimes()
+
+	userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	userAttributeCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.UserAttribute, error) {
+		if name == "u-dev" {
+			return &v3.UserAttribute{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				GroupPrincipals: map[string]v3.Principals{
+					"openldap": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "openldap_group://cn=devs"}}}},
+				},
+			}, nil
+		}
+		return nil, notFound("userattributes", name)
+	}).AnyTimes()
+
+	globalRoles := map[string]*v3.GlobalRole{
+		"user":  {ObjectMeta: metav1.ObjectMeta{Name: "user"}, Rules: []rbacv1.PolicyRule{{APIGroups: []string{"management.cattle.io"}, Resources: []string{"preferences"}, Verbs: []string{"*"}}}},
+		"admin": {ObjectMeta: metav1.ObjectMeta{Name: "admin"}, Builtin: true},
+	}
+	grCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRole](ctrl)
+	grCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.GlobalRole, error) {
+		if gr, ok := globalRoles[name]; ok {
+			return gr, nil
+		}
+		return nil, notFound("globalroles", name)
+	}).AnyTimes()
+	grLister := &fakes.GlobalRoleListerMock{
+		GetFunc: func(_, name string) (*v3.GlobalRole, error) {
+			if gr, ok := globalRoles[name]; ok {
+				return gr, nil
+			}
+			return nil, notFound("globalroles", name)
+		},
+	}
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl)
+	grbCache.EXPECT().List(gomock.Any()).Return([]*v3.GlobalRoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-dev"}, UserName: "u-dev", GlobalRoleName: "user"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-admin"}, UserName: "u-admin", GlobalRoleName: "admin"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-disabled"}, UserName: "u-disabled", GlobalRoleName: "admin"},
+	}, nil).AnyTimes()
+
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace string, _ labels.Selector) ([]*v3.ClusterRoleTemplateBinding, error) {
+		if namespace != "c-abcde" {
+			return nil, nil
+		}
+		return []*v3.ClusterRoleTemplateBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-nodes", Namespace: namespace}, ClusterName: namespace, UserName: "u-dev", RoleTemplateName: "nodes-manage"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-other", Namespace: namespace}, ClusterName: namespace, UserName: "u-other", RoleTemplateName: "nodes-manage"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-scoped", Namespace: namespace}, ClusterName: namespace, UserName: "u-platform", RoleTemplateName: "pods-view", NamespaceSelector: &metav1.LabelSelector{}},
+		}, nil
+	}).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace string, _ labels.Selector) ([]*v3.ProjectRoleTemplateBinding, error) {
+		if namespace != "p-fghij" {
+			return nil, nil
+		}
+		return []*v3.ProjectRoleTemplateBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "prtb-devs", Namespace: namespace}, ProjectName: "c-abcde:p-fghij", GroupPrincipalName: "openldap_group://cn=devs", RoleTemplateName: "read-only"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "prtb-missing", Namespace: namespace}, ProjectName: "c-abcde:p-fghij", UserName: "u-dev", RoleTemplateName: "missing"},
+		}, nil
+	}).AnyTimes()
+
+	roleTemplates := map[string]*v3.RoleTemplate{
+		"nodes-manage": {ObjectMeta: metav1.ObjectMeta{Name: "nodes-manage"}, Context: "cluster", Rules: []rbacv1.PolicyRule{nodeAdmin}},
+		"read-only":    {ObjectMeta: metav1.ObjectMeta{Name: "read-only"}, Context: "project", RoleTemplateNames: []string{"pods-view"}},
+		"pods-view":    {ObjectMeta: metav1.ObjectMeta{Name: "pods-view"}, Context: "project", Rules: []rbacv1.PolicyRule{podReader}},
+	}
+	rtCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	rtCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.RoleTemplate, error) {
+		if rt, ok := roleTemplates[name]; ok {
+			return rt, nil
+		}
+		return nil, notFound("roletemplates", name)
+	}).AnyTimes()
+
+	return &handler{
+		evaluator: &evaluator{
+			userCache:          userCache,
+			userAttributeCache: userAttributeCache,
+			grbCache:           grbCache,
+			grCache:            grCache,
+			grLister:           grLister,
+			crtbCache:          crtbCache,
+			prtbCache:          prtbCache,
+			rtCache:            rtCache,
+			clusterRoleCache:   fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRole](ctrl),
+		},
+		subjectAccessReviews: &fakeSubjectAccessReviews{admins: map[string]bool{"u-admin": true}},
+	}
+}
+
+func serve(h *handler, userID string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(body)
+	req := httptest.NewRequest(http.MethodPost, BasePath, &buf)
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userID}))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeResult(t *testing.T, rec *httptest.ResponseRecorder) Result {
+	var result Result
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	return result
+}
+
+func TestQueryGrantedByGroup(t *testing.T) {
+	h := setup(t)
+
+	rec := serve(h, "u-dev", Query{Verb: "list", Resource: "pods", Namespace: "ns-a", ProjectID: "c-abcde:p-fghij"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	result := decodeResult(t, rec)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, "granted by ProjectRoleTemplateBinding p-fghij/prtb-devs", result.Reason)
+	assert.Equal(t, "u-dev", result.UserID)
+	assert.Equal(t, []string{"openldap_group://cn=devs"}, result.Groups)
+	assert.Equal(t, []Binding{
+		{Kind: globalRoleBindingKind, Name: "grb-dev", RoleName: "user", Subject: "u-dev"},
+		{Kind: clusterRoleTemplateBindingKind, Name: "crtb-nodes", Namespace: "c-abcde", RoleName: "nodes-manage", Subject: "u-dev"},
+		{Kind: projectRoleTemplateBindingKind, Name: "prtb-devs", Namespace: "p-fghij", RoleName: "read-only", Subject: "openldap_group://cn=devs", Allowed: true},
+		{Kind: projectRoleTemplateBindingKind, Name: "prtb-missing", Namespace: "p-fghij", RoleName: "missing", Subject: "u-dev", Error: `roletemplates "missing" not found`},
+	}, result.Bindings)
+}
+
+func TestQueryDenied(t *testing.T) {
+	h := setup(t)
+
+	// The ProjectRoleTemplateBindings aren't evaluated without a project.
+	rec := serve(h, "u-dev", Query{Verb: "list", Resource: "pods", Namespace: "ns-a", ClusterID: "c-abcde"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	result := decodeResult(t, rec)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "no binding of the user or its groups grants the access", result.Reason)
+	assert.Len(t, result.Bindings, 2)
+
+	rec = serve(h, "u-dev", Query{Verb: "delete", Resource: "nodes", ClusterID: "c-abcde"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.True(t, decodeResult(t, rec).Allowed)
+}
+
+func TestQueryGlobalRoles(t *testing.T) {
+	h := setup(t)
+
+	// Global roles grant their rules in the local cluster.
+	rec := serve(h, "u-dev", Query{Verb: "update", APIGroup: "management.cattle.io", Resource: "preferences"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "granted by GlobalRoleBinding grb-dev", decodeResult(t, rec).Reason)
+
+	// Admins are granted everything in the downstream clusters.
+	rec = serve(h, "u-admin", Query{Verb: "delete", Resource: "secrets", ClusterID: "c-abcde"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.True(t, decodeResult(t, rec).Allowed)
+
+	// Disabled users are granted nothing, though their bindings are reported.
+	rec = serve(h, "u-admin", Query{UserID: "u-disabled", Verb: "delete", Resource: "secrets", ClusterID: "c-abcde"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	result := decodeResult(t, rec)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "user u-disabled is disabled", result.Reason)
+	require.Len(t, result.Bindings, 1)
+	assert.True(t, result.Bindings[0].Allowed)
+}
+
+func TestQueryNamespaceSelector(t *testing.T) {
+	h := setup(t)
+
+	// Bindings scoped to namespaces don't grant cluster-wide access.
+	rec := serve(h, "u-admin", Query{UserID: "u-platform", Verb: "list", Resource: "pods", ClusterID: "c-abcde"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.False(t, decodeResult(t, rec).Allowed)
+
+	rec = serve(h, "u-admin", Query{UserID: "u-platform", Verb: "list", Resource: "pods", Namespace: "kube-system", ClusterID: "c-abcde"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "granted by ClusterRoleTemplateBinding c-abcde/crtb-scoped", decodeResult(t, rec).Reason)
+}
+
+func TestQueryOtherUser(t *testing.T) {
+	h := setup(t)
+
+	rec := serve(h, "u-dev", Query{UserID: "u-admin", Verb: "get", Resource: "pods"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(h, "u-admin", Query{UserID: "u-dev", Verb: "get", Resource: "pods", ClusterID: "c-abcde"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "u-dev", decodeResult(t, rec).UserID)
+
+	rec = serve(h, "u-admin", Query{UserID: "u-missing", Verb: "get", Resource: "pods"})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestQueryInvalid(t *testing.T) {
+	h := setup(t)
+
+	tests := map[string]Query{
+		"missing verb":       {Resource: "pods"},
+		"missing resource":   {Verb: "get"},
+		"invalid project":    {Verb: "get", Resource: "pods", ProjectID: "p-fghij"},
+		"mismatched cluster": {Verb: "get", Resource: "pods", ClusterID: "c-other", ProjectID: "c-abcde:p-fghij"},
+	}
+	for name, q := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := serve(h, "u-dev", q)
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		})
+	}
+}
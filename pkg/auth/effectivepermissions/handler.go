@@ -0,0 +1,107 @@
+package effectivepermissions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// BasePath is the path of the effective permissions endpoint.
+	BasePath = "/v1-effective-permissions"
+
+	maxBodySize = 64 * 1024
+)
+
+type handler struct {
+	*evaluator
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewHandler returns the handler of the effective permissions endpoint.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	return newHandler(mgmt).router()
+}
+
+func newHandler(mgmt *config.ScaledContext) *handler {
+	return &handler{
+		evaluator:            newEvaluator(mgmt),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+	}
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(BasePath).HandlerFunc(h.query)
+	return root
+}
+
+// query writes the answer to the query of the body. The caller's own permissions are evaluated unless the query sets
+// another user, which requires being allowed to get that user.
+func (h *handler) query(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	var q Query
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&q); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid query")
+		return
+	}
+	if err := q.validate(); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if q.UserID == "" {
+		q.UserID = caller.GetName()
+	}
+	if q.UserID != caller.GetName() {
+		allowed, err := h.authorize(r.Context(), caller, "get", v3.UserResourceName, q.UserID)
+		if err != nil {
+			logrus.Errorf("[effectivepermissions] failed to authorize user %s: %v", caller.GetName(), err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+			return
+		}
+		if !allowed {
+			util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to query the permissions of user %s", q.UserID))
+			return
+		}
+	}
+
+	result, err := h.evaluate(q)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("user %s not found", q.UserID))
+			return
+		}
+		logrus.Errorf("[effectivepermissions] failed to evaluate the permissions of user %s: %v", q.UserID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to evaluate the permissions")
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, result)
+}
+
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, verb, resource, name string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    v3.SchemeGroupVersion.Group,
+		Resource: resource,
+		Name:     name,
+		Verb:     verb,
+	})
+}
@@ -0,0 +1,294 @@
+// Package emailverification verifies the email addresses of the local users with signed links sent to them. When
+// email-verification-required is set, the users created with an email address can't log in until they verify it, and
+// only the verified addresses are used to send emails to the users, so that mistyped or spoofed addresses don't
+// receive their password reset links and notifications.
+package emailverification
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// PendingAnnotation is set to "true" on the users who must verify their email address before logging in.
+	PendingAnnotation = "auth.cattle.io/email-verification-pending"
+	// VerifiedAnnotation holds the last email address the user verified.
+	VerifiedAnnotation = "auth.cattle.io/email-verified"
+	// SigningKeySecretName is the name of the secret holding the key signing the tokens.
+	SigningKeySecretName = "email-verification-signing-key"
+
+	signingKeyKey = "key"
+	keyLen        = 32
+	// resendInterval is how long after sending a link to a user another one can be sent.
+	resendInterval = 5 * time.Minute
+	// linkPath is the path of the page of the UI verifying the email address with the token.
+	linkPath = "/dashboard/auth/verify-email"
+)
+
+var (
+	// PendingErrorCode is the code of the login errors of the users who haven't verified their email address.
+	PendingErrorCode = httperror.ErrorCode{Code: "EmailVerificationPending", Status: 403}
+
+	// ErrInvalidToken is returned for the tokens which aren't signed by Rancher, have expired, or are for another
+	// email address than the current one of their user.
+	ErrInvalidToken = errors.New("invalid or expired email verification token")
+)
+
+// Required tells whether the email addresses of the new local users must be verified.
+func Required() bool {
+	return settings.EmailVerificationRequired.Get() == "true"
+}
+
+// Pending tells whether the user must verify their email address before logging in.
+func Pending(user *v3.User) bool {
+	return Required() && user.Annotations[PendingAnnotation] == "true"
+}
+
+// Address returns the email address to send the emails of the user to, empty if they don't have one or it must be
+// verified and isn't.
+func Address(user *v3.User) string {
+	if Required() && user.Annotations[VerifiedAnnotation] != user.Email {
+		return ""
+	}
+	return user.Email
+}
+
+// Mailer sends the emails with the links.
+type Mailer interface {
+	SendMessage(to, name string, data interface{}) error
+}
+
+// Manager sends the verification links to the users and verifies their tokens.
+type Manager struct {
+	users   mgmtcontrollers.UserClient
+	secrets wcorev1.SecretClient
+	mailer  Mailer
+	now     func() time.Time
+	async   func(func())
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+
+	keyMu sync.Mutex
+	key   []byte
+}
+
+// NewManager returns a Manager sending the emails with the SMTP server of the settings.
+func NewManager(mgmt *config.ScaledContext) *Manager {
+	return newManager(
+		mgmt.Wrangler.Mgmt.User(),
+		mgmt.Wrangler.Core.Secret(),
+		mail.NewSender(mgmt.Core.Secrets("").Controller().Lister()),
+	)
+}
+
+func newManager(users mgmtcontrollers.UserClient, secrets wcorev1.SecretClient, mailer Mailer) *Manager {
+	return &Manager{
+		users:   users,
+		secrets: secrets,
+		mailer:  mailer,
+		now:     time.Now,
+		async:   func(f func()) { go f() },
+		sent:    map[string]time.Time{},
+	}
+}
+
+// Send sends a verification link to the email address of the user, unless it's already verified or a link was sent
+// to them recently.
+func (m *Manager) Send(userID string) {
+	if !m.throttle(userID) {
+		return
+	}
+	m.async(func() {
+		if err := m.send(userID); err != nil {
+			logrus.Errorf("[emailverification] failed to send a verification link to user %s: %v", userID, err)
+		}
+	})
+}
+
+// throttle records a link sent to the user, and tells whether the last one was sent long enough ago.
+func (m *Manager) throttle(userID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if last, ok := m.sent[userID]; ok && now.Sub(last) < resendInterval {
+		return false
+	}
+	for id, last := range m.sent {
+		if now.Sub(last) >= resendInterval {
+			delete(m.sent, id)
+		}
+	}
+	m.sent[userID] = now
+	return true
+}
+
+func (m *Manager) send(userID string) error {
+	user, err := m.users.Get(userID, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if user.Email == "" || user.Annotations[VerifiedAnnotation] == user.Email {
+		return nil
+	}
+	token, expiresAt, err := m.issue(user)
+	if err != nil {
+		return err
+	}
+	username := user.Username
+	if username == "" {
+		username = user.Name
+	}
+	return m.mailer.SendMessage(user.Email, mail.EmailVerificationMessage, mail.EmailVerificationData{
+		Username:  username,
+		Link:      strings.TrimRight(settings.ServerURL.Get(), "/") + linkPath + "?token=" + url.QueryEscape(token),
+		ExpiresAt: expiresAt.UTC().Format(time.RFC1123),
+	})
+}
+
+// CheckLogin returns an error for the logins of the users who must verify their email address, and sends them a new
+// link. It must only be called once the user is authenticated.
+func (m *Manager) CheckLogin(user *v3.User) error {
+	if !Pending(user) {
+		return nil
+	}
+	m.Send(user.Name)
+	return httperror.NewAPIError(PendingErrorCode, "the email address of the user must be verified, a verification link was sent to it")
+}
+
+// Verify marks the email address of the token as verified for its user.
+func (m *Manager) Verify(token string) error {
+	userID, email, err := m.verify(token)
+	if err != nil {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		user, err := m.users.Get(userID, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		if user.Email != email {
+			return ErrInvalidToken
+		}
+		if user.Annotations[VerifiedAnnotation] == email && user.Annotations[PendingAnnotation] == "" {
+			return nil
+		}
+		user = user.DeepCopy()
+		if user.Annotations == nil {
+			user.Annotations = map[string]string{}
+		}
+		user.Annotations[VerifiedAnnotation] = email
+		delete(user.Annotations, PendingAnnotation)
+		if _, err := m.users.Update(user); err != nil {
+			return err
+		}
+		logrus.Infof("[emailverification] user %s verified their email address", userID)
+		return nil
+	})
+}
+
+// issue signs a token for the user and their email address.
+func (m *Manager) issue(user *v3.User) (string, time.Time, error) {
+	key, err := m.signingKey()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := m.now().Add(time.Duration(settings.EmailVerificationTokenTTLHours.GetInt()) * time.Hour).Truncate(time.Second)
+	payload := strings.Join([]string{user.Name, user.Email, strconv.FormatInt(expiresAt.Unix(), 10)}, "\n")
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + sign(key, payload), expiresAt, nil
+}
+
+// verify checks the signature and the expiry of the token, and returns its user and email address.
+func (m *Manager) verify(token string) (string, string, error) {
+	encodedPayload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", ErrInvalidToken
+	}
+	rawPayload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+	payload := string(rawPayload)
+	key, err := m.signingKey()
+	if err != nil {
+		return "", "", err
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(key, payload))) {
+		return "", "", ErrInvalidToken
+	}
+
+	parts := strings.Split(payload, "\n")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", ErrInvalidToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || !m.now().Before(time.Unix(expiresAt, 0)) {
+		return "", "", ErrInvalidToken
+	}
+	return parts[0], parts[1], nil
+}
+
+// signingKey returns the key signing the tokens, generating it on first use.
+func (m *Manager) signingKey() ([]byte, error) {
+	m.keyMu.Lock()
+	defer m.keyMu.Unlock()
+	if m.key != nil {
+		return m.key, nil
+	}
+
+	secret, err := m.secrets.Get(tokens.SecretNamespace, SigningKeySecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		key := make([]byte, keyLen)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generating the email verification signing key: %w", err)
+		}
+		secret, err = m.secrets.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: SigningKeySecretName, Namespace: tokens.SecretNamespace},
+			Data:       map[string][]byte{signingKeyKey: key},
+		})
+		if apierrors.IsAlreadyExists(err) {
+			// Another server generated it first.
+			secret, err = m.secrets.Get(tokens.SecretNamespace, SigningKeySecretName, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting the email verification signing key: %w", err)
+	}
+	if len(secret.Data[signingKeyKey]) < keyLen {
+		return nil, fmt.Errorf("the email verification signing key of secret %s/%s is too short", tokens.SecretNamespace, SigningKeySecretName)
+	}
+	m.key = secret.Data[signingKeyKey]
+	return m.key, nil
+}
+
+func sign(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
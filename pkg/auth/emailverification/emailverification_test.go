@@ -0,0 +1,193 @@
+package emailverification
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeMailer struct {
+	to   []string
+	data []mail.EmailVerificationData
+}
+
+func (f *fakeMailer) SendMessage(to, name string, data interface{}) error {
+	f.to = append(f.to, to)
+	f.data = append(f.data, data.(mail.EmailVerificationData))
+	return nil
+}
+
+var linkToken = regexp.MustCompile(`\?token=(\S+)$`)
+
+// token returns the token of the last link sent.
+func (f *fakeMailer) token(t *testing.T) string {
+	require.NotEmpty(t, f.data)
+	match := linkToken.FindStringSubmatch(f.data[len(f.data)-1].Link)
+	require.Len(t, match, 2)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	return token
+}
+
+func newTestManager(t *testing.T, now *time.Time, user *v3.User) (*Manager, *fakeMailer, map[string]*v3.User) {
+	ctrl := gomock.NewController(t)
+	users := map[string]*v3.User{user.Name: user}
+	userClient := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+	userClient.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, _ metav1.GetOptions) (*v3.User, error) {
+		if u, ok := users[name]; ok {
+			return u.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "users"}, name)
+	}).AnyTimes()
+	userClient.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *v3.User) (*v3.User, error) {
+		users[u.Name] = u.DeepCopy()
+		return u, nil
+	}).AnyTimes()
+
+	stored := map[string]*corev1.Secret{}
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		stored[secret.Name] = secret.DeepCopy()
+		return secret, nil
+	}).AnyTimes()
+
+	mailer := &fakeMailer{}
+	m := newManager(userClient, secrets, mailer)
+	m.now = func() time.Time { return *now }
+	m.async = func(f func()) { f() }
+	return m, mailer, users
+}
+
+func requireVerification(t *testing.T) {
+	require.NoError(t, settings.EmailVerificationRequired.Set("true"))
+	require.NoError(t, settings.ServerURL.Set("https://rancher.example.com"))
+	t.Cleanup(func() {
+		settings.EmailVerificationRequired.Set(settings.EmailVerificationRequired.Default)
+		settings.ServerURL.Set(settings.ServerURL.Default)
+	})
+}
+
+func pendingUser() *v3.User {
+	return &v3.User{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "u-abc",
+			Annotations: map[string]string{PendingAnnotation: "true"},
+		},
+		Username: "alice",
+		Email:    "alice@example.com",
+	}
+}
+
+func TestAddress(t *testing.T) {
+	user := pendingUser()
+	assert.Equal(t, "alice@example.com", Address(user))
+	assert.False(t, Pending(user))
+
+	requireVerification(t)
+	assert.Empty(t, Address(user))
+	assert.True(t, Pending(user))
+
+	user.Annotations[VerifiedAnnotation] = "alice@example.com"
+	assert.Equal(t, "alice@example.com", Address(user))
+	user.Email = "alice@example.org"
+	assert.Empty(t, Address(user))
+}
+
+func TestSendAndVerify(t *testing.T) {
+	requireVerification(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, mailer, users := newTestManager(t, &now, pendingUser())
+
+	err := m.CheckLogin(users["u-abc"])
+	var apiErr *httperror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, PendingErrorCode, apiErr.Code)
+	require.Len(t, mailer.to, 1)
+	assert.Equal(t, "alice@example.com", mailer.to[0])
+	assert.Equal(t, "alice", mailer.data[0].Username)
+	assert.Equal(t, "Thu, 02 May 2024 12:00:00 UTC", mailer.data[0].ExpiresAt)
+	assert.Contains(t, mailer.data[0].Link, "https://rancher.example.com/dashboard/auth/verify-email?token=")
+
+	// The links aren't sent again right away.
+	m.CheckLogin(users["u-abc"])
+	assert.Len(t, mailer.to, 1)
+
+	require.NoError(t, m.Verify(mailer.token(t)))
+	assert.Equal(t, "alice@example.com", users["u-abc"].Annotations[VerifiedAnnotation])
+	assert.NotContains(t, users["u-abc"].Annotations, PendingAnnotation)
+	assert.NoError(t, m.CheckLogin(users["u-abc"]))
+	assert.NoError(t, m.Verify(mailer.token(t)))
+
+	// Verified addresses aren't sent links.
+	now = now.Add(time.Hour)
+	m.Send("u-abc")
+	assert.Len(t, mailer.to, 1)
+}
+
+func TestInvalidTokens(t *testing.T) {
+	requireVerification(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, mailer, users := newTestManager(t, &now, pendingUser())
+	m.Send("u-abc")
+	token := mailer.token(t)
+
+	assert.ErrorIs(t, m.Verify("garbage"), ErrInvalidToken)
+	payload, signature, _ := strings.Cut(token, ".")
+	assert.ErrorIs(t, m.Verify(payload+"."+strings.Repeat("A", len(signature))), ErrInvalidToken)
+
+	// The tokens are for the address they were sent to.
+	users["u-abc"].Email = "alice@example.org"
+	assert.ErrorIs(t, m.Verify(token), ErrInvalidToken)
+	users["u-abc"].Email = "alice@example.com"
+
+	now = now.Add(24 * time.Hour)
+	assert.ErrorIs(t, m.Verify(token), ErrInvalidToken)
+	assert.Equal(t, "true", users["u-abc"].Annotations[PendingAnnotation])
+}
+
+func TestHandler(t *testing.T) {
+	requireVerification(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, mailer, users := newTestManager(t, &now, pendingUser())
+	m.Send("u-abc")
+	h := &handler{manager: m}
+
+	post := func(body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, BasePath, bytes.NewBufferString(body)))
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusBadRequest, post(`{}`))
+	assert.Equal(t, http.StatusBadRequest, post(`{"token":"garbage"}`))
+	assert.Equal(t, http.StatusNoContent, post(`{"token":"`+mailer.token(t)+`"}`))
+	assert.Equal(t, "alice@example.com", users["u-abc"].Annotations[VerifiedAnnotation])
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, BasePath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
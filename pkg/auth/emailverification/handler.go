@@ -0,0 +1,55 @@
+package emailverification
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BasePath is the path of the unauthenticated endpoint verifying the email addresses with the tokens of the links.
+	BasePath = "/v1-email-verification"
+
+	maxBodySize = 4 * 1024
+)
+
+type verifyInput struct {
+	Token string `json:"token"`
+}
+
+type handler struct {
+	manager *Manager
+}
+
+// NewHandler returns the unauthenticated handler verifying the email addresses.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	return &handler{manager: NewManager(mgmt)}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.ReturnHTTPError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var input verifyInput
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&input); err != nil || input.Token == "" {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	err := h.manager.Verify(input.Token)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, ErrInvalidToken):
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		logrus.Errorf("[emailverification] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to verify the email address")
+	}
+}
@@ -0,0 +1,49 @@
+package externalsecrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// awsSecretsManagerFetcher reads AWS Secrets Manager secrets with the default credential chain.
+// The path of references is the name or ARN of the secret; the region is taken from ARNs when present.
+type awsSecretsManagerFetcher struct{}
+
+func (f *awsSecretsManagerFetcher) Fetch(ctx context.Context, ref Reference) (string, error) {
+	config := aws.NewConfig()
+	if region := arnRegion(ref.Path); region != "" {
+		config = config.WithRegion(region)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create an AWS session: %w", err)
+	}
+
+	output, err := secretsmanager.New(sess).GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(ref.Path),
+	})
+	if err != nil {
+		return "", err
+	}
+	if output.SecretString != nil {
+		return *output.SecretString, nil
+	}
+	return string(output.SecretBinary), nil
+}
+
+// arnRegion returns the region of a secret ARN, e.g. arn:aws:secretsmanager:us-east-1:123456789012:secret:name.
+func arnRegion(id string) string {
+	parts := strings.SplitN(id, ":", 5)
+	if len(parts) < 5 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[3]
+}
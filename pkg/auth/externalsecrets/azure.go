@@ -0,0 +1,108 @@
+package externalsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+const (
+	keyVaultScope      = "https://vault.azure.net/.default"
+	keyVaultAPIVersion = "7.4"
+)
+
+// azureKeyVaultFetcher reads Azure Key Vault secrets with the default Azure credential chain.
+// The path of references is <vault>/<secret>[/<version>], where the vault is either its name
+// or its host name for clouds other than the public one.
+type azureKeyVaultFetcher struct {
+	client        *http.Client
+	newCredential func() (azcore.TokenCredential, error)
+
+	mu         sync.Mutex
+	credential azcore.TokenCredential
+}
+
+func newAzureKeyVaultFetcher() *azureKeyVaultFetcher {
+	return &azureKeyVaultFetcher{
+		client: http.DefaultClient,
+		newCredential: func() (azcore.TokenCredential, error) {
+			return azidentity.NewDefaultAzureCredential(nil)
+		},
+	}
+}
+
+func (f *azureKeyVaultFetcher) Fetch(ctx context.Context, ref Reference) (string, error) {
+	vault, secret, ok := strings.Cut(ref.Path, "/")
+	if !ok || vault == "" || secret == "" {
+		return "", fmt.Errorf("azure key vault references must have the form <vault>/<secret>[/<version>]")
+	}
+	host := vault
+	if !strings.Contains(host, ".") {
+		host += ".vault.azure.net"
+	}
+
+	credential, err := f.getCredential()
+	if err != nil {
+		return "", err
+	}
+	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{keyVaultScope}})
+	if err != nil {
+		return "", fmt.Errorf("failed to get a token for azure key vault: %w", err)
+	}
+
+	u := url.URL{
+		Scheme:   "https",
+		Host:     host,
+		Path:     "/secrets/" + secret,
+		RawQuery: url.Values{"api-version": {keyVaultAPIVersion}}.Encode(),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("azure key vault returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var bundle struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &bundle); err != nil {
+		return "", fmt.Errorf("failed to decode the azure key vault secret: %w", err)
+	}
+	return bundle.Value, nil
+}
+
+func (f *azureKeyVaultFetcher) getCredential() (azcore.TokenCredential, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.credential != nil {
+		return f.credential, nil
+	}
+	credential, err := f.newCredential()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find azure credentials: %w", err)
+	}
+	f.credential = credential
+	return credential, nil
+}
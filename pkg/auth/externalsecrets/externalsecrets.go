@@ -0,0 +1,179 @@
+// Package externalsecrets resolves references to secrets kept in external stores, so that credentials of auth
+// providers can be supplied as references instead of being stored in Rancher secrets.
+//
+// References have the form <store>://<path>[#<key>]:
+//
+//	vault://secret/data/rancher/ldap#password         a field of a HashiCorp Vault KV secret
+//	awssm://rancher/ldap#password                     an AWS Secrets Manager secret, a field of it if it's JSON
+//	azurekv://my-vault/ldap-password[/version]        an Azure Key Vault secret, a field of it if it's JSON
+//
+// Stores are accessed with the credentials of the Rancher server, found the way their SDKs usually do:
+// VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE for Vault, the default credential chains for AWS and Azure.
+package externalsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	VaultStore             = "vault"
+	AWSSecretsManagerStore = "awssm"
+	AzureKeyVaultStore     = "azurekv"
+
+	fetchTimeout = 30 * time.Second
+)
+
+// Reference is a reference to a secret in an external store.
+type Reference struct {
+	Store string
+	Path  string
+	Key   string
+}
+
+func (r Reference) String() string {
+	s := r.Store + "://" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// Parse parses a reference to a secret in an external store.
+// It returns false if the value isn't a reference to a known store.
+func Parse(value string) (Reference, bool) {
+	store, rest, ok := strings.Cut(value, "://")
+	if !ok {
+		return Reference{}, false
+	}
+	switch store {
+	case VaultStore, AWSSecretsManagerStore, AzureKeyVaultStore:
+	default:
+		return Reference{}, false
+	}
+	path, key, _ := strings.Cut(rest, "#")
+	if path == "" {
+		return Reference{}, false
+	}
+	return Reference{Store: store, Path: path, Key: key}, true
+}
+
+// IsReference returns whether a value is a reference to a secret in an external store.
+func IsReference(value string) bool {
+	_, ok := Parse(value)
+	return ok
+}
+
+// Fetcher fetches secrets from an external store.
+type Fetcher interface {
+	Fetch(ctx context.Context, ref Reference) (string, error)
+}
+
+type cachedValue struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Resolver resolves references to secrets, caching their values.
+type Resolver struct {
+	fetchers map[string]Fetcher
+	ttl      func() time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedValue
+}
+
+// NewResolver returns a Resolver fetching secrets with the fetcher of their store.
+func NewResolver(fetchers map[string]Fetcher) *Resolver {
+	return &Resolver{
+		fetchers: fetchers,
+		ttl: func() time.Duration {
+			return time.Duration(settings.AuthExternalSecretCacheTTLSeconds.GetInt()) * time.Second
+		},
+		now:   time.Now,
+		cache: map[string]cachedValue{},
+	}
+}
+
+var defaultResolver = NewResolver(map[string]Fetcher{
+	VaultStore:             newVaultFetcher(),
+	AWSSecretsManagerStore: &awsSecretsManagerFetcher{},
+	AzureKeyVaultStore:     newAzureKeyVaultFetcher(),
+})
+
+// Resolve returns the value of the secret a reference points to.
+func Resolve(value string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	return defaultResolver.Resolve(ctx, value)
+}
+
+// Resolve returns the value of the secret a reference points to, from the cache while it's fresh.
+// If the store can't be reached, the last known value is returned until the store is back.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	ref, ok := Parse(value)
+	if !ok {
+		return "", fmt.Errorf("invalid external secret reference %q", value)
+	}
+	fetcher, ok := r.fetchers[ref.Store]
+	if !ok {
+		return "", fmt.Errorf("unsupported external secret store %q", ref.Store)
+	}
+
+	now := r.now()
+	r.mu.Lock()
+	cached, found := r.cache[value]
+	r.mu.Unlock()
+	if found && now.Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	secret, err := fetcher.Fetch(ctx, ref)
+	if err == nil && ref.Key != "" && ref.Store != VaultStore {
+		secret, err = jsonField(secret, ref.Key)
+	}
+	if err != nil {
+		if found {
+			logrus.Warnf("[externalsecrets] failed to refresh %s, using the cached value: %v", ref, err)
+			return cached.value, nil
+		}
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	r.mu.Lock()
+	r.cache[value] = cachedValue{value: secret, expiresAt: now.Add(r.ttl())}
+	r.mu.Unlock()
+	return secret, nil
+}
+
+// jsonField returns a field of a secret holding a JSON object.
+func jsonField(secret, key string) (string, error) {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret isn't a JSON object, can't read key %s: %w", key, err)
+	}
+	return fieldValue(fields, key)
+}
+
+func fieldValue(fields map[string]any, key string) (string, error) {
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret", key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
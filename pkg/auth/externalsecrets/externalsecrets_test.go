@@ -0,0 +1,139 @@
+package externalsecrets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		ref   Reference
+		ok    bool
+	}{
+		{value: "vault://secret/data/rancher/ldap#password", ref: Reference{Store: "vault", Path: "secret/data/rancher/ldap", Key: "password"}, ok: true},
+		{value: "awssm://arn:aws:secretsmanager:us-east-1:123456789012:secret:ldap", ref: Reference{Store: "awssm", Path: "arn:aws:secretsmanager:us-east-1:123456789012:secret:ldap"}, ok: true},
+		{value: "azurekv://my-vault/ldap-password/1234#password", ref: Reference{Store: "azurekv", Path: "my-vault/ldap-password/1234", Key: "password"}, ok: true},
+		{value: "cattle-global-data:openldapconfig-serviceaccountpassword"},
+		{value: "https://example.com/secret"},
+		{value: "vault://#password"},
+		{value: "password"},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			ref, ok := Parse(test.value)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.ref, ref)
+			if ok {
+				assert.Equal(t, test.value, ref.String())
+			}
+		})
+	}
+}
+
+type fakeFetcher struct {
+	value string
+	err   error
+	calls int
+}
+
+func (f *fakeFetcher) Fetch(context.Context, Reference) (string, error) {
+	f.calls++
+	return f.value, f.err
+}
+
+func TestResolve(t *testing.T) {
+	fetcher := &fakeFetcher{value: `{"password":"secret","port":636}`}
+	resolver := NewResolver(map[string]Fetcher{AWSSecretsManagerStore: fetcher})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	resolver.now = func() time.Time { return now }
+	resolver.ttl = func() time.Duration { return time.Minute }
+
+	value, err := resolver.Resolve(context.Background(), "awssm://rancher/ldap#password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", value)
+
+	value, err = resolver.Resolve(context.Background(), "awssm://rancher/ldap#port")
+	require.NoError(t, err)
+	assert.Equal(t, "636", value)
+	assert.Equal(t, 2, fetcher.calls)
+
+	// Values are cached until they expire.
+	fetcher.value = `{"password":"rotated"}`
+	value, err = resolver.Resolve(context.Background(), "awssm://rancher/ldap#password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", value)
+	assert.Equal(t, 2, fetcher.calls)
+
+	now = now.Add(time.Minute)
+	value, err = resolver.Resolve(context.Background(), "awssm://rancher/ldap#password")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", value)
+
+	// The last known value is used while the store is unreachable.
+	now = now.Add(time.Minute)
+	fetcher.err = errors.New("unreachable")
+	value, err = resolver.Resolve(context.Background(), "awssm://rancher/ldap#password")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", value)
+
+	_, err = resolver.Resolve(context.Background(), "awssm://rancher/other")
+	assert.ErrorContains(t, err, "unreachable")
+
+	_, err = resolver.Resolve(context.Background(), "vault://secret/data/ldap#password")
+	assert.ErrorContains(t, err, "unsupported")
+}
+
+func TestVaultFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" || r.Header.Get("X-Vault-Namespace") != "rancher" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/ldap":
+			w.Write([]byte(`{"data":{"data":{"password":"v2-secret"},"metadata":{"version":3}}}`))
+		case "/v1/kv/ldap":
+			w.Write([]byte(`{"data":{"password":"v1-secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := &vaultFetcher{
+		client:  server.Client(),
+		address: func() string { return server.URL },
+		token:   func() string { return "token" },
+		ns:      func() string { return "rancher" },
+	}
+
+	value, err := fetcher.Fetch(context.Background(), Reference{Store: VaultStore, Path: "secret/data/ldap", Key: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2-secret", value)
+
+	value, err = fetcher.Fetch(context.Background(), Reference{Store: VaultStore, Path: "kv/ldap", Key: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1-secret", value)
+
+	_, err = fetcher.Fetch(context.Background(), Reference{Store: VaultStore, Path: "kv/ldap", Key: "user"})
+	assert.ErrorContains(t, err, "key user not found")
+
+	_, err = fetcher.Fetch(context.Background(), Reference{Store: VaultStore, Path: "kv/missing", Key: "password"})
+	assert.ErrorContains(t, err, "404")
+
+	_, err = fetcher.Fetch(context.Background(), Reference{Store: VaultStore, Path: "kv/ldap"})
+	assert.Error(t, err)
+}
+
+func TestARNRegion(t *testing.T) {
+	assert.Equal(t, "eu-west-1", arnRegion("arn:aws:secretsmanager:eu-west-1:123456789012:secret:ldap-AbCdEf"))
+	assert.Empty(t, arnRegion("rancher/ldap"))
+}
@@ -0,0 +1,81 @@
+package externalsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// vaultFetcher reads fields of HashiCorp Vault KV secrets through the HTTP API.
+// The path of references is the API path of the secret, e.g. secret/data/rancher/ldap for a KV v2 engine.
+type vaultFetcher struct {
+	client  *http.Client
+	address func() string
+	token   func() string
+	ns      func() string
+}
+
+func newVaultFetcher() *vaultFetcher {
+	return &vaultFetcher{
+		client:  http.DefaultClient,
+		address: func() string { return os.Getenv("VAULT_ADDR") },
+		token:   func() string { return os.Getenv("VAULT_TOKEN") },
+		ns:      func() string { return os.Getenv("VAULT_NAMESPACE") },
+	}
+}
+
+func (f *vaultFetcher) Fetch(ctx context.Context, ref Reference) (string, error) {
+	if ref.Key == "" {
+		return "", fmt.Errorf("vault references must name the key of the secret")
+	}
+	address := f.address()
+	if address == "" {
+		return "", fmt.Errorf("VAULT_ADDR isn't set")
+	}
+	u, err := url.JoinPath(address, "v1", ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", f.token())
+	if ns := f.ns(); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode the vault secret: %w", err)
+	}
+	fields := secret.Data
+	// KV v2 engines nest the fields of the secret next to its metadata.
+	if nested, ok := fields["data"].(map[string]any); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = nested
+		}
+	}
+	return fieldValue(fields, ref.Key)
+}
@@ -0,0 +1,242 @@
+// Package failedlogins tells the users at their next successful login how many logins to their account failed since
+// their previous one, and where the last failure came from, as many compliance frameworks require. The failures are
+// counted in a secret per user, once the account of the failed login is known: the user whose second factor was
+// wrong or who is disabled, or else the users who logged in before with the username of the failed login. The users
+// can also be emailed the failures, with failed-logins in email-notifications.
+package failedlogins
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/notifications"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	secretPrefix = "failed-logins-"
+	summaryKey   = "summary"
+)
+
+// SecretName returns the name of the secret holding the failed logins of the user since their last successful login.
+func SecretName(userID string) string {
+	return secretPrefix + userID
+}
+
+// Summary is the failed logins to an account since its last successful login.
+type Summary struct {
+	Count    int       `json:"count"`
+	LastTime time.Time `json:"lastTime"`
+	// LastSource is the client address of the last failed login, if known.
+	LastSource string `json:"lastSource,omitempty"`
+	// Message describes the failed logins to the user.
+	Message string `json:"message,omitempty"`
+}
+
+// message returns the sentence describing the failed logins, e.g. "There were 3 failed login attempts since your last
+// login, last from IP 10.0.0.1".
+func (s *Summary) message() string {
+	attempts := "There were %d failed login attempts"
+	if s.Count == 1 {
+		attempts = "There was %d failed login attempt"
+	}
+	message := fmt.Sprintf(attempts+" since your last login", s.Count)
+	if s.LastSource != "" {
+		message += ", last from IP " + s.LastSource
+	}
+	return message
+}
+
+// Tracker counts the failed logins of the users. A nil Tracker counts none.
+type Tracker struct {
+	users          mgmtcontrollers.UserCache
+	userAttributes mgmtcontrollers.UserAttributeCache
+	secrets        wcorev1.SecretClient
+	notifier       *notifications.Notifier
+	now            func() time.Time
+	async          func(func())
+}
+
+// NewTracker returns a Tracker emailing the failed logins with the notifier.
+func NewTracker(mgmt *config.ScaledContext, notifier *notifications.Notifier) *Tracker {
+	return newTracker(
+		mgmt.Wrangler.Mgmt.User().Cache(),
+		mgmt.Wrangler.Mgmt.UserAttribute().Cache(),
+		mgmt.Wrangler.Core.Secret(),
+		notifier,
+	)
+}
+
+func newTracker(users mgmtcontrollers.UserCache, userAttributes mgmtcontrollers.UserAttributeCache, secrets wcorev1.SecretClient, notifier *notifications.Notifier) *Tracker {
+	return &Tracker{
+		users:          users,
+		userAttributes: userAttributes,
+		secrets:        secrets,
+		notifier:       notifier,
+		now:            time.Now,
+		async:          func(f func()) { go f() },
+	}
+}
+
+// Fail counts a failed login to the account of the user, or, when the user isn't known, to the accounts of the users
+// who logged in to the provider with the username before. It doesn't block.
+func (t *Tracker) Fail(provider, username, userID string, source net.IP) {
+	if t == nil || (userID == "" && username == "") {
+		return
+	}
+	at := t.now()
+	t.async(func() {
+		userIDs := []string{userID}
+		if userID == "" {
+			var err error
+			if userIDs, err = t.usersByUsername(provider, username); err != nil {
+				logrus.Errorf("[failedlogins] failed to find the users of username %s of provider %s: %v", username, provider, err)
+				return
+			}
+		}
+		for _, id := range userIDs {
+			if err := t.fail(id, at, source); err != nil {
+				logrus.Errorf("[failedlogins] failed to count a failed login of user %s: %v", id, err)
+			}
+		}
+	})
+}
+
+// usersByUsername returns the users who logged in to the provider with the username, ignoring the case.
+func (t *Tracker) usersByUsername(provider, username string) ([]string, error) {
+	attributes, err := t.userAttributes.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []string
+	for _, attribute := range attributes {
+		for _, name := range attribute.ExtraByProvider[provider][common.UserAttributeUserName] {
+			if strings.EqualFold(name, username) {
+				userIDs = append(userIDs, attribute.Name)
+				break
+			}
+		}
+	}
+	return userIDs, nil
+}
+
+func (t *Tracker) fail(userID string, at time.Time, source net.IP) error {
+	user, err := t.users.Get(userID)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := t.secrets.Get(tokens.SecretNamespace, SecretName(userID), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("getting the failed logins: %w", err)
+		}
+		first := apierrors.IsNotFound(err)
+
+		summary := &Summary{}
+		if !first {
+			if summary, err = parseSummary(secret); err != nil {
+				logrus.Warnf("[failedlogins] resetting the invalid failed logins of user %s: %v", userID, err)
+				summary = &Summary{}
+			}
+		}
+		summary.Count++
+		if at.After(summary.LastTime) {
+			summary.LastTime = at
+			summary.LastSource = ""
+			if source != nil {
+				summary.LastSource = source.String()
+			}
+		}
+
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		if first {
+			_, err = t.secrets.Create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      SecretName(userID),
+					Namespace: tokens.SecretNamespace,
+					Labels:    map[string]string{tokens.UserIDLabel: userID},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: v3.UserGroupVersionKind.GroupVersion().String(),
+						Kind:       v3.UserGroupVersionKind.Kind,
+						Name:       user.Name,
+						UID:        user.UID,
+					}},
+				},
+				Data: map[string][]byte{summaryKey: data},
+			})
+			if apierrors.IsAlreadyExists(err) {
+				// Another failed login of the user created it first.
+				return apierrors.NewConflict(corev1.Resource("secrets"), SecretName(userID), err)
+			}
+			return err
+		}
+		secret = secret.DeepCopy()
+		secret.Data = map[string][]byte{summaryKey: data}
+		_, err = t.secrets.Update(secret)
+		return err
+	})
+}
+
+// Succeed returns the failed logins to the account of the user since their last successful login, nil if there were
+// none, and forgets them. The user is emailed about them, if it's enabled.
+func (t *Tracker) Succeed(userID string) *Summary {
+	if t == nil {
+		return nil
+	}
+	secret, err := t.secrets.Get(tokens.SecretNamespace, SecretName(userID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		logrus.Errorf("[failedlogins] failed to get the failed logins of user %s: %v", userID, err)
+		return nil
+	}
+	summary, err := parseSummary(secret)
+	if err != nil {
+		logrus.Warnf("[failedlogins] ignoring the invalid failed logins of user %s: %v", userID, err)
+	}
+
+	// The failures counted since the secret was read are kept for the next login.
+	err = t.secrets.Delete(tokens.SecretNamespace, SecretName(userID), &metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &secret.ResourceVersion},
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		logrus.Errorf("[failedlogins] failed to reset the failed logins of user %s: %v", userID, err)
+	}
+	if summary == nil || summary.Count == 0 {
+		return nil
+	}
+
+	summary.Message = summary.message()
+	t.notifier.FailedLogins(userID, summary.Count, summary.LastTime, net.ParseIP(summary.LastSource))
+	return summary
+}
+
+func parseSummary(secret *corev1.Secret) (*Summary, error) {
+	summary := &Summary{}
+	if err := json.Unmarshal(secret.Data[summaryKey], summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -0,0 +1,148 @@
+package failedlogins
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestTracker(t *testing.T, now *time.Time) (*Tracker, map[string]*corev1.Secret) {
+	ctrl := gomock.NewController(t)
+	gr := schema.GroupResource{Resource: "secrets"}
+
+	users := fake.NewMockNonNamespacedCacheInterface[*apiv3.User](ctrl)
+	users.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*apiv3.User, error) {
+		switch name {
+		case "u-alice", "u-bob":
+			return &apiv3.User{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name)}}, nil
+		}
+		return nil, apierrors.NewNotFound(apiv3.Resource("users"), name)
+	}).AnyTimes()
+
+	userAttributes := fake.NewMockNonNamespacedCacheInterface[*apiv3.UserAttribute](ctrl)
+	userAttributes.EXPECT().List(labels.Everything()).Return([]*apiv3.UserAttribute{
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "u-alice"},
+			ExtraByProvider: map[string]map[string][]string{"local": {common.UserAttributeUserName: {"alice"}}},
+		},
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "u-bob"},
+			ExtraByProvider: map[string]map[string][]string{"openldap": {common.UserAttributeUserName: {"alice"}}},
+		},
+	}, nil).AnyTimes()
+
+	stored := map[string]*corev1.Secret{}
+	version := 0
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if _, ok := stored[secret.Name]; ok {
+			return nil, apierrors.NewAlreadyExists(gr, secret.Name)
+		}
+		version++
+		created := secret.DeepCopy()
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if stored[secret.Name].ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Delete(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, options *metav1.DeleteOptions) error {
+		secret, ok := stored[name]
+		if !ok {
+			return apierrors.NewNotFound(gr, name)
+		}
+		if options.Preconditions != nil && *options.Preconditions.ResourceVersion != secret.ResourceVersion {
+			return apierrors.NewConflict(gr, name, nil)
+		}
+		delete(stored, name)
+		return nil
+	}).AnyTimes()
+
+	tracker := newTracker(users, userAttributes, secrets, nil)
+	tracker.now = func() time.Time { return *now }
+	tracker.async = func(f func()) { f() }
+	return tracker, stored
+}
+
+func TestFailAndSucceed(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tracker, stored := newTestTracker(t, &now)
+
+	// There were no failed logins.
+	assert.Nil(t, tracker.Succeed("u-alice"))
+
+	tracker.Fail("local", "", "u-alice", net.ParseIP("10.0.0.1"))
+	now = now.Add(time.Minute)
+	tracker.Fail("local", "", "u-alice", net.ParseIP("10.0.0.2"))
+	require.Contains(t, stored, SecretName("u-alice"))
+	secret := stored[SecretName("u-alice")]
+	assert.Equal(t, "u-alice", secret.Labels[tokens.UserIDLabel])
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, "User", secret.OwnerReferences[0].Kind)
+	assert.Equal(t, "u-alice", secret.OwnerReferences[0].Name)
+
+	summary := tracker.Succeed("u-alice")
+	require.NotNil(t, summary)
+	assert.Equal(t, 2, summary.Count)
+	assert.Equal(t, now, summary.LastTime)
+	assert.Equal(t, "10.0.0.2", summary.LastSource)
+	assert.Equal(t, "There were 2 failed login attempts since your last login, last from IP 10.0.0.2", summary.Message)
+
+	// The failed logins are forgotten once told.
+	assert.NotContains(t, stored, SecretName("u-alice"))
+	assert.Nil(t, tracker.Succeed("u-alice"))
+}
+
+func TestFailByUsername(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tracker, stored := newTestTracker(t, &now)
+
+	// Only the users who logged in to the provider with the username are counted, ignoring the case.
+	tracker.Fail("local", "Alice", "", nil)
+	assert.Contains(t, stored, SecretName("u-alice"))
+	assert.NotContains(t, stored, SecretName("u-bob"))
+
+	summary := tracker.Succeed("u-alice")
+	require.NotNil(t, summary)
+	assert.Equal(t, "There was 1 failed login attempt since your last login", summary.Message)
+
+	// The unknown usernames and users aren't counted.
+	tracker.Fail("local", "mallory", "", nil)
+	tracker.Fail("local", "", "u-deleted", nil)
+	assert.Empty(t, stored)
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *Tracker
+	tracker.Fail("local", "alice", "", nil)
+	assert.Nil(t, tracker.Succeed("u-alice"))
+}
@@ -0,0 +1,228 @@
+package groupdisplaynames
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+// DisplayNameAnnotation holds the display name of the principal of a binding, as shown by the UI and reports.
+const DisplayNameAnnotation = "auth.cattle.io/principal-display-name"
+
+const (
+	kindCRTB = "clusterroletemplatebinding"
+	kindPRTB = "projectroletemplatebinding"
+	kindGRB  = "globalrolebinding"
+)
+
+// Refresher re-resolves the group principals of role bindings with the identity providers they belong to,
+// and updates the display names stored on the bindings when the groups were renamed.
+// Groups of providers which can't look up groups are left untouched.
+type Refresher struct {
+	crtbCache        mgmtcontrollers.ClusterRoleTemplateBindingCache
+	crtbs            mgmtcontrollers.ClusterRoleTemplateBindingClient
+	prtbCache        mgmtcontrollers.ProjectRoleTemplateBindingCache
+	prtbs            mgmtcontrollers.ProjectRoleTemplateBindingClient
+	grbCache         mgmtcontrollers.GlobalRoleBindingCache
+	grbs             mgmtcontrollers.GlobalRoleBindingClient
+	enabledProviders func() []string
+	getGroupLookup   func(string) common.GroupLookup
+}
+
+// New creates a new instance of Refresher.
+func New(wContext *wrangler.Context) *Refresher {
+	return &Refresher{
+		crtbCache:        wContext.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		crtbs:            wContext.Mgmt.ClusterRoleTemplateBinding(),
+		prtbCache:        wContext.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		prtbs:            wContext.Mgmt.ProjectRoleTemplateBinding(),
+		grbCache:         wContext.Mgmt.GlobalRoleBinding().Cache(),
+		grbs:             wContext.Mgmt.GlobalRoleBinding(),
+		enabledProviders: providers.EnabledProviders,
+		getGroupLookup:   providers.GetGroupLookup,
+	}
+}
+
+// binding is a role binding to a group principal.
+type binding struct {
+	kind        string
+	namespace   string
+	name        string
+	principalID string
+	displayName string
+}
+
+// Run the refresh of the display names.
+func (r *Refresher) Run(ctx context.Context) error {
+	if ctx.Err() != nil {
+		logrus.Info("groupdisplaynames: context canceled, quitting")
+		return nil
+	}
+
+	startedAt := time.Now()
+
+	lookups := map[string]common.GroupLookup{}
+	for _, providerName := range r.enabledProviders() {
+		if lookup := r.getGroupLookup(providerName); lookup != nil {
+			lookups[providerName] = lookup
+		}
+	}
+	if len(lookups) == 0 {
+		logrus.Info("groupdisplaynames: nothing to do, no enabled auth provider can look up groups")
+		return nil
+	}
+
+	bindings, err := r.listBindings()
+	if err != nil {
+		return err
+	}
+
+	logrus.Info("groupdisplaynames: started")
+
+	var processed, updated, errCount int
+	// Groups are looked up once per run, however many bindings they have.
+	resolved := map[string]string{}
+	failed := map[string]bool{}
+
+	defer func() {
+		logrus.Infof(
+			"groupdisplaynames: finished in %v seconds (groups %d, bindings processed %d, updated %d, errors %d)",
+			time.Since(startedAt).Seconds(),
+			len(resolved), processed, updated, errCount,
+		)
+	}()
+
+	for _, b := range bindings {
+		if ctx.Err() != nil {
+			logrus.Info("groupdisplaynames: context canceled, quitting")
+			break
+		}
+
+		providerName := providers.PrincipalProvider(b.principalID)
+		if !strings.HasPrefix(b.principalID, providerName+"_group://") {
+			continue
+		}
+		lookup, ok := lookups[providerName]
+		if !ok || failed[b.principalID] {
+			continue
+		}
+
+		displayName, ok := resolved[b.principalID]
+		if !ok {
+			principal, err := lookup.LookupGroup(b.principalID)
+			if err != nil {
+				logrus.Errorf("groupdisplaynames: error looking up group %s: %v", b.principalID, err)
+				failed[b.principalID] = true
+				errCount++
+				continue
+			}
+			displayName = principal.DisplayName
+			resolved[b.principalID] = displayName
+		}
+
+		processed++
+
+		if displayName == "" || displayName == b.displayName {
+			continue
+		}
+
+		logrus.Debugf("groupdisplaynames: updating display name of group %s on %s %s/%s from %q to %q",
+			b.principalID, b.kind, b.namespace, b.name, b.displayName, displayName)
+		if err := r.update(b, displayName); err != nil {
+			logrus.Errorf("groupdisplaynames: error updating %s %s/%s: %v", b.kind, b.namespace, b.name, err)
+			errCount++
+			continue
+		}
+		updated++
+	}
+
+	return nil
+}
+
+// listBindings lists the cluster, project and global role bindings of group principals.
+func (r *Refresher) listBindings() ([]binding, error) {
+	var bindings []binding
+
+	crtbs, err := r.crtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing cluster role template bindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		if crtb.GroupPrincipalName != "" && crtb.DeletionTimestamp == nil {
+			bindings = append(bindings, binding{kindCRTB, crtb.Namespace, crtb.Name, crtb.GroupPrincipalName, crtb.Annotations[DisplayNameAnnotation]})
+		}
+	}
+
+	prtbs, err := r.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing project role template bindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		if prtb.GroupPrincipalName != "" && prtb.DeletionTimestamp == nil {
+			bindings = append(bindings, binding{kindPRTB, prtb.Namespace, prtb.Name, prtb.GroupPrincipalName, prtb.Annotations[DisplayNameAnnotation]})
+		}
+	}
+
+	grbs, err := r.grbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing global role bindings: %w", err)
+	}
+	for _, grb := range grbs {
+		if grb.GroupPrincipalName != "" && grb.DeletionTimestamp == nil {
+			bindings = append(bindings, binding{kindGRB, "", grb.Name, grb.GroupPrincipalName, grb.Annotations[DisplayNameAnnotation]})
+		}
+	}
+
+	return bindings, nil
+}
+
+// update sets the display name on the latest version of the binding.
+func (r *Refresher) update(b binding, displayName string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		switch b.kind {
+		case kindCRTB:
+			var crtb *v3.ClusterRoleTemplateBinding
+			if crtb, err = r.crtbs.Get(b.namespace, b.name, metav1.GetOptions{}); err == nil {
+				crtb.Annotations = withDisplayName(crtb.Annotations, displayName)
+				_, err = r.crtbs.Update(crtb)
+			}
+		case kindPRTB:
+			var prtb *v3.ProjectRoleTemplateBinding
+			if prtb, err = r.prtbs.Get(b.namespace, b.name, metav1.GetOptions{}); err == nil {
+				prtb.Annotations = withDisplayName(prtb.Annotations, displayName)
+				_, err = r.prtbs.Update(prtb)
+			}
+		case kindGRB:
+			var grb *v3.GlobalRoleBinding
+			if grb, err = r.grbs.Get(b.name, metav1.GetOptions{}); err == nil {
+				grb.Annotations = withDisplayName(grb.Annotations, displayName)
+				_, err = r.grbs.Update(grb)
+			}
+		}
+		if apierrors.IsNotFound(err) { // The binding is no longer, move on.
+			return nil
+		}
+		return err
+	})
+}
+
+func withDisplayName(annotations map[string]string, displayName string) map[string]string {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[DisplayNameAnnotation] = displayName
+	return annotations
+}
@@ -0,0 +1,119 @@
+package groupdisplaynames
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type fakeGroupLookup struct {
+	names   map[string]string
+	lookups map[string]int
+}
+
+func (f *fakeGroupLookup) LookupGroup(principalID string) (v3.Principal, error) {
+	f.lookups[principalID]++
+	name, ok := f.names[principalID]
+	if !ok {
+		return v3.Principal{}, fmt.Errorf("group %s not found", principalID)
+	}
+	return v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: principalID}, DisplayName: name}, nil
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl)
+	grbs := fake.NewMockNonNamespacedClientInterface[*v3.GlobalRoleBinding, *v3.GlobalRoleBindingList](ctrl)
+
+	renamed := &v3.ClusterRoleTemplateBinding{
+		ObjectMeta:         metav1.ObjectMeta{Namespace: "c-abcde", Name: "crtb-renamed", Annotations: map[string]string{DisplayNameAnnotation: "Old Name"}},
+		GroupPrincipalName: "openldap_group://cn=devs",
+	}
+	unchanged := &v3.ClusterRoleTemplateBinding{
+		ObjectMeta:         metav1.ObjectMeta{Namespace: "c-abcde", Name: "crtb-unchanged", Annotations: map[string]string{DisplayNameAnnotation: "Ops"}},
+		GroupPrincipalName: "openldap_group://cn=ops",
+	}
+	user := &v3.ClusterRoleTemplateBinding{
+		ObjectMeta:        metav1.ObjectMeta{Namespace: "c-abcde", Name: "crtb-user"},
+		UserPrincipalName: "openldap_user://uid=alice",
+	}
+	missing := &v3.ProjectRoleTemplateBinding{
+		ObjectMeta:         metav1.ObjectMeta{Namespace: "p-abcde", Name: "prtb-missing"},
+		GroupPrincipalName: "openldap_group://cn=gone",
+	}
+	noLookup := &v3.ProjectRoleTemplateBinding{
+		ObjectMeta:         metav1.ObjectMeta{Namespace: "p-abcde", Name: "prtb-github"},
+		GroupPrincipalName: "github_org://1234",
+	}
+	unannotated := &v3.GlobalRoleBinding{
+		ObjectMeta:         metav1.ObjectMeta{Name: "grb-unannotated"},
+		GroupPrincipalName: "openldap_group://cn=devs",
+	}
+
+	crtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ClusterRoleTemplateBinding{renamed, unchanged, user}, nil)
+	prtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ProjectRoleTemplateBinding{missing, noLookup}, nil)
+	grbCache.EXPECT().List(labels.Everything()).Return([]*v3.GlobalRoleBinding{unannotated}, nil)
+
+	crtbs.EXPECT().Get(renamed.Namespace, renamed.Name, gomock.Any()).Return(renamed.DeepCopy(), nil)
+	crtbs.EXPECT().Update(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		assert.Equal(t, "Developers", crtb.Annotations[DisplayNameAnnotation])
+		return crtb, nil
+	})
+	grbs.EXPECT().Get(unannotated.Name, gomock.Any()).Return(unannotated.DeepCopy(), nil)
+	grbs.EXPECT().Update(gomock.Any()).DoAndReturn(func(grb *v3.GlobalRoleBinding) (*v3.GlobalRoleBinding, error) {
+		assert.Equal(t, "Developers", grb.Annotations[DisplayNameAnnotation])
+		return grb, nil
+	})
+
+	lookup := &fakeGroupLookup{
+		names: map[string]string{
+			"openldap_group://cn=devs": "Developers",
+			"openldap_group://cn=ops":  "Ops",
+		},
+		lookups: map[string]int{},
+	}
+	r := &Refresher{
+		crtbCache:        crtbCache,
+		crtbs:            crtbs,
+		prtbCache:        prtbCache,
+		prtbs:            prtbs,
+		grbCache:         grbCache,
+		grbs:             grbs,
+		enabledProviders: func() []string { return []string{"openldap", "github"} },
+		getGroupLookup: func(providerName string) common.GroupLookup {
+			if providerName == "openldap" {
+				return lookup
+			}
+			return nil
+		},
+	}
+	require.NoError(t, r.Run(context.Background()))
+
+	// Each group is looked up once, however many bindings it has.
+	assert.Equal(t, map[string]int{
+		"openldap_group://cn=devs": 1,
+		"openldap_group://cn=ops":  1,
+		"openldap_group://cn=gone": 1,
+	}, lookup.lookups)
+}
+
+func TestRunNoGroupLookup(t *testing.T) {
+	r := &Refresher{
+		enabledProviders: func() []string { return []string{"github"} },
+		getGroupLookup:   func(string) common.GroupLookup { return nil },
+	}
+	require.NoError(t, r.Run(context.Background()))
+}
@@ -0,0 +1,257 @@
+// Package groupsync materializes the group principals of the auth providers as Group objects, so that groups can be
+// labeled, referenced and inspected without searching the providers.
+package groupsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/groupdisplaynames"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+// SyncedLabel marks the groups managed by the sync. Other groups are left untouched.
+const SyncedLabel = "auth.cattle.io/synced-group"
+
+// Syncer creates, updates and deletes Group objects from the group principals found on the user attributes
+// and the role bindings. Groups are only synced for the enabled auth providers.
+type Syncer struct {
+	groupCache         mgmtcontrollers.GroupCache
+	groups             mgmtcontrollers.GroupClient
+	userAttributeCache mgmtcontrollers.UserAttributeCache
+	crtbCache          mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbCache          mgmtcontrollers.ProjectRoleTemplateBindingCache
+	grbCache           mgmtcontrollers.GlobalRoleBindingCache
+	enabledProviders   func() []string
+	now                func() time.Time
+}
+
+// New creates a new instance of Syncer.
+func New(wContext *wrangler.Context) *Syncer {
+	return &Syncer{
+		groupCache:         wContext.Mgmt.Group().Cache(),
+		groups:             wContext.Mgmt.Group(),
+		userAttributeCache: wContext.Mgmt.UserAttribute().Cache(),
+		crtbCache:          wContext.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbCache:          wContext.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		grbCache:           wContext.Mgmt.GlobalRoleBinding().Cache(),
+		enabledProviders:   providers.EnabledProviders,
+		now:                time.Now,
+	}
+}
+
+// GroupName returns the name of the Group object of a group principal.
+func GroupName(principalID string) string {
+	sum := sha256.Sum256([]byte(principalID))
+	return "g-" + strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum[:]))[:10]
+}
+
+// group is a group principal found on the user attributes or the role bindings.
+type group struct {
+	principalID string
+	provider    string
+	displayName string
+	members     int
+}
+
+// Run the sync of the groups.
+func (s *Syncer) Run(ctx context.Context) error {
+	if ctx.Err() != nil {
+		logrus.Info("groupsync: context canceled, quitting")
+		return nil
+	}
+
+	startedAt := s.now()
+
+	enabled := map[string]bool{}
+	for _, providerName := range s.enabledProviders() {
+		enabled[providerName] = true
+	}
+
+	found, err := s.collect(enabled)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.groupCache.List(labels.SelectorFromSet(labels.Set{SyncedLabel: "true"}))
+	if err != nil {
+		return fmt.Errorf("error listing groups: %w", err)
+	}
+
+	logrus.Info("groupsync: started")
+
+	var created, updated, deleted, errCount int
+
+	defer func() {
+		logrus.Infof(
+			"groupsync: finished in %v seconds (groups %d, created %d, updated %d, deleted %d, errors %d)",
+			time.Since(startedAt).Seconds(),
+			len(found), created, updated, deleted, errCount,
+		)
+	}()
+
+	lastSync := metav1.NewTime(startedAt)
+
+	for _, obj := range existing {
+		if ctx.Err() != nil {
+			logrus.Info("groupsync: context canceled, quitting")
+			return nil
+		}
+
+		g, ok := found[obj.Name]
+		if !ok || g.principalID != obj.PrincipalID {
+			if err := s.groups.Delete(obj.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				logrus.Errorf("groupsync: error deleting group %s: %v", obj.Name, err)
+				errCount++
+				continue
+			}
+			deleted++
+			continue
+		}
+		delete(found, obj.Name)
+
+		if err := s.update(obj.Name, g, lastSync); err != nil {
+			logrus.Errorf("groupsync: error updating group %s for %s: %v", obj.Name, g.principalID, err)
+			errCount++
+			continue
+		}
+		updated++
+	}
+
+	for name, g := range found {
+		if ctx.Err() != nil {
+			logrus.Info("groupsync: context canceled, quitting")
+			return nil
+		}
+
+		obj := &v3.Group{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{SyncedLabel: "true"},
+			},
+		}
+		setFields(obj, g, lastSync)
+		if _, err := s.groups.Create(obj); err != nil {
+			logrus.Errorf("groupsync: error creating group %s for %s: %v", name, g.principalID, err)
+			errCount++
+			continue
+		}
+		created++
+	}
+
+	return nil
+}
+
+// collect returns the group principals of the enabled providers keyed by the name of their Group object.
+// Members are counted from the user attributes, which reflect the groups of the users as of their last login or refresh.
+func (s *Syncer) collect(enabled map[string]bool) (map[string]*group, error) {
+	found := map[string]*group{}
+	add := func(principalID, provider, displayName string) *group {
+		name := GroupName(principalID)
+		g, ok := found[name]
+		if !ok {
+			g = &group{principalID: principalID, provider: provider}
+			found[name] = g
+		}
+		if g.displayName == "" {
+			g.displayName = displayName
+		}
+		return g
+	}
+
+	attribsList, err := s.userAttributeCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing user attributes: %w", err)
+	}
+	for _, attribs := range attribsList {
+		for provider, principals := range attribs.GroupPrincipals {
+			if !enabled[provider] {
+				continue
+			}
+			seen := map[string]bool{}
+			for _, principal := range principals.Items {
+				if principal.Name == "" || seen[principal.Name] {
+					continue
+				}
+				seen[principal.Name] = true
+				add(principal.Name, provider, principal.DisplayName).members++
+			}
+		}
+	}
+
+	// Groups bound to roles are synced even if no known user is a member.
+	addBound := func(principalID string, annotations map[string]string) {
+		if principalID == "" {
+			return
+		}
+		if provider := providers.PrincipalProvider(principalID); enabled[provider] {
+			add(principalID, provider, annotations[groupdisplaynames.DisplayNameAnnotation])
+		}
+	}
+
+	crtbs, err := s.crtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing cluster role template bindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		addBound(crtb.GroupPrincipalName, crtb.Annotations)
+	}
+
+	prtbs, err := s.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing project role template bindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		addBound(prtb.GroupPrincipalName, prtb.Annotations)
+	}
+
+	grbs, err := s.grbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing global role bindings: %w", err)
+	}
+	for _, grb := range grbs {
+		addBound(grb.GroupPrincipalName, grb.Annotations)
+	}
+
+	return found, nil
+}
+
+// update sets the synced fields on the latest version of the group.
+func (s *Syncer) update(name string, g *group, lastSync metav1.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := s.groups.Get(name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) { // The group is no longer, it's created on the next run.
+				return nil
+			}
+			return err
+		}
+		setFields(obj, g, lastSync)
+		_, err = s.groups.Update(obj)
+		return err
+	})
+}
+
+func setFields(obj *v3.Group, g *group, lastSync metav1.Time) {
+	obj.PrincipalID = g.principalID
+	obj.Provider = g.provider
+	obj.MemberCount = g.members
+	obj.LastSync = &lastSync
+	if g.displayName != "" {
+		obj.DisplayName = g.displayName
+	} else if obj.DisplayName == "" {
+		obj.DisplayName = g.principalID
+	}
+}
@@ -0,0 +1,144 @@
+package groupsync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/groupdisplaynames"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestGroupName(t *testing.T) {
+	name := GroupName("openldap_group://cn=devs,dc=example,dc=com")
+	assert.Len(t, name, 12)
+	assert.Regexp(t, "^g-[a-z2-7]{10}$", name)
+	assert.Equal(t, name, GroupName("openldap_group://cn=devs,dc=example,dc=com"))
+	assert.NotEqual(t, name, GroupName("openldap_group://cn=ops,dc=example,dc=com"))
+}
+
+func TestRun(t *testing.T) {
+	const (
+		devs   = "openldap_group://cn=devs"
+		ops    = "openldap_group://cn=ops"
+		admins = "openldap_group://cn=admins"
+		gone   = "openldap_group://cn=gone"
+		org    = "github_org://1234"
+	)
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	groupCache := fake.NewMockNonNamespacedCacheInterface[*v3.Group](ctrl)
+	groups := fake.NewMockNonNamespacedClientInterface[*v3.Group, *v3.GroupList](ctrl)
+	userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl)
+
+	userAttributeCache.EXPECT().List(labels.Everything()).Return([]*v3.UserAttribute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "u-alice"},
+			GroupPrincipals: map[string]v3.Principals{
+				"openldap": {Items: []v3.Principal{
+					{ObjectMeta: metav1.ObjectMeta{Name: devs}, DisplayName: "Developers"},
+					{ObjectMeta: metav1.ObjectMeta{Name: ops}, DisplayName: "Operations"},
+				}},
+				"github": {Items: []v3.Principal{
+					{ObjectMeta: metav1.ObjectMeta{Name: org}, DisplayName: "Example"},
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "u-bob"},
+			GroupPrincipals: map[string]v3.Principals{
+				"openldap": {Items: []v3.Principal{
+					{ObjectMeta: metav1.ObjectMeta{Name: devs}, DisplayName: "Developers"},
+				}},
+			},
+		},
+	}, nil)
+	crtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ClusterRoleTemplateBinding{
+		{
+			ObjectMeta:         metav1.ObjectMeta{Namespace: "c-abcde", Name: "crtb-admins", Annotations: map[string]string{groupdisplaynames.DisplayNameAnnotation: "Admins"}},
+			GroupPrincipalName: admins,
+		},
+		{
+			ObjectMeta:        metav1.ObjectMeta{Namespace: "c-abcde", Name: "crtb-user"},
+			UserPrincipalName: "openldap_user://uid=alice",
+		},
+	}, nil)
+	prtbCache.EXPECT().List("", labels.Everything()).Return(nil, nil)
+	grbCache.EXPECT().List(labels.Everything()).Return([]*v3.GlobalRoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-org"}, GroupPrincipalName: org},
+	}, nil)
+
+	existingDevs := &v3.Group{
+		ObjectMeta:  metav1.ObjectMeta{Name: GroupName(devs), Labels: map[string]string{SyncedLabel: "true"}},
+		DisplayName: "Devs",
+		PrincipalID: devs,
+		Provider:    "openldap",
+		MemberCount: 1,
+	}
+	existingGone := &v3.Group{
+		ObjectMeta:  metav1.ObjectMeta{Name: GroupName(gone), Labels: map[string]string{SyncedLabel: "true"}},
+		PrincipalID: gone,
+		Provider:    "openldap",
+	}
+	groupCache.EXPECT().List(labels.SelectorFromSet(labels.Set{SyncedLabel: "true"})).Return([]*v3.Group{existingDevs, existingGone}, nil)
+
+	groups.EXPECT().Delete(GroupName(gone), gomock.Any()).Return(nil)
+	groups.EXPECT().Get(GroupName(devs), gomock.Any()).Return(existingDevs.DeepCopy(), nil)
+	var updated *v3.Group
+	groups.EXPECT().Update(gomock.Any()).DoAndReturn(func(group *v3.Group) (*v3.Group, error) {
+		updated = group
+		return group, nil
+	})
+	created := map[string]*v3.Group{}
+	groups.EXPECT().Create(gomock.Any()).Times(2).DoAndReturn(func(group *v3.Group) (*v3.Group, error) {
+		created[group.PrincipalID] = group
+		return group, nil
+	})
+
+	syncer := &Syncer{
+		groupCache:         groupCache,
+		groups:             groups,
+		userAttributeCache: userAttributeCache,
+		crtbCache:          crtbCache,
+		prtbCache:          prtbCache,
+		grbCache:           grbCache,
+		enabledProviders:   func() []string { return []string{"openldap"} },
+		now:                func() time.Time { return now },
+	}
+
+	err := syncer.Run(context.Background())
+	require.NoError(t, err)
+
+	require.NotNil(t, updated)
+	assert.Equal(t, "Developers", updated.DisplayName)
+	assert.Equal(t, 2, updated.MemberCount)
+	assert.Equal(t, metav1.NewTime(now), *updated.LastSync)
+
+	require.Len(t, created, 2)
+	assert.Equal(t, GroupName(ops), created[ops].Name)
+	assert.Equal(t, "true", created[ops].Labels[SyncedLabel])
+	assert.Equal(t, "Operations", created[ops].DisplayName)
+	assert.Equal(t, "openldap", created[ops].Provider)
+	assert.Equal(t, 1, created[ops].MemberCount)
+	assert.Equal(t, "Admins", created[admins].DisplayName)
+	assert.Equal(t, 0, created[admins].MemberCount)
+}
+
+func TestRunCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	syncer := &Syncer{}
+	err := syncer.Run(ctx)
+	require.NoError(t, err)
+}
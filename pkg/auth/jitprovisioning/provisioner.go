@@ -0,0 +1,176 @@
+package jitprovisioning
+
+import (
+	"fmt"
+	"strings"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+// ProvisionedAnnotation is set on users once the JIT provisioning policies have been evaluated for them.
+// Its value is the comma separated list of the policies applied to the user.
+const ProvisionedAnnotation = "auth.cattle.io/jit-provisioned"
+
+// Provisioner applies the JIT provisioning policies to users on their first login.
+type Provisioner struct {
+	policyCache        mgmtcontrollers.JITProvisioningPolicyCache
+	clusterCache       mgmtcontrollers.ClusterCache
+	users              mgmtcontrollers.UserClient
+	globalRoleBindings mgmtcontrollers.GlobalRoleBindingClient
+	crtbs              mgmtcontrollers.ClusterRoleTemplateBindingClient
+	prtbs              mgmtcontrollers.ProjectRoleTemplateBindingClient
+}
+
+// NewProvisioner creates a new instance of Provisioner.
+func NewProvisioner(wContext *wrangler.Context) *Provisioner {
+	return &Provisioner{
+		policyCache:        wContext.Mgmt.JITProvisioningPolicy().Cache(),
+		clusterCache:       wContext.Mgmt.Cluster().Cache(),
+		users:              wContext.Mgmt.User(),
+		globalRoleBindings: wContext.Mgmt.GlobalRoleBinding(),
+		crtbs:              wContext.Mgmt.ClusterRoleTemplateBinding(),
+		prtbs:              wContext.Mgmt.ProjectRoleTemplateBinding(),
+	}
+}
+
+// Provision binds the roles of the policies matching the user who logged in with the provider.
+// Policies are only evaluated on the first login of a user, users already annotated with ProvisionedAnnotation are skipped.
+func (p *Provisioner) Provision(user *v3.User, provider string, groupPrincipals []v3.Principal, extras map[string][]string) error {
+	if _, ok := user.Annotations[ProvisionedAnnotation]; ok {
+		return nil
+	}
+
+	policies, err := p.policyCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing JIT provisioning policies: %w", err)
+	}
+
+	var applied []string
+	for _, policy := range policies {
+		if !matches(&policy.Spec, provider, groupPrincipals, extras) {
+			continue
+		}
+		if err := p.apply(user, policy); err != nil {
+			return fmt.Errorf("error applying JIT provisioning policy %s to user %s: %w", policy.Name, user.Name, err)
+		}
+		logrus.Infof("jitprovisioning: applied policy %s to user %s", policy.Name, user.Name)
+		applied = append(applied, policy.Name)
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := p.users.Get(user.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if latest.Annotations == nil {
+			latest.Annotations = map[string]string{}
+		}
+		latest.Annotations[ProvisionedAnnotation] = strings.Join(applied, ",")
+		_, err = p.users.Update(latest)
+		return err
+	})
+}
+
+// matches returns true if the policy selects the user who logged in with the provider.
+// A user is selected if they are a member of any of the group principals and have all the attributes of the policy.
+func matches(spec *v3.JITProvisioningPolicySpec, provider string, groupPrincipals []v3.Principal, extras map[string][]string) bool {
+	if spec.Provider != "" && spec.Provider != provider {
+		return false
+	}
+
+	if len(spec.GroupPrincipals) > 0 {
+		member := false
+		for _, group := range groupPrincipals {
+			for _, groupName := range spec.GroupPrincipals {
+				if group.Name == groupName {
+					member = true
+				}
+			}
+		}
+		if !member {
+			return false
+		}
+	}
+
+	for key, value := range spec.Attributes {
+		found := false
+		for _, v := range extras[key] {
+			if v == value {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// apply creates the bindings of the policy for the user. Bindings have deterministic names
+// so a policy which failed to be fully applied can be retried.
+func (p *Provisioner) apply(user *v3.User, policy *v3.JITProvisioningPolicy) error {
+	for _, globalRole := range policy.Spec.GlobalRoles {
+		_, err := p.globalRoleBindings.Create(&v3.GlobalRoleBinding{
+			ObjectMeta:     metav1.ObjectMeta{Name: name.SafeConcatName("grb", "jit", user.Name, globalRole)},
+			UserName:       user.Name,
+			GlobalRoleName: globalRole,
+		})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	for _, clusterRole := range policy.Spec.ClusterRoles {
+		selector, err := metav1.LabelSelectorAsSelector(&clusterRole.ClusterSelector)
+		if err != nil {
+			return fmt.Errorf("invalid cluster selector: %w", err)
+		}
+		clusters, err := p.clusterCache.List(selector)
+		if err != nil {
+			return err
+		}
+		for _, cluster := range clusters {
+			binding := &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:       metav1.ObjectMeta{Name: name.SafeConcatName("crtb", "jit", user.Name, clusterRole.RoleTemplateName), Namespace: cluster.Name},
+				UserName:         user.Name,
+				ClusterName:      cluster.Name,
+				RoleTemplateName: clusterRole.RoleTemplateName,
+			}
+			provenance.Set(&binding.ObjectMeta, provenance.SourceJITPolicy, provenance.Creator("JITProvisioningPolicy", policy.Name))
+			_, err := p.crtbs.Create(binding)
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+	}
+
+	for _, projectRole := range policy.Spec.ProjectRoles {
+		for _, projectName := range projectRole.ProjectNames {
+			_, projectID, ok := strings.Cut(projectName, ":")
+			if !ok {
+				return fmt.Errorf("invalid project name %s, expected <cluster>:<project>", projectName)
+			}
+			binding := &v3.ProjectRoleTemplateBinding{
+				ObjectMeta:       metav1.ObjectMeta{Name: name.SafeConcatName("prtb", "jit", user.Name, projectRole.RoleTemplateName), Namespace: projectID},
+				UserName:         user.Name,
+				ProjectName:      projectName,
+				RoleTemplateName: projectRole.RoleTemplateName,
+			}
+			provenance.Set(&binding.ObjectMeta, provenance.SourceJITPolicy, provenance.Creator("JITProvisioningPolicy", policy.Name))
+			_, err := p.prtbs.Create(binding)
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package jitprovisioning
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const platformTeam = "openldap_group://cn=platform-team,ou=groups,dc=example,dc=com"
+
+func TestMatches(t *testing.T) {
+	groups := []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: platformTeam}}}
+	extras := map[string][]string{"username": {"jdoe"}}
+
+	tests := []struct {
+		name string
+		spec v3.JITProvisioningPolicySpec
+		want bool
+	}{
+		{
+			name: "empty policy",
+			want: true,
+		},
+		{
+			name: "same provider",
+			spec: v3.JITProvisioningPolicySpec{Provider: "openldap"},
+			want: true,
+		},
+		{
+			name: "other provider",
+			spec: v3.JITProvisioningPolicySpec{Provider: "github"},
+		},
+		{
+			name: "member of a group",
+			spec: v3.JITProvisioningPolicySpec{GroupPrincipals: []string{"openldap_group://cn=other", platformTeam}},
+			want: true,
+		},
+		{
+			name: "not a member of the groups",
+			spec: v3.JITProvisioningPolicySpec{GroupPrincipals: []string{"openldap_group://cn=other"}},
+		},
+		{
+			name: "matching attributes",
+			spec: v3.JITProvisioningPolicySpec{Attributes: map[string]string{"username": "jdoe"}},
+			want: true,
+		},
+		{
+			name: "missing attribute",
+			spec: v3.JITProvisioningPolicySpec{Attributes: map[string]string{"username": "jdoe", "department": "it"}},
+		},
+		{
+			name: "member of a group without the attributes",
+			spec: v3.JITProvisioningPolicySpec{GroupPrincipals: []string{platformTeam}, Attributes: map[string]string{"username": "someone"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matches(&tt.spec, "openldap", groups, extras))
+		})
+	}
+}
+
+func TestProvision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	policyCache := fake.NewMockNonNamespacedCacheInterface[*v3.JITProvisioningPolicy](ctrl)
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	users := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+	grbs := fake.NewMockNonNamespacedClientInterface[*v3.GlobalRoleBinding, *v3.GlobalRoleBindingList](ctrl)
+	crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+
+	policyCache.EXPECT().List(labels.Everything()).Return([]*v3.JITProvisioningPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-team"},
+			Spec: v3.JITProvisioningPolicySpec{
+				GroupPrincipals: []string{platformTeam},
+				GlobalRoles:     []string{"user"},
+				ClusterRoles: []v3.JITClusterRole{{
+					RoleTemplateName: "cluster-member",
+					ClusterSelector:  metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+				}},
+				ProjectRoles: []v3.JITProjectRole{{
+					RoleTemplateName: "project-member",
+					ProjectNames:     []string{"c-abcde:p-fghij"},
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "github"},
+			Spec:       v3.JITProvisioningPolicySpec{Provider: "github", GlobalRoles: []string{"admin"}},
+		},
+	}, nil)
+	clusterCache.EXPECT().List(gomock.Any()).Return([]*v3.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-klmno"}},
+	}, nil)
+
+	grbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(grb *v3.GlobalRoleBinding) (*v3.GlobalRoleBinding, error) {
+		assert.Equal(t, "u-abcde", grb.UserName)
+		assert.Equal(t, "user", grb.GlobalRoleName)
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{}, grb.Name)
+	})
+	var clusters []string
+	crtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		assert.Equal(t, "u-abcde", crtb.UserName)
+		assert.Equal(t, "cluster-member", crtb.RoleTemplateName)
+		assert.Equal(t, crtb.ClusterName, crtb.Namespace)
+		clusters = append(clusters, crtb.ClusterName)
+		return crtb, nil
+	}).Times(2)
+	prtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(prtb *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+		assert.Equal(t, "u-abcde", prtb.UserName)
+		assert.Equal(t, "c-abcde:p-fghij", prtb.ProjectName)
+		assert.Equal(t, "p-fghij", prtb.Namespace)
+		return prtb, nil
+	})
+	users.EXPECT().Get("u-abcde", gomock.Any()).Return(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}}, nil)
+	users.EXPECT().Update(gomock.Any()).DoAndReturn(func(user *v3.User) (*v3.User, error) {
+		assert.Equal(t, "platform-team", user.Annotations[ProvisionedAnnotation])
+		return user, nil
+	})
+
+	provisioner := &Provisioner{
+		policyCache:        policyCache,
+		clusterCache:       clusterCache,
+		users:              users,
+		globalRoleBindings: grbs,
+		crtbs:              crtbs,
+		prtbs:              prtbs,
+	}
+	user := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}}
+	groups := []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: platformTeam}}}
+
+	err := provisioner.Provision(user, "openldap", groups, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c-abcde", "c-klmno"}, clusters)
+}
+
+func TestProvisionAlreadyProvisioned(t *testing.T) {
+	provisioner := &Provisioner{}
+	user := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde", Annotations: map[string]string{ProvisionedAnnotation: ""}}}
+
+	err := provisioner.Provision(user, "openldap", nil, nil)
+	assert.NoError(t, err)
+}
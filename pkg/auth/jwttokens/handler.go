@@ -0,0 +1,69 @@
+package jwttokens
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// JWKSPath is the path of the public keys validating the JWTs.
+	JWKSPath = "/.well-known/jwks.json"
+	// DiscoveryPath is the path of the discovery document pointing services to the keys, in the format of OpenID
+	// Connect discovery, which most JWT validators support.
+	DiscoveryPath = "/.well-known/openid-configuration"
+
+	// keySetMaxAge is how long clients can cache the keys, in seconds. A new key signs JWTs as soon as it's generated,
+	// so clients are expected to fetch the keys again when they meet an unknown key ID.
+	keySetMaxAge = "300"
+)
+
+type handler struct {
+	issuer *Issuer
+}
+
+// NewHandler returns the handler of the JWKS and discovery endpoints.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	h := &handler{issuer: NewIssuer(mgmt.Wrangler.Core.Secret())}
+
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodGet).Path(JWKSPath).HandlerFunc(h.keySet)
+	root.Methods(http.MethodGet).Path(DiscoveryPath).HandlerFunc(h.discovery)
+	return root
+}
+
+// keySet returns the public keys validating the JWTs.
+func (h *handler) keySet(w http.ResponseWriter, r *http.Request) {
+	if !h.issuer.enabled() {
+		http.NotFound(w, r)
+		return
+	}
+	set, err := h.issuer.KeySet()
+	if err != nil {
+		logrus.Errorf("[jwttokens] failed to get the JWT signing keys: %v", err)
+		http.Error(w, "failed to get the JWT signing keys", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age="+keySetMaxAge)
+	util.WriteJSON(w, http.StatusOK, set)
+}
+
+// discovery returns the issuer of the JWTs and the location of their keys.
+func (h *handler) discovery(w http.ResponseWriter, r *http.Request) {
+	issuer := h.issuer.issuer()
+	if !h.issuer.enabled() || issuer == "" {
+		http.NotFound(w, r)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                issuer,
+		"jwks_uri":                              issuer + JWKSPath,
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{string(signingAlgorithm)},
+		"claims_supported":                      []string{"iss", "sub", "jti", "iat", "nbf", "exp", "principal", "cluster", "tokenScope"},
+	})
+}
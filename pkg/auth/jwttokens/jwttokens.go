@@ -0,0 +1,197 @@
+// Package jwttokens issues Rancher tokens as JWTs signed by Rancher, when auth-jwt-tokens-enabled is set. Services and
+// ingress controllers can validate them offline with the keys published at /.well-known/jwks.json, instead of calling
+// Rancher for every request.
+// A JWT stands for a regular token, whose name is its ID (jti). Rancher looks the token up whenever the JWT is used, so
+// deleting or disabling the token revokes the JWT; services validating JWTs offline can look it up too, to check it
+// hasn't been revoked.
+package jwttokens
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+)
+
+// signingAlgorithm is the algorithm signing the JWTs.
+const signingAlgorithm = jose.RS256
+
+// ErrDisabled is returned when JWTs are issued or used while auth-jwt-tokens-enabled isn't set.
+var ErrDisabled = errors.New("JWT tokens are not enabled")
+
+// Claims are the claims of the JWTs. The subject is the ID of the user of the token and the ID (jti) the name of
+// the token.
+type Claims struct {
+	jwt.Claims
+	// Principal is the ID of the principal of the user of the token.
+	Principal string `json:"principal,omitempty"`
+	// Cluster is the cluster the token is restricted to, if any.
+	Cluster string `json:"cluster,omitempty"`
+	// Scope is the scope of the token, if any.
+	Scope *v3.TokenScope `json:"tokenScope,omitempty"`
+}
+
+// Enabled tells whether tokens can be issued and used as JWTs.
+func Enabled() bool {
+	return settings.AuthJWTTokensEnabled.Get() == "true"
+}
+
+// IsJWT tells whether the value of a token is a JWT, rather than <name>:<key>.
+func IsJWT(value string) bool {
+	return strings.Count(value, ".") == 2 && !strings.Contains(value, ":")
+}
+
+// Issuer signs and verifies the JWTs.
+type Issuer struct {
+	keys    *keyStore
+	issuer  func() string
+	enabled func() bool
+	now     func() time.Time
+}
+
+// NewIssuer returns an Issuer keeping its signing keys in secrets.
+func NewIssuer(secrets wcorev1.SecretClient) *Issuer {
+	return &Issuer{
+		keys: &keyStore{
+			secrets:  secrets,
+			rotation: rotationPeriod,
+			now:      time.Now,
+		},
+		issuer:  settings.ServerURL.Get,
+		enabled: Enabled,
+		now:     time.Now,
+	}
+}
+
+// rotationPeriod returns how long a key signs the JWTs, per auth-jwt-signing-key-rotation-days.
+func rotationPeriod() time.Duration {
+	days, err := strconv.Atoi(settings.AuthJWTSigningKeyRotationDays.Get())
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Sign returns the token as a JWT. The JWT expires along with the token, or after the rotation period of the signing
+// keys if the token lives longer.
+func (i *Issuer) Sign(token *v3.Token) (string, error) {
+	if !i.enabled() {
+		return "", ErrDisabled
+	}
+	issuer := i.issuer()
+	if issuer == "" {
+		return "", errors.New("server-url must be set to issue JWTs")
+	}
+	if token.TTLMillis <= 0 {
+		return "", fmt.Errorf("token %s doesn't expire", token.Name)
+	}
+
+	key, err := i.keys.current()
+	if err != nil {
+		return "", err
+	}
+	privateKey, err := key.privateKey()
+	if err != nil {
+		return "", err
+	}
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: signingAlgorithm, Key: jose.JSONWebKey{Key: privateKey, KeyID: key.ID}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		return "", fmt.Errorf("creating JWT signer: %w", err)
+	}
+
+	now := i.now()
+	expiry := token.CreationTimestamp.Add(time.Duration(token.TTLMillis) * time.Millisecond)
+	if maxExpiry := now.Add(i.keys.rotation()); expiry.After(maxExpiry) {
+		expiry = maxExpiry
+	}
+	claims := Claims{
+		Claims: jwt.Claims{
+			Issuer:    issuer,
+			Subject:   token.UserID,
+			ID:        token.Name,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Expiry:    jwt.NewNumericDate(expiry),
+		},
+		Principal: token.UserPrincipal.Name,
+		Cluster:   token.ClusterName,
+		Scope:     token.Scope,
+	}
+	value, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		return "", fmt.Errorf("signing JWT of token %s: %w", token.Name, err)
+	}
+	return value, nil
+}
+
+// Verify returns the claims of the JWT if it was signed by Rancher and hasn't expired.
+func (i *Issuer) Verify(value string) (*Claims, error) {
+	if !i.enabled() {
+		return nil, ErrDisabled
+	}
+	issuer := i.issuer()
+	if issuer == "" {
+		return nil, errors.New("server-url isn't set")
+	}
+
+	parsed, err := jwt.ParseSigned(value)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JWT: %w", err)
+	}
+	if len(parsed.Headers) != 1 || parsed.Headers[0].Algorithm != string(signingAlgorithm) {
+		return nil, fmt.Errorf("JWT must be signed once with %s", signingAlgorithm)
+	}
+	publicKey, err := i.keys.publicKey(parsed.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	if err := parsed.Claims(publicKey, claims); err != nil {
+		return nil, fmt.Errorf("verifying JWT: %w", err)
+	}
+	if claims.Expiry == nil || claims.ID == "" {
+		return nil, errors.New("JWT has no expiry or ID")
+	}
+	if err := claims.ValidateWithLeeway(jwt.Expected{Issuer: issuer, Time: i.now()}, 0); err != nil {
+		return nil, fmt.Errorf("validating JWT: %w", err)
+	}
+	return claims, nil
+}
+
+// KeySet returns the public keys which can have signed valid JWTs, generating the first key if needed so that
+// services can fetch it before any JWT is issued.
+func (i *Issuer) KeySet() (*jose.JSONWebKeySet, error) {
+	if _, err := i.keys.current(); err != nil {
+		return nil, err
+	}
+	keys, err := i.keys.published()
+	if err != nil {
+		return nil, err
+	}
+
+	set := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+	for _, key := range keys {
+		privateKey, err := key.privateKey()
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jose.JSONWebKey{
+			Key:       &privateKey.PublicKey,
+			KeyID:     key.ID,
+			Algorithm: string(signingAlgorithm),
+			Use:       "sig",
+		})
+	}
+	return set, nil
+}
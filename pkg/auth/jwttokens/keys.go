@@ -0,0 +1,246 @@
+package jwttokens
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// SigningKeysSecretName is the name of the secret holding the keys signing the JWTs, in the cattle-system namespace.
+	SigningKeysSecretName = "jwt-signing-keys"
+
+	signingKeysKey = "keys"
+	rsaKeyBits     = 2048
+
+	// keysCacheTTL is how long the keys are cached before being read from the secret again.
+	keysCacheTTL = time.Minute
+	// minKeyMissRefetchInterval limits how often an unknown key ID triggers a read of the keys, so that JWTs with bogus
+	// key IDs can't be used to flood the API server.
+	minKeyMissRefetchInterval = 10 * time.Second
+)
+
+// signingKey is a key signing the JWTs.
+type signingKey struct {
+	ID string `json:"id"`
+	// Key is the PKCS #8 encoding of the RSA private key.
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newSigningKey(now time.Time) (signingKey, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("generating signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("encoding signing key: %w", err)
+	}
+	thumbprint, err := (&jose.JSONWebKey{Key: &privateKey.PublicKey}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("computing the ID of the signing key: %w", err)
+	}
+	return signingKey{
+		ID:        base64.RawURLEncoding.EncodeToString(thumbprint),
+		Key:       der,
+		CreatedAt: now.UTC(),
+	}, nil
+}
+
+func (k signingKey) privateKey() (*rsa.PrivateKey, error) {
+	key, err := x509.ParsePKCS8PrivateKey(k.Key)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing key %s: %w", k.ID, err)
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an RSA key", k.ID)
+	}
+	return privateKey, nil
+}
+
+// keyStore keeps the signing keys in a secret, oldest first. The newest key signs the JWTs and is replaced once it's
+// older than the rotation period. The replaced keys are kept for as long again to validate the JWTs they signed,
+// which can't live longer than that.
+type keyStore struct {
+	secrets  wcorev1.SecretClient
+	rotation func() time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cached    []signingKey
+	fetchedAt time.Time
+}
+
+// current returns the key to sign with, generating one if there is none yet or if the newest one is due for rotation.
+func (s *keyStore) current() (signingKey, error) {
+	keys, err := s.keys(false)
+	if err != nil {
+		return signingKey{}, err
+	}
+	if key, ok := s.active(keys); ok {
+		return key, nil
+	}
+
+	var key signingKey
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := s.secrets.Get(tokens.SecretNamespace, SigningKeysSecretName, metav1.GetOptions{})
+		found := err == nil
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		var keys []signingKey
+		if found {
+			if keys, err = parseKeys(secret); err != nil {
+				return err
+			}
+		}
+		// Another replica may have rotated the key in the meantime.
+		var ok bool
+		if key, ok = s.active(keys); ok {
+			s.setCache(keys)
+			return nil
+		}
+
+		if key, err = newSigningKey(s.now()); err != nil {
+			return err
+		}
+		keys = s.prune(append(keys, key))
+		data, err := json.Marshal(keys)
+		if err != nil {
+			return err
+		}
+		if !found {
+			_, err = s.secrets.Create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      SigningKeysSecretName,
+					Namespace: tokens.SecretNamespace,
+				},
+				Data: map[string][]byte{signingKeysKey: data},
+			})
+			if apierrors.IsAlreadyExists(err) {
+				return apierrors.NewConflict(corev1.Resource("secrets"), SigningKeysSecretName, err)
+			}
+		} else {
+			secret = secret.DeepCopy()
+			if secret.Data == nil {
+				secret.Data = map[string][]byte{}
+			}
+			secret.Data[signingKeysKey] = data
+			_, err = s.secrets.Update(secret)
+		}
+		if err != nil {
+			return err
+		}
+		s.setCache(keys)
+		return nil
+	})
+	if err != nil {
+		return signingKey{}, fmt.Errorf("rotating the JWT signing key: %w", err)
+	}
+	return key, nil
+}
+
+// published returns the keys which can have signed valid JWTs.
+func (s *keyStore) published() ([]signingKey, error) {
+	keys, err := s.keys(false)
+	if err != nil {
+		return nil, err
+	}
+	return s.prune(keys), nil
+}
+
+// publicKey returns the public key of the published key with the ID.
+func (s *keyStore) publicKey(id string) (*rsa.PublicKey, error) {
+	for _, force := range []bool{false, true} {
+		keys, err := s.keys(force)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range s.prune(keys) {
+			if key.ID != id {
+				continue
+			}
+			privateKey, err := key.privateKey()
+			if err != nil {
+				return nil, err
+			}
+			return &privateKey.PublicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", id)
+}
+
+// active returns the newest key if it isn't due for rotation.
+func (s *keyStore) active(keys []signingKey) (signingKey, bool) {
+	if len(keys) == 0 {
+		return signingKey{}, false
+	}
+	newest := keys[len(keys)-1]
+	return newest, s.now().Sub(newest.CreatedAt) < s.rotation()
+}
+
+// prune drops the keys replaced for longer than the rotation period, as the JWTs they signed have expired.
+func (s *keyStore) prune(keys []signingKey) []signingKey {
+	cutoff := s.now().Add(-s.rotation())
+	for len(keys) > 1 && keys[1].CreatedAt.Before(cutoff) {
+		keys = keys[1:]
+	}
+	return keys
+}
+
+// keys returns the keys of the secret, which are cached for keysCacheTTL unless force is set.
+func (s *keyStore) keys(force bool) ([]signingKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := s.now().Sub(s.fetchedAt)
+	if !s.fetchedAt.IsZero() && age < keysCacheTTL && (!force || age < minKeyMissRefetchInterval) {
+		return s.cached, nil
+	}
+
+	secret, err := s.secrets.Get(tokens.SecretNamespace, SigningKeysSecretName, metav1.GetOptions{})
+	var keys []signingKey
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("getting the JWT signing keys: %w", err)
+	default:
+		if keys, err = parseKeys(secret); err != nil {
+			return nil, err
+		}
+	}
+	s.cached, s.fetchedAt = keys, s.now()
+	return keys, nil
+}
+
+func (s *keyStore) setCache(keys []signingKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cached, s.fetchedAt = keys, s.now()
+}
+
+func parseKeys(secret *corev1.Secret) ([]signingKey, error) {
+	var keys []signingKey
+	if raw := secret.Data[signingKeysKey]; len(raw) > 0 {
+		if err := json.Unmarshal(raw, &keys); err != nil {
+			return nil, fmt.Errorf("parsing the JWT signing keys of secret %s: %w", secret.Name, err)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,81 @@
+package loginanomalies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+)
+
+const (
+	ipPlaceholder = "{ip}"
+	lookupTimeout = 5 * time.Second
+	// maxLookupResponseSize bounds the responses of the geo lookup service.
+	maxLookupResponseSize = 64 * 1024
+)
+
+// Location is the rough location of an address.
+type Location struct {
+	// Country is the code or name of the country, as given by the Locator.
+	Country   string   `json:"country,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// Locator locates the addresses of the logins.
+type Locator interface {
+	// Locate returns the location of the address, nil if it's unknown.
+	Locate(ip net.IP) (*Location, error)
+}
+
+// HTTPLocator locates the addresses with the service of the login-geo-lookup-url setting.
+type HTTPLocator struct {
+	client *http.Client
+}
+
+// NewHTTPLocator returns a Locator using the service of the login-geo-lookup-url setting.
+func NewHTTPLocator() *HTTPLocator {
+	return &HTTPLocator{client: &http.Client{Timeout: lookupTimeout}}
+}
+
+// Locate returns the location of the address given by the service. The private and local addresses, and all of them
+// when the setting is empty, aren't located.
+func (l *HTTPLocator) Locate(ip net.IP) (*Location, error) {
+	lookupURL := settings.LoginGeoLookupURL.Get()
+	if lookupURL == "" || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+		return nil, nil
+	}
+	lookupURL = strings.ReplaceAll(lookupURL, ipPlaceholder, url.PathEscape(ip.String()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lookupURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s setting: %w", settings.LoginGeoLookupURL.Name, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the geo lookup service responded %d", resp.StatusCode)
+	}
+
+	location := &Location{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxLookupResponseSize)).Decode(location); err != nil {
+		return nil, fmt.Errorf("invalid response of the geo lookup service: %w", err)
+	}
+	if location.Country == "" && (location.Latitude == nil || location.Longitude == nil) {
+		return nil, nil
+	}
+	return location, nil
+}
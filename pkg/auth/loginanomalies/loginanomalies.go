@@ -0,0 +1,374 @@
+// Package loginanomalies detects the unusual logins of the users, when login-anomaly-detection is enabled. It keeps the
+// fingerprints of the logins of each user, their source network, user agent and rough location, and raises an auth
+// event and a notification for the first login of a user from a country, and for the logins of a user from distant
+// networks within minutes of each other. The logins are located with the service of login-geo-lookup-url, or with
+// another Locator.
+package loginanomalies
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/notifications"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// The reasons of the LoginAnomaly auth events.
+const (
+	// ReasonFirstSeenCountry is the reason of the first login of a user from a country.
+	ReasonFirstSeenCountry = "FirstSeenCountry"
+	// ReasonDistantConcurrentLogin is the reason of a login of a user from a network distant from the network of
+	// another of their recent logins.
+	ReasonDistantConcurrentLogin = "DistantConcurrentLogin"
+)
+
+const (
+	historySecretPrefix = "login-history-"
+	historyKey          = "history"
+	// maxNetworks, maxCountries and maxUserAgents are how many networks, countries and user agents are remembered per
+	// user, the ones used the longest ago are forgotten first.
+	maxNetworks   = 50
+	maxCountries  = 50
+	maxUserAgents = 20
+	// lastSeenResolution is how stale the last login from a network can be before it's updated, so that the logins
+	// in a row don't all update the secret.
+	lastSeenResolution = time.Minute
+	// The logins are grouped by network: the /24 IPv4 and /48 IPv6 networks.
+	ipv4NetworkBits = 24
+	ipv6NetworkBits = 48
+	earthRadiusKm   = 6371
+)
+
+// HistorySecretName returns the name of the secret holding the fingerprints of the logins of the user.
+func HistorySecretName(userID string) string {
+	return historySecretPrefix + userID
+}
+
+// history holds the fingerprints of the logins of a user.
+type history struct {
+	// Networks are the networks the user logged in from, by CIDR.
+	Networks map[string]network `json:"networks,omitempty"`
+	// Countries are the last logins of the user from each country.
+	Countries map[string]time.Time `json:"countries,omitempty"`
+	// UserAgents are the last logins of the user with each user agent, by hash.
+	UserAgents map[string]time.Time `json:"userAgents,omitempty"`
+}
+
+type network struct {
+	LastSeen time.Time `json:"lastSeen"`
+	Location
+}
+
+// login is a login to check.
+type login struct {
+	network   string
+	location  Location
+	userAgent string
+	at        time.Time
+}
+
+// anomaly is an unusual login.
+type anomaly struct {
+	reason string
+	// description is a sentence describing the anomaly, e.g. "It's the first login from country FR".
+	description string
+}
+
+// Detector detects the unusual logins. A nil Detector detects none.
+type Detector struct {
+	secrets  wcorev1.SecretClient
+	locator  Locator
+	notifier *notifications.Notifier
+	now      func() time.Time
+	async    func(func())
+}
+
+// NewDetector returns a Detector locating the logins with the service of login-geo-lookup-url, and notifying the
+// users with the notifier.
+func NewDetector(mgmt *config.ScaledContext, notifier *notifications.Notifier) *Detector {
+	return newDetector(mgmt.Wrangler.Core.Secret(), NewHTTPLocator(), notifier)
+}
+
+func newDetector(secrets wcorev1.SecretClient, locator Locator, notifier *notifications.Notifier) *Detector {
+	return &Detector{
+		secrets:  secrets,
+		locator:  locator,
+		notifier: notifier,
+		now:      time.Now,
+		async:    func(f func()) { go f() },
+	}
+}
+
+// Login records the fingerprint of a successful login of the user, and raises an event and a notification when it's
+// unusual. The first login of a user isn't unusual.
+func (d *Detector) Login(user *v3.User, provider string, source net.IP, userAgent string) {
+	if d == nil || source == nil || settings.LoginAnomalyDetection.Get() != "true" {
+		return
+	}
+	d.async(func() {
+		anomalies, err := d.login(user, source, userAgent)
+		if err != nil {
+			logrus.Errorf("[loginanomalies] failed to check the login of user %s: %v", user.Name, err)
+			return
+		}
+		for _, a := range anomalies {
+			authevents.Record(authevents.Event{
+				Type:     authevents.LoginAnomaly,
+				UserName: user.Name,
+				Provider: provider,
+				SourceIP: source,
+				Reason:   a.reason,
+				Detail:   a.description,
+			})
+			d.notifier.LoginAnomaly(user, provider, source, userAgent, a.description)
+		}
+	})
+}
+
+func (d *Detector) login(user *v3.User, source net.IP, userAgent string) ([]anomaly, error) {
+	l := login{
+		network:   networkOf(source),
+		userAgent: userAgentKey(userAgent),
+		at:        d.now(),
+	}
+	if location, err := d.locator.Locate(source); err != nil {
+		// The login is still checked, without its location.
+		logrus.Warnf("[loginanomalies] failed to locate %s: %v", source, err)
+	} else if location != nil {
+		l.location = *location
+	}
+
+	var anomalies []anomaly
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := d.secrets.Get(tokens.SecretNamespace, HistorySecretName(user.Name), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("getting the login history: %w", err)
+		}
+		first := apierrors.IsNotFound(err)
+
+		h := &history{}
+		if !first {
+			if h, err = parseHistory(secret); err != nil {
+				logrus.Warnf("[loginanomalies] resetting the invalid login history of user %s: %v", user.Name, err)
+				h = &history{}
+			}
+		}
+		anomalies = detect(h, l, windowSetting(), distanceSetting())
+		if !h.add(l) {
+			return nil
+		}
+
+		data, err := json.Marshal(h)
+		if err != nil {
+			return err
+		}
+		if first {
+			_, err = d.secrets.Create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      HistorySecretName(user.Name),
+					Namespace: tokens.SecretNamespace,
+					Labels:    map[string]string{tokens.UserIDLabel: user.Name},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: v3.UserGroupVersionKind.GroupVersion().String(),
+						Kind:       v3.UserGroupVersionKind.Kind,
+						Name:       user.Name,
+						UID:        user.UID,
+					}},
+				},
+				Data: map[string][]byte{historyKey: data},
+			})
+			if apierrors.IsAlreadyExists(err) {
+				// Another login of the user created it first.
+				return apierrors.NewConflict(corev1.Resource("secrets"), HistorySecretName(user.Name), err)
+			}
+		} else {
+			secret = secret.DeepCopy()
+			secret.Data = map[string][]byte{historyKey: data}
+			_, err = d.secrets.Update(secret)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("recording the login: %w", err)
+	}
+	return anomalies, nil
+}
+
+// detect returns the anomalies of the login, given the previous logins of the user.
+func detect(h *history, l login, window time.Duration, distanceKm float64) []anomaly {
+	var anomalies []anomaly
+	// The countries are only compared once the logins of the user were located before.
+	if country := l.location.Country; country != "" && len(h.Countries) > 0 {
+		if _, ok := h.Countries[country]; !ok {
+			anomalies = append(anomalies, anomaly{
+				reason:      ReasonFirstSeenCountry,
+				description: fmt.Sprintf("It's the first login from country %s", country),
+			})
+		}
+	}
+
+	// The most recent distant login is reported.
+	var recent string
+	var recentNetwork network
+	for cidr, n := range h.Networks {
+		if cidr == l.network || l.at.Sub(n.LastSeen) > window || !distant(n.Location, l.location, distanceKm) {
+			continue
+		}
+		if recent == "" || n.LastSeen.After(recentNetwork.LastSeen) {
+			recent, recentNetwork = cidr, n
+		}
+	}
+	if recent != "" {
+		where := recent
+		if recentNetwork.Country != "" {
+			where += " in " + recentNetwork.Country
+		}
+		anomalies = append(anomalies, anomaly{
+			reason: ReasonDistantConcurrentLogin,
+			description: fmt.Sprintf("It's %s after a login from the distant network %s",
+				l.at.Sub(recentNetwork.LastSeen).Round(time.Minute), where),
+		})
+	}
+	return anomalies
+}
+
+// distant tells whether the locations are further apart than distanceKm. The locations whose coordinates are unknown
+// are distant when they are in different countries, and those whose country is unknown aren't.
+func distant(a, b Location, distanceKm float64) bool {
+	if a.Latitude != nil && a.Longitude != nil && b.Latitude != nil && b.Longitude != nil {
+		return haversineKm(*a.Latitude, *a.Longitude, *b.Latitude, *b.Longitude) >= distanceKm
+	}
+	return a.Country != "" && b.Country != "" && a.Country != b.Country
+}
+
+// haversineKm returns the great-circle distance between two points, in kilometers.
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// add adds the login to the history, and returns whether it changed.
+func (h *history) add(l login) bool {
+	if h.Networks == nil {
+		h.Networks = map[string]network{}
+	}
+	if h.Countries == nil {
+		h.Countries = map[string]time.Time{}
+	}
+	if h.UserAgents == nil {
+		h.UserAgents = map[string]time.Time{}
+	}
+
+	var changed bool
+	if n, ok := h.Networks[l.network]; !ok || l.at.Sub(n.LastSeen) >= lastSeenResolution {
+		h.Networks[l.network] = network{LastSeen: l.at, Location: l.location}
+		changed = true
+	}
+	if l.location.Country != "" {
+		if lastSeen, ok := h.Countries[l.location.Country]; !ok || l.at.Sub(lastSeen) >= lastSeenResolution {
+			h.Countries[l.location.Country] = l.at
+			changed = true
+		}
+	}
+	if lastSeen, ok := h.UserAgents[l.userAgent]; !ok || l.at.Sub(lastSeen) >= lastSeenResolution {
+		h.UserAgents[l.userAgent] = l.at
+		changed = true
+	}
+
+	prune(h.Countries, maxCountries)
+	prune(h.UserAgents, maxUserAgents)
+	if len(h.Networks) > maxNetworks {
+		lastSeen := make(map[string]time.Time, len(h.Networks))
+		for cidr, n := range h.Networks {
+			lastSeen[cidr] = n.LastSeen
+		}
+		for _, cidr := range oldest(lastSeen, len(h.Networks)-maxNetworks) {
+			delete(h.Networks, cidr)
+		}
+	}
+	return changed
+}
+
+// prune removes the entries used the longest ago beyond the maximum.
+func prune(entries map[string]time.Time, max int) {
+	if len(entries) <= max {
+		return
+	}
+	for _, key := range oldest(entries, len(entries)-max) {
+		delete(entries, key)
+	}
+}
+
+// oldest returns the count keys used the longest ago.
+func oldest(entries map[string]time.Time, count int) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return entries[keys[i]].Before(entries[keys[j]])
+	})
+	return keys[:count]
+}
+
+func parseHistory(secret *corev1.Secret) (*history, error) {
+	h := &history{}
+	if data := secret.Data[historyKey]; len(data) > 0 {
+		if err := json.Unmarshal(data, h); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// networkOf returns the network of the address, as a CIDR.
+func networkOf(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(ipv4NetworkBits, 32)), Mask: net.CIDRMask(ipv4NetworkBits, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(ipv6NetworkBits, 128)), Mask: net.CIDRMask(ipv6NetworkBits, 128)}).String()
+}
+
+// userAgentKey identifies the user agent of a login. It's hashed, so that the secret doesn't keep the user agents.
+func userAgentKey(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+func windowSetting() time.Duration {
+	minutes, err := strconv.Atoi(settings.LoginAnomalyWindowMinutes.Get())
+	if err != nil || minutes < 0 {
+		logrus.Errorf("[loginanomalies] invalid %s setting %q, using the default", settings.LoginAnomalyWindowMinutes.Name, settings.LoginAnomalyWindowMinutes.Get())
+		minutes, _ = strconv.Atoi(settings.LoginAnomalyWindowMinutes.Default)
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func distanceSetting() float64 {
+	km, err := strconv.ParseFloat(settings.LoginAnomalyDistanceKm.Get(), 64)
+	if err != nil || km < 0 {
+		logrus.Errorf("[loginanomalies] invalid %s setting %q, using the default", settings.LoginAnomalyDistanceKm.Name, settings.LoginAnomalyDistanceKm.Get())
+		km, _ = strconv.ParseFloat(settings.LoginAnomalyDistanceKm.Default, 64)
+	}
+	return km
+}
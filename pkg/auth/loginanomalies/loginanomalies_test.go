@@ -0,0 +1,213 @@
+package loginanomalies
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeLocator map[string]*Location
+
+func (f fakeLocator) Locate(ip net.IP) (*Location, error) {
+	return f[ip.String()], nil
+}
+
+func coordinates(country string, latitude, longitude float64) *Location {
+	return &Location{Country: country, Latitude: &latitude, Longitude: &longitude}
+}
+
+var (
+	paris  = net.ParseIP("81.0.0.1")
+	lyon   = net.ParseIP("82.0.0.1")
+	berlin = net.ParseIP("85.0.0.1")
+
+	locator = fakeLocator{
+		paris.String():  coordinates("FR", 48.86, 2.35),
+		lyon.String():   coordinates("FR", 45.76, 4.84),
+		berlin.String(): coordinates("DE", 52.52, 13.40),
+	}
+)
+
+func testUser() *v3.User {
+	return &v3.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "u-abc", UID: "uid"},
+		Username:   "alice",
+	}
+}
+
+func newTestDetector(t *testing.T, now *time.Time) (*Detector, map[string]*corev1.Secret) {
+	ctrl := gomock.NewController(t)
+	stored := map[string]*corev1.Secret{}
+	gr := schema.GroupResource{Resource: "secrets"}
+	version := 0
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		version++
+		created := secret.DeepCopy()
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if stored[secret.Name].ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+
+	d := newDetector(secrets, locator, nil)
+	d.now = func() time.Time { return *now }
+	d.async = func(f func()) { f() }
+	return d, stored
+}
+
+func reasons(anomalies []anomaly) []string {
+	var reasons []string
+	for _, a := range anomalies {
+		reasons = append(reasons, a.reason)
+	}
+	return reasons
+}
+
+func TestLogin(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := testUser()
+	d, stored := newTestDetector(t, &now)
+
+	// The first login isn't unusual.
+	anomalies, err := d.login(user, paris, "Firefox")
+	require.NoError(t, err)
+	assert.Empty(t, anomalies)
+	require.Contains(t, stored, HistorySecretName(user.Name))
+	assert.Equal(t, user.Name, stored[HistorySecretName(user.Name)].Labels[tokens.UserIDLabel])
+	assert.NotContains(t, string(stored[HistorySecretName(user.Name)].Data[historyKey]), "Firefox")
+
+	// A nearby network of the same country isn't unusual.
+	now = now.Add(5 * time.Minute)
+	anomalies, err = d.login(user, lyon, "Firefox")
+	require.NoError(t, err)
+	assert.Empty(t, anomalies)
+
+	// A new country within minutes of a login from a distant network is unusual twice.
+	now = now.Add(5 * time.Minute)
+	anomalies, err = d.login(user, berlin, "Firefox")
+	require.NoError(t, err)
+	assert.Equal(t, []string{ReasonFirstSeenCountry, ReasonDistantConcurrentLogin}, reasons(anomalies))
+	assert.Equal(t, "It's the first login from country DE", anomalies[0].description)
+	assert.Equal(t, "It's 5m0s after a login from the distant network 82.0.0.0/24 in FR", anomalies[1].description)
+
+	// A known country long after the last login from a distant network isn't unusual.
+	now = now.Add(2 * time.Hour)
+	anomalies, err = d.login(user, paris, "Firefox")
+	require.NoError(t, err)
+	assert.Empty(t, anomalies)
+}
+
+func TestLoginDisabled(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	d, stored := newTestDetector(t, &now)
+
+	d.Login(testUser(), "local", paris, "Firefox")
+	assert.Empty(t, stored)
+
+	require.NoError(t, settings.LoginAnomalyDetection.Set("true"))
+	defer settings.LoginAnomalyDetection.Set(settings.LoginAnomalyDetection.Default)
+	d.Login(testUser(), "local", paris, "Firefox")
+	assert.Contains(t, stored, HistorySecretName(testUser().Name))
+
+	var none *Detector
+	none.Login(testUser(), "local", paris, "Firefox")
+}
+
+func TestDetectWithoutCoordinates(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := &history{}
+	h.add(login{network: "10.0.0.0/24", location: Location{Country: "FR"}, at: now})
+	h.add(login{network: "10.0.1.0/24", at: now})
+
+	// The networks in different countries are distant, those whose country is unknown aren't.
+	anomalies := detect(h, login{network: "10.0.2.0/24", location: Location{Country: "DE"}, at: now.Add(time.Minute)}, 30*time.Minute, 500)
+	assert.Equal(t, []string{ReasonFirstSeenCountry, ReasonDistantConcurrentLogin}, reasons(anomalies))
+
+	anomalies = detect(h, login{network: "10.0.2.0/24", at: now.Add(time.Minute)}, 30*time.Minute, 500)
+	assert.Empty(t, anomalies)
+}
+
+func TestHistoryAdd(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := &history{}
+	assert.True(t, h.add(login{network: "10.0.0.0/24", userAgent: "a", at: now}))
+	// The logins in a row don't change it.
+	assert.False(t, h.add(login{network: "10.0.0.0/24", userAgent: "a", at: now.Add(time.Second)}))
+
+	for i := 0; i < maxNetworks+5; i++ {
+		h.add(login{network: fmt.Sprintf("10.0.%d.0/24", i+1), userAgent: "a", at: now.Add(time.Duration(i+1) * time.Hour)})
+	}
+	assert.Len(t, h.Networks, maxNetworks)
+	assert.NotContains(t, h.Networks, "10.0.0.0/24")
+	assert.Contains(t, h.Networks, fmt.Sprintf("10.0.%d.0/24", maxNetworks+5))
+}
+
+func TestNetworkOf(t *testing.T) {
+	assert.Equal(t, "81.2.3.0/24", networkOf(net.ParseIP("81.2.3.4")))
+	assert.Equal(t, "2001:db8:1::/48", networkOf(net.ParseIP("2001:db8:1:2::1")))
+}
+
+func TestHaversineKm(t *testing.T) {
+	assert.InDelta(t, 878, haversineKm(48.86, 2.35, 52.52, 13.40), 5)
+	assert.InDelta(t, 0, haversineKm(48.86, 2.35, 48.86, 2.35), 0.001)
+}
+
+func TestHTTPLocator(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"country":"FR","latitude":48.86,"longitude":2.35,"city":"Paris"}`))
+	}))
+	defer server.Close()
+	l := NewHTTPLocator()
+
+	// Nothing is located without the setting.
+	location, err := l.Locate(paris)
+	require.NoError(t, err)
+	assert.Nil(t, location)
+
+	require.NoError(t, settings.LoginGeoLookupURL.Set(server.URL+"/json/{ip}"))
+	defer settings.LoginGeoLookupURL.Set(settings.LoginGeoLookupURL.Default)
+
+	location, err = l.Locate(paris)
+	require.NoError(t, err)
+	assert.Equal(t, "/json/81.0.0.1", path)
+	assert.Equal(t, coordinates("FR", 48.86, 2.35), location)
+
+	// The private addresses aren't located.
+	location, err = l.Locate(net.ParseIP("10.0.0.1"))
+	require.NoError(t, err)
+	assert.Nil(t, location)
+}
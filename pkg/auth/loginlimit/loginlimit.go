@@ -0,0 +1,242 @@
+// Package loginlimit protects the logins with a username and password from brute-force attacks. The failed logins are
+// counted per username and per client address: each failure delays the next attempt exponentially, and too many
+// failures lock the username or the client address out for auth-login-lockout-minutes. The failures are also counted
+// when the delays and lockouts are disabled, for the challenges of the captcha package.
+// The counters are kept in memory by each Rancher server.
+package loginlimit
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/auth/providers/activedirectory"
+	"github.com/rancher/rancher/pkg/auth/providers/ldap"
+	"github.com/rancher/rancher/pkg/auth/providers/local"
+	"github.com/rancher/rancher/pkg/metrics"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ScopeUsername is the scope of the counters of the usernames.
+	ScopeUsername = "username"
+	// ScopeSource is the scope of the counters of the client addresses.
+	ScopeSource = "source"
+
+	baseDelay = time.Second
+	maxDelay  = 30 * time.Second
+	// maxCounters bounds the memory used by an attack from many addresses or on many usernames.
+	maxCounters = 100000
+)
+
+var (
+	// TooManyAttemptsErrorCode is the code of the logins rejected until the username or the client address can try
+	// again.
+	TooManyAttemptsErrorCode = httperror.ErrorCode{Code: "TooManyLoginAttempts", Status: http.StatusTooManyRequests}
+
+	// limitedProviders are the providers of logins with a username and password.
+	limitedProviders = map[string]bool{
+		local.Name:           true,
+		activedirectory.Name: true,
+		ldap.OpenLdapName:    true,
+		ldap.FreeIpaName:     true,
+	}
+)
+
+// counter holds the recent failed logins of a username or a client address.
+type counter struct {
+	failures    int
+	lastFailure time.Time
+	// retryAt is when the next attempt is allowed.
+	retryAt time.Time
+	// lockedUntil is the end of the lockout, zero if it isn't locked out.
+	lockedUntil time.Time
+}
+
+// Limiter counts the failed logins and tells how long the next ones must wait.
+type Limiter struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+	now      func() time.Time
+}
+
+// NewLimiter returns a Limiter without failed logins.
+func NewLimiter() *Limiter {
+	return &Limiter{
+		counters: map[string]*counter{},
+		now:      time.Now,
+	}
+}
+
+// Limited tells whether the logins with the provider are protected.
+func Limited(provider string) bool {
+	return limitedProviders[provider]
+}
+
+// Check returns how long the client must wait before logging in with the username, 0 if it can try now.
+// The source is the address of the client, nil if unknown.
+func (l *Limiter) Check(provider, username string, source net.IP) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	var throttled string
+	for _, scope := range l.scopes(provider, username, source) {
+		if w := l.wait(scope, now); w > wait {
+			wait, throttled = w, scope.name
+		}
+	}
+	if wait > 0 {
+		metrics.IncLoginThrottled(throttled)
+	}
+	return wait
+}
+
+// Failures returns the recent failed logins of the username or of the source, whichever has the most.
+func (l *Limiter) Failures(provider, username string, source net.IP) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var failures int
+	for _, scope := range l.scopes(provider, username, source) {
+		if c, ok := l.counters[scope.key]; ok && !expired(c, now) && c.failures > failures {
+			failures = c.failures
+		}
+	}
+	return failures
+}
+
+// Fail records a failed login with the username from the source, and locks them out when they reach their limit.
+func (l *Limiter) Fail(provider, username string, source net.IP) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	metrics.IncLoginFailures(provider)
+	now := l.now()
+	for _, scope := range l.scopes(provider, username, source) {
+		if l.fail(scope.key, scope.maxFailures, now) {
+			metrics.IncLoginLockouts(scope.name)
+			logrus.Warnf("[loginlimit] %s %s of provider %s is locked out for %v after %d failed logins",
+				scope.name, scope.value, provider, lockoutDuration(), scope.maxFailures)
+		}
+	}
+}
+
+// Succeed forgets the failed logins with the username. Those of the client address are kept, so that an attacker
+// can't reset them with an account of their own.
+func (l *Limiter) Succeed(provider, username string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.counters, usernameKey(provider, username))
+}
+
+type scope struct {
+	name        string
+	value       string
+	key         string
+	maxFailures int
+}
+
+// scopes returns the scopes of a login, with their limits, 0 when their delays and lockouts are disabled.
+func (l *Limiter) scopes(provider, username string, source net.IP) []scope {
+	scopes := []scope{{
+		name:        ScopeUsername,
+		value:       username,
+		key:         usernameKey(provider, username),
+		maxFailures: settings.AuthLoginMaxFailuresPerUsername.GetInt(),
+	}}
+	if source != nil {
+		scopes = append(scopes, scope{
+			name:        ScopeSource,
+			value:       source.String(),
+			key:         ScopeSource + "/" + source.String(),
+			maxFailures: settings.AuthLoginMaxFailuresPerSource.GetInt(),
+		})
+	}
+	return scopes
+}
+
+// wait returns how long the attempts of the scope must wait.
+func (l *Limiter) wait(scope scope, now time.Time) time.Duration {
+	c, ok := l.counters[scope.key]
+	if !ok {
+		return 0
+	}
+	if expired(c, now) {
+		delete(l.counters, scope.key)
+		return 0
+	}
+	if scope.maxFailures <= 0 {
+		return 0
+	}
+	until := c.retryAt
+	if c.lockedUntil.After(until) {
+		until = c.lockedUntil
+	}
+	if now.Before(until) {
+		return until.Sub(now)
+	}
+	return 0
+}
+
+// fail counts a failure of the key, and tells whether it locked the key out. The failures of the keys without a limit
+// are only counted.
+func (l *Limiter) fail(key string, maxFailures int, now time.Time) bool {
+	c, ok := l.counters[key]
+	if !ok || expired(c, now) {
+		if len(l.counters) >= maxCounters {
+			l.prune(now)
+		}
+		if len(l.counters) >= maxCounters {
+			logrus.Warnf("[loginlimit] too many failed logins to track, not counting more of them")
+			return false
+		}
+		c = &counter{}
+		l.counters[key] = c
+	}
+
+	c.failures++
+	c.lastFailure = now
+	if maxFailures <= 0 {
+		return false
+	}
+	delay := maxDelay
+	if shift := c.failures - 1; shift < 8 && baseDelay<<shift < maxDelay {
+		delay = baseDelay << shift
+	}
+	c.retryAt = now.Add(delay)
+	if c.failures == maxFailures {
+		c.lockedUntil = now.Add(lockoutDuration())
+		return true
+	}
+	return false
+}
+
+// prune forgets the expired counters.
+func (l *Limiter) prune(now time.Time) {
+	for key, c := range l.counters {
+		if expired(c, now) {
+			delete(l.counters, key)
+		}
+	}
+}
+
+// expired tells whether the failures of the counter are too old to be remembered, and its lockout is over.
+func expired(c *counter, now time.Time) bool {
+	return !now.Before(c.lastFailure.Add(lockoutDuration())) && !now.Before(c.lockedUntil)
+}
+
+func lockoutDuration() time.Duration {
+	return time.Duration(settings.AuthLoginLockoutMinutes.GetInt()) * time.Minute
+}
+
+func usernameKey(provider, username string) string {
+	return ScopeUsername + "/" + provider + "/" + strings.ToLower(username)
+}
@@ -0,0 +1,119 @@
+package loginlimit
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(now *time.Time) *Limiter {
+	l := NewLimiter()
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestUsernameLockout(t *testing.T) {
+	defer settings.AuthLoginMaxFailuresPerUsername.Set(settings.AuthLoginMaxFailuresPerUsername.Default)
+	require.NoError(t, settings.AuthLoginMaxFailuresPerUsername.Set("4"))
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	source := net.ParseIP("10.0.0.1")
+
+	assert.Zero(t, l.Check("local", "alice", source))
+
+	// Each failure doubles the delay before the next attempt.
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		l.Fail("local", "alice", source)
+		assert.Equal(t, delay, l.Check("local", "Alice", source), i)
+		assert.Zero(t, l.Check("local", "bob", net.ParseIP("10.0.0.2")), i)
+		assert.Zero(t, l.Check("openldap", "alice", net.ParseIP("10.0.0.2")), i)
+		now = now.Add(delay)
+	}
+
+	// The username is locked out when it reaches its limit, from all addresses.
+	l.Fail("local", "alice", source)
+	assert.Equal(t, 15*time.Minute, l.Check("local", "alice", net.ParseIP("10.0.0.2")))
+	now = now.Add(15 * time.Minute)
+	assert.Zero(t, l.Check("local", "alice", source))
+
+	// The lockout is over, and the failures forgotten.
+	l.Fail("local", "alice", source)
+	assert.Equal(t, time.Second, l.Check("local", "alice", source))
+
+	// A successful login forgets the failures of the username.
+	l.Succeed("local", "alice")
+	assert.Zero(t, l.Check("local", "alice", net.ParseIP("10.0.0.2")))
+}
+
+func TestSourceLockout(t *testing.T) {
+	defer settings.AuthLoginMaxFailuresPerUsername.Set(settings.AuthLoginMaxFailuresPerUsername.Default)
+	defer settings.AuthLoginMaxFailuresPerSource.Set(settings.AuthLoginMaxFailuresPerSource.Default)
+	require.NoError(t, settings.AuthLoginMaxFailuresPerUsername.Set("0"))
+	require.NoError(t, settings.AuthLoginMaxFailuresPerSource.Set("3"))
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	source := net.ParseIP("10.0.0.1")
+
+	for _, username := range []string{"alice", "bob", "carol"} {
+		l.Fail("local", username, source)
+		now = now.Add(time.Minute)
+	}
+	assert.Equal(t, 14*time.Minute, l.Check("local", "dave", source))
+	assert.Zero(t, l.Check("local", "dave", net.ParseIP("10.0.0.2")))
+	assert.Zero(t, l.Check("local", "dave", nil))
+
+	// The failures of the address aren't forgotten on a successful login.
+	l.Succeed("local", "dave")
+	assert.Equal(t, 14*time.Minute, l.Check("local", "dave", source))
+}
+
+func TestFailuresExpire(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	source := net.ParseIP("10.0.0.1")
+
+	for i := 0; i < 9; i++ {
+		l.Fail("activedirectory", "alice", source)
+	}
+	assert.Equal(t, maxDelay, l.Check("activedirectory", "alice", source))
+
+	// The failures older than the lockout duration are forgotten.
+	now = now.Add(15 * time.Minute)
+	assert.Zero(t, l.Check("activedirectory", "alice", source))
+	assert.Empty(t, l.counters)
+	l.Fail("activedirectory", "alice", source)
+	assert.Equal(t, time.Second, l.Check("activedirectory", "alice", source))
+}
+
+func TestLimited(t *testing.T) {
+	assert.True(t, Limited("local"))
+	assert.True(t, Limited("freeipa"))
+	assert.False(t, Limited("github"))
+}
+
+func TestFailures(t *testing.T) {
+	defer settings.AuthLoginMaxFailuresPerUsername.Set(settings.AuthLoginMaxFailuresPerUsername.Default)
+	require.NoError(t, settings.AuthLoginMaxFailuresPerUsername.Set("0"))
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	source := net.ParseIP("10.0.0.1")
+
+	// The failures are counted without delays when the limit is disabled.
+	l.Fail("local", "alice", nil)
+	l.Fail("local", "alice", nil)
+	l.Fail("local", "bob", source)
+	assert.Zero(t, l.Check("local", "alice", nil))
+	assert.Equal(t, 2, l.Failures("local", "alice", source))
+	assert.Equal(t, 1, l.Failures("local", "carol", source))
+	assert.Zero(t, l.Failures("local", "carol", net.ParseIP("10.0.0.2")))
+
+	now = now.Add(15 * time.Minute)
+	assert.Zero(t, l.Failures("local", "alice", source))
+}
@@ -0,0 +1,404 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/notifications"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/securityevents"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// BasePath is the path of the MFA endpoints, the only ones accepting the tokens of enrollment logins.
+	BasePath = "/v1-mfa"
+
+	// enrollAction generates a new TOTP key for the caller.
+	enrollAction = "enroll"
+	// verifyAction confirms the enrollment of the caller with a code of the new key.
+	verifyAction = "verify"
+	// regenerateRecoveryCodesAction replaces the recovery codes of a user.
+	regenerateRecoveryCodesAction = "regenerate-recovery-codes"
+
+	maxBodySize = 1024
+)
+
+// Status is the enrollment of a user.
+type Status struct {
+	// Enabled tells whether the logins of the user need a code.
+	Enabled bool `json:"enabled"`
+	// Pending tells whether the user has a key waiting for a code to confirm the enrollment.
+	Pending bool `json:"pending"`
+	// RecoveryCodes is how many recovery codes of the user haven't been used.
+	RecoveryCodes int `json:"recoveryCodes"`
+}
+
+// Enrollment is the new key of a user, to add to an authenticator app.
+type Enrollment struct {
+	Secret string `json:"secret"`
+	// URI is the otpauth provisioning URI of the key, to show as a QR code.
+	URI string `json:"uri"`
+}
+
+// RecoveryCodes are the one-time codes a user can log in with instead of a TOTP code, for when they lose their
+// device. They are only shown when generated.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type codeInput struct {
+	Code string `json:"code"`
+}
+
+type handler struct {
+	manager              *Manager
+	userCache            mgmtcontrollers.UserCache
+	tokenCache           mgmtcontrollers.TokenCache
+	tokens               mgmtcontrollers.TokenClient
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+	notifier             *notifications.Notifier
+}
+
+// NewHandler returns the handler of the MFA endpoints.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	h := &handler{
+		manager:              NewManager(mgmt.Wrangler.Core.Secret()),
+		userCache:            mgmt.Wrangler.Mgmt.User().Cache(),
+		tokenCache:           mgmt.Wrangler.Mgmt.Token().Cache(),
+		tokens:               mgmt.Wrangler.Mgmt.Token(),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+		notifier:             notifications.NewNotifier(mgmt),
+	}
+	return h.router()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodGet).Path(BasePath).HandlerFunc(h.status)
+	root.Methods(http.MethodPost).Path(BasePath).Queries("action", enrollAction).HandlerFunc(h.enroll)
+	root.Methods(http.MethodPost).Path(BasePath).Queries("action", verifyAction).HandlerFunc(h.verify)
+	root.Methods(http.MethodPost).Path(BasePath).Queries("action", regenerateRecoveryCodesAction).HandlerFunc(h.regenerateRecoveryCodes)
+	root.Methods(http.MethodDelete).Path(BasePath).HandlerFunc(h.unenroll)
+	root.Methods(http.MethodGet).Path(BasePath + "/users/{user}").HandlerFunc(h.userStatus)
+	root.Methods(http.MethodPost).Path(BasePath+"/users/{user}").Queries("action", regenerateRecoveryCodesAction).HandlerFunc(h.forceRegenerateRecoveryCodes)
+	root.Methods(http.MethodDelete).Path(BasePath + "/users/{user}").HandlerFunc(h.reset)
+	return root
+}
+
+// status writes the enrollment of the caller.
+func (h *handler) status(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	h.writeStatus(w, r, userInfo.GetName())
+}
+
+// userStatus writes the enrollment of the user of the path, with how many recovery codes they have left.
+// It's only allowed to those who can get any user.
+func (h *handler) userStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizeAdmin(w, r, "get")
+	if !ok {
+		return
+	}
+	h.writeStatus(w, r, userID)
+}
+
+func (h *handler) writeStatus(w http.ResponseWriter, r *http.Request, userID string) {
+	secret, err := h.manager.enrollment(userID)
+	if err != nil {
+		logrus.Errorf("[mfa] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get the MFA status")
+		return
+	}
+	status := Status{
+		Enabled: confirmed(secret),
+		Pending: secret != nil && !confirmed(secret),
+	}
+	if status.Enabled {
+		status.RecoveryCodes = len(recoveryCodeHashes(secret))
+	}
+	util.WriteJSON(w, http.StatusOK, status)
+}
+
+// enroll generates a new key for the caller, which must be confirmed with a code before it's used for logins.
+func (h *handler) enroll(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	u, err := h.userCache.Get(userInfo.GetName())
+	if err != nil {
+		logrus.Errorf("[mfa] failed to get user %s: %v", userInfo.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to enroll")
+		return
+	}
+
+	key, err := h.manager.enroll(u)
+	if errors.Is(err, errAlreadyEnrolled) {
+		util.ReturnHTTPError(w, r, http.StatusConflict, "MFA is already enabled, it must be disabled before enrolling again")
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[mfa] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to enroll")
+		return
+	}
+
+	account := u.Username
+	if account == "" {
+		account = u.Name
+	}
+	util.WriteJSON(w, http.StatusCreated, Enrollment{
+		Secret: secretEncoding.EncodeToString(key),
+		URI:    provisioningURI(key, account),
+	})
+}
+
+// verify confirms the pending enrollment of the caller, and writes their recovery codes. The enrollment login of the
+// request, if any, is logged out, so that the user logs in again with a code.
+func (h *handler) verify(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	input, ok := readCode(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.manager.confirm(userInfo.GetName(), input.Code)
+	if err != nil {
+		if errors.Is(err, errInvalidCode) {
+			util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid TOTP code")
+			return
+		}
+		logrus.Errorf("[mfa] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to verify the code")
+		return
+	}
+	h.mfaChanged(r, userInfo.GetName(), "Two-factor authentication with an authenticator app was enabled")
+
+	if ids := userInfo.GetExtra()[common.ExtraRequestTokenID]; len(ids) > 0 {
+		token, err := h.tokenCache.Get(ids[0])
+		if err == nil && token.Labels[tokens.TokenKindLabel] == tokens.MFAEnrollmentTokenKind {
+			if err := h.tokens.Delete(token.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				logrus.Errorf("[mfa] failed to delete the enrollment token %s: %v", token.Name, err)
+			}
+		}
+	}
+	util.WriteJSON(w, http.StatusOK, RecoveryCodes{RecoveryCodes: codes})
+}
+
+// regenerateRecoveryCodes replaces the recovery codes of the caller, who must prove they still hold the key.
+func (h *handler) regenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	input, ok := readCode(w, r)
+	if !ok {
+		return
+	}
+
+	secret, err := h.manager.enrollment(userInfo.GetName())
+	if err == nil && !confirmed(secret) {
+		err = errNotEnrolled
+	}
+	if err == nil {
+		err = h.manager.checkCode(secret, input.Code)
+	}
+	var codes []string
+	if err == nil {
+		// The secret was updated by the check of the code.
+		secret, err = h.manager.enrollment(userInfo.GetName())
+	}
+	if err == nil {
+		codes, err = h.manager.regenerateRecoveryCodes(secret)
+	}
+	if err == nil {
+		h.mfaChanged(r, userInfo.GetName(), "New recovery codes were generated")
+	}
+	h.writeRecoveryCodes(w, r, codes, err)
+}
+
+// forceRegenerateRecoveryCodes replaces the recovery codes of the user of the path, for the users who used or lost
+// them. The new codes are written to the caller, who hands them to the user.
+// It's only allowed to those who can update any user.
+func (h *handler) forceRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizeAdmin(w, r, "update")
+	if !ok {
+		return
+	}
+	secret, err := h.manager.enrollment(userID)
+	var codes []string
+	if err == nil {
+		codes, err = h.manager.regenerateRecoveryCodes(secret)
+	}
+	if err == nil {
+		userInfo, _ := request.UserFrom(r.Context())
+		logrus.Infof("[mfa] user %s regenerated the recovery codes of user %s", userInfo.GetName(), userID)
+		h.mfaChanged(r, userID, "New recovery codes were generated by an administrator")
+	}
+	h.writeRecoveryCodes(w, r, codes, err)
+}
+
+func (h *handler) writeRecoveryCodes(w http.ResponseWriter, r *http.Request, codes []string, err error) {
+	switch {
+	case errors.Is(err, errNotEnrolled):
+		util.ReturnHTTPError(w, r, http.StatusConflict, "MFA is not enabled")
+	case errors.Is(err, errInvalidCode):
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid TOTP or recovery code")
+	case err != nil:
+		logrus.Errorf("[mfa] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to regenerate the recovery codes")
+	default:
+		util.WriteJSON(w, http.StatusOK, RecoveryCodes{RecoveryCodes: codes})
+	}
+}
+
+// unenroll disables MFA for the caller, who must prove they still hold the key or a recovery code.
+func (h *handler) unenroll(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	input, ok := readCode(w, r)
+	if !ok {
+		return
+	}
+
+	secret, err := h.manager.enrollment(userInfo.GetName())
+	if err == nil && confirmed(secret) {
+		err = h.manager.checkCode(secret, input.Code)
+	}
+	if err == nil {
+		err = h.manager.reset(userInfo.GetName())
+	}
+	if err != nil {
+		if errors.Is(err, errInvalidCode) {
+			util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid TOTP or recovery code")
+			return
+		}
+		logrus.Errorf("[mfa] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to disable MFA")
+		return
+	}
+	h.mfaDisabled(r, userInfo.GetName(), "Two-factor authentication with an authenticator app was disabled")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reset deletes the key of the user of the path, for the users who lost their device.
+// It's only allowed to those who can update any user.
+func (h *handler) reset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizeAdmin(w, r, "update")
+	if !ok {
+		return
+	}
+
+	if err := h.manager.reset(userID); err != nil {
+		logrus.Errorf("[mfa] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to reset MFA")
+		return
+	}
+	userInfo, _ := request.UserFrom(r.Context())
+	logrus.Infof("[mfa] user %s reset the MFA of user %s", userInfo.GetName(), userID)
+	h.mfaDisabled(r, userID, "Two-factor authentication with an authenticator app was disabled by an administrator")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeAdmin returns the user of the path, if the caller is allowed the verb on all users.
+func (h *handler) authorizeAdmin(w http.ResponseWriter, r *http.Request, verb string) (string, bool) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return "", false
+	}
+	userID := mux.Vars(r)["user"]
+	allowed, err := h.authorize(r.Context(), userInfo, verb)
+	if err != nil {
+		logrus.Errorf("[mfa] failed to authorize user %s: %v", userInfo.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return "", false
+	}
+	if !allowed {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to manage the MFA of user %s", userID))
+		return "", false
+	}
+	return userID, true
+}
+
+// authorize tells whether the user is allowed the verb on all users.
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, verb string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    mgmtv3.UserGroupVersionKind.Group,
+		Resource: mgmtv3.UserResource.Name,
+		Verb:     verb,
+	})
+}
+
+func caller(w http.ResponseWriter, r *http.Request) (user.Info, bool) {
+	userInfo, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return nil, false
+	}
+	return userInfo, true
+}
+
+func readCode(w http.ResponseWriter, r *http.Request) (codeInput, bool) {
+	var input codeInput
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&input); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid request body")
+		return input, false
+	}
+	return input, true
+}
+
+// mfaChanged notifies the user of a change of their second factors and records it, with the caller as the actor when
+// they changed those of another user.
+func (h *handler) mfaChanged(r *http.Request, userID, change string) {
+	h.notifier.MFAChanged(userID, change)
+	event := authevents.Event{
+		Type:     authevents.MFAChanged,
+		UserName: userID,
+		Detail:   change,
+	}
+	if userInfo, ok := request.UserFrom(r.Context()); ok && userInfo.GetName() != userID {
+		event.Actor = userInfo.GetName()
+	}
+	authevents.Record(event)
+}
+
+// mfaDisabled records the disabling of a second factor of the user as a security event too.
+func (h *handler) mfaDisabled(r *http.Request, userID, change string) {
+	h.mfaChanged(r, userID, change)
+	event := securityevents.Event{
+		Type:     securityevents.MFADisabled,
+		UserName: userID,
+		Target:   userID,
+		Detail:   change,
+	}
+	if userInfo, ok := request.UserFrom(r.Context()); ok && userInfo.GetName() != userID {
+		event.Actor = userInfo.GetName()
+	}
+	securityevents.Record(event)
+}
@@ -0,0 +1,232 @@
+// Package mfa implements the TOTP second factor of the logins with a username and password: the enrollment of the
+// users, the verification of their codes or one-time recovery codes during the login, and the enforcement of the
+// second factor for the providers listed in auth-mfa-required-providers and the users with the mfa-required annotation.
+package mfa
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/auth/providers/activedirectory"
+	"github.com/rancher/rancher/pkg/auth/providers/ldap"
+	"github.com/rancher/rancher/pkg/auth/providers/local"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RequiredAnnotation requires the second factor for a user, whatever their provider, when set to "true".
+	RequiredAnnotation = "auth.cattle.io/mfa-required"
+
+	secretPrefix   = "mfa-"
+	secretKey      = "secret"
+	confirmedKey   = "confirmed"
+	lastCounterKey = "lastCounter"
+)
+
+var (
+	// RequiredErrorCode is the code of the login errors telling the client that the second factor is needed.
+	RequiredErrorCode = httperror.ErrorCode{Code: "MFARequired", Status: 401}
+
+	// ErrCodeRequired is returned for the logins of enrolled users without a valid second factor.
+	ErrCodeRequired = httperror.NewAPIError(RequiredErrorCode, "a valid second factor is required")
+	// ErrEnrollmentRequired is returned for the logins of the users who must use a second factor but haven't enrolled.
+	ErrEnrollmentRequired = errors.New("TOTP enrollment required")
+
+	errInvalidCode     = errors.New("invalid TOTP code")
+	errAlreadyEnrolled = errors.New("already enrolled")
+	errNotEnrolled     = errors.New("not enrolled")
+
+	// supportedProviders are the providers of logins with a username and password.
+	supportedProviders = map[string]bool{
+		local.Name:           true,
+		activedirectory.Name: true,
+		ldap.OpenLdapName:    true,
+		ldap.FreeIpaName:     true,
+	}
+)
+
+// Manager stores the TOTP keys of the users in secrets, and verifies their codes.
+type Manager struct {
+	secrets wcorev1.SecretClient
+	now     func() time.Time
+}
+
+// NewManager returns a Manager storing the keys with the secrets client.
+func NewManager(secrets wcorev1.SecretClient) *Manager {
+	return &Manager{
+		secrets: secrets,
+		now:     time.Now,
+	}
+}
+
+// SecretName returns the name of the secret holding the TOTP key of the user.
+func SecretName(userID string) string {
+	return secretPrefix + userID
+}
+
+// Required tells whether the user must log in with a second factor with the provider.
+func Required(user *v3.User, provider string) bool {
+	if !supportedProviders[provider] {
+		return false
+	}
+	if user.Annotations[RequiredAnnotation] == "true" {
+		return true
+	}
+	for _, name := range strings.Split(settings.AuthMFARequiredProviders.Get(), ",") {
+		if strings.TrimSpace(name) == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckLogin verifies the second factor of a login of the user with the provider, a TOTP code or a recovery code.
+// It returns ErrCodeRequired when the user has enrolled and the code isn't valid, and ErrEnrollmentRequired when the
+// user must enroll first.
+func (m *Manager) CheckLogin(user *v3.User, provider, code string) error {
+	if !supportedProviders[provider] {
+		return nil
+	}
+	secret, err := m.enrollment(user.Name)
+	if err != nil {
+		return err
+	}
+	if confirmed(secret) {
+		if err := m.checkCode(secret, code); err != nil {
+			if errors.Is(err, errInvalidCode) {
+				return ErrCodeRequired
+			}
+			return err
+		}
+		return nil
+	}
+	if Required(user, provider) {
+		return ErrEnrollmentRequired
+	}
+	return nil
+}
+
+// Enrolled tells whether the user has confirmed a TOTP key.
+func (m *Manager) Enrolled(userID string) (bool, error) {
+	secret, err := m.enrollment(userID)
+	if err != nil {
+		return false, err
+	}
+	return confirmed(secret), nil
+}
+
+// enrollment returns the secret of the TOTP key of the user, nil if the user hasn't enrolled.
+func (m *Manager) enrollment(userID string) (*corev1.Secret, error) {
+	secret, err := m.secrets.Get(tokens.SecretNamespace, SecretName(userID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting the TOTP key of user %s: %w", userID, err)
+	}
+	return secret, nil
+}
+
+// enroll generates a new, unconfirmed TOTP key for the user, replacing the one of a previous unconfirmed enrollment.
+// Users who have confirmed their enrollment must reset it first.
+func (m *Manager) enroll(user *v3.User) ([]byte, error) {
+	key, err := generateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("generating a TOTP key: %w", err)
+	}
+	secret, err := m.enrollment(user.Name)
+	if err != nil {
+		return nil, err
+	}
+	if confirmed(secret) {
+		return nil, errAlreadyEnrolled
+	}
+	if secret != nil {
+		secret.Data = map[string][]byte{secretKey: key}
+		if _, err := m.secrets.Update(secret); err != nil {
+			return nil, fmt.Errorf("updating the TOTP key of user %s: %w", user.Name, err)
+		}
+		return key, nil
+	}
+
+	_, err = m.secrets.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SecretName(user.Name),
+			Namespace: tokens.SecretNamespace,
+			Labels:    map[string]string{tokens.UserIDLabel: user.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v3.UserGroupVersionKind.GroupVersion().String(),
+				Kind:       v3.UserGroupVersionKind.Kind,
+				Name:       user.Name,
+				UID:        user.UID,
+			}},
+		},
+		Data: map[string][]byte{secretKey: key},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating the TOTP key of user %s: %w", user.Name, err)
+	}
+	return key, nil
+}
+
+// confirm verifies a code of the pending TOTP key of the user, which is used for the logins from then on, and
+// returns the new recovery codes of the user.
+func (m *Manager) confirm(userID, code string) ([]string, error) {
+	secret, err := m.enrollment(userID)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, errInvalidCode
+	}
+	secret.Data[confirmedKey] = []byte("true")
+	codes, err := setRecoveryCodes(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(secret, code); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verify checks the code against the TOTP key of the secret, and records its time step so that it can't be reused.
+// The update fails on conflicts, which makes concurrent uses of the same code fail.
+func (m *Manager) verify(secret *corev1.Secret, code string) error {
+	lastCounter, _ := strconv.ParseUint(string(secret.Data[lastCounterKey]), 10, 64)
+	counter, ok := validate(secret.Data[secretKey], code, m.now(), lastCounter)
+	if !ok {
+		return errInvalidCode
+	}
+	secret.Data[lastCounterKey] = []byte(strconv.FormatUint(counter, 10))
+	if _, err := m.secrets.Update(secret); err != nil {
+		if apierrors.IsConflict(err) {
+			return errInvalidCode
+		}
+		return fmt.Errorf("updating the TOTP key of user %s: %w", secret.Labels[tokens.UserIDLabel], err)
+	}
+	return nil
+}
+
+// reset deletes the TOTP key of the user, who must enroll again if the second factor is required.
+func (m *Manager) reset(userID string) error {
+	err := m.secrets.Delete(tokens.SecretNamespace, SecretName(userID), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting the TOTP key of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+func confirmed(secret *corev1.Secret) bool {
+	return secret != nil && string(secret.Data[confirmedKey]) == "true"
+}
@@ -0,0 +1,231 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+func newTestManager(t *testing.T, now *time.Time) (*Manager, map[string]*corev1.Secret) {
+	ctrl := gomock.NewController(t)
+	stored := map[string]*corev1.Secret{}
+	gr := schema.GroupResource{Resource: "secrets"}
+	version := 0
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		version++
+		created := secret.DeepCopy()
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if stored[secret.Name].ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Delete(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ *metav1.DeleteOptions) error {
+		if _, ok := stored[name]; !ok {
+			return apierrors.NewNotFound(gr, name)
+		}
+		delete(stored, name)
+		return nil
+	}).AnyTimes()
+
+	m := NewManager(secrets)
+	m.now = func() time.Time { return *now }
+	return m, stored
+}
+
+func TestCheckLogin(t *testing.T) {
+	defer settings.AuthMFARequiredProviders.Set(settings.AuthMFARequiredProviders.Default)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, stored := newTestManager(t, &now)
+	u := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde", UID: "1234"}}
+
+	// Users who haven't enrolled log in with their password alone, unless MFA is required.
+	assert.NoError(t, m.CheckLogin(u, "local", ""))
+	require.NoError(t, settings.AuthMFARequiredProviders.Set("openldap, local"))
+	assert.ErrorIs(t, m.CheckLogin(u, "local", ""), ErrEnrollmentRequired)
+	assert.NoError(t, m.CheckLogin(u, "github", ""))
+	require.NoError(t, settings.AuthMFARequiredProviders.Set(""))
+	u.Annotations = map[string]string{RequiredAnnotation: "true"}
+	assert.ErrorIs(t, m.CheckLogin(u, "activedirectory", ""), ErrEnrollmentRequired)
+
+	// The key is only used for logins once confirmed with a code.
+	key, err := m.enroll(u)
+	require.NoError(t, err)
+	secret := stored[SecretName(u.Name)]
+	require.NotNil(t, secret)
+	assert.Equal(t, u.Name, secret.Labels[tokens.UserIDLabel])
+	assert.Equal(t, u.UID, secret.OwnerReferences[0].UID)
+	assert.ErrorIs(t, m.CheckLogin(u, "local", ""), ErrEnrollmentRequired)
+
+	_, err = m.confirm(u.Name, "abcdef")
+	assert.ErrorIs(t, err, errInvalidCode)
+	counter := uint64(now.Unix() / 30)
+	codes, err := m.confirm(u.Name, totpCode(key, counter))
+	require.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	_, err = m.enroll(u)
+	assert.ErrorIs(t, err, errAlreadyEnrolled)
+
+	// Logins need a code, which can only be used once.
+	assert.ErrorIs(t, m.CheckLogin(u, "local", ""), ErrCodeRequired)
+	assert.ErrorIs(t, m.CheckLogin(u, "local", totpCode(key, counter)), ErrCodeRequired)
+	now = now.Add(period)
+	assert.NoError(t, m.CheckLogin(u, "local", totpCode(key, counter+1)))
+	// Only the logins with a username and password need a code.
+	assert.NoError(t, m.CheckLogin(u, "github", ""))
+
+	require.NoError(t, m.reset(u.Name))
+	assert.Empty(t, stored)
+	assert.NoError(t, m.reset(u.Name))
+}
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	allowed map[string]bool
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	sar.Status.Allowed = f.allowed[sar.Spec.User] && sar.Spec.ResourceAttributes.Resource == "users"
+	return sar, nil
+}
+
+func TestResetHandler(t *testing.T) {
+	now := time.Now()
+	m, stored := newTestManager(t, &now)
+	_, err := m.enroll(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}})
+	require.NoError(t, err)
+	h := &handler{
+		manager:              m,
+		subjectAccessReviews: &fakeSubjectAccessReviews{allowed: map[string]bool{"u-admin": true}},
+	}
+
+	serve := func(userID string) int {
+		req := httptest.NewRequest(http.MethodDelete, BasePath+"/users/u-abcde", nil)
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userID}))
+		rec := httptest.NewRecorder()
+		h.router().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, serve("u-fghij"))
+	assert.NotEmpty(t, stored)
+	assert.Equal(t, http.StatusNoContent, serve("u-admin"))
+	assert.Empty(t, stored)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, stored := newTestManager(t, &now)
+	u := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}}
+	key, err := m.enroll(u)
+	require.NoError(t, err)
+	codes, err := m.confirm(u.Name, totpCode(key, uint64(now.Unix()/30)))
+	require.NoError(t, err)
+	for _, code := range codes {
+		assert.True(t, isRecoveryCode(code), code)
+		assert.NotContains(t, string(stored[SecretName(u.Name)].Data[recoveryCodesKey]), code)
+	}
+
+	// Recovery codes replace the TOTP codes, once each.
+	assert.NoError(t, m.CheckLogin(u, "local", " "+strings.ToUpper(codes[0])+" "))
+	assert.ErrorIs(t, m.CheckLogin(u, "local", codes[0]), ErrCodeRequired)
+	assert.ErrorIs(t, m.CheckLogin(u, "local", "aaaaa-aaaaa"), ErrCodeRequired)
+	assert.NoError(t, m.CheckLogin(u, "local", codes[1]))
+	assert.Len(t, recoveryCodeHashes(stored[SecretName(u.Name)]), recoveryCodeCount-2)
+
+	secret, err := m.enrollment(u.Name)
+	require.NoError(t, err)
+	newCodes, err := m.regenerateRecoveryCodes(secret)
+	require.NoError(t, err)
+	assert.Len(t, recoveryCodeHashes(stored[SecretName(u.Name)]), recoveryCodeCount)
+	assert.ErrorIs(t, m.CheckLogin(u, "local", codes[2]), ErrCodeRequired)
+	assert.NoError(t, m.CheckLogin(u, "local", newCodes[2]))
+
+	_, err = m.regenerateRecoveryCodes(nil)
+	assert.ErrorIs(t, err, errNotEnrolled)
+}
+
+func TestRecoveryCodesHandler(t *testing.T) {
+	now := time.Now()
+	m, _ := newTestManager(t, &now)
+	key, err := m.enroll(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}})
+	require.NoError(t, err)
+	codes, err := m.confirm("u-abcde", totpCode(key, uint64(now.Unix()/30)))
+	require.NoError(t, err)
+	h := &handler{
+		manager:              m,
+		subjectAccessReviews: &fakeSubjectAccessReviews{allowed: map[string]bool{"u-admin": true}},
+	}
+
+	serve := func(method, target, userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userID}))
+		rec := httptest.NewRecorder()
+		h.router().ServeHTTP(rec, req)
+		return rec
+	}
+	status := func(rec *httptest.ResponseRecorder) Status {
+		var s Status
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&s))
+		return s
+	}
+
+	// Users regenerate their codes with a code.
+	rec := serve(http.MethodPost, BasePath+"?action=regenerate-recovery-codes", "u-abcde", `{"code":"abcdef"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = serve(http.MethodPost, BasePath+"?action=regenerate-recovery-codes", "u-abcde", `{"code":"`+codes[0]+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var regenerated RecoveryCodes
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&regenerated))
+	assert.Len(t, regenerated.RecoveryCodes, recoveryCodeCount)
+	assert.Equal(t, recoveryCodeCount, status(serve(http.MethodGet, BasePath, "u-abcde", "")).RecoveryCodes)
+
+	// Admins see how many codes are left, and force new ones.
+	assert.NoError(t, m.CheckLogin(&v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}}, "local", regenerated.RecoveryCodes[0]))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, BasePath+"/users/u-abcde", "u-fghij", "").Code)
+	rec = serve(http.MethodGet, BasePath+"/users/u-abcde", "u-admin", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, Status{Enabled: true, RecoveryCodes: recoveryCodeCount - 1}, status(rec))
+
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, BasePath+"/users/u-abcde?action=regenerate-recovery-codes", "u-fghij", "").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, BasePath+"/users/u-abcde?action=regenerate-recovery-codes", "u-admin", "").Code)
+	assert.Equal(t, recoveryCodeCount, status(serve(http.MethodGet, BasePath, "u-abcde", "")).RecoveryCodes)
+	assert.Equal(t, http.StatusConflict, serve(http.MethodPost, BasePath+"/users/u-fghij?action=regenerate-recovery-codes", "u-admin", "").Code)
+}
@@ -0,0 +1,125 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/tokens/hashers"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	recoveryCodesKey = "recoveryCodes"
+
+	recoveryCodeCount = 10
+	// recoveryCodeGroupSize is the size of the two groups of characters of a recovery code, separated by a dash.
+	recoveryCodeGroupSize = 5
+	// recoveryCodeAlphabet leaves out the characters which are easily mistaken for others.
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// generateRecoveryCodes returns new recovery codes, and their hashes to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	hasher := hashers.GetHasher()
+	for i := 0; i < recoveryCodeCount; i++ {
+		var b strings.Builder
+		for j := 0; j < 2*recoveryCodeGroupSize; j++ {
+			if j == recoveryCodeGroupSize {
+				b.WriteByte('-')
+			}
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeAlphabet))))
+			if err != nil {
+				return nil, nil, fmt.Errorf("generating a recovery code: %w", err)
+			}
+			b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+		}
+		hash, err := hasher.CreateHash(b.String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("hashing a recovery code: %w", err)
+		}
+		codes = append(codes, b.String())
+		hashes = append(hashes, hash)
+	}
+	return codes, hashes, nil
+}
+
+// setRecoveryCodes replaces the recovery codes of the secret with new ones, and returns them. The secret must be
+// updated by the caller.
+func setRecoveryCodes(secret *corev1.Secret) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	secret.Data[recoveryCodesKey] = []byte(strings.Join(hashes, "\n"))
+	return codes, nil
+}
+
+// recoveryCodeHashes returns the hashes of the recovery codes of the secret which haven't been used.
+func recoveryCodeHashes(secret *corev1.Secret) []string {
+	if secret == nil || len(secret.Data[recoveryCodesKey]) == 0 {
+		return nil
+	}
+	return strings.Split(string(secret.Data[recoveryCodesKey]), "\n")
+}
+
+// isRecoveryCode tells whether a code has the format of the recovery codes rather than of the TOTP codes.
+func isRecoveryCode(code string) bool {
+	code = strings.TrimSpace(code)
+	return len(code) == 2*recoveryCodeGroupSize+1 && code[recoveryCodeGroupSize] == '-'
+}
+
+// useRecoveryCode checks the code against the unused recovery codes of the secret, and removes it.
+// The update fails on conflicts, which makes concurrent uses of the same code fail.
+func (m *Manager) useRecoveryCode(secret *corev1.Secret, code string) error {
+	code = strings.ToLower(strings.TrimSpace(code))
+	hashes := recoveryCodeHashes(secret)
+	for i, hash := range hashes {
+		hasher, err := hashers.GetHasherForHash(hash)
+		if err != nil || hasher.VerifyHash(hash, code) != nil {
+			continue
+		}
+
+		remaining := append(hashes[:i:i], hashes[i+1:]...)
+		secret.Data[recoveryCodesKey] = []byte(strings.Join(remaining, "\n"))
+		userID := secret.Labels[tokens.UserIDLabel]
+		if _, err := m.secrets.Update(secret); err != nil {
+			if apierrors.IsConflict(err) {
+				return errInvalidCode
+			}
+			return fmt.Errorf("updating the recovery codes of user %s: %w", userID, err)
+		}
+		logrus.Infof("[mfa] user %s used a recovery code, %d remaining", userID, len(remaining))
+		return nil
+	}
+	return errInvalidCode
+}
+
+// checkCode verifies a TOTP code or a recovery code of the confirmed key of the secret.
+func (m *Manager) checkCode(secret *corev1.Secret, code string) error {
+	if isRecoveryCode(code) {
+		return m.useRecoveryCode(secret, code)
+	}
+	return m.verify(secret, code)
+}
+
+// regenerateRecoveryCodes replaces the recovery codes of the user, who must have confirmed their enrollment.
+func (m *Manager) regenerateRecoveryCodes(secret *corev1.Secret) ([]string, error) {
+	if !confirmed(secret) {
+		return nil, errNotEnrolled
+	}
+	codes, err := setRecoveryCodes(secret)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.secrets.Update(secret); err != nil {
+		return nil, fmt.Errorf("updating the recovery codes of user %s: %w", secret.Labels[tokens.UserIDLabel], err)
+	}
+	return codes, nil
+}
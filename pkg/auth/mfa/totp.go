@@ -0,0 +1,77 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// issuer is the name of the account of the authenticator apps.
+	issuer = "Rancher"
+	// secretSize is the size of the TOTP keys, 160 bits as recommended by RFC 4226 for HMAC-SHA1.
+	secretSize = 20
+	digits     = 6
+	period     = 30 * time.Second
+	// skew is how many time steps before and after the current one are accepted, for the clock drift of devices.
+	skew = 1
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateSecret returns a random TOTP key.
+func generateSecret() ([]byte, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// provisioningURI returns the otpauth URI of the key, which authenticator apps read from a QR code.
+func provisioningURI(secret []byte, account string) string {
+	query := url.Values{}
+	query.Set("secret", secretEncoding.EncodeToString(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(int(period.Seconds())))
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// totpCode returns the TOTP code of the key for a time step, as defined by RFC 6238.
+func totpCode(secret []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, value%1000000)
+}
+
+// validate checks a code against the time steps around now, and returns the time step it matches.
+// Time steps up to lastCounter are refused so that a code can't be used twice.
+func validate(secret []byte, value string, now time.Time, lastCounter uint64) (uint64, bool) {
+	if len(value) != digits {
+		return 0, false
+	}
+	current := now.Unix() / int64(period.Seconds())
+	for counter := current - skew; counter <= current+skew; counter++ {
+		if counter < 0 || uint64(counter) <= lastCounter {
+			continue
+		}
+		if hmac.Equal([]byte(totpCode(secret, uint64(counter))), []byte(value)) {
+			return uint64(counter), true
+		}
+	}
+	return 0, false
+}
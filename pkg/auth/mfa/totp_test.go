@@ -0,0 +1,65 @@
+package mfa
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 key of the test vectors of RFC 6238.
+var rfcSecret = []byte("12345678901234567890")
+
+func TestTOTPCode(t *testing.T) {
+	// The test vectors of RFC 6238 have 8 digits, the codes are their last 6.
+	tests := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range tests {
+		assert.Equal(t, want, totpCode(rfcSecret, uint64(unix/30)), "time %d", unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	counter, ok := validate(rfcSecret, "005924", now, 0)
+	require.True(t, ok)
+	assert.Equal(t, uint64(1234567890/30), counter)
+
+	// The codes of the previous and next time steps are accepted for the clock drift.
+	_, ok = validate(rfcSecret, "005924", now.Add(period), 0)
+	assert.True(t, ok)
+	_, ok = validate(rfcSecret, "005924", now.Add(-period), 0)
+	assert.True(t, ok)
+	_, ok = validate(rfcSecret, "005924", now.Add(2*period), 0)
+	assert.False(t, ok)
+
+	// A code can't be used twice.
+	_, ok = validate(rfcSecret, "005924", now, counter)
+	assert.False(t, ok)
+
+	_, ok = validate(rfcSecret, "000000", now, 0)
+	assert.False(t, ok)
+	_, ok = validate(rfcSecret, "5924", now, 0)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(provisioningURI(rfcSecret, "admin"))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Rancher:admin", uri.Path)
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri.Query().Get("secret"))
+	assert.Equal(t, "Rancher", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
@@ -0,0 +1,100 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// ExpiryNotifiedAnnotation is set on the API keys whose users were notified that they expire soon.
+	ExpiryNotifiedAnnotation = "auth.cattle.io/expiry-notified"
+
+	expiryCheckInterval = time.Hour
+)
+
+type expiryNotifier struct {
+	*Notifier
+	tokenCache mgmtcontrollers.TokenCache
+	tokens     mgmtcontrollers.TokenClient
+}
+
+// StartExpiryNotices notifies the users of their API keys expiring within token-expiry-notice-hours until the context
+// is done.
+func StartExpiryNotices(ctx context.Context, mgmt *config.ScaledContext) {
+	e := &expiryNotifier{
+		Notifier:   NewNotifier(mgmt),
+		tokenCache: mgmt.Wrangler.Mgmt.Token().Cache(),
+		tokens:     mgmt.Wrangler.Mgmt.Token(),
+	}
+	go wait.UntilWithContext(ctx, e.run, expiryCheckInterval)
+}
+
+func (e *expiryNotifier) run(_ context.Context) {
+	hours := settings.TokenExpiryNoticeHours.GetInt()
+	if hours <= 0 || !Enabled(mail.TokenExpiringMessage) {
+		return
+	}
+	tokenList, err := e.tokenCache.List(labels.Everything())
+	if err != nil {
+		logrus.Errorf("[notifications] failed to list the tokens: %v", err)
+		return
+	}
+	now := e.now()
+	notice := time.Duration(hours) * time.Hour
+	for _, token := range tokenList {
+		expiresAt, ok := apiKeyExpiry(token)
+		if !ok || token.Annotations[ExpiryNotifiedAnnotation] != "" || !now.Before(expiresAt) || expiresAt.Sub(now) > notice {
+			continue
+		}
+		if err := e.notify(token, expiresAt); err != nil {
+			logrus.Errorf("[notifications] failed to notify user %s that token %s expires: %v", token.UserID, token.Name, err)
+		}
+	}
+}
+
+// notify sends the notice of the token to its user, and marks the token as notified.
+func (e *expiryNotifier) notify(token *v3.Token, expiresAt time.Time) error {
+	user, err := e.userCache.Get(token.UserID)
+	if err != nil {
+		return err
+	}
+	if address := emailverification.Address(user); address != "" {
+		err = e.mailer.SendMessage(address, mail.TokenExpiringMessage, mail.TokenExpiringData{
+			Username:    displayName(user),
+			TokenName:   token.Name,
+			Description: token.Description,
+			ExpiresAt:   expiresAt.UTC().Format(time.RFC1123),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	token = token.DeepCopy()
+	if token.Annotations == nil {
+		token.Annotations = map[string]string{}
+	}
+	token.Annotations[ExpiryNotifiedAnnotation] = e.now().UTC().Format(time.RFC3339)
+	_, err = e.tokens.Update(token)
+	return err
+}
+
+// apiKeyExpiry returns when the token expires, if it's an API key which expires. The login tokens and those of
+// refresh token families are renewed by logging in again, so they aren't API keys.
+func apiKeyExpiry(token *v3.Token) (time.Time, bool) {
+	if !token.IsDerived || token.TTLMillis <= 0 || token.Labels[tokens.TokenFamilyLabel] != "" {
+		return time.Time{}, false
+	}
+	return token.CreationTimestamp.Add(time.Duration(token.TTLMillis) * time.Millisecond), true
+}
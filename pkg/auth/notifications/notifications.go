@@ -0,0 +1,311 @@
+// Package notifications emails the users about the security events of their account listed in email-notifications:
+// the logins from a device they haven't logged in from before, the unusual logins, the failed logins since their
+// previous login, the changes of their second factors, and their API keys about to expire. Only the users with an email address are notified, once verified if email-verification-required
+// is set.
+package notifications
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	devicesSecretPrefix = "known-devices-"
+	devicesKey          = "devices"
+	// maxDevices is how many devices are remembered per user, the ones used the longest ago are forgotten first.
+	maxDevices = 20
+	// lastSeenResolution is how stale the last login from a known device can be before it's updated, so that most
+	// logins don't update the secret.
+	lastSeenResolution = 24 * time.Hour
+	maxUserAgentLen    = 256
+)
+
+// Mailer sends the messages.
+type Mailer interface {
+	SendMessage(to, name string, data interface{}) error
+}
+
+// Notifier sends the notifications of the users. A nil Notifier sends none.
+type Notifier struct {
+	userCache mgmtcontrollers.UserCache
+	secrets   wcorev1.SecretClient
+	mailer    Mailer
+	now       func() time.Time
+	async     func(func())
+}
+
+// NewNotifier returns a Notifier sending the emails with the SMTP server of the settings.
+func NewNotifier(mgmt *config.ScaledContext) *Notifier {
+	return newNotifier(
+		mgmt.Wrangler.Mgmt.User().Cache(),
+		mgmt.Wrangler.Core.Secret(),
+		mail.NewSender(mgmt.Core.Secrets("").Controller().Lister()),
+	)
+}
+
+func newNotifier(userCache mgmtcontrollers.UserCache, secrets wcorev1.SecretClient, mailer Mailer) *Notifier {
+	return &Notifier{
+		userCache: userCache,
+		secrets:   secrets,
+		mailer:    mailer,
+		now:       time.Now,
+		async:     func(f func()) { go f() },
+	}
+}
+
+// Enabled tells whether the users are notified of the event, one of the messages of package mail.
+func Enabled(event string) bool {
+	if !mail.Configured() {
+		return false
+	}
+	for _, name := range strings.Split(settings.EmailNotifications.Get(), ",") {
+		if strings.TrimSpace(name) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// DevicesSecretName returns the name of the secret holding the devices the user logged in from.
+func DevicesSecretName(userID string) string {
+	return devicesSecretPrefix + userID
+}
+
+// Login remembers the device of a login of the user, identified by its client address and user agent, and notifies
+// the user when they haven't logged in from it before. The first login of a user isn't notified.
+func (n *Notifier) Login(user *v3.User, provider string, source net.IP, userAgent string) {
+	if n == nil || !Enabled(mail.NewDeviceLoginMessage) {
+		return
+	}
+	n.async(func() {
+		if err := n.login(user, provider, source, userAgent); err != nil {
+			logrus.Errorf("[notifications] failed to check the login device of user %s: %v", user.Name, err)
+		}
+	})
+}
+
+func (n *Notifier) login(user *v3.User, provider string, source net.IP, userAgent string) error {
+	now := n.now()
+	secret, err := n.secrets.Get(tokens.SecretNamespace, DevicesSecretName(user.Name), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting the known devices: %w", err)
+	}
+	first := apierrors.IsNotFound(err)
+
+	devices := map[string]time.Time{}
+	if !first {
+		devices = parseDevices(secret)
+	}
+	key := deviceKey(source, userAgent)
+	lastSeen, known := devices[key]
+	if known && now.Sub(lastSeen) < lastSeenResolution {
+		return nil
+	}
+	devices[key] = now
+
+	data := map[string][]byte{devicesKey: formatDevices(devices)}
+	if first {
+		_, err = n.secrets.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DevicesSecretName(user.Name),
+				Namespace: tokens.SecretNamespace,
+				Labels:    map[string]string{tokens.UserIDLabel: user.Name},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: v3.UserGroupVersionKind.GroupVersion().String(),
+					Kind:       v3.UserGroupVersionKind.Kind,
+					Name:       user.Name,
+					UID:        user.UID,
+				}},
+			},
+			Data: data,
+		})
+	} else {
+		secret = secret.DeepCopy()
+		secret.Data = data
+		_, err = n.secrets.Update(secret)
+	}
+	if err != nil {
+		return fmt.Errorf("recording the device: %w", err)
+	}
+
+	address := emailverification.Address(user)
+	if known || first || address == "" {
+		return nil
+	}
+	sourceAddress := "unknown"
+	if source != nil {
+		sourceAddress = source.String()
+	}
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = userAgent[:maxUserAgentLen]
+	}
+	return n.mailer.SendMessage(address, mail.NewDeviceLoginMessage, mail.NewDeviceLoginData{
+		Username:  displayName(user),
+		Provider:  provider,
+		Time:      now.UTC().Format(time.RFC1123),
+		Address:   sourceAddress,
+		UserAgent: userAgent,
+	})
+}
+
+// LoginAnomaly notifies the user of an unusual login, described by a sentence like "It's the first login from country
+// FR".
+func (n *Notifier) LoginAnomaly(user *v3.User, provider string, source net.IP, userAgent, anomaly string) {
+	if n == nil || !Enabled(mail.LoginAnomalyMessage) {
+		return
+	}
+	address := emailverification.Address(user)
+	if address == "" {
+		return
+	}
+	sourceAddress := "unknown"
+	if source != nil {
+		sourceAddress = source.String()
+	}
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = userAgent[:maxUserAgentLen]
+	}
+	data := mail.LoginAnomalyData{
+		Username:  displayName(user),
+		Provider:  provider,
+		Time:      n.now().UTC().Format(time.RFC1123),
+		Address:   sourceAddress,
+		Anomaly:   anomaly,
+		UserAgent: userAgent,
+	}
+	n.async(func() {
+		if err := n.mailer.SendMessage(address, mail.LoginAnomalyMessage, data); err != nil {
+			logrus.Errorf("[notifications] failed to notify user %s of a login anomaly: %v", user.Name, err)
+		}
+	})
+}
+
+// FailedLogins notifies the user, who just logged in, of the logins to their account which failed since their previous
+// login, the last one at last from the source.
+func (n *Notifier) FailedLogins(userID string, count int, last time.Time, source net.IP) {
+	if n == nil || !Enabled(mail.FailedLoginsMessage) {
+		return
+	}
+	n.async(func() {
+		user, err := n.userCache.Get(userID)
+		if err != nil {
+			logrus.Errorf("[notifications] failed to get user %s: %v", userID, err)
+			return
+		}
+		address := emailverification.Address(user)
+		if address == "" {
+			return
+		}
+		sourceAddress := "unknown"
+		if source != nil {
+			sourceAddress = source.String()
+		}
+		err = n.mailer.SendMessage(address, mail.FailedLoginsMessage, mail.FailedLoginsData{
+			Username: displayName(user),
+			Count:    count,
+			LastTime: last.UTC().Format(time.RFC1123),
+			Address:  sourceAddress,
+		})
+		if err != nil {
+			logrus.Errorf("[notifications] failed to notify user %s of failed logins: %v", userID, err)
+		}
+	})
+}
+
+// MFAChanged notifies the user of a change of their second factors, described by a sentence like "A security key was
+// registered".
+func (n *Notifier) MFAChanged(userID, change string) {
+	if n == nil || !Enabled(mail.MFAChangeMessage) {
+		return
+	}
+	n.async(func() {
+		user, err := n.userCache.Get(userID)
+		if err != nil {
+			logrus.Errorf("[notifications] failed to get user %s: %v", userID, err)
+			return
+		}
+		address := emailverification.Address(user)
+		if address == "" {
+			return
+		}
+		err = n.mailer.SendMessage(address, mail.MFAChangeMessage, mail.MFAChangeData{
+			Username: displayName(user),
+			Change:   change,
+			Time:     n.now().UTC().Format(time.RFC1123),
+		})
+		if err != nil {
+			logrus.Errorf("[notifications] failed to notify user %s of an MFA change: %v", userID, err)
+		}
+	})
+}
+
+// deviceKey identifies the device of a login. It's hashed, so that the secret doesn't keep the addresses.
+func deviceKey(source net.IP, userAgent string) string {
+	sum := sha256.Sum256([]byte(source.String() + "\x00" + userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+// parseDevices returns the last login time of the devices of the secret, by key.
+func parseDevices(secret *corev1.Secret) map[string]time.Time {
+	devices := map[string]time.Time{}
+	for _, line := range strings.Split(string(secret.Data[devicesKey]), "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		devices[key] = time.Unix(seconds, 0)
+	}
+	return devices
+}
+
+// formatDevices formats the devices used the most recently, a line per device with its key and last login time.
+func formatDevices(devices map[string]time.Time) []byte {
+	keys := make([]string, 0, len(devices))
+	for key := range devices {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return devices[keys[i]].After(devices[keys[j]])
+	})
+	if len(keys) > maxDevices {
+		keys = keys[:maxDevices]
+	}
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, key+" "+strconv.FormatInt(devices[key].Unix(), 10))
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func displayName(user *v3.User) string {
+	if user.Username != "" {
+		return user.Username
+	}
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.Name
+}
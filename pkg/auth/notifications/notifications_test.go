@@ -0,0 +1,237 @@
+package notifications
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type sentMessage struct {
+	to, name string
+	data     interface{}
+}
+
+type fakeMailer struct {
+	sent []sentMessage
+}
+
+func (f *fakeMailer) SendMessage(to, name string, data interface{}) error {
+	f.sent = append(f.sent, sentMessage{to: to, name: name, data: data})
+	return nil
+}
+
+func configure(t *testing.T) {
+	require.NoError(t, settings.SMTPServer.Set("smtp.example.com:587"))
+	require.NoError(t, settings.SMTPFrom.Set("rancher@example.com"))
+	t.Cleanup(func() {
+		settings.SMTPServer.Set(settings.SMTPServer.Default)
+		settings.SMTPFrom.Set(settings.SMTPFrom.Default)
+	})
+}
+
+func testUser() *v3.User {
+	return &v3.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "u-abc", UID: "uid"},
+		Username:   "alice",
+		Email:      "alice@example.com",
+	}
+}
+
+func newTestNotifier(t *testing.T, now *time.Time, user *v3.User) (*Notifier, *fakeMailer, map[string]*corev1.Secret) {
+	ctrl := gomock.NewController(t)
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userCache.EXPECT().Get(user.Name).Return(user, nil).AnyTimes()
+
+	stored := map[string]*corev1.Secret{}
+	gr := schema.GroupResource{Resource: "secrets"}
+	version := 0
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		version++
+		created := secret.DeepCopy()
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if stored[secret.Name].ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+
+	mailer := &fakeMailer{}
+	n := newNotifier(userCache, secrets, mailer)
+	n.now = func() time.Time { return *now }
+	n.async = func(f func()) { f() }
+	return n, mailer, stored
+}
+
+func TestEnabled(t *testing.T) {
+	assert.False(t, Enabled(mail.NewDeviceLoginMessage))
+
+	configure(t)
+	assert.True(t, Enabled(mail.NewDeviceLoginMessage))
+	assert.True(t, Enabled(mail.TokenExpiringMessage))
+
+	defer settings.EmailNotifications.Set(settings.EmailNotifications.Default)
+	require.NoError(t, settings.EmailNotifications.Set("mfa-change"))
+	assert.False(t, Enabled(mail.NewDeviceLoginMessage))
+	assert.True(t, Enabled(mail.MFAChangeMessage))
+}
+
+func TestLogin(t *testing.T) {
+	configure(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := testUser()
+	n, mailer, stored := newTestNotifier(t, &now, user)
+	laptop := net.ParseIP("10.0.0.1")
+
+	// The first device isn't notified.
+	n.Login(user, "local", laptop, "Firefox")
+	assert.Empty(t, mailer.sent)
+	require.Contains(t, stored, DevicesSecretName(user.Name))
+	assert.Equal(t, user.Name, stored[DevicesSecretName(user.Name)].Labels[tokens.UserIDLabel])
+
+	n.Login(user, "local", laptop, "Firefox")
+	assert.Empty(t, mailer.sent)
+
+	now = now.Add(time.Minute)
+	n.Login(user, "local", net.ParseIP("192.0.2.1"), "curl/8.0")
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "alice@example.com", mailer.sent[0].to)
+	assert.Equal(t, mail.NewDeviceLoginMessage, mailer.sent[0].name)
+	assert.Equal(t, mail.NewDeviceLoginData{
+		Username:  "alice",
+		Provider:  "local",
+		Time:      "Wed, 01 May 2024 12:01:00 UTC",
+		Address:   "192.0.2.1",
+		UserAgent: "curl/8.0",
+	}, mailer.sent[0].data)
+
+	// The same browser from another address is another device.
+	n.Login(user, "local", net.ParseIP("10.0.0.2"), "Firefox")
+	assert.Len(t, mailer.sent, 2)
+}
+
+func TestLoginForgetsOldDevices(t *testing.T) {
+	configure(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := testUser()
+	n, mailer, stored := newTestNotifier(t, &now, user)
+
+	n.Login(user, "local", net.ParseIP("10.0.0.1"), "Firefox")
+	for i := 0; i < maxDevices; i++ {
+		now = now.Add(time.Minute)
+		n.Login(user, "local", net.ParseIP("10.0.1."+strconv.Itoa(i)), "Firefox")
+	}
+	assert.Len(t, parseDevices(stored[DevicesSecretName(user.Name)]), maxDevices)
+	mailer.sent = nil
+
+	// The first device was used the longest ago, and was forgotten.
+	n.Login(user, "local", net.ParseIP("10.0.0.1"), "Firefox")
+	assert.Len(t, mailer.sent, 1)
+}
+
+func TestMFAChanged(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := testUser()
+	n, mailer, _ := newTestNotifier(t, &now, user)
+
+	// Nothing is sent without an SMTP server.
+	n.MFAChanged(user.Name, "A security key was registered")
+	assert.Empty(t, mailer.sent)
+
+	configure(t)
+	n.MFAChanged(user.Name, "A security key was registered")
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, mail.MFAChangeData{
+		Username: "alice",
+		Change:   "A security key was registered",
+		Time:     "Wed, 01 May 2024 12:00:00 UTC",
+	}, mailer.sent[0].data)
+
+	var none *Notifier
+	none.MFAChanged(user.Name, "A security key was registered")
+}
+
+func TestExpiryNotices(t *testing.T) {
+	configure(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := testUser()
+	n, mailer, _ := newTestNotifier(t, &now, user)
+
+	newToken := func(name string, ttl time.Duration, derived bool) *v3.Token {
+		return &v3.Token{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour)),
+				Labels:            map[string]string{},
+			},
+			UserID:      user.Name,
+			IsDerived:   derived,
+			TTLMillis:   ttl.Milliseconds(),
+			Description: "ci",
+		}
+	}
+	expiring := newToken("token-expiring", 48*time.Hour, true)
+	later := newToken("token-later", 30*24*time.Hour, true)
+	expired := newToken("token-expired", time.Hour, true)
+	forever := newToken("token-forever", 0, true)
+	login := newToken("token-login", 48*time.Hour, false)
+	family := newToken("token-family", 48*time.Hour, true)
+	family.Labels[tokens.TokenFamilyLabel] = "family"
+	notified := newToken("token-notified", 48*time.Hour, true)
+	notified.Annotations = map[string]string{ExpiryNotifiedAnnotation: "2024-05-01T00:00:00Z"}
+
+	ctrl := gomock.NewController(t)
+	tokenCache := fake.NewMockNonNamespacedCacheInterface[*v3.Token](ctrl)
+	tokenCache.EXPECT().List(labels.Everything()).Return([]*v3.Token{expiring, later, expired, forever, login, family, notified}, nil).AnyTimes()
+	tokenClient := fake.NewMockNonNamespacedClientInterface[*v3.Token, *v3.TokenList](ctrl)
+	var updated []*v3.Token
+	tokenClient.EXPECT().Update(gomock.Any()).DoAndReturn(func(token *v3.Token) (*v3.Token, error) {
+		updated = append(updated, token)
+		return token, nil
+	}).AnyTimes()
+
+	e := &expiryNotifier{Notifier: n, tokenCache: tokenCache, tokens: tokenClient}
+	e.run(context.Background())
+
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, mail.TokenExpiringData{
+		Username:    "alice",
+		TokenName:   "token-expiring",
+		Description: "ci",
+		ExpiresAt:   "Thu, 02 May 2024 12:00:00 UTC",
+	}, mailer.sent[0].data)
+	require.Len(t, updated, 1)
+	assert.Equal(t, "token-expiring", updated[0].Name)
+	assert.NotEmpty(t, updated[0].Annotations[ExpiryNotifiedAnnotation])
+	assert.Empty(t, expiring.Annotations, "the cached token must not be modified")
+}
@@ -0,0 +1,121 @@
+// Package passwordhash hashes the passwords of the local users with argon2id, with the cost set by the
+// password-hash-* settings. The bcrypt hashes of the previous versions are still verified, and are replaced, like the
+// argon2id hashes with outdated parameters, at the next login of their users.
+package passwordhash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	argon2idPrefix = "$argon2id$"
+	// argon2idFormat is the PHC string format of the argon2id hashes, followed by the salt and the key.
+	argon2idFormat = "$argon2id$v=%d$m=%d,t=%d,p=%d$"
+
+	saltLen = 16
+	keyLen  = 32
+)
+
+var (
+	// ErrMismatch is returned when the password doesn't match the hash.
+	ErrMismatch = errors.New("password does not match the hash")
+
+	errInvalidHash = errors.New("invalid password hash")
+)
+
+// params are the cost parameters of argon2id.
+type params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// currentParams returns the parameters set by the settings, raised to the minimums of argon2id.
+func currentParams() params {
+	p := params{
+		memory:      uint32(max(settings.PasswordHashMemoryKiB.GetInt(), 0)),
+		iterations:  uint32(max(settings.PasswordHashIterations.GetInt(), 1)),
+		parallelism: uint8(min(max(settings.PasswordHashParallelism.GetInt(), 1), 255)),
+	}
+	if minMemory := 8 * uint32(p.parallelism); p.memory < minMemory {
+		p.memory = minMemory
+	}
+	return p
+}
+
+// Hash hashes the password with argon2id and the parameters set by the settings.
+func Hash(password string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generating a salt: %w", err)
+	}
+	p := currentParams()
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, keyLen)
+	return fmt.Sprintf(argon2idFormat, argon2.Version, p.memory, p.iterations, p.parallelism) +
+		base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(key), nil
+}
+
+// Verify returns nil if the password matches the argon2id or bcrypt hash, ErrMismatch if it doesn't.
+func Verify(hash, password string) error {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return ErrMismatch
+			}
+			return err
+		}
+		return nil
+	}
+
+	p, salt, key, err := parse(hash)
+	if err != nil {
+		return err
+	}
+	computed := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, computed) == 0 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// NeedsRehash tells whether the hash must be replaced by a hash of the password with the current parameters: the
+// bcrypt hashes and the argon2id hashes with other parameters.
+func NeedsRehash(hash string) bool {
+	p, _, _, err := parse(hash)
+	return err != nil || p != currentParams()
+}
+
+// parse returns the parameters, the salt and the key of an argon2id hash.
+func parse(hash string) (params, []byte, []byte, error) {
+	var p params
+	var version int
+	// The salt and the key are the last two fields, separated by "$".
+	fields := strings.Split(hash, "$")
+	if len(fields) != 6 {
+		return p, nil, nil, errInvalidHash
+	}
+	if _, err := fmt.Sscanf(strings.Join(fields[:4], "$")+"$", argon2idFormat, &version, &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, nil, nil, errInvalidHash
+	}
+	if version != argon2.Version || p.iterations < 1 || p.parallelism < 1 {
+		return p, nil, nil, errInvalidHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(fields[4])
+	if err != nil {
+		return p, nil, nil, errInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(fields[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errInvalidHash
+	}
+	return p, salt, key, nil
+}
@@ -0,0 +1,74 @@
+package passwordhash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashAndVerify(t *testing.T) {
+	hash, err := Hash("correct horse battery staple")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"), hash)
+
+	assert.NoError(t, Verify(hash, "correct horse battery staple"))
+	assert.ErrorIs(t, Verify(hash, "correct horse battery"), ErrMismatch)
+
+	other, err := Hash("correct horse battery staple")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "the hashes must be salted")
+}
+
+func TestVerifyBcrypt(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("legacy password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	assert.NoError(t, Verify(string(hash), "legacy password"))
+	assert.ErrorIs(t, Verify(string(hash), "wrong password"), ErrMismatch)
+	assert.True(t, NeedsRehash(string(hash)))
+}
+
+func TestVerifyInvalidHash(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"plaintext",
+		"$argon2id$v=19$m=19456,t=2,p=1$c2FsdA",
+		"$argon2id$v=16$m=19456,t=2,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=19456,t=0,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=19456,t=2,p=1$c2FsdHNhbHQ$not base64",
+	} {
+		assert.Error(t, Verify(hash, "password"), hash)
+		assert.NotErrorIs(t, Verify(hash, "password"), ErrMismatch, hash)
+		assert.True(t, NeedsRehash(hash), hash)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	defer settings.PasswordHashMemoryKiB.Set(settings.PasswordHashMemoryKiB.Default)
+	defer settings.PasswordHashIterations.Set(settings.PasswordHashIterations.Default)
+
+	hash, err := Hash("password")
+	require.NoError(t, err)
+	assert.False(t, NeedsRehash(hash))
+
+	require.NoError(t, settings.PasswordHashIterations.Set("3"))
+	assert.True(t, NeedsRehash(hash))
+	// The hashes with other parameters are still verified.
+	assert.NoError(t, Verify(hash, "password"))
+
+	rehashed, err := Hash("password")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rehashed, "$argon2id$v=19$m=19456,t=3,p=1$"), rehashed)
+	assert.False(t, NeedsRehash(rehashed))
+
+	require.NoError(t, settings.PasswordHashMemoryKiB.Set("0"))
+	assert.True(t, NeedsRehash(rehashed))
+	minimal, err := Hash("password")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(minimal, "$argon2id$v=19$m=8,t=3,p=1$"), minimal)
+	assert.NoError(t, Verify(minimal, "password"))
+}
@@ -0,0 +1,83 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	breachCheckTimeout = 10 * time.Second
+	// hashPrefixLen is the length of the prefixes of the SHA-1 hashes sent to the range API.
+	hashPrefixLen = 5
+	// maxRangeSize bounds the responses of the range API, which list a few thousand suffixes.
+	maxRangeSize = 4 * 1024 * 1024
+)
+
+// ErrBreached is returned for the passwords which are known to have been breached.
+var ErrBreached = errors.New("Password has appeared in a data breach, it must not be used")
+
+// breachClient is the client of the range API.
+var breachClient = http.DefaultClient
+
+// checkBreached returns ErrBreached if the range API of password-breach-check-url lists the password. The password is
+// accepted when the API can't be queried.
+func checkBreached(password string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), breachCheckTimeout)
+	defer cancel()
+	breached, err := lookupRange(ctx, settings.PasswordBreachCheckURL.Get(), password)
+	if err != nil {
+		logrus.Warnf("[passwordpolicy] failed to check whether the password was breached: %v", err)
+		return nil
+	}
+	if breached {
+		return ErrBreached
+	}
+	return nil
+}
+
+// lookupRange queries the range API for the suffixes of the hashes sharing the prefix of the hash of the password,
+// and tells whether the suffix of the password is listed. Only the prefix is sent.
+func lookupRange(ctx context.Context, url, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:hashPrefixLen], hash[hashPrefixLen:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// The padding hides how many hashes share the prefix from the observers of the responses.
+	req.Header.Set("Add-Padding", "true")
+	resp, err := breachClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxRangeSize))
+	for scanner.Scan() {
+		listed, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(listed, suffix) {
+			continue
+		}
+		// The padding suffixes have a count of 0.
+		n, err := strconv.Atoi(count)
+		return err == nil && n > 0, nil
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,53 @@
+package passwordpolicy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestBreachCheck(t *testing.T) {
+	defer settings.PasswordBreachCheckURL.Set(settings.PasswordBreachCheckURL.Default)
+
+	breached, padded := sha1Hex("breached-password"), sha1Hex("padded-password")
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		if strings.HasPrefix(r.URL.Path, "/unavailable/") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n")
+		fmt.Fprintf(w, "%s:42\r\n", strings.ToLower(breached[hashPrefixLen:]))
+		fmt.Fprintf(w, "%s:0\r\n", padded[hashPrefixLen:])
+	}))
+	defer server.Close()
+	require.NoError(t, settings.PasswordBreachCheckURL.Set(server.URL+"/range/"))
+
+	policy := Policy{MinLength: 12, BreachCheck: true}
+	assert.ErrorIs(t, policy.Validate("alice", "breached-password"), ErrBreached)
+	assert.NoError(t, policy.Validate("alice", "padded-password"))
+	assert.NoError(t, policy.Validate("alice", "unlisted-password"))
+	assert.NoError(t, Policy{MinLength: 12}.Validate("alice", "breached-password"))
+	// Only the prefixes of the hashes are sent.
+	assert.Equal(t, "/range/"+breached[:hashPrefixLen], requested[0])
+	assert.Len(t, requested, 3)
+
+	// The passwords are accepted when the API is unavailable.
+	require.NoError(t, settings.PasswordBreachCheckURL.Set(server.URL+"/unavailable/"))
+	assert.NoError(t, policy.Validate("alice", "breached-password"))
+}
@@ -0,0 +1,25 @@
+package passwordpolicy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PolicyPath is the path of the unauthenticated endpoint returning the password policy.
+const PolicyPath = "/v1-password-policy"
+
+// NewHandler returns the handler serving the current password policy.
+func NewHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(Current()); err != nil {
+			logrus.Errorf("[passwordpolicy] failed to write response: %v", err)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package passwordpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	historySecretPrefix = "password-history-"
+	hashesKey           = "hashes"
+	changedAtKey        = "changedAt"
+)
+
+// ErrReused is returned for the passwords which are among the last passwords of the user.
+var ErrReused = errors.New("Password must not be one of the last passwords of the user")
+
+// History stores the hashes of the previous passwords of the local users and when they last changed their password
+// in secrets.
+type History struct {
+	secrets wcorev1.SecretClient
+	now     func() time.Time
+}
+
+// NewHistory returns a History storing the passwords with the secrets client.
+func NewHistory(secrets wcorev1.SecretClient) *History {
+	return &History{
+		secrets: secrets,
+		now:     time.Now,
+	}
+}
+
+// HistorySecretName returns the name of the secret holding the previous passwords of the user.
+func HistorySecretName(userID string) string {
+	return historySecretPrefix + userID
+}
+
+// Check returns ErrReused if the password is the current password of the user or one of their previous passwords
+// remembered by the policy.
+func (h *History) Check(user *v3.User, password string, policy Policy) error {
+	if policy.HistorySize <= 0 {
+		return nil
+	}
+	hashes := []string{user.Password}
+	if policy.HistorySize > 1 {
+		secret, err := h.get(user.Name)
+		if err != nil {
+			return err
+		}
+		previous := previousHashes(secret)
+		if len(previous) > policy.HistorySize-1 {
+			previous = previous[:policy.HistorySize-1]
+		}
+		hashes = append(hashes, previous...)
+	}
+	for _, hash := range hashes {
+		if hash != "" && passwordhash.Verify(hash, password) == nil {
+			return ErrReused
+		}
+	}
+	return nil
+}
+
+// Record remembers the password hash the user had before changing it, as long as the policy needs it, and when they
+// changed it. The user is the one before the change.
+func (h *History) Record(user *v3.User, policy Policy) error {
+	secret, err := h.get(user.Name)
+	if err != nil {
+		return err
+	}
+
+	var hashes []string
+	if size := policy.HistorySize; size > 1 {
+		hashes = append([]string{user.Password}, previousHashes(secret)...)
+		if len(hashes) > size-1 {
+			hashes = hashes[:size-1]
+		}
+	}
+	data := map[string][]byte{
+		hashesKey:    []byte(strings.Join(hashes, "\n")),
+		changedAtKey: []byte(h.now().UTC().Format(time.RFC3339)),
+	}
+
+	if secret != nil {
+		secret.Data = data
+		if _, err := h.secrets.Update(secret); err != nil {
+			return fmt.Errorf("updating the password history of user %s: %w", user.Name, err)
+		}
+		return nil
+	}
+	_, err = h.secrets.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HistorySecretName(user.Name),
+			Namespace: tokens.SecretNamespace,
+			Labels:    map[string]string{tokens.UserIDLabel: user.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v3.UserGroupVersionKind.GroupVersion().String(),
+				Kind:       v3.UserGroupVersionKind.Kind,
+				Name:       user.Name,
+				UID:        user.UID,
+			}},
+		},
+		Data: data,
+	})
+	if err != nil {
+		return fmt.Errorf("creating the password history of user %s: %w", user.Name, err)
+	}
+	return nil
+}
+
+// Expired tells whether the password of the user is older than the maximum age of the policy. The passwords which
+// were never changed are as old as the user.
+func (h *History) Expired(user *v3.User, policy Policy) (bool, error) {
+	if policy.MaxAgeDays <= 0 {
+		return false, nil
+	}
+	secret, err := h.get(user.Name)
+	if err != nil {
+		return false, err
+	}
+	changedAt := user.CreationTimestamp.Time
+	if secret != nil {
+		if t, err := time.Parse(time.RFC3339, string(secret.Data[changedAtKey])); err == nil {
+			changedAt = t
+		}
+	}
+	return !h.now().Before(changedAt.AddDate(0, 0, policy.MaxAgeDays)), nil
+}
+
+// get returns the secret of the password history of the user, nil if they never changed their password.
+func (h *History) get(userID string) (*corev1.Secret, error) {
+	secret, err := h.secrets.Get(tokens.SecretNamespace, HistorySecretName(userID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting the password history of user %s: %w", userID, err)
+	}
+	return secret, nil
+}
+
+// previousHashes returns the hashes of the previous passwords of the secret, the most recent first.
+func previousHashes(secret *corev1.Secret) []string {
+	if secret == nil || len(secret.Data[hashesKey]) == 0 {
+		return nil
+	}
+	return strings.Split(string(secret.Data[hashesKey]), "\n")
+}
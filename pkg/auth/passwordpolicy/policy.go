@@ -0,0 +1,137 @@
+// Package passwordpolicy enforces the requirements of the passwords of the local users: their minimum length, the
+// character classes they must contain, that they aren't common or breached passwords, that they don't reuse the last
+// passwords of the user, and how long they can be used. The policy is configured with the password-* settings, and
+// served to the clients by the handler of PolicyPath so that they can show it before the users pick a password.
+package passwordpolicy
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rancher/rancher/pkg/settings"
+)
+
+const (
+	// maxHistorySize bounds the passwords compared on each change, as each comparison hashes the new password.
+	maxHistorySize = 24
+	// minUsernameLength is the length from which the passwords can't contain the username.
+	minUsernameLength = 3
+)
+
+var (
+	//go:embed common-passwords.txt
+	commonPasswordsList string
+
+	// commonPasswords are the lowercase common passwords rejected by the dictionary check.
+	commonPasswords = parseDictionary(commonPasswordsList)
+)
+
+// Policy is the password policy of the local users.
+type Policy struct {
+	// MinLength is the minimum number of characters of the passwords.
+	MinLength int `json:"minLength"`
+	// MinCharacterClasses is how many of the uppercase letters, lowercase letters, digits and symbols classes the
+	// passwords must contain.
+	MinCharacterClasses int `json:"minCharacterClasses"`
+	// HistorySize is how many of their last passwords, the current one included, the users can't reuse.
+	HistorySize int `json:"historySize"`
+	// MaxAgeDays is how many days the passwords can be used before they must be changed, 0 if they don't expire.
+	MaxAgeDays int `json:"maxAgeDays"`
+	// DictionaryCheck rejects the common passwords and the passwords containing the username.
+	DictionaryCheck bool `json:"dictionaryCheck"`
+	// BreachCheck rejects the passwords which are known to have been breached.
+	BreachCheck bool `json:"breachCheck"`
+}
+
+// Current returns the policy configured with the settings.
+func Current() Policy {
+	p := Policy{
+		MinLength:           settings.PasswordMinLength.GetInt(),
+		MinCharacterClasses: settings.PasswordMinCharacterClasses.GetInt(),
+		HistorySize:         settings.PasswordHistorySize.GetInt(),
+		MaxAgeDays:          settings.PasswordMaxAgeDays.GetInt(),
+		DictionaryCheck:     strings.EqualFold(settings.PasswordDictionaryCheck.Get(), "true"),
+		BreachCheck:         strings.EqualFold(settings.PasswordBreachCheck.Get(), "true"),
+	}
+	if p.HistorySize > maxHistorySize {
+		p.HistorySize = maxHistorySize
+	}
+	return p
+}
+
+// Validate checks that the password of the user meets the length, complexity, dictionary and breach requirements of
+// the policy. The reuse of previous passwords is checked by History.
+func (p Policy) Validate(username, password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("Password must be at least %v characters", p.MinLength)
+	}
+	if classes := characterClasses(password); classes < p.MinCharacterClasses {
+		return fmt.Errorf("Password must contain at least %v of uppercase letters, lowercase letters, digits and symbols", p.MinCharacterClasses)
+	}
+	if p.DictionaryCheck {
+		if utf8.RuneCountInString(username) >= minUsernameLength && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+			return errors.New("Password must not contain the username")
+		}
+		if common(password) {
+			return errors.New("Password is too common")
+		}
+	}
+	if p.BreachCheck {
+		return checkBreached(password)
+	}
+	return nil
+}
+
+// characterClasses returns how many of the uppercase letters, lowercase letters, digits and symbols classes the
+// password contains.
+func characterClasses(password string) int {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsSpace(r) && !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+	var classes int
+	for _, present := range []bool{upper, lower, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	return classes
+}
+
+// common tells whether the password is a common password, ignoring the case and the digits and symbols appended
+// to it, as in "Password123!".
+func common(password string) bool {
+	password = strings.ToLower(password)
+	if commonPasswords[password] {
+		return true
+	}
+	stem := strings.TrimRightFunc(password, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	return stem != "" && commonPasswords[stem]
+}
+
+func parseDictionary(list string) map[string]bool {
+	words := map[string]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" && !strings.HasPrefix(word, "#") {
+			words[strings.ToLower(word)] = true
+		}
+	}
+	return words
+}
@@ -0,0 +1,234 @@
+package passwordpolicy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		username string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "too short",
+			policy:   Policy{MinLength: 12},
+			password: "tooshort",
+			wantErr:  true,
+		},
+		{
+			name:     "length in runes",
+			policy:   Policy{MinLength: 12},
+			password: "абвгдеёжзий1",
+		},
+		{
+			name:     "too few character classes",
+			policy:   Policy{MinLength: 12, MinCharacterClasses: 3},
+			password: "alllowercase123",
+			wantErr:  true,
+		},
+		{
+			name:     "enough character classes",
+			policy:   Policy{MinLength: 12, MinCharacterClasses: 3},
+			password: "Mixedcase-and-symbols",
+		},
+		{
+			name:     "contains the username",
+			policy:   Policy{MinLength: 12, DictionaryCheck: true},
+			username: "alice",
+			password: "xx-ALICE-rocks",
+			wantErr:  true,
+		},
+		{
+			name:     "contains the username without dictionary check",
+			policy:   Policy{MinLength: 12},
+			username: "alice",
+			password: "xx-ALICE-rocks",
+		},
+		{
+			name:     "common password",
+			policy:   Policy{MinLength: 8, DictionaryCheck: true},
+			password: "Password",
+			wantErr:  true,
+		},
+		{
+			name:     "common password with digits and symbols appended",
+			policy:   Policy{MinLength: 12, DictionaryCheck: true},
+			password: "Password123!",
+			wantErr:  true,
+		},
+		{
+			name:     "uncommon password",
+			policy:   Policy{MinLength: 12, DictionaryCheck: true},
+			username: "alice",
+			password: "correct horse battery staple",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.username, tt.password)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	defer settings.PasswordHistorySize.Set(settings.PasswordHistorySize.Default)
+	defer settings.PasswordDictionaryCheck.Set(settings.PasswordDictionaryCheck.Default)
+
+	assert.Equal(t, Policy{MinLength: 12}, Current())
+
+	require.NoError(t, settings.PasswordHistorySize.Set("1000"))
+	require.NoError(t, settings.PasswordDictionaryCheck.Set("true"))
+	assert.Equal(t, Policy{MinLength: 12, HistorySize: maxHistorySize, DictionaryCheck: true}, Current())
+}
+
+func newTestHistory(t *testing.T, now *time.Time) (*History, map[string]*corev1.Secret) {
+	ctrl := gomock.NewController(t)
+	stored := map[string]*corev1.Secret{}
+	gr := schema.GroupResource{Resource: "secrets"}
+	version := 0
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		version++
+		created := secret.DeepCopy()
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if stored[secret.Name].ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+
+	h := NewHistory(secrets)
+	h.now = func() time.Time { return *now }
+	return h, stored
+}
+
+// changePassword checks and records a password change of the user like the user API does.
+func changePassword(t *testing.T, h *History, user *v3.User, password string, policy Policy) error {
+	if err := h.Check(user, password, policy); err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	require.NoError(t, h.Record(user, policy))
+	user.Password = string(hash)
+	return nil
+}
+
+func TestHistory(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, stored := newTestHistory(t, &now)
+	hash, err := bcrypt.GenerateFromPassword([]byte("first-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abc", UID: "uid"}, Password: string(hash)}
+
+	// The history is disabled.
+	require.NoError(t, changePassword(t, h, user, "first-password", Policy{}))
+	secret := stored[HistorySecretName("u-abc")]
+	require.NotNil(t, secret)
+	assert.Equal(t, "u-abc", secret.Labels[tokens.UserIDLabel])
+	assert.Equal(t, "u-abc", secret.OwnerReferences[0].Name)
+	assert.Empty(t, secret.Data[hashesKey])
+
+	policy := Policy{HistorySize: 3}
+	assert.ErrorIs(t, changePassword(t, h, user, "first-password", policy), ErrReused)
+	require.NoError(t, changePassword(t, h, user, "second-password", policy))
+	require.NoError(t, changePassword(t, h, user, "third-password", policy))
+	for _, password := range []string{"first-password", "second-password", "third-password"} {
+		assert.ErrorIs(t, h.Check(user, password, policy), ErrReused, password)
+	}
+	require.NoError(t, changePassword(t, h, user, "fourth-password", policy))
+	assert.Len(t, previousHashes(stored[HistorySecretName("u-abc")]), 2)
+	// first-password is now older than the last three passwords.
+	assert.NoError(t, h.Check(user, "first-password", policy))
+	assert.ErrorIs(t, h.Check(user, "second-password", policy), ErrReused)
+	// Only the current password is remembered by a smaller history.
+	assert.NoError(t, h.Check(user, "third-password", Policy{HistorySize: 1}))
+	assert.ErrorIs(t, h.Check(user, "fourth-password", Policy{HistorySize: 1}), ErrReused)
+}
+
+func TestExpired(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := created
+	h, _ := newTestHistory(t, &now)
+	user := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abc", CreationTimestamp: metav1.NewTime(created)}}
+	policy := Policy{MaxAgeDays: 30}
+
+	expired, err := h.Expired(user, policy)
+	require.NoError(t, err)
+	assert.False(t, expired)
+
+	// The passwords which were never changed are as old as the user.
+	now = created.AddDate(0, 0, 30)
+	expired, err = h.Expired(user, policy)
+	require.NoError(t, err)
+	assert.True(t, expired)
+	expired, err = h.Expired(user, Policy{})
+	require.NoError(t, err)
+	assert.False(t, expired)
+
+	require.NoError(t, h.Record(user, policy))
+	now = now.AddDate(0, 0, 29)
+	expired, err = h.Expired(user, policy)
+	require.NoError(t, err)
+	assert.False(t, expired)
+	now = now.AddDate(0, 0, 1)
+	expired, err = h.Expired(user, policy)
+	require.NoError(t, err)
+	assert.True(t, expired)
+}
+
+func TestHandler(t *testing.T) {
+	defer settings.PasswordMinCharacterClasses.Set(settings.PasswordMinCharacterClasses.Default)
+	require.NoError(t, settings.PasswordMinCharacterClasses.Set("3"))
+
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PolicyPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var policy Policy
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&policy))
+	assert.Equal(t, Policy{MinLength: 12, MinCharacterClasses: 3}, policy)
+
+	rec = httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, PolicyPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,128 @@
+package passwordreset
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BasePath is the path of the unauthenticated endpoints requesting and completing the password resets.
+	BasePath = "/v1-password-reset"
+
+	requestAction = "request"
+	resetAction   = "reset"
+
+	maxBodySize = 64 * 1024
+)
+
+type requestInput struct {
+	Username string `json:"username"`
+}
+
+type resetInput struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+type handler struct {
+	manager *Manager
+}
+
+// NewHandler returns the unauthenticated handler of the password resets.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	h := &handler{
+		manager: NewManager(
+			mgmt.Wrangler.Mgmt.User(),
+			mgmt.Wrangler.Mgmt.User().Cache(),
+			mgmt.Wrangler.Core.Secret(),
+			mail.NewSender(mgmt.Core.Secrets("").Controller().Lister()),
+		),
+	}
+	return h.router()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(BasePath).Queries("action", requestAction).HandlerFunc(h.request)
+	root.Methods(http.MethodPost).Path(BasePath).Queries("action", resetAction).HandlerFunc(h.reset)
+	return root
+}
+
+// request sends a reset link to the user. It answers the same whether the user exists or not.
+func (h *handler) request(w http.ResponseWriter, r *http.Request) {
+	var input requestInput
+	if !readJSON(w, r, &input) {
+		return
+	}
+	if input.Username == "" {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "username is required")
+		return
+	}
+	if !h.enabled(w, r) {
+		return
+	}
+	if err := h.manager.Request(input.Username, requests.ClientIP(r)); err != nil {
+		if errors.Is(err, ErrTooManyRequests) {
+			util.ReturnHTTPError(w, r, TooManyRequestsErrorCode.Status, err.Error())
+			return
+		}
+		logrus.Errorf("[passwordreset] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to request the password reset")
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// reset sets the new password of the user of the token.
+func (h *handler) reset(w http.ResponseWriter, r *http.Request) {
+	var input resetInput
+	if !readJSON(w, r, &input) {
+		return
+	}
+	if input.Token == "" || input.NewPassword == "" {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "token and newPassword are required")
+		return
+	}
+	if !h.enabled(w, r) {
+		return
+	}
+	err := h.manager.Reset(input.Token, input.NewPassword, requests.ClientIP(r))
+	var invalidPassword *InvalidPasswordError
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, ErrInvalidToken):
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, err.Error())
+	case errors.As(err, &invalidPassword):
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		logrus.Errorf("[passwordreset] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to reset the password")
+	}
+}
+
+func (h *handler) enabled(w http.ResponseWriter, r *http.Request) bool {
+	if !Enabled() {
+		util.ReturnHTTPError(w, r, http.StatusNotFound, ErrDisabled.Error())
+		return false
+	}
+	return true
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(v); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
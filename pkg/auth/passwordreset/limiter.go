@@ -0,0 +1,86 @@
+package passwordreset
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+)
+
+const (
+	window = time.Hour
+	// maxKeys bounds the memory used by requests for many usernames or from many addresses.
+	maxKeys = 100000
+)
+
+// limiter counts the requests of the last hour per username and per client address. The counts are kept in memory
+// by each Rancher server.
+type limiter struct {
+	mu       sync.Mutex
+	requests map[string][]time.Time
+	now      func() time.Time
+}
+
+func newLimiter() *limiter {
+	return &limiter{
+		requests: map[string][]time.Time{},
+		now:      time.Now,
+	}
+}
+
+// allow records a request for the username from the source, and tells whether they are both under
+// password-reset-max-requests-per-hour. The source is nil if unknown.
+func (l *limiter) allow(username string, source net.IP) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	max := settings.PasswordResetMaxRequestsPerHour.GetInt()
+	keys := []string{"username/" + strings.ToLower(username)}
+	if source != nil {
+		keys = append(keys, "source/"+source.String())
+	}
+	allowed := true
+	for _, key := range keys {
+		if len(l.recent(key, now)) >= max {
+			allowed = false
+		}
+	}
+	if !allowed {
+		return false
+	}
+
+	if len(l.requests) >= maxKeys {
+		l.prune(now)
+		if len(l.requests) >= maxKeys {
+			return false
+		}
+	}
+	for _, key := range keys {
+		l.requests[key] = append(l.recent(key, now), now)
+	}
+	return true
+}
+
+// recent returns the requests of the key in the last hour, and forgets the older ones.
+func (l *limiter) recent(key string, now time.Time) []time.Time {
+	times := l.requests[key]
+	i := 0
+	for i < len(times) && !times[i].After(now.Add(-window)) {
+		i++
+	}
+	if i == len(times) {
+		delete(l.requests, key)
+		return nil
+	}
+	l.requests[key] = times[i:]
+	return times[i:]
+}
+
+func (l *limiter) prune(now time.Time) {
+	for key := range l.requests {
+		l.recent(key, now)
+	}
+}
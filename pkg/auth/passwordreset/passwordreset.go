@@ -0,0 +1,424 @@
+// Package passwordreset lets the local users reset their forgotten password. A user requests a reset with their
+// username, and is sent a link to the email address of their user. The link holds a token signed by Rancher, which
+// expires after password-reset-token-ttl-minutes and can only be used once, with the last token requested.
+// The requests are rate limited per username and per client address, and the requests and resets are logged as
+// audit events.
+package passwordreset
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	normanv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/settings"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// SigningKeySecretName is the name of the secret holding the key signing the tokens.
+	SigningKeySecretName = "password-reset-signing-key"
+
+	signingKeyKey = "key"
+	nonceHashKey  = "nonceHash"
+	expiresAtKey  = "expiresAt"
+
+	keyLen   = 32
+	nonceLen = 32
+	// linkPath is the path of the page of the UI resetting the password with the token.
+	linkPath = "/dashboard/auth/reset-password"
+)
+
+var (
+	// TooManyRequestsErrorCode is the code of the requests rejected by the rate limits.
+	TooManyRequestsErrorCode = httperror.ErrorCode{Code: "TooManyPasswordResetRequests", Status: 429}
+
+	// ErrDisabled is returned when password-reset-enabled isn't set.
+	ErrDisabled = errors.New("password reset is disabled")
+	// ErrTooManyRequests is returned for the requests exceeding password-reset-max-requests-per-hour.
+	ErrTooManyRequests = errors.New("too many password reset requests")
+	// ErrInvalidToken is returned for the tokens which aren't signed by Rancher, have expired, or were already used or
+	// replaced by a later request.
+	ErrInvalidToken = errors.New("invalid or expired password reset token")
+)
+
+// InvalidPasswordError is returned for the new passwords which don't meet the password policy.
+type InvalidPasswordError struct {
+	err error
+}
+
+func (e *InvalidPasswordError) Error() string {
+	return e.err.Error()
+}
+
+func (e *InvalidPasswordError) Unwrap() error {
+	return e.err
+}
+
+// Mailer sends the emails with the links.
+type Mailer interface {
+	SendMessage(to, name string, data interface{}) error
+}
+
+// Manager issues the tokens of the password resets, sends them to the users and resets their password.
+type Manager struct {
+	users     mgmtcontrollers.UserClient
+	userCache mgmtcontrollers.UserCache
+	secrets   wcorev1.SecretClient
+	history   *passwordpolicy.History
+	mailer    Mailer
+	limiter   *limiter
+	now       func() time.Time
+	// async runs the sending of the links, so that the requests for unknown users can't be told apart by their
+	// duration.
+	async func(func())
+
+	keyMu sync.Mutex
+	key   []byte
+}
+
+// NewManager returns a Manager.
+func NewManager(users mgmtcontrollers.UserClient, userCache mgmtcontrollers.UserCache, secrets wcorev1.SecretClient, mailer Mailer) *Manager {
+	return &Manager{
+		users:     users,
+		userCache: userCache,
+		secrets:   secrets,
+		history:   passwordpolicy.NewHistory(secrets),
+		mailer:    mailer,
+		limiter:   newLimiter(),
+		now:       time.Now,
+		async:     func(f func()) { go f() },
+	}
+}
+
+// Enabled tells whether the users can reset their password.
+func Enabled() bool {
+	return strings.EqualFold(settings.PasswordResetEnabled.Get(), "true")
+}
+
+// Request sends a password reset link to the local user with the username, if they have an email address. It
+// doesn't tell whether such a user exists. The source is the address of the client, nil if unknown.
+func (m *Manager) Request(username string, source net.IP) error {
+	if !Enabled() {
+		return ErrDisabled
+	}
+	if !m.limiter.allow(username, source) {
+		audit("PasswordResetThrottled", username, "", source)
+		return ErrTooManyRequests
+	}
+	m.async(func() {
+		if err := m.sendLink(username, source); err != nil {
+			logrus.Errorf("[passwordreset] failed to send a password reset link to user %s: %v", username, err)
+		}
+	})
+	return nil
+}
+
+// sendLink issues a token for the user with the username and sends it to them.
+func (m *Manager) sendLink(username string, source net.IP) error {
+	user, err := m.localUser(username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		audit("PasswordResetIgnored", username, "", source)
+		return nil
+	}
+
+	token, expiresAt, err := m.issue(user)
+	if err != nil {
+		return err
+	}
+	link := strings.TrimRight(settings.ServerURL.Get(), "/") + linkPath + "?token=" + url.QueryEscape(token)
+	data := mail.PasswordResetData{
+		Username:  user.Username,
+		Link:      link,
+		ExpiresAt: expiresAt.UTC().Format(time.RFC1123),
+	}
+	if err := m.mailer.SendMessage(emailverification.Address(user), mail.PasswordResetMessage, data); err != nil {
+		return err
+	}
+	audit("PasswordResetRequested", username, user.Name, source)
+	return nil
+}
+
+// localUser returns the enabled local user with the username and an email address to send the link to, nil if
+// there's none.
+func (m *Manager) localUser(username string) (*v3.User, error) {
+	if username == "" {
+		return nil, nil
+	}
+	users, err := m.userCache.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		if u.Username == username && u.Password != "" && emailverification.Address(u) != "" && !u.IsSystem() && (u.Enabled == nil || *u.Enabled) {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+// issue signs a new token for the user, and records it as the only valid one.
+func (m *Manager) issue(user *v3.User) (string, time.Time, error) {
+	key, err := m.signingKey()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	nonce := make([]byte, nonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", time.Time{}, fmt.Errorf("generating a password reset token: %w", err)
+	}
+	encodedNonce := base64.RawURLEncoding.EncodeToString(nonce)
+	expiresAt := m.now().Add(time.Duration(settings.PasswordResetTokenTTLMinutes.GetInt()) * time.Minute).Truncate(time.Second)
+
+	data := map[string][]byte{
+		nonceHashKey: []byte(hashNonce(encodedNonce)),
+		expiresAtKey: []byte(strconv.FormatInt(expiresAt.Unix(), 10)),
+	}
+	record, err := m.record(user.Name)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if record != nil {
+		record.Data = data
+		_, err = m.secrets.Update(record)
+	} else {
+		_, err = m.secrets.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tokens.PasswordResetSecretName(user.Name),
+				Namespace: tokens.SecretNamespace,
+				Labels:    map[string]string{tokens.UserIDLabel: user.Name},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: normanv3.UserGroupVersionKind.GroupVersion().String(),
+					Kind:       normanv3.UserGroupVersionKind.Kind,
+					Name:       user.Name,
+					UID:        user.UID,
+				}},
+			},
+			Data: data,
+		})
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("recording the password reset token of user %s: %w", user.Name, err)
+	}
+
+	payload := strings.Join([]string{user.Name, strconv.FormatInt(expiresAt.Unix(), 10), encodedNonce}, ".")
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + sign(key, payload), expiresAt, nil
+}
+
+// Reset sets the new password of the user of the token, and invalidates the token. The source is the address of the
+// client, nil if unknown.
+func (m *Manager) Reset(token, password string, source net.IP) error {
+	if !Enabled() {
+		return ErrDisabled
+	}
+	userID, nonce, err := m.verify(token)
+	if err != nil {
+		audit("PasswordResetFailed", "", userID, source)
+		return err
+	}
+	user, err := m.users.Get(userID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if user.Enabled != nil && !*user.Enabled {
+		return ErrInvalidToken
+	}
+
+	policy := passwordpolicy.Current()
+	if err := policy.Validate(user.Username, password); err != nil {
+		return &InvalidPasswordError{err: err}
+	}
+	if password == user.Username {
+		return &InvalidPasswordError{err: errors.New("Password cannot be the same as username")}
+	}
+	if err := m.history.Check(user, password, policy); err != nil {
+		if errors.Is(err, passwordpolicy.ErrReused) {
+			return &InvalidPasswordError{err: err}
+		}
+		return err
+	}
+
+	// The token is consumed before the password is changed, so that concurrent resets with it can't both succeed.
+	if err := m.consume(userID, nonce); err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			audit("PasswordResetFailed", user.Username, userID, source)
+		}
+		return err
+	}
+	hash, err := passwordhash.Hash(password)
+	if err != nil {
+		return err
+	}
+	previous := user.DeepCopy()
+	user.Password = hash
+	user.MustChangePassword = false
+	if _, err := m.users.Update(user); err != nil {
+		return fmt.Errorf("updating the password of user %s: %w", userID, err)
+	}
+	if err := m.history.Record(previous, policy); err != nil {
+		logrus.Errorf("[passwordreset] %v", err)
+	}
+	audit("PasswordResetCompleted", user.Username, userID, source)
+	return nil
+}
+
+// verify checks the signature and the expiry of the token, and returns its user and its nonce.
+func (m *Manager) verify(token string) (string, string, error) {
+	encodedPayload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", ErrInvalidToken
+	}
+	rawPayload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+	payload := string(rawPayload)
+	key, err := m.signingKey()
+	if err != nil {
+		return "", "", err
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(key, payload))) {
+		return "", "", ErrInvalidToken
+	}
+
+	// The user IDs may contain dots, the expiry and the nonce can't.
+	rest, nonce, _ := cutLast(payload, ".")
+	userID, expiry, _ := cutLast(rest, ".")
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || userID == "" || nonce == "" {
+		return "", "", ErrInvalidToken
+	}
+	if !m.now().Before(time.Unix(expiresAt, 0)) {
+		return userID, "", ErrInvalidToken
+	}
+	return userID, nonce, nil
+}
+
+// consume deletes the record of the token of the user, if the nonce is the one of the last token issued and it hasn't
+// expired.
+func (m *Manager) consume(userID, nonce string) error {
+	record, err := m.record(userID)
+	if err != nil {
+		return err
+	}
+	if record == nil || !hmac.Equal(record.Data[nonceHashKey], []byte(hashNonce(nonce))) {
+		return ErrInvalidToken
+	}
+	err = m.secrets.Delete(tokens.SecretNamespace, record.Name, &metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &record.ResourceVersion},
+	})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return fmt.Errorf("deleting the password reset token of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// record returns the secret of the last token of the user, nil if there's none.
+func (m *Manager) record(userID string) (*corev1.Secret, error) {
+	secret, err := m.secrets.Get(tokens.SecretNamespace, tokens.PasswordResetSecretName(userID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting the password reset token of user %s: %w", userID, err)
+	}
+	return secret, nil
+}
+
+// signingKey returns the key signing the tokens, generating it on first use.
+func (m *Manager) signingKey() ([]byte, error) {
+	m.keyMu.Lock()
+	defer m.keyMu.Unlock()
+	if m.key != nil {
+		return m.key, nil
+	}
+
+	secret, err := m.secrets.Get(tokens.SecretNamespace, SigningKeySecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		key := make([]byte, keyLen)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generating the password reset signing key: %w", err)
+		}
+		secret, err = m.secrets.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: SigningKeySecretName, Namespace: tokens.SecretNamespace},
+			Data:       map[string][]byte{signingKeyKey: key},
+		})
+		if apierrors.IsAlreadyExists(err) {
+			// Another server generated it first.
+			secret, err = m.secrets.Get(tokens.SecretNamespace, SigningKeySecretName, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting the password reset signing key: %w", err)
+	}
+	if len(secret.Data[signingKeyKey]) < keyLen {
+		return nil, fmt.Errorf("the password reset signing key of secret %s/%s is too short", tokens.SecretNamespace, SigningKeySecretName)
+	}
+	m.key = secret.Data[signingKeyKey]
+	return m.key, nil
+}
+
+func sign(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func hashNonce(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// audit logs an event of a password reset.
+func audit(event, username, userID string, source net.IP) {
+	fields := logrus.Fields{"event": event}
+	if username != "" {
+		fields["username"] = username
+	}
+	if userID != "" {
+		fields["userID"] = userID
+	}
+	if source != nil {
+		fields["sourceIP"] = source.String()
+	}
+	logrus.WithFields(fields).Info("[passwordreset] audit")
+}
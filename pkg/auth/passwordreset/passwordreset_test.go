@@ -0,0 +1,258 @@
+package passwordreset
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/mail"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type sentEmail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	sent []sentEmail
+}
+
+func (f *fakeMailer) SendMessage(to, name string, data interface{}) error {
+	subject, body, err := mail.Render(name, data)
+	if err != nil {
+		return err
+	}
+	f.sent = append(f.sent, sentEmail{to: to, subject: subject, body: body})
+	return nil
+}
+
+var linkToken = regexp.MustCompile(`\?token=(\S+)`)
+
+// token returns the token of the link of the email.
+func (e sentEmail) token(t *testing.T) string {
+	match := linkToken.FindStringSubmatch(e.body)
+	require.Len(t, match, 2, e.body)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	return token
+}
+
+func newTestManager(t *testing.T, now *time.Time, users ...*v3.User) (*Manager, *fakeMailer, map[string]*v3.User) {
+	ctrl := gomock.NewController(t)
+	storedUsers := map[string]*v3.User{}
+	for _, u := range users {
+		storedUsers[u.Name] = u
+	}
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userCache.EXPECT().List(labels.Everything()).DoAndReturn(func(labels.Selector) ([]*v3.User, error) {
+		var list []*v3.User
+		for _, u := range storedUsers {
+			list = append(list, u.DeepCopy())
+		}
+		return list, nil
+	}).AnyTimes()
+	userClient := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+	userClient.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, _ metav1.GetOptions) (*v3.User, error) {
+		if u, ok := storedUsers[name]; ok {
+			return u.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "users"}, name)
+	}).AnyTimes()
+	userClient.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *v3.User) (*v3.User, error) {
+		storedUsers[u.Name] = u.DeepCopy()
+		return u, nil
+	}).AnyTimes()
+
+	stored := map[string]*corev1.Secret{}
+	gr := schema.GroupResource{Resource: "secrets"}
+	version := 0
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if _, ok := stored[secret.Name]; ok {
+			return nil, apierrors.NewAlreadyExists(gr, secret.Name)
+		}
+		version++
+		created := secret.DeepCopy()
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if stored[secret.Name].ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Delete(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, opts *metav1.DeleteOptions) error {
+		secret, ok := stored[name]
+		if !ok {
+			return apierrors.NewNotFound(gr, name)
+		}
+		if opts.Preconditions != nil && *opts.Preconditions.ResourceVersion != secret.ResourceVersion {
+			return apierrors.NewConflict(gr, name, nil)
+		}
+		delete(stored, name)
+		return nil
+	}).AnyTimes()
+
+	mailer := &fakeMailer{}
+	m := NewManager(userClient, userCache, secrets, mailer)
+	m.now = func() time.Time { return *now }
+	m.limiter.now = m.now
+	m.async = func(f func()) { f() }
+	return m, mailer, storedUsers
+}
+
+func testUser(t *testing.T) *v3.User {
+	hash, err := passwordhash.Hash("the old password")
+	require.NoError(t, err)
+	return &v3.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "u-abc", UID: "uid"},
+		Username:   "alice",
+		Email:      "alice@example.com",
+		Password:   hash,
+	}
+}
+
+func enable(t *testing.T) {
+	require.NoError(t, settings.PasswordResetEnabled.Set("true"))
+	require.NoError(t, settings.ServerURL.Set("https://rancher.example.com"))
+	t.Cleanup(func() {
+		settings.PasswordResetEnabled.Set(settings.PasswordResetEnabled.Default)
+		settings.ServerURL.Set(settings.ServerURL.Default)
+	})
+}
+
+func TestRequestAndReset(t *testing.T) {
+	enable(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, mailer, users := newTestManager(t, &now, testUser(t))
+	source := net.ParseIP("10.0.0.1")
+
+	require.NoError(t, m.Request("alice", source))
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "alice@example.com", mailer.sent[0].to)
+	assert.Contains(t, mailer.sent[0].body, "https://rancher.example.com/dashboard/auth/reset-password?token=")
+	token := mailer.sent[0].token(t)
+
+	err := m.Reset(token, "short", source)
+	var invalidPassword *InvalidPasswordError
+	assert.ErrorAs(t, err, &invalidPassword)
+	// The password policy applies to the resets.
+	defer settings.PasswordHistorySize.Set(settings.PasswordHistorySize.Default)
+	require.NoError(t, settings.PasswordHistorySize.Set("1"))
+	assert.ErrorAs(t, m.Reset(token, "the old password", source), &invalidPassword)
+
+	require.NoError(t, m.Reset(token, "a brand new password", source))
+	assert.NoError(t, passwordhash.Verify(users["u-abc"].Password, "a brand new password"))
+	// The tokens can only be used once.
+	assert.ErrorIs(t, m.Reset(token, "another new password", source), ErrInvalidToken)
+}
+
+func TestRequestUnknownUsers(t *testing.T) {
+	enable(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	withoutEmail := testUser(t)
+	withoutEmail.Name, withoutEmail.Username, withoutEmail.Email = "u-def", "bob", ""
+	disabled := testUser(t)
+	disabled.Name, disabled.Username, disabled.Enabled = "u-ghi", "carol", new(bool)
+	m, mailer, _ := newTestManager(t, &now, withoutEmail, disabled)
+
+	for _, username := range []string{"unknown", "bob", "carol"} {
+		assert.NoError(t, m.Request(username, nil), username)
+	}
+	assert.Empty(t, mailer.sent)
+}
+
+func TestTokens(t *testing.T) {
+	enable(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, mailer, _ := newTestManager(t, &now, testUser(t))
+
+	require.NoError(t, m.Request("alice", nil))
+	require.NoError(t, m.Request("alice", nil))
+	require.Len(t, mailer.sent, 2)
+	first, second := mailer.sent[0].token(t), mailer.sent[1].token(t)
+
+	// A later request replaces the previous token.
+	assert.ErrorIs(t, m.Reset(first, "a brand new password", nil), ErrInvalidToken)
+
+	// The signature covers the user and the expiry.
+	payload, signature, _ := strings.Cut(second, ".")
+	assert.ErrorIs(t, m.Reset(payload+"."+strings.Repeat("A", len(signature)), "a brand new password", nil), ErrInvalidToken)
+	assert.ErrorIs(t, m.Reset("garbage", "a brand new password", nil), ErrInvalidToken)
+
+	now = now.Add(30 * time.Minute)
+	assert.ErrorIs(t, m.Reset(second, "a brand new password", nil), ErrInvalidToken)
+}
+
+func TestRateLimit(t *testing.T) {
+	enable(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, mailer, _ := newTestManager(t, &now, testUser(t))
+	source := net.ParseIP("10.0.0.1")
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, m.Request("alice", source))
+	}
+	assert.ErrorIs(t, m.Request("alice", net.ParseIP("10.0.0.2")), ErrTooManyRequests)
+	assert.ErrorIs(t, m.Request("unknown", source), ErrTooManyRequests)
+	assert.Len(t, mailer.sent, 5)
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, m.Request("alice", source))
+}
+
+func TestHandler(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, mailer, _ := newTestManager(t, &now, testUser(t))
+	h := (&handler{manager: m}).router()
+
+	post := func(action, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, BasePath+"?action="+action, bytes.NewBufferString(body)))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNotFound, post(requestAction, `{"username":"alice"}`).Code)
+
+	enable(t)
+	assert.Equal(t, http.StatusBadRequest, post(requestAction, `{}`).Code)
+	assert.Equal(t, http.StatusAccepted, post(requestAction, `{"username":"unknown"}`).Code)
+	assert.Equal(t, http.StatusAccepted, post(requestAction, `{"username":"alice"}`).Code)
+	require.Len(t, mailer.sent, 1)
+	token := mailer.sent[0].token(t)
+
+	assert.Equal(t, http.StatusBadRequest, post(resetAction, `{"token":"garbage","newPassword":"a brand new password"}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, post(resetAction, `{"token":"`+token+`","newPassword":"short"}`).Code)
+	assert.Equal(t, http.StatusNoContent, post(resetAction, `{"token":"`+token+`","newPassword":"a brand new password"}`).Code)
+}
@@ -0,0 +1,357 @@
+// Package principalmetadata caches the display metadata of the principals bound to roles as PrincipalMetadata objects,
+// so that the principals of the bindings listed by the members pages are served without looking them up from their
+// auth provider, which is slow with directory providers and fails while they're unavailable.
+package principalmetadata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/groupdisplaynames"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+// Syncer creates, updates and deletes the PrincipalMetadata objects of the principals bound to roles. Groups are
+// resolved with the providers that can look them up, and users from their latest login. The metadata of a principal
+// that can't be resolved is kept as is, so that it's still served while its provider is unavailable.
+type Syncer struct {
+	metadataCache    mgmtcontrollers.PrincipalMetadataCache
+	metadatas        mgmtcontrollers.PrincipalMetadataClient
+	userCache        mgmtcontrollers.UserCache
+	tokenCache       mgmtcontrollers.TokenCache
+	crtbCache        mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbCache        mgmtcontrollers.ProjectRoleTemplateBindingCache
+	grbCache         mgmtcontrollers.GlobalRoleBindingCache
+	enabledProviders func() []string
+	getGroupLookup   func(string) common.GroupLookup
+	now              func() time.Time
+}
+
+// New creates a new instance of Syncer.
+func New(wContext *wrangler.Context) *Syncer {
+	return &Syncer{
+		metadataCache:    wContext.Mgmt.PrincipalMetadata().Cache(),
+		metadatas:        wContext.Mgmt.PrincipalMetadata(),
+		userCache:        wContext.Mgmt.User().Cache(),
+		tokenCache:       wContext.Mgmt.Token().Cache(),
+		crtbCache:        wContext.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbCache:        wContext.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		grbCache:         wContext.Mgmt.GlobalRoleBinding().Cache(),
+		enabledProviders: providers.EnabledProviders,
+		getGroupLookup:   providers.GetGroupLookup,
+		now:              time.Now,
+	}
+}
+
+// Name returns the name of the PrincipalMetadata object of a principal.
+func Name(principalID string) string {
+	sum := sha256.Sum256([]byte(principalID))
+	return "pm-" + strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum[:]))[:10]
+}
+
+// Principal returns the principal cached by the metadata.
+func Principal(metadata *v3.PrincipalMetadata) v3.Principal {
+	return v3.Principal{
+		ObjectMeta:     metav1.ObjectMeta{Name: metadata.PrincipalID},
+		DisplayName:    metadata.DisplayName,
+		LoginName:      metadata.LoginName,
+		PrincipalType:  metadata.PrincipalType,
+		Provider:       metadata.Provider,
+		ProfilePicture: metadata.ProfilePicture,
+		ProfileURL:     metadata.ProfileURL,
+	}
+}
+
+// Run the sync of the principal metadata.
+func (s *Syncer) Run(ctx context.Context) error {
+	if ctx.Err() != nil {
+		logrus.Info("principalmetadata: context canceled, quitting")
+		return nil
+	}
+
+	startedAt := s.now()
+
+	enabled := map[string]bool{}
+	for _, providerName := range s.enabledProviders() {
+		enabled[providerName] = true
+	}
+
+	bound, err := s.collect(enabled)
+	if err != nil {
+		return err
+	}
+
+	logins, err := s.latestLogins()
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.metadataCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing principal metadata: %w", err)
+	}
+
+	logrus.Info("principalmetadata: started")
+
+	principalCount := len(bound)
+	var created, updated, deleted, errCount int
+
+	defer func() {
+		logrus.Infof(
+			"principalmetadata: finished in %v seconds (principals %d, created %d, updated %d, deleted %d, errors %d)",
+			time.Since(startedAt).Seconds(),
+			principalCount, created, updated, deleted, errCount,
+		)
+	}()
+
+	lastSync := metav1.NewTime(startedAt)
+	groupLookups := map[string]common.GroupLookup{}
+
+	for _, obj := range existing {
+		if ctx.Err() != nil {
+			logrus.Info("principalmetadata: context canceled, quitting")
+			return nil
+		}
+
+		b, ok := bound[obj.Name]
+		if !ok || b.principalID != obj.PrincipalID {
+			if err := s.metadatas.Delete(obj.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				logrus.Errorf("principalmetadata: error deleting %s: %v", obj.Name, err)
+				errCount++
+				continue
+			}
+			deleted++
+			continue
+		}
+		delete(bound, obj.Name)
+
+		principal, ok := s.resolve(b, logins, groupLookups)
+		if !ok {
+			errCount++
+			continue
+		}
+		if err := s.update(obj.Name, principal, lastSync); err != nil {
+			logrus.Errorf("principalmetadata: error updating %s for %s: %v", obj.Name, b.principalID, err)
+			errCount++
+			continue
+		}
+		updated++
+	}
+
+	for name, b := range bound {
+		if ctx.Err() != nil {
+			logrus.Info("principalmetadata: context canceled, quitting")
+			return nil
+		}
+
+		principal, ok := s.resolve(b, logins, groupLookups)
+		if !ok {
+			errCount++
+			continue
+		}
+		obj := &v3.PrincipalMetadata{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		setFields(obj, principal, lastSync)
+		if _, err := s.metadatas.Create(obj); err != nil {
+			logrus.Errorf("principalmetadata: error creating %s for %s: %v", name, b.principalID, err)
+			errCount++
+			continue
+		}
+		created++
+	}
+
+	return nil
+}
+
+// boundPrincipal is a principal bound to roles.
+type boundPrincipal struct {
+	principalID string
+	provider    string
+	// displayName is the display name of the principal stored on its bindings, if any.
+	displayName string
+	// userName is the Rancher user of a user principal bound by the name of the user, if any.
+	userName string
+}
+
+// collect returns the principals of the enabled providers bound to roles, keyed by the name of their PrincipalMetadata
+// object. The users bound by name are bound by their principals.
+func (s *Syncer) collect(enabled map[string]bool) (map[string]*boundPrincipal, error) {
+	found := map[string]*boundPrincipal{}
+	add := func(principalID, userName string, annotations map[string]string) {
+		provider := providers.PrincipalProvider(principalID)
+		if principalID == "" || !enabled[provider] {
+			return
+		}
+		b, ok := found[Name(principalID)]
+		if !ok {
+			b = &boundPrincipal{principalID: principalID, provider: provider}
+			found[Name(principalID)] = b
+		}
+		if b.displayName == "" {
+			b.displayName = annotations[groupdisplaynames.DisplayNameAnnotation]
+		}
+		if b.userName == "" {
+			b.userName = userName
+		}
+	}
+	addUser := func(userName string) error {
+		if userName == "" {
+			return nil
+		}
+		user, err := s.userCache.Get(userName)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error getting user %s: %w", userName, err)
+		}
+		for _, principalID := range user.PrincipalIDs {
+			add(principalID, userName, nil)
+		}
+		return nil
+	}
+
+	crtbs, err := s.crtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing cluster role template bindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		add(crtb.UserPrincipalName, crtb.UserName, crtb.Annotations)
+		add(crtb.GroupPrincipalName, "", crtb.Annotations)
+		if err := addUser(crtb.UserName); err != nil {
+			return nil, err
+		}
+	}
+
+	prtbs, err := s.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing project role template bindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		add(prtb.UserPrincipalName, prtb.UserName, prtb.Annotations)
+		add(prtb.GroupPrincipalName, "", prtb.Annotations)
+		if err := addUser(prtb.UserName); err != nil {
+			return nil, err
+		}
+	}
+
+	grbs, err := s.grbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing global role bindings: %w", err)
+	}
+	for _, grb := range grbs {
+		add(grb.GroupPrincipalName, "", grb.Annotations)
+		if err := addUser(grb.UserName); err != nil {
+			return nil, err
+		}
+	}
+
+	return found, nil
+}
+
+// latestLogins returns the latest tokens of the users, keyed by the ID of their user principal.
+func (s *Syncer) latestLogins() (map[string]*v3.Token, error) {
+	tokens, err := s.tokenCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing tokens: %w", err)
+	}
+	logins := map[string]*v3.Token{}
+	for _, token := range tokens {
+		principalID := token.UserPrincipal.Name
+		if principalID == "" {
+			continue
+		}
+		if latest, ok := logins[principalID]; !ok || latest.CreationTimestamp.Before(&token.CreationTimestamp) {
+			logins[principalID] = token
+		}
+	}
+	return logins, nil
+}
+
+// resolve returns the current principal of a bound principal. Groups are looked up from their provider, falling back
+// to the display name stored on their bindings. Users are resolved from the principal of their latest token, falling
+// back to their Rancher user. It returns false if the principal can't be resolved.
+func (s *Syncer) resolve(b *boundPrincipal, logins map[string]*v3.Token, groupLookups map[string]common.GroupLookup) (v3.Principal, bool) {
+	principal := v3.Principal{
+		ObjectMeta:  metav1.ObjectMeta{Name: b.principalID},
+		DisplayName: b.displayName,
+		Provider:    b.provider,
+	}
+
+	if strings.HasPrefix(b.principalID, b.provider+"_group://") {
+		principal.PrincipalType = common.GroupPrincipalType
+		lookup, ok := groupLookups[b.provider]
+		if !ok {
+			lookup = s.getGroupLookup(b.provider)
+			groupLookups[b.provider] = lookup
+		}
+		if lookup != nil {
+			group, err := lookup.LookupGroup(b.principalID)
+			if err != nil {
+				logrus.Errorf("principalmetadata: error looking up group %s: %v", b.principalID, err)
+				return principal, false
+			}
+			principal.DisplayName = group.DisplayName
+			principal.ProfilePicture = group.ProfilePicture
+			principal.ProfileURL = group.ProfileURL
+		}
+		return principal, principal.DisplayName != ""
+	}
+
+	principal.PrincipalType = common.UserPrincipalType
+	if latest, ok := logins[b.principalID]; ok {
+		principal.DisplayName = latest.UserPrincipal.DisplayName
+		principal.LoginName = latest.UserPrincipal.LoginName
+		principal.ProfilePicture = latest.UserPrincipal.ProfilePicture
+		principal.ProfileURL = latest.UserPrincipal.ProfileURL
+		return principal, true
+	}
+
+	if b.userName != "" {
+		user, err := s.userCache.Get(b.userName)
+		if err == nil {
+			principal.DisplayName = user.DisplayName
+			principal.LoginName = user.Username
+		}
+	}
+	return principal, principal.DisplayName != ""
+}
+
+// update sets the synced fields on the latest version of the metadata.
+func (s *Syncer) update(name string, principal v3.Principal, lastSync metav1.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := s.metadatas.Get(name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) { // The metadata is no longer, it's created on the next run.
+				return nil
+			}
+			return err
+		}
+		setFields(obj, principal, lastSync)
+		_, err = s.metadatas.Update(obj)
+		return err
+	})
+}
+
+func setFields(obj *v3.PrincipalMetadata, principal v3.Principal, lastSync metav1.Time) {
+	obj.PrincipalID = principal.Name
+	obj.DisplayName = principal.DisplayName
+	obj.LoginName = principal.LoginName
+	obj.PrincipalType = principal.PrincipalType
+	obj.Provider = principal.Provider
+	obj.ProfilePicture = principal.ProfilePicture
+	obj.ProfileURL = principal.ProfileURL
+	obj.LastSync = &lastSync
+}
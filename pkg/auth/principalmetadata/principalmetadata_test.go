@@ -0,0 +1,167 @@
+package principalmetadata
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/groupdisplaynames"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// fakeGroupLookup looks up the groups it has, and fails to look up the others.
+type fakeGroupLookup map[string]v3.Principal
+
+func (f fakeGroupLookup) LookupGroup(principalID string) (v3.Principal, error) {
+	group, ok := f[principalID]
+	if !ok {
+		return v3.Principal{}, fmt.Errorf("can't reach the directory")
+	}
+	return group, nil
+}
+
+func TestName(t *testing.T) {
+	name := Name("openldap_user://uid=alice,dc=example,dc=com")
+	assert.Regexp(t, "^pm-[a-z2-7]{10}$", name)
+	assert.Equal(t, name, Name("openldap_user://uid=alice,dc=example,dc=com"))
+	assert.NotEqual(t, name, Name("openldap_user://uid=bob,dc=example,dc=com"))
+}
+
+func TestPrincipal(t *testing.T) {
+	principal := Principal(&v3.PrincipalMetadata{
+		PrincipalID:   "openldap_group://cn=devs",
+		DisplayName:   "Developers",
+		PrincipalType: "group",
+		Provider:      "openldap",
+	})
+	assert.Equal(t, v3.Principal{
+		ObjectMeta:    metav1.ObjectMeta{Name: "openldap_group://cn=devs"},
+		DisplayName:   "Developers",
+		PrincipalType: "group",
+		Provider:      "openldap",
+	}, principal)
+}
+
+func TestRun(t *testing.T) {
+	const (
+		devs  = "openldap_group://cn=devs"
+		down  = "openldap_group://cn=down"
+		gone  = "openldap_group://cn=gone"
+		alice = "openldap_user://uid=alice"
+		bob   = "openldap_user://uid=bob"
+		org   = "github_org://1234"
+	)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	metadataCache := fake.NewMockNonNamespacedCacheInterface[*v3.PrincipalMetadata](ctrl)
+	metadatas := fake.NewMockNonNamespacedClientInterface[*v3.PrincipalMetadata, *v3.PrincipalMetadataList](ctrl)
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	tokenCache := fake.NewMockNonNamespacedCacheInterface[*v3.Token](ctrl)
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl)
+
+	crtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ClusterRoleTemplateBinding{
+		{
+			ObjectMeta:         metav1.ObjectMeta{Namespace: "c-abcde", Name: "crtb-devs", Annotations: map[string]string{groupdisplaynames.DisplayNameAnnotation: "Devs"}},
+			GroupPrincipalName: devs,
+		},
+		{
+			ObjectMeta:        metav1.ObjectMeta{Namespace: "c-abcde", Name: "crtb-alice"},
+			UserPrincipalName: alice,
+		},
+	}, nil)
+	prtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ProjectRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "p-abcde", Name: "prtb-down"}, GroupPrincipalName: down},
+	}, nil)
+	// The principals of the providers which aren't enabled aren't cached.
+	grbCache.EXPECT().List(labels.Everything()).Return([]*v3.GlobalRoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-bob"}, UserName: "u-bob"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-org"}, GroupPrincipalName: org},
+	}, nil)
+	userCache.EXPECT().Get("u-bob").Return(&v3.User{
+		ObjectMeta:   metav1.ObjectMeta{Name: "u-bob"},
+		DisplayName:  "Bob",
+		Username:     "bob",
+		PrincipalIDs: []string{"local://u-bob", bob},
+	}, nil).AnyTimes()
+	tokenCache.EXPECT().List(labels.Everything()).Return([]*v3.Token{
+		{
+			ObjectMeta:    metav1.ObjectMeta{Name: "token-new", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			UserPrincipal: v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: alice}, DisplayName: "Alice Liddell", LoginName: "alice"},
+		},
+		{
+			ObjectMeta:    metav1.ObjectMeta{Name: "token-old", CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour))},
+			UserPrincipal: v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: alice}, DisplayName: "Alice"},
+		},
+	}, nil)
+
+	existingDevs := &v3.PrincipalMetadata{ObjectMeta: metav1.ObjectMeta{Name: Name(devs)}, PrincipalID: devs, DisplayName: "Devs"}
+	existingDown := &v3.PrincipalMetadata{ObjectMeta: metav1.ObjectMeta{Name: Name(down)}, PrincipalID: down, DisplayName: "Down"}
+	existingGone := &v3.PrincipalMetadata{ObjectMeta: metav1.ObjectMeta{Name: Name(gone)}, PrincipalID: gone}
+	metadataCache.EXPECT().List(labels.Everything()).Return([]*v3.PrincipalMetadata{existingDevs, existingDown, existingGone}, nil)
+
+	// The metadata of the group which can't be looked up is kept as is.
+	metadatas.EXPECT().Delete(Name(gone), gomock.Any()).Return(nil)
+	metadatas.EXPECT().Get(Name(devs), gomock.Any()).Return(existingDevs.DeepCopy(), nil)
+	var updated *v3.PrincipalMetadata
+	metadatas.EXPECT().Update(gomock.Any()).DoAndReturn(func(obj *v3.PrincipalMetadata) (*v3.PrincipalMetadata, error) {
+		updated = obj
+		return obj, nil
+	})
+	created := map[string]*v3.PrincipalMetadata{}
+	metadatas.EXPECT().Create(gomock.Any()).Times(2).DoAndReturn(func(obj *v3.PrincipalMetadata) (*v3.PrincipalMetadata, error) {
+		created[obj.PrincipalID] = obj
+		return obj, nil
+	})
+
+	syncer := &Syncer{
+		metadataCache:    metadataCache,
+		metadatas:        metadatas,
+		userCache:        userCache,
+		tokenCache:       tokenCache,
+		crtbCache:        crtbCache,
+		prtbCache:        prtbCache,
+		grbCache:         grbCache,
+		enabledProviders: func() []string { return []string{"openldap"} },
+		getGroupLookup: func(string) common.GroupLookup {
+			return fakeGroupLookup{devs: {ObjectMeta: metav1.ObjectMeta{Name: devs}, DisplayName: "Developers"}}
+		},
+		now: func() time.Time { return now },
+	}
+
+	err := syncer.Run(context.Background())
+	require.NoError(t, err)
+
+	require.NotNil(t, updated)
+	assert.Equal(t, "Developers", updated.DisplayName)
+	assert.Equal(t, "group", updated.PrincipalType)
+	assert.Equal(t, "openldap", updated.Provider)
+	assert.Equal(t, metav1.NewTime(now), *updated.LastSync)
+
+	require.Len(t, created, 2)
+	assert.Equal(t, Name(alice), created[alice].Name)
+	assert.Equal(t, "Alice Liddell", created[alice].DisplayName)
+	assert.Equal(t, "alice", created[alice].LoginName)
+	assert.Equal(t, "user", created[alice].PrincipalType)
+	assert.Equal(t, "Bob", created[bob].DisplayName)
+	assert.Equal(t, "bob", created[bob].LoginName)
+}
+
+func TestRunCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	syncer := &Syncer{}
+	err := syncer.Run(ctx)
+	require.NoError(t, err)
+}
@@ -10,12 +10,21 @@
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
 	"github.com/rancher/norman/types/convert"
+	"github.com/rancher/rancher/pkg/apis/management.cattle.io"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/principalmetadata"
+	"github.com/rancher/rancher/pkg/auth/principalscope"
 	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/requests"
 	"github.com/rancher/rancher/pkg/auth/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
@@ -26,6 +35,8 @@ type principalsHandler struct {
 	auth             requests.Authenticator
 	tokenMGR         *tokens.Manager
 	ac               types.AccessControl
+	scoper           *principalscope.Scoper
+	metadataCache    mgmtcontrollers.PrincipalMetadataCache
 }
 
 func newPrincipalsHandler(ctx context.Context, clusterRouter requests.ClusterRouter, mgmt *config.ScaledContext) *principalsHandler {
@@ -36,6 +47,8 @@ func newPrincipalsHandler(ctx context.Context, clusterRouter requests.ClusterRou
 		auth:             requests.NewAuthenticator(ctx, clusterRouter, mgmt),
 		tokenMGR:         tokens.NewManager(ctx, mgmt),
 		ac:               mgmt.AccessControl,
+		scoper:           principalscope.NewScoper(mgmt.Wrangler),
+		metadataCache:    mgmt.Wrangler.Mgmt.PrincipalMetadata().Cache(),
 	}
 }
 
@@ -48,6 +61,16 @@ func (h *principalsHandler) actions(actionName string, action *types.Action, api
 	if err := json.NewDecoder(apiContext.Request.Body).Decode(input); err != nil {
 		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
 	}
+	if input.ProjectID != "" && !canAddMembers(apiContext, input.ProjectID) {
+		return httperror.NewAPIError(httperror.PermissionDenied, fmt.Sprintf("can not add members to project %s", input.ProjectID))
+	}
+	if input.ClusterID != "" && !canAddClusterMembers(apiContext, input.ClusterID) {
+		return httperror.NewAPIError(httperror.PermissionDenied, fmt.Sprintf("can not add members to cluster %s", input.ClusterID))
+	}
+	restricted := principalscope.Restricted(apiContext)
+	if restricted && !canSearch(apiContext, input) {
+		return httperror.NewAPIError(httperror.PermissionDenied, "can not search principals")
+	}
 
 	token, err := h.getToken(apiContext.Request)
 	if err != nil {
@@ -56,8 +79,21 @@ func (h *principalsHandler) actions(actionName string, action *types.Action, api
 
 	ps, err := providers.SearchPrincipals(input.Name, input.PrincipalType, token)
 	if err != nil {
+		recordSearch(apiContext.Request, token, input, 0, err)
 		return err
 	}
+	if restricted {
+		if ps, err = h.scoper.Filter(input.ProjectID, ps); err != nil {
+			return err
+		}
+		var groups []string
+		for _, group := range h.tokenMGR.GetGroupsForTokenAuthProvider(token) {
+			groups = append(groups, group.Name)
+		}
+		if ps, err = h.scoper.FilterBreadth(token.GetUserPrincipal().Name, groups, ps); err != nil {
+			return err
+		}
+	}
 
 	var principals []map[string]interface{}
 	for _, p := range ps {
@@ -70,6 +106,7 @@ func (h *principalsHandler) actions(actionName string, action *types.Action, api
 
 	context := map[string]string{"resource": "principals", "apiGroup": "management.cattle.io"}
 	principals = h.ac.FilterList(apiContext, apiContext.Schema, principals, context)
+	recordSearch(apiContext.Request, token, input, len(principals), nil)
 
 	apiContext.WriteResponse(http.StatusOK, principals)
 	return nil
@@ -84,13 +121,17 @@ func (h *principalsHandler) list(apiContext *types.APIContext, next types.Reques
 	}
 
 	if apiContext.ID != "" {
-		princ, err := providers.GetPrincipal(apiContext.ID, token)
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				return httperror.NewAPIError(httperror.NotFound, err.Error())
-			}
+		princ, ok := h.cachedPrincipal(apiContext.ID, token)
+		if !ok {
+			princ, err = providers.GetPrincipal(apiContext.ID, token)
+			recordResolution(apiContext.Request, token, apiContext.ID, err)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return httperror.NewAPIError(httperror.NotFound, err.Error())
+				}
 
-			return err
+				return err
+			}
 		}
 
 		p, err := convertPrincipal(apiContext.Schema, princ)
@@ -124,6 +165,94 @@ func (h *principalsHandler) list(apiContext *types.APIContext, next types.Reques
 	return nil
 }
 
+// cachedPrincipal returns the principal from its cached metadata, if the principal is bound to roles and its metadata
+// was synced, so that the principals of the binding listings aren't looked up from their provider.
+func (h *principalsHandler) cachedPrincipal(principalID string, token accessor.TokenAccessor) (v32.Principal, bool) {
+	metadata, err := h.metadataCache.Get(principalmetadata.Name(principalID))
+	if err != nil || metadata.PrincipalID != principalID || metadata.LastSync == nil {
+		return v32.Principal{}, false
+	}
+
+	principal := principalmetadata.Principal(metadata)
+	principal.Me = principalID == token.GetUserPrincipal().Name
+	if principal.PrincipalType == common.GroupPrincipalType {
+		for _, group := range h.tokenMGR.GetGroupsForTokenAuthProvider(token) {
+			if group.Name == principalID {
+				principal.MemberOf = true
+				break
+			}
+		}
+	}
+	return principal, true
+}
+
+// recordSearch records the search of principals, with the number of principals found, as an auth event.
+func recordSearch(req *http.Request, token accessor.TokenAccessor, input *v32.SearchPrincipalsInput, found int, err error) {
+	event := authevents.Event{
+		Type:     authevents.PrincipalsSearched,
+		UserName: token.GetUserID(),
+		Provider: token.GetAuthProvider(),
+		SourceIP: requests.ClientIP(req),
+		Target:   input.Name,
+		Detail:   fmt.Sprintf("%d principals found", found),
+	}
+	if input.PrincipalType != "" {
+		event.Detail = fmt.Sprintf("%d %s principals found", found, input.PrincipalType)
+	}
+	if err != nil {
+		event.Reason = authevents.ReasonProviderError
+		event.Detail = err.Error()
+	}
+	authevents.Record(event)
+}
+
+// recordResolution records the lookup of a principal in the auth providers as an auth event. The principals found in
+// the cached metadata aren't recorded, as they're bound to roles already, and weren't looked up in the directories.
+func recordResolution(req *http.Request, token accessor.TokenAccessor, principalID string, err error) {
+	event := authevents.Event{
+		Type:        authevents.PrincipalResolved,
+		UserName:    token.GetUserID(),
+		PrincipalID: principalID,
+		Provider:    token.GetAuthProvider(),
+		SourceIP:    requests.ClientIP(req),
+	}
+	switch {
+	case apierrors.IsNotFound(err):
+		event.Reason = authevents.ReasonPrincipalNotFound
+	case err != nil:
+		event.Reason = authevents.ReasonProviderError
+		event.Detail = err.Error()
+	}
+	authevents.Record(event)
+}
+
+// canAddMembers returns true if the user can create the ProjectRoleTemplateBindings of the project, given as
+// <cluster>:<project>.
+func canAddMembers(apiContext *types.APIContext, projectID string) bool {
+	_, projectName := ref.Parse(projectID)
+	obj := map[string]interface{}{rbac.NamespaceID: projectName}
+	return apiContext.AccessControl.CanDo(management.GroupName, "projectroletemplatebindings", "create", apiContext, obj, apiContext.Schema) == nil
+}
+
+// canAddClusterMembers returns true if the user can create the ClusterRoleTemplateBindings of the cluster.
+func canAddClusterMembers(apiContext *types.APIContext, clusterID string) bool {
+	obj := map[string]interface{}{rbac.NamespaceID: clusterID}
+	return apiContext.AccessControl.CanDo(management.GroupName, "clusterroletemplatebindings", "create", apiContext, obj, apiContext.Schema) == nil
+}
+
+// canSearch returns true if the user may search principals. Unless the principal-search-requires-permission setting is
+// on, anyone may. Otherwise the user must be searching them to add members to a project or a cluster, which was
+// authorized already, or be allowed the search verb on principals.
+func canSearch(apiContext *types.APIContext, input *v32.SearchPrincipalsInput) bool {
+	if settings.PrincipalSearchRequiresPermission.Get() != "true" {
+		return true
+	}
+	if input.ProjectID != "" || input.ClusterID != "" {
+		return true
+	}
+	return apiContext.AccessControl.CanDo(management.GroupName, "principals", "search", apiContext, nil, apiContext.Schema) == nil
+}
+
 func convertPrincipal(schema *types.Schema, principal v3.Principal) (map[string]interface{}, error) {
 	data, err := convert.EncodeToMap(principal)
 	if err != nil {
@@ -0,0 +1,258 @@
+// Package principalscope restricts the principals the users who aren't admins can search and add as project members
+// to those of designated groups and organizational units, so that the project owners can manage the members of their
+// projects without being able to enumerate the whole directory. The scopes are configured per project, with the
+// memberSearchScope of the project, or per auth provider, with the principal-search-scopes setting. The
+// principal-search-breadth setting further restricts the searches of each provider to the groups of the user searching,
+// or to their organizational unit.
+package principalscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/apis/management.cattle.io"
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// membersPageSize is how many members of a designated group are listed at a time.
+const membersPageSize = 500
+
+const (
+	// BreadthOwnGroups restricts the searches to the groups of the user searching and to their members.
+	BreadthOwnGroups = "own-groups"
+	// BreadthSameOU restricts the searches to the principals of the organizational unit of the user searching.
+	BreadthSameOU = "same-ou"
+	// BreadthDirectory doesn't restrict the searches.
+	BreadthDirectory = "directory"
+)
+
+// Scoper finds the search scopes of the principals and tells whether principals are in them.
+type Scoper struct {
+	projectCache    mgmtcontrollers.ProjectCache
+	getMemberLister func(string) common.GroupMemberLister
+	providerScopes  func() string
+	breadths        func() string
+}
+
+func NewScoper(wContext *wrangler.Context) *Scoper {
+	return &Scoper{
+		projectCache:    wContext.Mgmt.Project().Cache(),
+		getMemberLister: providers.GetGroupMemberLister,
+		providerScopes:  settings.PrincipalSearchScopes.Get,
+		breadths:        settings.PrincipalSearchBreadth.Get,
+	}
+}
+
+// Restricted returns true if the user of the request is restricted to the search scopes, which is the case unless
+// they can list all the users.
+func Restricted(apiContext *types.APIContext) bool {
+	return apiContext.AccessControl.CanDo(management.GroupName, apiv3.UserResourceName, "list", apiContext, nil, apiContext.Schema) != nil
+}
+
+// Scope returns the search scope of the principals of the provider added to the project, or nil if they aren't
+// restricted. The scope of the project, if it has one, applies to the principals of every provider. The project ID is
+// <cluster>:<project>, and is empty when the principals aren't added to a project.
+func (s *Scoper) Scope(projectID, provider string) (*apiv3.PrincipalSearchScope, error) {
+	if projectID != "" {
+		clusterName, projectName := ref.Parse(projectID)
+		project, err := s.projectCache.Get(clusterName, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("getting project %s: %w", projectID, err)
+		}
+		if project.Spec.MemberSearchScope != nil {
+			return project.Spec.MemberSearchScope, nil
+		}
+	}
+
+	value := s.providerScopes()
+	if value == "" {
+		return nil, nil
+	}
+	var scopes map[string]*apiv3.PrincipalSearchScope
+	if err := json.Unmarshal([]byte(value), &scopes); err != nil {
+		return nil, fmt.Errorf("parsing setting %s: %w", settings.PrincipalSearchScopes.Name, err)
+	}
+	return scopes[provider], nil
+}
+
+// Filter returns the principals which are in the search scope of their provider in the project.
+func (s *Scoper) Filter(projectID string, principals []apiv3.Principal) ([]apiv3.Principal, error) {
+	matchers := map[string]*Matcher{}
+	var filtered []apiv3.Principal
+	for _, principal := range principals {
+		provider := providers.PrincipalProvider(principal.Name)
+		matcher, ok := matchers[provider]
+		if !ok {
+			scope, err := s.Scope(projectID, provider)
+			if err != nil {
+				return nil, err
+			}
+			if scope != nil {
+				if matcher, err = s.Matcher(scope); err != nil {
+					return nil, err
+				}
+			}
+			matchers[provider] = matcher
+		}
+		if matcher == nil || matcher.Allows(principal.Name) {
+			filtered = append(filtered, principal)
+		}
+	}
+	return filtered, nil
+}
+
+// Allowed returns true if one of the principals is in the search scope of its provider in the project.
+func (s *Scoper) Allowed(projectID string, principalIDs ...string) (bool, error) {
+	for _, principalID := range principalIDs {
+		filtered, err := s.Filter(projectID, []apiv3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: principalID}}})
+		if err != nil {
+			return false, err
+		}
+		if len(filtered) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Breadth returns the search breadth of the principals of the provider, BreadthDirectory unless the
+// principal-search-breadth setting restricts it.
+func (s *Scoper) Breadth(provider string) (string, error) {
+	value := s.breadths()
+	if value == "" {
+		return BreadthDirectory, nil
+	}
+	var breadths map[string]string
+	if err := json.Unmarshal([]byte(value), &breadths); err != nil {
+		return "", fmt.Errorf("parsing setting %s: %w", settings.PrincipalSearchBreadth.Name, err)
+	}
+	switch breadth := breadths[provider]; breadth {
+	case "":
+		return BreadthDirectory, nil
+	case BreadthOwnGroups, BreadthSameOU, BreadthDirectory:
+		return breadth, nil
+	default:
+		return "", fmt.Errorf("invalid search breadth %q of provider %s in setting %s", breadth, provider, settings.PrincipalSearchBreadth.Name)
+	}
+}
+
+// FilterBreadth returns the principals which are within the search breadth of their provider for the user, given as
+// their principal and the principals of their groups. The user can always find themselves.
+func (s *Scoper) FilterBreadth(userPrincipalID string, groupPrincipalIDs []string, principals []apiv3.Principal) ([]apiv3.Principal, error) {
+	matchers := map[string]*Matcher{}
+	var filtered []apiv3.Principal
+	for _, principal := range principals {
+		provider := providers.PrincipalProvider(principal.Name)
+		matcher, ok := matchers[provider]
+		if !ok {
+			breadth, err := s.Breadth(provider)
+			if err != nil {
+				return nil, err
+			}
+			switch breadth {
+			case BreadthOwnGroups:
+				var groups []string
+				for _, group := range groupPrincipalIDs {
+					if providers.PrincipalProvider(group) == provider {
+						groups = append(groups, group)
+					}
+				}
+				if matcher, err = s.Matcher(&apiv3.PrincipalSearchScope{GroupPrincipals: groups}); err != nil {
+					return nil, err
+				}
+			case BreadthSameOU:
+				matcher = &Matcher{}
+				if providers.PrincipalProvider(userPrincipalID) == provider {
+					if _, dn, ok := strings.Cut(userPrincipalID, "://"); ok {
+						if _, ou, ok := strings.Cut(normalizeDN(dn), ","); ok && ou != "" {
+							matcher.bases = []string{ou}
+						}
+					}
+				}
+			}
+			matchers[provider] = matcher
+		}
+		if matcher == nil || principal.Name == userPrincipalID || matcher.Allows(principal.Name) {
+			filtered = append(filtered, principal)
+		}
+	}
+	return filtered, nil
+}
+
+// Matcher tells whether principals are in a search scope.
+type Matcher struct {
+	groups  map[string]bool
+	members map[string]bool
+	bases   []string
+}
+
+// Matcher returns the matcher of the scope. The members of its groups are listed from their provider, if it can list
+// the members of groups.
+func (s *Scoper) Matcher(scope *apiv3.PrincipalSearchScope) (*Matcher, error) {
+	m := &Matcher{groups: map[string]bool{}, members: map[string]bool{}}
+	for _, base := range scope.SearchBases {
+		if base = normalizeDN(base); base != "" {
+			m.bases = append(m.bases, base)
+		}
+	}
+	for _, group := range scope.GroupPrincipals {
+		m.groups[group] = true
+		lister := s.getMemberLister(providers.PrincipalProvider(group))
+		if lister == nil {
+			continue
+		}
+		continueToken := ""
+		for {
+			members, next, err := lister.ListGroupMembers(group, membersPageSize, continueToken)
+			if err != nil {
+				return nil, fmt.Errorf("listing the members of %s: %w", group, err)
+			}
+			for _, member := range members {
+				m.members[member.Name] = true
+			}
+			if next == "" {
+				break
+			}
+			continueToken = next
+		}
+	}
+	return m, nil
+}
+
+// Allows returns true if the principal is one of the groups of the scope, a member of one of them, or if its
+// distinguished name is in one of the organizational units of the scope.
+func (m *Matcher) Allows(principalID string) bool {
+	if m.groups[principalID] || m.members[principalID] {
+		return true
+	}
+	_, dn, ok := strings.Cut(principalID, "://")
+	if !ok {
+		return false
+	}
+	dn = normalizeDN(dn)
+	for _, base := range m.bases {
+		if dn == base || strings.HasSuffix(dn, ","+base) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDN lowercases the distinguished name and removes the spaces around its components, so that
+// OU=Team A, DC=example matches ou=team a,dc=example.
+func normalizeDN(dn string) string {
+	components := strings.Split(strings.ToLower(dn), ",")
+	for i, component := range components {
+		components[i] = strings.TrimSpace(component)
+	}
+	return strings.Join(components, ",")
+}
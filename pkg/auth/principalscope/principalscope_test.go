@@ -0,0 +1,180 @@
+package principalscope
+
+import (
+	"testing"
+
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	teamA = "openldap_group://cn=team-a,ou=groups,dc=example,dc=com"
+	alice = "openldap_user://uid=alice,ou=people,dc=example,dc=com"
+	bob   = "openldap_user://uid=bob,OU=Contractors, DC=example,DC=com"
+	carol = "openldap_user://uid=carol,ou=people,dc=example,dc=com"
+)
+
+// fakeMemberLister lists the members of the groups two at a time.
+type fakeMemberLister map[string][]apiv3.Principal
+
+func (f fakeMemberLister) ListGroupMembers(principalID string, _ int, continueToken string) ([]apiv3.Principal, string, error) {
+	return common.PagePrincipals(f[principalID], 2, continueToken)
+}
+
+func principals(ids ...string) []apiv3.Principal {
+	var principals []apiv3.Principal
+	for _, id := range ids {
+		principals = append(principals, apiv3.Principal{ObjectMeta: metav1.ObjectMeta{Name: id}})
+	}
+	return principals
+}
+
+func newScoper(t *testing.T, setting string) *Scoper {
+	ctrl := gomock.NewController(t)
+	projectCache := fake.NewMockCacheInterface[*apiv3.Project](ctrl)
+	projectCache.EXPECT().Get("c-abc", "p-scoped").Return(&apiv3.Project{
+		Spec: apiv3.ProjectSpec{MemberSearchScope: &apiv3.PrincipalSearchScope{SearchBases: []string{"ou=contractors,dc=example,dc=com"}}},
+	}, nil).AnyTimes()
+	projectCache.EXPECT().Get("c-abc", "p-default").Return(&apiv3.Project{}, nil).AnyTimes()
+
+	lister := fakeMemberLister{teamA: principals(alice, "openldap_user://uid=dave,ou=people,dc=example,dc=com", "openldap_user://uid=erin,ou=people,dc=example,dc=com")}
+	return &Scoper{
+		projectCache: projectCache,
+		getMemberLister: func(provider string) common.GroupMemberLister {
+			if provider == "openldap" {
+				return lister
+			}
+			return nil
+		},
+		providerScopes: func() string { return setting },
+		breadths:       func() string { return "" },
+	}
+}
+
+func TestScope(t *testing.T) {
+	t.Parallel()
+	s := newScoper(t, `{"openldap": {"groupPrincipals": ["`+teamA+`"]}}`)
+
+	scope, err := s.Scope("c-abc:p-scoped", "openldap")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ou=contractors,dc=example,dc=com"}, scope.SearchBases, "the scope of the project overrides the setting")
+
+	scope, err = s.Scope("c-abc:p-default", "openldap")
+	require.NoError(t, err)
+	assert.Equal(t, []string{teamA}, scope.GroupPrincipals)
+
+	scope, err = s.Scope("", "github")
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+
+	_, err = newScoper(t, "{invalid").Scope("", "openldap")
+	assert.Error(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	s := newScoper(t, `{"openldap": {"groupPrincipals": ["`+teamA+`"]}}`)
+
+	// carol isn't a member of team-a, and the principals of github aren't restricted.
+	filtered, err := s.Filter("", principals(teamA, alice, carol, "github_user://123"))
+	require.NoError(t, err)
+	assert.Equal(t, principals(teamA, alice, "github_user://123"), filtered)
+
+	filtered, err = s.Filter("c-abc:p-scoped", principals(teamA, alice, bob, "github_user://123"))
+	require.NoError(t, err)
+	assert.Equal(t, principals(bob), filtered)
+}
+
+func TestAllowed(t *testing.T) {
+	t.Parallel()
+	s := newScoper(t, `{"openldap": {"searchBases": ["ou=people,dc=example,dc=com"]}}`)
+
+	allowed, err := s.Allowed("c-abc:p-default", "local://u-abcde", carol)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = s.Allowed("c-abc:p-scoped", "local://u-abcde", carol)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestMatcherAllows(t *testing.T) {
+	t.Parallel()
+	m := &Matcher{
+		groups:  map[string]bool{teamA: true},
+		members: map[string]bool{carol: true},
+		bases:   []string{normalizeDN("OU=Contractors, DC=example,DC=com")},
+	}
+
+	assert.True(t, m.Allows(teamA))
+	assert.True(t, m.Allows(carol))
+	assert.True(t, m.Allows(bob))
+	assert.True(t, m.Allows("activedirectory_user://ou=contractors,dc=example,dc=com"))
+	assert.False(t, m.Allows(alice))
+	assert.False(t, m.Allows("openldap_user://uid=mallory,ou=evilcontractors,dc=example,dc=com"))
+	assert.False(t, m.Allows("u-abcde"))
+}
+
+func TestFilterBreadth(t *testing.T) {
+	t.Parallel()
+	teamB := "openldap_group://cn=team-b,ou=groups,dc=example,dc=com"
+	dave := "openldap_user://uid=dave,ou=people,dc=example,dc=com"
+	found := principals(teamA, teamB, alice, bob, carol, dave, "local://u-abcde")
+
+	tests := []struct {
+		name     string
+		setting  string
+		expected []apiv3.Principal
+		error    string
+	}{
+		{
+			name:     "no setting",
+			expected: found,
+		},
+		{
+			name:     "directory",
+			setting:  `{"openldap": "directory"}`,
+			expected: found,
+		},
+		{
+			name:     "own groups",
+			setting:  `{"openldap": "own-groups"}`,
+			expected: principals(teamA, alice, carol, dave, "local://u-abcde"),
+		},
+		{
+			name:     "same ou",
+			setting:  `{"openldap": "same-ou", "local": "own-groups"}`,
+			expected: principals(alice, carol, dave),
+		},
+		{
+			name:    "invalid breadth",
+			setting: `{"openldap": "everyone"}`,
+			error:   `invalid search breadth "everyone" of provider openldap`,
+		},
+		{
+			name:    "invalid setting",
+			setting: `["openldap"]`,
+			error:   "parsing setting principal-search-breadth",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := newScoper(t, "")
+			s.breadths = func() string { return tt.setting }
+
+			filtered, err := s.FilterBreadth(carol, []string{teamA}, found)
+			if tt.error != "" {
+				assert.ErrorContains(t, err, tt.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filtered)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package provenance records on the role template bindings how they were created and by whom, so that the controllers
+// managing bindings only act on the ones they own when several automation sources manage the bindings of a cluster.
+package provenance
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SourceLabel is set on the role template bindings to the source which created them. Being a label, the bindings
+	// can be filtered by their source with the API.
+	SourceLabel = "auth.cattle.io/binding-source"
+	// CreatorAnnotation is set on the role template bindings to who created them: the user for the bindings created
+	// with the API, and the kind and name of the object they're derived from for the ones created by controllers.
+	CreatorAnnotation = "auth.cattle.io/binding-creator"
+)
+
+// The sources of the role template bindings.
+const (
+	SourceManual              = "manual"
+	SourceTerraform           = "terraform"
+	SourceSCIM                = "scim"
+	SourceJITPolicy           = "jit-policy"
+	SourceGroupRule           = "group-rule"
+	SourceAttributeRule       = "attribute-rule"
+	SourceOrganization        = "organization"
+	SourceAccessRequest       = "access-request"
+	SourceProjectHierarchy    = "project-hierarchy"
+	SourceMultiClusterBinding = "multi-cluster-binding"
+)
+
+// apiSources are the sources the callers of the API can declare for the bindings they create, the other ones being
+// reserved to the controllers.
+var apiSources = []string{SourceManual, SourceTerraform, SourceSCIM}
+
+// Set records the source and the creator of a binding in its metadata.
+func Set(meta *metav1.ObjectMeta, source, creator string) {
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	meta.Labels[SourceLabel] = source
+	if creator == "" {
+		return
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[CreatorAnnotation] = creator
+}
+
+// Creator returns the creator of a binding derived from an object, as the lowercase kind and the name of the object.
+func Creator(kind, name string) string {
+	return strings.ToLower(kind) + "/" + name
+}
+
+// Source returns the source of a binding, or an empty string if it was created before its provenance was recorded.
+func Source(obj metav1.Object) string {
+	return obj.GetLabels()[SourceLabel]
+}
+
+// Owns returns true if the binding was created by the source. The bindings without a source, created before their
+// provenance was recorded, are owned by all the sources, the controllers selecting them with their own labels.
+func Owns(obj metav1.Object, source string) bool {
+	current := Source(obj)
+	return current == "" || current == source
+}
+
+// Managed returns true if the binding was created by a controller, which manages it, rather than with the API.
+func Managed(obj metav1.Object) bool {
+	source := Source(obj)
+	return source != "" && !slices.Contains(apiSources, source)
+}
+
+// APISource returns the source of a binding created with the API: the one declared in its SourceLabel, or
+// SourceManual. It returns an error if the declared source is reserved to the controllers.
+func APISource(source string) (string, error) {
+	if source == "" {
+		return SourceManual, nil
+	}
+	if !slices.Contains(apiSources, source) {
+		return "", fmt.Errorf("%s must be one of %s", SourceLabel, strings.Join(apiSources, ", "))
+	}
+	return source, nil
+}
@@ -0,0 +1,57 @@
+package provenance
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSet(t *testing.T) {
+	meta := metav1.ObjectMeta{Labels: map[string]string{"auth.cattle.io/group-membership-rule": "devs"}}
+	Set(&meta, SourceGroupRule, Creator("GroupMembershipRule", "devs"))
+	assert.Equal(t, map[string]string{"auth.cattle.io/group-membership-rule": "devs", SourceLabel: SourceGroupRule}, meta.Labels)
+	assert.Equal(t, map[string]string{CreatorAnnotation: "groupmembershiprule/devs"}, meta.Annotations)
+
+	// The creator is unknown for the bindings created by the system users.
+	meta = metav1.ObjectMeta{}
+	Set(&meta, SourceManual, "")
+	assert.Equal(t, SourceManual, meta.Labels[SourceLabel])
+	assert.Nil(t, meta.Annotations)
+}
+
+func TestOwns(t *testing.T) {
+	binding := &v3.ClusterRoleTemplateBinding{}
+	assert.True(t, Owns(binding, SourceOrganization), "bindings without a source are owned by all the sources")
+
+	Set(&binding.ObjectMeta, SourceOrganization, Creator("Organization", "acme"))
+	assert.Equal(t, SourceOrganization, Source(binding))
+	assert.True(t, Owns(binding, SourceOrganization))
+	assert.False(t, Owns(binding, SourceGroupRule))
+}
+
+func TestManaged(t *testing.T) {
+	binding := &v3.ProjectRoleTemplateBinding{}
+	assert.False(t, Managed(binding))
+
+	Set(&binding.ObjectMeta, SourceTerraform, "")
+	assert.False(t, Managed(binding))
+
+	Set(&binding.ObjectMeta, SourceAccessRequest, Creator("AccessRequest", "ar-1"))
+	assert.True(t, Managed(binding))
+}
+
+func TestAPISource(t *testing.T) {
+	source, err := APISource("")
+	require.NoError(t, err)
+	assert.Equal(t, SourceManual, source)
+
+	source, err = APISource(SourceTerraform)
+	require.NoError(t, err)
+	assert.Equal(t, SourceTerraform, source)
+
+	_, err = APISource(SourceJITPolicy)
+	assert.EqualError(t, err, "auth.cattle.io/binding-source must be one of manual, terraform, scim")
+}
@@ -0,0 +1,245 @@
+// Package providerprobe periodically probes the identity services of the enabled auth providers and publishes the
+// results in the connectivity status and conditions of their auth configs.
+package providerprobe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/condition"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providerrefresh"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	probeInterval = 5 * time.Minute
+	// maxStatusAge is how long the status of an auth config is kept while probes find nothing new.
+	// Every update of an auth config refreshes all of its users, so it's only updated when the status
+	// changes or gets this old.
+	maxStatusAge = time.Hour
+	// expiryWarning is how long before a credential expires the CredentialsValid condition starts warning about it.
+	expiryWarning = 30 * 24 * time.Hour
+)
+
+type authConfigsClient interface {
+	Get(name string, opts metav1.GetOptions) (runtime.Object, error)
+	Update(name string, o runtime.Object) (runtime.Object, error)
+}
+
+// Prober probes the identity services of the enabled auth providers.
+type Prober struct {
+	authConfigs      authConfigsClient
+	enabledProviders func() []string
+	getProber        func(string) common.Prober
+	lastSyncTime     func(string) time.Time
+	now              func() time.Time
+}
+
+// Start probes the enabled auth providers until the context is done.
+func Start(ctx context.Context, mgmt *config.ManagementContext) {
+	p := &Prober{
+		authConfigs:      mgmt.Management.AuthConfigs("").ObjectClient().UnstructuredClient(),
+		enabledProviders: providers.EnabledProviders,
+		getProber:        providers.GetProber,
+		lastSyncTime:     providerrefresh.LastSyncTime,
+		now:              time.Now,
+	}
+	go wait.UntilWithContext(ctx, p.Run, probeInterval)
+}
+
+// Run probes each enabled auth provider that can probe its identity service and updates the status of its auth config.
+func (p *Prober) Run(ctx context.Context) {
+	for _, name := range p.enabledProviders() {
+		prober := p.getProber(name)
+		if prober == nil {
+			continue
+		}
+		if err := p.probe(ctx, name, prober); err != nil {
+			logrus.Errorf("[providerprobe] failed to update the status of auth config %s: %v", name, err)
+		}
+	}
+}
+
+func (p *Prober) probe(ctx context.Context, name string, prober common.Prober) error {
+	now := p.now()
+	connectivity, err := prober.Probe(ctx)
+	if err != nil {
+		connectivity = &v3.AuthConfigConnectivity{Error: err.Error()}
+	}
+	connectivity.LastProbeTime = now.UTC().Format(time.RFC3339)
+	if lastSync := p.lastSyncTime(name); !lastSync.IsZero() {
+		connectivity.LastSyncTime = lastSync.UTC().Format(time.RFC3339)
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := p.authConfigs.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		// The auth config is updated unstructured, since the AuthConfig type lacks the fields of the provider.
+		u, ok := obj.(runtime.Unstructured)
+		if !ok {
+			return fmt.Errorf("failed to read unstructured data for AuthConfig %s", name)
+		}
+		content := u.UnstructuredContent()
+
+		authConfig := &v3.AuthConfig{}
+		if err := convert(content["status"], &authConfig.Status); err != nil {
+			return fmt.Errorf("failed to decode the status: %w", err)
+		}
+		previous := authConfig.Status.DeepCopy()
+
+		updated := *connectivity
+		// The last metadata refresh and sync outlive failed probes and restarts.
+		if stored := previous.Connectivity; stored != nil {
+			if updated.MetadataRefreshTime == "" {
+				updated.MetadataRefreshTime = stored.MetadataRefreshTime
+			}
+			if updated.LastSyncTime == "" {
+				updated.LastSyncTime = stored.LastSyncTime
+			}
+		}
+		authConfig.Status.Connectivity = &updated
+		setConditions(authConfig, now)
+
+		if !changed(previous, &authConfig.Status) && !stale(previous.Connectivity, now) {
+			return nil
+		}
+
+		var status map[string]any
+		if err := convert(authConfig.Status, &status); err != nil {
+			return fmt.Errorf("failed to encode the status: %w", err)
+		}
+		content["status"] = status
+		u.SetUnstructuredContent(content)
+		_, err = p.authConfigs.Update(name, u)
+		return err
+	})
+}
+
+// setConditions sets the conditions of the auth config from its connectivity status.
+func setConditions(authConfig *v3.AuthConfig, now time.Time) {
+	connectivity := authConfig.Status.Connectivity
+
+	var unreachable []string
+	for _, endpoint := range connectivity.Endpoints {
+		if !endpoint.Reachable {
+			unreachable = append(unreachable, fmt.Sprintf("%s %s: %s", endpoint.Name, endpoint.URL, endpoint.Error))
+		}
+	}
+	switch {
+	case len(unreachable) > 0:
+		setCondition(authConfig, v3.AuthConfigConditionReachable, false, "Unreachable", strings.Join(unreachable, "; "))
+	case connectivity.Error != "":
+		setCondition(authConfig, v3.AuthConfigConditionReachable, false, "ProbeFailed", connectivity.Error)
+	default:
+		setCondition(authConfig, v3.AuthConfigConditionReachable, true, "", "")
+	}
+
+	var expired, expiring []string
+	for _, credential := range connectivity.Credentials {
+		expiresAt, err := time.Parse(time.RFC3339, credential.ExpiresAt)
+		if err != nil {
+			continue
+		}
+		description := credential.Name
+		if credential.Subject != "" {
+			description += " " + credential.Subject
+		}
+		if !now.Before(expiresAt) {
+			expired = append(expired, description+" expired at "+credential.ExpiresAt)
+		} else if expiresAt.Sub(now) < expiryWarning {
+			expiring = append(expiring, description+" expires at "+credential.ExpiresAt)
+		}
+	}
+	switch {
+	case len(expired) > 0:
+		setCondition(authConfig, v3.AuthConfigConditionCredentialsValid, false, "Expired", strings.Join(expired, "; "))
+	case len(expiring) > 0:
+		setCondition(authConfig, v3.AuthConfigConditionCredentialsValid, true, "ExpiringSoon", strings.Join(expiring, "; "))
+	default:
+		setCondition(authConfig, v3.AuthConfigConditionCredentialsValid, true, "", "")
+	}
+
+	if connectivity.MetadataValidUntil != "" {
+		validUntil, err := time.Parse(time.RFC3339, connectivity.MetadataValidUntil)
+		if err == nil && !now.Before(validUntil) {
+			setCondition(authConfig, v3.AuthConfigConditionMetadataValid, false, "Expired",
+				"the metadata of the identity service expired at "+connectivity.MetadataValidUntil)
+		} else {
+			setCondition(authConfig, v3.AuthConfigConditionMetadataValid, true, "", "")
+		}
+	}
+}
+
+func setCondition(authConfig *v3.AuthConfig, cond condition.Cond, status bool, reason, message string) {
+	if status {
+		cond.True(authConfig)
+	} else {
+		cond.False(authConfig)
+	}
+	cond.Reason(authConfig, reason)
+	cond.Message(authConfig, message)
+}
+
+// changed returns whether the probe found something new, ignoring when probes, metadata refreshes and syncs ran.
+func changed(previous, current *v3.AuthConfigStatus) bool {
+	var before, after map[string]any
+	if err := convert(withoutTimes(previous), &before); err != nil {
+		return true
+	}
+	if err := convert(withoutTimes(current), &after); err != nil {
+		return true
+	}
+	return !reflect.DeepEqual(before, after)
+}
+
+func withoutTimes(status *v3.AuthConfigStatus) *v3.AuthConfigStatus {
+	status = status.DeepCopy()
+	if connectivity := status.Connectivity; connectivity != nil {
+		connectivity.LastProbeTime = ""
+		connectivity.MetadataRefreshTime = ""
+		connectivity.LastSyncTime = ""
+	}
+	for i := range status.Conditions {
+		status.Conditions[i].LastUpdateTime = ""
+		status.Conditions[i].LastTransitionTime = ""
+	}
+	return status
+}
+
+// stale returns whether the stored connectivity status is older than maxStatusAge.
+func stale(connectivity *v3.AuthConfigConnectivity, now time.Time) bool {
+	if connectivity == nil {
+		return true
+	}
+	lastProbe, err := time.Parse(time.RFC3339, connectivity.LastProbeTime)
+	if err != nil {
+		return true
+	}
+	return now.Sub(lastProbe) >= maxStatusAge
+}
+
+// convert converts between the typed and the unstructured status through their JSON encoding.
+func convert(in, out any) error {
+	if in == nil {
+		return nil
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,242 @@
+package providerprobe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type fakeAuthConfigs struct {
+	objects map[string]*unstructured.Unstructured
+	updates int
+}
+
+func (f *fakeAuthConfigs) Get(name string, _ metav1.GetOptions) (runtime.Object, error) {
+	obj, ok := f.objects[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (f *fakeAuthConfigs) Update(name string, o runtime.Object) (runtime.Object, error) {
+	f.updates++
+	f.objects[name] = o.(*unstructured.Unstructured).DeepCopy()
+	return o, nil
+}
+
+type fakeProber struct {
+	connectivity *v3.AuthConfigConnectivity
+	err          error
+}
+
+func (f *fakeProber) Probe(context.Context) (*v3.AuthConfigConnectivity, error) {
+	if f.connectivity == nil {
+		return nil, f.err
+	}
+	connectivity := *f.connectivity
+	return &connectivity, f.err
+}
+
+func newAuthConfigs() *fakeAuthConfigs {
+	return &fakeAuthConfigs{
+		objects: map[string]*unstructured.Unstructured{
+			"openldap": {Object: map[string]any{
+				"metadata": map[string]any{"name": "openldap"},
+				"type":     "openLdapConfig",
+				"enabled":  true,
+				"servers":  []any{"ldap.example.com"},
+				"status": map[string]any{
+					"conditions": []any{map[string]any{"type": "SecretsMigrated", "status": "True"}},
+				},
+			}},
+		},
+	}
+}
+
+func newTestProber(authConfigs *fakeAuthConfigs, prober common.Prober, now *time.Time) *Prober {
+	return &Prober{
+		authConfigs:      authConfigs,
+		enabledProviders: func() []string { return []string{"openldap", "local"} },
+		getProber: func(name string) common.Prober {
+			if name == "openldap" {
+				return prober
+			}
+			return nil
+		},
+		lastSyncTime: func(string) time.Time { return time.Time{} },
+		now:          func() time.Time { return *now },
+	}
+}
+
+func getStatus(t *testing.T, authConfigs *fakeAuthConfigs) v3.AuthConfigStatus {
+	t.Helper()
+	var status v3.AuthConfigStatus
+	require.NoError(t, convert(authConfigs.objects["openldap"].Object["status"], &status))
+	return status
+}
+
+func getCondition(status v3.AuthConfigStatus, cond string) *v3.AuthConfigConditions {
+	for i := range status.Conditions {
+		if string(status.Conditions[i].Type) == cond {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	authConfigs := newAuthConfigs()
+	prober := &fakeProber{connectivity: &v3.AuthConfigConnectivity{
+		Endpoints: []v3.AuthConfigEndpointStatus{
+			{Name: "server", URL: "ldap.example.com:389", Reachable: true},
+		},
+		Credentials: []v3.AuthConfigCredentialStatus{
+			{Name: "certificate", Subject: "CN=ldap", ExpiresAt: "2025-05-01T12:00:00Z"},
+		},
+	}}
+	p := newTestProber(authConfigs, prober, &now)
+
+	p.Run(context.Background())
+
+	require.Equal(t, 1, authConfigs.updates)
+	obj := authConfigs.objects["openldap"].Object
+	assert.Equal(t, []any{"ldap.example.com"}, obj["servers"], "fields of the provider must be kept")
+
+	status := getStatus(t, authConfigs)
+	require.NotNil(t, status.Connectivity)
+	assert.Equal(t, "2024-05-01T12:00:00Z", status.Connectivity.LastProbeTime)
+	assert.Equal(t, prober.connectivity.Endpoints, status.Connectivity.Endpoints)
+	assert.Equal(t, prober.connectivity.Credentials, status.Connectivity.Credentials)
+	assert.NotNil(t, getCondition(status, "SecretsMigrated"), "other conditions must be kept")
+	assert.Equal(t, "True", string(getCondition(status, "Reachable").Status))
+	assert.Equal(t, "True", string(getCondition(status, "CredentialsValid").Status))
+	assert.Nil(t, getCondition(status, "MetadataValid"))
+
+	// Nothing changed, the status isn't rewritten until it's stale.
+	now = now.Add(10 * time.Minute)
+	p.Run(context.Background())
+	assert.Equal(t, 1, authConfigs.updates)
+
+	now = now.Add(maxStatusAge)
+	p.Run(context.Background())
+	assert.Equal(t, 2, authConfigs.updates)
+	assert.Equal(t, now.Format(time.RFC3339), getStatus(t, authConfigs).Connectivity.LastProbeTime)
+}
+
+func TestRunUpdatesOnChange(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	authConfigs := newAuthConfigs()
+	prober := &fakeProber{connectivity: &v3.AuthConfigConnectivity{
+		Endpoints:           []v3.AuthConfigEndpointStatus{{Name: "jwks", URL: "https://idp.example.com/jwks", Reachable: true}},
+		MetadataRefreshTime: now.Format(time.RFC3339),
+	}}
+	p := newTestProber(authConfigs, prober, &now)
+	p.lastSyncTime = func(string) time.Time { return now.Add(-time.Minute) }
+
+	p.Run(context.Background())
+	require.Equal(t, 1, authConfigs.updates)
+
+	// The endpoint goes down.
+	now = now.Add(probeInterval)
+	prober.connectivity = &v3.AuthConfigConnectivity{
+		Endpoints: []v3.AuthConfigEndpointStatus{{Name: "jwks", URL: "https://idp.example.com/jwks", Error: "connection refused"}},
+	}
+	p.lastSyncTime = func(string) time.Time { return time.Time{} }
+	p.Run(context.Background())
+	require.Equal(t, 2, authConfigs.updates)
+
+	status := getStatus(t, authConfigs)
+	reachable := getCondition(status, "Reachable")
+	assert.Equal(t, "False", string(reachable.Status))
+	assert.Equal(t, "Unreachable", reachable.Reason)
+	assert.Equal(t, "jwks https://idp.example.com/jwks: connection refused", reachable.Message)
+	assert.Equal(t, "2024-05-01T12:00:00Z", status.Connectivity.MetadataRefreshTime, "the last metadata refresh must be kept")
+	assert.Equal(t, "2024-05-01T11:59:00Z", status.Connectivity.LastSyncTime, "the last sync must be kept")
+}
+
+func TestRunProbeError(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	authConfigs := newAuthConfigs()
+	p := newTestProber(authConfigs, &fakeProber{err: errors.New("failed to retrieve openldap")}, &now)
+
+	p.Run(context.Background())
+
+	status := getStatus(t, authConfigs)
+	assert.Equal(t, "failed to retrieve openldap", status.Connectivity.Error)
+	reachable := getCondition(status, "Reachable")
+	assert.Equal(t, "False", string(reachable.Status))
+	assert.Equal(t, "ProbeFailed", reachable.Reason)
+}
+
+func TestSetConditions(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		connectivity v3.AuthConfigConnectivity
+		cond         string
+		status       string
+		reason       string
+		message      string
+	}{
+		{
+			name: "expired certificate",
+			connectivity: v3.AuthConfigConnectivity{Credentials: []v3.AuthConfigCredentialStatus{
+				{Name: "spCert", Subject: "CN=rancher", ExpiresAt: "2024-04-01T00:00:00Z"},
+				{Name: "idpCertificate", ExpiresAt: "2030-01-01T00:00:00Z"},
+			}},
+			cond:    "CredentialsValid",
+			status:  "False",
+			reason:  "Expired",
+			message: "spCert CN=rancher expired at 2024-04-01T00:00:00Z",
+		},
+		{
+			name: "expiring secret",
+			connectivity: v3.AuthConfigConnectivity{Credentials: []v3.AuthConfigCredentialStatus{
+				{Name: "applicationSecret", ExpiresAt: "2024-05-10T00:00:00Z"},
+			}},
+			cond:    "CredentialsValid",
+			status:  "True",
+			reason:  "ExpiringSoon",
+			message: "applicationSecret expires at 2024-05-10T00:00:00Z",
+		},
+		{
+			name:         "expired metadata",
+			connectivity: v3.AuthConfigConnectivity{MetadataValidUntil: "2024-05-01T00:00:00Z"},
+			cond:         "MetadataValid",
+			status:       "False",
+			reason:       "Expired",
+			message:      "the metadata of the identity service expired at 2024-05-01T00:00:00Z",
+		},
+		{
+			name:         "valid metadata",
+			connectivity: v3.AuthConfigConnectivity{MetadataValidUntil: "2024-06-01T00:00:00Z"},
+			cond:         "MetadataValid",
+			status:       "True",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			authConfig := &v3.AuthConfig{Status: v3.AuthConfigStatus{Connectivity: &test.connectivity}}
+
+			setConditions(authConfig, now)
+
+			cond := getCondition(authConfig.Status, test.cond)
+			require.NotNil(t, cond)
+			assert.Equal(t, test.status, string(cond.Status))
+			assert.Equal(t, test.reason, cond.Reason)
+			assert.Equal(t, test.message, cond.Message)
+		})
+	}
+}
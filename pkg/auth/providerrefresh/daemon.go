@@ -3,6 +3,7 @@
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -18,6 +19,11 @@
 var (
 	ref *refresher
 	c   = cron.New()
+
+	lastSyncs = struct {
+		sync.RWMutex
+		times map[string]time.Time
+	}{times: map[string]time.Time{}}
 )
 
 func StartRefreshDaemon(ctx context.Context, scaledContext *config.ScaledContext, mgmtContext *config.ManagementContext) {
@@ -112,3 +118,17 @@ func ParseCron(setting string) (cron.Schedule, error) {
 	}
 	return schedule, nil
 }
+
+// LastSyncTime returns the last time the group principals of a user were refetched from the provider,
+// or the zero time if they haven't been since Rancher started.
+func LastSyncTime(providerName string) time.Time {
+	lastSyncs.RLock()
+	defer lastSyncs.RUnlock()
+	return lastSyncs.times[providerName]
+}
+
+func recordSync(providerName string) {
+	lastSyncs.Lock()
+	defer lastSyncs.Unlock()
+	lastSyncs.times[providerName] = time.Now()
+}
@@ -3,6 +3,7 @@
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +12,7 @@
 
 	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/authevents"
 	"github.com/rancher/rancher/pkg/auth/providers"
 	"github.com/rancher/rancher/pkg/auth/settings"
 	"github.com/rancher/rancher/pkg/auth/tokens"
@@ -258,6 +260,9 @@ func (r *refresher) refreshAttributes(attribs *v3.UserAttribute) (*v3.UserAttrib
 				}
 			} else {
 				newGroupPrincipals, err = providers.RefetchGroupPrincipals(principalID, providerName, secret)
+				if err == nil {
+					recordSync(providerName)
+				}
 				if err != nil {
 					// In the case that we cant access a server, we still want to continue refreshing, but
 					// we no longer want to disable derived tokens, or remove their login tokens for this provider
@@ -282,6 +287,15 @@ func (r *refresher) refreshAttributes(attribs *v3.UserAttribute) (*v3.UserAttrib
 			newGroupPrincipals = nil
 		}
 
+		if added, removed := groupChanges(attribs.GroupPrincipals[providerName].Items, newGroupPrincipals); len(added) > 0 || len(removed) > 0 {
+			authevents.Record(authevents.Event{
+				Type:        authevents.GroupsRefreshed,
+				UserName:    user.Name,
+				PrincipalID: principalID,
+				Provider:    providerName,
+				Detail:      fmt.Sprintf("added groups: [%s], removed groups: [%s]", strings.Join(added, ", "), strings.Join(removed, ", ")),
+			})
+		}
 		attribs.GroupPrincipals[providerName] = v32.Principals{Items: newGroupPrincipals}
 
 		canAccessProvider := false
@@ -372,6 +386,29 @@ func (r *refresher) refreshAttributes(attribs *v3.UserAttribute) (*v3.UserAttrib
 	return attribs, err
 }
 
+// groupChanges returns the names of the group principals added and removed between before and after, sorted.
+func groupChanges(before, after []v3.Principal) (added, removed []string) {
+	previous := make(map[string]bool, len(before))
+	for _, group := range before {
+		previous[group.Name] = true
+	}
+	current := make(map[string]bool, len(after))
+	for _, group := range after {
+		current[group.Name] = true
+		if !previous[group.Name] {
+			added = append(added, group.Name)
+		}
+	}
+	for _, group := range before {
+		if !current[group.Name] {
+			removed = append(removed, group.Name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
 func GetPrincipalIDForProvider(providerName string, user *v3.User) string {
 	prefix := providerName + "_user://"
 	if providerName == "local" {
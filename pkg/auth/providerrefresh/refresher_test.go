@@ -777,3 +777,20 @@ func (p *mockShibbolethProvider) GetUserExtraAttributes(userPrincipal v3.Princip
 func (p *mockShibbolethProvider) CleanupResources(*v3.AuthConfig) error {
 	return nil
 }
+
+func TestGroupChanges(t *testing.T) {
+	group := func(name string) v3.Principal {
+		return v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	added, removed := groupChanges(
+		[]v3.Principal{group("ldap_group://ops"), group("ldap_group://dev")},
+		[]v3.Principal{group("ldap_group://qa"), group("ldap_group://dev"), group("ldap_group://admins")},
+	)
+	assert.Equal(t, []string{"ldap_group://admins", "ldap_group://qa"}, added)
+	assert.Equal(t, []string{"ldap_group://ops"}, removed)
+
+	added, removed = groupChanges([]v3.Principal{group("ldap_group://dev")}, []v3.Principal{group("ldap_group://dev")})
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
@@ -73,8 +73,8 @@ func (p *adProvider) testAndApply(request *types.APIContext) error {
 		return err
 	}
 
-	if len(config.Servers) < 1 {
-		return httperror.NewAPIError(httperror.InvalidBodyContent, "must supply a server")
+	if len(config.Servers) < 1 && config.ServerDiscoveryDomain == "" {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "must supply a server or a server discovery domain")
 	}
 
 	if config.UserSearchAttribute != "" {
@@ -1,6 +1,7 @@
 package activedirectory
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"reflect"
@@ -11,6 +12,8 @@
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types/slice"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/attributelabels"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/providers/common/ldap"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +21,11 @@
 
 var defaultUserAttributes = []string{MemberOfAttribute, ObjectClass, ObjectGUIDAttribute}
 
+// userSearchAttributes returns the default user attributes and the attributes mapped to user labels.
+func userSearchAttributes() []string {
+	return append(append([]string{}, defaultUserAttributes...), attributelabels.Attributes()...)
+}
+
 func (p *adProvider) loginUser(lConn ldapv3.Client, credentials *v3.BasicLogin, config *v3.ActiveDirectoryConfig) (v3.Principal, []v3.Principal, error) {
 	logrus.Debug("Now generating Ldap token")
 
@@ -55,7 +63,7 @@ func (p *adProvider) loginUser(lConn ldapv3.Client, credentials *v3.BasicLogin,
 	searchRequest := ldap.NewWholeSubtreeSearchRequest(
 		config.UserSearchBase,
 		filter,
-		config.GetUserSearchAttributes(defaultUserAttributes...),
+		config.GetUserSearchAttributes(userSearchAttributes()...),
 	)
 
 	result, err := lConn.Search(searchRequest)
@@ -96,6 +104,192 @@ func (p *adProvider) loginUser(lConn ldapv3.Client, credentials *v3.BasicLogin,
 	return userPrincipal, groupPrincipals, err
 }
 
+// HealthCheck connects to Active Directory and binds with the service account.
+func (p *adProvider) HealthCheck() error {
+	config, caPool, err := p.getActiveDirectoryConfig()
+	if err != nil {
+		return err
+	}
+	return p.bindServiceAccount(config, caPool)
+}
+
+// Probe checks that each domain controller accepts connections and that the service account can bind,
+// and reports the expiry of the CA certificates.
+func (p *adProvider) Probe(ctx context.Context) (*v3.AuthConfigConnectivity, error) {
+	config, caPool, err := p.getActiveDirectoryConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	servers, err := domainControllers(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	connectivity := &v3.AuthConfigConnectivity{
+		Endpoints:   ldap.ProbeServers(ctx, servers, config.Port),
+		Credentials: common.CertificateExpiries("certificate", config.Certificate),
+	}
+	if err := p.bindServiceAccount(config, caPool); err != nil {
+		connectivity.Error = err.Error()
+	}
+	return connectivity, nil
+}
+
+func (p *adProvider) bindServiceAccount(config *v3.ActiveDirectoryConfig, caPool *x509.CertPool) error {
+	lConn, err := p.ldapConnection(config, caPool)
+	if err != nil {
+		return err
+	}
+	defer lConn.Close()
+
+	return ldap.AuthenticateServiceAccountUser(config.ServiceAccountPassword, config.ServiceAccountUsername, config.DefaultLoginDomain, lConn)
+}
+
+// UserExists looks up the user principal in Active Directory with the service account.
+func (p *adProvider) UserExists(principalID string) (bool, error) {
+	config, caPool, err := p.getActiveDirectoryConfig()
+	if err != nil {
+		return false, err
+	}
+
+	lConn, err := p.ldapConnection(config, caPool)
+	if err != nil {
+		return false, err
+	}
+	defer lConn.Close()
+
+	err = ldap.AuthenticateServiceAccountUser(config.ServiceAccountPassword, config.ServiceAccountUsername, config.DefaultLoginDomain, lConn)
+	if err != nil {
+		return false, err
+	}
+
+	dn, _, err := p.getDNAndScopeFromPrincipalID(principalID)
+	if err != nil {
+		return false, err
+	}
+
+	search := ldap.NewBaseObjectSearchRequest(
+		dn,
+		fmt.Sprintf("(%s=%s)", ObjectClass, ldap.SanitizeAttr(config.UserObjectClass)),
+		[]string{ObjectClass},
+	)
+	result, err := lConn.Search(search)
+	if err != nil {
+		if ldapv3.IsErrorWithCode(err, ldapv3.LDAPResultNoSuchObject) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return len(result.Entries) > 0, nil
+}
+
+// LookupGroup looks up the group principal in Active Directory with the service account.
+func (p *adProvider) LookupGroup(principalID string) (v3.Principal, error) {
+	config, caPool, err := p.getActiveDirectoryConfig()
+	if err != nil {
+		return v3.Principal{}, err
+	}
+
+	lConn, err := p.ldapConnection(config, caPool)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	defer lConn.Close()
+
+	// Bind first so that a failing service account isn't mistaken for the group, as getPrincipal falls back to the DN.
+	err = ldap.AuthenticateServiceAccountUser(config.ServiceAccountPassword, config.ServiceAccountUsername, config.DefaultLoginDomain, lConn)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+
+	dn, scope, err := p.getDNAndScopeFromPrincipalID(principalID)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	if scope != GroupScope {
+		return v3.Principal{}, fmt.Errorf("%s is not a group principal", principalID)
+	}
+
+	principal, err := p.getPrincipal(dn, scope, config, caPool)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	if principal == nil {
+		return v3.Principal{}, fmt.Errorf("group %s not found", dn)
+	}
+	return *principal, nil
+}
+
+// ListGroupMembers lists the users whose memberOf attribute holds the group on the Active Directory server with the service account.
+func (p *adProvider) ListGroupMembers(principalID string, limit int, continueToken string) ([]v3.Principal, string, error) {
+	config, caPool, err := p.getActiveDirectoryConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	lConn, err := p.ldapConnection(config, caPool)
+	if err != nil {
+		return nil, "", err
+	}
+	defer lConn.Close()
+
+	dn, scope, err := p.getDNAndScopeFromPrincipalID(principalID)
+	if err != nil {
+		return nil, "", err
+	}
+	if scope != GroupScope {
+		return nil, "", fmt.Errorf("%s is not a group principal", principalID)
+	}
+
+	query := fmt.Sprintf("(&(%s=%s)(%s=%s))",
+		ObjectClass,
+		ldap.SanitizeAttr(config.UserObjectClass),
+		MemberOfAttribute,
+		ldapv3.EscapeFilter(dn),
+	)
+	members, err := p.searchLdap(query, UserScope, config, lConn)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return common.PagePrincipals(members, limit, continueToken)
+}
+
+// SearchUsersByAttribute searches the users whose attribute holds the value on the Active Directory server with the
+// service account.
+func (p *adProvider) SearchUsersByAttribute(attribute, value string) ([]v3.Principal, error) {
+	if !ldap.IsValidAttr(attribute) {
+		return nil, fmt.Errorf("invalid attribute %q", attribute)
+	}
+
+	config, caPool, err := p.getActiveDirectoryConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.UserSearchFilter != "" {
+		if _, err := ldapv3.CompileFilter(config.UserSearchFilter); err != nil {
+			return nil, fmt.Errorf("invalid user search filter")
+		}
+	}
+
+	lConn, err := p.ldapConnection(config, caPool)
+	if err != nil {
+		return nil, err
+	}
+	defer lConn.Close()
+
+	query := fmt.Sprintf("(&(%s=%s)(%s=%s)%s)",
+		ObjectClass,
+		ldap.SanitizeAttr(config.UserObjectClass),
+		attribute,
+		ldapv3.EscapeFilter(value),
+		config.UserSearchFilter,
+	)
+	return p.searchLdap(query, UserScope, config, lConn)
+}
+
 func (p *adProvider) RefetchGroupPrincipals(principalID string, secret string) ([]v3.Principal, error) {
 	config, caPool, err := p.getActiveDirectoryConfig()
 	if err != nil {
@@ -125,7 +319,7 @@ func (p *adProvider) RefetchGroupPrincipals(principalID string, secret string) (
 	search := ldap.NewBaseObjectSearchRequest(
 		dn,
 		fmt.Sprintf("(%s=%s)", ObjectClass, ldap.SanitizeAttr(config.UserObjectClass)),
-		config.GetUserSearchAttributes(defaultUserAttributes...),
+		config.GetUserSearchAttributes(userSearchAttributes()...),
 	)
 
 	result, err := lConn.Search(search)
@@ -369,7 +563,7 @@ func (p *adProvider) getPrincipal(distinguishedName string, scope string, config
 
 	var attrs []string
 	if strings.EqualFold(UserScope, scope) {
-		attrs = config.GetUserSearchAttributes(defaultUserAttributes...)
+		attrs = config.GetUserSearchAttributes(userSearchAttributes()...)
 	} else {
 		attrs = config.GetGroupSearchAttributes(MemberOfAttribute, ObjectClass)
 	}
@@ -407,7 +601,7 @@ func (p *adProvider) getPrincipal(distinguishedName string, scope string, config
 	return principal, nil
 }
 
-func (p *adProvider) searchPrincipals(name, principalType string, config *v3.ActiveDirectoryConfig, lConn *ldapv3.Conn) ([]v3.Principal, error) {
+func (p *adProvider) searchPrincipals(name, principalType string, config *v3.ActiveDirectoryConfig, lConn ldapv3.Client) ([]v3.Principal, error) {
 	var principals []v3.Principal
 
 	if principalType == "" || principalType == "user" {
@@ -429,7 +623,7 @@ func (p *adProvider) searchPrincipals(name, principalType string, config *v3.Act
 	return principals, nil
 }
 
-func (p *adProvider) searchUser(name string, config *v3.ActiveDirectoryConfig, lConn *ldapv3.Conn) ([]v3.Principal, error) {
+func (p *adProvider) searchUser(name string, config *v3.ActiveDirectoryConfig, lConn ldapv3.Client) ([]v3.Principal, error) {
 	if config.UserSearchFilter != "" {
 		// Make sure user search filter contains a valid LDAP query expression
 		// before interpolating it into the search filter.
@@ -451,7 +645,7 @@ func (p *adProvider) searchUser(name string, config *v3.ActiveDirectoryConfig, l
 	return p.searchLdap(query, UserScope, config, lConn)
 }
 
-func (p *adProvider) searchGroup(name string, config *v3.ActiveDirectoryConfig, lConn *ldapv3.Conn) ([]v3.Principal, error) {
+func (p *adProvider) searchGroup(name string, config *v3.ActiveDirectoryConfig, lConn ldapv3.Client) ([]v3.Principal, error) {
 	if config.GroupSearchFilter != "" {
 		// Make sure group search filter contains a valid LDAP query expression
 		// before interpolating it into the search filter.
@@ -474,7 +668,7 @@ func (p *adProvider) searchGroup(name string, config *v3.ActiveDirectoryConfig,
 	return p.searchLdap(query, GroupScope, config, lConn)
 }
 
-func (p *adProvider) searchLdap(query string, scope string, config *v3.ActiveDirectoryConfig, lConn *ldapv3.Conn) ([]v3.Principal, error) {
+func (p *adProvider) searchLdap(query string, scope string, config *v3.ActiveDirectoryConfig, lConn ldapv3.Client) ([]v3.Principal, error) {
 	var principals []v3.Principal
 	var search *ldapv3.SearchRequest
 
@@ -483,7 +677,7 @@ func (p *adProvider) searchLdap(query string, scope string, config *v3.ActiveDir
 		search = ldap.NewWholeSubtreeSearchRequest(
 			searchDomain,
 			query,
-			config.GetUserSearchAttributes(defaultUserAttributes...),
+			config.GetUserSearchAttributes(userSearchAttributes()...),
 		)
 	} else {
 		if config.GroupSearchBase != "" {
@@ -524,14 +718,29 @@ func (p *adProvider) searchLdap(query string, scope string, config *v3.ActiveDir
 	return principals, nil
 }
 
-func (p *adProvider) ldapConnection(config *v3.ActiveDirectoryConfig, caPool *x509.CertPool) (*ldapv3.Conn, error) {
-	servers := config.Servers
+func (p *adProvider) ldapConnection(config *v3.ActiveDirectoryConfig, caPool *x509.CertPool) (ldapv3.Client, error) {
+	servers, err := domainControllers(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
 	TLS := config.TLS
 	port := config.Port
 	connectionTimeout := config.ConnectionTimeout
 	startTLS := config.StartTLS
+	if config.BindMethod == BindMethodNTLM {
+		return ldap.NewNTLMConn(servers, TLS, startTLS, port, connectionTimeout, caPool)
+	}
 	return ldap.NewLDAPConn(servers, TLS, startTLS, port, connectionTimeout, caPool)
 }
+
+// domainControllers returns the configured servers, or the domain controllers discovered in DNS if a discovery domain is set.
+func domainControllers(ctx context.Context, config *v3.ActiveDirectoryConfig) ([]string, error) {
+	if config.ServerDiscoveryDomain == "" {
+		return config.Servers, nil
+	}
+	return ldap.DiscoverServers(ctx, config.ServerDiscoveryDomain, config.ServerDiscoverySite)
+}
+
 func (p *adProvider) permissionCheck(attributes []*ldapv3.EntryAttribute, config *v3.ActiveDirectoryConfig) bool {
 	userObjectClass := config.UserObjectClass
 	userEnabledAttribute := config.UserEnabledAttribute
@@ -12,7 +12,9 @@
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth/accessor"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/providers/common/ldap"
 	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/tracing"
 	v3client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3public"
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
@@ -41,6 +43,8 @@
 	StatusMigrationFailed              = "Failed"
 	StatusLoginDisabled                = "login is disabled while migration is running"
 	StatusACMigrationRunning           = "migration-ad-guid-migration-status"
+	BindMethodSimple                   = "simple"
+	BindMethodNTLM                     = "ntlm"
 )
 
 var scopes = []string{UserScope, GroupScope}
@@ -110,13 +114,15 @@ func (p *adProvider) AuthenticateUser(ctx context.Context, input interface{}) (v
 		return v3.Principal{}, nil, "", httperror.WrapAPIError(err, httperror.ClusterUnavailable, StatusLoginDisabled)
 	}
 
+	_, span := tracing.StartSpan(ctx, "ldap.connect")
 	lConn, err := p.ldapConnection(config, caPool)
+	tracing.End(span, err)
 	if err != nil {
 		return v3.Principal{}, nil, "", err
 	}
 	defer lConn.Close()
 
-	principal, groupPrincipal, err := p.loginUser(lConn, login, config)
+	principal, groupPrincipal, err := p.loginUser(ldap.Traced(ctx, lConn), login, config)
 	if err != nil {
 		return v3.Principal{}, nil, "", err
 	}
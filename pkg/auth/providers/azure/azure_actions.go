@@ -95,6 +95,9 @@ func (ap *Provider) testAndApply(request *types.APIContext) error {
 		Code: azureADConfigApplyInput.Code,
 	}
 
+	if azureADConfig.ApplicationSecret == "" && azureADConfig.ApplicationCertificate == "" {
+		return httperror.NewAPIError(httperror.MissingRequired, "either an application secret or an application certificate is required")
+	}
 	if azureADConfig.ApplicationSecret != "" {
 		value, err := common.ReadFromSecret(ap.secrets, azureADConfig.ApplicationSecret,
 			strings.ToLower(client.AzureADConfigFieldApplicationSecret))
@@ -103,6 +106,14 @@ func (ap *Provider) testAndApply(request *types.APIContext) error {
 		}
 		azureADConfig.ApplicationSecret = value
 	}
+	if azureADConfig.ApplicationCertificate != "" {
+		value, err := common.ReadFromSecret(ap.secrets, azureADConfig.ApplicationCertificate,
+			strings.ToLower(client.AzureADConfigFieldApplicationCertificate))
+		if err != nil {
+			return err
+		}
+		azureADConfig.ApplicationCertificate = value
+	}
 	// Call provider
 	userPrincipal, groupPrincipals, providerToken, err := ap.loginUser(azureADConfig, azureLogin, true)
 	if err != nil {
@@ -4,9 +4,11 @@
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
@@ -126,6 +128,58 @@ func (ap *Provider) RefetchGroupPrincipals(principalID, secret string) ([]v3.Pri
 	return groupPrincipals, nil
 }
 
+// UserExists looks up the user principal in Microsoft Graph with the credentials of the application.
+// Users can't be looked up with the deprecated Azure AD Graph, which requires the token of a user.
+func (ap *Provider) UserExists(principalID string) (bool, error) {
+	cfg, err := ap.GetAzureConfigK8s()
+	if err != nil {
+		return false, err
+	}
+	if IsConfigDeprecated(cfg) {
+		return false, fmt.Errorf("looking up users isn't supported with the deprecated Azure AD Graph")
+	}
+	azureClient, err := clients.NewAzureClientFromSecret(cfg, false, "", ap.secrets)
+	if err != nil {
+		return false, err
+	}
+
+	parsed, err := clients.ParsePrincipalID(principalID)
+	if err != nil {
+		return false, err
+	}
+	users, err := azureClient.ListUsers(fmt.Sprintf("id eq '%s'", parsed["ID"]))
+	if err != nil {
+		return false, err
+	}
+
+	return len(users) > 0, nil
+}
+
+// LookupGroup looks up the group principal in Microsoft Graph with the credentials of the application.
+// Groups can't be looked up with the deprecated Azure AD Graph, which requires the token of a user.
+func (ap *Provider) LookupGroup(principalID string) (v3.Principal, error) {
+	cfg, err := ap.GetAzureConfigK8s()
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	if IsConfigDeprecated(cfg) {
+		return v3.Principal{}, fmt.Errorf("looking up groups isn't supported with the deprecated Azure AD Graph")
+	}
+	azureClient, err := clients.NewAzureClientFromSecret(cfg, false, "", ap.secrets)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+
+	parsed, err := clients.ParsePrincipalID(principalID)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	if parsed["type"] != "group" {
+		return v3.Principal{}, fmt.Errorf("%s is not a group principal", principalID)
+	}
+	return azureClient.GetGroup(parsed["ID"])
+}
+
 func (ap *Provider) SearchPrincipals(name, principalType string, token accessor.TokenAccessor) ([]v3.Principal, error) {
 	cfg, err := ap.GetAzureConfigK8s()
 	if err != nil {
@@ -373,6 +427,13 @@ func (ap *Provider) saveAzureConfigK8s(config *v32.AzureADConfig) error {
 
 	config.ApplicationSecret = name
 
+	field = strings.ToLower(client.AzureADConfigFieldApplicationCertificate)
+	name, err = common.CreateOrUpdateSecrets(ap.secrets, config.ApplicationCertificate, field, strings.ToLower(config.Type))
+	if err != nil {
+		return err
+	}
+	config.ApplicationCertificate = name
+
 	logrus.Debugf("updating AzureADConfig")
 	_, err = ap.authConfigs.ObjectClient().Update(config.ObjectMeta.Name, config)
 	if err != nil {
@@ -407,6 +468,14 @@ func (ap *Provider) GetAzureConfigK8s() (*v32.AzureADConfig, error) {
 		}
 		storedAzureADConfig.ApplicationSecret = value
 	}
+	if storedAzureADConfig.ApplicationCertificate != "" {
+		value, err := common.ReadFromSecret(ap.secrets, storedAzureADConfig.ApplicationCertificate,
+			strings.ToLower(client.AzureADConfigFieldApplicationCertificate))
+		if err != nil {
+			return nil, err
+		}
+		storedAzureADConfig.ApplicationCertificate = value
+	}
 
 	return storedAzureADConfig, nil
 }
@@ -551,3 +620,50 @@ func (ap *Provider) IsDisabledProvider() (bool, error) {
 	}
 	return !azureConfig.Enabled, nil
 }
+
+// Probe checks that the login and graph endpoints answer, and reports the expiry of the application certificate or secret.
+// The expiry of the secret isn't reported with the deprecated Azure AD Graph, or if the application can't read itself.
+func (ap *Provider) Probe(ctx context.Context) (*v32.AuthConfigConnectivity, error) {
+	cfg, err := ap.GetAzureConfigK8s()
+	if err != nil {
+		return nil, err
+	}
+
+	connectivity := &v32.AuthConfigConnectivity{
+		Endpoints: []v32.AuthConfigEndpointStatus{
+			common.ProbeURL(ctx, http.DefaultClient, "endpoint", cfg.Endpoint),
+			common.ProbeURL(ctx, http.DefaultClient, "graphEndpoint", cfg.GraphEndpoint),
+		},
+	}
+	if cfg.ApplicationCertificate != "" {
+		expiresAt, err := clients.ApplicationCertificateExpiry(cfg.ApplicationCertificate)
+		if err != nil {
+			connectivity.Error = err.Error()
+			return connectivity, nil
+		}
+		connectivity.Credentials = append(connectivity.Credentials, v32.AuthConfigCredentialStatus{
+			Name:      "applicationCertificate",
+			ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+		})
+		return connectivity, nil
+	}
+	if IsConfigDeprecated(cfg) {
+		return connectivity, nil
+	}
+
+	azureClient, err := clients.NewMSGraphClient(cfg, ap.secrets)
+	if err != nil {
+		connectivity.Error = err.Error()
+		return connectivity, nil
+	}
+	expiresAt, err := azureClient.ApplicationSecretExpiry(cfg.ApplicationID, cfg.ApplicationSecret)
+	if err != nil {
+		logrus.Debugf("[AZURE_PROVIDER] Unable to get the expiry of the application secret: %v", err)
+		return connectivity, nil
+	}
+	connectivity.Credentials = append(connectivity.Credentials, v32.AuthConfigCredentialStatus{
+		Name:      "applicationSecret",
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	})
+	return connectivity, nil
+}
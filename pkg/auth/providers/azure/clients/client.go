@@ -1,6 +1,8 @@
 package clients
 
 import (
+	"errors"
+
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
@@ -9,6 +11,9 @@
 // Name is the identifier for the Azure AD auth provider.
 const Name = "azuread"
 
+// errCertificateNotSupported is returned when a certificate is configured with the deprecated Azure AD Graph API.
+var errCertificateNotSupported = errors.New("application certificates are only supported with Microsoft Graph")
+
 // AzureClient specifies the subset of operations that a real client would delegate to some SDK for accessing
 // one of the two APIs to work with Active Directory resources - Azure AD Graph and Microsoft Graph.
 type AzureClient interface {
@@ -27,6 +32,9 @@ type AzureClient interface {
 // externally stored secrets with tokens, as is the case when a client is created to use an existing access token.
 func NewAzureClientFromCredential(config *v32.AzureADConfig, useDeprecatedAzureADClient bool, credential *v32.AzureADLogin, secrets wcorev1.SecretController) (AzureClient, error) {
 	if useDeprecatedAzureADClient {
+		if config.ApplicationCertificate != "" {
+			return nil, errCertificateNotSupported
+		}
 		return NewADGraphClientFromCredential(config, credential)
 	}
 	return NewMSGraphClient(config, secrets)
@@ -36,6 +44,9 @@ func NewAzureClientFromCredential(config *v32.AzureADConfig, useDeprecatedAzureA
 // The client would fetch the access token from either a refresh token or secret contents passed in.
 func NewAzureClientFromSecret(config *v32.AzureADConfig, useDeprecatedAzureADClient bool, secret string, secrets wcorev1.SecretController) (AzureClient, error) {
 	if useDeprecatedAzureADClient {
+		if config.ApplicationCertificate != "" {
+			return nil, errCertificateNotSupported
+		}
 		return NewAzureADGraphClientFromADALToken(config, secret)
 	}
 	return NewMSGraphClient(config, secrets)
@@ -5,6 +5,7 @@
 
 	lru "github.com/hashicorp/golang-lru"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
@@ -12,6 +13,9 @@
 // GroupCache is an in-memory cache of group principals.
 var GroupCache *lru.Cache
 
+// groupCacheName is the name of GroupCache, as counted by the metrics of the lookups.
+const groupCacheName = "azure-groups"
+
 // UserGroupsToPrincipals attempts to convert a value representing a collection of groups to a slice of principal values.
 // It also stores group values in an in-memory cache for faster subsequent access.
 func UserGroupsToPrincipals(azureClient AzureClient, groupNames []string) ([]v3.Principal, error) {
@@ -29,7 +33,9 @@ func UserGroupsToPrincipals(azureClient AzureClient, groupNames []string) ([]v3.
 		j := i
 		groupID := id
 
-		if principal, ok := GroupCache.Get(groupID); ok {
+		principal, ok := GroupCache.Get(groupID)
+		metrics.IncAuthCacheLookups(groupCacheName, ok)
+		if ok {
 			p, ok := principal.(v3.Principal)
 			if !ok {
 				logrus.Errorf("failed to convert a cached group to principal")
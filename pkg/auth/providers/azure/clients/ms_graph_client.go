@@ -6,6 +6,7 @@
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -17,6 +18,7 @@
 	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
 	msgraphsdkgo "github.com/microsoftgraph/msgraph-sdk-go"
 	msgraphcore "github.com/microsoftgraph/msgraph-sdk-go-core"
+	msgraphapplications "github.com/microsoftgraph/msgraph-sdk-go/applications"
 	msgraphgroups "github.com/microsoftgraph/msgraph-sdk-go/groups"
 	"github.com/microsoftgraph/msgraph-sdk-go/models"
 	"github.com/microsoftgraph/msgraph-sdk-go/models/odataerrors"
@@ -42,12 +44,12 @@
 // Graph client.
 
 // It first tries to fetch the token from the refresh token, if the access token is found in the database.
-// If that fails, it tries to acquire it directly from the auth provider with the credential (application secret in Azure).
+// If that fails, it tries to acquire it directly from the auth provider with the credential (application secret or certificate in Azure).
 // It also checks that the access token has the necessary permissions.
 func NewMSGraphClient(config *v32.AzureADConfig, secrets wcorev1.SecretController) (*AzureMSGraphClient, error) {
-	cred, err := confidential.NewCredFromSecret(config.ApplicationSecret)
+	cred, err := newCredential(config)
 	if err != nil {
-		return nil, fmt.Errorf("could not create a cred from a secret: %w", err)
+		return nil, err
 	}
 
 	authorityURL, err := url.JoinPath(config.Endpoint, config.TenantID)
@@ -389,9 +391,9 @@ func oidFromIDToken(token string, config *v32.AzureADConfig) (string, error) {
 
 // oidFromAuthCode exchanges the AuthCode for a IDToken, returning the user OID
 func oidFromAuthCode(token string, config *v32.AzureADConfig) (string, error) {
-	cred, err := confidential.NewCredFromSecret(config.ApplicationSecret)
+	cred, err := newCredential(config)
 	if err != nil {
-		return "", fmt.Errorf("could not create a cred from a secret: %w", err)
+		return "", err
 	}
 	authorityURL, err := url.JoinPath(config.Endpoint, config.TenantID)
 	if err != nil {
@@ -415,6 +417,63 @@ func oidFromAuthCode(token string, config *v32.AzureADConfig) (string, error) {
 	return authResult.IDToken.Oid, nil
 }
 
+// newCredential returns the credential of the application: its certificate if one is configured, its secret otherwise.
+// With a certificate, MSAL signs a new client assertion for every token request, so assertions never go stale.
+// The certificate is read again whenever a client is created, which picks up rotations of the secret holding it.
+func newCredential(config *v32.AzureADConfig) (confidential.Credential, error) {
+	if config.ApplicationCertificate == "" {
+		cred, err := confidential.NewCredFromSecret(config.ApplicationSecret)
+		if err != nil {
+			return confidential.Credential{}, fmt.Errorf("could not create a cred from a secret: %w", err)
+		}
+		return cred, nil
+	}
+
+	certs, key, err := confidential.CertFromPEM([]byte(config.ApplicationCertificate), "")
+	if err != nil {
+		return confidential.Credential{}, fmt.Errorf("could not parse the application certificate: %w", err)
+	}
+	cred, err := confidential.NewCredFromCert(certs, key)
+	if err != nil {
+		return confidential.Credential{}, fmt.Errorf("could not create a cred from a certificate: %w", err)
+	}
+	return cred, nil
+}
+
+// ApplicationCertificateExpiry returns when the certificate in a PEM bundle expires.
+func ApplicationCertificateExpiry(certificate string) (time.Time, error) {
+	certs, _, err := confidential.CertFromPEM([]byte(certificate), "")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse the application certificate: %w", err)
+	}
+	return certs[0].NotAfter, nil
+}
+
+// ApplicationSecretExpiry returns when the client secret of the application expires. The secret is told apart from the
+// other secrets of the application by its hint, which are its first characters. It requires the Application.Read.All permission.
+func (c AzureMSGraphClient) ApplicationSecretExpiry(applicationID, secret string) (time.Time, error) {
+	logrus.Debugf("[%s] ApplicationSecretExpiry %s", providerLogPrefix, applicationID)
+	filter := fmt.Sprintf("appId eq '%s'", applicationID)
+	result, err := c.GraphClient.Applications().Get(context.Background(), &msgraphapplications.ApplicationsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &msgraphapplications.ApplicationsRequestBuilderGetQueryParameters{
+			Filter: &filter,
+			Select: []string{"passwordCredentials"},
+		}})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("getting application: %w", getMSGraphErrorData(err))
+	}
+
+	for _, application := range result.GetValue() {
+		for _, credential := range application.GetPasswordCredentials() {
+			hint, endDateTime := credential.GetHint(), credential.GetEndDateTime()
+			if hint != nil && endDateTime != nil && *hint != "" && strings.HasPrefix(secret, *hint) {
+				return *endDateTime, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("no secret of application %s matches the configured secret", applicationID)
+}
+
 func getMSGraphErrorData(err error) error {
 	if odataErr, ok := err.(*odataerrors.ODataError); ok {
 		if oErr := odataErr.GetErrorEscaped(); oErr != nil {
@@ -1,14 +1,22 @@
 package clients
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	apismgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	wranglerfake "github.com/rancher/wrangler/v3/pkg/generic/fake"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -465,3 +473,44 @@ func newTestSecretsClient(ctrl *gomock.Controller) *wranglerfake.MockControllerI
 
 	return secretController
 }
+
+func TestNewCredential(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	certificate := newTestCertificate(t, notAfter)
+
+	_, err := newCredential(&apismgmtv3.AzureADConfig{ApplicationSecret: "secret"})
+	assert.NoError(t, err)
+
+	_, err = newCredential(&apismgmtv3.AzureADConfig{ApplicationCertificate: certificate})
+	assert.NoError(t, err)
+
+	_, err = newCredential(&apismgmtv3.AzureADConfig{ApplicationCertificate: "not a certificate"})
+	assert.ErrorContains(t, err, "could not parse the application certificate")
+
+	expiresAt, err := ApplicationCertificateExpiry(certificate)
+	require.NoError(t, err)
+	assert.Equal(t, notAfter, expiresAt.UTC())
+}
+
+func TestNewAzureClientFromSecretWithCertificateAndDeprecatedClient(t *testing.T) {
+	_, err := NewAzureClientFromSecret(&apismgmtv3.AzureADConfig{ApplicationCertificate: "certificate"}, true, "", nil)
+	assert.ErrorIs(t, err, errCertificateNotSupported)
+}
+
+// newTestCertificate returns a PEM bundle with a self-signed certificate and its private key.
+func newTestCertificate(t *testing.T, notAfter time.Time) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rancher"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(certPEM) + string(keyPEM)
+}
@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/sessions"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+)
+
+// BackchannelLogoutPath is the endpoint the OPs of the OIDC providers post logout tokens to when users log out of them,
+// following the OpenID Connect Back-Channel Logout specification. It's the backchannel_logout_uri of the Rancher client.
+const BackchannelLogoutPath = "/v1-oidc/{provider}/backchannel-logout"
+
+// logoutTokenVerifier is implemented by the providers supporting back-channel logout.
+type logoutTokenVerifier interface {
+	LogoutTokenPrincipal(ctx context.Context, rawToken string) (string, error)
+}
+
+type userByPrincipal interface {
+	GetUserByPrincipalID(principalName string) (*v3.User, error)
+}
+
+type userLogouts interface {
+	LogoutEverywhere(userID, reason string) (int, error)
+}
+
+type backchannelLogoutHandler struct {
+	providers func(providerName string) (common.AuthProvider, error)
+	users     userByPrincipal
+	logouts   userLogouts
+}
+
+// NewBackchannelLogoutHandler returns the handler of the back-channel logout endpoint, which logs the users the OP
+// logged out of all their Rancher sessions. It must be served without authentication, the OP authenticates by
+// signing the logout tokens.
+func NewBackchannelLogoutHandler(ctx context.Context, mgmt *config.ScaledContext) http.Handler {
+	h := &backchannelLogoutHandler{
+		providers: GetProvider,
+		users:     mgmt.UserManager,
+		logouts:   sessions.NewRevoker(ctx, mgmt),
+	}
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(BackchannelLogoutPath).HandlerFunc(h.logout)
+	return root
+}
+
+func (h *backchannelLogoutHandler) logout(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	providerName := mux.Vars(r)["provider"]
+	provider, err := h.providers(providerName)
+	if err != nil {
+		writeBackchannelError(w, http.StatusNotFound, "provider not found")
+		return
+	}
+	verifier, ok := provider.(logoutTokenVerifier)
+	if !ok {
+		writeBackchannelError(w, http.StatusNotFound, "provider doesn't support back-channel logout")
+		return
+	}
+
+	principalID, err := verifier.LogoutTokenPrincipal(r.Context(), r.PostFormValue("logout_token"))
+	if err != nil {
+		logrus.Debugf("[backchannel-logout] invalid logout token for provider %s: %v", providerName, err)
+		writeBackchannelError(w, http.StatusBadRequest, "invalid logout token")
+		return
+	}
+
+	user, err := h.users.GetUserByPrincipalID(principalID)
+	if err != nil {
+		logrus.Errorf("[backchannel-logout] failed to get the user of principal %s: %v", principalID, err)
+		writeBackchannelError(w, http.StatusInternalServerError, "failed to log out")
+		return
+	}
+	// The user never logged in to Rancher, there's nothing to log out of.
+	if user == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if _, err := h.logouts.LogoutEverywhere(user.Name, "back-channel logout of "+providerName); err != nil {
+		logrus.Errorf("[backchannel-logout] failed to log user %s out: %v", user.Name, err)
+		writeBackchannelError(w, http.StatusInternalServerError, "failed to log out")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeBackchannelError writes an error in the format of the OAuth 2.0 errors, as required by the specification.
+func writeBackchannelError(w http.ResponseWriter, status int, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"error":             "invalid_request",
+		"error_description": description,
+	}); err != nil {
+		logrus.Errorf("[backchannel-logout] failed to write response: %v", err)
+	}
+}
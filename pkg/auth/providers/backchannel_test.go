@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeOIDCProvider struct {
+	common.AuthProvider
+}
+
+func (f *fakeOIDCProvider) LogoutTokenPrincipal(_ context.Context, rawToken string) (string, error) {
+	if !strings.HasPrefix(rawToken, "valid:") {
+		return "", fmt.Errorf("invalid signature")
+	}
+	return "keycloakoidc_user://" + strings.TrimPrefix(rawToken, "valid:"), nil
+}
+
+type fakeUsersByPrincipal map[string]*v3.User
+
+func (f fakeUsersByPrincipal) GetUserByPrincipalID(principalName string) (*v3.User, error) {
+	return f[principalName], nil
+}
+
+type fakeLogouts struct {
+	users   []string
+	reasons []string
+}
+
+func (f *fakeLogouts) LogoutEverywhere(userID, reason string) (int, error) {
+	f.users = append(f.users, userID)
+	f.reasons = append(f.reasons, reason)
+	return 2, nil
+}
+
+func TestBackchannelLogout(t *testing.T) {
+	logouts := &fakeLogouts{}
+	h := &backchannelLogoutHandler{
+		providers: func(providerName string) (common.AuthProvider, error) {
+			switch providerName {
+			case "keycloakoidc":
+				return &fakeOIDCProvider{}, nil
+			case "github":
+				return fakeProvider{}, nil
+			}
+			return nil, fmt.Errorf("No such provider '%s'", providerName)
+		},
+		users: fakeUsersByPrincipal{
+			"keycloakoidc_user://jdoe": {ObjectMeta: metav1.ObjectMeta{Name: "u-jdoe"}},
+		},
+		logouts: logouts,
+	}
+	root := mux.NewRouter()
+	root.Methods(http.MethodPost).Path(BackchannelLogoutPath).HandlerFunc(h.logout)
+
+	post := func(provider, logoutToken string) *httptest.ResponseRecorder {
+		form := url.Values{"logout_token": {logoutToken}}
+		req := httptest.NewRequest(http.MethodPost, "/v1-oidc/"+provider+"/backchannel-logout", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		root.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post("keycloakoidc", "valid:jdoe")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, []string{"u-jdoe"}, logouts.users)
+	assert.Equal(t, []string{"back-channel logout of keycloakoidc"}, logouts.reasons)
+
+	// Users who never logged in to Rancher have nothing to log out of.
+	rec = post("keycloakoidc", "valid:unknown")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, logouts.users, 1)
+
+	rec = post("keycloakoidc", "forged")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error": "invalid_request", "error_description": "invalid logout token"}`, rec.Body.String())
+
+	rec = post("github", "valid:jdoe")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = post("unknown", "valid:jdoe")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Len(t, logouts.users, 1)
+}
@@ -0,0 +1,109 @@
+package cas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/retry"
+)
+
+func (p *casProvider) formatter(apiContext *types.APIContext, resource *types.RawResource) {
+	common.AddCommonActions(apiContext, resource)
+	resource.AddAction(apiContext, "configureTest")
+	resource.AddAction(apiContext, "testAndApply")
+}
+
+func (p *casProvider) actionHandler(actionName string, action *types.Action, request *types.APIContext) error {
+	handled, err := common.HandleCommonAction(actionName, action, request, Name, p.authConfigs)
+	if err != nil {
+		return err
+	}
+	if handled {
+		return nil
+	}
+
+	if actionName == "configureTest" {
+		return p.configureTest(request)
+	} else if actionName == "testAndApply" {
+		return p.testAndApply(request)
+	}
+
+	return httperror.NewAPIError(httperror.ActionNotAvailable, "")
+}
+
+func (p *casProvider) configureTest(request *types.APIContext) error {
+	casConfig := &v32.CASConfig{}
+	if err := json.NewDecoder(request.Request.Body).Decode(casConfig); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent,
+			fmt.Sprintf("Failed to parse body: %v", err))
+	}
+	if _, err := newCASClient(casConfig); err != nil {
+		return httperror.WrapAPIError(err, httperror.InvalidBodyContent, err.Error())
+	}
+
+	data := map[string]interface{}{
+		"redirectUrl": loginURL(casConfig.ServerURL),
+		"type":        "casConfigTestOutput",
+	}
+
+	request.WriteResponse(http.StatusOK, data)
+	return nil
+}
+
+// testAndApply validates the service ticket obtained with the configuration before saving it.
+func (p *casProvider) testAndApply(request *types.APIContext) error {
+	casConfigApplyInput := &v32.CASConfigApplyInput{}
+	if err := json.NewDecoder(request.Request.Body).Decode(casConfigApplyInput); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent,
+			fmt.Sprintf("Failed to parse body: %v", err))
+	}
+
+	casConfig := &casConfigApplyInput.CASConfig
+	userPrincipal, groupPrincipals, err := p.loginUser(casConfig, casConfigApplyInput.Ticket, casConfigApplyInput.Service)
+	if err != nil {
+		return err
+	}
+
+	// If this works, save the config adding the enabled flag.
+	user, err := p.userMGR.SetPrincipalOnCurrentUser(request, userPrincipal)
+	if err != nil {
+		return err
+	}
+
+	casConfig.Enabled = casConfigApplyInput.Enabled
+	if err := p.saveCASConfig(casConfig); err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Failed to save %s config: %v", Name, err))
+	}
+
+	userExtraInfo := p.GetUserExtraAttributes(userPrincipal)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return p.tokenMGR.UserAttributeCreateOrUpdate(user.Name, userPrincipal.Provider, groupPrincipals, userExtraInfo)
+	}); err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Failed to create or update userAttribute: %v", err))
+	}
+
+	return p.tokenMGR.CreateTokenAndSetCookie(user.Name, userPrincipal, groupPrincipals, "", 0, "Token via CAS Configuration", request)
+}
+
+func (p *casProvider) saveCASConfig(config *v32.CASConfig) error {
+	storedConfig, err := p.getCASConfig()
+	if err != nil {
+		return err
+	}
+	config.APIVersion = "management.cattle.io/v3"
+	config.Kind = v3.AuthConfigGroupVersionKind.Kind
+	config.Type = client.CASConfigType
+	config.ObjectMeta = storedConfig.ObjectMeta
+
+	logrus.Debugf("updating %s config", Name)
+	_, err = p.authConfigs.ObjectClient().Update(config.ObjectMeta.Name, config)
+	return err
+}
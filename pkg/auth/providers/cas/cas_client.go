@@ -0,0 +1,154 @@
+package cas
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	maxBodySize    = 1 << 20
+)
+
+// errInvalidTicket is returned when the CAS server rejects the service ticket.
+var errInvalidTicket = errors.New("invalid service ticket")
+
+// serviceResponse is the body of a CAS 3.0 service ticket validation response.
+type serviceResponse struct {
+	XMLName xml.Name               `xml:"http://www.yale.edu/tp/cas serviceResponse"`
+	Success *authenticationSuccess `xml:"http://www.yale.edu/tp/cas authenticationSuccess"`
+	Failure *authenticationFailure `xml:"http://www.yale.edu/tp/cas authenticationFailure"`
+}
+
+type authenticationSuccess struct {
+	User       string     `xml:"http://www.yale.edu/tp/cas user"`
+	Attributes attributes `xml:"http://www.yale.edu/tp/cas attributes"`
+}
+
+type authenticationFailure struct {
+	Code    string `xml:"code,attr"`
+	Message string `xml:",chardata"`
+}
+
+// attributes are the attributes released by the CAS server, by their local name.
+// Multi-valued attributes are released as repeated elements.
+type attributes struct {
+	Values []attribute `xml:",any"`
+}
+
+type attribute struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// validation is the outcome of a successful service ticket validation.
+type validation struct {
+	User       string
+	Attributes map[string][]string
+}
+
+// casClient validates service tickets with the CAS server.
+type casClient struct {
+	httpClient *http.Client
+	serverURL  string
+}
+
+func newCASClient(config *v32.CASConfig) (*casClient, error) {
+	serverURL, err := url.Parse(config.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid serverUrl: %w", err)
+	}
+	if serverURL.Scheme != "https" || serverURL.Host == "" {
+		return nil, fmt.Errorf("invalid serverUrl %q: must be an https URL", config.ServerURL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.Certificate != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(config.Certificate)) {
+			return nil, errors.New("invalid certificate: no PEM encoded certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &casClient{
+		httpClient: &http.Client{Transport: transport, Timeout: defaultTimeout},
+		serverURL:  strings.TrimSuffix(config.ServerURL, "/"),
+	}, nil
+}
+
+// loginURL returns the URL of the login page of the CAS server.
+// Clients add the service parameter the server redirects back to with the ticket.
+func loginURL(serverURL string) string {
+	return strings.TrimSuffix(serverURL, "/") + "/login"
+}
+
+// validate validates the service ticket for the service with the CAS 3.0 serviceValidate endpoint.
+func (c *casClient) validate(ticket, service string) (*validation, error) {
+	query := url.Values{}
+	query.Set("service", service)
+	query.Set("ticket", ticket)
+
+	req, err := http.NewRequest(http.MethodGet, c.serverURL+"/p3/serviceValidate?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/xml")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("service ticket validation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("reading service ticket validation response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("service ticket validation failed with status %d", resp.StatusCode)
+	}
+
+	return parseServiceResponse(body)
+}
+
+func parseServiceResponse(body []byte) (*validation, error) {
+	var response serviceResponse
+	if err := xml.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid service ticket validation response: %w", err)
+	}
+	if response.Failure != nil {
+		return nil, fmt.Errorf("%w: %s %s", errInvalidTicket, response.Failure.Code, strings.TrimSpace(response.Failure.Message))
+	}
+	if response.Success == nil {
+		return nil, errors.New("invalid service ticket validation response: no authentication result")
+	}
+
+	user := strings.TrimSpace(response.Success.User)
+	if user == "" {
+		return nil, errors.New("invalid service ticket validation response: no user")
+	}
+
+	result := &validation{User: user, Attributes: map[string][]string{}}
+	for _, attribute := range response.Success.Attributes.Values {
+		name := attribute.XMLName.Local
+		result.Attributes[name] = append(result.Attributes[name], strings.TrimSpace(attribute.Value))
+	}
+	return result, nil
+}
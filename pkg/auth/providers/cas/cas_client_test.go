@@ -0,0 +1,106 @@
+package cas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServiceResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		wantUser       string
+		wantAttributes map[string][]string
+		wantErr        error
+		wantErrString  string
+	}{
+		{
+			name: "success with attributes",
+			body: `<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">
+  <cas:authenticationSuccess>
+    <cas:user>jdoe</cas:user>
+    <cas:attributes>
+      <cas:displayName>John Doe</cas:displayName>
+      <cas:memberOf>faculty</cas:memberOf>
+      <cas:memberOf>staff</cas:memberOf>
+    </cas:attributes>
+  </cas:authenticationSuccess>
+</cas:serviceResponse>`,
+			wantUser: "jdoe",
+			wantAttributes: map[string][]string{
+				"displayName": {"John Doe"},
+				"memberOf":    {"faculty", "staff"},
+			},
+		},
+		{
+			name: "success without attributes",
+			body: `<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">
+  <cas:authenticationSuccess><cas:user>jdoe</cas:user></cas:authenticationSuccess>
+</cas:serviceResponse>`,
+			wantUser:       "jdoe",
+			wantAttributes: map[string][]string{},
+		},
+		{
+			name: "invalid ticket",
+			body: `<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">
+  <cas:authenticationFailure code="INVALID_TICKET">Ticket ST-1856339 not recognized</cas:authenticationFailure>
+</cas:serviceResponse>`,
+			wantErr: errInvalidTicket,
+		},
+		{
+			name:          "no user",
+			body:          `<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas"><cas:authenticationSuccess/></cas:serviceResponse>`,
+			wantErrString: "no user",
+		},
+		{
+			name:          "not a service response",
+			body:          `<html><body>Login</body></html>`,
+			wantErrString: "invalid service ticket validation response",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseServiceResponse([]byte(tt.body))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if tt.wantErrString != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrString)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, result.User)
+			assert.Equal(t, tt.wantAttributes, result.Attributes)
+		})
+	}
+}
+
+func TestCheckService(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   string
+		serverURL string
+		wantErr   bool
+	}{
+		{name: "service of this Rancher", service: "https://rancher.example.com/verify-auth?provider=cas", serverURL: "https://rancher.example.com"},
+		{name: "host is case insensitive", service: "https://Rancher.Example.com/verify-auth", serverURL: "https://rancher.example.com"},
+		{name: "other host", service: "https://evil.example.com/verify-auth", serverURL: "https://rancher.example.com", wantErr: true},
+		{name: "other scheme", service: "http://rancher.example.com/verify-auth", serverURL: "https://rancher.example.com", wantErr: true},
+		{name: "no service", serverURL: "https://rancher.example.com", wantErr: true},
+		{name: "no server-url", service: "https://rancher.example.com/verify-auth", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkService(tt.service, tt.serverURL)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
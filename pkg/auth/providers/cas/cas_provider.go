@@ -0,0 +1,334 @@
+package cas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	publicclient "github.com/rancher/rancher/pkg/client/generated/management/v3public"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// Name is the name of the CAS provider.
+	Name = "cas"
+
+	userType  = common.UserPrincipalType
+	groupType = common.GroupPrincipalType
+)
+
+type userManager interface {
+	SetPrincipalOnCurrentUser(apiContext *types.APIContext, principal v32.Principal) (*v32.User, error)
+	CheckAccess(accessMode string, allowedPrincipalIDs []string, userPrincipalID string, groups []v32.Principal) (bool, error)
+}
+
+type tokenManager interface {
+	UserAttributeCreateOrUpdate(userID, provider string, groupPrincipals []v32.Principal, userExtraInfo map[string][]string, loginTime ...time.Time) error
+	CreateTokenAndSetCookie(userID string, userPrincipal v32.Principal, groupPrincipals []v32.Principal, providerToken string, ttl int, description string, request *types.APIContext) error
+}
+
+type casProvider struct {
+	ctx            context.Context
+	authConfigs    v3.AuthConfigInterface
+	authConfigsRaw objectclient.GenericClient
+	userMGR        userManager
+	tokenMGR       tokenManager
+	serverURL      func() string
+}
+
+func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, userMGR userManager, tokenMGR tokenManager) common.AuthProvider {
+	authConfigs := mgmtCtx.Management.AuthConfigs("")
+	return &casProvider{
+		ctx:            ctx,
+		authConfigs:    authConfigs,
+		authConfigsRaw: authConfigs.ObjectClient().UnstructuredClient(),
+		userMGR:        userMGR,
+		tokenMGR:       tokenMGR,
+		serverURL:      settings.ServerURL.Get,
+	}
+}
+
+func (p *casProvider) GetName() string {
+	return Name
+}
+
+func (p *casProvider) LogoutAll(apiContext *types.APIContext, token accessor.TokenAccessor) error {
+	return nil
+}
+
+func (p *casProvider) Logout(apiContext *types.APIContext, token accessor.TokenAccessor) error {
+	return nil
+}
+
+func (p *casProvider) CustomizeSchema(schema *types.Schema) {
+	schema.ActionHandler = p.actionHandler
+	schema.Formatter = p.formatter
+}
+
+// TransformToAuthProvider adds the URL of the CAS login page, which clients redirect users to with the service
+// parameter set to the page receiving the ticket.
+func (p *casProvider) TransformToAuthProvider(authConfig map[string]interface{}) (map[string]interface{}, error) {
+	provider := common.TransformToAuthProvider(authConfig)
+	serverURL, _ := authConfig[client.CASConfigFieldServerURL].(string)
+	provider[publicclient.CASProviderFieldRedirectURL] = loginURL(serverURL)
+	return provider, nil
+}
+
+// AuthenticateUser validates the service ticket of the login request and returns the user principal and the group
+// principals mapped from the released attributes.
+func (p *casProvider) AuthenticateUser(ctx context.Context, input interface{}) (v32.Principal, []v32.Principal, string, error) {
+	login, ok := input.(*v32.CASLogin)
+	if !ok {
+		return v32.Principal{}, nil, "", errors.New("unexpected input type")
+	}
+
+	config, err := p.getCASConfig()
+	if err != nil {
+		return v32.Principal{}, nil, "", errors.New("can't find authprovider")
+	}
+
+	userPrincipal, groupPrincipals, err := p.loginUser(config, login.Ticket, login.Service)
+	if err != nil {
+		return v32.Principal{}, nil, "", err
+	}
+
+	return userPrincipal, groupPrincipals, "", nil
+}
+
+func (p *casProvider) loginUser(config *v32.CASConfig, ticket, service string) (v32.Principal, []v32.Principal, error) {
+	if ticket == "" {
+		return v32.Principal{}, nil, httperror.NewAPIError(httperror.MissingRequired, "ticket is required")
+	}
+	if err := checkService(service, p.serverURL()); err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.InvalidBodyContent, err.Error())
+	}
+
+	casClient, err := newCASClient(config)
+	if err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.ServerError, fmt.Sprintf("invalid %s config: %v", Name, err))
+	}
+	result, err := casClient.validate(ticket, service)
+	if err != nil {
+		if errors.Is(err, errInvalidTicket) {
+			logrus.Debugf("[%s] rejecting service ticket: %v", Name, err)
+			return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, "invalid service ticket")
+		}
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.ServerError, "unable to validate the service ticket")
+	}
+
+	userPrincipal, groupPrincipals, err := toUserAndGroupPrincipals(config, result)
+	if err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, err.Error())
+	}
+
+	allowed, err := p.userMGR.CheckAccess(config.AccessMode, config.AllowedPrincipalIDs, userPrincipal.Name, groupPrincipals)
+	if err != nil {
+		return v32.Principal{}, nil, err
+	}
+	if !allowed {
+		return v32.Principal{}, nil, httperror.NewAPIError(httperror.PermissionDenied, "Permission denied")
+	}
+
+	return userPrincipal, groupPrincipals, nil
+}
+
+// SearchPrincipals returns a principal of the requested type with the searchKey as its ID, since CAS doesn't
+// support looking up users. If the principalType is empty, both a user and a group principal are returned.
+func (p *casProvider) SearchPrincipals(searchKey, principalType string, token accessor.TokenAccessor) ([]v32.Principal, error) {
+	var principals []v32.Principal
+	if principalType != groupType {
+		principal := toPrincipal(userType, searchKey, searchKey)
+		p.markPrincipal(&principal, token)
+		principals = append(principals, principal)
+	}
+	if principalType != userType {
+		principal := toPrincipal(groupType, searchKey, searchKey)
+		p.markPrincipal(&principal, token)
+		principals = append(principals, principal)
+	}
+	return principals, nil
+}
+
+func (p *casProvider) GetPrincipal(principalID string, token accessor.TokenAccessor) (v32.Principal, error) {
+	principalType, id, err := parsePrincipalID(principalID)
+	if err != nil {
+		return v32.Principal{}, err
+	}
+
+	if token != nil && token.GetUserPrincipal().Name == principalID {
+		principal := token.GetUserPrincipal()
+		principal.Me = true
+		return principal, nil
+	}
+
+	principal := toPrincipal(principalType, id, id)
+	p.markPrincipal(&principal, token)
+	return principal, nil
+}
+
+// RefetchGroupPrincipals isn't supported, the groups of a user are only released when it logs in.
+func (p *casProvider) RefetchGroupPrincipals(principalID string, secret string) ([]v32.Principal, error) {
+	return nil, errors.New("Not implemented")
+}
+
+func (p *casProvider) CanAccessWithGroupProviders(userPrincipalID string, groupPrincipals []v32.Principal) (bool, error) {
+	config, err := p.getCASConfig()
+	if err != nil {
+		logrus.Errorf("Error fetching CAS config: %v", err)
+		return false, err
+	}
+	return p.userMGR.CheckAccess(config.AccessMode, config.AllowedPrincipalIDs, userPrincipalID, groupPrincipals)
+}
+
+func (p *casProvider) GetUserExtraAttributes(userPrincipal v32.Principal) map[string][]string {
+	return common.GetCommonUserExtraAttributes(userPrincipal)
+}
+
+// IsDisabledProvider checks if the CAS provider is currently disabled in Rancher.
+func (p *casProvider) IsDisabledProvider() (bool, error) {
+	config, err := p.getCASConfig()
+	if err != nil {
+		return false, err
+	}
+	return !config.Enabled, nil
+}
+
+func (p *casProvider) markPrincipal(principal *v32.Principal, token accessor.TokenAccessor) {
+	if token == nil {
+		return
+	}
+	switch principal.PrincipalType {
+	case userType:
+		principal.Me = common.SamePrincipal(token.GetUserPrincipal(), *principal)
+	case groupType:
+		for _, group := range token.GetGroupPrincipals() {
+			if group.Name == principal.Name {
+				principal.MemberOf = true
+				break
+			}
+		}
+	}
+}
+
+func (p *casProvider) getCASConfig() (*v32.CASConfig, error) {
+	authConfigObj, err := p.authConfigsRaw.Get(Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s config: %w", Name, err)
+	}
+
+	u, ok := authConfigObj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve %s config, cannot read k8s Unstructured data", Name)
+	}
+
+	storedConfig := &v32.CASConfig{}
+	if err := common.Decode(u.UnstructuredContent(), storedConfig); err != nil {
+		return nil, fmt.Errorf("unable to decode %s config: %w", Name, err)
+	}
+
+	return storedConfig, nil
+}
+
+// checkService makes sure the ticket was issued for a service of this Rancher. A ticket issued for another service
+// validates just as well if it's presented with that service's URL.
+func checkService(service, serverURL string) error {
+	if service == "" {
+		return errors.New("service is required")
+	}
+	serviceURL, err := url.Parse(service)
+	if err != nil {
+		return fmt.Errorf("invalid service: %w", err)
+	}
+	rancherURL, err := url.Parse(serverURL)
+	if err != nil || rancherURL.Host == "" {
+		return errors.New("server-url setting must be set to log in with CAS")
+	}
+	if serviceURL.Scheme != rancherURL.Scheme || !strings.EqualFold(serviceURL.Host, rancherURL.Host) {
+		return fmt.Errorf("service %s isn't a URL of this Rancher", service)
+	}
+	return nil
+}
+
+// toUserAndGroupPrincipals maps the user and the attributes released by the CAS server to the user principal and
+// its group principals.
+func toUserAndGroupPrincipals(config *v32.CASConfig, result *validation) (v32.Principal, []v32.Principal, error) {
+	name := result.User
+	if config.UserNameAttribute != "" {
+		name = firstValue(result.Attributes, config.UserNameAttribute)
+		if name == "" {
+			return v32.Principal{}, nil, fmt.Errorf("attribute %s wasn't released for user %s", config.UserNameAttribute, result.User)
+		}
+	}
+
+	displayName := name
+	if config.DisplayNameAttribute != "" {
+		if value := firstValue(result.Attributes, config.DisplayNameAttribute); value != "" {
+			displayName = value
+		}
+	}
+	userPrincipal := toPrincipal(userType, name, displayName)
+	userPrincipal.Me = true
+
+	var groupPrincipals []v32.Principal
+	if config.GroupsAttribute != "" {
+		seen := map[string]bool{}
+		for _, group := range result.Attributes[config.GroupsAttribute] {
+			if group == "" || seen[group] {
+				continue
+			}
+			seen[group] = true
+			groupPrincipal := toPrincipal(groupType, group, group)
+			groupPrincipal.MemberOf = true
+			groupPrincipals = append(groupPrincipals, groupPrincipal)
+		}
+	}
+
+	return userPrincipal, groupPrincipals, nil
+}
+
+func firstValue(attributes map[string][]string, name string) string {
+	for _, value := range attributes[name] {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func toPrincipal(principalType, id, displayName string) v32.Principal {
+	return v32.Principal{
+		ObjectMeta:    metav1.ObjectMeta{Name: Name + "_" + principalType + "://" + id},
+		DisplayName:   displayName,
+		LoginName:     id,
+		PrincipalType: principalType,
+		Provider:      Name,
+	}
+}
+
+// parsePrincipalID splits a principal ID like cas_user://jdoe into its type and the ID released by the CAS server.
+func parsePrincipalID(principalID string) (string, string, error) {
+	scope, id, ok := strings.Cut(principalID, "://")
+	if !ok || id == "" {
+		return "", "", fmt.Errorf("invalid id %s", principalID)
+	}
+	principalType, ok := strings.CutPrefix(scope, Name+"_")
+	if !ok || (principalType != userType && principalType != groupType) {
+		return "", "", fmt.Errorf("invalid id %s", principalID)
+	}
+	return principalType, id, nil
+}
@@ -0,0 +1,259 @@
+package cas
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/norman/types"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const testService = "https://rancher.example.com/verify-auth?provider=cas"
+
+// newTestCASServer returns a CAS server accepting ST-valid tickets issued for testService.
+func newTestCASServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cas/p3/serviceValidate" {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("ticket") != "ST-valid" || query.Get("service") != testService {
+			fmt.Fprintf(w, `<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">`+
+				`<cas:authenticationFailure code="INVALID_TICKET">Ticket %s not recognized</cas:authenticationFailure>`+
+				`</cas:serviceResponse>`, query.Get("ticket"))
+			return
+		}
+		fmt.Fprint(w, `<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas"><cas:authenticationSuccess>`+
+			`<cas:user>jdoe</cas:user>`+
+			`<cas:attributes><cas:mail>jdoe@example.edu</cas:mail><cas:cn>John Doe</cas:cn>`+
+			`<cas:memberOf>faculty</cas:memberOf><cas:memberOf>staff</cas:memberOf><cas:memberOf>faculty</cas:memberOf></cas:attributes>`+
+			`</cas:authenticationSuccess></cas:serviceResponse>`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAuthenticateUser(t *testing.T) {
+	server := newTestCASServer(t)
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := []struct {
+		name              string
+		login             *v3.CASLogin
+		userNameAttribute string
+		allowed           bool
+		wantUser          string
+		wantErrCode       *httperror.ErrorCode
+	}{
+		{
+			name:     "valid ticket",
+			login:    &v3.CASLogin{Ticket: "ST-valid", Service: testService},
+			allowed:  true,
+			wantUser: "cas_user://jdoe",
+		},
+		{
+			name:              "user name attribute",
+			login:             &v3.CASLogin{Ticket: "ST-valid", Service: testService},
+			userNameAttribute: "mail",
+			allowed:           true,
+			wantUser:          "cas_user://jdoe@example.edu",
+		},
+		{
+			name:              "user name attribute not released",
+			login:             &v3.CASLogin{Ticket: "ST-valid", Service: testService},
+			userNameAttribute: "uid",
+			allowed:           true,
+			wantErrCode:       &httperror.Unauthorized,
+		},
+		{
+			name:        "invalid ticket",
+			login:       &v3.CASLogin{Ticket: "ST-other", Service: testService},
+			allowed:     true,
+			wantErrCode: &httperror.Unauthorized,
+		},
+		{
+			name:        "service of another host",
+			login:       &v3.CASLogin{Ticket: "ST-valid", Service: "https://evil.example.com/"},
+			allowed:     true,
+			wantErrCode: &httperror.InvalidBodyContent,
+		},
+		{
+			name:        "no ticket",
+			login:       &v3.CASLogin{Service: testService},
+			allowed:     true,
+			wantErrCode: &httperror.MissingRequired,
+		},
+		{
+			name:        "access denied",
+			login:       &v3.CASLogin{Ticket: "ST-valid", Service: testService},
+			allowed:     false,
+			wantErrCode: &httperror.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &casProvider{
+				authConfigsRaw: mockGenericClient{ObjectMap: map[string]interface{}{
+					"serverUrl":            server.URL + "/cas",
+					"certificate":          certificate,
+					"userNameAttribute":    tt.userNameAttribute,
+					"displayNameAttribute": "cn",
+					"groupsAttribute":      "memberOf",
+					"enabled":              true,
+				}},
+				userMGR:   &mockUserManager{allowed: tt.allowed},
+				serverURL: func() string { return "https://rancher.example.com" },
+			}
+
+			user, groups, _, err := provider.AuthenticateUser(context.Background(), tt.login)
+			if tt.wantErrCode != nil {
+				require.Error(t, err)
+				apiErr, ok := err.(*httperror.APIError)
+				require.True(t, ok)
+				assert.Equal(t, *tt.wantErrCode, apiErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, user.Name)
+			assert.Equal(t, "John Doe", user.DisplayName)
+			assert.Equal(t, Name, user.Provider)
+			assert.True(t, user.Me)
+			var groupNames []string
+			for _, group := range groups {
+				assert.True(t, group.MemberOf)
+				groupNames = append(groupNames, group.Name)
+			}
+			assert.Equal(t, []string{"cas_group://faculty", "cas_group://staff"}, groupNames)
+		})
+	}
+}
+
+func TestTransformToAuthProvider(t *testing.T) {
+	provider := &casProvider{}
+	authProvider, err := provider.TransformToAuthProvider(map[string]interface{}{
+		"metadata":  map[string]interface{}{"name": Name},
+		"serverUrl": "https://cas.example.edu/cas/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://cas.example.edu/cas/login", authProvider["redirectUrl"])
+}
+
+func TestSearchPrincipals(t *testing.T) {
+	provider := &casProvider{}
+	token := &v3.Token{
+		UserPrincipal:   v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: "cas_user://jdoe"}, LoginName: "jdoe", Provider: Name, PrincipalType: userType},
+		GroupPrincipals: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "cas_group://jdoe"}}},
+	}
+
+	principals, err := provider.SearchPrincipals("jdoe", "", token)
+	require.NoError(t, err)
+	require.Len(t, principals, 2)
+	assert.Equal(t, "cas_user://jdoe", principals[0].Name)
+	assert.True(t, principals[0].Me)
+	assert.Equal(t, "cas_group://jdoe", principals[1].Name)
+	assert.True(t, principals[1].MemberOf)
+
+	principals, err = provider.SearchPrincipals("faculty", groupType, token)
+	require.NoError(t, err)
+	require.Len(t, principals, 1)
+	assert.Equal(t, "cas_group://faculty", principals[0].Name)
+	assert.False(t, principals[0].MemberOf)
+}
+
+func TestGetPrincipal(t *testing.T) {
+	provider := &casProvider{}
+	token := &v3.Token{
+		UserPrincipal: v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: "cas_user://jdoe"}, DisplayName: "John Doe", Provider: Name, PrincipalType: userType},
+	}
+
+	principal, err := provider.GetPrincipal("cas_user://jdoe", token)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", principal.DisplayName)
+	assert.True(t, principal.Me)
+
+	principal, err = provider.GetPrincipal("cas_group://faculty", token)
+	require.NoError(t, err)
+	assert.Equal(t, "faculty", principal.DisplayName)
+	assert.Equal(t, groupType, principal.PrincipalType)
+
+	_, err = provider.GetPrincipal("github_user://jdoe", token)
+	assert.Error(t, err)
+}
+
+type mockUserManager struct {
+	allowed bool
+}
+
+func (m *mockUserManager) SetPrincipalOnCurrentUser(apiContext *types.APIContext, principal v3.Principal) (*v3.User, error) {
+	panic("unimplemented")
+}
+
+func (m *mockUserManager) CheckAccess(accessMode string, allowedPrincipalIDs []string, userPrincipalID string, groups []v3.Principal) (bool, error) {
+	return m.allowed, nil
+}
+
+type mockGenericClient struct {
+	ObjectMap map[string]interface{}
+}
+
+func (m mockGenericClient) UnstructuredClient() objectclient.GenericClient {
+	panic("unimplemented")
+}
+func (m mockGenericClient) GroupVersionKind() schema.GroupVersionKind {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Create(o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) GetNamespaced(namespace, name string, opts metav1.GetOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Get(name string, opts metav1.GetOptions) (runtime.Object, error) {
+	return &unstructured.Unstructured{Object: m.ObjectMap}, nil
+}
+func (m mockGenericClient) Update(name string, o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) UpdateStatus(name string, o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) DeleteNamespaced(namespace, name string, opts *metav1.DeleteOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Delete(name string, opts *metav1.DeleteOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) List(opts metav1.ListOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) ListNamespaced(namespace string, opts metav1.ListOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) DeleteCollection(deleteOptions *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Patch(name string, o runtime.Object, patchType k8stypes.PatchType, data []byte, subresources ...string) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) ObjectFactory() objectclient.ObjectFactory {
+	panic("unimplemented")
+}
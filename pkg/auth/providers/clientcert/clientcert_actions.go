@@ -0,0 +1,98 @@
+package clientcert
+
+import (
+	"fmt"
+
+	"github.com/rancher/norman/api/handler"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	managementschema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/retry"
+)
+
+func (p *clientCertProvider) formatter(apiContext *types.APIContext, resource *types.RawResource) {
+	common.AddCommonActions(apiContext, resource)
+	resource.AddAction(apiContext, "testAndApply")
+}
+
+func (p *clientCertProvider) actionHandler(actionName string, action *types.Action, request *types.APIContext) error {
+	handled, err := common.HandleCommonAction(actionName, action, request, Name, p.authConfigs)
+	if err != nil {
+		return err
+	}
+	if handled {
+		return nil
+	}
+
+	if actionName == "testAndApply" {
+		return p.testAndApply(request)
+	}
+
+	return httperror.NewAPIError(httperror.ActionNotAvailable, "")
+}
+
+// testAndApply authenticates the current request with its client certificate before saving the config.
+func (p *clientCertProvider) testAndApply(request *types.APIContext) error {
+	input, err := handler.ParseAndValidateActionBody(request, request.Schemas.Schema(&managementschema.Version,
+		client.ClientCertTestAndApplyInputType))
+	if err != nil {
+		return err
+	}
+
+	configApplyInput := &v32.ClientCertTestAndApplyInput{}
+	if err := common.Decode(input, configApplyInput); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent,
+			fmt.Sprintf("Failed to parse body: %v", err))
+	}
+
+	config := &configApplyInput.ClientCertConfig
+
+	if _, err := parseCertificates([]byte(config.CACertificates)); err != nil {
+		return httperror.WrapAPIError(err, httperror.InvalidBodyContent, fmt.Sprintf("invalid caCertificates: %v", err))
+	}
+
+	userPrincipal, groupPrincipals, err := p.loginUser(config, request.Request)
+	if err != nil {
+		return err
+	}
+
+	// If this works, save the config adding the enabled flag.
+	config.Enabled = configApplyInput.Enabled
+	if err := p.saveClientCertConfig(config); err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Failed to save %s config: %v", Name, err))
+	}
+
+	user, err := p.userMGR.SetPrincipalOnCurrentUser(request, userPrincipal)
+	if err != nil {
+		return err
+	}
+
+	userExtraInfo := p.GetUserExtraAttributes(userPrincipal)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return p.tokenMGR.UserAttributeCreateOrUpdate(user.Name, userPrincipal.Provider, groupPrincipals, userExtraInfo)
+	}); err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Failed to create or update userAttribute: %v", err))
+	}
+
+	return p.tokenMGR.CreateTokenAndSetCookie(user.Name, userPrincipal, groupPrincipals, "", 0, "Token via client certificate Configuration", request)
+}
+
+func (p *clientCertProvider) saveClientCertConfig(config *v32.ClientCertConfig) error {
+	storedConfig, err := p.getClientCertConfig()
+	if err != nil {
+		return err
+	}
+	config.APIVersion = "management.cattle.io/v3"
+	config.Kind = v3.AuthConfigGroupVersionKind.Kind
+	config.Type = client.ClientCertConfigType
+	config.ObjectMeta = storedConfig.ObjectMeta
+
+	logrus.Debugf("updating %s config", Name)
+	_, err = p.authConfigs.ObjectClient().Update(config.ObjectMeta.Name, config)
+	return err
+}
@@ -0,0 +1,290 @@
+package clientcert
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/util"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// Name is the name of the X.509 client certificate provider.
+	Name = "clientcert"
+
+	userType  = common.UserPrincipalType
+	groupType = common.GroupPrincipalType
+)
+
+type userManager interface {
+	SetPrincipalOnCurrentUser(apiContext *types.APIContext, principal v32.Principal) (*v32.User, error)
+	CheckAccess(accessMode string, allowedPrincipalIDs []string, userPrincipalID string, groups []v32.Principal) (bool, error)
+}
+
+type tokenManager interface {
+	UserAttributeCreateOrUpdate(userID, provider string, groupPrincipals []v32.Principal, userExtraInfo map[string][]string, loginTime ...time.Time) error
+	CreateTokenAndSetCookie(userID string, userPrincipal v32.Principal, groupPrincipals []v32.Principal, providerToken string, ttl int, description string, request *types.APIContext) error
+}
+
+type clientCertProvider struct {
+	ctx            context.Context
+	authConfigs    v3.AuthConfigInterface
+	authConfigsRaw objectclient.GenericClient
+	userMGR        userManager
+	tokenMGR       tokenManager
+	revocation     *revocationChecker
+	now            func() time.Time
+}
+
+func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, userMGR userManager, tokenMGR tokenManager) common.AuthProvider {
+	authConfigs := mgmtCtx.Management.AuthConfigs("")
+	return &clientCertProvider{
+		ctx:            ctx,
+		authConfigs:    authConfigs,
+		authConfigsRaw: authConfigs.ObjectClient().UnstructuredClient(),
+		userMGR:        userMGR,
+		tokenMGR:       tokenMGR,
+		revocation:     newRevocationChecker(),
+		now:            time.Now,
+	}
+}
+
+func (p *clientCertProvider) GetName() string {
+	return Name
+}
+
+func (p *clientCertProvider) LogoutAll(apiContext *types.APIContext, token accessor.TokenAccessor) error {
+	return nil
+}
+
+func (p *clientCertProvider) Logout(apiContext *types.APIContext, token accessor.TokenAccessor) error {
+	return nil
+}
+
+func (p *clientCertProvider) CustomizeSchema(schema *types.Schema) {
+	schema.ActionHandler = p.actionHandler
+	schema.Formatter = p.formatter
+}
+
+func (p *clientCertProvider) TransformToAuthProvider(authConfig map[string]interface{}) (map[string]interface{}, error) {
+	return common.TransformToAuthProvider(authConfig), nil
+}
+
+// AuthenticateUser validates the client certificate of the login request and returns the user principal and the
+// group principals mapped from it.
+func (p *clientCertProvider) AuthenticateUser(ctx context.Context, input interface{}) (v32.Principal, []v32.Principal, string, error) {
+	req, ok := ctx.Value(util.RequestKey).(*http.Request)
+	if !ok {
+		return v32.Principal{}, nil, "", errors.New("missing login request")
+	}
+
+	config, err := p.getClientCertConfig()
+	if err != nil {
+		return v32.Principal{}, nil, "", errors.New("can't find authprovider")
+	}
+
+	userPrincipal, groupPrincipals, err := p.loginUser(config, req)
+	if err != nil {
+		return v32.Principal{}, nil, "", err
+	}
+
+	return userPrincipal, groupPrincipals, "", nil
+}
+
+func (p *clientCertProvider) loginUser(config *v32.ClientCertConfig, req *http.Request) (v32.Principal, []v32.Principal, error) {
+	certs, err := clientCertificates(config, req)
+	if err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, "client certificate required")
+	}
+
+	now := p.now()
+	issuer, err := verifyCertificate(config, certs, now)
+	if err != nil {
+		logrus.Debugf("[%s] rejecting certificate %s: %v", Name, certs[0].Subject, err)
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, "invalid client certificate")
+	}
+	if err := p.revocation.check(config, certs[0], issuer, now); err != nil {
+		if errors.Is(err, errRevoked) {
+			return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, "client certificate has been revoked")
+		}
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.ServerError, "unable to check the revocation status of the client certificate")
+	}
+
+	userPrincipal, groupPrincipals, err := toUserAndGroupPrincipals(config, certs[0])
+	if err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, "invalid client certificate")
+	}
+
+	allowed, err := p.userMGR.CheckAccess(config.AccessMode, config.AllowedPrincipalIDs, userPrincipal.Name, groupPrincipals)
+	if err != nil {
+		return v32.Principal{}, nil, err
+	}
+	if !allowed {
+		return v32.Principal{}, nil, httperror.NewAPIError(httperror.PermissionDenied, "Permission denied")
+	}
+
+	return userPrincipal, groupPrincipals, nil
+}
+
+// SearchPrincipals returns a principal of the requested type with the searchKey as its ID, since certificates
+// can't be looked up. If the principalType is empty, both a user and a group principal are returned.
+func (p *clientCertProvider) SearchPrincipals(searchKey, principalType string, token accessor.TokenAccessor) ([]v32.Principal, error) {
+	var principals []v32.Principal
+	if principalType != groupType {
+		principal := toPrincipal(userType, searchKey, searchKey)
+		p.markPrincipal(&principal, token)
+		principals = append(principals, principal)
+	}
+	if principalType != userType {
+		principal := toPrincipal(groupType, searchKey, searchKey)
+		p.markPrincipal(&principal, token)
+		principals = append(principals, principal)
+	}
+	return principals, nil
+}
+
+func (p *clientCertProvider) GetPrincipal(principalID string, token accessor.TokenAccessor) (v32.Principal, error) {
+	principalType, id, err := parsePrincipalID(principalID)
+	if err != nil {
+		return v32.Principal{}, err
+	}
+
+	if token != nil && token.GetUserPrincipal().Name == principalID {
+		principal := token.GetUserPrincipal()
+		principal.Me = true
+		return principal, nil
+	}
+
+	principal := toPrincipal(principalType, id, id)
+	p.markPrincipal(&principal, token)
+	return principal, nil
+}
+
+// RefetchGroupPrincipals isn't supported, the groups of a user are only known when it presents its certificate.
+func (p *clientCertProvider) RefetchGroupPrincipals(principalID string, secret string) ([]v32.Principal, error) {
+	return nil, errors.New("Not implemented")
+}
+
+func (p *clientCertProvider) CanAccessWithGroupProviders(userPrincipalID string, groupPrincipals []v32.Principal) (bool, error) {
+	config, err := p.getClientCertConfig()
+	if err != nil {
+		logrus.Errorf("Error fetching client certificate config: %v", err)
+		return false, err
+	}
+	return p.userMGR.CheckAccess(config.AccessMode, config.AllowedPrincipalIDs, userPrincipalID, groupPrincipals)
+}
+
+func (p *clientCertProvider) GetUserExtraAttributes(userPrincipal v32.Principal) map[string][]string {
+	return common.GetCommonUserExtraAttributes(userPrincipal)
+}
+
+// IsDisabledProvider checks if the client certificate provider is currently disabled in Rancher.
+func (p *clientCertProvider) IsDisabledProvider() (bool, error) {
+	config, err := p.getClientCertConfig()
+	if err != nil {
+		return false, err
+	}
+	return !config.Enabled, nil
+}
+
+func (p *clientCertProvider) markPrincipal(principal *v32.Principal, token accessor.TokenAccessor) {
+	if token == nil {
+		return
+	}
+	switch principal.PrincipalType {
+	case userType:
+		principal.Me = common.SamePrincipal(token.GetUserPrincipal(), *principal)
+	case groupType:
+		for _, group := range token.GetGroupPrincipals() {
+			if group.Name == principal.Name {
+				principal.MemberOf = true
+				break
+			}
+		}
+	}
+}
+
+func (p *clientCertProvider) getClientCertConfig() (*v32.ClientCertConfig, error) {
+	return getClientCertConfig(p.authConfigsRaw)
+}
+
+func getClientCertConfig(genericClient objectclient.GenericClient) (*v32.ClientCertConfig, error) {
+	authConfigObj, err := genericClient.Get(Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s config: %w", Name, err)
+	}
+
+	u, ok := authConfigObj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve %s config, cannot read k8s Unstructured data", Name)
+	}
+
+	storedConfig := &v32.ClientCertConfig{}
+	if err := common.Decode(u.UnstructuredContent(), storedConfig); err != nil {
+		return nil, fmt.Errorf("unable to decode %s config: %w", Name, err)
+	}
+
+	return storedConfig, nil
+}
+
+// toUserAndGroupPrincipals maps the fields of a verified certificate to the user principal and its group principals.
+func toUserAndGroupPrincipals(config *v32.ClientCertConfig, cert *x509.Certificate) (v32.Principal, []v32.Principal, error) {
+	name, err := username(config, cert)
+	if err != nil {
+		return v32.Principal{}, nil, err
+	}
+
+	displayName := cert.Subject.CommonName
+	if displayName == "" {
+		displayName = name
+	}
+	userPrincipal := toPrincipal(userType, name, displayName)
+	userPrincipal.Me = true
+
+	var groupPrincipals []v32.Principal
+	for _, group := range groups(config, cert) {
+		groupPrincipal := toPrincipal(groupType, group, group)
+		groupPrincipal.MemberOf = true
+		groupPrincipals = append(groupPrincipals, groupPrincipal)
+	}
+
+	return userPrincipal, groupPrincipals, nil
+}
+
+func toPrincipal(principalType, id, displayName string) v32.Principal {
+	return v32.Principal{
+		ObjectMeta:    metav1.ObjectMeta{Name: Name + "_" + principalType + "://" + id},
+		DisplayName:   displayName,
+		LoginName:     id,
+		PrincipalType: principalType,
+		Provider:      Name,
+	}
+}
+
+// parsePrincipalID splits a principal ID like clientcert_user://jdoe into its type and the ID mapped from the certificate.
+func parsePrincipalID(principalID string) (string, string, error) {
+	scope, id, ok := strings.Cut(principalID, "://")
+	if !ok || id == "" {
+		return "", "", fmt.Errorf("invalid id %s", principalID)
+	}
+	principalType, ok := strings.CutPrefix(scope, Name+"_")
+	if !ok || (principalType != userType && principalType != groupType) {
+		return "", "", fmt.Errorf("invalid id %s", principalID)
+	}
+	return principalType, id, nil
+}
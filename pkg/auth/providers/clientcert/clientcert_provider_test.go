@@ -0,0 +1,240 @@
+package clientcert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/norman/types"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestAuthenticateUser(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	otherCA := newTestCA(t, "other")
+
+	tests := []struct {
+		name        string
+		cert        *x509.Certificate
+		allowed     bool
+		wantErrCode *httperror.ErrorCode
+	}{
+		{
+			name:    "valid certificate",
+			cert:    ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "John Doe", OrganizationalUnit: []string{"admins", "devs"}}, EmailAddresses: []string{"jdoe@example.com"}}),
+			allowed: true,
+		},
+		{
+			name:        "no certificate",
+			allowed:     true,
+			wantErrCode: &httperror.Unauthorized,
+		},
+		{
+			name:        "untrusted certificate",
+			cert:        otherCA.issue(t, &x509.Certificate{EmailAddresses: []string{"jdoe@example.com"}}),
+			allowed:     true,
+			wantErrCode: &httperror.Unauthorized,
+		},
+		{
+			name:        "certificate without username",
+			cert:        ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "John Doe"}}),
+			allowed:     true,
+			wantErrCode: &httperror.Unauthorized,
+		},
+		{
+			name:        "access denied",
+			cert:        ca.issue(t, &x509.Certificate{EmailAddresses: []string{"jdoe@example.com"}}),
+			allowed:     false,
+			wantErrCode: &httperror.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &clientCertProvider{
+				authConfigsRaw: mockGenericClient{ObjectMap: map[string]interface{}{
+					"caCertificates": ca.pem(),
+					"usernameField":  UsernameFieldEmail,
+					"groupField":     GroupFieldOrganizationalUnit,
+					"enabled":        true,
+				}},
+				userMGR:    &mockUserManager{allowed: tt.allowed},
+				revocation: newRevocationChecker(),
+				now:        time.Now,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v3-public/clientCertProviders/clientcert?action=login", nil)
+			if tt.cert != nil {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.cert}}
+			}
+			ctx := context.WithValue(context.Background(), util.RequestKey, req)
+
+			user, groups, _, err := provider.AuthenticateUser(ctx, &v3.ClientCertLogin{})
+			if tt.wantErrCode != nil {
+				require.Error(t, err)
+				apiErr, ok := err.(*httperror.APIError)
+				require.True(t, ok)
+				assert.Equal(t, *tt.wantErrCode, apiErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "clientcert_user://jdoe@example.com", user.Name)
+			assert.Equal(t, "John Doe", user.DisplayName)
+			assert.Equal(t, Name, user.Provider)
+			assert.True(t, user.Me)
+			var groupNames []string
+			for _, group := range groups {
+				assert.True(t, group.MemberOf)
+				groupNames = append(groupNames, group.Name)
+			}
+			assert.ElementsMatch(t, []string{"clientcert_group://admins", "clientcert_group://devs"}, groupNames)
+		})
+	}
+}
+
+func TestSearchPrincipals(t *testing.T) {
+	provider := &clientCertProvider{}
+	token := &v3.Token{
+		UserPrincipal:   v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: "clientcert_user://jdoe"}, LoginName: "jdoe", Provider: Name, PrincipalType: userType},
+		GroupPrincipals: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "clientcert_group://jdoe"}}},
+	}
+
+	principals, err := provider.SearchPrincipals("jdoe", "", token)
+	require.NoError(t, err)
+	require.Len(t, principals, 2)
+	assert.Equal(t, "clientcert_user://jdoe", principals[0].Name)
+	assert.True(t, principals[0].Me)
+	assert.Equal(t, "clientcert_group://jdoe", principals[1].Name)
+	assert.True(t, principals[1].MemberOf)
+
+	principals, err = provider.SearchPrincipals("admins", groupType, token)
+	require.NoError(t, err)
+	require.Len(t, principals, 1)
+	assert.Equal(t, "clientcert_group://admins", principals[0].Name)
+	assert.False(t, principals[0].MemberOf)
+}
+
+func TestGetPrincipal(t *testing.T) {
+	provider := &clientCertProvider{}
+	token := &v3.Token{
+		UserPrincipal: v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: "clientcert_user://jdoe"}, DisplayName: "John Doe", Provider: Name, PrincipalType: userType},
+	}
+
+	principal, err := provider.GetPrincipal("clientcert_user://jdoe", token)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", principal.DisplayName)
+	assert.True(t, principal.Me)
+
+	principal, err = provider.GetPrincipal("clientcert_group://admins", token)
+	require.NoError(t, err)
+	assert.Equal(t, "admins", principal.DisplayName)
+	assert.Equal(t, groupType, principal.PrincipalType)
+
+	_, err = provider.GetPrincipal("github_user://jdoe", token)
+	assert.Error(t, err)
+}
+
+func TestParsePrincipalID(t *testing.T) {
+	tests := []struct {
+		principalID string
+		wantType    string
+		wantID      string
+		wantErr     bool
+	}{
+		{principalID: "clientcert_user://jdoe@example.com", wantType: userType, wantID: "jdoe@example.com"},
+		{principalID: "clientcert_group://admins", wantType: groupType, wantID: "admins"},
+		{principalID: "clientcert_user://", wantErr: true},
+		{principalID: "clientcert_robot://jdoe", wantErr: true},
+		{principalID: "openldap_user://jdoe", wantErr: true},
+		{principalID: "jdoe", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.principalID, func(t *testing.T) {
+			principalType, id, err := parsePrincipalID(tt.principalID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, principalType)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
+
+type mockUserManager struct {
+	allowed bool
+}
+
+func (m *mockUserManager) SetPrincipalOnCurrentUser(apiContext *types.APIContext, principal v3.Principal) (*v3.User, error) {
+	panic("unimplemented")
+}
+
+func (m *mockUserManager) CheckAccess(accessMode string, allowedPrincipalIDs []string, userPrincipalID string, groups []v3.Principal) (bool, error) {
+	return m.allowed, nil
+}
+
+type mockGenericClient struct {
+	ObjectMap map[string]interface{}
+}
+
+func (m mockGenericClient) UnstructuredClient() objectclient.GenericClient {
+	panic("unimplemented")
+}
+func (m mockGenericClient) GroupVersionKind() schema.GroupVersionKind {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Create(o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) GetNamespaced(namespace, name string, opts metav1.GetOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Get(name string, opts metav1.GetOptions) (runtime.Object, error) {
+	return &unstructured.Unstructured{Object: m.ObjectMap}, nil
+}
+func (m mockGenericClient) Update(name string, o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) UpdateStatus(name string, o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) DeleteNamespaced(namespace, name string, opts *metav1.DeleteOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Delete(name string, opts *metav1.DeleteOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) List(opts metav1.ListOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) ListNamespaced(namespace string, opts metav1.ListOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) DeleteCollection(deleteOptions *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Patch(name string, o runtime.Object, patchType k8stypes.PatchType, data []byte, subresources ...string) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) ObjectFactory() objectclient.ObjectFactory {
+	panic("unimplemented")
+}
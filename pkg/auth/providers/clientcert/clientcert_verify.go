@@ -0,0 +1,378 @@
+package clientcert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	UsernameFieldCommonName = "commonName"
+	UsernameFieldEmail      = "email"
+	UsernameFieldUPN        = "upn"
+
+	GroupFieldOrganizationalUnit = "organizationalUnit"
+	GroupFieldOrganization       = "organization"
+
+	// maxResponseSize limits the size of the OCSP responses and CRLs read from the network.
+	maxResponseSize = 10 << 20
+)
+
+var (
+	errNoCertificate     = errors.New("no client certificate presented")
+	errUntrustedHeader   = errors.New("client certificate header not set by a trusted proxy")
+	errRevoked           = errors.New("certificate has been revoked")
+	errRevocationUnknown = errors.New("revocation status of certificate is unknown")
+
+	oidSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+	oidEmailAddress   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}
+	// oidUPN identifies the Microsoft user principal name in the otherName SAN of smartcard logon certificates.
+	oidUPN = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 20, 2, 3}
+)
+
+// clientCertificates returns the certificate chain presented by the client, either in the TLS handshake with Rancher
+// or in the header set by a TLS terminating proxy. The header only holds the public certificate, which proves nothing
+// about the client holding the private key, so it's only read from the proxies of auth-trusted-proxy-cidrs.
+func clientCertificates(config *v32.ClientCertConfig, req *http.Request) ([]*x509.Certificate, error) {
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		return req.TLS.PeerCertificates, nil
+	}
+	if config.ClientCertificateHeader == "" {
+		return nil, errNoCertificate
+	}
+	value := req.Header.Get(config.ClientCertificateHeader)
+	if value == "" {
+		return nil, errNoCertificate
+	}
+	if !util.FromTrustedProxy(req) {
+		return nil, fmt.Errorf("%w: %s", errUntrustedHeader, req.RemoteAddr)
+	}
+
+	data, err := url.QueryUnescape(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header: %w", config.ClientCertificateHeader, err)
+	}
+	certs, err := parseCertificates([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header: %w", config.ClientCertificateHeader, err)
+	}
+	return certs, nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+// verifyCertificate verifies that the first certificate of certs chains up to one of the configured CAs and is valid
+// for client authentication. It returns the issuer of the certificate, which is nil if the certificate itself is a CA.
+func verifyCertificate(config *v32.ClientCertConfig, certs []*x509.Certificate, now time.Time) (*x509.Certificate, error) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(config.CACertificates)) {
+		return nil, errors.New("no valid CA certificate configured")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	chains, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(chains[0]) < 2 {
+		return nil, nil
+	}
+	return chains[0][1], nil
+}
+
+// revocationChecker checks the revocation status of certificates with OCSP and CRLs.
+// CRLs are cached until their next update.
+type revocationChecker struct {
+	client *http.Client
+
+	mu   sync.Mutex
+	crls map[string]*x509.RevocationList
+}
+
+func newRevocationChecker() *revocationChecker {
+	return &revocationChecker{
+		client: &http.Client{Timeout: 10 * time.Second},
+		crls:   map[string]*x509.RevocationList{},
+	}
+}
+
+// check returns errRevoked if cert has been revoked by issuer. Certificates whose status can't be determined are
+// rejected with errRevocationUnknown, unless soft fail is configured.
+func (c *revocationChecker) check(config *v32.ClientCertConfig, cert, issuer *x509.Certificate, now time.Time) error {
+	if issuer == nil || (!config.OCSPEnabled && !config.CRLEnabled) {
+		return nil
+	}
+
+	var errs []error
+	if config.OCSPEnabled {
+		err := c.checkOCSP(cert, issuer, now)
+		if err == nil || errors.Is(err, errRevoked) {
+			return err
+		}
+		errs = append(errs, fmt.Errorf("OCSP: %w", err))
+	}
+	if config.CRLEnabled {
+		err := c.checkCRL(cert, issuer, now)
+		if err == nil || errors.Is(err, errRevoked) {
+			return err
+		}
+		errs = append(errs, fmt.Errorf("CRL: %w", err))
+	}
+
+	err := fmt.Errorf("%w: %w", errRevocationUnknown, errors.Join(errs...))
+	if config.RevocationSoftFail {
+		logrus.Warnf("[%s] accepting certificate %s of %s: %v", Name, cert.SerialNumber, cert.Subject, err)
+		return nil
+	}
+	return err
+}
+
+func (c *revocationChecker) checkOCSP(cert, issuer *x509.Certificate, now time.Time) error {
+	if len(cert.OCSPServer) == 0 {
+		return errors.New("certificate has no OCSP responder")
+	}
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, server := range cert.OCSPServer {
+		body, err := c.post(server, "application/ocsp-request", request)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		response, err := ocsp.ParseResponseForCert(body, cert, issuer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid response from %s: %w", server, err))
+			continue
+		}
+		if !response.NextUpdate.IsZero() && now.After(response.NextUpdate) {
+			errs = append(errs, fmt.Errorf("stale response from %s", server))
+			continue
+		}
+
+		switch response.Status {
+		case ocsp.Good:
+			return nil
+		case ocsp.Revoked:
+			return errRevoked
+		default:
+			errs = append(errs, fmt.Errorf("%s doesn't know the certificate", server))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *revocationChecker) checkCRL(cert, issuer *x509.Certificate, now time.Time) error {
+	if len(cert.CRLDistributionPoints) == 0 {
+		return errors.New("certificate has no CRL distribution point")
+	}
+
+	var errs []error
+	for _, distributionPoint := range cert.CRLDistributionPoints {
+		crl, err := c.getCRL(distributionPoint, issuer, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return errRevoked
+			}
+		}
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// getCRL returns the CRL of distributionPoint, fetching it if it isn't cached or has passed its next update.
+func (c *revocationChecker) getCRL(distributionPoint string, issuer *x509.Certificate, now time.Time) (*x509.RevocationList, error) {
+	c.mu.Lock()
+	crl, ok := c.crls[distributionPoint]
+	c.mu.Unlock()
+	if ok && now.Before(crl.NextUpdate) && crl.CheckSignatureFrom(issuer) == nil {
+		return crl, nil
+	}
+
+	body, err := c.get(distributionPoint)
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(body); block != nil {
+		body = block.Bytes
+	}
+	crl, err = x509.ParseRevocationList(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRL from %s: %w", distributionPoint, err)
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("invalid CRL from %s: %w", distributionPoint, err)
+	}
+	if !crl.NextUpdate.IsZero() && now.After(crl.NextUpdate) {
+		return nil, fmt.Errorf("stale CRL from %s", distributionPoint)
+	}
+
+	c.mu.Lock()
+	c.crls[distributionPoint] = crl
+	c.mu.Unlock()
+	return crl, nil
+}
+
+func (c *revocationChecker) get(url string) ([]byte, error) {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	return readResponse(url, resp)
+}
+
+func (c *revocationChecker) post(url, contentType string, body []byte) ([]byte, error) {
+	resp, err := c.client.Post(url, contentType, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return readResponse(url, resp)
+}
+
+func readResponse(url string, resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+}
+
+// username returns the value of the configured username field of cert.
+func username(config *v32.ClientCertConfig, cert *x509.Certificate) (string, error) {
+	var value string
+	switch config.UsernameField {
+	case "", UsernameFieldCommonName:
+		value = cert.Subject.CommonName
+	case UsernameFieldEmail:
+		value = email(cert)
+	case UsernameFieldUPN:
+		upn, err := userPrincipalName(cert)
+		if err != nil {
+			return "", err
+		}
+		value = upn
+	default:
+		return "", fmt.Errorf("invalid usernameField %s", config.UsernameField)
+	}
+	if value == "" {
+		return "", fmt.Errorf("certificate has no %s", config.UsernameField)
+	}
+	return value, nil
+}
+
+// groups returns the values of the configured group field of the subject of cert.
+func groups(config *v32.ClientCertConfig, cert *x509.Certificate) []string {
+	switch config.GroupField {
+	case GroupFieldOrganizationalUnit:
+		return cert.Subject.OrganizationalUnit
+	case GroupFieldOrganization:
+		return cert.Subject.Organization
+	}
+	return nil
+}
+
+// email returns the first email SAN of cert, falling back to the deprecated emailAddress attribute of the subject.
+func email(cert *x509.Certificate) string {
+	if len(cert.EmailAddresses) > 0 {
+		return cert.EmailAddresses[0]
+	}
+	for _, name := range cert.Subject.Names {
+		if name.Type.Equal(oidEmailAddress) {
+			if value, ok := name.Value.(string); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// userPrincipalName returns the UPN of the otherName SAN of cert, which the standard library doesn't parse.
+func userPrincipalName(cert *x509.Certificate) (string, error) {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidSubjectAltName) {
+			continue
+		}
+
+		var names asn1.RawValue
+		if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+			return "", fmt.Errorf("invalid subject alternative names: %w", err)
+		}
+		rest := names.Bytes
+		for len(rest) > 0 {
+			var name asn1.RawValue
+			var err error
+			rest, err = asn1.Unmarshal(rest, &name)
+			if err != nil {
+				return "", fmt.Errorf("invalid subject alternative names: %w", err)
+			}
+			// otherName [0] { type-id OBJECT IDENTIFIER, value [0] EXPLICIT ANY }
+			if name.Class != asn1.ClassContextSpecific || name.Tag != 0 {
+				continue
+			}
+			var typeID asn1.ObjectIdentifier
+			value, err := asn1.Unmarshal(name.Bytes, &typeID)
+			if err != nil || !typeID.Equal(oidUPN) {
+				continue
+			}
+			var wrapper asn1.RawValue
+			if _, err := asn1.Unmarshal(value, &wrapper); err != nil {
+				return "", fmt.Errorf("invalid user principal name: %w", err)
+			}
+			var upn string
+			if _, err := asn1.UnmarshalWithParams(wrapper.Bytes, &upn, "utf8"); err != nil {
+				return "", fmt.Errorf("invalid user principal name: %w", err)
+			}
+			return upn, nil
+		}
+	}
+	return "", nil
+}
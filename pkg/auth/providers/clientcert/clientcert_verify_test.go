@@ -0,0 +1,346 @@
+package clientcert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) pem() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+}
+
+// issue signs a client certificate based on template, filling in the fields the tests don't care about.
+func (ca *testCA) issue(t *testing.T, template *x509.Certificate) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	if template.SerialNumber == nil {
+		template.SerialNumber = big.NewInt(2)
+	}
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+	if template.ExtKeyUsage == nil {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// upnExtension returns a subject alternative name extension holding upn as an otherName.
+func upnExtension(t *testing.T, upn string) pkix.Extension {
+	t.Helper()
+	typeID, err := asn1.Marshal(oidUPN)
+	require.NoError(t, err)
+	utf8, err := asn1.MarshalWithParams(upn, "utf8")
+	require.NoError(t, err)
+	value, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: utf8})
+	require.NoError(t, err)
+	names, err := asn1.Marshal([]asn1.RawValue{{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: append(typeID, value...)}})
+	require.NoError(t, err)
+	return pkix.Extension{Id: oidSubjectAltName, Value: names}
+}
+
+func TestUsername(t *testing.T) {
+	ca := newTestCA(t, "ca")
+
+	tests := []struct {
+		name     string
+		field    string
+		template *x509.Certificate
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "common name",
+			field:    UsernameFieldCommonName,
+			template: &x509.Certificate{Subject: pkix.Name{CommonName: "jdoe"}},
+			want:     "jdoe",
+		},
+		{
+			name:     "common name by default",
+			template: &x509.Certificate{Subject: pkix.Name{CommonName: "jdoe"}},
+			want:     "jdoe",
+		},
+		{
+			name:     "email SAN",
+			field:    UsernameFieldEmail,
+			template: &x509.Certificate{Subject: pkix.Name{CommonName: "John Doe"}, EmailAddresses: []string{"jdoe@example.com"}},
+			want:     "jdoe@example.com",
+		},
+		{
+			name:  "email subject attribute",
+			field: UsernameFieldEmail,
+			template: &x509.Certificate{Subject: pkix.Name{
+				CommonName: "John Doe",
+				ExtraNames: []pkix.AttributeTypeAndValue{{Type: oidEmailAddress, Value: "jdoe@example.com"}},
+			}},
+			want: "jdoe@example.com",
+		},
+		{
+			name:     "upn",
+			field:    UsernameFieldUPN,
+			template: &x509.Certificate{Subject: pkix.Name{CommonName: "John Doe"}, ExtraExtensions: []pkix.Extension{upnExtension(t, "jdoe@EXAMPLE.COM")}},
+			want:     "jdoe@EXAMPLE.COM",
+		},
+		{
+			name:     "missing upn",
+			field:    UsernameFieldUPN,
+			template: &x509.Certificate{Subject: pkix.Name{CommonName: "John Doe"}, EmailAddresses: []string{"jdoe@example.com"}},
+			wantErr:  true,
+		},
+		{
+			name:     "missing common name",
+			field:    UsernameFieldCommonName,
+			template: &x509.Certificate{EmailAddresses: []string{"jdoe@example.com"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := ca.issue(t, tt.template)
+
+			got, err := username(&v3.ClientCertConfig{UsernameField: tt.field}, cert)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	root := newTestCA(t, "root")
+	otherRoot := newTestCA(t, "other")
+
+	intermediateTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(3),
+		Subject:               pkix.Name{CommonName: "intermediate"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	intermediateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, intermediateTemplate, root.cert, &intermediateKey.PublicKey, root.key)
+	require.NoError(t, err)
+	intermediateCert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	intermediate := &testCA{cert: intermediateCert, key: intermediateKey}
+
+	config := &v3.ClientCertConfig{CACertificates: root.pem()}
+
+	t.Run("issued by CA", func(t *testing.T) {
+		cert := root.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "jdoe"}})
+		issuer, err := verifyCertificate(config, []*x509.Certificate{cert}, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, root.cert.Raw, issuer.Raw)
+	})
+
+	t.Run("issued by intermediate", func(t *testing.T) {
+		cert := intermediate.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "jdoe"}})
+		issuer, err := verifyCertificate(config, []*x509.Certificate{cert, intermediateCert}, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, intermediateCert.Raw, issuer.Raw)
+
+		_, err = verifyCertificate(config, []*x509.Certificate{cert}, time.Now())
+		assert.Error(t, err, "expected the intermediate to be required")
+	})
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		cert := otherRoot.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "jdoe"}})
+		_, err := verifyCertificate(config, []*x509.Certificate{cert}, time.Now())
+		assert.Error(t, err)
+	})
+
+	t.Run("server certificate", func(t *testing.T) {
+		cert := root.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "jdoe"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})
+		_, err := verifyCertificate(config, []*x509.Certificate{cert}, time.Now())
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		cert := root.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "jdoe"}})
+		_, err := verifyCertificate(config, []*x509.Certificate{cert}, time.Now().Add(2*time.Hour))
+		assert.Error(t, err)
+	})
+}
+
+func TestClientCertificates(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	cert := ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "jdoe"}})
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	_, err := clientCertificates(&v3.ClientCertConfig{}, req)
+	assert.ErrorIs(t, err, errNoCertificate)
+
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	certs, err := clientCertificates(&v3.ClientCertConfig{}, req)
+	require.NoError(t, err)
+	assert.Equal(t, []*x509.Certificate{cert}, certs)
+
+	require.NoError(t, settings.AuthTrustedProxyCIDRs.Set("10.0.0.0/8"))
+	defer settings.AuthTrustedProxyCIDRs.Set("")
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "10.0.0.2:41000"
+	req.Header.Set("ssl-client-cert", url.QueryEscape(certPEM))
+	_, err = clientCertificates(&v3.ClientCertConfig{}, req)
+	assert.ErrorIs(t, err, errNoCertificate, "expected the header to be ignored unless configured")
+
+	certs, err = clientCertificates(&v3.ClientCertConfig{ClientCertificateHeader: "ssl-client-cert"}, req)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	assert.Equal(t, cert.Raw, certs[0].Raw)
+
+	req.Header.Set("ssl-client-cert", "invalid")
+	_, err = clientCertificates(&v3.ClientCertConfig{ClientCertificateHeader: "ssl-client-cert"}, req)
+	assert.Error(t, err)
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "198.51.100.7:41000"
+	req.Header.Set("ssl-client-cert", url.QueryEscape(certPEM))
+	_, err = clientCertificates(&v3.ClientCertConfig{ClientCertificateHeader: "ssl-client-cert"}, req)
+	assert.ErrorIs(t, err, errUntrustedHeader, "expected the header to be rejected from an untrusted address")
+
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	certs, err = clientCertificates(&v3.ClientCertConfig{ClientCertificateHeader: "ssl-client-cert"}, req)
+	require.NoError(t, err)
+	assert.Equal(t, []*x509.Certificate{cert}, certs)
+}
+
+func TestCheckRevocationCRL(t *testing.T) {
+	ca := newTestCA(t, "ca")
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Minute),
+		NextUpdate: time.Now().Add(time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: big.NewInt(10), RevocationTime: time.Now().Add(-time.Minute)},
+		},
+	}, ca.cert, ca.key)
+	require.NoError(t, err)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(crl)
+	}))
+	defer server.Close()
+
+	config := &v3.ClientCertConfig{CRLEnabled: true}
+	checker := newRevocationChecker()
+
+	revoked := ca.issue(t, &x509.Certificate{SerialNumber: big.NewInt(10), CRLDistributionPoints: []string{server.URL}})
+	assert.ErrorIs(t, checker.check(config, revoked, ca.cert, time.Now()), errRevoked)
+
+	good := ca.issue(t, &x509.Certificate{SerialNumber: big.NewInt(11), CRLDistributionPoints: []string{server.URL}})
+	assert.NoError(t, checker.check(config, good, ca.cert, time.Now()))
+	assert.Equal(t, 1, requests, "expected the CRL to be cached")
+
+	otherCA := newTestCA(t, "other")
+	assert.ErrorIs(t, newRevocationChecker().check(config, good, otherCA.cert, time.Now()), errRevocationUnknown,
+		"expected a CRL that isn't signed by the issuer to be rejected")
+
+	noDistributionPoint := ca.issue(t, &x509.Certificate{SerialNumber: big.NewInt(12)})
+	assert.ErrorIs(t, checker.check(config, noDistributionPoint, ca.cert, time.Now()), errRevocationUnknown)
+
+	config.RevocationSoftFail = true
+	assert.NoError(t, checker.check(config, noDistributionPoint, ca.cert, time.Now()))
+}
+
+func TestCheckRevocationOCSP(t *testing.T) {
+	ca := newTestCA(t, "ca")
+
+	status := ocsp.Good
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		request, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		response, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:           status,
+			SerialNumber:     request.SerialNumber,
+			ThisUpdate:       time.Now().Add(-time.Minute),
+			NextUpdate:       time.Now().Add(time.Hour),
+			RevokedAt:        time.Now().Add(-time.Minute),
+			RevocationReason: ocsp.KeyCompromise,
+		}, ca.key)
+		require.NoError(t, err)
+		w.Write(response)
+	}))
+	defer server.Close()
+
+	cert := ca.issue(t, &x509.Certificate{OCSPServer: []string{server.URL}})
+	checker := newRevocationChecker()
+
+	assert.NoError(t, checker.check(&v3.ClientCertConfig{OCSPEnabled: true}, cert, ca.cert, time.Now()))
+
+	status = ocsp.Revoked
+	assert.ErrorIs(t, checker.check(&v3.ClientCertConfig{OCSPEnabled: true}, cert, ca.cert, time.Now()), errRevoked)
+
+	status = ocsp.Unknown
+	assert.ErrorIs(t, checker.check(&v3.ClientCertConfig{OCSPEnabled: true}, cert, ca.cert, time.Now()), errRevocationUnknown)
+
+	assert.NoError(t, checker.check(&v3.ClientCertConfig{}, cert, ca.cert, time.Now()), "expected no check if disabled")
+}
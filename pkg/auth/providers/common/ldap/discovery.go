@@ -0,0 +1,138 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+)
+
+// serverDiscovery is shared by all the providers, so that the records of a domain are resolved once per refresh interval.
+var serverDiscovery = newSRVDiscovery(net.DefaultResolver.LookupSRV)
+
+// DiscoverServers returns the LDAP servers of a domain published in DNS SRV records (RFC 2782), as Active Directory
+// domain controllers are. The servers of the site are preferred: _ldap._tcp.<site>._sites.<domain> is looked up first,
+// falling back to _ldap._tcp.<domain>.
+// The servers are ordered by priority, and randomly by weight within the same priority, so that connections are
+// spread as the records intend. The records are resolved again once older than auth-ldap-server-discovery-refresh-seconds,
+// and the last resolved records are used while they can't be.
+func DiscoverServers(ctx context.Context, domain, site string) ([]string, error) {
+	return serverDiscovery.discover(ctx, domain, site)
+}
+
+type resolvedRecords struct {
+	records    []*net.SRV
+	resolvedAt time.Time
+}
+
+type srvDiscovery struct {
+	lookupSRV func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	now       func() time.Time
+	intn      func(int) int
+
+	mu       sync.Mutex
+	resolved map[string]resolvedRecords
+}
+
+func newSRVDiscovery(lookupSRV func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)) *srvDiscovery {
+	return &srvDiscovery{
+		lookupSRV: lookupSRV,
+		now:       time.Now,
+		intn:      rand.Intn,
+		resolved:  map[string]resolvedRecords{},
+	}
+}
+
+func (d *srvDiscovery) discover(ctx context.Context, domain, site string) ([]string, error) {
+	key := site + "/" + domain
+
+	d.mu.Lock()
+	cached, ok := d.resolved[key]
+	d.mu.Unlock()
+
+	if !ok || d.now().Sub(cached.resolvedAt) >= discoveryRefreshInterval() {
+		records, err := d.resolve(ctx, domain, site)
+		if err != nil {
+			if !ok {
+				return nil, err
+			}
+			logrus.Warnf("ldap: using the servers of %s resolved at %s: %v", domain, cached.resolvedAt.Format(time.RFC3339), err)
+		} else {
+			cached = resolvedRecords{records: records, resolvedAt: d.now()}
+			d.mu.Lock()
+			d.resolved[key] = cached
+			d.mu.Unlock()
+		}
+	}
+
+	return orderRecords(cached.records, d.intn), nil
+}
+
+func (d *srvDiscovery) resolve(ctx context.Context, domain, site string) ([]*net.SRV, error) {
+	if site != "" {
+		_, records, err := d.lookupSRV(ctx, "ldap", "tcp", site+"._sites."+domain)
+		if err == nil && len(records) > 0 {
+			return records, nil
+		}
+		logrus.Debugf("ldap: no servers found for site %s of %s, looking up the servers of the domain: %v", site, domain, err)
+	}
+
+	_, records, err := d.lookupSRV(ctx, "ldap", "tcp", domain)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: error looking up the servers of %s: %w", domain, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("ldap: no servers found for %s", domain)
+	}
+	return records, nil
+}
+
+// orderRecords returns the hostnames of the records by ascending priority. Within the same priority, each record is
+// picked with a probability proportional to its weight among the remaining ones (RFC 2782).
+func orderRecords(records []*net.SRV, intn func(int) int) []string {
+	sorted := append([]*net.SRV{}, records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+
+	servers := make([]string, 0, len(sorted))
+	for start := 0; start < len(sorted); {
+		end := start
+		for end < len(sorted) && sorted[end].Priority == sorted[start].Priority {
+			end++
+		}
+
+		group := sorted[start:end]
+		for len(group) > 0 {
+			total := 0
+			for _, record := range group {
+				total += int(record.Weight)
+			}
+			picked := 0
+			if total > 0 {
+				n := intn(total)
+				for sum := 0; picked < len(group); picked++ {
+					sum += int(group[picked].Weight)
+					if sum > n {
+						break
+					}
+				}
+			}
+			servers = append(servers, strings.TrimSuffix(group[picked].Target, "."))
+			group = append(group[:picked:picked], group[picked+1:]...)
+		}
+		start = end
+	}
+	return servers
+}
+
+func discoveryRefreshInterval() time.Duration {
+	return time.Duration(settings.AuthLDAPServerDiscoveryRefreshSeconds.GetInt()) * time.Second
+}
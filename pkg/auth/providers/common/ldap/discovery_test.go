@@ -0,0 +1,94 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRecords(t *testing.T) {
+	records := []*net.SRV{
+		{Target: "backup.example.com.", Priority: 10, Weight: 100},
+		{Target: "dc1.example.com.", Priority: 0, Weight: 0},
+		{Target: "dc2.example.com.", Priority: 0, Weight: 60},
+		{Target: "dc3.example.com.", Priority: 0, Weight: 40},
+	}
+
+	// The first pick falls within the weight of dc3, the last of the priority 0 records with a weight.
+	picks := []int{70, 0, 0}
+	intn := func(n int) int {
+		pick := picks[0]
+		picks = picks[1:]
+		return pick % n
+	}
+	assert.Equal(t, []string{"dc3.example.com", "dc2.example.com", "dc1.example.com", "backup.example.com"}, orderRecords(records, intn))
+
+	// Weights spread the first server picked.
+	counts := map[string]int{}
+	for i := 0; i < 100; i++ {
+		n := i
+		counts[orderRecords(records, func(m int) int { return n % m })[0]]++
+	}
+	assert.Equal(t, map[string]int{"dc2.example.com": 60, "dc3.example.com": 40}, counts)
+}
+
+func TestDiscoverServers(t *testing.T) {
+	var lookups []string
+	siteRecords := []*net.SRV{{Target: "dc1.site.example.com.", Weight: 100}}
+	domainRecords := []*net.SRV{{Target: "dc2.example.com.", Weight: 100}}
+	var lookupErr error
+	d := newSRVDiscovery(func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		lookups = append(lookups, "_"+service+"._"+proto+"."+name)
+		if lookupErr != nil {
+			return "", nil, lookupErr
+		}
+		switch name {
+		case "branch._sites.example.com":
+			return "", siteRecords, nil
+		case "example.com":
+			return "", domainRecords, nil
+		}
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	servers, err := d.discover(ctx, "example.com", "branch")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dc1.site.example.com"}, servers)
+
+	// Sites without domain controllers fall back to the domain.
+	servers, err = d.discover(ctx, "example.com", "hq")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dc2.example.com"}, servers)
+	assert.Equal(t, []string{"_ldap._tcp.branch._sites.example.com", "_ldap._tcp.hq._sites.example.com", "_ldap._tcp.example.com"}, lookups)
+
+	// The records are cached until the refresh interval.
+	lookups = nil
+	siteRecords = []*net.SRV{{Target: "dc3.site.example.com.", Weight: 100}}
+	servers, err = d.discover(ctx, "example.com", "branch")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dc1.site.example.com"}, servers)
+	assert.Empty(t, lookups)
+
+	now = now.Add(discoveryRefreshInterval())
+	servers, err = d.discover(ctx, "example.com", "branch")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dc3.site.example.com"}, servers)
+
+	// The last resolved records are used while DNS fails.
+	now = now.Add(discoveryRefreshInterval())
+	lookupErr = errors.New("server misbehaving")
+	servers, err = d.discover(ctx, "example.com", "branch")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dc3.site.example.com"}, servers)
+
+	_, err = d.discover(ctx, "other.example.com", "")
+	assert.Error(t, err)
+}
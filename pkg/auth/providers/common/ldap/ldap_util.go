@@ -13,6 +13,7 @@
 	"github.com/pkg/errors"
 	"github.com/rancher/norman/httperror"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/attributelabels"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -208,6 +209,16 @@ func AttributesToPrincipal(attribs []*ldapv3.EntryAttribute, dnStr, scope, provi
 		Me:            true,
 		Provider:      providerName,
 	}
+	if kind == "user" {
+		principal.ExtraInfo = attributelabels.ExtraInfo(func(attribute string) []string {
+			for _, attr := range attribs {
+				if strings.EqualFold(attr.Name, attribute) {
+					return attr.Values
+				}
+			}
+			return nil
+		})
+	}
 	return principal, nil
 }
 
@@ -0,0 +1,442 @@
+package ldap
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+)
+
+// NTLM negotiate flags (MS-NLMP 2.2.2.5).
+const (
+	ntlmNegotiateUnicode                 = 0x00000001
+	ntlmRequestTarget                    = 0x00000004
+	ntlmNegotiateSign                    = 0x00000010
+	ntlmNegotiateSeal                    = 0x00000020
+	ntlmNegotiateNTLM                    = 0x00000200
+	ntlmNegotiateAlwaysSign              = 0x00008000
+	ntlmNegotiateExtendedSessionSecurity = 0x00080000
+	ntlmNegotiateTargetInfo              = 0x00800000
+	ntlmNegotiateVersion                 = 0x02000000
+	ntlmNegotiate128                     = 0x20000000
+	ntlmNegotiateKeyExch                 = 0x40000000
+	ntlmNegotiate56                      = 0x80000000
+)
+
+// NTLM AV pair IDs (MS-NLMP 2.2.2.1).
+const (
+	ntlmAvEOL             = 0x0000
+	ntlmAvNbDomainName    = 0x0002
+	ntlmAvFlags           = 0x0006
+	ntlmAvTimestamp       = 0x0007
+	ntlmAvTargetName      = 0x0009
+	ntlmAvChannelBindings = 0x000A
+
+	ntlmAvFlagMICPresent = 0x00000002
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// ntlmVersion is the version sent in the messages, which is required for them to carry a MIC.
+// It claims Windows 10 and NTLMSSP revision 15.
+var ntlmVersion = []byte{10, 0, 0x61, 0x4a, 0, 0, 0, 15}
+
+// ntlmClient authenticates with NTLMv2 (MS-NLMP).
+type ntlmClient struct {
+	domain   string
+	user     string
+	password string
+	// targetName is the service principal name of the server, e.g. ldap/dc1.example.com.
+	targetName string
+	// channelBindings is the hash of the channel bindings of the TLS connection, or nil without TLS.
+	channelBindings []byte
+	// seal negotiates signing and sealing of the messages once authenticated, for connections without TLS.
+	// Active Directory refuses it over TLS.
+	seal bool
+
+	now  func() time.Time
+	rand io.Reader
+
+	negotiateMessage []byte
+}
+
+// newNTLMClient returns a client authenticating as the user, which is either DOMAIN\user or a user principal name.
+func newNTLMClient(username, password, targetName string, channelBindings []byte, seal bool) *ntlmClient {
+	var domain string
+	if i := strings.Index(username, "\\"); i >= 0 {
+		domain, username = username[:i], username[i+1:]
+	}
+	return &ntlmClient{
+		domain:          domain,
+		user:            username,
+		password:        password,
+		targetName:      targetName,
+		channelBindings: channelBindings,
+		seal:            seal,
+		now:             time.Now,
+		rand:            rand.Reader,
+	}
+}
+
+func (c *ntlmClient) flags() uint32 {
+	flags := uint32(ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign |
+		ntlmNegotiateExtendedSessionSecurity | ntlmNegotiateTargetInfo | ntlmNegotiateVersion |
+		ntlmNegotiate128 | ntlmNegotiateKeyExch | ntlmNegotiate56)
+	if c.seal {
+		flags |= ntlmNegotiateSign | ntlmNegotiateSeal
+	}
+	return flags
+}
+
+// negotiate returns the NEGOTIATE_MESSAGE starting the authentication.
+func (c *ntlmClient) negotiate() []byte {
+	msg := make([]byte, 40)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], c.flags())
+	// The domain and workstation fields are empty.
+	binary.LittleEndian.PutUint32(msg[20:], 40)
+	binary.LittleEndian.PutUint32(msg[28:], 40)
+	copy(msg[32:], ntlmVersion)
+	c.negotiateMessage = msg
+	return msg
+}
+
+// authenticate returns the AUTHENTICATE_MESSAGE answering the CHALLENGE_MESSAGE of the server,
+// and the session signing and sealing the messages if it was negotiated.
+func (c *ntlmClient) authenticate(challenge []byte) ([]byte, *ntlmSession, error) {
+	if len(challenge) < 48 || !bytes.Equal(challenge[:8], ntlmSignature) || binary.LittleEndian.Uint32(challenge[8:]) != 2 {
+		return nil, nil, errors.New("ntlm: invalid challenge message")
+	}
+	flags := c.flags() & binary.LittleEndian.Uint32(challenge[20:])
+	if flags&ntlmNegotiateExtendedSessionSecurity == 0 {
+		return nil, nil, errors.New("ntlm: the server doesn't support extended session security")
+	}
+	if c.seal && flags&(ntlmNegotiateSeal|ntlmNegotiateKeyExch|ntlmNegotiate128) != ntlmNegotiateSeal|ntlmNegotiateKeyExch|ntlmNegotiate128 {
+		return nil, nil, errors.New("ntlm: the server doesn't support sealing with 128-bit exchanged keys")
+	}
+	serverChallenge := challenge[24:32]
+	serverTargetInfo, err := ntlmField(challenge, 40)
+	if err != nil {
+		return nil, nil, err
+	}
+	serverPairs, err := parseAVPairs(serverTargetInfo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	domain := c.domain
+	if domain == "" && !strings.Contains(c.user, "@") {
+		domain = decodeUTF16(serverPairs.get(ntlmAvNbDomainName))
+	}
+
+	clientChallenge := make([]byte, 8)
+	if _, err := io.ReadFull(c.rand, clientChallenge); err != nil {
+		return nil, nil, err
+	}
+	timestamp := serverPairs.get(ntlmAvTimestamp)
+	if timestamp == nil {
+		timestamp = fileTime(c.now())
+	}
+
+	ntowf := ntowfv2(c.user, c.password, domain)
+	temp := ntlmv2Temp(timestamp, clientChallenge, c.targetInfo(serverPairs))
+	ntProof := hmacMD5(ntowf, serverChallenge, temp)
+	ntResponse := append(ntProof, temp...)
+	lmResponse := make([]byte, 24)
+	if serverPairs.get(ntlmAvTimestamp) == nil {
+		lmResponse = append(hmacMD5(ntowf, serverChallenge, clientChallenge), clientChallenge...)
+	}
+
+	// The key exchange key is the session base key with NTLMv2.
+	sessionKey := hmacMD5(ntowf, ntProof)
+	var encryptedSessionKey []byte
+	if flags&ntlmNegotiateKeyExch != 0 {
+		exportedSessionKey := make([]byte, 16)
+		if _, err := io.ReadFull(c.rand, exportedSessionKey); err != nil {
+			return nil, nil, err
+		}
+		cipher, err := rc4.NewCipher(sessionKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		encryptedSessionKey = make([]byte, 16)
+		cipher.XORKeyStream(encryptedSessionKey, exportedSessionKey)
+		sessionKey = exportedSessionKey
+	}
+
+	const headerLen = 88
+	payloads := [][]byte{lmResponse, ntResponse, encodeUTF16(domain), encodeUTF16(c.user), nil, encryptedSessionKey}
+	msg := make([]byte, headerLen)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+	offset := headerLen
+	for i, payload := range payloads {
+		field := msg[12+8*i:]
+		binary.LittleEndian.PutUint16(field, uint16(len(payload)))
+		binary.LittleEndian.PutUint16(field[2:], uint16(len(payload)))
+		binary.LittleEndian.PutUint32(field[4:], uint32(offset))
+		msg = append(msg, payload...)
+		offset += len(payload)
+	}
+	binary.LittleEndian.PutUint32(msg[60:], flags)
+	copy(msg[64:], ntlmVersion)
+	// The MIC covers all the messages of the authentication, with the MIC zeroed.
+	copy(msg[72:], hmacMD5(sessionKey, c.negotiateMessage, challenge, msg))
+
+	if flags&ntlmNegotiateSeal == 0 {
+		return msg, nil, nil
+	}
+	session, err := newNTLMSession(sessionKey, true)
+	return msg, session, err
+}
+
+// targetInfo returns the AV pairs of the server, to which are added the flags telling there's a MIC,
+// the service principal name and the channel bindings.
+func (c *ntlmClient) targetInfo(serverPairs avPairs) []byte {
+	var pairs avPairs
+	avFlags := uint32(ntlmAvFlagMICPresent)
+	for _, pair := range serverPairs {
+		switch pair.id {
+		case ntlmAvEOL, ntlmAvTargetName, ntlmAvChannelBindings:
+		case ntlmAvFlags:
+			if len(pair.value) == 4 {
+				avFlags |= binary.LittleEndian.Uint32(pair.value)
+			}
+		default:
+			pairs = append(pairs, pair)
+		}
+	}
+	pairs = append(pairs, avPair{id: ntlmAvFlags, value: binary.LittleEndian.AppendUint32(nil, avFlags)})
+	if c.targetName != "" {
+		pairs = append(pairs, avPair{id: ntlmAvTargetName, value: encodeUTF16(c.targetName)})
+	}
+	channelBindings := c.channelBindings
+	if channelBindings == nil {
+		channelBindings = make([]byte, 16)
+	}
+	pairs = append(pairs, avPair{id: ntlmAvChannelBindings, value: channelBindings})
+	return pairs.encode()
+}
+
+// ntlmSession signs and seals messages with the keys of an NTLM authentication (MS-NLMP 3.4).
+type ntlmSession struct {
+	sendSigningKey []byte
+	recvSigningKey []byte
+	sendHandle     *rc4.Cipher
+	recvHandle     *rc4.Cipher
+	sendSeq        uint32
+	recvSeq        uint32
+}
+
+// newNTLMSession returns the session of the client, or of the server for tests.
+func newNTLMSession(exportedSessionKey []byte, client bool) (*ntlmSession, error) {
+	clientSigningKey := md5Sum(exportedSessionKey, []byte("session key to client-to-server signing key magic constant\x00"))
+	serverSigningKey := md5Sum(exportedSessionKey, []byte("session key to server-to-client signing key magic constant\x00"))
+	clientSealingKey := md5Sum(exportedSessionKey, []byte("session key to client-to-server sealing key magic constant\x00"))
+	serverSealingKey := md5Sum(exportedSessionKey, []byte("session key to server-to-client sealing key magic constant\x00"))
+	if !client {
+		clientSigningKey, serverSigningKey = serverSigningKey, clientSigningKey
+		clientSealingKey, serverSealingKey = serverSealingKey, clientSealingKey
+	}
+	sendHandle, err := rc4.NewCipher(clientSealingKey)
+	if err != nil {
+		return nil, err
+	}
+	recvHandle, err := rc4.NewCipher(serverSealingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &ntlmSession{
+		sendSigningKey: clientSigningKey,
+		recvSigningKey: serverSigningKey,
+		sendHandle:     sendHandle,
+		recvHandle:     recvHandle,
+	}, nil
+}
+
+// seal encrypts a message and returns it prefixed with its signature.
+func (s *ntlmSession) seal(msg []byte) []byte {
+	sealed := make([]byte, 16+len(msg))
+	s.sendHandle.XORKeyStream(sealed[16:], msg)
+	copy(sealed, ntlmMessageSignature(s.sendSigningKey, s.sendHandle, s.sendSeq, msg))
+	s.sendSeq++
+	return sealed
+}
+
+// unseal decrypts a message prefixed with its signature, and verifies the signature.
+func (s *ntlmSession) unseal(sealed []byte) ([]byte, error) {
+	if len(sealed) < 16 {
+		return nil, errors.New("ntlm: sealed message too short")
+	}
+	msg := make([]byte, len(sealed)-16)
+	s.recvHandle.XORKeyStream(msg, sealed[16:])
+	signature := ntlmMessageSignature(s.recvSigningKey, s.recvHandle, s.recvSeq, msg)
+	s.recvSeq++
+	if !hmac.Equal(signature, sealed[:16]) {
+		return nil, errors.New("ntlm: invalid message signature")
+	}
+	return msg, nil
+}
+
+// ntlmMessageSignature returns the signature of a message with extended session security and key exchange.
+func ntlmMessageSignature(signingKey []byte, handle *rc4.Cipher, seq uint32, msg []byte) []byte {
+	seqNum := binary.LittleEndian.AppendUint32(nil, seq)
+	checksum := hmacMD5(signingKey, seqNum, msg)[:8]
+	handle.XORKeyStream(checksum, checksum)
+
+	signature := binary.LittleEndian.AppendUint32(nil, 1)
+	signature = append(signature, checksum...)
+	return append(signature, seqNum...)
+}
+
+// tlsChannelBindings returns the hash of the gss_channel_bindings_struct (RFC 2744) holding the tls-server-end-point
+// channel binding (RFC 5929) of a TLS connection, as sent in the MsvAvChannelBindings AV pair.
+func tlsChannelBindings(state tls.ConnectionState) ([]byte, error) {
+	if len(state.PeerCertificates) == 0 {
+		return nil, errors.New("ntlm: no server certificate to bind to")
+	}
+	cert := state.PeerCertificates[0]
+
+	// The hash of the certificate signature is used, MD5 and SHA-1 being replaced with SHA-256.
+	var h hash.Hash
+	switch cert.SignatureAlgorithm {
+	case x509.SHA384WithRSA, x509.ECDSAWithSHA384, x509.SHA384WithRSAPSS:
+		h = sha512.New384()
+	case x509.SHA512WithRSA, x509.ECDSAWithSHA512, x509.SHA512WithRSAPSS:
+		h = sha512.New()
+	default:
+		h = sha256.New()
+	}
+	h.Write(cert.Raw)
+	applicationData := append([]byte("tls-server-end-point:"), h.Sum(nil)...)
+
+	// The initiator and acceptor addresses are empty.
+	bindings := make([]byte, 16)
+	bindings = binary.LittleEndian.AppendUint32(bindings, uint32(len(applicationData)))
+	bindings = append(bindings, applicationData...)
+	return md5Sum(bindings), nil
+}
+
+type avPair struct {
+	id    uint16
+	value []byte
+}
+
+type avPairs []avPair
+
+func parseAVPairs(data []byte) (avPairs, error) {
+	var pairs avPairs
+	for len(data) >= 4 {
+		id := binary.LittleEndian.Uint16(data)
+		length := int(binary.LittleEndian.Uint16(data[2:]))
+		if len(data) < 4+length {
+			break
+		}
+		if id == ntlmAvEOL {
+			return pairs, nil
+		}
+		pairs = append(pairs, avPair{id: id, value: data[4 : 4+length]})
+		data = data[4+length:]
+	}
+	return nil, errors.New("ntlm: invalid target info")
+}
+
+func (p avPairs) get(id uint16) []byte {
+	for _, pair := range p {
+		if pair.id == id {
+			return pair.value
+		}
+	}
+	return nil
+}
+
+// encode returns the AV pairs terminated by MsvAvEOL.
+func (p avPairs) encode() []byte {
+	var data []byte
+	for _, pair := range append(p, avPair{id: ntlmAvEOL}) {
+		data = binary.LittleEndian.AppendUint16(data, pair.id)
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(pair.value)))
+		data = append(data, pair.value...)
+	}
+	return data
+}
+
+// ntlmField returns the payload of the message field whose length and offset are at the position.
+func ntlmField(msg []byte, position int) ([]byte, error) {
+	length := int(binary.LittleEndian.Uint16(msg[position:]))
+	offset := int(binary.LittleEndian.Uint32(msg[position+4:]))
+	if offset+length > len(msg) {
+		return nil, fmt.Errorf("ntlm: invalid field at %d", position)
+	}
+	return msg[offset : offset+length], nil
+}
+
+// ntlmv2Temp returns the NTLMv2 client challenge structure hashed in the response.
+func ntlmv2Temp(timestamp, clientChallenge, targetInfo []byte) []byte {
+	temp := []byte{1, 1, 0, 0, 0, 0, 0, 0}
+	temp = append(temp, timestamp...)
+	temp = append(temp, clientChallenge...)
+	temp = append(temp, 0, 0, 0, 0)
+	temp = append(temp, targetInfo...)
+	return append(temp, 0, 0, 0, 0)
+}
+
+func ntowfv2(user, password, domain string) []byte {
+	h := md4.New()
+	h.Write(encodeUTF16(password))
+	return hmacMD5(h.Sum(nil), encodeUTF16(strings.ToUpper(user)+domain))
+}
+
+// fileTime returns the time as a Windows FILETIME, in 100ns intervals since January 1, 1601.
+func fileTime(t time.Time) []byte {
+	const epochDelta = 116444736000000000
+	return binary.LittleEndian.AppendUint64(nil, uint64(t.UnixNano()/100+epochDelta))
+}
+
+func hmacMD5(key []byte, data ...[]byte) []byte {
+	h := hmac.New(md5.New, key)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func md5Sum(data ...[]byte) []byte {
+	h := md5.New()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func encodeUTF16(s string) []byte {
+	var b []byte
+	for _, r := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, r)
+	}
+	return b
+}
+
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
@@ -0,0 +1,259 @@
+package ldap
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	ldapv3 "github.com/go-ldap/ldap/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// maxSealedMessageSize bounds the size of the sealed messages read from the server.
+const maxSealedMessageSize = 16 << 20
+
+// NTLMConn is an LDAP connection binding with NTLM, as required by Active Directory domain controllers enforcing
+// LDAP channel binding (LdapEnforceChannelBinding) or signing (LDAPServerIntegrity).
+// Over TLS, binds carry the channel binding token of the connection. Without TLS, the messages are signed
+// and sealed once bound.
+// A signed connection can't be bound again, so each bind is done on a new connection to the same server,
+// which replaces the current one.
+type NTLMConn struct {
+	*ldapv3.Conn
+
+	server   string
+	port     int64
+	tls      bool
+	startTLS bool
+	timeout  time.Duration
+	caPool   *x509.CertPool
+}
+
+// NewNTLMConn connects to the first of the servers accepting connections.
+func NewNTLMConn(servers []string, TLS, startTLS bool, port int64, connectionTimeout int64, caPool *x509.CertPool) (*NTLMConn, error) {
+	logrus.Debug("Now creating Ldap connection binding with NTLM")
+	if len(servers) < 1 {
+		return nil, errors.New("ldap: invalid server config. at least 1 server needs to be configured")
+	}
+
+	var err error
+	for _, server := range servers {
+		c := &NTLMConn{
+			server:   server,
+			port:     port,
+			tls:      TLS,
+			startTLS: startTLS,
+			timeout:  time.Duration(connectionTimeout) * time.Millisecond,
+			caPool:   caPool,
+		}
+		var conn net.Conn
+		if conn, err = c.dial(); err == nil {
+			c.Conn = c.start(conn)
+			return c, nil
+		}
+	}
+
+	return nil, err
+}
+
+// Bind binds with NTLM on a new connection to the server. The username is either DOMAIN\user or a user principal name.
+func (c *NTLMConn) Bind(username, password string) error {
+	if password == "" {
+		return ldapv3.NewError(ldapv3.ErrorEmptyPassword, errors.New("ldap: empty password not allowed by the client"))
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return ldapv3.NewError(ldapv3.ErrorNetwork, err)
+	}
+
+	var channelBindings []byte
+	tlsConn, isTLS := conn.(*tls.Conn)
+	if isTLS {
+		if channelBindings, err = tlsChannelBindings(tlsConn.ConnectionState()); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	client := newNTLMClient(username, password, "ldap/"+c.server, channelBindings, !isTLS)
+
+	session, err := c.bind(conn, client)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if session != nil {
+		conn = &sealedConn{Conn: conn, session: session}
+	}
+
+	c.Conn.Close()
+	c.Conn = c.start(conn)
+	return nil
+}
+
+// bind runs the NTLM bind with the Sicily bind requests of Active Directory, on the connection not yet handed to an ldapv3.Conn.
+func (c *NTLMConn) bind(conn net.Conn, client *ntlmClient) (*ntlmSession, error) {
+	if c.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(c.timeout))
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	response, err := roundTrip(conn, 1, sicilyBindRequest(ber.TagEnumerated, client.negotiate()))
+	if err != nil {
+		return nil, err
+	}
+	// The challenge is sent in the matched DN of the response.
+	var challenge []byte
+	if bindResponse := response.Children[1]; len(bindResponse.Children) >= 3 {
+		challenge = bindResponse.Children[1].ByteValue
+	}
+	if !bytes.HasPrefix(challenge, ntlmSignature) {
+		if err := ldapv3.GetLDAPError(response); err != nil {
+			return nil, err
+		}
+		return nil, ldapv3.NewError(ldapv3.ErrorUnexpectedResponse, errors.New("ldap: no NTLM challenge in the bind response"))
+	}
+
+	authenticate, session, err := client.authenticate(challenge)
+	if err != nil {
+		return nil, err
+	}
+	response, err = roundTrip(conn, 2, sicilyBindRequest(ber.TagEmbeddedPDV, authenticate))
+	if err != nil {
+		return nil, err
+	}
+	if err := ldapv3.GetLDAPError(response); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// dial connects to the server, with TLS if configured.
+func (c *NTLMConn) dial() (net.Conn, error) {
+	addr := net.JoinHostPort(c.server, strconv.FormatInt(c.port, 10))
+	dialer := &net.Dialer{Timeout: c.timeout}
+	tlsConfig := &tls.Config{RootCAs: c.caPool, ServerName: c.server}
+
+	if c.tls {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("ldap: error creating ssl connection: %w", err)
+		}
+		return conn, nil
+	}
+
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: error creating connection: %w", err)
+	}
+	if !c.startTLS {
+		return conn, nil
+	}
+
+	if c.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+	request := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapv3.ApplicationExtendedRequest, nil, "Start TLS")
+	request.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, "1.3.6.1.4.1.1466.20037", "TLS Extended Command"))
+	response, err := roundTrip(conn, 1, request)
+	if err == nil {
+		err = ldapv3.GetLDAPError(response)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ldap: error upgrading startTLS connection: %w", err)
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ldap: error upgrading startTLS connection: %w", err)
+	}
+	conn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
+func (c *NTLMConn) start(conn net.Conn) *ldapv3.Conn {
+	lConn := ldapv3.NewConn(conn, c.tls || c.startTLS)
+	lConn.Start()
+	lConn.SetTimeout(c.timeout)
+	return lConn
+}
+
+func sicilyBindRequest(tag ber.Tag, message []byte) *ber.Packet {
+	request := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapv3.ApplicationBindRequest, nil, "Bind Request")
+	request.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 3, "Version"))
+	request.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "User Name"))
+	request.AppendChild(ber.Encode(ber.ClassContext, ber.TypePrimitive, tag, message, "authentication"))
+	return request
+}
+
+// roundTrip sends a request and reads its response, on a connection not yet handed to an ldapv3.Conn.
+func roundTrip(conn net.Conn, messageID int64, request *ber.Packet) (*ber.Packet, error) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Request")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	packet.AppendChild(request)
+	if _, err := conn.Write(packet.Bytes()); err != nil {
+		return nil, ldapv3.NewError(ldapv3.ErrorNetwork, err)
+	}
+
+	response, err := ber.ReadPacket(conn)
+	if err != nil {
+		return nil, ldapv3.NewError(ldapv3.ErrorNetwork, err)
+	}
+	if len(response.Children) < 2 {
+		return nil, ldapv3.NewError(ldapv3.ErrorUnexpectedResponse, errors.New("ldap: invalid response"))
+	}
+	return response, nil
+}
+
+// sealedConn is the SASL security layer (RFC 4422) of a connection bound with NTLM without TLS:
+// the messages are signed and sealed, and sent as buffers prefixed with their length.
+// The ldapv3.Conn writes whole messages from a single goroutine and reads from another,
+// so writes and reads don't need to be synchronized.
+type sealedConn struct {
+	net.Conn
+	session *ntlmSession
+	unread  []byte
+}
+
+func (c *sealedConn) Write(p []byte) (int, error) {
+	sealed := c.session.seal(p)
+	buf := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(sealed)), uint32(len(sealed)))
+	if _, err := c.Conn.Write(append(buf, sealed...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *sealedConn) Read(p []byte) (int, error) {
+	if len(c.unread) == 0 {
+		var length [4]byte
+		if _, err := io.ReadFull(c.Conn, length[:]); err != nil {
+			return 0, err
+		}
+		size := binary.BigEndian.Uint32(length[:])
+		if size > maxSealedMessageSize {
+			return 0, fmt.Errorf("ldap: sealed message of %d bytes exceeds the maximum size", size)
+		}
+		sealed := make([]byte, size)
+		if _, err := io.ReadFull(c.Conn, sealed); err != nil {
+			return 0, err
+		}
+		msg, err := c.session.unseal(sealed)
+		if err != nil {
+			return 0, err
+		}
+		c.unread = msg
+	}
+	n := copy(p, c.unread)
+	c.unread = c.unread[n:]
+	return n, nil
+}
@@ -0,0 +1,214 @@
+package ldap
+
+import (
+	"bytes"
+	"crypto/rc4"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// TestNTLMv2Vectors checks the responses with the NTLMv2 authentication example of MS-NLMP 4.2.4.
+func TestNTLMv2Vectors(t *testing.T) {
+	ntowf := ntowfv2("User", "Password", "Domain")
+	assert.Equal(t, mustDecodeHex(t, "0c868a403bfd7a93a3001ef22ef02e3f"), ntowf)
+
+	serverChallenge := mustDecodeHex(t, "0123456789abcdef")
+	clientChallenge := mustDecodeHex(t, "aaaaaaaaaaaaaaaa")
+	targetInfo := avPairs{
+		{id: ntlmAvNbDomainName, value: encodeUTF16("Domain")},
+		{id: 0x0001, value: encodeUTF16("Server")},
+	}.encode()
+	temp := ntlmv2Temp(make([]byte, 8), clientChallenge, targetInfo)
+
+	ntProof := hmacMD5(ntowf, serverChallenge, temp)
+	assert.Equal(t, mustDecodeHex(t, "68cd0ab851e51c96aabc927bebef6a1c"), ntProof)
+	assert.Equal(t, mustDecodeHex(t, "8de40ccadbc14a82f15cb0ad0de95ca3"), hmacMD5(ntowf, ntProof))
+	assert.Equal(t, mustDecodeHex(t, "86c35097ac9cec102554764a57cccc19"), hmacMD5(ntowf, serverChallenge, clientChallenge))
+}
+
+// TestNTLMSealVector checks sealing with the example of MS-NLMP 4.2.4.4.
+func TestNTLMSealVector(t *testing.T) {
+	session, err := newNTLMSession(bytes.Repeat([]byte{0x55}, 16), true)
+	require.NoError(t, err)
+
+	sealed := session.seal(encodeUTF16("Plaintext"))
+	assert.Equal(t, mustDecodeHex(t, "010000007fb38ec5c55d497600000000"), sealed[:16])
+	assert.Equal(t, mustDecodeHex(t, "54e50165bf1936dc996020c1811b0f06fb5f"), sealed[16:])
+}
+
+func TestNTLMSessionRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 16)
+	client, err := newNTLMSession(key, true)
+	require.NoError(t, err)
+	server, err := newNTLMSession(key, false)
+	require.NoError(t, err)
+
+	for _, msg := range []string{"first", "second"} {
+		unsealed, err := server.unseal(client.seal([]byte(msg)))
+		require.NoError(t, err)
+		assert.Equal(t, msg, string(unsealed))
+
+		unsealed, err = client.unseal(server.seal([]byte(msg)))
+		require.NoError(t, err)
+		assert.Equal(t, msg, string(unsealed))
+	}
+
+	sealed := client.seal([]byte("tampered"))
+	sealed[len(sealed)-1] ^= 0xff
+	_, err = server.unseal(sealed)
+	assert.Error(t, err)
+}
+
+// testChallenge returns a CHALLENGE_MESSAGE of a server supporting all the flags.
+func testChallenge(serverChallenge []byte, pairs avPairs) []byte {
+	targetInfo := pairs.encode()
+	msg := make([]byte, 56)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 2)
+	binary.LittleEndian.PutUint32(msg[16:], 56)
+	binary.LittleEndian.PutUint32(msg[20:], 0xffffffff)
+	copy(msg[24:], serverChallenge)
+	binary.LittleEndian.PutUint16(msg[40:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint16(msg[42:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint32(msg[44:], 56)
+	return append(msg, targetInfo...)
+}
+
+func TestNTLMAuthenticate(t *testing.T) {
+	serverChallenge := mustDecodeHex(t, "0123456789abcdef")
+	timestamp := fileTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	challenge := testChallenge(serverChallenge, avPairs{
+		{id: ntlmAvNbDomainName, value: encodeUTF16("EXAMPLE")},
+		{id: ntlmAvTimestamp, value: timestamp},
+	})
+	channelBindings := bytes.Repeat([]byte{0xcb}, 16)
+
+	client := newNTLMClient("EXAMPLE\\svc-rancher", "Password", "ldap/dc1.example.com", channelBindings, true)
+	client.rand = bytes.NewReader(bytes.Repeat([]byte{0xaa}, 24))
+	negotiate := client.negotiate()
+	authenticate, session, err := client.authenticate(challenge)
+	require.NoError(t, err)
+	require.NotNil(t, session)
+
+	field := func(position int) []byte {
+		value, err := ntlmField(authenticate, position)
+		require.NoError(t, err)
+		return value
+	}
+	assert.Equal(t, make([]byte, 24), field(12), "no LM response when the server sends a timestamp")
+	assert.Equal(t, "EXAMPLE", decodeUTF16(field(28)))
+	assert.Equal(t, "svc-rancher", decodeUTF16(field(36)))
+
+	// The server verifies the NT response.
+	ntowf := ntowfv2("svc-rancher", "Password", "EXAMPLE")
+	ntResponse := field(20)
+	ntProof, temp := ntResponse[:16], ntResponse[16:]
+	assert.Equal(t, hmacMD5(ntowf, serverChallenge, temp), ntProof)
+	assert.Equal(t, timestamp, temp[8:16])
+
+	pairs, err := parseAVPairs(temp[28:])
+	require.NoError(t, err)
+	assert.Equal(t, channelBindings, pairs.get(ntlmAvChannelBindings))
+	assert.Equal(t, "ldap/dc1.example.com", decodeUTF16(pairs.get(ntlmAvTargetName)))
+	assert.Equal(t, uint32(ntlmAvFlagMICPresent), binary.LittleEndian.Uint32(pairs.get(ntlmAvFlags)))
+
+	// The server recovers the exported session key to verify the MIC and unseal the messages.
+	cipher, err := rc4.NewCipher(hmacMD5(ntowf, ntProof))
+	require.NoError(t, err)
+	exportedSessionKey := make([]byte, 16)
+	cipher.XORKeyStream(exportedSessionKey, field(52))
+
+	mic := append([]byte{}, authenticate[72:88]...)
+	zeroed := append([]byte{}, authenticate...)
+	copy(zeroed[72:88], make([]byte, 16))
+	assert.Equal(t, hmacMD5(exportedSessionKey, negotiate, challenge, zeroed), mic)
+
+	server, err := newNTLMSession(exportedSessionKey, false)
+	require.NoError(t, err)
+	unsealed, err := server.unseal(session.seal([]byte("search")))
+	require.NoError(t, err)
+	assert.Equal(t, "search", string(unsealed))
+}
+
+func TestNTLMAuthenticateOverTLS(t *testing.T) {
+	challenge := testChallenge(make([]byte, 8), avPairs{{id: ntlmAvNbDomainName, value: encodeUTF16("EXAMPLE")}})
+
+	client := newNTLMClient("svc-rancher@example.com", "Password", "ldap/dc1.example.com", bytes.Repeat([]byte{0xcb}, 16), false)
+	client.negotiate()
+	authenticate, session, err := client.authenticate(challenge)
+	require.NoError(t, err)
+	assert.Nil(t, session, "sealing isn't negotiated over TLS")
+	assert.Zero(t, binary.LittleEndian.Uint32(authenticate[60:])&(ntlmNegotiateSign|ntlmNegotiateSeal))
+
+	domain, err := ntlmField(authenticate, 28)
+	require.NoError(t, err)
+	assert.Empty(t, domain, "user principal names carry their domain")
+
+	lmResponse, err := ntlmField(authenticate, 12)
+	require.NoError(t, err)
+	assert.Len(t, lmResponse, 24)
+	assert.NotEqual(t, make([]byte, 24), lmResponse, "LMv2 response without server timestamp")
+}
+
+func TestTLSChannelBindings(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("certificate"), SignatureAlgorithm: x509.SHA256WithRSA}
+	bindings, err := tlsChannelBindings(tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}})
+	require.NoError(t, err)
+	assert.Len(t, bindings, 16)
+
+	other := &x509.Certificate{Raw: []byte("certificate"), SignatureAlgorithm: x509.SHA384WithRSA}
+	otherBindings, err := tlsChannelBindings(tls.ConnectionState{PeerCertificates: []*x509.Certificate{other}})
+	require.NoError(t, err)
+	assert.NotEqual(t, bindings, otherBindings)
+
+	_, err = tlsChannelBindings(tls.ConnectionState{})
+	assert.Error(t, err)
+}
+
+func TestSealedConn(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 16)
+	clientSession, err := newNTLMSession(key, true)
+	require.NoError(t, err)
+	serverSession, err := newNTLMSession(key, false)
+	require.NoError(t, err)
+
+	clientPipe, serverPipe := net.Pipe()
+	defer clientPipe.Close()
+	defer serverPipe.Close()
+	client := &sealedConn{Conn: clientPipe, session: clientSession}
+	server := &sealedConn{Conn: serverPipe, session: serverSession}
+
+	go func() {
+		buf := make([]byte, 64)
+		n, err := server.Read(buf)
+		if err != nil {
+			return
+		}
+		server.Write(bytes.ToUpper(buf[:n]))
+	}()
+
+	_, err = client.Write([]byte("request"))
+	require.NoError(t, err)
+	// The response is read in several calls, as ber.ReadPacket does.
+	first := make([]byte, 3)
+	_, err = client.Read(first)
+	require.NoError(t, err)
+	rest := make([]byte, 16)
+	n, err := client.Read(rest)
+	require.NoError(t, err)
+	assert.Equal(t, "REQUEST", string(first)+string(rest[:n]))
+}
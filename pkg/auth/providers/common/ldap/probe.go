@@ -0,0 +1,18 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+)
+
+// ProbeServers returns whether each LDAP server accepts connections on the port.
+func ProbeServers(ctx context.Context, servers []string, port int64) []v3.AuthConfigEndpointStatus {
+	endpoints := make([]v3.AuthConfigEndpointStatus, 0, len(servers))
+	for _, server := range servers {
+		endpoints = append(endpoints, common.ProbeAddress(ctx, "server", fmt.Sprintf("%s:%d", server, port)))
+	}
+	return endpoints
+}
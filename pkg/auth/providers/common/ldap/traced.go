@@ -0,0 +1,54 @@
+package ldap
+
+import (
+	"context"
+
+	ldapv3 "github.com/go-ldap/ldap/v3"
+	"github.com/rancher/rancher/pkg/auth/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedConn traces the binds and searches of an LDAP connection as children of the span of its context.
+type tracedConn struct {
+	ldapv3.Client
+	ctx context.Context
+}
+
+// Traced returns the connection tracing its binds and searches as children of the span of the context.
+func Traced(ctx context.Context, lConn ldapv3.Client) ldapv3.Client {
+	return &tracedConn{Client: lConn, ctx: ctx}
+}
+
+func (c *tracedConn) Bind(username, password string) (err error) {
+	_, span := tracing.StartSpan(c.ctx, "ldap.bind")
+	defer func() { tracing.End(span, err) }()
+	return c.Client.Bind(username, password)
+}
+
+func (c *tracedConn) Search(searchRequest *ldapv3.SearchRequest) (result *ldapv3.SearchResult, err error) {
+	_, span := tracing.StartSpan(c.ctx, "ldap.search", searchAttributes(searchRequest)...)
+	defer func() { endSearch(span, result, err) }()
+	return c.Client.Search(searchRequest)
+}
+
+func (c *tracedConn) SearchWithPaging(searchRequest *ldapv3.SearchRequest, pagingSize uint32) (result *ldapv3.SearchResult, err error) {
+	_, span := tracing.StartSpan(c.ctx, "ldap.search", append(searchAttributes(searchRequest), attribute.Int64("ldap.paging_size", int64(pagingSize)))...)
+	defer func() { endSearch(span, result, err) }()
+	return c.Client.SearchWithPaging(searchRequest, pagingSize)
+}
+
+// searchAttributes doesn't include the filter, which can contain the names of the users.
+func searchAttributes(searchRequest *ldapv3.SearchRequest) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("ldap.base_dn", searchRequest.BaseDN),
+		attribute.Int("ldap.scope", searchRequest.Scope),
+	}
+}
+
+func endSearch(span trace.Span, result *ldapv3.SearchResult, err error) {
+	if result != nil {
+		span.SetAttributes(attribute.Int("ldap.entries", len(result.Entries)))
+	}
+	tracing.End(span, err)
+}
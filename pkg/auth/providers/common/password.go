@@ -5,6 +5,7 @@
 	"reflect"
 	"strings"
 
+	"github.com/rancher/rancher/pkg/auth/externalsecrets"
 	"github.com/rancher/rancher/pkg/namespace"
 	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -29,11 +30,16 @@ func NameForSecret(s *v1.Secret) string {
 // In the event that the Secret already exists, if the .Data doesn't match the
 // desired state it is overwritten.
 //
-// It returns a string with the namespace:name of the created Secret.
+// It returns a string with the namespace:name of the created Secret. References
+// to secrets in external stores are returned as they are, without creating a Secret.
 func CreateOrUpdateSecrets(secrets wcorev1.SecretController, secretInfo, field, authType string) (string, error) {
 	if secretInfo == "" {
 		return "", nil
 	}
+	// References to external stores are kept in the config and resolved at use time.
+	if externalsecrets.IsReference(secretInfo) {
+		return secretInfo, nil
+	}
 
 	name := fmt.Sprintf("%s-%s", authType, field)
 	secret := &v1.Secret{
@@ -66,6 +72,9 @@ func CreateOrUpdateSecrets(secrets wcorev1.SecretController, secretInfo, field,
 }
 
 func ReadFromSecret(secrets wcorev1.SecretController, secretInfo string, field string) (string, error) {
+	if externalsecrets.IsReference(secretInfo) {
+		return externalsecrets.Resolve(secretInfo)
+	}
 	if strings.HasPrefix(secretInfo, SecretsNamespace) {
 		data, err := ReadFromSecretData(secrets, secretInfo)
 		if err != nil {
@@ -103,3 +103,15 @@ func TestSavePasswordSecret(t *testing.T) {
 	assert.Equal(t, wantSecret.Namespace+":"+wantSecret.Name, name)
 	assert.Equal(t, wantSecret, createdSecret)
 }
+
+func TestSavePasswordSecretExternalReference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	// No secret must be created for references to external stores.
+	secretController := wranglerfake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+
+	name, err := SavePasswordSecret(secretController, "vault://secret/data/ldap#password",
+		clientv3.LdapConfigFieldServiceAccountPassword,
+		"shibbolethConfig")
+	assert.NoError(t, err)
+	assert.Equal(t, "vault://secret/data/ldap#password", name)
+}
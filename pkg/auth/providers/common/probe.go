@@ -0,0 +1,86 @@
+package common
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+)
+
+// ProbeTimeout bounds how long probes wait for an endpoint of an identity service.
+const ProbeTimeout = 10 * time.Second
+
+// ProbeURL returns whether the identity service answers on the URL of an endpoint.
+// Any HTTP response counts, since endpoints like token endpoints reject requests without credentials.
+func ProbeURL(ctx context.Context, client *http.Client, name, url string) v3.AuthConfigEndpointStatus {
+	status := v3.AuthConfigEndpointStatus{Name: name, URL: url}
+
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	resp.Body.Close()
+
+	status.Reachable = true
+	return status
+}
+
+// ProbeAddress returns whether the identity service accepts TCP connections on the host:port address of an endpoint.
+func ProbeAddress(ctx context.Context, name, address string) v3.AuthConfigEndpointStatus {
+	status := v3.AuthConfigEndpointStatus{Name: name, URL: address}
+
+	dialer := net.Dialer{Timeout: ProbeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	conn.Close()
+
+	status.Reachable = true
+	return status
+}
+
+// CertificateExpiries returns the expiry of each certificate of a PEM bundle.
+// Blocks that aren't certificates are skipped.
+func CertificateExpiries(name, bundle string) []v3.AuthConfigCredentialStatus {
+	var credentials []v3.AuthConfigCredentialStatus
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return credentials
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		credentials = append(credentials, CertificateExpiry(name, cert))
+	}
+}
+
+// CertificateExpiry returns the expiry of a certificate.
+func CertificateExpiry(name string, cert *x509.Certificate) v3.AuthConfigCredentialStatus {
+	return v3.AuthConfigCredentialStatus{
+		Name:      name,
+		Subject:   cert.Subject.String(),
+		ExpiresAt: cert.NotAfter.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCertificatePEM(t *testing.T, commonName string, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCertificateExpiries(t *testing.T) {
+	first := newCertificatePEM(t, "first", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	second := newCertificatePEM(t, "second", time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+	key := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")}))
+
+	assert.Equal(t, []v3.AuthConfigCredentialStatus{
+		{Name: "certificate", Subject: "CN=first", ExpiresAt: "2030-01-01T00:00:00Z"},
+		{Name: "certificate", Subject: "CN=second", ExpiresAt: "2031-01-01T00:00:00Z"},
+	}, CertificateExpiries("certificate", first+key+second))
+	assert.Empty(t, CertificateExpiries("certificate", ""))
+	assert.Empty(t, CertificateExpiries("certificate", "not a certificate"))
+}
+
+func TestProbeURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	status := ProbeURL(context.Background(), server.Client(), "token", server.URL)
+	assert.Equal(t, v3.AuthConfigEndpointStatus{Name: "token", URL: server.URL, Reachable: true}, status)
+
+	server.Close()
+	status = ProbeURL(context.Background(), server.Client(), "token", server.URL)
+	assert.False(t, status.Reachable)
+	assert.NotEmpty(t, status.Error)
+}
+
+func TestProbeAddress(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	status := ProbeAddress(context.Background(), "server", address)
+	assert.Equal(t, v3.AuthConfigEndpointStatus{Name: "server", URL: address, Reachable: true}, status)
+
+	listener.Close()
+	status = ProbeAddress(context.Background(), "server", address)
+	assert.False(t, status.Reachable)
+	assert.NotEmpty(t, status.Error)
+}
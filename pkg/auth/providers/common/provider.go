@@ -47,3 +47,46 @@ type AuthProvider interface {
 	// forced. If "logout-all" is not supported by the provider do nothing and return nil.
 	Logout(apiContext *types.APIContext, token accessor.TokenAccessor) error
 }
+
+// HealthChecker is implemented by providers that can probe the availability of the identity service they rely on.
+// Providers that don't implement it are assumed to be available.
+type HealthChecker interface {
+	// HealthCheck returns an error if the identity service of the provider can't be reached.
+	HealthCheck() error
+}
+
+// Prober is implemented by providers that can report the connectivity details of their identity service,
+// which the probe daemon publishes in the status of their auth config.
+type Prober interface {
+	// Probe returns the reachability of the endpoints of the identity service, the expiry of the certificates
+	// and secrets of the provider and the freshness of the metadata of the identity service.
+	Probe(ctx context.Context) (*v3.AuthConfigConnectivity, error)
+}
+
+// UserLookup is implemented by providers that can look up whether their identity service still knows a user.
+// Users of providers that don't implement it are never deprovisioned.
+type UserLookup interface {
+	// UserExists returns false if the identity service of the provider no longer knows the user principal.
+	UserExists(principalID string) (bool, error)
+}
+
+// GroupLookup is implemented by providers that can look up group principals without the token of a user.
+// Display names of groups of providers that don't implement it aren't refreshed.
+type GroupLookup interface {
+	// LookupGroup returns the current group principal from the identity service of the provider.
+	LookupGroup(principalID string) (v3.Principal, error)
+}
+
+// GroupMemberLister is implemented by providers that can list the members of a group principal without the token of a user.
+type GroupMemberLister interface {
+	// ListGroupMembers returns a page of the user principals that are direct members of the group principal,
+	// and the continue token of the next page, which is empty on the last page.
+	ListGroupMembers(principalID string, limit int, continueToken string) ([]v3.Principal, string, error)
+}
+
+// UserAttributeSearcher is implemented by providers that can search their users by a directory attribute without the
+// token of a user.
+type UserAttributeSearcher interface {
+	// SearchUsersByAttribute returns the user principals whose attribute holds the value.
+	SearchUsersByAttribute(attribute, value string) ([]v3.Principal, error)
+}
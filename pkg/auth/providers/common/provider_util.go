@@ -3,6 +3,8 @@
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
@@ -56,9 +58,46 @@ func GetCommonUserExtraAttributes(userPrincipal v3.Principal) map[string][]strin
 	if userPrincipal.LoginName != "" {
 		extras[UserAttributeUserName] = []string{userPrincipal.LoginName}
 	}
+	// The extra info of the principal holds the provider attributes mapped to user labels.
+	for key, value := range userPrincipal.ExtraInfo {
+		if _, ok := extras[key]; !ok && value != "" {
+			extras[key] = []string{value}
+		}
+	}
 	return extras
 }
 
+// PagePrincipals returns the page of the principals, sorted by ID, starting at the offset held by the continue token,
+// and the continue token of the next page, which is empty on the last page.
+func PagePrincipals(principals []v3.Principal, limit int, continueToken string) ([]v3.Principal, string, error) {
+	offset := 0
+	if continueToken != "" {
+		var err error
+		offset, err = strconv.Atoi(continueToken)
+		if err != nil || offset < 0 {
+			return nil, "", fmt.Errorf("invalid continue token %q", continueToken)
+		}
+	}
+
+	sort.Slice(principals, func(i, j int) bool {
+		return principals[i].Name < principals[j].Name
+	})
+
+	if offset >= len(principals) {
+		return []v3.Principal{}, "", nil
+	}
+	end := len(principals)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	var next string
+	if end < len(principals) {
+		next = strconv.Itoa(end)
+	}
+	return principals[offset:end], next, nil
+}
+
 // SamePrincipal compares two principals for strong equality. This is true when
 // the types, login and resource name match.
 func SamePrincipal(me, other v3.Principal) bool {
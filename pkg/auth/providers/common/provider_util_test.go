@@ -72,3 +72,37 @@ func getMockAuthConfig() map[string]any {
 		},
 	}
 }
+
+func TestPagePrincipals(t *testing.T) {
+	t.Parallel()
+	principals := func(names ...string) []apimgmtv3.Principal {
+		var result []apimgmtv3.Principal
+		for _, name := range names {
+			result = append(result, apimgmtv3.Principal{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+		return result
+	}
+
+	page, next, err := common.PagePrincipals(principals("c", "a", "b"), 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, principals("a", "b"), page)
+	assert.Equal(t, "2", next)
+
+	page, next, err = common.PagePrincipals(principals("c", "a", "b"), 2, next)
+	assert.NoError(t, err)
+	assert.Equal(t, principals("c"), page)
+	assert.Empty(t, next)
+
+	page, next, err = common.PagePrincipals(principals("c", "a", "b"), 0, "")
+	assert.NoError(t, err)
+	assert.Equal(t, principals("a", "b", "c"), page)
+	assert.Empty(t, next)
+
+	page, next, err = common.PagePrincipals(principals("a"), 2, "5")
+	assert.NoError(t, err)
+	assert.Empty(t, page)
+	assert.Empty(t, next)
+
+	_, _, err = common.PagePrincipals(principals("a"), 2, "abc")
+	assert.Error(t, err)
+}
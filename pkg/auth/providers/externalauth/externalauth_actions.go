@@ -0,0 +1,134 @@
+package externalauth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/norman/api/handler"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	managementschema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/retry"
+)
+
+func (p *externalAuthProvider) formatter(apiContext *types.APIContext, resource *types.RawResource) {
+	common.AddCommonActions(apiContext, resource)
+	resource.AddAction(apiContext, "testAndApply")
+}
+
+func (p *externalAuthProvider) actionHandler(actionName string, action *types.Action, request *types.APIContext) error {
+	handled, err := common.HandleCommonAction(actionName, action, request, Name, p.authConfigs)
+	if err != nil {
+		return err
+	}
+	if handled {
+		return nil
+	}
+
+	if actionName == "testAndApply" {
+		return p.testAndApply(request)
+	}
+
+	return httperror.NewAPIError(httperror.ActionNotAvailable, "")
+}
+
+func (p *externalAuthProvider) testAndApply(request *types.APIContext) error {
+	input, err := handler.ParseAndValidateActionBody(request, request.Schemas.Schema(&managementschema.Version,
+		client.ExternalAuthTestAndApplyInputType))
+	if err != nil {
+		return err
+	}
+
+	configApplyInput := &v32.ExternalAuthTestAndApplyInput{}
+	if err := common.Decode(input, configApplyInput); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent,
+			fmt.Sprintf("Failed to parse body: %v", err))
+	}
+
+	config := &configApplyInput.ExternalAuthConfig
+
+	// Secret fields that weren't changed are submitted as references to the stored secrets.
+	if config.ClientKey != "" {
+		value, err := common.ReadFromSecret(p.secrets, config.ClientKey, strings.ToLower(client.ExternalAuthConfigFieldClientKey))
+		if err != nil {
+			return err
+		}
+		config.ClientKey = value
+	}
+	if config.HMACSecret != "" {
+		value, err := common.ReadFromSecret(p.secrets, config.HMACSecret, strings.ToLower(client.ExternalAuthConfigFieldHMACSecret))
+		if err != nil {
+			return err
+		}
+		config.HMACSecret = value
+	}
+
+	if _, err := newWebhookClient(config); err != nil {
+		return httperror.WrapAPIError(err, httperror.InvalidBodyContent, err.Error())
+	}
+
+	login := &v32.BasicLogin{
+		Username: configApplyInput.Username,
+		Password: configApplyInput.Password,
+	}
+
+	userPrincipal, groupPrincipals, err := p.loginUser(config, login)
+	if err != nil {
+		return err
+	}
+
+	// If this works, save the config adding the enabled flag.
+	config.Enabled = configApplyInput.Enabled
+	if err := p.saveExternalAuthConfig(config); err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Failed to save %s config: %v", Name, err))
+	}
+
+	user, err := p.userMGR.SetPrincipalOnCurrentUser(request, userPrincipal)
+	if err != nil {
+		return err
+	}
+
+	userExtraInfo := p.GetUserExtraAttributes(userPrincipal)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return p.tokenMGR.UserAttributeCreateOrUpdate(user.Name, userPrincipal.Provider, groupPrincipals, userExtraInfo)
+	}); err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Failed to create or update userAttribute: %v", err))
+	}
+
+	return p.tokenMGR.CreateTokenAndSetCookie(user.Name, userPrincipal, groupPrincipals, "", 0, "Token via External Auth Configuration", request)
+}
+
+func (p *externalAuthProvider) saveExternalAuthConfig(config *v32.ExternalAuthConfig) error {
+	storedConfig, err := p.getExternalAuthConfig()
+	if err != nil {
+		return err
+	}
+	config.APIVersion = "management.cattle.io/v3"
+	config.Kind = v3.AuthConfigGroupVersionKind.Kind
+	config.Type = client.ExternalAuthConfigType
+	config.ObjectMeta = storedConfig.ObjectMeta
+
+	configType := strings.ToLower(config.Type)
+	name, err := common.CreateOrUpdateSecrets(p.secrets, config.ClientKey,
+		strings.ToLower(client.ExternalAuthConfigFieldClientKey), configType)
+	if err != nil {
+		return err
+	}
+	config.ClientKey = name
+
+	name, err = common.CreateOrUpdateSecrets(p.secrets, config.HMACSecret,
+		strings.ToLower(client.ExternalAuthConfigFieldHMACSecret), configType)
+	if err != nil {
+		return err
+	}
+	config.HMACSecret = name
+
+	logrus.Debugf("updating %s config", Name)
+	_, err = p.authConfigs.ObjectClient().Update(config.ObjectMeta.Name, config)
+	return err
+}
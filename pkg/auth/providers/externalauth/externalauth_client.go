@@ -0,0 +1,215 @@
+package externalauth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+)
+
+const (
+	// APIVersion is the version of the webhook contract sent with every request.
+	APIVersion = "externalauth.cattle.io/v1"
+
+	// TimestampHeader holds the Unix time, in seconds, at which the request was signed.
+	TimestampHeader = "X-Rancher-Timestamp"
+	// SignatureHeader holds the signature of the request in the form sha256=<hex encoded HMAC>.
+	// See Sign for how the signature is computed.
+	SignatureHeader = "X-Rancher-Signature"
+
+	// OperationAuthenticate validates the username and password of a user and returns the user and its groups.
+	OperationAuthenticate = "authenticate"
+	// OperationSearch returns the principals matching the search key, optionally restricted to a principal type.
+	OperationSearch = "search"
+	// OperationGetPrincipal returns the user or group with the given ID.
+	OperationGetPrincipal = "getPrincipal"
+	// OperationGetGroups returns the user with the given ID and its current groups.
+	OperationGetGroups = "getGroups"
+
+	defaultTimeout = 10 * time.Second
+	maxBodySize    = 1 << 20
+)
+
+var (
+	// errUnauthorized is returned when the webhook rejects the supplied credentials.
+	errUnauthorized = errors.New("invalid credentials")
+	// errNotFound is returned when the webhook doesn't know the requested principal.
+	errNotFound = errors.New("principal not found")
+)
+
+// Request is the body of every request POSTed to the webhook.
+type Request struct {
+	APIVersion string `json:"apiVersion"`
+	Operation  string `json:"operation"`
+	// Username and Password are set for the authenticate operation.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// SearchKey and PrincipalType are set for the search operation.
+	// PrincipalType is either "user", "group" or empty to search for both.
+	SearchKey     string `json:"searchKey,omitempty"`
+	PrincipalType string `json:"principalType,omitempty"`
+	// PrincipalID is set for the getPrincipal and getGroups operations.
+	// It is the ID returned by the webhook, without the provider prefix added by Rancher.
+	PrincipalID string `json:"principalId,omitempty"`
+}
+
+// Principal is a user or a group as returned by the webhook.
+type Principal struct {
+	// ID uniquely and permanently identifies the principal within its type.
+	ID string `json:"id"`
+	// Type is either "user" or "group".
+	Type           string `json:"type"`
+	DisplayName    string `json:"displayName,omitempty"`
+	LoginName      string `json:"loginName,omitempty"`
+	ProfilePicture string `json:"profilePicture,omitempty"`
+}
+
+// Response is the body of a successful (200 OK) webhook response.
+// The webhook responds with 401 Unauthorized to reject credentials and with 404 Not Found for an unknown principal.
+type Response struct {
+	// User is set in response to the authenticate, getPrincipal and getGroups operations.
+	User *Principal `json:"user,omitempty"`
+	// Groups is set in response to the authenticate and getGroups operations.
+	Groups []Principal `json:"groups,omitempty"`
+	// Principals is set in response to the search operation.
+	Principals []Principal `json:"principals,omitempty"`
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the timestamp and the body joined by a dot, keyed with the secret.
+// Webhooks verify requests by computing the same value and comparing it to the SignatureHeader,
+// and should reject requests whose TimestampHeader is too far in the past.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookClient sends signed requests to the webhook.
+type webhookClient struct {
+	httpClient *http.Client
+	endpoint   string
+	secret     string
+	now        func() time.Time
+}
+
+func newWebhookClient(config *v32.ExternalAuthConfig) (*webhookClient, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	if endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q: must be an https URL", config.Endpoint)
+	}
+	if config.HMACSecret == "" {
+		return nil, errors.New("hmacSecret is required")
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.Certificate != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(config.Certificate)) {
+			return nil, errors.New("invalid certificate: no PEM encoded certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.ClientCert != "" || config.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(config.ClientCert), []byte(config.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	timeout := defaultTimeout
+	if config.TimeoutSeconds > 0 {
+		timeout = time.Duration(config.TimeoutSeconds) * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &webhookClient{
+		httpClient: &http.Client{Transport: transport, Timeout: timeout},
+		endpoint:   config.Endpoint,
+		secret:     config.HMACSecret,
+		now:        time.Now,
+	}, nil
+}
+
+func (c *webhookClient) authenticate(username, password string) (*Response, error) {
+	return c.do(&Request{Operation: OperationAuthenticate, Username: username, Password: password})
+}
+
+func (c *webhookClient) search(searchKey, principalType string) (*Response, error) {
+	return c.do(&Request{Operation: OperationSearch, SearchKey: searchKey, PrincipalType: principalType})
+}
+
+func (c *webhookClient) getPrincipal(principalType, id string) (*Response, error) {
+	return c.do(&Request{Operation: OperationGetPrincipal, PrincipalType: principalType, PrincipalID: id})
+}
+
+func (c *webhookClient) getGroups(userID string) (*Response, error) {
+	return c.do(&Request{Operation: OperationGetGroups, PrincipalType: userType, PrincipalID: userID})
+}
+
+func (c *webhookClient) do(request *Request) (*Response, error) {
+	request.APIVersion = APIVersion
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(c.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(c.secret, timestamp, body))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request to external auth webhook failed: %w", request.Operation, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("reading external auth webhook response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, errUnauthorized
+	case http.StatusNotFound:
+		return nil, errNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status code %d from external auth webhook: %s", resp.StatusCode, string(respBody))
+	}
+
+	response := &Response{}
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return nil, fmt.Errorf("decoding external auth webhook response: %w", err)
+	}
+	return response, nil
+}
@@ -0,0 +1,237 @@
+package externalauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "s3cr3t"
+
+// newTestWebhook starts a TLS server that verifies the signature of every request before passing it to handler.
+func newTestWebhook(t *testing.T, handler func(w http.ResponseWriter, request *Request)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp := r.Header.Get(TimestampHeader)
+		if r.Header.Get(SignatureHeader) != "sha256="+Sign(testSecret, timestamp, body) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		request := &Request{}
+		require.NoError(t, json.Unmarshal(body, request))
+		handler(w, request)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func serverCertificate(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func testConfig(server *httptest.Server) *v3.ExternalAuthConfig {
+	return &v3.ExternalAuthConfig{
+		Endpoint:    server.URL,
+		Certificate: serverCertificate(server),
+		HMACSecret:  testSecret,
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Computed with: printf '1700000000.{}' | openssl dgst -sha256 -hmac s3cr3t
+	assert.Equal(t, "dd8508e44d9a9f82f2690fb7dff1da8a6ae99700d98a23a4e7e1c307af3cb6cb", Sign(testSecret, "1700000000", []byte("{}")))
+	assert.NotEqual(t, Sign(testSecret, "1700000000", []byte("{}")), Sign(testSecret, "1700000001", []byte("{}")))
+	assert.NotEqual(t, Sign(testSecret, "1700000000", []byte("{}")), Sign("other", "1700000000", []byte("{}")))
+}
+
+func TestWebhookClientSendsSignedRequests(t *testing.T) {
+	var received *Request
+	server := newTestWebhook(t, func(w http.ResponseWriter, request *Request) {
+		received = request
+		json.NewEncoder(w).Encode(Response{
+			User:   &Principal{ID: "jdoe", Type: userType},
+			Groups: []Principal{{ID: "admins", Type: groupType}},
+		})
+	})
+
+	webhook, err := newWebhookClient(testConfig(server))
+	require.NoError(t, err)
+
+	response, err := webhook.authenticate("jdoe", "password")
+	require.NoError(t, err)
+
+	assert.Equal(t, &Request{APIVersion: APIVersion, Operation: OperationAuthenticate, Username: "jdoe", Password: "password"}, received)
+	require.NotNil(t, response.User)
+	assert.Equal(t, "jdoe", response.User.ID)
+	require.Len(t, response.Groups, 1)
+	assert.Equal(t, "admins", response.Groups[0].ID)
+}
+
+func TestWebhookClientRejectedSignature(t *testing.T) {
+	server := newTestWebhook(t, func(w http.ResponseWriter, request *Request) {
+		json.NewEncoder(w).Encode(Response{})
+	})
+
+	config := testConfig(server)
+	config.HMACSecret = "wrong"
+	webhook, err := newWebhookClient(config)
+	require.NoError(t, err)
+
+	_, err = webhook.search("j", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code 400")
+}
+
+func TestWebhookClientStatusCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    error
+	}{
+		{name: "unauthorized", statusCode: http.StatusUnauthorized, wantErr: errUnauthorized},
+		{name: "forbidden", statusCode: http.StatusForbidden, wantErr: errUnauthorized},
+		{name: "not found", statusCode: http.StatusNotFound, wantErr: errNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestWebhook(t, func(w http.ResponseWriter, request *Request) {
+				w.WriteHeader(tt.statusCode)
+			})
+
+			webhook, err := newWebhookClient(testConfig(server))
+			require.NoError(t, err)
+
+			_, err = webhook.getPrincipal(userType, "jdoe")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestWebhookClientUntrustedServer(t *testing.T) {
+	server := newTestWebhook(t, func(w http.ResponseWriter, request *Request) {
+		json.NewEncoder(w).Encode(Response{})
+	})
+
+	config := testConfig(server)
+	config.Certificate = ""
+	webhook, err := newWebhookClient(config)
+	require.NoError(t, err)
+
+	_, err = webhook.search("j", "")
+	assert.Error(t, err)
+}
+
+func TestWebhookClientMutualTLS(t *testing.T) {
+	clientCert, clientKey := newClientCertificate(t)
+	block, _ := pem.Decode([]byte(clientCert))
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(parsed)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response{Principals: []Principal{{ID: "jdoe", Type: userType}}})
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	config := testConfig(server)
+	webhook, err := newWebhookClient(config)
+	require.NoError(t, err)
+	_, err = webhook.search("j", "")
+	assert.Error(t, err, "expected the server to reject a client without a certificate")
+
+	config.ClientCert = clientCert
+	config.ClientKey = clientKey
+	webhook, err = newWebhookClient(config)
+	require.NoError(t, err)
+	response, err := webhook.search("j", "")
+	require.NoError(t, err)
+	assert.Len(t, response.Principals, 1)
+}
+
+func TestNewWebhookClientValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  v3.ExternalAuthConfig
+		wantErr string
+	}{
+		{
+			name:    "plain http endpoint",
+			config:  v3.ExternalAuthConfig{Endpoint: "http://auth.example.com", HMACSecret: testSecret},
+			wantErr: "must be an https URL",
+		},
+		{
+			name:    "missing endpoint",
+			config:  v3.ExternalAuthConfig{HMACSecret: testSecret},
+			wantErr: "must be an https URL",
+		},
+		{
+			name:    "missing hmac secret",
+			config:  v3.ExternalAuthConfig{Endpoint: "https://auth.example.com"},
+			wantErr: "hmacSecret is required",
+		},
+		{
+			name:    "invalid certificate",
+			config:  v3.ExternalAuthConfig{Endpoint: "https://auth.example.com", HMACSecret: testSecret, Certificate: "invalid"},
+			wantErr: "invalid certificate",
+		},
+		{
+			name:    "client certificate without key",
+			config:  v3.ExternalAuthConfig{Endpoint: "https://auth.example.com", HMACSecret: testSecret, ClientCert: "invalid"},
+			wantErr: "invalid client certificate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newWebhookClient(&tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func newClientCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rancher"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
@@ -0,0 +1,391 @@
+package externalauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// Name is the name of the external authentication webhook provider.
+	Name = "externalauth"
+
+	userType  = common.UserPrincipalType
+	groupType = common.GroupPrincipalType
+)
+
+type userManager interface {
+	SetPrincipalOnCurrentUser(apiContext *types.APIContext, principal v32.Principal) (*v32.User, error)
+	CheckAccess(accessMode string, allowedPrincipalIDs []string, userPrincipalID string, groups []v32.Principal) (bool, error)
+}
+
+type tokenManager interface {
+	UserAttributeCreateOrUpdate(userID, provider string, groupPrincipals []v32.Principal, userExtraInfo map[string][]string, loginTime ...time.Time) error
+	CreateTokenAndSetCookie(userID string, userPrincipal v32.Principal, groupPrincipals []v32.Principal, providerToken string, ttl int, description string, request *types.APIContext) error
+}
+
+type externalAuthProvider struct {
+	ctx            context.Context
+	authConfigs    v3.AuthConfigInterface
+	authConfigsRaw objectclient.GenericClient
+	secrets        wcorev1.SecretController
+	userMGR        userManager
+	tokenMGR       tokenManager
+}
+
+func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, userMGR userManager, tokenMGR tokenManager) common.AuthProvider {
+	authConfigs := mgmtCtx.Management.AuthConfigs("")
+	return &externalAuthProvider{
+		ctx:            ctx,
+		authConfigs:    authConfigs,
+		authConfigsRaw: authConfigs.ObjectClient().UnstructuredClient(),
+		secrets:        mgmtCtx.Wrangler.Core.Secret(),
+		userMGR:        userMGR,
+		tokenMGR:       tokenMGR,
+	}
+}
+
+func (p *externalAuthProvider) GetName() string {
+	return Name
+}
+
+func (p *externalAuthProvider) LogoutAll(apiContext *types.APIContext, token accessor.TokenAccessor) error {
+	return nil
+}
+
+func (p *externalAuthProvider) Logout(apiContext *types.APIContext, token accessor.TokenAccessor) error {
+	return nil
+}
+
+func (p *externalAuthProvider) CustomizeSchema(schema *types.Schema) {
+	schema.ActionHandler = p.actionHandler
+	schema.Formatter = p.formatter
+}
+
+func (p *externalAuthProvider) TransformToAuthProvider(authConfig map[string]interface{}) (map[string]interface{}, error) {
+	return common.TransformToAuthProvider(authConfig), nil
+}
+
+// AuthenticateUser validates the credentials with the webhook and returns the user principal and its group principals.
+func (p *externalAuthProvider) AuthenticateUser(ctx context.Context, input interface{}) (v32.Principal, []v32.Principal, string, error) {
+	login, ok := input.(*v32.BasicLogin)
+	if !ok {
+		return v32.Principal{}, nil, "", errors.New("unexpected input type")
+	}
+
+	config, err := p.getExternalAuthConfig()
+	if err != nil {
+		return v32.Principal{}, nil, "", errors.New("can't find authprovider")
+	}
+
+	userPrincipal, groupPrincipals, err := p.loginUser(config, login)
+	if err != nil {
+		return v32.Principal{}, nil, "", err
+	}
+
+	return userPrincipal, groupPrincipals, "", nil
+}
+
+func (p *externalAuthProvider) loginUser(config *v32.ExternalAuthConfig, login *v32.BasicLogin) (v32.Principal, []v32.Principal, error) {
+	webhook, err := newWebhookClient(config)
+	if err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.ServerError, "invalid external auth configuration")
+	}
+
+	response, err := webhook.authenticate(login.Username, login.Password)
+	if err != nil {
+		if errors.Is(err, errUnauthorized) {
+			return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, "authentication failed")
+		}
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.ServerError, "server error while authenticating")
+	}
+	if response.User == nil {
+		return v32.Principal{}, nil, httperror.NewAPIError(httperror.Unauthorized, "authentication failed")
+	}
+
+	userPrincipal, groupPrincipals, err := toUserAndGroupPrincipals(response)
+	if err != nil {
+		return v32.Principal{}, nil, err
+	}
+	userPrincipal.Me = true
+
+	allowed, err := p.userMGR.CheckAccess(config.AccessMode, config.AllowedPrincipalIDs, userPrincipal.Name, groupPrincipals)
+	if err != nil {
+		return v32.Principal{}, nil, err
+	}
+	if !allowed {
+		return v32.Principal{}, nil, httperror.NewAPIError(httperror.PermissionDenied, "Permission denied")
+	}
+
+	return userPrincipal, groupPrincipals, nil
+}
+
+func (p *externalAuthProvider) SearchPrincipals(searchKey, principalType string, myToken accessor.TokenAccessor) ([]v32.Principal, error) {
+	config, err := p.getExternalAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := newWebhookClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := webhook.search(searchKey, principalType)
+	if err != nil {
+		return nil, err
+	}
+
+	principals := make([]v32.Principal, 0, len(response.Principals))
+	for _, external := range response.Principals {
+		principal, err := toPrincipal(external)
+		if err != nil {
+			logrus.Warnf("[%s] skipping invalid search result: %v", Name, err)
+			continue
+		}
+		if principalType != "" && principal.PrincipalType != principalType {
+			continue
+		}
+		p.markPrincipal(&principal, myToken)
+		principals = append(principals, principal)
+	}
+
+	return principals, nil
+}
+
+func (p *externalAuthProvider) GetPrincipal(principalID string, token accessor.TokenAccessor) (v32.Principal, error) {
+	principalType, externalID, err := parsePrincipalID(principalID)
+	if err != nil {
+		return v32.Principal{}, err
+	}
+
+	config, err := p.getExternalAuthConfig()
+	if err != nil {
+		return v32.Principal{}, err
+	}
+	webhook, err := newWebhookClient(config)
+	if err != nil {
+		return v32.Principal{}, err
+	}
+
+	response, err := webhook.getPrincipal(principalType, externalID)
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			return v32.Principal{}, httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("principal %s not found", principalID))
+		}
+		return v32.Principal{}, err
+	}
+	if response.User == nil {
+		return v32.Principal{}, httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("principal %s not found", principalID))
+	}
+
+	principal, err := toPrincipal(*response.User)
+	if err != nil {
+		return v32.Principal{}, err
+	}
+	if principal.Name != principalID {
+		return v32.Principal{}, fmt.Errorf("external auth webhook returned principal %s instead of %s", principal.Name, principalID)
+	}
+	p.markPrincipal(&principal, token)
+
+	return principal, nil
+}
+
+// RefetchGroupPrincipals asks the webhook for the current groups of the user.
+func (p *externalAuthProvider) RefetchGroupPrincipals(principalID string, secret string) ([]v32.Principal, error) {
+	principalType, externalID, err := parsePrincipalID(principalID)
+	if err != nil {
+		return nil, err
+	}
+	if principalType != userType {
+		return nil, fmt.Errorf("principal %s is not a user", principalID)
+	}
+
+	config, err := p.getExternalAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := newWebhookClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := webhook.getGroups(externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupPrincipals := make([]v32.Principal, 0, len(response.Groups))
+	for _, group := range response.Groups {
+		principal, err := toPrincipal(group)
+		if err != nil {
+			return nil, err
+		}
+		if principal.PrincipalType != groupType {
+			return nil, fmt.Errorf("external auth webhook returned %s principal %s as a group", principal.PrincipalType, principal.Name)
+		}
+		groupPrincipals = append(groupPrincipals, principal)
+	}
+
+	return groupPrincipals, nil
+}
+
+func (p *externalAuthProvider) CanAccessWithGroupProviders(userPrincipalID string, groupPrincipals []v32.Principal) (bool, error) {
+	config, err := p.getExternalAuthConfig()
+	if err != nil {
+		logrus.Errorf("Error fetching external auth config: %v", err)
+		return false, err
+	}
+	return p.userMGR.CheckAccess(config.AccessMode, config.AllowedPrincipalIDs, userPrincipalID, groupPrincipals)
+}
+
+func (p *externalAuthProvider) GetUserExtraAttributes(userPrincipal v32.Principal) map[string][]string {
+	return common.GetCommonUserExtraAttributes(userPrincipal)
+}
+
+// IsDisabledProvider checks if the external auth provider is currently disabled in Rancher.
+func (p *externalAuthProvider) IsDisabledProvider() (bool, error) {
+	config, err := p.getExternalAuthConfig()
+	if err != nil {
+		return false, err
+	}
+	return !config.Enabled, nil
+}
+
+func (p *externalAuthProvider) markPrincipal(principal *v32.Principal, token accessor.TokenAccessor) {
+	if token == nil {
+		return
+	}
+	switch principal.PrincipalType {
+	case userType:
+		principal.Me = common.SamePrincipal(token.GetUserPrincipal(), *principal)
+	case groupType:
+		for _, group := range token.GetGroupPrincipals() {
+			if group.Name == principal.Name {
+				principal.MemberOf = true
+				break
+			}
+		}
+	}
+}
+
+func (p *externalAuthProvider) getExternalAuthConfig() (*v32.ExternalAuthConfig, error) {
+	return getExternalAuthConfig(p.authConfigsRaw, func(value, field string) (string, error) {
+		return common.ReadFromSecret(p.secrets, value, strings.ToLower(field))
+	})
+}
+
+// getExternalAuthConfig reads the stored config and resolves its secret fields using resolveSecret.
+func getExternalAuthConfig(genericClient objectclient.GenericClient, resolveSecret func(value, field string) (string, error)) (*v32.ExternalAuthConfig, error) {
+	authConfigObj, err := genericClient.Get(Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s config: %w", Name, err)
+	}
+
+	u, ok := authConfigObj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve %s config, cannot read k8s Unstructured data", Name)
+	}
+
+	storedConfig := &v32.ExternalAuthConfig{}
+	if err := common.Decode(u.UnstructuredContent(), storedConfig); err != nil {
+		return nil, fmt.Errorf("unable to decode %s config: %w", Name, err)
+	}
+
+	if storedConfig.ClientKey != "" {
+		value, err := resolveSecret(storedConfig.ClientKey, client.ExternalAuthConfigFieldClientKey)
+		if err != nil {
+			return nil, err
+		}
+		storedConfig.ClientKey = value
+	}
+	if storedConfig.HMACSecret != "" {
+		value, err := resolveSecret(storedConfig.HMACSecret, client.ExternalAuthConfigFieldHMACSecret)
+		if err != nil {
+			return nil, err
+		}
+		storedConfig.HMACSecret = value
+	}
+
+	return storedConfig, nil
+}
+
+// toUserAndGroupPrincipals converts the user and groups of a webhook response to principals.
+func toUserAndGroupPrincipals(response *Response) (v32.Principal, []v32.Principal, error) {
+	userPrincipal, err := toPrincipal(*response.User)
+	if err != nil {
+		return v32.Principal{}, nil, err
+	}
+	if userPrincipal.PrincipalType != userType {
+		return v32.Principal{}, nil, fmt.Errorf("external auth webhook returned %s principal %s as the user", userPrincipal.PrincipalType, userPrincipal.Name)
+	}
+
+	groupPrincipals := make([]v32.Principal, 0, len(response.Groups))
+	for _, group := range response.Groups {
+		groupPrincipal, err := toPrincipal(group)
+		if err != nil {
+			return v32.Principal{}, nil, err
+		}
+		if groupPrincipal.PrincipalType != groupType {
+			return v32.Principal{}, nil, fmt.Errorf("external auth webhook returned %s principal %s as a group", groupPrincipal.PrincipalType, groupPrincipal.Name)
+		}
+		groupPrincipal.MemberOf = true
+		groupPrincipals = append(groupPrincipals, groupPrincipal)
+	}
+
+	return userPrincipal, groupPrincipals, nil
+}
+
+// toPrincipal converts a principal returned by the webhook to a principal of the provider.
+func toPrincipal(external Principal) (v32.Principal, error) {
+	if external.ID == "" {
+		return v32.Principal{}, errors.New("external auth webhook returned a principal without an id")
+	}
+	if external.Type != userType && external.Type != groupType {
+		return v32.Principal{}, fmt.Errorf("external auth webhook returned principal %s with invalid type %q", external.ID, external.Type)
+	}
+
+	principal := v32.Principal{
+		ObjectMeta:     metav1.ObjectMeta{Name: Name + "_" + external.Type + "://" + external.ID},
+		DisplayName:    external.DisplayName,
+		LoginName:      external.LoginName,
+		ProfilePicture: external.ProfilePicture,
+		PrincipalType:  external.Type,
+		Provider:       Name,
+	}
+	if principal.DisplayName == "" {
+		principal.DisplayName = external.LoginName
+	}
+	if principal.DisplayName == "" {
+		principal.DisplayName = external.ID
+	}
+	return principal, nil
+}
+
+// parsePrincipalID splits a principal ID like externalauth_user://jdoe into its type and the ID known to the webhook.
+func parsePrincipalID(principalID string) (string, string, error) {
+	scope, externalID, ok := strings.Cut(principalID, "://")
+	if !ok || externalID == "" {
+		return "", "", fmt.Errorf("invalid id %s", principalID)
+	}
+	principalType, ok := strings.CutPrefix(scope, Name+"_")
+	if !ok || (principalType != userType && principalType != groupType) {
+		return "", "", fmt.Errorf("invalid id %s", principalID)
+	}
+	return principalType, externalID, nil
+}
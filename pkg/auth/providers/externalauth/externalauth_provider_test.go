@@ -0,0 +1,284 @@
+package externalauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/norman/types"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestAuthenticateUser(t *testing.T) {
+	server := newTestWebhook(t, func(w http.ResponseWriter, request *Request) {
+		if request.Operation != OperationAuthenticate || request.Password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(Response{
+			User:   &Principal{ID: request.Username, Type: userType, DisplayName: "John Doe", LoginName: request.Username},
+			Groups: []Principal{{ID: "admins", Type: groupType}},
+		})
+	})
+
+	tests := []struct {
+		name          string
+		password      string
+		allowed       bool
+		wantErrCode   *httperror.ErrorCode
+		wantUser      string
+		wantGroupName string
+	}{
+		{
+			name:          "valid credentials",
+			password:      "password",
+			allowed:       true,
+			wantUser:      "externalauth_user://jdoe",
+			wantGroupName: "externalauth_group://admins",
+		},
+		{
+			name:        "invalid credentials",
+			password:    "wrong",
+			allowed:     true,
+			wantErrCode: &httperror.Unauthorized,
+		},
+		{
+			name:        "access denied",
+			password:    "password",
+			allowed:     false,
+			wantErrCode: &httperror.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestProvider(server, tt.allowed)
+
+			user, groups, _, err := provider.AuthenticateUser(context.Background(), &v3.BasicLogin{Username: "jdoe", Password: tt.password})
+			if tt.wantErrCode != nil {
+				require.Error(t, err)
+				apiErr, ok := err.(*httperror.APIError)
+				require.True(t, ok)
+				assert.Equal(t, *tt.wantErrCode, apiErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, user.Name)
+			assert.Equal(t, "John Doe", user.DisplayName)
+			assert.Equal(t, Name, user.Provider)
+			require.Len(t, groups, 1)
+			assert.Equal(t, tt.wantGroupName, groups[0].Name)
+			assert.Equal(t, groupType, groups[0].PrincipalType)
+		})
+	}
+}
+
+func TestSearchPrincipals(t *testing.T) {
+	server := newTestWebhook(t, func(w http.ResponseWriter, request *Request) {
+		json.NewEncoder(w).Encode(Response{Principals: []Principal{
+			{ID: "jdoe", Type: userType},
+			{ID: "admins", Type: groupType},
+			{ID: "", Type: userType},
+			{ID: "invalid", Type: "robot"},
+		}})
+	})
+	provider := newTestProvider(server, true)
+
+	token := &v3.Token{
+		UserPrincipal:   v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: "externalauth_user://jdoe"}, Provider: Name, PrincipalType: userType},
+		GroupPrincipals: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "externalauth_group://admins"}}},
+	}
+
+	principals, err := provider.SearchPrincipals("j", "", token)
+	require.NoError(t, err)
+	require.Len(t, principals, 2)
+	assert.Equal(t, "externalauth_user://jdoe", principals[0].Name)
+	assert.True(t, principals[0].Me)
+	assert.Equal(t, "externalauth_group://admins", principals[1].Name)
+	assert.True(t, principals[1].MemberOf)
+
+	principals, err = provider.SearchPrincipals("j", groupType, token)
+	require.NoError(t, err)
+	require.Len(t, principals, 1)
+	assert.Equal(t, "externalauth_group://admins", principals[0].Name)
+}
+
+func TestGetPrincipal(t *testing.T) {
+	server := newTestWebhook(t, func(w http.ResponseWriter, request *Request) {
+		if request.PrincipalID != "jdoe" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(Response{User: &Principal{ID: request.PrincipalID, Type: request.PrincipalType}})
+	})
+	provider := newTestProvider(server, true)
+
+	principal, err := provider.GetPrincipal("externalauth_user://jdoe", &v3.Token{})
+	require.NoError(t, err)
+	assert.Equal(t, "externalauth_user://jdoe", principal.Name)
+	assert.Equal(t, "jdoe", principal.DisplayName)
+
+	_, err = provider.GetPrincipal("externalauth_user://unknown", &v3.Token{})
+	require.Error(t, err)
+	assert.True(t, httperror.IsNotFound(err))
+
+	_, err = provider.GetPrincipal("github_user://jdoe", &v3.Token{})
+	assert.Error(t, err)
+}
+
+func TestRefetchGroupPrincipals(t *testing.T) {
+	server := newTestWebhook(t, func(w http.ResponseWriter, request *Request) {
+		require.Equal(t, OperationGetGroups, request.Operation)
+		require.Equal(t, "jdoe", request.PrincipalID)
+		json.NewEncoder(w).Encode(Response{
+			User:   &Principal{ID: "jdoe", Type: userType},
+			Groups: []Principal{{ID: "admins", Type: groupType}, {ID: "devs", Type: groupType}},
+		})
+	})
+	provider := newTestProvider(server, true)
+
+	groups, err := provider.RefetchGroupPrincipals("externalauth_user://jdoe", "")
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "externalauth_group://admins", groups[0].Name)
+	assert.Equal(t, "externalauth_group://devs", groups[1].Name)
+
+	_, err = provider.RefetchGroupPrincipals("externalauth_group://admins", "")
+	assert.Error(t, err)
+}
+
+func TestParsePrincipalID(t *testing.T) {
+	tests := []struct {
+		principalID string
+		wantType    string
+		wantID      string
+		wantErr     bool
+	}{
+		{principalID: "externalauth_user://jdoe", wantType: userType, wantID: "jdoe"},
+		{principalID: "externalauth_group://cn=admins,dc=example", wantType: groupType, wantID: "cn=admins,dc=example"},
+		{principalID: "externalauth_user://", wantErr: true},
+		{principalID: "externalauth_robot://jdoe", wantErr: true},
+		{principalID: "openldap_user://jdoe", wantErr: true},
+		{principalID: "jdoe", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.principalID, func(t *testing.T) {
+			principalType, id, err := parsePrincipalID(tt.principalID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, principalType)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
+
+func TestGetExternalAuthConfig(t *testing.T) {
+	genericClient := mockGenericClient{ObjectMap: map[string]interface{}{
+		"endpoint":   "https://auth.example.com",
+		"clientKey":  "cattle-global-data:externalauthconfig-clientkey",
+		"hmacSecret": "cattle-global-data:externalauthconfig-hmacsecret",
+		"enabled":    true,
+	}}
+	secrets := map[string]string{
+		"cattle-global-data:externalauthconfig-clientkey":  "key",
+		"cattle-global-data:externalauthconfig-hmacsecret": "secret",
+	}
+
+	config, err := getExternalAuthConfig(genericClient, func(value, field string) (string, error) {
+		return secrets[value], nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://auth.example.com", config.Endpoint)
+	assert.Equal(t, "key", config.ClientKey)
+	assert.Equal(t, "secret", config.HMACSecret)
+	assert.True(t, config.Enabled)
+}
+
+func newTestProvider(server *httptest.Server, allowed bool) *externalAuthProvider {
+	config := testConfig(server)
+	return &externalAuthProvider{
+		authConfigsRaw: mockGenericClient{ObjectMap: map[string]interface{}{
+			"endpoint":    config.Endpoint,
+			"certificate": config.Certificate,
+			"hmacSecret":  config.HMACSecret,
+			"enabled":     true,
+		}},
+		userMGR: &mockUserManager{allowed: allowed},
+	}
+}
+
+type mockUserManager struct {
+	allowed bool
+}
+
+func (m *mockUserManager) SetPrincipalOnCurrentUser(apiContext *types.APIContext, principal v3.Principal) (*v3.User, error) {
+	panic("unimplemented")
+}
+
+func (m *mockUserManager) CheckAccess(accessMode string, allowedPrincipalIDs []string, userPrincipalID string, groups []v3.Principal) (bool, error) {
+	return m.allowed, nil
+}
+
+type mockGenericClient struct {
+	ObjectMap map[string]interface{}
+}
+
+func (m mockGenericClient) UnstructuredClient() objectclient.GenericClient {
+	panic("unimplemented")
+}
+func (m mockGenericClient) GroupVersionKind() schema.GroupVersionKind {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Create(o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) GetNamespaced(namespace, name string, opts metav1.GetOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Get(name string, opts metav1.GetOptions) (runtime.Object, error) {
+	return &unstructured.Unstructured{Object: m.ObjectMap}, nil
+}
+func (m mockGenericClient) Update(name string, o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) UpdateStatus(name string, o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) DeleteNamespaced(namespace, name string, opts *metav1.DeleteOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Delete(name string, opts *metav1.DeleteOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) List(opts metav1.ListOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) ListNamespaced(namespace string, opts metav1.ListOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) DeleteCollection(deleteOptions *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Patch(name string, o runtime.Object, patchType k8stypes.PatchType, data []byte, subresources ...string) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) ObjectFactory() objectclient.ObjectFactory {
+	panic("unimplemented")
+}
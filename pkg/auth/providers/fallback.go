@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const fallbackProbeInterval = 30 * time.Second
+
+var fallback fallbackState
+
+type fallbackState struct {
+	mu       sync.RWMutex
+	provider string
+}
+
+// FallbackProvider returns the provider logins are allowed with because the providers before it in the
+// auth-provider-fallback-order setting failed their health probe. It's empty while the first provider is healthy.
+func FallbackProvider() string {
+	fallback.mu.RLock()
+	defer fallback.mu.RUnlock()
+	return fallback.provider
+}
+
+func (f *fallbackState) set(provider string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if provider == f.provider {
+		return
+	}
+	if provider == "" {
+		logrus.Infof("[fallback] primary auth provider is healthy again, disabling fallback to %s", f.provider)
+	} else {
+		logrus.Warnf("[fallback] auth providers before %s in the %s setting are unavailable, falling back to %s",
+			provider, settings.AuthProviderFallbackOrder.Name, provider)
+	}
+	f.provider = provider
+}
+
+// startFallbackProbe periodically probes the providers of the auth-provider-fallback-order setting.
+func startFallbackProbe(ctx context.Context) {
+	go wait.UntilWithContext(ctx, func(_ context.Context) {
+		fallback.set(probeFallbackOrder(settings.AuthProviderFallbackOrder.Get()))
+	}, fallbackProbeInterval)
+}
+
+// probeFallbackOrder returns the first healthy provider of the comma separated order,
+// or an empty string if that's the first provider or no provider is healthy.
+func probeFallbackOrder(order string) string {
+	var names []string
+	for _, name := range strings.Split(order, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	for i, name := range names {
+		provider, err := GetProvider(name)
+		if err != nil {
+			logrus.Warnf("[fallback] unknown auth provider %s in the %s setting", name, settings.AuthProviderFallbackOrder.Name)
+			continue
+		}
+		if checker, ok := provider.(common.HealthChecker); ok {
+			if err := checker.HealthCheck(); err != nil {
+				logrus.Debugf("[fallback] auth provider %s failed its health probe: %v", name, err)
+				continue
+			}
+		}
+		if i == 0 {
+			return ""
+		}
+		return name
+	}
+	return ""
+}
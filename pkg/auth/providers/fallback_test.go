@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/auth/providers/activedirectory"
+	"github.com/rancher/rancher/pkg/auth/providers/ldap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeFallbackOrder(t *testing.T) {
+	defer cleanup()
+	Providers[activedirectory.Name] = &healthCheckProvider{}
+	Providers[ldap.OpenLdapName] = &healthCheckProvider{}
+	Providers[LocalProvider] = &fakeProvider{}
+
+	tests := []struct {
+		name    string
+		order   string
+		adErr   error
+		ldapErr error
+		want    string
+	}{
+		{
+			name:  "no order",
+			order: "",
+			want:  "",
+		},
+		{
+			name:  "primary is healthy",
+			order: "activedirectory,local",
+			want:  "",
+		},
+		{
+			name:  "primary is unavailable",
+			order: "activedirectory, local",
+			adErr: errors.New("connection refused"),
+			want:  LocalProvider,
+		},
+		{
+			name:    "first fallback is unavailable",
+			order:   "activedirectory,openldap,local",
+			adErr:   errors.New("connection refused"),
+			ldapErr: errors.New("connection refused"),
+			want:    LocalProvider,
+		},
+		{
+			name:  "unknown providers are skipped",
+			order: "activedirectory,unknown,openldap",
+			adErr: errors.New("connection refused"),
+			want:  ldap.OpenLdapName,
+		},
+		{
+			name:    "all providers are unavailable",
+			order:   "activedirectory,openldap",
+			adErr:   errors.New("connection refused"),
+			ldapErr: errors.New("connection refused"),
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Providers[activedirectory.Name].(*healthCheckProvider).err = tt.adErr
+			Providers[ldap.OpenLdapName].(*healthCheckProvider).err = tt.ldapErr
+
+			assert.Equal(t, tt.want, probeFallbackOrder(tt.order))
+		})
+	}
+}
+
+func TestFallbackProvider(t *testing.T) {
+	defer fallback.set("")
+
+	assert.Empty(t, FallbackProvider())
+	fallback.set(LocalProvider)
+	assert.Equal(t, LocalProvider, FallbackProvider())
+}
+
+// healthCheckProvider fails its health probe with err.
+type healthCheckProvider struct {
+	fakeProvider
+	err error
+}
+
+func (p *healthCheckProvider) HealthCheck() error {
+	return p.err
+}
@@ -436,3 +436,18 @@ func (g *ghProvider) IsDisabledProvider() (bool, error) {
 	}
 	return !ghConfig.Enabled, nil
 }
+
+// Probe checks that the API and the OAuth token endpoint of the GitHub host answer.
+func (g *ghProvider) Probe(ctx context.Context) (*v32.AuthConfigConnectivity, error) {
+	config, err := g.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &v32.AuthConfigConnectivity{
+		Endpoints: []v32.AuthConfigEndpointStatus{
+			common.ProbeURL(ctx, g.githubClient.httpClient, "api", g.githubClient.getURL("API", config)),
+			common.ProbeURL(ctx, g.githubClient.httpClient, "token", g.githubClient.getURL("TOKEN", config)),
+		},
+	}, nil
+}
@@ -0,0 +1,130 @@
+package kerberos
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/norman/api/handler"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	managementschema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/retry"
+)
+
+func (p *kerberosProvider) formatter(apiContext *types.APIContext, resource *types.RawResource) {
+	common.AddCommonActions(apiContext, resource)
+	resource.AddAction(apiContext, "testAndApply")
+}
+
+func (p *kerberosProvider) actionHandler(actionName string, action *types.Action, request *types.APIContext) error {
+	handled, err := common.HandleCommonAction(actionName, action, request, Name, p.authConfigs)
+	if err != nil {
+		return err
+	}
+	if handled {
+		return nil
+	}
+
+	if actionName == "testAndApply" {
+		return p.testAndApply(request)
+	}
+
+	return httperror.NewAPIError(httperror.ActionNotAvailable, "")
+}
+
+// testAndApply authenticates the current request with its Negotiate token before saving the config.
+// Requests without a token are challenged, so that the browser of an admin on a domain-joined machine retries with one.
+func (p *kerberosProvider) testAndApply(request *types.APIContext) error {
+	input, err := handler.ParseAndValidateActionBody(request, request.Schemas.Schema(&managementschema.Version,
+		client.KerberosTestAndApplyInputType))
+	if err != nil {
+		return err
+	}
+
+	configApplyInput := &v32.KerberosTestAndApplyInput{}
+	if err := common.Decode(input, configApplyInput); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent,
+			fmt.Sprintf("Failed to parse body: %v", err))
+	}
+
+	config := &configApplyInput.KerberosConfig
+
+	if config.Keytab != "" {
+		value, err := common.ReadFromSecret(p.secrets, config.Keytab, strings.ToLower(client.KerberosConfigFieldKeytab))
+		if err != nil {
+			return err
+		}
+		config.Keytab = value
+	}
+
+	if err := p.validateConfig(config); err != nil {
+		return httperror.WrapAPIError(err, httperror.InvalidBodyContent, err.Error())
+	}
+
+	userPrincipal, groupPrincipals, err := p.loginUser(config, request.Request)
+	if err != nil {
+		SetNegotiateChallenge(request.Response, err)
+		return err
+	}
+
+	// If this works, save the config adding the enabled flag.
+	config.Enabled = configApplyInput.Enabled
+	if err := p.saveKerberosConfig(config); err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Failed to save %s config: %v", Name, err))
+	}
+
+	user, err := p.userMGR.SetPrincipalOnCurrentUser(request, userPrincipal)
+	if err != nil {
+		return err
+	}
+
+	userExtraInfo := p.GetUserExtraAttributes(userPrincipal)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return p.tokenMGR.UserAttributeCreateOrUpdate(user.Name, userPrincipal.Provider, groupPrincipals, userExtraInfo)
+	}); err != nil {
+		return httperror.NewAPIError(httperror.ServerError, fmt.Sprintf("Failed to create or update userAttribute: %v", err))
+	}
+
+	return p.tokenMGR.CreateTokenAndSetCookie(user.Name, userPrincipal, groupPrincipals, "", 0, "Token via Kerberos Configuration", request)
+}
+
+func (p *kerberosProvider) validateConfig(config *v32.KerberosConfig) error {
+	kt, err := parseKeytab(config.Keytab)
+	if err != nil {
+		return err
+	}
+	if !hasServicePrincipal(kt, config.ServicePrincipalName) {
+		return fmt.Errorf("keytab has no key for %s", config.ServicePrincipalName)
+	}
+	if _, err := p.directoryProvider(config); err != nil {
+		return fmt.Errorf("invalid directoryProvider: %w", err)
+	}
+	return nil
+}
+
+func (p *kerberosProvider) saveKerberosConfig(config *v32.KerberosConfig) error {
+	storedConfig, err := p.getKerberosConfig()
+	if err != nil {
+		return err
+	}
+	config.APIVersion = "management.cattle.io/v3"
+	config.Kind = v3.AuthConfigGroupVersionKind.Kind
+	config.Type = client.KerberosConfigType
+	config.ObjectMeta = storedConfig.ObjectMeta
+
+	field := strings.ToLower(client.KerberosConfigFieldKeytab)
+	name, err := common.CreateOrUpdateSecrets(p.secrets, config.Keytab, field, strings.ToLower(config.Type))
+	if err != nil {
+		return err
+	}
+	config.Keytab = name
+
+	logrus.Debugf("updating %s config", Name)
+	_, err = p.authConfigs.ObjectClient().Update(config.ObjectMeta.Name, config)
+	return err
+}
@@ -0,0 +1,357 @@
+package kerberos
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jcmturner/goidentity/v6"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/service"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	krbtypes "github.com/jcmturner/gokrb5/v8/types"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/util"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// Name is the name of the Kerberos SPNEGO provider.
+	Name = "kerberos"
+
+	defaultMaxClockSkew = 5 * time.Minute
+)
+
+// errNegotiateRequired is returned when a request doesn't carry a valid Negotiate token.
+var errNegotiateRequired = errors.New("negotiate authentication required")
+
+type userManager interface {
+	SetPrincipalOnCurrentUser(apiContext *types.APIContext, principal v32.Principal) (*v32.User, error)
+	CheckAccess(accessMode string, allowedPrincipalIDs []string, userPrincipalID string, groups []v32.Principal) (bool, error)
+}
+
+type tokenManager interface {
+	UserAttributeCreateOrUpdate(userID, provider string, groupPrincipals []v32.Principal, userExtraInfo map[string][]string, loginTime ...time.Time) error
+	CreateTokenAndSetCookie(userID string, userPrincipal v32.Principal, groupPrincipals []v32.Principal, providerToken string, ttl int, description string, request *types.APIContext) error
+}
+
+// kerberosProvider authenticates users with the Kerberos service tickets sent by browsers in Negotiate tokens.
+// Users and groups are owned by the configured directory provider: a user logging in with Kerberos is the
+// same Rancher user as when logging in with a password against the directory.
+type kerberosProvider struct {
+	ctx            context.Context
+	authConfigs    v3.AuthConfigInterface
+	authConfigsRaw objectclient.GenericClient
+	secrets        wcorev1.SecretController
+	userMGR        userManager
+	tokenMGR       tokenManager
+	getProvider    func(name string) (common.AuthProvider, error)
+}
+
+// Configure returns the Kerberos provider. getProvider is used to look up the configured directory provider.
+func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, userMGR userManager, tokenMGR tokenManager, getProvider func(name string) (common.AuthProvider, error)) common.AuthProvider {
+	authConfigs := mgmtCtx.Management.AuthConfigs("")
+	return &kerberosProvider{
+		ctx:            ctx,
+		authConfigs:    authConfigs,
+		authConfigsRaw: authConfigs.ObjectClient().UnstructuredClient(),
+		secrets:        mgmtCtx.Wrangler.Core.Secret(),
+		userMGR:        userMGR,
+		tokenMGR:       tokenMGR,
+		getProvider:    getProvider,
+	}
+}
+
+// SetNegotiateChallenge asks the client to authenticate with a Negotiate token if err indicates that it didn't.
+// Browsers only send Negotiate tokens in response to such a challenge.
+func SetNegotiateChallenge(w http.ResponseWriter, err error) {
+	var apiErr *httperror.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == httperror.Unauthorized {
+		w.Header().Set(spnego.HTTPHeaderAuthResponse, spnego.HTTPHeaderAuthResponseValueKey)
+	}
+}
+
+func (p *kerberosProvider) GetName() string {
+	return Name
+}
+
+func (p *kerberosProvider) LogoutAll(apiContext *types.APIContext, token accessor.TokenAccessor) error {
+	return nil
+}
+
+func (p *kerberosProvider) Logout(apiContext *types.APIContext, token accessor.TokenAccessor) error {
+	return nil
+}
+
+func (p *kerberosProvider) CustomizeSchema(schema *types.Schema) {
+	schema.ActionHandler = p.actionHandler
+	schema.Formatter = p.formatter
+}
+
+func (p *kerberosProvider) TransformToAuthProvider(authConfig map[string]interface{}) (map[string]interface{}, error) {
+	return common.TransformToAuthProvider(authConfig), nil
+}
+
+// AuthenticateUser validates the Negotiate token of the login request and returns the matching directory user and its groups.
+func (p *kerberosProvider) AuthenticateUser(ctx context.Context, input interface{}) (v32.Principal, []v32.Principal, string, error) {
+	req, ok := ctx.Value(util.RequestKey).(*http.Request)
+	if !ok {
+		return v32.Principal{}, nil, "", errors.New("missing login request")
+	}
+
+	config, err := p.getKerberosConfig()
+	if err != nil {
+		return v32.Principal{}, nil, "", errors.New("can't find authprovider")
+	}
+
+	userPrincipal, groupPrincipals, err := p.loginUser(config, req)
+	if err != nil {
+		return v32.Principal{}, nil, "", err
+	}
+
+	return userPrincipal, groupPrincipals, "", nil
+}
+
+func (p *kerberosProvider) loginUser(config *v32.KerberosConfig, req *http.Request) (v32.Principal, []v32.Principal, error) {
+	username, realm, err := acceptNegotiateToken(config, req)
+	if err != nil {
+		if errors.Is(err, errNegotiateRequired) {
+			return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, "Negotiate authentication required")
+		}
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.ServerError, "server error while authenticating")
+	}
+
+	return p.directoryUser(config, username, realm)
+}
+
+// directoryUser returns the directory user matching the Kerberos client principal and its groups.
+func (p *kerberosProvider) directoryUser(config *v32.KerberosConfig, username, realm string) (v32.Principal, []v32.Principal, error) {
+	if len(config.Realms) > 0 && !slices.ContainsFunc(config.Realms, func(r string) bool { return strings.EqualFold(r, realm) }) {
+		return v32.Principal{}, nil, httperror.NewAPIError(httperror.PermissionDenied, "Permission denied")
+	}
+
+	directory, err := p.directoryProvider(config)
+	if err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.ServerError, "server error while authenticating")
+	}
+
+	userPrincipal, err := findUser(directory, username)
+	if err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.Unauthorized, "Unauthorized")
+	}
+	userPrincipal.Me = true
+
+	groupPrincipals, err := directory.RefetchGroupPrincipals(userPrincipal.Name, "")
+	if err != nil {
+		return v32.Principal{}, nil, httperror.WrapAPIError(err, httperror.ServerError, "server error while authenticating")
+	}
+
+	allowed, err := p.userMGR.CheckAccess(config.AccessMode, config.AllowedPrincipalIDs, userPrincipal.Name, groupPrincipals)
+	if err != nil {
+		return v32.Principal{}, nil, err
+	}
+	if !allowed {
+		return v32.Principal{}, nil, httperror.NewAPIError(httperror.PermissionDenied, "Permission denied")
+	}
+
+	return userPrincipal, groupPrincipals, nil
+}
+
+// acceptNegotiateToken validates the Negotiate token in the Authorization header of the request with the keytab
+// and returns the name and realm of the authenticated client principal.
+func acceptNegotiateToken(config *v32.KerberosConfig, req *http.Request) (string, string, error) {
+	kt, err := parseKeytab(config.Keytab)
+	if err != nil {
+		return "", "", err
+	}
+
+	maxClockSkew := defaultMaxClockSkew
+	if config.MaxClockSkewSeconds > 0 {
+		maxClockSkew = time.Duration(config.MaxClockSkewSeconds) * time.Second
+	}
+
+	var identity goidentity.Identity
+	handler := spnego.SPNEGOKRB5Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = goidentity.FromHTTPRequestContext(r)
+	}), kt,
+		service.KeytabPrincipal(config.ServicePrincipalName),
+		service.MaxClockSkew(maxClockSkew),
+		// Groups are read from the directory provider, so the PAC isn't needed.
+		service.DecodePAC(false),
+	)
+	handler.ServeHTTP(&discardResponseWriter{header: http.Header{}}, req)
+
+	if identity == nil || !identity.Authenticated() {
+		return "", "", errNegotiateRequired
+	}
+	return identity.UserName(), identity.Domain(), nil
+}
+
+// parseKeytab decodes a base64 encoded keytab.
+func parseKeytab(value string) (*keytab.Keytab, error) {
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("keytab is not base64 encoded: %w", err)
+	}
+	kt := keytab.New()
+	if err := kt.Unmarshal(b); err != nil {
+		return nil, fmt.Errorf("invalid keytab: %w", err)
+	}
+	return kt, nil
+}
+
+// hasServicePrincipal reports whether the keytab holds a key of the service principal.
+func hasServicePrincipal(kt *keytab.Keytab, servicePrincipalName string) bool {
+	spn, realm := krbtypes.ParseSPNString(servicePrincipalName)
+	for _, entry := range kt.Entries {
+		if slices.Equal(entry.Principal.Components, spn.NameString) && (realm == "" || strings.EqualFold(entry.Principal.Realm, realm)) {
+			return true
+		}
+	}
+	return false
+}
+
+// findUser returns the user of the directory whose login name is username.
+func findUser(directory common.AuthProvider, username string) (v32.Principal, error) {
+	principals, err := directory.SearchPrincipals(username, common.UserPrincipalType, &v32.Token{})
+	if err != nil {
+		return v32.Principal{}, err
+	}
+	for _, principal := range principals {
+		if principal.PrincipalType == common.UserPrincipalType && strings.EqualFold(principal.LoginName, username) {
+			return principal, nil
+		}
+	}
+	return v32.Principal{}, fmt.Errorf("user %s not found in %s", username, directory.GetName())
+}
+
+func (p *kerberosProvider) directoryProvider(config *v32.KerberosConfig) (common.AuthProvider, error) {
+	if config.DirectoryProvider == "" {
+		return nil, errors.New("directoryProvider is not set")
+	}
+	return p.getProvider(config.DirectoryProvider)
+}
+
+func (p *kerberosProvider) SearchPrincipals(searchKey, principalType string, myToken accessor.TokenAccessor) ([]v32.Principal, error) {
+	config, err := p.getKerberosConfig()
+	if err != nil {
+		return nil, err
+	}
+	directory, err := p.directoryProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	return directory.SearchPrincipals(searchKey, principalType, myToken)
+}
+
+func (p *kerberosProvider) GetPrincipal(principalID string, token accessor.TokenAccessor) (v32.Principal, error) {
+	config, err := p.getKerberosConfig()
+	if err != nil {
+		return v32.Principal{}, err
+	}
+	directory, err := p.directoryProvider(config)
+	if err != nil {
+		return v32.Principal{}, err
+	}
+	return directory.GetPrincipal(principalID, token)
+}
+
+func (p *kerberosProvider) RefetchGroupPrincipals(principalID string, secret string) ([]v32.Principal, error) {
+	config, err := p.getKerberosConfig()
+	if err != nil {
+		return nil, err
+	}
+	directory, err := p.directoryProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	return directory.RefetchGroupPrincipals(principalID, secret)
+}
+
+func (p *kerberosProvider) CanAccessWithGroupProviders(userPrincipalID string, groupPrincipals []v32.Principal) (bool, error) {
+	config, err := p.getKerberosConfig()
+	if err != nil {
+		logrus.Errorf("Error fetching kerberos config: %v", err)
+		return false, err
+	}
+	return p.userMGR.CheckAccess(config.AccessMode, config.AllowedPrincipalIDs, userPrincipalID, groupPrincipals)
+}
+
+func (p *kerberosProvider) GetUserExtraAttributes(userPrincipal v32.Principal) map[string][]string {
+	return common.GetCommonUserExtraAttributes(userPrincipal)
+}
+
+// IsDisabledProvider checks if the Kerberos auth provider is currently disabled in Rancher.
+func (p *kerberosProvider) IsDisabledProvider() (bool, error) {
+	config, err := p.getKerberosConfig()
+	if err != nil {
+		return false, err
+	}
+	return !config.Enabled, nil
+}
+
+func (p *kerberosProvider) getKerberosConfig() (*v32.KerberosConfig, error) {
+	return getKerberosConfig(p.authConfigsRaw, func(value string) (string, error) {
+		return common.ReadFromSecret(p.secrets, value, strings.ToLower(client.KerberosConfigFieldKeytab))
+	})
+}
+
+// getKerberosConfig reads the stored config and resolves the keytab using resolveSecret.
+func getKerberosConfig(genericClient objectclient.GenericClient, resolveSecret func(value string) (string, error)) (*v32.KerberosConfig, error) {
+	authConfigObj, err := genericClient.Get(Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s config: %w", Name, err)
+	}
+
+	u, ok := authConfigObj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve %s config, cannot read k8s Unstructured data", Name)
+	}
+
+	storedConfig := &v32.KerberosConfig{}
+	if err := common.Decode(u.UnstructuredContent(), storedConfig); err != nil {
+		return nil, fmt.Errorf("unable to decode %s config: %w", Name, err)
+	}
+
+	if storedConfig.Keytab != "" {
+		value, err := resolveSecret(storedConfig.Keytab)
+		if err != nil {
+			return nil, err
+		}
+		storedConfig.Keytab = value
+	}
+
+	return storedConfig, nil
+}
+
+// discardResponseWriter discards the responses written by the SPNEGO handler.
+// The login handler writes its own response and challenges the client with SetNegotiateChallenge.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
@@ -0,0 +1,196 @@
+package kerberos
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAcceptNegotiateTokenWithoutToken(t *testing.T) {
+	config := &v3.KerberosConfig{
+		Keytab:               newKeytab(t, "HTTP/rancher.example.com", "EXAMPLE.COM"),
+		ServicePrincipalName: "HTTP/rancher.example.com",
+	}
+
+	for name, header := range map[string]string{
+		"no authorization header": "",
+		"basic authorization":     "Basic dXNlcjpwYXNz",
+		"invalid token":           "Negotiate invalid",
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "https://rancher.example.com/v3-public/kerberosProviders/kerberos?action=login", nil)
+			require.NoError(t, err)
+			if header != "" {
+				req.Header.Set("Authorization", header)
+			}
+
+			_, _, err = acceptNegotiateToken(config, req)
+			assert.ErrorIs(t, err, errNegotiateRequired)
+		})
+	}
+}
+
+func TestSetNegotiateChallenge(t *testing.T) {
+	w := &discardResponseWriter{header: http.Header{}}
+	SetNegotiateChallenge(w, httperror.WrapAPIError(errNegotiateRequired, httperror.Unauthorized, "Negotiate authentication required"))
+	assert.Equal(t, "Negotiate", w.Header().Get("WWW-Authenticate"))
+
+	w = &discardResponseWriter{header: http.Header{}}
+	SetNegotiateChallenge(w, httperror.NewAPIError(httperror.PermissionDenied, "Permission denied"))
+	assert.Empty(t, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestParseKeytab(t *testing.T) {
+	kt, err := parseKeytab(newKeytab(t, "HTTP/rancher.example.com", "EXAMPLE.COM"))
+	require.NoError(t, err)
+	assert.True(t, hasServicePrincipal(kt, "HTTP/rancher.example.com"))
+	assert.True(t, hasServicePrincipal(kt, "HTTP/rancher.example.com@EXAMPLE.COM"))
+	assert.False(t, hasServicePrincipal(kt, "HTTP/rancher.example.com@OTHER.COM"))
+	assert.False(t, hasServicePrincipal(kt, "HTTP/other.example.com"))
+
+	_, err = parseKeytab("not base64!")
+	assert.Error(t, err)
+
+	_, err = parseKeytab(base64.StdEncoding.EncodeToString([]byte("not a keytab")))
+	assert.Error(t, err)
+}
+
+func TestDirectoryUser(t *testing.T) {
+	directory := &mockDirectoryProvider{
+		users: []v3.Principal{
+			{ObjectMeta: metav1.ObjectMeta{Name: "activedirectory_user://CN=jdoe2,DC=example,DC=com"}, LoginName: "jdoe2", PrincipalType: common.UserPrincipalType},
+			{ObjectMeta: metav1.ObjectMeta{Name: "activedirectory_user://CN=jdoe,DC=example,DC=com"}, LoginName: "jdoe", PrincipalType: common.UserPrincipalType},
+		},
+		groups: []v3.Principal{
+			{ObjectMeta: metav1.ObjectMeta{Name: "activedirectory_group://CN=admins,DC=example,DC=com"}, PrincipalType: common.GroupPrincipalType},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		username    string
+		realm       string
+		realms      []string
+		allowed     bool
+		wantErrCode *httperror.ErrorCode
+		wantUser    string
+	}{
+		{
+			name:     "user found in directory",
+			username: "JDoe",
+			realm:    "EXAMPLE.COM",
+			allowed:  true,
+			wantUser: "activedirectory_user://CN=jdoe,DC=example,DC=com",
+		},
+		{
+			name:     "realm allowed",
+			username: "jdoe",
+			realm:    "EXAMPLE.COM",
+			realms:   []string{"example.com"},
+			allowed:  true,
+			wantUser: "activedirectory_user://CN=jdoe,DC=example,DC=com",
+		},
+		{
+			name:        "realm not allowed",
+			username:    "jdoe",
+			realm:       "OTHER.COM",
+			realms:      []string{"EXAMPLE.COM"},
+			allowed:     true,
+			wantErrCode: &httperror.PermissionDenied,
+		},
+		{
+			name:        "user not found in directory",
+			username:    "unknown",
+			realm:       "EXAMPLE.COM",
+			allowed:     true,
+			wantErrCode: &httperror.Unauthorized,
+		},
+		{
+			name:        "access denied",
+			username:    "jdoe",
+			realm:       "EXAMPLE.COM",
+			allowed:     false,
+			wantErrCode: &httperror.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &kerberosProvider{
+				userMGR: &mockUserManager{allowed: tt.allowed},
+				getProvider: func(name string) (common.AuthProvider, error) {
+					if name != "activedirectory" {
+						return nil, errors.New("no such provider")
+					}
+					return directory, nil
+				},
+			}
+			config := &v3.KerberosConfig{DirectoryProvider: "activedirectory", Realms: tt.realms}
+
+			user, groups, err := provider.directoryUser(config, tt.username, tt.realm)
+			if tt.wantErrCode != nil {
+				require.Error(t, err)
+				apiErr, ok := err.(*httperror.APIError)
+				require.True(t, ok)
+				assert.Equal(t, *tt.wantErrCode, apiErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, user.Name)
+			assert.True(t, user.Me)
+			assert.Equal(t, directory.groups, groups)
+		})
+	}
+}
+
+func newKeytab(t *testing.T, spn, realm string) string {
+	t.Helper()
+	kt := keytab.New()
+	require.NoError(t, kt.AddEntry(spn, realm, "password", time.Now(), 1, etypeID.AES256_CTS_HMAC_SHA1_96))
+	b, err := kt.Marshal()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+type mockUserManager struct {
+	allowed bool
+}
+
+func (m *mockUserManager) SetPrincipalOnCurrentUser(apiContext *types.APIContext, principal v3.Principal) (*v3.User, error) {
+	panic("unimplemented")
+}
+
+func (m *mockUserManager) CheckAccess(accessMode string, allowedPrincipalIDs []string, userPrincipalID string, groups []v3.Principal) (bool, error) {
+	return m.allowed, nil
+}
+
+// mockDirectoryProvider returns all of its users from every user search and all of its groups for every user.
+type mockDirectoryProvider struct {
+	common.AuthProvider
+	users  []v3.Principal
+	groups []v3.Principal
+}
+
+func (m *mockDirectoryProvider) GetName() string {
+	return "activedirectory"
+}
+
+func (m *mockDirectoryProvider) SearchPrincipals(name, principalType string, myToken accessor.TokenAccessor) ([]v3.Principal, error) {
+	return m.users, nil
+}
+
+func (m *mockDirectoryProvider) RefetchGroupPrincipals(principalID string, secret string) ([]v3.Principal, error) {
+	return m.groups, nil
+}
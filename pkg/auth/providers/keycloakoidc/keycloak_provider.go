@@ -3,11 +3,16 @@
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
 
 	gooidc "github.com/coreos/go-oidc/v3/oidc"
 	"github.com/pkg/errors"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/convert"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth/accessor"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
@@ -21,6 +26,7 @@
 	"golang.org/x/oauth2"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
@@ -34,7 +40,7 @@ type keyCloakOIDCProvider struct {
 }
 
 func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, userMGR user.Manager, tokenMGR *tokens.Manager) common.AuthProvider {
-	return &keyCloakOIDCProvider{
+	k := &keyCloakOIDCProvider{
 		oidc.OpenIDCProvider{
 			Name:        Name,
 			Type:        client.KeyCloakOIDCConfigType,
@@ -45,12 +51,125 @@ func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, userMGR user.
 			TokenMGR:    tokenMGR,
 		},
 	}
+	k.GroupNamesFromClaims = k.getRoleGroupNames
+	return k
 }
 
 func (k *keyCloakOIDCProvider) GetName() string {
 	return Name
 }
 
+// getKeyCloakOIDCConfig returns the Keycloak specific part of the stored config.
+// Secrets are not resolved, use GetOIDCConfig to get the full OIDC configuration.
+func (k *keyCloakOIDCProvider) getKeyCloakOIDCConfig() (*v32.KeyCloakOIDCConfig, error) {
+	authConfigObj, err := k.AuthConfigs.ObjectClient().UnstructuredClient().Get(k.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve KeyCloakOIDCConfig, error: %w", err)
+	}
+	u, ok := authConfigObj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve KeyCloakOIDCConfig, cannot read k8s Unstructured data")
+	}
+	storedConfig := &v32.KeyCloakOIDCConfig{}
+	if err := common.Decode(u.UnstructuredContent(), storedConfig); err != nil {
+		return nil, fmt.Errorf("unable to decode KeyCloakOIDCConfig: %w", err)
+	}
+	return storedConfig, nil
+}
+
+// getRoleGroupNames returns the names of the groups derived from the realm and client roles in the claims.
+func (k *keyCloakOIDCProvider) getRoleGroupNames(claimInfo oidc.ClaimInfo) []string {
+	config, err := k.getKeyCloakOIDCConfig()
+	if err != nil {
+		logrus.Errorf("[keycloak oidc] getRoleGroupNames: error fetching config: %v", err)
+		return nil
+	}
+	return roleGroupNames(config, claimInfo)
+}
+
+// roleGroupNames maps the roles found in the realm_access and resource_access claims to group names
+// according to the role mapping of the config.
+func roleGroupNames(config *v32.KeyCloakOIDCConfig, claimInfo oidc.ClaimInfo) []string {
+	if !roleMappingEnabled(config) {
+		return nil
+	}
+	var groups []string
+	if config.RealmRolesAsGroups {
+		for _, role := range claimInfo.RealmAccess.Roles {
+			if role != "" {
+				groups = append(groups, config.RoleGroupPrefix+role)
+			}
+		}
+	}
+	for _, clientID := range config.ClientRolesAsGroups {
+		for _, role := range claimInfo.ResourceAccess[clientID].Roles {
+			if role != "" {
+				groups = append(groups, config.RoleGroupPrefix+clientID+":"+role)
+			}
+		}
+	}
+	return groups
+}
+
+// roleMappingEnabled returns true if any roles are mapped to group principals.
+// A prefix is required since realm roles could not be told apart from plain group names otherwise.
+func roleMappingEnabled(config *v32.KeyCloakOIDCConfig) bool {
+	return config.RoleGroupPrefix != "" && (config.RealmRolesAsGroups || len(config.ClientRolesAsGroups) > 0)
+}
+
+// isRoleGroupName returns true if the name has the form of a group derived from a role.
+func isRoleGroupName(config *v32.KeyCloakOIDCConfig, name string) bool {
+	if !roleMappingEnabled(config) {
+		return false
+	}
+	return strings.HasPrefix(name, config.RoleGroupPrefix) && len(name) > len(config.RoleGroupPrefix)
+}
+
+// unknownGroupAccount returns the account of a group principal keycloak doesn't know, which was derived from a role if
+// its name has the form of one. Other unknown groups are returned as they are, without a name.
+func unknownGroupAccount(config *v32.KeyCloakOIDCConfig, externalID string, acct account) account {
+	if isRoleGroupName(config, externalID) {
+		return account{Name: externalID}
+	}
+	return acct
+}
+
+// CustomizeSchema refuses the configs mapping roles to group principals without a role group prefix.
+func (k *keyCloakOIDCProvider) CustomizeSchema(schema *types.Schema) {
+	k.OpenIDCProvider.CustomizeSchema(schema)
+	schema.Validator = k.validateRoleMapping
+}
+
+// validateRoleMapping returns an error if the updated config maps roles to group principals without a role group
+// prefix. The fields missing from the update keep their stored value.
+func (k *keyCloakOIDCProvider) validateRoleMapping(request *types.APIContext, _ *types.Schema, data map[string]interface{}) error {
+	if request.Method != http.MethodPut && request.Method != http.MethodPost {
+		return nil
+	}
+	config := &v32.KeyCloakOIDCConfig{}
+	if request.Method == http.MethodPut {
+		stored, err := k.getKeyCloakOIDCConfig()
+		if err != nil {
+			return err
+		}
+		config = stored
+	}
+	if value, ok := data[client.KeyCloakOIDCConfigFieldRealmRolesAsGroups]; ok {
+		config.RealmRolesAsGroups = convert.ToBool(value)
+	}
+	if value, ok := data[client.KeyCloakOIDCConfigFieldClientRolesAsGroups]; ok {
+		config.ClientRolesAsGroups = convert.ToStringSlice(value)
+	}
+	if value, ok := data[client.KeyCloakOIDCConfigFieldRoleGroupPrefix]; ok {
+		config.RoleGroupPrefix = convert.ToString(value)
+	}
+	if (config.RealmRolesAsGroups || len(config.ClientRolesAsGroups) > 0) && config.RoleGroupPrefix == "" {
+		return httperror.NewAPIError(httperror.InvalidBodyContent,
+			"roleGroupPrefix is required to map roles to groups, it tells them apart from the keycloak groups")
+	}
+	return nil
+}
+
 func (k *keyCloakOIDCProvider) newClient(config *v32.OIDCConfig, token accessor.TokenAccessor) (*KeyCloakClient, error) {
 	// creating context for new client and for refreshing oauth token if needed
 	ctx, err := oidc.AddCertKeyToContext(context.Background(), config.Certificate, config.PrivateKey)
@@ -96,6 +215,12 @@ func (k *keyCloakOIDCProvider) SearchPrincipals(searchValue, principalType strin
 		p := k.toPrincipal(acct.Type, acct, token)
 		principals = append(principals, p)
 	}
+	// groups derived from roles are not known to the keycloak groups API, so allow searching them by their prefixed name
+	if principalType == "" || principalType == GroupType {
+		if kcConfig, err := k.getKeyCloakOIDCConfig(); err == nil && isRoleGroupName(kcConfig, searchValue) {
+			principals = append(principals, k.toPrincipal(GroupType, account{Name: searchValue}, token))
+		}
+	}
 	return principals, nil
 }
 
@@ -152,6 +277,11 @@ func (k *keyCloakOIDCProvider) GetPrincipal(principalID string, token accessor.T
 	if err != nil {
 		return v3.Principal{}, err
 	}
+	if principalType == GroupType && acct.Name == "" {
+		if kcConfig, err := k.getKeyCloakOIDCConfig(); err == nil {
+			acct = unknownGroupAccount(kcConfig, externalID, acct)
+		}
+	}
 	princ := k.toPrincipal(principalType, acct, token)
 	return princ, err
 }
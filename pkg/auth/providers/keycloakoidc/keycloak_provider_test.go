@@ -0,0 +1,121 @@
+package keycloakoidc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/oidc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleGroupNames(t *testing.T) {
+	claimInfo := oidc.ClaimInfo{
+		RealmAccess: oidc.RoleClaim{Roles: []string{"admin", "offline_access"}},
+		ResourceAccess: map[string]oidc.RoleClaim{
+			"rancher": {Roles: []string{"operator"}},
+			"other":   {Roles: []string{"viewer"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config *v32.KeyCloakOIDCConfig
+		want   []string
+	}{
+		{
+			name:   "mapping disabled",
+			config: &v32.KeyCloakOIDCConfig{},
+		},
+		{
+			name:   "realm roles without prefix",
+			config: &v32.KeyCloakOIDCConfig{RealmRolesAsGroups: true},
+		},
+		{
+			name:   "realm roles",
+			config: &v32.KeyCloakOIDCConfig{RealmRolesAsGroups: true, RoleGroupPrefix: "role:"},
+			want:   []string{"role:admin", "role:offline_access"},
+		},
+		{
+			name:   "client roles with prefix",
+			config: &v32.KeyCloakOIDCConfig{ClientRolesAsGroups: []string{"rancher", "missing"}, RoleGroupPrefix: "role:"},
+			want:   []string{"role:rancher:operator"},
+		},
+		{
+			name: "realm and client roles with prefix",
+			config: &v32.KeyCloakOIDCConfig{
+				RealmRolesAsGroups:  true,
+				ClientRolesAsGroups: []string{"rancher"},
+				RoleGroupPrefix:     "kc-",
+			},
+			want: []string{"kc-admin", "kc-offline_access", "kc-rancher:operator"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, roleGroupNames(tt.config, claimInfo))
+		})
+	}
+}
+
+func TestIsRoleGroupName(t *testing.T) {
+	config := &v32.KeyCloakOIDCConfig{RealmRolesAsGroups: true, RoleGroupPrefix: "role:"}
+
+	assert.True(t, isRoleGroupName(config, "role:admin"))
+	assert.False(t, isRoleGroupName(config, "role:"))
+	assert.False(t, isRoleGroupName(config, "admin"))
+	assert.False(t, isRoleGroupName(&v32.KeyCloakOIDCConfig{RoleGroupPrefix: "role:"}, "role:admin"))
+	assert.False(t, isRoleGroupName(&v32.KeyCloakOIDCConfig{RealmRolesAsGroups: true}, "admin"))
+}
+
+func TestUnknownGroupAccount(t *testing.T) {
+	config := &v32.KeyCloakOIDCConfig{RealmRolesAsGroups: true, RoleGroupPrefix: "role:"}
+
+	// Only the names of the groups derived from roles make a principal out of a group keycloak doesn't know.
+	assert.Equal(t, account{Name: "role:admin"}, unknownGroupAccount(config, "role:admin", account{}))
+	assert.Equal(t, account{}, unknownGroupAccount(config, "admins", account{}))
+	assert.Equal(t, account{}, unknownGroupAccount(&v32.KeyCloakOIDCConfig{RealmRolesAsGroups: true}, "admins", account{}))
+}
+
+func TestValidateRoleMapping(t *testing.T) {
+	k := &keyCloakOIDCProvider{}
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "no role mapping",
+			data: map[string]interface{}{},
+		},
+		{
+			name:    "realm roles without prefix",
+			data:    map[string]interface{}{"realmRolesAsGroups": true},
+			wantErr: true,
+		},
+		{
+			name:    "client roles with an empty prefix",
+			data:    map[string]interface{}{"clientRolesAsGroups": []interface{}{"rancher"}, "roleGroupPrefix": ""},
+			wantErr: true,
+		},
+		{
+			name: "realm roles with prefix",
+			data: map[string]interface{}{"realmRolesAsGroups": true, "roleGroupPrefix": "role:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &types.APIContext{Method: http.MethodPost}
+			err := k.validateRoleMapping(request, nil, tt.data)
+			if tt.wantErr {
+				assert.True(t, httperror.IsAPIError(err), "expected an API error, got %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package ldap
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"reflect"
@@ -10,6 +11,8 @@
 	"github.com/pkg/errors"
 	"github.com/rancher/norman/httperror"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/attributelabels"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/providers/common/ldap"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,6 +20,11 @@
 
 var operationalAttrList = []string{"1.1", "+", "*"}
 
+// userSearchAttributes returns the object class and the attributes mapped to user labels.
+func userSearchAttributes() []string {
+	return append([]string{ObjectClass}, attributelabels.Attributes()...)
+}
+
 func (p *ldapProvider) loginUser(lConn ldapv3.Client, credentials *v3.BasicLogin, config *v3.LdapConfig) (v3.Principal, []v3.Principal, error) {
 	logrus.Debug("Now generating Ldap token")
 
@@ -49,7 +57,7 @@ func (p *ldapProvider) loginUser(lConn ldapv3.Client, credentials *v3.BasicLogin
 	searchRequest := ldap.NewWholeSubtreeSearchRequest(
 		config.UserSearchBase,
 		filter,
-		config.GetUserSearchAttributes(ObjectClass),
+		config.GetUserSearchAttributes(userSearchAttributes()...),
 	)
 
 	result, err := lConn.Search(searchRequest)
@@ -323,7 +331,7 @@ func (p *ldapProvider) getPrincipal(distinguishedName string, scope string, conf
 
 	var attrs []string
 	if strings.EqualFold("user", entityType) {
-		attrs = config.GetUserSearchAttributes(ObjectClass)
+		attrs = config.GetUserSearchAttributes(userSearchAttributes()...)
 	} else {
 		attrs = config.GetGroupSearchAttributes(ObjectClass)
 	}
@@ -448,7 +456,7 @@ func (p *ldapProvider) searchLdap(query string, scope string, config *v3.LdapCon
 		search = ldap.NewWholeSubtreeSearchRequest(
 			searchDomain,
 			query,
-			config.GetUserSearchAttributes(ObjectClass),
+			config.GetUserSearchAttributes(userSearchAttributes()...),
 		)
 	} else {
 		if config.GroupSearchBase != "" {
@@ -520,6 +528,187 @@ func (p *ldapProvider) permissionCheck(attributes []*ldapv3.EntryAttribute, conf
 	return ldap.HasPermission(attributes, userObjectClass, userEnabledAttribute, userDisabledBitMask)
 }
 
+// HealthCheck connects to the LDAP server and binds with the service account.
+func (p *ldapProvider) HealthCheck() error {
+	config, caPool, err := p.getLDAPConfig(p.authConfigs.ObjectClient().UnstructuredClient())
+	if err != nil {
+		return err
+	}
+	return p.bindServiceAccount(config, caPool)
+}
+
+// Probe checks that each LDAP server accepts connections and that the service account can bind,
+// and reports the expiry of the CA certificates.
+func (p *ldapProvider) Probe(ctx context.Context) (*v3.AuthConfigConnectivity, error) {
+	config, caPool, err := p.getLDAPConfig(p.authConfigs.ObjectClient().UnstructuredClient())
+	if err != nil {
+		return nil, err
+	}
+
+	connectivity := &v3.AuthConfigConnectivity{
+		Endpoints:   ldap.ProbeServers(ctx, config.Servers, config.Port),
+		Credentials: common.CertificateExpiries("certificate", config.Certificate),
+	}
+	if err := p.bindServiceAccount(config, caPool); err != nil {
+		connectivity.Error = err.Error()
+	}
+	return connectivity, nil
+}
+
+func (p *ldapProvider) bindServiceAccount(config *v3.LdapConfig, caPool *x509.CertPool) error {
+	lConn, err := ldap.Connect(config, caPool)
+	if err != nil {
+		return err
+	}
+	defer lConn.Close()
+
+	return ldap.AuthenticateServiceAccountUser(config.ServiceAccountPassword, config.ServiceAccountDistinguishedName, "", lConn)
+}
+
+// UserExists looks up the user principal on the LDAP server with the service account.
+func (p *ldapProvider) UserExists(principalID string) (bool, error) {
+	config, caPool, err := p.getLDAPConfig(p.authConfigs.ObjectClient().UnstructuredClient())
+	if err != nil {
+		return false, err
+	}
+	lConn, err := ldap.Connect(config, caPool)
+	if err != nil {
+		return false, err
+	}
+	defer lConn.Close()
+
+	err = ldap.AuthenticateServiceAccountUser(config.ServiceAccountPassword, config.ServiceAccountDistinguishedName, "", lConn)
+	if err != nil {
+		return false, err
+	}
+
+	distinguishedName, _, err := p.getDNAndScopeFromPrincipalID(principalID)
+	if err != nil {
+		return false, err
+	}
+
+	searchRequest := ldap.NewBaseObjectSearchRequest(
+		distinguishedName,
+		fmt.Sprintf("(%s=%s)", ObjectClass, ldap.SanitizeAttr(config.UserObjectClass)),
+		[]string{ObjectClass},
+	)
+	result, err := lConn.Search(searchRequest)
+	if err != nil {
+		if ldapv3.IsErrorWithCode(err, ldapv3.LDAPResultNoSuchObject) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return len(result.Entries) > 0, nil
+}
+
+// LookupGroup looks up the group principal on the LDAP server with the service account.
+func (p *ldapProvider) LookupGroup(principalID string) (v3.Principal, error) {
+	config, caPool, err := p.getLDAPConfig(p.authConfigs.ObjectClient().UnstructuredClient())
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	lConn, err := ldap.Connect(config, caPool)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	defer lConn.Close()
+
+	// Bind first so that a failing service account isn't mistaken for the group, as getPrincipal falls back to the DN.
+	err = ldap.AuthenticateServiceAccountUser(config.ServiceAccountPassword, config.ServiceAccountDistinguishedName, "", lConn)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+
+	distinguishedName, scope, err := p.getDNAndScopeFromPrincipalID(principalID)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	if scope != p.groupScope {
+		return v3.Principal{}, fmt.Errorf("%s is not a group principal", principalID)
+	}
+
+	principal, err := p.getPrincipal(distinguishedName, scope, config, caPool)
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	if principal == nil {
+		return v3.Principal{}, fmt.Errorf("group %s not found", distinguishedName)
+	}
+	return *principal, nil
+}
+
+// ListGroupMembers lists the users whose member attribute holds the group on the LDAP server with the service account.
+func (p *ldapProvider) ListGroupMembers(principalID string, limit int, continueToken string) ([]v3.Principal, string, error) {
+	if p.samlSearchProvider() {
+		return nil, "", fmt.Errorf("%s can't list group members", p.providerName)
+	}
+
+	config, caPool, err := p.getLDAPConfig(p.authConfigs.ObjectClient().UnstructuredClient())
+	if err != nil {
+		return nil, "", err
+	}
+	lConn, err := ldap.Connect(config, caPool)
+	if err != nil {
+		return nil, "", err
+	}
+	defer lConn.Close()
+
+	distinguishedName, scope, err := p.getDNAndScopeFromPrincipalID(principalID)
+	if err != nil {
+		return nil, "", err
+	}
+	if scope != p.groupScope {
+		return nil, "", fmt.Errorf("%s is not a group principal", principalID)
+	}
+
+	query := fmt.Sprintf("(&(%s=%s)(%s=%s))",
+		ObjectClass,
+		ldap.SanitizeAttr(config.UserObjectClass),
+		ldap.SanitizeAttr(config.UserMemberAttribute),
+		ldapv3.EscapeFilter(distinguishedName),
+	)
+	members, err := p.searchLdap(query, p.userScope, config, lConn)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return common.PagePrincipals(members, limit, continueToken)
+}
+
+// SearchUsersByAttribute searches the users whose attribute holds the value on the LDAP server with the service account.
+func (p *ldapProvider) SearchUsersByAttribute(attribute, value string) ([]v3.Principal, error) {
+	if !ldap.IsValidAttr(attribute) {
+		return nil, fmt.Errorf("invalid attribute %q", attribute)
+	}
+
+	config, caPool, err := p.getLDAPConfig(p.authConfigs.ObjectClient().UnstructuredClient())
+	if err != nil {
+		return nil, err
+	}
+	if config.UserSearchFilter != "" {
+		if _, err := ldapv3.CompileFilter(config.UserSearchFilter); err != nil {
+			return nil, fmt.Errorf("invalid user search filter")
+		}
+	}
+
+	lConn, err := ldap.Connect(config, caPool)
+	if err != nil {
+		return nil, err
+	}
+	defer lConn.Close()
+
+	query := fmt.Sprintf("(&(%s=%s)(%s=%s)%s)",
+		ObjectClass,
+		ldap.SanitizeAttr(config.UserObjectClass),
+		attribute,
+		ldapv3.EscapeFilter(value),
+		config.UserSearchFilter,
+	)
+	return p.searchLdap(query, p.userScope, config, lConn)
+}
+
 func (p *ldapProvider) RefetchGroupPrincipals(principalID string, secret string) ([]v3.Principal, error) {
 	config, caPool, err := p.getLDAPConfig(p.authConfigs.ObjectClient().UnstructuredClient())
 	if err != nil {
@@ -544,7 +733,7 @@ func (p *ldapProvider) RefetchGroupPrincipals(principalID string, secret string)
 	searchRequest := ldap.NewBaseObjectSearchRequest(
 		distinguishedName,
 		fmt.Sprintf("(%s=%s)", ObjectClass, config.UserObjectClass),
-		config.GetUserSearchAttributes(ObjectClass),
+		config.GetUserSearchAttributes(userSearchAttributes()...),
 	)
 
 	result, err := lConn.Search(searchRequest)
@@ -15,6 +15,7 @@
 	"github.com/rancher/rancher/pkg/auth/accessor"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/providers/common/ldap"
+	"github.com/rancher/rancher/pkg/auth/tracing"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -151,13 +152,15 @@ func (p *ldapProvider) AuthenticateUser(ctx context.Context, input interface{})
 		return v3.Principal{}, nil, "", errors.New("can't find authprovider")
 	}
 
+	_, span := tracing.StartSpan(ctx, "ldap.connect")
 	lConn, err := ldap.Connect(config, caPool)
+	tracing.End(span, err)
 	if err != nil {
 		return v3.Principal{}, nil, "", err
 	}
 	defer lConn.Close()
 
-	principal, groupPrincipal, err := p.loginUser(lConn, login, config)
+	principal, groupPrincipal, err := p.loginUser(ldap.Traced(ctx, lConn), login, config)
 	if err != nil {
 		return v3.Principal{}, nil, "", err
 	}
@@ -361,7 +364,7 @@ func (p *ldapProvider) samlSearchGetPrincipal(
 		searchRequest = ldap.NewWholeSubtreeSearchRequest(
 			config.UserSearchBase,
 			filter,
-			config.GetUserSearchAttributes(ObjectClass),
+			config.GetUserSearchAttributes(userSearchAttributes()...),
 		)
 	} else {
 		filter := fmt.Sprintf(
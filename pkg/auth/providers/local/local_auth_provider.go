@@ -11,12 +11,15 @@
 	"github.com/rancher/norman/types"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/webauthn"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
@@ -35,15 +38,19 @@
 	searchIndexDefaultLen = 6
 )
 
-var invalidHash, _ = bcrypt.GenerateFromPassword([]byte("invalid"), bcrypt.DefaultCost)
+var invalidHash, _ = passwordhash.Hash("invalid")
 
 type Provider struct {
-	userLister   v3.UserLister
-	groupLister  v3.GroupLister
-	userIndexer  cache.Indexer
-	gmIndexer    cache.Indexer
-	groupIndexer cache.Indexer
-	tokenMGR     *tokens.Manager
+	userLister        v3.UserLister
+	users             v3.UserInterface
+	groupLister       v3.GroupLister
+	userIndexer       cache.Indexer
+	gmIndexer         cache.Indexer
+	groupIndexer      cache.Indexer
+	tokenMGR          *tokens.Manager
+	securityKeys      *webauthn.Manager
+	passwordHistory   *passwordpolicy.History
+	emailVerification *emailverification.Manager
 }
 
 func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, tokenMGR *tokens.Manager) common.AuthProvider {
@@ -60,12 +67,16 @@ func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, tokenMGR *tok
 	gInformer.AddIndexers(gIndexers)
 
 	l := &Provider{
-		userIndexer:  informer.GetIndexer(),
-		gmIndexer:    gmInformer.GetIndexer(),
-		groupLister:  mgmtCtx.Management.Groups("").Controller().Lister(),
-		groupIndexer: gInformer.GetIndexer(),
-		userLister:   mgmtCtx.Management.Users("").Controller().Lister(),
-		tokenMGR:     tokenMGR,
+		userIndexer:       informer.GetIndexer(),
+		gmIndexer:         gmInformer.GetIndexer(),
+		groupLister:       mgmtCtx.Management.Groups("").Controller().Lister(),
+		groupIndexer:      gInformer.GetIndexer(),
+		userLister:        mgmtCtx.Management.Users("").Controller().Lister(),
+		users:             mgmtCtx.Management.Users(""),
+		tokenMGR:          tokenMGR,
+		securityKeys:      webauthn.NewManager(mgmtCtx.Wrangler.Core.Secret()),
+		passwordHistory:   passwordpolicy.NewHistory(mgmtCtx.Wrangler.Core.Secret()),
+		emailVerification: emailverification.NewManager(mgmtCtx),
 	}
 	return l
 }
@@ -125,14 +136,27 @@ func (l *Provider) AuthenticateUser(ctx context.Context, input interface{}) (v3.
 	if err != nil {
 		// If the user don't exist the password is evaluated
 		// to avoid user enumeration via timing attack (time based side-channel).
-		bcrypt.CompareHashAndPassword(invalidHash, []byte(pwd))
+		passwordhash.Verify(invalidHash, pwd)
 		logrus.Debugf("Get User [%s] failed during Authentication: %v", username, err)
 		return v3.Principal{}, nil, "", authFailedError
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(pwd)); err != nil {
+	if pwd == "" && localInput.WebAuthn.CredentialID != "" {
+		// Users log in with a passkey instead of their password.
+		if err := l.securityKeys.VerifyLogin(user.Name, localInput.WebAuthn, true); err != nil {
+			logrus.Debugf("Passkey authentication failed for User [%s]: %v", username, err)
+			return v3.Principal{}, nil, "", authFailedError
+		}
+	} else if err := passwordhash.Verify(user.Password, pwd); err != nil {
 		logrus.Debugf("Authentication failed for User [%s]: %v", username, err)
 		return v3.Principal{}, nil, "", authFailedError
+	} else if err := l.updatePassword(user, pwd); err != nil {
+		return v3.Principal{}, nil, "", err
+	}
+
+	// The users who haven't verified their email address yet are sent a new link.
+	if err := l.emailVerification.CheckLogin(user); err != nil {
+		return v3.Principal{}, nil, "", err
 	}
 
 	principalID := getLocalPrincipalID(user)
@@ -147,6 +171,46 @@ func (l *Provider) AuthenticateUser(ctx context.Context, input interface{}) (v3.
 	return userPrincipal, groupPrincipals, "", nil
 }
 
+// updatePassword rehashes the password of the user when its hash is outdated, and requires them to change it when
+// it's older than the maximum age of the password policy.
+func (l *Provider) updatePassword(user *v3.User, pwd string) error {
+	var expired bool
+	if !user.MustChangePassword {
+		var err error
+		if expired, err = l.passwordHistory.Expired(user, passwordpolicy.Current()); err != nil {
+			return err
+		}
+	}
+	rehash := passwordhash.NeedsRehash(user.Password)
+	if !expired && !rehash {
+		return nil
+	}
+
+	user = user.DeepCopy()
+	if rehash {
+		hash, err := passwordhash.Hash(pwd)
+		if err != nil {
+			return fmt.Errorf("rehashing the password of user %s: %w", user.Name, err)
+		}
+		user.Password = hash
+	}
+	if expired {
+		user.MustChangePassword = true
+	}
+	if _, err := l.users.Update(user); err != nil {
+		if !expired {
+			// The password is rehashed at a later login.
+			logrus.Warnf("Failed to rehash the password of user %s: %v", user.Name, err)
+			return nil
+		}
+		return fmt.Errorf("requiring user %s to change their expired password: %w", user.Name, err)
+	}
+	if expired {
+		logrus.Infof("The password of user %s has expired, they must change it", user.Name)
+	}
+	return nil
+}
+
 func getLocalPrincipalID(user *v3.User) string {
 	// TODO error condition handling: no principal, more than one that would match
 	var principalID string
@@ -0,0 +1,307 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	jose "github.com/go-jose/go-jose/v3"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// minKeyMissRefetchInterval limits how often an unknown key ID triggers a refetch of the keys,
+	// so that tokens with bogus key IDs can't be used to flood the identity provider.
+	minKeyMissRefetchInterval = 10 * time.Second
+
+	jwksFetchTimeout = 30 * time.Second
+)
+
+// keySets is shared by all the OIDC providers, so that keys are fetched once per JWKS URL instead of on every login.
+var keySets = newKeySetCache()
+
+type keySetCache struct {
+	mu   sync.Mutex
+	sets map[string]*cachedKeySet
+}
+
+func newKeySetCache() *keySetCache {
+	return &keySetCache{sets: map[string]*cachedKeySet{}}
+}
+
+// get returns the key set of a JWKS URL. The keys are fetched with the HTTP client of the context, if any,
+// so that client certificates configured for the provider are used.
+func (c *keySetCache) get(ctx context.Context, jwksURL string) *cachedKeySet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	set, ok := c.sets[jwksURL]
+	if !ok {
+		set = newCachedKeySet(jwksURL)
+		c.sets[jwksURL] = set
+	}
+	if client, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		set.setClient(client)
+	}
+	return set
+}
+
+type cachedKey struct {
+	key jose.JSONWebKey
+	// removedAt is when the key stopped being published by the identity provider. It's zero while it's published.
+	removedAt time.Time
+}
+
+// cachedKeySet is an oidc.KeySet tolerating rotations of the signing keys:
+//   - keys are refreshed in the background once they're older than auth-oidc-jwks-refresh-interval-minutes,
+//     while the cached keys keep being used,
+//   - a token signed with an unknown key ID triggers a refetch, so that new keys are picked up immediately,
+//   - keys that are no longer published are still accepted for auth-oidc-jwks-stale-key-grace-minutes.
+//
+// If the identity provider can't be reached, the cached keys are used until it's back.
+type cachedKeySet struct {
+	url string
+	now func() time.Time
+
+	mu        sync.Mutex
+	client    *http.Client
+	keys      map[string]cachedKey
+	fetchedAt time.Time
+	lastFetch time.Time
+	fetchErr  error
+	// fetching is closed once the running fetch is done. It's nil while no fetch is running.
+	fetching chan struct{}
+}
+
+func newCachedKeySet(jwksURL string) *cachedKeySet {
+	return &cachedKeySet{
+		url:    jwksURL,
+		now:    time.Now,
+		client: http.DefaultClient,
+		keys:   map[string]cachedKey{},
+	}
+}
+
+func (s *cachedKeySet) setClient(client *http.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+}
+
+// VerifySignature verifies the signature of a JWT with the cached keys and returns its payload.
+func (s *cachedKeySet) VerifySignature(ctx context.Context, jwt string) ([]byte, error) {
+	jws, err := jose.ParseSigned(jwt)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: malformed jwt: %w", err)
+	}
+	keyID := ""
+	if len(jws.Signatures) > 0 {
+		keyID = jws.Signatures[0].Header.KeyID
+	}
+
+	s.mu.Lock()
+	empty := len(s.keys) == 0
+	now := s.now()
+	stale := now.Sub(s.fetchedAt) >= refreshInterval() && now.Sub(s.lastFetch) >= minKeyMissRefetchInterval
+	s.mu.Unlock()
+
+	if empty {
+		if err := s.waitFetch(ctx); err != nil {
+			return nil, err
+		}
+	} else if stale {
+		s.fetch()
+	}
+
+	if payload, err := s.verify(jws, keyID); err == nil {
+		return payload, nil
+	}
+
+	// The token may be signed with a key published since the keys were last fetched.
+	s.mu.Lock()
+	canRefetch := s.now().Sub(s.lastFetch) >= minKeyMissRefetchInterval
+	s.mu.Unlock()
+	if !canRefetch {
+		return nil, fmt.Errorf("oidc: failed to verify id token signature")
+	}
+	logrus.Debugf("[generic oidc] no cached key verifies the token signed with key ID %q, refetching keys from %s", keyID, s.url)
+	if err := s.waitFetch(ctx); err != nil {
+		return nil, err
+	}
+	return s.verify(jws, keyID)
+}
+
+// verify verifies the signature of a JWT with the keys matching its key ID, or all the keys if it has none.
+func (s *cachedKeySet) verify(jws *jose.JSONWebSignature, keyID string) ([]byte, error) {
+	s.mu.Lock()
+	grace := staleKeyGrace()
+	now := s.now()
+	var keys []jose.JSONWebKey
+	for id, cached := range s.keys {
+		if !cached.removedAt.IsZero() && now.Sub(cached.removedAt) >= grace {
+			delete(s.keys, id)
+			continue
+		}
+		if keyID == "" || keyID == cached.key.KeyID {
+			keys = append(keys, cached.key)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, key := range keys {
+		if payload, err := jws.Verify(&key); err == nil {
+			return payload, nil
+		}
+	}
+	return nil, fmt.Errorf("oidc: failed to verify id token signature")
+}
+
+// fetch starts fetching the keys unless a fetch is already running, and returns a channel closed once it's done.
+// The fetch isn't bound to the context of any request, since all the logins of the provider wait for it.
+func (s *cachedKeySet) fetch() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fetching != nil {
+		return s.fetching
+	}
+	done := make(chan struct{})
+	s.fetching = done
+	s.lastFetch = s.now()
+	client := s.client
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+		defer cancel()
+		keys, err := fetchKeys(ctx, client, s.url)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetchErr = err
+		if err != nil {
+			logrus.Warnf("[generic oidc] failed to fetch the signing keys from %s: %v", s.url, err)
+		} else {
+			s.update(keys)
+		}
+		s.fetching = nil
+		close(done)
+	}()
+	return done
+}
+
+// waitFetch fetches the keys and waits for them. It only fails if no keys could ever be fetched.
+func (s *cachedKeySet) waitFetch(ctx context.Context) error {
+	select {
+	case <-s.fetch():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.keys) == 0 && s.fetchErr != nil {
+		return s.fetchErr
+	}
+	return nil
+}
+
+// update replaces the cached keys, marking the ones no longer published as removed so they're kept for the grace period.
+func (s *cachedKeySet) update(keys []jose.JSONWebKey) {
+	now := s.now()
+	published := map[string]bool{}
+	for _, key := range keys {
+		id := keyCacheID(key)
+		published[id] = true
+		s.keys[id] = cachedKey{key: key}
+	}
+	for id, cached := range s.keys {
+		if !published[id] && cached.removedAt.IsZero() {
+			logrus.Infof("[generic oidc] signing key %q is no longer published by %s, accepting it for the grace period", cached.key.KeyID, s.url)
+			cached.removedAt = now
+			s.keys[id] = cached
+		}
+	}
+	s.fetchedAt = now
+}
+
+// keyCacheID identifies a key in the cache. Keys without ID are told apart by their thumbprint.
+func keyCacheID(key jose.JSONWebKey) string {
+	if key.KeyID != "" {
+		return key.KeyID
+	}
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return fmt.Sprintf("%v", key.Key)
+	}
+	return fmt.Sprintf("%x", thumbprint)
+}
+
+func fetchKeys(ctx context.Context, client *http.Client, jwksURL string) ([]jose.JSONWebKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: can't create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: get keys failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: get keys failed: %s %s", resp.Status, body)
+	}
+
+	var keySet jose.JSONWebKeySet
+	if err := json.Unmarshal(body, &keySet); err != nil {
+		return nil, fmt.Errorf("oidc: failed to decode keys: %w", err)
+	}
+	return keySet.Keys, nil
+}
+
+// newVerifier returns a verifier of the ID tokens of a provider using the shared key sets, tolerating the configured clock skew.
+func newVerifier(ctx context.Context, provider *oidc.Provider, config *v32.OIDCConfig) (*oidc.IDTokenVerifier, error) {
+	issuer, jwksURL := config.Issuer, config.JWKSUrl
+	// Providers configured through discovery have claims telling their issuer and JWKS URL.
+	var claims struct {
+		Issuer  string `json:"issuer"`
+		JWKSURL string `json:"jwks_uri"`
+	}
+	if err := provider.Claims(&claims); err == nil {
+		issuer, jwksURL = claims.Issuer, claims.JWKSURL
+	}
+	if jwksURL == "" {
+		return nil, fmt.Errorf("oidc: no JWKS URL for issuer %s", issuer)
+	}
+
+	leeway := clockSkewLeeway()
+	return oidc.NewVerifier(issuer, keySets.get(ctx, jwksURL), &oidc.Config{
+		ClientID: config.ClientID,
+		Now: func() time.Time {
+			return time.Now().Add(-leeway)
+		},
+	}), nil
+}
+
+func refreshInterval() time.Duration {
+	return time.Duration(settings.AuthOIDCJWKSRefreshIntervalMinutes.GetInt()) * time.Minute
+}
+
+func staleKeyGrace() time.Duration {
+	return time.Duration(settings.AuthOIDCJWKSStaleKeyGraceMinutes.GetInt()) * time.Minute
+}
+
+func clockSkewLeeway() time.Duration {
+	return time.Duration(settings.AuthOIDCClockSkewLeewaySeconds.GetInt()) * time.Second
+}
@@ -0,0 +1,170 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testJWKSServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	keys     []jose.JSONWebKey
+	down     bool
+	requests int
+}
+
+func newTestJWKSServer(t *testing.T) *testJWKSServer {
+	s := &testJWKSServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		if s.down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testJWKSServer) publish(keys ...*rsa.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = nil
+	for _, key := range keys {
+		s.keys = append(s.keys, jose.JSONWebKey{Key: key.Public(), KeyID: keyIDs[key], Algorithm: string(jose.RS256), Use: "sig"})
+	}
+}
+
+func (s *testJWKSServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *testJWKSServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+var keyIDs = map[*rsa.PrivateKey]string{}
+
+func newTestSigningKey(t *testing.T, keyID string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyIDs[key] = keyID
+	return key
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: key, KeyID: keyIDs[key]}}, nil)
+	require.NoError(t, err)
+	jws, err := signer.Sign([]byte(`{"sub":"user"}`))
+	require.NoError(t, err)
+	token, err := jws.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestCachedKeySetRotation(t *testing.T) {
+	oldKey := newTestSigningKey(t, "old")
+	newKey := newTestSigningKey(t, "new")
+	server := newTestJWKSServer(t)
+	server.publish(oldKey)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	set := newCachedKeySet(server.URL)
+	set.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	payload, err := set.VerifySignature(ctx, signTestToken(t, oldKey))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"user"}`, string(payload))
+	assert.Equal(t, 1, server.requestCount())
+
+	// Cached keys are used without fetching them again.
+	_, err = set.VerifySignature(ctx, signTestToken(t, oldKey))
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.requestCount())
+
+	// A token signed with a new key triggers a refetch.
+	server.publish(newKey)
+	now = now.Add(time.Minute)
+	_, err = set.VerifySignature(ctx, signTestToken(t, newKey))
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.requestCount())
+
+	// The removed key is still accepted during the grace period.
+	_, err = set.VerifySignature(ctx, signTestToken(t, oldKey))
+	require.NoError(t, err)
+
+	now = now.Add(staleKeyGrace())
+	_, err = set.VerifySignature(ctx, signTestToken(t, oldKey))
+	assert.Error(t, err)
+}
+
+func TestCachedKeySetKeyMissRateLimit(t *testing.T) {
+	key := newTestSigningKey(t, "key")
+	unknownKey := newTestSigningKey(t, "unknown")
+	server := newTestJWKSServer(t)
+	server.publish(key)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	set := newCachedKeySet(server.URL)
+	set.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := set.VerifySignature(ctx, signTestToken(t, key))
+	require.NoError(t, err)
+
+	// Unknown key IDs don't refetch the keys more than once per interval.
+	now = now.Add(minKeyMissRefetchInterval)
+	for i := 0; i < 3; i++ {
+		_, err = set.VerifySignature(ctx, signTestToken(t, unknownKey))
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, server.requestCount())
+}
+
+func TestCachedKeySetUnreachable(t *testing.T) {
+	key := newTestSigningKey(t, "key")
+	server := newTestJWKSServer(t)
+	server.publish(key)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	set := newCachedKeySet(server.URL)
+	set.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := set.VerifySignature(ctx, signTestToken(t, key))
+	require.NoError(t, err)
+
+	// The cached keys are used while the identity provider is down, and a refresh is attempted in the background.
+	server.setDown(true)
+	now = now.Add(refreshInterval())
+	_, err = set.VerifySignature(ctx, signTestToken(t, key))
+	require.NoError(t, err)
+	<-set.fetch()
+	_, err = set.VerifySignature(ctx, signTestToken(t, key))
+	require.NoError(t, err)
+
+	// Without cached keys, the fetch error is returned.
+	empty := newCachedKeySet(server.URL)
+	_, err = empty.VerifySignature(ctx, signTestToken(t, key))
+	assert.ErrorContains(t, err, "503")
+}
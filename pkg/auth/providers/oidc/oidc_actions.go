@@ -13,6 +13,7 @@
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/externalsecrets"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	managementschema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
 	"k8s.io/client-go/util/retry"
@@ -98,8 +99,21 @@ func (o *OpenIDCProvider) TestAndApply(request *types.APIContext) error {
 	}
 	oidcConfig.Issuer = issuerURL.String()
 
+	// References to external secret stores are saved as they are, but the login needs the secrets.
+	loginConfig := oidcConfig
+	if externalsecrets.IsReference(loginConfig.ClientSecret) {
+		if loginConfig.ClientSecret, err = externalsecrets.Resolve(loginConfig.ClientSecret); err != nil {
+			return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("[generic oidc]: failed to resolve the client secret: %v", err))
+		}
+	}
+	if externalsecrets.IsReference(loginConfig.PrivateKey) {
+		if loginConfig.PrivateKey, err = externalsecrets.Resolve(loginConfig.PrivateKey); err != nil {
+			return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("[generic oidc]: failed to resolve the private key: %v", err))
+		}
+	}
+
 	// call provider
-	userPrincipal, groupPrincipals, providerToken, _, err := o.LoginUser(request.Request.Context(), oidcLogin, &oidcConfig)
+	userPrincipal, groupPrincipals, providerToken, _, err := o.LoginUser(request.Request.Context(), oidcLogin, &loginConfig)
 	if err != nil {
 		if httperror.IsAPIError(err) {
 			return err
@@ -0,0 +1,62 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+)
+
+// backchannelLogoutEvent is the event of the logout tokens of the OpenID Connect Back-Channel Logout specification.
+const backchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// LogoutTokenPrincipal verifies the logout token the OP posts to the back-channel logout endpoint when a user logs out,
+// and returns the principal of the user.
+func (o *OpenIDCProvider) LogoutTokenPrincipal(ctx context.Context, rawToken string) (string, error) {
+	config, err := o.GetOIDCConfig()
+	if err != nil {
+		return "", err
+	}
+	if !config.Enabled {
+		return "", fmt.Errorf("provider %s is disabled", o.Name)
+	}
+	subject, err := o.logoutTokenSubject(ctx, config, rawToken)
+	if err != nil {
+		return "", err
+	}
+	return o.Name + "_" + UserType + "://" + subject, nil
+}
+
+// logoutTokenSubject returns the subject of the logout token if it's signed by the OP, for Rancher, and not expired.
+func (o *OpenIDCProvider) logoutTokenSubject(ctx context.Context, config *v32.OIDCConfig, rawToken string) (string, error) {
+	provider, err := o.getOIDCProvider(ctx, config)
+	if err != nil {
+		return "", err
+	}
+	token, err := provider.Verifier(&oidc.Config{ClientID: config.ClientID}).Verify(ctx, rawToken)
+	if err != nil {
+		return "", fmt.Errorf("invalid logout token: %w", err)
+	}
+
+	var claims struct {
+		Events map[string]json.RawMessage `json:"events"`
+		Nonce  *string                    `json:"nonce"`
+	}
+	if err := token.Claims(&claims); err != nil {
+		return "", fmt.Errorf("decoding the claims of the logout token: %w", err)
+	}
+	if _, ok := claims.Events[backchannelLogoutEvent]; !ok {
+		return "", fmt.Errorf("logout token has no back-channel logout event")
+	}
+	// A nonce tells an ID token, which must not be accepted as a logout token.
+	if claims.Nonce != nil {
+		return "", fmt.Errorf("logout token has a nonce")
+	}
+	// Rancher doesn't keep the sessions of the OP, the logout tokens with only a sid can't be mapped to a user.
+	if token.Subject == "" {
+		return "", fmt.Errorf("logout token has no subject")
+	}
+	return token.Subject, nil
+}
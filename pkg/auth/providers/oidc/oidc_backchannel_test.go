@@ -0,0 +1,103 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogoutTokenSubject(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", ":0") // choose any available port
+	require.NoError(t, err)
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	server := mockOIDCServer(listener, newOIDCResponses(privateKey, port))
+	defer server.Shutdown(context.TODO())
+
+	logoutToken := func(key *rsa.PrivateKey, overrides jwt.MapClaims) string {
+		claims := jwt.MapClaims{
+			"iss":    "http://localhost:" + port,
+			"aud":    "test",
+			"iat":    time.Now().Unix(),
+			"exp":    time.Now().Add(2 * time.Minute).Unix(),
+			"jti":    "bWJq",
+			"sub":    "a8d0d2c4-6543-4546-8f1a-73e1d7dffcbd",
+			"events": map[string]interface{}{backchannelLogoutEvent: map[string]interface{}{}},
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(claims, k)
+				continue
+			}
+			claims[k] = v
+		}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			token: logoutToken(privateKey, nil),
+		},
+		{
+			name:    "other signing key",
+			token:   logoutToken(otherKey, nil),
+			wantErr: "invalid logout token",
+		},
+		{
+			name:    "other audience",
+			token:   logoutToken(privateKey, jwt.MapClaims{"aud": "other"}),
+			wantErr: "invalid logout token",
+		},
+		{
+			name:    "expired",
+			token:   logoutToken(privateKey, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}),
+			wantErr: "invalid logout token",
+		},
+		{
+			name:    "no logout event",
+			token:   logoutToken(privateKey, jwt.MapClaims{"events": nil}),
+			wantErr: "no back-channel logout event",
+		},
+		{
+			name:    "ID token",
+			token:   logoutToken(privateKey, jwt.MapClaims{"nonce": "n-0S6_WzA2Mj"}),
+			wantErr: "has a nonce",
+		},
+		{
+			name:    "session only",
+			token:   logoutToken(privateKey, jwt.MapClaims{"sub": nil, "sid": "08a5019c-17e1-4977-8f42-65a12843ea02"}),
+			wantErr: "no subject",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := OpenIDCProvider{Name: "keycloakoidc"}
+			subject, err := o.logoutTokenSubject(context.TODO(), newOIDCContext(port), tt.token)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "a8d0d2c4-6543-4546-8f1a-73e1d7dffcbd", subject)
+		})
+	}
+}
@@ -11,6 +11,8 @@
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tracing"
 )
 
 func getClientCertificates(certificate, key string) ([]tls.Certificate, error) {
@@ -57,6 +59,8 @@ func AddCertKeyToContext(ctx context.Context, certificate, key string) (context.
 	if err != nil {
 		return nil, err
 	}
+	// The calls to the provider are traced as children of the login.
+	client.Transport = tracing.Transport(client.Transport)
 
 	return oidc.ClientContext(ctx, client), nil
 }
@@ -103,3 +107,30 @@ func FetchAuthURL(config map[string]interface{}) (string, error) {
 
 	return discoveryInfo.AuthorizationEndpoint, nil
 }
+
+// fetchMetadata probes an endpoint serving metadata of the issuer, like its discovery document or JWKS, and decodes
+// the metadata into out. The endpoint is reachable if it answers, the error reports metadata it failed to serve.
+func fetchMetadata(ctx context.Context, client *http.Client, name, metadataURL string, out any) (v3.AuthConfigEndpointStatus, error) {
+	status := v3.AuthConfigEndpointStatus{Name: name, URL: metadataURL}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+	defer resp.Body.Close()
+
+	status.Reachable = true
+	if resp.StatusCode != http.StatusOK {
+		return status, fmt.Errorf("failed to fetch %s: %s", name, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return status, fmt.Errorf("unable to decode %s: %w", name, err)
+	}
+	return status, nil
+}
@@ -178,7 +178,11 @@ func TestAddCertKeyToContext(t *testing.T) {
 			got, ok := ctx.Value(oauth2.HTTPClient).(*http.Client)
 			require.True(t, ok, "expected to find an http client accessible in the context but didn't")
 
-			gotTransport := got.Transport.(*http.Transport)
+			transport := got.Transport
+			if traced, ok := transport.(interface{ Unwrap() http.RoundTripper }); ok {
+				transport = traced.Unwrap()
+			}
+			gotTransport := transport.(*http.Transport)
 			if !gotTransport.TLSClientConfig.RootCAs.Equal(tc.wantPool) {
 				t.Fatalf("system cert pool did not match desired")
 			}
@@ -4,6 +4,7 @@
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"slices"
 	"strings"
@@ -17,6 +18,8 @@
 	"github.com/rancher/norman/types/convert"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/attributelabels"
+	"github.com/rancher/rancher/pkg/auth/externalsecrets"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/tokens"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
@@ -53,6 +56,9 @@ type OpenIDCProvider struct {
 	Secrets     wcorev1.SecretController
 	UserMGR     user.Manager
 	TokenMGR    tokenManager
+	// GroupNamesFromClaims, if set, returns the names of additional groups derived from the claims,
+	// which are added to the user's group principals.
+	GroupNamesFromClaims func(claimInfo ClaimInfo) []string
 }
 
 type ClaimInfo struct {
@@ -66,6 +72,14 @@ type ClaimInfo struct {
 	Groups            []string `json:"groups"`
 	FullGroupPath     []string `json:"full_group_path"`
 	ACR               string   `json:"acr"`
+	// RealmAccess and ResourceAccess hold the roles assigned to the user by Keycloak.
+	RealmAccess    RoleClaim            `json:"realm_access,omitempty"`
+	ResourceAccess map[string]RoleClaim `json:"resource_access,omitempty"`
+}
+
+// RoleClaim is the representation of the roles granted in a realm or to a client.
+type RoleClaim struct {
+	Roles []string `json:"roles,omitempty"`
 }
 
 func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, userMGR user.Manager, tokenMGR *tokens.Manager) common.AuthProvider {
@@ -268,6 +282,22 @@ func (o *OpenIDCProvider) userToPrincipal(userInfo *oidc.UserInfo, claimInfo Cla
 		PrincipalType: UserType,
 		Me:            false,
 	}
+	var claims map[string]interface{}
+	if err := userInfo.Claims(&claims); err == nil {
+		p.ExtraInfo = attributelabels.ExtraInfo(func(attribute string) []string {
+			switch value := claims[attribute].(type) {
+			case string:
+				return []string{value}
+			case float64, bool:
+				return []string{fmt.Sprint(value)}
+			case []interface{}:
+				if len(value) > 0 {
+					return []string{fmt.Sprint(value[0])}
+				}
+			}
+			return nil
+		})
+	}
 	return p
 }
 
@@ -358,7 +388,13 @@ func (o *OpenIDCProvider) GetOIDCConfig() (*v32.OIDCConfig, error) {
 		}
 		storedOidcConfig.PrivateKey = value
 	}
-	if storedOidcConfig.ClientSecret != "" {
+	if externalsecrets.IsReference(storedOidcConfig.ClientSecret) {
+		value, err := common.ReadFromSecret(o.Secrets, storedOidcConfig.ClientSecret, strings.ToLower(client.OIDCConfigFieldClientSecret))
+		if err != nil {
+			return nil, err
+		}
+		storedOidcConfig.ClientSecret = value
+	} else if storedOidcConfig.ClientSecret != "" {
 		data, err := common.ReadFromSecretData(o.Secrets, storedOidcConfig.ClientSecret)
 		if err != nil {
 			return nil, err
@@ -394,7 +430,10 @@ func (o *OpenIDCProvider) getUserInfoFromAuthCode(ctx *context.Context, config *
 		return userInfo, oauth2Token, err
 	}
 	oauthConfig := ConfigToOauthConfig(provider.Endpoint(), config)
-	var verifier = provider.Verifier(&oidc.Config{ClientID: config.ClientID})
+	verifier, err := newVerifier(updatedContext, provider, config)
+	if err != nil {
+		return userInfo, oauth2Token, err
+	}
 
 	oauth2Token, err = oauthConfig.Exchange(updatedContext, authCode, oauth2.SetAuthURLParam("scope", strings.Join(oauthConfig.Scopes, " ")))
 	if err != nil {
@@ -462,7 +501,10 @@ func (o *OpenIDCProvider) getClaimInfoFromToken(ctx context.Context, config *v32
 		return nil, err
 	}
 	oauthConfig := ConfigToOauthConfig(provider.Endpoint(), config)
-	var verifier = provider.Verifier(&oidc.Config{ClientID: config.ClientID})
+	verifier, err := newVerifier(updatedContext, provider, config)
+	if err != nil {
+		return nil, err
+	}
 
 	// Valid will return false if access token is expired
 	if !token.Valid() {
@@ -555,6 +597,13 @@ func (o *OpenIDCProvider) getGroupsFromClaimInfo(claimInfo ClaimInfo) []v3.Princ
 			groupPrincipals = append(groupPrincipals, groupPrincipal)
 		}
 	}
+	if o.GroupNamesFromClaims != nil {
+		for _, group := range o.GroupNamesFromClaims(claimInfo) {
+			groupPrincipal := o.groupToPrincipal(group)
+			groupPrincipal.MemberOf = true
+			groupPrincipals = append(groupPrincipals, groupPrincipal)
+		}
+	}
 	return groupPrincipals
 }
 
@@ -579,6 +628,64 @@ func (o *OpenIDCProvider) IsDisabledProvider() (bool, error) {
 	return !oidcConfig.Enabled, nil
 }
 
+// Probe fetches the discovery document and the JWKS of the issuer, and reports the expiry of the certificate.
+func (o *OpenIDCProvider) Probe(ctx context.Context) (*v32.AuthConfigConnectivity, error) {
+	config, err := o.GetOIDCConfig()
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := getHTTPClient(config.Certificate, config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Timeout = common.ProbeTimeout
+
+	connectivity := &v32.AuthConfigConnectivity{
+		Credentials: common.CertificateExpiries("certificate", config.Certificate),
+	}
+
+	jwksURL := config.JWKSUrl
+	if jwksURL == "" {
+		discoveryURL, err := url.JoinPath(config.Issuer, "/.well-known/openid-configuration")
+		if err != nil {
+			return nil, fmt.Errorf("could not form discovery URL: %w", err)
+		}
+		var discovery struct {
+			JWKSURL string `json:"jwks_uri"`
+		}
+		endpoint, err := fetchMetadata(ctx, httpClient, "discovery", discoveryURL, &discovery)
+		connectivity.Endpoints = append(connectivity.Endpoints, endpoint)
+		if err != nil {
+			connectivity.Error = err.Error()
+			return connectivity, nil
+		}
+		if !endpoint.Reachable {
+			return connectivity, nil
+		}
+		jwksURL = discovery.JWKSURL
+	}
+
+	var jwks struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	endpoint, err := fetchMetadata(ctx, httpClient, "jwks", jwksURL, &jwks)
+	connectivity.Endpoints = append(connectivity.Endpoints, endpoint)
+	if err != nil {
+		connectivity.Error = err.Error()
+		return connectivity, nil
+	}
+	if !endpoint.Reachable {
+		return connectivity, nil
+	}
+	if len(jwks.Keys) == 0 {
+		connectivity.Error = "the JWKS of the issuer has no keys"
+		return connectivity, nil
+	}
+
+	connectivity.MetadataRefreshTime = time.Now().UTC().Format(time.RFC3339)
+	return connectivity, nil
+}
+
 func (o *OpenIDCProvider) getOIDCProvider(ctx context.Context, oidcConfig *v32.OIDCConfig) (*oidc.Provider, error) {
 	oidcFields := map[string]string{
 		client.OIDCConfigFieldIssuer:           oidcConfig.Issuer,
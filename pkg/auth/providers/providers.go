@@ -3,27 +3,41 @@
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/rancher/norman/types"
 	"github.com/rancher/rancher/pkg/auth/accessor"
 	"github.com/rancher/rancher/pkg/auth/providers/activedirectory"
 	"github.com/rancher/rancher/pkg/auth/providers/azure"
+	"github.com/rancher/rancher/pkg/auth/providers/cas"
+	"github.com/rancher/rancher/pkg/auth/providers/clientcert"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/providers/externalauth"
 	"github.com/rancher/rancher/pkg/auth/providers/genericoidc"
 	"github.com/rancher/rancher/pkg/auth/providers/github"
 	"github.com/rancher/rancher/pkg/auth/providers/googleoauth"
+	"github.com/rancher/rancher/pkg/auth/providers/kerberos"
 	"github.com/rancher/rancher/pkg/auth/providers/keycloakoidc"
 	"github.com/rancher/rancher/pkg/auth/providers/ldap"
 	"github.com/rancher/rancher/pkg/auth/providers/local"
 	"github.com/rancher/rancher/pkg/auth/providers/oidc"
 	"github.com/rancher/rancher/pkg/auth/providers/saml"
+	"github.com/rancher/rancher/pkg/auth/providerslo"
 	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/tracing"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	publicclient "github.com/rancher/rancher/pkg/client/generated/management/v3public"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics"
 	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 var (
@@ -33,6 +47,7 @@
 	Providers              = make(map[string]common.AuthProvider)
 	LocalProvider          = "local"
 	providersByType        = make(map[string]common.AuthProvider)
+	authConfigs            mgmtv3.AuthConfigCache
 	confMu                 sync.Mutex
 	userExtraAttributesMap = map[string]bool{common.UserAttributePrincipalID: true, common.UserAttributeUserName: true}
 )
@@ -55,6 +70,7 @@ func Configure(ctx context.Context, mgmt *config.ScaledContext) {
 	defer confMu.Unlock()
 	userMGR := mgmt.UserManager
 	tokenMGR := tokens.NewManager(ctx, mgmt)
+	authConfigs = mgmt.Wrangler.Mgmt.AuthConfig().Cache()
 
 	// TODO: refactor to eliminate the need for these callbacks, which exist to avoid the import cycle.
 	tokens.OnLogoutAll(ProviderLogoutAll)
@@ -163,6 +179,34 @@ func Configure(ctx context.Context, mgmt *config.ScaledContext) {
 	Providers[genericoidc.Name] = p
 	providersByType[client.GenericOIDCConfigType] = p
 	providersByType[publicclient.GenericOIDCProviderType] = p
+
+	p = externalauth.Configure(ctx, mgmt, userMGR, tokenMGR)
+	ProviderNames[externalauth.Name] = true
+	Providers[externalauth.Name] = p
+	providersByType[client.ExternalAuthConfigType] = p
+	providersByType[publicclient.ExternalAuthProviderType] = p
+
+	p = kerberos.Configure(ctx, mgmt, userMGR, tokenMGR, GetProvider)
+	ProviderNames[kerberos.Name] = true
+	Providers[kerberos.Name] = p
+	providersByType[client.KerberosConfigType] = p
+	providersByType[publicclient.KerberosProviderType] = p
+
+	p = clientcert.Configure(ctx, mgmt, userMGR, tokenMGR)
+	ProviderNames[clientcert.Name] = true
+	Providers[clientcert.Name] = p
+	UnrefreshableProviders[clientcert.Name] = true
+	providersByType[client.ClientCertConfigType] = p
+	providersByType[publicclient.ClientCertProviderType] = p
+
+	p = cas.Configure(ctx, mgmt, userMGR, tokenMGR)
+	ProviderNames[cas.Name] = true
+	Providers[cas.Name] = p
+	UnrefreshableProviders[cas.Name] = true
+	providersByType[client.CASConfigType] = p
+	providersByType[publicclient.CASProviderType] = p
+
+	startFallbackProbe(ctx)
 }
 
 func ProviderLogoutAll(apiContext *types.APIContext, token accessor.TokenAccessor) error {
@@ -198,15 +242,79 @@ func IsValidUserExtraAttribute(key string) bool {
 	return false
 }
 
+// The operations of the providers, as recorded by the metrics of their requests.
+const (
+	operationAuthenticate  = "authenticate"
+	operationGetPrincipal  = "getPrincipal"
+	operationSearch        = "search"
+	operationRefetchGroups = "refetchGroups"
+)
+
+// observeRequest records a request to the provider for the operation, started at started, in the metrics and for the
+// SLO of the provider.
+func observeRequest(providerName, operation string, started time.Time, err error) {
+	metrics.ObserveAuthProviderRequest(providerName, operation, started, err)
+	providerslo.Observe(providerName, time.Since(started), err)
+}
+
 func AuthenticateUser(ctx context.Context, input interface{}, providerName string) (v3.Principal, []v3.Principal, string, error) {
-	return Providers[providerName].AuthenticateUser(ctx, input)
+	started := time.Now()
+	ctx, span := tracing.StartSpan(ctx, "auth.provider.authenticate", attribute.String("auth.provider", providerName))
+	principal, groups, secret, err := Providers[providerName].AuthenticateUser(ctx, input)
+	span.SetAttributes(attribute.Int("auth.groups", len(groups)))
+	tracing.End(span, err)
+	observeRequest(providerName, operationAuthenticate, started, err)
+	return principal, groups, secret, err
+}
+
+// PrincipalProvider returns the name of the provider a principal belongs to, e.g. openldap for openldap_user://jdoe
+// and local for local://u-abcde. Every provider prefixes the IDs of its principals with its name, which keeps the
+// principals of providers that are enabled at the same time from colliding.
+func PrincipalProvider(principalID string) string {
+	scheme, _, ok := strings.Cut(principalID, "://")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(scheme, "_")
+	return name
+}
+
+// EnabledProviders returns the sorted names of the enabled providers other than local.
+func EnabledProviders() []string {
+	if authConfigs == nil {
+		return nil
+	}
+	configs, err := authConfigs.List(labels.Everything())
+	if err != nil {
+		logrus.Warnf("Failed to list auth configs: %v", err)
+		return nil
+	}
+
+	var names []string
+	for _, config := range configs {
+		if config.Enabled && config.Name != LocalProvider && Providers[config.Name] != nil {
+			names = append(names, config.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
 func GetPrincipal(principalID string, myToken accessor.TokenAccessor) (v3.Principal, error) {
-	principal, err := Providers[myToken.GetAuthProvider()].GetPrincipal(principalID, myToken)
+	// Principals of another enabled provider are looked up with that provider, so that users of one provider
+	// can grant access to the users and groups of the others.
+	if owner := PrincipalProvider(principalID); owner != myToken.GetAuthProvider() && owner != LocalProvider && slices.Contains(EnabledProviders(), owner) {
+		principal, err := getPrincipal(owner, principalID, myToken)
+		if err == nil {
+			return principal, nil
+		}
+		logrus.Debugf("[GetPrincipal] failed to get principal %s from provider %s: %v", principalID, owner, err)
+	}
+
+	principal, err := getPrincipal(myToken.GetAuthProvider(), principalID, myToken)
 
 	if err != nil && myToken.GetAuthProvider() != LocalProvider {
-		p2, e2 := Providers[LocalProvider].GetPrincipal(principalID, myToken)
+		p2, e2 := getPrincipal(LocalProvider, principalID, myToken)
 		if e2 == nil {
 			return p2, nil
 		}
@@ -215,6 +323,13 @@ func GetPrincipal(principalID string, myToken accessor.TokenAccessor) (v3.Princi
 	return principal, err
 }
 
+func getPrincipal(providerName, principalID string, myToken accessor.TokenAccessor) (v3.Principal, error) {
+	started := time.Now()
+	principal, err := Providers[providerName].GetPrincipal(principalID, myToken)
+	observeRequest(providerName, operationGetPrincipal, started, err)
+	return principal, err
+}
+
 func SearchPrincipals(name, principalType string, myToken accessor.TokenAccessor) ([]v3.Principal, error) {
 	ap := myToken.GetAuthProvider()
 	if ap == "" {
@@ -223,10 +338,11 @@ func SearchPrincipals(name, principalType string, myToken accessor.TokenAccessor
 	if Providers[ap] == nil {
 		return []v3.Principal{}, fmt.Errorf("[SearchPrincipals] authProvider %v not initialized", ap)
 	}
-	principals, err := Providers[ap].SearchPrincipals(name, principalType, myToken)
+	principals, err := searchPrincipals(ap, name, principalType, myToken)
 	if err != nil {
 		return principals, err
 	}
+	principals = append(principals, searchOtherProviders(ap, name, principalType, myToken, principals)...)
 	if ap != LocalProvider {
 		lp := Providers[LocalProvider]
 		if lpDedupe, _ := lp.(*local.Provider); lpDedupe != nil {
@@ -240,18 +356,87 @@ func SearchPrincipals(name, principalType string, myToken accessor.TokenAccessor
 	return principals, err
 }
 
+// searchOtherProviders searches the enabled providers other than the one of the token, skipping the principals
+// already found. A failing provider is skipped, so that the outage of one provider doesn't break the search.
+func searchOtherProviders(tokenProvider, name, principalType string, myToken accessor.TokenAccessor, found []v3.Principal) []v3.Principal {
+	seen := make(map[string]bool, len(found))
+	for _, principal := range found {
+		seen[principal.Name] = true
+	}
+
+	var principals []v3.Principal
+	for _, providerName := range EnabledProviders() {
+		if providerName == tokenProvider {
+			continue
+		}
+		result, err := searchPrincipals(providerName, name, principalType, myToken)
+		if err != nil {
+			logrus.Debugf("[SearchPrincipals] failed to search provider %s: %v", providerName, err)
+			continue
+		}
+		for _, principal := range result {
+			if !seen[principal.Name] {
+				seen[principal.Name] = true
+				principals = append(principals, principal)
+			}
+		}
+	}
+	return principals
+}
+
+func searchPrincipals(providerName, name, principalType string, myToken accessor.TokenAccessor) ([]v3.Principal, error) {
+	started := time.Now()
+	principals, err := Providers[providerName].SearchPrincipals(name, principalType, myToken)
+	observeRequest(providerName, operationSearch, started, err)
+	return principals, err
+}
+
 func CanAccessWithGroupProviders(providerName string, userPrincipalID string, groups []v3.Principal) (bool, error) {
 	return Providers[providerName].CanAccessWithGroupProviders(userPrincipalID, groups)
 }
 
 func RefetchGroupPrincipals(principalID string, providerName string, secret string) ([]v3.Principal, error) {
-	return Providers[providerName].RefetchGroupPrincipals(principalID, secret)
+	started := time.Now()
+	groups, err := Providers[providerName].RefetchGroupPrincipals(principalID, secret)
+	observeRequest(providerName, operationRefetchGroups, started, err)
+	return groups, err
 }
 
 func GetUserExtraAttributes(providerName string, userPrincipal v3.Principal) map[string][]string {
 	return Providers[providerName].GetUserExtraAttributes(userPrincipal)
 }
 
+// GetUserLookup returns the provider as a common.UserLookup, or nil if the provider can't look up its users.
+func GetUserLookup(providerName string) common.UserLookup {
+	lookup, _ := Providers[providerName].(common.UserLookup)
+	return lookup
+}
+
+// GetGroupLookup returns the provider as a common.GroupLookup, or nil if the provider can't look up its groups.
+func GetGroupLookup(providerName string) common.GroupLookup {
+	lookup, _ := Providers[providerName].(common.GroupLookup)
+	return lookup
+}
+
+// GetGroupMemberLister returns the provider as a common.GroupMemberLister, or nil if the provider can't list group members.
+func GetGroupMemberLister(providerName string) common.GroupMemberLister {
+	lister, _ := Providers[providerName].(common.GroupMemberLister)
+	return lister
+}
+
+// GetUserAttributeSearcher returns the provider as a common.UserAttributeSearcher, or nil if the provider can't search
+// its users by attribute.
+func GetUserAttributeSearcher(providerName string) common.UserAttributeSearcher {
+	searcher, _ := Providers[providerName].(common.UserAttributeSearcher)
+	return searcher
+}
+
+// GetProber returns the provider as a common.Prober, or nil if it can't probe its identity service.
+func GetProber(providerName string) common.Prober {
+	prober, _ := Providers[providerName].(common.Prober)
+	return prober
+}
+
 func IsDisabledProvider(providerName string) (bool, error) {
 	provider, err := GetProvider(providerName)
 	if err != nil {
@@ -12,9 +12,14 @@
 	"github.com/rancher/rancher/pkg/auth/providers/azure"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/providers/github"
+	"github.com/rancher/rancher/pkg/auth/providers/ldap"
+	"github.com/rancher/rancher/pkg/auth/providers/oidc"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -106,9 +111,119 @@ func TestProviderHasPerUserTokens(t *testing.T) {
 	assert.True(t, hasPerUserSecrets)
 }
 
+func TestPrincipalProvider(t *testing.T) {
+	tests := map[string]string{
+		"openldap_user://cn=jdoe,dc=example,dc=com": ldap.OpenLdapName,
+		"github_team://1234":                        github.Name,
+		"keycloakoidc_group://admins":               "keycloakoidc",
+		"keycloak_user://jdoe":                      "keycloak",
+		"local://u-abcde":                           LocalProvider,
+		"jdoe":                                      "",
+	}
+	for principalID, want := range tests {
+		assert.Equal(t, want, PrincipalProvider(principalID), principalID)
+	}
+}
+
+func TestSearchPrincipalsOfEnabledProviders(t *testing.T) {
+	t.Cleanup(cleanup)
+	Providers[ldap.OpenLdapName] = &principalsProvider{principals: []v3.Principal{
+		{ObjectMeta: metav1.ObjectMeta{Name: "openldap_user://jdoe"}},
+	}}
+	Providers[oidc.Name] = &principalsProvider{principals: []v3.Principal{
+		{ObjectMeta: metav1.ObjectMeta{Name: "oidc_user://jdoe"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "oidc_group://contractors"}},
+	}}
+	Providers[github.Name] = &principalsProvider{err: errors.New("unavailable")}
+	Providers[azure.Name] = &principalsProvider{principals: []v3.Principal{
+		{ObjectMeta: metav1.ObjectMeta{Name: "azuread_user://jdoe"}},
+	}}
+	authConfigs = newAuthConfigCache(t, map[string]bool{
+		ldap.OpenLdapName: true,
+		oidc.Name:         true,
+		github.Name:       true,
+		azure.Name:        false,
+		LocalProvider:     true,
+	})
+
+	principals, err := SearchPrincipals("jdoe", "", &v3.Token{AuthProvider: ldap.OpenLdapName})
+	require.NoError(t, err)
+
+	var names []string
+	for _, principal := range principals {
+		names = append(names, principal.Name)
+	}
+	assert.Equal(t, []string{"openldap_user://jdoe", "oidc_user://jdoe", "oidc_group://contractors"}, names)
+}
+
+func TestGetPrincipalOfEnabledProvider(t *testing.T) {
+	t.Cleanup(cleanup)
+	Providers[ldap.OpenLdapName] = &principalsProvider{principals: []v3.Principal{
+		{ObjectMeta: metav1.ObjectMeta{Name: "openldap_user://jdoe"}},
+	}}
+	Providers[oidc.Name] = &principalsProvider{principals: []v3.Principal{
+		{ObjectMeta: metav1.ObjectMeta{Name: "oidc_group://contractors"}, DisplayName: "Contractors"},
+	}}
+	Providers[azure.Name] = &principalsProvider{principals: []v3.Principal{
+		{ObjectMeta: metav1.ObjectMeta{Name: "azuread_group://contractors"}, DisplayName: "Contractors"},
+	}}
+	authConfigs = newAuthConfigCache(t, map[string]bool{
+		ldap.OpenLdapName: true,
+		oidc.Name:         true,
+		azure.Name:        false,
+	})
+	Providers[LocalProvider] = &principalsProvider{}
+	token := &v3.Token{AuthProvider: ldap.OpenLdapName}
+
+	principal, err := GetPrincipal("oidc_group://contractors", token)
+	require.NoError(t, err)
+	assert.Equal(t, "Contractors", principal.DisplayName)
+
+	principal, err = GetPrincipal("openldap_user://jdoe", token)
+	require.NoError(t, err)
+	assert.Equal(t, "openldap_user://jdoe", principal.Name)
+
+	_, err = GetPrincipal("azuread_group://contractors", token)
+	assert.Error(t, err, "expected principals of disabled providers to be looked up with the provider of the token")
+}
+
+func newAuthConfigCache(t *testing.T, enabled map[string]bool) *fake.MockNonNamespacedCacheInterface[*v3.AuthConfig] {
+	ctrl := gomock.NewController(t)
+	cache := fake.NewMockNonNamespacedCacheInterface[*v3.AuthConfig](ctrl)
+	cache.EXPECT().List(gomock.Any()).DoAndReturn(func(_ labels.Selector) ([]*v3.AuthConfig, error) {
+		var configs []*v3.AuthConfig
+		for name, enabled := range enabled {
+			configs = append(configs, &v3.AuthConfig{ObjectMeta: metav1.ObjectMeta{Name: name}, Enabled: enabled})
+		}
+		return configs, nil
+	}).AnyTimes()
+	return cache
+}
+
 func cleanup() {
 	Providers = make(map[string]common.AuthProvider)
 	providersWithSecrets = make(map[string]bool)
+	authConfigs = nil
+}
+
+// principalsProvider returns its principals from every search, and the one with the requested ID from GetPrincipal.
+type principalsProvider struct {
+	fakeProvider
+	principals []v3.Principal
+	err        error
+}
+
+func (p *principalsProvider) SearchPrincipals(_, _ string, _ accessor.TokenAccessor) ([]v3.Principal, error) {
+	return p.principals, p.err
+}
+
+func (p *principalsProvider) GetPrincipal(principalID string, _ accessor.TokenAccessor) (v3.Principal, error) {
+	for _, principal := range p.principals {
+		if principal.Name == principalID {
+			return principal, nil
+		}
+	}
+	return v3.Principal{}, fmt.Errorf("principal %s not found", principalID)
 }
 
 type mockUnstructuredGetter struct {
@@ -48,6 +48,10 @@ func NewHandler(ctx context.Context, mgmtCtx *config.ScaledContext, opts ...Serv
 	v3public.OIDCProviderType,
 	v3public.KeyCloakOIDCProviderType,
 	v3public.GenericOIDCProviderType,
+	v3public.ExternalAuthProviderType,
+	v3public.KerberosProviderType,
+	v3public.ClientCertProviderType,
+	v3public.CASProviderType,
 }
 
 func authProviderSchemas(ctx context.Context, management *config.ScaledContext, schemas *types.Schemas) error {
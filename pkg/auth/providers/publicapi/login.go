@@ -3,8 +3,10 @@
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,27 +15,46 @@
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
 	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/captcha"
+	"github.com/rancher/rancher/pkg/auth/failedlogins"
+	"github.com/rancher/rancher/pkg/auth/jitprovisioning"
+	"github.com/rancher/rancher/pkg/auth/loginanomalies"
+	"github.com/rancher/rancher/pkg/auth/loginlimit"
+	"github.com/rancher/rancher/pkg/auth/mfa"
+	"github.com/rancher/rancher/pkg/auth/notifications"
 	"github.com/rancher/rancher/pkg/auth/providers"
 	"github.com/rancher/rancher/pkg/auth/providers/activedirectory"
 	"github.com/rancher/rancher/pkg/auth/providers/azure"
+	"github.com/rancher/rancher/pkg/auth/providers/cas"
+	"github.com/rancher/rancher/pkg/auth/providers/clientcert"
+	"github.com/rancher/rancher/pkg/auth/providers/externalauth"
 	"github.com/rancher/rancher/pkg/auth/providers/genericoidc"
 	"github.com/rancher/rancher/pkg/auth/providers/github"
 	"github.com/rancher/rancher/pkg/auth/providers/googleoauth"
+	"github.com/rancher/rancher/pkg/auth/providers/kerberos"
 	"github.com/rancher/rancher/pkg/auth/providers/keycloakoidc"
 	"github.com/rancher/rancher/pkg/auth/providers/ldap"
 	"github.com/rancher/rancher/pkg/auth/providers/local"
 	"github.com/rancher/rancher/pkg/auth/providers/oidc"
 	"github.com/rancher/rancher/pkg/auth/providers/saml"
+	"github.com/rancher/rancher/pkg/auth/refreshtokens"
+	"github.com/rancher/rancher/pkg/auth/requests"
 	"github.com/rancher/rancher/pkg/auth/settings"
 	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/tracing"
 	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/auth/webauthn"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3public"
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics"
 	schema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3public"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/user"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/pointer"
 )
@@ -43,12 +64,22 @@
 )
 
 func newLoginHandler(ctx context.Context, mgmt *config.ScaledContext) *loginHandler {
+	notifier := notifications.NewNotifier(mgmt)
 	return &loginHandler{
 		scaledContext: mgmt,
 		userMGR:       mgmt.UserManager,
 		tokenMGR:      tokens.NewManager(ctx, mgmt),
+		refreshTokens: refreshtokens.NewIssuer(ctx, mgmt),
 		clusterLister: mgmt.Management.Clusters("").Controller().Lister(),
 		secretLister:  mgmt.Core.Secrets("").Controller().Lister(),
+		provisioner:   jitprovisioning.NewProvisioner(mgmt.Wrangler),
+		mfa:           mfa.NewManager(mgmt.Wrangler.Core.Secret()),
+		securityKeys:  webauthn.NewManager(mgmt.Wrangler.Core.Secret()),
+		loginLimiter:  loginlimit.NewLimiter(),
+		captcha:       captcha.NewChecker(mgmt.Core.Secrets("").Controller().Lister()),
+		notifier:      notifier,
+		anomalies:     loginanomalies.NewDetector(mgmt, notifier),
+		failedLogins:  failedlogins.NewTracker(mgmt, notifier),
 	}
 }
 
@@ -56,8 +87,17 @@ type loginHandler struct {
 	scaledContext *config.ScaledContext
 	userMGR       user.Manager
 	tokenMGR      *tokens.Manager
+	refreshTokens *refreshtokens.Issuer
 	clusterLister v3.ClusterLister
 	secretLister  v1.SecretLister
+	provisioner   *jitprovisioning.Provisioner
+	mfa           *mfa.Manager
+	securityKeys  *webauthn.Manager
+	loginLimiter  *loginlimit.Limiter
+	captcha       *captcha.Checker
+	notifier      *notifications.Notifier
+	anomalies     *loginanomalies.Detector
+	failedLogins  *failedlogins.Tracker
 }
 
 func (h *loginHandler) login(actionName string, action *types.Action, request *types.APIContext) error {
@@ -67,7 +107,11 @@ func (h *loginHandler) login(actionName string, action *types.Action, request *t
 
 	w := request.Response
 
-	token, unhashedTokenKey, responseType, err := h.createLoginToken(request)
+	// The spans of the login are part of the caller's trace, if any.
+	ctx, span := tracing.StartSpan(tracing.Extract(request.Request), "auth.login", attribute.String("auth.provider.type", request.Type))
+	request.Request = request.Request.WithContext(ctx)
+	token, unhashedTokenKey, responseType, refreshToken, err := h.createLoginToken(request)
+	tracing.End(span, err)
 	if err != nil {
 		// if user fails to authenticate, hide the details of the exact error. bad credentials will already be APIErrors
 		// otherwise, return a generic error message
@@ -77,6 +121,12 @@ func (h *loginHandler) login(actionName string, action *types.Action, request *t
 		return httperror.WrapAPIError(err, httperror.ServerError, "Server error while authenticating")
 	}
 
+	// The users are told of the logins to their account which failed since their previous login.
+	var failed *failedlogins.Summary
+	if responseType != "saml" && token.UserID != "" {
+		failed = h.failedLogins.Succeed(token.UserID)
+	}
+
 	if responseType == "cookie" {
 		tokenCookie := &http.Cookie{
 			Name:     CookieName,
@@ -86,6 +136,14 @@ func (h *loginHandler) login(actionName string, action *types.Action, request *t
 			HttpOnly: true,
 		}
 		http.SetCookie(w, tokenCookie)
+		// The cookie logins have no response body otherwise.
+		if failed != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			if err := json.NewEncoder(w).Encode(map[string]interface{}{"failedLogins": failed}); err != nil {
+				logrus.Errorf("failed to write the failed logins of user %s: %v", token.UserID, err)
+			}
+		}
 	} else if responseType == "saml" {
 		return nil
 	} else {
@@ -94,14 +152,20 @@ func (h *loginHandler) login(actionName string, action *types.Action, request *t
 			return httperror.WrapAPIError(err, httperror.ServerError, "Server error while authenticating")
 		}
 		tokenData["token"] = token.ObjectMeta.Name + ":" + unhashedTokenKey
+		if refreshToken != "" {
+			tokenData["refreshToken"] = refreshToken
+		}
+		if failed != nil {
+			tokenData["failedLogins"] = failed
+		}
 		request.WriteResponse(http.StatusCreated, tokenData)
 	}
 
 	return nil
 }
 
-// createLoginToken returns token, unhashed token key (where applicable), responseType and error
-func (h *loginHandler) createLoginToken(request *types.APIContext) (v3.Token, string, string, error) {
+// createLoginToken returns token, unhashed token key (where applicable), responseType, refresh token (if requested) and error
+func (h *loginHandler) createLoginToken(request *types.APIContext) (v3.Token, string, string, string, error) {
 	var userPrincipal v3.Principal
 	var groupPrincipals []v3.Principal
 	var providerToken string
@@ -110,14 +174,14 @@ func (h *loginHandler) createLoginToken(request *types.APIContext) (v3.Token, st
 	bytes, err := ioutil.ReadAll(request.Request.Body)
 	if err != nil {
 		logrus.Errorf("login failed with error: %v", err)
-		return v3.Token{}, "", "", httperror.NewAPIError(httperror.InvalidBodyContent, "")
+		return v3.Token{}, "", "", "", httperror.NewAPIError(httperror.InvalidBodyContent, "")
 	}
 
 	generic := &apiv3.GenericLogin{}
 	err = json.Unmarshal(bytes, generic)
 	if err != nil {
 		logrus.Errorf("unmarshal failed with error: %v", err)
-		return v3.Token{}, "", "", httperror.NewAPIError(httperror.InvalidBodyContent, "")
+		return v3.Token{}, "", "", "", httperror.NewAPIError(httperror.InvalidBodyContent, "")
 	}
 	responseType := generic.ResponseType
 	description := generic.Description
@@ -176,14 +240,36 @@ func (h *loginHandler) createLoginToken(request *types.APIContext) (v3.Token, st
 	case client.GenericOIDCProviderType:
 		input = &apiv3.OIDCLogin{}
 		providerName = genericoidc.Name
+	case client.ExternalAuthProviderType:
+		input = &apiv3.BasicLogin{}
+		providerName = externalauth.Name
+	case client.KerberosProviderType:
+		input = &apiv3.KerberosLogin{}
+		providerName = kerberos.Name
+	case client.ClientCertProviderType:
+		input = &apiv3.ClientCertLogin{}
+		providerName = clientcert.Name
+	case client.CASProviderType:
+		input = &apiv3.CASLogin{}
+		providerName = cas.Name
 	default:
-		return v3.Token{}, "", "", httperror.NewAPIError(httperror.ServerError, "unknown authentication provider")
+		return v3.Token{}, "", "", "", httperror.NewAPIError(httperror.ServerError, "unknown authentication provider")
+	}
+
+	source := requests.ClientIP(request.Request)
+	trace.SpanFromContext(request.Request.Context()).SetAttributes(attribute.String("auth.provider", providerName))
+
+	// Several providers can be enabled at the same time, the login action of the one picked by the user must be enabled,
+	// unless it's the provider Rancher falls back to because the ones before it in the fallback order are unavailable.
+	if disabled, err := providers.IsDisabledProvider(providerName); (err != nil || disabled) && providerName != providers.FallbackProvider() {
+		h.recordLoginFailure(providerName, "", "", source, authevents.ReasonProviderDisabled)
+		return v3.Token{}, "", "", "", httperror.NewAPIError(httperror.Unauthorized, fmt.Sprintf("authentication provider %s is not enabled", providerName))
 	}
 
 	err = json.Unmarshal(bytes, input)
 	if err != nil {
 		logrus.Errorf("unmarshal failed with error: %v", err)
-		return v3.Token{}, "", "", httperror.NewAPIError(httperror.InvalidBodyContent, "")
+		return v3.Token{}, "", "", "", httperror.NewAPIError(httperror.InvalidBodyContent, "")
 	}
 
 	// Authenticate User
@@ -193,13 +279,44 @@ func (h *loginHandler) createLoginToken(request *types.APIContext) (v3.Token, st
 	if providerName == saml.PingName || providerName == saml.ADFSName || providerName == saml.KeyCloakName ||
 		providerName == saml.OKTAName || providerName == saml.ShibbolethName {
 		err = saml.PerformSamlLogin(providerName, request, input)
-		return v3.Token{}, "", "saml", err
+		return v3.Token{}, "", "saml", "", err
+	}
+
+	// The logins with a username and password must wait after failures, per username and per client address.
+	var username string
+	if basic, ok := input.(*apiv3.BasicLogin); ok {
+		username = basic.Username
+	}
+	limited := loginlimit.Limited(providerName)
+	if limited {
+		if wait := h.loginLimiter.Check(providerName, username, source); wait > 0 {
+			h.recordLoginFailure(providerName, username, "", source, authevents.ReasonThrottled)
+			request.Response.Header().Set("Retry-After", strconv.FormatInt(int64((wait+time.Second-1)/time.Second), 10))
+			return v3.Token{}, "", "", "", httperror.NewAPIError(loginlimit.TooManyAttemptsErrorCode, "too many failed logins, try again later")
+		}
+		// After a few failures, the client must also solve a challenge.
+		if captcha.Required(providerName, h.loginLimiter.Failures(providerName, username, source)) {
+			if err := h.captcha.Verify(request.Request.Context(), generic.CaptchaResponse, source); err != nil {
+				return v3.Token{}, "", "", "", err
+			}
+		}
 	}
 
 	ctx := context.WithValue(request.Request.Context(), util.RequestKey, request.Request)
 	userPrincipal, groupPrincipals, providerToken, err = providers.AuthenticateUser(ctx, input, providerName)
 	if err != nil {
-		return v3.Token{}, "", "", err
+		if limited && loginFailed(err) {
+			h.loginLimiter.Fail(providerName, username, source)
+		}
+		reason := authevents.ReasonProviderError
+		if loginFailed(err) {
+			reason = authevents.ReasonInvalidCredentials
+		}
+		h.recordLoginFailure(providerName, username, "", source, reason)
+		if providerName == kerberos.Name {
+			kerberos.SetNegotiateChallenge(request.Response, err)
+		}
+		return v3.Token{}, "", "", "", err
 	}
 
 	displayName := userPrincipal.DisplayName
@@ -244,21 +361,131 @@ func (h *loginHandler) createLoginToken(request *types.APIContext) (v3.Token, st
 		return true, nil
 	})
 	if err != nil {
-		return v3.Token{}, "", "", fmt.Errorf("error creating or updating user and/or userAttribute for %s: %w", userPrincipal.Name, err)
+		return v3.Token{}, "", "", "", fmt.Errorf("error creating or updating user and/or userAttribute for %s: %w", userPrincipal.Name, err)
 	}
 
 	if !enabled {
-		return v3.Token{}, "", "", httperror.NewAPIError(httperror.PermissionDenied, "Permission Denied")
+		h.recordLoginFailure(providerName, userPrincipal.Name, currUser.Name, source, authevents.ReasonUserDisabled)
+		return v3.Token{}, "", "", "", httperror.NewAPIError(httperror.PermissionDenied, "Permission Denied")
+	}
+
+	userExtraInfo := providers.GetUserExtraAttributes(providerName, userPrincipal)
+	if err := h.provisioner.Provision(currUser, providerName, groupPrincipals, userExtraInfo); err != nil {
+		logrus.Errorf("Error provisioning user %s: %v", currUser.Name, err)
+	}
+
+	// The users who enrolled in MFA must send a second factor with their password. Those who must use MFA but haven't enrolled
+	// get a short-lived token which can only be used to enroll.
+	switch err := h.checkSecondFactor(currUser, providerName, input, generic); {
+	case errors.Is(err, mfa.ErrEnrollmentRequired):
+		if limited {
+			h.loginLimiter.Succeed(providerName, username)
+		}
+		if strings.HasPrefix(responseType, tokens.KubeconfigResponseType) {
+			return v3.Token{}, "", "", "", httperror.NewAPIError(mfa.RequiredErrorCode, "MFA enrollment is required before logging in")
+		}
+		token, tokenValue, err := h.tokenMGR.NewMFAEnrollmentToken(currUser.Name, userPrincipal, "MFA enrollment")
+		return token, tokenValue, responseType, "", err
+	case err != nil:
+		// Logins without a second factor are only asked for it, those with a wrong one failed.
+		if loginFailed(err) && (generic.TOTPCode != "" || generic.WebAuthn.CredentialID != "") {
+			if limited {
+				h.loginLimiter.Fail(providerName, username, source)
+			}
+			h.recordLoginFailure(providerName, userPrincipal.Name, currUser.Name, source, authevents.ReasonSecondFactorFailed)
+		}
+		return v3.Token{}, "", "", "", err
+	}
+	if limited {
+		h.loginLimiter.Succeed(providerName, username)
+	}
+	h.notifier.Login(currUser, providerName, source, request.Request.UserAgent())
+	h.anomalies.Login(currUser, providerName, source, request.Request.UserAgent())
+	metrics.IncLogins(providerName, authevents.LoginSucceeded)
+	authevents.Record(authevents.Event{
+		Type:        authevents.LoginSucceeded,
+		UserName:    currUser.Name,
+		PrincipalID: userPrincipal.Name,
+		Provider:    providerName,
+		SourceIP:    source,
+	})
+
+	// Short-lived tokens paired with a refresh token replace the login and kubeconfig tokens when requested.
+	// Browser sessions keep their cookie.
+	if generic.RefreshToken && responseType != "cookie" {
+		kind, clusterID := "session", ""
+		if strings.HasPrefix(responseType, tokens.KubeconfigResponseType) {
+			kind, clusterID = tokens.KubeconfigResponseType, tokens.KubeconfigClusterID(responseType)
+			description = "Kubeconfig token"
+		}
+		grant, err := h.refreshTokens.Issue(currUser.Name, userPrincipal, kind, clusterID, description)
+		if err != nil {
+			return v3.Token{}, "", "", "", err
+		}
+		return grant.Token, grant.TokenValue, responseType, grant.RefreshToken, nil
 	}
 
 	if strings.HasPrefix(responseType, tokens.KubeconfigResponseType) {
 		token, tokenValue, err := tokens.GetKubeConfigToken(currUser.Name, responseType, h.userMGR, userPrincipal)
 		if err != nil {
-			return v3.Token{}, "", "", err
+			return v3.Token{}, "", "", "", err
 		}
-		return *token, tokenValue, responseType, nil
+		return *token, tokenValue, responseType, "", nil
 	}
 
+	_, span := tracing.StartSpan(ctx, "auth.token.create")
 	rToken, unhashedTokenKey, err := h.tokenMGR.NewLoginToken(currUser.Name, userPrincipal, groupPrincipals, providerToken, ttl, description)
-	return rToken, unhashedTokenKey, responseType, err
+	tracing.End(span, err)
+	return rToken, unhashedTokenKey, responseType, "", err
+}
+
+// loginFailed tells whether the error of a login is a failure to authenticate, rather than a server error.
+func loginFailed(err error) bool {
+	var apiErr *httperror.APIError
+	return errors.As(err, &apiErr) && apiErr.Code.Status == http.StatusUnauthorized
+}
+
+// recordLoginFailure records a failed login to the provider, as the principal when it's known, and the user once
+// it's resolved. The failures of the users to log in, rather than of the providers, are counted for the next login of
+// the user.
+func (h *loginHandler) recordLoginFailure(providerName, principalID, userName string, source net.IP, reason string) {
+	if reason != authevents.ReasonProviderDisabled && reason != authevents.ReasonProviderError {
+		h.failedLogins.Fail(providerName, principalID, userName, source)
+	}
+	metrics.IncLogins(providerName, reason)
+	authevents.Record(authevents.Event{
+		Type:        authevents.LoginFailed,
+		UserName:    userName,
+		PrincipalID: principalID,
+		Provider:    providerName,
+		SourceIP:    source,
+		Reason:      reason,
+	})
+}
+
+// checkSecondFactor verifies the second factor of the logins with a username and password: a TOTP code, or a security
+// key for local users. The passwordless logins of local users were already verified with their passkey.
+func (h *loginHandler) checkSecondFactor(user *v3.User, providerName string, input interface{}, generic *apiv3.GenericLogin) error {
+	if providerName == local.Name {
+		if basic, ok := input.(*apiv3.BasicLogin); ok && basic.Password == "" && generic.WebAuthn.CredentialID != "" {
+			return nil
+		}
+		if generic.WebAuthn.CredentialID != "" {
+			return h.securityKeys.VerifyLogin(user.Name, generic.WebAuthn, false)
+		}
+		hasKeys, err := h.securityKeys.HasCredentials(user.Name)
+		if err != nil {
+			return err
+		}
+		if hasKeys {
+			enrolled, err := h.mfa.Enrolled(user.Name)
+			if err != nil {
+				return err
+			}
+			if !enrolled {
+				return mfa.ErrCodeRequired
+			}
+		}
+	}
+	return h.mfa.CheckLogin(user, providerName, generic.TOTPCode)
 }
@@ -54,7 +54,7 @@ func (s *authProvidersStore) List(apiContext *types.APIContext, schema *types.Sc
 	list, _ := rrr.(*unstructured.UnstructuredList)
 	for _, i := range list.Items {
 		if t, ok := i.Object["type"].(string); ok && t != "" {
-			if enabled, ok := i.Object["enabled"].(bool); ok && enabled {
+			if enabled, ok := i.Object["enabled"].(bool); (ok && enabled) || i.GetName() == providers.FallbackProvider() {
 				i.Object[".host"] = util.GetHost(apiContext.Request)
 				provider, err := providers.GetProviderByType(t).TransformToAuthProvider(i.Object)
 				if err != nil {
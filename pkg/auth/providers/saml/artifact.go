@@ -0,0 +1,51 @@
+package saml
+
+import (
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/crewjam/saml"
+)
+
+const artifactResolutionTimeout = 30 * time.Second
+
+// newArtifactResolutionClient returns the client resolving artifacts with the SOAP back-channel of the IdP.
+// It authenticates with the SP certificate and key, since IdPs requiring the artifact binding usually require
+// mutual TLS on their artifact resolution service, and trusts the system CAs along with the configured ones.
+func newArtifactResolutionClient(key *rsa.PrivateKey, cert *x509.Certificate, caCerts string) (*http.Client, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if caCerts != "" && !pool.AppendCertsFromPEM([]byte(caCerts)) {
+		return nil, fmt.Errorf("SAML: no valid certificate found in the artifact resolution CA certificates")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs: pool,
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{cert.Raw},
+			PrivateKey:  key,
+			Leaf:        cert,
+		}},
+		MinVersion: tls.VersionTLS12,
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   artifactResolutionTimeout,
+	}, nil
+}
+
+// responseBinding returns the binding the IdP is asked to send its responses with.
+// Responses sent with the artifact binding are resolved by ParseResponse when the ACS receives the artifact.
+func (s *Provider) responseBinding() string {
+	if s.artifactBinding {
+		return saml.HTTPArtifactBinding
+	}
+	return saml.HTTPPostBinding
+}
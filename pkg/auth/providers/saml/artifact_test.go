@@ -0,0 +1,66 @@
+package saml
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArtifactResolutionClient(t *testing.T) {
+	spKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rancher-sp"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &spKey.PublicKey, spKey)
+	require.NoError(t, err)
+	spCert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	var clientCerts []*x509.Certificate
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientCerts = r.TLS.PeerCertificates
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	client, err := newArtifactResolutionClient(spKey, spCert, serverCA)
+	require.NoError(t, err)
+	resp, err := client.Post(server.URL, "text/xml", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Len(t, clientCerts, 1)
+	assert.Equal(t, spCert.Raw, clientCerts[0].Raw)
+
+	// The server isn't trusted without the configured CA certificates.
+	client, err = newArtifactResolutionClient(spKey, spCert, "")
+	require.NoError(t, err)
+	_, err = client.Post(server.URL, "text/xml", nil)
+	assert.Error(t, err)
+
+	_, err = newArtifactResolutionClient(spKey, spCert, "not a certificate")
+	assert.Error(t, err)
+}
+
+func TestResponseBinding(t *testing.T) {
+	assert.Equal(t, saml.HTTPPostBinding, (&Provider{}).responseBinding())
+	assert.Equal(t, saml.HTTPArtifactBinding, (&Provider{artifactBinding: true}).responseBinding())
+}
@@ -0,0 +1,170 @@
+package saml
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	oktaDefaultUserIDAttribute = "login"
+	oktaDefaultSearchLimit     = 100
+	oktaRequestTimeout         = 30 * time.Second
+)
+
+// oktaUser is the representation of a user returned by the Okta Users API.
+type oktaUser struct {
+	ID      string         `json:"id"`
+	Status  string         `json:"status"`
+	Profile map[string]any `json:"profile"`
+}
+
+// oktaGroup is the representation of a group returned by the Okta Groups API.
+type oktaGroup struct {
+	ID      string `json:"id"`
+	Profile struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"profile"`
+}
+
+// oktaClient searches users and groups using the Okta API.
+type oktaClient struct {
+	httpClient      *http.Client
+	orgURL          string
+	apiToken        string
+	userIDAttribute string
+	limit           int64
+}
+
+func newOktaClient(config *v32.OktaAPIFields) *oktaClient {
+	c := &oktaClient{
+		httpClient:      &http.Client{Timeout: oktaRequestTimeout},
+		orgURL:          strings.TrimSuffix(config.OrgURL, "/"),
+		apiToken:        config.APIToken,
+		userIDAttribute: config.UserIDAttribute,
+		limit:           config.SearchLimit,
+	}
+	if c.userIDAttribute == "" {
+		c.userIDAttribute = oktaDefaultUserIDAttribute
+	}
+	if c.limit <= 0 {
+		c.limit = oktaDefaultSearchLimit
+	}
+	return c
+}
+
+// searchUsers returns users whose first name, last name or email starts with the search term.
+func (c *oktaClient) searchUsers(searchTerm string) ([]oktaUser, error) {
+	var users []oktaUser
+	query := url.Values{"q": {searchTerm}, "limit": {strconv.FormatInt(c.limit, 10)}}
+	if err := c.get("/api/v1/users", query, &users); err != nil {
+		return nil, fmt.Errorf("searching okta users: %w", err)
+	}
+	return users, nil
+}
+
+// searchGroups returns groups whose name starts with the search term.
+func (c *oktaClient) searchGroups(searchTerm string) ([]oktaGroup, error) {
+	var groups []oktaGroup
+	query := url.Values{"q": {searchTerm}, "limit": {strconv.FormatInt(c.limit, 10)}}
+	if err := c.get("/api/v1/groups", query, &groups); err != nil {
+		return nil, fmt.Errorf("searching okta groups: %w", err)
+	}
+	return groups, nil
+}
+
+// getUser returns the user whose user ID attribute matches the given value, or nil if there is none.
+func (c *oktaClient) getUser(userID string) (*oktaUser, error) {
+	var users []oktaUser
+	filter := fmt.Sprintf("profile.%s eq %s", c.userIDAttribute, strconv.Quote(userID))
+	query := url.Values{"search": {filter}, "limit": {"1"}}
+	if err := c.get("/api/v1/users", query, &users); err != nil {
+		return nil, fmt.Errorf("getting okta user: %w", err)
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
+
+// getGroup returns the group with the given name, or nil if there is none.
+func (c *oktaClient) getGroup(name string) (*oktaGroup, error) {
+	groups, err := c.searchGroups(name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		if groups[i].Profile.Name == name {
+			return &groups[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *oktaClient) get(path string, query url.Values, result any) error {
+	req, err := http.NewRequest(http.MethodGet, c.orgURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "SSWS "+c.apiToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from okta: %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, result)
+}
+
+// userToPrincipal converts an Okta user to a principal of the provider.
+// The principal ID is formed from the user ID attribute so that it matches the UID released in SAML assertions.
+func (c *oktaClient) userToPrincipal(providerName string, user oktaUser) v3.Principal {
+	login := profileString(user.Profile, "login")
+	displayName := profileString(user.Profile, "displayName")
+	if displayName == "" {
+		displayName = strings.TrimSpace(profileString(user.Profile, "firstName") + " " + profileString(user.Profile, "lastName"))
+	}
+	if displayName == "" {
+		displayName = login
+	}
+	return v3.Principal{
+		ObjectMeta:  metav1.ObjectMeta{Name: providerName + "_user://" + profileString(user.Profile, c.userIDAttribute)},
+		DisplayName: displayName,
+		LoginName:   login,
+		Provider:    providerName,
+	}
+}
+
+// groupToPrincipal converts an Okta group to a principal of the provider.
+func (c *oktaClient) groupToPrincipal(providerName string, group oktaGroup) v3.Principal {
+	return v3.Principal{
+		ObjectMeta:  metav1.ObjectMeta{Name: providerName + "_group://" + group.Profile.Name},
+		DisplayName: group.Profile.Name,
+		LoginName:   group.Profile.Name,
+		Provider:    providerName,
+	}
+}
+
+func profileString(profile map[string]any, key string) string {
+	value, _ := profile[key].(string)
+	return value
+}
@@ -0,0 +1,112 @@
+package saml
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeOktaServer(t *testing.T) *httptest.Server {
+	users := []map[string]any{
+		{
+			"id":      "00u1",
+			"status":  "ACTIVE",
+			"profile": map[string]any{"login": "alice@example.com", "email": "alice@example.com", "firstName": "Alice", "lastName": "Smith"},
+		},
+	}
+	groups := []map[string]any{
+		{"id": "00g1", "profile": map[string]any{"name": "admins"}},
+		{"id": "00g2", "profile": map[string]any{"name": "admins-readonly"}},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "SSWS secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var result any
+		switch r.URL.Path {
+		case "/api/v1/users":
+			result = []map[string]any{}
+			if r.URL.Query().Get("q") == "ali" || r.URL.Query().Get("search") == `profile.login eq "alice@example.com"` {
+				result = users
+			}
+		case "/api/v1/groups":
+			result = groups
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(result))
+	}))
+}
+
+func TestSearchPrincipalsOktaAPI(t *testing.T) {
+	server := newFakeOktaServer(t)
+	defer server.Close()
+
+	provider := &Provider{
+		name:      OKTAName,
+		userType:  "okta_user",
+		groupType: "okta_group",
+		ldapProvider: &mockLdapProvider{
+			providerName: OKTAName,
+		},
+		authConfigsRaw: mockGenericClient{ObjectMap: map[string]interface{}{
+			"oktaApiConfig": map[string]interface{}{
+				"orgUrl":   server.URL,
+				"apiToken": "secret-token",
+			},
+		}},
+	}
+
+	principals, err := provider.SearchPrincipals("ali", common.UserPrincipalType, &v3.Token{})
+	require.NoError(t, err)
+	require.Len(t, principals, 1)
+	assert.Equal(t, "okta_user://alice@example.com", principals[0].Name)
+	assert.Equal(t, "Alice Smith", principals[0].DisplayName)
+	assert.Equal(t, common.UserPrincipalType, principals[0].PrincipalType)
+
+	principals, err = provider.SearchPrincipals("admins", common.GroupPrincipalType, nil)
+	require.NoError(t, err)
+	require.Len(t, principals, 2)
+	assert.Equal(t, "okta_group://admins", principals[0].Name)
+	assert.Equal(t, common.GroupPrincipalType, principals[0].PrincipalType)
+
+	principal, err := provider.GetPrincipal("okta_user://alice@example.com", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Smith", principal.DisplayName)
+	assert.Equal(t, "alice@example.com", principal.LoginName)
+
+	principal, err = provider.GetPrincipal("okta_group://admins", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "okta_group://admins", principal.Name)
+
+	// principals unknown to okta are still resolved so existing bindings keep working
+	principal, err = provider.GetPrincipal("okta_user://bob", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "okta_user://bob", principal.Name)
+	assert.Equal(t, "bob", principal.DisplayName)
+}
+
+func TestOktaClientErrors(t *testing.T) {
+	server := newFakeOktaServer(t)
+	defer server.Close()
+
+	client := &oktaClient{
+		httpClient:      server.Client(),
+		orgURL:          server.URL,
+		apiToken:        "wrong-token",
+		userIDAttribute: oktaDefaultUserIDAttribute,
+		limit:           oktaDefaultSearchLimit,
+	}
+
+	_, err := client.searchUsers("ali")
+	assert.ErrorContains(t, err, "unexpected status code 401")
+}
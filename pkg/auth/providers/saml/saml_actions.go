@@ -63,11 +63,21 @@ func (s *Provider) testAndEnable(actionName string, action *types.Action, reques
 	finalRedirectURL := samlLogin.FinalRedirectURL
 	logrus.Debugf("SAML [testAndEnable]: Final redirect will be (%v)", finalRedirectURL)
 
-	provider.clientState.SetPath(provider.serviceProvider.AcsURL.Path)
+	wsFedEndpoint := provider.wsFedEndpoint()
+	if wsFedEndpoint != "" {
+		provider.clientState.SetPath(provider.wsFedURL.Path)
+	} else {
+		provider.clientState.SetPath(provider.serviceProvider.AcsURL.Path)
+	}
 	provider.clientState.SetState(request.Response, request.Request, "Rancher_FinalRedirectURL", finalRedirectURL)
 	provider.clientState.SetState(request.Response, request.Request, "Rancher_Action", testAndEnableAction)
 
-	idpRedirectURL, err := provider.HandleSamlLogin(request.Response, request.Request, provider.userMGR.GetUser(request))
+	var idpRedirectURL string
+	if wsFedEndpoint != "" {
+		idpRedirectURL, err = provider.HandleWSFedLogin(request.Response, request.Request, wsFedEndpoint, provider.userMGR.GetUser(request))
+	} else {
+		idpRedirectURL, err = provider.HandleSamlLogin(request.Response, request.Request, provider.userMGR.GetUser(request))
+	}
 	if err != nil {
 		return err
 	}
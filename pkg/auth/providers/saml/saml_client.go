@@ -22,6 +22,7 @@
 	"github.com/gorilla/mux"
 	responsewriter "github.com/rancher/apiserver/pkg/middleware"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/attributelabels"
 	"github.com/rancher/rancher/pkg/auth/settings"
 	"github.com/rancher/rancher/pkg/auth/tokens"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
@@ -145,6 +146,8 @@ func InitializeSamlServiceProvider(configToSet *v32.SamlConfig, name string) err
 	acsURL.Path = acsURL.Path + "/saml/acs"
 	sloURL := *actURL
 	sloURL.Path = sloURL.Path + "/saml/slo"
+	wsFedURL := *actURL
+	wsFedURL.Path = wsFedURL.Path + "/wsfed"
 
 	sp := saml.ServiceProvider{
 		Key:             privKey,
@@ -177,7 +180,19 @@ func InitializeSamlServiceProvider(configToSet *v32.SamlConfig, name string) err
 		sp.AuthnNameIDFormat = saml.UnspecifiedNameIDFormat
 	}
 
+	provider.artifactBinding = configToSet.ArtifactBindingEnabled
+	if configToSet.ArtifactBindingEnabled {
+		if sp.GetArtifactBindingLocation(saml.SOAPBinding) == "" {
+			return fmt.Errorf("SAML: cannot enable the artifact binding, the IDP metadata has no SOAP artifact resolution service")
+		}
+		sp.HTTPClient, err = newArtifactResolutionClient(privKey, cert, configToSet.ArtifactResolutionCACerts)
+		if err != nil {
+			return err
+		}
+	}
+
 	provider.serviceProvider = &sp
+	provider.wsFedURL = &wsFedURL
 
 	cookieStore := ClientCookies{
 		ServiceProvider: &sp,
@@ -194,26 +209,32 @@ func InitializeSamlServiceProvider(configToSet *v32.SamlConfig, name string) err
 	switch name {
 	case PingName:
 		root.Get("PingACS").HandlerFunc(provider.ServeHTTP)
+		root.Get("PingACSGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("PingSLO").HandlerFunc(provider.ServeHTTP)
 		root.Get("PingSLOGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("PingMetadata").HandlerFunc(provider.ServeHTTP)
 	case ADFSName:
 		root.Get("AdfsACS").HandlerFunc(provider.ServeHTTP)
+		root.Get("AdfsACSGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("AdfsSLO").HandlerFunc(provider.ServeHTTP)
 		root.Get("AdfsSLOGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("AdfsMetadata").HandlerFunc(provider.ServeHTTP)
+		root.Get("AdfsWSFed").HandlerFunc(provider.ServeHTTP)
 	case KeyCloakName:
 		root.Get("KeyCloakACS").HandlerFunc(provider.ServeHTTP)
+		root.Get("KeyCloakACSGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("KeyCloakSLO").HandlerFunc(provider.ServeHTTP)
 		root.Get("KeyCloakSLOGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("KeyCloakMetadata").HandlerFunc(provider.ServeHTTP)
 	case OKTAName:
 		root.Get("OktaACS").HandlerFunc(provider.ServeHTTP)
+		root.Get("OktaACSGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("OktaSLO").HandlerFunc(provider.ServeHTTP)
 		root.Get("OktaSLOGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("OktaMetadata").HandlerFunc(provider.ServeHTTP)
 	case ShibbolethName:
 		root.Get("ShibbolethACS").HandlerFunc(provider.ServeHTTP)
+		root.Get("ShibbolethACSGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("ShibbolethSLO").HandlerFunc(provider.ServeHTTP)
 		root.Get("ShibbolethSLOGet").HandlerFunc(provider.ServeHTTP)
 		root.Get("ShibbolethMetadata").HandlerFunc(provider.ServeHTTP)
@@ -228,26 +249,33 @@ func AuthHandler() http.Handler {
 	root = mux.NewRouter()
 
 	root.Methods("POST").Path("/v1-saml/ping/saml/acs").Name("PingACS")
+	// Artifacts of the HTTP-Artifact binding can be sent with a redirect.
+	root.Methods("GET").Path("/v1-saml/ping/saml/acs").Name("PingACSGet")
 	root.Methods("POST").Path("/v1-saml/ping/saml/slo").Name("PingSLO")
 	root.Methods("GET").Path("/v1-saml/ping/saml/slo").Name("PingSLOGet")
 	root.Methods("GET").Path("/v1-saml/ping/saml/metadata").Name("PingMetadata")
 
 	root.Methods("POST").Path("/v1-saml/adfs/saml/acs").Name("AdfsACS")
+	root.Methods("GET").Path("/v1-saml/adfs/saml/acs").Name("AdfsACSGet")
 	root.Methods("POST").Path("/v1-saml/adfs/saml/slo").Name("AdfsSLO")
 	root.Methods("GET").Path("/v1-saml/adfs/saml/slo").Name("AdfsSLOGet")
 	root.Methods("GET").Path("/v1-saml/adfs/saml/metadata").Name("AdfsMetadata")
+	root.Methods("POST").Path("/v1-saml/adfs/wsfed").Name("AdfsWSFed")
 
 	root.Methods("POST").Path("/v1-saml/keycloak/saml/acs").Name("KeyCloakACS")
+	root.Methods("GET").Path("/v1-saml/keycloak/saml/acs").Name("KeyCloakACSGet")
 	root.Methods("POST").Path("/v1-saml/keycloak/saml/slo").Name("KeyCloakSLO")
 	root.Methods("GET").Path("/v1-saml/keycloak/saml/slo").Name("KeyCloakSLOGet")
 	root.Methods("GET").Path("/v1-saml/keycloak/saml/metadata").Name("KeyCloakMetadata")
 
 	root.Methods("POST").Path("/v1-saml/okta/saml/acs").Name("OktaACS")
+	root.Methods("GET").Path("/v1-saml/okta/saml/acs").Name("OktaACSGet")
 	root.Methods("POST").Path("/v1-saml/okta/saml/slo").Name("OktaSLO")
 	root.Methods("GET").Path("/v1-saml/okta/saml/slo").Name("OktaSLOGet")
 	root.Methods("GET").Path("/v1-saml/okta/saml/metadata").Name("OktaMetadata")
 
 	root.Methods("POST").Path("/v1-saml/shibboleth/saml/acs").Name("ShibbolethACS")
+	root.Methods("GET").Path("/v1-saml/shibboleth/saml/acs").Name("ShibbolethACSGet")
 	root.Methods("POST").Path("/v1-saml/shibboleth/saml/slo").Name("ShibbolethSLO")
 	root.Methods("GET").Path("/v1-saml/shibboleth/saml/slo").Name("ShibbolethSLOGet")
 	root.Methods("GET").Path("/v1-saml/shibboleth/saml/metadata").Name("ShibbolethMetadata")
@@ -281,6 +309,10 @@ func (s *Provider) getSamlPrincipals(config *v32.SamlConfig, samlData map[string
 		userPrincipal.LoginName = userName[0]
 	}
 
+	userPrincipal.ExtraInfo = attributelabels.ExtraInfo(func(attribute string) []string {
+		return samlData[attribute]
+	})
+
 	groups, ok := samlData[config.GroupsField]
 	if ok {
 		for _, group := range groups {
@@ -411,6 +443,22 @@ func (s *Provider) HandleSamlAssertion(w http.ResponseWriter, r *http.Request, a
 		http.Redirect(w, r, redirectURL+"errorCode=422&err="+UITranslationKeyForErrorMessage, http.StatusFound)
 		return
 	}
+
+	if s.name == ShibbolethName {
+		entitlementMappings, err := s.getShibbolethEntitlementMappings(s.authConfigsRaw)
+		if err != nil {
+			log.Errorf("SAML: Error getting Shibboleth entitlement mappings %v", err)
+			http.Redirect(w, r, redirectURL+"errorCode=500", http.StatusFound)
+			return
+		}
+		entitlementGroups, err := s.entitlementGroupPrincipals(entitlementMappings, samlData, groupPrincipals)
+		if err != nil {
+			log.Errorf("SAML: Error mapping Shibboleth entitlements %v", err)
+			http.Redirect(w, r, redirectURL+"errorCode=500", http.StatusFound)
+			return
+		}
+		groupPrincipals = append(groupPrincipals, entitlementGroups...)
+	}
 	allowedPrincipals := config.AllowedPrincipalIDs
 
 	allowed, err := s.userMGR.CheckAccess(config.AccessMode, allowedPrincipals, userPrincipal.Name, groupPrincipals)
@@ -492,6 +540,10 @@ func (s *Provider) HandleSamlAssertion(w http.ResponseWriter, r *http.Request, a
 		return
 	}
 
+	if err := s.provisioner.Provision(user, s.name, groupPrincipals, userExtraInfo); err != nil {
+		log.Errorf("SAML: Failed provisioning user %s with error: %v", user.Name, err)
+	}
+
 	err = s.setRancherToken(w, s.tokenMGR, user.Name, userPrincipal, groupPrincipals, true)
 	if err != nil {
 		log.Errorf("SAML: Failed creating token with error: %v", err)
@@ -15,7 +15,7 @@
 
 const rancherUserID = "rancherUserID"
 
-// ServeHTTP is the handler for /saml/metadata and /saml/acs endpoints
+// ServeHTTP is the handler for /saml/metadata, /saml/acs, /saml/slo and /wsfed endpoints
 func (s *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	serviceProvider := s.serviceProvider
 
@@ -62,6 +62,24 @@ func (s *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.wsFedURL != nil && r.URL.Path == s.wsFedURL.Path {
+		log.Debugf("SAML [ServeHTTP]: WS-Federation sign-in processing started")
+
+		s.HandleWSFedSignIn(w, r)
+
+		log.Debugf("SAML [ServeHTTP]: WS-Federation sign-in processing completed")
+		return
+	}
+
+	if r.URL.Path == serviceProvider.SloURL.Path && r.Form.Get("SAMLRequest") != "" {
+		log.Debugf("SAML [ServeHTTP]: logout request processing started")
+
+		s.HandleSamlLogoutRequest(w, r)
+
+		log.Debugf("SAML [ServeHTTP]: logout request processing completed")
+		return
+	}
+
 	if r.URL.Path == serviceProvider.SloURL.Path {
 		log.Debugf("SAML [ServeHTTP]: logout response processing started")
 
@@ -111,7 +129,7 @@ func (s *Provider) HandleSamlLogin(w http.ResponseWriter, r *http.Request, userI
 	binding := saml.HTTPRedirectBinding
 	bindingLocation := serviceProvider.GetSSOBindingLocation(binding)
 
-	req, err := serviceProvider.MakeAuthenticationRequest(bindingLocation, binding, saml.HTTPPostBinding)
+	req, err := serviceProvider.MakeAuthenticationRequest(bindingLocation, binding, s.responseBinding())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return "", err
@@ -2,23 +2,32 @@
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/crewjam/saml"
 	"github.com/pkg/errors"
 	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/objectclient"
 	"github.com/rancher/norman/types"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth/accessor"
 	"github.com/rancher/rancher/pkg/auth/api/secrets"
+	"github.com/rancher/rancher/pkg/auth/jitprovisioning"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/providers/ldap"
+	"github.com/rancher/rancher/pkg/auth/sessions"
 	"github.com/rancher/rancher/pkg/auth/tokens"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	publicclient "github.com/rancher/rancher/pkg/client/generated/management/v3public"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/user"
@@ -41,11 +50,16 @@
 type Provider struct {
 	ctx             context.Context
 	authConfigs     v3.AuthConfigInterface
+	authConfigsRaw  objectclient.GenericClient
 	secrets         wcorev1.SecretController
 	samlTokens      v3.SamlTokenInterface
 	userMGR         user.Manager
 	tokenMGR        *tokens.Manager
+	provisioner     *jitprovisioning.Provisioner
+	userAttributes  mgmtcontrollers.UserAttributeCache
+	logouts         userLogouts
 	serviceProvider *saml.ServiceProvider
+	wsFedURL        *url.URL
 	name            string
 	userType        string
 	groupType       string
@@ -53,22 +67,27 @@ type Provider struct {
 	ldapProvider    common.AuthProvider
 	sloEnabled      bool
 	sloForced       bool
+	artifactBinding bool
 }
 
 var SamlProviders = make(map[string]*Provider)
 
 func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, userMGR user.Manager, tokenMGR *tokens.Manager, name string) common.AuthProvider {
 	samlp := &Provider{
-		ctx:         ctx,
-		authConfigs: mgmtCtx.Management.AuthConfigs(""),
-		secrets:     mgmtCtx.Wrangler.Core.Secret(),
-		samlTokens:  mgmtCtx.Management.SamlTokens(""),
-		userMGR:     userMGR,
-		tokenMGR:    tokenMGR,
-		name:        name,
-		userType:    name + "_user",
-		groupType:   name + "_group",
-	}
+		ctx:            ctx,
+		authConfigs:    mgmtCtx.Management.AuthConfigs(""),
+		secrets:        mgmtCtx.Wrangler.Core.Secret(),
+		samlTokens:     mgmtCtx.Management.SamlTokens(""),
+		userMGR:        userMGR,
+		tokenMGR:       tokenMGR,
+		provisioner:    jitprovisioning.NewProvisioner(mgmtCtx.Wrangler),
+		userAttributes: mgmtCtx.Wrangler.Mgmt.UserAttribute().Cache(),
+		logouts:        sessions.NewRevoker(ctx, mgmtCtx),
+		name:           name,
+		userType:       name + "_user",
+		groupType:      name + "_group",
+	}
+	samlp.authConfigsRaw = samlp.authConfigs.ObjectClient().UnstructuredClient()
 
 	if samlp.hasLdapGroupSearch() {
 		samlp.ldapProvider = ldap.Configure(ctx, mgmtCtx, userMGR, tokenMGR, name)
@@ -166,6 +185,19 @@ func (s *Provider) LogoutAll(apiContext *types.APIContext, token accessor.TokenA
 	finalRedirectURL := samlLogout.FinalRedirectURL
 
 	w := apiContext.Response
+	if endpoint := provider.wsFedEndpoint(); endpoint != "" {
+		idpRedirectURL, err := wsFedSignOutURL(endpoint, finalRedirectURL)
+		if err != nil {
+			return err
+		}
+		logrus.Debugf("SAML [logout-all]: Redirecting to the identity provider WS-Federation sign-out page at %v", idpRedirectURL)
+		apiContext.WriteResponse(http.StatusOK, map[string]interface{}{
+			"idpRedirectUrl": idpRedirectURL,
+			"type":           "samlConfigLogoutOutput",
+		})
+		return nil
+	}
+
 	provider.clientState.SetPath(provider.serviceProvider.SloURL.Path)
 	provider.clientState.SetState(w, r, "Rancher_FinalRedirectURL", finalRedirectURL)
 	provider.clientState.SetState(w, r, "Rancher_Action", "logout-all")
@@ -206,7 +238,12 @@ func PerformSamlLogin(name string, apiContext *types.APIContext, input interface
 			return fmt.Errorf("SAML: Provider %v clientState not set", name)
 		}
 
-		provider.clientState.SetPath(provider.serviceProvider.AcsURL.Path)
+		wsFedEndpoint := provider.wsFedEndpoint()
+		if wsFedEndpoint != "" {
+			provider.clientState.SetPath(provider.wsFedURL.Path)
+		} else {
+			provider.clientState.SetPath(provider.serviceProvider.AcsURL.Path)
+		}
 		provider.clientState.SetState(apiContext.Response, apiContext.Request, "Rancher_FinalRedirectURL", finalRedirectURL)
 		provider.clientState.SetState(apiContext.Response, apiContext.Request, "Rancher_Action", loginAction)
 		provider.clientState.SetState(apiContext.Response, apiContext.Request, "Rancher_PublicKey", login.PublicKey)
@@ -214,7 +251,13 @@ func PerformSamlLogin(name string, apiContext *types.APIContext, input interface
 		provider.clientState.SetState(apiContext.Response, apiContext.Request, "Rancher_ResponseType", login.ResponseType)
 
 		// userID is not needed for login. It's only needed for testAndEnable
-		idpRedirectURL, err := provider.HandleSamlLogin(apiContext.Response, apiContext.Request, "")
+		var idpRedirectURL string
+		var err error
+		if wsFedEndpoint != "" {
+			idpRedirectURL, err = provider.HandleWSFedLogin(apiContext.Response, apiContext.Request, wsFedEndpoint, "")
+		} else {
+			idpRedirectURL, err = provider.HandleSamlLogin(apiContext.Response, apiContext.Request, "")
+		}
 		if err != nil {
 			return err
 		}
@@ -317,7 +360,11 @@ func (s *Provider) saveSamlConfig(config *v32.SamlConfig) error {
 		return nil
 	}
 
-	_, err = s.authConfigs.ObjectClient().Update(config.ObjectMeta.Name, config)
+	fullConfig, err := s.withStoredConfig(config)
+	if err != nil {
+		return err
+	}
+	_, err = s.authConfigs.ObjectClient().Update(config.ObjectMeta.Name, fullConfig)
 	return err
 }
 
@@ -359,6 +406,14 @@ func (s *Provider) SearchPrincipals(searchKey, principalType string, token acces
 		}
 	}
 
+	okta, err := s.getOktaClient()
+	if err != nil {
+		return nil, err
+	}
+	if okta != nil {
+		return s.searchOktaPrincipals(okta, searchKey, principalType, token)
+	}
+
 	var principals []v3.Principal
 
 	if principalType != common.GroupPrincipalType {
@@ -401,6 +456,17 @@ func (s *Provider) GetPrincipal(principalID string, token accessor.TokenAccessor
 		}
 	}
 
+	okta, err := s.getOktaClient()
+	if err != nil {
+		return v3.Principal{}, err
+	}
+	if okta != nil {
+		p, found, err := s.getOktaPrincipal(okta, externalID, principalType, token)
+		if err != nil || found {
+			return p, err
+		}
+	}
+
 	p := v3.Principal{
 		ObjectMeta:  metav1.ObjectMeta{Name: principalType + "://" + externalID},
 		DisplayName: externalID,
@@ -412,6 +478,98 @@ func (s *Provider) GetPrincipal(principalID string, token accessor.TokenAccessor
 	return p, nil
 }
 
+// getOktaClient returns a client for the Okta API if the provider is Okta and the API is configured, otherwise nil.
+func (s *Provider) getOktaClient() (*oktaClient, error) {
+	if s.name != OKTAName {
+		return nil, nil
+	}
+	config, err := s.getOktaAPIConfig(s.authConfigsRaw, true)
+	if err != nil {
+		return nil, err
+	}
+	if config.OrgURL == "" || config.APIToken == "" {
+		return nil, nil
+	}
+	return newOktaClient(config), nil
+}
+
+// getOktaAPIConfig returns the stored Okta API configuration.
+// If resolveSecret is true the API token is read from its secret.
+func (s *Provider) getOktaAPIConfig(genericClient objectclient.GenericClient, resolveSecret bool) (*v32.OktaAPIFields, error) {
+	authConfigObj, err := genericClient.Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("SAML: failed to retrieve OKTAConfig, error: %w", err)
+	}
+	u, ok := authConfigObj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("SAML: failed to retrieve OKTAConfig, cannot read k8s Unstructured data")
+	}
+
+	config := &v32.OktaAPIFields{}
+	subConfig, ok := u.UnstructuredContent()[client.OKTAConfigFieldOktaAPIConfig].(map[string]interface{})
+	if !ok {
+		return config, nil
+	}
+	if err := common.Decode(subConfig, config); err != nil {
+		return nil, fmt.Errorf("unable to decode Okta API Config: %w", err)
+	}
+
+	if resolveSecret && config.APIToken != "" {
+		value, err := common.ReadFromSecret(s.secrets, config.APIToken, strings.ToLower(client.OktaAPIFieldsFieldAPIToken))
+		if err != nil {
+			return nil, err
+		}
+		config.APIToken = value
+	}
+	return config, nil
+}
+
+// searchOktaPrincipals searches users and groups of the requested principalType using the Okta API.
+func (s *Provider) searchOktaPrincipals(okta *oktaClient, searchKey, principalType string, token accessor.TokenAccessor) ([]v3.Principal, error) {
+	var principals []v3.Principal
+
+	if principalType != common.GroupPrincipalType {
+		users, err := okta.searchUsers(searchKey)
+		if err != nil {
+			logrus.Errorf("SAML: error searching okta users: %v", err)
+			return nil, err
+		}
+		for _, user := range users {
+			principals = append(principals, s.toPrincipal(s.userType, okta.userToPrincipal(s.name, user), token))
+		}
+	}
+
+	if principalType != common.UserPrincipalType {
+		groups, err := okta.searchGroups(searchKey)
+		if err != nil {
+			logrus.Errorf("SAML: error searching okta groups: %v", err)
+			return nil, err
+		}
+		for _, group := range groups {
+			principals = append(principals, s.toPrincipal(s.groupType, okta.groupToPrincipal(s.name, group), token))
+		}
+	}
+
+	return principals, nil
+}
+
+// getOktaPrincipal looks up a principal using the Okta API. It returns false if the principal doesn't exist in Okta.
+func (s *Provider) getOktaPrincipal(okta *oktaClient, externalID, principalType string, token accessor.TokenAccessor) (v3.Principal, bool, error) {
+	if principalType == s.userType {
+		user, err := okta.getUser(externalID)
+		if err != nil || user == nil {
+			return v3.Principal{}, false, err
+		}
+		return s.toPrincipal(principalType, okta.userToPrincipal(s.name, *user), token), true, nil
+	}
+
+	group, err := okta.getGroup(externalID)
+	if err != nil || group == nil {
+		return v3.Principal{}, false, err
+	}
+	return s.toPrincipal(principalType, okta.groupToPrincipal(s.name, *group), token), true, nil
+}
+
 func (s *Provider) isThisUserMe(me, other v3.Principal) bool {
 	return me.ObjectMeta.Name == other.ObjectMeta.Name &&
 		me.PrincipalType == other.PrincipalType
@@ -468,13 +626,13 @@ func (s *Provider) combineSamlAndLdapConfig(config *v32.SamlConfig) (runtime.Obj
 
 		// if the the config subkey not in the crd
 		if ldapConfig == nil {
-			return config, nil
+			return s.withStoredConfig(config)
 		}
 
 		// only return the saml config on other errors
 		// if not configured it might have data in it we want to keep
 		if !ldap.IsNotConfigured(err) {
-			return config, nil
+			return s.withStoredConfig(config)
 		}
 	}
 
@@ -496,20 +654,72 @@ func (s *Provider) combineSamlAndLdapConfig(config *v32.SamlConfig) (runtime.Obj
 		ldapConfig.LdapFields.ServiceAccountPassword = secretName
 		// Set the status for SecretsMigrated to True so it doesn't get re-migrated
 		v32.AuthConfigConditionSecretsMigrated.SetStatus(&samlConfig, "True")
+		entitlementMappings, err := s.getShibbolethEntitlementMappings(s.authConfigsRaw)
+		if err != nil {
+			return config, err
+		}
 		fullConfig = &v32.ShibbolethConfig{
-			SamlConfig:     samlConfig,
-			OpenLdapConfig: ldapConfig.LdapFields,
+			SamlConfig:          samlConfig,
+			OpenLdapConfig:      ldapConfig.LdapFields,
+			EntitlementMappings: entitlementMappings,
 		}
 	case OKTAName:
+		oktaAPIConfig, err := s.getOktaAPIConfig(s.authConfigsRaw, false)
+		if err != nil {
+			return config, err
+		}
 		fullConfig = &v32.OKTAConfig{
 			SamlConfig:     samlConfig,
 			OpenLdapConfig: ldapConfig.LdapFields,
+			OktaAPIConfig:  *oktaAPIConfig,
 		}
 	}
 
 	return fullConfig, nil
 }
 
+// withStoredConfig adds the stored provider specific configuration, like the Okta API configuration,
+// the Shibboleth entitlement mappings or the ADFS WS-Federation configuration, to the config so it isn't lost when the config is saved without
+// an LDAP configuration.
+func (s *Provider) withStoredConfig(config *v32.SamlConfig) (runtime.Object, error) {
+	switch s.name {
+	case OKTAName:
+		oktaAPIConfig, err := s.getOktaAPIConfig(s.authConfigsRaw, false)
+		if err != nil {
+			return config, err
+		}
+		if oktaAPIConfig.OrgURL == "" {
+			return config, nil
+		}
+		oktaConfig := &v32.OKTAConfig{OktaAPIConfig: *oktaAPIConfig}
+		config.DeepCopyInto(&oktaConfig.SamlConfig)
+		return oktaConfig, nil
+	case ShibbolethName:
+		entitlementMappings, err := s.getShibbolethEntitlementMappings(s.authConfigsRaw)
+		if err != nil {
+			return config, err
+		}
+		if len(entitlementMappings) == 0 {
+			return config, nil
+		}
+		shibbolethConfig := &v32.ShibbolethConfig{EntitlementMappings: entitlementMappings}
+		config.DeepCopyInto(&shibbolethConfig.SamlConfig)
+		return shibbolethConfig, nil
+	case ADFSName:
+		wsFedConfig, err := s.getWSFedConfig(s.authConfigsRaw)
+		if err != nil {
+			return config, err
+		}
+		if *wsFedConfig == (v32.WSFedFields{}) {
+			return config, nil
+		}
+		adfsConfig := &v32.ADFSConfig{WSFedConfig: *wsFedConfig}
+		config.DeepCopyInto(&adfsConfig.SamlConfig)
+		return adfsConfig, nil
+	}
+	return config, nil
+}
+
 func (s *Provider) hasLdapGroupSearch() bool {
 	return ShibbolethName == s.name || OKTAName == s.name
 }
@@ -526,3 +736,52 @@ func (s *Provider) IsDisabledProvider() (bool, error) {
 	}
 	return !samlConfig.Enabled, nil
 }
+
+// Probe checks that the single sign-on services of the IdP answer, and reports the expiry of the IdP metadata and of
+// the certificates of the IdP and the service provider.
+func (s *Provider) Probe(ctx context.Context) (*v32.AuthConfigConnectivity, error) {
+	config, err := s.getSamlConfig()
+	if err != nil {
+		return nil, err
+	}
+	idm := &IDPMetadata{}
+	if err := xml.NewDecoder(strings.NewReader(config.IDPMetadataContent)).Decode(idm); err != nil {
+		return nil, fmt.Errorf("SAML: cannot decode IDP Metadata content: %w", err)
+	}
+
+	connectivity := &v32.AuthConfigConnectivity{
+		Credentials: common.CertificateExpiries("spCert", config.SpCert),
+	}
+	if !idm.ValidUntil.IsZero() {
+		connectivity.MetadataValidUntil = idm.ValidUntil.UTC().Format(time.RFC3339)
+	}
+
+	httpClient := &http.Client{Timeout: common.ProbeTimeout}
+	probed := map[string]bool{}
+	for _, descriptor := range idm.IDPSSODescriptors {
+		for _, key := range descriptor.KeyDescriptors {
+			for _, certificate := range key.KeyInfo.X509Data.X509Certificates {
+				// The certificates are base64 encoded DER, commonly wrapped across lines.
+				der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(certificate.Data), ""))
+				if err != nil {
+					continue
+				}
+				cert, err := x509.ParseCertificate(der)
+				if err != nil {
+					continue
+				}
+				connectivity.Credentials = append(connectivity.Credentials, common.CertificateExpiry("idpCertificate", cert))
+			}
+		}
+		// Each binding of the service usually shares its location.
+		for _, service := range descriptor.SingleSignOnServices {
+			if probed[service.Location] {
+				continue
+			}
+			probed[service.Location] = true
+			connectivity.Endpoints = append(connectivity.Endpoints, common.ProbeURL(ctx, httpClient, "singleSignOnService", service.Location))
+		}
+	}
+
+	return connectivity, nil
+}
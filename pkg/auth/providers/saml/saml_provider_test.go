@@ -4,6 +4,7 @@
 	"context"
 	"testing"
 
+	"github.com/rancher/norman/objectclient"
 	"github.com/rancher/norman/types"
 	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/auth/accessor"
@@ -16,6 +17,11 @@
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/rest"
 )
 
@@ -101,6 +107,7 @@ func TestSearchPrincipals(t *testing.T) {
 					providerName:     providerName,
 					isLdapConfigured: tt.isLdapConfigured,
 				},
+				authConfigsRaw: mockGenericClient{},
 			}
 
 			results, err := provider.SearchPrincipals(tt.searchKey, tt.principalType, &v3.Token{})
@@ -121,6 +128,7 @@ func TestSearchPrincipals(t *testing.T) {
 					providerName:     providerName,
 					isLdapConfigured: tt.isLdapConfigured,
 				},
+				authConfigsRaw: mockGenericClient{},
 			}
 
 			results, err := provider.SearchPrincipals(tt.searchKey, tt.principalType, &ext.Token{})
@@ -177,6 +185,9 @@ func (p *mockLdapProvider) CustomizeSchema(schema *types.Schema) {
 }
 
 func (p *mockLdapProvider) GetPrincipal(principalID string, token accessor.TokenAccessor) (v3.Principal, error) {
+	if !p.isLdapConfigured {
+		return v3.Principal{}, ldap.ErrorNotConfigured{}
+	}
 	panic("GetPrincipal Unimplemented!")
 }
 
@@ -203,3 +214,54 @@ func (p *mockLdapProvider) GetUserExtraAttributesFromToken(token accessor.TokenA
 func (p *mockLdapProvider) IsDisabledProvider() (bool, error) {
 	panic("IsDisabledProvider Unimplemented!")
 }
+
+// mockGenericClient returns the ObjectMap as the stored auth config.
+type mockGenericClient struct {
+	ObjectMap map[string]interface{}
+}
+
+func (m mockGenericClient) UnstructuredClient() objectclient.GenericClient {
+	panic("unimplemented")
+}
+func (m mockGenericClient) GroupVersionKind() schema.GroupVersionKind {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Create(o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) GetNamespaced(namespace, name string, opts metav1.GetOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Get(name string, opts metav1.GetOptions) (runtime.Object, error) {
+	return &unstructured.Unstructured{Object: m.ObjectMap}, nil
+}
+func (m mockGenericClient) Update(name string, o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) UpdateStatus(name string, o runtime.Object) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) DeleteNamespaced(namespace, name string, opts *metav1.DeleteOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Delete(name string, opts *metav1.DeleteOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) List(opts metav1.ListOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) ListNamespaced(namespace string, opts metav1.ListOptions) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) DeleteCollection(deleteOptions *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	panic("unimplemented")
+}
+func (m mockGenericClient) Patch(name string, o runtime.Object, patchType apitypes.PatchType, data []byte, subresources ...string) (runtime.Object, error) {
+	panic("unimplemented")
+}
+func (m mockGenericClient) ObjectFactory() objectclient.ObjectFactory {
+	panic("unimplemented")
+}
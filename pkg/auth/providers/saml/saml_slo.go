@@ -0,0 +1,220 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers the hashes of the supported query signature algorithms
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	dsig "github.com/russellhaering/goxmldsig"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// maxLogoutRequestSize bounds the inflated logout requests of the HTTP-Redirect binding.
+const maxLogoutRequestSize = 1 << 20
+
+// userLogouts logs users out of all their sessions.
+type userLogouts interface {
+	LogoutEverywhere(userID, reason string) (int, error)
+}
+
+// HandleSamlLogoutRequest processes the logout request sent by the IdP to /saml/slo when the user logs out of another
+// service provider, or of the IdP itself. The users with the name ID are logged out everywhere: their sessions,
+// the tokens derived from them, like the kubeconfig tokens, and the credentials cached for them are revoked.
+func (s *Provider) HandleSamlLogoutRequest(w http.ResponseWriter, r *http.Request) {
+	certs, err := idpSigningCerts(s.serviceProvider.IDPMetadata)
+	if err != nil {
+		log.Errorf("SAML [HandleSamlLogoutRequest]: %v", err)
+		http.Error(w, "cannot validate logout request", http.StatusInternalServerError)
+		return
+	}
+
+	now := saml.TimeNow()
+	logoutRequest, err := parseLogoutRequest(r, certs, now)
+	if err == nil {
+		err = validateLogoutRequest(logoutRequest, s.serviceProvider.IDPMetadata.EntityID, s.serviceProvider.SloURL.String(), now)
+	}
+	if err != nil {
+		log.Debugf("SAML [HandleSamlLogoutRequest]: logout request validation failed: %v", err)
+		http.Error(w, "invalid logout request", http.StatusBadRequest)
+		return
+	}
+
+	userIDs, err := s.usersByNameID(logoutRequest.NameID.Value)
+	if err != nil {
+		log.Errorf("SAML [HandleSamlLogoutRequest]: failed to find the users of name ID %s: %v", logoutRequest.NameID.Value, err)
+		http.Error(w, "failed to process logout request", http.StatusInternalServerError)
+		return
+	}
+	for _, userID := range userIDs {
+		if _, err := s.logouts.LogoutEverywhere(userID, "SAML logout request of "+s.name); err != nil {
+			log.Errorf("SAML [HandleSamlLogoutRequest]: failed to log user %s out: %v", userID, err)
+			http.Error(w, "failed to process logout request", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	redirectURL, err := s.serviceProvider.MakeRedirectLogoutResponse(logoutRequest.ID, r.Form.Get("RelayState"))
+	if err != nil {
+		log.Errorf("SAML [HandleSamlLogoutRequest]: failed to make the logout response: %v", err)
+		http.Error(w, "failed to process logout request", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+
+	log.Debugf("SAML [HandleSamlLogoutRequest]: logged out %d users, redirected to (%s)", len(userIDs), redirectURL)
+}
+
+// usersByNameID returns the users who logged in with the name ID, which Rancher sends the IdP in its own logout requests.
+func (s *Provider) usersByNameID(nameID string) ([]string, error) {
+	attributes, err := s.userAttributes.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []string
+	for _, attribute := range attributes {
+		for _, username := range attribute.ExtraByProvider[s.name][common.UserAttributeUserName] {
+			if username == nameID {
+				userIDs = append(userIDs, attribute.Name)
+				break
+			}
+		}
+	}
+	return userIDs, nil
+}
+
+// parseLogoutRequest returns the logout request of the HTTP-Redirect binding, signed in the query, or of the HTTP-POST
+// binding, signed in the XML. The signature must be made with one of the certificates.
+func parseLogoutRequest(r *http.Request, certs []*x509.Certificate, now time.Time) (*saml.LogoutRequest, error) {
+	if data := r.URL.Query().Get("SAMLRequest"); data != "" {
+		compressed, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode SAMLRequest: %w", err)
+		}
+		raw, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), maxLogoutRequestSize))
+		if err != nil {
+			return nil, fmt.Errorf("cannot inflate SAMLRequest: %w", err)
+		}
+		if err := verifyQuerySignature(r.URL.RawQuery, certs); err != nil {
+			return nil, err
+		}
+		logoutRequest := &saml.LogoutRequest{}
+		if err := xml.Unmarshal(raw, logoutRequest); err != nil {
+			return nil, fmt.Errorf("cannot parse logout request: %w", err)
+		}
+		return logoutRequest, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(r.PostForm.Get("SAMLRequest"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode SAMLRequest: %w", err)
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(raw); err != nil || doc.Root() == nil {
+		return nil, fmt.Errorf("cannot parse logout request: %v", err)
+	}
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certs})
+	validationContext.Clock = dsig.NewFakeClockAt(now)
+	validated, err := validationContext.Validate(doc.Root())
+	if err != nil {
+		return nil, fmt.Errorf("cannot validate the signature of the logout request: %w", err)
+	}
+
+	validatedDoc := etree.NewDocument()
+	validatedDoc.SetRoot(validated.Copy())
+	data, err := validatedDoc.WriteToBytes()
+	if err != nil {
+		return nil, err
+	}
+	logoutRequest := &saml.LogoutRequest{}
+	if err := xml.Unmarshal(data, logoutRequest); err != nil {
+		return nil, fmt.Errorf("cannot parse logout request: %w", err)
+	}
+	return logoutRequest, nil
+}
+
+// verifyQuerySignature verifies the signature of a message of the HTTP-Redirect binding, which is made over the
+// parameters as they were URL-encoded by the IdP.
+func verifyQuerySignature(rawQuery string, certs []*x509.Certificate) error {
+	params := map[string]string{}
+	for _, param := range strings.Split(rawQuery, "&") {
+		key, value, _ := strings.Cut(param, "=")
+		params[key] = value
+	}
+	if params["Signature"] == "" || params["SigAlg"] == "" {
+		return fmt.Errorf("logout request isn't signed")
+	}
+
+	signed := "SAMLRequest=" + params["SAMLRequest"]
+	if relayState, ok := params["RelayState"]; ok {
+		signed += "&RelayState=" + relayState
+	}
+	signed += "&SigAlg=" + params["SigAlg"]
+
+	sigAlg, err := url.QueryUnescape(params["SigAlg"])
+	if err != nil {
+		return fmt.Errorf("cannot decode SigAlg: %w", err)
+	}
+	var hash crypto.Hash
+	switch sigAlg {
+	case dsig.RSASHA256SignatureMethod:
+		hash = crypto.SHA256
+	case dsig.RSASHA512SignatureMethod:
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signature algorithm %s", sigAlg)
+	}
+	encoded, err := url.QueryUnescape(params["Signature"])
+	if err != nil {
+		return fmt.Errorf("cannot decode Signature: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("cannot decode Signature: %w", err)
+	}
+
+	digest := hash.New()
+	digest.Write([]byte(signed))
+	sum := digest.Sum(nil)
+	for _, cert := range certs {
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok && rsa.VerifyPKCS1v15(key, hash, sum, signature) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot validate the signature of the logout request")
+}
+
+// validateLogoutRequest checks the logout request is issued by the IdP for Rancher, recently, and names a user.
+func validateLogoutRequest(logoutRequest *saml.LogoutRequest, issuer, destination string, now time.Time) error {
+	if logoutRequest.Issuer == nil || logoutRequest.Issuer.Value != issuer {
+		return fmt.Errorf("logout request isn't issued by %s", issuer)
+	}
+	if logoutRequest.Destination != "" && logoutRequest.Destination != destination {
+		return fmt.Errorf("logout request isn't intended for %s", destination)
+	}
+	if now.Add(saml.MaxClockSkew).Before(logoutRequest.IssueInstant) || logoutRequest.IssueInstant.Add(saml.MaxIssueDelay).Before(now) {
+		return fmt.Errorf("logout request was issued at %s", logoutRequest.IssueInstant)
+	}
+	if logoutRequest.NotOnOrAfter != nil && !now.Add(-saml.MaxClockSkew).Before(*logoutRequest.NotOnOrAfter) {
+		return fmt.Errorf("logout request has expired")
+	}
+	if logoutRequest.NameID == nil || logoutRequest.NameID.Value == "" {
+		return fmt.Errorf("logout request has no name ID")
+	}
+	return nil
+}
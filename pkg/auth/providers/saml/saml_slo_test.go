@@ -0,0 +1,264 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	testIdPEntityID = "https://idp.example.com/metadata"
+	testSloURL      = "https://rancher.example.com/v1-saml/okta/saml/slo"
+)
+
+type fakeLogouts struct {
+	users []string
+}
+
+func (f *fakeLogouts) LogoutEverywhere(userID, reason string) (int, error) {
+	f.users = append(f.users, userID)
+	return 1, nil
+}
+
+func logoutRequestXML(issueInstant time.Time, nameID string) string {
+	return fmt.Sprintf(`<samlp:LogoutRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_logout1" Version="2.0" IssueInstant="%s" Destination="%s">`+
+		`<saml:Issuer>%s</saml:Issuer><saml:NameID>%s</saml:NameID></samlp:LogoutRequest>`,
+		issueInstant.UTC().Format(time.RFC3339), testSloURL, testIdPEntityID, nameID)
+}
+
+// postLogoutRequest returns the request of the HTTP-POST binding, with the logout request signed in the XML.
+func postLogoutRequest(t *testing.T, cert tls.Certificate, logoutRequest string) *http.Request {
+	t.Helper()
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromString(logoutRequest))
+	signed, err := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(cert)).SignEnveloped(doc.Root())
+	require.NoError(t, err)
+	doc.SetRoot(signed)
+	raw, err := doc.WriteToBytes()
+	require.NoError(t, err)
+
+	form := url.Values{"SAMLRequest": {base64.StdEncoding.EncodeToString(raw)}}
+	r := httptest.NewRequest(http.MethodPost, testSloURL, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	require.NoError(t, r.ParseForm())
+	return r
+}
+
+// redirectLogoutRequest returns the request of the HTTP-Redirect binding, with the logout request signed in the query.
+func redirectLogoutRequest(t *testing.T, cert tls.Certificate, logoutRequest, relayState string) *http.Request {
+	t.Helper()
+	var compressed bytes.Buffer
+	w, err := flate.NewWriter(&compressed, flate.BestCompression)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(logoutRequest))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	query := "SAMLRequest=" + url.QueryEscape(base64.StdEncoding.EncodeToString(compressed.Bytes())) +
+		"&RelayState=" + url.QueryEscape(relayState) +
+		"&SigAlg=" + url.QueryEscape(dsig.RSASHA256SignatureMethod)
+	digest := sha256.Sum256([]byte(query))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, cert.PrivateKey.(*rsa.PrivateKey), crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	query += "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature))
+
+	r := httptest.NewRequest(http.MethodGet, testSloURL+"?"+query, nil)
+	require.NoError(t, r.ParseForm())
+	return r
+}
+
+func TestParseLogoutRequest(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	cert := newTestSigningCert(t)
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	otherCert := newTestSigningCert(t)
+	logoutRequest := logoutRequestXML(now, "jdoe@example.com")
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		wantErr string
+	}{
+		{
+			name:    "POST binding",
+			request: postLogoutRequest(t, cert, logoutRequest),
+		},
+		{
+			name:    "Redirect binding",
+			request: redirectLogoutRequest(t, cert, logoutRequest, "state"),
+		},
+		{
+			name:    "POST binding signed by another certificate",
+			request: postLogoutRequest(t, otherCert, logoutRequest),
+			wantErr: "cannot validate the signature",
+		},
+		{
+			name:    "Redirect binding signed by another certificate",
+			request: redirectLogoutRequest(t, otherCert, logoutRequest, "state"),
+			wantErr: "cannot validate the signature",
+		},
+		{
+			name: "Redirect binding with a tampered relay state",
+			request: func() *http.Request {
+				r := redirectLogoutRequest(t, cert, logoutRequest, "state")
+				r.URL.RawQuery = strings.Replace(r.URL.RawQuery, "RelayState=state", "RelayState=other", 1)
+				return r
+			}(),
+			wantErr: "cannot validate the signature",
+		},
+		{
+			name: "Redirect binding without signature",
+			request: func() *http.Request {
+				r := redirectLogoutRequest(t, cert, logoutRequest, "state")
+				r.URL.RawQuery = r.URL.RawQuery[:strings.Index(r.URL.RawQuery, "&Signature=")]
+				return r
+			}(),
+			wantErr: "isn't signed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseLogoutRequest(tt.request, []*x509.Certificate{x509Cert}, now)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "_logout1", parsed.ID)
+			assert.Equal(t, "jdoe@example.com", parsed.NameID.Value)
+			assert.NoError(t, validateLogoutRequest(parsed, testIdPEntityID, testSloURL, now))
+		})
+	}
+}
+
+func TestValidateLogoutRequest(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-saml.MaxClockSkew - time.Minute)
+	valid := func() *saml.LogoutRequest {
+		return &saml.LogoutRequest{
+			IssueInstant: now,
+			Destination:  testSloURL,
+			Issuer:       &saml.Issuer{Value: testIdPEntityID},
+			NameID:       &saml.NameID{Value: "jdoe"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(r *saml.LogoutRequest)
+		wantErr string
+	}{
+		{name: "valid", modify: func(r *saml.LogoutRequest) {}},
+		{name: "other issuer", modify: func(r *saml.LogoutRequest) { r.Issuer.Value = "https://evil.example.com" }, wantErr: "isn't issued by"},
+		{name: "other destination", modify: func(r *saml.LogoutRequest) { r.Destination = "https://other.example.com/slo" }, wantErr: "isn't intended for"},
+		{name: "old", modify: func(r *saml.LogoutRequest) { r.IssueInstant = now.Add(-time.Hour) }, wantErr: "was issued at"},
+		{name: "expired", modify: func(r *saml.LogoutRequest) { r.NotOnOrAfter = &expired }, wantErr: "has expired"},
+		{name: "no name ID", modify: func(r *saml.LogoutRequest) { r.NameID = nil }, wantErr: "has no name ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(r)
+			err := validateLogoutRequest(r, testIdPEntityID, testSloURL, now)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHandleSamlLogoutRequest(t *testing.T) {
+	cert := newTestSigningCert(t)
+	sloURL, err := url.Parse(testSloURL)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	userAttributes := fake.NewMockNonNamespacedCacheInterface[*v32.UserAttribute](ctrl)
+	userAttributes.EXPECT().List(labels.Everything()).Return([]*v32.UserAttribute{
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "u-jdoe"},
+			ExtraByProvider: map[string]map[string][]string{OKTAName: {"username": {"jdoe@example.com"}}},
+		},
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "u-other"},
+			ExtraByProvider: map[string]map[string][]string{OKTAName: {"username": {"other@example.com"}}},
+		},
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "u-ping"},
+			ExtraByProvider: map[string]map[string][]string{PingName: {"username": {"jdoe@example.com"}}},
+		},
+	}, nil)
+
+	logouts := &fakeLogouts{}
+	p := &Provider{
+		name:           OKTAName,
+		userAttributes: userAttributes,
+		logouts:        logouts,
+		serviceProvider: &saml.ServiceProvider{
+			EntityID: "https://rancher.example.com/v1-saml/okta/saml/metadata",
+			SloURL:   *sloURL,
+			IDPMetadata: &saml.EntityDescriptor{
+				EntityID: testIdPEntityID,
+				IDPSSODescriptors: []saml.IDPSSODescriptor{{
+					SSODescriptor: saml.SSODescriptor{
+						RoleDescriptor: saml.RoleDescriptor{
+							KeyDescriptors: []saml.KeyDescriptor{{
+								Use: "signing",
+								KeyInfo: saml.KeyInfo{X509Data: saml.X509Data{X509Certificates: []saml.X509Certificate{{
+									Data: base64.StdEncoding.EncodeToString(cert.Certificate[0]),
+								}}}},
+							}},
+						},
+						SingleLogoutServices: []saml.Endpoint{{Binding: saml.HTTPRedirectBinding, Location: "https://idp.example.com/slo"}},
+					},
+				}},
+			},
+		},
+	}
+
+	r := redirectLogoutRequest(t, cert, logoutRequestXML(time.Now(), "jdoe@example.com"), "state")
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, r)
+
+	require.Equal(t, http.StatusFound, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"u-jdoe"}, logouts.users)
+	location, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "idp.example.com", location.Host)
+	assert.Equal(t, "state", location.Query().Get("RelayState"))
+	assert.NotEmpty(t, location.Query().Get("SAMLResponse"))
+
+	// Logout requests which aren't signed by the IdP are rejected.
+	r = redirectLogoutRequest(t, newTestSigningCert(t), logoutRequestXML(time.Now(), "jdoe@example.com"), "state")
+	rec = httptest.NewRecorder()
+	p.ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []string{"u-jdoe"}, logouts.users)
+}
@@ -0,0 +1,103 @@
+package saml
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/rancher/norman/objectclient"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// attributeOIDs maps the OIDs of the eduPerson attributes used for authorization to their names,
+// so mappings can refer to the attributes by either, whatever the IdP releases.
+var attributeOIDs = map[string]string{
+	"urn:oid:1.3.6.1.4.1.5923.1.1.1.7": "eduPersonEntitlement",
+	"urn:oid:1.3.6.1.4.1.5923.1.1.1.9": "eduPersonScopedAffiliation",
+}
+
+// getShibbolethEntitlementMappings returns the entitlement mappings of the stored Shibboleth config.
+func (s *Provider) getShibbolethEntitlementMappings(genericClient objectclient.GenericClient) ([]v32.ShibbolethEntitlementMapping, error) {
+	authConfigObj, err := genericClient.Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("SAML: failed to retrieve ShibbolethConfig, error: %w", err)
+	}
+	u, ok := authConfigObj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("SAML: failed to retrieve ShibbolethConfig, cannot read k8s Unstructured data")
+	}
+
+	mappings, ok := u.UnstructuredContent()[client.ShibbolethConfigFieldEntitlementMappings].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var config v32.ShibbolethConfig
+	if err := common.Decode(map[string]interface{}{client.ShibbolethConfigFieldEntitlementMappings: mappings}, &config); err != nil {
+		return nil, fmt.Errorf("unable to decode Shibboleth entitlement mappings: %w", err)
+	}
+	return config.EntitlementMappings, nil
+}
+
+// entitlementGroupPrincipals returns the group principals the values of the attributes of the assertion map to.
+// Groups already in groupPrincipals aren't returned again.
+func (s *Provider) entitlementGroupPrincipals(mappings []v32.ShibbolethEntitlementMapping, samlData map[string][]string, groupPrincipals []v3.Principal) ([]v3.Principal, error) {
+	seen := make(map[string]bool, len(groupPrincipals))
+	for _, group := range groupPrincipals {
+		seen[group.Name] = true
+	}
+
+	var principals []v3.Principal
+	for _, mapping := range mappings {
+		pattern, err := regexp.Compile("^(?:" + mapping.Pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q for attribute %s: %w", mapping.Pattern, mapping.Attribute, err)
+		}
+
+		for _, value := range attributeValues(samlData, mapping.Attribute) {
+			submatches := pattern.FindStringSubmatchIndex(value)
+			if submatches == nil {
+				continue
+			}
+			groupName := value
+			if mapping.Group != "" {
+				groupName = string(pattern.ExpandString(nil, mapping.Group, value, submatches))
+			}
+			if groupName == "" {
+				continue
+			}
+
+			principal := v3.Principal{
+				ObjectMeta:    metav1.ObjectMeta{Name: s.groupType + "://" + groupName},
+				DisplayName:   groupName,
+				Provider:      s.name,
+				PrincipalType: "group",
+				MemberOf:      true,
+			}
+			if !seen[principal.Name] {
+				seen[principal.Name] = true
+				principals = append(principals, principal)
+			}
+		}
+	}
+	return principals, nil
+}
+
+// attributeValues returns the values of the attribute, released either by name or by OID.
+func attributeValues(samlData map[string][]string, attribute string) []string {
+	if values, ok := samlData[attribute]; ok {
+		return values
+	}
+	if name, ok := attributeOIDs[attribute]; ok {
+		return samlData[name]
+	}
+	for oid, name := range attributeOIDs {
+		if name == attribute {
+			return samlData[oid]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package saml
+
+import (
+	"testing"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEntitlementGroupPrincipals(t *testing.T) {
+	provider := &Provider{name: ShibbolethName, groupType: ShibbolethName + "_group"}
+	samlData := map[string][]string{
+		"eduPersonEntitlement": {
+			"urn:mace:example.org:rancher:admins",
+			"urn:mace:example.org:wiki:editors",
+		},
+		"urn:oid:1.3.6.1.4.1.5923.1.1.1.9": {"staff@example.org", "member@example.org", "student@other.org"},
+	}
+
+	tests := []struct {
+		name     string
+		mappings []v32.ShibbolethEntitlementMapping
+		existing []v3.Principal
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "entitlement submatch",
+			mappings: []v32.ShibbolethEntitlementMapping{
+				{Attribute: "eduPersonEntitlement", Pattern: `urn:mace:example\.org:rancher:(.+)`, Group: "rancher-$1"},
+			},
+			want: []string{"shibboleth_group://rancher-admins"},
+		},
+		{
+			name: "affiliation released by OID",
+			mappings: []v32.ShibbolethEntitlementMapping{
+				{Attribute: "eduPersonScopedAffiliation", Pattern: `(staff|faculty)@example\.org`},
+			},
+			want: []string{"shibboleth_group://staff@example.org"},
+		},
+		{
+			name: "pattern must match the whole value",
+			mappings: []v32.ShibbolethEntitlementMapping{
+				{Attribute: "eduPersonScopedAffiliation", Pattern: `staff`},
+			},
+		},
+		{
+			name: "groups are deduplicated",
+			mappings: []v32.ShibbolethEntitlementMapping{
+				{Attribute: "eduPersonScopedAffiliation", Pattern: `.+@example\.org`, Group: "example"},
+				{Attribute: "eduPersonEntitlement", Pattern: `.*:wiki:.*`, Group: "wiki"},
+			},
+			existing: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "shibboleth_group://wiki"}}},
+			want:     []string{"shibboleth_group://example"},
+		},
+		{
+			name: "invalid pattern",
+			mappings: []v32.ShibbolethEntitlementMapping{
+				{Attribute: "eduPersonEntitlement", Pattern: `(`},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principals, err := provider.entitlementGroupPrincipals(tt.mappings, samlData, tt.existing)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, principal := range principals {
+				assert.Equal(t, "group", principal.PrincipalType)
+				assert.True(t, principal.MemberOf)
+				names = append(names, principal.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	"github.com/golang-jwt/jwt"
+	"github.com/rancher/norman/objectclient"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	dsig "github.com/russellhaering/goxmldsig"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	wsFedSignIn  = "wsignin1.0"
+	wsFedSignOut = "wsignout1.0"
+
+	saml1AssertionNamespace = "urn:oasis:names:tc:SAML:1.0:assertion"
+	saml2AssertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+)
+
+// getWSFedConfig returns the stored WS-Federation configuration of the ADFS provider.
+func (s *Provider) getWSFedConfig(genericClient objectclient.GenericClient) (*v32.WSFedFields, error) {
+	authConfigObj, err := genericClient.Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("SAML: failed to retrieve ADFSConfig, error: %w", err)
+	}
+	u, ok := authConfigObj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("SAML: failed to retrieve ADFSConfig, cannot read k8s Unstructured data")
+	}
+
+	config := &v32.WSFedFields{}
+	subConfig, ok := u.UnstructuredContent()[client.ADFSConfigFieldWSFedConfig].(map[string]interface{})
+	if !ok {
+		return config, nil
+	}
+	if err := common.Decode(subConfig, config); err != nil {
+		return nil, fmt.Errorf("unable to decode WS-Federation Config: %w", err)
+	}
+	return config, nil
+}
+
+// wsFedEndpoint returns the WS-Federation endpoint of the IdP, or an empty string if
+// users don't sign in with WS-Federation.
+func (s *Provider) wsFedEndpoint() string {
+	if s.name != ADFSName {
+		return ""
+	}
+	config, err := s.getWSFedConfig(s.authConfigsRaw)
+	if err != nil {
+		log.Errorf("SAML: Error getting WS-Federation config %v", err)
+		return ""
+	}
+	if !config.Enabled {
+		return ""
+	}
+	return config.Endpoint
+}
+
+// wsFedRealm returns the realm identifying Rancher to the IdP, which is the entity ID of the service provider.
+func (s *Provider) wsFedRealm() string {
+	if s.serviceProvider.EntityID != "" {
+		return s.serviceProvider.EntityID
+	}
+	return s.serviceProvider.MetadataURL.String()
+}
+
+// HandleWSFedLogin returns the URL of the WS-Federation sign-in page of the IdP.
+// The context passed to the IdP is integrity protected the same way as the SAML relay state.
+func (s *Provider) HandleWSFedLogin(w http.ResponseWriter, r *http.Request, endpoint, userID string) (string, error) {
+	wctx := base64.URLEncoding.EncodeToString(randomBytes(42))
+
+	secretBlock := x509.MarshalPKCS1PrivateKey(s.serviceProvider.Key)
+	state := jwt.New(jwt.SigningMethodHS256)
+	claims := state.Claims.(jwt.MapClaims)
+	claims["id"] = wctx
+	claims["uri"] = r.URL.String()
+	if userID != "" {
+		claims[rancherUserID] = userID
+	}
+
+	signedState, err := state.SignedString(secretBlock)
+	if err != nil {
+		return "", err
+	}
+
+	s.clientState.SetState(w, r, wctx, signedState)
+
+	redirectURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("SAML: invalid WS-Federation endpoint: %w", err)
+	}
+	query := redirectURL.Query()
+	query.Set("wa", wsFedSignIn)
+	query.Set("wtrealm", s.wsFedRealm())
+	query.Set("wreply", s.wsFedURL.String())
+	query.Set("wctx", wctx)
+	redirectURL.RawQuery = query.Encode()
+
+	return redirectURL.String(), nil
+}
+
+// wsFedSignOutURL returns the URL of the WS-Federation sign-out page of the IdP, which redirects to wreply once done.
+func wsFedSignOutURL(endpoint, wreply string) (string, error) {
+	redirectURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("SAML: invalid WS-Federation endpoint: %w", err)
+	}
+	query := redirectURL.Query()
+	query.Set("wa", wsFedSignOut)
+	if wreply != "" {
+		query.Set("wreply", wreply)
+	}
+	redirectURL.RawQuery = query.Encode()
+	return redirectURL.String(), nil
+}
+
+// HandleWSFedSignIn validates the security token posted by the IdP and logs the user in with its assertion.
+func (s *Provider) HandleWSFedSignIn(w http.ResponseWriter, r *http.Request) {
+	if wa := r.Form.Get("wa"); wa != wsFedSignIn {
+		log.Debugf("SAML [HandleWSFedSignIn]: unexpected action %q", wa)
+		http.Error(w, "unexpected WS-Federation action", http.StatusBadRequest)
+		return
+	}
+
+	wctx := r.Form.Get("wctx")
+	if err := s.validateWSFedContext(r, wctx); err != nil {
+		log.Debugf("SAML [HandleWSFedSignIn]: invalid context: %v", err)
+		http.Redirect(w, r, r.URL.Host+"/login?errorCode=403", http.StatusFound)
+		return
+	}
+
+	certs, err := idpSigningCerts(s.serviceProvider.IDPMetadata)
+	if err != nil {
+		log.Errorf("SAML [HandleWSFedSignIn]: %v", err)
+		http.Redirect(w, r, r.URL.Host+"/login?errorCode=500", http.StatusFound)
+		return
+	}
+
+	assertion, err := parseWSFedResult(r.Form.Get("wresult"), certs, s.wsFedRealm(), saml.TimeNow())
+	if err != nil {
+		log.Debugf("SAML [HandleWSFedSignIn]: token validation failed: %v", err)
+		http.Redirect(w, r, r.URL.Host+"/login?errorCode=403", http.StatusFound)
+		return
+	}
+
+	// The context plays the role of the SAML relay state from now on.
+	r.Form.Set("RelayState", wctx)
+	s.HandleSamlAssertion(w, r, assertion)
+}
+
+// validateWSFedContext checks the context returned by the IdP is the one Rancher signed when redirecting to it.
+func (s *Provider) validateWSFedContext(r *http.Request, wctx string) error {
+	if wctx == "" {
+		return fmt.Errorf("missing wctx")
+	}
+	jwtParser := jwt.Parser{
+		ValidMethods: []string{jwt.SigningMethodHS256.Name},
+	}
+	token, err := jwtParser.Parse(s.clientState.GetState(r, wctx), func(t *jwt.Token) (interface{}, error) {
+		return x509.MarshalPKCS1PrivateKey(s.serviceProvider.Key), nil
+	})
+	if err != nil {
+		return err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["id"] != wctx {
+		return fmt.Errorf("invalid state")
+	}
+	return nil
+}
+
+// idpSigningCerts returns the certificates the IdP signs tokens with, from its metadata.
+func idpSigningCerts(metadata *saml.EntityDescriptor) ([]*x509.Certificate, error) {
+	whitespace := regexp.MustCompile(`\s+`)
+
+	var certs []*x509.Certificate
+	for _, descriptor := range metadata.IDPSSODescriptors {
+		for _, keyDescriptor := range descriptor.KeyDescriptors {
+			if keyDescriptor.Use != "" && keyDescriptor.Use != "signing" {
+				continue
+			}
+			for _, certificate := range keyDescriptor.KeyInfo.X509Data.X509Certificates {
+				certBytes, err := base64.StdEncoding.DecodeString(whitespace.ReplaceAllString(certificate.Data, ""))
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse IdP signing certificate: %w", err)
+				}
+				cert, err := x509.ParseCertificate(certBytes)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse IdP signing certificate: %w", err)
+				}
+				certs = append(certs, cert)
+			}
+		}
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("cannot find any signing certificate in the IdP metadata")
+	}
+	return certs, nil
+}
+
+// parseWSFedResult extracts the assertion from the RequestSecurityTokenResponse posted by the IdP.
+// The assertion must be signed by one of the certificates, be valid at the given time and be intended for the realm.
+// SAML 1.1 assertions, which ADFS issues by default, are converted to SAML 2.0.
+func parseWSFedResult(wresult string, certs []*x509.Certificate, realm string, now time.Time) (*saml.Assertion, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(wresult); err != nil {
+		return nil, fmt.Errorf("cannot parse wresult: %w", err)
+	}
+	token := doc.FindElement("//RequestedSecurityToken")
+	if token == nil || len(token.ChildElements()) != 1 {
+		return nil, fmt.Errorf("cannot find the requested security token")
+	}
+	assertionEl := token.ChildElements()[0]
+	if assertionEl.Tag != "Assertion" {
+		return nil, fmt.Errorf("unsupported security token %s", assertionEl.Tag)
+	}
+
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certs})
+	validationContext.Clock = dsig.NewFakeClockAt(now)
+	namespace := assertionEl.NamespaceURI()
+	if namespace == saml1AssertionNamespace {
+		validationContext.IdAttribute = "AssertionID"
+	}
+	validated, err := validationContext.Validate(assertionEl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot validate the signature of the assertion: %w", err)
+	}
+
+	validatedDoc := etree.NewDocument()
+	validatedDoc.SetRoot(validated.Copy())
+	data, err := validatedDoc.WriteToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var assertion *saml.Assertion
+	switch namespace {
+	case saml2AssertionNamespace:
+		assertion = &saml.Assertion{}
+		if err := xml.Unmarshal(data, assertion); err != nil {
+			return nil, fmt.Errorf("cannot parse SAML 2.0 assertion: %w", err)
+		}
+	case saml1AssertionNamespace:
+		saml1 := &saml1Assertion{}
+		if err := xml.Unmarshal(data, saml1); err != nil {
+			return nil, fmt.Errorf("cannot parse SAML 1.1 assertion: %w", err)
+		}
+		assertion = saml1.toSAML2()
+	default:
+		return nil, fmt.Errorf("unsupported assertion namespace %s", namespace)
+	}
+
+	if err := validateConditions(assertion.Conditions, realm, now); err != nil {
+		return nil, err
+	}
+	return assertion, nil
+}
+
+// validateConditions checks the assertion is valid at the given time and intended for the realm.
+func validateConditions(conditions *saml.Conditions, realm string, now time.Time) error {
+	if conditions == nil {
+		return fmt.Errorf("assertion has no conditions")
+	}
+	if !conditions.NotBefore.IsZero() && now.Add(saml.MaxClockSkew).Before(conditions.NotBefore) {
+		return fmt.Errorf("assertion is not yet valid")
+	}
+	if !conditions.NotOnOrAfter.IsZero() && !now.Add(-saml.MaxClockSkew).Before(conditions.NotOnOrAfter) {
+		return fmt.Errorf("assertion has expired")
+	}
+	for _, restriction := range conditions.AudienceRestrictions {
+		if strings.TrimSpace(restriction.Audience.Value) == realm {
+			return nil
+		}
+	}
+	return fmt.Errorf("assertion isn't intended for realm %s", realm)
+}
+
+// saml1Assertion holds the parts of a SAML 1.1 assertion Rancher uses.
+type saml1Assertion struct {
+	XMLName    xml.Name `xml:"urn:oasis:names:tc:SAML:1.0:assertion Assertion"`
+	Conditions struct {
+		NotBefore    time.Time `xml:",attr"`
+		NotOnOrAfter time.Time `xml:",attr"`
+		Audiences    []string  `xml:"AudienceRestrictionCondition>Audience"`
+	} `xml:"Conditions"`
+	AttributeStatements []struct {
+		Attributes []struct {
+			AttributeName      string   `xml:",attr"`
+			AttributeNamespace string   `xml:",attr"`
+			Values             []string `xml:"AttributeValue"`
+		} `xml:"Attribute"`
+	} `xml:"AttributeStatement"`
+}
+
+// toSAML2 converts the assertion to SAML 2.0. Attribute names are the claim types, e.g.
+// http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn, the same as ADFS releases in SAML 2.0 assertions.
+func (a *saml1Assertion) toSAML2() *saml.Assertion {
+	assertion := &saml.Assertion{
+		Conditions: &saml.Conditions{
+			NotBefore:    a.Conditions.NotBefore,
+			NotOnOrAfter: a.Conditions.NotOnOrAfter,
+		},
+	}
+	for _, audience := range a.Conditions.Audiences {
+		assertion.Conditions.AudienceRestrictions = append(assertion.Conditions.AudienceRestrictions,
+			saml.AudienceRestriction{Audience: saml.Audience{Value: audience}})
+	}
+	for _, statement := range a.AttributeStatements {
+		var attributeStatement saml.AttributeStatement
+		for _, attribute := range statement.Attributes {
+			name := attribute.AttributeName
+			if attribute.AttributeNamespace != "" {
+				name = strings.TrimSuffix(attribute.AttributeNamespace, "/") + "/" + attribute.AttributeName
+			}
+			samlAttribute := saml.Attribute{Name: name}
+			for _, value := range attribute.Values {
+				samlAttribute.Values = append(samlAttribute.Values, saml.AttributeValue{Value: value})
+			}
+			attributeStatement.Attributes = append(attributeStatement.Attributes, samlAttribute)
+		}
+		assertion.AttributeStatements = append(assertion.AttributeStatements, attributeStatement)
+	}
+	return assertion
+}
@@ -0,0 +1,168 @@
+package saml
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRealm = "https://rancher.example.com/v1-saml/adfs/saml/metadata"
+
+	saml1Token = `<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:1.0:assertion" MajorVersion="1" MinorVersion="1" AssertionID="_abcde" Issuer="http://adfs.example.com/adfs/services/trust" IssueInstant="2024-01-01T10:00:00Z">` +
+		`<saml:Conditions NotBefore="2024-01-01T10:00:00Z" NotOnOrAfter="2024-01-01T11:00:00Z"><saml:AudienceRestrictionCondition><saml:Audience>%s</saml:Audience></saml:AudienceRestrictionCondition></saml:Conditions>` +
+		`<saml:AttributeStatement><saml:Subject><saml:NameIdentifier>jdoe</saml:NameIdentifier></saml:Subject>` +
+		`<saml:Attribute AttributeName="upn" AttributeNamespace="http://schemas.xmlsoap.org/ws/2005/05/identity/claims"><saml:AttributeValue>jdoe@example.com</saml:AttributeValue></saml:Attribute>` +
+		`<saml:Attribute AttributeName="Group" AttributeNamespace="http://schemas.xmlsoap.org/claims"><saml:AttributeValue>admins</saml:AttributeValue><saml:AttributeValue>devs</saml:AttributeValue></saml:Attribute>` +
+		`</saml:AttributeStatement></saml:Assertion>`
+
+	saml2Token = `<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_fghij" Version="2.0" IssueInstant="2024-01-01T10:00:00Z">` +
+		`<saml:Issuer>http://adfs.example.com/adfs/services/trust</saml:Issuer>` +
+		`<saml:Conditions NotBefore="2024-01-01T10:00:00Z" NotOnOrAfter="2024-01-01T11:00:00Z"><saml:AudienceRestriction><saml:Audience>%s</saml:Audience></saml:AudienceRestriction></saml:Conditions>` +
+		`<saml:AttributeStatement><saml:Attribute Name="http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn"><saml:AttributeValue>jdoe@example.com</saml:AttributeValue></saml:Attribute></saml:AttributeStatement>` +
+		`</saml:Assertion>`
+)
+
+func newTestSigningCert(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ADFS Signing"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// signedWResult signs the assertion with the certificate and wraps it in a RequestSecurityTokenResponse.
+func signedWResult(t *testing.T, cert tls.Certificate, assertion, idAttribute string) string {
+	t.Helper()
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromString(assertion))
+
+	signingContext := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(cert))
+	signingContext.IdAttribute = idAttribute
+	signed, err := signingContext.SignEnveloped(doc.Root())
+	require.NoError(t, err)
+
+	rstr := etree.NewDocument()
+	response := rstr.CreateElement("t:RequestSecurityTokenResponse")
+	response.CreateAttr("xmlns:t", "http://schemas.xmlsoap.org/ws/2005/02/trust")
+	response.CreateElement("t:RequestedSecurityToken").AddChild(signed)
+	wresult, err := rstr.WriteToString()
+	require.NoError(t, err)
+	return wresult
+}
+
+func TestParseWSFedResult(t *testing.T) {
+	cert := newTestSigningCert(t)
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	otherCert := newTestSigningCert(t)
+	now := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		wresult   string
+		now       time.Time
+		wantAttrs map[string][]string
+		wantErr   string
+	}{
+		{
+			name:    "SAML 1.1 token",
+			wresult: signedWResult(t, cert, fmt.Sprintf(saml1Token, testRealm), "AssertionID"),
+			now:     now,
+			wantAttrs: map[string][]string{
+				"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn": {"jdoe@example.com"},
+				"http://schemas.xmlsoap.org/claims/Group":                   {"admins", "devs"},
+			},
+		},
+		{
+			name:    "SAML 2.0 token",
+			wresult: signedWResult(t, cert, fmt.Sprintf(saml2Token, testRealm), "ID"),
+			now:     now,
+			wantAttrs: map[string][]string{
+				"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn": {"jdoe@example.com"},
+			},
+		},
+		{
+			name:    "other realm",
+			wresult: signedWResult(t, cert, fmt.Sprintf(saml1Token, "https://other.example.com"), "AssertionID"),
+			now:     now,
+			wantErr: "isn't intended for realm",
+		},
+		{
+			name:    "expired token",
+			wresult: signedWResult(t, cert, fmt.Sprintf(saml1Token, testRealm), "AssertionID"),
+			now:     now.Add(2 * time.Hour),
+			wantErr: "expired",
+		},
+		{
+			name:    "untrusted signer",
+			wresult: signedWResult(t, otherCert, fmt.Sprintf(saml1Token, testRealm), "AssertionID"),
+			now:     now,
+			wantErr: "cannot validate the signature",
+		},
+		{
+			name:    "unsigned token",
+			wresult: `<t:RequestSecurityTokenResponse xmlns:t="http://schemas.xmlsoap.org/ws/2005/02/trust"><t:RequestedSecurityToken>` + fmt.Sprintf(saml1Token, testRealm) + `</t:RequestedSecurityToken></t:RequestSecurityTokenResponse>`,
+			now:     now,
+			wantErr: "cannot validate the signature",
+		},
+		{
+			name:    "missing token",
+			wresult: `<t:RequestSecurityTokenResponse xmlns:t="http://schemas.xmlsoap.org/ws/2005/02/trust"/>`,
+			now:     now,
+			wantErr: "cannot find the requested security token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertion, err := parseWSFedResult(tt.wresult, []*x509.Certificate{x509Cert}, testRealm, tt.now)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			attrs := map[string][]string{}
+			for _, statement := range assertion.AttributeStatements {
+				for _, attribute := range statement.Attributes {
+					for _, value := range attribute.Values {
+						attrs[attribute.Name] = append(attrs[attribute.Name], value.Value)
+					}
+				}
+			}
+			assert.Equal(t, tt.wantAttrs, attrs)
+		})
+	}
+}
+
+func TestWSFedSignOutURL(t *testing.T) {
+	signOutURL, err := wsFedSignOutURL("https://adfs.example.com/adfs/ls/", "https://rancher.example.com/dashboard/auth/logout")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(signOutURL)
+	require.NoError(t, err)
+	assert.Equal(t, "adfs.example.com", parsed.Host)
+	assert.Equal(t, "/adfs/ls/", parsed.Path)
+	assert.Equal(t, wsFedSignOut, parsed.Query().Get("wa"))
+	assert.Equal(t, "https://rancher.example.com/dashboard/auth/logout", parsed.Query().Get("wreply"))
+}
@@ -5,6 +5,7 @@
 
 	"github.com/rancher/norman/store/subtype"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/rancher/pkg/auth/api/revisions"
 	"github.com/rancher/rancher/pkg/auth/api/secrets"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	managementschema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
@@ -27,16 +28,26 @@
 	client.OIDCConfigType,
 	client.KeyCloakOIDCConfigType,
 	client.GenericOIDCConfigType,
+	client.ExternalAuthConfigType,
+	client.KerberosConfigType,
+	client.ClientCertConfigType,
+	client.CASConfigType,
 }
 
 func SetupAuthConfig(ctx context.Context, management *config.ScaledContext, schemas *types.Schemas) {
 	Configure(ctx, management)
 
+	history := revisions.NewHistory(management.Wrangler.Core.ConfigMap(),
+		management.Management.AuthConfigs("").ObjectClient().UnstructuredClient())
+
 	authConfigBaseSchema := schemas.Schema(&managementschema.Version, client.AuthConfigType)
-	authConfigBaseSchema.Store = secrets.Wrap(authConfigBaseSchema.Store, management.Wrangler.Core.Secret())
+	authConfigBaseSchema.Store = history.Wrap(secrets.Wrap(authConfigBaseSchema.Store, management.Wrangler.Core.Secret()))
 	for _, authConfigSubtype := range authConfigTypes {
 		subSchema := schemas.Schema(&managementschema.Version, authConfigSubtype)
 		GetProviderByType(authConfigSubtype).CustomizeSchema(subSchema)
+		if authConfigSubtype != client.LocalConfigType {
+			history.CustomizeSchema(subSchema)
+		}
 		subSchema.Store = subtype.NewSubTypeStore(authConfigSubtype, authConfigBaseSchema.Store)
 	}
 }
@@ -0,0 +1,368 @@
+package providerslo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	evaluationInterval = time.Minute
+	// minRequests is how many requests the window must have for its compliance to tell anything.
+	minRequests = 10
+	// maxStatusAge is how long the SLO status of an auth config is kept while its compliance only changes in numbers.
+	// Every update of an auth config refreshes all of its users, so it's only updated when the state of the SLO
+	// changes or its status gets this old.
+	maxStatusAge   = time.Hour
+	webhookTimeout = 10 * time.Second
+)
+
+// The states of the SLO alerts.
+const (
+	StateBreached  = "breached"
+	StateRecovered = "recovered"
+)
+
+// Alert is posted to the auth-provider-slo-webhook-url when the SLO of an auth provider is breached or recovers.
+type Alert struct {
+	// Provider is the name of the auth provider.
+	Provider string `json:"provider"`
+	// State is StateBreached or StateRecovered.
+	State string    `json:"state"`
+	Time  time.Time `json:"time"`
+	// Compliance is the percentage of the requests of the window which met the objective, empty if there were too
+	// few requests to tell.
+	Compliance    string  `json:"compliance,omitempty"`
+	Target        float64 `json:"target"`
+	LatencyMs     int64   `json:"latencyMs"`
+	WindowMinutes int64   `json:"windowMinutes"`
+	// Since is when the compliance fell below the target.
+	Since string `json:"since,omitempty"`
+}
+
+type authConfigsClient interface {
+	Get(name string, opts metav1.GetOptions) (runtime.Object, error)
+	Update(name string, o runtime.Object) (runtime.Object, error)
+}
+
+// Evaluator evaluates the compliance of the enabled auth providers with their SLO.
+type Evaluator struct {
+	authConfigs      authConfigsClient
+	configMaps       wcorev1.ConfigMapClient
+	enabledProviders func() []string
+	httpClient       *http.Client
+	now              func() time.Time
+	async            func(func())
+}
+
+// StartEvaluation evaluates the SLOs of the providers enabledProviders returns until the context is done. It must run
+// on the leader only.
+func StartEvaluation(ctx context.Context, mgmt *config.ManagementContext, enabledProviders func() []string) {
+	e := &Evaluator{
+		authConfigs:      mgmt.Management.AuthConfigs("").ObjectClient().UnstructuredClient(),
+		configMaps:       mgmt.Wrangler.Core.ConfigMap(),
+		enabledProviders: enabledProviders,
+		httpClient:       &http.Client{Timeout: webhookTimeout},
+		now:              time.Now,
+		async:            func(f func()) { go f() },
+	}
+	go wait.UntilWithContext(ctx, e.Run, evaluationInterval)
+}
+
+// Run evaluates the compliance of each enabled auth provider, updates the status of its auth config, and alerts of the
+// breaches and recoveries of its SLO.
+func (e *Evaluator) Run(_ context.Context) {
+	o, err := currentObjective()
+	if err != nil {
+		logrus.Errorf("[providerslo] %v", err)
+		return
+	}
+	if o.target <= 0 {
+		return
+	}
+	configMap, err := e.configMaps.Get(namespace.System, ConfigMapName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		logrus.Errorf("[providerslo] failed to get config map %s: %v", ConfigMapName, err)
+		return
+	}
+
+	now := e.now()
+	for _, name := range e.enabledProviders() {
+		if name == localProvider {
+			continue
+		}
+		var data string
+		if configMap != nil {
+			data = configMap.Data[name]
+		}
+		counts := summarize(decodeBuckets(name, data), now, o.window)
+		alert, err := e.update(name, counts, o, now)
+		if err != nil {
+			logrus.Errorf("[providerslo] failed to update the SLO status of auth config %s: %v", name, err)
+			continue
+		}
+		if alert != nil {
+			e.alert(alert)
+		}
+	}
+}
+
+// summarize sums the requests of the buckets within the window.
+func summarize(buckets []*bucket, now time.Time, window time.Duration) bucket {
+	var counts bucket
+	cutoff := now.Add(-window).Unix()
+	for _, b := range buckets {
+		if b.Minute >= cutoff {
+			counts.add(b)
+		}
+	}
+	return counts
+}
+
+// update evaluates the SLO of the auth config, and updates its status. It returns the alert to send, if any, once the
+// status is stored, so that the alerts aren't sent again.
+func (e *Evaluator) update(name string, counts bucket, o objective, now time.Time) (*Alert, error) {
+	var alert *Alert
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		alert = nil
+		obj, err := e.authConfigs.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		// The auth config is updated unstructured, since the AuthConfig type lacks the fields of the provider.
+		u, ok := obj.(runtime.Unstructured)
+		if !ok {
+			return fmt.Errorf("failed to read unstructured data for AuthConfig %s", name)
+		}
+		content := u.UnstructuredContent()
+
+		authConfig := &v3.AuthConfig{}
+		if err := convert(content["status"], &authConfig.Status); err != nil {
+			return fmt.Errorf("failed to decode the status: %w", err)
+		}
+		previous := authConfig.Status.DeepCopy()
+
+		slo, state := evaluate(previous.SLO, counts, o, now)
+		authConfig.Status.SLO = slo
+		setCondition(authConfig, slo, o)
+		if state != "" {
+			since := slo.BelowTargetSince
+			if state == StateRecovered && previous.SLO != nil {
+				since = previous.SLO.BelowTargetSince
+			}
+			alert = &Alert{
+				Provider:      name,
+				State:         state,
+				Time:          now,
+				Compliance:    slo.Compliance,
+				Target:        o.target,
+				LatencyMs:     o.latency.Milliseconds(),
+				WindowMinutes: int64(o.window / time.Minute),
+				Since:         since,
+			}
+		}
+
+		if !changed(previous, &authConfig.Status) && !stale(previous.SLO, now) {
+			return nil
+		}
+
+		var status map[string]any
+		if err := convert(authConfig.Status, &status); err != nil {
+			return fmt.Errorf("failed to encode the status: %w", err)
+		}
+		content["status"] = status
+		u.SetUnstructuredContent(content)
+		_, err = e.authConfigs.Update(name, u)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// evaluate returns the SLO status of the counts of the window, and StateBreached or StateRecovered if the SLO was
+// breached or recovered, given its previous status.
+func evaluate(previous *v3.AuthConfigSLO, counts bucket, o objective, now time.Time) (*v3.AuthConfigSLO, string) {
+	slo := &v3.AuthConfigSLO{
+		LastEvaluationTime: now.UTC().Format(time.RFC3339),
+		Requests:           counts.Requests,
+		Errors:             counts.Errors,
+		Slow:               counts.Slow,
+	}
+	if previous != nil {
+		slo.BelowTargetSince = previous.BelowTargetSince
+		slo.BreachedSince = previous.BreachedSince
+	}
+
+	// The windows with too few requests meet the objective, as they can't tell.
+	met := true
+	if counts.Requests >= minRequests {
+		compliance := 100 * float64(counts.Requests-counts.Errors-counts.Slow) / float64(counts.Requests)
+		slo.Compliance = strconv.FormatFloat(compliance, 'f', 2, 64)
+		met = compliance >= o.target
+	}
+
+	if met {
+		slo.BelowTargetSince = ""
+		if slo.BreachedSince != "" {
+			slo.BreachedSince = ""
+			return slo, StateRecovered
+		}
+		return slo, ""
+	}
+
+	since, err := time.Parse(time.RFC3339, slo.BelowTargetSince)
+	if err != nil {
+		since = now
+		slo.BelowTargetSince = now.UTC().Format(time.RFC3339)
+	}
+	if slo.BreachedSince == "" && now.Sub(since) >= o.breach {
+		slo.BreachedSince = now.UTC().Format(time.RFC3339)
+		return slo, StateBreached
+	}
+	return slo, ""
+}
+
+// setCondition sets the SLOMet condition of the auth config from its SLO status. Its message has no numbers, so that
+// it only changes with the state of the SLO.
+func setCondition(authConfig *v3.AuthConfig, slo *v3.AuthConfigSLO, o objective) {
+	cond := v3.AuthConfigConditionSLOMet
+	message := fmt.Sprintf("less than %s%% of the requests succeeded within %s over %s since %s",
+		strconv.FormatFloat(o.target, 'f', -1, 64), o.latency, o.window, slo.BelowTargetSince)
+	switch {
+	case slo.BreachedSince != "":
+		cond.False(authConfig)
+		cond.Reason(authConfig, "Breached")
+		cond.Message(authConfig, message)
+	case slo.BelowTargetSince != "":
+		cond.True(authConfig)
+		cond.Reason(authConfig, "BelowTarget")
+		cond.Message(authConfig, message)
+	default:
+		cond.True(authConfig)
+		cond.Reason(authConfig, "")
+		cond.Message(authConfig, "")
+	}
+}
+
+// alert records the alert as an AuthEvent, and posts it to the webhook, if any.
+func (e *Evaluator) alert(alert *Alert) {
+	eventType := authevents.ProviderSLOBreached
+	detail := fmt.Sprintf("%s%% of the requests met the objective, below the target of %s%%, since %s",
+		alert.Compliance, strconv.FormatFloat(alert.Target, 'f', -1, 64), alert.Since)
+	if alert.State == StateRecovered {
+		eventType = authevents.ProviderSLORecovered
+		detail = fmt.Sprintf("the requests meet the objective again, after missing it since %s", alert.Since)
+		if alert.Compliance != "" {
+			detail = fmt.Sprintf("%s%% of the requests meet the objective again, after missing it since %s", alert.Compliance, alert.Since)
+		}
+	}
+	authevents.Record(authevents.Event{
+		Type:     eventType,
+		Provider: alert.Provider,
+		Target:   alert.Provider,
+		Detail:   detail,
+	})
+
+	url := settings.AuthProviderSLOWebhookURL.Get()
+	if url == "" {
+		return
+	}
+	e.async(func() {
+		if err := e.post(url, alert); err != nil {
+			logrus.Errorf("[providerslo] failed to post the %s SLO of auth provider %s to the webhook: %v", alert.State, alert.Provider, err)
+		}
+	})
+}
+
+func (e *Evaluator) post(url string, alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// changed returns whether the state of the SLO changed, ignoring the counts of the requests and when it was evaluated.
+func changed(previous, current *v3.AuthConfigStatus) bool {
+	var before, after map[string]any
+	if err := convert(withoutCounts(previous), &before); err != nil {
+		return true
+	}
+	if err := convert(withoutCounts(current), &after); err != nil {
+		return true
+	}
+	return !reflect.DeepEqual(before, after)
+}
+
+func withoutCounts(status *v3.AuthConfigStatus) *v3.AuthConfigStatus {
+	status = status.DeepCopy()
+	if slo := status.SLO; slo != nil {
+		slo.LastEvaluationTime = ""
+		slo.Requests = 0
+		slo.Errors = 0
+		slo.Slow = 0
+		slo.Compliance = ""
+	}
+	for i := range status.Conditions {
+		status.Conditions[i].LastUpdateTime = ""
+		status.Conditions[i].LastTransitionTime = ""
+	}
+	return status
+}
+
+// stale returns whether the stored SLO status is older than maxStatusAge.
+func stale(slo *v3.AuthConfigSLO, now time.Time) bool {
+	if slo == nil {
+		return true
+	}
+	lastEvaluation, err := time.Parse(time.RFC3339, slo.LastEvaluationTime)
+	if err != nil {
+		return true
+	}
+	return now.Sub(lastEvaluation) >= maxStatusAge
+}
+
+// convert converts between the typed and the unstructured status through their JSON encoding.
+func convert(in, out any) error {
+	if in == nil {
+		return nil
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,274 @@
+// Package providerslo tracks the compliance of the requests to the auth providers with their service level objective,
+// the percentage of the requests which must succeed within a latency over a rolling window, as set by the
+// auth-provider-slo settings. Every replica counts the requests it makes to the providers per minute, and adds the
+// counts to the auth-provider-slo config map. The leader evaluates the compliance of the enabled providers from it,
+// publishes it in the status of their auth configs, and reports the SLOs missed for a sustained period as breached,
+// as AuthEvents and to the auth-provider-slo-webhook-url, so that operators learn of the degradations of the identity
+// providers before the users do.
+package providerslo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// ConfigMapName is the name of the config map of the cattle-system namespace holding the requests of the window,
+	// counted by all the replicas, per provider and minute.
+	ConfigMapName = "auth-provider-slo"
+
+	flushInterval = time.Minute
+	// localProvider isn't an identity service, its requests aren't counted.
+	localProvider = "local"
+)
+
+// bucket is the requests to a provider during a minute.
+type bucket struct {
+	// Minute is the start of the minute, in seconds since the epoch.
+	Minute int64 `json:"minute"`
+	// Requests is how many requests were made.
+	Requests int64 `json:"requests"`
+	// Errors is how many requests failed on the provider's side.
+	Errors int64 `json:"errors,omitempty"`
+	// Slow is how many requests succeeded, but slower than the latency objective.
+	Slow int64 `json:"slow,omitempty"`
+}
+
+func (b *bucket) add(other *bucket) {
+	b.Requests += other.Requests
+	b.Errors += other.Errors
+	b.Slow += other.Slow
+}
+
+// objective is the service level objective of the auth-provider-slo settings.
+type objective struct {
+	// target is the percentage of the requests which must meet the objective, 0 if it's disabled.
+	target  float64
+	latency time.Duration
+	window  time.Duration
+	breach  time.Duration
+}
+
+func currentObjective() (objective, error) {
+	target, err := strconv.ParseFloat(settings.AuthProviderSLOTarget.Get(), 64)
+	if err != nil || target < 0 || target > 100 {
+		return objective{}, fmt.Errorf("invalid %s setting %q: must be a percentage", settings.AuthProviderSLOTarget.Name, settings.AuthProviderSLOTarget.Get())
+	}
+	o := objective{
+		target:  target,
+		latency: time.Duration(settings.AuthProviderSLOLatencyMs.GetInt()) * time.Millisecond,
+		window:  time.Duration(settings.AuthProviderSLOWindowMinutes.GetInt()) * time.Minute,
+		breach:  time.Duration(settings.AuthProviderSLOBreachMinutes.GetInt()) * time.Minute,
+	}
+	if o.latency <= 0 || o.window <= 0 || o.breach < 0 {
+		return objective{}, fmt.Errorf("invalid %s, %s or %s setting", settings.AuthProviderSLOLatencyMs.Name,
+			settings.AuthProviderSLOWindowMinutes.Name, settings.AuthProviderSLOBreachMinutes.Name)
+	}
+	return o, nil
+}
+
+// recorder holds the requests counted by the replica since they were last added to the config map.
+type recorder struct {
+	mu      sync.Mutex
+	pending map[string]map[int64]*bucket
+}
+
+var (
+	requests = &recorder{pending: map[string]map[int64]*bucket{}}
+	now      = time.Now
+)
+
+// Observe counts a request to the provider which took the duration, and failed with err if it isn't nil. The errors
+// of the clients, like invalid credentials or unknown principals, aren't failures of the provider.
+func Observe(provider string, duration time.Duration, err error) {
+	if provider == "" || provider == localProvider {
+		return
+	}
+	o, oerr := currentObjective()
+	if oerr != nil || o.target <= 0 {
+		return
+	}
+	observed := &bucket{Requests: 1}
+	switch {
+	case providerError(err):
+		observed.Errors = 1
+	case duration > o.latency:
+		observed.Slow = 1
+	}
+	requests.add(provider, now().Truncate(time.Minute).Unix(), observed)
+}
+
+// providerError tells whether the error is a failure of the provider, rather than of the client.
+func providerError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *httperror.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code.Status >= http.StatusInternalServerError
+	}
+	return true
+}
+
+func (r *recorder) add(provider string, minute int64, observed *bucket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	buckets := r.pending[provider]
+	if buckets == nil {
+		buckets = map[int64]*bucket{}
+		r.pending[provider] = buckets
+	}
+	if b := buckets[minute]; b != nil {
+		b.add(observed)
+		return
+	}
+	buckets[minute] = &bucket{Minute: minute, Requests: observed.Requests, Errors: observed.Errors, Slow: observed.Slow}
+}
+
+// take returns the pending requests, and forgets them.
+func (r *recorder) take() map[string]map[int64]*bucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pending := r.pending
+	r.pending = map[string]map[int64]*bucket{}
+	return pending
+}
+
+// restore adds back the requests which couldn't be added to the config map.
+func (r *recorder) restore(pending map[string]map[int64]*bucket) {
+	for provider, buckets := range pending {
+		for minute, b := range buckets {
+			r.add(provider, minute, b)
+		}
+	}
+}
+
+// Start adds the requests counted by the replica to the config map until the context is done. It must run on every
+// replica, as they all make requests to the providers.
+func Start(ctx context.Context, scaledContext *config.ScaledContext) {
+	f := &flusher{configMaps: scaledContext.Wrangler.Core.ConfigMap()}
+	go wait.UntilWithContext(ctx, f.flush, flushInterval)
+}
+
+type flusher struct {
+	configMaps wcorev1.ConfigMapClient
+}
+
+func (f *flusher) flush(_ context.Context) {
+	o, err := currentObjective()
+	if err != nil {
+		logrus.Errorf("[providerslo] %v", err)
+		return
+	}
+	pending := requests.take()
+	if len(pending) == 0 || o.target <= 0 {
+		return
+	}
+	if err := f.merge(pending, o.window); err != nil {
+		logrus.Errorf("[providerslo] failed to add the requests to the auth providers to config map %s: %v", ConfigMapName, err)
+		requests.restore(pending)
+	}
+}
+
+// merge adds the pending requests to the config map, and drops the requests older than the window.
+func (f *flusher) merge(pending map[string]map[int64]*bucket, window time.Duration) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := f.configMaps.Get(namespace.System, ConfigMapName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		create := apierrors.IsNotFound(err)
+		if create {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: namespace.System},
+			}
+		} else {
+			configMap = configMap.DeepCopy()
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+
+		cutoff := now().Add(-window).Unix()
+		providers := map[string]bool{}
+		for provider := range configMap.Data {
+			providers[provider] = true
+		}
+		for provider := range pending {
+			providers[provider] = true
+		}
+		for provider := range providers {
+			merged := map[int64]*bucket{}
+			for _, b := range decodeBuckets(provider, configMap.Data[provider]) {
+				merged[b.Minute] = b
+			}
+			for minute, b := range pending[provider] {
+				if existing := merged[minute]; existing != nil {
+					existing.add(b)
+				} else {
+					copied := *b
+					merged[minute] = &copied
+				}
+			}
+			var buckets []*bucket
+			for minute, b := range merged {
+				if minute >= cutoff {
+					buckets = append(buckets, b)
+				}
+			}
+			if len(buckets) == 0 {
+				delete(configMap.Data, provider)
+				continue
+			}
+			sort.Slice(buckets, func(i, j int) bool { return buckets[i].Minute < buckets[j].Minute })
+			data, err := json.Marshal(buckets)
+			if err != nil {
+				return err
+			}
+			configMap.Data[provider] = string(data)
+		}
+
+		if create {
+			_, err = f.configMaps.Create(configMap)
+			if apierrors.IsAlreadyExists(err) {
+				// Another replica created it first.
+				return apierrors.NewConflict(corev1.Resource("configmaps"), ConfigMapName, err)
+			}
+			return err
+		}
+		_, err = f.configMaps.Update(configMap)
+		return err
+	})
+}
+
+// decodeBuckets returns the buckets of the provider stored in the config map, none if they're invalid.
+func decodeBuckets(provider, data string) []*bucket {
+	if data == "" {
+		return nil
+	}
+	var buckets []*bucket
+	if err := json.Unmarshal([]byte(data), &buckets); err != nil {
+		logrus.Warnf("[providerslo] dropping the invalid requests of auth provider %s: %v", provider, err)
+		return nil
+	}
+	return buckets
+}
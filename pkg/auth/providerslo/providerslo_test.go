@@ -0,0 +1,242 @@
+package providerslo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type fakeAuthConfigs struct {
+	objects map[string]*unstructured.Unstructured
+	updates int
+}
+
+func (f *fakeAuthConfigs) Get(name string, _ metav1.GetOptions) (runtime.Object, error) {
+	obj, ok := f.objects[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (f *fakeAuthConfigs) Update(name string, o runtime.Object) (runtime.Object, error) {
+	f.updates++
+	f.objects[name] = o.(*unstructured.Unstructured).DeepCopy()
+	return o, nil
+}
+
+func (f *fakeAuthConfigs) status(t *testing.T, name string) v3.AuthConfigStatus {
+	var status v3.AuthConfigStatus
+	require.NoError(t, convert(f.objects[name].Object["status"], &status))
+	return status
+}
+
+func newFakeConfigMaps(t *testing.T) (*fake.MockClientInterface[*corev1.ConfigMap, *corev1.ConfigMapList], map[string]*corev1.ConfigMap) {
+	ctrl := gomock.NewController(t)
+	stored := map[string]*corev1.ConfigMap{}
+	configMaps := fake.NewMockClientInterface[*corev1.ConfigMap, *corev1.ConfigMapList](ctrl)
+	configMaps.EXPECT().Get(namespace.System, ConfigMapName, gomock.Any()).DoAndReturn(func(_, name string, _ metav1.GetOptions) (*corev1.ConfigMap, error) {
+		if configMap, ok := stored[name]; ok {
+			return configMap.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), name)
+	}).AnyTimes()
+	configMaps.EXPECT().Create(gomock.Any()).DoAndReturn(func(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		stored[configMap.Name] = configMap.DeepCopy()
+		return configMap, nil
+	}).AnyTimes()
+	configMaps.EXPECT().Update(gomock.Any()).DoAndReturn(func(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		stored[configMap.Name] = configMap.DeepCopy()
+		return configMap, nil
+	}).AnyTimes()
+	return configMaps, stored
+}
+
+func setNow(t *testing.T, at *time.Time) {
+	previous := now
+	now = func() time.Time { return *at }
+	t.Cleanup(func() { now = previous })
+}
+
+func TestProviderError(t *testing.T) {
+	assert.False(t, providerError(nil))
+	assert.False(t, providerError(httperror.NewAPIError(httperror.Unauthorized, "invalid credentials")))
+	assert.False(t, providerError(httperror.NewAPIError(httperror.NotFound, "principal not found")))
+	assert.True(t, providerError(httperror.NewAPIError(httperror.ServerError, "server error")))
+	assert.True(t, providerError(errors.New("connection refused")))
+}
+
+func TestObserveAndFlush(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 30, 0, time.UTC)
+	setNow(t, &at)
+	requests.take()
+	configMaps, stored := newFakeConfigMaps(t)
+	f := &flusher{configMaps: configMaps}
+
+	Observe("openldap", 100*time.Millisecond, nil)
+	Observe("openldap", 3*time.Second, nil)
+	Observe("openldap", 100*time.Millisecond, errors.New("connection refused"))
+	Observe("openldap", 100*time.Millisecond, httperror.NewAPIError(httperror.Unauthorized, "invalid credentials"))
+	// The local provider isn't an identity service.
+	Observe("local", 3*time.Second, nil)
+	f.flush(context.Background())
+
+	require.Contains(t, stored, ConfigMapName)
+	assert.NotContains(t, stored[ConfigMapName].Data, "local")
+	buckets := decodeBuckets("openldap", stored[ConfigMapName].Data["openldap"])
+	require.Len(t, buckets, 1)
+	assert.Equal(t, bucket{Minute: at.Truncate(time.Minute).Unix(), Requests: 4, Errors: 1, Slow: 1}, *buckets[0])
+
+	// The requests of the other replicas are added to those stored, and those older than the window dropped.
+	at = at.Add(30 * time.Minute)
+	Observe("openldap", 100*time.Millisecond, nil)
+	f.flush(context.Background())
+	buckets = decodeBuckets("openldap", stored[ConfigMapName].Data["openldap"])
+	require.Len(t, buckets, 2)
+	assert.Equal(t, int64(5), summarize(buckets, at, time.Hour).Requests)
+
+	at = at.Add(45 * time.Minute)
+	Observe("openldap", 100*time.Millisecond, nil)
+	f.flush(context.Background())
+	buckets = decodeBuckets("openldap", stored[ConfigMapName].Data["openldap"])
+	require.Len(t, buckets, 2)
+	assert.Equal(t, int64(2), summarize(buckets, at, time.Hour).Requests)
+}
+
+func TestObserveDisabled(t *testing.T) {
+	require.NoError(t, settings.AuthProviderSLOTarget.Set("0"))
+	defer settings.AuthProviderSLOTarget.Set(settings.AuthProviderSLOTarget.Default)
+	requests.take()
+
+	Observe("openldap", 3*time.Second, nil)
+	assert.Empty(t, requests.take())
+}
+
+func TestEvaluate(t *testing.T) {
+	o := objective{target: 99, latency: 2 * time.Second, window: time.Hour, breach: 15 * time.Minute}
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	good := bucket{Requests: 1000, Errors: 2, Slow: 3}
+	bad := bucket{Requests: 1000, Errors: 50, Slow: 10}
+
+	slo, state := evaluate(nil, good, o, start)
+	assert.Empty(t, state)
+	assert.Equal(t, "99.50", slo.Compliance)
+	assert.Empty(t, slo.BelowTargetSince)
+
+	// The compliance falls below the target, but isn't breached until it stays there for the breach duration.
+	slo, state = evaluate(slo, bad, o, start.Add(time.Minute))
+	assert.Empty(t, state)
+	assert.Equal(t, "94.00", slo.Compliance)
+	assert.Equal(t, "2026-05-01T12:01:00Z", slo.BelowTargetSince)
+	assert.Empty(t, slo.BreachedSince)
+
+	slo, state = evaluate(slo, bad, o, start.Add(16*time.Minute))
+	assert.Equal(t, StateBreached, state)
+	assert.Equal(t, "2026-05-01T12:16:00Z", slo.BreachedSince)
+
+	// It's only reported breached once.
+	slo, state = evaluate(slo, bad, o, start.Add(17*time.Minute))
+	assert.Empty(t, state)
+	assert.Equal(t, "2026-05-01T12:16:00Z", slo.BreachedSince)
+
+	slo, state = evaluate(slo, good, o, start.Add(30*time.Minute))
+	assert.Equal(t, StateRecovered, state)
+	assert.Empty(t, slo.BelowTargetSince)
+	assert.Empty(t, slo.BreachedSince)
+
+	// Too few requests can't tell.
+	slo, state = evaluate(nil, bucket{Requests: 5, Errors: 5}, o, start)
+	assert.Empty(t, state)
+	assert.Empty(t, slo.Compliance)
+	assert.Empty(t, slo.BelowTargetSince)
+}
+
+func TestRun(t *testing.T) {
+	require.NoError(t, settings.AuthProviderSLOBreachMinutes.Set("0"))
+	defer settings.AuthProviderSLOBreachMinutes.Set(settings.AuthProviderSLOBreachMinutes.Default)
+
+	var alerts []Alert
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		alerts = append(alerts, alert)
+	}))
+	defer webhook.Close()
+	require.NoError(t, settings.AuthProviderSLOWebhookURL.Set(webhook.URL))
+	defer settings.AuthProviderSLOWebhookURL.Set(settings.AuthProviderSLOWebhookURL.Default)
+
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	configMaps, stored := newFakeConfigMaps(t)
+	authConfigs := &fakeAuthConfigs{objects: map[string]*unstructured.Unstructured{
+		"openldap": {Object: map[string]any{
+			"metadata": map[string]any{"name": "openldap"},
+			"type":     "openLdapConfig",
+			"enabled":  true,
+			"servers":  []any{"ldap.example.com"},
+			"status":   map[string]any{},
+		}},
+	}}
+	e := &Evaluator{
+		authConfigs:      authConfigs,
+		configMaps:       configMaps,
+		enabledProviders: func() []string { return []string{"openldap", "local"} },
+		httpClient:       webhook.Client(),
+		now:              func() time.Time { return at },
+		async:            func(f func()) { f() },
+	}
+	store := func(b bucket) {
+		b.Minute = at.Truncate(time.Minute).Unix()
+		data, err := json.Marshal([]bucket{b})
+		require.NoError(t, err)
+		stored[ConfigMapName] = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: namespace.System},
+			Data:       map[string]string{"openldap": string(data)},
+		}
+	}
+
+	store(bucket{Requests: 100, Errors: 50})
+	e.Run(context.Background())
+	status := authConfigs.status(t, "openldap")
+	require.NotNil(t, status.SLO)
+	assert.Equal(t, "50.00", status.SLO.Compliance)
+	assert.NotEmpty(t, status.SLO.BreachedSince)
+	assert.False(t, v3.AuthConfigConditionSLOMet.IsTrue(&v3.AuthConfig{Status: status}))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, StateBreached, alerts[0].State)
+	assert.Equal(t, "openldap", alerts[0].Provider)
+
+	// The counts alone don't update the auth config, which would refresh its users.
+	updates := authConfigs.updates
+	at = at.Add(time.Minute)
+	store(bucket{Requests: 100, Errors: 40})
+	e.Run(context.Background())
+	assert.Equal(t, updates, authConfigs.updates)
+	assert.Len(t, alerts, 1)
+
+	at = at.Add(time.Minute)
+	store(bucket{Requests: 100})
+	e.Run(context.Background())
+	status = authConfigs.status(t, "openldap")
+	assert.Empty(t, status.SLO.BreachedSince)
+	assert.True(t, v3.AuthConfigConditionSLOMet.IsTrue(&v3.AuthConfig{Status: status}))
+	require.Len(t, alerts, 2)
+	assert.Equal(t, StateRecovered, alerts[1].State)
+}
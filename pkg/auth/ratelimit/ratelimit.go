@@ -0,0 +1,144 @@
+// Package ratelimit limits the rate of the requests of each user, or each token, to the authenticated APIs, so that a
+// runaway automation account can't degrade the APIs for everyone. Each user or token has a bucket of
+// api-rate-limit-burst requests, refilled with api-rate-limit-requests-per-second, and the requests past it are rejected
+// with 429 Too Many Requests. The buckets are kept in memory by each Rancher server.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/metrics"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// ScopeUser is the scope of the buckets of the users.
+	ScopeUser = "user"
+	// ScopeToken is the scope of the buckets of the tokens.
+	ScopeToken = "token"
+
+	// maxBuckets bounds the memory used by requests from many users or tokens.
+	maxBuckets = 100000
+)
+
+// bucket holds the requests a user or a token can make right away.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps a bucket of requests for each user or token.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// NewLimiter returns a Limiter with full buckets.
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Allow takes a request from the bucket of the key. If the bucket is empty, it returns false and how long until the
+// next request is allowed.
+func (l *Limiter) Allow(key string, perSecond, burst int) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxBuckets {
+			l.prune(now, perSecond, burst)
+		}
+		if len(l.buckets) >= maxBuckets {
+			logrus.Warnf("[ratelimit] too many users and tokens to track, not limiting more of them")
+			return true, 0
+		}
+		b = &bucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+		metrics.SetAPIRateLimitBuckets(len(l.buckets))
+	}
+
+	b.tokens = refill(b, now, perSecond, burst)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / float64(perSecond) * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// prune forgets the full buckets, which are the same as new ones.
+func (l *Limiter) prune(now time.Time, perSecond, burst int) {
+	for key, b := range l.buckets {
+		if refill(b, now, perSecond, burst) >= float64(burst) {
+			delete(l.buckets, key)
+		}
+	}
+	metrics.SetAPIRateLimitBuckets(len(l.buckets))
+}
+
+// refill returns the requests in the bucket at now, which never exceed the burst.
+func refill(b *bucket, now time.Time, perSecond, burst int) float64 {
+	return math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*float64(perSecond))
+}
+
+// Middleware rejects the requests of the users or tokens which exceeded their rate limit. It must run after the
+// authentication, and before the impersonation, so that requests count for the authenticated user.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		perSecond := settings.APIRateLimitRequestsPerSecond.GetInt()
+		if perSecond <= 0 {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		burst := settings.APIRateLimitBurst.GetInt()
+		if burst < 1 {
+			burst = 1
+		}
+
+		scope, key := requestKey(req)
+		if key == "" {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		if ok, wait := l.Allow(scope+"/"+key, perSecond, burst); !ok {
+			metrics.IncAPIRateLimited(scope)
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			rw.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			util.WriteError(rw, http.StatusTooManyRequests, fmt.Errorf("too many requests, retry after %d seconds", retryAfter))
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// requestKey returns the scope and the key of the bucket of the request, an empty key if it isn't authenticated.
+// Requests without a token, e.g. authenticated with a service account, count for their user.
+func requestKey(req *http.Request) (string, string) {
+	userInfo, ok := request.UserFrom(req.Context())
+	if !ok {
+		return "", ""
+	}
+	if settings.APIRateLimitScope.Get() == ScopeToken {
+		if tokenID := userInfo.GetExtra()[common.ExtraRequestTokenID]; len(tokenID) > 0 && tokenID[0] != "" {
+			return ScopeToken, tokenID[0]
+		}
+	}
+	return ScopeUser, userInfo.GetName()
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func newTestLimiter(now *time.Time) *Limiter {
+	l := NewLimiter()
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+
+	// The burst is allowed at once.
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("user/u-alice", 2, 3)
+		assert.True(t, ok, i)
+	}
+	ok, wait := l.Allow("user/u-alice", 2, 3)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Other keys have their own bucket.
+	ok, _ = l.Allow("user/u-bob", 2, 3)
+	assert.True(t, ok)
+
+	// The bucket is refilled with the rate, up to the burst.
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow("user/u-alice", 2, 3)
+	assert.True(t, ok)
+	ok, _ = l.Allow("user/u-alice", 2, 3)
+	assert.False(t, ok)
+
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("user/u-alice", 2, 3)
+		assert.True(t, ok, i)
+	}
+	ok, _ = l.Allow("user/u-alice", 2, 3)
+	assert.False(t, ok)
+}
+
+func TestMiddleware(t *testing.T) {
+	require.NoError(t, settings.APIRateLimitRequestsPerSecond.Set("1"))
+	require.NoError(t, settings.APIRateLimitBurst.Set("2"))
+	t.Cleanup(func() {
+		settings.APIRateLimitRequestsPerSecond.Set(settings.APIRateLimitRequestsPerSecond.Default)
+		settings.APIRateLimitBurst.Set(settings.APIRateLimitBurst.Default)
+		settings.APIRateLimitScope.Set(settings.APIRateLimitScope.Default)
+	})
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	handler := l.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	serve := func(userName, tokenID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v3/clusters", nil)
+		if userName != "" {
+			req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{
+				Name:  userName,
+				Extra: map[string][]string{common.ExtraRequestTokenID: {tokenID}},
+			}))
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	// The tokens of a user share its limit.
+	assert.Equal(t, http.StatusOK, serve("u-alice", "token-1").Code)
+	assert.Equal(t, http.StatusOK, serve("u-alice", "token-2").Code)
+	rw := serve("u-alice", "token-3")
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "1", rw.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, serve("u-bob", "token-4").Code)
+
+	// Unauthenticated requests are left to the authentication.
+	assert.Equal(t, http.StatusOK, serve("", "").Code)
+
+	// Each token has its own limit.
+	require.NoError(t, settings.APIRateLimitScope.Set(ScopeToken))
+	assert.Equal(t, http.StatusOK, serve("u-alice", "token-3").Code)
+	assert.Equal(t, http.StatusOK, serve("u-alice", "token-3").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve("u-alice", "token-3").Code)
+
+	// The rate limit is disabled.
+	require.NoError(t, settings.APIRateLimitRequestsPerSecond.Set("0"))
+	assert.Equal(t, http.StatusOK, serve("u-alice", "token-3").Code)
+}
@@ -0,0 +1,185 @@
+package rbacbundle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// BasePath is the path of the RBAC bundle endpoint. A GET exports the bundle as YAML, a POST imports the YAML
+	// bundle of the body, only reporting the changes when the dryRun query parameter is true.
+	BasePath = "/v1-rbac-bundle"
+
+	maxBodySize = 8 * 1024 * 1024
+)
+
+// resources are the resources of the objects of bundles.
+var resources = []string{
+	v3.RoleTemplateResourceName,
+	v3.GlobalRoleResourceName,
+	v3.ClusterRoleTemplateBindingResourceName,
+	v3.ProjectRoleTemplateBindingResourceName,
+}
+
+type handler struct {
+	*bundler
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewHandler returns the handler of the RBAC bundle endpoint.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	return newHandler(mgmt).router()
+}
+
+func newHandler(mgmt *config.ScaledContext) *handler {
+	return &handler{
+		bundler:              newBundler(mgmt),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+	}
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodGet).Path(BasePath).HandlerFunc(h.exportBundle)
+	root.Methods(http.MethodPost).Path(BasePath).HandlerFunc(h.importBundle)
+	return root
+}
+
+// exportBundle writes the bundle of the install, which requires being allowed to list the objects of bundles.
+func (h *handler) exportBundle(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	if !h.authorizeAll(w, r, caller, "list") {
+		return
+	}
+
+	bundle, err := h.export()
+	if err != nil {
+		logrus.Errorf("[rbacbundle] failed to export the bundle: %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to export the bundle")
+		return
+	}
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		logrus.Errorf("[rbacbundle] failed to marshal the bundle: %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to export the bundle")
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		logrus.Errorf("[rbacbundle] failed to write response: %v", err)
+	}
+}
+
+// importBundle applies the bundle of the body and writes the changes. A dry run requires being allowed to list the
+// objects of bundles. Applying them requires being allowed to create, update and delete them, and to escalate role
+// templates and global roles, as they're applied by Rancher, bypassing the escalation checks of the callers' requests.
+func (h *handler) importBundle(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid dryRun")
+			return
+		}
+	}
+
+	if dryRun {
+		if !h.authorizeAll(w, r, caller, "list") {
+			return
+		}
+	} else {
+		if !h.authorizeAll(w, r, caller, "create", "update", "delete") {
+			return
+		}
+		for _, resource := range []string{v3.RoleTemplateResourceName, v3.GlobalRoleResourceName} {
+			if !h.authorizeOrFail(w, r, caller, "escalate", resource) {
+				return
+			}
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "failed to read the bundle")
+		return
+	}
+	if len(data) > maxBodySize {
+		util.ReturnHTTPError(w, r, http.StatusRequestEntityTooLarge, "bundle too large")
+		return
+	}
+	var bundle Bundle
+	if err := yaml.UnmarshalStrict(data, &bundle); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid bundle: %v", err))
+		return
+	}
+	if err := bundle.validate(); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	result := h.apply(&bundle, dryRun)
+	if !dryRun {
+		logrus.Infof("[rbacbundle] user %s imported a bundle, %d changes", caller.GetName(), len(result.Changes))
+	}
+	util.WriteJSON(w, http.StatusOK, result)
+}
+
+// authorizeAll checks that the caller is allowed the verbs on all the resources of bundles, writing the error
+// otherwise.
+func (h *handler) authorizeAll(w http.ResponseWriter, r *http.Request, caller user.Info, verbs ...string) bool {
+	for _, resource := range resources {
+		for _, verb := range verbs {
+			if !h.authorizeOrFail(w, r, caller, verb, resource) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (h *handler) authorizeOrFail(w http.ResponseWriter, r *http.Request, caller user.Info, verb, resource string) bool {
+	allowed, err := h.authorize(r.Context(), caller, verb, resource)
+	if err != nil {
+		logrus.Errorf("[rbacbundle] failed to authorize user %s: %v", caller.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return false
+	}
+	if !allowed {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to %s %s", verb, resource))
+		return false
+	}
+	return true
+}
+
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, verb, resource string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    v3.SchemeGroupVersion.Group,
+		Resource: resource,
+		Verb:     verb,
+	})
+}
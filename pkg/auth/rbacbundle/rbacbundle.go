@@ -0,0 +1,550 @@
+package rbacbundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Kind is the kind of the bundles.
+const Kind = "RBACBundle"
+
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionReplace   = "replace"
+	ActionUnchanged = "unchanged"
+
+	localPrincipalPrefix = "local://"
+)
+
+// Bundle holds the role templates, global roles and role template bindings of a Rancher install, so that they can be
+// kept in Git and applied to another install. Built-in roles, and objects owned by another object, are left out.
+// The objects only keep their name, namespace, and the labels and annotations outside of the cattle.io domains, and the
+// users of bindings are referred to by their principal, if they have an external one.
+type Bundle struct {
+	Kind                        string                          `json:"kind"`
+	RoleTemplates               []v3.RoleTemplate               `json:"roleTemplates,omitempty"`
+	GlobalRoles                 []v3.GlobalRole                 `json:"globalRoles,omitempty"`
+	ClusterRoleTemplateBindings []v3.ClusterRoleTemplateBinding `json:"clusterRoleTemplateBindings,omitempty"`
+	ProjectRoleTemplateBindings []v3.ProjectRoleTemplateBinding `json:"projectRoleTemplateBindings,omitempty"`
+}
+
+// Result holds the changes importing a bundle made, or would make on a dry run. Objects that aren't in the bundle are
+// left as they are.
+type Result struct {
+	DryRun    bool     `json:"dryRun"`
+	Changes   []Change `json:"changes"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// Change is the creation, update or replacement of an object of a bundle. Bindings are replaced rather than updated,
+// as their fields are immutable.
+type Change struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Action    string `json:"action"`
+	// Fields are the top-level fields, and the metadata fields, the change updates.
+	Fields []string `json:"fields,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+type bundler struct {
+	rtCache    mgmtv3.RoleTemplateCache
+	rtClient   mgmtv3.RoleTemplateClient
+	grCache    mgmtv3.GlobalRoleCache
+	grClient   mgmtv3.GlobalRoleClient
+	crtbCache  mgmtv3.ClusterRoleTemplateBindingCache
+	crtbClient mgmtv3.ClusterRoleTemplateBindingClient
+	prtbCache  mgmtv3.ProjectRoleTemplateBindingCache
+	prtbClient mgmtv3.ProjectRoleTemplateBindingClient
+	userCache  mgmtv3.UserCache
+}
+
+func newBundler(mgmt *config.ScaledContext) *bundler {
+	return &bundler{
+		rtCache:    mgmt.Wrangler.Mgmt.RoleTemplate().Cache(),
+		rtClient:   mgmt.Wrangler.Mgmt.RoleTemplate(),
+		grCache:    mgmt.Wrangler.Mgmt.GlobalRole().Cache(),
+		grClient:   mgmt.Wrangler.Mgmt.GlobalRole(),
+		crtbCache:  mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		crtbClient: mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding(),
+		prtbCache:  mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		prtbClient: mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding(),
+		userCache:  mgmt.Wrangler.Mgmt.User().Cache(),
+	}
+}
+
+// export returns the bundle of the objects of the install, sorted by namespace and name.
+func (b *bundler) export() (*Bundle, error) {
+	bundle := &Bundle{Kind: Kind}
+
+	rts, err := b.rtCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role templates: %w", err)
+	}
+	for _, rt := range rts {
+		if exported(&rt.ObjectMeta) && !rt.Builtin {
+			bundle.RoleTemplates = append(bundle.RoleTemplates, *b.normalizeRoleTemplate(rt))
+		}
+	}
+
+	grs, err := b.grCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list global roles: %w", err)
+	}
+	for _, gr := range grs {
+		if exported(&gr.ObjectMeta) && !gr.Builtin {
+			bundle.GlobalRoles = append(bundle.GlobalRoles, *b.normalizeGlobalRole(gr))
+		}
+	}
+
+	crtbs, err := b.crtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster role template bindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		if exported(&crtb.ObjectMeta) {
+			bundle.ClusterRoleTemplateBindings = append(bundle.ClusterRoleTemplateBindings, *b.normalizeCRTB(crtb))
+		}
+	}
+
+	prtbs, err := b.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project role template bindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		if exported(&prtb.ObjectMeta) && prtb.ServiceAccount == "" {
+			bundle.ProjectRoleTemplateBindings = append(bundle.ProjectRoleTemplateBindings, *b.normalizePRTB(prtb))
+		}
+	}
+
+	sort.Slice(bundle.RoleTemplates, func(i, j int) bool {
+		return bundle.RoleTemplates[i].Name < bundle.RoleTemplates[j].Name
+	})
+	sort.Slice(bundle.GlobalRoles, func(i, j int) bool {
+		return bundle.GlobalRoles[i].Name < bundle.GlobalRoles[j].Name
+	})
+	sort.Slice(bundle.ClusterRoleTemplateBindings, func(i, j int) bool {
+		return less(&bundle.ClusterRoleTemplateBindings[i].ObjectMeta, &bundle.ClusterRoleTemplateBindings[j].ObjectMeta)
+	})
+	sort.Slice(bundle.ProjectRoleTemplateBindings, func(i, j int) bool {
+		return less(&bundle.ProjectRoleTemplateBindings[i].ObjectMeta, &bundle.ProjectRoleTemplateBindings[j].ObjectMeta)
+	})
+	return bundle, nil
+}
+
+// validate checks that the objects of the bundle are named, that the bindings have a namespace and that no object is
+// in the bundle twice.
+func (bundle *Bundle) validate() error {
+	if bundle.Kind != Kind {
+		return fmt.Errorf("kind must be %s", Kind)
+	}
+	seen := map[string]bool{}
+	check := func(kind string, meta *metav1.ObjectMeta, namespaced bool) error {
+		if meta.Name == "" {
+			return fmt.Errorf("a %s has no name", kind)
+		}
+		if namespaced && meta.Namespace == "" {
+			return fmt.Errorf("%s %s has no namespace", kind, meta.Name)
+		}
+		key := kind + "/" + meta.Namespace + "/" + meta.Name
+		if seen[key] {
+			return fmt.Errorf("%s %s is in the bundle twice", kind, name(meta))
+		}
+		seen[key] = true
+		return nil
+	}
+	for i := range bundle.RoleTemplates {
+		if err := check("RoleTemplate", &bundle.RoleTemplates[i].ObjectMeta, false); err != nil {
+			return err
+		}
+	}
+	for i := range bundle.GlobalRoles {
+		if err := check("GlobalRole", &bundle.GlobalRoles[i].ObjectMeta, false); err != nil {
+			return err
+		}
+	}
+	for i := range bundle.ClusterRoleTemplateBindings {
+		if err := check("ClusterRoleTemplateBinding", &bundle.ClusterRoleTemplateBindings[i].ObjectMeta, true); err != nil {
+			return err
+		}
+	}
+	for i := range bundle.ProjectRoleTemplateBindings {
+		if err := check("ProjectRoleTemplateBinding", &bundle.ProjectRoleTemplateBindings[i].ObjectMeta, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply creates the objects of the bundle that don't exist and updates those that differ, or only reports the
+// changes on a dry run. Role templates are applied first, those inherited before those inheriting them, then global
+// roles and bindings, which refer to them. A failed change is reported and doesn't stop the others.
+func (b *bundler) apply(bundle *Bundle, dryRun bool) *Result {
+	result := &Result{DryRun: dryRun, Changes: []Change{}}
+	record := func(change Change) {
+		if change.Action == ActionUnchanged && change.Error == "" {
+			result.Unchanged++
+			return
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	rts := applier[*v3.RoleTemplate]{
+		kind:      "RoleTemplate",
+		get:       func(_, name string) (*v3.RoleTemplate, error) { return b.rtCache.Get(name) },
+		normalize: b.normalizeRoleTemplate,
+		create:    func(rt *v3.RoleTemplate) error { _, err := b.rtClient.Create(rt); return err },
+		update:    func(rt *v3.RoleTemplate) error { _, err := b.rtClient.Update(rt); return err },
+	}
+	for _, rt := range inheritanceOrder(bundle.RoleTemplates) {
+		record(rts.apply(rt.DeepCopy(), dryRun))
+	}
+
+	grs := applier[*v3.GlobalRole]{
+		kind:      "GlobalRole",
+		get:       func(_, name string) (*v3.GlobalRole, error) { return b.grCache.Get(name) },
+		normalize: b.normalizeGlobalRole,
+		create:    func(gr *v3.GlobalRole) error { _, err := b.grClient.Create(gr); return err },
+		update:    func(gr *v3.GlobalRole) error { _, err := b.grClient.Update(gr); return err },
+	}
+	for i := range bundle.GlobalRoles {
+		record(grs.apply(bundle.GlobalRoles[i].DeepCopy(), dryRun))
+	}
+
+	crtbs := applier[*v3.ClusterRoleTemplateBinding]{
+		kind:      "ClusterRoleTemplateBinding",
+		get:       b.crtbCache.Get,
+		normalize: b.normalizeCRTB,
+		create: func(crtb *v3.ClusterRoleTemplateBinding) error {
+			_, err := b.crtbClient.Create(crtb)
+			return err
+		},
+		delete: func(namespace, name string) error {
+			return b.crtbClient.Delete(namespace, name, &metav1.DeleteOptions{})
+		},
+	}
+	for i := range bundle.ClusterRoleTemplateBindings {
+		record(crtbs.apply(bundle.ClusterRoleTemplateBindings[i].DeepCopy(), dryRun))
+	}
+
+	prtbs := applier[*v3.ProjectRoleTemplateBinding]{
+		kind:      "ProjectRoleTemplateBinding",
+		get:       b.prtbCache.Get,
+		normalize: b.normalizePRTB,
+		create: func(prtb *v3.ProjectRoleTemplateBinding) error {
+			_, err := b.prtbClient.Create(prtb)
+			return err
+		},
+		delete: func(namespace, name string) error {
+			return b.prtbClient.Delete(namespace, name, &metav1.DeleteOptions{})
+		},
+	}
+	for i := range bundle.ProjectRoleTemplateBindings {
+		record(prtbs.apply(bundle.ProjectRoleTemplateBindings[i].DeepCopy(), dryRun))
+	}
+
+	return result
+}
+
+type object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// applier applies the objects of a kind. Objects without update are immutable, they're deleted and created again when
+// they differ.
+type applier[T object] struct {
+	kind      string
+	get       func(namespace, name string) (T, error)
+	normalize func(T) T
+	create    func(T) error
+	update    func(T) error
+	delete    func(namespace, name string) error
+}
+
+func (a applier[T]) apply(desired T, dryRun bool) Change {
+	change := Change{Kind: a.kind, Name: desired.GetName(), Namespace: desired.GetNamespace()}
+	fail := func(err error) Change {
+		change.Error = err.Error()
+		return change
+	}
+
+	existing, err := a.get(desired.GetNamespace(), desired.GetName())
+	if apierrors.IsNotFound(err) {
+		change.Action = ActionCreate
+		if !dryRun {
+			if err := a.create(desired); err != nil {
+				return fail(err)
+			}
+		}
+		return change
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	updated := desired.DeepCopyObject().(T)
+	withExistingMeta(updated, existing)
+	change.Fields, err = diffFields(a.normalize(existing), a.normalize(updated))
+	if err != nil {
+		return fail(err)
+	}
+	if len(change.Fields) == 0 {
+		change.Action = ActionUnchanged
+		return change
+	}
+
+	if a.update != nil {
+		change.Action = ActionUpdate
+		if !dryRun {
+			if err := a.update(updated); err != nil {
+				return fail(err)
+			}
+		}
+		return change
+	}
+	change.Action = ActionReplace
+	if !dryRun {
+		if err := a.delete(existing.GetNamespace(), existing.GetName()); err != nil && !apierrors.IsNotFound(err) {
+			return fail(err)
+		}
+		if err := a.create(desired); err != nil {
+			return fail(err)
+		}
+	}
+	return change
+}
+
+// withExistingMeta sets the metadata of the existing object the bundle doesn't hold on the object updating it, and
+// adds the labels and annotations of the existing object it doesn't set.
+func withExistingMeta(updated, existing metav1.Object) {
+	updated.SetUID(existing.GetUID())
+	updated.SetResourceVersion(existing.GetResourceVersion())
+	updated.SetGeneration(existing.GetGeneration())
+	updated.SetCreationTimestamp(existing.GetCreationTimestamp())
+	updated.SetFinalizers(existing.GetFinalizers())
+	updated.SetOwnerReferences(existing.GetOwnerReferences())
+	updated.SetLabels(merge(existing.GetLabels(), updated.GetLabels()))
+	updated.SetAnnotations(merge(existing.GetAnnotations(), updated.GetAnnotations()))
+}
+
+// diffFields returns the top-level fields, and the metadata fields, that differ between the objects.
+func diffFields(existing, updated any) ([]string, error) {
+	existingFields, err := toMap(existing)
+	if err != nil {
+		return nil, err
+	}
+	updatedFields, err := toMap(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for _, key := range keys(existingFields, updatedFields) {
+		if reflect.DeepEqual(existingFields[key], updatedFields[key]) {
+			continue
+		}
+		existingMeta, ok1 := existingFields[key].(map[string]any)
+		updatedMeta, ok2 := updatedFields[key].(map[string]any)
+		if key != "metadata" || !ok1 || !ok2 {
+			fields = append(fields, key)
+			continue
+		}
+		for _, metaKey := range keys(existingMeta, updatedMeta) {
+			if !reflect.DeepEqual(existingMeta[metaKey], updatedMeta[metaKey]) {
+				fields = append(fields, key+"."+metaKey)
+			}
+		}
+	}
+	return fields, nil
+}
+
+func toMap(obj any) (map[string]any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func keys(maps ...map[string]any) []string {
+	var all []string
+	seen := map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				all = append(all, key)
+			}
+		}
+	}
+	sort.Strings(all)
+	return all
+}
+
+// inheritanceOrder sorts the role templates by name, moving the role templates inherited by others before them.
+func inheritanceOrder(rts []v3.RoleTemplate) []*v3.RoleTemplate {
+	byName := map[string]*v3.RoleTemplate{}
+	var names []string
+	for i := range rts {
+		byName[rts[i].Name] = &rts[i]
+		names = append(names, rts[i].Name)
+	}
+	sort.Strings(names)
+
+	var ordered []*v3.RoleTemplate
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		rt, ok := byName[name]
+		if !ok || visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, inherited := range rt.RoleTemplateNames {
+			visit(inherited)
+		}
+		ordered = append(ordered, rt)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return ordered
+}
+
+func (b *bundler) normalizeRoleTemplate(rt *v3.RoleTemplate) *v3.RoleTemplate {
+	normalized := rt.DeepCopy()
+	normalized.TypeMeta = metav1.TypeMeta{}
+	normalized.ObjectMeta = normalizedMeta(&rt.ObjectMeta)
+	return normalized
+}
+
+func (b *bundler) normalizeGlobalRole(gr *v3.GlobalRole) *v3.GlobalRole {
+	normalized := gr.DeepCopy()
+	normalized.TypeMeta = metav1.TypeMeta{}
+	normalized.ObjectMeta = normalizedMeta(&gr.ObjectMeta)
+	normalized.Status = v3.GlobalRoleStatus{}
+	return normalized
+}
+
+func (b *bundler) normalizeCRTB(crtb *v3.ClusterRoleTemplateBinding) *v3.ClusterRoleTemplateBinding {
+	normalized := crtb.DeepCopy()
+	normalized.TypeMeta = metav1.TypeMeta{}
+	normalized.ObjectMeta = normalizedMeta(&crtb.ObjectMeta)
+	normalized.Status = v3.ClusterRoleTemplateBindingStatus{}
+	normalized.UserName, normalized.UserPrincipalName = b.normalizeUser(crtb.UserName, crtb.UserPrincipalName)
+	return normalized
+}
+
+func (b *bundler) normalizePRTB(prtb *v3.ProjectRoleTemplateBinding) *v3.ProjectRoleTemplateBinding {
+	normalized := prtb.DeepCopy()
+	normalized.TypeMeta = metav1.TypeMeta{}
+	normalized.ObjectMeta = normalizedMeta(&prtb.ObjectMeta)
+	normalized.UserName, normalized.UserPrincipalName = b.normalizeUser(prtb.UserName, prtb.UserPrincipalName)
+	return normalized
+}
+
+// normalizeUser returns the user and principal a binding refers to, as the principal of the user alone when possible,
+// since user IDs differ from one install to another. A user without an external principal keeps being referred to by
+// its ID.
+func (b *bundler) normalizeUser(userName, principalName string) (string, string) {
+	if principalName != "" || userName == "" {
+		return "", principalName
+	}
+	user, err := b.userCache.Get(userName)
+	if err != nil {
+		return userName, ""
+	}
+	principalIDs := append([]string(nil), user.PrincipalIDs...)
+	sort.Strings(principalIDs)
+	for _, principalID := range principalIDs {
+		if !strings.HasPrefix(principalID, localPrincipalPrefix) {
+			return "", principalID
+		}
+	}
+	return userName, ""
+}
+
+// normalizedMeta returns the metadata of an object kept in bundles.
+func normalizedMeta(meta *metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      withoutManagedKeys(meta.Labels),
+		Annotations: withoutManagedKeys(meta.Annotations),
+	}
+}
+
+// withoutManagedKeys returns the labels or annotations without those of the cattle.io domains, which Rancher manages.
+func withoutManagedKeys(values map[string]string) map[string]string {
+	var kept map[string]string
+	for key, value := range values {
+		if isManagedKey(key) {
+			continue
+		}
+		if kept == nil {
+			kept = map[string]string{}
+		}
+		kept[key] = value
+	}
+	return kept
+}
+
+func isManagedKey(key string) bool {
+	domain, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	return domain == "cattle.io" || strings.HasSuffix(domain, ".cattle.io") || domain == "kubectl.kubernetes.io"
+}
+
+// exported returns whether an object belongs in bundles, which leave out objects owned by another one, since their
+// owner manages them.
+func exported(meta *metav1.ObjectMeta) bool {
+	return meta.DeletionTimestamp == nil && len(meta.OwnerReferences) == 0
+}
+
+func merge(existing, updated map[string]string) map[string]string {
+	if len(existing) == 0 {
+		return updated
+	}
+	merged := make(map[string]string, len(existing)+len(updated))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range updated {
+		merged[key] = value
+	}
+	return merged
+}
+
+func less(a, b *metav1.ObjectMeta) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+func name(meta *metav1.ObjectMeta) string {
+	if meta.Namespace == "" {
+		return meta.Name
+	}
+	return meta.Namespace + "/" + meta.Name
+}
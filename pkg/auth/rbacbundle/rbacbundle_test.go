@@ -0,0 +1,404 @@
+package rbacbundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"sigs.k8s.io/yaml"
+)
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	// admins are allowed everything, readers are allowed to list.
+	admins  map[string]bool
+	readers map[string]bool
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	sar.Status.Allowed = f.admins[sar.Spec.User] || (f.readers[sar.Spec.User] && sar.Spec.ResourceAttributes.Verb == "list")
+	return sar, nil
+}
+
+var (
+	podReader  = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}}
+	nodeReader = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list"}}
+)
+
+type mocks struct {
+	rtClient   *fake.MockNonNamespacedClientInterface[*v3.RoleTemplate, *v3.RoleTemplateList]
+	grClient   *fake.MockNonNamespacedClientInterface[*v3.GlobalRole, *v3.GlobalRoleList]
+	crtbClient *fake.MockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList]
+	prtbClient *fake.MockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList]
+}
+
+func notFound(resource, name string) error {
+	return apierrors.NewNotFound(schema.GroupResource{Resource: resource}, name)
+}
+
+func setup(t *testing.T) (*handler, *mocks) {
+	ctrl := gomock.NewController(t)
+
+	rts := map[string]*v3.RoleTemplate{
+		"project-member": {ObjectMeta: metav1.ObjectMeta{Name: "project-member"}, Builtin: true},
+		"pod-reader": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "pod-reader",
+				ResourceVersion: "12",
+				Labels:          map[string]string{"cattle.io/creator": "norman", "team": "a"},
+				Annotations:     map[string]string{"lifecycle.cattle.io/create.mgmt-auth-roletemplate-lifecycle": "true"},
+			},
+			Context: "project",
+			Rules:   []rbacv1.PolicyRule{podReader},
+		},
+	}
+	rtCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	rtCache.EXPECT().List(gomock.Any()).DoAndReturn(func(_ any) ([]*v3.RoleTemplate, error) {
+		return []*v3.RoleTemplate{rts["project-member"], rts["pod-reader"]}, nil
+	}).AnyTimes()
+	rtCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.RoleTemplate, error) {
+		if rt, ok := rts[name]; ok {
+			return rt, nil
+		}
+		return nil, notFound("roletemplates", name)
+	}).AnyTimes()
+
+	auditor := &v3.GlobalRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "auditor", ResourceVersion: "7", Finalizers: []string{"wrangler.cattle.io/auth-prov-v2-roletemplate"}},
+		Rules:      []rbacv1.PolicyRule{podReader},
+		Status:     v3.GlobalRoleStatus{Summary: "Complete"},
+	}
+	grCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRole](ctrl)
+	grCache.EXPECT().List(gomock.Any()).Return([]*v3.GlobalRole{
+		{ObjectMeta: metav1.ObjectMeta{Name: "admin"}, Builtin: true},
+		auditor,
+	}, nil).AnyTimes()
+	grCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.GlobalRole, error) {
+		if name == auditor.Name {
+			return auditor, nil
+		}
+		return nil, notFound("globalroles", name)
+	}).AnyTimes()
+
+	crtbs := []*v3.ClusterRoleTemplateBinding{
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "crtb-b", Namespace: "c-abc"},
+			UserName:         "u-alice",
+			ClusterName:      "c-abc",
+			RoleTemplateName: "cluster-member",
+			Status:           v3.ClusterRoleTemplateBindingStatus{Summary: "Completed"},
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "crtb-a", Namespace: "c-abc", OwnerReferences: []metav1.OwnerReference{{Name: "owner"}}},
+			UserName:         "u-alice",
+			ClusterName:      "c-abc",
+			RoleTemplateName: "cluster-owner",
+		},
+	}
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("", gomock.Any()).Return(crtbs, nil).AnyTimes()
+	crtbCache.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string) (*v3.ClusterRoleTemplateBinding, error) {
+		for _, crtb := range crtbs {
+			if crtb.Namespace == namespace && crtb.Name == name {
+				return crtb, nil
+			}
+		}
+		return nil, notFound("clusterroletemplatebindings", name)
+	}).AnyTimes()
+
+	prtbs := []*v3.ProjectRoleTemplateBinding{
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "prtb-b", Namespace: "p-xyz"},
+			UserName:         "u-local",
+			ProjectName:      "c-abc:p-xyz",
+			RoleTemplateName: "pod-reader",
+		},
+		{
+			ObjectMeta:         metav1.ObjectMeta{Name: "prtb-a", Namespace: "p-xyz"},
+			GroupPrincipalName: "openldap_group://cn=devs",
+			ProjectName:        "c-abc:p-xyz",
+			RoleTemplateName:   "pod-reader",
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "prtb-sa", Namespace: "p-xyz"},
+			ServiceAccount:   "default:deployer",
+			ProjectName:      "c-abc:p-xyz",
+			RoleTemplateName: "pod-reader",
+		},
+	}
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", gomock.Any()).Return(prtbs, nil).AnyTimes()
+	prtbCache.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string) (*v3.ProjectRoleTemplateBinding, error) {
+		for _, prtb := range prtbs {
+			if prtb.Namespace == namespace && prtb.Name == name {
+				return prtb, nil
+			}
+		}
+		return nil, notFound("projectroletemplatebindings", name)
+	}).AnyTimes()
+
+	users := map[string]*v3.User{
+		"u-alice": {ObjectMeta: metav1.ObjectMeta{Name: "u-alice"}, PrincipalIDs: []string{"openldap_user://uid=alice", "local://u-alice"}},
+		"u-local": {ObjectMeta: metav1.ObjectMeta{Name: "u-local"}, PrincipalIDs: []string{"local://u-local"}},
+	}
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.User, error) {
+		if u, ok := users[name]; ok {
+			return u, nil
+		}
+		return nil, notFound("users", name)
+	}).AnyTimes()
+
+	m := &mocks{
+		rtClient:   fake.NewMockNonNamespacedClientInterface[*v3.RoleTemplate, *v3.RoleTemplateList](ctrl),
+		grClient:   fake.NewMockNonNamespacedClientInterface[*v3.GlobalRole, *v3.GlobalRoleList](ctrl),
+		crtbClient: fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl),
+		prtbClient: fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl),
+	}
+	h := &handler{
+		bundler: &bundler{
+			rtCache:    rtCache,
+			rtClient:   m.rtClient,
+			grCache:    grCache,
+			grClient:   m.grClient,
+			crtbCache:  crtbCache,
+			crtbClient: m.crtbClient,
+			prtbCache:  prtbCache,
+			prtbClient: m.prtbClient,
+			userCache:  userCache,
+		},
+		subjectAccessReviews: &fakeSubjectAccessReviews{
+			admins:  map[string]bool{"u-admin": true},
+			readers: map[string]bool{"u-reader": true},
+		},
+	}
+	return h, m
+}
+
+func TestExport(t *testing.T) {
+	h, _ := setup(t)
+
+	bundle, err := h.export()
+	require.NoError(t, err)
+
+	assert.Equal(t, &Bundle{
+		Kind: Kind,
+		RoleTemplates: []v3.RoleTemplate{{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-reader", Labels: map[string]string{"team": "a"}},
+			Context:    "project",
+			Rules:      []rbacv1.PolicyRule{podReader},
+		}},
+		GlobalRoles: []v3.GlobalRole{{
+			ObjectMeta: metav1.ObjectMeta{Name: "auditor"},
+			Rules:      []rbacv1.PolicyRule{podReader},
+		}},
+		ClusterRoleTemplateBindings: []v3.ClusterRoleTemplateBinding{{
+			ObjectMeta:        metav1.ObjectMeta{Name: "crtb-b", Namespace: "c-abc"},
+			UserPrincipalName: "openldap_user://uid=alice",
+			ClusterName:       "c-abc",
+			RoleTemplateName:  "cluster-member",
+		}},
+		ProjectRoleTemplateBindings: []v3.ProjectRoleTemplateBinding{
+			{
+				ObjectMeta:         metav1.ObjectMeta{Name: "prtb-a", Namespace: "p-xyz"},
+				GroupPrincipalName: "openldap_group://cn=devs",
+				ProjectName:        "c-abc:p-xyz",
+				RoleTemplateName:   "pod-reader",
+			},
+			{
+				ObjectMeta:       metav1.ObjectMeta{Name: "prtb-b", Namespace: "p-xyz"},
+				UserName:         "u-local",
+				ProjectName:      "c-abc:p-xyz",
+				RoleTemplateName: "pod-reader",
+			},
+		},
+	}, bundle)
+}
+
+// changedBundle holds a new role template inheriting another new one, an unchanged role template, an updated global
+// role, a binding with another role template and a new binding.
+func changedBundle() *Bundle {
+	return &Bundle{
+		Kind: Kind,
+		RoleTemplates: []v3.RoleTemplate{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a-pod-and-node-reader"}, Context: "project", RoleTemplateNames: []string{"z-node-reader", "pod-reader"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod-reader", Labels: map[string]string{"team": "a"}}, Context: "project", Rules: []rbacv1.PolicyRule{podReader}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "z-node-reader"}, Context: "project", Rules: []rbacv1.PolicyRule{nodeReader}},
+		},
+		GlobalRoles: []v3.GlobalRole{
+			{ObjectMeta: metav1.ObjectMeta{Name: "auditor"}, Rules: []rbacv1.PolicyRule{podReader, nodeReader}},
+		},
+		ClusterRoleTemplateBindings: []v3.ClusterRoleTemplateBinding{{
+			ObjectMeta:        metav1.ObjectMeta{Name: "crtb-b", Namespace: "c-abc"},
+			UserPrincipalName: "openldap_user://uid=alice",
+			ClusterName:       "c-abc",
+			RoleTemplateName:  "cluster-owner",
+		}},
+		ProjectRoleTemplateBindings: []v3.ProjectRoleTemplateBinding{{
+			ObjectMeta:        metav1.ObjectMeta{Name: "prtb-c", Namespace: "p-xyz"},
+			UserPrincipalName: "openldap_user://uid=bob",
+			ProjectName:       "c-abc:p-xyz",
+			RoleTemplateName:  "a-pod-and-node-reader",
+		}},
+	}
+}
+
+var expectedChanges = []Change{
+	{Kind: "RoleTemplate", Name: "z-node-reader", Action: ActionCreate},
+	{Kind: "RoleTemplate", Name: "a-pod-and-node-reader", Action: ActionCreate},
+	{Kind: "GlobalRole", Name: "auditor", Action: ActionUpdate, Fields: []string{"rules"}},
+	{Kind: "ClusterRoleTemplateBinding", Name: "crtb-b", Namespace: "c-abc", Action: ActionReplace, Fields: []string{"roleTemplateName"}},
+	{Kind: "ProjectRoleTemplateBinding", Name: "prtb-c", Namespace: "p-xyz", Action: ActionCreate},
+}
+
+func TestApplyDryRun(t *testing.T) {
+	h, _ := setup(t)
+
+	result := h.apply(changedBundle(), true)
+
+	assert.Equal(t, &Result{DryRun: true, Changes: expectedChanges, Unchanged: 1}, result)
+}
+
+func TestApply(t *testing.T) {
+	h, m := setup(t)
+
+	gomock.InOrder(
+		m.rtClient.EXPECT().Create(gomock.Any()).DoAndReturn(func(rt *v3.RoleTemplate) (*v3.RoleTemplate, error) {
+			assert.Equal(t, "z-node-reader", rt.Name)
+			return rt, nil
+		}),
+		m.rtClient.EXPECT().Create(gomock.Any()).DoAndReturn(func(rt *v3.RoleTemplate) (*v3.RoleTemplate, error) {
+			assert.Equal(t, "a-pod-and-node-reader", rt.Name)
+			return rt, nil
+		}),
+	)
+	m.grClient.EXPECT().Update(gomock.Any()).DoAndReturn(func(gr *v3.GlobalRole) (*v3.GlobalRole, error) {
+		assert.Equal(t, "7", gr.ResourceVersion)
+		assert.Equal(t, []string{"wrangler.cattle.io/auth-prov-v2-roletemplate"}, gr.Finalizers)
+		assert.Equal(t, []rbacv1.PolicyRule{podReader, nodeReader}, gr.Rules)
+		return gr, nil
+	})
+	gomock.InOrder(
+		m.crtbClient.EXPECT().Delete("c-abc", "crtb-b", gomock.Any()).Return(nil),
+		m.crtbClient.EXPECT().Create(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+			assert.Equal(t, "cluster-owner", crtb.RoleTemplateName)
+			return crtb, nil
+		}),
+	)
+	m.prtbClient.EXPECT().Create(gomock.Any()).Return(nil, apierrors.NewForbidden(schema.GroupResource{Resource: "projectroletemplatebindings"}, "prtb-c", nil))
+
+	result := h.apply(changedBundle(), false)
+
+	require.Len(t, result.Changes, len(expectedChanges))
+	assert.Equal(t, expectedChanges[:4], result.Changes[:4])
+	assert.NotEmpty(t, result.Changes[4].Error)
+	assert.Equal(t, 1, result.Unchanged)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundle  *Bundle
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			bundle: changedBundle(),
+		},
+		{
+			name:    "wrong kind",
+			bundle:  &Bundle{Kind: "List"},
+			wantErr: "kind must be RBACBundle",
+		},
+		{
+			name: "binding without namespace",
+			bundle: &Bundle{Kind: Kind, ClusterRoleTemplateBindings: []v3.ClusterRoleTemplateBinding{
+				{ObjectMeta: metav1.ObjectMeta{Name: "crtb-a"}},
+			}},
+			wantErr: "ClusterRoleTemplateBinding crtb-a has no namespace",
+		},
+		{
+			name: "duplicate",
+			bundle: &Bundle{Kind: Kind, GlobalRoles: []v3.GlobalRole{
+				{ObjectMeta: metav1.ObjectMeta{Name: "auditor"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "auditor"}},
+			}},
+			wantErr: "GlobalRole auditor is in the bundle twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.bundle.validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func serve(h *handler, method, url, userName string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, bytes.NewReader(body))
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userName}))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerExport(t *testing.T) {
+	h, _ := setup(t)
+
+	rec := serve(h, http.MethodGet, BasePath, "u-reader", nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+	var bundle Bundle
+	require.NoError(t, yaml.UnmarshalStrict(rec.Body.Bytes(), &bundle))
+	assert.Len(t, bundle.RoleTemplates, 1)
+	assert.Len(t, bundle.ProjectRoleTemplateBindings, 2)
+
+	// The export is deterministic.
+	again := serve(h, http.MethodGet, BasePath, "u-reader", nil)
+	assert.Equal(t, rec.Body.String(), again.Body.String())
+}
+
+func TestHandlerImport(t *testing.T) {
+	h, _ := setup(t)
+	body, err := yaml.Marshal(changedBundle())
+	require.NoError(t, err)
+
+	rec := serve(h, http.MethodGet, BasePath, "u-nobody", nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(h, http.MethodPost, BasePath, "u-reader", body)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(h, http.MethodPost, BasePath+"?dryRun=maybe", "u-reader", body)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(h, http.MethodPost, BasePath+"?dryRun=true", "u-reader", []byte("kind: RBACBundle\nroles: []\n"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(h, http.MethodPost, BasePath+"?dryRun=true", "u-reader", body)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var result Result
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, Result{DryRun: true, Changes: expectedChanges, Unchanged: 1}, result)
+}
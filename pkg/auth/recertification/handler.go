@@ -0,0 +1,319 @@
+package recertification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// BasePath is the path of the recertification campaign endpoints.
+	BasePath = "/v1-recertification-campaigns"
+
+	// DecideAction records the decisions of a reviewer on the bindings of a campaign.
+	DecideAction = "decide"
+
+	// ReviewVerb is the verb the users deciding on campaigns they aren't reviewers of must be allowed on them.
+	ReviewVerb = "review"
+
+	maxBodySize      = 256 * 1024
+	maxCommentLength = 1024
+)
+
+type handler struct {
+	campaigns            mgmtcontrollers.RecertificationCampaignClient
+	campaignCache        mgmtcontrollers.RecertificationCampaignCache
+	crtbs                mgmtcontrollers.ClusterRoleTemplateBindingClient
+	prtbs                mgmtcontrollers.ProjectRoleTemplateBindingClient
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+	now                  func() time.Time
+}
+
+// NewHandler returns the handler of the recertification campaign endpoints.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	h := &handler{
+		campaigns:            mgmt.Wrangler.Mgmt.RecertificationCampaign(),
+		campaignCache:        mgmt.Wrangler.Mgmt.RecertificationCampaign().Cache(),
+		crtbs:                mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding(),
+		prtbs:                mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding(),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+		now:                  time.Now,
+	}
+	return h.router()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodGet).Path(BasePath).HandlerFunc(h.list)
+	root.Methods(http.MethodGet).Path(BasePath + "/{name}").HandlerFunc(h.get)
+	root.Methods(http.MethodPost).Path(BasePath+"/{name}").Queries("action", DecideAction).HandlerFunc(h.decide)
+	return root
+}
+
+// decideInput is the body of the decide action.
+type decideInput struct {
+	Decisions []decisionInput `json:"decisions"`
+}
+
+// decisionInput is the decision of a reviewer on a binding of a campaign.
+type decisionInput struct {
+	// BindingName is the namespaced name of the binding, as <namespace>:<name>.
+	BindingName string `json:"bindingName"`
+	// Decision is either "Approved" or "Revoked".
+	Decision string `json:"decision"`
+	// Comment is why the reviewer made the decision.
+	Comment string `json:"comment,omitempty"`
+}
+
+// list writes the campaigns the caller reviews, or all of them for those allowed to list them, most recent first. They
+// can be filtered by state with the state query parameter.
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	listAll, err := h.authorize(r.Context(), caller, "list", v3.RecertificationCampaignResourceName, "")
+	if err != nil {
+		logrus.Errorf("[recertification] failed to authorize user %s: %v", caller.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return
+	}
+	all, err := h.campaignCache.List(labels.Everything())
+	if err != nil {
+		logrus.Errorf("[recertification] failed to list recertification campaigns: %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to list recertification campaigns")
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	campaigns := make([]*v3.RecertificationCampaign, 0, len(all))
+	for _, campaign := range all {
+		if !listAll && !slices.Contains(campaign.Spec.Reviewers, caller.GetName()) {
+			continue
+		}
+		if state != "" && campaign.Status.State != state {
+			continue
+		}
+		campaigns = append(campaigns, campaign)
+	}
+	sort.Slice(campaigns, func(i, j int) bool {
+		return campaigns[j].CreationTimestamp.Before(&campaigns[i].CreationTimestamp)
+	})
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"type": "collection",
+		"data": campaigns,
+	})
+}
+
+// get writes a campaign the caller reviews, or any of them for those allowed to get them.
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	campaign, ok := h.campaign(w, r)
+	if !ok {
+		return
+	}
+	if !slices.Contains(campaign.Spec.Reviewers, caller.GetName()) {
+		allowed, err := h.authorize(r.Context(), caller, "get", v3.RecertificationCampaignResourceName, campaign.Name)
+		if err != nil {
+			logrus.Errorf("[recertification] failed to authorize user %s: %v", caller.GetName(), err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+			return
+		}
+		if !allowed {
+			util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("recertification campaign %s not found", campaign.Name))
+			return
+		}
+	}
+	util.WriteJSON(w, http.StatusOK, campaign)
+}
+
+// decide records the decisions of the caller on bindings of an open campaign, and removes the revoked bindings right
+// away. Approved bindings can still be revoked until the deadline, while revocations are final. The decisions are all
+// rejected if any of them is invalid.
+func (h *handler) decide(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	campaign, ok := h.campaign(w, r)
+	if !ok {
+		return
+	}
+	if !h.reviewer(w, r, caller, campaign) {
+		return
+	}
+	if campaign.Status.State != StateOpen || !h.now().Before(campaign.Spec.Deadline.Time) {
+		util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("recertification campaign %s is closed", campaign.Name))
+		return
+	}
+
+	var input decideInput
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&input); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid decisions")
+		return
+	}
+	if len(input.Decisions) == 0 {
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, "decisions must not be empty")
+		return
+	}
+
+	campaign = campaign.DeepCopy()
+	indexes := map[string]int{}
+	for i, item := range campaign.Status.Items {
+		indexes[item.BindingName] = i
+	}
+	now := metav1.NewTime(h.now())
+	var decided []*v3.RecertificationItem
+	for _, decision := range input.Decisions {
+		i, ok := indexes[decision.BindingName]
+		if !ok {
+			util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, fmt.Sprintf("binding %s isn't reviewed by recertification campaign %s", decision.BindingName, campaign.Name))
+			return
+		}
+		item := &campaign.Status.Items[i]
+		if decision.Decision != DecisionApproved && decision.Decision != DecisionRevoked {
+			util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, fmt.Sprintf("decision on binding %s must be either %s or %s", item.BindingName, DecisionApproved, DecisionRevoked))
+			return
+		}
+		if len(decision.Comment) > maxCommentLength {
+			util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, fmt.Sprintf("comment must be at most %d characters", maxCommentLength))
+			return
+		}
+		if ownBinding(caller, item) {
+			util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("can't decide on your own binding %s", item.BindingName))
+			return
+		}
+		if item.Decision == DecisionRevoked {
+			util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("binding %s is already revoked", item.BindingName))
+			return
+		}
+		item.Decision = decision.Decision
+		item.DecidedBy = caller.GetName()
+		item.DecidedAt = &now
+		item.Comment = decision.Comment
+		decided = append(decided, item)
+	}
+
+	updated, err := h.campaigns.UpdateStatus(campaign)
+	if apierrors.IsConflict(err) {
+		util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("recertification campaign %s was modified, try again", campaign.Name))
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[recertification] failed to record the decisions on recertification campaign %s: %v", campaign.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to record the decisions")
+		return
+	}
+
+	// The revoked bindings failing to be removed now are removed when the campaign is closed.
+	for _, item := range decided {
+		logDecision(campaign, item)
+		if item.Decision != DecisionRevoked {
+			continue
+		}
+		if err := RemoveBinding(h.crtbs, h.prtbs, item); err != nil {
+			logrus.Errorf("[recertification] failed to remove revoked %s %s: %v", item.Kind, item.BindingName, err)
+		}
+	}
+	util.WriteJSON(w, http.StatusOK, updated)
+}
+
+// campaign returns the campaign of the path. It's read from the API server rather than the cache, so that decisions
+// are made on its latest state.
+func (h *handler) campaign(w http.ResponseWriter, r *http.Request) (*v3.RecertificationCampaign, bool) {
+	name := mux.Vars(r)["name"]
+	campaign, err := h.campaigns.Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("recertification campaign %s not found", name))
+		return nil, false
+	}
+	if err != nil {
+		logrus.Errorf("[recertification] failed to get recertification campaign %s: %v", name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get recertification campaign")
+		return nil, false
+	}
+	return campaign, true
+}
+
+// reviewer tells whether the caller can decide on the campaign: its reviewers can, as well as the users allowed the
+// review verb on it.
+func (h *handler) reviewer(w http.ResponseWriter, r *http.Request, caller user.Info, campaign *v3.RecertificationCampaign) bool {
+	if slices.Contains(campaign.Spec.Reviewers, caller.GetName()) {
+		return true
+	}
+	allowed, err := h.authorize(r.Context(), caller, ReviewVerb, v3.RecertificationCampaignResourceName, campaign.Name)
+	if err != nil {
+		logrus.Errorf("[recertification] failed to authorize user %s: %v", caller.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return false
+	}
+	if !allowed {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to decide on recertification campaign %s", campaign.Name))
+		return false
+	}
+	return true
+}
+
+// ownBinding tells whether the binding grants its role template to the caller, either directly or through one of the
+// caller's principals or groups.
+func ownBinding(caller user.Info, item *v3.RecertificationItem) bool {
+	return item.Subject == caller.GetName() ||
+		slices.Contains(caller.GetExtra()[common.UserAttributePrincipalID], item.Subject) ||
+		slices.Contains(caller.GetGroups(), item.Subject)
+}
+
+// authorize tells whether the user can apply the verb to the resource of the management API group.
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, verb, resource, name string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    v3.SchemeGroupVersion.Group,
+		Resource: resource,
+		Name:     name,
+		Verb:     verb,
+	})
+}
+
+func logDecision(campaign *v3.RecertificationCampaign, item *v3.RecertificationItem) {
+	fields := logrus.Fields{
+		"event":            "RecertificationDecision",
+		"campaign":         campaign.Name,
+		"clusterName":      campaign.Spec.ClusterName,
+		"kind":             item.Kind,
+		"bindingName":      item.BindingName,
+		"roleTemplateName": item.RoleTemplateName,
+		"subject":          item.Subject,
+		"decision":         item.Decision,
+		"actor":            item.DecidedBy,
+	}
+	if item.Comment != "" {
+		fields["comment"] = item.Comment
+	}
+	logrus.WithFields(fields).Info("recertification: audit")
+}
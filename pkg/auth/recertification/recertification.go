@@ -0,0 +1,270 @@
+// Package recertification generates the recertification campaigns of the clusters, in which reviewers approve or
+// revoke the role template bindings of a cluster and of its projects. The bindings which weren't approved by the
+// deadline of their campaign are removed.
+package recertification
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// StateOpen is the state of the campaigns until their deadline, and StateClosed of those whose bindings which
+	// weren't approved were removed.
+	StateOpen   = "Open"
+	StateClosed = "Closed"
+
+	// DecisionApproved keeps a binding and DecisionRevoked removes it. DecisionExpired is recorded on the bindings
+	// removed at the deadline without being approved.
+	DecisionApproved = "Approved"
+	DecisionRevoked  = "Revoked"
+	DecisionExpired  = "Expired"
+
+	// CRTBKind and PRTBKind are the kinds of the bindings reviewed.
+	CRTBKind = "ClusterRoleTemplateBinding"
+	PRTBKind = "ProjectRoleTemplateBinding"
+
+	// reviewerRoleTemplateName is the role template of the users reviewing the bindings of a cluster.
+	reviewerRoleTemplateName = "cluster-owner"
+
+	defaultDuration = 14 * 24 * time.Hour
+)
+
+// Generator creates a recertification campaign for each cluster without an open one, listing the bindings of the
+// cluster and of its projects, and assigning the owners of the cluster as its reviewers.
+type Generator struct {
+	clusterCache  mgmtcontrollers.ClusterCache
+	campaigns     mgmtcontrollers.RecertificationCampaignClient
+	campaignCache mgmtcontrollers.RecertificationCampaignCache
+	crtbCache     mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbCache     mgmtcontrollers.ProjectRoleTemplateBindingCache
+	now           func() time.Time
+}
+
+// New creates a new instance of Generator.
+func New(wContext *wrangler.Context) *Generator {
+	return &Generator{
+		clusterCache:  wContext.Mgmt.Cluster().Cache(),
+		campaigns:     wContext.Mgmt.RecertificationCampaign(),
+		campaignCache: wContext.Mgmt.RecertificationCampaign().Cache(),
+		crtbCache:     wContext.Mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbCache:     wContext.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		now:           time.Now,
+	}
+}
+
+// Run the generation of the campaigns.
+func (g *Generator) Run(ctx context.Context) error {
+	if ctx.Err() != nil {
+		logrus.Info("recertification: context canceled, quitting")
+		return nil
+	}
+
+	startedAt := time.Now()
+
+	clusters, err := g.clusterCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing clusters: %w", err)
+	}
+	campaigns, err := g.campaignCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing recertification campaigns: %w", err)
+	}
+	open := map[string]bool{}
+	for _, campaign := range campaigns {
+		if campaign.Status.State != StateClosed {
+			open[campaign.Spec.ClusterName] = true
+		}
+	}
+
+	logrus.Info("recertification: started")
+
+	var generated, skipped, errCount int
+
+	defer func() {
+		logrus.Infof(
+			"recertification: finished in %v seconds (clusters %d, generated %d, skipped %d, errors %d)",
+			time.Since(startedAt).Seconds(),
+			len(clusters), generated, skipped, errCount,
+		)
+	}()
+
+	for _, cluster := range clusters {
+		if ctx.Err() != nil {
+			logrus.Info("recertification: context canceled, quitting")
+			return nil
+		}
+		if cluster.DeletionTimestamp != nil {
+			continue
+		}
+		if open[cluster.Name] {
+			skipped++
+			continue
+		}
+
+		items, reviewers, err := g.review(cluster.Name)
+		if err != nil {
+			logrus.Errorf("recertification: error listing the bindings of cluster %s: %v", cluster.Name, err)
+			errCount++
+			continue
+		}
+		if len(items) == 0 {
+			continue
+		}
+		if err := g.generate(cluster, items, reviewers); err != nil {
+			logrus.Errorf("recertification: error generating the campaign of cluster %s: %v", cluster.Name, err)
+			errCount++
+			continue
+		}
+		generated++
+	}
+
+	return nil
+}
+
+// review returns the bindings of the cluster and of its projects to review, sorted by kind and name, and the owners of
+// the cluster reviewing them. The bindings created by controllers are left to them, and the temporary bindings, whose
+// subjects don't review the cluster either, expire on their own.
+func (g *Generator) review(clusterName string) ([]v3.RecertificationItem, []string, error) {
+	var items []v3.RecertificationItem
+	reviewers := map[string]bool{}
+
+	crtbs, err := g.crtbCache.List(clusterName, labels.Everything())
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing ClusterRoleTemplateBindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		if crtb.DeletionTimestamp != nil || crtb.ClusterName != clusterName {
+			continue
+		}
+		if crtb.ExpiresAt != nil || crtb.TTL != "" {
+			continue
+		}
+		if crtb.RoleTemplateName == reviewerRoleTemplateName && crtb.UserName != "" {
+			reviewers[crtb.UserName] = true
+		}
+		if provenance.Managed(crtb) {
+			continue
+		}
+		items = append(items, v3.RecertificationItem{
+			Kind:             CRTBKind,
+			BindingName:      crtb.Namespace + ":" + crtb.Name,
+			RoleTemplateName: crtb.RoleTemplateName,
+			Subject:          subject(crtb.UserName, crtb.UserPrincipalName, crtb.GroupName, crtb.GroupPrincipalName),
+		})
+	}
+
+	prtbs, err := g.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing ProjectRoleTemplateBindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		if prtb.DeletionTimestamp != nil || prtb.ServiceAccount != "" || !strings.HasPrefix(prtb.ProjectName, clusterName+":") {
+			continue
+		}
+		if provenance.Managed(prtb) || prtb.ExpiresAt != nil || prtb.TTL != "" {
+			continue
+		}
+		items = append(items, v3.RecertificationItem{
+			Kind:             PRTBKind,
+			BindingName:      prtb.Namespace + ":" + prtb.Name,
+			ProjectName:      prtb.ProjectName,
+			RoleTemplateName: prtb.RoleTemplateName,
+			Subject:          subject(prtb.UserName, prtb.UserPrincipalName, prtb.GroupName, prtb.GroupPrincipalName),
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind < items[j].Kind
+		}
+		return items[i].BindingName < items[j].BindingName
+	})
+	names := make([]string, 0, len(reviewers))
+	for name := range reviewers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return items, names, nil
+}
+
+// generate creates the campaign of the cluster, owned by the cluster so that it's removed with it. The campaign is
+// removed if its items can't be recorded, so that the cluster isn't skipped by the next runs.
+func (g *Generator) generate(cluster *v3.Cluster, items []v3.RecertificationItem, reviewers []string) error {
+	duration := time.Duration(settings.RecertificationCampaignDurationHours.GetInt()) * time.Hour
+	if duration <= 0 {
+		duration = defaultDuration
+	}
+	campaign, err := g.campaigns.Create(&v3.RecertificationCampaign{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: cluster.Name + "-",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v3.SchemeGroupVersion.String(),
+				Kind:       "Cluster",
+				Name:       cluster.Name,
+				UID:        cluster.UID,
+			}},
+		},
+		Spec: v3.RecertificationCampaignSpec{
+			ClusterName: cluster.Name,
+			Reviewers:   reviewers,
+			Deadline:    metav1.NewTime(g.now().Add(duration)),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating the campaign: %w", err)
+	}
+
+	campaign.Status.State = StateOpen
+	campaign.Status.Items = items
+	if _, err := g.campaigns.UpdateStatus(campaign); err != nil {
+		if err := g.campaigns.Delete(campaign.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			logrus.Errorf("recertification: error removing campaign %s: %v", campaign.Name, err)
+		}
+		return fmt.Errorf("recording the bindings of campaign %s: %w", campaign.Name, err)
+	}
+	logrus.Infof("recertification: generated campaign %s reviewing %d bindings of cluster %s", campaign.Name, len(items), cluster.Name)
+	return nil
+}
+
+// subject returns the user name or principal a binding grants its role template to.
+func subject(userName, userPrincipalName, groupName, groupPrincipalName string) string {
+	switch {
+	case userName != "":
+		return userName
+	case userPrincipalName != "":
+		return userPrincipalName
+	case groupPrincipalName != "":
+		return groupPrincipalName
+	default:
+		return groupName
+	}
+}
+
+// RemoveBinding removes the binding of an item. Bindings already removed are ignored.
+func RemoveBinding(crtbs mgmtcontrollers.ClusterRoleTemplateBindingClient, prtbs mgmtcontrollers.ProjectRoleTemplateBindingClient, item *v3.RecertificationItem) error {
+	namespace, name, _ := strings.Cut(item.BindingName, ":")
+	var err error
+	if item.Kind == CRTBKind {
+		err = crtbs.Delete(namespace, name, &metav1.DeleteOptions{})
+	} else {
+		err = prtbs.Delete(namespace, name, &metav1.DeleteOptions{})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
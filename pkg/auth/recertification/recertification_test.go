@@ -0,0 +1,328 @@
+package recertification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	// admins are allowed everything.
+	admins map[string]bool
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	sar.Status.Allowed = f.admins[sar.Spec.User]
+	return sar, nil
+}
+
+type store struct {
+	campaigns map[string]*v3.RecertificationCampaign
+	// deleted are the namespaced names of the bindings removed.
+	deleted []string
+}
+
+func newStore(ctrl *gomock.Controller) (*store, *fake.MockNonNamespacedClientInterface[*v3.RecertificationCampaign, *v3.RecertificationCampaignList], *fake.MockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList], *fake.MockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList]) {
+	s := &store{campaigns: map[string]*v3.RecertificationCampaign{}}
+
+	campaigns := fake.NewMockNonNamespacedClientInterface[*v3.RecertificationCampaign, *v3.RecertificationCampaignList](ctrl)
+	campaigns.EXPECT().Create(gomock.Any()).DoAndReturn(func(campaign *v3.RecertificationCampaign) (*v3.RecertificationCampaign, error) {
+		campaign = campaign.DeepCopy()
+		campaign.Name = fmt.Sprintf("%s%d", campaign.GenerateName, len(s.campaigns)+1)
+		campaign.Status = v3.RecertificationCampaignStatus{}
+		s.campaigns[campaign.Name] = campaign
+		return campaign.DeepCopy(), nil
+	}).AnyTimes()
+	campaigns.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(campaign *v3.RecertificationCampaign) (*v3.RecertificationCampaign, error) {
+		s.campaigns[campaign.Name] = campaign.DeepCopy()
+		return campaign, nil
+	}).AnyTimes()
+	campaigns.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, _ metav1.GetOptions) (*v3.RecertificationCampaign, error) {
+		if campaign, ok := s.campaigns[name]; ok {
+			return campaign.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "recertificationcampaigns"}, name)
+	}).AnyTimes()
+
+	crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	crtbs.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ *metav1.DeleteOptions) error {
+		s.deleted = append(s.deleted, namespace+":"+name)
+		return nil
+	}).AnyTimes()
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	prtbs.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ *metav1.DeleteOptions) error {
+		s.deleted = append(s.deleted, namespace+":"+name)
+		return nil
+	}).AnyTimes()
+
+	return s, campaigns, crtbs, prtbs
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s, campaigns, _, _ := newStore(ctrl)
+
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	clusterCache.EXPECT().List(gomock.Any()).Return([]*v3.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", UID: "uid-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-open"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-empty"}},
+	}, nil)
+	campaignCache := fake.NewMockNonNamespacedCacheInterface[*v3.RecertificationCampaign](ctrl)
+	campaignCache.EXPECT().List(gomock.Any()).Return([]*v3.RecertificationCampaign{
+		{Spec: v3.RecertificationCampaignSpec{ClusterName: "c-open"}, Status: v3.RecertificationCampaignStatus{State: StateOpen}},
+		{Spec: v3.RecertificationCampaignSpec{ClusterName: "c-abcde"}, Status: v3.RecertificationCampaignStatus{State: StateClosed}},
+	}, nil)
+
+	managed := metav1.ObjectMeta{Name: "crtb-managed", Namespace: "c-abcde"}
+	provenance.Set(&managed, provenance.SourceOrganization, provenance.Creator("Organization", "acme"))
+	expiring := metav1.NewTime(now.Add(time.Hour))
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace string, _ labels.Selector) ([]*v3.ClusterRoleTemplateBinding, error) {
+		if namespace != "c-abcde" {
+			return nil, nil
+		}
+		return []*v3.ClusterRoleTemplateBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-owner", Namespace: "c-abcde"}, ClusterName: "c-abcde", RoleTemplateName: "cluster-owner", UserName: "u-owner"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-group", Namespace: "c-abcde"}, ClusterName: "c-abcde", RoleTemplateName: "cluster-member", GroupPrincipalName: "openldap_group://devs"},
+			{ObjectMeta: managed, ClusterName: "c-abcde", RoleTemplateName: "organization-admin", UserName: "u-admin"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-temporary", Namespace: "c-abcde"}, ClusterName: "c-abcde", RoleTemplateName: "cluster-owner", UserName: "u-oncall", ExpiresAt: &expiring},
+		}, nil
+	}).Times(2)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", gomock.Any()).Return([]*v3.ProjectRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-member", Namespace: "p-fghij"}, ProjectName: "c-abcde:p-fghij", RoleTemplateName: "project-member", UserName: "u-dev"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-ttl", Namespace: "p-fghij"}, ProjectName: "c-abcde:p-fghij", RoleTemplateName: "project-member", UserName: "u-dev", TTL: "8h"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-other", Namespace: "p-klmno"}, ProjectName: "c-other:p-klmno", RoleTemplateName: "project-member", UserName: "u-dev"},
+	}, nil).Times(2)
+
+	g := &Generator{
+		clusterCache:  clusterCache,
+		campaigns:     campaigns,
+		campaignCache: campaignCache,
+		crtbCache:     crtbCache,
+		prtbCache:     prtbCache,
+		now:           func() time.Time { return now },
+	}
+	require.NoError(t, g.Run(context.Background()))
+
+	require.Len(t, s.campaigns, 1, "the cluster with an open campaign and the one without bindings are skipped")
+	campaign := s.campaigns["c-abcde-1"]
+	require.NotNil(t, campaign)
+	assert.Equal(t, "c-abcde", campaign.Spec.ClusterName)
+	assert.Equal(t, []string{"u-owner"}, campaign.Spec.Reviewers)
+	assert.Equal(t, now.Add(14*24*time.Hour), campaign.Spec.Deadline.Time)
+	assert.Equal(t, "uid-1", string(campaign.OwnerReferences[0].UID))
+	assert.Equal(t, StateOpen, campaign.Status.State)
+	assert.Equal(t, []v3.RecertificationItem{
+		{Kind: CRTBKind, BindingName: "c-abcde:crtb-group", RoleTemplateName: "cluster-member", Subject: "openldap_group://devs"},
+		{Kind: CRTBKind, BindingName: "c-abcde:crtb-owner", RoleTemplateName: "cluster-owner", Subject: "u-owner"},
+		{Kind: PRTBKind, BindingName: "p-fghij:prtb-member", ProjectName: "c-abcde:p-fghij", RoleTemplateName: "project-member", Subject: "u-dev"},
+	}, campaign.Status.Items)
+}
+
+func setupHandler(t *testing.T, now *time.Time) (*handler, *store) {
+	ctrl := gomock.NewController(t)
+	s, campaigns, crtbs, prtbs := newStore(ctrl)
+	s.campaigns["c-abcde-1"] = &v3.RecertificationCampaign{
+		ObjectMeta: metav1.ObjectMeta{Name: "c-abcde-1"},
+		Spec: v3.RecertificationCampaignSpec{
+			ClusterName: "c-abcde",
+			Reviewers:   []string{"u-owner"},
+			Deadline:    metav1.NewTime(now.Add(time.Hour)),
+		},
+		Status: v3.RecertificationCampaignStatus{
+			State: StateOpen,
+			Items: []v3.RecertificationItem{
+				{Kind: CRTBKind, BindingName: "c-abcde:crtb-group", RoleTemplateName: "cluster-member", Subject: "openldap_group://devs"},
+				{Kind: CRTBKind, BindingName: "c-abcde:crtb-owner", RoleTemplateName: "cluster-owner", Subject: "u-owner"},
+				{Kind: PRTBKind, BindingName: "p-fghij:prtb-member", ProjectName: "c-abcde:p-fghij", RoleTemplateName: "project-member", Subject: "u-dev"},
+			},
+		},
+	}
+	campaignCache := fake.NewMockNonNamespacedCacheInterface[*v3.RecertificationCampaign](ctrl)
+	campaignCache.EXPECT().List(gomock.Any()).DoAndReturn(func(_ labels.Selector) ([]*v3.RecertificationCampaign, error) {
+		var list []*v3.RecertificationCampaign
+		for _, campaign := range s.campaigns {
+			list = append(list, campaign)
+		}
+		return list, nil
+	}).AnyTimes()
+
+	h := &handler{
+		campaigns:            campaigns,
+		campaignCache:        campaignCache,
+		crtbs:                crtbs,
+		prtbs:                prtbs,
+		subjectAccessReviews: &fakeSubjectAccessReviews{admins: map[string]bool{"u-admin": true}},
+		now:                  func() time.Time { return *now },
+	}
+	return h, s
+}
+
+func serve(h *handler, method, target string, caller user.Info, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	req = req.WithContext(request.WithUser(req.Context(), caller))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+	return rec
+}
+
+func decide(decisions ...decisionInput) decideInput {
+	return decideInput{Decisions: decisions}
+}
+
+func TestDecide(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s := setupHandler(t, &now)
+	owner := &user.DefaultInfo{Name: "u-owner"}
+	target := BasePath + "/c-abcde-1?action=" + DecideAction
+
+	rec := serve(h, http.MethodPost, target, owner, decide(
+		decisionInput{BindingName: "c-abcde:crtb-group", Decision: DecisionApproved, Comment: "still on call"},
+		decisionInput{BindingName: "p-fghij:prtb-member", Decision: DecisionRevoked, Comment: "left the team"},
+	))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	items := s.campaigns["c-abcde-1"].Status.Items
+	assert.Equal(t, DecisionApproved, items[0].Decision)
+	assert.Equal(t, "u-owner", items[0].DecidedBy)
+	assert.Equal(t, "still on call", items[0].Comment)
+	assert.Equal(t, DecisionRevoked, items[2].Decision)
+	assert.Empty(t, items[1].Decision)
+	assert.Equal(t, []string{"p-fghij:prtb-member"}, s.deleted, "revoked bindings are removed right away")
+
+	// Approvals can be changed, revocations can't.
+	rec = serve(h, http.MethodPost, target, owner, decide(decisionInput{BindingName: "c-abcde:crtb-group", Decision: DecisionRevoked}))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = serve(h, http.MethodPost, target, owner, decide(decisionInput{BindingName: "p-fghij:prtb-member", Decision: DecisionApproved}))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Admins decide on the campaigns they don't review.
+	rec = serve(h, http.MethodPost, target, &user.DefaultInfo{Name: "u-admin"}, decide(decisionInput{BindingName: "c-abcde:crtb-owner", Decision: DecisionApproved}))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "u-admin", s.campaigns["c-abcde-1"].Status.Items[1].DecidedBy)
+}
+
+func TestDecideInvalid(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	owner := &user.DefaultInfo{Name: "u-owner"}
+	target := BasePath + "/c-abcde-1?action=" + DecideAction
+
+	tests := []struct {
+		name   string
+		caller user.Info
+		body   decideInput
+		status int
+	}{
+		{
+			name:   "not a reviewer",
+			caller: &user.DefaultInfo{Name: "u-dev"},
+			body:   decide(decisionInput{BindingName: "c-abcde:crtb-group", Decision: DecisionApproved}),
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "own binding",
+			caller: owner,
+			body:   decide(decisionInput{BindingName: "c-abcde:crtb-owner", Decision: DecisionApproved}),
+			status: http.StatusForbidden,
+		},
+		{
+			name: "own group binding",
+			caller: &user.DefaultInfo{
+				Name:   "u-admin",
+				Groups: []string{"openldap_group://devs"},
+				Extra:  map[string][]string{common.UserAttributePrincipalID: {"local://u-admin"}},
+			},
+			body:   decide(decisionInput{BindingName: "c-abcde:crtb-group", Decision: DecisionApproved}),
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "unknown binding",
+			caller: owner,
+			body:   decide(decisionInput{BindingName: "c-abcde:crtb-unknown", Decision: DecisionApproved}),
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "invalid decision",
+			caller: owner,
+			body:   decide(decisionInput{BindingName: "c-abcde:crtb-group", Decision: DecisionExpired}),
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "no decision",
+			caller: owner,
+			body:   decide(),
+			status: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, s := setupHandler(t, &now)
+			rec := serve(h, http.MethodPost, target, tt.caller, tt.body)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			for _, item := range s.campaigns["c-abcde-1"].Status.Items {
+				assert.Empty(t, item.Decision)
+			}
+			assert.Empty(t, s.deleted)
+		})
+	}
+
+	t.Run("past the deadline", func(t *testing.T) {
+		h, _ := setupHandler(t, &now)
+		later := now.Add(2 * time.Hour)
+		h.now = func() time.Time { return later }
+		rec := serve(h, http.MethodPost, target, owner, decide(decisionInput{BindingName: "c-abcde:crtb-group", Decision: DecisionApproved}))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
+
+func TestListAndGet(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, _ := setupHandler(t, &now)
+
+	count := func(caller string, query string) int {
+		rec := serve(h, http.MethodGet, BasePath+query, &user.DefaultInfo{Name: caller}, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var list struct {
+			Data []v3.RecertificationCampaign `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		return len(list.Data)
+	}
+	assert.Equal(t, 1, count("u-owner", ""))
+	assert.Equal(t, 1, count("u-admin", "?state="+StateOpen))
+	assert.Equal(t, 0, count("u-admin", "?state="+StateClosed))
+	assert.Equal(t, 0, count("u-dev", ""))
+
+	rec := serve(h, http.MethodGet, BasePath+"/c-abcde-1", &user.DefaultInfo{Name: "u-owner"}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = serve(h, http.MethodGet, BasePath+"/c-abcde-1", &user.DefaultInfo{Name: "u-dev"}, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
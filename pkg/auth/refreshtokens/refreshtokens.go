@@ -0,0 +1,228 @@
+// Package refreshtokens pairs short-lived Rancher tokens with rotating refresh tokens, so that clients logging in for a
+// long time don't hold a long-lived bearer token.
+// The refresh tokens issued for a login form a family. Each refresh returns a new token and a new refresh token of
+// the family, and uses up the one presented. A used refresh token being presented again means it leaked, so the whole
+// family is revoked, along with its tokens. A family expires after auth-refresh-token-ttl-minutes, however many times
+// it's refreshed.
+package refreshtokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// GrantType is the grant_type of the token requests refreshing a token, see RFC 6749 section 6.
+	GrantType = "refresh_token"
+
+	tokenPath = "/v1-token/refresh"
+
+	// defaultAccessTokenTTL applies when auth-access-token-ttl-minutes isn't a positive number of minutes,
+	// as tokens without a time to live never expire.
+	defaultAccessTokenTTL = 15 * time.Minute
+)
+
+// Error codes of the token endpoint, see RFC 6749 section 5.2.
+const (
+	errInvalidGrant         = "invalid_grant"
+	errUnsupportedGrantType = "unsupported_grant_type"
+	errServerError          = "server_error"
+)
+
+var (
+	// ErrInvalidGrant is returned for unknown, expired or reused refresh tokens.
+	ErrInvalidGrant = errors.New("invalid refresh token")
+	errReused       = fmt.Errorf("%w: refresh token reused", ErrInvalidGrant)
+)
+
+type tokenManager interface {
+	NewFamilyToken(family, kind, clusterName, userID string, userPrincipal v3.Principal, ttl int64, description string) (v3.Token, string, error)
+	RevokeTokenFamily(family string) error
+	EnforceSessionLimit(userID string) error
+}
+
+// Grant is a token and the refresh token to replace it with once it expires.
+type Grant struct {
+	Token        v3.Token
+	TokenValue   string
+	RefreshToken string
+	// ExpiresIn is the time to live of the token in seconds.
+	ExpiresIn int64
+}
+
+// Issuer issues and refreshes the tokens of refresh token families.
+type Issuer struct {
+	store           *store
+	tokenMGR        tokenManager
+	accessTokenTTL  func() string
+	refreshTokenTTL func() string
+}
+
+// NewIssuer returns an Issuer storing the refresh tokens in secrets.
+func NewIssuer(ctx context.Context, mgmt *config.ScaledContext) *Issuer {
+	return &Issuer{
+		store: &store{
+			secrets: mgmt.Wrangler.Core.Secret(),
+			now:     time.Now,
+		},
+		tokenMGR:        tokens.NewManager(ctx, mgmt),
+		accessTokenTTL:  settings.AuthAccessTokenTTLMinutes.Get,
+		refreshTokenTTL: settings.AuthRefreshTokenTTLMinutes.Get,
+	}
+}
+
+// Issue starts a new refresh token family for the user.
+// The kind of the tokens is session for logins, or kubeconfig for kubeconfigs, which can be scoped to a cluster.
+func (i *Issuer) Issue(userID string, userPrincipal v3.Principal, kind, clusterName, description string) (*Grant, error) {
+	if kind == "session" {
+		if err := i.tokenMGR.EnforceSessionLimit(userID); err != nil {
+			return nil, err
+		}
+	}
+	family, err := randomID(16)
+	if err != nil {
+		return nil, err
+	}
+	return i.issue(&refreshToken{
+		Family:        family,
+		ExpiresAt:     i.store.now().Add(minutes(i.refreshTokenTTL())),
+		UserID:        userID,
+		UserPrincipal: userPrincipal,
+		Kind:          kind,
+		ClusterName:   clusterName,
+		Description:   description,
+	})
+}
+
+// Refresh uses up the refresh token and returns a new token and refresh token of its family.
+// Refreshing with a used refresh token revokes the family.
+func (i *Issuer) Refresh(value string) (*Grant, error) {
+	token, err := i.store.get(value)
+	switch {
+	case errors.Is(err, errNotFound), errors.Is(err, errExpired):
+		return nil, ErrInvalidGrant
+	case err != nil:
+		return nil, err
+	}
+
+	if token.Status == statusUsed {
+		i.revoke(token)
+		return nil, errReused
+	}
+	if err := i.store.markUsed(token); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			// The refresh token was used concurrently.
+			i.revoke(token)
+			return nil, errReused
+		}
+		return nil, err
+	}
+
+	return i.issue(token)
+}
+
+func (i *Issuer) issue(family *refreshToken) (*Grant, error) {
+	ttl := minutes(i.accessTokenTTL())
+	if ttl <= 0 {
+		ttl = defaultAccessTokenTTL
+	}
+	token, tokenValue, err := i.tokenMGR.NewFamilyToken(family.Family, family.Kind, family.ClusterName, family.UserID, family.UserPrincipal, ttl.Milliseconds(), family.Description)
+	if err != nil {
+		return nil, fmt.Errorf("creating token of refresh token family %s: %w", family.Family, err)
+	}
+	refreshToken, err := i.store.create(family)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Grant{
+		Token:        token,
+		TokenValue:   tokenValue,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(ttl.Seconds()),
+	}, nil
+}
+
+// RevokeFamily deletes the refresh tokens and tokens of a family, ending the login it was issued for.
+func (i *Issuer) RevokeFamily(family string) error {
+	if err := i.store.deleteFamily(family); err != nil {
+		return fmt.Errorf("deleting the refresh tokens of family %s: %w", family, err)
+	}
+	return i.tokenMGR.RevokeTokenFamily(family)
+}
+
+// revoke deletes the refresh tokens and tokens of the family of a reused refresh token.
+func (i *Issuer) revoke(token *refreshToken) {
+	logrus.Warnf("[refreshtokens] refresh token of user %s reused, revoking its family %s", token.UserID, token.Family)
+	if err := i.store.deleteFamily(token.Family); err != nil {
+		logrus.Errorf("[refreshtokens] failed to delete the refresh tokens of family %s: %v", token.Family, err)
+	}
+	if err := i.tokenMGR.RevokeTokenFamily(token.Family); err != nil {
+		logrus.Errorf("[refreshtokens] failed to revoke the tokens of family %s: %v", token.Family, err)
+	}
+}
+
+type handler struct {
+	issuer *Issuer
+}
+
+// NewHandler returns the handler of the token refresh endpoint.
+func NewHandler(ctx context.Context, mgmt *config.ScaledContext) http.Handler {
+	h := &handler{issuer: NewIssuer(ctx, mgmt)}
+
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(tokenPath).HandlerFunc(h.refresh)
+	return root
+}
+
+// refresh returns a new token and refresh token for a refresh token.
+func (h *handler) refresh(w http.ResponseWriter, r *http.Request) {
+	if r.PostFormValue("grant_type") != GrantType {
+		util.WriteOAuthError(w, http.StatusBadRequest, errUnsupportedGrantType, "")
+		return
+	}
+
+	grant, err := h.issuer.Refresh(r.PostFormValue("refresh_token"))
+	if err != nil {
+		if errors.Is(err, errReused) {
+			util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "refresh token already used, the login was revoked")
+			return
+		}
+		if errors.Is(err, ErrInvalidGrant) {
+			util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "")
+			return
+		}
+		logrus.Errorf("[refreshtokens] failed to refresh token: %v", err)
+		util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+
+	util.WriteOAuthJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  grant.Token.Name + ":" + grant.TokenValue,
+		"token_type":    "Bearer",
+		"expires_in":    grant.ExpiresIn,
+		"refresh_token": grant.RefreshToken,
+	})
+}
+
+func minutes(value string) time.Duration {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(n) * time.Minute
+}
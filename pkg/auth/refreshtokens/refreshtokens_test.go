@@ -0,0 +1,209 @@
+package refreshtokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeTokenManager struct {
+	tokens  map[string]v3.Token
+	revoked []string
+	created int
+}
+
+func (f *fakeTokenManager) NewFamilyToken(family, kind, clusterName, userID string, userPrincipal v3.Principal, ttl int64, description string) (v3.Token, string, error) {
+	f.created++
+	token := v3.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "token-" + strconv.Itoa(f.created),
+			Labels: map[string]string{tokens.TokenKindLabel: kind, tokens.TokenFamilyLabel: family},
+		},
+		UserID:        userID,
+		UserPrincipal: userPrincipal,
+		TTLMillis:     ttl,
+		ClusterName:   clusterName,
+		Description:   description,
+	}
+	f.tokens[token.Name] = token
+	return token, "secretkey", nil
+}
+
+func (f *fakeTokenManager) RevokeTokenFamily(family string) error {
+	f.revoked = append(f.revoked, family)
+	for name, token := range f.tokens {
+		if token.Labels[tokens.TokenFamilyLabel] == family {
+			delete(f.tokens, name)
+		}
+	}
+	return nil
+}
+
+func (f *fakeTokenManager) EnforceSessionLimit(userID string) error {
+	return nil
+}
+
+type testEnv struct {
+	handler  *handler
+	tokenMGR *fakeTokenManager
+	now      time.Time
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	ctrl := gomock.NewController(t)
+	env := &testEnv{
+		tokenMGR: &fakeTokenManager{tokens: map[string]v3.Token{}},
+		now:      time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	env.handler = &handler{
+		issuer: &Issuer{
+			store: &store{
+				secrets: tokens.NewFakeSecrets(ctrl),
+				now:     func() time.Time { return env.now },
+			},
+			tokenMGR:        env.tokenMGR,
+			accessTokenTTL:  func() string { return "15" },
+			refreshTokenTTL: func() string { return "1440" },
+		},
+	}
+	return env
+}
+
+func (e *testEnv) refresh(t *testing.T, refreshToken string) (int, map[string]interface{}) {
+	t.Helper()
+	form := url.Values{"grant_type": {GrantType}, "refresh_token": {refreshToken}}
+	req := httptest.NewRequest(http.MethodPost, tokenPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	e.handler.refresh(rec, req)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec.Code, body
+}
+
+func TestIssue(t *testing.T) {
+	env := newTestEnv(t)
+	principal := v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: "local://u-abcde"}, Provider: "local"}
+
+	grant, err := env.handler.issuer.Issue("u-abcde", principal, "kubeconfig", "c-12345", "Kubeconfig token")
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", grant.Token.Name)
+	assert.Equal(t, "secretkey", grant.TokenValue)
+	assert.EqualValues(t, 15*60, grant.ExpiresIn)
+	assert.EqualValues(t, 15*60*1000, grant.Token.TTLMillis)
+	assert.Equal(t, "c-12345", grant.Token.ClusterName)
+	assert.Equal(t, "kubeconfig", grant.Token.Labels[tokens.TokenKindLabel])
+	assert.Regexp(t, `^[0-9a-f]{10}:[A-Za-z0-9_-]{43}$`, grant.RefreshToken)
+
+	status, body := env.refresh(t, grant.RefreshToken)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "token-2:secretkey", body["access_token"])
+	assert.Equal(t, "Bearer", body["token_type"])
+	assert.EqualValues(t, 15*60, body["expires_in"])
+	assert.NotEqual(t, grant.RefreshToken, body["refresh_token"])
+
+	refreshed := env.tokenMGR.tokens["token-2"]
+	assert.Equal(t, grant.Token.Labels[tokens.TokenFamilyLabel], refreshed.Labels[tokens.TokenFamilyLabel])
+	assert.Equal(t, "u-abcde", refreshed.UserID)
+	assert.Equal(t, principal.Name, refreshed.UserPrincipal.Name)
+	assert.Equal(t, "c-12345", refreshed.ClusterName)
+	assert.Equal(t, "Kubeconfig token", refreshed.Description)
+}
+
+func TestRefreshRotates(t *testing.T) {
+	env := newTestEnv(t)
+	grant, err := env.handler.issuer.Issue("u-abcde", v3.Principal{}, "session", "", "")
+	require.NoError(t, err)
+
+	refreshToken := grant.RefreshToken
+	for i := 0; i < 3; i++ {
+		env.now = env.now.Add(10 * time.Minute)
+		status, body := env.refresh(t, refreshToken)
+		require.Equal(t, http.StatusOK, status)
+		refreshToken = body["refresh_token"].(string)
+	}
+	assert.Len(t, env.tokenMGR.tokens, 4)
+	assert.Empty(t, env.tokenMGR.revoked)
+}
+
+func TestReusedRefreshTokenRevokesFamily(t *testing.T) {
+	env := newTestEnv(t)
+	grant, err := env.handler.issuer.Issue("u-abcde", v3.Principal{}, "session", "", "")
+	require.NoError(t, err)
+	other, err := env.handler.issuer.Issue("u-abcde", v3.Principal{}, "session", "", "")
+	require.NoError(t, err)
+
+	status, body := env.refresh(t, grant.RefreshToken)
+	require.Equal(t, http.StatusOK, status)
+	rotated := body["refresh_token"].(string)
+
+	status, body = env.refresh(t, grant.RefreshToken)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errInvalidGrant, body["error"])
+	assert.Contains(t, body["error_description"], "revoked")
+	assert.Equal(t, []string{grant.Token.Labels[tokens.TokenFamilyLabel]}, env.tokenMGR.revoked)
+
+	// The refresh token rotated before the reuse was revoked too.
+	_, body = env.refresh(t, rotated)
+	assert.Equal(t, errInvalidGrant, body["error"])
+
+	// Other families are untouched.
+	status, _ = env.refresh(t, other.RefreshToken)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, env.tokenMGR.tokens, other.Token.Name)
+	assert.NotContains(t, env.tokenMGR.tokens, grant.Token.Name)
+}
+
+func TestRefreshFamilyExpires(t *testing.T) {
+	env := newTestEnv(t)
+	grant, err := env.handler.issuer.Issue("u-abcde", v3.Principal{}, "session", "", "")
+	require.NoError(t, err)
+
+	env.now = env.now.Add(23 * time.Hour)
+	status, body := env.refresh(t, grant.RefreshToken)
+	require.Equal(t, http.StatusOK, status)
+
+	// Rotating doesn't extend the family.
+	env.now = env.now.Add(time.Hour)
+	_, body = env.refresh(t, body["refresh_token"].(string))
+	assert.Equal(t, errInvalidGrant, body["error"])
+	assert.Empty(t, env.tokenMGR.revoked)
+}
+
+func TestRefreshInvalidRequests(t *testing.T) {
+	env := newTestEnv(t)
+	grant, err := env.handler.issuer.Issue("u-abcde", v3.Principal{}, "session", "", "")
+	require.NoError(t, err)
+	id, _, _ := strings.Cut(grant.RefreshToken, ":")
+
+	req := httptest.NewRequest(http.MethodPost, tokenPath, strings.NewReader(url.Values{"grant_type": {"password"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	env.handler.refresh(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), errUnsupportedGrantType)
+
+	for _, refreshToken := range []string{"", id, id + ":wrong", "other:" + strings.Repeat("a", 43)} {
+		status, body := env.refresh(t, refreshToken)
+		assert.Equal(t, http.StatusBadRequest, status, refreshToken)
+		assert.Equal(t, errInvalidGrant, body["error"], refreshToken)
+	}
+	assert.Empty(t, env.tokenMGR.revoked)
+
+	status, _ := env.refresh(t, grant.RefreshToken)
+	assert.Equal(t, http.StatusOK, status)
+}
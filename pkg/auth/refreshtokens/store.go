@@ -0,0 +1,195 @@
+package refreshtokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	secretPrefix = "refreshtoken-"
+
+	statusActive = "active"
+	statusUsed   = "used"
+
+	refreshSecretKey = "refreshSecretHash"
+	familyKey        = "family"
+	statusKey        = "status"
+	userIDKey        = "userId"
+	userPrincipalKey = "userPrincipal"
+	clusterNameKey   = "clusterName"
+	descriptionKey   = "description"
+)
+
+var (
+	errNotFound = errors.New("refresh token not found")
+	errExpired  = errors.New("refresh token expired")
+)
+
+// refreshToken is an active or used refresh token of a family.
+// It's stored in a secret, which holds a hash of the refresh token rather than the token itself.
+// Used refresh tokens are kept until the family expires, so that their reuse is detected.
+type refreshToken struct {
+	secret *corev1.Secret
+
+	Family        string
+	Status        string
+	ExpiresAt     time.Time
+	UserID        string
+	UserPrincipal v3.Principal
+	Kind          string
+	ClusterName   string
+	Description   string
+}
+
+type store struct {
+	secrets wcorev1.SecretClient
+	now     func() time.Time
+}
+
+// create stores a new active refresh token of the family and returns its value.
+func (s *store) create(token *refreshToken) (string, error) {
+	id, err := randomID(5)
+	if err != nil {
+		return "", err
+	}
+	refreshSecretBytes := make([]byte, 32)
+	if _, err := rand.Read(refreshSecretBytes); err != nil {
+		return "", err
+	}
+	refreshSecret := base64.RawURLEncoding.EncodeToString(refreshSecretBytes)
+	principal, err := json.Marshal(token.UserPrincipal)
+	if err != nil {
+		return "", err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretPrefix + id,
+			Namespace: tokens.SecretNamespace,
+			Labels: map[string]string{
+				tokens.RefreshTokenLabel:       "true",
+				tokens.UserIDLabel:             token.UserID,
+				tokens.RefreshTokenFamilyLabel: token.Family,
+			},
+		},
+		Data: map[string][]byte{
+			refreshSecretKey:                []byte(hash(refreshSecret)),
+			familyKey:                       []byte(token.Family),
+			statusKey:                       []byte(statusActive),
+			tokens.RefreshTokenExpiresAtKey: []byte(token.ExpiresAt.UTC().Format(time.RFC3339)),
+			userIDKey:                       []byte(token.UserID),
+			userPrincipalKey:                principal,
+			tokens.RefreshTokenKindKey:      []byte(token.Kind),
+			clusterNameKey:                  []byte(token.ClusterName),
+			descriptionKey:                  []byte(token.Description),
+		},
+	}
+	if _, err := s.secrets.Create(secret); err != nil {
+		return "", fmt.Errorf("creating refresh token: %w", err)
+	}
+
+	return id + ":" + refreshSecret, nil
+}
+
+// get returns the refresh token with the value.
+func (s *store) get(value string) (*refreshToken, error) {
+	id, refreshSecret, ok := strings.Cut(value, ":")
+	if !ok || id == "" || refreshSecret == "" {
+		return nil, errNotFound
+	}
+	secret, err := s.secrets.Get(tokens.SecretNamespace, secretPrefix+id, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(secret.Data[refreshSecretKey], []byte(hash(refreshSecret))) != 1 {
+		return nil, errNotFound
+	}
+
+	token := &refreshToken{
+		secret:      secret,
+		Family:      string(secret.Data[familyKey]),
+		Status:      string(secret.Data[statusKey]),
+		UserID:      string(secret.Data[userIDKey]),
+		Kind:        string(secret.Data[tokens.RefreshTokenKindKey]),
+		ClusterName: string(secret.Data[clusterNameKey]),
+		Description: string(secret.Data[descriptionKey]),
+	}
+	expiresAt, err := time.Parse(time.RFC3339, string(secret.Data[tokens.RefreshTokenExpiresAtKey]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiry of refresh token %s: %w", secret.Name, err)
+	}
+	token.ExpiresAt = expiresAt
+	if err := json.Unmarshal(secret.Data[userPrincipalKey], &token.UserPrincipal); err != nil {
+		return nil, fmt.Errorf("invalid principal of refresh token %s: %w", secret.Name, err)
+	}
+
+	if !s.now().Before(token.ExpiresAt) {
+		return token, errExpired
+	}
+	return token, nil
+}
+
+// markUsed marks the refresh token as used.
+// Conflicting updates fail, so that a refresh token is only rotated once.
+func (s *store) markUsed(token *refreshToken) error {
+	secret := token.secret.DeepCopy()
+	secret.Data[statusKey] = []byte(statusUsed)
+	updated, err := s.secrets.Update(secret)
+	if err != nil {
+		return err
+	}
+	token.secret = updated
+	token.Status = statusUsed
+	return nil
+}
+
+// deleteFamily deletes the refresh tokens of the family.
+func (s *store) deleteFamily(family string) error {
+	selector := labels.SelectorFromSet(labels.Set{
+		tokens.RefreshTokenLabel:       "true",
+		tokens.RefreshTokenFamilyLabel: family,
+	})
+	secrets, err := s.secrets.List(tokens.SecretNamespace, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		if err := s.secrets.Delete(tokens.SecretNamespace, secret.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// randomID returns a random lowercase hex string, usable in names and label values.
+func randomID(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -12,17 +12,23 @@
 	"github.com/rancher/norman/httperror"
 	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/jwttokens"
+	"github.com/rancher/rancher/pkg/auth/mfa"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
 	"github.com/rancher/rancher/pkg/auth/providers"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/tokens"
 	"github.com/rancher/rancher/pkg/auth/tokens/hashers"
+	"github.com/rancher/rancher/pkg/auth/tracing"
+	"github.com/rancher/rancher/pkg/auth/webauthn"
 	exttokenstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
 	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/steve/pkg/auth"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -63,6 +69,12 @@ type tokenAuthenticator struct {
 	refreshUser         func(userID string, force bool)
 	now                 func() time.Time // Make it easier to test.
 	extTokenStore       *exttokenstore.SystemStore
+	usageRecorder       *tokens.UsageRecorder
+	jwtVerifier         jwtVerifier
+}
+
+type jwtVerifier interface {
+	Verify(value string) (*jwttokens.Claims, error)
 }
 
 // ToAuthMiddleware converts an Authenticator to an auth.Middleware.
@@ -104,6 +116,8 @@ func NewAuthenticator(ctx context.Context, clusterRouter ClusterRouter, mgmtCtx
 		},
 		now:           time.Now,
 		extTokenStore: extTokenStore,
+		usageRecorder: tokens.NewUsageRecorder(mgmtCtx.Wrangler.Core.Secret()),
+		jwtVerifier:   jwttokens.NewIssuer(mgmtCtx.Wrangler.Core.Secret()),
 	}
 }
 
@@ -113,7 +127,12 @@ func tokenKeyIndexer(obj interface{}) ([]string, error) {
 		return []string{}, nil
 	}
 
-	return []string{token.Token}, nil
+	key, err := tokens.StoredKey(token)
+	if err != nil {
+		logrus.Errorf("Failed to index token %s: %v", token.Name, err)
+		return []string{}, nil
+	}
+	return []string{key}, nil
 }
 
 // Authenticate authenticates a request using a request's token.
@@ -130,6 +149,33 @@ func (a *tokenAuthenticator) Authenticate(req *http.Request) (*AuthenticatorResp
 	if cluster != "" && cluster != a.clusterRouter(req) {
 		return nil, errors.Wrapf(ErrMustAuthenticate, "clusterID does not match")
 	}
+	if t, ok := token.(*v3.Token); ok {
+		// Service keys can rotate themselves whatever their scope, so that read-only keys can be rotated on schedule too.
+		if !isServiceKeyRotation(t, req) {
+			if err := checkScope(t.Scope, req, a.clusterRouter(req)); err != nil {
+				return nil, errors.Wrap(ErrMustAuthenticate, err.Error())
+			}
+		}
+		if err := CheckSourceAddress(t.AllowedCIDRs, req); err != nil {
+			return nil, errors.Wrap(ErrMustAuthenticate, err.Error())
+		}
+		// The logins of the users who must enroll in MFA can only be used to enroll, with a TOTP key or a security key.
+		if t.Labels[tokens.TokenKindLabel] == tokens.MFAEnrollmentTokenKind &&
+			req.URL.Path != mfa.BasePath && !strings.HasPrefix(req.URL.Path, webauthn.BasePath+"/") {
+			return nil, errors.Wrap(ErrMustAuthenticate, "MFA enrollment required")
+		}
+		// Every request of an administrator logged in as a user is audited.
+		if t.Labels[tokens.TokenKindLabel] == tokens.LoginAsTokenKind {
+			logrus.WithFields(logrus.Fields{
+				"event":      "LoginAsRequest",
+				"admin":      t.Annotations[tokens.LoginAsByAnnotation],
+				"user":       t.UserID,
+				"token":      t.Name,
+				"method":     req.Method,
+				"requestURI": req.RequestURI,
+			}).Info("loginas: audit")
+		}
+	}
 
 	// If the auth provider is specified make sure it exists and enabled.
 	if token.GetAuthProvider() != "" {
@@ -208,6 +254,10 @@ func (a *tokenAuthenticator) Authenticate(req *http.Request) (*AuthenticatorResp
 
 	logrus.Debugf("Extras returned %v", authResp.Extras)
 
+	if t, ok := token.(*v3.Token); ok && a.usageRecorder != nil {
+		a.usageRecorder.Record(t, newUsageEvent(req, a.clusterRouter(req), a.now()))
+	}
+
 	now := a.now().Truncate(time.Second) // Use the second precision.
 	lastUsed := token.GetLastUsedAt()
 	if lastUsed != nil {
@@ -218,17 +268,30 @@ func (a *tokenAuthenticator) Authenticate(req *http.Request) (*AuthenticatorResp
 	}
 
 	if err := func() error {
-		switch token.(type) {
+		switch t := token.(type) {
 		case *v3.Token:
-			patch, err := json.Marshal([]struct {
+			type patchOp struct {
 				Op    string `json:"op"`
 				Path  string `json:"path"`
 				Value any    `json:"value"`
-			}{{
+			}
+			ops := []patchOp{{
 				Op:    "replace",
 				Path:  "/lastUsedAt",
 				Value: metav1.NewTime(now),
-			}})
+			}}
+			// Requests of login sessions extend their idle timeout, along with lastUsedAt so that it costs no extra write.
+			// Watches are left out, as they're opened by clients in the background rather than by user activity.
+			if isSessionActivity(t, req, a.clusterRouter(req)) {
+				if expiry := tokens.IdleExpiry(now); expiry != nil {
+					ops = append(ops, patchOp{
+						Op:    "add",
+						Path:  "/activityLastSeenAt",
+						Value: expiry,
+					})
+				}
+			}
+			patch, err := json.Marshal(ops)
 			if err != nil {
 				return err
 			}
@@ -249,6 +312,12 @@ func (a *tokenAuthenticator) Authenticate(req *http.Request) (*AuthenticatorResp
 	return authResp, nil
 }
 
+// isSessionActivity tells whether the request is activity of a login session, as opposed to the use of a derived token
+// or a watch.
+func isSessionActivity(token *v3.Token, req *http.Request, cluster string) bool {
+	return !token.IsDerived && newScopedRequest(req, cluster).verb != "watch"
+}
+
 func getUserExtraInfo(token accessor.TokenAccessor, user *v3.User, attribs *v3.UserAttribute) map[string][]string {
 	extraInfo := make(map[string][]string)
 
@@ -284,6 +353,16 @@ func getUserExtraInfo(token accessor.TokenAccessor, user *v3.User, attribs *v3.U
 	return extraInfo
 }
 
+// The types of the tokens of the requests, as counted by the metrics of the validations.
+const (
+	tokenTypeLegacy = "token"
+	tokenTypeExt    = "ext"
+	tokenTypeJWT    = "jwt"
+
+	// tokenCacheName is the name of the cache of the tokens by key, as counted by the metrics of the lookups.
+	tokenCacheName = "tokens"
+)
+
 // TokenFromRequest retrieves and verifies the token from the request.
 func (a *tokenAuthenticator) TokenFromRequest(req *http.Request) (accessor.TokenAccessor, error) {
 	tokenAuthValue := tokens.GetTokenAuthFromRequest(req)
@@ -291,6 +370,24 @@ func (a *tokenAuthenticator) TokenFromRequest(req *http.Request) (accessor.Token
 		return nil, ErrMustAuthenticate
 	}
 
+	tokenType := tokenTypeLegacy
+	if jwttokens.IsJWT(tokenAuthValue) && a.jwtVerifier != nil {
+		tokenType = tokenTypeJWT
+	} else if strings.HasPrefix(tokenAuthValue, "ext/") {
+		tokenType = tokenTypeExt
+	}
+	ctx, span := tracing.StartSpan(req.Context(), "auth.token.validate", attribute.String("auth.token.type", tokenType))
+	token, err := a.tokenFromValue(ctx, tokenAuthValue, tokenType)
+	tracing.End(span, err)
+	metrics.IncTokenValidations(tokenType, err == nil)
+	return token, err
+}
+
+func (a *tokenAuthenticator) tokenFromValue(ctx context.Context, tokenAuthValue, tokenType string) (accessor.TokenAccessor, error) {
+	if tokenType == tokenTypeJWT {
+		return a.tokenFromJWT(ctx, tokenAuthValue)
+	}
+
 	tokenName, tokenKey := tokens.SplitTokenParts(tokenAuthValue)
 	if tokenName == "" || tokenKey == "" {
 		return nil, ErrMustAuthenticate
@@ -302,7 +399,9 @@ func (a *tokenAuthenticator) TokenFromRequest(req *http.Request) (accessor.Token
 		// Ext token detected. Perform roughly the same process as for legacy tokens, using
 		// a different store.  No indexer/cache in play here.
 
+		_, span := tracing.StartSpan(ctx, "auth.token.get", attribute.String("auth.token.store", tokenTypeExt))
 		storedToken, err := a.extTokenStore.Get(extTokenName, "", &metav1.GetOptions{})
+		tracing.End(span, err)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				return nil, ErrMustAuthenticate
@@ -329,10 +428,11 @@ func (a *tokenAuthenticator) TokenFromRequest(req *http.Request) (accessor.Token
 	} else if len(objs) == 0 {
 		lookupUsingClient = true
 	}
+	metrics.IncAuthCacheLookups(tokenCacheName, !lookupUsingClient)
 
 	var storedToken *v3.Token
 	if lookupUsingClient {
-		storedToken, err = a.tokenClient.Get(tokenName, metav1.GetOptions{})
+		storedToken, err = a.getToken(ctx, tokenName)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				return nil, ErrMustAuthenticate
@@ -350,6 +450,45 @@ func (a *tokenAuthenticator) TokenFromRequest(req *http.Request) (accessor.Token
 	return storedToken, nil
 }
 
+// tokenFromJWT retrieves the token a JWT issued by Rancher stands for. The token is looked up for every request, so that
+// deleting, disabling or expiring it revokes the JWT.
+func (a *tokenAuthenticator) tokenFromJWT(ctx context.Context, value string) (accessor.TokenAccessor, error) {
+	claims, err := a.jwtVerifier.Verify(value)
+	if err != nil {
+		return nil, errors.Wrapf(ErrMustAuthenticate, "failed to verify JWT: %v", err)
+	}
+
+	var storedToken *v3.Token
+	if obj, exists, err := a.tokenIndexer.GetByKey(claims.ID); err == nil && exists {
+		storedToken, _ = obj.(*v3.Token)
+	}
+	if storedToken == nil {
+		storedToken, err = a.getToken(ctx, claims.ID)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, ErrMustAuthenticate
+			}
+			return nil, errors.Wrapf(ErrMustAuthenticate, "failed to retrieve auth token, error: %v", err)
+		}
+	}
+
+	if storedToken.UserID != claims.Subject {
+		return nil, errors.Wrapf(ErrMustAuthenticate, "JWT subject does not match token %s", storedToken.Name)
+	}
+	if tokens.IsExpired(*storedToken) || tokens.IsIdleExpired(*storedToken, metav1.NewTime(a.now())) {
+		return nil, errors.Wrapf(ErrMustAuthenticate, "token %s expired", storedToken.Name)
+	}
+	return storedToken, nil
+}
+
+// getToken gets the token from the API server, when it isn't in the cache.
+func (a *tokenAuthenticator) getToken(ctx context.Context, name string) (*v3.Token, error) {
+	_, span := tracing.StartSpan(ctx, "auth.token.get", attribute.String("auth.token.store", tokenTypeLegacy))
+	token, err := a.tokenClient.Get(name, metav1.GetOptions{})
+	tracing.End(span, err)
+	return token, err
+}
+
 // Given a stored token with hashed key, check if the provided (unhashed) tokenKey matches and is valid
 func extVerifyToken(storedToken *ext.Token, tokenName, tokenKey string) (int, error) {
 	invalidAuthTokenErr := errors.New("invalid token")
@@ -20,6 +20,7 @@
 	exttokenstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	mgmtFakes "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/wrangler/v3/pkg/generic/fake"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -300,6 +301,51 @@ func TestTokenAuthenticatorAuthenticate(t *testing.T) {
 		require.NotEmpty(t, patchData)
 	})
 
+	t.Run("session activity extends the idle timeout", func(t *testing.T) {
+		oldTokenLastUsedAt := token.LastUsedAt
+		defer func() {
+			token.LastUsedAt = oldTokenLastUsedAt
+			token.IsDerived = false
+		}()
+		lastUsedAt := metav1.NewTime(now.Add(-time.Second).Truncate(time.Second))
+		token.LastUsedAt = &lastUsedAt
+
+		type patchOp struct {
+			Op    string      `json:"op"`
+			Path  string      `json:"path"`
+			Value metav1.Time `json:"value"`
+		}
+		authenticate := func(req *http.Request) []patchOp {
+			patchData = nil
+			userRefresher.reset()
+			resp, err := authenticator.Authenticate(req)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			var ops []patchOp
+			require.NoError(t, json.Unmarshal(patchData, &ops))
+			return ops
+		}
+
+		ops := authenticate(req)
+		require.Len(t, ops, 2)
+		assert.Equal(t, "/activityLastSeenAt", ops[1].Path)
+		idleTTL := time.Duration(settings.AuthUserSessionIdleTTLMinutes.GetInt()) * time.Minute
+		assert.True(t, ops[1].Value.Time.Equal(now.Truncate(time.Second).Add(idleTTL)))
+
+		// Watches aren't user activity.
+		watchReq := httptest.NewRequest(http.MethodGet, "/v1/namespaces?watch=true", nil)
+		watchReq.Header.Set("Authorization", "Bearer "+token.Name+":"+token.Token)
+		ops = authenticate(watchReq)
+		require.Len(t, ops, 1)
+		assert.Equal(t, "/lastUsedAt", ops[0].Path)
+
+		// Derived tokens have no idle timeout.
+		token.IsDerived = true
+		ops = authenticate(req)
+		require.Len(t, ops, 1)
+		assert.Equal(t, "/lastUsedAt", ops[0].Path)
+	})
+
 	t.Run("error updating lastUsedAt doesn't fail the request", func(t *testing.T) {
 		oldTokenLastUsedAt := token.LastUsedAt
 		defer func() {
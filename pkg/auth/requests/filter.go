@@ -44,6 +44,7 @@ func (h authHeaderHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 		// If the request is not authenticated as a service account,
 		// we need to set impersonation headers.
 		req.Header.Set("Impersonate-User", userInfo.GetName())
+		req.Header.Del("Impersonate-Uid")
 		req.Header.Del("Impersonate-Group")
 		for _, group := range userInfo.GetGroups() {
 			req.Header.Add("Impersonate-Group", group)
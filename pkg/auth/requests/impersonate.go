@@ -53,18 +53,31 @@ func (i *ImpersonatingAuth) ImpersonationMiddleware(next http.Handler) http.Hand
 		if ok {
 			auditUser.RequestUser = reqUser
 			auditUser.RequestGroups = reqGroup
+			auditUser.RequestExtra = reqExtras
 		}
 
 		// If there is an impersonate header, the incoming request is attempting to
 		// impersonate a different user, verify the token user is authz to impersonate
 		if i.sar != nil && reqUser != "" && reqUser != user {
+			imp := &impersonation{
+				user:      user,
+				groups:    groups,
+				reqUser:   reqUser,
+				reqGroups: reqGroup,
+				reqExtras: reqExtras,
+			}
+			deny := func(err error) {
+				imp.audit(req, err)
+				util.WriteError(rw, http.StatusForbidden, err)
+			}
+
 			if strings.HasPrefix(reqUser, serviceaccount.ServiceAccountUsernamePrefix) {
 				canDo, err := i.sar.UserCanImpersonateServiceAccount(req, user, reqUser)
 				if err != nil {
-					util.WriteError(rw, http.StatusForbidden, fmt.Errorf("error checking if user can impersonate service account: %w", err))
+					deny(fmt.Errorf("error checking if user can impersonate service account: %w", err))
 					return
 				} else if !canDo {
-					util.WriteError(rw, http.StatusForbidden, fmt.Errorf("not allowed to impersonate service account"))
+					deny(fmt.Errorf("not allowed to impersonate service account"))
 					return
 				}
 				// add impersonated SA to context
@@ -72,10 +85,10 @@ func (i *ImpersonatingAuth) ImpersonationMiddleware(next http.Handler) http.Hand
 			} else {
 				canDo, err := i.sar.UserCanImpersonateUser(req, user, reqUser)
 				if err != nil {
-					util.WriteError(rw, http.StatusForbidden, fmt.Errorf("error checking if user can impersonate user: %w", err))
+					deny(fmt.Errorf("error checking if user can impersonate user: %w", err))
 					return
 				} else if !canDo {
-					util.WriteError(rw, http.StatusForbidden, fmt.Errorf("not allowed to impersonate user"))
+					deny(fmt.Errorf("not allowed to impersonate user"))
 					return
 				}
 
@@ -86,10 +99,10 @@ func (i *ImpersonatingAuth) ImpersonationMiddleware(next http.Handler) http.Hand
 					}
 					canDo, err := i.sar.UserCanImpersonateGroup(req, user, g)
 					if err != nil {
-						util.WriteError(rw, http.StatusForbidden, fmt.Errorf("error checking if user can impersonate group: %w", err))
+						deny(fmt.Errorf("error checking if user can impersonate group: %w", err))
 						return
 					} else if !canDo {
-						util.WriteError(rw, http.StatusForbidden, fmt.Errorf("not allowed to impersonate group"))
+						deny(fmt.Errorf("not allowed to impersonate group"))
 						return
 					}
 				}
@@ -97,10 +110,10 @@ func (i *ImpersonatingAuth) ImpersonationMiddleware(next http.Handler) http.Hand
 				if len(reqExtras) > 0 {
 					canDo, err := i.sar.UserCanImpersonateExtras(req, user, reqExtras)
 					if err != nil {
-						util.WriteError(rw, http.StatusForbidden, fmt.Errorf("error checking if user can impersonate extras: %w", err))
+						deny(fmt.Errorf("error checking if user can impersonate extras: %w", err))
 						return
 					} else if !canDo {
-						util.WriteError(rw, http.StatusForbidden, fmt.Errorf("not allowed to impersonate extras"))
+						deny(fmt.Errorf("not allowed to impersonate extras"))
 						return
 					}
 
@@ -109,16 +122,15 @@ func (i *ImpersonatingAuth) ImpersonationMiddleware(next http.Handler) http.Hand
 					case 1:
 						token, err := i.extTokenStore.Fetch(requestTokenID[0])
 						if err != nil {
-							util.WriteError(rw, http.StatusForbidden, fmt.Errorf("error getting request token: %w", err))
+							deny(fmt.Errorf("error getting request token: %w", err))
 							return
 						}
 						if token.GetUserID() != reqUser {
-							util.WriteError(rw, http.StatusForbidden,
-								fmt.Errorf("request token user does not match impersonation user"))
+							deny(fmt.Errorf("request token user does not match impersonation user"))
 							return
 						}
 					default:
-						util.WriteError(rw, http.StatusForbidden, fmt.Errorf("multiple requesttokenid values"))
+						deny(fmt.Errorf("multiple requesttokenid values"))
 						return
 					}
 				}
@@ -132,6 +144,12 @@ func (i *ImpersonatingAuth) ImpersonationMiddleware(next http.Handler) http.Hand
 				}
 				*req = *req.WithContext(request.WithUser(req.Context(), userInfo))
 			}
+
+			imp.audit(req, nil)
+			if ok {
+				auditUser.Impersonator = user
+				auditUser.ImpersonatorGroups = groups
+			}
 		}
 
 		next.ServeHTTP(rw, req)
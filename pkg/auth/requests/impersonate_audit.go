@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	impersonationAllowedEvent = "ImpersonationAllowed"
+	impersonationDeniedEvent  = "ImpersonationDenied"
+)
+
+// impersonation pairs the authenticated user of a request with the identity it impersonates.
+type impersonation struct {
+	user      string
+	groups    []string
+	reqUser   string
+	reqGroups []string
+	reqExtras map[string][]string
+}
+
+// audit logs an event for the impersonated request. A nil err means the impersonation was allowed.
+func (i *impersonation) audit(req *http.Request, err error) {
+	fields := logrus.Fields{
+		"event":              impersonationAllowedEvent,
+		"user":               i.user,
+		"groups":             i.groups,
+		"impersonatedUser":   i.reqUser,
+		"impersonatedGroups": i.reqGroups,
+		"impersonatedExtras": i.reqExtras,
+		"method":             req.Method,
+		"requestURI":         req.RequestURI,
+	}
+	if err != nil {
+		fields["event"] = impersonationDeniedEvent
+		fields["reason"] = err.Error()
+	}
+
+	logrus.WithFields(fields).Info("impersonation: audit")
+}
@@ -15,6 +15,7 @@
 	"github.com/rancher/rancher/pkg/auth/requests/sar"
 	exttokenstore "github.com/rancher/rancher/pkg/ext/stores/tokens"
 	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -679,6 +680,73 @@ func TestAuthenticateImpersonation(t *testing.T) {
 	}
 }
 
+func TestImpersonationAudit(t *testing.T) {
+	hook := logrusTest.NewGlobal()
+	defer hook.Reset()
+
+	ctrl := gomock.NewController(t)
+	userInfo := &user.DefaultInfo{
+		Name:   "user",
+		UID:    "user",
+		Groups: []string{"system:authenticated"},
+	}
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/v3/clusters", nil)
+		req.Header.Set("Impersonate-User", "impUser")
+		req.Header.Set("Impersonate-Group", "impGroup")
+		req.Header.Set("Impersonate-Extra-foo", "bar")
+		return req.WithContext(request.WithUser(req.Context(), userInfo))
+	}
+
+	tests := []struct {
+		desc       string
+		canDo      bool
+		wantEvent  string
+		wantReason string
+	}{
+		{
+			desc:      "allowed",
+			canDo:     true,
+			wantEvent: impersonationAllowedEvent,
+		},
+		{
+			desc:       "denied",
+			wantEvent:  impersonationDeniedEvent,
+			wantReason: "not allowed to impersonate extras",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			hook.Reset()
+
+			req := newRequest()
+			mock := mocks.NewMockSubjectAccessReview(ctrl)
+			mock.EXPECT().UserCanImpersonateUser(req, "user", "impUser").Return(true, nil)
+			mock.EXPECT().UserCanImpersonateGroup(req, "user", "impGroup").Return(true, nil)
+			mock.EXPECT().UserCanImpersonateExtras(req, "user", map[string][]string{"foo": {"bar"}}).Return(test.canDo, nil)
+
+			ia := &ImpersonatingAuth{sar: mock}
+			ia.ImpersonationMiddleware(&mockHandler{}).ServeHTTP(httptest.NewRecorder(), req)
+
+			entry := hook.LastEntry()
+			require.NotNil(t, entry)
+			assert.Equal(t, test.wantEvent, entry.Data["event"])
+			assert.Equal(t, "user", entry.Data["user"])
+			assert.Equal(t, []string{"system:authenticated"}, entry.Data["groups"])
+			assert.Equal(t, "impUser", entry.Data["impersonatedUser"])
+			assert.Equal(t, []string{"impGroup"}, entry.Data["impersonatedGroups"])
+			assert.Equal(t, map[string][]string{"foo": {"bar"}}, entry.Data["impersonatedExtras"])
+			assert.Equal(t, http.MethodGet, entry.Data["method"])
+			assert.Equal(t, "/v3/clusters", entry.Data["requestURI"])
+			if test.wantReason != "" {
+				assert.Equal(t, test.wantReason, entry.Data["reason"])
+			} else {
+				assert.NotContains(t, entry.Data, "reason")
+			}
+		})
+	}
+}
+
 type mockHandler struct {
 	serveHTTPWasCalled bool
 }
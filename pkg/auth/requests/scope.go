@@ -0,0 +1,172 @@
+package requests
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/servicekeys"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	localCluster       = "local"
+	managementAPIGroup = "management.cattle.io"
+)
+
+var (
+	readOnlyVerbs = sets.New("get", "list", "watch")
+
+	// discoveryTypes are the types of the Rancher and Steve APIs a token scoped to clusters or projects can read, though
+	// they aren't in a cluster or project: the schemas, and the clusters and projects to find those allowed in.
+	discoveryTypes = sets.New("schemas", "clusters", "projects", "management.cattle.io.clusters", "management.cattle.io.projects")
+
+	kubeRequestInfoFactory = &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("apis", "api"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}
+)
+
+// scopedRequest is what a request accesses, as far as token scopes are concerned.
+type scopedRequest struct {
+	cluster  string
+	project  string
+	apiGroup string
+	verb     string
+	// discovery is set for the requests to the root or to the discovery types of the Rancher and Steve APIs.
+	discovery bool
+}
+
+// checkScope returns an error if the scope of a token doesn't allow the request. A token scoped to clusters or projects
+// is only allowed the requests in those, and the reads of the discovery types.
+func checkScope(scope *v3.TokenScope, req *http.Request, cluster string) error {
+	if scope == nil {
+		return nil
+	}
+	r := newScopedRequest(req, cluster)
+
+	if len(scope.Clusters) > 0 || len(scope.Projects) > 0 {
+		switch {
+		case r.project != "":
+			projectCluster, _, _ := strings.Cut(r.project, ":")
+			if !slices.Contains(scope.Projects, r.project) && !slices.Contains(scope.Clusters, projectCluster) {
+				return fmt.Errorf("token scope does not allow project %s", r.project)
+			}
+		case r.cluster != "":
+			if !slices.Contains(scope.Clusters, r.cluster) {
+				return fmt.Errorf("token scope does not allow cluster %s", r.cluster)
+			}
+		case !r.discovery || !readOnlyVerbs.Has(r.verb):
+			return fmt.Errorf("token scope does not allow requests outside of its clusters and projects")
+		}
+	}
+	if len(scope.APIGroups) > 0 && !slices.Contains(scope.APIGroups, "*") && !slices.Contains(scope.APIGroups, r.apiGroup) {
+		return fmt.Errorf("token scope does not allow API group %q", r.apiGroup)
+	}
+	if len(scope.Verbs) > 0 && !slices.Contains(scope.Verbs, "*") && !slices.Contains(scope.Verbs, r.verb) {
+		return fmt.Errorf("token scope does not allow verb %s", r.verb)
+	}
+	if scope.ReadOnly && !readOnlyVerbs.Has(r.verb) {
+		return fmt.Errorf("token scope is read-only, verb %s is not allowed", r.verb)
+	}
+	return nil
+}
+
+// isServiceKeyRotation tells whether the request is a service key rotating itself.
+func isServiceKeyRotation(token *v3.Token, req *http.Request) bool {
+	return token.Labels[tokens.TokenKindLabel] == tokens.ServiceKeyTokenKind &&
+		req.Method == http.MethodPost && req.URL.Path == servicekeys.BasePath && req.URL.Query().Get("action") == servicekeys.RotateAction
+}
+
+// newScopedRequest tells the cluster, project, API group and verb of a request to the Kubernetes API of a cluster
+// (/k8s/clusters/<cluster>) or of the local cluster, to the Rancher API (/v3) or to the Steve API (/v1).
+// The Steve API serves the resources of the local cluster, except for the management.cattle.io group,
+// which is the Rancher API like /v3.
+func newScopedRequest(req *http.Request, cluster string) scopedRequest {
+	r := scopedRequest{cluster: cluster}
+	path := req.URL.Path
+	if cluster != "" {
+		path = strings.TrimPrefix(path, "/k8s/clusters/"+cluster)
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var resource string
+	if len(parts) > 1 {
+		resource = parts[1]
+	}
+
+	switch parts[0] {
+	case "api", "apis":
+		if r.cluster == "" {
+			r.cluster = localCluster
+		}
+		kubeReq := req.Clone(req.Context())
+		kubeReq.URL.Path = path
+		if info, err := kubeRequestInfoFactory.NewRequestInfo(kubeReq); err == nil {
+			r.apiGroup = info.APIGroup
+			r.verb = info.Verb
+			return r
+		}
+	case "v3":
+		r.apiGroup = managementAPIGroup
+		// /v3/projects/<project> and /v3/project/<project>/<type> name a project.
+		if len(parts) > 2 && (resource == "projects" || resource == "project") {
+			r.project = parts[2]
+		}
+		// Collections are /v3/<type>, /v3/cluster/<cluster>/<type> and /v3/project/<project>/<type>.
+		collection := len(parts) == 2
+		if resource == "cluster" || resource == "project" {
+			collection = len(parts) == 4
+		}
+		r.verb = restVerb(req, collection, resource == "subscribe")
+		r.discovery = len(parts) == 1 || discoveryTypes.Has(resource)
+		return r
+	case "v1":
+		// Steve types are <group>.<resource>, or <resource> for the core group.
+		if i := strings.LastIndex(resource, "."); i > 0 {
+			r.apiGroup = resource[:i]
+		}
+		r.discovery = len(parts) == 1 || discoveryTypes.Has(resource)
+		switch {
+		case resource == "management.cattle.io.clusters" && len(parts) > 2:
+			r.cluster = parts[2]
+		case r.apiGroup != managementAPIGroup && resource != "subscribe" && !r.discovery:
+			r.cluster = localCluster
+		}
+		r.verb = restVerb(req, len(parts) == 2, resource == "subscribe")
+		return r
+	}
+
+	r.verb = restVerb(req, false, false)
+	return r
+}
+
+// restVerb returns the verb of a request to the Rancher or Steve API.
+func restVerb(req *http.Request, collection, subscribe bool) string {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		if subscribe || req.URL.Query().Get("watch") == "true" {
+			return "watch"
+		}
+		if collection {
+			return "list"
+		}
+		return "get"
+	case http.MethodPost:
+		// Actions change the resource they're run on.
+		if req.URL.Query().Get("action") != "" {
+			return "update"
+		}
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	}
+	return strings.ToLower(req.Method)
+}
@@ -0,0 +1,211 @@
+package requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/clusterrouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScopedRequest(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   scopedRequest
+	}{
+		{http.MethodGet, "/v3/clusters", scopedRequest{apiGroup: "management.cattle.io", verb: "list", discovery: true}},
+		{http.MethodGet, "/v3/clusters/c-abcde", scopedRequest{cluster: "c-abcde", apiGroup: "management.cattle.io", verb: "get", discovery: true}},
+		{http.MethodDelete, "/v3/clusters/c-abcde", scopedRequest{cluster: "c-abcde", apiGroup: "management.cattle.io", verb: "delete", discovery: true}},
+		{http.MethodPost, "/v3/clusters/c-abcde?action=generateKubeconfig", scopedRequest{cluster: "c-abcde", apiGroup: "management.cattle.io", verb: "update", discovery: true}},
+		{http.MethodGet, "/v3/projects/c-abcde:p-fghij", scopedRequest{project: "c-abcde:p-fghij", apiGroup: "management.cattle.io", verb: "get", discovery: true}},
+		{http.MethodPost, "/v3/project/c-abcde:p-fghij/apps", scopedRequest{project: "c-abcde:p-fghij", apiGroup: "management.cattle.io", verb: "create"}},
+		{http.MethodGet, "/v3/subscribe", scopedRequest{apiGroup: "management.cattle.io", verb: "watch"}},
+		{http.MethodGet, "/v3", scopedRequest{apiGroup: "management.cattle.io", verb: "get", discovery: true}},
+		{http.MethodGet, "/v3/schemas/cluster", scopedRequest{apiGroup: "management.cattle.io", verb: "get", discovery: true}},
+		{http.MethodPost, "/v3/users", scopedRequest{apiGroup: "management.cattle.io", verb: "create"}},
+		{http.MethodGet, "/k8s/clusters/c-abcde/api/v1/namespaces/default/pods", scopedRequest{cluster: "c-abcde", verb: "list"}},
+		{http.MethodGet, "/k8s/clusters/c-abcde/apis/apps/v1/namespaces/default/deployments/web", scopedRequest{cluster: "c-abcde", apiGroup: "apps", verb: "get"}},
+		{http.MethodGet, "/k8s/clusters/c-abcde/api/v1/pods?watch=true", scopedRequest{cluster: "c-abcde", verb: "watch"}},
+		{http.MethodPatch, "/apis/management.cattle.io/v3/clusters/c-abcde", scopedRequest{cluster: "local", apiGroup: "management.cattle.io", verb: "patch"}},
+		{http.MethodGet, "/v1/management.cattle.io.clusters", scopedRequest{apiGroup: "management.cattle.io", verb: "list", discovery: true}},
+		{http.MethodPut, "/v1/management.cattle.io.clusters/c-abcde", scopedRequest{cluster: "c-abcde", apiGroup: "management.cattle.io", verb: "update", discovery: true}},
+		{http.MethodGet, "/v1/apps.deployments/default/web", scopedRequest{cluster: "local", apiGroup: "apps", verb: "get"}},
+		{http.MethodDelete, "/v1/pods/default/web", scopedRequest{cluster: "local", verb: "delete"}},
+		{http.MethodGet, "/v1/schemas/pod", scopedRequest{verb: "get", discovery: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			assert.Equal(t, tt.want, newScopedRequest(req, clusterrouter.GetClusterID(req)))
+		})
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   *v3.TokenScope
+		method  string
+		path    string
+		wantErr string
+	}{
+		{
+			name:   "no scope",
+			method: http.MethodDelete,
+			path:   "/v3/clusters/c-abcde",
+		},
+		{
+			name:   "list clusters",
+			scope:  &v3.TokenScope{APIGroups: []string{"management.cattle.io"}, Verbs: []string{"get", "list"}},
+			method: http.MethodGet,
+			path:   "/v3/clusters",
+		},
+		{
+			name:    "delete cluster",
+			scope:   &v3.TokenScope{APIGroups: []string{"management.cattle.io"}, Verbs: []string{"get", "list"}},
+			method:  http.MethodDelete,
+			path:    "/v3/clusters/c-abcde",
+			wantErr: "does not allow verb delete",
+		},
+		{
+			name:    "other API group",
+			scope:   &v3.TokenScope{APIGroups: []string{"management.cattle.io"}},
+			method:  http.MethodGet,
+			path:    "/k8s/clusters/c-abcde/apis/apps/v1/deployments",
+			wantErr: `does not allow API group "apps"`,
+		},
+		{
+			name:   "any API group",
+			scope:  &v3.TokenScope{APIGroups: []string{"*"}, Verbs: []string{"*"}},
+			method: http.MethodPost,
+			path:   "/k8s/clusters/c-abcde/apis/apps/v1/namespaces/default/deployments",
+		},
+		{
+			name:   "scoped cluster",
+			scope:  &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method: http.MethodGet,
+			path:   "/k8s/clusters/c-abcde/api/v1/pods",
+		},
+		{
+			name:    "other cluster",
+			scope:   &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method:  http.MethodGet,
+			path:    "/k8s/clusters/c-fghij/api/v1/pods",
+			wantErr: "does not allow cluster c-fghij",
+		},
+		{
+			name:    "local cluster",
+			scope:   &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method:  http.MethodGet,
+			path:    "/v1/secrets",
+			wantErr: "does not allow cluster local",
+		},
+		{
+			name:   "project of a scoped cluster",
+			scope:  &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method: http.MethodGet,
+			path:   "/v3/projects/c-abcde:p-fghij",
+		},
+		{
+			name:   "scoped project",
+			scope:  &v3.TokenScope{Projects: []string{"c-abcde:p-fghij"}},
+			method: http.MethodGet,
+			path:   "/v3/project/c-abcde:p-fghij/apps",
+		},
+		{
+			name:    "other project",
+			scope:   &v3.TokenScope{Projects: []string{"c-abcde:p-fghij"}},
+			method:  http.MethodGet,
+			path:    "/v3/projects/c-abcde:p-klmno",
+			wantErr: "does not allow project c-abcde:p-klmno",
+		},
+		{
+			name:    "cluster of a scoped project",
+			scope:   &v3.TokenScope{Projects: []string{"c-abcde:p-fghij"}},
+			method:  http.MethodGet,
+			path:    "/k8s/clusters/c-abcde/api/v1/secrets",
+			wantErr: "does not allow cluster c-abcde",
+		},
+		{
+			name:   "list clusters with a scoped cluster",
+			scope:  &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method: http.MethodGet,
+			path:   "/v3/clusters",
+		},
+		{
+			name:   "schemas with a scoped project",
+			scope:  &v3.TokenScope{Projects: []string{"c-abcde:p-fghij"}},
+			method: http.MethodGet,
+			path:   "/v1/schemas",
+		},
+		{
+			name:    "global /v3 read with a scoped cluster",
+			scope:   &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method:  http.MethodGet,
+			path:    "/v3/users",
+			wantErr: "outside of its clusters and projects",
+		},
+		{
+			name:    "global /v3 write with a scoped cluster",
+			scope:   &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method:  http.MethodPost,
+			path:    "/v3/globalrolebindings",
+			wantErr: "outside of its clusters and projects",
+		},
+		{
+			name:    "cluster creation with a scoped cluster",
+			scope:   &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method:  http.MethodPost,
+			path:    "/v3/clusters",
+			wantErr: "outside of its clusters and projects",
+		},
+		{
+			name:    "global /v1 write with a scoped project",
+			scope:   &v3.TokenScope{Projects: []string{"c-abcde:p-fghij"}},
+			method:  http.MethodPut,
+			path:    "/v1/management.cattle.io.users/u-abcde",
+			wantErr: "outside of its clusters and projects",
+		},
+		{
+			name:    "/v1 subscribe with a scoped cluster",
+			scope:   &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method:  http.MethodGet,
+			path:    "/v1/subscribe",
+			wantErr: "outside of its clusters and projects",
+		},
+		{
+			name:    "other endpoint with a scoped cluster",
+			scope:   &v3.TokenScope{Clusters: []string{"c-abcde"}},
+			method:  http.MethodPost,
+			path:    "/v1-service-keys",
+			wantErr: "outside of its clusters and projects",
+		},
+		{
+			name:   "read-only get",
+			scope:  &v3.TokenScope{ReadOnly: true},
+			method: http.MethodGet,
+			path:   "/v1/management.cattle.io.clusters/c-abcde",
+		},
+		{
+			name:    "read-only update",
+			scope:   &v3.TokenScope{ReadOnly: true},
+			method:  http.MethodPut,
+			path:    "/v3/clusters/c-abcde",
+			wantErr: "read-only",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			err := checkScope(tt.scope, req, clusterrouter.GetClusterID(req))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package requests
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/auth/util"
+)
+
+// CheckSourceAddress returns an error if the client address of the request isn't in the allowed CIDRs of a token.
+func CheckSourceAddress(allowedCIDRs []string, req *http.Request) error {
+	if len(allowedCIDRs) == 0 {
+		return nil
+	}
+
+	ip := ClientIP(req)
+	if ip == nil {
+		return fmt.Errorf("unable to determine the client address")
+	}
+	for _, network := range util.ParseCIDRs(allowedCIDRs) {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("token is not allowed from %s", ip)
+}
+
+// ClientIP returns the address of the client of the request, as forwarded by the proxies of auth-trusted-proxy-cidrs.
+// It returns nil if the address can't be determined.
+func ClientIP(req *http.Request) net.IP {
+	return clientIP(req, util.TrustedProxies())
+}
+
+// clientIP returns the address of the client of the request. The X-Forwarded-For header is only honored for requests
+// from trusted proxies: it's read from the last address, added by the proxy in front of Rancher, back to the first
+// address not of a trusted proxy, as the addresses before that can be set by the client.
+func clientIP(req *http.Request, trustedProxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+
+	var forwardedFor []string
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		forwardedFor = append(forwardedFor, strings.Split(header, ",")...)
+	}
+	for i := len(forwardedFor) - 1; i >= 0 && util.IsTrusted(ip, trustedProxies); i-- {
+		forwarded := net.ParseIP(strings.TrimSpace(forwardedFor[i]))
+		if forwarded == nil {
+			return nil
+		}
+		ip = forwarded
+	}
+	return ip
+}
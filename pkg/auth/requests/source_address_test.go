@@ -0,0 +1,85 @@
+package requests
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := util.ParseCIDRs([]string{"10.0.0.0/8", "192.168.1.1"})
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{
+			name:       "direct",
+			remoteAddr: "203.0.113.5:41000",
+			want:       "203.0.113.5",
+		},
+		{
+			name:         "forwarded by untrusted client",
+			remoteAddr:   "203.0.113.5:41000",
+			forwardedFor: []string{"198.51.100.7"},
+			want:         "203.0.113.5",
+		},
+		{
+			name:         "forwarded by trusted proxy",
+			remoteAddr:   "10.0.0.2:41000",
+			forwardedFor: []string{"198.51.100.7"},
+			want:         "198.51.100.7",
+		},
+		{
+			name:         "spoofed by the client",
+			remoteAddr:   "10.0.0.2:41000",
+			forwardedFor: []string{"10.1.2.3, 198.51.100.7"},
+			want:         "198.51.100.7",
+		},
+		{
+			name:         "chain of trusted proxies",
+			remoteAddr:   "10.0.0.2:41000",
+			forwardedFor: []string{"198.51.100.7", "192.168.1.1"},
+			want:         "198.51.100.7",
+		},
+		{
+			name:         "invalid forwarded address",
+			remoteAddr:   "10.0.0.2:41000",
+			forwardedFor: []string{"unknown"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v3/clusters", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			ip := clientIP(req, trusted)
+			if tt.want == "" {
+				assert.Nil(t, ip)
+				return
+			}
+			assert.Equal(t, tt.want, ip.String())
+		})
+	}
+}
+
+func TestCheckSourceAddress(t *testing.T) {
+	require.NoError(t, settings.AuthTrustedProxyCIDRs.Set("10.0.0.0/8"))
+	defer settings.AuthTrustedProxyCIDRs.Set("")
+
+	req := httptest.NewRequest("GET", "/v3/clusters", nil)
+	req.RemoteAddr = "10.0.0.2:41000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+
+	assert.NoError(t, CheckSourceAddress(nil, req))
+	assert.NoError(t, CheckSourceAddress([]string{"198.51.100.0/24"}, req))
+	assert.ErrorContains(t, CheckSourceAddress([]string{"203.0.113.0/24", "10.0.0.0/8"}, req), "not allowed from 198.51.100.7")
+}
@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newUsageEvent returns the usage event of a token for the request.
+func newUsageEvent(req *http.Request, cluster string, now time.Time) tokens.UsageEvent {
+	event := tokens.UsageEvent{
+		Time:          metav1.NewTime(now),
+		UserAgent:     req.UserAgent(),
+		EndpointClass: endpointClass(req, cluster),
+	}
+	if ip := ClientIP(req); ip != nil {
+		event.SourceIP = ip.String()
+	}
+	return event
+}
+
+// endpointClass tells the API a request is to, kubernetes, rancher (/v3), steve (/v1) or other, and its verb,
+// like kubernetes:list.
+func endpointClass(req *http.Request, cluster string) string {
+	path := req.URL.Path
+	if cluster != "" {
+		path = strings.TrimPrefix(path, "/k8s/clusters/"+cluster)
+	}
+	api := "other"
+	switch prefix, _, _ := strings.Cut(strings.Trim(path, "/"), "/"); prefix {
+	case "api", "apis":
+		api = "kubernetes"
+	case "v3":
+		api = "rancher"
+	case "v1":
+		api = "steve"
+	}
+	return api + ":" + newScopedRequest(req, cluster).verb
+}
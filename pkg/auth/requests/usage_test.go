@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointClass(t *testing.T) {
+	tests := []struct {
+		method  string
+		target  string
+		cluster string
+		want    string
+	}{
+		{method: "GET", target: "/k8s/clusters/c-abcde/api/v1/namespaces/default/pods", cluster: "c-abcde", want: "kubernetes:list"},
+		{method: "DELETE", target: "/apis/apps/v1/namespaces/default/deployments/web", want: "kubernetes:delete"},
+		{method: "GET", target: "/v3/clusters/c-abcde", want: "rancher:get"},
+		{method: "POST", target: "/v1/management.cattle.io.projects", want: "steve:create"},
+		{method: "GET", target: "/meta/proxy/example.com", want: "other:get"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			assert.Equal(t, tt.want, endpointClass(httptest.NewRequest(tt.method, tt.target, nil), tt.cluster))
+		})
+	}
+}
+
+func TestNewUsageEvent(t *testing.T) {
+	req := httptest.NewRequest("GET", "/v3/tokens", nil)
+	req.RemoteAddr = "203.0.113.5:41000"
+	req.Header.Set("User-Agent", "rancher-cli")
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := newUsageEvent(req, "", now)
+	assert.Equal(t, "203.0.113.5", event.SourceIP)
+	assert.Equal(t, "rancher-cli", event.UserAgent)
+	assert.Equal(t, "rancher:list", event.EndpointClass)
+	assert.True(t, event.Time.Time.Equal(now))
+}
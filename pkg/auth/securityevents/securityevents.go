@@ -0,0 +1,229 @@
+// Package securityevents records the high-signal security findings as SecurityEvents, so that the alerting pipelines,
+// like alert managers or Fleet, can watch them and route them: the bindings to administrator global roles, the changes
+// of auth configs, the disabling of second factors, the activations of break glass accounts and the creations of
+// long-lived tokens. Each event is also logged, and exported to the audit sinks, so that it's kept even when it can't
+// be stored. The SecurityEvents older than the security-event-retention-hours setting are removed.
+package securityevents
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/auditsinks"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// The types of the events.
+const (
+	AdminBindingCreated   = "AdminBindingCreated"
+	AuthConfigChanged     = "AuthConfigChanged"
+	MFADisabled           = "MFADisabled"
+	BreakGlassUsed        = "BreakGlassUsed"
+	LongLivedTokenCreated = "LongLivedTokenCreated"
+)
+
+// severities are the severities of the types of events.
+var severities = map[string]string{
+	AdminBindingCreated:   v3.SecurityEventSeverityHigh,
+	AuthConfigChanged:     v3.SecurityEventSeverityHigh,
+	MFADisabled:           v3.SecurityEventSeverityMedium,
+	BreakGlassUsed:        v3.SecurityEventSeverityCritical,
+	LongLivedTokenCreated: v3.SecurityEventSeverityMedium,
+}
+
+const (
+	// TypeLabel, SeverityLabel and UserLabel are set on the SecurityEvents to their type, severity and user, so that
+	// the alerting pipelines can select them with label selectors.
+	TypeLabel     = "securityevents.cattle.io/type"
+	SeverityLabel = "securityevents.cattle.io/severity"
+	UserLabel     = "securityevents.cattle.io/user"
+
+	// LongLivedTokenTTL is the TTL above which the creation of a token is a security event. The tokens which never
+	// expire are long-lived too.
+	LongLivedTokenTTL = 30 * 24 * time.Hour
+
+	// queueSize is how many events can wait to be stored. The events recorded while the queue is full are only
+	// logged and exported.
+	queueSize     = 100
+	purgeInterval = time.Hour
+	listPageSize  = 500
+	maxDetailLen  = 1024
+)
+
+// Event is a security event.
+type Event struct {
+	// Type is one of the types of events of this package.
+	Type string
+	// UserName is the name of the user the event is about, if any.
+	UserName string
+	// Actor is the user who caused the event, when known and it isn't the user the event is about.
+	Actor string
+	// Target is the object the event is about, e.g. the name of a global role binding.
+	Target string
+	// SourceIP is the address of the client of the request the event comes from, if any.
+	SourceIP net.IP
+	// Detail describes the event.
+	Detail string
+}
+
+var (
+	queue = make(chan *v3.SecurityEvent, queueSize)
+	now   = time.Now
+)
+
+// IsLongLivedToken tells whether a token with the TTL, in milliseconds, is long-lived. A TTL of 0 never expires.
+func IsLongLivedToken(ttlMillis int64) bool {
+	return ttlMillis <= 0 || time.Duration(ttlMillis)*time.Millisecond > LongLivedTokenTTL
+}
+
+// Record logs the event, exports it to the audit sinks, and stores it as a SecurityEvent in the background, once Start
+// was called.
+func Record(event Event) {
+	securityEvent := newSecurityEvent(event, now())
+
+	fields := logrus.Fields{"event": event.Type, "severity": securityEvent.Severity}
+	for key, value := range map[string]string{
+		"user":   securityEvent.UserName,
+		"actor":  securityEvent.Actor,
+		"target": securityEvent.Target,
+		"source": securityEvent.SourceIP,
+		"detail": securityEvent.Detail,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	logrus.WithFields(fields).Warn("securityevents: audit")
+	auditsinks.Publish(auditsinks.Entry{
+		Source: auditsinks.SourceSecurity,
+		Type:   event.Type,
+		Time:   securityEvent.Time.Time,
+		Data:   securityEvent,
+	})
+
+	select {
+	case queue <- securityEvent:
+	default:
+		logrus.Warnf("[securityevents] too many events waiting to be stored, dropping event %s of user %s", event.Type, event.UserName)
+	}
+}
+
+func newSecurityEvent(event Event, at time.Time) *v3.SecurityEvent {
+	detail := event.Detail
+	if len(detail) > maxDetailLen {
+		detail = detail[:maxDetailLen]
+	}
+	severity, ok := severities[event.Type]
+	if !ok {
+		severity = v3.SecurityEventSeverityMedium
+	}
+	securityEvent := &v3.SecurityEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "securityevent-",
+			Labels:       map[string]string{},
+		},
+		Type:     event.Type,
+		Severity: severity,
+		Time:     metav1.NewTime(at),
+		UserName: event.UserName,
+		Actor:    event.Actor,
+		Target:   event.Target,
+		Detail:   detail,
+	}
+	if event.SourceIP != nil {
+		securityEvent.SourceIP = event.SourceIP.String()
+	}
+	for label, value := range map[string]string{
+		TypeLabel:     event.Type,
+		SeverityLabel: severity,
+		UserLabel:     event.UserName,
+	} {
+		// The values that can't be label values, e.g. the names of the groups, are only in the fields of the event.
+		if value != "" && len(validation.IsValidLabelValue(value)) == 0 {
+			securityEvent.Labels[label] = value
+		}
+	}
+	return securityEvent
+}
+
+// Start stores the recorded events until the context is done. It must run on every replica, as they all serve the
+// requests causing the events.
+func Start(ctx context.Context, scaledContext *config.ScaledContext) {
+	go write(ctx, scaledContext.Wrangler.Mgmt.SecurityEvent())
+}
+
+func write(ctx context.Context, events mgmtcontrollers.SecurityEventClient) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-queue:
+			if _, err := events.Create(event); err != nil {
+				logrus.Errorf("[securityevents] failed to store event %s of user %s: %v", event.Type, event.UserName, err)
+			}
+		}
+	}
+}
+
+// StartPurgeDaemon periodically removes the SecurityEvents older than the security-event-retention-hours setting.
+func StartPurgeDaemon(ctx context.Context, scaledContext *config.ScaledContext) {
+	p := &purger{
+		events: scaledContext.Wrangler.Mgmt.SecurityEvent(),
+		now:    time.Now,
+	}
+	go wait.JitterUntil(p.purge, purgeInterval, .1, true, ctx.Done())
+}
+
+type purger struct {
+	events mgmtcontrollers.SecurityEventClient
+	now    func() time.Time
+}
+
+func (p *purger) purge() {
+	hours, err := strconv.ParseInt(settings.SecurityEventRetentionHours.Get(), 10, 64)
+	if err != nil {
+		logrus.Errorf("[securityevents] invalid %s setting: %v", settings.SecurityEventRetentionHours.Name, err)
+		return
+	}
+	if hours <= 0 {
+		return
+	}
+	cutoff := p.now().Add(-time.Duration(hours) * time.Hour)
+
+	var count int
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		list, err := p.events.List(opts)
+		if err != nil {
+			logrus.Errorf("[securityevents] failed to list events: %v", err)
+			return
+		}
+		for _, event := range list.Items {
+			if !event.Time.Time.Before(cutoff) {
+				continue
+			}
+			if err := p.events.Delete(event.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				logrus.Errorf("[securityevents] failed to delete event %s: %v", event.Name, err)
+				continue
+			}
+			count++
+		}
+		if list.Continue == "" {
+			break
+		}
+		opts.Continue = list.Continue
+	}
+	if count > 0 {
+		logrus.Infof("[securityevents] purged %d events older than %d hours", count, hours)
+	}
+}
@@ -0,0 +1,117 @@
+package securityevents
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewSecurityEvent(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := newSecurityEvent(Event{
+		Type:     BreakGlassUsed,
+		UserName: "u-abc12",
+		Target:   "emergency",
+		SourceIP: net.ParseIP("10.0.0.1"),
+		Detail:   "global role admin granted for 1h0m0s: the identity provider is down",
+	}, at)
+
+	assert.Equal(t, "securityevent-", event.GenerateName)
+	assert.Equal(t, BreakGlassUsed, event.Type)
+	assert.Equal(t, v3.SecurityEventSeverityCritical, event.Severity)
+	assert.Equal(t, at, event.Time.Time)
+	assert.Equal(t, "10.0.0.1", event.SourceIP)
+	assert.Equal(t, "emergency", event.Target)
+	assert.Equal(t, map[string]string{
+		TypeLabel:     BreakGlassUsed,
+		SeverityLabel: v3.SecurityEventSeverityCritical,
+		UserLabel:     "u-abc12",
+	}, event.Labels)
+}
+
+func TestNewSecurityEventSkipsInvalidLabelValues(t *testing.T) {
+	event := newSecurityEvent(Event{
+		Type:     AdminBindingCreated,
+		UserName: strings.Repeat("u", 64),
+		Detail:   strings.Repeat("d", maxDetailLen+10),
+	}, time.Now())
+
+	assert.Equal(t, map[string]string{
+		TypeLabel:     AdminBindingCreated,
+		SeverityLabel: v3.SecurityEventSeverityHigh,
+	}, event.Labels)
+	assert.Equal(t, strings.Repeat("u", 64), event.UserName)
+	assert.Len(t, event.Detail, maxDetailLen)
+	assert.Empty(t, event.SourceIP)
+}
+
+func TestIsLongLivedToken(t *testing.T) {
+	day := (24 * time.Hour).Milliseconds()
+
+	assert.True(t, IsLongLivedToken(0), "tokens which never expire are long-lived")
+	assert.True(t, IsLongLivedToken(31*day))
+	assert.False(t, IsLongLivedToken(30*day))
+	assert.False(t, IsLongLivedToken((16 * time.Hour).Milliseconds()))
+}
+
+func TestRecord(t *testing.T) {
+	Record(Event{Type: MFADisabled, UserName: "u-abc12", Actor: "u-admin"})
+
+	select {
+	case event := <-queue:
+		assert.Equal(t, MFADisabled, event.Type)
+		assert.Equal(t, v3.SecurityEventSeverityMedium, event.Severity)
+		assert.Equal(t, "u-abc12", event.UserName)
+		assert.Equal(t, "u-admin", event.Actor)
+	default:
+		t.Fatal("the event wasn't queued")
+	}
+}
+
+func TestPurge(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, settings.SecurityEventRetentionHours.Set("24"))
+	defer settings.SecurityEventRetentionHours.Set(settings.SecurityEventRetentionHours.Default)
+
+	event := func(name string, age time.Duration) v3.SecurityEvent {
+		return v3.SecurityEvent{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Time:       metav1.NewTime(now.Add(-age)),
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	events := fake.NewMockNonNamespacedClientInterface[*v3.SecurityEvent, *v3.SecurityEventList](ctrl)
+	events.EXPECT().List(metav1.ListOptions{Limit: listPageSize}).Return(&v3.SecurityEventList{
+		ListMeta: metav1.ListMeta{Continue: "next"},
+		Items:    []v3.SecurityEvent{event("old", 48*time.Hour), event("recent", time.Hour)},
+	}, nil)
+	events.EXPECT().List(metav1.ListOptions{Limit: listPageSize, Continue: "next"}).Return(&v3.SecurityEventList{
+		Items: []v3.SecurityEvent{event("older", 72*time.Hour)},
+	}, nil)
+	events.EXPECT().Delete("old", gomock.Any()).Return(nil)
+	events.EXPECT().Delete("older", gomock.Any()).Return(nil)
+
+	p := &purger{events: events, now: func() time.Time { return now }}
+	p.purge()
+}
+
+func TestPurgeKeepsEventsWithoutRetention(t *testing.T) {
+	require.NoError(t, settings.SecurityEventRetentionHours.Set("0"))
+	defer settings.SecurityEventRetentionHours.Set(settings.SecurityEventRetentionHours.Default)
+
+	ctrl := gomock.NewController(t)
+	events := fake.NewMockNonNamespacedClientInterface[*v3.SecurityEvent, *v3.SecurityEventList](ctrl)
+
+	p := &purger{events: events, now: time.Now}
+	p.purge()
+}
@@ -8,14 +8,39 @@
 	"github.com/gorilla/mux"
 	"github.com/rancher/norman/store/proxy"
 	"github.com/rancher/rancher/pkg/api/norman"
+	"github.com/rancher/rancher/pkg/auth/accessrequests"
+	"github.com/rancher/rancher/pkg/auth/activity"
 	"github.com/rancher/rancher/pkg/auth/api"
+	"github.com/rancher/rancher/pkg/auth/auditsinks"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/breakglass"
 	"github.com/rancher/rancher/pkg/auth/data"
+	"github.com/rancher/rancher/pkg/auth/devicecode"
+	"github.com/rancher/rancher/pkg/auth/effectivepermissions"
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/jwttokens"
+	"github.com/rancher/rancher/pkg/auth/mfa"
+	"github.com/rancher/rancher/pkg/auth/notifications"
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
+	"github.com/rancher/rancher/pkg/auth/passwordreset"
+	"github.com/rancher/rancher/pkg/auth/providerprobe"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
+	"github.com/rancher/rancher/pkg/auth/providers"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/providers/publicapi"
 	"github.com/rancher/rancher/pkg/auth/providers/saml"
+	"github.com/rancher/rancher/pkg/auth/providerslo"
+	"github.com/rancher/rancher/pkg/auth/rbacbundle"
+	"github.com/rancher/rancher/pkg/auth/recertification"
+	"github.com/rancher/rancher/pkg/auth/refreshtokens"
 	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/securityevents"
+	"github.com/rancher/rancher/pkg/auth/servicekeys"
+	"github.com/rancher/rancher/pkg/auth/sessions"
+	"github.com/rancher/rancher/pkg/auth/tokenexchange"
 	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/tracing"
+	"github.com/rancher/rancher/pkg/auth/webauthn"
 	"github.com/rancher/rancher/pkg/clusterrouter"
 	"github.com/rancher/rancher/pkg/features"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -93,11 +118,23 @@ func newAPIManagement(ctx context.Context, scaledContext *config.ScaledContext)
 	}
 
 	saml := saml.AuthHandler()
+	jwtHandler := jwttokens.NewHandler(scaledContext)
 
 	root := mux.NewRouter()
 	root.UseEncodedPath()
 	root.PathPrefix("/v3-public").Handler(publicAPI)
 	root.PathPrefix("/v1-saml").Handler(saml)
+	root.Path(providers.BackchannelLogoutPath).Handler(providers.NewBackchannelLogoutHandler(ctx, scaledContext))
+	root.PathPrefix("/v1-device").Handler(devicecode.NewHandler(ctx, scaledContext))
+	root.Path(tokenexchange.TokenPath).Handler(tokenexchange.NewHandler(ctx, scaledContext))
+	root.Path(jwttokens.JWKSPath).Handler(jwtHandler)
+	root.Path(jwttokens.DiscoveryPath).Handler(jwtHandler)
+	root.PathPrefix("/v1-token").Handler(refreshtokens.NewHandler(ctx, scaledContext))
+	root.Path(webauthn.LoginPath).Handler(webauthn.NewLoginHandler(scaledContext))
+	root.Path(passwordpolicy.PolicyPath).Handler(passwordpolicy.NewHandler())
+	root.Path(passwordreset.BasePath).Handler(passwordreset.NewHandler(scaledContext))
+	root.Path(emailverification.BasePath).Handler(emailverification.NewHandler(scaledContext))
+	root.Path(breakglass.BasePath).Handler(breakglass.NewActivationHandler(ctx, scaledContext))
 	root.NotFoundHandler = privateAPI
 
 	return func(next http.Handler) http.Handler {
@@ -127,6 +164,16 @@ func newPrivateAPI(ctx context.Context, scaledContext *config.ScaledContext) (*m
 	root.PathPrefix("/v3/user").Handler(otherAPIs)
 	root.PathPrefix("/v3/schema").Handler(otherAPIs)
 	root.PathPrefix("/v3/subscribe").Handler(otherAPIs)
+	root.PathPrefix("/v1-sessions").Handler(sessions.NewHandler(ctx, scaledContext))
+	root.PathPrefix(activity.BasePath).Handler(activity.NewHandler(scaledContext))
+	root.PathPrefix(servicekeys.BasePath).Handler(servicekeys.NewHandler(ctx, scaledContext))
+	root.PathPrefix(accessrequests.BasePath).Handler(accessrequests.NewHandler(scaledContext))
+	root.PathPrefix(breakglass.BasePath + "/").Handler(breakglass.NewHandler(ctx, scaledContext))
+	root.PathPrefix(effectivepermissions.BasePath).Handler(effectivepermissions.NewHandler(scaledContext))
+	root.PathPrefix(rbacbundle.BasePath).Handler(rbacbundle.NewHandler(scaledContext))
+	root.PathPrefix(recertification.BasePath).Handler(recertification.NewHandler(scaledContext))
+	root.PathPrefix(mfa.BasePath).Handler(mfa.NewHandler(scaledContext))
+	root.PathPrefix(webauthn.BasePath + "/").Handler(webauthn.NewHandler(scaledContext))
 	return root, nil
 }
 
@@ -145,7 +192,14 @@ func (s *Server) OnLeader(ctx context.Context) error {
 	}
 
 	tokens.StartPurgeDaemon(ctx, management)
+	tokens.StartEncryptionDaemon(ctx, management)
+	tokens.StartHashMigration(ctx, management)
 	providerrefresh.StartRefreshDaemon(ctx, s.scaledContext, management)
+	providerprobe.Start(ctx, management)
+	providerslo.StartEvaluation(ctx, management, providers.EnabledProviders)
+	notifications.StartExpiryNotices(ctx, s.scaledContext)
+	authevents.StartPurgeDaemon(ctx, s.scaledContext)
+	securityevents.StartPurgeDaemon(ctx, s.scaledContext)
 	logrus.Infof("Steve auth startup complete")
 	return nil
 }
@@ -158,6 +212,12 @@ func (s *Server) Start(ctx context.Context, leader bool) error {
 	if err := s.scaledContext.Start(ctx); err != nil {
 		return err
 	}
+	authevents.Start(ctx, s.scaledContext)
+	securityevents.Start(ctx, s.scaledContext)
+	auditsinks.Start(ctx, s.scaledContext)
+	activity.Start(ctx, s.scaledContext)
+	providerslo.Start(ctx, s.scaledContext)
+	tracing.Start(ctx)
 	if leader {
 		return s.OnLeader(ctx)
 	}
@@ -0,0 +1,140 @@
+package servicekeys
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// CreatedEvent, RotatedEvent and DeletedEvent are the events of the audit trails of the service keys.
+	CreatedEvent = "ServiceKeyCreated"
+	RotatedEvent = "ServiceKeyRotated"
+	DeletedEvent = "ServiceKeyDeleted"
+
+	auditEventsKey = "events"
+	// maxAuditEvents is how many events the audit trail of a service key keeps, the oldest ones being dropped first.
+	maxAuditEvents = 100
+)
+
+// AuditEvent is a change of a service key.
+type AuditEvent struct {
+	Time  metav1.Time `json:"time"`
+	Event string      `json:"event"`
+	// Actor is the user who made the change, which is the key itself when it rotates itself.
+	Actor  string `json:"actor"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// AuditSecretName returns the name of the secret holding the audit trail of the service key.
+func AuditSecretName(keyID string) string {
+	return "service-key-audit-" + keyID
+}
+
+// record logs the event and adds it to the audit trail of the service key.
+func (h *handler) record(key *v3.User, actor, event, detail string) {
+	auditEvent := AuditEvent{Time: metav1.NewTime(h.now()), Event: event, Actor: actor, Detail: detail}
+	logEvent(key, auditEvent)
+	if err := h.addEvent(key, auditEvent); err != nil {
+		logrus.Errorf("[servicekeys] failed to add event %s to the audit trail of service key %s: %v", event, key.Name, err)
+	}
+}
+
+func logEvent(key *v3.User, event AuditEvent) {
+	fields := logrus.Fields{
+		"event":      event.Event,
+		"serviceKey": key.Name,
+		"name":       key.DisplayName,
+		"projectID":  ProjectID(key),
+		"actor":      event.Actor,
+	}
+	if event.Detail != "" {
+		fields["detail"] = event.Detail
+	}
+	logrus.WithFields(fields).Info("[servicekeys] audit")
+}
+
+// addEvent appends the event to the secret of the audit trail, which is owned by the user of the service key so that
+// it's deleted along with it.
+func (h *handler) addEvent(key *v3.User, event AuditEvent) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := h.secrets.Get(tokens.SecretNamespace, AuditSecretName(key.Name), metav1.GetOptions{})
+		found := err == nil
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		var events []AuditEvent
+		if found {
+			if events, err = parseEvents(secret); err != nil {
+				// A corrupted trail is restarted rather than blocking the changes of the key.
+				logrus.Warnf("[servicekeys] discarding the audit trail of service key %s: %v", key.Name, err)
+			}
+		}
+		events = append(events, event)
+		if len(events) > maxAuditEvents {
+			events = events[len(events)-maxAuditEvents:]
+		}
+		data, err := json.Marshal(events)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			_, err = h.secrets.Create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      AuditSecretName(key.Name),
+					Namespace: tokens.SecretNamespace,
+					Labels:    map[string]string{tokens.UserIDLabel: key.Name},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: mgmtv3.UserGroupVersionKind.GroupVersion().String(),
+						Kind:       mgmtv3.UserGroupVersionKind.Kind,
+						Name:       key.Name,
+						UID:        key.UID,
+					}},
+				},
+				Data: map[string][]byte{auditEventsKey: data},
+			})
+			if apierrors.IsAlreadyExists(err) {
+				return apierrors.NewConflict(corev1.Resource("secrets"), AuditSecretName(key.Name), err)
+			}
+			return err
+		}
+		secret = secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[auditEventsKey] = data
+		_, err = h.secrets.Update(secret)
+		return err
+	})
+}
+
+// events returns the audit trail of the service key, oldest event first.
+func (h *handler) events(key *v3.User) ([]AuditEvent, error) {
+	secret, err := h.secrets.Get(tokens.SecretNamespace, AuditSecretName(key.Name), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []AuditEvent{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseEvents(secret)
+}
+
+func parseEvents(secret *corev1.Secret) ([]AuditEvent, error) {
+	events := []AuditEvent{}
+	if raw := secret.Data[auditEventsKey]; len(raw) > 0 {
+		if err := json.Unmarshal(raw, &events); err != nil {
+			return nil, fmt.Errorf("parsing the audit trail of secret %s: %w", secret.Name, err)
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,313 @@
+package servicekeys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// BasePath is the path of the service key endpoints.
+	BasePath = "/v1-service-keys"
+
+	// RotateAction rotates a service key. Service keys can rotate themselves with it on the base path.
+	RotateAction = "rotate"
+	maxBodySize  = 64 * 1024
+)
+
+// NewHandler returns the handler of the service key endpoints.
+func NewHandler(ctx context.Context, mgmt *config.ScaledContext) http.Handler {
+	return newHandler(ctx, mgmt).router()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(BasePath).Queries("action", RotateAction).HandlerFunc(h.rotateSelf)
+	root.Methods(http.MethodGet).Path(BasePath + "/{project}").HandlerFunc(h.list)
+	root.Methods(http.MethodPost).Path(BasePath + "/{project}").HandlerFunc(h.create)
+	root.Methods(http.MethodGet).Path(BasePath + "/{project}/{id}").HandlerFunc(h.get)
+	root.Methods(http.MethodPost).Path(BasePath+"/{project}/{id}").Queries("action", RotateAction).HandlerFunc(h.rotateKey)
+	root.Methods(http.MethodDelete).Path(BasePath + "/{project}/{id}").HandlerFunc(h.remove)
+	root.Methods(http.MethodGet).Path(BasePath + "/{project}/{id}/audit").HandlerFunc(h.auditTrail)
+	return root
+}
+
+// list writes the service keys of the project.
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	_, _, projectID, ok := h.access(w, r, "list")
+	if !ok {
+		return
+	}
+	keys, err := h.userCache.List(labels.SelectorFromSet(labels.Set{ProjectLabel: ProjectLabelValue(projectID)}))
+	if err != nil {
+		logrus.Errorf("[servicekeys] failed to list the service keys of project %s: %v", projectID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to list service keys")
+		return
+	}
+
+	serviceKeys := make([]ServiceKey, 0, len(keys))
+	for _, key := range keys {
+		serviceKey, err := h.view(key)
+		if err != nil {
+			logrus.Errorf("[servicekeys] failed to get service key %s: %v", key.Name, err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to list service keys")
+			return
+		}
+		serviceKeys = append(serviceKeys, serviceKey)
+	}
+	sort.Slice(serviceKeys, func(i, j int) bool {
+		return serviceKeys[i].Name < serviceKeys[j].Name
+	})
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"type": "collection",
+		"data": serviceKeys,
+	})
+}
+
+// get writes a service key of the project.
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	_, _, projectID, ok := h.access(w, r, "get")
+	if !ok {
+		return
+	}
+	key, ok := h.key(w, r, projectID)
+	if !ok {
+		return
+	}
+	h.writeKey(w, r, http.StatusOK, key, nil, "")
+}
+
+// create creates a service key in the project, and writes it with its token.
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	caller, project, projectID, ok := h.access(w, r, "create")
+	if !ok {
+		return
+	}
+	var input createInput
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&input); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid service key")
+		return
+	}
+	if err := input.validate(); err != nil {
+		util.ReturnHTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	for _, role := range input.Roles {
+		if status, err := h.checkRole(r.Context(), caller, project, role); err != nil {
+			if status == http.StatusInternalServerError {
+				logrus.Errorf("[servicekeys] failed to check role %s: %v", role, err)
+				err = fmt.Errorf("failed to check role %s", role)
+			}
+			util.ReturnHTTPError(w, r, status, err.Error())
+			return
+		}
+	}
+
+	key, token, value, err := h.createKey(caller, project, projectID, input)
+	if apierrors.IsAlreadyExists(err) {
+		util.ReturnHTTPError(w, r, http.StatusConflict, fmt.Sprintf("service key %s already exists in project %s", input.Name, projectID))
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[servicekeys] failed to create service key %s in project %s: %v", input.Name, projectID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to create service key")
+		return
+	}
+	h.record(key, caller.GetName(), CreatedEvent, "roles "+strings.Join(input.Roles, ", "))
+	h.writeKey(w, r, http.StatusCreated, key, &token, value)
+}
+
+// rotateKey rotates a service key of the project, and writes it with its new token.
+func (h *handler) rotateKey(w http.ResponseWriter, r *http.Request) {
+	caller, _, projectID, ok := h.access(w, r, "create")
+	if !ok {
+		return
+	}
+	key, ok := h.key(w, r, projectID)
+	if !ok {
+		return
+	}
+	h.rotateAndWrite(w, r, key, caller.GetName())
+}
+
+// rotateSelf rotates the service key authenticating the request, so that automation can rotate its own key on
+// schedule.
+func (h *handler) rotateSelf(w http.ResponseWriter, r *http.Request) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return
+	}
+	key, err := h.userCache.Get(caller.GetName())
+	if err != nil || ProjectID(key) == "" {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, "only service keys can rotate themselves")
+		return
+	}
+	h.rotateAndWrite(w, r, key, caller.GetName())
+}
+
+func (h *handler) rotateAndWrite(w http.ResponseWriter, r *http.Request, key *v3.User, actor string) {
+	token, value, err := h.rotate(key)
+	if err != nil {
+		logrus.Errorf("[servicekeys] failed to rotate service key %s: %v", key.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to rotate service key")
+		return
+	}
+	h.record(key, actor, RotatedEvent, "")
+	h.writeKey(w, r, http.StatusOK, key, &token, value)
+}
+
+// remove deletes a service key of the project, along with its role bindings and tokens.
+func (h *handler) remove(w http.ResponseWriter, r *http.Request) {
+	caller, _, projectID, ok := h.access(w, r, "delete")
+	if !ok {
+		return
+	}
+	key, ok := h.key(w, r, projectID)
+	if !ok {
+		return
+	}
+	if err := h.users.Delete(key.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		logrus.Errorf("[servicekeys] failed to delete service key %s: %v", key.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to delete service key")
+		return
+	}
+	// The audit trail is deleted along with the key, so the deletion is only logged.
+	logEvent(key, AuditEvent{Time: metav1.NewTime(h.now()), Event: DeletedEvent, Actor: caller.GetName()})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// auditTrail writes the audit trail of a service key of the project, oldest event first.
+func (h *handler) auditTrail(w http.ResponseWriter, r *http.Request) {
+	_, _, projectID, ok := h.access(w, r, "get")
+	if !ok {
+		return
+	}
+	key, ok := h.key(w, r, projectID)
+	if !ok {
+		return
+	}
+	events, err := h.events(key)
+	if err != nil {
+		logrus.Errorf("[servicekeys] failed to get the audit trail of service key %s: %v", key.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get audit trail")
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"type": "collection",
+		"data": events,
+	})
+}
+
+// access returns the caller and the project of the request, if the caller can apply the verb to the role bindings of
+// the project, that is manage its members. Service keys can't manage service keys, as they could otherwise give
+// themselves more permissions or outlive their rotation schedule.
+func (h *handler) access(w http.ResponseWriter, r *http.Request, verb string) (user.Info, *v3.Project, string, bool) {
+	caller, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return nil, nil, "", false
+	}
+	if callerUser, err := h.userCache.Get(caller.GetName()); err == nil && ProjectID(callerUser) != "" {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, "service keys can't manage service keys")
+		return nil, nil, "", false
+	}
+
+	projectID, err := url.PathUnescape(mux.Vars(r)["project"])
+	clusterName, projectName, found := strings.Cut(projectID, ":")
+	if err != nil || !found || clusterName == "" || projectName == "" {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid project ID, must be <cluster>:<project>")
+		return nil, nil, "", false
+	}
+
+	allowed, err := h.authorize(r.Context(), caller, verb, projectName, mgmtv3.ProjectRoleTemplateBindingResource.Name, "")
+	if err != nil {
+		logrus.Errorf("[servicekeys] failed to authorize user %s: %v", caller.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return nil, nil, "", false
+	}
+	if !allowed {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to %s the service keys of project %s", verb, projectID))
+		return nil, nil, "", false
+	}
+
+	project, err := h.projectCache.Get(clusterName, projectName)
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("project %s not found", projectID))
+		return nil, nil, "", false
+	}
+	if err != nil {
+		logrus.Errorf("[servicekeys] failed to get project %s: %v", projectID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get project")
+		return nil, nil, "", false
+	}
+	return caller, project, projectID, true
+}
+
+// key returns the service key of the path, if it belongs to the project.
+func (h *handler) key(w http.ResponseWriter, r *http.Request, projectID string) (*v3.User, bool) {
+	id := mux.Vars(r)["id"]
+	key, err := h.userCache.Get(id)
+	if err != nil || ProjectID(key) != projectID {
+		if err != nil && !apierrors.IsNotFound(err) {
+			logrus.Errorf("[servicekeys] failed to get service key %s: %v", id, err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get service key")
+			return nil, false
+		}
+		util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("service key %s not found", id))
+		return nil, false
+	}
+	return key, true
+}
+
+// authorize tells whether the user can apply the verb to the resource of the management API group.
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, verb, namespace, resource, name string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:     mgmtv3.ProjectRoleTemplateBindingGroupVersionKind.Group,
+		Namespace: namespace,
+		Resource:  resource,
+		Name:      name,
+		Verb:      verb,
+	})
+}
+
+// writeKey writes the service key, with the token just issued for it if any.
+func (h *handler) writeKey(w http.ResponseWriter, r *http.Request, status int, key *v3.User, token *v3.Token, value string) {
+	serviceKey, err := h.view(key)
+	if err != nil {
+		logrus.Errorf("[servicekeys] failed to get service key %s: %v", key.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to get service key")
+		return
+	}
+	if token != nil {
+		// The cache may not have the token yet.
+		rotatedAt := metav1.NewTime(h.now())
+		if !token.CreationTimestamp.IsZero() {
+			rotatedAt = token.CreationTimestamp
+		}
+		serviceKey.RotatedAt = &rotatedAt
+		serviceKey.RotateBy = rotatedAt.Add(tokenTTL(token)).UTC().Format(timeFormat)
+		serviceKey.Value = value
+	}
+	util.WriteJSON(w, status, serviceKey)
+}
@@ -0,0 +1,378 @@
+// Package servicekeys manages the service keys of the projects: API keys owned by a project rather than by a person, so
+// that automation doesn't depend on the account of an employee, which is deactivated when they leave.
+// A service key is a system user bound to role templates of its project, like the system accounts of the projects.
+// Its tokens are restricted to the project, and expire on a rotation schedule unless the key is rotated. The members
+// allowed to manage the members of the project manage its service keys, and the changes are recorded in an audit trail
+// kept along with each key.
+package servicekeys
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// ProjectLabel is set on the users of the service keys to their project, in the <cluster>_<project> format.
+	ProjectLabel = "auth.cattle.io/service-key-project"
+	// RotationDaysAnnotation is the number of days after which the service key must be rotated.
+	RotationDaysAnnotation = "auth.cattle.io/service-key-rotation-days"
+	// ScopeAnnotation holds the JSON of the scope of the tokens of the service key, on top of their project.
+	ScopeAnnotation = "auth.cattle.io/service-key-scope"
+	// CreatorAnnotation is the user who created the service key.
+	CreatorAnnotation = "auth.cattle.io/service-key-creator"
+
+	principalPrefix = "system://service-key/"
+	maxNameLength   = 63
+	timeFormat      = time.RFC3339
+)
+
+// ServiceKey is a service key of a project.
+type ServiceKey struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description,omitempty"`
+	ProjectID    string         `json:"projectId"`
+	Roles        []string       `json:"roles"`
+	Scope        *v3.TokenScope `json:"scope,omitempty"`
+	RotationDays int            `json:"rotationDays"`
+	CreatedBy    string         `json:"createdBy,omitempty"`
+	CreatedAt    metav1.Time    `json:"createdAt"`
+	// RotatedAt is when the current token of the key was issued, and RotateBy when it expires unless the key is rotated.
+	RotatedAt  *metav1.Time `json:"rotatedAt,omitempty"`
+	RotateBy   string       `json:"rotateBy,omitempty"`
+	LastUsedAt *metav1.Time `json:"lastUsedAt,omitempty"`
+	// Value is the token of the key. It's only returned when the key is created or rotated.
+	Value string `json:"value,omitempty"`
+}
+
+type createInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Roles       []string `json:"roles"`
+	// Scope further restricts the tokens of the key to some API groups and verbs.
+	Scope        *v3.TokenScope `json:"scope"`
+	RotationDays int            `json:"rotationDays"`
+}
+
+type tokenIssuer interface {
+	NewServiceKeyToken(userID string, userPrincipal v3.Principal, scope *v3.TokenScope, ttl int64, description string) (v3.Token, string, error)
+}
+
+type handler struct {
+	users                mgmtcontrollers.UserClient
+	userCache            mgmtcontrollers.UserCache
+	projectCache         mgmtcontrollers.ProjectCache
+	roleTemplateCache    mgmtcontrollers.RoleTemplateCache
+	prtbs                mgmtcontrollers.ProjectRoleTemplateBindingClient
+	prtbCache            mgmtcontrollers.ProjectRoleTemplateBindingCache
+	tokenCache           mgmtcontrollers.TokenCache
+	tokens               mgmtcontrollers.TokenClient
+	secrets              wcorev1.SecretClient
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+	issuer               tokenIssuer
+	now                  func() time.Time
+}
+
+func newHandler(ctx context.Context, mgmt *config.ScaledContext) *handler {
+	return &handler{
+		users:                mgmt.Wrangler.Mgmt.User(),
+		userCache:            mgmt.Wrangler.Mgmt.User().Cache(),
+		projectCache:         mgmt.Wrangler.Mgmt.Project().Cache(),
+		roleTemplateCache:    mgmt.Wrangler.Mgmt.RoleTemplate().Cache(),
+		prtbs:                mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding(),
+		prtbCache:            mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		tokenCache:           mgmt.Wrangler.Mgmt.Token().Cache(),
+		tokens:               mgmt.Wrangler.Mgmt.Token(),
+		secrets:              mgmt.Wrangler.Core.Secret(),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+		issuer:               tokens.NewManager(ctx, mgmt),
+		now:                  time.Now,
+	}
+}
+
+// ProjectLabelValue returns the value of ProjectLabel for the project, whose ID is in the <cluster>:<project> format.
+func ProjectLabelValue(projectID string) string {
+	return strings.Replace(projectID, ":", "_", 1)
+}
+
+// ProjectID returns the project of a service key user, empty if the user isn't a service key.
+func ProjectID(key *v3.User) string {
+	return strings.Replace(key.Labels[ProjectLabel], "_", ":", 1)
+}
+
+// userName returns the name of the user of a service key. It's derived from the project and the name of the key, as
+// for the other system users, so that the names of the keys of a project are unique.
+func userName(projectID, name string) string {
+	hash := sha256.Sum256([]byte(principalPrefix + projectID + "/" + name))
+	return "u-" + strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(hash[:])[:10])
+}
+
+func principal(key *v3.User) v3.Principal {
+	return v3.Principal{
+		ObjectMeta:    metav1.ObjectMeta{Name: "local://" + key.Name},
+		DisplayName:   key.DisplayName,
+		LoginName:     key.DisplayName,
+		PrincipalType: "user",
+		Provider:      "local",
+	}
+}
+
+// validate checks the input of a new service key, and defaults its rotation interval.
+func (in *createInput) validate() error {
+	if in.Name == "" || len(in.Name) > maxNameLength {
+		return fmt.Errorf("name is required and must be at most %d characters", maxNameLength)
+	}
+	if len(in.Roles) == 0 {
+		return fmt.Errorf("at least one role is required")
+	}
+	maxDays := settings.ServiceKeyRotationDays.GetInt()
+	if in.RotationDays == 0 {
+		in.RotationDays = maxDays
+	}
+	if in.RotationDays < 1 || in.RotationDays > maxDays {
+		return fmt.Errorf("rotationDays must be between 1 and %d", maxDays)
+	}
+	if in.Scope != nil {
+		if len(in.Scope.Clusters) > 0 || len(in.Scope.Projects) > 0 {
+			return fmt.Errorf("the scope of a service key can't list clusters or projects, it's restricted to its project")
+		}
+		if err := tokens.ValidateScopeVerbs(in.Scope.Verbs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRole returns an error, and its HTTP status, if the role can't be given to a service key of the project by the
+// caller. It must be a project role which isn't locked, and the caller must have it in the project or be allowed to
+// bind it anywhere, so that they can't give a key more permissions than their own.
+func (h *handler) checkRole(ctx context.Context, caller user.Info, project *v3.Project, role string) (int, error) {
+	roleTemplate, err := h.roleTemplateCache.Get(role)
+	if apierrors.IsNotFound(err) {
+		return http.StatusUnprocessableEntity, fmt.Errorf("role %s not found", role)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if roleTemplate.Context != "project" || roleTemplate.Locked {
+		return http.StatusUnprocessableEntity, fmt.Errorf("role %s can't be given to a service key", role)
+	}
+
+	bindings, err := h.prtbCache.List(project.Name, labels.Everything())
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, binding := range bindings {
+		if binding.UserName == caller.GetName() && binding.RoleTemplateName == role {
+			return http.StatusOK, nil
+		}
+	}
+	allowed, err := h.authorize(ctx, caller, "bind", "", "roletemplates", role)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !allowed {
+		return http.StatusForbidden, fmt.Errorf("not allowed to give role %s to a service key", role)
+	}
+	return http.StatusOK, nil
+}
+
+// createKey creates the user of the service key, binds it to its roles in the project, and issues its first token.
+func (h *handler) createKey(caller user.Info, project *v3.Project, projectID string, input createInput) (*v3.User, v3.Token, string, error) {
+	annotations := map[string]string{
+		RotationDaysAnnotation: strconv.Itoa(input.RotationDays),
+		CreatorAnnotation:      caller.GetName(),
+	}
+	if input.Scope != nil {
+		scope, err := json.Marshal(input.Scope)
+		if err != nil {
+			return nil, v3.Token{}, "", err
+		}
+		annotations[ScopeAnnotation] = string(scope)
+	}
+	name := userName(projectID, input.Name)
+	key, err := h.users.Create(&v3.User{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{ProjectLabel: ProjectLabelValue(projectID)},
+			Annotations: annotations,
+		},
+		DisplayName:  input.Name,
+		Description:  input.Description,
+		PrincipalIDs: []string{principalPrefix + projectID + "/" + input.Name, "local://" + name},
+	})
+	if err != nil {
+		return nil, v3.Token{}, "", err
+	}
+
+	token, value, err := func() (v3.Token, string, error) {
+		for _, role := range input.Roles {
+			_, err := h.prtbs.Create(&v3.ProjectRoleTemplateBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name + "-" + role,
+					Namespace: project.Name,
+				},
+				ProjectName:      projectID,
+				UserName:         key.Name,
+				RoleTemplateName: role,
+			})
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				return v3.Token{}, "", fmt.Errorf("binding role %s: %w", role, err)
+			}
+		}
+		return h.issue(key)
+	}()
+	if err != nil {
+		// Deleting the user deletes the bindings already created.
+		if err := h.users.Delete(key.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, v3.Token{}, "", fmt.Errorf("deleting service key %s: %w", key.Name, err)
+		}
+		return nil, v3.Token{}, "", err
+	}
+	return key, token, value, nil
+}
+
+// rotate issues a new token for the service key, and shortens the lifetime of the previous ones to the grace period.
+func (h *handler) rotate(key *v3.User) (v3.Token, string, error) {
+	token, value, err := h.issue(key)
+	if err != nil {
+		return v3.Token{}, "", err
+	}
+	previous, err := h.keyTokens(key)
+	if err != nil {
+		return v3.Token{}, "", err
+	}
+	graceEnd := h.now().Add(time.Duration(settings.ServiceKeyRotationGraceHours.GetInt()) * time.Hour)
+	for _, old := range previous {
+		if old.Name == token.Name || !expiry(old).After(graceEnd) {
+			continue
+		}
+		old = old.DeepCopy()
+		old.TTLMillis = graceEnd.Sub(old.CreationTimestamp.Time).Milliseconds()
+		if _, err := h.tokens.Update(old); err != nil && !apierrors.IsNotFound(err) {
+			return v3.Token{}, "", fmt.Errorf("shortening the lifetime of token %s: %w", old.Name, err)
+		}
+	}
+	return token, value, nil
+}
+
+// issue creates a token of the service key, restricted to its project and expiring at the end of the grace period
+// after the rotation interval.
+func (h *handler) issue(key *v3.User) (v3.Token, string, error) {
+	scope := &v3.TokenScope{}
+	if raw := key.Annotations[ScopeAnnotation]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), scope); err != nil {
+			return v3.Token{}, "", fmt.Errorf("parsing the scope of service key %s: %w", key.Name, err)
+		}
+	}
+	scope.Clusters = nil
+	scope.Projects = []string{ProjectID(key)}
+
+	ttl := time.Duration(rotationDays(key))*24*time.Hour + time.Duration(settings.ServiceKeyRotationGraceHours.GetInt())*time.Hour
+	description := "Service key " + key.DisplayName + " of project " + ProjectID(key)
+	token, value, err := h.issuer.NewServiceKeyToken(key.Name, principal(key), scope, ttl.Milliseconds(), description)
+	if err != nil {
+		return v3.Token{}, "", fmt.Errorf("issuing a token for service key %s: %w", key.Name, err)
+	}
+	return token, token.Name + ":" + value, nil
+}
+
+// keyTokens returns the unexpired tokens of the service key.
+func (h *handler) keyTokens(key *v3.User) ([]*v3.Token, error) {
+	all, err := h.tokenCache.List(labels.SelectorFromSet(labels.Set{tokens.UserIDLabel: key.Name}))
+	if err != nil {
+		return nil, err
+	}
+	var active []*v3.Token
+	for _, token := range all {
+		if token.UserID == key.Name && (token.TTLMillis == 0 || expiry(token).After(h.now())) {
+			active = append(active, token)
+		}
+	}
+	return active, nil
+}
+
+// view returns the service key as returned by the API, with the status of its tokens.
+func (h *handler) view(key *v3.User) (ServiceKey, error) {
+	projectID := ProjectID(key)
+	serviceKey := ServiceKey{
+		ID:           key.Name,
+		Name:         key.DisplayName,
+		Description:  key.Description,
+		ProjectID:    projectID,
+		Roles:        []string{},
+		RotationDays: rotationDays(key),
+		CreatedBy:    key.Annotations[CreatorAnnotation],
+		CreatedAt:    key.CreationTimestamp,
+	}
+	if raw := key.Annotations[ScopeAnnotation]; raw != "" {
+		scope := &v3.TokenScope{}
+		if err := json.Unmarshal([]byte(raw), scope); err == nil {
+			serviceKey.Scope = scope
+		}
+	}
+
+	_, projectName, _ := strings.Cut(projectID, ":")
+	bindings, err := h.prtbCache.List(projectName, labels.Everything())
+	if err != nil {
+		return ServiceKey{}, err
+	}
+	for _, binding := range bindings {
+		if binding.UserName == key.Name {
+			serviceKey.Roles = append(serviceKey.Roles, binding.RoleTemplateName)
+		}
+	}
+	slices.Sort(serviceKey.Roles)
+
+	keyTokens, err := h.keyTokens(key)
+	if err != nil {
+		return ServiceKey{}, err
+	}
+	for _, token := range keyTokens {
+		if serviceKey.RotatedAt == nil || serviceKey.RotatedAt.Before(&token.CreationTimestamp) {
+			rotatedAt := token.CreationTimestamp
+			serviceKey.RotatedAt = &rotatedAt
+			serviceKey.RotateBy = expiry(token).UTC().Format(timeFormat)
+		}
+		if token.LastUsedAt != nil && (serviceKey.LastUsedAt == nil || serviceKey.LastUsedAt.Before(token.LastUsedAt)) {
+			serviceKey.LastUsedAt = token.LastUsedAt
+		}
+	}
+	return serviceKey, nil
+}
+
+func rotationDays(key *v3.User) int {
+	days, err := strconv.Atoi(key.Annotations[RotationDaysAnnotation])
+	if err != nil || days <= 0 {
+		return settings.ServiceKeyRotationDays.GetInt()
+	}
+	return days
+}
+
+func tokenTTL(token *v3.Token) time.Duration {
+	return time.Duration(token.TTLMillis) * time.Millisecond
+}
+
+func expiry(token *v3.Token) time.Time {
+	return token.CreationTimestamp.Add(tokenTTL(token))
+}
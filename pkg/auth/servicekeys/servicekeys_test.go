@@ -0,0 +1,398 @@
+package servicekeys
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const projectID = "c-abcde:p-fghij"
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	// allowed are the users allowed to apply any verb to the role bindings of the project, and binders those allowed to
+	// bind any role.
+	allowed map[string]bool
+	binders map[string]bool
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	attributes := sar.Spec.ResourceAttributes
+	switch {
+	case attributes.Verb == "bind" && attributes.Resource == "roletemplates":
+		sar.Status.Allowed = f.binders[sar.Spec.User]
+	case attributes.Resource == "projectroletemplatebindings" && attributes.Namespace == "p-fghij":
+		sar.Status.Allowed = f.allowed[sar.Spec.User]
+	}
+	return sar, nil
+}
+
+type fakeIssuer struct {
+	issued []v3.Token
+	now    *time.Time
+}
+
+func (f *fakeIssuer) NewServiceKeyToken(userID string, userPrincipal v3.Principal, scope *v3.TokenScope, ttl int64, description string) (v3.Token, string, error) {
+	token := v3.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("token-%d", len(f.issued)+1),
+			CreationTimestamp: metav1.NewTime(*f.now),
+			Labels:            map[string]string{tokens.UserIDLabel: userID, tokens.TokenKindLabel: tokens.ServiceKeyTokenKind},
+		},
+		UserID:        userID,
+		UserPrincipal: userPrincipal,
+		Scope:         scope,
+		TTLMillis:     ttl,
+		Description:   description,
+		IsDerived:     true,
+	}
+	f.issued = append(f.issued, token)
+	return token, "secret", nil
+}
+
+type store struct {
+	users   map[string]*v3.User
+	prtbs   map[string]*v3.ProjectRoleTemplateBinding
+	tokens  map[string]*v3.Token
+	secrets map[string]*corev1.Secret
+}
+
+func setup(t *testing.T, now *time.Time) (*handler, *store, *fakeIssuer) {
+	ctrl := gomock.NewController(t)
+	s := &store{
+		users: map[string]*v3.User{},
+		prtbs: map[string]*v3.ProjectRoleTemplateBinding{
+			"owner": {
+				ObjectMeta:       metav1.ObjectMeta{Name: "owner", Namespace: "p-fghij"},
+				ProjectName:      projectID,
+				UserName:         "u-owner",
+				RoleTemplateName: "project-owner",
+			},
+		},
+		tokens:  map[string]*v3.Token{},
+		secrets: map[string]*corev1.Secret{},
+	}
+	notFound := func(resource, name string) error {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: resource}, name)
+	}
+
+	users := fake.NewMockNonNamespacedClientInterface[*v3.User, *v3.UserList](ctrl)
+	users.EXPECT().Create(gomock.Any()).DoAndReturn(func(u *v3.User) (*v3.User, error) {
+		if _, ok := s.users[u.Name]; ok {
+			return nil, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "users"}, u.Name)
+		}
+		u = u.DeepCopy()
+		u.CreationTimestamp = metav1.NewTime(*now)
+		s.users[u.Name] = u
+		return u, nil
+	}).AnyTimes()
+	users.EXPECT().Delete(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, _ *metav1.DeleteOptions) error {
+		delete(s.users, name)
+		return nil
+	}).AnyTimes()
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.User, error) {
+		if u, ok := s.users[name]; ok {
+			return u, nil
+		}
+		return nil, notFound("users", name)
+	}).AnyTimes()
+	userCache.EXPECT().List(gomock.Any()).DoAndReturn(func(selector labels.Selector) ([]*v3.User, error) {
+		var list []*v3.User
+		for _, u := range s.users {
+			if selector.Matches(labels.Set(u.Labels)) {
+				list = append(list, u)
+			}
+		}
+		return list, nil
+	}).AnyTimes()
+
+	projectCache := fake.NewMockCacheInterface[*v3.Project](ctrl)
+	projectCache.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string) (*v3.Project, error) {
+		if namespace == "c-abcde" && name == "p-fghij" {
+			return &v3.Project{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}, nil
+		}
+		return nil, notFound("projects", name)
+	}).AnyTimes()
+
+	roleTemplateCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	roleTemplateCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.RoleTemplate, error) {
+		switch name {
+		case "project-owner", "project-member", "read-only":
+			return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}, Context: "project"}, nil
+		case "cluster-owner":
+			return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}, Context: "cluster"}, nil
+		}
+		return nil, notFound("roletemplates", name)
+	}).AnyTimes()
+
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	prtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(binding *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+		s.prtbs[binding.Name] = binding.DeepCopy()
+		return binding, nil
+	}).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace string, _ labels.Selector) ([]*v3.ProjectRoleTemplateBinding, error) {
+		var list []*v3.ProjectRoleTemplateBinding
+		for _, binding := range s.prtbs {
+			if binding.Namespace == namespace {
+				list = append(list, binding)
+			}
+		}
+		return list, nil
+	}).AnyTimes()
+
+	tokenCache := fake.NewMockNonNamespacedCacheInterface[*v3.Token](ctrl)
+	tokenCache.EXPECT().List(gomock.Any()).DoAndReturn(func(selector labels.Selector) ([]*v3.Token, error) {
+		var list []*v3.Token
+		for _, token := range s.tokens {
+			if selector.Matches(labels.Set(token.Labels)) {
+				list = append(list, token)
+			}
+		}
+		return list, nil
+	}).AnyTimes()
+	tokenClient := fake.NewMockNonNamespacedClientInterface[*v3.Token, *v3.TokenList](ctrl)
+	tokenClient.EXPECT().Update(gomock.Any()).DoAndReturn(func(token *v3.Token) (*v3.Token, error) {
+		s.tokens[token.Name] = token.DeepCopy()
+		return token, nil
+	}).AnyTimes()
+
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(_, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := s.secrets[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, notFound("secrets", name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		s.secrets[secret.Name] = secret.DeepCopy()
+		return secret, nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		s.secrets[secret.Name] = secret.DeepCopy()
+		return secret, nil
+	}).AnyTimes()
+
+	issuer := &fakeIssuer{now: now}
+	h := &handler{
+		users:             users,
+		userCache:         userCache,
+		projectCache:      projectCache,
+		roleTemplateCache: roleTemplateCache,
+		prtbs:             prtbs,
+		prtbCache:         prtbCache,
+		tokenCache:        tokenCache,
+		tokens:            tokenClient,
+		secrets:           secrets,
+		subjectAccessReviews: &fakeSubjectAccessReviews{
+			allowed: map[string]bool{"u-owner": true, "u-admin": true},
+			binders: map[string]bool{"u-admin": true},
+		},
+		issuer: issuer,
+		now:    func() time.Time { return *now },
+	}
+	return h, s, issuer
+}
+
+func serve(h *handler, method, target, userID string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userID}))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeKey(t *testing.T, rec *httptest.ResponseRecorder) ServiceKey {
+	var key ServiceKey
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &key))
+	return key
+}
+
+// createKey creates a service key as the project owner, and adds its token to the store.
+func createKey(t *testing.T, h *handler, s *store, issuer *fakeIssuer, input createInput) ServiceKey {
+	rec := serve(h, http.MethodPost, BasePath+"/"+projectID, "u-owner", input)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	token := issuer.issued[len(issuer.issued)-1]
+	s.tokens[token.Name] = &token
+	return decodeKey(t, rec)
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s, issuer := setup(t, &now)
+
+	key := createKey(t, h, s, issuer, createInput{
+		Name:         "ci",
+		Description:  "deploys the apps",
+		Roles:        []string{"project-owner"},
+		Scope:        &v3.TokenScope{ReadOnly: true},
+		RotationDays: 30,
+	})
+	assert.Equal(t, "ci", key.Name)
+	assert.Equal(t, projectID, key.ProjectID)
+	assert.Equal(t, []string{"project-owner"}, key.Roles)
+	assert.Equal(t, 30, key.RotationDays)
+	assert.Equal(t, "u-owner", key.CreatedBy)
+	assert.Equal(t, "token-1:secret", key.Value)
+	assert.Equal(t, "2024-06-01T12:00:00Z", key.RotateBy)
+
+	u := s.users[key.ID]
+	require.NotNil(t, u)
+	assert.True(t, u.IsSystem())
+	assert.Empty(t, u.Username)
+	assert.Equal(t, projectID, ProjectID(u))
+	binding := s.prtbs[key.ID+"-project-owner"]
+	require.NotNil(t, binding)
+	assert.Equal(t, "p-fghij", binding.Namespace)
+	assert.Equal(t, projectID, binding.ProjectName)
+	assert.Equal(t, key.ID, binding.UserName)
+
+	token := issuer.issued[0]
+	assert.Equal(t, key.ID, token.UserID)
+	assert.Equal(t, "local://"+key.ID, token.UserPrincipal.Name)
+	assert.Equal(t, &v3.TokenScope{Projects: []string{projectID}, ReadOnly: true}, token.Scope)
+	assert.Equal(t, (31 * 24 * time.Hour).Milliseconds(), token.TTLMillis)
+
+	rec := serve(h, http.MethodGet, BasePath+"/"+projectID+"/"+key.ID+"/audit", "u-owner", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var trail struct {
+		Data []AuditEvent `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trail))
+	require.Len(t, trail.Data, 1)
+	assert.Equal(t, CreatedEvent, trail.Data[0].Event)
+	assert.Equal(t, "u-owner", trail.Data[0].Actor)
+
+	// The names of the keys are unique in a project.
+	rec = serve(h, http.MethodPost, BasePath+"/"+projectID, "u-owner", createInput{Name: "ci", Roles: []string{"project-owner"}})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = serve(h, http.MethodGet, BasePath+"/"+projectID, "u-owner", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Data []ServiceKey `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Empty(t, list.Data[0].Value)
+}
+
+func TestCreateRoles(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, _, _ := setup(t, &now)
+
+	tests := []struct {
+		name   string
+		caller string
+		input  createInput
+		status int
+	}{
+		{name: "role not held", caller: "u-owner", input: createInput{Name: "a", Roles: []string{"project-member"}}, status: http.StatusForbidden},
+		{name: "admins bind any role", caller: "u-admin", input: createInput{Name: "b", Roles: []string{"project-member"}}, status: http.StatusCreated},
+		{name: "cluster role", caller: "u-admin", input: createInput{Name: "c", Roles: []string{"cluster-owner"}}, status: http.StatusUnprocessableEntity},
+		{name: "unknown role", caller: "u-admin", input: createInput{Name: "d", Roles: []string{"unknown"}}, status: http.StatusUnprocessableEntity},
+		{name: "no roles", caller: "u-admin", input: createInput{Name: "e"}, status: http.StatusUnprocessableEntity},
+		{name: "rotation too long", caller: "u-admin", input: createInput{Name: "f", Roles: []string{"read-only"}, RotationDays: 91}, status: http.StatusUnprocessableEntity},
+		{name: "scope with projects", caller: "u-admin", input: createInput{Name: "g", Roles: []string{"read-only"}, Scope: &v3.TokenScope{Projects: []string{"c-x:p-y"}}}, status: http.StatusUnprocessableEntity},
+		{name: "invalid verb", caller: "u-admin", input: createInput{Name: "h", Roles: []string{"read-only"}, Scope: &v3.TokenScope{Verbs: []string{"escalate"}}}, status: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(h, http.MethodPost, BasePath+"/"+projectID, tt.caller, tt.input)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s, issuer := setup(t, &now)
+	key := createKey(t, h, s, issuer, createInput{Name: "ci", Roles: []string{"project-owner"}, RotationDays: 30})
+
+	now = now.Add(10 * 24 * time.Hour)
+	rec := serve(h, http.MethodPost, BasePath+"/"+projectID+"/"+key.ID+"?action=rotate", "u-owner", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rotated := decodeKey(t, rec)
+	assert.Equal(t, "token-2:secret", rotated.Value)
+	assert.Equal(t, "2024-06-11T12:00:00Z", rotated.RotateBy)
+
+	// The previous token keeps working for the grace period.
+	assert.Equal(t, now.Add(24*time.Hour), expiry(s.tokens["token-1"]))
+	s.tokens["token-2"] = &issuer.issued[1]
+
+	// The keys rotate themselves, but can't manage keys.
+	now = now.Add(time.Hour)
+	rec = serve(h, http.MethodPost, BasePath+"?action=rotate", key.ID, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "token-3:secret", decodeKey(t, rec).Value)
+	assert.Equal(t, now.Add(24*time.Hour), expiry(s.tokens["token-2"]))
+	// Tokens expiring sooner aren't extended.
+	assert.Equal(t, now.Add(23*time.Hour), expiry(s.tokens["token-1"]))
+
+	rec = serve(h, http.MethodPost, BasePath+"/"+projectID+"/"+key.ID+"?action=rotate", key.ID, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(h, http.MethodPost, BasePath+"?action=rotate", "u-owner", nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	events, err := h.events(s.users[key.ID])
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, RotatedEvent, events[1].Event)
+	assert.Equal(t, "u-owner", events[1].Actor)
+	assert.Equal(t, RotatedEvent, events[2].Event)
+	assert.Equal(t, key.ID, events[2].Actor)
+}
+
+func TestAccess(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, s, issuer := setup(t, &now)
+	key := createKey(t, h, s, issuer, createInput{Name: "ci", Roles: []string{"project-owner"}})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		caller string
+		status int
+	}{
+		{name: "project member", method: http.MethodGet, target: BasePath + "/" + projectID + "/" + key.ID, caller: "u-owner", status: http.StatusOK},
+		{name: "encoded project", method: http.MethodGet, target: BasePath + "/c-abcde%3Ap-fghij/" + key.ID, caller: "u-owner", status: http.StatusOK},
+		{name: "other user", method: http.MethodGet, target: BasePath + "/" + projectID, caller: "u-other", status: http.StatusForbidden},
+		{name: "invalid project", method: http.MethodGet, target: BasePath + "/p-fghij", caller: "u-owner", status: http.StatusBadRequest},
+		{name: "unknown key", method: http.MethodGet, target: BasePath + "/" + projectID + "/u-owner", caller: "u-owner", status: http.StatusNotFound},
+		{name: "delete", method: http.MethodDelete, target: BasePath + "/" + projectID + "/" + key.ID, caller: "u-owner", status: http.StatusNoContent},
+		{name: "deleted key", method: http.MethodGet, target: BasePath + "/" + projectID + "/" + key.ID, caller: "u-owner", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(h, tt.method, tt.target, tt.caller, nil)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/refreshtokens"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// logoutEverywhereAction revokes all the sessions of a user, including the current one, and the credentials cached for them.
+const logoutEverywhereAction = "logoutEverywhere"
+
+type secretDeleter interface {
+	Delete(namespace, name string, opts *metav1.DeleteOptions) error
+}
+
+// Revoker revokes the tokens of users. Deleting a token also deletes its ClusterAuthToken from the downstream clusters
+// with the authorized cluster endpoint, and the ClusterUserAttribute of the user with its last token.
+type Revoker struct {
+	tokenCache mgmtcontrollers.TokenCache
+	tokens     mgmtcontrollers.TokenClient
+	secrets    secretDeleter
+	families   familyRevoker
+}
+
+// NewRevoker returns a Revoker for the tokens of the management cluster.
+func NewRevoker(ctx context.Context, mgmt *config.ScaledContext) *Revoker {
+	return &Revoker{
+		tokenCache: mgmt.Wrangler.Mgmt.Token().Cache(),
+		tokens:     mgmt.Wrangler.Mgmt.Token(),
+		secrets:    mgmt.Wrangler.Core.Secret(),
+		families:   refreshtokens.NewIssuer(ctx, mgmt),
+	}
+}
+
+// LogoutEverywhere revokes all the tokens of the user: the tokens of its logins, the tokens derived from them, like the
+// API keys and kubeconfig tokens, and the refresh tokens of its logins. It also deletes the secrets of the auth provider
+// cached for the user, like its OAuth access token, so that they can't be used until the user logs in again.
+// The reason is recorded in the audit log, e.g. the IdP which requested the logout.
+func (r *Revoker) LogoutEverywhere(userID, reason string) (int, error) {
+	activeTokens, err := r.activeTokens(userID)
+	if err != nil {
+		return 0, fmt.Errorf("listing the tokens of user %s: %w", userID, err)
+	}
+
+	revoked := 0
+	for _, token := range activeTokens {
+		if err := r.revokeToken(token); err != nil {
+			return revoked, fmt.Errorf("revoking token %s: %w", token.Name, err)
+		}
+		revoked++
+	}
+
+	err = r.secrets.Delete(tokens.SecretNamespace, tokens.ProviderSecretName(userID), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return revoked, fmt.Errorf("deleting the provider secrets of user %s: %w", userID, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":   "LogoutEverywhere",
+		"user":    userID,
+		"revoked": revoked,
+		"reason":  reason,
+	}).Info("sessions: audit")
+	authevents.Record(authevents.Event{
+		Type:     authevents.Logout,
+		UserName: userID,
+		Reason:   reason,
+		Detail:   fmt.Sprintf("logged out everywhere, %d sessions revoked", revoked),
+	})
+	return revoked, nil
+}
+
+// revokeToken deletes the token. Tokens issued with a refresh token are revoked along with their family,
+// so that the login can't be refreshed anymore.
+func (r *Revoker) revokeToken(token *v3.Token) error {
+	if family := token.Labels[tokens.TokenFamilyLabel]; family != "" {
+		return r.families.RevokeFamily(family)
+	}
+	if err := r.tokens.Delete(token.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// activeTokens returns the enabled, unexpired tokens of the user.
+func (r *Revoker) activeTokens(userID string) ([]*v3.Token, error) {
+	all, err := r.tokenCache.List(labels.SelectorFromSet(labels.Set{tokens.UserIDLabel: userID}))
+	if err != nil {
+		return nil, err
+	}
+	var active []*v3.Token
+	for _, token := range all {
+		if token.UserID != userID || tokens.IsExpired(*token) || (token.Enabled != nil && !*token.Enabled) {
+			continue
+		}
+		active = append(active, token)
+	}
+	return active, nil
+}
+
+// logoutEverywhere logs the caller, or the user of the path for admins, out of all its sessions. The session cookies of
+// the caller are deleted, the request's token being revoked too.
+func (h *handler) logoutEverywhere(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := h.target(w, r, "delete")
+	if !ok {
+		return
+	}
+	// The caller was authenticated by target.
+	caller, _ := request.UserFrom(r.Context())
+	self := caller.GetName() == userID
+
+	revoked, err := h.LogoutEverywhere(userID, "requested by "+caller.GetName())
+	if err != nil {
+		logrus.Errorf("[sessions] failed to log user %s out everywhere: %v", userID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	if self {
+		for _, name := range []string{tokens.CookieName, tokens.CSRFCookie} {
+			http.SetCookie(w, &http.Cookie{
+				Name:     name,
+				Value:    "",
+				Secure:   r.URL.Scheme == "https",
+				Path:     "/",
+				HttpOnly: true,
+				MaxAge:   -1,
+				Expires:  time.Unix(0, 0),
+			})
+		}
+	}
+	util.WriteJSON(w, http.StatusOK, map[string]int{"revoked": revoked})
+}
@@ -0,0 +1,291 @@
+// Package sessions lets users list and revoke their active sessions, that is their unexpired tokens: the tokens of
+// their logins, and the tokens derived from them, like the API keys and kubeconfig tokens.
+// Users can also log out everywhere, which revokes all their sessions along with the credentials cached for them.
+// Admins, or anyone allowed to list and delete tokens, can do the same for any user. Those only allowed to delete tokens,
+// like the holders of the Revoke Tokens role, can list the sessions of any user too, as the sessions don't hold the keys
+// of their tokens. The revocations are recorded as auth events.
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	basePath = "/v1-sessions"
+
+	// revokeOthersAction revokes the sessions of the caller except the current one.
+	revokeOthersAction = "revokeOthers"
+	// revokeAllAction revokes all the sessions of a user.
+	revokeAllAction = "revokeAll"
+)
+
+// Session is an active token of a user.
+type Session struct {
+	ID           string       `json:"id"`
+	Kind         string       `json:"kind"`
+	Description  string       `json:"description,omitempty"`
+	AuthProvider string       `json:"authProvider,omitempty"`
+	ClusterName  string       `json:"clusterName,omitempty"`
+	CreatedAt    metav1.Time  `json:"createdAt"`
+	ExpiresAt    string       `json:"expiresAt,omitempty"`
+	LastUsedAt   *metav1.Time `json:"lastUsedAt,omitempty"`
+	// SourceIP and UserAgent are the client address and device of the last recorded use of the token.
+	SourceIP  string `json:"sourceIp,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Current   bool   `json:"current"`
+}
+
+type familyRevoker interface {
+	RevokeFamily(family string) error
+}
+
+type handler struct {
+	*Revoker
+	secretCache          wcorev1.SecretCache
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewHandler returns the handler of the session management endpoints.
+func NewHandler(ctx context.Context, mgmt *config.ScaledContext) http.Handler {
+	h := &handler{
+		Revoker:              NewRevoker(ctx, mgmt),
+		secretCache:          mgmt.Wrangler.Core.Secret().Cache(),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+	}
+	return h.router()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodGet).Path(basePath).HandlerFunc(h.list)
+	root.Methods(http.MethodPost).Path(basePath).Queries("action", revokeOthersAction).HandlerFunc(h.revokeOthers)
+	root.Methods(http.MethodPost).Path(basePath).Queries("action", logoutEverywhereAction).HandlerFunc(h.logoutEverywhere)
+	root.Methods(http.MethodGet).Path(basePath + "/users/{user}").HandlerFunc(h.list)
+	root.Methods(http.MethodPost).Path(basePath+"/users/{user}").Queries("action", revokeAllAction).HandlerFunc(h.revokeAll)
+	root.Methods(http.MethodPost).Path(basePath+"/users/{user}").Queries("action", logoutEverywhereAction).HandlerFunc(h.logoutEverywhere)
+	root.Methods(http.MethodDelete).Path(basePath + "/users/{user}/{id}").HandlerFunc(h.revoke)
+	root.Methods(http.MethodDelete).Path(basePath + "/{id}").HandlerFunc(h.revoke)
+	return root
+}
+
+// list writes the active sessions of the caller, or of the user of the path for admins, most recent first.
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	userID, currentToken, ok := h.target(w, r, "list", "delete")
+	if !ok {
+		return
+	}
+	activeTokens, err := h.activeTokens(userID)
+	if err != nil {
+		logrus.Errorf("[sessions] failed to list the tokens of user %s: %v", userID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to list sessions")
+		return
+	}
+
+	sessions := make([]Session, 0, len(activeTokens))
+	for _, token := range activeTokens {
+		sessions = append(sessions, h.session(token, currentToken))
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[j].CreatedAt.Before(&sessions[i].CreatedAt)
+	})
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"type": "collection",
+		"data": sessions,
+	})
+}
+
+// revoke revokes a session of the caller, or of the user of the path for admins.
+func (h *handler) revoke(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := h.target(w, r, "delete")
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	token, err := h.tokenCache.Get(id)
+	if err != nil || token.UserID != userID {
+		if err != nil && !apierrors.IsNotFound(err) {
+			logrus.Errorf("[sessions] failed to get token %s: %v", id, err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to revoke session")
+			return
+		}
+		util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("session %s not found", id))
+		return
+	}
+
+	if err := h.revokeToken(token); err != nil {
+		logrus.Errorf("[sessions] failed to revoke token %s: %v", id, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to revoke session")
+		return
+	}
+	recordRevoked(r, token)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokeOthers revokes the sessions of the caller except the one of the request.
+func (h *handler) revokeOthers(w http.ResponseWriter, r *http.Request) {
+	userID, currentToken, ok := h.target(w, r, "delete")
+	if !ok {
+		return
+	}
+	h.revokeTokens(w, r, userID, currentToken)
+}
+
+// revokeAll revokes all the sessions of the user of the path.
+func (h *handler) revokeAll(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := h.target(w, r, "delete")
+	if !ok {
+		return
+	}
+	h.revokeTokens(w, r, userID, "")
+}
+
+func (h *handler) revokeTokens(w http.ResponseWriter, r *http.Request, userID, keep string) {
+	activeTokens, err := h.activeTokens(userID)
+	if err != nil {
+		logrus.Errorf("[sessions] failed to list the tokens of user %s: %v", userID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	var keepToken *v3.Token
+	if keep != "" {
+		keepToken, _ = h.tokenCache.Get(keep)
+	}
+
+	revoked := 0
+	for _, token := range activeTokens {
+		if token.Name == keep {
+			continue
+		}
+		// The tokens of the login the request is part of, like its refreshed tokens, are kept too.
+		if keepToken != nil && sameFamily(token, keepToken) {
+			continue
+		}
+		if err := h.revokeToken(token); err != nil {
+			logrus.Errorf("[sessions] failed to revoke token %s: %v", token.Name, err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to revoke sessions")
+			return
+		}
+		recordRevoked(r, token)
+		revoked++
+	}
+	util.WriteJSON(w, http.StatusOK, map[string]int{"revoked": revoked})
+}
+
+// target returns the user whose sessions the request is for, and the token of the request.
+// Requests for the sessions of another user are only allowed to those who can apply one of the verbs to any token.
+func (h *handler) target(w http.ResponseWriter, r *http.Request, verbs ...string) (string, string, bool) {
+	userInfo, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return "", "", false
+	}
+	var currentToken string
+	if ids := userInfo.GetExtra()[common.ExtraRequestTokenID]; len(ids) > 0 {
+		currentToken = ids[0]
+	}
+
+	userID, ok := mux.Vars(r)["user"]
+	if !ok || userID == userInfo.GetName() {
+		return userInfo.GetName(), currentToken, true
+	}
+
+	for _, verb := range verbs {
+		allowed, err := h.authorize(r.Context(), userInfo, verb)
+		if err != nil {
+			logrus.Errorf("[sessions] failed to authorize user %s: %v", userInfo.GetName(), err)
+			util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+			return "", "", false
+		}
+		if allowed {
+			return userID, currentToken, true
+		}
+	}
+	util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to %s the sessions of user %s", verbs[0], userID))
+	return "", "", false
+}
+
+// recordRevoked records the revocation of the token by the caller of the request.
+func recordRevoked(r *http.Request, token *v3.Token) {
+	event := authevents.Event{
+		Type:     authevents.TokenRevoked,
+		UserName: token.UserID,
+		Provider: token.AuthProvider,
+		Target:   token.Name,
+	}
+	if caller, ok := request.UserFrom(r.Context()); ok && caller.GetName() != token.UserID {
+		event.Actor = caller.GetName()
+	}
+	authevents.Record(event)
+}
+
+// authorize tells whether the user can apply the verb to all tokens.
+func (h *handler) authorize(ctx context.Context, userInfo user.Info, verb string) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    mgmtv3.TokenGroupVersionKind.Group,
+		Resource: mgmtv3.TokenResource.Name,
+		Verb:     verb,
+	})
+}
+
+func (h *handler) session(token *v3.Token, currentToken string) Session {
+	kind := token.Labels[tokens.TokenKindLabel]
+	if kind == "" {
+		kind = "session"
+		if token.IsDerived {
+			kind = "derived"
+		}
+	}
+	token = token.DeepCopy()
+	tokens.SetTokenExpiresAt(token)
+	session := Session{
+		ID:           token.Name,
+		Kind:         kind,
+		Description:  token.Description,
+		AuthProvider: token.AuthProvider,
+		ClusterName:  token.ClusterName,
+		CreatedAt:    token.CreationTimestamp,
+		ExpiresAt:    token.ExpiresAt,
+		LastUsedAt:   token.LastUsedAt,
+		Current:      token.Name == currentToken,
+	}
+
+	secret, err := h.secretCache.Get(tokens.SecretNamespace, tokens.UsageSecretName(token.Name))
+	if err != nil {
+		return session
+	}
+	if events, err := tokens.ParseUsageEvents(secret); err == nil && len(events) > 0 {
+		last := events[len(events)-1]
+		session.SourceIP = last.SourceIP
+		session.UserAgent = last.UserAgent
+	}
+	return session
+}
+
+// sameFamily tells whether the tokens were issued for the same login with refresh tokens.
+func sameFamily(a, b *v3.Token) bool {
+	family := a.Labels[tokens.TokenFamilyLabel]
+	return family != "" && family == b.Labels[tokens.TokenFamilyLabel]
+}
@@ -0,0 +1,285 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	allowed map[string]bool
+	// verbs holds the only verb allowed to each user which isn't allowed everything.
+	verbs map[string]string
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	sar.Status.Allowed = f.allowed[sar.Spec.User] || f.verbs[sar.Spec.User] == sar.Spec.ResourceAttributes.Verb
+	return sar, nil
+}
+
+type fakeFamilies struct {
+	revoked []string
+}
+
+func (f *fakeFamilies) RevokeFamily(family string) error {
+	f.revoked = append(f.revoked, family)
+	return nil
+}
+
+func newToken(name, userID string, created time.Time, lbls map[string]string) *v3.Token {
+	tokenLabels := map[string]string{tokens.UserIDLabel: userID}
+	for k, v := range lbls {
+		tokenLabels[k] = v
+	}
+	return &v3.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            tokenLabels,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		UserID: userID,
+	}
+}
+
+type fakeSecrets struct {
+	deleted []string
+}
+
+func (f *fakeSecrets) Delete(namespace, name string, _ *metav1.DeleteOptions) error {
+	f.deleted = append(f.deleted, namespace+"/"+name)
+	return nil
+}
+
+func setup(t *testing.T, stored map[string]*v3.Token) (*handler, *[]string, *fakeFamilies) {
+	ctrl := gomock.NewController(t)
+	gr := schema.GroupResource{Group: "management.cattle.io", Resource: "tokens"}
+
+	tokenCache := fake.NewMockNonNamespacedCacheInterface[*v3.Token](ctrl)
+	tokenCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.Token, error) {
+		if token, ok := stored[name]; ok {
+			return token, nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	tokenCache.EXPECT().List(gomock.Any()).DoAndReturn(func(selector labels.Selector) ([]*v3.Token, error) {
+		var list []*v3.Token
+		for _, token := range stored {
+			if selector.Matches(labels.Set(token.Labels)) {
+				list = append(list, token)
+			}
+		}
+		return list, nil
+	}).AnyTimes()
+
+	var deleted []string
+	tokenClient := fake.NewMockNonNamespacedClientInterface[*v3.Token, *v3.TokenList](ctrl)
+	tokenClient.EXPECT().Delete(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, _ *metav1.DeleteOptions) error {
+		deleted = append(deleted, name)
+		return nil
+	}).AnyTimes()
+
+	usage, err := json.Marshal([]tokens.UsageEvent{{SourceIP: "203.0.113.5", UserAgent: "Mozilla/5.0"}})
+	require.NoError(t, err)
+	secretCache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+	secretCache.EXPECT().Get(tokens.SecretNamespace, gomock.Any()).DoAndReturn(func(namespace, name string) (*corev1.Secret, error) {
+		if name == tokens.UsageSecretName("token-current") {
+			return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name}, Data: map[string][]byte{"events": usage}}, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}).AnyTimes()
+
+	families := &fakeFamilies{}
+	return &handler{
+		Revoker: &Revoker{
+			tokenCache: tokenCache,
+			tokens:     tokenClient,
+			secrets:    &fakeSecrets{},
+			families:   families,
+		},
+		secretCache: secretCache,
+		subjectAccessReviews: &fakeSubjectAccessReviews{
+			allowed: map[string]bool{"u-admin": true},
+			verbs:   map[string]string{"u-revoker": "delete"},
+		},
+	}, &deleted, families
+}
+
+func serve(h *handler, method, target, userID, tokenID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{
+		Name:  userID,
+		Extra: map[string][]string{common.ExtraRequestTokenID: {tokenID}},
+	}))
+	rec := httptest.NewRecorder()
+	h.router().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListSessions(t *testing.T) {
+	now := time.Now()
+	disabled := false
+	stored := map[string]*v3.Token{
+		"token-current": newToken("token-current", "u-abcde", now, nil),
+		"token-apikey":  newToken("token-apikey", "u-abcde", now.Add(-time.Hour), nil),
+		"token-expired": newToken("token-expired", "u-abcde", now.Add(-2*time.Hour), nil),
+		"token-other":   newToken("token-other", "u-fghij", now, nil),
+	}
+	stored["token-apikey"].IsDerived = true
+	stored["token-expired"].TTLMillis = time.Minute.Milliseconds()
+	stored["token-disabled"] = newToken("token-disabled", "u-abcde", now, nil)
+	stored["token-disabled"].Enabled = &disabled
+	h, _, _ := setup(t, stored)
+
+	rec := serve(h, http.MethodGet, "/v1-sessions", "u-abcde", "token-current")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data []Session `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, "token-current", response.Data[0].ID)
+	assert.Equal(t, "session", response.Data[0].Kind)
+	assert.True(t, response.Data[0].Current)
+	assert.Equal(t, "203.0.113.5", response.Data[0].SourceIP)
+	assert.Equal(t, "Mozilla/5.0", response.Data[0].UserAgent)
+	assert.Equal(t, "token-apikey", response.Data[1].ID)
+	assert.Equal(t, "derived", response.Data[1].Kind)
+	assert.False(t, response.Data[1].Current)
+
+	// Only admins can list the sessions of other users.
+	rec = serve(h, http.MethodGet, "/v1-sessions/users/u-fghij", "u-abcde", "token-current")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(h, http.MethodGet, "/v1-sessions/users/u-fghij", "u-admin", "token-admin")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "token-other", response.Data[0].ID)
+
+	// Those only allowed to delete tokens can list the sessions of other users too, as they don't hold the keys.
+	rec = serve(h, http.MethodGet, "/v1-sessions/users/u-fghij", "u-revoker", "token-revoker")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "token-other", response.Data[0].ID)
+}
+
+func TestRevokeSession(t *testing.T) {
+	now := time.Now()
+	stored := map[string]*v3.Token{
+		"token-current":   newToken("token-current", "u-abcde", now, nil),
+		"token-apikey":    newToken("token-apikey", "u-abcde", now, nil),
+		"token-refreshed": newToken("token-refreshed", "u-abcde", now, map[string]string{tokens.TokenFamilyLabel: "family1"}),
+		"token-other":     newToken("token-other", "u-fghij", now, nil),
+	}
+	h, deleted, families := setup(t, stored)
+
+	rec := serve(h, http.MethodDelete, "/v1-sessions/token-apikey", "u-abcde", "token-current")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []string{"token-apikey"}, *deleted)
+
+	// Tokens issued with a refresh token are revoked with their family.
+	rec = serve(h, http.MethodDelete, "/v1-sessions/token-refreshed", "u-abcde", "token-current")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []string{"family1"}, families.revoked)
+
+	// The sessions of other users aren't found.
+	rec = serve(h, http.MethodDelete, "/v1-sessions/token-other", "u-abcde", "token-current")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = serve(h, http.MethodDelete, "/v1-sessions/users/u-fghij/token-other", "u-abcde", "token-current")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	hook := logrusTest.NewGlobal()
+	defer hook.Reset()
+	rec = serve(h, http.MethodDelete, "/v1-sessions/users/u-fghij/token-other", "u-admin", "token-admin")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []string{"token-apikey", "token-other"}, *deleted)
+
+	// The revocation is recorded with the admin as its actor.
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "authevents: audit", entry.Message)
+	assert.Equal(t, authevents.TokenRevoked, entry.Data["event"])
+	assert.Equal(t, "u-fghij", entry.Data["user"])
+	assert.Equal(t, "u-admin", entry.Data["actor"])
+	assert.Equal(t, "token-other", entry.Data["target"])
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	now := time.Now()
+	stored := map[string]*v3.Token{
+		"token-current":   newToken("token-current", "u-abcde", now, map[string]string{tokens.TokenFamilyLabel: "family1"}),
+		"token-previous":  newToken("token-previous", "u-abcde", now, map[string]string{tokens.TokenFamilyLabel: "family1"}),
+		"token-apikey":    newToken("token-apikey", "u-abcde", now, nil),
+		"token-refreshed": newToken("token-refreshed", "u-abcde", now, map[string]string{tokens.TokenFamilyLabel: "family2"}),
+		"token-other":     newToken("token-other", "u-fghij", now, nil),
+	}
+	h, deleted, families := setup(t, stored)
+
+	rec := serve(h, http.MethodPost, "/v1-sessions?action=revokeOthers", "u-abcde", "token-current")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"revoked": 2}`, rec.Body.String())
+	assert.Equal(t, []string{"token-apikey"}, *deleted)
+	assert.Equal(t, []string{"family2"}, families.revoked)
+
+	rec = serve(h, http.MethodPost, "/v1-sessions/users/u-fghij?action=revokeAll", "u-admin", "token-admin")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"token-apikey", "token-other"}, *deleted)
+}
+
+func TestLogoutEverywhere(t *testing.T) {
+	now := time.Now()
+	stored := map[string]*v3.Token{
+		"token-current":    newToken("token-current", "u-abcde", now, map[string]string{tokens.TokenFamilyLabel: "family1"}),
+		"token-kubeconfig": newToken("token-kubeconfig", "u-abcde", now, nil),
+		"token-apikey":     newToken("token-apikey", "u-abcde", now, nil),
+		"token-other":      newToken("token-other", "u-fghij", now, nil),
+	}
+	stored["token-kubeconfig"].IsDerived = true
+	stored["token-kubeconfig"].ClusterName = "c-m-12345"
+	h, deleted, families := setup(t, stored)
+
+	rec := serve(h, http.MethodPost, "/v1-sessions?action=logoutEverywhere", "u-abcde", "token-current")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"revoked": 3}`, rec.Body.String())
+	assert.ElementsMatch(t, []string{"token-kubeconfig", "token-apikey"}, *deleted)
+	assert.Equal(t, []string{"family1"}, families.revoked)
+	assert.Equal(t, []string{tokens.SecretNamespace + "/" + tokens.ProviderSecretName("u-abcde")}, h.secrets.(*fakeSecrets).deleted)
+
+	// The session cookies are deleted, the current session being revoked.
+	cookies := map[string]bool{}
+	for _, cookie := range rec.Result().Cookies() {
+		cookies[cookie.Name] = cookie.MaxAge < 0
+	}
+	assert.Equal(t, map[string]bool{tokens.CookieName: true, tokens.CSRFCookie: true}, cookies)
+
+	// Only admins can log other users out everywhere, and their own cookies are kept.
+	rec = serve(h, http.MethodPost, "/v1-sessions/users/u-fghij?action=logoutEverywhere", "u-abcde", "token-current")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = serve(h, http.MethodPost, "/v1-sessions/users/u-fghij?action=logoutEverywhere", "u-admin", "token-admin")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"revoked": 1}`, rec.Body.String())
+	assert.Contains(t, *deleted, "token-other")
+	assert.Empty(t, rec.Result().Cookies())
+}
@@ -0,0 +1,192 @@
+package tokenexchange
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// Federation trusts the tokens of an external OIDC issuer, like the ID tokens of the jobs of a CI system, to be
+// exchanged for tokens of a Rancher user.
+type Federation struct {
+	// Name identifies the federation in the tokens it issues.
+	Name string `json:"name"`
+	// Issuer is the issuer URL of the external tokens, whose keys are found with OIDC discovery.
+	Issuer string `json:"issuer"`
+	// Audience is the audience the external tokens must be issued for, usually the URL of Rancher.
+	Audience string `json:"audience"`
+	// Claims are the claims the external tokens must have. The values are patterns in the syntax of path.Match, as in
+	// "repo:example/app:ref:refs/heads/*". At least one is required, the issuers of CI systems are shared by everyone.
+	Claims map[string]string `json:"claims"`
+	// UserID is the Rancher user the issued tokens act as.
+	UserID string `json:"userId"`
+	// Scope is the scope of the issued tokens, in the syntax of the scope parameter. Requests can only narrow it.
+	Scope string `json:"scope,omitempty"`
+	// MaxTTLMinutes caps the time to live of the issued tokens, below auth-token-exchange-max-ttl-minutes.
+	MaxTTLMinutes int `json:"maxTTLMinutes,omitempty"`
+}
+
+// parseFederations returns the federations of the auth-token-federation setting.
+func parseFederations(value string) ([]Federation, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var federations []Federation
+	if err := json.Unmarshal([]byte(value), &federations); err != nil {
+		return nil, fmt.Errorf("invalid federations: %w", err)
+	}
+	names := map[string]bool{}
+	for _, federation := range federations {
+		switch {
+		case federation.Name == "":
+			return nil, fmt.Errorf("federation of issuer %s has no name", federation.Issuer)
+		case names[federation.Name]:
+			return nil, fmt.Errorf("duplicate federation %s", federation.Name)
+		case federation.Issuer == "":
+			return nil, fmt.Errorf("federation %s has no issuer", federation.Name)
+		case federation.Audience == "":
+			return nil, fmt.Errorf("federation %s has no audience", federation.Name)
+		case len(federation.Claims) == 0:
+			return nil, fmt.Errorf("federation %s has no claims", federation.Name)
+		case federation.UserID == "":
+			return nil, fmt.Errorf("federation %s has no user", federation.Name)
+		case federation.MaxTTLMinutes < 0:
+			return nil, fmt.Errorf("federation %s has a negative maxTTLMinutes", federation.Name)
+		}
+		names[federation.Name] = true
+		for claim, pattern := range federation.Claims {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("federation %s has an invalid pattern for claim %s: %w", federation.Name, claim, err)
+			}
+		}
+		if _, err := parseScope(federation.Scope); err != nil {
+			return nil, fmt.Errorf("federation %s has an invalid scope: %w", federation.Name, err)
+		}
+	}
+	return federations, nil
+}
+
+// matches tells whether the claims of a verified external token have the claims of the federation.
+func (f *Federation) matches(claims map[string]interface{}) bool {
+	for claim, pattern := range f.Claims {
+		value, ok := claims[claim]
+		if !ok {
+			return false
+		}
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case bool, float64:
+			s = fmt.Sprint(v)
+		default:
+			return false
+		}
+		if matched, _ := path.Match(pattern, s); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// subject returns the subject of an external token, with the claims the federation matched, for the audit trail.
+func (f *Federation) subject(claims map[string]interface{}) string {
+	keys := make([]string, 0, len(f.Claims))
+	for claim := range f.Claims {
+		keys = append(keys, claim)
+	}
+	sort.Strings(keys)
+
+	subject := f.Name
+	if sub, ok := claims["sub"].(string); ok {
+		subject += ":" + sub
+	}
+	for _, claim := range keys {
+		if claim != "sub" {
+			subject += fmt.Sprintf(",%s=%v", claim, claims[claim])
+		}
+	}
+	return subject
+}
+
+// unverifiedIssuer returns the iss claim of a JWT, without verifying it, to find the federation to verify it with.
+func unverifiedIssuer(raw string) (string, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed JWT payload: %w", err)
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("malformed JWT claims: %w", err)
+	}
+	if claims.Issuer == "" {
+		return "", fmt.Errorf("JWT has no issuer")
+	}
+	return claims.Issuer, nil
+}
+
+type federatedVerifier interface {
+	Verify(issuer, audience, raw string) (map[string]interface{}, error)
+}
+
+// oidcVerifier verifies the external tokens with the keys found with OIDC discovery. The providers are cached per
+// issuer, they cache the keys and fetch them again when the issuer rotates them.
+type oidcVerifier struct {
+	ctx       context.Context
+	mu        sync.Mutex
+	providers map[string]*oidc.Provider
+}
+
+func newOIDCVerifier(ctx context.Context) *oidcVerifier {
+	return &oidcVerifier{
+		ctx:       ctx,
+		providers: map[string]*oidc.Provider{},
+	}
+}
+
+// Verify returns the claims of the external token if it's signed by the issuer, for the audience, and not expired.
+func (v *oidcVerifier) Verify(issuer, audience, raw string) (map[string]interface{}, error) {
+	provider, err := v.provider(issuer)
+	if err != nil {
+		return nil, err
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: audience}).Verify(v.ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	claims := map[string]interface{}{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("decoding claims: %w", err)
+	}
+	return claims, nil
+}
+
+func (v *oidcVerifier) provider(issuer string) (*oidc.Provider, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if provider, ok := v.providers[issuer]; ok {
+		return provider, nil
+	}
+	// The context of the provider is used to fetch the keys for as long as the provider is cached.
+	provider, err := oidc.NewProvider(v.ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovering issuer %s: %w", issuer, err)
+	}
+	v.providers[issuer] = provider
+	return provider, nil
+}
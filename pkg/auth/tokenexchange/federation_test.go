@@ -0,0 +1,214 @@
+package tokenexchange
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeVerifier accepts the tokens of fakeJWT, for the audience they were issued for.
+type fakeVerifier struct{}
+
+func (f *fakeVerifier) Verify(issuer, audience, raw string) (map[string]interface{}, error) {
+	claims, err := decodeClaims(raw)
+	if err != nil {
+		return nil, err
+	}
+	if claims["iss"] != issuer || claims["aud"] != audience {
+		return nil, fmt.Errorf("invalid issuer or audience")
+	}
+	return claims, nil
+}
+
+func fakeJWT(t *testing.T, claims map[string]interface{}) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return "header." + base64.RawURLEncoding.EncodeToString(payload) + ".signature"
+}
+
+func decodeClaims(raw string) (map[string]interface{}, error) {
+	if _, err := unverifiedIssuer(raw); err != nil {
+		return nil, err
+	}
+	payload, _ := base64.RawURLEncoding.DecodeString(raw[len("header.") : len(raw)-len(".signature")])
+	claims := map[string]interface{}{}
+	return claims, json.Unmarshal(payload, &claims)
+}
+
+const (
+	githubIssuer = "https://token.actions.githubusercontent.com"
+	audience     = "https://rancher.example.com"
+)
+
+func githubFederation(t *testing.T) string {
+	federations, err := json.Marshal([]Federation{{
+		Name:          "github-actions",
+		Issuer:        githubIssuer,
+		Audience:      audience,
+		Claims:        map[string]string{"repository": "example/app", "ref": "refs/heads/*"},
+		UserID:        "u-ci",
+		Scope:         "cluster:c-m-12345",
+		MaxTTLMinutes: 15,
+	}})
+	require.NoError(t, err)
+	return string(federations)
+}
+
+func federatedForm(subjectToken, scope string) url.Values {
+	form := exchangeForm(subjectToken, scope)
+	form.Set("subject_token_type", JWTTokenType)
+	return form
+}
+
+func TestExchangeFederated(t *testing.T) {
+	env := newTestEnv(t)
+	env.users["u-ci"] = &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-ci"}, Username: "ci"}
+	env.federations = githubFederation(t)
+
+	value := fakeJWT(t, map[string]interface{}{
+		"iss":        githubIssuer,
+		"aud":        audience,
+		"sub":        "repo:example/app:ref:refs/heads/main",
+		"repository": "example/app",
+		"ref":        "refs/heads/main",
+	})
+	rec, body := env.exchange(federatedForm(value, "read-only"))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "token-federated:key", body["access_token"])
+	assert.Equal(t, AccessTokenType, body["issued_token_type"])
+	assert.Equal(t, float64(900), body["expires_in"])
+	assert.Equal(t, "read-only cluster:c-m-12345", body["scope"])
+
+	require.Equal(t, 1, env.tokenMGR.calls)
+	assert.Equal(t, "u-ci", env.tokenMGR.userID)
+	assert.Equal(t, &v3.TokenScope{Clusters: []string{"c-m-12345"}, ReadOnly: true}, env.tokenMGR.scope)
+	assert.Equal(t, (15 * time.Minute).Milliseconds(), env.tokenMGR.ttl)
+	assert.Equal(t, "github-actions:repo:example/app:ref:refs/heads/main,ref=refs/heads/main,repository=example/app", env.tokenMGR.federatedSubject)
+}
+
+func TestExchangeFederatedRejected(t *testing.T) {
+	disabled := false
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":        githubIssuer,
+			"aud":        audience,
+			"sub":        "repo:example/app:ref:refs/heads/main",
+			"repository": "example/app",
+			"ref":        "refs/heads/main",
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		value  string
+		scope  string
+		setup  func(env *testEnv)
+		code   string
+	}{
+		{
+			name:   "no federations",
+			claims: claims(nil),
+			setup:  func(env *testEnv) { env.federations = "" },
+			code:   errInvalidGrant,
+		},
+		{
+			name:  "malformed token",
+			value: "not-a-jwt",
+			code:  errInvalidGrant,
+		},
+		{
+			name:   "untrusted issuer",
+			claims: claims(map[string]interface{}{"iss": "https://gitlab.example.com"}),
+			code:   errInvalidGrant,
+		},
+		{
+			name:   "wrong audience",
+			claims: claims(map[string]interface{}{"aud": "https://other.example.com"}),
+			code:   errInvalidGrant,
+		},
+		{
+			name:   "other repository",
+			claims: claims(map[string]interface{}{"repository": "attacker/app"}),
+			code:   errInvalidGrant,
+		},
+		{
+			name:   "missing claim",
+			claims: claims(map[string]interface{}{"ref": nil}),
+			code:   errInvalidGrant,
+		},
+		{
+			name:   "disabled user",
+			claims: claims(nil),
+			setup:  func(env *testEnv) { env.users["u-ci"].Enabled = &disabled },
+			code:   errInvalidGrant,
+		},
+		{
+			name:   "wider scope",
+			claims: claims(nil),
+			scope:  "cluster:c-m-67890",
+			code:   errInvalidScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.users["u-ci"] = &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-ci"}}
+			env.federations = githubFederation(t)
+			if tt.setup != nil {
+				tt.setup(env)
+			}
+			value := tt.value
+			if value == "" {
+				value = fakeJWT(t, tt.claims)
+			}
+
+			rec, body := env.exchange(federatedForm(value, tt.scope))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, tt.code, body["error"])
+			assert.Zero(t, env.tokenMGR.calls)
+		})
+	}
+}
+
+func TestParseFederations(t *testing.T) {
+	valid := `{"name":"ci","issuer":"https://ci.example.com","audience":"rancher","claims":{"sub":"project:*"},"userId":"u-ci"}`
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "empty"},
+		{name: "valid", value: "[" + valid + "]"},
+		{name: "invalid JSON", value: "{", wantErr: "invalid federations"},
+		{name: "duplicate", value: "[" + valid + "," + valid + "]", wantErr: "duplicate federation ci"},
+		{name: "no claims", value: `[{"name":"ci","issuer":"https://ci.example.com","audience":"rancher","userId":"u-ci"}]`, wantErr: "has no claims"},
+		{name: "no user", value: `[{"name":"ci","issuer":"https://ci.example.com","audience":"rancher","claims":{"sub":"x"}}]`, wantErr: "has no user"},
+		{name: "invalid pattern", value: `[{"name":"ci","issuer":"https://ci.example.com","audience":"rancher","claims":{"sub":"["},"userId":"u-ci"}]`, wantErr: "invalid pattern"},
+		{name: "invalid scope", value: `[{"name":"ci","issuer":"https://ci.example.com","audience":"rancher","claims":{"sub":"x"},"userId":"u-ci","scope":"admin"}]`, wantErr: "invalid scope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFederations(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
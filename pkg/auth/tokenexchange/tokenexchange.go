@@ -0,0 +1,503 @@
+// Package tokenexchange implements the token exchange of RFC 8693, for clients to trade a Rancher token for a derived
+// token with a narrower scope and a shorter time to live, like a read-only token for a single cluster handed to a CI
+// pipeline. An exchanged token can't access anything the token it was exchanged for can't, nor outlive it.
+// The JWTs of the external issuers trusted by auth-token-federation, like the ID tokens of CI jobs, are exchanged for
+// short-lived tokens of the user of their federation, so that CI systems don't need long-lived Rancher tokens.
+package tokenexchange
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/jwttokens"
+	"github.com/rancher/rancher/pkg/auth/providers"
+	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/clusterrouter"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GrantType is the grant_type of the token exchange requests, see RFC 8693 section 2.1.
+	GrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// AccessTokenType is the type of the tokens exchanged and issued, see RFC 8693 section 3.
+	AccessTokenType = "urn:ietf:params:oauth:token-type:access_token"
+	// JWTTokenType is the type of the tokens issued as JWTs, when auth-jwt-tokens-enabled is set, and of the external
+	// tokens of the federations.
+	JWTTokenType = "urn:ietf:params:oauth:token-type:jwt"
+
+	// TokenPath is the path of the token exchange endpoint.
+	TokenPath = "/v1-token/exchange"
+
+	// defaultTTL applies when auth-token-exchange-max-ttl-minutes isn't a positive number of minutes.
+	defaultTTL = time.Hour
+)
+
+// The values of the scope parameter, separated by spaces. Each one restricts the issued token further.
+const (
+	// ReadOnlyScope restricts the token to the get, list and watch verbs.
+	ReadOnlyScope = "read-only"
+	// ClusterScopePrefix restricts the token to a cluster, as in cluster:c-m-abcdef.
+	ClusterScopePrefix = "cluster:"
+	// ProjectScopePrefix restricts the token to a project, as in project:c-m-abcdef:p-ghijk.
+	ProjectScopePrefix = "project:"
+	// APIGroupScopePrefix restricts the token to an API group, as in group:apps, or group: for the core group.
+	APIGroupScopePrefix = "group:"
+	// VerbScopePrefix restricts the token to a verb, as in verb:get.
+	VerbScopePrefix = "verb:"
+)
+
+// Error codes of the token endpoint, see RFC 6749 section 5.2.
+const (
+	errInvalidGrant         = "invalid_grant"
+	errInvalidRequest       = "invalid_request"
+	errInvalidScope         = "invalid_scope"
+	errUnsupportedGrantType = "unsupported_grant_type"
+	errServerError          = "server_error"
+)
+
+// exchangeableTokenKinds are the kinds of the tokens that can be exchanged: the login sessions, and the API tokens,
+// which have no kind. The other tokens are bound to a purpose, like MFA enrollment or login as, the exchanged token
+// would escape.
+var exchangeableTokenKinds = []string{"session", ""}
+
+type tokenAuthenticator interface {
+	TokenFromRequest(req *http.Request) (accessor.TokenAccessor, error)
+}
+
+type tokenManager interface {
+	NewExchangedToken(subject *v3.Token, scope *v3.TokenScope, ttl int64, description string) (v3.Token, string, error)
+	NewFederatedToken(userID string, userPrincipal v3.Principal, scope *v3.TokenScope, ttl int64, description, subject string) (v3.Token, string, error)
+}
+
+type jwtSigner interface {
+	Sign(token *v3.Token) (string, error)
+}
+
+type handler struct {
+	auth               tokenAuthenticator
+	tokenMGR           tokenManager
+	jwtSigner          jwtSigner
+	jwtEnabled         func() bool
+	userCache          mgmtcontrollers.UserCache
+	isDisabledProvider func(providerName string) (bool, error)
+	maxTTL             func() string
+	federations        func() string
+	verifier           federatedVerifier
+	now                func() time.Time
+}
+
+// NewHandler returns the handler of the token exchange endpoint.
+func NewHandler(ctx context.Context, mgmt *config.ScaledContext) http.Handler {
+	h := &handler{
+		auth:               requests.NewAuthenticator(ctx, clusterrouter.GetClusterID, mgmt),
+		tokenMGR:           tokens.NewManager(ctx, mgmt),
+		jwtSigner:          jwttokens.NewIssuer(mgmt.Wrangler.Core.Secret()),
+		jwtEnabled:         jwttokens.Enabled,
+		userCache:          mgmt.Wrangler.Mgmt.User().Cache(),
+		isDisabledProvider: providers.IsDisabledProvider,
+		maxTTL:             settings.AuthTokenExchangeMaxTTLMinutes.Get,
+		federations:        settings.AuthTokenFederation.Get,
+		verifier:           newOIDCVerifier(ctx),
+		now:                time.Now,
+	}
+	return http.HandlerFunc(h.exchange)
+}
+
+// exchange issues a token derived from the subject token of the request, restricted to the requested scope.
+func (h *handler) exchange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if r.PostFormValue("grant_type") != GrantType {
+		util.WriteOAuthError(w, http.StatusBadRequest, errUnsupportedGrantType, "")
+		return
+	}
+	value := r.PostFormValue("subject_token")
+	if value == "" {
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidRequest, "subject_token is required")
+		return
+	}
+	subjectTokenType := r.PostFormValue("subject_token_type")
+	if subjectTokenType != AccessTokenType && subjectTokenType != JWTTokenType {
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidRequest, "subject_token_type must be "+AccessTokenType+" or "+JWTTokenType)
+		return
+	}
+	issuedTokenType := AccessTokenType
+	switch tokenType := r.PostFormValue("requested_token_type"); tokenType {
+	case "", AccessTokenType:
+	case JWTTokenType:
+		if !h.jwtEnabled() {
+			util.WriteOAuthError(w, http.StatusBadRequest, errInvalidRequest, "JWT tokens are not enabled")
+			return
+		}
+		issuedTokenType = JWTTokenType
+	default:
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidRequest, "requested_token_type must be "+AccessTokenType+" or "+JWTTokenType)
+		return
+	}
+	requested, err := parseScope(r.PostFormValue("scope"))
+	if err != nil {
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidScope, err.Error())
+		return
+	}
+	var requestedTTL time.Duration
+	if expiresIn := r.PostFormValue("expires_in"); expiresIn != "" {
+		seconds, err := strconv.ParseInt(expiresIn, 10, 64)
+		if err != nil || seconds <= 0 {
+			util.WriteOAuthError(w, http.StatusBadRequest, errInvalidRequest, "expires_in must be a positive number of seconds")
+			return
+		}
+		requestedTTL = time.Duration(seconds) * time.Second
+	}
+
+	if subjectTokenType == JWTTokenType {
+		h.exchangeFederated(w, value, requested, requestedTTL, issuedTokenType)
+		return
+	}
+
+	subject, err := h.subjectToken(r, value)
+	if err != nil {
+		logrus.Debugf("[tokenexchange] rejected subject token: %v", err)
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "")
+		return
+	}
+	scope, err := narrow(subject.Scope, requested)
+	if err != nil {
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidScope, err.Error())
+		return
+	}
+	ttl, err := h.ttl(subject, requestedTTL)
+	if err != nil {
+		logrus.Errorf("[tokenexchange] failed to determine the time to live of the token: %v", err)
+		util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+	if ttl < time.Second {
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "subject token is about to expire")
+		return
+	}
+
+	token, tokenValue, err := h.tokenMGR.NewExchangedToken(subject, scope, ttl.Milliseconds(), "Exchanged for token "+subject.Name)
+	if err != nil {
+		logrus.Errorf("[tokenexchange] failed to create token exchanged for token %s: %v", subject.Name, err)
+		util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+
+	h.writeToken(w, &token, tokenValue, issuedTokenType, scope, ttl)
+}
+
+// exchangeFederated issues a token of the user of the federation trusting the issuer of the external token, restricted
+// to the scope of the federation and the requested one.
+func (h *handler) exchangeFederated(w http.ResponseWriter, value string, requested *v3.TokenScope, requestedTTL time.Duration, issuedTokenType string) {
+	federation, claims, err := h.federatedSubject(value)
+	if err != nil {
+		logrus.Debugf("[tokenexchange] rejected federated subject token: %v", err)
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "")
+		return
+	}
+	user, err := h.userCache.Get(federation.UserID)
+	if err != nil {
+		logrus.Errorf("[tokenexchange] failed to get user %s of federation %s: %v", federation.UserID, federation.Name, err)
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "")
+		return
+	}
+	if user.Enabled != nil && !*user.Enabled {
+		logrus.Debugf("[tokenexchange] rejected federated subject token: user %s is not enabled", user.Name)
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidGrant, "")
+		return
+	}
+
+	federationScope, _ := parseScope(federation.Scope) // Validated with the federation.
+	scope, err := narrow(federationScope, requested)
+	if err != nil {
+		util.WriteOAuthError(w, http.StatusBadRequest, errInvalidScope, err.Error())
+		return
+	}
+	ttl, err := h.ttl(&v3.Token{}, requestedTTL)
+	if err != nil {
+		logrus.Errorf("[tokenexchange] failed to determine the time to live of the token: %v", err)
+		util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+	if federation.MaxTTLMinutes > 0 {
+		ttl = min(ttl, time.Duration(federation.MaxTTLMinutes)*time.Minute)
+	}
+
+	principal := v3.Principal{
+		ObjectMeta:    metav1.ObjectMeta{Name: "local://" + user.Name},
+		DisplayName:   user.DisplayName,
+		LoginName:     user.Username,
+		PrincipalType: "user",
+		Provider:      providers.LocalProvider,
+	}
+	subject := federation.subject(claims)
+	token, tokenValue, err := h.tokenMGR.NewFederatedToken(user.Name, principal, scope, ttl.Milliseconds(), "Federated token of "+federation.Name, subject)
+	if err != nil {
+		logrus.Errorf("[tokenexchange] failed to create token of federation %s: %v", federation.Name, err)
+		util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+	logrus.Infof("[tokenexchange] issued token %s of user %s to %s", token.Name, user.Name, subject)
+
+	h.writeToken(w, &token, tokenValue, issuedTokenType, scope, ttl)
+}
+
+// federatedSubject returns the federation trusting the issuer of the external token, and the claims of the token if
+// it's valid and has the claims of the federation.
+func (h *handler) federatedSubject(value string) (*Federation, map[string]interface{}, error) {
+	federations, err := parseFederations(h.federations())
+	if err != nil {
+		return nil, nil, err
+	}
+	issuer, err := unverifiedIssuer(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var verifyErr error
+	for i := range federations {
+		federation := &federations[i]
+		if federation.Issuer != issuer {
+			continue
+		}
+		claims, err := h.verifier.Verify(federation.Issuer, federation.Audience, value)
+		if err != nil {
+			verifyErr = err
+			continue
+		}
+		if federation.matches(claims) {
+			return federation, claims, nil
+		}
+	}
+	if verifyErr != nil {
+		return nil, nil, verifyErr
+	}
+	return nil, nil, fmt.Errorf("no federation trusts the token of issuer %s", issuer)
+}
+
+// writeToken writes the response with the issued token, as a JWT if requested.
+func (h *handler) writeToken(w http.ResponseWriter, token *v3.Token, tokenValue, issuedTokenType string, scope *v3.TokenScope, ttl time.Duration) {
+	accessToken := token.Name + ":" + tokenValue
+	if issuedTokenType == JWTTokenType {
+		// The key of the token is never handed out, the JWT is the only way to use it.
+		var err error
+		if accessToken, err = h.jwtSigner.Sign(token); err != nil {
+			logrus.Errorf("[tokenexchange] failed to sign JWT of token %s: %v", token.Name, err)
+			util.WriteOAuthError(w, http.StatusInternalServerError, errServerError, "")
+			return
+		}
+	}
+
+	response := map[string]interface{}{
+		"access_token":      accessToken,
+		"issued_token_type": issuedTokenType,
+		"token_type":        "Bearer",
+		"expires_in":        int64(ttl.Seconds()),
+	}
+	if scope != nil {
+		response["scope"] = formatScope(scope)
+	}
+	util.WriteOAuthJSON(w, http.StatusOK, response)
+}
+
+// subjectToken returns the subject token if it could be used to authenticate the request, that is if it's valid,
+// enabled, allowed from the address of the client and its user and auth provider are enabled.
+func (h *handler) subjectToken(r *http.Request, value string) (*v3.Token, error) {
+	req := r.Clone(r.Context())
+	req.Header.Del("Cookie")
+	req.Header.Set(tokens.AuthHeaderName, tokens.AuthValuePrefix+" "+value)
+	tokenAccessor, err := h.auth.TokenFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := tokenAccessor.(*v3.Token)
+	if !ok {
+		return nil, fmt.Errorf("token %s can't be exchanged", tokenAccessor.GetName())
+	}
+	if !token.GetIsEnabled() {
+		return nil, fmt.Errorf("token %s is not enabled", token.Name)
+	}
+	if kind := token.Labels[tokens.TokenKindLabel]; !slices.Contains(exchangeableTokenKinds, kind) {
+		return nil, fmt.Errorf("token %s of kind %q can't be exchanged", token.Name, kind)
+	}
+	if err := requests.CheckSourceAddress(token.AllowedCIDRs, r); err != nil {
+		return nil, err
+	}
+	if token.AuthProvider != "" {
+		disabled, err := h.isDisabledProvider(token.AuthProvider)
+		if err != nil {
+			return nil, fmt.Errorf("checking if provider %s is disabled: %w", token.AuthProvider, err)
+		}
+		if disabled {
+			return nil, fmt.Errorf("provider %s is disabled", token.AuthProvider)
+		}
+	}
+	user, err := h.userCache.Get(token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user %s: %w", token.UserID, err)
+	}
+	if user.Enabled != nil && !*user.Enabled {
+		return nil, fmt.Errorf("user %s is not enabled", user.Name)
+	}
+	return token, nil
+}
+
+// ttl returns the time to live of a token exchanged for the subject token: the requested one, if any, capped by
+// auth-token-exchange-max-ttl-minutes and auth-token-max-ttl-minutes, and by the time the subject token has left.
+func (h *handler) ttl(subject *v3.Token, requested time.Duration) (time.Duration, error) {
+	ttl := time.Duration(0)
+	if minutes, err := strconv.ParseInt(h.maxTTL(), 10, 64); err == nil {
+		ttl = time.Duration(minutes) * time.Minute
+	}
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	if requested > 0 && requested < ttl {
+		ttl = requested
+	}
+	ttl, err := tokens.ClampToMaxTTL(ttl)
+	if err != nil {
+		return 0, err
+	}
+
+	now := h.now()
+	if subject.TTLMillis > 0 {
+		expiresAt := subject.CreationTimestamp.Add(time.Duration(subject.TTLMillis) * time.Millisecond)
+		ttl = min(ttl, expiresAt.Sub(now))
+	}
+	if subject.ActivityLastSeenAt != nil && !subject.ActivityLastSeenAt.IsZero() {
+		ttl = min(ttl, subject.ActivityLastSeenAt.Sub(now))
+	}
+	return ttl.Truncate(time.Second), nil
+}
+
+// parseScope returns the token scope of the scope parameter, or nil if it's empty.
+func parseScope(value string) (*v3.TokenScope, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	scope := &v3.TokenScope{}
+	for _, field := range fields {
+		switch {
+		case field == ReadOnlyScope:
+			scope.ReadOnly = true
+		case strings.HasPrefix(field, ClusterScopePrefix):
+			cluster := strings.TrimPrefix(field, ClusterScopePrefix)
+			if cluster == "" {
+				return nil, fmt.Errorf("invalid scope %q: no cluster", field)
+			}
+			scope.Clusters = append(scope.Clusters, cluster)
+		case strings.HasPrefix(field, ProjectScopePrefix):
+			project := strings.TrimPrefix(field, ProjectScopePrefix)
+			cluster, name, _ := strings.Cut(project, ":")
+			if cluster == "" || name == "" {
+				return nil, fmt.Errorf("invalid scope %q: projects are <cluster>:<project>", field)
+			}
+			scope.Projects = append(scope.Projects, project)
+		case strings.HasPrefix(field, APIGroupScopePrefix):
+			scope.APIGroups = append(scope.APIGroups, strings.TrimPrefix(field, APIGroupScopePrefix))
+		case strings.HasPrefix(field, VerbScopePrefix):
+			scope.Verbs = append(scope.Verbs, strings.TrimPrefix(field, VerbScopePrefix))
+		default:
+			return nil, fmt.Errorf("invalid scope %q", field)
+		}
+	}
+	if err := tokens.ValidateScopeVerbs(scope.Verbs); err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
+
+// formatScope returns the scope parameter of a token scope.
+func formatScope(scope *v3.TokenScope) string {
+	var fields []string
+	if scope.ReadOnly {
+		fields = append(fields, ReadOnlyScope)
+	}
+	for _, cluster := range scope.Clusters {
+		fields = append(fields, ClusterScopePrefix+cluster)
+	}
+	for _, project := range scope.Projects {
+		fields = append(fields, ProjectScopePrefix+project)
+	}
+	for _, group := range scope.APIGroups {
+		fields = append(fields, APIGroupScopePrefix+group)
+	}
+	for _, verb := range scope.Verbs {
+		fields = append(fields, VerbScopePrefix+verb)
+	}
+	return strings.Join(fields, " ")
+}
+
+// narrow returns the scope of a token exchanged for a token with the subject scope: the requested scope, which must
+// be within the subject one. The restrictions of the subject scope the request doesn't narrow are kept.
+func narrow(subject, requested *v3.TokenScope) (*v3.TokenScope, error) {
+	if requested == nil {
+		return subject, nil
+	}
+	if subject == nil {
+		return requested, nil
+	}
+
+	scope := &v3.TokenScope{ReadOnly: subject.ReadOnly || requested.ReadOnly}
+	if len(requested.Clusters) == 0 && len(requested.Projects) == 0 {
+		scope.Clusters, scope.Projects = subject.Clusters, subject.Projects
+	} else {
+		restricted := len(subject.Clusters) > 0 || len(subject.Projects) > 0
+		for _, cluster := range requested.Clusters {
+			if restricted && !slices.Contains(subject.Clusters, cluster) {
+				return nil, fmt.Errorf("cluster %s is not in the scope of the subject token", cluster)
+			}
+		}
+		for _, project := range requested.Projects {
+			cluster, _, _ := strings.Cut(project, ":")
+			if restricted && !slices.Contains(subject.Projects, project) && !slices.Contains(subject.Clusters, cluster) {
+				return nil, fmt.Errorf("project %s is not in the scope of the subject token", project)
+			}
+		}
+		scope.Clusters, scope.Projects = requested.Clusters, requested.Projects
+	}
+
+	var err error
+	if scope.APIGroups, err = narrowList("API group", subject.APIGroups, requested.APIGroups); err != nil {
+		return nil, err
+	}
+	if scope.Verbs, err = narrowList("verb", subject.Verbs, requested.Verbs); err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
+
+// narrowList narrows the API groups or verbs of a subject scope, which are unrestricted if empty or "*".
+func narrowList(kind string, subject, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return subject, nil
+	}
+	if len(subject) == 0 || slices.Contains(subject, "*") {
+		return requested, nil
+	}
+	for _, value := range requested {
+		if !slices.Contains(subject, value) {
+			return nil, fmt.Errorf("%s %q is not in the scope of the subject token", kind, value)
+		}
+	}
+	return requested, nil
+}
@@ -0,0 +1,426 @@
+package tokenexchange
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeAuthenticator struct {
+	token accessor.TokenAccessor
+	value string
+}
+
+func (f *fakeAuthenticator) TokenFromRequest(req *http.Request) (accessor.TokenAccessor, error) {
+	if f.token == nil || tokens.GetTokenAuthFromRequest(req) != f.value {
+		return nil, requests.ErrMustAuthenticate
+	}
+	return f.token, nil
+}
+
+type fakeTokenManager struct {
+	subject          *v3.Token
+	scope            *v3.TokenScope
+	ttl              int64
+	calls            int
+	userID           string
+	federatedSubject string
+}
+
+func (f *fakeTokenManager) NewExchangedToken(subject *v3.Token, scope *v3.TokenScope, ttl int64, description string) (v3.Token, string, error) {
+	f.calls++
+	f.subject = subject
+	f.scope = scope
+	f.ttl = ttl
+	return v3.Token{ObjectMeta: metav1.ObjectMeta{Name: "token-exchanged"}}, "key", nil
+}
+
+func (f *fakeTokenManager) NewFederatedToken(userID string, userPrincipal v3.Principal, scope *v3.TokenScope, ttl int64, description, subject string) (v3.Token, string, error) {
+	f.calls++
+	f.userID = userID
+	f.scope = scope
+	f.ttl = ttl
+	f.federatedSubject = subject
+	return v3.Token{ObjectMeta: metav1.ObjectMeta{Name: "token-federated"}}, "key", nil
+}
+
+type fakeSigner struct{}
+
+func (f *fakeSigner) Sign(token *v3.Token) (string, error) {
+	return "header.payload-of-" + token.Name + ".signature", nil
+}
+
+type testEnv struct {
+	handler     *handler
+	auth        *fakeAuthenticator
+	tokenMGR    *fakeTokenManager
+	verifier    *fakeVerifier
+	users       map[string]*v3.User
+	now         time.Time
+	jwt         bool
+	federations string
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	ctrl := gomock.NewController(t)
+	env := &testEnv{
+		auth:     &fakeAuthenticator{},
+		tokenMGR: &fakeTokenManager{},
+		verifier: &fakeVerifier{},
+		users:    map[string]*v3.User{"u-abcde": {ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}}},
+		now:      time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.User, error) {
+		if user, ok := env.users[name]; ok {
+			return user, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "users"}, name)
+	}).AnyTimes()
+	env.handler = &handler{
+		auth:               env.auth,
+		tokenMGR:           env.tokenMGR,
+		jwtSigner:          &fakeSigner{},
+		jwtEnabled:         func() bool { return env.jwt },
+		userCache:          userCache,
+		isDisabledProvider: func(string) (bool, error) { return false, nil },
+		maxTTL:             func() string { return "60" },
+		federations:        func() string { return env.federations },
+		verifier:           env.verifier,
+		now:                func() time.Time { return env.now },
+	}
+	return env
+}
+
+// setSubject makes the token the one the authenticator accepts.
+func (e *testEnv) setSubject(token *v3.Token) string {
+	token.Name = "token-subject"
+	token.UserID = "u-abcde"
+	token.CreationTimestamp = metav1.NewTime(e.now.Add(-time.Hour))
+	e.auth.token = token
+	e.auth.value = "token-subject:secret"
+	return e.auth.value
+}
+
+func (e *testEnv) exchange(form url.Values) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodPost, TokenPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	e.handler.exchange(rec, req)
+
+	var body map[string]interface{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	return rec, body
+}
+
+func exchangeForm(subjectToken, scope string) url.Values {
+	form := url.Values{
+		"grant_type":         {GrantType},
+		"subject_token":      {subjectToken},
+		"subject_token_type": {AccessTokenType},
+	}
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+	return form
+}
+
+func TestExchange(t *testing.T) {
+	env := newTestEnv(t)
+	value := env.setSubject(&v3.Token{})
+
+	rec, body := env.exchange(exchangeForm(value, "read-only cluster:c-m-12345"))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "token-exchanged:key", body["access_token"])
+	assert.Equal(t, AccessTokenType, body["issued_token_type"])
+	assert.Equal(t, "Bearer", body["token_type"])
+	assert.Equal(t, float64(3600), body["expires_in"])
+	assert.Equal(t, "read-only cluster:c-m-12345", body["scope"])
+
+	require.Equal(t, 1, env.tokenMGR.calls)
+	assert.Equal(t, "token-subject", env.tokenMGR.subject.Name)
+	assert.Equal(t, &v3.TokenScope{Clusters: []string{"c-m-12345"}, ReadOnly: true}, env.tokenMGR.scope)
+	assert.Equal(t, time.Hour.Milliseconds(), env.tokenMGR.ttl)
+}
+
+func TestExchangeRequestedTTL(t *testing.T) {
+	env := newTestEnv(t)
+	value := env.setSubject(&v3.Token{})
+
+	form := exchangeForm(value, "")
+	form.Set("expires_in", "300")
+	rec, body := env.exchange(form)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, float64(300), body["expires_in"])
+	assert.NotContains(t, body, "scope")
+
+	// The token can't outlive the subject token.
+	value = env.setSubject(&v3.Token{TTLMillis: (time.Hour + 10*time.Minute).Milliseconds()})
+	rec, body = env.exchange(exchangeForm(value, ""))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, float64(600), body["expires_in"])
+}
+
+func TestExchangeJWT(t *testing.T) {
+	env := newTestEnv(t)
+	value := env.setSubject(&v3.Token{})
+	form := exchangeForm(value, "read-only")
+	form.Set("requested_token_type", JWTTokenType)
+
+	rec, body := env.exchange(form)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, errInvalidRequest, body["error"])
+	assert.Zero(t, env.tokenMGR.calls)
+
+	env.jwt = true
+	rec, body = env.exchange(form)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "header.payload-of-token-exchanged.signature", body["access_token"])
+	assert.Equal(t, JWTTokenType, body["issued_token_type"])
+	assert.Equal(t, "Bearer", body["token_type"])
+	assert.Equal(t, "read-only", body["scope"])
+}
+
+func TestExchangeRejected(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name    string
+		subject *v3.Token
+		form    func(value string) url.Values
+		setup   func(env *testEnv)
+		code    string
+	}{
+		{
+			name: "unsupported grant type",
+			form: func(value string) url.Values {
+				form := exchangeForm(value, "")
+				form.Set("grant_type", "refresh_token")
+				return form
+			},
+			code: errUnsupportedGrantType,
+		},
+		{
+			name: "no subject token",
+			form: func(string) url.Values { return exchangeForm("", "") },
+			code: errInvalidRequest,
+		},
+		{
+			name: "unsupported subject token type",
+			form: func(value string) url.Values {
+				form := exchangeForm(value, "")
+				form.Set("subject_token_type", "urn:ietf:params:oauth:token-type:id_token")
+				return form
+			},
+			code: errInvalidRequest,
+		},
+		{
+			name: "invalid subject token",
+			form: func(string) url.Values { return exchangeForm("token-subject:wrong", "") },
+			code: errInvalidGrant,
+		},
+		{
+			name:    "disabled subject token",
+			subject: &v3.Token{Enabled: &disabled},
+			code:    errInvalidGrant,
+		},
+		{
+			name:    "subject token not allowed from the client address",
+			subject: &v3.Token{AllowedCIDRs: []string{"10.0.0.0/8"}},
+			code:    errInvalidGrant,
+		},
+		{
+			name:  "disabled user",
+			setup: func(env *testEnv) { env.users["u-abcde"].Enabled = &disabled },
+			code:  errInvalidGrant,
+		},
+		{
+			name:  "disabled provider",
+			setup: func(env *testEnv) { env.handler.isDisabledProvider = func(string) (bool, error) { return true, nil } },
+			subject: &v3.Token{
+				AuthProvider: "github",
+			},
+			code: errInvalidGrant,
+		},
+		{
+			name:    "wider scope",
+			subject: &v3.Token{Scope: &v3.TokenScope{Clusters: []string{"c-m-12345"}}},
+			form:    func(value string) url.Values { return exchangeForm(value, "cluster:c-m-67890") },
+			code:    errInvalidScope,
+		},
+		{
+			name: "invalid scope",
+			form: func(value string) url.Values { return exchangeForm(value, "admin") },
+			code: errInvalidScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			subject := tt.subject
+			if subject == nil {
+				subject = &v3.Token{}
+			}
+			value := env.setSubject(subject)
+			if tt.setup != nil {
+				tt.setup(env)
+			}
+			form := exchangeForm(value, "")
+			if tt.form != nil {
+				form = tt.form(value)
+			}
+
+			rec, body := env.exchange(form)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, tt.code, body["error"])
+			assert.Zero(t, env.tokenMGR.calls)
+		})
+	}
+}
+
+func TestExchangeTokenKinds(t *testing.T) {
+	tests := []struct {
+		kind string
+		want int
+	}{
+		{kind: "", want: http.StatusOK},
+		{kind: "session", want: http.StatusOK},
+		{kind: tokens.MFAEnrollmentTokenKind, want: http.StatusBadRequest},
+		{kind: tokens.LoginAsTokenKind, want: http.StatusBadRequest},
+		{kind: tokens.ServiceKeyTokenKind, want: http.StatusBadRequest},
+		{kind: tokens.ExchangedTokenKind, want: http.StatusBadRequest},
+		{kind: tokens.FederatedTokenKind, want: http.StatusBadRequest},
+		{kind: "kubeconfig", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run("kind "+tt.kind, func(t *testing.T) {
+			env := newTestEnv(t)
+			value := env.setSubject(&v3.Token{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{tokens.TokenKindLabel: tt.kind}}})
+
+			rec, body := env.exchange(exchangeForm(value, ""))
+			require.Equal(t, tt.want, rec.Code, rec.Body.String())
+			if tt.want != http.StatusOK {
+				assert.Equal(t, errInvalidGrant, body["error"])
+				assert.Zero(t, env.tokenMGR.calls)
+			}
+		})
+	}
+}
+
+func TestNarrow(t *testing.T) {
+	tests := []struct {
+		name      string
+		subject   *v3.TokenScope
+		requested *v3.TokenScope
+		want      *v3.TokenScope
+		wantErr   string
+	}{
+		{
+			name: "unscoped",
+		},
+		{
+			name:      "unscoped subject",
+			requested: &v3.TokenScope{Clusters: []string{"c-1"}, ReadOnly: true},
+			want:      &v3.TokenScope{Clusters: []string{"c-1"}, ReadOnly: true},
+		},
+		{
+			name:    "nothing requested",
+			subject: &v3.TokenScope{Clusters: []string{"c-1"}, Verbs: []string{"get"}},
+			want:    &v3.TokenScope{Clusters: []string{"c-1"}, Verbs: []string{"get"}},
+		},
+		{
+			name:      "restrictions kept",
+			subject:   &v3.TokenScope{Clusters: []string{"c-1", "c-2"}, APIGroups: []string{"apps"}, ReadOnly: true},
+			requested: &v3.TokenScope{Clusters: []string{"c-2"}, Verbs: []string{"list"}},
+			want:      &v3.TokenScope{Clusters: []string{"c-2"}, APIGroups: []string{"apps"}, Verbs: []string{"list"}, ReadOnly: true},
+		},
+		{
+			name:      "project of a cluster",
+			subject:   &v3.TokenScope{Clusters: []string{"c-1"}},
+			requested: &v3.TokenScope{Projects: []string{"c-1:p-1"}},
+			want:      &v3.TokenScope{Projects: []string{"c-1:p-1"}},
+		},
+		{
+			name:      "cluster of a project",
+			subject:   &v3.TokenScope{Projects: []string{"c-1:p-1"}},
+			requested: &v3.TokenScope{Clusters: []string{"c-1"}},
+			wantErr:   "cluster c-1 is not in the scope",
+		},
+		{
+			name:      "other project",
+			subject:   &v3.TokenScope{Projects: []string{"c-1:p-1"}},
+			requested: &v3.TokenScope{Projects: []string{"c-1:p-2"}},
+			wantErr:   "project c-1:p-2 is not in the scope",
+		},
+		{
+			name:      "all verbs of a subject with some",
+			subject:   &v3.TokenScope{Verbs: []string{"get", "list"}},
+			requested: &v3.TokenScope{Verbs: []string{"*"}},
+			wantErr:   `verb "*" is not in the scope`,
+		},
+		{
+			name:      "other API group",
+			subject:   &v3.TokenScope{APIGroups: []string{"apps"}},
+			requested: &v3.TokenScope{APIGroups: []string{""}},
+			wantErr:   `API group "" is not in the scope`,
+		},
+		{
+			name:      "some API groups of a subject with all",
+			subject:   &v3.TokenScope{APIGroups: []string{"*"}},
+			requested: &v3.TokenScope{APIGroups: []string{"apps"}},
+			want:      &v3.TokenScope{APIGroups: []string{"apps"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := narrow(tt.subject, tt.requested)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	scope, err := parseScope("read-only  cluster:c-1 project:c-1:p-1 group: group:apps verb:get verb:list")
+	require.NoError(t, err)
+	assert.Equal(t, &v3.TokenScope{
+		Clusters:  []string{"c-1"},
+		Projects:  []string{"c-1:p-1"},
+		APIGroups: []string{"", "apps"},
+		Verbs:     []string{"get", "list"},
+		ReadOnly:  true,
+	}, scope)
+	assert.Equal(t, "read-only cluster:c-1 project:c-1:p-1 group: group:apps verb:get verb:list", formatScope(scope))
+
+	scope, err = parseScope(" ")
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+
+	for _, value := range []string{"cluster:", "project:c-1", "project::p-1", "verb:fly", "write"} {
+		_, err := parseScope(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -43,6 +43,8 @@ func NewAPIHandler(ctx context.Context, apiContext *config.ScaledContext, opts .
 	schema.ListHandler = api.tokenListHandler
 	schema.CreateHandler = api.tokenCreateHandler
 	schema.DeleteHandler = api.tokenDeleteHandler
+	schema.LinkHandler = api.tokenLinkHandler
+	schema.Formatter = usageFormatter
 
 	server := normanapi.NewAPIServer()
 	if err := server.AddSchemas(schemas); err != nil {
@@ -85,3 +87,11 @@ func (t *tokenAPI) tokenDeleteHandler(request *types.APIContext, _ types.Request
 	logrus.Debugf("TokenDeleteHandler called")
 	return t.mgr.removeToken(request)
 }
+
+func (t *tokenAPI) tokenLinkHandler(request *types.APIContext, next types.RequestHandler) error {
+	logrus.Debugf("TokenLinkHandler called for link %v", request.Link)
+	if request.Link == usageLink {
+		return t.mgr.getTokenUsage(request)
+	}
+	return httperror.NewAPIError(httperror.NotFound, "link not found")
+}
@@ -0,0 +1,121 @@
+package encryption
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"sigs.k8s.io/yaml"
+)
+
+// configCheckInterval is how often the configuration file is checked for changes, like a key rotation.
+const configCheckInterval = time.Minute
+
+// Config is the configuration of the token encryption, read from the file of auth-token-encryption-config-file.
+// Exactly one key service must be configured.
+type Config struct {
+	Static    *StaticConfig    `json:"static,omitempty"`
+	Vault     *VaultConfig     `json:"vault,omitempty"`
+	KMSPlugin *KMSPluginConfig `json:"kmsPlugin,omitempty"`
+}
+
+// NewKeyService returns the key service of the configuration.
+func (c *Config) NewKeyService() (KeyService, error) {
+	var services []KeyService
+	if c.Static != nil {
+		service, err := newStaticKeyService(c.Static)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+	}
+	if c.Vault != nil {
+		service, err := newVaultKeyService(c.Vault)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+	}
+	if c.KMSPlugin != nil {
+		service, err := newKMSPluginKeyService(c.KMSPlugin)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+	}
+	if len(services) != 1 {
+		return nil, errors.New("exactly one of static, vault and kmsPlugin must be configured")
+	}
+	return services[0], nil
+}
+
+// LoadConfig reads the configuration file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading token encryption config: %w", err)
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("parsing token encryption config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+var current = &configured{}
+
+// configured is the Encrypter of the configuration file, loaded again when the file or the setting changes.
+type configured struct {
+	mu        sync.Mutex
+	path      string
+	modTime   time.Time
+	checkedAt time.Time
+	encrypter *Encrypter
+	err       error
+}
+
+// Current returns the Encrypter configured by auth-token-encryption-config-file, or nil if token encryption isn't
+// configured.
+func Current() (*Encrypter, error) {
+	return current.get(settings.AuthTokenEncryptionConfigFile.Get(), time.Now())
+}
+
+func (c *configured) get(path string, now time.Time) (*Encrypter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if path == "" {
+		c.path, c.encrypter, c.err = "", nil, nil
+		return nil, nil
+	}
+	if path == c.path && now.Sub(c.checkedAt) < configCheckInterval {
+		return c.encrypter, c.err
+	}
+	c.checkedAt = now
+
+	info, err := os.Stat(path)
+	if err != nil {
+		c.path, c.encrypter, c.err = path, nil, fmt.Errorf("reading token encryption config: %w", err)
+		return nil, c.err
+	}
+	if path == c.path && info.ModTime().Equal(c.modTime) && c.err == nil {
+		return c.encrypter, nil
+	}
+
+	c.path, c.modTime = path, info.ModTime()
+	config, err := LoadConfig(path)
+	if err != nil {
+		c.encrypter, c.err = nil, err
+		return nil, err
+	}
+	keys, err := config.NewKeyService()
+	if err != nil {
+		c.encrypter, c.err = nil, fmt.Errorf("invalid token encryption config %s: %w", path, err)
+		return nil, c.err
+	}
+	c.encrypter, c.err = NewEncrypter(keys), nil
+	return c.encrypter, nil
+}
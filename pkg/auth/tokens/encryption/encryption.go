@@ -0,0 +1,276 @@
+// Package encryption encrypts the token material Rancher stores, like the keys or hashes of tokens and the access
+// tokens of auth providers, so that a stolen backup of etcd doesn't yield usable credentials.
+// Values are encrypted with envelope encryption: a data key encrypts them with AES-GCM, and is itself encrypted by a key
+// service which keeps the key encrypting keys out of the cluster, like Vault transit, a cloud KMS through a Kubernetes
+// KMS v2 plugin, or a static key file. Each data key is encrypted once and stored along with the values it encrypts.
+// Rotating the key of the key service makes new values use a new data key. Existing values are re-encrypted by
+// the token encryption daemon.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// prefix marks the encrypted values.
+	prefix = "kms:v1:"
+
+	dataKeySize = 32
+	// dataKeyMaxAge is how long a data key encrypts values before a new one is generated.
+	dataKeyMaxAge = 24 * time.Hour
+	// keyIDCheckInterval is how often the key service is asked for its current key, to notice rotations.
+	keyIDCheckInterval = time.Minute
+	// maxCachedDataKeys bounds the decrypted data keys kept in memory.
+	maxCachedDataKeys = 1000
+
+	keyServiceTimeout = 10 * time.Second
+)
+
+// WrappedKey is a data key encrypted by a key service.
+type WrappedKey struct {
+	// KeyID is the ID of the key of the key service which encrypted the data key.
+	KeyID      string `json:"k"`
+	Ciphertext []byte `json:"w"`
+	// Annotations are returned by some key services along with the ciphertext, and needed to decrypt it.
+	Annotations map[string][]byte `json:"a,omitempty"`
+}
+
+// KeyService encrypts and decrypts data keys with a key it holds.
+type KeyService interface {
+	// KeyID returns the ID of the current key, which changes when the key is rotated.
+	KeyID(ctx context.Context) (string, error)
+	Wrap(ctx context.Context, dataKey []byte) (WrappedKey, error)
+	Unwrap(ctx context.Context, key WrappedKey) ([]byte, error)
+}
+
+// envelope is an encrypted value.
+type envelope struct {
+	Key        WrappedKey `json:"key"`
+	Nonce      []byte     `json:"n"`
+	Ciphertext []byte     `json:"c"`
+}
+
+type dataKey struct {
+	key       []byte
+	wrapped   WrappedKey
+	createdAt time.Time
+}
+
+// Encrypter encrypts and decrypts values with data keys encrypted by a key service.
+type Encrypter struct {
+	keys KeyService
+	now  func() time.Time
+
+	mu        sync.Mutex
+	current   *dataKey
+	keyID     string
+	checkedAt time.Time
+	// decrypted are the decrypted data keys, by the hash of their ciphertext.
+	decrypted map[string][]byte
+}
+
+// NewEncrypter returns an Encrypter whose data keys are encrypted by the key service.
+func NewEncrypter(keys KeyService) *Encrypter {
+	return &Encrypter{
+		keys:      keys,
+		now:       time.Now,
+		decrypted: map[string][]byte{},
+	}
+}
+
+// IsEncrypted tells whether the value was encrypted by an Encrypter.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt returns the value encrypted with the current data key.
+func (e *Encrypter) Encrypt(value string) (string, error) {
+	key, err := e.dataKey()
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(key.key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+
+	data, err := json.Marshal(envelope{
+		Key:        key.wrapped,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, []byte(value), nil),
+	})
+	if err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decrypt returns the value an encrypted value was encrypted from. Values which aren't encrypted are returned as is,
+// as they may predate the encryption.
+func (e *Encrypter) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	env, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	key, err := e.unwrap(env.Key)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return "", errors.New("invalid encrypted value: bad nonce")
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypting value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRewrap tells whether the value should be encrypted again, because it isn't encrypted or its data key was
+// encrypted by a key the key service has since rotated.
+func (e *Encrypter) NeedsRewrap(value string) (bool, error) {
+	if !IsEncrypted(value) {
+		return true, nil
+	}
+	env, err := parse(value)
+	if err != nil {
+		return false, err
+	}
+	keyID, err := e.currentKeyID()
+	if err != nil {
+		return false, err
+	}
+	return env.Key.KeyID != keyID, nil
+}
+
+// dataKey returns the data key encrypting the values, generating a new one if the key service rotated its key or
+// the current one is older than dataKeyMaxAge.
+func (e *Encrypter) dataKey() (*dataKey, error) {
+	keyID, err := e.currentKeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.current != nil && e.current.wrapped.KeyID == keyID && e.now().Sub(e.current.createdAt) < dataKeyMaxAge {
+		return e.current, nil
+	}
+
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("generating data key: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyServiceTimeout)
+	defer cancel()
+	wrapped, err := e.keys.Wrap(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting data key: %w", err)
+	}
+	e.current = &dataKey{key: key, wrapped: wrapped, createdAt: e.now()}
+	e.cache(wrapped, key)
+	return e.current, nil
+}
+
+// currentKeyID returns the ID of the current key of the key service, asking it at most every keyIDCheckInterval.
+func (e *Encrypter) currentKeyID() (string, error) {
+	e.mu.Lock()
+	if e.keyID != "" && e.now().Sub(e.checkedAt) < keyIDCheckInterval {
+		defer e.mu.Unlock()
+		return e.keyID, nil
+	}
+	e.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyServiceTimeout)
+	defer cancel()
+	keyID, err := e.keys.KeyID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting the current key of the key service: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.keyID, e.checkedAt = keyID, e.now()
+	return keyID, nil
+}
+
+// unwrap returns the decrypted data key, which is cached so that the key service isn't called for every value.
+func (e *Encrypter) unwrap(wrapped WrappedKey) ([]byte, error) {
+	e.mu.Lock()
+	key, ok := e.decrypted[cacheKey(wrapped)]
+	e.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyServiceTimeout)
+	defer cancel()
+	key, err := e.keys.Unwrap(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting data key: %w", err)
+	}
+	if len(key) != dataKeySize {
+		return nil, errors.New("invalid data key")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cache(wrapped, key)
+	return key, nil
+}
+
+// cache keeps the decrypted data key. It must be called with the lock held.
+func (e *Encrypter) cache(wrapped WrappedKey, key []byte) {
+	if len(e.decrypted) >= maxCachedDataKeys {
+		e.decrypted = map[string][]byte{}
+	}
+	e.decrypted[cacheKey(wrapped)] = key
+}
+
+func cacheKey(wrapped WrappedKey) string {
+	sum := sha256.Sum256(append([]byte(wrapped.KeyID+"/"), wrapped.Ciphertext...))
+	return string(sum[:])
+}
+
+func parse(value string) (*envelope, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	env := &envelope{}
+	if err := json.Unmarshal(data, env); err != nil {
+		return nil, fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	return env, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,221 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+// countingKeyService counts the data keys it wraps and unwraps.
+type countingKeyService struct {
+	KeyService
+	wrapped   int
+	unwrapped int
+}
+
+func (c *countingKeyService) Wrap(ctx context.Context, dataKey []byte) (WrappedKey, error) {
+	c.wrapped++
+	return c.KeyService.Wrap(ctx, dataKey)
+}
+
+func (c *countingKeyService) Unwrap(ctx context.Context, key WrappedKey) ([]byte, error) {
+	c.unwrapped++
+	return c.KeyService.Unwrap(ctx, key)
+}
+
+func newStatic(t *testing.T, keys ...StaticKey) KeyService {
+	t.Helper()
+	service, err := newStaticKeyService(&StaticConfig{Keys: keys})
+	require.NoError(t, err)
+	return service
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	keys := &countingKeyService{KeyService: newStatic(t, StaticKey{Name: "one", Secret: testKey('a')})}
+	encrypter := NewEncrypter(keys)
+
+	first, err := encrypter.Encrypt("token-key")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(first))
+	assert.NotContains(t, first, "token-key")
+
+	second, err := encrypter.Encrypt("token-key")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second, "the nonce must differ")
+	assert.Equal(t, 1, keys.wrapped, "the data key must be reused")
+
+	// a new encrypter, like after a restart, decrypts the data key once
+	other := NewEncrypter(keys)
+	for _, value := range []string{first, second} {
+		decrypted, err := other.Decrypt(value)
+		require.NoError(t, err)
+		assert.Equal(t, "token-key", decrypted)
+	}
+	assert.Equal(t, 1, keys.unwrapped)
+
+	// values which predate the encryption are returned as is
+	decrypted, err := other.Decrypt("plain")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", decrypted)
+
+	// tampered values are rejected
+	env, err := parse(first)
+	require.NoError(t, err)
+	env.Ciphertext[0] ^= 1
+	_, err = other.Decrypt(mustEncode(t, env))
+	assert.Error(t, err)
+}
+
+func TestDataKeyMaxAge(t *testing.T) {
+	keys := &countingKeyService{KeyService: newStatic(t, StaticKey{Name: "one", Secret: testKey('a')})}
+	encrypter := NewEncrypter(keys)
+	now := time.Now()
+	encrypter.now = func() time.Time { return now }
+
+	_, err := encrypter.Encrypt("value")
+	require.NoError(t, err)
+	now = now.Add(dataKeyMaxAge)
+	_, err = encrypter.Encrypt("value")
+	require.NoError(t, err)
+	assert.Equal(t, 2, keys.wrapped)
+}
+
+func TestRotation(t *testing.T) {
+	one := StaticKey{Name: "one", Secret: testKey('a')}
+	two := StaticKey{Name: "two", Secret: testKey('b')}
+
+	old := NewEncrypter(newStatic(t, one))
+	value, err := old.Encrypt("token-key")
+	require.NoError(t, err)
+	needed, err := old.NeedsRewrap(value)
+	require.NoError(t, err)
+	assert.False(t, needed)
+	needed, err = old.NeedsRewrap("plain")
+	require.NoError(t, err)
+	assert.True(t, needed, "values which aren't encrypted must be encrypted")
+
+	// the new key comes first, the old one is kept to decrypt
+	rotated := NewEncrypter(newStatic(t, two, one))
+	needed, err = rotated.NeedsRewrap(value)
+	require.NoError(t, err)
+	assert.True(t, needed)
+
+	decrypted, err := rotated.Decrypt(value)
+	require.NoError(t, err)
+	rewrapped, err := rotated.Encrypt(decrypted)
+	require.NoError(t, err)
+	needed, err = rotated.NeedsRewrap(rewrapped)
+	require.NoError(t, err)
+	assert.False(t, needed)
+
+	// once the old key is removed, only the rewrapped value can be decrypted
+	current := NewEncrypter(newStatic(t, two))
+	_, err = current.Decrypt(value)
+	assert.Error(t, err)
+	decrypted, err = current.Decrypt(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "token-key", decrypted)
+}
+
+func TestNewKeyService(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name:    "nothing configured",
+			wantErr: "exactly one",
+		},
+		{
+			name: "several key services",
+			config: Config{
+				Static: &StaticConfig{Keys: []StaticKey{{Name: "one", Secret: testKey('a')}}},
+				Vault:  &VaultConfig{Address: "https://vault.example.com", KeyName: "rancher", TokenFile: "/token"},
+			},
+			wantErr: "exactly one",
+		},
+		{
+			name:    "no static keys",
+			config:  Config{Static: &StaticConfig{}},
+			wantErr: "no keys",
+		},
+		{
+			name:    "short static key",
+			config:  Config{Static: &StaticConfig{Keys: []StaticKey{{Name: "one", Secret: base64.StdEncoding.EncodeToString([]byte("short"))}}}},
+			wantErr: "32 bytes",
+		},
+		{
+			name: "duplicate static keys",
+			config: Config{Static: &StaticConfig{Keys: []StaticKey{
+				{Name: "one", Secret: testKey('a')},
+				{Name: "one", Secret: testKey('b')},
+			}}},
+			wantErr: "duplicate",
+		},
+		{
+			name:    "incomplete vault",
+			config:  Config{Vault: &VaultConfig{Address: "https://vault.example.com"}},
+			wantErr: "required",
+		},
+		{
+			name:   "static",
+			config: Config{Static: &StaticConfig{Keys: []StaticKey{{Name: "one", Secret: testKey('a')}}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.config.NewKeyService()
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConfigured(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("static:\n  keys:\n  - name: one\n    secret: "+testKey('a')+"\n"), 0600))
+
+	c := &configured{}
+	now := time.Now()
+
+	encrypter, err := c.get("", now)
+	require.NoError(t, err)
+	assert.Nil(t, encrypter)
+
+	encrypter, err = c.get(path, now)
+	require.NoError(t, err)
+	require.NotNil(t, encrypter)
+	again, err := c.get(path, now.Add(configCheckInterval))
+	require.NoError(t, err)
+	assert.Same(t, encrypter, again, "the config must only be loaded again when the file changes")
+
+	require.NoError(t, os.WriteFile(path, []byte("unknown: true\n"), 0600))
+	require.NoError(t, os.Chtimes(path, now.Add(time.Hour), now.Add(time.Hour)))
+	_, err = c.get(path, now.Add(time.Second))
+	require.NoError(t, err, "the file isn't checked before configCheckInterval")
+	_, err = c.get(path, now.Add(2*configCheckInterval))
+	assert.Error(t, err)
+}
+
+func mustEncode(t *testing.T, env *envelope) string {
+	t.Helper()
+	data, err := json.Marshal(env)
+	require.NoError(t, err)
+	return prefix + base64.RawURLEncoding.EncodeToString(data)
+}
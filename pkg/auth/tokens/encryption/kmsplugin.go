@@ -0,0 +1,71 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	kmsapi "k8s.io/kms/apis/v2"
+)
+
+// KMSPluginConfig configures a Kubernetes KMS v2 plugin, which cloud providers ship for their KMS, like AWS KMS,
+// Azure Key Vault or Google Cloud KMS.
+type KMSPluginConfig struct {
+	// Endpoint is the path of the unix socket of the plugin, like /var/run/kms-plugin/socket.sock.
+	Endpoint string `json:"endpoint"`
+}
+
+type kmsPluginKeyService struct {
+	client kmsapi.KeyManagementServiceClient
+}
+
+func newKMSPluginKeyService(config *KMSPluginConfig) (*kmsPluginKeyService, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("kmsPlugin: endpoint is required")
+	}
+	conn, err := grpc.NewClient("unix:"+strings.TrimPrefix(config.Endpoint, "unix://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("kmsPlugin: connecting to %s: %w", config.Endpoint, err)
+	}
+	return &kmsPluginKeyService{client: kmsapi.NewKeyManagementServiceClient(conn)}, nil
+}
+
+func (k *kmsPluginKeyService) KeyID(ctx context.Context) (string, error) {
+	status, err := k.client.Status(ctx, &kmsapi.StatusRequest{})
+	if err != nil {
+		return "", fmt.Errorf("kmsPlugin: status: %w", err)
+	}
+	if status.Healthz != "ok" {
+		return "", fmt.Errorf("kmsPlugin: unhealthy: %s", status.Healthz)
+	}
+	return status.KeyId, nil
+}
+
+func (k *kmsPluginKeyService) Wrap(ctx context.Context, dataKey []byte) (WrappedKey, error) {
+	response, err := k.client.Encrypt(ctx, &kmsapi.EncryptRequest{Plaintext: dataKey, Uid: string(uuid.NewUUID())})
+	if err != nil {
+		return WrappedKey{}, fmt.Errorf("kmsPlugin: encrypt: %w", err)
+	}
+	return WrappedKey{
+		KeyID:       response.KeyId,
+		Ciphertext:  response.Ciphertext,
+		Annotations: response.Annotations,
+	}, nil
+}
+
+func (k *kmsPluginKeyService) Unwrap(ctx context.Context, key WrappedKey) ([]byte, error) {
+	response, err := k.client.Decrypt(ctx, &kmsapi.DecryptRequest{
+		Ciphertext:  key.Ciphertext,
+		Uid:         string(uuid.NewUUID()),
+		KeyId:       key.KeyID,
+		Annotations: key.Annotations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kmsPlugin: decrypt: %w", err)
+	}
+	return response.Plaintext, nil
+}
@@ -0,0 +1,89 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// StaticConfig configures keys held in the configuration file, which should be kept out of the cluster backups.
+type StaticConfig struct {
+	// Keys are the keys encrypting the data keys. The first one encrypts the new data keys, the others are only used
+	// to decrypt. Rotating the key is adding a new key first, then removing the old one once the token encryption
+	// daemon has re-encrypted the values.
+	Keys []StaticKey `json:"keys"`
+}
+
+// StaticKey is a key of a static configuration.
+type StaticKey struct {
+	Name string `json:"name"`
+	// Secret is the base64 encoding of the 32 bytes of the AES-256 key.
+	Secret string `json:"secret"`
+}
+
+type staticKeyService struct {
+	current string
+	keys    map[string][]byte
+}
+
+func newStaticKeyService(config *StaticConfig) (*staticKeyService, error) {
+	if len(config.Keys) == 0 {
+		return nil, errors.New("static: no keys")
+	}
+	s := &staticKeyService{
+		current: config.Keys[0].Name,
+		keys:    map[string][]byte{},
+	}
+	for _, key := range config.Keys {
+		if key.Name == "" {
+			return nil, errors.New("static: key without a name")
+		}
+		if _, ok := s.keys[key.Name]; ok {
+			return nil, fmt.Errorf("static: duplicate key %s", key.Name)
+		}
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil || len(secret) != 32 {
+			return nil, fmt.Errorf("static: the secret of key %s must be 32 bytes encoded in base64", key.Name)
+		}
+		s.keys[key.Name] = secret
+	}
+	return s, nil
+}
+
+func (s *staticKeyService) KeyID(ctx context.Context) (string, error) {
+	return s.current, nil
+}
+
+func (s *staticKeyService) Wrap(ctx context.Context, dataKey []byte) (WrappedKey, error) {
+	aead, err := newAEAD(s.keys[s.current])
+	if err != nil {
+		return WrappedKey{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return WrappedKey{}, err
+	}
+	return WrappedKey{
+		KeyID:      s.current,
+		Ciphertext: aead.Seal(nonce, nonce, dataKey, []byte(s.current)),
+	}, nil
+}
+
+func (s *staticKeyService) Unwrap(ctx context.Context, key WrappedKey) ([]byte, error) {
+	secret, ok := s.keys[key.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("static: unknown key %s", key.KeyID)
+	}
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(key.Ciphertext) < aead.NonceSize() {
+		return nil, errors.New("static: invalid ciphertext")
+	}
+	nonce, ciphertext := key.Ciphertext[:aead.NonceSize()], key.Ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(key.KeyID))
+}
@@ -0,0 +1,157 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// VaultConfig configures a key of the transit secrets engine of Vault.
+type VaultConfig struct {
+	// Address is the URL of Vault, like https://vault.example.com:8200.
+	Address string `json:"address"`
+	// MountPath is the path the transit secrets engine is mounted at, transit by default.
+	MountPath string `json:"mountPath,omitempty"`
+	// KeyName is the name of the transit key. Rotating it in Vault makes new values use a new data key.
+	KeyName string `json:"keyName"`
+	// Namespace is the Vault Enterprise namespace of the key, if any.
+	Namespace string `json:"namespace,omitempty"`
+	// TokenFile is the path of the file holding the Vault token. It's read for every request, so that the token
+	// can be renewed by an agent.
+	TokenFile string `json:"tokenFile"`
+	// CAFile is the path of the PEM encoded CA certificates of Vault, if it isn't signed by a well-known CA.
+	CAFile string `json:"caFile,omitempty"`
+}
+
+type vaultKeyService struct {
+	config  VaultConfig
+	baseURL string
+	client  *http.Client
+}
+
+func newVaultKeyService(config *VaultConfig) (*vaultKeyService, error) {
+	if config.Address == "" || config.KeyName == "" || config.TokenFile == "" {
+		return nil, errors.New("vault: address, keyName and tokenFile are required")
+	}
+	mountPath := strings.Trim(config.MountPath, "/")
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("vault: reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("vault: no certificate in CA file %s", config.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &vaultKeyService{
+		config:  *config,
+		baseURL: strings.TrimRight(config.Address, "/") + "/v1/" + mountPath,
+		client:  &http.Client{Transport: transport},
+	}, nil
+}
+
+// KeyID returns the name and latest version of the transit key, like rancher:v2.
+func (v *vaultKeyService) KeyID(ctx context.Context) (string, error) {
+	var response struct {
+		Data struct {
+			LatestVersion int `json:"latest_version"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, "/keys/"+url.PathEscape(v.config.KeyName), nil, &response); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:v%d", v.config.KeyName, response.Data.LatestVersion), nil
+}
+
+func (v *vaultKeyService) Wrap(ctx context.Context, dataKey []byte) (WrappedKey, error) {
+	var response struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	if err := v.do(ctx, http.MethodPost, "/encrypt/"+url.PathEscape(v.config.KeyName), request, &response); err != nil {
+		return WrappedKey{}, err
+	}
+	// The ciphertext is vault:v<version>:<base64>.
+	parts := strings.SplitN(response.Data.Ciphertext, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return WrappedKey{}, errors.New("vault: unexpected ciphertext format")
+	}
+	return WrappedKey{
+		KeyID:      v.config.KeyName + ":" + parts[1],
+		Ciphertext: []byte(response.Data.Ciphertext),
+	}, nil
+}
+
+func (v *vaultKeyService) Unwrap(ctx context.Context, key WrappedKey) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"ciphertext": string(key.Ciphertext)}
+	if err := v.do(ctx, http.MethodPost, "/decrypt/"+url.PathEscape(v.config.KeyName), request, &response); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Data.Plaintext)
+}
+
+func (v *vaultKeyService) do(ctx context.Context, method, path string, body, result interface{}) error {
+	token, err := os.ReadFile(v.config.TokenFile)
+	if err != nil {
+		return fmt.Errorf("vault: reading token file: %w", err)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, v.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", strings.TrimSpace(string(token)))
+	if v.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.config.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("vault: %s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("vault: decoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package tokens
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/rancher/pkg/auth/tokens/encryption"
+	corev1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// StartEncryptionDaemon periodically encrypts the stored token material which isn't encrypted yet, and encrypts again
+// the material whose data key was encrypted by a key the key service has since rotated, once token encryption is
+// configured.
+func StartEncryptionDaemon(ctx context.Context, mgmt *config.ManagementContext) {
+	e := &encryptionDaemon{
+		tokenLister:   mgmt.Management.Tokens("").Controller().Lister(),
+		tokens:        mgmt.Management.Tokens(""),
+		userLister:    mgmt.Management.Users("").Controller().Lister(),
+		secretsLister: mgmt.Core.Secrets("").Controller().Lister(),
+		secrets:       mgmt.Core.Secrets(""),
+	}
+	go wait.JitterUntil(e.encrypt, time.Duration(intervalSeconds)*time.Second, .1, true, ctx.Done())
+}
+
+type encryptionDaemon struct {
+	tokenLister   v3.TokenLister
+	tokens        v3.TokenInterface
+	userLister    v3.UserLister
+	secretsLister corev1.SecretLister
+	secrets       corev1.SecretInterface
+}
+
+func (e *encryptionDaemon) encrypt() {
+	encrypter, err := encryption.Current()
+	if err != nil {
+		logrus.Errorf("[token-encryption] Error loading the token encryption config: %v", err)
+		return
+	}
+	if encrypter == nil {
+		return
+	}
+
+	allTokens, err := e.tokenLister.List("", labels.Everything())
+	if err != nil {
+		logrus.Errorf("[token-encryption] Error listing tokens: %v", err)
+		return
+	}
+
+	var count int
+	for _, token := range allTokens {
+		value, changed, err := rewrap(encrypter, token.Token)
+		if err != nil {
+			logrus.Errorf("[token-encryption] Error encrypting token %s: %v", token.Name, err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		token = token.DeepCopy()
+		token.Token = value
+		if _, err := e.tokens.Update(token); err != nil && !clientbase.IsNotFound(err) && !apierrors.IsConflict(err) {
+			logrus.Errorf("[token-encryption] Error updating token %s: %v", token.Name, err)
+			continue
+		}
+		count++
+	}
+	if count > 0 {
+		logrus.Infof("[token-encryption] Encrypted %d tokens", count)
+	}
+
+	// the access tokens of the auth providers are kept in the <user>-secret secrets
+	secrets, err := e.secretsLister.List(SecretNamespace, labels.Everything())
+	if err != nil {
+		logrus.Errorf("[token-encryption] Error listing secrets: %v", err)
+		return
+	}
+
+	count = 0
+	for _, secret := range secrets {
+		userID, ok := strings.CutSuffix(secret.Name, secretNameEnding)
+		if !ok || len(secret.Data) == 0 {
+			continue
+		}
+		if _, err := e.userLister.Get("", userID); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logrus.Errorf("[token-encryption] Error getting user %s: %v", userID, err)
+			}
+			continue
+		}
+
+		var updated map[string][]byte
+		for provider, data := range secret.Data {
+			value, changed, err := rewrap(encrypter, string(data))
+			if err != nil {
+				logrus.Errorf("[token-encryption] Error encrypting the %s secret of user %s: %v", provider, userID, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			if updated == nil {
+				updated = map[string][]byte{}
+			}
+			updated[provider] = []byte(value)
+		}
+		if updated == nil {
+			continue
+		}
+
+		secret = secret.DeepCopy()
+		for provider, value := range updated {
+			secret.Data[provider] = value
+		}
+		if _, err := e.secrets.Update(secret); err != nil && !clientbase.IsNotFound(err) && !apierrors.IsConflict(err) {
+			logrus.Errorf("[token-encryption] Error updating the secret of user %s: %v", userID, err)
+			continue
+		}
+		count++
+	}
+	if count > 0 {
+		logrus.Infof("[token-encryption] Encrypted the auth provider secrets of %d users", count)
+	}
+}
+
+// rewrap returns the value encrypted with the current data key if it isn't encrypted or its data key was encrypted
+// by a rotated key, and whether it changed.
+func rewrap(encrypter *encryption.Encrypter, value string) (string, bool, error) {
+	if value == "" {
+		return value, false, nil
+	}
+	needed, err := encrypter.NeedsRewrap(value)
+	if err != nil || !needed {
+		return value, false, err
+	}
+	plaintext, err := encrypter.Decrypt(value)
+	if err != nil {
+		return value, false, err
+	}
+	encrypted, err := encrypter.Encrypt(plaintext)
+	if err != nil {
+		return value, false, err
+	}
+	return encrypted, true, nil
+}
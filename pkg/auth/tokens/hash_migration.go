@@ -0,0 +1,174 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/rancher/pkg/features"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// HashingProgressAnnotation reports the progress of the token hashing on the token-hashing feature, as the number
+	// of hashed tokens over the number of tokens.
+	HashingProgressAnnotation = "auth.cattle.io/token-hashing-progress"
+	// HashingCompletedAtAnnotation is when all the tokens were hashed, in RFC 3339 format, on the token-hashing feature.
+	HashingCompletedAtAnnotation = "auth.cattle.io/token-hashing-completed-at"
+
+	hashingInterval = time.Minute
+)
+
+// StartHashMigration hashes the tokens which aren't hashed yet in batches once token hashing is enabled, so that
+// enabling it on large installations doesn't update every token at once. The tokens which aren't hashed yet are
+// accepted until the transition set by auth-token-hashing-transition-hours ends.
+func StartHashMigration(ctx context.Context, mgmt *config.ManagementContext) {
+	m := &hashMigrator{
+		tokenLister: mgmt.Management.Tokens("").Controller().Lister(),
+		tokens:      mgmt.Management.Tokens(""),
+		features:    mgmt.Wrangler.Mgmt.Feature(),
+		now:         time.Now,
+	}
+	go wait.JitterUntil(m.migrate, hashingInterval, .1, true, ctx.Done())
+}
+
+// HashingStartedAt returns when the tokens started being hashed, if they did.
+func HashingStartedAt() (time.Time, bool) {
+	startedAt, err := time.Parse(time.RFC3339, settings.AuthTokenHashingStartedAt.Get())
+	return startedAt, err == nil
+}
+
+// InHashingTransition tells whether the tokens are being hashed and the ones which aren't hashed yet are still accepted.
+// It's also the case once token hashing is enabled, until the tokens start being hashed.
+func InHashingTransition(now time.Time) bool {
+	if !features.TokenHashing.Enabled() {
+		return false
+	}
+	startedAt, ok := HashingStartedAt()
+	if !ok {
+		return true
+	}
+	return now.Before(hashingTransitionEnd(startedAt))
+}
+
+// AcceptsUnhashedTokens tells whether tokens which aren't hashed are accepted, which is the case unless token hashing
+// is enabled and its transition ended.
+func AcceptsUnhashedTokens(now time.Time) bool {
+	return !features.TokenHashing.Enabled() || InHashingTransition(now)
+}
+
+func hashingTransitionEnd(startedAt time.Time) time.Time {
+	hours := settings.AuthTokenHashingTransitionHours.GetInt()
+	if hours < 0 {
+		hours = 0
+	}
+	return startedAt.Add(time.Duration(hours) * time.Hour)
+}
+
+type hashMigrator struct {
+	tokenLister v3.TokenLister
+	tokens      v3.TokenInterface
+	features    mgmtcontrollers.FeatureController
+	now         func() time.Time
+}
+
+func (m *hashMigrator) migrate() {
+	if !features.TokenHashing.Enabled() {
+		return
+	}
+
+	now := m.now()
+	startedAt, ok := HashingStartedAt()
+	if !ok {
+		startedAt = now.UTC()
+		if err := settings.AuthTokenHashingStartedAt.Set(startedAt.Format(time.RFC3339)); err != nil {
+			logrus.Errorf("[token-hashing] Error recording the start of the token hashing: %v", err)
+			return
+		}
+		logrus.Infof("[token-hashing] Started hashing the tokens, the ones which aren't hashed yet are accepted until %s",
+			hashingTransitionEnd(startedAt).Format(time.RFC3339))
+	}
+
+	allTokens, err := m.tokenLister.List("", labels.Everything())
+	if err != nil {
+		logrus.Errorf("[token-hashing] Error listing tokens: %v", err)
+		return
+	}
+
+	var unhashed []*v3.Token
+	for _, token := range allTokens {
+		if token.Annotations[TokenHashed] != "true" && token.Token != "" {
+			unhashed = append(unhashed, token)
+		}
+	}
+
+	// the tokens which remain when the transition ends are hashed at once, as they're no longer accepted
+	batchSize := settings.AuthTokenHashingBatchSize.GetInt()
+	if batchSize <= 0 || !now.Before(hashingTransitionEnd(startedAt)) {
+		batchSize = len(unhashed)
+	}
+
+	var hashed int
+	for _, token := range unhashed[:min(batchSize, len(unhashed))] {
+		token = token.DeepCopy()
+		if err := ConvertTokenKeyToHash(token); err != nil {
+			logrus.Errorf("[token-hashing] Error hashing token %s: %v", token.Name, err)
+			continue
+		}
+		if _, err := m.tokens.Update(token); err != nil {
+			if !clientbase.IsNotFound(err) && !apierrors.IsConflict(err) {
+				logrus.Errorf("[token-hashing] Error updating token %s: %v", token.Name, err)
+			}
+			continue
+		}
+		hashed++
+	}
+
+	remaining := len(unhashed) - hashed
+	if hashed > 0 {
+		logrus.Infof("[token-hashing] Hashed %d tokens, %d of %d tokens are hashed", hashed, len(allTokens)-remaining, len(allTokens))
+	}
+	if err := m.reportProgress(len(allTokens)-remaining, len(allTokens), now); err != nil {
+		logrus.Errorf("[token-hashing] Error reporting the progress of the token hashing: %v", err)
+	}
+}
+
+// reportProgress records the progress of the token hashing on the token-hashing feature.
+func (m *hashMigrator) reportProgress(hashed, total int, now time.Time) error {
+	feature, err := m.features.Cache().Get(features.TokenHashing.Name())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	progress := fmt.Sprintf("%d/%d", hashed, total)
+	_, completed := feature.Annotations[HashingCompletedAtAnnotation]
+	done := hashed == total
+	if feature.Annotations[HashingProgressAnnotation] == progress && completed == done {
+		return nil
+	}
+
+	feature = feature.DeepCopy()
+	if feature.Annotations == nil {
+		feature.Annotations = map[string]string{}
+	}
+	feature.Annotations[HashingProgressAnnotation] = progress
+	if !done {
+		delete(feature.Annotations, HashingCompletedAtAnnotation)
+	} else if !completed {
+		feature.Annotations[HashingCompletedAtAnnotation] = now.UTC().Format(time.RFC3339)
+		logrus.Infof("[token-hashing] All %d tokens are hashed", total)
+	}
+	_, err = m.features.Update(feature)
+	return err
+}
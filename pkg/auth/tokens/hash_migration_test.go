@@ -0,0 +1,132 @@
+package tokens
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/features"
+	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestHashMigration(t *testing.T) {
+	features.TokenHashing.Set(true)
+	defer features.TokenHashing.Set(false)
+	require.NoError(t, settings.AuthTokenHashingBatchSize.Set("2"))
+	defer settings.AuthTokenHashingBatchSize.Set(settings.AuthTokenHashingBatchSize.Default)
+	defer settings.AuthTokenHashingStartedAt.Set("")
+
+	stored := map[string]*v3.Token{}
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("token-%d", i)
+		stored[name] = &v3.Token{ObjectMeta: metav1.ObjectMeta{Name: name}, Token: "key-" + name}
+	}
+	tokenLister := &fakes.TokenListerMock{
+		ListFunc: func(namespace string, selector labels.Selector) ([]*v3.Token, error) {
+			var list []*v3.Token
+			for _, token := range stored {
+				list = append(list, token)
+			}
+			return list, nil
+		},
+	}
+	tokenClient := &fakes.TokenInterfaceMock{
+		UpdateFunc: func(token *v3.Token) (*v3.Token, error) {
+			stored[token.Name] = token
+			return token, nil
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	feature := &v3.Feature{ObjectMeta: metav1.ObjectMeta{Name: features.TokenHashing.Name()}}
+	featureCache := fake.NewMockNonNamespacedCacheInterface[*v3.Feature](ctrl)
+	featureCache.EXPECT().Get(features.TokenHashing.Name()).DoAndReturn(func(string) (*v3.Feature, error) {
+		return feature, nil
+	}).AnyTimes()
+	featureClient := fake.NewMockNonNamespacedControllerInterface[*v3.Feature, *v3.FeatureList](ctrl)
+	featureClient.EXPECT().Cache().Return(featureCache).AnyTimes()
+	featureClient.EXPECT().Update(gomock.Any()).DoAndReturn(func(obj *v3.Feature) (*v3.Feature, error) {
+		feature = obj
+		return obj, nil
+	}).AnyTimes()
+
+	now := time.Now()
+	m := &hashMigrator{
+		tokenLister: tokenLister,
+		tokens:      tokenClient,
+		features:    featureClient,
+		now:         func() time.Time { return now },
+	}
+
+	countHashed := func() int {
+		var count int
+		for _, token := range stored {
+			if token.Annotations[TokenHashed] == "true" {
+				count++
+			}
+		}
+		return count
+	}
+
+	// the migration starts with the transition, and hashes a batch every run
+	assert.True(t, InHashingTransition(now))
+	m.migrate()
+	startedAt, ok := HashingStartedAt()
+	require.True(t, ok)
+	assert.WithinDuration(t, now, startedAt, time.Second)
+	assert.Equal(t, 2, countHashed())
+	assert.Equal(t, "2/5", feature.Annotations[HashingProgressAnnotation])
+	assert.NotContains(t, feature.Annotations, HashingCompletedAtAnnotation)
+
+	// both hashed and unhashed tokens are accepted during the transition
+	for _, token := range stored {
+		_, err := VerifyToken(token, token.Name, "key-"+token.Name)
+		assert.NoError(t, err)
+	}
+
+	// the remaining tokens are hashed at once when the transition ends
+	now = startedAt.Add(time.Duration(settings.AuthTokenHashingTransitionHours.GetInt()) * time.Hour)
+	assert.False(t, InHashingTransition(now))
+	assert.False(t, AcceptsUnhashedTokens(now))
+	m.migrate()
+	assert.Equal(t, 5, countHashed())
+	assert.Equal(t, "5/5", feature.Annotations[HashingProgressAnnotation])
+	assert.Contains(t, feature.Annotations, HashingCompletedAtAnnotation)
+	assert.Len(t, tokenClient.UpdateCalls(), 5)
+}
+
+func TestVerifyUnhashedTokenAfterTransition(t *testing.T) {
+	features.TokenHashing.Set(true)
+	defer features.TokenHashing.Set(false)
+	defer settings.AuthTokenHashingStartedAt.Set("")
+
+	token := &v3.Token{
+		ObjectMeta: metav1.ObjectMeta{Name: "token", CreationTimestamp: metav1.Now()},
+		Token:      "key",
+	}
+
+	// the transition starts with the migration
+	require.NoError(t, settings.AuthTokenHashingStartedAt.Set(""))
+	_, err := VerifyToken(token, "token", "key")
+	assert.NoError(t, err)
+
+	require.NoError(t, settings.AuthTokenHashingStartedAt.Set(time.Now().Add(-time.Hour).Format(time.RFC3339)))
+	_, err = VerifyToken(token, "token", "key")
+	assert.NoError(t, err)
+
+	require.NoError(t, settings.AuthTokenHashingStartedAt.Set(time.Now().Add(-48*time.Hour).Format(time.RFC3339)))
+	_, err = VerifyToken(token, "token", "key")
+	assert.Error(t, err)
+
+	features.TokenHashing.Set(false)
+	_, err = VerifyToken(token, "token", "key")
+	assert.NoError(t, err)
+}
@@ -6,9 +6,11 @@
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/rancher/norman/httperror"
@@ -16,6 +18,8 @@
 	"github.com/rancher/norman/types/convert"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/securityevents"
 	"github.com/rancher/rancher/pkg/auth/util"
 	clientv3 "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
@@ -31,6 +35,7 @@
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -40,11 +45,35 @@
 	userPrincipalIndex     = "authn.management.cattle.io/user-principal-index"
 	UserIDLabel            = "authn.management.cattle.io/token-userId"
 	TokenKindLabel         = "authn.management.cattle.io/kind"
+	TokenFamilyLabel       = "authn.management.cattle.io/token-family"
 	TokenHashed            = "authn.management.cattle.io/token-hashed"
 	tokenKeyIndex          = "authn.management.cattle.io/token-key-index"
 	secretNameEnding       = "-secret"
 	SecretNamespace        = "cattle-system"
 	KubeconfigResponseType = "kubeconfig"
+	// MFAEnrollmentTokenKind is the kind of the login tokens of the users who must enroll in MFA before getting a
+	// session. They are only accepted by the MFA endpoints.
+	MFAEnrollmentTokenKind = "mfa-enrollment"
+	// mfaEnrollmentTokenTTL is how long users have to enroll in MFA after logging in.
+	mfaEnrollmentTokenTTL = 15 * time.Minute
+	// ServiceKeyTokenKind is the kind of the tokens of the project service keys.
+	ServiceKeyTokenKind = "service-key"
+	// passwordResetSecretPrefix prefixes the names of the secrets recording the pending password resets.
+	passwordResetSecretPrefix = "password-reset-token-"
+	// ExchangedTokenKind is the kind of the tokens issued by the token exchange endpoint.
+	ExchangedTokenKind = "exchanged"
+	// ExchangedFromLabel is the name of the token an exchanged token was issued for.
+	ExchangedFromLabel = "authn.management.cattle.io/exchanged-from"
+	// FederatedTokenKind is the kind of the tokens issued by the token exchange endpoint for the tokens of trusted
+	// external issuers.
+	FederatedTokenKind = "federated"
+	// FederatedSubjectAnnotation is the federation and the subject of the external token a federated token was issued
+	// for, as in github-actions:repo:example/app:ref:refs/heads/main.
+	FederatedSubjectAnnotation = "authn.management.cattle.io/federated-subject"
+	// LoginAsTokenKind is the kind of the short-lived tokens issued to administrators to act as another user.
+	LoginAsTokenKind = "login-as"
+	// LoginAsByAnnotation is the ID of the administrator a login as token was issued to.
+	LoginAsByAnnotation = "authn.management.cattle.io/login-as-by"
 )
 
 var (
@@ -72,6 +101,8 @@ func NewManager(ctx context.Context, apiContext *config.ScaledContext) *Manager
 		userLister:          apiContext.Management.Users("").Controller().Lister(),
 		secrets:             apiContext.Core.Secrets(""),
 		secretLister:        apiContext.Core.Secrets("").Controller().Lister(),
+
+		subjectAccessReviews: apiContext.K8sClient.AuthorizationV1().SubjectAccessReviews(),
 	}
 }
 
@@ -97,6 +128,8 @@ type Manager struct {
 	userLister          v3.UserLister
 	secrets             v1.SecretInterface
 	secretLister        v1.SecretLister
+
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
 }
 
 type (
@@ -130,6 +163,26 @@ func (m *Manager) createDerivedToken(jsonInput clientv3.Token, tokenAuthValue st
 	if err != nil {
 		return v3.Token{}, "", 401, err
 	}
+	// A scoped token could otherwise create a token without its restrictions.
+	if token.Scope != nil {
+		return v3.Token{}, "", 403, fmt.Errorf("scoped tokens can't create tokens")
+	}
+	// A login as token could otherwise outlive its time box.
+	if token.Labels[TokenKindLabel] == LoginAsTokenKind {
+		return v3.Token{}, "", 403, fmt.Errorf("login as tokens can't create tokens")
+	}
+	// A federated token replaces a long-lived token, it can't create one.
+	if token.Labels[TokenKindLabel] == FederatedTokenKind {
+		return v3.Token{}, "", 403, fmt.Errorf("federated tokens can't create tokens")
+	}
+	scope, err := tokenScope(jsonInput.Scope)
+	if err != nil {
+		return v3.Token{}, "", 422, err
+	}
+	allowedCIDRs, err := tokenAllowedCIDRs(jsonInput.AllowedCIDRs, token.AllowedCIDRs)
+	if err != nil {
+		return v3.Token{}, "", 422, err
+	}
 
 	tokenTTL, err := ClampToMaxTTL(time.Duration(int64(jsonInput.TTLMillis)) * time.Millisecond)
 	if err != nil {
@@ -146,6 +199,8 @@ func (m *Manager) createDerivedToken(jsonInput clientv3.Token, tokenAuthValue st
 		ProviderInfo:  token.ProviderInfo,
 		Description:   jsonInput.Description,
 		ClusterName:   jsonInput.ClusterID,
+		Scope:         scope,
+		AllowedCIDRs:  allowedCIDRs,
 	}
 	derivedToken, unhashedTokenKey, err = m.createToken(&derivedToken)
 
@@ -178,6 +233,25 @@ func (m *Manager) createToken(k8sToken *v3.Token) (v3.Token, string, error) {
 	if err != nil {
 		return v3.Token{}, "", err
 	}
+	authevents.Record(authevents.Event{
+		Type:     authevents.TokenCreated,
+		UserName: createdToken.UserID,
+		Provider: createdToken.AuthProvider,
+		Target:   createdToken.Name,
+		Detail:   createdToken.Description,
+	})
+	if securityevents.IsLongLivedToken(createdToken.TTLMillis) {
+		ttl := "no expiry"
+		if createdToken.TTLMillis > 0 {
+			ttl = "TTL " + (time.Duration(createdToken.TTLMillis) * time.Millisecond).String()
+		}
+		securityevents.Record(securityevents.Event{
+			Type:     securityevents.LongLivedTokenCreated,
+			UserName: createdToken.UserID,
+			Target:   createdToken.Name,
+			Detail:   ttl,
+		})
+	}
 
 	return *createdToken, key, nil
 }
@@ -423,6 +497,13 @@ func (m *Manager) logout(actionName string, action *types.Action, request *types
 		logrus.Errorf("deleteTokenByName failed with error: %v", err)
 		return httperror.NewAPIErrorLong(status, util.GetHTTPErrorCode(status), fmt.Sprintf("%v", err))
 	}
+	authevents.Record(authevents.Event{
+		Type:     authevents.Logout,
+		UserName: storedToken.UserID,
+		Provider: storedToken.AuthProvider,
+		Target:   storedToken.Name,
+		Detail:   actionName,
+	})
 	return nil
 }
 
@@ -498,15 +579,40 @@ func (m *Manager) removeToken(request *types.APIContext) error {
 	if _, err := m.deleteTokenByName(t.Name); err != nil {
 		return err
 	}
+	event := authevents.Event{
+		Type:     authevents.TokenRevoked,
+		UserName: t.UserID,
+		Provider: t.AuthProvider,
+		Target:   t.Name,
+	}
+	if currentAuthToken.UserID != t.UserID {
+		event.Actor = currentAuthToken.UserID
+	}
+	authevents.Record(event)
 
 	request.WriteResponse(http.StatusNoContent, nil)
 	return nil
 }
 
+// ProviderSecretName returns the name of the secret holding the auth provider secrets of the user.
+func ProviderSecretName(userID string) string {
+	return userID + secretNameEnding
+}
+
+// PasswordResetSecretName returns the name of the secret recording the pending password reset of the user.
+func PasswordResetSecretName(userID string) string {
+	return passwordResetSecretPrefix + userID
+}
+
 // CreateSecret saves the secret in k8s. Secret is saved under the userID-secret with
 // key being the provider and data being the providers secret
 func (m *Manager) CreateSecret(userID, provider, secret string) error {
-	_, err := m.secretLister.Get(SecretNamespace, userID+secretNameEnding)
+	secret, err := encryptValue(secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the secret of user %s: %w", userID, err)
+	}
+
+	_, err = m.secretLister.Get(SecretNamespace, userID+secretNameEnding)
 	// An error either means it already exists or something bad happened
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
@@ -538,7 +644,11 @@ func (m *Manager) GetSecret(userID string, provider string, fallbackTokens []acc
 	}
 
 	if (err == nil) && cachedSecret != nil && string(cachedSecret.Data[provider]) != "" {
-		return string(cachedSecret.Data[provider]), nil
+		secret, err := decryptValue(string(cachedSecret.Data[provider]))
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt the secret of user %s: %w", userID, err)
+		}
+		return secret, nil
 	}
 
 	for _, token := range fallbackTokens {
@@ -552,6 +662,11 @@ func (m *Manager) GetSecret(userID string, provider string, fallbackTokens []acc
 }
 
 func (m *Manager) UpdateSecret(userID, provider, secret string) error {
+	secret, err := encryptValue(secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the secret of user %s: %w", userID, err)
+	}
+
 	cachedSecret, err := m.secretLister.Get(SecretNamespace, userID+secretNameEnding)
 	if err != nil {
 		return err
@@ -689,6 +804,10 @@ func (m *Manager) userAttributeChanged(attribs *v32.UserAttribute, provider stri
 var PerUserCacheProviders = []string{"github", "azuread", "googleoauth", "oidc", "keycloakoidc", "genericoidc"}
 
 func (m *Manager) NewLoginToken(userID string, userPrincipal v3.Principal, groupPrincipals []v3.Principal, providerToken string, ttl int64, description string) (v3.Token, string, error) {
+	if err := m.EnforceSessionLimit(userID); err != nil {
+		return v3.Token{}, "", err
+	}
+
 	provider := userPrincipal.Provider
 	// Providers that use oauth need to create a secret for storing the access token.
 	if utils.Contains(PerUserCacheProviders, provider) && providerToken != "" {
@@ -705,6 +824,8 @@ func (m *Manager) NewLoginToken(userID string, userPrincipal v3.Principal, group
 		UserID:        userID,
 		AuthProvider:  provider,
 		Description:   description,
+		// The idle timeout starts with the login, and is extended by the activity of the session.
+		ActivityLastSeenAt: IdleExpiry(time.Now()),
 		ObjectMeta: metav1.ObjectMeta{
 			Labels: map[string]string{
 				TokenKindLabel: "session",
@@ -715,6 +836,155 @@ func (m *Manager) NewLoginToken(userID string, userPrincipal v3.Principal, group
 	return m.createToken(token)
 }
 
+// NewMFAEnrollmentToken creates a short-lived login token of a user who must enroll in MFA, which only gives access to
+// the MFA endpoints. It isn't a session: users log in again with a code once enrolled.
+func (m *Manager) NewMFAEnrollmentToken(userID string, userPrincipal v3.Principal, description string) (v3.Token, string, error) {
+	token := &v3.Token{
+		UserPrincipal: userPrincipal,
+		IsDerived:     false,
+		TTLMillis:     mfaEnrollmentTokenTTL.Milliseconds(),
+		UserID:        userID,
+		AuthProvider:  userPrincipal.Provider,
+		Description:   description,
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				TokenKindLabel: MFAEnrollmentTokenKind,
+			},
+		},
+	}
+
+	return m.createToken(token)
+}
+
+// NewFamilyToken creates a token of a refresh token family, which is revoked with the family.
+// The kind is session for login tokens and kubeconfig for the tokens of kubeconfigs, which can be scoped to a cluster.
+func (m *Manager) NewFamilyToken(family, kind, clusterName, userID string, userPrincipal v3.Principal, ttl int64, description string) (v3.Token, string, error) {
+	token := &v3.Token{
+		UserPrincipal: userPrincipal,
+		IsDerived:     kind != "session",
+		TTLMillis:     ttl,
+		UserID:        userID,
+		AuthProvider:  userPrincipal.Provider,
+		Description:   description,
+		ClusterName:   clusterName,
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				TokenKindLabel:   kind,
+				TokenFamilyLabel: family,
+			},
+		},
+	}
+
+	return m.createToken(token)
+}
+
+// NewServiceKeyToken creates a token of a project service key, whose user is the service key itself.
+func (m *Manager) NewServiceKeyToken(userID string, userPrincipal v3.Principal, scope *v32.TokenScope, ttl int64, description string) (v3.Token, string, error) {
+	token := &v3.Token{
+		UserPrincipal: userPrincipal,
+		IsDerived:     true,
+		TTLMillis:     ttl,
+		UserID:        userID,
+		AuthProvider:  userPrincipal.Provider,
+		Description:   description,
+		Scope:         scope,
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				TokenKindLabel: ServiceKeyTokenKind,
+			},
+		},
+	}
+
+	return m.createToken(token)
+}
+
+// NewExchangedToken creates a token derived from the subject token, for its user, with the given scope and time to
+// live. The token keeps the cluster and allowed CIDRs of the subject token.
+func (m *Manager) NewExchangedToken(subject *v3.Token, scope *v32.TokenScope, ttl int64, description string) (v3.Token, string, error) {
+	token := &v3.Token{
+		UserPrincipal: subject.UserPrincipal,
+		IsDerived:     true,
+		TTLMillis:     ttl,
+		UserID:        subject.UserID,
+		AuthProvider:  subject.AuthProvider,
+		ProviderInfo:  subject.ProviderInfo,
+		Description:   description,
+		ClusterName:   subject.ClusterName,
+		Scope:         scope,
+		AllowedCIDRs:  subject.AllowedCIDRs,
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				TokenKindLabel:     ExchangedTokenKind,
+				ExchangedFromLabel: subject.Name,
+			},
+		},
+	}
+
+	return m.createToken(token)
+}
+
+// NewFederatedToken creates a derived token of the user for the token of a trusted external issuer, with the given
+// scope and time to live. The subject identifies the external token.
+func (m *Manager) NewFederatedToken(userID string, userPrincipal v3.Principal, scope *v32.TokenScope, ttl int64, description, subject string) (v3.Token, string, error) {
+	token := &v3.Token{
+		UserPrincipal: userPrincipal,
+		IsDerived:     true,
+		TTLMillis:     ttl,
+		UserID:        userID,
+		AuthProvider:  userPrincipal.Provider,
+		Description:   description,
+		Scope:         scope,
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				TokenKindLabel: FederatedTokenKind,
+			},
+			Annotations: map[string]string{
+				FederatedSubjectAnnotation: subject,
+			},
+		},
+	}
+
+	return m.createToken(token)
+}
+
+// NewLoginAsToken creates a short-lived token of the user for the administrator adminID, who acts as the user.
+// The token is listed with the tokens of the user and doesn't count toward the user's session limit.
+func (m *Manager) NewLoginAsToken(userID string, userPrincipal v3.Principal, adminID string, ttl int64, description string) (v3.Token, string, error) {
+	token := &v3.Token{
+		UserPrincipal: userPrincipal,
+		IsDerived:     false,
+		TTLMillis:     ttl,
+		UserID:        userID,
+		AuthProvider:  userPrincipal.Provider,
+		Description:   description,
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				TokenKindLabel: LoginAsTokenKind,
+			},
+			Annotations: map[string]string{
+				LoginAsByAnnotation: adminID,
+			},
+		},
+	}
+
+	return m.createToken(token)
+}
+
+// RevokeTokenFamily deletes the tokens of a refresh token family.
+func (m *Manager) RevokeTokenFamily(family string) error {
+	set := labels.Set{TokenFamilyLabel: family}
+	tokenList, err := m.tokensClient.List(metav1.ListOptions{LabelSelector: set.AsSelector().String()})
+	if err != nil {
+		return fmt.Errorf("error listing the tokens of family %s: %w", family, err)
+	}
+	for _, token := range tokenList.Items {
+		if err := m.tokensClient.Delete(token.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error deleting token %s of family %s: %w", token.Name, family, err)
+		}
+	}
+	return nil
+}
+
 func (m *Manager) UpdateToken(token *v3.Token) (*v3.Token, error) {
 	return m.updateToken(token)
 }
@@ -859,6 +1129,82 @@ func ClampToMaxTTL(ttl time.Duration) (time.Duration, error) {
 	return maxTTL, nil
 }
 
+// scopeVerbs are the verbs token scopes can list.
+var scopeVerbs = map[string]bool{
+	"*": true, "get": true, "list": true, "watch": true, "create": true, "update": true, "patch": true, "delete": true, "deletecollection": true,
+}
+
+// ValidateScopeVerbs returns an error if a verb can't be listed in a token scope.
+func ValidateScopeVerbs(verbs []string) error {
+	for _, verb := range verbs {
+		if !scopeVerbs[verb] {
+			return fmt.Errorf("invalid verb %q in token scope", verb)
+		}
+	}
+	return nil
+}
+
+// tokenScope validates the scope requested for a token. An empty scope places no restriction and is dropped.
+func tokenScope(input *clientv3.TokenScope) (*v32.TokenScope, error) {
+	if input == nil {
+		return nil, nil
+	}
+	if err := ValidateScopeVerbs(input.Verbs); err != nil {
+		return nil, err
+	}
+	for _, project := range input.Projects {
+		if cluster, name, ok := strings.Cut(project, ":"); !ok || cluster == "" || name == "" {
+			return nil, fmt.Errorf("invalid project %q in token scope, must be <cluster>:<project>", project)
+		}
+	}
+	if len(input.Clusters) == 0 && len(input.Projects) == 0 && len(input.APIGroups) == 0 && len(input.Verbs) == 0 && !input.ReadOnly {
+		return nil, nil
+	}
+	return &v32.TokenScope{
+		Clusters:  input.Clusters,
+		Projects:  input.Projects,
+		APIGroups: input.APIGroups,
+		Verbs:     input.Verbs,
+		ReadOnly:  input.ReadOnly,
+	}, nil
+}
+
+// tokenAllowedCIDRs validates the CIDRs requested for a token derived from a token allowed from the parent CIDRs.
+// The derived token is allowed from the parent CIDRs if none are requested, and can't be allowed from other networks.
+func tokenAllowedCIDRs(cidrs, parentCIDRs []string) ([]string, error) {
+	if len(cidrs) == 0 {
+		return parentCIDRs, nil
+	}
+
+	var parentNetworks []*net.IPNet
+	for _, cidr := range parentCIDRs {
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			parentNetworks = append(parentNetworks, network)
+		}
+	}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q in allowedCIDRs", cidr)
+		}
+		if len(parentCIDRs) > 0 && !containsNetwork(parentNetworks, network) {
+			return nil, fmt.Errorf("CIDR %s is not within the allowed CIDRs of the token creating it", cidr)
+		}
+	}
+	return cidrs, nil
+}
+
+func containsNetwork(networks []*net.IPNet, network *net.IPNet) bool {
+	ones, _ := network.Mask.Size()
+	for _, n := range networks {
+		parentOnes, _ := n.Mask.Size()
+		if n.Contains(network.IP) && parentOnes <= ones && len(n.IP) == len(network.IP) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetKubeconfigDefaultTokenTTLInMilliSeconds will return the default TTL for kubeconfig tokens
 func GetKubeconfigDefaultTokenTTLInMilliSeconds() (*int64, error) {
 	defaultTokenTTL, err := ParseTokenTTL(settings.KubeconfigDefaultTokenTTLMinutes.Get())
@@ -9,6 +9,7 @@
 
 	"github.com/rancher/norman/types"
 	"github.com/rancher/rancher/pkg/auth/tokens/hashers"
+	clientv3 "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	"github.com/rancher/rancher/pkg/features"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	mgmtFakes "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
@@ -296,3 +297,63 @@ func TestUserAttributeCreateOrUpdateUpdatesGroups(t *testing.T) {
 	require.Len(t, principals.Items, 1)
 	assert.Equal(t, principals.Items[0].Name, "group1")
 }
+
+func TestTokenScope(t *testing.T) {
+	scope, err := tokenScope(nil)
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+
+	scope, err = tokenScope(&clientv3.TokenScope{})
+	require.NoError(t, err)
+	assert.Nil(t, scope, "empty scopes place no restriction")
+
+	scope, err = tokenScope(&clientv3.TokenScope{
+		Clusters:  []string{"c-abcde"},
+		Projects:  []string{"c-fghij:p-klmno"},
+		APIGroups: []string{"management.cattle.io"},
+		Verbs:     []string{"get", "list"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c-abcde"}, scope.Clusters)
+	assert.Equal(t, []string{"c-fghij:p-klmno"}, scope.Projects)
+	assert.Equal(t, []string{"management.cattle.io"}, scope.APIGroups)
+	assert.Equal(t, []string{"get", "list"}, scope.Verbs)
+
+	scope, err = tokenScope(&clientv3.TokenScope{ReadOnly: true})
+	require.NoError(t, err)
+	assert.True(t, scope.ReadOnly)
+
+	_, err = tokenScope(&clientv3.TokenScope{Verbs: []string{"escalate"}})
+	assert.ErrorContains(t, err, "invalid verb")
+
+	_, err = tokenScope(&clientv3.TokenScope{Projects: []string{"p-klmno"}})
+	assert.ErrorContains(t, err, "invalid project")
+}
+
+func TestTokenAllowedCIDRs(t *testing.T) {
+	cidrs, err := tokenAllowedCIDRs(nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, cidrs)
+
+	cidrs, err = tokenAllowedCIDRs([]string{"10.0.0.0/8", "2001:db8::/32"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "2001:db8::/32"}, cidrs)
+
+	_, err = tokenAllowedCIDRs([]string{"10.0.0.1"}, nil)
+	assert.ErrorContains(t, err, "invalid CIDR")
+
+	// Derived tokens inherit the CIDRs of the token creating them, and can only narrow them.
+	cidrs, err = tokenAllowedCIDRs(nil, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8"}, cidrs)
+
+	cidrs, err = tokenAllowedCIDRs([]string{"10.1.0.0/16"}, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.1.0.0/16"}, cidrs)
+
+	_, err = tokenAllowedCIDRs([]string{"0.0.0.0/0"}, []string{"10.0.0.0/8"})
+	assert.ErrorContains(t, err, "not within")
+
+	_, err = tokenAllowedCIDRs([]string{"192.168.0.0/16"}, []string{"10.0.0.0/8"})
+	assert.ErrorContains(t, err, "not within")
+}
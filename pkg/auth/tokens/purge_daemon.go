@@ -5,6 +5,7 @@
 	"time"
 
 	"github.com/rancher/norman/clientbase"
+	corev1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -16,12 +17,28 @@
 
 const intervalSeconds int64 = 3600
 
+// DeviceAuthorizationLabel marks the secrets holding the device authorization requests of the rancher cli.
+const DeviceAuthorizationLabel = "cattle.io/device-authorization"
+
+const (
+	// RefreshTokenLabel marks the secrets holding refresh tokens.
+	RefreshTokenLabel = "cattle.io/refresh-token"
+	// RefreshTokenFamilyLabel is the family of the refresh tokens, which is also the TokenFamilyLabel of their tokens.
+	RefreshTokenFamilyLabel = "cattle.io/refresh-token-family"
+	// RefreshTokenExpiresAtKey is the key of the RFC 3339 expiry of the refresh token family in their secrets.
+	RefreshTokenExpiresAtKey = "expiresAt"
+	// RefreshTokenKindKey is the key of the kind of the tokens of the refresh token family in their secrets.
+	RefreshTokenKindKey = "kind"
+)
+
 func StartPurgeDaemon(ctx context.Context, mgmt *config.ManagementContext) {
 	p := &purger{
 		tokenLister:      mgmt.Management.Tokens("").Controller().Lister(),
 		tokens:           mgmt.Management.Tokens(""),
 		samlTokensLister: mgmt.Management.SamlTokens("").Controller().Lister(),
 		samlTokens:       mgmt.Management.SamlTokens(""),
+		secretsLister:    mgmt.Core.Secrets("").Controller().Lister(),
+		secrets:          mgmt.Core.Secrets(""),
 	}
 	go wait.JitterUntil(p.purge, time.Duration(intervalSeconds)*time.Second, .1, true, ctx.Done())
 }
@@ -31,6 +48,8 @@ type purger struct {
 	tokens           v3.TokenInterface
 	samlTokens       v3.SamlTokenInterface
 	samlTokensLister v3.SamlTokenLister
+	secrets          corev1.SecretInterface
+	secretsLister    corev1.SecretLister
 }
 
 func (p *purger) purge() {
@@ -75,4 +94,48 @@ func (p *purger) purge() {
 	if count > 0 {
 		logrus.Infof("Purged %v saml tokens", count)
 	}
+
+	// device authorizations expire long before, unless they're redeemed
+	deviceAuthorizations, err := p.secretsLister.List(SecretNamespace, labels.SelectorFromSet(labels.Set{DeviceAuthorizationLabel: "true"}))
+	if err != nil {
+		return
+	}
+
+	count = 0
+	for _, secret := range deviceAuthorizations {
+		if secret.CreationTimestamp.Add(15 * time.Minute).Before(time.Now()) {
+			err = p.secrets.DeleteNamespaced(SecretNamespace, secret.Name, &metav1.DeleteOptions{})
+			if err != nil && !clientbase.IsNotFound(err) {
+				logrus.Errorf("Error: while deleting expired device authorization %v: %v", err, secret.Name)
+				continue
+			}
+			count++
+		}
+	}
+	if count > 0 {
+		logrus.Infof("Purged %v device authorizations", count)
+	}
+
+	// refresh tokens are kept until their family expires, so that the reuse of rotated ones is detected
+	refreshTokens, err := p.secretsLister.List(SecretNamespace, labels.SelectorFromSet(labels.Set{RefreshTokenLabel: "true"}))
+	if err != nil {
+		return
+	}
+
+	count = 0
+	for _, secret := range refreshTokens {
+		expiresAt, err := time.Parse(time.RFC3339, string(secret.Data[RefreshTokenExpiresAtKey]))
+		if err == nil && expiresAt.After(time.Now()) {
+			continue
+		}
+		err = p.secrets.DeleteNamespaced(SecretNamespace, secret.Name, &metav1.DeleteOptions{})
+		if err != nil && !clientbase.IsNotFound(err) {
+			logrus.Errorf("Error: while deleting expired refresh token %v: %v", err, secret.Name)
+			continue
+		}
+		count++
+	}
+	if count > 0 {
+		logrus.Infof("Purged %v refresh tokens", count)
+	}
 }
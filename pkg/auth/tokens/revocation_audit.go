@@ -0,0 +1,90 @@
+package tokens
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// tokenPathPrefixes are the paths of the tokens in the Kubernetes API, directly or through the proxy of the local
+// cluster, and in the Steve API.
+var tokenPathPrefixes = []string{
+	"/apis/management.cattle.io/v3/tokens/",
+	"/k8s/clusters/local/apis/management.cattle.io/v3/tokens/",
+	"/v1/management.cattle.io.tokens/",
+}
+
+// NewRevocationAuditMiddleware returns the middleware recording the deletions of tokens through the Kubernetes and
+// Steve APIs as auth events, as the revocations through the /v3/tokens API are. It must be chained after the
+// authentication filter, as it reads the user from the request context.
+func NewRevocationAuditMiddleware(tokenCache mgmtcontrollers.TokenCache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			name := deletedTokenName(req)
+			userInfo, ok := request.UserFrom(req.Context())
+			if name == "" || !ok {
+				next.ServeHTTP(rw, req)
+				return
+			}
+			// The token is read before it's deleted, for the user it belongs to.
+			token, err := tokenCache.Get(name)
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					logrus.Errorf("[tokens] failed to get token %s for the audit of its deletion: %v", name, err)
+				}
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			sw := &statusWriter{ResponseWriter: rw, status: http.StatusOK}
+			next.ServeHTTP(sw, req)
+			if sw.status >= http.StatusBadRequest {
+				return
+			}
+			event := authevents.Event{
+				Type:     authevents.TokenRevoked,
+				UserName: token.UserID,
+				Provider: token.AuthProvider,
+				Target:   token.Name,
+			}
+			if userInfo.GetName() != token.UserID {
+				event.Actor = userInfo.GetName()
+			}
+			authevents.Record(event)
+		})
+	}
+}
+
+// deletedTokenName returns the name of the token the request deletes, or "" if it doesn't delete a token.
+func deletedTokenName(req *http.Request) string {
+	if req.Method != http.MethodDelete {
+		return ""
+	}
+	for _, prefix := range tokenPathPrefixes {
+		if name, ok := strings.CutPrefix(req.URL.Path, prefix); ok && name != "" && !strings.Contains(name, "/") {
+			return name
+		}
+	}
+	return ""
+}
+
+// statusWriter records the status of the response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
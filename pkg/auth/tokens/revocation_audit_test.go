@@ -0,0 +1,106 @@
+package tokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestRevocationAuditMiddleware(t *testing.T) {
+	hook := logrusTest.NewGlobal()
+	defer hook.Reset()
+
+	ctrl := gomock.NewController(t)
+	tokenCache := fake.NewMockNonNamespacedCacheInterface[*v32.Token](ctrl)
+	tokenCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v32.Token, error) {
+		if name == "token-abcde" {
+			return &v32.Token{ObjectMeta: metav1.ObjectMeta{Name: name}, UserID: "u-abcde", AuthProvider: "local"}, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "management.cattle.io", Resource: "tokens"}, name)
+	}).AnyTimes()
+
+	tests := []struct {
+		desc      string
+		method    string
+		path      string
+		status    int
+		wantEvent bool
+	}{
+		{
+			desc:      "kubernetes api",
+			method:    http.MethodDelete,
+			path:      "/apis/management.cattle.io/v3/tokens/token-abcde",
+			status:    http.StatusOK,
+			wantEvent: true,
+		},
+		{
+			desc:      "local cluster proxy",
+			method:    http.MethodDelete,
+			path:      "/k8s/clusters/local/apis/management.cattle.io/v3/tokens/token-abcde",
+			status:    http.StatusOK,
+			wantEvent: true,
+		},
+		{
+			desc:      "steve api",
+			method:    http.MethodDelete,
+			path:      "/v1/management.cattle.io.tokens/token-abcde",
+			status:    http.StatusNoContent,
+			wantEvent: true,
+		},
+		{
+			desc:   "refused deletion",
+			method: http.MethodDelete,
+			path:   "/apis/management.cattle.io/v3/tokens/token-abcde",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "unknown token",
+			method: http.MethodDelete,
+			path:   "/apis/management.cattle.io/v3/tokens/token-fghij",
+			status: http.StatusOK,
+		},
+		{
+			desc:   "read",
+			method: http.MethodGet,
+			path:   "/apis/management.cattle.io/v3/tokens/token-abcde",
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			hook.Reset()
+			next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(tt.status)
+			})
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: "u-revoker"}))
+
+			NewRevocationAuditMiddleware(tokenCache)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			if !tt.wantEvent {
+				assert.Empty(t, hook.AllEntries())
+				return
+			}
+			entry := hook.LastEntry()
+			require.NotNil(t, entry)
+			assert.Equal(t, "authevents: audit", entry.Message)
+			assert.Equal(t, authevents.TokenRevoked, entry.Data["event"])
+			assert.Equal(t, "u-abcde", entry.Data["user"])
+			assert.Equal(t, "u-revoker", entry.Data["actor"])
+			assert.Equal(t, "token-abcde", entry.Data["target"])
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package tokens
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// SessionLimitPolicyReject fails the logins past the maximum number of sessions.
+	SessionLimitPolicyReject = "reject"
+	// SessionLimitPolicyEvictOldest ends the oldest sessions to make room for new logins.
+	SessionLimitPolicyEvictOldest = "evict-oldest"
+)
+
+// ErrSessionLimitReached is returned for logins past auth-user-max-sessions with the reject policy.
+var ErrSessionLimitReached = httperror.NewAPIError(httperror.PermissionDenied, "maximum number of concurrent sessions reached")
+
+// loginSession is a login of a user, made of a single login token, or of the tokens of a refresh token family.
+type loginSession struct {
+	family    string
+	tokens    []string
+	createdAt time.Time
+}
+
+// EnforceSessionLimit makes room for a new login session of the user according to auth-user-max-sessions
+// and auth-user-max-sessions-policy, or returns ErrSessionLimitReached.
+func (m *Manager) EnforceSessionLimit(userID string) error {
+	maxSessions := settings.AuthUserMaxSessions.GetInt()
+	if maxSessions <= 0 {
+		return nil
+	}
+
+	sessions, err := m.loginSessions(userID)
+	if err != nil {
+		return fmt.Errorf("listing the sessions of user %s: %w", userID, err)
+	}
+	excess := len(sessions) - maxSessions + 1
+	if excess <= 0 {
+		return nil
+	}
+	if settings.AuthUserMaxSessionsPolicy.Get() != SessionLimitPolicyEvictOldest {
+		return ErrSessionLimitReached
+	}
+
+	for _, session := range sessions[:excess] {
+		logrus.Infof("Ending the oldest session of user %s, created at %s, past the maximum of %d sessions", userID, session.createdAt.Format(time.RFC3339), maxSessions)
+		if err := m.endSession(session); err != nil {
+			return fmt.Errorf("ending a session of user %s: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// loginSessions returns the active login sessions of the user, oldest first.
+func (m *Manager) loginSessions(userID string) ([]*loginSession, error) {
+	tokenList, err := m.tokensClient.List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{UserIDLabel: userID}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := metav1.Now()
+	families := map[string]*loginSession{}
+	var sessions []*loginSession
+	for _, token := range tokenList.Items {
+		if token.UserID != userID || token.IsDerived || token.Labels[TokenKindLabel] == MFAEnrollmentTokenKind ||
+			token.Labels[TokenKindLabel] == LoginAsTokenKind ||
+			IsExpired(token) || IsIdleExpired(token, now) || (token.Enabled != nil && !*token.Enabled) {
+			continue
+		}
+		family := token.Labels[TokenFamilyLabel]
+		if session, ok := families[family]; ok && family != "" {
+			session.tokens = append(session.tokens, token.Name)
+			if token.CreationTimestamp.Time.Before(session.createdAt) {
+				session.createdAt = token.CreationTimestamp.Time
+			}
+			continue
+		}
+		session := &loginSession{
+			family:    family,
+			tokens:    []string{token.Name},
+			createdAt: token.CreationTimestamp.Time,
+		}
+		if family != "" {
+			families[family] = session
+		}
+		sessions = append(sessions, session)
+	}
+
+	// The sessions with refresh tokens last as long as their family, even when their current token has expired.
+	refreshTokens, err := m.secretLister.List(SecretNamespace, labels.SelectorFromSet(labels.Set{
+		RefreshTokenLabel: "true",
+		UserIDLabel:       userID,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range refreshTokens {
+		family := secret.Labels[RefreshTokenFamilyLabel]
+		if family == "" || string(secret.Data[RefreshTokenKindKey]) != "session" {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, string(secret.Data[RefreshTokenExpiresAtKey]))
+		if err != nil || !now.Time.Before(expiresAt) {
+			continue
+		}
+		if session, ok := families[family]; ok {
+			if secret.CreationTimestamp.Time.Before(session.createdAt) {
+				session.createdAt = secret.CreationTimestamp.Time
+			}
+			continue
+		}
+		session := &loginSession{
+			family:    family,
+			createdAt: secret.CreationTimestamp.Time,
+		}
+		families[family] = session
+		sessions = append(sessions, session)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].createdAt.Before(sessions[j].createdAt)
+	})
+	return sessions, nil
+}
+
+// endSession deletes the tokens of the session, and the refresh tokens of its family so that it can't be refreshed.
+func (m *Manager) endSession(session *loginSession) error {
+	if session.family != "" {
+		refreshTokens, err := m.secretLister.List(SecretNamespace, labels.SelectorFromSet(labels.Set{
+			RefreshTokenLabel:       "true",
+			RefreshTokenFamilyLabel: session.family,
+		}))
+		if err != nil {
+			return err
+		}
+		for _, secret := range refreshTokens {
+			if err := m.secrets.DeleteNamespaced(SecretNamespace, secret.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		return m.RevokeTokenFamily(session.family)
+	}
+
+	for _, name := range session.tokens {
+		if err := m.tokensClient.Delete(name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
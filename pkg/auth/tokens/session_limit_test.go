@@ -0,0 +1,122 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	coreFakes "github.com/rancher/rancher/pkg/generated/norman/core/v1/fakes"
+	mgmtFakes "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestEnforceSessionLimit(t *testing.T) {
+	require.NoError(t, settings.AuthUserMaxSessions.Set("3"))
+	defer settings.AuthUserMaxSessions.Set(settings.AuthUserMaxSessions.Default)
+	defer settings.AuthUserMaxSessionsPolicy.Set(settings.AuthUserMaxSessionsPolicy.Default)
+
+	now := time.Now()
+	userToken := func(name string, age time.Duration, lbls map[string]string) v3.Token {
+		token := v3.Token{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				Labels:            map[string]string{UserIDLabel: "u-abcde"},
+			},
+			UserID: "u-abcde",
+		}
+		for k, v := range lbls {
+			token.Labels[k] = v
+		}
+		return token
+	}
+
+	var deletedTokens, deletedSecrets []string
+	newManager := func(tokens []v3.Token) *Manager {
+		deletedTokens, deletedSecrets = nil, nil
+		return &Manager{
+			tokensClient: &mgmtFakes.TokenInterfaceMock{
+				ListFunc: func(opts metav1.ListOptions) (*v3.TokenList, error) {
+					selector, err := labels.Parse(opts.LabelSelector)
+					require.NoError(t, err)
+					list := &v3.TokenList{}
+					for _, token := range tokens {
+						if selector.Matches(labels.Set(token.Labels)) {
+							list.Items = append(list.Items, token)
+						}
+					}
+					return list, nil
+				},
+				DeleteFunc: func(name string, _ *metav1.DeleteOptions) error {
+					deletedTokens = append(deletedTokens, name)
+					return nil
+				},
+			},
+			secretLister: &coreFakes.SecretListerMock{
+				ListFunc: func(namespace string, selector labels.Selector) ([]*corev1.Secret, error) {
+					secret := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              "refreshtoken-abcde",
+							CreationTimestamp: metav1.NewTime(now.Add(-4 * time.Hour)),
+							Labels: map[string]string{
+								RefreshTokenLabel:       "true",
+								RefreshTokenFamilyLabel: "family1",
+								UserIDLabel:             "u-abcde",
+							},
+						},
+						Data: map[string][]byte{
+							RefreshTokenKindKey:      []byte("session"),
+							RefreshTokenExpiresAtKey: []byte(now.Add(time.Hour).UTC().Format(time.RFC3339)),
+						},
+					}
+					if selector.Matches(labels.Set(secret.Labels)) {
+						return []*corev1.Secret{secret}, nil
+					}
+					return nil, nil
+				},
+			},
+			secrets: &coreFakes.SecretInterfaceMock{
+				DeleteNamespacedFunc: func(namespace, name string, _ *metav1.DeleteOptions) error {
+					deletedSecrets = append(deletedSecrets, name)
+					return nil
+				},
+			},
+		}
+	}
+
+	expired := userToken("token-expired", 3*time.Hour, nil)
+	expired.TTLMillis = time.Hour.Milliseconds()
+	derived := userToken("token-derived", 5*time.Hour, nil)
+	derived.IsDerived = true
+	tokens := []v3.Token{
+		userToken("token-old", 2*time.Hour, nil),
+		expired,
+		derived,
+		userToken("token-other", time.Hour, map[string]string{UserIDLabel: "u-fghij"}),
+	}
+
+	// The expired, derived tokens and the tokens of other users aren't sessions, the refresh token family is one.
+	m := newManager(tokens)
+	require.NoError(t, m.EnforceSessionLimit("u-abcde"))
+	require.NoError(t, settings.AuthUserMaxSessions.Set("2"))
+	assert.ErrorIs(t, m.EnforceSessionLimit("u-abcde"), ErrSessionLimitReached)
+	assert.Empty(t, deletedTokens)
+
+	// The oldest sessions are evicted to make room for the new one.
+	require.NoError(t, settings.AuthUserMaxSessions.Set("1"))
+	require.NoError(t, settings.AuthUserMaxSessionsPolicy.Set(SessionLimitPolicyEvictOldest))
+	m = newManager(append(tokens, userToken("token-family1", time.Minute, map[string]string{TokenFamilyLabel: "family1"})))
+	require.NoError(t, m.EnforceSessionLimit("u-abcde"))
+	assert.Equal(t, []string{"token-family1", "token-old"}, deletedTokens)
+	assert.Equal(t, []string{"refreshtoken-abcde"}, deletedSecrets)
+
+	// Without a limit, sessions aren't even listed.
+	require.NoError(t, settings.AuthUserMaxSessions.Set("0"))
+	m = &Manager{}
+	assert.NoError(t, m.EnforceSessionLimit("u-abcde"))
+}
@@ -1,7 +1,17 @@
 package tokens
 
 import (
+	"strconv"
+
 	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // NewMockedManager returns a token manager with mocked clients to be used in other packages' tests.
@@ -10,3 +20,69 @@ func NewMockedManager(tokensClient *fakes.TokenInterfaceMock) *Manager { // add
 		tokensClient: tokensClient,
 	}
 }
+
+// NewFakeSecrets returns a client storing the secrets of SecretNamespace in memory to be used in other packages' tests,
+// with resource versions so that conflicts are detected.
+func NewFakeSecrets(ctrl *gomock.Controller) *fake.MockClientInterface[*corev1.Secret, *corev1.SecretList] {
+	stored := map[string]*corev1.Secret{}
+	version := 0
+	gr := schema.GroupResource{Resource: "secrets"}
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if _, ok := stored[secret.Name]; ok {
+			return nil, apierrors.NewAlreadyExists(gr, secret.Name)
+		}
+		version++
+		created := secret.DeepCopy()
+		created.UID = types.UID(secret.Name)
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Get(SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().List(SecretNamespace, gomock.Any()).DoAndReturn(func(namespace string, opts metav1.ListOptions) (*corev1.SecretList, error) {
+		selector, err := labels.Parse(opts.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		list := &corev1.SecretList{}
+		for _, secret := range stored {
+			if selector.Matches(labels.Set(secret.Labels)) {
+				list.Items = append(list.Items, *secret.DeepCopy())
+			}
+		}
+		return list, nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		current, ok := stored[secret.Name]
+		if !ok {
+			return nil, apierrors.NewNotFound(gr, secret.Name)
+		}
+		if current.ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Delete(SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, opts *metav1.DeleteOptions) error {
+		current, ok := stored[name]
+		if !ok {
+			return apierrors.NewNotFound(gr, name)
+		}
+		if opts != nil && opts.Preconditions != nil && opts.Preconditions.ResourceVersion != nil &&
+			*opts.Preconditions.ResourceVersion != current.ResourceVersion {
+			return apierrors.NewConflict(gr, name, nil)
+		}
+		delete(stored, name)
+		return nil
+	}).AnyTimes()
+	return secrets
+}
@@ -10,9 +10,11 @@
 	"github.com/pkg/errors"
 	"github.com/rancher/norman/types"
 	"github.com/rancher/norman/types/convert"
+	"github.com/rancher/rancher/pkg/auth/tokens/encryption"
 	"github.com/rancher/rancher/pkg/auth/tokens/hashers"
 	"github.com/rancher/rancher/pkg/features"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/user"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,6 +71,17 @@ func IsIdleExpired(token v3.Token, lastTimeActivity metav1.Time) bool {
 	return token.ActivityLastSeenAt.Compare(lastTimeActivity.Time) <= 0
 }
 
+// IdleExpiry returns when a login session active at the given time expires without further activity,
+// or nil if auth-user-session-idle-ttl-minutes doesn't set an idle timeout.
+func IdleExpiry(now time.Time) *metav1.Time {
+	minutes := settings.AuthUserSessionIdleTTLMinutes.GetInt()
+	if minutes <= 0 {
+		return nil
+	}
+	expiry := metav1.NewTime(now.Add(time.Duration(minutes) * time.Minute).UTC())
+	return &expiry
+}
+
 func GetTokenAuthFromRequest(req *http.Request) string {
 	var tokenAuthValue string
 	authHeader := req.Header.Get(AuthHeaderName)
@@ -132,6 +145,11 @@ func GetKubeConfigToken(userName, responseType string, userMGR user.Manager, use
 	return token, tokenVal, nil
 }
 
+// KubeconfigClusterID returns the cluster of a kubeconfig login responseType, kubeconfig_<cluster>, if any.
+func KubeconfigClusterID(responseType string) string {
+	return extractClusterIDFromResponseType(responseType)
+}
+
 func extractClusterIDFromResponseType(responseType string) string {
 	responseSplit := strings.SplitN(responseType, "_", 2)
 	if len(responseSplit) != 2 {
@@ -146,18 +164,28 @@ func VerifyToken(storedToken *v3.Token, tokenName, tokenKey string) (int, error)
 	if storedToken == nil || storedToken.Name != tokenName {
 		return http.StatusUnprocessableEntity, invalidAuthTokenErr
 	}
+	storedKey, err := StoredKey(storedToken)
+	if err != nil {
+		logrus.Errorf("unable to read the key of token %s: %v", tokenName, err)
+		return http.StatusInternalServerError, fmt.Errorf("unable to verify token")
+	}
 	if storedToken.Annotations != nil && storedToken.Annotations[TokenHashed] == "true" {
-		hasher, err := hashers.GetHasherForHash(storedToken.Token)
+		hasher, err := hashers.GetHasherForHash(storedKey)
 		if err != nil {
 			logrus.Errorf("unable to get a hasher for token with error %v", err)
 			return http.StatusInternalServerError, fmt.Errorf("unable to verify hash")
 		}
-		if err := hasher.VerifyHash(storedToken.Token, tokenKey); err != nil {
+		if err := hasher.VerifyHash(storedKey, tokenKey); err != nil {
 			logrus.Errorf("VerifyHash failed with error: %v", err)
 			return http.StatusUnprocessableEntity, invalidAuthTokenErr
 		}
 	} else {
-		if storedToken.Token != tokenKey {
+		// tokens which aren't hashed are only accepted until the token hashing transition ends
+		if !AcceptsUnhashedTokens(time.Now()) {
+			logrus.Warnf("Token %s isn't hashed after the end of the token hashing transition", tokenName)
+			return http.StatusUnprocessableEntity, invalidAuthTokenErr
+		}
+		if storedKey != tokenKey {
 			return http.StatusUnprocessableEntity, invalidAuthTokenErr
 		}
 	}
@@ -171,14 +199,19 @@ func VerifyToken(storedToken *v3.Token, tokenName, tokenKey string) (int, error)
 	return http.StatusOK, nil
 }
 
-// ConvertTokenKeyToHash takes a token with an un-hashed key and converts it to a hashed key
+// ConvertTokenKeyToHash takes a token with an un-hashed key and converts it to a hashed key, if token hashing is enabled.
+// The key is then encrypted, if token encryption is configured.
 func ConvertTokenKeyToHash(token *v3.Token) error {
-	if !features.TokenHashing.Enabled() {
+	if token == nil || len(token.Token) == 0 {
 		return nil
 	}
-	if token != nil && len(token.Token) > 0 {
+	if features.TokenHashing.Enabled() && token.Annotations[TokenHashed] != "true" {
+		key, err := StoredKey(token)
+		if err != nil {
+			return err
+		}
 		hasher := hashers.GetHasher()
-		hashedToken, err := hasher.CreateHash(token.Token)
+		hashedToken, err := hasher.CreateHash(key)
 		if err != nil {
 			logrus.Errorf("Failed to generate hash from token: %v", err)
 			return errors.New("failed to generate hash from token")
@@ -189,5 +222,54 @@ func ConvertTokenKeyToHash(token *v3.Token) error {
 		}
 		token.Annotations[TokenHashed] = "true"
 	}
+	return EncryptTokenKey(token)
+}
+
+// EncryptTokenKey encrypts the key, or hash, of the token if token encryption is configured and it isn't already encrypted.
+func EncryptTokenKey(token *v3.Token) error {
+	if token == nil || len(token.Token) == 0 {
+		return nil
+	}
+	encrypted, err := encryptValue(token.Token)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt token: %w", err)
+	}
+	token.Token = encrypted
 	return nil
 }
+
+// StoredKey returns the key, or hash, of the token, decrypting it if it's encrypted.
+func StoredKey(token *v3.Token) (string, error) {
+	key, err := decryptValue(token.Token)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt token: %w", err)
+	}
+	return key, nil
+}
+
+// encryptValue encrypts the value if token encryption is configured and it isn't already encrypted.
+func encryptValue(value string) (string, error) {
+	if encryption.IsEncrypted(value) {
+		return value, nil
+	}
+	encrypter, err := encryption.Current()
+	if err != nil || encrypter == nil {
+		return value, err
+	}
+	return encrypter.Encrypt(value)
+}
+
+// decryptValue returns the value an encrypted value was encrypted from, or the value itself if it isn't encrypted.
+func decryptValue(value string) (string, error) {
+	if !encryption.IsEncrypted(value) {
+		return value, nil
+	}
+	encrypter, err := encryption.Current()
+	if err != nil {
+		return "", err
+	}
+	if encrypter == nil {
+		return "", errors.New("token encryption isn't configured")
+	}
+	return encrypter.Decrypt(value)
+}
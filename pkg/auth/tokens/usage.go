@@ -0,0 +1,290 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// TokenUsageLabel marks the secrets holding the usage history of tokens.
+	TokenUsageLabel = "cattle.io/token-usage"
+
+	usageLink         = "usage"
+	usageSecretPrefix = "tokenusage-"
+	usageEventsKey    = "events"
+
+	// usageDedupInterval is how often the same use of a token, from the same address and client to the same class
+	// of endpoint, is recorded.
+	usageDedupInterval = time.Minute
+	// maxRecentUsages bounds the uses remembered for deduplication before the expired ones are dropped.
+	maxRecentUsages  = 10000
+	maxUserAgentSize = 256
+)
+
+// UsageEvent is a use of a token.
+type UsageEvent struct {
+	Time          metav1.Time `json:"time"`
+	SourceIP      string      `json:"sourceIp"`
+	UserAgent     string      `json:"userAgent"`
+	EndpointClass string      `json:"endpointClass"`
+}
+
+type usageKey struct {
+	token         string
+	sourceIP      string
+	userAgent     string
+	endpointClass string
+}
+
+// UsageRecorder records the recent uses of tokens in secrets, one per token, owned by the token.
+// The history of a token is bounded by auth-token-usage-history-max-events and auth-token-usage-history-retention-hours.
+type UsageRecorder struct {
+	secrets wcorev1.SecretClient
+	now     func() time.Time
+
+	mu     sync.Mutex
+	recent map[usageKey]time.Time
+}
+
+// NewUsageRecorder returns a UsageRecorder storing the usage history of tokens in secrets.
+func NewUsageRecorder(secrets wcorev1.SecretClient) *UsageRecorder {
+	return &UsageRecorder{
+		secrets: secrets,
+		now:     time.Now,
+		recent:  map[usageKey]time.Time{},
+	}
+}
+
+// Record adds the event to the usage history of the token in the background, unless the same use was recorded
+// in the last minute.
+func (r *UsageRecorder) Record(token *v32.Token, event UsageEvent) {
+	if usageHistoryMaxEvents() <= 0 || !r.shouldRecord(token.Name, event) {
+		return
+	}
+	go func() {
+		if err := r.add(token, event); err != nil {
+			logrus.Errorf("Error recording usage of token %s: %v", token.Name, err)
+		}
+	}()
+}
+
+// shouldRecord tells whether the event isn't the same use of the token as one recorded in the last minute.
+func (r *UsageRecorder) shouldRecord(tokenName string, event UsageEvent) bool {
+	key := usageKey{
+		token:         tokenName,
+		sourceIP:      event.SourceIP,
+		userAgent:     event.UserAgent,
+		endpointClass: event.EndpointClass,
+	}
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if last, ok := r.recent[key]; ok && now.Sub(last) < usageDedupInterval {
+		return false
+	}
+	if len(r.recent) >= maxRecentUsages {
+		for k, last := range r.recent {
+			if now.Sub(last) >= usageDedupInterval {
+				delete(r.recent, k)
+			}
+		}
+	}
+	r.recent[key] = now
+	return true
+}
+
+// add appends the event to the usage history of the token, dropping the events past the retention.
+func (r *UsageRecorder) add(token *v32.Token, event UsageEvent) error {
+	if len(event.UserAgent) > maxUserAgentSize {
+		event.UserAgent = event.UserAgent[:maxUserAgentSize]
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := r.secrets.Get(SecretNamespace, UsageSecretName(token.Name), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			events, err := json.Marshal(pruneUsageEvents([]UsageEvent{event}, r.now()))
+			if err != nil {
+				return err
+			}
+			_, err = r.secrets.Create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      UsageSecretName(token.Name),
+					Namespace: SecretNamespace,
+					Labels: map[string]string{
+						TokenUsageLabel: "true",
+						UserIDLabel:     token.UserID,
+					},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: v3.TokenGroupVersionKind.GroupVersion().String(),
+						Kind:       v3.TokenGroupVersionKind.Kind,
+						Name:       token.Name,
+						UID:        token.UID,
+					}},
+				},
+				Data: map[string][]byte{usageEventsKey: events},
+			})
+			if apierrors.IsAlreadyExists(err) {
+				// Retry with the secret created concurrently.
+				return apierrors.NewConflict(corev1.Resource("secrets"), UsageSecretName(token.Name), err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		history, err := ParseUsageEvents(secret)
+		if err != nil {
+			// Start over rather than keep failing on a corrupted history.
+			logrus.Warnf("Discarding the usage history of token %s: %v", token.Name, err)
+		}
+		events, err := json.Marshal(pruneUsageEvents(append(history, event), r.now()))
+		if err != nil {
+			return err
+		}
+		secret = secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[usageEventsKey] = events
+		_, err = r.secrets.Update(secret)
+		return err
+	})
+}
+
+// pruneUsageEvents drops the events older than the retention, then the oldest events past the maximum number of events.
+func pruneUsageEvents(events []UsageEvent, now time.Time) []UsageEvent {
+	if hours, err := strconv.ParseInt(settings.AuthTokenUsageHistoryRetentionHours.Get(), 10, 64); err == nil && hours > 0 {
+		cutoff := now.Add(-time.Duration(hours) * time.Hour)
+		kept := events[:0]
+		for _, event := range events {
+			if event.Time.Time.After(cutoff) {
+				kept = append(kept, event)
+			}
+		}
+		events = kept
+	}
+	if maxEvents := usageHistoryMaxEvents(); len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+	return events
+}
+
+// ParseUsageEvents returns the usage events of a token, oldest first, from its usage history secret.
+func ParseUsageEvents(secret *corev1.Secret) ([]UsageEvent, error) {
+	var events []UsageEvent
+	if data := secret.Data[usageEventsKey]; len(data) > 0 {
+		if err := json.Unmarshal(data, &events); err != nil {
+			return nil, fmt.Errorf("invalid usage history in secret %s: %w", secret.Name, err)
+		}
+	}
+	return events, nil
+}
+
+// UsageSecretName returns the name of the secret holding the usage history of a token.
+func UsageSecretName(tokenName string) string {
+	return usageSecretPrefix + tokenName
+}
+
+func usageHistoryMaxEvents() int {
+	n, err := strconv.Atoi(settings.AuthTokenUsageHistoryMaxEvents.Get())
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// usageFormatter adds the link to the usage history of tokens.
+func usageFormatter(apiContext *types.APIContext, resource *types.RawResource) {
+	resource.Links[usageLink] = apiContext.URLBuilder.Link(usageLink, resource)
+}
+
+// getTokenUsage writes the usage history of a token, most recent first.
+// Users can read the history of their tokens, and those allowed to get any token, like admins, of all tokens.
+func (m *Manager) getTokenUsage(apiContext *types.APIContext) error {
+	tokenAuthValue := GetTokenAuthFromRequest(apiContext.Request)
+	if tokenAuthValue == "" {
+		return httperror.NewAPIError(httperror.Unauthorized, "No valid token cookie or auth header")
+	}
+	currentAuthToken, _, err := m.getToken(tokenAuthValue)
+	if err != nil {
+		return httperror.NewAPIError(httperror.Unauthorized, err.Error())
+	}
+
+	token, err := m.tokensClient.Get(apiContext.ID, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("%s not found", apiContext.ID))
+		}
+		return err
+	}
+	if token.UserID != currentAuthToken.UserID {
+		allowed, err := m.canGetToken(apiContext.Request, token.Name)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return httperror.NewAPIError(httperror.NotFound, fmt.Sprintf("%s not found", apiContext.ID))
+		}
+	}
+
+	var events []UsageEvent
+	secret, err := m.secretLister.Get(SecretNamespace, UsageSecretName(token.Name))
+	switch {
+	case err == nil:
+		if events, err = ParseUsageEvents(secret); err != nil {
+			return err
+		}
+	case !apierrors.IsNotFound(err):
+		return err
+	}
+	// Most recent first.
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	if events == nil {
+		events = []UsageEvent{}
+	}
+
+	apiContext.Response.Header().Set("Content-Type", "application/json")
+	apiContext.Response.WriteHeader(http.StatusOK)
+	return json.NewEncoder(apiContext.Response).Encode(map[string]interface{}{
+		"type": "collection",
+		"data": events,
+	})
+}
+
+// canGetToken tells whether the user of the request is allowed to get the token, other than by owning it.
+func (m *Manager) canGetToken(req *http.Request, tokenName string) (bool, error) {
+	userInfo, ok := request.UserFrom(req.Context())
+	if !ok {
+		return false, nil
+	}
+	return util.Authorize(req.Context(), m.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    v3.TokenGroupVersionKind.Group,
+		Resource: v3.TokenResource.Name,
+		Verb:     "get",
+		Name:     tokenName,
+	})
+}
@@ -0,0 +1,113 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestUsageRecorderDeduplicates(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := NewUsageRecorder(nil)
+	r.now = func() time.Time { return now }
+
+	event := UsageEvent{SourceIP: "203.0.113.5", UserAgent: "kubectl/v1.30.0", EndpointClass: "kubernetes:list"}
+	assert.True(t, r.shouldRecord("token-abcde", event))
+	assert.False(t, r.shouldRecord("token-abcde", event))
+
+	// Other tokens, addresses, clients and endpoints are recorded.
+	assert.True(t, r.shouldRecord("token-fghij", event))
+	other := event
+	other.SourceIP = "198.51.100.7"
+	assert.True(t, r.shouldRecord("token-abcde", other))
+	other = event
+	other.EndpointClass = "kubernetes:delete"
+	assert.True(t, r.shouldRecord("token-abcde", other))
+
+	now = now.Add(usageDedupInterval)
+	assert.True(t, r.shouldRecord("token-abcde", event))
+}
+
+func TestUsageRecorderAdd(t *testing.T) {
+	require.NoError(t, settings.AuthTokenUsageHistoryMaxEvents.Set("3"))
+	defer settings.AuthTokenUsageHistoryMaxEvents.Set(settings.AuthTokenUsageHistoryMaxEvents.Default)
+	require.NoError(t, settings.AuthTokenUsageHistoryRetentionHours.Set("24"))
+	defer settings.AuthTokenUsageHistoryRetentionHours.Set(settings.AuthTokenUsageHistoryRetentionHours.Default)
+
+	ctrl := gomock.NewController(t)
+	stored := map[string]*corev1.Secret{}
+	gr := schema.GroupResource{Resource: "secrets"}
+	version := 0
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		version++
+		created := secret.DeepCopy()
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if stored[secret.Name].ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := NewUsageRecorder(secrets)
+	r.now = func() time.Time { return now }
+	token := &v32.Token{ObjectMeta: metav1.ObjectMeta{Name: "token-abcde", UID: "1234"}, UserID: "u-abcde"}
+
+	for i := 0; i < 4; i++ {
+		event := UsageEvent{Time: metav1.NewTime(now), SourceIP: fmt.Sprintf("203.0.113.%d", i), EndpointClass: "rancher:get"}
+		require.NoError(t, r.add(token, event))
+		now = now.Add(time.Hour)
+	}
+
+	secret := stored[UsageSecretName(token.Name)]
+	require.NotNil(t, secret)
+	assert.Equal(t, "u-abcde", secret.Labels[UserIDLabel])
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, "Token", secret.OwnerReferences[0].Kind)
+	assert.Equal(t, token.UID, secret.OwnerReferences[0].UID)
+
+	// Only the last events are kept.
+	events, err := ParseUsageEvents(secret)
+	require.NoError(t, err)
+	var ips []string
+	for _, event := range events {
+		ips = append(ips, event.SourceIP)
+	}
+	assert.Equal(t, []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"}, ips)
+
+	// Events past the retention are dropped.
+	now = now.Add(24 * time.Hour)
+	require.NoError(t, r.add(token, UsageEvent{Time: metav1.NewTime(now), SourceIP: "198.51.100.7", UserAgent: string(make([]byte, 1000))}))
+	require.NoError(t, json.Unmarshal(stored[UsageSecretName(token.Name)].Data[usageEventsKey], &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "198.51.100.7", events[0].SourceIP)
+	assert.Len(t, events[0].UserAgent, maxUserAgentSize)
+}
@@ -0,0 +1,193 @@
+// Package tracing traces the logins and the token validations with OpenTelemetry, from the API handlers to the auth
+// providers, their LDAP and HTTP calls, and the token store, so that the slow logins can be diagnosed from a trace.
+// The spans are exported to the OTLP collector of the auth-tracing-otlp-endpoint setting, and nothing is traced if it's
+// empty. The trace context of the requests, in the traceparent header, is honored.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	instrumentationName = "github.com/rancher/rancher/pkg/auth"
+	serviceName         = "rancher"
+	// shutdownTimeout bounds the time spent flushing the spans of a replaced provider.
+	shutdownTimeout = 10 * time.Second
+)
+
+var propagator = propagation.TraceContext{}
+
+// providerCache holds the tracer provider of the auth-tracing settings, built again when they change, so that the
+// tracing can be enabled without restarting.
+type providerCache struct {
+	mu       sync.Mutex
+	ctx      context.Context
+	value    string
+	provider trace.TracerProvider
+	shutdown func(context.Context) error
+}
+
+var providers = &providerCache{provider: noop.NewTracerProvider()}
+
+// Start enables the tracing until the context is done, when the pending spans are flushed.
+func Start(ctx context.Context) {
+	providers.mu.Lock()
+	defer providers.mu.Unlock()
+	providers.ctx = ctx
+
+	go func() {
+		<-ctx.Done()
+		providers.mu.Lock()
+		defer providers.mu.Unlock()
+		providers.replace(noop.NewTracerProvider(), nil)
+		providers.value = ""
+	}()
+}
+
+// current returns the tracer provider of the settings, or a provider tracing nothing until Start is called.
+func (c *providerCache) current() trace.TracerProvider {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ctx == nil || c.ctx.Err() != nil {
+		return noop.NewTracerProvider()
+	}
+	value := fmt.Sprintf("%s|%s|%s", settings.AuthTracingOTLPEndpoint.Get(), settings.AuthTracingOTLPInsecure.Get(), settings.AuthTracingSampleRatio.Get())
+	if value == c.value {
+		return c.provider
+	}
+
+	c.value = value
+	provider, err := newProvider(c.ctx)
+	if err != nil {
+		// The previous provider is kept until the settings are fixed.
+		logrus.Errorf("[tracing] invalid auth tracing settings, keeping the previous ones: %v", err)
+		return c.provider
+	}
+	if provider == nil {
+		c.replace(noop.NewTracerProvider(), nil)
+	} else {
+		c.replace(provider, provider.Shutdown)
+	}
+	return c.provider
+}
+
+// replace sets the provider, flushing the spans of the previous one in the background.
+func (c *providerCache) replace(provider trace.TracerProvider, shutdown func(context.Context) error) {
+	previous := c.shutdown
+	c.provider, c.shutdown = provider, shutdown
+	if previous == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := previous(ctx); err != nil {
+			logrus.Warnf("[tracing] failed to flush the auth spans: %v", err)
+		}
+	}()
+}
+
+// newProvider returns the provider exporting the spans to the collector of the settings, or nil if there is none.
+func newProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	endpoint := settings.AuthTracingOTLPEndpoint.Get()
+	if endpoint == "" {
+		return nil, nil
+	}
+	ratio, err := strconv.ParseFloat(settings.AuthTracingSampleRatio.Get(), 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return nil, fmt.Errorf("%s must be a number between 0 and 1", settings.AuthTracingSampleRatio.Name)
+	}
+
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if settings.AuthTracingOTLPInsecure.Get() == "true" {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+	// The exporter connects lazily, so that an unreachable collector doesn't fail the logins.
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP exporter of %s: %w", endpoint, err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	), nil
+}
+
+// StartSpan starts a span, child of the span of the context if any. It must be ended with End.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return providers.current().Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends the span, recording the error if it isn't nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Extract returns the context of the request with the trace context of its headers, for its spans to be part of the
+// caller's trace.
+func Extract(req *http.Request) context.Context {
+	return propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+}
+
+// Transport wraps the round tripper to trace the requests, and to propagate the trace context to the servers.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// Unwrap returns the wrapped round tripper.
+func (t *transport) Unwrap() http.RoundTripper {
+	return t.base
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := providers.current().Tracer(instrumentationName).Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		))
+
+	req = req.Clone(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
@@ -0,0 +1,117 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans makes the spans recorded until the end of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := providers
+	providers = &providerCache{ctx: context.Background(), provider: provider}
+	// The cached value must match the settings, for the provider not to be built again.
+	providers.value = settings.AuthTracingOTLPEndpoint.Get() + "|" + settings.AuthTracingOTLPInsecure.Get() + "|" + settings.AuthTracingSampleRatio.Get()
+	t.Cleanup(func() { providers = previous })
+	return recorder
+}
+
+func TestNothingTracedWithoutStart(t *testing.T) {
+	_, span := StartSpan(context.Background(), "auth.login")
+	defer span.End()
+	assert.False(t, span.IsRecording())
+}
+
+func TestNothingTracedWithoutEndpoint(t *testing.T) {
+	previous := providers
+	providers = &providerCache{ctx: context.Background()}
+	defer func() { providers = previous }()
+
+	_, span := StartSpan(context.Background(), "auth.login")
+	defer span.End()
+	assert.False(t, span.IsRecording())
+}
+
+func TestInvalidSampleRatio(t *testing.T) {
+	require.NoError(t, settings.AuthTracingOTLPEndpoint.Set("localhost:4317"))
+	defer settings.AuthTracingOTLPEndpoint.Set(settings.AuthTracingOTLPEndpoint.Default)
+	require.NoError(t, settings.AuthTracingSampleRatio.Set("2"))
+	defer settings.AuthTracingSampleRatio.Set(settings.AuthTracingSampleRatio.Default)
+
+	_, err := newProvider(context.Background())
+	assert.ErrorContains(t, err, "must be a number between 0 and 1")
+}
+
+func TestEnd(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, parent := StartSpan(context.Background(), "auth.login")
+	_, child := StartSpan(ctx, "auth.provider.authenticate")
+	End(child, errors.New("invalid credentials"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "auth.provider.authenticate", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "invalid credentials", spans[0].Status().Description)
+	assert.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestExtract(t *testing.T) {
+	recorder := recordSpans(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/v3-public/localProviders/local?action=login", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, span := StartSpan(Extract(req), "auth.login")
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
+
+func TestTransport(t *testing.T) {
+	recorder := recordSpans(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, parent := StartSpan(context.Background(), "auth.login")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/token", nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "HTTP GET", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, traceparent, spans[0].SpanContext().SpanID().String())
+	// The request of the caller isn't changed.
+	assert.Empty(t, req.Header.Get("traceparent"))
+}
@@ -32,7 +32,7 @@ func NewUserLabeler(ctx context.Context, wContext *wrangler.Context) *UserLabele
 		userCache:          wContext.Mgmt.User().Cache(),
 		users:              wContext.Mgmt.User(),
 		userAttributeCache: wContext.Mgmt.UserAttribute().Cache(),
-		readSettings:       readSettings,
+		readSettings:       settingsReader(wContext.Mgmt.AuthConfig().Cache()),
 	}
 }
 
@@ -178,27 +178,43 @@ func setLabels(settings settings, user *mgmtv3.User, attribs *mgmtv3.UserAttribu
 
 	var deleteAfterTime, disableAfterTime time.Time
 
-	if settings.ShouldDelete() && !user.IsDefaultAdmin() && !lastLogin.IsZero() {
+	excluded := settings.isExcluded(user, attribs)
+	userPolicy := settings.policyFor(user)
+	if excluded {
+		userPolicy = policy{} // Excluded users are never disabled or deleted.
+	}
+
+	if userPolicy.deleteAfter != 0 && !user.IsDefaultAdmin() && !lastLogin.IsZero() {
 		if attribs.DeleteAfter != nil {
 			if userDeleteAfter := attribs.DeleteAfter.Duration; userDeleteAfter > 0 {
 				deleteAfterTime = lastLogin.Add(userDeleteAfter) // User-specific override.
 			}
 		} else {
-			deleteAfterTime = lastLogin.Add(settings.deleteAfter)
+			deleteAfterTime = lastLogin.Add(userPolicy.deleteAfter)
 		}
 	}
 	updated = ensureLabel(deleteAfterTime, DeleteAfterLabelKey, user) || updated
 
-	if settings.ShouldDisable() && !user.IsDefaultAdmin() && !lastLogin.IsZero() {
+	if userPolicy.disableAfter != 0 && !user.IsDefaultAdmin() && !lastLogin.IsZero() {
 		if attribs.DisableAfter != nil {
 			if userDisableAfter := attribs.DisableAfter.Duration; userDisableAfter > 0 {
 				disableAfterTime = lastLogin.Add(userDisableAfter) // User-specific override.
 			}
 		} else {
-			disableAfterTime = lastLogin.Add(settings.disableAfter)
+			disableAfterTime = lastLogin.Add(userPolicy.disableAfter)
+		}
+	}
+	updated = ensureLabel(disableAfterTime, DisableAfterLabelKey, user) || updated
+
+	// The inactive flag is set by the retention process, and removed once the user logs in again.
+	if _, ok := user.Labels[InactiveLabelKey]; ok {
+		if !settings.ShouldFlag() || excluded || lastLogin.IsZero() ||
+			time.Now().Before(lastLogin.Add(settings.flagAfter)) {
+			delete(user.Labels, InactiveLabelKey)
+			updated = true
 		}
 	}
-	return ensureLabel(disableAfterTime, DisableAfterLabelKey, user) || updated
+	return updated
 }
 
 // toEpochTimeString returns the epoch time as a string.
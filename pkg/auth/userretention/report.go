@@ -0,0 +1,100 @@
+package userretention
+
+import (
+	"encoding/json"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReportConfigMapName is the name of the config map, in the cattle-system namespace, holding the report of the
+	// last run of the user retention process.
+	ReportConfigMapName = "user-retention-report"
+	reportKey           = "report"
+
+	// FlaggedEvent, DisabledEvent and DeletedEvent are the audit events of the user retention process.
+	FlaggedEvent  = "InactiveUserFlagged"
+	DisabledEvent = "InactiveUserDisabled"
+	DeletedEvent  = "InactiveUserDeleted"
+)
+
+// Report is the outcome of a run of the user retention process. In dry-run mode, it lists the users which would have been
+// flagged, disabled or deleted.
+type Report struct {
+	StartedAt  metav1.Time   `json:"startedAt"`
+	FinishedAt metav1.Time   `json:"finishedAt"`
+	DryRun     bool          `json:"dryRun"`
+	Processed  int           `json:"processed"`
+	Skipped    int           `json:"skipped"`
+	Excluded   int           `json:"excluded"`
+	Errors     int           `json:"errors"`
+	Flagged    []ReportEntry `json:"flagged"`
+	Disabled   []ReportEntry `json:"disabled"`
+	Deleted    []ReportEntry `json:"deleted"`
+}
+
+// ReportEntry is a user acted upon by the user retention process.
+type ReportEntry struct {
+	User      string      `json:"user"`
+	Username  string      `json:"username,omitempty"`
+	LastLogin metav1.Time `json:"lastLogin"`
+}
+
+func (r *Report) add(entries *[]ReportEntry, user *v3.User, lastLogin time.Time) {
+	*entries = append(*entries, ReportEntry{
+		User:      user.Name,
+		Username:  user.Username,
+		LastLogin: metav1.NewTime(lastLogin),
+	})
+}
+
+// audit logs an action of the user retention process on a user.
+func audit(event string, user *v3.User, lastLogin time.Time, dryRun bool) {
+	logrus.WithFields(logrus.Fields{
+		"event":     event,
+		"user":      user.Name,
+		"username":  user.Username,
+		"lastLogin": lastLogin.Format(time.RFC3339),
+		"dryRun":    dryRun,
+	}).Info("userretention: audit")
+}
+
+// reportWriter returns a function storing the report in the ReportConfigMapName config map.
+func reportWriter(configMaps wcorev1.ConfigMapClient) func(*Report) error {
+	return func(report *Report) error {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+
+		configMap, err := configMaps.Get(namespace.System, ReportConfigMapName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			_, err = configMaps.Create(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ReportConfigMapName,
+					Namespace: namespace.System,
+				},
+				Data: map[string]string{reportKey: string(data)},
+			})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		configMap = configMap.DeepCopy()
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[reportKey] = string(data)
+		_, err = configMaps.Update(configMap)
+		return err
+	}
+}
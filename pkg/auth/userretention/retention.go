@@ -20,6 +20,8 @@
 	LastLoginLabelKey    = "cattle.io/last-login"
 	DisableAfterLabelKey = "cattle.io/disable-after"
 	DeleteAfterLabelKey  = "cattle.io/delete-after"
+	// InactiveLabelKey flags the users inactive for longer than FlagInactiveUserAfter.
+	InactiveLabelKey = "cattle.io/inactive"
 )
 
 // Retention is the user retention process that disables or deletes inactive users
@@ -31,11 +33,14 @@
 // - only disable users (disableAfter > 0 && deleteAfter == 0)
 // - progressively disable and delete users (0 < disableAfter < deleteAfter)
 // - only delete users (disableAfter == 0 && deleteAfter > 0 or 0 < deleteAfter < disableAfter)
+// Users can also be flagged as inactive ahead of that, and excluded from retention altogether. The auth configs can
+// override the settings for the users of their provider.
 type Retention struct {
 	userAttributeCache mgmtcontrollers.UserAttributeCache
 	userCache          mgmtcontrollers.UserCache
 	users              mgmtcontrollers.UserClient
 	readSettings       func() (settings, error)
+	writeReport        func(*Report) error
 }
 
 // New creates a new instance of Retention.
@@ -44,7 +49,8 @@ func New(wContext *wrangler.Context) *Retention {
 		userCache:          wContext.Mgmt.User().Cache(),
 		users:              wContext.Mgmt.User(),
 		userAttributeCache: wContext.Mgmt.UserAttribute().Cache(),
-		readSettings:       readSettings,
+		readSettings:       settingsReader(wContext.Mgmt.AuthConfig().Cache()),
+		writeReport:        reportWriter(wContext.Core.ConfigMap()),
 	}
 }
 
@@ -62,14 +68,14 @@ func (r *Retention) Run(ctx context.Context) error {
 		return fmt.Errorf("error reading settings: %w, retention is disabled", err)
 	}
 
-	if !settings.ShouldDisable() && !settings.ShouldDelete() {
-		logrus.Info("userretention: nothing to do, neither DisableInactiveUserAfter nor DeleteInactiveUserAfter is set")
+	if !settings.ShouldDisable() && !settings.ShouldDelete() && !settings.ShouldFlag() {
+		logrus.Info("userretention: nothing to do, neither DisableInactiveUserAfter, DeleteInactiveUserAfter nor FlagInactiveUserAfter is set")
 		return nil
 	}
 
 	logrus.Infof(
-		"userretention: started (disable-inactive-user-after %s, delete-inactive-user-after %s, user-last-login-default %s, user-retention-dry-run %t)",
-		settings.disableAfter, settings.deleteAfter, settings.FormatDefaultLastLogin(), settings.dryRun,
+		"userretention: started (disable-inactive-user-after %s, delete-inactive-user-after %s, flag-inactive-user-after %s, user-last-login-default %s, user-retention-dry-run %t, auth config policies %d, exclusions %d)",
+		settings.disableAfter, settings.deleteAfter, settings.flagAfter, settings.FormatDefaultLastLogin(), settings.dryRun, len(settings.providers), len(settings.excluded),
 	)
 
 	users, err := r.userCache.List(labels.Everything())
@@ -77,15 +83,25 @@ func (r *Retention) Run(ctx context.Context) error {
 		return fmt.Errorf("error listing users: %w", err)
 	}
 
-	var processed, skipped, disabled, deleted, errCount int
+	var processed, skipped, excluded, flagged, disabled, deleted, errCount int
 	now := time.Now()
+	report := &Report{StartedAt: metav1.NewTime(startedAt), DryRun: settings.dryRun}
 
 	defer func() {
 		logrus.Infof(
-			"userretention: finished in %v seconds (processed %d, skipped %d, disabled %d, deleted %d, errors %d)",
+			"userretention: finished in %v seconds (processed %d, skipped %d, excluded %d, flagged %d, disabled %d, deleted %d, errors %d)",
 			time.Since(startedAt).Seconds(),
-			processed, skipped, disabled, deleted, errCount,
+			processed, skipped, excluded, flagged, disabled, deleted, errCount,
 		)
+
+		if r.writeReport == nil {
+			return
+		}
+		report.FinishedAt = metav1.Now()
+		report.Processed, report.Skipped, report.Excluded, report.Errors = processed, skipped, excluded, errCount
+		if err := r.writeReport(report); err != nil {
+			logrus.Errorf("userretention: error writing the report: %v", err)
+		}
 	}()
 
 	for _, user := range users {
@@ -119,12 +135,19 @@ func (r *Retention) Run(ctx context.Context) error {
 
 		var (
 			userDeleteAfter, userDisableAfter time.Duration
-			disableUser                       bool
+			disableUser, flagUser             bool
 		)
 
+		isExcluded := settings.isExcluded(user, attribs)
+		if isExcluded {
+			logrus.Debugf("userretention: user %s is excluded", user.Name)
+			excluded++
+		}
+
+		userPolicy := settings.policyFor(user)
 		lastLogin := lastLoginTime(settings, attribs)
-		if !lastLogin.IsZero() {
-			deleteAfterTime := lastLogin.Add(settings.deleteAfter)
+		if !lastLogin.IsZero() && !isExcluded {
+			deleteAfterTime := lastLogin.Add(userPolicy.deleteAfter)
 			if attribs.DeleteAfter != nil { // Apply user-specific override.
 				if userDeleteAfter = attribs.DeleteAfter.Duration; userDeleteAfter <= 0 {
 					deleteAfterTime = time.Time{} // The user shouldn't be considered for deletion.
@@ -134,7 +157,7 @@ func (r *Retention) Run(ctx context.Context) error {
 			}
 			deleteAfterTime = deleteAfterTime.Truncate(time.Second)
 
-			disableAfterTime := lastLogin.Add(settings.disableAfter)
+			disableAfterTime := lastLogin.Add(userPolicy.disableAfter)
 			if attribs.DisableAfter != nil { // Apply user-specific override.
 				if userDisableAfter = attribs.DisableAfter.Duration; userDisableAfter <= 0 {
 					disableAfterTime = time.Time{} // The user shouldn't be considered for being disabled.
@@ -149,9 +172,11 @@ func (r *Retention) Run(ctx context.Context) error {
 				skipped++ // This is to keep the counter updated.
 			}
 
-			if settings.ShouldDelete() && !deleteAfterTime.IsZero() &&
+			if userPolicy.deleteAfter != 0 && !deleteAfterTime.IsZero() &&
 				now.After(deleteAfterTime) {
 				logrus.Infof("userretention: deleting user %s", user.Name)
+				report.add(&report.Deleted, user, lastLogin)
+				audit(DeletedEvent, user, lastLogin, settings.dryRun)
 
 				if !settings.dryRun {
 					err := r.users.Delete(user.Name, &metav1.DeleteOptions{})
@@ -167,13 +192,23 @@ func (r *Retention) Run(ctx context.Context) error {
 
 			}
 
-			if settings.ShouldDisable() && !disableAfterTime.IsZero() &&
+			if userPolicy.disableAfter != 0 && !disableAfterTime.IsZero() &&
 				now.After(disableAfterTime) && pointer.BoolDeref(user.Enabled, true) {
 				logrus.Infof("userretention: disabling user %s", user.Name)
+				report.add(&report.Disabled, user, lastLogin)
+				audit(DisabledEvent, user, lastLogin, settings.dryRun)
 				// Flag the needed update but don't apply it as we may need to update retention labels too.
 				disableUser = true
 				disabled++
 			}
+
+			if settings.ShouldFlag() && now.After(lastLogin.Add(settings.flagAfter)) && user.Labels[InactiveLabelKey] != "true" {
+				logrus.Infof("userretention: flagging user %s as inactive", user.Name)
+				report.add(&report.Flagged, user, lastLogin)
+				audit(FlaggedEvent, user, lastLogin, settings.dryRun)
+				flagUser = true
+				flagged++
+			}
 		}
 
 		var userGetTry int
@@ -204,6 +239,10 @@ func (r *Retention) Run(ctx context.Context) error {
 
 			// Update the retention labels if necessary.
 			labelsUpdated := setLabels(settings, user, attribs)
+			if flagUser && user.Labels[InactiveLabelKey] != "true" {
+				user.Labels[InactiveLabelKey] = "true"
+				labelsUpdated = true
+			}
 
 			// No user updates; return early.
 			if !labelsUpdated && !disableUser {
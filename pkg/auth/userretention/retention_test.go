@@ -560,3 +560,126 @@ func TestRetentionRunContextCancelled(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRetentionRunPoliciesAndReport(t *testing.T) {
+	users := map[string]*v3.User{
+		"u-ckrl4grxg5": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "u-ckrl4grxg5",
+			},
+			PrincipalIDs: []string{"activedirectory_user://CN=testuser2,CN=Users,DC=qa,DC=rancher,DC=space", "local://u-ckrl4grxg5"},
+			Enabled:      pointer.Bool(true),
+		},
+		"u-cx7gc": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "u-cx7gc",
+			},
+			Username:     "testuser",
+			PrincipalIDs: []string{"local://u-cx7gc"},
+			Enabled:      pointer.Bool(true),
+		},
+		"u-mo773yttt4": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "u-mo773yttt4",
+			},
+			Username:     "breakglass",
+			PrincipalIDs: []string{"local://u-mo773yttt4"},
+			Enabled:      pointer.Bool(true),
+		},
+	}
+	userAttributes := map[string]*v3.UserAttribute{}
+	for name := range users {
+		userAttributes[name] = &v3.UserAttribute{LastLogin: &metav1.Time{Time: time.Now().Add(-time.Hour)}}
+	}
+
+	ctrl := gomock.NewController(t)
+
+	usersCacheClient := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	usersCacheClient.EXPECT().List(gomock.Any()).Times(1).DoAndReturn(func(selector labels.Selector) ([]*v3.User, error) {
+		result := make([]*v3.User, 0, len(users))
+		for _, user := range users {
+			result = append(result, user)
+		}
+		return result, nil
+	})
+
+	usersClient := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+	usersClient.EXPECT().Update(gomock.Any()).Times(0)
+	usersClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+	userAttributeCacheClient := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	userAttributeCacheClient.EXPECT().Get(gomock.Any()).AnyTimes().DoAndReturn(func(name string) (*v3.UserAttribute, error) {
+		if attr, ok := userAttributes[name]; ok {
+			return attr, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+	})
+
+	var report *Report
+	retention := Retention{
+		userAttributeCache: userAttributeCacheClient,
+		userCache:          usersCacheClient,
+		users:              usersClient,
+		readSettings: func() (settings, error) {
+			return settings{
+				disableAfter: 3 * time.Hour,
+				flagAfter:    30 * time.Minute,
+				dryRun:       true,
+				excluded:     map[string]bool{"breakglass": true},
+				providers: map[string]policy{
+					"activedirectory": {disableAfter: 30 * time.Minute},
+				},
+			}, nil
+		},
+		writeReport: func(r *Report) error {
+			report = r
+			return nil
+		},
+	}
+
+	err := retention.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report == nil {
+		t.Fatal("Expected a report")
+	}
+	if want, got := true, report.DryRun; want != got {
+		t.Errorf("Expected DryRun %t got %t", want, got)
+	}
+	if want, got := 3, report.Processed; want != got {
+		t.Errorf("Expected Processed %d got %d", want, got)
+	}
+	if want, got := 1, report.Excluded; want != got {
+		t.Errorf("Expected Excluded %d got %d", want, got)
+	}
+
+	reported := func(entries []ReportEntry) []string {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.User)
+		}
+		sort.Strings(names)
+		return names
+	}
+	if want, got := []string{"u-ckrl4grxg5"}, reported(report.Disabled); !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected disabled users %v got %v", want, got)
+	}
+	if want, got := []string{"u-ckrl4grxg5", "u-cx7gc"}, reported(report.Flagged); !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected flagged users %v got %v", want, got)
+	}
+	if want, got := 0, len(report.Deleted); want != got {
+		t.Errorf("Expected deleted users %d got %d", want, got)
+	}
+
+	// Nothing is changed in dry-run mode.
+	for id, user := range users {
+		if want, got := true, pointer.BoolDeref(user.Enabled, false); want != got {
+			t.Errorf("Expected Enabled for user %s %t got %t", id, want, got)
+		}
+		if want, got := "", user.Labels[InactiveLabelKey]; want != got {
+			t.Errorf("Expected label %s for user %s %q got %q", InactiveLabelKey, id, want, got)
+		}
+	}
+}
@@ -5,25 +5,133 @@
 	"strings"
 	"time"
 
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	appsettings "github.com/rancher/rancher/pkg/settings"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// DisableAfterAnnotation and DeleteAfterAnnotation set on an auth config override the DisableInactiveUserAfter and
+	// DeleteInactiveUserAfter settings for the users of the auth provider. A zero value means never.
+	DisableAfterAnnotation = "auth.cattle.io/disable-inactive-user-after"
+	DeleteAfterAnnotation  = "auth.cattle.io/delete-inactive-user-after"
 )
 
 // settings control user retention process.
 type settings struct {
 	disableAfter     time.Duration
 	deleteAfter      time.Duration
+	flagAfter        time.Duration
 	defaultLastLogin time.Time
 	dryRun           bool
+	// excluded are the names, usernames and principal IDs of the users, and the principal IDs of the groups,
+	// which aren't subject to retention.
+	excluded map[string]bool
+	// providers are the policies of the auth providers overriding disableAfter and deleteAfter.
+	providers map[string]policy
+}
+
+// policy is how long users can be inactive before being disabled or deleted, zero meaning never.
+type policy struct {
+	disableAfter time.Duration
+	deleteAfter  time.Duration
 }
 
 // ShouldDisable returns true if the user retention process should disable users.
 func (s *settings) ShouldDisable() bool {
-	return s.disableAfter != 0
+	if s.disableAfter != 0 {
+		return true
+	}
+	for _, p := range s.providers {
+		if p.disableAfter != 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // ShouldDelete returns true if the user retention process should delete users.
 func (s *settings) ShouldDelete() bool {
-	return s.deleteAfter != 0
+	if s.deleteAfter != 0 {
+		return true
+	}
+	for _, p := range s.providers {
+		if p.deleteAfter != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldFlag returns true if the user retention process should flag inactive users.
+func (s *settings) ShouldFlag() bool {
+	return s.flagAfter != 0
+}
+
+// policyFor returns the policy applying to the user, which is the one of the auth providers of its principals,
+// or the settings if none has one. The most lenient one applies to users of several auth providers with a policy.
+func (s *settings) policyFor(user *v3.User) policy {
+	var (
+		result policy
+		found  bool
+	)
+	for _, principalID := range user.PrincipalIDs {
+		p, ok := s.providers[providerOf(principalID)]
+		if !ok {
+			continue
+		}
+		if !found {
+			result, found = p, true
+			continue
+		}
+		result.disableAfter = lenient(result.disableAfter, p.disableAfter)
+		result.deleteAfter = lenient(result.deleteAfter, p.deleteAfter)
+	}
+	if !found {
+		return policy{disableAfter: s.disableAfter, deleteAfter: s.deleteAfter}
+	}
+	return result
+}
+
+// isExcluded returns true if the user, or one of its groups, is on the exclusion list.
+func (s *settings) isExcluded(user *v3.User, attribs *v3.UserAttribute) bool {
+	if len(s.excluded) == 0 {
+		return false
+	}
+	if s.excluded[user.Name] || (user.Username != "" && s.excluded[user.Username]) {
+		return true
+	}
+	for _, principalID := range user.PrincipalIDs {
+		if s.excluded[principalID] {
+			return true
+		}
+	}
+	if attribs != nil {
+		for _, groups := range attribs.GroupPrincipals {
+			for _, group := range groups.Items {
+				if s.excluded[group.Name] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// lenient returns the longest duration, zero meaning never.
+func lenient(a, b time.Duration) time.Duration {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return max(a, b)
+}
+
+// providerOf returns the auth provider of a principal ID, like activedirectory for activedirectory_user://CN=user.
+func providerOf(principalID string) string {
+	scheme, _, _ := strings.Cut(principalID, "://")
+	provider, _, _ := strings.Cut(scheme, "_")
+	return provider
 }
 
 // FormatDefaultLastLogin returns formatted value of the default last login.
@@ -63,7 +171,76 @@ func readSettings() (settings, error) {
 		}
 	}
 
+	if value := appsettings.FlagInactiveUserAfter.Get(); value != "" {
+		parsed.flagAfter, err = time.ParseDuration(value)
+		if err != nil {
+			return settings{}, fmt.Errorf("%s: %w", appsettings.FlagInactiveUserAfter.Name, err)
+		}
+	}
+
 	parsed.dryRun = strings.EqualFold(appsettings.UserRetentionDryRun.Get(), "true")
 
+	for _, entry := range strings.Split(appsettings.UserRetentionExclusions.Get(), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			if parsed.excluded == nil {
+				parsed.excluded = map[string]bool{}
+			}
+			parsed.excluded[entry] = true
+		}
+	}
+
 	return parsed, nil
 }
+
+// settingsReader returns a function reading the settings along with the policies of the auth configs.
+func settingsReader(authConfigCache mgmtcontrollers.AuthConfigCache) func() (settings, error) {
+	return func() (settings, error) {
+		parsed, err := readSettings()
+		if err != nil {
+			return settings{}, err
+		}
+
+		authConfigs, err := authConfigCache.List(labels.Everything())
+		if err != nil {
+			return settings{}, fmt.Errorf("error listing auth configs: %w", err)
+		}
+		for _, authConfig := range authConfigs {
+			p, ok, err := authConfigPolicy(&parsed, authConfig)
+			if err != nil {
+				return settings{}, err
+			}
+			if ok {
+				if parsed.providers == nil {
+					parsed.providers = map[string]policy{}
+				}
+				parsed.providers[authConfig.Name] = p
+			}
+		}
+
+		return parsed, nil
+	}
+}
+
+// authConfigPolicy returns the policy set by the annotations of the auth config, if any, falling back to the settings
+// for the one which isn't set.
+func authConfigPolicy(s *settings, authConfig *v3.AuthConfig) (policy, bool, error) {
+	disableAfter, hasDisableAfter := authConfig.Annotations[DisableAfterAnnotation]
+	deleteAfter, hasDeleteAfter := authConfig.Annotations[DeleteAfterAnnotation]
+	if !authConfig.Enabled || (!hasDisableAfter && !hasDeleteAfter) {
+		return policy{}, false, nil
+	}
+
+	p := policy{disableAfter: s.disableAfter, deleteAfter: s.deleteAfter}
+	var err error
+	if hasDisableAfter {
+		if p.disableAfter, err = time.ParseDuration(disableAfter); err != nil {
+			return policy{}, false, fmt.Errorf("auth config %s: %s: %w", authConfig.Name, DisableAfterAnnotation, err)
+		}
+	}
+	if hasDeleteAfter {
+		if p.deleteAfter, err = time.ParseDuration(deleteAfter); err != nil {
+			return policy{}, false, fmt.Errorf("auth config %s: %s: %w", authConfig.Name, DeleteAfterAnnotation, err)
+		}
+	}
+	return p, true, nil
+}
@@ -5,7 +5,9 @@
 	"testing"
 	"time"
 
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	appsettings "github.com/rancher/rancher/pkg/settings"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestSettingsShouldDisable(t *testing.T) {
@@ -208,3 +210,158 @@ func TestReadSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestSettingsPolicyFor(t *testing.T) {
+	s := settings{
+		disableAfter: time.Hour,
+		deleteAfter:  2 * time.Hour,
+		providers: map[string]policy{
+			"activedirectory": {disableAfter: 3 * time.Hour, deleteAfter: 4 * time.Hour},
+			"github":          {disableAfter: 5 * time.Hour},
+		},
+	}
+
+	tests := []struct {
+		desc         string
+		principalIDs []string
+		want         policy
+	}{
+		{
+			desc:         "local user",
+			principalIDs: []string{"local://u-cx7gc"},
+			want:         policy{disableAfter: time.Hour, deleteAfter: 2 * time.Hour},
+		},
+		{
+			desc:         "user of an auth provider with a policy",
+			principalIDs: []string{"activedirectory_user://CN=testuser1,CN=Users,DC=qa,DC=rancher,DC=space", "local://u-ckrl4grxg5"},
+			want:         policy{disableAfter: 3 * time.Hour, deleteAfter: 4 * time.Hour},
+		},
+		{
+			desc:         "user of several auth providers with a policy",
+			principalIDs: []string{"activedirectory_user://CN=testuser1,CN=Users,DC=qa,DC=rancher,DC=space", "github_user://1234"},
+			want:         policy{disableAfter: 5 * time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			user := &v3.User{PrincipalIDs: tt.principalIDs}
+			if want, got := tt.want, s.policyFor(user); want != got {
+				t.Errorf("Expected %+v got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestSettingsIsExcluded(t *testing.T) {
+	s := settings{excluded: map[string]bool{
+		"u-excluded":                   true,
+		"breakglass":                   true,
+		"github_user://1234":           true,
+		"activedirectory_group://CN=x": true,
+	}}
+
+	tests := []struct {
+		desc    string
+		user    *v3.User
+		attribs *v3.UserAttribute
+		want    bool
+	}{
+		{
+			desc: "not excluded",
+			user: &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-cx7gc"}, PrincipalIDs: []string{"local://u-cx7gc"}},
+		},
+		{
+			desc: "excluded by name",
+			user: &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-excluded"}},
+			want: true,
+		},
+		{
+			desc: "excluded by username",
+			user: &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-cx7gc"}, Username: "breakglass"},
+			want: true,
+		},
+		{
+			desc: "excluded by principal",
+			user: &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-cx7gc"}, PrincipalIDs: []string{"github_user://1234"}},
+			want: true,
+		},
+		{
+			desc: "excluded by group",
+			user: &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-cx7gc"}},
+			attribs: &v3.UserAttribute{GroupPrincipals: map[string]v3.Principals{
+				"activedirectory": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "activedirectory_group://CN=x"}}}},
+			}},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.want, s.isExcluded(tt.user, tt.attribs); want != got {
+				t.Errorf("Expected %t got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestAuthConfigPolicy(t *testing.T) {
+	s := &settings{disableAfter: time.Hour, deleteAfter: 2 * time.Hour}
+
+	tests := []struct {
+		desc        string
+		enabled     bool
+		annotations map[string]string
+		want        policy
+		wantOK      bool
+		wantErr     bool
+	}{
+		{
+			desc:        "disabled auth config",
+			annotations: map[string]string{DisableAfterAnnotation: "3h"},
+		},
+		{
+			desc:    "no policy",
+			enabled: true,
+		},
+		{
+			desc:        "disable after override",
+			enabled:     true,
+			annotations: map[string]string{DisableAfterAnnotation: "3h"},
+			want:        policy{disableAfter: 3 * time.Hour, deleteAfter: 2 * time.Hour},
+			wantOK:      true,
+		},
+		{
+			desc:        "never delete",
+			enabled:     true,
+			annotations: map[string]string{DeleteAfterAnnotation: "0s"},
+			want:        policy{disableAfter: time.Hour},
+			wantOK:      true,
+		},
+		{
+			desc:        "invalid duration",
+			enabled:     true,
+			annotations: map[string]string{DeleteAfterAnnotation: "forever"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			authConfig := &v3.AuthConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "activedirectory", Annotations: tt.annotations},
+				Enabled:    tt.enabled,
+			}
+			got, ok, err := authConfigPolicy(s, authConfig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %t got %v", tt.wantErr, err)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("Expected ok %t got %t", tt.wantOK, ok)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// Authorize tells whether the user is allowed the verb on the resource of the attributes, with a SubjectAccessReview.
+func Authorize(ctx context.Context, subjectAccessReviews authv1.SubjectAccessReviewInterface, userInfo user.Info, attributes authzv1.ResourceAttributes) (bool, error) {
+	extra := map[string]authzv1.ExtraValue{}
+	for key, value := range userInfo.GetExtra() {
+		extra[key] = value
+	}
+	response, err := subjectAccessReviews.Create(ctx, &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attributes,
+			User:               userInfo.GetName(),
+			Groups:             userInfo.GetGroups(),
+			Extra:              extra,
+			UID:                userInfo.GetUID(),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to create a SubjectAccessReview: %w", err)
+	}
+	return response.Status.Allowed, nil
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"maps"
+	"strings"
+)
+
+// lifecyclePrefix prefixes the annotations and finalizers norman's lifecycles set on the objects they handled.
+const lifecyclePrefix = "lifecycle.cattle.io/"
+
+// RecreatedAnnotations returns the annotations to set on the copy of a deleted object, without those of norman's
+// lifecycles: when they're kept, the lifecycles consider the copy handled and neither add their finalizers nor run
+// their Create handlers for it.
+func RecreatedAnnotations(annotations map[string]string) map[string]string {
+	if annotations == nil {
+		return nil
+	}
+	recreated := maps.Clone(annotations)
+	maps.DeleteFunc(recreated, func(key, _ string) bool {
+		return strings.HasPrefix(key, lifecyclePrefix)
+	})
+	return recreated
+}
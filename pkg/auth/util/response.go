@@ -0,0 +1,33 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WriteJSON writes the body as the JSON response with the status.
+func WriteJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.Errorf("Failed to write the response: %v", err)
+	}
+}
+
+// WriteOAuthJSON writes the body as the JSON response of an OAuth endpoint, which must not be cached as it can hold
+// tokens.
+func WriteOAuthJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, status, body)
+}
+
+// WriteOAuthError writes the OAuth error response with its error code and optional description.
+func WriteOAuthError(w http.ResponseWriter, status int, code, description string) {
+	response := map[string]string{"error": code}
+	if description != "" {
+		response["error_description"] = description
+	}
+	WriteOAuthJSON(w, status, response)
+}
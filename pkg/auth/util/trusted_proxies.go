@@ -0,0 +1,64 @@
+package util
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+)
+
+// TrustedProxies returns the networks of the proxies in front of Rancher, as set in auth-trusted-proxy-cidrs.
+func TrustedProxies() []*net.IPNet {
+	return ParseCIDRs(strings.Split(settings.AuthTrustedProxyCIDRs.Get(), ","))
+}
+
+// FromTrustedProxy returns whether the request was received from one of the proxies of auth-trusted-proxy-cidrs. The
+// headers set by these proxies can be trusted, those of the other requests are set by the clients.
+func FromTrustedProxy(req *http.Request) bool {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && IsTrusted(ip, TrustedProxies())
+}
+
+// IsTrusted returns whether ip is in one of the networks of the trusted proxies.
+func IsTrusted(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCIDRs parses the CIDRs, or single addresses, skipping the invalid ones.
+func ParseCIDRs(cidrs []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil {
+				bits := 8 * net.IPv6len
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 8*net.IPv4len
+				}
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logrus.Warnf("Ignoring invalid CIDR %q: %v", cidr, err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
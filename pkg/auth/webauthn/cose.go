@@ -0,0 +1,167 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// The COSE algorithms of the supported credentials, see https://www.iana.org/assignments/cose/cose.xhtml.
+const (
+	algES256 = -7
+	algEdDSA = -8
+	algRS256 = -257
+)
+
+// supportedAlgorithms are offered to the authenticators at registration, in order of preference.
+var supportedAlgorithms = []int64{algES256, algEdDSA, algRS256}
+
+const (
+	coseKeyType   = 1
+	coseAlgorithm = 3
+	// The parameters of the keys depend on their type: the curve and coordinates of EC2 and OKP keys, the modulus and
+	// exponent of RSA keys.
+	coseParam1 = -1
+	coseParam2 = -2
+	coseParam3 = -3
+
+	keyTypeOKP = 1
+	keyTypeEC2 = 2
+	keyTypeRSA = 3
+
+	curveP256    = 1
+	curveEd25519 = 6
+)
+
+// publicKey is the public key of a credential, with its COSE algorithm.
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parsePublicKey parses a COSE_Key, as found in the attested credential data of authenticators.
+func parsePublicKey(data []byte) (*publicKey, error) {
+	var fields map[int64]cbor.RawMessage
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("invalid COSE key: %w", err)
+	}
+	var kty, alg int64
+	if err := unmarshalField(fields, coseKeyType, &kty); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(fields, coseAlgorithm, &alg); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case kty == keyTypeEC2 && alg == algES256:
+		var crv int64
+		var x, y []byte
+		if err := unmarshalFields(fields, map[int64]interface{}{coseParam1: &crv, coseParam2: &x, coseParam3: &y}); err != nil {
+			return nil, err
+		}
+		if crv != curveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 key")
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("invalid P-256 key")
+		}
+		return &publicKey{alg: alg, key: key}, nil
+	case kty == keyTypeOKP && alg == algEdDSA:
+		var crv int64
+		var x []byte
+		if err := unmarshalFields(fields, map[int64]interface{}{coseParam1: &crv, coseParam2: &x}); err != nil {
+			return nil, err
+		}
+		if crv != curveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return &publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+	case kty == keyTypeRSA && alg == algRS256:
+		var n, e []byte
+		if err := unmarshalFields(fields, map[int64]interface{}{coseParam1: &n, coseParam2: &e}); err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) < 256 || !exponent.IsInt64() || exponent.Int64() < 3 {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &publicKey{alg: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %d with algorithm %d", kty, alg)
+}
+
+// verify checks the signature of the data with the key.
+func (k *publicKey) verify(data, signature []byte) error {
+	return verifySignature(k.alg, k.key, data, signature)
+}
+
+// verifySignature checks a signature of the data with a key and a COSE algorithm.
+func verifySignature(alg int64, key crypto.PublicKey, data, signature []byte) error {
+	switch alg {
+	case algES256:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		digest := sha256.Sum256(data)
+		if !ok || !ecdsa.VerifyASN1(ecKey, digest[:], signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case algEdDSA:
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(edKey, data, signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case algRS256:
+		rsaKey, ok := key.(*rsa.PublicKey)
+		digest := sha256.Sum256(data)
+		if !ok || rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature) != nil {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm %d", alg)
+}
+
+// certificateAlgorithm returns the COSE algorithm matching the key of an attestation certificate.
+func certificateAlgorithm(cert *x509.Certificate) int64 {
+	switch cert.PublicKeyAlgorithm {
+	case x509.ECDSA:
+		return algES256
+	case x509.Ed25519:
+		return algEdDSA
+	case x509.RSA:
+		return algRS256
+	}
+	return 0
+}
+
+func unmarshalField(fields map[int64]cbor.RawMessage, key int64, v interface{}) error {
+	raw, ok := fields[key]
+	if !ok {
+		return fmt.Errorf("COSE key parameter %d is missing", key)
+	}
+	if err := cbor.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid COSE key parameter %d: %w", key, err)
+	}
+	return nil
+}
+
+func unmarshalFields(fields map[int64]cbor.RawMessage, values map[int64]interface{}) error {
+	for key, v := range values {
+		if err := unmarshalField(fields, key, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
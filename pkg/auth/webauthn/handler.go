@@ -0,0 +1,335 @@
+package webauthn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/rancher/pkg/auth/authevents"
+	"github.com/rancher/rancher/pkg/auth/notifications"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	"github.com/rancher/rancher/pkg/auth/securityevents"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// BasePath is the path of the endpoints managing the credentials, which accept the tokens of MFA enrollment logins.
+	BasePath = "/v1-webauthn"
+	// LoginPath is the path of the unauthenticated endpoint returning the login challenges.
+	LoginPath = "/v1-webauthn-login"
+
+	// beginAction starts a registration, finishAction completes it with the response of the authenticator.
+	beginAction  = "begin"
+	finishAction = "finish"
+
+	maxBodySize = 64 * 1024
+)
+
+type beginInput struct {
+	// Passwordless registers a passkey, which can be used instead of the password.
+	Passwordless bool `json:"passwordless"`
+}
+
+type loginInput struct {
+	Username string `json:"username"`
+}
+
+type handler struct {
+	manager              *Manager
+	userCache            mgmtcontrollers.UserCache
+	tokenCache           mgmtcontrollers.TokenCache
+	tokens               mgmtcontrollers.TokenClient
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+	notifier             *notifications.Notifier
+}
+
+func newHandler(mgmt *config.ScaledContext) *handler {
+	return &handler{
+		manager:              NewManager(mgmt.Wrangler.Core.Secret()),
+		userCache:            mgmt.Wrangler.Mgmt.User().Cache(),
+		tokenCache:           mgmt.Wrangler.Mgmt.Token().Cache(),
+		tokens:               mgmt.Wrangler.Mgmt.Token(),
+		subjectAccessReviews: mgmt.K8sClient.AuthorizationV1().SubjectAccessReviews(),
+		notifier:             notifications.NewNotifier(mgmt),
+	}
+}
+
+// NewHandler returns the handler of the endpoints managing the credentials.
+func NewHandler(mgmt *config.ScaledContext) http.Handler {
+	return newHandler(mgmt).router()
+}
+
+// NewLoginHandler returns the unauthenticated handler of the login challenges.
+func NewLoginHandler(mgmt *config.ScaledContext) http.Handler {
+	return newHandler(mgmt).loginRouter()
+}
+
+func (h *handler) router() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodGet).Path(BasePath + "/credentials").HandlerFunc(h.list)
+	root.Methods(http.MethodPost).Path(BasePath+"/credentials").Queries("action", beginAction).HandlerFunc(h.beginRegistration)
+	root.Methods(http.MethodPost).Path(BasePath+"/credentials").Queries("action", finishAction).HandlerFunc(h.finishRegistration)
+	root.Methods(http.MethodDelete).Path(BasePath + "/credentials/{id}").HandlerFunc(h.remove)
+	root.Methods(http.MethodDelete).Path(BasePath + "/users/{user}").HandlerFunc(h.reset)
+	return root
+}
+
+func (h *handler) loginRouter() http.Handler {
+	root := mux.NewRouter()
+	root.UseEncodedPath()
+	root.Methods(http.MethodPost).Path(LoginPath).HandlerFunc(h.beginLogin)
+	return root
+}
+
+// list writes the credentials of the caller.
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	credentials, err := h.manager.Credentials(userInfo.GetName())
+	if err != nil {
+		logrus.Errorf("[webauthn] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to list the credentials")
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"type": "collection",
+		"data": credentials,
+	})
+}
+
+// beginRegistration writes the options of a new credential of the caller, who must be a local user.
+func (h *handler) beginRegistration(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	var input beginInput
+	if !readJSON(w, r, &input) {
+		return
+	}
+	u, err := h.userCache.Get(userInfo.GetName())
+	if err != nil {
+		logrus.Errorf("[webauthn] failed to get user %s: %v", userInfo.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to start the registration")
+		return
+	}
+	if u.Username == "" {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, "security keys are only supported for local users")
+		return
+	}
+
+	options, err := h.manager.BeginRegistration(u, input.Passwordless)
+	if errors.Is(err, errTooManyCredentials) {
+		util.ReturnHTTPError(w, r, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[webauthn] failed to start a registration for user %s: %v", u.Name, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to start the registration")
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, options)
+}
+
+// finishRegistration verifies the response of the authenticator and adds the credential. The MFA enrollment login of
+// the request, if any, is logged out, so that the user logs in again with the new credential.
+func (h *handler) finishRegistration(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	var response RegistrationResponse
+	if !readJSON(w, r, &response) {
+		return
+	}
+
+	credential, err := h.manager.FinishRegistration(userInfo.GetName(), response)
+	if errors.Is(err, ErrInvalidRegistration) {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, errTooManyCredentials) {
+		util.ReturnHTTPError(w, r, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[webauthn] failed to register a credential for user %s: %v", userInfo.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to register the credential")
+		return
+	}
+	h.mfaChanged(r, userInfo.GetName(), "A security key was registered")
+
+	if ids := userInfo.GetExtra()[common.ExtraRequestTokenID]; len(ids) > 0 {
+		token, err := h.tokenCache.Get(ids[0])
+		if err == nil && token.Labels[tokens.TokenKindLabel] == tokens.MFAEnrollmentTokenKind {
+			if err := h.tokens.Delete(token.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				logrus.Errorf("[webauthn] failed to delete the enrollment token %s: %v", token.Name, err)
+			}
+		}
+	}
+	util.WriteJSON(w, http.StatusCreated, credential)
+}
+
+// remove removes a credential of the caller.
+func (h *handler) remove(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	err := h.manager.RemoveCredential(userInfo.GetName(), id)
+	if errors.Is(err, errNotFound) {
+		util.ReturnHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("credential %s not found", id))
+		return
+	}
+	if err != nil {
+		logrus.Errorf("[webauthn] failed to remove credential %s of user %s: %v", id, userInfo.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to remove the credential")
+		return
+	}
+	h.mfaChanged(r, userInfo.GetName(), "A security key was removed")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reset removes the credentials of the user of the path, for the users who lost their keys.
+// It's only allowed to those who can update any user.
+func (h *handler) reset(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := caller(w, r)
+	if !ok {
+		return
+	}
+	userID := mux.Vars(r)["user"]
+	allowed, err := h.authorize(r.Context(), userInfo)
+	if err != nil {
+		logrus.Errorf("[webauthn] failed to authorize user %s: %v", userInfo.GetName(), err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to authorize")
+		return
+	}
+	if !allowed {
+		util.ReturnHTTPError(w, r, http.StatusForbidden, fmt.Sprintf("not allowed to reset the credentials of user %s", userID))
+		return
+	}
+
+	if err := h.manager.Reset(userID); err != nil {
+		logrus.Errorf("[webauthn] %v", err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to reset the credentials")
+		return
+	}
+	logrus.Infof("[webauthn] user %s reset the credentials of user %s", userInfo.GetName(), userID)
+	h.mfaDisabled(r, userID, "The security keys were removed by an administrator")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// beginLogin writes the options of a login challenge of the local user with the username.
+func (h *handler) beginLogin(w http.ResponseWriter, r *http.Request) {
+	var input loginInput
+	if !readJSON(w, r, &input) {
+		return
+	}
+	userID, err := h.localUser(input.Username)
+	if err != nil {
+		logrus.Errorf("[webauthn] failed to look up user %s: %v", input.Username, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to start the login")
+		return
+	}
+	options, err := h.manager.BeginLogin(userID)
+	if err != nil {
+		logrus.Errorf("[webauthn] failed to start a login for user %s: %v", userID, err)
+		util.ReturnHTTPError(w, r, http.StatusInternalServerError, "failed to start the login")
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, options)
+}
+
+// localUser returns the ID of the local user with the username, empty if there's none.
+func (h *handler) localUser(username string) (string, error) {
+	if username == "" {
+		return "", nil
+	}
+	users, err := h.userCache.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	for _, u := range users {
+		if u.Username == username {
+			return u.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// authorize tells whether the user can update all users.
+func (h *handler) authorize(ctx context.Context, userInfo user.Info) (bool, error) {
+	return util.Authorize(ctx, h.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    mgmtv3.UserGroupVersionKind.Group,
+		Resource: mgmtv3.UserResource.Name,
+		Verb:     "update",
+	})
+}
+
+func caller(w http.ResponseWriter, r *http.Request) (user.Info, bool) {
+	userInfo, ok := request.UserFrom(r.Context())
+	if !ok {
+		util.ReturnHTTPError(w, r, http.StatusUnauthorized, "must authenticate")
+		return nil, false
+	}
+	return userInfo, true
+}
+
+// readJSON decodes the body of the request, an empty body leaves v unchanged.
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		util.ReturnHTTPError(w, r, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
+
+// mfaChanged notifies the user of a change of their second factors and records it, with the caller as the actor when
+// they changed those of another user.
+func (h *handler) mfaChanged(r *http.Request, userID, change string) {
+	h.notifier.MFAChanged(userID, change)
+	event := authevents.Event{
+		Type:     authevents.MFAChanged,
+		UserName: userID,
+		Detail:   change,
+	}
+	if userInfo, ok := request.UserFrom(r.Context()); ok && userInfo.GetName() != userID {
+		event.Actor = userInfo.GetName()
+	}
+	authevents.Record(event)
+}
+
+// mfaDisabled records the disabling of a second factor of the user as a security event too.
+func (h *handler) mfaDisabled(r *http.Request, userID, change string) {
+	h.mfaChanged(r, userID, change)
+	event := securityevents.Event{
+		Type:     securityevents.MFADisabled,
+		UserName: userID,
+		Target:   userID,
+		Detail:   change,
+	}
+	if userInfo, ok := request.UserFrom(r.Context()); ok && userInfo.GetName() != userID {
+		event.Actor = userInfo.GetName()
+	}
+	securityevents.Record(event)
+}
@@ -0,0 +1,205 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	clientDataTypeCreate = "webauthn.create"
+	clientDataTypeGet    = "webauthn.get"
+
+	attestationFormatNone   = "none"
+	attestationFormatPacked = "packed"
+
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagAttestedCredData = 0x40
+
+	// authenticatorDataSize is the size of the RP ID hash, the flags and the signature counter.
+	authenticatorDataSize = 37
+	aaguidSize            = 16
+	maxCredentialIDSize   = 1023
+)
+
+// encoding is the encoding of the binary fields of the WebAuthn messages exchanged with the clients.
+var encoding = base64.RawURLEncoding
+
+// clientData is the JSON data the browser signs with the challenge.
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin,omitempty"`
+}
+
+// authenticatorData is the data signed by the authenticator.
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+	// The attested credential data is only in the authenticator data of registrations.
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+// attestationObject is the response of the authenticator to a registration.
+type attestationObject struct {
+	Format    string          `cbor:"fmt"`
+	Statement cbor.RawMessage `cbor:"attStmt"`
+	AuthData  []byte          `cbor:"authData"`
+}
+
+// packedStatement is the attestation statement of the packed format.
+type packedStatement struct {
+	Alg int64    `cbor:"alg"`
+	Sig []byte   `cbor:"sig"`
+	X5C [][]byte `cbor:"x5c,omitempty"`
+}
+
+// parseClientData decodes the client data and checks it's for the ceremony, the challenge and the origin.
+func parseClientData(raw []byte, ceremony, challenge, origin string) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("invalid client data: %w", err)
+	}
+	if data.Type != ceremony {
+		return fmt.Errorf("client data type %q is not %q", data.Type, ceremony)
+	}
+	if subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1 {
+		return errors.New("challenge mismatch")
+	}
+	if data.Origin != origin || data.CrossOrigin {
+		return fmt.Errorf("origin %q is not allowed", data.Origin)
+	}
+	return nil
+}
+
+// challengeOf returns the challenge of the client data, to find the pending challenge it answers.
+func challengeOf(raw []byte) string {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return ""
+	}
+	return data.Challenge
+}
+
+// parseAuthenticatorData decodes the authenticator data, and checks it's for the relying party and the user was present.
+func parseAuthenticatorData(raw []byte, rpID string) (*authenticatorData, error) {
+	if len(raw) < authenticatorDataSize {
+		return nil, errors.New("authenticator data is too short")
+	}
+	data := &authenticatorData{
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	if !bytes.Equal(data.rpIDHash, rpIDHash[:]) {
+		return nil, errors.New("relying party ID mismatch")
+	}
+	if data.flags&flagUserPresent == 0 {
+		return nil, errors.New("user presence is required")
+	}
+	if data.flags&flagAttestedCredData == 0 {
+		return data, nil
+	}
+
+	rest := raw[authenticatorDataSize:]
+	if len(rest) < aaguidSize+2 {
+		return nil, errors.New("attested credential data is too short")
+	}
+	data.aaguid = rest[:aaguidSize]
+	idSize := int(binary.BigEndian.Uint16(rest[aaguidSize : aaguidSize+2]))
+	rest = rest[aaguidSize+2:]
+	if idSize > maxCredentialIDSize || len(rest) < idSize {
+		return nil, errors.New("invalid credential ID")
+	}
+	data.credentialID = rest[:idSize]
+	// The public key is followed by the extensions, if any.
+	var key cbor.RawMessage
+	if _, err := cbor.UnmarshalFirst(rest[idSize:], &key); err != nil {
+		return nil, fmt.Errorf("invalid credential public key: %w", err)
+	}
+	data.publicKey = key
+	return data, nil
+}
+
+// userVerified tells whether the authenticator verified the user, with a PIN or biometrics.
+func (d *authenticatorData) userVerified() bool {
+	return d.flags&flagUserVerified != 0
+}
+
+// aaguidString formats an AAGUID as a UUID, the way the authenticator metadata lists them.
+func aaguidString(aaguid []byte) string {
+	if len(aaguid) != aaguidSize {
+		return ""
+	}
+	h := hex.EncodeToString(aaguid)
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// verifyPackedAttestation checks a packed attestation statement, and returns the attestation certificate chain,
+// empty for self attestation.
+func verifyPackedAttestation(statement cbor.RawMessage, authData []byte, clientDataHash []byte, key *publicKey) ([]*x509.Certificate, error) {
+	var stmt packedStatement
+	if err := cbor.Unmarshal(statement, &stmt); err != nil {
+		return nil, fmt.Errorf("invalid packed attestation statement: %w", err)
+	}
+	signed := append(append([]byte{}, authData...), clientDataHash...)
+
+	if len(stmt.X5C) == 0 {
+		// Self attestation is signed with the credential key.
+		if stmt.Alg != key.alg {
+			return nil, errors.New("self attestation algorithm mismatch")
+		}
+		if err := key.verify(signed, stmt.Sig); err != nil {
+			return nil, fmt.Errorf("self attestation: %w", err)
+		}
+		return nil, nil
+	}
+
+	var chain []*x509.Certificate
+	for _, der := range stmt.X5C {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attestation certificate: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+	if stmt.Alg != certificateAlgorithm(chain[0]) {
+		return nil, errors.New("attestation algorithm mismatch")
+	}
+	if err := verifySignature(stmt.Alg, chain[0].PublicKey, signed, stmt.Sig); err != nil {
+		return nil, fmt.Errorf("attestation: %w", err)
+	}
+	return chain, nil
+}
+
+// verifyAttestationChain checks the attestation certificate chains to one of the trusted roots.
+func verifyAttestationChain(chain []*x509.Certificate, roots *x509.CertPool) error {
+	if len(chain) == 0 {
+		return errors.New("self attestation is not trusted")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
@@ -0,0 +1,662 @@
+// Package webauthn lets local users register FIDO2 security keys and passkeys, and use them to log in: as a second
+// factor after their password, or instead of their password for the passkeys which verify the user.
+// The credentials of a user and their pending challenges are stored in a secret of the user.
+package webauthn
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/rancher/norman/httperror"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AttestationPolicyNone accepts any authenticator.
+	AttestationPolicyNone = "none"
+	// AttestationPolicyDirect only accepts the authenticators with an attestation certified by a trusted CA.
+	AttestationPolicyDirect = "direct"
+
+	secretPrefix = "webauthn-"
+	stateKey     = "webauthn"
+
+	challengeSize = 32
+	challengeTTL  = 5 * time.Minute
+	// maxPendingLogins is how many login challenges of a user can be pending at the same time.
+	maxPendingLogins = 5
+	// maxCredentials is how many security keys and passkeys a user can register.
+	maxCredentials = 10
+)
+
+var (
+	// ErrInvalidAssertion is returned for the logins with an assertion which doesn't verify.
+	ErrInvalidAssertion = httperror.NewAPIError(httperror.Unauthorized, "invalid security key assertion")
+	// ErrInvalidRegistration is returned for registrations which don't verify or aren't allowed by the policies.
+	ErrInvalidRegistration = errors.New("invalid registration")
+
+	errTooManyCredentials = fmt.Errorf("a user can't register more than %d credentials", maxCredentials)
+	errNotFound           = errors.New("credential not found")
+
+	// aaguidExtension is the OID of the extension of the attestation certificates holding the AAGUID.
+	aaguidExtension = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+)
+
+// Credential is a registered security key or passkey.
+type Credential struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// AAGUID identifies the model of the authenticator.
+	AAGUID string `json:"aaguid,omitempty"`
+	// Passwordless tells whether the credential can be used instead of the password.
+	Passwordless bool         `json:"passwordless"`
+	CreatedAt    metav1.Time  `json:"createdAt"`
+	LastUsedAt   *metav1.Time `json:"lastUsedAt,omitempty"`
+}
+
+// storedCredential is a Credential with its public key and signature counter.
+type storedCredential struct {
+	Credential
+	PublicKey []byte `json:"publicKey"`
+	SignCount uint32 `json:"signCount"`
+}
+
+type pendingChallenge struct {
+	Challenge    string      `json:"challenge"`
+	ExpiresAt    metav1.Time `json:"expiresAt"`
+	Passwordless bool        `json:"passwordless,omitempty"`
+}
+
+// state is what's stored for a user.
+type state struct {
+	Credentials  []storedCredential `json:"credentials,omitempty"`
+	Registration *pendingChallenge  `json:"registration,omitempty"`
+	Logins       []pendingChallenge `json:"logins,omitempty"`
+}
+
+type relyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type userEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type credentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type credentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type authenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions are the options of navigator.credentials.create for a registration, with base64url binary fields.
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     relyingPartyEntity     `json:"rp"`
+	User                   userEntity             `json:"user"`
+	PubKeyCredParams       []credentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []credentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection authenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions are the options of navigator.credentials.get for a login, with base64url binary fields.
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	RPID             string                 `json:"rpId"`
+	Timeout          int64                  `json:"timeout"`
+	AllowCredentials []credentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// RegistrationResponse is the response of the authenticator to a registration, with base64url binary fields.
+type RegistrationResponse struct {
+	// Name is the name the user gives to the credential.
+	Name              string `json:"name"`
+	ID                string `json:"id"`
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject"`
+}
+
+// Manager registers the credentials of the users and verifies their assertions.
+type Manager struct {
+	secrets wcorev1.SecretClient
+	now     func() time.Time
+}
+
+// NewManager returns a Manager storing the credentials with the secrets client.
+func NewManager(secrets wcorev1.SecretClient) *Manager {
+	return &Manager{
+		secrets: secrets,
+		now:     time.Now,
+	}
+}
+
+// SecretName returns the name of the secret holding the credentials of the user.
+func SecretName(userID string) string {
+	return secretPrefix + userID
+}
+
+// relyingParty returns the relying party ID and the origin of the ceremonies, derived from server-url.
+func relyingParty() (string, string, error) {
+	serverURL, err := url.Parse(settings.ServerURL.Get())
+	if err != nil || serverURL.Host == "" {
+		return "", "", errors.New("server-url is not set")
+	}
+	rpID := settings.AuthWebAuthnRPID.Get()
+	if rpID == "" {
+		rpID = serverURL.Hostname()
+	}
+	return rpID, serverURL.Scheme + "://" + serverURL.Host, nil
+}
+
+// BeginRegistration returns the options to register a new credential for the user. Passkeys must verify the user
+// and be discoverable, so that they can replace the password.
+func (m *Manager) BeginRegistration(user *v3.User, passwordless bool) (*CreationOptions, error) {
+	rpID, _, err := relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	secret, st, err := m.load(user.Name)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		secret = newSecret(user)
+	}
+	if len(st.Credentials) >= maxCredentials {
+		return nil, errTooManyCredentials
+	}
+	challenge, err := newChallenge()
+	if err != nil {
+		return nil, err
+	}
+	st.Registration = &pendingChallenge{
+		Challenge:    challenge,
+		ExpiresAt:    metav1.NewTime(m.now().Add(challengeTTL)),
+		Passwordless: passwordless,
+	}
+	if err := m.save(secret, st); err != nil {
+		return nil, err
+	}
+
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Username
+	}
+	options := &CreationOptions{
+		Challenge: challenge,
+		RP:        relyingPartyEntity{ID: rpID, Name: "Rancher"},
+		User: userEntity{
+			ID:          encoding.EncodeToString([]byte(user.Name)),
+			Name:        user.Username,
+			DisplayName: displayName,
+		},
+		Timeout: challengeTTL.Milliseconds(),
+		AuthenticatorSelection: authenticatorSelection{
+			ResidentKey:      "discouraged",
+			UserVerification: "discouraged",
+		},
+		Attestation: "none",
+	}
+	for _, alg := range supportedAlgorithms {
+		options.PubKeyCredParams = append(options.PubKeyCredParams, credentialParameter{Type: "public-key", Alg: alg})
+	}
+	for _, credential := range st.Credentials {
+		options.ExcludeCredentials = append(options.ExcludeCredentials, credentialDescriptor{Type: "public-key", ID: credential.ID})
+	}
+	if passwordless {
+		options.AuthenticatorSelection = authenticatorSelection{ResidentKey: "required", UserVerification: "required"}
+	}
+	if settings.AuthWebAuthnAttestationPolicy.Get() == AttestationPolicyDirect {
+		options.Attestation = "direct"
+	}
+	return options, nil
+}
+
+// FinishRegistration verifies the response of the authenticator to the pending registration of the user, and adds
+// the new credential.
+func (m *Manager) FinishRegistration(userID string, response RegistrationResponse) (*Credential, error) {
+	rpID, origin, err := relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	secret, st, err := m.load(userID)
+	if err != nil {
+		return nil, err
+	}
+	pending := st.Registration
+	if secret == nil || pending == nil || !m.now().Before(pending.ExpiresAt.Time) {
+		return nil, fmt.Errorf("%w: no pending registration", ErrInvalidRegistration)
+	}
+
+	credential, err := m.verifyRegistration(response, pending, rpID, origin)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRegistration, err)
+	}
+	for _, existing := range st.Credentials {
+		if existing.ID == credential.ID {
+			return nil, fmt.Errorf("%w: credential already registered", ErrInvalidRegistration)
+		}
+	}
+	if len(st.Credentials) >= maxCredentials {
+		return nil, errTooManyCredentials
+	}
+
+	st.Credentials = append(st.Credentials, *credential)
+	st.Registration = nil
+	if err := m.save(secret, st); err != nil {
+		return nil, err
+	}
+	return &credential.Credential, nil
+}
+
+func (m *Manager) verifyRegistration(response RegistrationResponse, pending *pendingChallenge, rpID, origin string) (*storedCredential, error) {
+	clientDataJSON, err := encoding.DecodeString(response.ClientDataJSON)
+	if err != nil {
+		return nil, errors.New("invalid client data encoding")
+	}
+	if err := parseClientData(clientDataJSON, clientDataTypeCreate, pending.Challenge, origin); err != nil {
+		return nil, err
+	}
+	rawAttestation, err := encoding.DecodeString(response.AttestationObject)
+	if err != nil {
+		return nil, errors.New("invalid attestation object encoding")
+	}
+	var attestation attestationObject
+	if err := cbor.Unmarshal(rawAttestation, &attestation); err != nil {
+		return nil, fmt.Errorf("invalid attestation object: %w", err)
+	}
+	authData, err := parseAuthenticatorData(attestation.AuthData, rpID)
+	if err != nil {
+		return nil, err
+	}
+	if authData.credentialID == nil {
+		return nil, errors.New("attested credential data is missing")
+	}
+	if pending.Passwordless && !authData.userVerified() {
+		return nil, errors.New("passkeys must verify the user")
+	}
+	id := encoding.EncodeToString(authData.credentialID)
+	if id != response.ID {
+		return nil, errors.New("credential ID mismatch")
+	}
+	key, err := parsePublicKey(authData.publicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := checkAttestation(&attestation, clientDataHash[:], key, authData.aaguid); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(response.Name)
+	if name == "" {
+		name = "Security key"
+	}
+	return &storedCredential{
+		Credential: Credential{
+			ID:           id,
+			Name:         name,
+			AAGUID:       aaguidString(authData.aaguid),
+			Passwordless: pending.Passwordless,
+			CreatedAt:    metav1.NewTime(m.now()),
+		},
+		PublicKey: authData.publicKey,
+		SignCount: authData.signCount,
+	}, nil
+}
+
+// checkAttestation checks the attestation of a new credential against auth-webauthn-attestation-policy, and its
+// authenticator model against auth-webauthn-allowed-aaguids.
+func checkAttestation(attestation *attestationObject, clientDataHash []byte, key *publicKey, aaguid []byte) error {
+	direct := settings.AuthWebAuthnAttestationPolicy.Get() == AttestationPolicyDirect
+	switch attestation.Format {
+	case attestationFormatPacked:
+		chain, err := verifyPackedAttestation(attestation.Statement, attestation.AuthData, clientDataHash, key)
+		if err != nil {
+			return err
+		}
+		if len(chain) > 0 {
+			if err := checkCertificateAAGUID(chain[0], aaguid); err != nil {
+				return err
+			}
+		}
+		if direct {
+			roots := x509.NewCertPool()
+			roots.AppendCertsFromPEM([]byte(settings.AuthWebAuthnAttestationCACerts.Get()))
+			if err := verifyAttestationChain(chain, roots); err != nil {
+				return fmt.Errorf("untrusted attestation: %w", err)
+			}
+		}
+	case attestationFormatNone:
+		if direct {
+			return errors.New("an attestation is required")
+		}
+	default:
+		// The other formats are accepted without verification when no attestation is required.
+		if direct {
+			return fmt.Errorf("unsupported attestation format %q", attestation.Format)
+		}
+	}
+
+	allowed := settings.AuthWebAuthnAllowedAAGUIDs.Get()
+	if allowed == "" {
+		return nil
+	}
+	model := aaguidString(aaguid)
+	for _, entry := range strings.Split(allowed, ",") {
+		if strings.EqualFold(strings.TrimSpace(entry), model) {
+			return nil
+		}
+	}
+	return fmt.Errorf("authenticator model %s is not allowed", model)
+}
+
+// checkCertificateAAGUID checks the AAGUID of an attestation certificate, if any, is the one of the authenticator data.
+func checkCertificateAAGUID(cert *x509.Certificate, aaguid []byte) error {
+	for _, extension := range cert.Extensions {
+		if !extension.Id.Equal(aaguidExtension) {
+			continue
+		}
+		var value []byte
+		if _, err := asn1.Unmarshal(extension.Value, &value); err != nil || string(value) != string(aaguid) {
+			return errors.New("attestation certificate AAGUID mismatch")
+		}
+	}
+	return nil
+}
+
+// BeginLogin returns the options of a login challenge of the user, listing their credentials. Users without
+// credentials get a challenge too, which can't be answered, so that the response doesn't tell whether they exist.
+func (m *Manager) BeginLogin(userID string) (*RequestOptions, error) {
+	rpID, _, err := relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := newChallenge()
+	if err != nil {
+		return nil, err
+	}
+	options := &RequestOptions{
+		Challenge:        challenge,
+		RPID:             rpID,
+		Timeout:          challengeTTL.Milliseconds(),
+		UserVerification: "preferred",
+	}
+	if userID == "" {
+		return options, nil
+	}
+
+	secret, st, err := m.load(userID)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || len(st.Credentials) == 0 {
+		return options, nil
+	}
+	m.prune(st)
+	st.Logins = append(st.Logins, pendingChallenge{
+		Challenge: challenge,
+		ExpiresAt: metav1.NewTime(m.now().Add(challengeTTL)),
+	})
+	if len(st.Logins) > maxPendingLogins {
+		st.Logins = st.Logins[len(st.Logins)-maxPendingLogins:]
+	}
+	if err := m.save(secret, st); err != nil {
+		return nil, err
+	}
+	for _, credential := range st.Credentials {
+		options.AllowCredentials = append(options.AllowCredentials, credentialDescriptor{Type: "public-key", ID: credential.ID})
+	}
+	return options, nil
+}
+
+// VerifyLogin verifies an assertion of a credential of the user to one of their pending login challenges.
+// Passwordless logins need a passkey which verified the user. It returns ErrInvalidAssertion if the assertion
+// doesn't verify.
+func (m *Manager) VerifyLogin(userID string, assertion v32.WebAuthnAssertion, passwordless bool) error {
+	rpID, origin, err := relyingParty()
+	if err != nil {
+		return err
+	}
+	secret, st, err := m.load(userID)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return ErrInvalidAssertion
+	}
+	m.prune(st)
+
+	if err := m.verifyAssertion(st, assertion, passwordless, rpID, origin); err != nil {
+		logrus.Debugf("[webauthn] login of user %s failed: %v", userID, err)
+		return ErrInvalidAssertion
+	}
+	if err := m.save(secret, st); err != nil {
+		if apierrors.IsConflict(err) {
+			// The challenge was used by a concurrent login.
+			return ErrInvalidAssertion
+		}
+		return err
+	}
+	return nil
+}
+
+// verifyAssertion checks the assertion, and updates the state with the use of the challenge and the credential.
+func (m *Manager) verifyAssertion(st *state, assertion v32.WebAuthnAssertion, passwordless bool, rpID, origin string) error {
+	var credential *storedCredential
+	for i := range st.Credentials {
+		if st.Credentials[i].ID == assertion.CredentialID {
+			credential = &st.Credentials[i]
+		}
+	}
+	if credential == nil {
+		return errNotFound
+	}
+	if passwordless && !credential.Passwordless {
+		return errors.New("the credential isn't a passkey")
+	}
+
+	clientDataJSON, err := encoding.DecodeString(assertion.ClientDataJSON)
+	if err != nil {
+		return errors.New("invalid client data encoding")
+	}
+	rawAuthData, err := encoding.DecodeString(assertion.AuthenticatorData)
+	if err != nil {
+		return errors.New("invalid authenticator data encoding")
+	}
+	signature, err := encoding.DecodeString(assertion.Signature)
+	if err != nil {
+		return errors.New("invalid signature encoding")
+	}
+
+	challenge := challengeOf(clientDataJSON)
+	pending := -1
+	for i, login := range st.Logins {
+		if login.Challenge == challenge {
+			pending = i
+		}
+	}
+	if pending < 0 {
+		return errors.New("no pending login with the challenge")
+	}
+	if err := parseClientData(clientDataJSON, clientDataTypeGet, st.Logins[pending].Challenge, origin); err != nil {
+		return err
+	}
+	authData, err := parseAuthenticatorData(rawAuthData, rpID)
+	if err != nil {
+		return err
+	}
+	if passwordless && !authData.userVerified() {
+		return errors.New("passkeys must verify the user")
+	}
+
+	key, err := parsePublicKey(credential.PublicKey)
+	if err != nil {
+		return err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := key.verify(append(rawAuthData, clientDataHash[:]...), signature); err != nil {
+		return err
+	}
+	// Authenticators with a counter increase it with each use, a counter going back is the sign of a cloned key.
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return fmt.Errorf("signature counter %d isn't greater than %d, the authenticator may be cloned", authData.signCount, credential.SignCount)
+	}
+
+	now := metav1.NewTime(m.now())
+	credential.SignCount = authData.signCount
+	credential.LastUsedAt = &now
+	st.Logins = append(st.Logins[:pending], st.Logins[pending+1:]...)
+	return nil
+}
+
+// HasCredentials tells whether the user registered a credential.
+func (m *Manager) HasCredentials(userID string) (bool, error) {
+	_, st, err := m.load(userID)
+	if err != nil {
+		return false, err
+	}
+	return len(st.Credentials) > 0, nil
+}
+
+// Credentials returns the credentials of the user.
+func (m *Manager) Credentials(userID string) ([]Credential, error) {
+	_, st, err := m.load(userID)
+	if err != nil {
+		return nil, err
+	}
+	credentials := make([]Credential, 0, len(st.Credentials))
+	for _, credential := range st.Credentials {
+		credentials = append(credentials, credential.Credential)
+	}
+	return credentials, nil
+}
+
+// RemoveCredential removes a credential of the user.
+func (m *Manager) RemoveCredential(userID, id string) error {
+	secret, st, err := m.load(userID)
+	if err != nil {
+		return err
+	}
+	for i, credential := range st.Credentials {
+		if credential.ID == id {
+			st.Credentials = append(st.Credentials[:i], st.Credentials[i+1:]...)
+			return m.save(secret, st)
+		}
+	}
+	return errNotFound
+}
+
+// Reset removes all the credentials of the user.
+func (m *Manager) Reset(userID string) error {
+	err := m.secrets.Delete(tokens.SecretNamespace, SecretName(userID), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting the WebAuthn credentials of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// load returns the secret and the state of the user, a nil secret if the user hasn't registered any credential.
+func (m *Manager) load(userID string) (*corev1.Secret, *state, error) {
+	secret, err := m.secrets.Get(tokens.SecretNamespace, SecretName(userID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, &state{}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting the WebAuthn credentials of user %s: %w", userID, err)
+	}
+	st := &state{}
+	if data := secret.Data[stateKey]; len(data) > 0 {
+		if err := json.Unmarshal(data, st); err != nil {
+			return nil, nil, fmt.Errorf("parsing the WebAuthn credentials of user %s: %w", userID, err)
+		}
+	}
+	return secret, st, nil
+}
+
+// save stores the state in the secret, which is created if it's new. It fails on conflicts.
+func (m *Manager) save(secret *corev1.Secret, st *state) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[stateKey] = data
+	if secret.ResourceVersion == "" {
+		_, err = m.secrets.Create(secret)
+	} else {
+		_, err = m.secrets.Update(secret)
+	}
+	return err
+}
+
+// prune drops the expired challenges.
+func (m *Manager) prune(st *state) {
+	now := m.now()
+	if st.Registration != nil && !now.Before(st.Registration.ExpiresAt.Time) {
+		st.Registration = nil
+	}
+	logins := st.Logins[:0]
+	for _, login := range st.Logins {
+		if now.Before(login.ExpiresAt.Time) {
+			logins = append(logins, login)
+		}
+	}
+	st.Logins = logins
+}
+
+func newSecret(user *v3.User) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SecretName(user.Name),
+			Namespace: tokens.SecretNamespace,
+			Labels:    map[string]string{tokens.UserIDLabel: user.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v3.UserGroupVersionKind.GroupVersion().String(),
+				Kind:       v3.UserGroupVersionKind.Kind,
+				Name:       user.Name,
+				UID:        user.UID,
+			}},
+		},
+	}
+}
+
+func newChallenge() (string, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return "", fmt.Errorf("generating a challenge: %w", err)
+	}
+	return encoding.EncodeToString(challenge), nil
+}
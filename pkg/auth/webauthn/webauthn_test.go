@@ -0,0 +1,301 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	testRPID   = "rancher.example.com"
+	testOrigin = "https://rancher.example.com"
+)
+
+// authenticator is a software security key with a P-256 credential.
+type authenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	aaguid    []byte
+	signCount uint32
+	flags     byte
+}
+
+func newAuthenticator(t *testing.T) *authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+	return &authenticator{
+		key:    key,
+		id:     id,
+		aaguid: []byte{0xcb, 0x69, 0x48, 0x1e, 0x8f, 0xf7, 0x40, 0x39, 0x93, 0xec, 0x0a, 0x27, 0x29, 0xa1, 0x54, 0xa8},
+		flags:  flagUserPresent | flagUserVerified,
+	}
+}
+
+func (a *authenticator) authData(t *testing.T, rpID string, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	flags := a.flags
+	if attested {
+		flags |= flagAttestedCredData
+	}
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if !attested {
+		return data
+	}
+	coseKey, err := cbor.Marshal(map[int64]interface{}{
+		coseKeyType:   keyTypeEC2,
+		coseAlgorithm: algES256,
+		coseParam1:    curveP256,
+		coseParam2:    a.key.X.FillBytes(make([]byte, 32)),
+		coseParam3:    a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+	data = append(data, a.aaguid...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.id)))
+	data = append(data, a.id...)
+	return append(data, coseKey...)
+}
+
+func (a *authenticator) sign(t *testing.T, authData, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+	return signature
+}
+
+func clientDataJSON(t *testing.T, ceremony, challenge, origin string) []byte {
+	data, err := json.Marshal(clientData{Type: ceremony, Challenge: challenge, Origin: origin})
+	require.NoError(t, err)
+	return data
+}
+
+// register answers the registration options with a self attestation.
+func (a *authenticator) register(t *testing.T, options *CreationOptions, format string) RegistrationResponse {
+	clientData := clientDataJSON(t, clientDataTypeCreate, options.Challenge, testOrigin)
+	authData := a.authData(t, options.RP.ID, true)
+	statement := map[string]interface{}{}
+	if format == attestationFormatPacked {
+		statement = map[string]interface{}{"alg": algES256, "sig": a.sign(t, authData, clientData)}
+	}
+	rawStatement, err := cbor.Marshal(statement)
+	require.NoError(t, err)
+	attestation, err := cbor.Marshal(attestationObject{Format: format, Statement: rawStatement, AuthData: authData})
+	require.NoError(t, err)
+	return RegistrationResponse{
+		Name:              "YubiKey",
+		ID:                encoding.EncodeToString(a.id),
+		ClientDataJSON:    encoding.EncodeToString(clientData),
+		AttestationObject: encoding.EncodeToString(attestation),
+	}
+}
+
+// assert answers the login options.
+func (a *authenticator) assert(t *testing.T, options *RequestOptions) v32.WebAuthnAssertion {
+	a.signCount++
+	clientData := clientDataJSON(t, clientDataTypeGet, options.Challenge, testOrigin)
+	authData := a.authData(t, options.RPID, false)
+	return v32.WebAuthnAssertion{
+		CredentialID:      encoding.EncodeToString(a.id),
+		ClientDataJSON:    encoding.EncodeToString(clientData),
+		AuthenticatorData: encoding.EncodeToString(authData),
+		Signature:         encoding.EncodeToString(a.sign(t, authData, clientData)),
+	}
+}
+
+func newTestManager(t *testing.T, now *time.Time) (*Manager, map[string]*corev1.Secret) {
+	ctrl := gomock.NewController(t)
+	stored := map[string]*corev1.Secret{}
+	gr := schema.GroupResource{Resource: "secrets"}
+	version := 0
+	secrets := fake.NewMockClientInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+	secrets.EXPECT().Get(tokens.SecretNamespace, gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+		if secret, ok := stored[name]; ok {
+			return secret.DeepCopy(), nil
+		}
+		return nil, apierrors.NewNotFound(gr, name)
+	}).AnyTimes()
+	secrets.EXPECT().Create(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		version++
+		created := secret.DeepCopy()
+		created.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = created
+		return created.DeepCopy(), nil
+	}).AnyTimes()
+	secrets.EXPECT().Update(gomock.Any()).DoAndReturn(func(secret *corev1.Secret) (*corev1.Secret, error) {
+		if stored[secret.Name].ResourceVersion != secret.ResourceVersion {
+			return nil, apierrors.NewConflict(gr, secret.Name, nil)
+		}
+		version++
+		updated := secret.DeepCopy()
+		updated.ResourceVersion = strconv.Itoa(version)
+		stored[secret.Name] = updated
+		return updated.DeepCopy(), nil
+	}).AnyTimes()
+
+	m := NewManager(secrets)
+	m.now = func() time.Time { return *now }
+	return m, stored
+}
+
+func setServerURL(t *testing.T) {
+	require.NoError(t, settings.ServerURL.Set(testOrigin))
+	t.Cleanup(func() { settings.ServerURL.Set(settings.ServerURL.Default) })
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	setServerURL(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m, stored := newTestManager(t, &now)
+	user := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde", UID: "1234"}, Username: "alice"}
+	key := newAuthenticator(t)
+
+	options, err := m.BeginRegistration(user, false)
+	require.NoError(t, err)
+	assert.Equal(t, testRPID, options.RP.ID)
+	assert.Equal(t, encoding.EncodeToString([]byte("u-abcde")), options.User.ID)
+	assert.Equal(t, "none", options.Attestation)
+
+	credential, err := m.FinishRegistration(user.Name, key.register(t, options, attestationFormatPacked))
+	require.NoError(t, err)
+	assert.Equal(t, "YubiKey", credential.Name)
+	assert.Equal(t, "cb69481e-8ff7-4039-93ec-0a2729a154a8", credential.AAGUID)
+	assert.False(t, credential.Passwordless)
+	secret := stored[SecretName(user.Name)]
+	require.NotNil(t, secret)
+	assert.Equal(t, user.UID, secret.OwnerReferences[0].UID)
+
+	// The registration challenge can only be used once.
+	_, err = m.FinishRegistration(user.Name, key.register(t, options, attestationFormatNone))
+	assert.ErrorIs(t, err, ErrInvalidRegistration)
+
+	login, err := m.BeginLogin(user.Name)
+	require.NoError(t, err)
+	require.Len(t, login.AllowCredentials, 1)
+	assert.Equal(t, credential.ID, login.AllowCredentials[0].ID)
+	assertion := key.assert(t, login)
+	require.NoError(t, m.VerifyLogin(user.Name, assertion, false))
+
+	// Challenges can't be replayed.
+	assert.ErrorIs(t, m.VerifyLogin(user.Name, assertion, false), ErrInvalidAssertion)
+
+	// Security keys which aren't passkeys can't replace the password.
+	login, err = m.BeginLogin(user.Name)
+	require.NoError(t, err)
+	assert.ErrorIs(t, m.VerifyLogin(user.Name, key.assert(t, login), true), ErrInvalidAssertion)
+
+	// A signature counter going back is the sign of a cloned key.
+	login, err = m.BeginLogin(user.Name)
+	require.NoError(t, err)
+	key.signCount = 0
+	assert.ErrorIs(t, m.VerifyLogin(user.Name, key.assert(t, login), false), ErrInvalidAssertion)
+
+	// Expired challenges aren't accepted.
+	key.signCount = 10
+	login, err = m.BeginLogin(user.Name)
+	require.NoError(t, err)
+	now = now.Add(challengeTTL)
+	assert.ErrorIs(t, m.VerifyLogin(user.Name, key.assert(t, login), false), ErrInvalidAssertion)
+
+	credentials, err := m.Credentials(user.Name)
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	assert.NotNil(t, credentials[0].LastUsedAt)
+}
+
+func TestPasskey(t *testing.T) {
+	setServerURL(t)
+	now := time.Now()
+	m, _ := newTestManager(t, &now)
+	user := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}, Username: "alice"}
+	key := newAuthenticator(t)
+
+	// Passkeys must verify the user.
+	options, err := m.BeginRegistration(user, true)
+	require.NoError(t, err)
+	assert.Equal(t, "required", options.AuthenticatorSelection.UserVerification)
+	key.flags = flagUserPresent
+	_, err = m.FinishRegistration(user.Name, key.register(t, options, attestationFormatNone))
+	assert.ErrorIs(t, err, ErrInvalidRegistration)
+
+	key.flags = flagUserPresent | flagUserVerified
+	credential, err := m.FinishRegistration(user.Name, key.register(t, options, attestationFormatNone))
+	require.NoError(t, err)
+	assert.True(t, credential.Passwordless)
+
+	login, err := m.BeginLogin(user.Name)
+	require.NoError(t, err)
+	key.flags = flagUserPresent
+	assert.ErrorIs(t, m.VerifyLogin(user.Name, key.assert(t, login), true), ErrInvalidAssertion)
+	login, err = m.BeginLogin(user.Name)
+	require.NoError(t, err)
+	key.flags = flagUserPresent | flagUserVerified
+	assert.NoError(t, m.VerifyLogin(user.Name, key.assert(t, login), true))
+}
+
+func TestRegistrationPolicies(t *testing.T) {
+	setServerURL(t)
+	defer settings.AuthWebAuthnAttestationPolicy.Set(settings.AuthWebAuthnAttestationPolicy.Default)
+	defer settings.AuthWebAuthnAllowedAAGUIDs.Set(settings.AuthWebAuthnAllowedAAGUIDs.Default)
+	now := time.Now()
+	m, _ := newTestManager(t, &now)
+	user := &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-abcde"}, Username: "alice"}
+	key := newAuthenticator(t)
+
+	// The direct policy requires an attestation certified by a trusted CA.
+	require.NoError(t, settings.AuthWebAuthnAttestationPolicy.Set(AttestationPolicyDirect))
+	for _, format := range []string{attestationFormatNone, attestationFormatPacked} {
+		options, err := m.BeginRegistration(user, false)
+		require.NoError(t, err)
+		assert.Equal(t, "direct", options.Attestation)
+		_, err = m.FinishRegistration(user.Name, key.register(t, options, format))
+		assert.ErrorIs(t, err, ErrInvalidRegistration, format)
+	}
+
+	require.NoError(t, settings.AuthWebAuthnAttestationPolicy.Set(AttestationPolicyNone))
+	require.NoError(t, settings.AuthWebAuthnAllowedAAGUIDs.Set("ee882879-721c-4913-9775-3dfcce97072a"))
+	options, err := m.BeginRegistration(user, false)
+	require.NoError(t, err)
+	_, err = m.FinishRegistration(user.Name, key.register(t, options, attestationFormatNone))
+	assert.ErrorIs(t, err, ErrInvalidRegistration)
+
+	require.NoError(t, settings.AuthWebAuthnAllowedAAGUIDs.Set("ee882879-721c-4913-9775-3dfcce97072a, CB69481E-8FF7-4039-93EC-0A2729A154A8"))
+	_, err = m.FinishRegistration(user.Name, key.register(t, options, attestationFormatNone))
+	assert.NoError(t, err)
+}
+
+func TestBeginLoginUnknownUser(t *testing.T) {
+	setServerURL(t)
+	now := time.Now()
+	m, stored := newTestManager(t, &now)
+
+	options, err := m.BeginLogin("u-unknown")
+	require.NoError(t, err)
+	assert.NotEmpty(t, options.Challenge)
+	assert.Empty(t, options.AllowCredentials)
+	assert.Empty(t, stored)
+}
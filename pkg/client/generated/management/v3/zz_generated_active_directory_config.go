@@ -5,6 +5,7 @@
 	ActiveDirectoryConfigFieldAccessMode                   = "accessMode"
 	ActiveDirectoryConfigFieldAllowedPrincipalIDs          = "allowedPrincipalIds"
 	ActiveDirectoryConfigFieldAnnotations                  = "annotations"
+	ActiveDirectoryConfigFieldBindMethod                   = "bindMethod"
 	ActiveDirectoryConfigFieldCertificate                  = "certificate"
 	ActiveDirectoryConfigFieldConnectionTimeout            = "connectionTimeout"
 	ActiveDirectoryConfigFieldCreated                      = "created"
@@ -26,6 +27,8 @@
 	ActiveDirectoryConfigFieldOwnerReferences              = "ownerReferences"
 	ActiveDirectoryConfigFieldPort                         = "port"
 	ActiveDirectoryConfigFieldRemoved                      = "removed"
+	ActiveDirectoryConfigFieldServerDiscoveryDomain        = "serverDiscoveryDomain"
+	ActiveDirectoryConfigFieldServerDiscoverySite          = "serverDiscoverySite"
 	ActiveDirectoryConfigFieldServers                      = "servers"
 	ActiveDirectoryConfigFieldServiceAccountPassword       = "serviceAccountPassword"
 	ActiveDirectoryConfigFieldServiceAccountUsername       = "serviceAccountUsername"
@@ -49,6 +52,7 @@ type ActiveDirectoryConfig struct {
 	AccessMode                   string            `json:"accessMode,omitempty" yaml:"accessMode,omitempty"`
 	AllowedPrincipalIDs          []string          `json:"allowedPrincipalIds,omitempty" yaml:"allowedPrincipalIds,omitempty"`
 	Annotations                  map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	BindMethod                   string            `json:"bindMethod,omitempty" yaml:"bindMethod,omitempty"`
 	Certificate                  string            `json:"certificate,omitempty" yaml:"certificate,omitempty"`
 	ConnectionTimeout            int64             `json:"connectionTimeout,omitempty" yaml:"connectionTimeout,omitempty"`
 	Created                      string            `json:"created,omitempty" yaml:"created,omitempty"`
@@ -70,6 +74,8 @@ type ActiveDirectoryConfig struct {
 	OwnerReferences              []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
 	Port                         int64             `json:"port,omitempty" yaml:"port,omitempty"`
 	Removed                      string            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	ServerDiscoveryDomain        string            `json:"serverDiscoveryDomain,omitempty" yaml:"serverDiscoveryDomain,omitempty"`
+	ServerDiscoverySite          string            `json:"serverDiscoverySite,omitempty" yaml:"serverDiscoverySite,omitempty"`
 	Servers                      []string          `json:"servers,omitempty" yaml:"servers,omitempty"`
 	ServiceAccountPassword       string            `json:"serviceAccountPassword,omitempty" yaml:"serviceAccountPassword,omitempty"`
 	ServiceAccountUsername       string            `json:"serviceAccountUsername,omitempty" yaml:"serviceAccountUsername,omitempty"`
@@ -0,0 +1,22 @@
+package client
+
+const (
+	AuthConfigConnectivityType                     = "authConfigConnectivity"
+	AuthConfigConnectivityFieldCredentials         = "credentials"
+	AuthConfigConnectivityFieldEndpoints           = "endpoints"
+	AuthConfigConnectivityFieldError               = "error"
+	AuthConfigConnectivityFieldLastProbeTime       = "lastProbeTime"
+	AuthConfigConnectivityFieldLastSyncTime        = "lastSyncTime"
+	AuthConfigConnectivityFieldMetadataRefreshTime = "metadataRefreshTime"
+	AuthConfigConnectivityFieldMetadataValidUntil  = "metadataValidUntil"
+)
+
+type AuthConfigConnectivity struct {
+	Credentials         []AuthConfigCredentialStatus `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	Endpoints           []AuthConfigEndpointStatus   `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Error               string                       `json:"error,omitempty" yaml:"error,omitempty"`
+	LastProbeTime       string                       `json:"lastProbeTime,omitempty" yaml:"lastProbeTime,omitempty"`
+	LastSyncTime        string                       `json:"lastSyncTime,omitempty" yaml:"lastSyncTime,omitempty"`
+	MetadataRefreshTime string                       `json:"metadataRefreshTime,omitempty" yaml:"metadataRefreshTime,omitempty"`
+	MetadataValidUntil  string                       `json:"metadataValidUntil,omitempty" yaml:"metadataValidUntil,omitempty"`
+}
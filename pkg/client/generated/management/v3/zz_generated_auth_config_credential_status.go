@@ -0,0 +1,14 @@
+package client
+
+const (
+	AuthConfigCredentialStatusType           = "authConfigCredentialStatus"
+	AuthConfigCredentialStatusFieldExpiresAt = "expiresAt"
+	AuthConfigCredentialStatusFieldName      = "name"
+	AuthConfigCredentialStatusFieldSubject   = "subject"
+)
+
+type AuthConfigCredentialStatus struct {
+	ExpiresAt string `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	Subject   string `json:"subject,omitempty" yaml:"subject,omitempty"`
+}
@@ -0,0 +1,16 @@
+package client
+
+const (
+	AuthConfigEndpointStatusType           = "authConfigEndpointStatus"
+	AuthConfigEndpointStatusFieldError     = "error"
+	AuthConfigEndpointStatusFieldName      = "name"
+	AuthConfigEndpointStatusFieldReachable = "reachable"
+	AuthConfigEndpointStatusFieldURL       = "url"
+)
+
+type AuthConfigEndpointStatus struct {
+	Error     string `json:"error,omitempty" yaml:"error,omitempty"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	Reachable bool   `json:"reachable,omitempty" yaml:"reachable,omitempty"`
+	URL       string `json:"url,omitempty" yaml:"url,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	AuthConfigFieldChangeType       = "authConfigFieldChange"
+	AuthConfigFieldChangeFieldField = "field"
+	AuthConfigFieldChangeFieldNew   = "new"
+	AuthConfigFieldChangeFieldOld   = "old"
+)
+
+type AuthConfigFieldChange struct {
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+	New   string `json:"new,omitempty" yaml:"new,omitempty"`
+	Old   string `json:"old,omitempty" yaml:"old,omitempty"`
+}
@@ -0,0 +1,18 @@
+package client
+
+const (
+	AuthConfigRevisionType              = "authConfigRevision"
+	AuthConfigRevisionFieldAuthor       = "author"
+	AuthConfigRevisionFieldDiff         = "diff"
+	AuthConfigRevisionFieldRevision     = "revision"
+	AuthConfigRevisionFieldRolledBackTo = "rolledBackTo"
+	AuthConfigRevisionFieldTimestamp    = "timestamp"
+)
+
+type AuthConfigRevision struct {
+	Author       string                  `json:"author,omitempty" yaml:"author,omitempty"`
+	Diff         []AuthConfigFieldChange `json:"diff,omitempty" yaml:"diff,omitempty"`
+	Revision     int64                   `json:"revision,omitempty" yaml:"revision,omitempty"`
+	RolledBackTo int64                   `json:"rolledBackTo,omitempty" yaml:"rolledBackTo,omitempty"`
+	Timestamp    string                  `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+}
@@ -0,0 +1,10 @@
+package client
+
+const (
+	AuthConfigRevisionsOutputType           = "authConfigRevisionsOutput"
+	AuthConfigRevisionsOutputFieldRevisions = "revisions"
+)
+
+type AuthConfigRevisionsOutput struct {
+	Revisions []AuthConfigRevision `json:"revisions,omitempty" yaml:"revisions,omitempty"`
+}
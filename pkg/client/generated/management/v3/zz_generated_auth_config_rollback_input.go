@@ -0,0 +1,10 @@
+package client
+
+const (
+	AuthConfigRollbackInputType          = "authConfigRollbackInput"
+	AuthConfigRollbackInputFieldRevision = "revision"
+)
+
+type AuthConfigRollbackInput struct {
+	Revision int64 `json:"revision,omitempty" yaml:"revision,omitempty"`
+}
@@ -0,0 +1,22 @@
+package client
+
+const (
+	AuthConfigSLOType                    = "authConfigSLO"
+	AuthConfigSLOFieldBelowTargetSince   = "belowTargetSince"
+	AuthConfigSLOFieldBreachedSince      = "breachedSince"
+	AuthConfigSLOFieldCompliance         = "compliance"
+	AuthConfigSLOFieldErrors             = "errors"
+	AuthConfigSLOFieldLastEvaluationTime = "lastEvaluationTime"
+	AuthConfigSLOFieldRequests           = "requests"
+	AuthConfigSLOFieldSlow               = "slow"
+)
+
+type AuthConfigSLO struct {
+	BelowTargetSince   string `json:"belowTargetSince,omitempty" yaml:"belowTargetSince,omitempty"`
+	BreachedSince      string `json:"breachedSince,omitempty" yaml:"breachedSince,omitempty"`
+	Compliance         string `json:"compliance,omitempty" yaml:"compliance,omitempty"`
+	Errors             int64  `json:"errors,omitempty" yaml:"errors,omitempty"`
+	LastEvaluationTime string `json:"lastEvaluationTime,omitempty" yaml:"lastEvaluationTime,omitempty"`
+	Requests           int64  `json:"requests,omitempty" yaml:"requests,omitempty"`
+	Slow               int64  `json:"slow,omitempty" yaml:"slow,omitempty"`
+}
@@ -1,10 +1,14 @@
 package client
 
 const (
-	AuthConfigStatusType            = "authConfigStatus"
-	AuthConfigStatusFieldConditions = "conditions"
+	AuthConfigStatusType              = "authConfigStatus"
+	AuthConfigStatusFieldConditions   = "conditions"
+	AuthConfigStatusFieldConnectivity = "connectivity"
+	AuthConfigStatusFieldSLO          = "slo"
 )
 
 type AuthConfigStatus struct {
-	Conditions []AuthConfigConditions `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Conditions   []AuthConfigConditions  `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Connectivity *AuthConfigConnectivity `json:"connectivity,omitempty" yaml:"connectivity,omitempty"`
+	SLO          *AuthConfigSLO          `json:"slo,omitempty" yaml:"slo,omitempty"`
 }
@@ -0,0 +1,46 @@
+package client
+
+const (
+	CASConfigType                      = "casConfig"
+	CASConfigFieldAccessMode           = "accessMode"
+	CASConfigFieldAllowedPrincipalIDs  = "allowedPrincipalIds"
+	CASConfigFieldAnnotations          = "annotations"
+	CASConfigFieldCertificate          = "certificate"
+	CASConfigFieldCreated              = "created"
+	CASConfigFieldCreatorID            = "creatorId"
+	CASConfigFieldDisplayNameAttribute = "displayNameAttribute"
+	CASConfigFieldEnabled              = "enabled"
+	CASConfigFieldGroupsAttribute      = "groupsAttribute"
+	CASConfigFieldLabels               = "labels"
+	CASConfigFieldLogoutAllSupported   = "logoutAllSupported"
+	CASConfigFieldName                 = "name"
+	CASConfigFieldOwnerReferences      = "ownerReferences"
+	CASConfigFieldRemoved              = "removed"
+	CASConfigFieldServerURL            = "serverUrl"
+	CASConfigFieldStatus               = "status"
+	CASConfigFieldType                 = "type"
+	CASConfigFieldUUID                 = "uuid"
+	CASConfigFieldUserNameAttribute    = "userNameAttribute"
+)
+
+type CASConfig struct {
+	AccessMode           string            `json:"accessMode,omitempty" yaml:"accessMode,omitempty"`
+	AllowedPrincipalIDs  []string          `json:"allowedPrincipalIds,omitempty" yaml:"allowedPrincipalIds,omitempty"`
+	Annotations          map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Certificate          string            `json:"certificate,omitempty" yaml:"certificate,omitempty"`
+	Created              string            `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID            string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	DisplayNameAttribute string            `json:"displayNameAttribute,omitempty" yaml:"displayNameAttribute,omitempty"`
+	Enabled              bool              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	GroupsAttribute      string            `json:"groupsAttribute,omitempty" yaml:"groupsAttribute,omitempty"`
+	Labels               map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	LogoutAllSupported   bool              `json:"logoutAllSupported,omitempty" yaml:"logoutAllSupported,omitempty"`
+	Name                 string            `json:"name,omitempty" yaml:"name,omitempty"`
+	OwnerReferences      []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	Removed              string            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	ServerURL            string            `json:"serverUrl,omitempty" yaml:"serverUrl,omitempty"`
+	Status               *AuthConfigStatus `json:"status,omitempty" yaml:"status,omitempty"`
+	Type                 string            `json:"type,omitempty" yaml:"type,omitempty"`
+	UUID                 string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	UserNameAttribute    string            `json:"userNameAttribute,omitempty" yaml:"userNameAttribute,omitempty"`
+}
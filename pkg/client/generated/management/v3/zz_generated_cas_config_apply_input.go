@@ -0,0 +1,16 @@
+package client
+
+const (
+	CASConfigApplyInputType           = "casConfigApplyInput"
+	CASConfigApplyInputFieldCASConfig = "casConfig"
+	CASConfigApplyInputFieldEnabled   = "enabled"
+	CASConfigApplyInputFieldService   = "service"
+	CASConfigApplyInputFieldTicket    = "ticket"
+)
+
+type CASConfigApplyInput struct {
+	CASConfig *CASConfig `json:"casConfig,omitempty" yaml:"casConfig,omitempty"`
+	Enabled   bool       `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Service   string     `json:"service,omitempty" yaml:"service,omitempty"`
+	Ticket    string     `json:"ticket,omitempty" yaml:"ticket,omitempty"`
+}
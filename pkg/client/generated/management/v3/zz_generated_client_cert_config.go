@@ -0,0 +1,50 @@
+package client
+
+const (
+	ClientCertConfigType                         = "clientCertConfig"
+	ClientCertConfigFieldAccessMode              = "accessMode"
+	ClientCertConfigFieldAllowedPrincipalIDs     = "allowedPrincipalIds"
+	ClientCertConfigFieldAnnotations             = "annotations"
+	ClientCertConfigFieldCACertificates          = "caCertificates"
+	ClientCertConfigFieldCRLEnabled              = "crlEnabled"
+	ClientCertConfigFieldClientCertificateHeader = "clientCertificateHeader"
+	ClientCertConfigFieldCreated                 = "created"
+	ClientCertConfigFieldCreatorID               = "creatorId"
+	ClientCertConfigFieldEnabled                 = "enabled"
+	ClientCertConfigFieldGroupField              = "groupField"
+	ClientCertConfigFieldLabels                  = "labels"
+	ClientCertConfigFieldLogoutAllSupported      = "logoutAllSupported"
+	ClientCertConfigFieldName                    = "name"
+	ClientCertConfigFieldOCSPEnabled             = "ocspEnabled"
+	ClientCertConfigFieldOwnerReferences         = "ownerReferences"
+	ClientCertConfigFieldRemoved                 = "removed"
+	ClientCertConfigFieldRevocationSoftFail      = "revocationSoftFail"
+	ClientCertConfigFieldStatus                  = "status"
+	ClientCertConfigFieldType                    = "type"
+	ClientCertConfigFieldUUID                    = "uuid"
+	ClientCertConfigFieldUsernameField           = "usernameField"
+)
+
+type ClientCertConfig struct {
+	AccessMode              string            `json:"accessMode,omitempty" yaml:"accessMode,omitempty"`
+	AllowedPrincipalIDs     []string          `json:"allowedPrincipalIds,omitempty" yaml:"allowedPrincipalIds,omitempty"`
+	Annotations             map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	CACertificates          string            `json:"caCertificates,omitempty" yaml:"caCertificates,omitempty"`
+	CRLEnabled              bool              `json:"crlEnabled,omitempty" yaml:"crlEnabled,omitempty"`
+	ClientCertificateHeader string            `json:"clientCertificateHeader,omitempty" yaml:"clientCertificateHeader,omitempty"`
+	Created                 string            `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID               string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	Enabled                 bool              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	GroupField              string            `json:"groupField,omitempty" yaml:"groupField,omitempty"`
+	Labels                  map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	LogoutAllSupported      bool              `json:"logoutAllSupported,omitempty" yaml:"logoutAllSupported,omitempty"`
+	Name                    string            `json:"name,omitempty" yaml:"name,omitempty"`
+	OCSPEnabled             bool              `json:"ocspEnabled,omitempty" yaml:"ocspEnabled,omitempty"`
+	OwnerReferences         []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	Removed                 string            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	RevocationSoftFail      bool              `json:"revocationSoftFail,omitempty" yaml:"revocationSoftFail,omitempty"`
+	Status                  *AuthConfigStatus `json:"status,omitempty" yaml:"status,omitempty"`
+	Type                    string            `json:"type,omitempty" yaml:"type,omitempty"`
+	UUID                    string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	UsernameField           string            `json:"usernameField,omitempty" yaml:"usernameField,omitempty"`
+}
@@ -0,0 +1,12 @@
+package client
+
+const (
+	ClientCertTestAndApplyInputType                  = "clientCertTestAndApplyInput"
+	ClientCertTestAndApplyInputFieldClientCertConfig = "clientCertConfig"
+	ClientCertTestAndApplyInputFieldEnabled          = "enabled"
+)
+
+type ClientCertTestAndApplyInput struct {
+	ClientCertConfig *ClientCertConfig `json:"clientCertConfig,omitempty" yaml:"clientCertConfig,omitempty"`
+	Enabled          bool              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+}
@@ -2,6 +2,7 @@
 
 const (
 	ClusterRoleTemplateBindingStatusType                          = "clusterRoleTemplateBindingStatus"
+	ClusterRoleTemplateBindingStatusFieldConditions               = "conditions"
 	ClusterRoleTemplateBindingStatusFieldLastUpdateTime           = "lastUpdateTime"
 	ClusterRoleTemplateBindingStatusFieldLocalConditions          = "localConditions"
 	ClusterRoleTemplateBindingStatusFieldObservedGenerationLocal  = "observedGenerationLocal"
@@ -13,6 +14,7 @@
 )
 
 type ClusterRoleTemplateBindingStatus struct {
+	Conditions               []Condition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
 	LastUpdateTime           string      `json:"lastUpdateTime,omitempty" yaml:"lastUpdateTime,omitempty"`
 	LocalConditions          []Condition `json:"localConditions,omitempty" yaml:"localConditions,omitempty"`
 	ObservedGenerationLocal  int64       `json:"observedGenerationLocal,omitempty" yaml:"observedGenerationLocal,omitempty"`
@@ -0,0 +1,10 @@
+package client
+
+const (
+	DetectDuplicateUsersOutputType            = "detectDuplicateUsersOutput"
+	DetectDuplicateUsersOutputFieldDuplicates = "duplicates"
+)
+
+type DetectDuplicateUsersOutput struct {
+	Duplicates []DuplicateUsers `json:"duplicates,omitempty" yaml:"duplicates,omitempty"`
+}
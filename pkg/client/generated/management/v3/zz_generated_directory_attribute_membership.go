@@ -0,0 +1,16 @@
+package client
+
+const (
+	DirectoryAttributeMembershipType                  = "directoryAttributeMembership"
+	DirectoryAttributeMembershipFieldAttribute        = "attribute"
+	DirectoryAttributeMembershipFieldProvider         = "provider"
+	DirectoryAttributeMembershipFieldRoleTemplateName = "roleTemplateName"
+	DirectoryAttributeMembershipFieldValue            = "value"
+)
+
+type DirectoryAttributeMembership struct {
+	Attribute        string `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Provider         string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	RoleTemplateName string `json:"roleTemplateName,omitempty" yaml:"roleTemplateName,omitempty"`
+	Value            string `json:"value,omitempty" yaml:"value,omitempty"`
+}
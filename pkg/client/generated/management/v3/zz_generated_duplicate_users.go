@@ -0,0 +1,12 @@
+package client
+
+const (
+	DuplicateUsersType         = "duplicateUsers"
+	DuplicateUsersFieldLogin   = "login"
+	DuplicateUsersFieldUserIDs = "userIds"
+)
+
+type DuplicateUsers struct {
+	Login   string   `json:"login,omitempty" yaml:"login,omitempty"`
+	UserIDs []string `json:"userIds,omitempty" yaml:"userIds,omitempty"`
+}
@@ -0,0 +1,10 @@
+package client
+
+const (
+	EraseUserDataInputType        = "eraseUserDataInput"
+	EraseUserDataInputFieldDryRun = "dryRun"
+)
+
+type EraseUserDataInput struct {
+	DryRun bool `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+}
@@ -0,0 +1,22 @@
+package client
+
+const (
+	EraseUserDataOutputType                             = "eraseUserDataOutput"
+	EraseUserDataOutputFieldClusterRoleTemplateBindings = "clusterRoleTemplateBindings"
+	EraseUserDataOutputFieldDryRun                      = "dryRun"
+	EraseUserDataOutputFieldGlobalRoleBindings          = "globalRoleBindings"
+	EraseUserDataOutputFieldPrincipalIDs                = "principalIds"
+	EraseUserDataOutputFieldProjectRoleTemplateBindings = "projectRoleTemplateBindings"
+	EraseUserDataOutputFieldSecrets                     = "secrets"
+	EraseUserDataOutputFieldTokens                      = "tokens"
+)
+
+type EraseUserDataOutput struct {
+	ClusterRoleTemplateBindings []string `json:"clusterRoleTemplateBindings,omitempty" yaml:"clusterRoleTemplateBindings,omitempty"`
+	DryRun                      bool     `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+	GlobalRoleBindings          []string `json:"globalRoleBindings,omitempty" yaml:"globalRoleBindings,omitempty"`
+	PrincipalIDs                []string `json:"principalIds,omitempty" yaml:"principalIds,omitempty"`
+	ProjectRoleTemplateBindings []string `json:"projectRoleTemplateBindings,omitempty" yaml:"projectRoleTemplateBindings,omitempty"`
+	Secrets                     []string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	Tokens                      []string `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+}
@@ -0,0 +1,48 @@
+package client
+
+const (
+	ExternalAuthConfigType                     = "externalAuthConfig"
+	ExternalAuthConfigFieldAccessMode          = "accessMode"
+	ExternalAuthConfigFieldAllowedPrincipalIDs = "allowedPrincipalIds"
+	ExternalAuthConfigFieldAnnotations         = "annotations"
+	ExternalAuthConfigFieldCertificate         = "certificate"
+	ExternalAuthConfigFieldClientCert          = "clientCert"
+	ExternalAuthConfigFieldClientKey           = "clientKey"
+	ExternalAuthConfigFieldCreated             = "created"
+	ExternalAuthConfigFieldCreatorID           = "creatorId"
+	ExternalAuthConfigFieldEnabled             = "enabled"
+	ExternalAuthConfigFieldEndpoint            = "endpoint"
+	ExternalAuthConfigFieldHMACSecret          = "hmacSecret"
+	ExternalAuthConfigFieldLabels              = "labels"
+	ExternalAuthConfigFieldLogoutAllSupported  = "logoutAllSupported"
+	ExternalAuthConfigFieldName                = "name"
+	ExternalAuthConfigFieldOwnerReferences     = "ownerReferences"
+	ExternalAuthConfigFieldRemoved             = "removed"
+	ExternalAuthConfigFieldStatus              = "status"
+	ExternalAuthConfigFieldTimeoutSeconds      = "timeoutSeconds"
+	ExternalAuthConfigFieldType                = "type"
+	ExternalAuthConfigFieldUUID                = "uuid"
+)
+
+type ExternalAuthConfig struct {
+	AccessMode          string            `json:"accessMode,omitempty" yaml:"accessMode,omitempty"`
+	AllowedPrincipalIDs []string          `json:"allowedPrincipalIds,omitempty" yaml:"allowedPrincipalIds,omitempty"`
+	Annotations         map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Certificate         string            `json:"certificate,omitempty" yaml:"certificate,omitempty"`
+	ClientCert          string            `json:"clientCert,omitempty" yaml:"clientCert,omitempty"`
+	ClientKey           string            `json:"clientKey,omitempty" yaml:"clientKey,omitempty"`
+	Created             string            `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID           string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	Enabled             bool              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Endpoint            string            `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	HMACSecret          string            `json:"hmacSecret,omitempty" yaml:"hmacSecret,omitempty"`
+	Labels              map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	LogoutAllSupported  bool              `json:"logoutAllSupported,omitempty" yaml:"logoutAllSupported,omitempty"`
+	Name                string            `json:"name,omitempty" yaml:"name,omitempty"`
+	OwnerReferences     []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	Removed             string            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Status              *AuthConfigStatus `json:"status,omitempty" yaml:"status,omitempty"`
+	TimeoutSeconds      int64             `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+	Type                string            `json:"type,omitempty" yaml:"type,omitempty"`
+	UUID                string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+}
@@ -0,0 +1,16 @@
+package client
+
+const (
+	ExternalAuthTestAndApplyInputType                    = "externalAuthTestAndApplyInput"
+	ExternalAuthTestAndApplyInputFieldEnabled            = "enabled"
+	ExternalAuthTestAndApplyInputFieldExternalAuthConfig = "externalAuthConfig"
+	ExternalAuthTestAndApplyInputFieldPassword           = "password"
+	ExternalAuthTestAndApplyInputFieldUsername           = "username"
+)
+
+type ExternalAuthTestAndApplyInput struct {
+	Enabled            bool                `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	ExternalAuthConfig *ExternalAuthConfig `json:"externalAuthConfig,omitempty" yaml:"externalAuthConfig,omitempty"`
+	Password           string              `json:"password,omitempty" yaml:"password,omitempty"`
+	Username           string              `json:"username,omitempty" yaml:"username,omitempty"`
+}
@@ -5,43 +5,47 @@
 )
 
 const (
-	GlobalRoleType                                    = "globalRole"
-	GlobalRoleFieldAnnotations                        = "annotations"
-	GlobalRoleFieldBuiltin                            = "builtin"
-	GlobalRoleFieldCreated                            = "created"
-	GlobalRoleFieldCreatorID                          = "creatorId"
-	GlobalRoleFieldDescription                        = "description"
-	GlobalRoleFieldInheritedClusterRoles              = "inheritedClusterRoles"
-	GlobalRoleFieldInheritedFleetWorkspacePermissions = "inheritedFleetWorkspacePermissions"
-	GlobalRoleFieldLabels                             = "labels"
-	GlobalRoleFieldName                               = "name"
-	GlobalRoleFieldNamespacedRules                    = "namespacedRules"
-	GlobalRoleFieldNewUserDefault                     = "newUserDefault"
-	GlobalRoleFieldOwnerReferences                    = "ownerReferences"
-	GlobalRoleFieldRemoved                            = "removed"
-	GlobalRoleFieldRules                              = "rules"
-	GlobalRoleFieldStatus                             = "status"
-	GlobalRoleFieldUUID                               = "uuid"
+	GlobalRoleType                                                      = "globalRole"
+	GlobalRoleFieldAnnotations                                          = "annotations"
+	GlobalRoleFieldBuiltin                                              = "builtin"
+	GlobalRoleFieldCreated                                              = "created"
+	GlobalRoleFieldCreatorID                                            = "creatorId"
+	GlobalRoleFieldDefaultPodSecurityAdmissionConfigurationTemplateName = "defaultPodSecurityAdmissionConfigurationTemplateName"
+	GlobalRoleFieldDescription                                          = "description"
+	GlobalRoleFieldInheritedClusterRoles                                = "inheritedClusterRoles"
+	GlobalRoleFieldInheritedClusterRolesNamespaceSelector               = "inheritedClusterRolesNamespaceSelector"
+	GlobalRoleFieldInheritedFleetWorkspacePermissions                   = "inheritedFleetWorkspacePermissions"
+	GlobalRoleFieldLabels                                               = "labels"
+	GlobalRoleFieldName                                                 = "name"
+	GlobalRoleFieldNamespacedRules                                      = "namespacedRules"
+	GlobalRoleFieldNewUserDefault                                       = "newUserDefault"
+	GlobalRoleFieldOwnerReferences                                      = "ownerReferences"
+	GlobalRoleFieldRemoved                                              = "removed"
+	GlobalRoleFieldRules                                                = "rules"
+	GlobalRoleFieldStatus                                               = "status"
+	GlobalRoleFieldUUID                                                 = "uuid"
 )
 
 type GlobalRole struct {
 	types.Resource
-	Annotations                        map[string]string         `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Builtin                            bool                      `json:"builtin,omitempty" yaml:"builtin,omitempty"`
-	Created                            string                    `json:"created,omitempty" yaml:"created,omitempty"`
-	CreatorID                          string                    `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
-	Description                        string                    `json:"description,omitempty" yaml:"description,omitempty"`
-	InheritedClusterRoles              []string                  `json:"inheritedClusterRoles,omitempty" yaml:"inheritedClusterRoles,omitempty"`
-	InheritedFleetWorkspacePermissions *FleetWorkspacePermission `json:"inheritedFleetWorkspacePermissions,omitempty" yaml:"inheritedFleetWorkspacePermissions,omitempty"`
-	Labels                             map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Name                               string                    `json:"name,omitempty" yaml:"name,omitempty"`
-	NamespacedRules                    map[string][]PolicyRule   `json:"namespacedRules,omitempty" yaml:"namespacedRules,omitempty"`
-	NewUserDefault                     bool                      `json:"newUserDefault,omitempty" yaml:"newUserDefault,omitempty"`
-	OwnerReferences                    []OwnerReference          `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
-	Removed                            string                    `json:"removed,omitempty" yaml:"removed,omitempty"`
-	Rules                              []PolicyRule              `json:"rules,omitempty" yaml:"rules,omitempty"`
-	Status                             GlobalRoleStatus          `json:"status,omitempty" yaml:"status,omitempty"`
-	UUID                               string                    `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Annotations                                          map[string]string         `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Builtin                                              bool                      `json:"builtin,omitempty" yaml:"builtin,omitempty"`
+	Created                                              string                    `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID                                            string                    `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	DefaultPodSecurityAdmissionConfigurationTemplateName string                    `json:"defaultPodSecurityAdmissionConfigurationTemplateName,omitempty" yaml:"defaultPodSecurityAdmissionConfigurationTemplateName,omitempty"`
+	Description                                          string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	InheritedClusterRoles                                []string                  `json:"inheritedClusterRoles,omitempty" yaml:"inheritedClusterRoles,omitempty"`
+	InheritedClusterRolesNamespaceSelector               *LabelSelector            `json:"inheritedClusterRolesNamespaceSelector,omitempty" yaml:"inheritedClusterRolesNamespaceSelector,omitempty"`
+	InheritedFleetWorkspacePermissions                   *FleetWorkspacePermission `json:"inheritedFleetWorkspacePermissions,omitempty" yaml:"inheritedFleetWorkspacePermissions,omitempty"`
+	Labels                                               map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Name                                                 string                    `json:"name,omitempty" yaml:"name,omitempty"`
+	NamespacedRules                                      map[string][]PolicyRule   `json:"namespacedRules,omitempty" yaml:"namespacedRules,omitempty"`
+	NewUserDefault                                       bool                      `json:"newUserDefault,omitempty" yaml:"newUserDefault,omitempty"`
+	OwnerReferences                                      []OwnerReference          `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	Removed                                              string                    `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Rules                                                []PolicyRule              `json:"rules,omitempty" yaml:"rules,omitempty"`
+	Status                                               GlobalRoleStatus          `json:"status,omitempty" yaml:"status,omitempty"`
+	UUID                                                 string                    `json:"uuid,omitempty" yaml:"uuid,omitempty"`
 }
 
 type GlobalRoleCollection struct {
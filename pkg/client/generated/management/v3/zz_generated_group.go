@@ -10,8 +10,12 @@
 	GroupFieldCreated         = "created"
 	GroupFieldCreatorID       = "creatorId"
 	GroupFieldLabels          = "labels"
+	GroupFieldLastSync        = "lastSync"
+	GroupFieldMemberCount     = "memberCount"
 	GroupFieldName            = "name"
 	GroupFieldOwnerReferences = "ownerReferences"
+	GroupFieldPrincipalID     = "principalId"
+	GroupFieldProvider        = "provider"
 	GroupFieldRemoved         = "removed"
 	GroupFieldUUID            = "uuid"
 )
@@ -22,8 +26,12 @@ type Group struct {
 	Created         string            `json:"created,omitempty" yaml:"created,omitempty"`
 	CreatorID       string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
 	Labels          map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	LastSync        string            `json:"lastSync,omitempty" yaml:"lastSync,omitempty"`
+	MemberCount     int64             `json:"memberCount,omitempty" yaml:"memberCount,omitempty"`
 	Name            string            `json:"name,omitempty" yaml:"name,omitempty"`
 	OwnerReferences []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	PrincipalID     string            `json:"principalId,omitempty" yaml:"principalId,omitempty"`
+	Provider        string            `json:"provider,omitempty" yaml:"provider,omitempty"`
 	Removed         string            `json:"removed,omitempty" yaml:"removed,omitempty"`
 	UUID            string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
 }
@@ -46,6 +54,8 @@ type GroupOperations interface {
 	Replace(existing *Group) (*Group, error)
 	ByID(id string) (*Group, error)
 	Delete(container *Group) error
+
+	CollectionActionMembers(resource *GroupCollection, input *GroupMembersInput) (*GroupMembersOutput, error)
 }
 
 func newGroupClient(apiClient *Client) *GroupClient {
@@ -116,3 +126,9 @@ func (c *GroupClient) ByID(id string) (*Group, error) {
 func (c *GroupClient) Delete(container *Group) error {
 	return c.apiClient.Ops.DoResourceDelete(GroupType, &container.Resource)
 }
+
+func (c *GroupClient) CollectionActionMembers(resource *GroupCollection, input *GroupMembersInput) (*GroupMembersOutput, error) {
+	resp := &GroupMembersOutput{}
+	err := c.apiClient.Ops.DoCollectionAction(GroupType, "members", &resource.Collection, input, resp)
+	return resp, err
+}
@@ -0,0 +1,20 @@
+package client
+
+const (
+	GroupMemberInfoType             = "groupMemberInfo"
+	GroupMemberInfoFieldActive      = "active"
+	GroupMemberInfoFieldDisplayName = "displayName"
+	GroupMemberInfoFieldLastLogin   = "lastLogin"
+	GroupMemberInfoFieldLoginName   = "loginName"
+	GroupMemberInfoFieldPrincipalID = "principalId"
+	GroupMemberInfoFieldUserID      = "userId"
+)
+
+type GroupMemberInfo struct {
+	Active      bool   `json:"active,omitempty" yaml:"active,omitempty"`
+	DisplayName string `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	LastLogin   string `json:"lastLogin,omitempty" yaml:"lastLogin,omitempty"`
+	LoginName   string `json:"loginName,omitempty" yaml:"loginName,omitempty"`
+	PrincipalID string `json:"principalId,omitempty" yaml:"principalId,omitempty"`
+	UserID      string `json:"userId,omitempty" yaml:"userId,omitempty"`
+}
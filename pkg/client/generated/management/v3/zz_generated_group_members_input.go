@@ -0,0 +1,14 @@
+package client
+
+const (
+	GroupMembersInputType             = "groupMembersInput"
+	GroupMembersInputFieldContinue    = "continue"
+	GroupMembersInputFieldLimit       = "limit"
+	GroupMembersInputFieldPrincipalID = "principalId"
+)
+
+type GroupMembersInput struct {
+	Continue    string `json:"continue,omitempty" yaml:"continue,omitempty"`
+	Limit       int64  `json:"limit,omitempty" yaml:"limit,omitempty"`
+	PrincipalID string `json:"principalId,omitempty" yaml:"principalId,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	GroupMembersOutputType             = "groupMembersOutput"
+	GroupMembersOutputFieldContinue    = "continue"
+	GroupMembersOutputFieldMembers     = "members"
+	GroupMembersOutputFieldPrincipalID = "principalId"
+)
+
+type GroupMembersOutput struct {
+	Continue    string            `json:"continue,omitempty" yaml:"continue,omitempty"`
+	Members     []GroupMemberInfo `json:"members,omitempty" yaml:"members,omitempty"`
+	PrincipalID string            `json:"principalId,omitempty" yaml:"principalId,omitempty"`
+}
@@ -0,0 +1,46 @@
+package client
+
+const (
+	KerberosConfigType                      = "kerberosConfig"
+	KerberosConfigFieldAccessMode           = "accessMode"
+	KerberosConfigFieldAllowedPrincipalIDs  = "allowedPrincipalIds"
+	KerberosConfigFieldAnnotations          = "annotations"
+	KerberosConfigFieldCreated              = "created"
+	KerberosConfigFieldCreatorID            = "creatorId"
+	KerberosConfigFieldDirectoryProvider    = "directoryProvider"
+	KerberosConfigFieldEnabled              = "enabled"
+	KerberosConfigFieldKeytab               = "keytab"
+	KerberosConfigFieldLabels               = "labels"
+	KerberosConfigFieldLogoutAllSupported   = "logoutAllSupported"
+	KerberosConfigFieldMaxClockSkewSeconds  = "maxClockSkewSeconds"
+	KerberosConfigFieldName                 = "name"
+	KerberosConfigFieldOwnerReferences      = "ownerReferences"
+	KerberosConfigFieldRealms               = "realms"
+	KerberosConfigFieldRemoved              = "removed"
+	KerberosConfigFieldServicePrincipalName = "servicePrincipalName"
+	KerberosConfigFieldStatus               = "status"
+	KerberosConfigFieldType                 = "type"
+	KerberosConfigFieldUUID                 = "uuid"
+)
+
+type KerberosConfig struct {
+	AccessMode           string            `json:"accessMode,omitempty" yaml:"accessMode,omitempty"`
+	AllowedPrincipalIDs  []string          `json:"allowedPrincipalIds,omitempty" yaml:"allowedPrincipalIds,omitempty"`
+	Annotations          map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Created              string            `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID            string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	DirectoryProvider    string            `json:"directoryProvider,omitempty" yaml:"directoryProvider,omitempty"`
+	Enabled              bool              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Keytab               string            `json:"keytab,omitempty" yaml:"keytab,omitempty"`
+	Labels               map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	LogoutAllSupported   bool              `json:"logoutAllSupported,omitempty" yaml:"logoutAllSupported,omitempty"`
+	MaxClockSkewSeconds  int64             `json:"maxClockSkewSeconds,omitempty" yaml:"maxClockSkewSeconds,omitempty"`
+	Name                 string            `json:"name,omitempty" yaml:"name,omitempty"`
+	OwnerReferences      []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	Realms               []string          `json:"realms,omitempty" yaml:"realms,omitempty"`
+	Removed              string            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	ServicePrincipalName string            `json:"servicePrincipalName,omitempty" yaml:"servicePrincipalName,omitempty"`
+	Status               *AuthConfigStatus `json:"status,omitempty" yaml:"status,omitempty"`
+	Type                 string            `json:"type,omitempty" yaml:"type,omitempty"`
+	UUID                 string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+}
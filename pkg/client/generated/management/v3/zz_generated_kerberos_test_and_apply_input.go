@@ -0,0 +1,12 @@
+package client
+
+const (
+	KerberosTestAndApplyInputType                = "kerberosTestAndApplyInput"
+	KerberosTestAndApplyInputFieldEnabled        = "enabled"
+	KerberosTestAndApplyInputFieldKerberosConfig = "kerberosConfig"
+)
+
+type KerberosTestAndApplyInput struct {
+	Enabled        bool            `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	KerberosConfig *KerberosConfig `json:"kerberosConfig,omitempty" yaml:"kerberosConfig,omitempty"`
+}
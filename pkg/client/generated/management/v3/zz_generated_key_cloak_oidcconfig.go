@@ -9,6 +9,7 @@
 	KeyCloakOIDCConfigFieldAuthEndpoint        = "authEndpoint"
 	KeyCloakOIDCConfigFieldCertificate         = "certificate"
 	KeyCloakOIDCConfigFieldClientID            = "clientId"
+	KeyCloakOIDCConfigFieldClientRolesAsGroups = "clientRolesAsGroups"
 	KeyCloakOIDCConfigFieldClientSecret        = "clientSecret"
 	KeyCloakOIDCConfigFieldCreated             = "created"
 	KeyCloakOIDCConfigFieldCreatorID           = "creatorId"
@@ -23,7 +24,9 @@
 	KeyCloakOIDCConfigFieldOwnerReferences     = "ownerReferences"
 	KeyCloakOIDCConfigFieldPrivateKey          = "privateKey"
 	KeyCloakOIDCConfigFieldRancherURL          = "rancherUrl"
+	KeyCloakOIDCConfigFieldRealmRolesAsGroups  = "realmRolesAsGroups"
 	KeyCloakOIDCConfigFieldRemoved             = "removed"
+	KeyCloakOIDCConfigFieldRoleGroupPrefix     = "roleGroupPrefix"
 	KeyCloakOIDCConfigFieldScopes              = "scope"
 	KeyCloakOIDCConfigFieldStatus              = "status"
 	KeyCloakOIDCConfigFieldTokenEndpoint       = "tokenEndpoint"
@@ -40,6 +43,7 @@ type KeyCloakOIDCConfig struct {
 	AuthEndpoint        string            `json:"authEndpoint,omitempty" yaml:"authEndpoint,omitempty"`
 	Certificate         string            `json:"certificate,omitempty" yaml:"certificate,omitempty"`
 	ClientID            string            `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	ClientRolesAsGroups []string          `json:"clientRolesAsGroups,omitempty" yaml:"clientRolesAsGroups,omitempty"`
 	ClientSecret        string            `json:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
 	Created             string            `json:"created,omitempty" yaml:"created,omitempty"`
 	CreatorID           string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
@@ -54,7 +58,9 @@ type KeyCloakOIDCConfig struct {
 	OwnerReferences     []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
 	PrivateKey          string            `json:"privateKey,omitempty" yaml:"privateKey,omitempty"`
 	RancherURL          string            `json:"rancherUrl,omitempty" yaml:"rancherUrl,omitempty"`
+	RealmRolesAsGroups  bool              `json:"realmRolesAsGroups,omitempty" yaml:"realmRolesAsGroups,omitempty"`
 	Removed             string            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	RoleGroupPrefix     string            `json:"roleGroupPrefix,omitempty" yaml:"roleGroupPrefix,omitempty"`
 	Scopes              string            `json:"scope,omitempty" yaml:"scope,omitempty"`
 	Status              *AuthConfigStatus `json:"status,omitempty" yaml:"status,omitempty"`
 	TokenEndpoint       string            `json:"tokenEndpoint,omitempty" yaml:"tokenEndpoint,omitempty"`
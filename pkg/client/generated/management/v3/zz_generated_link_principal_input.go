@@ -0,0 +1,16 @@
+package client
+
+const (
+	LinkPrincipalInputType          = "linkPrincipalInput"
+	LinkPrincipalInputFieldCode     = "code"
+	LinkPrincipalInputFieldPassword = "password"
+	LinkPrincipalInputFieldProvider = "provider"
+	LinkPrincipalInputFieldUsername = "username"
+)
+
+type LinkPrincipalInput struct {
+	Code     string `json:"code,omitempty" yaml:"code,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+}
@@ -0,0 +1,12 @@
+package client
+
+const (
+	LoginAsInputType            = "loginAsInput"
+	LoginAsInputFieldReason     = "reason"
+	LoginAsInputFieldTTLMinutes = "ttlMinutes"
+)
+
+type LoginAsInput struct {
+	Reason     string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	TTLMinutes int64  `json:"ttlMinutes,omitempty" yaml:"ttlMinutes,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	LoginAsOutputType           = "loginAsOutput"
+	LoginAsOutputFieldExpiresAt = "expiresAt"
+	LoginAsOutputFieldToken     = "token"
+	LoginAsOutputFieldTokenName = "tokenName"
+)
+
+type LoginAsOutput struct {
+	ExpiresAt string `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	Token     string `json:"token,omitempty" yaml:"token,omitempty"`
+	TokenName string `json:"tokenName,omitempty" yaml:"tokenName,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	MergeUsersInputType              = "mergeUsersInput"
+	MergeUsersInputFieldDryRun       = "dryRun"
+	MergeUsersInputFieldSourceUserID = "sourceUserId"
+	MergeUsersInputFieldTargetUserID = "targetUserId"
+)
+
+type MergeUsersInput struct {
+	DryRun       bool   `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+	SourceUserID string `json:"sourceUserId,omitempty" yaml:"sourceUserId,omitempty"`
+	TargetUserID string `json:"targetUserId,omitempty" yaml:"targetUserId,omitempty"`
+}
@@ -0,0 +1,24 @@
+package client
+
+const (
+	MergeUsersOutputType                             = "mergeUsersOutput"
+	MergeUsersOutputFieldClusterRoleTemplateBindings = "clusterRoleTemplateBindings"
+	MergeUsersOutputFieldClusters                    = "clusters"
+	MergeUsersOutputFieldDryRun                      = "dryRun"
+	MergeUsersOutputFieldGlobalRoleBindings          = "globalRoleBindings"
+	MergeUsersOutputFieldPrincipalIDs                = "principalIds"
+	MergeUsersOutputFieldProjectRoleTemplateBindings = "projectRoleTemplateBindings"
+	MergeUsersOutputFieldProjects                    = "projects"
+	MergeUsersOutputFieldTokens                      = "tokens"
+)
+
+type MergeUsersOutput struct {
+	ClusterRoleTemplateBindings []string `json:"clusterRoleTemplateBindings,omitempty" yaml:"clusterRoleTemplateBindings,omitempty"`
+	Clusters                    []string `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	DryRun                      bool     `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+	GlobalRoleBindings          []string `json:"globalRoleBindings,omitempty" yaml:"globalRoleBindings,omitempty"`
+	PrincipalIDs                []string `json:"principalIds,omitempty" yaml:"principalIds,omitempty"`
+	ProjectRoleTemplateBindings []string `json:"projectRoleTemplateBindings,omitempty" yaml:"projectRoleTemplateBindings,omitempty"`
+	Projects                    []string `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Tokens                      []string `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+}
@@ -0,0 +1,16 @@
+package client
+
+const (
+	OktaAPIFieldsType                 = "oktaAPIFields"
+	OktaAPIFieldsFieldAPIToken        = "apiToken"
+	OktaAPIFieldsFieldOrgURL          = "orgUrl"
+	OktaAPIFieldsFieldSearchLimit     = "searchLimit"
+	OktaAPIFieldsFieldUserIDAttribute = "userIdAttribute"
+)
+
+type OktaAPIFields struct {
+	APIToken        string `json:"apiToken,omitempty" yaml:"apiToken,omitempty"`
+	OrgURL          string `json:"orgUrl,omitempty" yaml:"orgUrl,omitempty"`
+	SearchLimit     int64  `json:"searchLimit,omitempty" yaml:"searchLimit,omitempty"`
+	UserIDAttribute string `json:"userIdAttribute,omitempty" yaml:"userIdAttribute,omitempty"`
+}
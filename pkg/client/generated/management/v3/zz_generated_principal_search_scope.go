@@ -0,0 +1,12 @@
+package client
+
+const (
+	PrincipalSearchScopeType                 = "principalSearchScope"
+	PrincipalSearchScopeFieldGroupPrincipals = "groupPrincipals"
+	PrincipalSearchScopeFieldSearchBases     = "searchBases"
+)
+
+type PrincipalSearchScope struct {
+	GroupPrincipals []string `json:"groupPrincipals,omitempty" yaml:"groupPrincipals,omitempty"`
+	SearchBases     []string `json:"searchBases,omitempty" yaml:"searchBases,omitempty"`
+}
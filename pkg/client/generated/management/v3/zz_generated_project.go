@@ -13,11 +13,14 @@
 	ProjectFieldCreated                       = "created"
 	ProjectFieldCreatorID                     = "creatorId"
 	ProjectFieldDescription                   = "description"
+	ProjectFieldDirectoryAttributeMemberships = "directoryAttributeMemberships"
 	ProjectFieldLabels                        = "labels"
+	ProjectFieldMemberSearchScope             = "memberSearchScope"
 	ProjectFieldName                          = "name"
 	ProjectFieldNamespaceDefaultResourceQuota = "namespaceDefaultResourceQuota"
 	ProjectFieldNamespaceId                   = "namespaceId"
 	ProjectFieldOwnerReferences               = "ownerReferences"
+	ProjectFieldParentProject                 = "parentProject"
 	ProjectFieldRemoved                       = "removed"
 	ProjectFieldResourceQuota                 = "resourceQuota"
 	ProjectFieldState                         = "state"
@@ -28,24 +31,27 @@
 
 type Project struct {
 	types.Resource
-	Annotations                   map[string]string       `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	ClusterID                     string                  `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
-	Conditions                    []ProjectCondition      `json:"conditions,omitempty" yaml:"conditions,omitempty"`
-	ContainerDefaultResourceLimit *ContainerResourceLimit `json:"containerDefaultResourceLimit,omitempty" yaml:"containerDefaultResourceLimit,omitempty"`
-	Created                       string                  `json:"created,omitempty" yaml:"created,omitempty"`
-	CreatorID                     string                  `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
-	Description                   string                  `json:"description,omitempty" yaml:"description,omitempty"`
-	Labels                        map[string]string       `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Name                          string                  `json:"name,omitempty" yaml:"name,omitempty"`
-	NamespaceDefaultResourceQuota *NamespaceResourceQuota `json:"namespaceDefaultResourceQuota,omitempty" yaml:"namespaceDefaultResourceQuota,omitempty"`
-	NamespaceId                   string                  `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
-	OwnerReferences               []OwnerReference        `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
-	Removed                       string                  `json:"removed,omitempty" yaml:"removed,omitempty"`
-	ResourceQuota                 *ProjectResourceQuota   `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
-	State                         string                  `json:"state,omitempty" yaml:"state,omitempty"`
-	Transitioning                 string                  `json:"transitioning,omitempty" yaml:"transitioning,omitempty"`
-	TransitioningMessage          string                  `json:"transitioningMessage,omitempty" yaml:"transitioningMessage,omitempty"`
-	UUID                          string                  `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Annotations                   map[string]string              `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	ClusterID                     string                         `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
+	Conditions                    []ProjectCondition             `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	ContainerDefaultResourceLimit *ContainerResourceLimit        `json:"containerDefaultResourceLimit,omitempty" yaml:"containerDefaultResourceLimit,omitempty"`
+	Created                       string                         `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID                     string                         `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	Description                   string                         `json:"description,omitempty" yaml:"description,omitempty"`
+	DirectoryAttributeMemberships []DirectoryAttributeMembership `json:"directoryAttributeMemberships,omitempty" yaml:"directoryAttributeMemberships,omitempty"`
+	Labels                        map[string]string              `json:"labels,omitempty" yaml:"labels,omitempty"`
+	MemberSearchScope             *PrincipalSearchScope          `json:"memberSearchScope,omitempty" yaml:"memberSearchScope,omitempty"`
+	Name                          string                         `json:"name,omitempty" yaml:"name,omitempty"`
+	NamespaceDefaultResourceQuota *NamespaceResourceQuota        `json:"namespaceDefaultResourceQuota,omitempty" yaml:"namespaceDefaultResourceQuota,omitempty"`
+	NamespaceId                   string                         `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
+	OwnerReferences               []OwnerReference               `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	ParentProject                 string                         `json:"parentProject,omitempty" yaml:"parentProject,omitempty"`
+	Removed                       string                         `json:"removed,omitempty" yaml:"removed,omitempty"`
+	ResourceQuota                 *ProjectResourceQuota          `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+	State                         string                         `json:"state,omitempty" yaml:"state,omitempty"`
+	Transitioning                 string                         `json:"transitioning,omitempty" yaml:"transitioning,omitempty"`
+	TransitioningMessage          string                         `json:"transitioningMessage,omitempty" yaml:"transitioningMessage,omitempty"`
+	UUID                          string                         `json:"uuid,omitempty" yaml:"uuid,omitempty"`
 }
 
 type ProjectCollection struct {
@@ -5,43 +5,51 @@
 )
 
 const (
-	ProjectRoleTemplateBindingType                  = "projectRoleTemplateBinding"
-	ProjectRoleTemplateBindingFieldAnnotations      = "annotations"
-	ProjectRoleTemplateBindingFieldCreated          = "created"
-	ProjectRoleTemplateBindingFieldCreatorID        = "creatorId"
-	ProjectRoleTemplateBindingFieldGroupID          = "groupId"
-	ProjectRoleTemplateBindingFieldGroupPrincipalID = "groupPrincipalId"
-	ProjectRoleTemplateBindingFieldLabels           = "labels"
-	ProjectRoleTemplateBindingFieldName             = "name"
-	ProjectRoleTemplateBindingFieldNamespaceId      = "namespaceId"
-	ProjectRoleTemplateBindingFieldOwnerReferences  = "ownerReferences"
-	ProjectRoleTemplateBindingFieldProjectID        = "projectId"
-	ProjectRoleTemplateBindingFieldRemoved          = "removed"
-	ProjectRoleTemplateBindingFieldRoleTemplateID   = "roleTemplateId"
-	ProjectRoleTemplateBindingFieldServiceAccount   = "serviceAccount"
-	ProjectRoleTemplateBindingFieldUUID             = "uuid"
-	ProjectRoleTemplateBindingFieldUserID           = "userId"
-	ProjectRoleTemplateBindingFieldUserPrincipalID  = "userPrincipalId"
+	ProjectRoleTemplateBindingType                   = "projectRoleTemplateBinding"
+	ProjectRoleTemplateBindingFieldAnnotations       = "annotations"
+	ProjectRoleTemplateBindingFieldCreated           = "created"
+	ProjectRoleTemplateBindingFieldCreatorID         = "creatorId"
+	ProjectRoleTemplateBindingFieldExpiresAt         = "expiresAt"
+	ProjectRoleTemplateBindingFieldGroupID           = "groupId"
+	ProjectRoleTemplateBindingFieldGroupPrincipalID  = "groupPrincipalId"
+	ProjectRoleTemplateBindingFieldLabels            = "labels"
+	ProjectRoleTemplateBindingFieldName              = "name"
+	ProjectRoleTemplateBindingFieldNamespaceId       = "namespaceId"
+	ProjectRoleTemplateBindingFieldNamespaceSelector = "namespaceSelector"
+	ProjectRoleTemplateBindingFieldOwnerReferences   = "ownerReferences"
+	ProjectRoleTemplateBindingFieldProjectID         = "projectId"
+	ProjectRoleTemplateBindingFieldRemoved           = "removed"
+	ProjectRoleTemplateBindingFieldRoleTemplateID    = "roleTemplateId"
+	ProjectRoleTemplateBindingFieldServiceAccount    = "serviceAccount"
+	ProjectRoleTemplateBindingFieldStatus            = "status"
+	ProjectRoleTemplateBindingFieldTTL               = "ttl"
+	ProjectRoleTemplateBindingFieldUUID              = "uuid"
+	ProjectRoleTemplateBindingFieldUserID            = "userId"
+	ProjectRoleTemplateBindingFieldUserPrincipalID   = "userPrincipalId"
 )
 
 type ProjectRoleTemplateBinding struct {
 	types.Resource
-	Annotations      map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Created          string            `json:"created,omitempty" yaml:"created,omitempty"`
-	CreatorID        string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
-	GroupID          string            `json:"groupId,omitempty" yaml:"groupId,omitempty"`
-	GroupPrincipalID string            `json:"groupPrincipalId,omitempty" yaml:"groupPrincipalId,omitempty"`
-	Labels           map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Name             string            `json:"name,omitempty" yaml:"name,omitempty"`
-	NamespaceId      string            `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
-	OwnerReferences  []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
-	ProjectID        string            `json:"projectId,omitempty" yaml:"projectId,omitempty"`
-	Removed          string            `json:"removed,omitempty" yaml:"removed,omitempty"`
-	RoleTemplateID   string            `json:"roleTemplateId,omitempty" yaml:"roleTemplateId,omitempty"`
-	ServiceAccount   string            `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
-	UUID             string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
-	UserID           string            `json:"userId,omitempty" yaml:"userId,omitempty"`
-	UserPrincipalID  string            `json:"userPrincipalId,omitempty" yaml:"userPrincipalId,omitempty"`
+	Annotations       map[string]string                 `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Created           string                            `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID         string                            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	ExpiresAt         string                            `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	GroupID           string                            `json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	GroupPrincipalID  string                            `json:"groupPrincipalId,omitempty" yaml:"groupPrincipalId,omitempty"`
+	Labels            map[string]string                 `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Name              string                            `json:"name,omitempty" yaml:"name,omitempty"`
+	NamespaceId       string                            `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
+	NamespaceSelector *LabelSelector                    `json:"namespaceSelector,omitempty" yaml:"namespaceSelector,omitempty"`
+	OwnerReferences   []OwnerReference                  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	ProjectID         string                            `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	Removed           string                            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	RoleTemplateID    string                            `json:"roleTemplateId,omitempty" yaml:"roleTemplateId,omitempty"`
+	ServiceAccount    string                            `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
+	Status            *ProjectRoleTemplateBindingStatus `json:"status,omitempty" yaml:"status,omitempty"`
+	TTL               string                            `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	UUID              string                            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	UserID            string                            `json:"userId,omitempty" yaml:"userId,omitempty"`
+	UserPrincipalID   string                            `json:"userPrincipalId,omitempty" yaml:"userPrincipalId,omitempty"`
 }
 
 type ProjectRoleTemplateBindingCollection struct {
@@ -0,0 +1,16 @@
+package client
+
+const (
+	ProjectRoleTemplateBindingStatusType                          = "projectRoleTemplateBindingStatus"
+	ProjectRoleTemplateBindingStatusFieldConditions               = "conditions"
+	ProjectRoleTemplateBindingStatusFieldObservedGenerationRemote = "observedGenerationRemote"
+	ProjectRoleTemplateBindingStatusFieldRemoteConditions         = "remoteConditions"
+	ProjectRoleTemplateBindingStatusFieldSummaryRemote            = "summaryRemote"
+)
+
+type ProjectRoleTemplateBindingStatus struct {
+	Conditions               []Condition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	ObservedGenerationRemote int64       `json:"observedGenerationRemote,omitempty" yaml:"observedGenerationRemote,omitempty"`
+	RemoteConditions         []Condition `json:"remoteConditions,omitempty" yaml:"remoteConditions,omitempty"`
+	SummaryRemote            string      `json:"summaryRemote,omitempty" yaml:"summaryRemote,omitempty"`
+}
@@ -5,16 +5,22 @@
 	ProjectSpecFieldClusterID                     = "clusterId"
 	ProjectSpecFieldContainerDefaultResourceLimit = "containerDefaultResourceLimit"
 	ProjectSpecFieldDescription                   = "description"
+	ProjectSpecFieldDirectoryAttributeMemberships = "directoryAttributeMemberships"
 	ProjectSpecFieldDisplayName                   = "displayName"
+	ProjectSpecFieldMemberSearchScope             = "memberSearchScope"
 	ProjectSpecFieldNamespaceDefaultResourceQuota = "namespaceDefaultResourceQuota"
+	ProjectSpecFieldParentProject                 = "parentProject"
 	ProjectSpecFieldResourceQuota                 = "resourceQuota"
 )
 
 type ProjectSpec struct {
-	ClusterID                     string                  `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
-	ContainerDefaultResourceLimit *ContainerResourceLimit `json:"containerDefaultResourceLimit,omitempty" yaml:"containerDefaultResourceLimit,omitempty"`
-	Description                   string                  `json:"description,omitempty" yaml:"description,omitempty"`
-	DisplayName                   string                  `json:"displayName,omitempty" yaml:"displayName,omitempty"`
-	NamespaceDefaultResourceQuota *NamespaceResourceQuota `json:"namespaceDefaultResourceQuota,omitempty" yaml:"namespaceDefaultResourceQuota,omitempty"`
-	ResourceQuota                 *ProjectResourceQuota   `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+	ClusterID                     string                         `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
+	ContainerDefaultResourceLimit *ContainerResourceLimit        `json:"containerDefaultResourceLimit,omitempty" yaml:"containerDefaultResourceLimit,omitempty"`
+	Description                   string                         `json:"description,omitempty" yaml:"description,omitempty"`
+	DirectoryAttributeMemberships []DirectoryAttributeMembership `json:"directoryAttributeMemberships,omitempty" yaml:"directoryAttributeMemberships,omitempty"`
+	DisplayName                   string                         `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	MemberSearchScope             *PrincipalSearchScope          `json:"memberSearchScope,omitempty" yaml:"memberSearchScope,omitempty"`
+	NamespaceDefaultResourceQuota *NamespaceResourceQuota        `json:"namespaceDefaultResourceQuota,omitempty" yaml:"namespaceDefaultResourceQuota,omitempty"`
+	ParentProject                 string                         `json:"parentProject,omitempty" yaml:"parentProject,omitempty"`
+	ResourceQuota                 *ProjectResourceQuota          `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
 }
@@ -0,0 +1,18 @@
+package client
+
+const (
+	RenderedBindingType             = "renderedBinding"
+	RenderedBindingFieldName        = "name"
+	RenderedBindingFieldNamespace   = "namespace"
+	RenderedBindingFieldRoleName    = "roleName"
+	RenderedBindingFieldSubjectKind = "subjectKind"
+	RenderedBindingFieldSubjectName = "subjectName"
+)
+
+type RenderedBinding struct {
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace   string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	RoleName    string `json:"roleName,omitempty" yaml:"roleName,omitempty"`
+	SubjectKind string `json:"subjectKind,omitempty" yaml:"subjectKind,omitempty"`
+	SubjectName string `json:"subjectName,omitempty" yaml:"subjectName,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	RenderedRoleType          = "renderedRole"
+	RenderedRoleFieldExternal = "external"
+	RenderedRoleFieldName     = "name"
+	RenderedRoleFieldRules    = "rules"
+)
+
+type RenderedRole struct {
+	External bool         `json:"external,omitempty" yaml:"external,omitempty"`
+	Name     string       `json:"name,omitempty" yaml:"name,omitempty"`
+	Rules    []PolicyRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
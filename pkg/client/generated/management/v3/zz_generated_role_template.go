@@ -14,6 +14,7 @@
 	RoleTemplateFieldCreated               = "created"
 	RoleTemplateFieldCreatorID             = "creatorId"
 	RoleTemplateFieldDescription           = "description"
+	RoleTemplateFieldExcludedRules         = "excludedRules"
 	RoleTemplateFieldExternal              = "external"
 	RoleTemplateFieldExternalRules         = "externalRules"
 	RoleTemplateFieldHidden                = "hidden"
@@ -38,6 +39,7 @@ type RoleTemplate struct {
 	Created               string            `json:"created,omitempty" yaml:"created,omitempty"`
 	CreatorID             string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
 	Description           string            `json:"description,omitempty" yaml:"description,omitempty"`
+	ExcludedRules         []PolicyRule      `json:"excludedRules,omitempty" yaml:"excludedRules,omitempty"`
 	External              bool              `json:"external,omitempty" yaml:"external,omitempty"`
 	ExternalRules         []PolicyRule      `json:"externalRules,omitempty" yaml:"externalRules,omitempty"`
 	Hidden                bool              `json:"hidden,omitempty" yaml:"hidden,omitempty"`
@@ -70,6 +72,10 @@ type RoleTemplateOperations interface {
 	Replace(existing *RoleTemplate) (*RoleTemplate, error)
 	ByID(id string) (*RoleTemplate, error)
 	Delete(container *RoleTemplate) error
+
+	ActionRender(resource *RoleTemplate, input *RoleTemplateRenderInput) (*RoleTemplateRenderOutput, error)
+
+	ActionSimulate(resource *RoleTemplate, input *RoleTemplateSimulateInput) (*RoleTemplateSimulateOutput, error)
 }
 
 func newRoleTemplateClient(apiClient *Client) *RoleTemplateClient {
@@ -140,3 +146,15 @@ func (c *RoleTemplateClient) ByID(id string) (*RoleTemplate, error) {
 func (c *RoleTemplateClient) Delete(container *RoleTemplate) error {
 	return c.apiClient.Ops.DoResourceDelete(RoleTemplateType, &container.Resource)
 }
+
+func (c *RoleTemplateClient) ActionRender(resource *RoleTemplate, input *RoleTemplateRenderInput) (*RoleTemplateRenderOutput, error) {
+	resp := &RoleTemplateRenderOutput{}
+	err := c.apiClient.Ops.DoAction(RoleTemplateType, "render", &resource.Resource, input, resp)
+	return resp, err
+}
+
+func (c *RoleTemplateClient) ActionSimulate(resource *RoleTemplate, input *RoleTemplateSimulateInput) (*RoleTemplateSimulateOutput, error) {
+	resp := &RoleTemplateSimulateOutput{}
+	err := c.apiClient.Ops.DoAction(RoleTemplateType, "simulate", &resource.Resource, input, resp)
+	return resp, err
+}
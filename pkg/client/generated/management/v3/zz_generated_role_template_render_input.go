@@ -0,0 +1,24 @@
+package client
+
+const (
+	RoleTemplateRenderInputType                   = "roleTemplateRenderInput"
+	RoleTemplateRenderInputFieldClusterID         = "clusterId"
+	RoleTemplateRenderInputFieldExcludedRules     = "excludedRules"
+	RoleTemplateRenderInputFieldExternalRules     = "externalRules"
+	RoleTemplateRenderInputFieldGroupPrincipalID  = "groupPrincipalId"
+	RoleTemplateRenderInputFieldProjectID         = "projectId"
+	RoleTemplateRenderInputFieldRoleTemplateNames = "roleTemplateNames"
+	RoleTemplateRenderInputFieldRules             = "rules"
+	RoleTemplateRenderInputFieldUserID            = "userId"
+)
+
+type RoleTemplateRenderInput struct {
+	ClusterID         string       `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
+	ExcludedRules     []PolicyRule `json:"excludedRules,omitempty" yaml:"excludedRules,omitempty"`
+	ExternalRules     []PolicyRule `json:"externalRules,omitempty" yaml:"externalRules,omitempty"`
+	GroupPrincipalID  string       `json:"groupPrincipalId,omitempty" yaml:"groupPrincipalId,omitempty"`
+	ProjectID         string       `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	RoleTemplateNames []string     `json:"roleTemplateNames,omitempty" yaml:"roleTemplateNames,omitempty"`
+	Rules             []PolicyRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+	UserID            string       `json:"userId,omitempty" yaml:"userId,omitempty"`
+}
@@ -0,0 +1,16 @@
+package client
+
+const (
+	RoleTemplateRenderOutputType                     = "roleTemplateRenderOutput"
+	RoleTemplateRenderOutputFieldClusterRoleBindings = "clusterRoleBindings"
+	RoleTemplateRenderOutputFieldClusterRoles        = "clusterRoles"
+	RoleTemplateRenderOutputFieldRoleBindings        = "roleBindings"
+	RoleTemplateRenderOutputFieldWarnings            = "warnings"
+)
+
+type RoleTemplateRenderOutput struct {
+	ClusterRoleBindings []RenderedBinding `json:"clusterRoleBindings,omitempty" yaml:"clusterRoleBindings,omitempty"`
+	ClusterRoles        []RenderedRole    `json:"clusterRoles,omitempty" yaml:"clusterRoles,omitempty"`
+	RoleBindings        []RenderedBinding `json:"roleBindings,omitempty" yaml:"roleBindings,omitempty"`
+	Warnings            []string          `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
@@ -0,0 +1,16 @@
+package client
+
+const (
+	RoleTemplateSimulateInputType                   = "roleTemplateSimulateInput"
+	RoleTemplateSimulateInputFieldExcludedRules     = "excludedRules"
+	RoleTemplateSimulateInputFieldExternalRules     = "externalRules"
+	RoleTemplateSimulateInputFieldRoleTemplateNames = "roleTemplateNames"
+	RoleTemplateSimulateInputFieldRules             = "rules"
+)
+
+type RoleTemplateSimulateInput struct {
+	ExcludedRules     []PolicyRule `json:"excludedRules,omitempty" yaml:"excludedRules,omitempty"`
+	ExternalRules     []PolicyRule `json:"externalRules,omitempty" yaml:"externalRules,omitempty"`
+	RoleTemplateNames []string     `json:"roleTemplateNames,omitempty" yaml:"roleTemplateNames,omitempty"`
+	Rules             []PolicyRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
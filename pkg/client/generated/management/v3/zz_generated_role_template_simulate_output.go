@@ -0,0 +1,16 @@
+package client
+
+const (
+	RoleTemplateSimulateOutputType               = "roleTemplateSimulateOutput"
+	RoleTemplateSimulateOutputFieldClusters      = "clusters"
+	RoleTemplateSimulateOutputFieldRoleTemplates = "roleTemplates"
+	RoleTemplateSimulateOutputFieldSubjectCount  = "subjectCount"
+	RoleTemplateSimulateOutputFieldWarnings      = "warnings"
+)
+
+type RoleTemplateSimulateOutput struct {
+	Clusters      []SimulatedCluster      `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	RoleTemplates []SimulatedRoleTemplate `json:"roleTemplates,omitempty" yaml:"roleTemplates,omitempty"`
+	SubjectCount  int64                   `json:"subjectCount,omitempty" yaml:"subjectCount,omitempty"`
+	Warnings      []string                `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
@@ -2,11 +2,15 @@
 
 const (
 	SearchPrincipalsInputType               = "searchPrincipalsInput"
+	SearchPrincipalsInputFieldClusterID     = "clusterId"
 	SearchPrincipalsInputFieldName          = "name"
 	SearchPrincipalsInputFieldPrincipalType = "principalType"
+	SearchPrincipalsInputFieldProjectID     = "projectId"
 )
 
 type SearchPrincipalsInput struct {
+	ClusterID     string `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
 	Name          string `json:"name,omitempty" yaml:"name,omitempty"`
 	PrincipalType string `json:"principalType,omitempty" yaml:"principalType,omitempty"`
+	ProjectID     string `json:"projectId,omitempty" yaml:"projectId,omitempty"`
 }
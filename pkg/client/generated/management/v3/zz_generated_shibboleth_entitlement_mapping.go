@@ -0,0 +1,14 @@
+package client
+
+const (
+	ShibbolethEntitlementMappingType           = "shibbolethEntitlementMapping"
+	ShibbolethEntitlementMappingFieldAttribute = "attribute"
+	ShibbolethEntitlementMappingFieldGroup     = "group"
+	ShibbolethEntitlementMappingFieldPattern   = "pattern"
+)
+
+type ShibbolethEntitlementMapping struct {
+	Attribute string `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Group     string `json:"group,omitempty" yaml:"group,omitempty"`
+	Pattern   string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+}
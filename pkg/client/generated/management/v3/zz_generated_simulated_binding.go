@@ -0,0 +1,18 @@
+package client
+
+const (
+	SimulatedBindingType             = "simulatedBinding"
+	SimulatedBindingFieldKind        = "kind"
+	SimulatedBindingFieldName        = "name"
+	SimulatedBindingFieldRoleName    = "roleName"
+	SimulatedBindingFieldSubjectKind = "subjectKind"
+	SimulatedBindingFieldSubjectName = "subjectName"
+)
+
+type SimulatedBinding struct {
+	Kind        string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	RoleName    string `json:"roleName,omitempty" yaml:"roleName,omitempty"`
+	SubjectKind string `json:"subjectKind,omitempty" yaml:"subjectKind,omitempty"`
+	SubjectName string `json:"subjectName,omitempty" yaml:"subjectName,omitempty"`
+}
@@ -0,0 +1,18 @@
+package client
+
+const (
+	SimulatedClusterType              = "simulatedCluster"
+	SimulatedClusterFieldAddedRules   = "addedRules"
+	SimulatedClusterFieldBindings     = "bindings"
+	SimulatedClusterFieldClusterName  = "clusterName"
+	SimulatedClusterFieldRemovedRules = "removedRules"
+	SimulatedClusterFieldSubjects     = "subjects"
+)
+
+type SimulatedCluster struct {
+	AddedRules   []PolicyRule       `json:"addedRules,omitempty" yaml:"addedRules,omitempty"`
+	Bindings     []SimulatedBinding `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+	ClusterName  string             `json:"clusterName,omitempty" yaml:"clusterName,omitempty"`
+	RemovedRules []PolicyRule       `json:"removedRules,omitempty" yaml:"removedRules,omitempty"`
+	Subjects     []string           `json:"subjects,omitempty" yaml:"subjects,omitempty"`
+}
@@ -0,0 +1,16 @@
+package client
+
+const (
+	SimulatedRoleTemplateType              = "simulatedRoleTemplate"
+	SimulatedRoleTemplateFieldAddedRules   = "addedRules"
+	SimulatedRoleTemplateFieldKind         = "kind"
+	SimulatedRoleTemplateFieldName         = "name"
+	SimulatedRoleTemplateFieldRemovedRules = "removedRules"
+)
+
+type SimulatedRoleTemplate struct {
+	AddedRules   []PolicyRule `json:"addedRules,omitempty" yaml:"addedRules,omitempty"`
+	Kind         string       `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Name         string       `json:"name,omitempty" yaml:"name,omitempty"`
+	RemovedRules []PolicyRule `json:"removedRules,omitempty" yaml:"removedRules,omitempty"`
+}
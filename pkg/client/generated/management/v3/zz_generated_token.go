@@ -7,6 +7,7 @@
 const (
 	TokenType                    = "token"
 	TokenFieldActivityLastSeenAt = "activityLastSeenAt"
+	TokenFieldAllowedCIDRs       = "allowedCIDRs"
 	TokenFieldAnnotations        = "annotations"
 	TokenFieldAuthProvider       = "authProvider"
 	TokenFieldClusterID          = "clusterId"
@@ -25,6 +26,7 @@
 	TokenFieldOwnerReferences    = "ownerReferences"
 	TokenFieldProviderInfo       = "providerInfo"
 	TokenFieldRemoved            = "removed"
+	TokenFieldScope              = "scope"
 	TokenFieldTTLMillis          = "ttl"
 	TokenFieldToken              = "token"
 	TokenFieldUUID               = "uuid"
@@ -35,6 +37,7 @@
 type Token struct {
 	types.Resource
 	ActivityLastSeenAt string            `json:"activityLastSeenAt,omitempty" yaml:"activityLastSeenAt,omitempty"`
+	AllowedCIDRs       []string          `json:"allowedCIDRs,omitempty" yaml:"allowedCIDRs,omitempty"`
 	Annotations        map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	AuthProvider       string            `json:"authProvider,omitempty" yaml:"authProvider,omitempty"`
 	ClusterID          string            `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
@@ -53,6 +56,7 @@ type Token struct {
 	OwnerReferences    []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
 	ProviderInfo       map[string]string `json:"providerInfo,omitempty" yaml:"providerInfo,omitempty"`
 	Removed            string            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Scope              *TokenScope       `json:"scope,omitempty" yaml:"scope,omitempty"`
 	TTLMillis          int64             `json:"ttl,omitempty" yaml:"ttl,omitempty"`
 	Token              string            `json:"token,omitempty" yaml:"token,omitempty"`
 	UUID               string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
@@ -0,0 +1,18 @@
+package client
+
+const (
+	TokenScopeType           = "tokenScope"
+	TokenScopeFieldAPIGroups = "apiGroups"
+	TokenScopeFieldClusters  = "clusters"
+	TokenScopeFieldProjects  = "projects"
+	TokenScopeFieldReadOnly  = "readOnly"
+	TokenScopeFieldVerbs     = "verbs"
+)
+
+type TokenScope struct {
+	APIGroups []string `json:"apiGroups,omitempty" yaml:"apiGroups,omitempty"`
+	Clusters  []string `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	Projects  []string `json:"projects,omitempty" yaml:"projects,omitempty"`
+	ReadOnly  bool     `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	Verbs     []string `json:"verbs,omitempty" yaml:"verbs,omitempty"`
+}
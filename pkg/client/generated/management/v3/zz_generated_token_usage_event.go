@@ -0,0 +1,16 @@
+package client
+
+const (
+	TokenUsageEventType               = "tokenUsageEvent"
+	TokenUsageEventFieldEndpointClass = "endpointClass"
+	TokenUsageEventFieldSourceIP      = "sourceIp"
+	TokenUsageEventFieldTime          = "time"
+	TokenUsageEventFieldUserAgent     = "userAgent"
+)
+
+type TokenUsageEvent struct {
+	EndpointClass string `json:"endpointClass,omitempty" yaml:"endpointClass,omitempty"`
+	SourceIP      string `json:"sourceIp,omitempty" yaml:"sourceIp,omitempty"`
+	Time          string `json:"time,omitempty" yaml:"time,omitempty"`
+	UserAgent     string `json:"userAgent,omitempty" yaml:"userAgent,omitempty"`
+}
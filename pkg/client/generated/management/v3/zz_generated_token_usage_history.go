@@ -0,0 +1,12 @@
+package client
+
+const (
+	TokenUsageHistoryType           = "tokenUsageHistory"
+	TokenUsageHistoryFieldEvents    = "events"
+	TokenUsageHistoryFieldTokenName = "tokenName"
+)
+
+type TokenUsageHistory struct {
+	Events    []TokenUsageEvent `json:"events,omitempty" yaml:"events,omitempty"`
+	TokenName string            `json:"tokenName,omitempty" yaml:"tokenName,omitempty"`
+}
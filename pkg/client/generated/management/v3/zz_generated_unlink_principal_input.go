@@ -0,0 +1,10 @@
+package client
+
+const (
+	UnlinkPrincipalInputType             = "unlinkPrincipalInput"
+	UnlinkPrincipalInputFieldPrincipalID = "principalId"
+)
+
+type UnlinkPrincipalInput struct {
+	PrincipalID string `json:"principalId,omitempty" yaml:"principalId,omitempty"`
+}
@@ -11,6 +11,7 @@
 	UserFieldCreated              = "created"
 	UserFieldCreatorID            = "creatorId"
 	UserFieldDescription          = "description"
+	UserFieldEmail                = "email"
 	UserFieldEnabled              = "enabled"
 	UserFieldLabels               = "labels"
 	UserFieldMe                   = "me"
@@ -34,6 +35,7 @@ type User struct {
 	Created              string            `json:"created,omitempty" yaml:"created,omitempty"`
 	CreatorID            string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
 	Description          string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Email                string            `json:"email,omitempty" yaml:"email,omitempty"`
 	Enabled              *bool             `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 	Labels               map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	Me                   bool              `json:"me,omitempty" yaml:"me,omitempty"`
@@ -69,13 +71,27 @@ type UserOperations interface {
 	ByID(id string) (*User, error)
 	Delete(container *User) error
 
+	ActionErasedata(resource *User, input *EraseUserDataInput) (*EraseUserDataOutput, error)
+
+	ActionExportdata(resource *User) (*UserDataExport, error)
+
+	ActionLoginas(resource *User, input *LoginAsInput) (*LoginAsOutput, error)
+
 	ActionRefreshauthprovideraccess(resource *User) error
 
 	ActionSetpassword(resource *User, input *SetPasswordInput) (*User, error)
 
 	CollectionActionChangepassword(resource *UserCollection, input *ChangePasswordInput) error
 
+	CollectionActionDetectduplicates(resource *UserCollection) (*DetectDuplicateUsersOutput, error)
+
+	CollectionActionLinkprincipal(resource *UserCollection, input *LinkPrincipalInput) error
+
+	CollectionActionMergeusers(resource *UserCollection, input *MergeUsersInput) (*MergeUsersOutput, error)
+
 	CollectionActionRefreshauthprovideraccess(resource *UserCollection) error
+
+	CollectionActionUnlinkprincipal(resource *UserCollection, input *UnlinkPrincipalInput) error
 }
 
 func newUserClient(apiClient *Client) *UserClient {
@@ -147,6 +163,24 @@ func (c *UserClient) Delete(container *User) error {
 	return c.apiClient.Ops.DoResourceDelete(UserType, &container.Resource)
 }
 
+func (c *UserClient) ActionErasedata(resource *User, input *EraseUserDataInput) (*EraseUserDataOutput, error) {
+	resp := &EraseUserDataOutput{}
+	err := c.apiClient.Ops.DoAction(UserType, "erasedata", &resource.Resource, input, resp)
+	return resp, err
+}
+
+func (c *UserClient) ActionExportdata(resource *User) (*UserDataExport, error) {
+	resp := &UserDataExport{}
+	err := c.apiClient.Ops.DoAction(UserType, "exportdata", &resource.Resource, nil, resp)
+	return resp, err
+}
+
+func (c *UserClient) ActionLoginas(resource *User, input *LoginAsInput) (*LoginAsOutput, error) {
+	resp := &LoginAsOutput{}
+	err := c.apiClient.Ops.DoAction(UserType, "loginas", &resource.Resource, input, resp)
+	return resp, err
+}
+
 func (c *UserClient) ActionRefreshauthprovideraccess(resource *User) error {
 	err := c.apiClient.Ops.DoAction(UserType, "refreshauthprovideraccess", &resource.Resource, nil, nil)
 	return err
@@ -163,7 +197,29 @@ func (c *UserClient) CollectionActionChangepassword(resource *UserCollection, in
 	return err
 }
 
+func (c *UserClient) CollectionActionDetectduplicates(resource *UserCollection) (*DetectDuplicateUsersOutput, error) {
+	resp := &DetectDuplicateUsersOutput{}
+	err := c.apiClient.Ops.DoCollectionAction(UserType, "detectduplicates", &resource.Collection, nil, resp)
+	return resp, err
+}
+
+func (c *UserClient) CollectionActionLinkprincipal(resource *UserCollection, input *LinkPrincipalInput) error {
+	err := c.apiClient.Ops.DoCollectionAction(UserType, "linkprincipal", &resource.Collection, input, nil)
+	return err
+}
+
+func (c *UserClient) CollectionActionMergeusers(resource *UserCollection, input *MergeUsersInput) (*MergeUsersOutput, error) {
+	resp := &MergeUsersOutput{}
+	err := c.apiClient.Ops.DoCollectionAction(UserType, "mergeusers", &resource.Collection, input, resp)
+	return resp, err
+}
+
 func (c *UserClient) CollectionActionRefreshauthprovideraccess(resource *UserCollection) error {
 	err := c.apiClient.Ops.DoCollectionAction(UserType, "refreshauthprovideraccess", &resource.Collection, nil, nil)
 	return err
 }
+
+func (c *UserClient) CollectionActionUnlinkprincipal(resource *UserCollection, input *UnlinkPrincipalInput) error {
+	err := c.apiClient.Ops.DoCollectionAction(UserType, "unlinkprincipal", &resource.Collection, input, nil)
+	return err
+}
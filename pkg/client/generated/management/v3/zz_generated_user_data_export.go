@@ -0,0 +1,28 @@
+package client
+
+const (
+	UserDataExportType                             = "userDataExport"
+	UserDataExportFieldClusterRoleTemplateBindings = "clusterRoleTemplateBindings"
+	UserDataExportFieldExportedAt                  = "exportedAt"
+	UserDataExportFieldExtraByProvider             = "extraByProvider"
+	UserDataExportFieldGlobalRoleBindings          = "globalRoleBindings"
+	UserDataExportFieldGroupPrincipals             = "groupPrincipals"
+	UserDataExportFieldLastLogin                   = "lastLogin"
+	UserDataExportFieldProjectRoleTemplateBindings = "projectRoleTemplateBindings"
+	UserDataExportFieldTokenUsage                  = "tokenUsage"
+	UserDataExportFieldTokens                      = "tokens"
+	UserDataExportFieldUser                        = "user"
+)
+
+type UserDataExport struct {
+	ClusterRoleTemplateBindings []ClusterRoleTemplateBinding   `json:"clusterRoleTemplateBindings,omitempty" yaml:"clusterRoleTemplateBindings,omitempty"`
+	ExportedAt                  string                         `json:"exportedAt,omitempty" yaml:"exportedAt,omitempty"`
+	ExtraByProvider             map[string]map[string][]string `json:"extraByProvider,omitempty" yaml:"extraByProvider,omitempty"`
+	GlobalRoleBindings          []GlobalRoleBinding            `json:"globalRoleBindings,omitempty" yaml:"globalRoleBindings,omitempty"`
+	GroupPrincipals             []string                       `json:"groupPrincipals,omitempty" yaml:"groupPrincipals,omitempty"`
+	LastLogin                   string                         `json:"lastLogin,omitempty" yaml:"lastLogin,omitempty"`
+	ProjectRoleTemplateBindings []ProjectRoleTemplateBinding   `json:"projectRoleTemplateBindings,omitempty" yaml:"projectRoleTemplateBindings,omitempty"`
+	TokenUsage                  []TokenUsageHistory            `json:"tokenUsage,omitempty" yaml:"tokenUsage,omitempty"`
+	Tokens                      []Token                        `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+	User                        *User                          `json:"user,omitempty" yaml:"user,omitempty"`
+}
@@ -0,0 +1,12 @@
+package client
+
+const (
+	WSFedFieldsType          = "wsFedFields"
+	WSFedFieldsFieldEnabled  = "enabled"
+	WSFedFieldsFieldEndpoint = "endpoint"
+)
+
+type WSFedFields struct {
+	Enabled  bool   `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+}
@@ -1,18 +1,26 @@
 package client
 
 const (
-	AzureADLoginType              = "azureADLogin"
-	AzureADLoginFieldCode         = "code"
-	AzureADLoginFieldDescription  = "description"
-	AzureADLoginFieldIDToken      = "id_token"
-	AzureADLoginFieldResponseType = "responseType"
-	AzureADLoginFieldTTLMillis    = "ttl"
+	AzureADLoginType                 = "azureADLogin"
+	AzureADLoginFieldCaptchaResponse = "captchaResponse"
+	AzureADLoginFieldCode            = "code"
+	AzureADLoginFieldDescription     = "description"
+	AzureADLoginFieldIDToken         = "id_token"
+	AzureADLoginFieldRefreshToken    = "refreshToken"
+	AzureADLoginFieldResponseType    = "responseType"
+	AzureADLoginFieldTOTPCode        = "totpCode"
+	AzureADLoginFieldTTLMillis       = "ttl"
+	AzureADLoginFieldWebAuthn        = "webauthn"
 )
 
 type AzureADLogin struct {
-	Code         string `json:"code,omitempty" yaml:"code,omitempty"`
-	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
-	IDToken      string `json:"id_token,omitempty" yaml:"id_token,omitempty"`
-	ResponseType string `json:"responseType,omitempty" yaml:"responseType,omitempty"`
-	TTLMillis    int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	CaptchaResponse string             `json:"captchaResponse,omitempty" yaml:"captchaResponse,omitempty"`
+	Code            string             `json:"code,omitempty" yaml:"code,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	IDToken         string             `json:"id_token,omitempty" yaml:"id_token,omitempty"`
+	RefreshToken    bool               `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	ResponseType    string             `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	TOTPCode        string             `json:"totpCode,omitempty" yaml:"totpCode,omitempty"`
+	TTLMillis       int64              `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	WebAuthn        *WebAuthnAssertion `json:"webauthn,omitempty" yaml:"webauthn,omitempty"`
 }
@@ -1,18 +1,26 @@
 package client
 
 const (
-	BasicLoginType              = "basicLogin"
-	BasicLoginFieldDescription  = "description"
-	BasicLoginFieldPassword     = "password"
-	BasicLoginFieldResponseType = "responseType"
-	BasicLoginFieldTTLMillis    = "ttl"
-	BasicLoginFieldUsername     = "username"
+	BasicLoginType                 = "basicLogin"
+	BasicLoginFieldCaptchaResponse = "captchaResponse"
+	BasicLoginFieldDescription     = "description"
+	BasicLoginFieldPassword        = "password"
+	BasicLoginFieldRefreshToken    = "refreshToken"
+	BasicLoginFieldResponseType    = "responseType"
+	BasicLoginFieldTOTPCode        = "totpCode"
+	BasicLoginFieldTTLMillis       = "ttl"
+	BasicLoginFieldUsername        = "username"
+	BasicLoginFieldWebAuthn        = "webauthn"
 )
 
 type BasicLogin struct {
-	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
-	Password     string `json:"password,omitempty" yaml:"password,omitempty"`
-	ResponseType string `json:"responseType,omitempty" yaml:"responseType,omitempty"`
-	TTLMillis    int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
-	Username     string `json:"username,omitempty" yaml:"username,omitempty"`
+	CaptchaResponse string             `json:"captchaResponse,omitempty" yaml:"captchaResponse,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Password        string             `json:"password,omitempty" yaml:"password,omitempty"`
+	RefreshToken    bool               `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	ResponseType    string             `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	TOTPCode        string             `json:"totpCode,omitempty" yaml:"totpCode,omitempty"`
+	TTLMillis       int64              `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Username        string             `json:"username,omitempty" yaml:"username,omitempty"`
+	WebAuthn        *WebAuthnAssertion `json:"webauthn,omitempty" yaml:"webauthn,omitempty"`
 }
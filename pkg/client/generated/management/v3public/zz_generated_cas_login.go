@@ -0,0 +1,26 @@
+package client
+
+const (
+	CASLoginType                 = "casLogin"
+	CASLoginFieldCaptchaResponse = "captchaResponse"
+	CASLoginFieldDescription     = "description"
+	CASLoginFieldRefreshToken    = "refreshToken"
+	CASLoginFieldResponseType    = "responseType"
+	CASLoginFieldService         = "service"
+	CASLoginFieldTOTPCode        = "totpCode"
+	CASLoginFieldTTLMillis       = "ttl"
+	CASLoginFieldTicket          = "ticket"
+	CASLoginFieldWebAuthn        = "webauthn"
+)
+
+type CASLogin struct {
+	CaptchaResponse string             `json:"captchaResponse,omitempty" yaml:"captchaResponse,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	RefreshToken    bool               `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	ResponseType    string             `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	Service         string             `json:"service,omitempty" yaml:"service,omitempty"`
+	TOTPCode        string             `json:"totpCode,omitempty" yaml:"totpCode,omitempty"`
+	TTLMillis       int64              `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Ticket          string             `json:"ticket,omitempty" yaml:"ticket,omitempty"`
+	WebAuthn        *WebAuthnAssertion `json:"webauthn,omitempty" yaml:"webauthn,omitempty"`
+}
@@ -0,0 +1,22 @@
+package client
+
+const (
+	ClientCertLoginType                 = "clientCertLogin"
+	ClientCertLoginFieldCaptchaResponse = "captchaResponse"
+	ClientCertLoginFieldDescription     = "description"
+	ClientCertLoginFieldRefreshToken    = "refreshToken"
+	ClientCertLoginFieldResponseType    = "responseType"
+	ClientCertLoginFieldTOTPCode        = "totpCode"
+	ClientCertLoginFieldTTLMillis       = "ttl"
+	ClientCertLoginFieldWebAuthn        = "webauthn"
+)
+
+type ClientCertLogin struct {
+	CaptchaResponse string             `json:"captchaResponse,omitempty" yaml:"captchaResponse,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	RefreshToken    bool               `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	ResponseType    string             `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	TOTPCode        string             `json:"totpCode,omitempty" yaml:"totpCode,omitempty"`
+	TTLMillis       int64              `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	WebAuthn        *WebAuthnAssertion `json:"webauthn,omitempty" yaml:"webauthn,omitempty"`
+}
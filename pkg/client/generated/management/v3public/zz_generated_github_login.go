@@ -1,16 +1,24 @@
 package client
 
 const (
-	GithubLoginType              = "githubLogin"
-	GithubLoginFieldCode         = "code"
-	GithubLoginFieldDescription  = "description"
-	GithubLoginFieldResponseType = "responseType"
-	GithubLoginFieldTTLMillis    = "ttl"
+	GithubLoginType                 = "githubLogin"
+	GithubLoginFieldCaptchaResponse = "captchaResponse"
+	GithubLoginFieldCode            = "code"
+	GithubLoginFieldDescription     = "description"
+	GithubLoginFieldRefreshToken    = "refreshToken"
+	GithubLoginFieldResponseType    = "responseType"
+	GithubLoginFieldTOTPCode        = "totpCode"
+	GithubLoginFieldTTLMillis       = "ttl"
+	GithubLoginFieldWebAuthn        = "webauthn"
 )
 
 type GithubLogin struct {
-	Code         string `json:"code,omitempty" yaml:"code,omitempty"`
-	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
-	ResponseType string `json:"responseType,omitempty" yaml:"responseType,omitempty"`
-	TTLMillis    int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	CaptchaResponse string             `json:"captchaResponse,omitempty" yaml:"captchaResponse,omitempty"`
+	Code            string             `json:"code,omitempty" yaml:"code,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	RefreshToken    bool               `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	ResponseType    string             `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	TOTPCode        string             `json:"totpCode,omitempty" yaml:"totpCode,omitempty"`
+	TTLMillis       int64              `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	WebAuthn        *WebAuthnAssertion `json:"webauthn,omitempty" yaml:"webauthn,omitempty"`
 }
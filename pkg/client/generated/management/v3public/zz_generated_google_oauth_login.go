@@ -1,16 +1,24 @@
 package client
 
 const (
-	GoogleOauthLoginType              = "googleOauthLogin"
-	GoogleOauthLoginFieldCode         = "code"
-	GoogleOauthLoginFieldDescription  = "description"
-	GoogleOauthLoginFieldResponseType = "responseType"
-	GoogleOauthLoginFieldTTLMillis    = "ttl"
+	GoogleOauthLoginType                 = "googleOauthLogin"
+	GoogleOauthLoginFieldCaptchaResponse = "captchaResponse"
+	GoogleOauthLoginFieldCode            = "code"
+	GoogleOauthLoginFieldDescription     = "description"
+	GoogleOauthLoginFieldRefreshToken    = "refreshToken"
+	GoogleOauthLoginFieldResponseType    = "responseType"
+	GoogleOauthLoginFieldTOTPCode        = "totpCode"
+	GoogleOauthLoginFieldTTLMillis       = "ttl"
+	GoogleOauthLoginFieldWebAuthn        = "webauthn"
 )
 
 type GoogleOauthLogin struct {
-	Code         string `json:"code,omitempty" yaml:"code,omitempty"`
-	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
-	ResponseType string `json:"responseType,omitempty" yaml:"responseType,omitempty"`
-	TTLMillis    int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	CaptchaResponse string             `json:"captchaResponse,omitempty" yaml:"captchaResponse,omitempty"`
+	Code            string             `json:"code,omitempty" yaml:"code,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	RefreshToken    bool               `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	ResponseType    string             `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	TOTPCode        string             `json:"totpCode,omitempty" yaml:"totpCode,omitempty"`
+	TTLMillis       int64              `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	WebAuthn        *WebAuthnAssertion `json:"webauthn,omitempty" yaml:"webauthn,omitempty"`
 }
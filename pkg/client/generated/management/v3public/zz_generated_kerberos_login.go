@@ -0,0 +1,22 @@
+package client
+
+const (
+	KerberosLoginType                 = "kerberosLogin"
+	KerberosLoginFieldCaptchaResponse = "captchaResponse"
+	KerberosLoginFieldDescription     = "description"
+	KerberosLoginFieldRefreshToken    = "refreshToken"
+	KerberosLoginFieldResponseType    = "responseType"
+	KerberosLoginFieldTOTPCode        = "totpCode"
+	KerberosLoginFieldTTLMillis       = "ttl"
+	KerberosLoginFieldWebAuthn        = "webauthn"
+)
+
+type KerberosLogin struct {
+	CaptchaResponse string             `json:"captchaResponse,omitempty" yaml:"captchaResponse,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	RefreshToken    bool               `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	ResponseType    string             `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	TOTPCode        string             `json:"totpCode,omitempty" yaml:"totpCode,omitempty"`
+	TTLMillis       int64              `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	WebAuthn        *WebAuthnAssertion `json:"webauthn,omitempty" yaml:"webauthn,omitempty"`
+}
@@ -1,16 +1,24 @@
 package client
 
 const (
-	OIDCLoginType              = "oidcLogin"
-	OIDCLoginFieldCode         = "code"
-	OIDCLoginFieldDescription  = "description"
-	OIDCLoginFieldResponseType = "responseType"
-	OIDCLoginFieldTTLMillis    = "ttl"
+	OIDCLoginType                 = "oidcLogin"
+	OIDCLoginFieldCaptchaResponse = "captchaResponse"
+	OIDCLoginFieldCode            = "code"
+	OIDCLoginFieldDescription     = "description"
+	OIDCLoginFieldRefreshToken    = "refreshToken"
+	OIDCLoginFieldResponseType    = "responseType"
+	OIDCLoginFieldTOTPCode        = "totpCode"
+	OIDCLoginFieldTTLMillis       = "ttl"
+	OIDCLoginFieldWebAuthn        = "webauthn"
 )
 
 type OIDCLogin struct {
-	Code         string `json:"code,omitempty" yaml:"code,omitempty"`
-	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
-	ResponseType string `json:"responseType,omitempty" yaml:"responseType,omitempty"`
-	TTLMillis    int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	CaptchaResponse string             `json:"captchaResponse,omitempty" yaml:"captchaResponse,omitempty"`
+	Code            string             `json:"code,omitempty" yaml:"code,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	RefreshToken    bool               `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	ResponseType    string             `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	TOTPCode        string             `json:"totpCode,omitempty" yaml:"totpCode,omitempty"`
+	TTLMillis       int64              `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	WebAuthn        *WebAuthnAssertion `json:"webauthn,omitempty" yaml:"webauthn,omitempty"`
 }
@@ -3,6 +3,7 @@
 const (
 	TokenType                    = "token"
 	TokenFieldActivityLastSeenAt = "activityLastSeenAt"
+	TokenFieldAllowedCIDRs       = "allowedCIDRs"
 	TokenFieldAnnotations        = "annotations"
 	TokenFieldAuthProvider       = "authProvider"
 	TokenFieldClusterID          = "clusterId"
@@ -21,6 +22,7 @@
 	TokenFieldOwnerReferences    = "ownerReferences"
 	TokenFieldProviderInfo       = "providerInfo"
 	TokenFieldRemoved            = "removed"
+	TokenFieldScope              = "scope"
 	TokenFieldTTLMillis          = "ttl"
 	TokenFieldToken              = "token"
 	TokenFieldUUID               = "uuid"
@@ -30,6 +32,7 @@
 
 type Token struct {
 	ActivityLastSeenAt string            `json:"activityLastSeenAt,omitempty" yaml:"activityLastSeenAt,omitempty"`
+	AllowedCIDRs       []string          `json:"allowedCIDRs,omitempty" yaml:"allowedCIDRs,omitempty"`
 	Annotations        map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	AuthProvider       string            `json:"authProvider,omitempty" yaml:"authProvider,omitempty"`
 	ClusterID          string            `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
@@ -48,6 +51,7 @@ type Token struct {
 	OwnerReferences    []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
 	ProviderInfo       map[string]string `json:"providerInfo,omitempty" yaml:"providerInfo,omitempty"`
 	Removed            string            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Scope              *TokenScope       `json:"scope,omitempty" yaml:"scope,omitempty"`
 	TTLMillis          int64             `json:"ttl,omitempty" yaml:"ttl,omitempty"`
 	Token              string            `json:"token,omitempty" yaml:"token,omitempty"`
 	UUID               string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
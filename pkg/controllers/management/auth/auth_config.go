@@ -4,10 +4,16 @@
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/rancher/pkg/auth/authevents"
 	"github.com/rancher/rancher/pkg/auth/cleanup"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
+	"github.com/rancher/rancher/pkg/auth/securityevents"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/sirupsen/logrus"
@@ -46,6 +52,10 @@ type authConfigController struct {
 	// that, the regular client will "eat" those internal-only fields, so in this case, we use
 	// the unstructured client, losing some validation, but gaining the flexibility we require.
 	authConfigsUnstructured objectclient.GenericClient
+
+	// configs are the configurations of the auth configs last seen, to record their changes.
+	configsMu sync.Mutex
+	configs   map[string]map[string]any
 }
 
 func newAuthConfigController(context context.Context, mgmt *config.ManagementContext, scaledContext *config.ScaledContext) *authConfigController {
@@ -97,6 +107,7 @@ func (ac *authConfigController) sync(key string, obj *v3.AuthConfig) (runtime.Ob
 	if err != nil {
 		return nil, err
 	}
+	ac.recordChange(obj.Name, unstructuredObj)
 
 	value := obj.Annotations[CleanupAnnotation]
 	if value == "" {
@@ -149,6 +160,53 @@ func (ac *authConfigController) sync(key string, obj *v3.AuthConfig) (runtime.Ob
 	return obj, nil
 }
 
+// recordChange records the fields of the configuration of the auth config changed since it was last seen. The first
+// configuration seen after Rancher starts is the baseline, the changes made while it wasn't running aren't recorded.
+func (ac *authConfigController) recordChange(name string, unstructuredObj *unstructured.Unstructured) {
+	config := unstructuredObj.DeepCopy().UnstructuredContent()
+	delete(config, "metadata")
+	delete(config, "status")
+
+	ac.configsMu.Lock()
+	if ac.configs == nil {
+		ac.configs = map[string]map[string]any{}
+	}
+	previous, seen := ac.configs[name]
+	ac.configs[name] = config
+	ac.configsMu.Unlock()
+	if !seen {
+		return
+	}
+
+	var changed []string
+	for field, value := range config {
+		if !reflect.DeepEqual(previous[field], value) {
+			changed = append(changed, field)
+		}
+	}
+	for field := range previous {
+		if _, ok := config[field]; !ok {
+			changed = append(changed, field)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	sort.Strings(changed)
+	detail := "changed fields: " + strings.Join(changed, ", ")
+	authevents.Record(authevents.Event{
+		Type:     authevents.AuthConfigChanged,
+		Provider: name,
+		Target:   name,
+		Detail:   detail,
+	})
+	securityevents.Record(securityevents.Event{
+		Type:   securityevents.AuthConfigChanged,
+		Target: name,
+		Detail: detail,
+	})
+}
+
 func (ac *authConfigController) updateAuthConfig(unstructuredObj *unstructured.Unstructured, obj *v3.AuthConfig) (*v3.AuthConfig, error) {
 	uobj, err := ac.authConfigsUnstructured.Update(obj.Name, unstructuredObj)
 	if err != nil {
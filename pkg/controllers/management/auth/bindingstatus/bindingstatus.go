@@ -0,0 +1,227 @@
+// Package bindingstatus summarizes whether the ClusterRoleTemplateBindings and ProjectRoleTemplateBindings took effect
+// in the status conditions of the bindings: whether their subject was resolved, and whether they were rendered in the
+// downstream cluster, are waiting for the cluster, or failed and why. The bindings annotated with RetryAnnotation are
+// reconciled again right away.
+package bindingstatus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/status"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	crtbHandler         = "mgmt-binding-status-crtb"
+	prtbHandler         = "mgmt-binding-status-prtb"
+	crtbClusterEnqueuer = "mgmt-binding-status-cluster-crtb"
+	prtbClusterEnqueuer = "mgmt-binding-status-cluster-prtb"
+
+	// ConditionPrincipalResolved tells whether the subject of a binding was resolved to a user or a group.
+	ConditionPrincipalResolved = "PrincipalResolved"
+	// ConditionRendered tells whether the RBAC objects of a binding were created in its downstream cluster.
+	ConditionRendered = "Rendered"
+
+	ReasonPrincipalResolved   = "PrincipalResolved"
+	ReasonPrincipalUnresolved = "PrincipalUnresolved"
+	ReasonRendered            = "Rendered"
+	ReasonAwaitingCluster     = "AwaitingCluster"
+
+	// RetryAnnotation set on a binding, to any value, reconciles it again right away in the management and the
+	// downstream clusters, instead of waiting for the backoff of its failed reconciliations. It's removed once
+	// noticed.
+	RetryAnnotation = "auth.cattle.io/retry"
+)
+
+type handler struct {
+	crtbs        mgmtcontrollers.ClusterRoleTemplateBindingController
+	crtbCache    mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbs        mgmtcontrollers.ProjectRoleTemplateBindingController
+	prtbCache    mgmtcontrollers.ProjectRoleTemplateBindingCache
+	clusterCache mgmtcontrollers.ClusterCache
+	s            *status.Status
+}
+
+// Register registers the handlers summarizing the status of the bindings, and enqueues the bindings of the clusters
+// on their changes, as whether the bindings are waiting for them depends on them being ready.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	mgmt := management.Wrangler.Mgmt
+	h := &handler{
+		crtbs:        mgmt.ClusterRoleTemplateBinding(),
+		crtbCache:    mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbs:        mgmt.ProjectRoleTemplateBinding(),
+		prtbCache:    mgmt.ProjectRoleTemplateBinding().Cache(),
+		clusterCache: mgmt.Cluster().Cache(),
+		s:            status.NewStatus(),
+	}
+	mgmt.ClusterRoleTemplateBinding().OnChange(ctx, crtbHandler, h.onCRTBChange)
+	mgmt.ProjectRoleTemplateBinding().OnChange(ctx, prtbHandler, h.onPRTBChange)
+	relatedresource.Watch(ctx, crtbClusterEnqueuer, h.enqueueCRTBs, mgmt.ClusterRoleTemplateBinding(), mgmt.Cluster())
+	relatedresource.Watch(ctx, prtbClusterEnqueuer, h.enqueuePRTBs, mgmt.ProjectRoleTemplateBinding(), mgmt.Cluster())
+}
+
+func (h *handler) onCRTBChange(_ string, crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+	if crtb == nil || crtb.DeletionTimestamp != nil {
+		return crtb, nil
+	}
+	if _, ok := crtb.Annotations[RetryAnnotation]; ok {
+		crtb = crtb.DeepCopy()
+		delete(crtb.Annotations, RetryAnnotation)
+		logrus.Infof("bindingstatus: retrying ClusterRoleTemplateBinding %s/%s", crtb.Namespace, crtb.Name)
+		return h.crtbs.Update(crtb)
+	}
+
+	conditions, err := h.conditions(crtb.ClusterName, crtb.Generation, subject{
+		userName:           crtb.UserName,
+		userPrincipalName:  crtb.UserPrincipalName,
+		groupName:          crtb.GroupName,
+		groupPrincipalName: crtb.GroupPrincipalName,
+	}, crtb.Status.ObservedGenerationRemote, crtb.Status.LocalConditions, crtb.Status.RemoteConditions)
+	if err != nil {
+		return crtb, err
+	}
+	if status.CompareConditions(crtb.Status.Conditions, conditions) {
+		return crtb, nil
+	}
+	status.KeepLastTransitionTimeIfConditionHasNotChanged(conditions, crtb.Status.Conditions)
+	crtb = crtb.DeepCopy()
+	crtb.Status.Conditions = conditions
+	return h.crtbs.UpdateStatus(crtb)
+}
+
+func (h *handler) onPRTBChange(_ string, prtb *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+	if prtb == nil || prtb.DeletionTimestamp != nil {
+		return prtb, nil
+	}
+	if _, ok := prtb.Annotations[RetryAnnotation]; ok {
+		prtb = prtb.DeepCopy()
+		delete(prtb.Annotations, RetryAnnotation)
+		logrus.Infof("bindingstatus: retrying ProjectRoleTemplateBinding %s/%s", prtb.Namespace, prtb.Name)
+		return h.prtbs.Update(prtb)
+	}
+
+	clusterName, _, _ := strings.Cut(prtb.ProjectName, ":")
+	conditions, err := h.conditions(clusterName, prtb.Generation, subject{
+		userName:           prtb.UserName,
+		userPrincipalName:  prtb.UserPrincipalName,
+		groupName:          prtb.GroupName,
+		groupPrincipalName: prtb.GroupPrincipalName,
+		serviceAccount:     prtb.ServiceAccount,
+	}, prtb.Status.ObservedGenerationRemote, nil, prtb.Status.RemoteConditions)
+	if err != nil {
+		return prtb, err
+	}
+	if status.CompareConditions(prtb.Status.Conditions, conditions) {
+		return prtb, nil
+	}
+	status.KeepLastTransitionTimeIfConditionHasNotChanged(conditions, prtb.Status.Conditions)
+	prtb = prtb.DeepCopy()
+	prtb.Status.Conditions = conditions
+	return h.prtbs.UpdateStatus(prtb)
+}
+
+// subject is the subject of a binding.
+type subject struct {
+	userName           string
+	userPrincipalName  string
+	groupName          string
+	groupPrincipalName string
+	serviceAccount     string
+}
+
+// conditions returns the conditions of a binding of the cluster, given its generation, its subject, the generation
+// observed by the downstream controller and the conditions of the management and the downstream controllers.
+func (h *handler) conditions(clusterName string, generation int64, sub subject, observedGenerationRemote int64, localConditions, remoteConditions []metav1.Condition) ([]metav1.Condition, error) {
+	var conditions []metav1.Condition
+
+	principalErr := unresolvedPrincipal(sub)
+	reason := ReasonPrincipalResolved
+	if principalErr != nil {
+		reason = ReasonPrincipalUnresolved
+	}
+	h.s.AddCondition(&conditions, metav1.Condition{Type: ConditionPrincipalResolved}, reason, principalErr)
+
+	rendered := metav1.Condition{Type: ConditionRendered}
+	if principalErr != nil {
+		h.s.AddCondition(&conditions, rendered, ReasonPrincipalUnresolved, principalErr)
+		return conditions, nil
+	}
+	for _, controllerConditions := range [][]metav1.Condition{localConditions, remoteConditions} {
+		for _, condition := range controllerConditions {
+			if condition.Status == metav1.ConditionFalse {
+				h.s.AddCondition(&conditions, rendered, condition.Reason, fmt.Errorf("%s", condition.Message))
+				return conditions, nil
+			}
+		}
+	}
+	if observedGenerationRemote >= generation {
+		h.s.AddCondition(&conditions, rendered, ReasonRendered, nil)
+		return conditions, nil
+	}
+
+	cluster, err := h.clusterCache.Get(clusterName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("getting cluster %s: %w", clusterName, err)
+	}
+	switch {
+	case cluster == nil || apierrors.IsNotFound(err):
+		err = fmt.Errorf("cluster %s doesn't exist", clusterName)
+	case cluster.DeletionTimestamp != nil:
+		err = fmt.Errorf("cluster %s is being deleted", clusterName)
+	case !v3.ClusterConditionReady.IsTrue(cluster):
+		err = fmt.Errorf("cluster %s isn't ready", clusterName)
+	default:
+		err = fmt.Errorf("waiting for cluster %s to render the binding", clusterName)
+	}
+	h.s.AddCondition(&conditions, rendered, ReasonAwaitingCluster, err)
+	return conditions, nil
+}
+
+// unresolvedPrincipal returns an error if the subject of a binding wasn't resolved to a user or a group.
+func unresolvedPrincipal(sub subject) error {
+	if sub.userName != "" || sub.groupName != "" || sub.groupPrincipalName != "" || sub.serviceAccount != "" {
+		return nil
+	}
+	if sub.userPrincipalName != "" {
+		return fmt.Errorf("user principal %s wasn't resolved to a user", sub.userPrincipalName)
+	}
+	return fmt.Errorf("the binding has no subject")
+}
+
+// enqueueCRTBs enqueues the ClusterRoleTemplateBindings of the cluster, in its namespace.
+func (h *handler) enqueueCRTBs(_, name string, _ runtime.Object) ([]relatedresource.Key, error) {
+	crtbs, err := h.crtbCache.List(name, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing the ClusterRoleTemplateBindings of cluster %s: %w", name, err)
+	}
+	keys := make([]relatedresource.Key, 0, len(crtbs))
+	for _, crtb := range crtbs {
+		keys = append(keys, relatedresource.Key{Namespace: crtb.Namespace, Name: crtb.Name})
+	}
+	return keys, nil
+}
+
+// enqueuePRTBs enqueues the ProjectRoleTemplateBindings of the projects of the cluster.
+func (h *handler) enqueuePRTBs(_, name string, _ runtime.Object) ([]relatedresource.Key, error) {
+	prtbs, err := h.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ProjectRoleTemplateBindings: %w", err)
+	}
+	var keys []relatedresource.Key
+	for _, prtb := range prtbs {
+		if strings.HasPrefix(prtb.ProjectName, name+":") {
+			keys = append(keys, relatedresource.Key{Namespace: prtb.Namespace, Name: prtb.Name})
+		}
+	}
+	return keys, nil
+}
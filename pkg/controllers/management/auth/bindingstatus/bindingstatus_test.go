@@ -0,0 +1,247 @@
+package bindingstatus
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/status"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func clusterCache(ctrl *gomock.Controller) *fake.MockNonNamespacedCacheInterface[*v3.Cluster] {
+	cache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	cache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.Cluster, error) {
+		cluster := &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		switch name {
+		case "c-ready":
+			cluster.Status.Conditions = []v3.ClusterCondition{{Type: "Ready", Status: corev1.ConditionTrue}}
+		case "c-down":
+			cluster.Status.Conditions = []v3.ClusterCondition{{Type: "Ready", Status: corev1.ConditionFalse}}
+		default:
+			return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "clusters"}, name)
+		}
+		return cluster, nil
+	}).AnyTimes()
+	return cache
+}
+
+func reasons(conditions []metav1.Condition) map[string]string {
+	reasons := map[string]string{}
+	for _, condition := range conditions {
+		reasons[condition.Type] = string(condition.Status) + "/" + condition.Reason
+	}
+	return reasons
+}
+
+func TestOnCRTBChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		crtb     *v3.ClusterRoleTemplateBinding
+		expected map[string]string
+		message  string
+	}{
+		{
+			name: "rendered",
+			crtb: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:  metav1.ObjectMeta{Generation: 2},
+				ClusterName: "c-down",
+				UserName:    "u-abcde",
+				Status:      v3.ClusterRoleTemplateBindingStatus{ObservedGenerationRemote: 2},
+			},
+			expected: map[string]string{ConditionPrincipalResolved: "True/PrincipalResolved", ConditionRendered: "True/Rendered"},
+		},
+		{
+			name: "awaiting cluster",
+			crtb: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:  metav1.ObjectMeta{Generation: 2},
+				ClusterName: "c-down",
+				UserName:    "u-abcde",
+				Status:      v3.ClusterRoleTemplateBindingStatus{ObservedGenerationRemote: 1},
+			},
+			expected: map[string]string{ConditionPrincipalResolved: "True/PrincipalResolved", ConditionRendered: "False/AwaitingCluster"},
+			message:  "cluster c-down isn't ready",
+		},
+		{
+			name: "awaiting rendering",
+			crtb: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:  metav1.ObjectMeta{Generation: 1},
+				ClusterName: "c-ready",
+				UserName:    "u-abcde",
+			},
+			expected: map[string]string{ConditionPrincipalResolved: "True/PrincipalResolved", ConditionRendered: "False/AwaitingCluster"},
+			message:  "waiting for cluster c-ready to render the binding",
+		},
+		{
+			name: "missing cluster",
+			crtb: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:  metav1.ObjectMeta{Generation: 1},
+				ClusterName: "c-gone",
+				UserName:    "u-abcde",
+			},
+			expected: map[string]string{ConditionPrincipalResolved: "True/PrincipalResolved", ConditionRendered: "False/AwaitingCluster"},
+			message:  "cluster c-gone doesn't exist",
+		},
+		{
+			name: "failed",
+			crtb: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:         metav1.ObjectMeta{Generation: 1},
+				ClusterName:        "c-ready",
+				GroupPrincipalName: "openldap_group://cn=devs",
+				Status: v3.ClusterRoleTemplateBindingStatus{
+					ObservedGenerationRemote: 1,
+					RemoteConditions: []metav1.Condition{
+						{Type: "ClusterRolesExists", Status: metav1.ConditionTrue, Reason: "ClusterRolesExists"},
+						{Type: "ClusterRoleBindingsExists", Status: metav1.ConditionFalse, Reason: "FailedToCreateBindings", Message: "forbidden"},
+					},
+				},
+			},
+			expected: map[string]string{ConditionPrincipalResolved: "True/PrincipalResolved", ConditionRendered: "False/FailedToCreateBindings"},
+			message:  "forbidden",
+		},
+		{
+			name: "principal unresolved",
+			crtb: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:        metav1.ObjectMeta{Generation: 1},
+				ClusterName:       "c-ready",
+				UserPrincipalName: "openldap_user://uid=alice",
+			},
+			expected: map[string]string{ConditionPrincipalResolved: "False/PrincipalUnresolved", ConditionRendered: "False/PrincipalUnresolved"},
+			message:  "user principal openldap_user://uid=alice wasn't resolved to a user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			crtbs := fake.NewMockControllerInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+			var updated *v3.ClusterRoleTemplateBinding
+			crtbs.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+				updated = crtb
+				return crtb, nil
+			})
+
+			h := &handler{crtbs: crtbs, clusterCache: clusterCache(ctrl), s: &status.Status{TimeNow: func() time.Time { return now }}}
+			_, err := h.onCRTBChange("", tt.crtb)
+			require.NoError(t, err)
+
+			require.NotNil(t, updated)
+			assert.Equal(t, tt.expected, reasons(updated.Status.Conditions))
+			assert.Equal(t, tt.message, updated.Status.Conditions[1].Message)
+			// The conditions aren't updated again when they didn't change.
+			_, err = h.onCRTBChange("", updated)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOnCRTBChangeKeepsTransitionTimes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	crtbs := fake.NewMockControllerInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	var updated *v3.ClusterRoleTemplateBinding
+	crtbs.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		updated = crtb
+		return crtb, nil
+	})
+
+	resolvedAt := metav1.NewTime(now.Add(-time.Hour))
+	crtb := &v3.ClusterRoleTemplateBinding{
+		ObjectMeta:  metav1.ObjectMeta{Generation: 2},
+		ClusterName: "c-ready",
+		UserName:    "u-abcde",
+		Status: v3.ClusterRoleTemplateBindingStatus{
+			ObservedGenerationRemote: 2,
+			Conditions: []metav1.Condition{
+				{Type: ConditionPrincipalResolved, Status: metav1.ConditionTrue, Reason: ReasonPrincipalResolved, LastTransitionTime: resolvedAt},
+				{Type: ConditionRendered, Status: metav1.ConditionFalse, Reason: ReasonAwaitingCluster, Message: "cluster c-ready isn't ready", LastTransitionTime: resolvedAt},
+			},
+		},
+	}
+	h := &handler{crtbs: crtbs, clusterCache: clusterCache(ctrl), s: &status.Status{TimeNow: func() time.Time { return now }}}
+	_, err := h.onCRTBChange("", crtb)
+	require.NoError(t, err)
+
+	require.NotNil(t, updated)
+	assert.Equal(t, resolvedAt, updated.Status.Conditions[0].LastTransitionTime)
+	assert.Equal(t, metav1.NewTime(now), updated.Status.Conditions[1].LastTransitionTime)
+}
+
+func TestOnPRTBChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prtbs := fake.NewMockControllerInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	var updated *v3.ProjectRoleTemplateBinding
+	prtbs.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(prtb *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+		updated = prtb
+		return prtb, nil
+	})
+
+	h := &handler{prtbs: prtbs, clusterCache: clusterCache(ctrl), s: &status.Status{TimeNow: func() time.Time { return now }}}
+	_, err := h.onPRTBChange("", &v3.ProjectRoleTemplateBinding{
+		ObjectMeta:  metav1.ObjectMeta{Generation: 1},
+		ProjectName: "c-down:p-fghij",
+		UserName:    "u-abcde",
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, updated)
+	assert.Equal(t, map[string]string{ConditionPrincipalResolved: "True/PrincipalResolved", ConditionRendered: "False/AwaitingCluster"}, reasons(updated.Status.Conditions))
+	assert.Equal(t, "cluster c-down isn't ready", updated.Status.Conditions[1].Message)
+}
+
+func TestRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	crtbs := fake.NewMockControllerInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	crtbs.EXPECT().Update(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		assert.Equal(t, map[string]string{"team": "a"}, crtb.Annotations)
+		return crtb, nil
+	})
+	prtbs := fake.NewMockControllerInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	prtbs.EXPECT().Update(gomock.Any()).DoAndReturn(func(prtb *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+		assert.Empty(t, prtb.Annotations)
+		return prtb, nil
+	})
+
+	h := &handler{crtbs: crtbs, prtbs: prtbs}
+	crtb := &v3.ClusterRoleTemplateBinding{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{RetryAnnotation: "1", "team": "a"}}}
+	_, err := h.onCRTBChange("", crtb)
+	require.NoError(t, err)
+	assert.Contains(t, crtb.Annotations, RetryAnnotation)
+
+	_, err = h.onPRTBChange("", &v3.ProjectRoleTemplateBinding{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{RetryAnnotation: ""}}})
+	require.NoError(t, err)
+}
+
+func TestEnqueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("c-abcde", labels.Everything()).Return([]*v3.ClusterRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "c-abcde", Name: "crtb-a"}},
+	}, nil)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ProjectRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "p-fghij", Name: "prtb-a"}, ProjectName: "c-abcde:p-fghij"},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "p-klmno", Name: "prtb-b"}, ProjectName: "c-abcdef:p-klmno"},
+	}, nil)
+
+	h := &handler{crtbCache: crtbCache, prtbCache: prtbCache}
+	keys, err := h.enqueueCRTBs("", "c-abcde", &v3.Cluster{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "c-abcde", keys[0].Namespace)
+	assert.Equal(t, "crtb-a", keys[0].Name)
+
+	keys, err = h.enqueuePRTBs("", "c-abcde", &v3.Cluster{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "p-fghij", keys[0].Namespace)
+	assert.Equal(t, "prtb-a", keys[0].Name)
+}
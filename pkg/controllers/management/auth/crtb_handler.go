@@ -12,6 +12,7 @@
 	controllersv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	typesrbacv1 "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1"
+	"github.com/rancher/rancher/pkg/metrics"
 	pkgrbac "github.com/rancher/rancher/pkg/rbac"
 	"github.com/rancher/rancher/pkg/user"
 	"github.com/sirupsen/logrus"
@@ -93,20 +94,26 @@ type crtbLifecycle struct {
 }
 
 func (c *crtbLifecycle) Create(obj *v3.ClusterRoleTemplateBinding) (runtime.Object, error) {
+	started, clusterName := time.Now(), obj.ClusterName
 	var localConditions []metav1.Condition
 	obj, err := c.reconcileSubject(obj, &localConditions)
-	return obj, errors.Join(err,
+	err = errors.Join(err,
 		c.reconcileBindings(obj, &localConditions),
 		c.updateStatus(obj, localConditions))
+	metrics.ObserveRBACReconcile(ctrbMGMTController, clusterName, started, localConditions, err)
+	return obj, err
 }
 
 func (c *crtbLifecycle) Updated(obj *v3.ClusterRoleTemplateBinding) (runtime.Object, error) {
+	started, clusterName := time.Now(), obj.ClusterName
 	var localConditions []metav1.Condition
 	obj, err := c.reconcileSubject(obj, &localConditions)
-	return obj, errors.Join(err,
+	err = errors.Join(err,
 		c.reconcileLabels(obj, &localConditions),
 		c.reconcileBindings(obj, &localConditions),
 		c.updateStatus(obj, localConditions))
+	metrics.ObserveRBACReconcile(ctrbMGMTController, clusterName, started, localConditions, err)
+	return obj, err
 }
 
 func (c *crtbLifecycle) Remove(obj *v3.ClusterRoleTemplateBinding) (runtime.Object, error) {
@@ -8,6 +8,7 @@
 	"time"
 
 	apisv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/securityevents"
 	"github.com/rancher/rancher/pkg/clustermanager"
 	"github.com/rancher/rancher/pkg/controllers"
 	"github.com/rancher/rancher/pkg/controllers/status"
@@ -64,6 +65,7 @@
 
 const (
 	crbNameAnnotation             = "authz.management.cattle.io/crb-name"
+	creatorIDAnnotation           = "field.cattle.io/creatorId"
 	crtbGrbOwnerIndex             = "authz.management.cattle.io/crtb-owner"
 	crbNamePrefix                 = "cattle-globalrolebinding-"
 	localClusterName              = "local"
@@ -150,10 +152,37 @@ func (grb *globalRoleBindingLifecycle) Create(obj *v3.GlobalRoleBinding) (runtim
 		grb.fleetPermissionsHandler.reconcileFleetWorkspacePermissionsBindings(obj, &localConditions),
 		grb.updateStatus(obj, localConditions),
 	)
+	// The binding is only recorded once reconciled, as Create is retried until then.
+	if returnError == nil {
+		grb.recordAdminBinding(obj)
+	}
 
 	return obj, returnError
 }
 
+// recordAdminBinding records the binding of a user or group to an administrator global role as a security event.
+func (grb *globalRoleBindingLifecycle) recordAdminBinding(obj *v3.GlobalRoleBinding) {
+	isAdmin, err := rbac.IsAdminGlobalRole(obj.GlobalRoleName, grb.grLister)
+	if err != nil {
+		logrus.Errorf("[%v] failed to check whether GlobalRole %s is an administrator role: %v", grbController, obj.GlobalRoleName, err)
+		return
+	}
+	if !isAdmin {
+		return
+	}
+	subject := "user " + obj.UserName
+	if obj.GroupPrincipalName != "" {
+		subject = "group " + obj.GroupPrincipalName
+	}
+	securityevents.Record(securityevents.Event{
+		Type:     securityevents.AdminBindingCreated,
+		UserName: obj.UserName,
+		Actor:    obj.Annotations[creatorIDAnnotation],
+		Target:   obj.Name,
+		Detail:   fmt.Sprintf("global role %s bound to %s", obj.GlobalRoleName, subject),
+	})
+}
+
 func (grb *globalRoleBindingLifecycle) Updated(obj *v3.GlobalRoleBinding) (runtime.Object, error) {
 	localConditions := []metav1.Condition{}
 	obj, err := grb.reconcileSubject(obj, &localConditions)
@@ -285,7 +314,7 @@ func (grb *globalRoleBindingLifecycle) reconcileClusterPermissions(globalRoleBin
 	for _, cluster := range clusters {
 		// we don't sync permissions for the local cluster, but we do want to purge user-created permissions
 		if cluster.Name == localClusterName {
-			err := grb.purgeCorruptRoles(nil, cluster, globalRoleBinding)
+			err := grb.purgeCorruptRoles(nil, nil, cluster, globalRoleBinding)
 			if err != nil {
 				// failure to remove bad bindings shouldn't affect our ability to sync new permissions, so we log and keep processing
 				logrus.Errorf("unable to purge roles for cluster %s and grb %s, some bindings may remain: %s", cluster.Name, globalRoleBinding.Name, err.Error())
@@ -294,13 +323,13 @@ func (grb *globalRoleBindingLifecycle) reconcileClusterPermissions(globalRoleBin
 			// inheritedClusterRoles only apply on non-local clusters, so skip the local cluster
 			continue
 		}
-		err := grb.purgeCorruptRoles(globalRole.InheritedClusterRoles, cluster, globalRoleBinding)
+		err := grb.purgeCorruptRoles(globalRole.InheritedClusterRoles, globalRole.InheritedClusterRolesNamespaceSelector, cluster, globalRoleBinding)
 		if err != nil {
 			// failure to remove bad bindings shouldn't affect our ability to sync new permissions, so we log and keep processing
 			logrus.Errorf("unable to purge roles for cluster %s and grb %s, some bindings may remain: %s", cluster.Name, globalRoleBinding.Name, err.Error())
 			missedClusters = true
 		}
-		missingRTs, err := grb.findMissingRTs(globalRole.InheritedClusterRoles, cluster, globalRoleBinding)
+		missingRTs, err := grb.findMissingRTs(globalRole.InheritedClusterRoles, globalRole.InheritedClusterRolesNamespaceSelector, cluster, globalRoleBinding)
 		if err != nil {
 			logrus.Errorf("unable to find missing roles for cluster %s and grb %s, some permissions may be missing: %s", cluster.Name, globalRoleBinding.Name, err.Error())
 			missedClusters = true
@@ -333,6 +362,7 @@ func (grb *globalRoleBindingLifecycle) reconcileClusterPermissions(globalRoleBin
 				RoleTemplateName:   wantRT,
 				UserName:           globalRoleBinding.UserName,
 				GroupPrincipalName: globalRoleBinding.GroupPrincipalName,
+				NamespaceSelector:  globalRole.InheritedClusterRolesNamespaceSelector.DeepCopy(),
 			})
 			// we don't immediately return so that we can create as many CRTBs as we can
 			if err != nil {
@@ -353,9 +383,9 @@ func (grb *globalRoleBindingLifecycle) reconcileClusterPermissions(globalRoleBin
 }
 
 // purgeCorruptRoles removes any CRTBs which were created for this role in the past, but are no longer valid, either
-// because they aren't for a currently requested RoleTemplate, or because they have been corrupted by user intervention.
-// Will return an error if a binding can't be deleted
-func (grb *globalRoleBindingLifecycle) purgeCorruptRoles(wantRTs []string, cluster *v3.Cluster, binding *v3.GlobalRoleBinding) error {
+// because they aren't for a currently requested RoleTemplate, or because they have been corrupted by user intervention,
+// or because they aren't scoped to the namespaces currently selected. Will return an error if a binding can't be deleted
+func (grb *globalRoleBindingLifecycle) purgeCorruptRoles(wantRTs []string, namespaceSelector *metav1.LabelSelector, cluster *v3.Cluster, binding *v3.GlobalRoleBinding) error {
 	currentCRTBs, err := grb.crtbCache.GetByIndex(crtbGrbOwnerIndex, fmt.Sprintf("%s/%s", cluster.Name, binding.Name))
 	if err != nil {
 		return fmt.Errorf("unable to get CRTBs for cluster %s: %w", cluster.Name, err)
@@ -373,7 +403,7 @@ func (grb *globalRoleBindingLifecycle) purgeCorruptRoles(wantRTs []string, clust
 		_, seen := seenRTs[crtb.RoleTemplateName]
 		// if the RT isn't one of the ones that we requested, or is corrupt, or refers to the same RT as a prior
 		// valid RT, then we remove it.
-		if !foundRT || !isCRTBValid(crtb, cluster, binding) || seen {
+		if !foundRT || !isCRTBValid(crtb, namespaceSelector, cluster, binding) || seen {
 			// CRTBs can't update some of these fields, so the safest method is to delete/re-create
 			err := grb.crtbClient.DeleteNamespaced(crtb.Namespace, crtb.Name, &metav1.DeleteOptions{})
 			if err != nil {
@@ -390,7 +420,7 @@ func (grb *globalRoleBindingLifecycle) purgeCorruptRoles(wantRTs []string, clust
 }
 
 // findMissingRTs finds which RoleTemplates were in wantRTs but don't have a valid binding for this cluster yet
-func (grb *globalRoleBindingLifecycle) findMissingRTs(wantRTs []string, cluster *v3.Cluster, binding *v3.GlobalRoleBinding) ([]string, error) {
+func (grb *globalRoleBindingLifecycle) findMissingRTs(wantRTs []string, namespaceSelector *metav1.LabelSelector, cluster *v3.Cluster, binding *v3.GlobalRoleBinding) ([]string, error) {
 	currentRTs := map[string]struct{}{}
 	for _, wantRT := range wantRTs {
 		currentRTs[wantRT] = struct{}{}
@@ -401,7 +431,7 @@ func (grb *globalRoleBindingLifecycle) findMissingRTs(wantRTs []string, cluster
 	}
 	for _, crtb := range currentCRTBs {
 		_, rtOk := currentRTs[crtb.RoleTemplateName]
-		if rtOk && isCRTBValid(crtb, cluster, binding) {
+		if rtOk && isCRTBValid(crtb, namespaceSelector, cluster, binding) {
 			delete(currentRTs, crtb.RoleTemplateName)
 		}
 	}
@@ -680,12 +710,13 @@ func (c *globalRoleBindingLifecycle) updateStatus(grb *apisv3.GlobalRoleBinding,
 	})
 }
 
-// isCRTBValid determines if a given CRTB is up to date for a given cluster and owning global role binding. Should
-// only be used in the context of CRTBs owned by GRBs
-func isCRTBValid(crtb *v3.ClusterRoleTemplateBinding, cluster *v3.Cluster, binding *v3.GlobalRoleBinding) bool {
+// isCRTBValid determines if a given CRTB is up to date for a given namespace selector, cluster and owning global role
+// binding. Should only be used in the context of CRTBs owned by GRBs
+func isCRTBValid(crtb *v3.ClusterRoleTemplateBinding, namespaceSelector *metav1.LabelSelector, cluster *v3.Cluster, binding *v3.GlobalRoleBinding) bool {
 	return crtb != nil && cluster != nil && binding != nil &&
 		crtb.ClusterName == cluster.Name &&
 		crtb.UserName == binding.UserName &&
 		crtb.GroupPrincipalName == binding.GroupPrincipalName &&
+		reflect.DeepEqual(crtb.NamespaceSelector, namespaceSelector) &&
 		crtb.DeletionTimestamp == nil
 }
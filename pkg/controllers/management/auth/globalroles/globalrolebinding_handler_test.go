@@ -51,6 +51,15 @@
 			"wrong-cluster-name", "wrong-user-name", "wrong-group-name",
 			"deleting", "duplicate"},
 	}
+	namespaceSelectorTestGR = v3.GlobalRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "namespace-selector-test-gr",
+		},
+		InheritedClusterRoles: []string{"already-exists", "view"},
+		InheritedClusterRolesNamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"platform": "true"},
+		},
+	}
 	notLocalCluster = v3.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "not-local",
@@ -1437,6 +1446,8 @@ func Test_reconcileClusterPermissions(t *testing.T) {
 			return &missingInheritTestGR, nil
 		case purgeTestGR.Name:
 			return &purgeTestGR, nil
+		case namespaceSelectorTestGR.Name:
+			return &namespaceSelectorTestGR, nil
 		default:
 			return nil, fmt.Errorf("not found")
 		}
@@ -1799,6 +1810,69 @@ func Test_reconcileClusterPermissions(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "inherited cluster roles scoped to namespaces, recreate unscoped roles",
+			stateSetup: func(state grbTestState) {
+				state.grListerMock.GetFunc = grListerGetFunc
+				state.crtbCacheMock.EXPECT().GetByIndex(crtbGrbOwnerIndex, "local/test-grb").Return([]*v3.ClusterRoleTemplateBinding{}, nil)
+				state.crtbCacheMock.EXPECT().GetByIndex(crtbGrbOwnerIndex, "not-local/test-grb").Return([]*v3.ClusterRoleTemplateBinding{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "crtb-grb-already-exists",
+							Namespace: "not-local",
+							Labels: map[string]string{
+								grbOwnerLabel: "test-grb",
+							},
+						},
+						RoleTemplateName: "already-exists",
+						ClusterName:      "not-local",
+						UserName:         "test-user",
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "crtb-grb-view",
+							Namespace: "not-local",
+							Labels: map[string]string{
+								grbOwnerLabel: "test-grb",
+							},
+						},
+						RoleTemplateName: "view",
+						ClusterName:      "not-local",
+						UserName:         "test-user",
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"platform": "true"},
+						},
+					},
+				}, nil).Times(2)
+				state.crtbClientMock.CreateFunc = func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+					state.stateChanges.createdCRTBs = append(state.stateChanges.createdCRTBs, crtb)
+					return crtb, nil
+				}
+				state.crtbClientMock.DeleteNamespacedFunc = func(_ string, name string, _ *metav1.DeleteOptions) error {
+					state.stateChanges.deletedCRTBNames = append(state.stateChanges.deletedCRTBNames, name)
+					return nil
+				}
+				state.clusterListerMock.ListFunc = func(namespace string, selector labels.Selector) ([]*v3.Cluster, error) {
+					return []*v3.Cluster{&notLocalCluster, &localCluster}, nil
+				}
+			},
+			stateAssertions: func(stateChanges grbTestStateChanges) {
+				require.Len(stateChanges.t, stateChanges.createdCRTBs, 1)
+				crtb := stateChanges.createdCRTBs[0]
+				require.Equal(stateChanges.t, "already-exists", crtb.RoleTemplateName)
+				require.Equal(stateChanges.t, namespaceSelectorTestGR.InheritedClusterRolesNamespaceSelector, crtb.NamespaceSelector)
+				require.Equal(stateChanges.t, []string{"crtb-grb-already-exists"}, stateChanges.deletedCRTBNames)
+			},
+			inputObject: &v3.GlobalRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-grb",
+					UID:  "1234",
+				},
+				GlobalRoleName: namespaceSelectorTestGR.Name,
+				UserName:       "test-user",
+			},
+			wantError: false,
+		},
 		{
 			name: "cluster lister error",
 			stateSetup: func(state grbTestState) {
@@ -0,0 +1,300 @@
+// Package groupmembership reconciles the group membership rules, which bind role templates to directory groups in the
+// clusters and projects matching label selectors. Rules are reconciled again when clusters and projects change, so
+// that new ones matching a rule grant its groups access without any manual setup, and when users log in with new
+// groups, for the rules selecting groups with patterns. The rules can only bind the role templates their authors could
+// bind themselves, which the Validator enforces.
+package groupmembership
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	ruleHandler           = "mgmt-group-membership-rule-handler"
+	clusterEnqueuer       = "mgmt-group-membership-rule-cluster"
+	projectEnqueuer       = "mgmt-group-membership-rule-project"
+	userAttributeEnqueuer = "mgmt-group-membership-rule-user-attribute"
+
+	// RuleLabel is set on the role template bindings of the group membership rules to the name of their rule.
+	RuleLabel = "auth.cattle.io/group-membership-rule"
+)
+
+type handler struct {
+	ruleCache          mgmtcontrollers.GroupMembershipRuleCache
+	clusterCache       mgmtcontrollers.ClusterCache
+	projectCache       mgmtcontrollers.ProjectCache
+	userAttributeCache mgmtcontrollers.UserAttributeCache
+	crtbs              mgmtcontrollers.ClusterRoleTemplateBindingClient
+	crtbCache          mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbs              mgmtcontrollers.ProjectRoleTemplateBindingClient
+	prtbCache          mgmtcontrollers.ProjectRoleTemplateBindingCache
+}
+
+// Register registers the handler of the group membership rules, and enqueues them on the changes of the clusters,
+// projects and user attributes.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	mgmt := management.Wrangler.Mgmt
+	h := &handler{
+		ruleCache:          mgmt.GroupMembershipRule().Cache(),
+		clusterCache:       mgmt.Cluster().Cache(),
+		projectCache:       mgmt.Project().Cache(),
+		userAttributeCache: mgmt.UserAttribute().Cache(),
+		crtbs:              mgmt.ClusterRoleTemplateBinding(),
+		crtbCache:          mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbs:              mgmt.ProjectRoleTemplateBinding(),
+		prtbCache:          mgmt.ProjectRoleTemplateBinding().Cache(),
+	}
+	rules := mgmt.GroupMembershipRule()
+	rules.OnChange(ctx, ruleHandler, h.OnChange)
+	relatedresource.WatchClusterScoped(ctx, clusterEnqueuer, h.enqueueRules, rules, mgmt.Cluster())
+	relatedresource.WatchClusterScoped(ctx, projectEnqueuer, h.enqueueRules, rules, mgmt.Project())
+	relatedresource.WatchClusterScoped(ctx, userAttributeEnqueuer, h.enqueuePatternRules, rules, mgmt.UserAttribute())
+}
+
+// OnChange creates the role template bindings of the rule which are missing, and deletes those the rule doesn't
+// grant anymore. The bindings are owned by the rule, and deleted along with it.
+func (h *handler) OnChange(_ string, rule *v3.GroupMembershipRule) (*v3.GroupMembershipRule, error) {
+	if rule == nil || rule.DeletionTimestamp != nil {
+		return rule, nil
+	}
+	groups, err := h.groups(rule)
+	if err != nil {
+		return rule, err
+	}
+	if err := h.reconcileCRTBs(rule, groups); err != nil {
+		return rule, fmt.Errorf("reconciling the ClusterRoleTemplateBindings of group membership rule %s: %w", rule.Name, err)
+	}
+	if err := h.reconcilePRTBs(rule, groups); err != nil {
+		return rule, fmt.Errorf("reconciling the ProjectRoleTemplateBindings of group membership rule %s: %w", rule.Name, err)
+	}
+	return rule, nil
+}
+
+// groups returns the group principals of the rule, and those of the users who logged in matching its patterns, sorted.
+// Invalid patterns are skipped.
+func (h *handler) groups(rule *v3.GroupMembershipRule) ([]string, error) {
+	set := map[string]bool{}
+	for _, group := range rule.Spec.GroupPrincipals {
+		set[group] = true
+	}
+
+	var patterns []*regexp.Regexp
+	for _, pattern := range rule.Spec.GroupPrincipalPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			logrus.Errorf("[%s] skipping invalid pattern %q of group membership rule %s: %v", ruleHandler, pattern, rule.Name, err)
+			continue
+		}
+		patterns = append(patterns, re)
+	}
+	if len(patterns) > 0 {
+		attributes, err := h.userAttributeCache.List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("listing user attributes: %w", err)
+		}
+		for _, attribute := range attributes {
+			for _, principals := range attribute.GroupPrincipals {
+				for _, principal := range principals.Items {
+					for _, re := range patterns {
+						if re.MatchString(principal.Name) {
+							set[principal.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	groups := make([]string, 0, len(set))
+	for group := range set {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+func (h *handler) reconcileCRTBs(rule *v3.GroupMembershipRule, groups []string) error {
+	desired := map[string]*v3.ClusterRoleTemplateBinding{}
+	for _, clusterRole := range rule.Spec.ClusterRoles {
+		selector, err := metav1.LabelSelectorAsSelector(&clusterRole.ClusterSelector)
+		if err != nil {
+			logrus.Errorf("[%s] skipping invalid cluster selector of group membership rule %s: %v", ruleHandler, rule.Name, err)
+			continue
+		}
+		clusters, err := h.clusterCache.List(selector)
+		if err != nil {
+			return err
+		}
+		for _, cluster := range clusters {
+			if cluster.DeletionTimestamp != nil {
+				continue
+			}
+			for _, group := range groups {
+				binding := &v3.ClusterRoleTemplateBinding{
+					ObjectMeta:         bindingMeta(rule, cluster.Name, group, clusterRole.RoleTemplateName),
+					ClusterName:        cluster.Name,
+					GroupPrincipalName: group,
+					RoleTemplateName:   clusterRole.RoleTemplateName,
+				}
+				desired[binding.Namespace+"/"+binding.Name] = binding
+			}
+		}
+	}
+
+	existing, err := h.crtbCache.List("", labels.SelectorFromSet(labels.Set{RuleLabel: rule.Name}))
+	if err != nil {
+		return err
+	}
+	for _, binding := range existing {
+		if _, ok := desired[binding.Namespace+"/"+binding.Name]; ok {
+			delete(desired, binding.Namespace+"/"+binding.Name)
+			continue
+		}
+		if !provenance.Owns(binding, provenance.SourceGroupRule) {
+			// the binding was relabeled by another source, which manages it now
+			continue
+		}
+		if err := h.crtbs.Delete(binding.Namespace, binding.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		logrus.Infof("groupmembership: rule %s revoked role %s of group %s in cluster %s", rule.Name, binding.RoleTemplateName, binding.GroupPrincipalName, binding.ClusterName)
+	}
+	for _, binding := range desired {
+		if _, err := h.crtbs.Create(binding); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		logrus.Infof("groupmembership: rule %s granted role %s to group %s in cluster %s", rule.Name, binding.RoleTemplateName, binding.GroupPrincipalName, binding.ClusterName)
+	}
+	return nil
+}
+
+func (h *handler) reconcilePRTBs(rule *v3.GroupMembershipRule, groups []string) error {
+	desired := map[string]*v3.ProjectRoleTemplateBinding{}
+	for _, projectRole := range rule.Spec.ProjectRoles {
+		clusterSelector, err := metav1.LabelSelectorAsSelector(&projectRole.ClusterSelector)
+		if err != nil {
+			logrus.Errorf("[%s] skipping invalid cluster selector of group membership rule %s: %v", ruleHandler, rule.Name, err)
+			continue
+		}
+		projectSelector, err := metav1.LabelSelectorAsSelector(&projectRole.ProjectSelector)
+		if err != nil {
+			logrus.Errorf("[%s] skipping invalid project selector of group membership rule %s: %v", ruleHandler, rule.Name, err)
+			continue
+		}
+		clusters, err := h.clusterCache.List(clusterSelector)
+		if err != nil {
+			return err
+		}
+		for _, cluster := range clusters {
+			if cluster.DeletionTimestamp != nil {
+				continue
+			}
+			projects, err := h.projectCache.List(cluster.Name, projectSelector)
+			if err != nil {
+				return err
+			}
+			for _, project := range projects {
+				if project.DeletionTimestamp != nil {
+					continue
+				}
+				for _, group := range groups {
+					binding := &v3.ProjectRoleTemplateBinding{
+						ObjectMeta:         bindingMeta(rule, project.Name, group, projectRole.RoleTemplateName),
+						ProjectName:        project.Namespace + ":" + project.Name,
+						GroupPrincipalName: group,
+						RoleTemplateName:   projectRole.RoleTemplateName,
+					}
+					desired[binding.Namespace+"/"+binding.Name] = binding
+				}
+			}
+		}
+	}
+
+	existing, err := h.prtbCache.List("", labels.SelectorFromSet(labels.Set{RuleLabel: rule.Name}))
+	if err != nil {
+		return err
+	}
+	for _, binding := range existing {
+		if _, ok := desired[binding.Namespace+"/"+binding.Name]; ok {
+			delete(desired, binding.Namespace+"/"+binding.Name)
+			continue
+		}
+		if !provenance.Owns(binding, provenance.SourceGroupRule) {
+			continue
+		}
+		if err := h.prtbs.Delete(binding.Namespace, binding.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		logrus.Infof("groupmembership: rule %s revoked role %s of group %s in project %s", rule.Name, binding.RoleTemplateName, binding.GroupPrincipalName, binding.ProjectName)
+	}
+	for _, binding := range desired {
+		if _, err := h.prtbs.Create(binding); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		logrus.Infof("groupmembership: rule %s granted role %s to group %s in project %s", rule.Name, binding.RoleTemplateName, binding.GroupPrincipalName, binding.ProjectName)
+	}
+	return nil
+}
+
+// bindingMeta returns the metadata of a binding of the rule. Its name is deterministic, so that it's only created once.
+func bindingMeta(rule *v3.GroupMembershipRule, namespace, group, roleTemplateName string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:      name.SafeConcatName("gmr", rule.Name, name.Hex(group+"/"+roleTemplateName, 10)),
+		Namespace: namespace,
+		Labels:    map[string]string{RuleLabel: rule.Name},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: v3.SchemeGroupVersion.String(),
+			Kind:       "GroupMembershipRule",
+			Name:       rule.Name,
+			UID:        rule.UID,
+		}},
+	}
+	provenance.Set(&meta, provenance.SourceGroupRule, provenance.Creator("GroupMembershipRule", rule.Name))
+	return meta
+}
+
+// enqueueRules enqueues all the rules, as the changed cluster or project may now match their selectors, or not anymore.
+func (h *handler) enqueueRules(_, _ string, _ runtime.Object) ([]relatedresource.Key, error) {
+	rules, err := h.ruleCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing group membership rules: %w", err)
+	}
+	keys := make([]relatedresource.Key, 0, len(rules))
+	for _, rule := range rules {
+		keys = append(keys, relatedresource.Key{Name: rule.Name})
+	}
+	return keys, nil
+}
+
+// enqueuePatternRules enqueues the rules with patterns, as the user attribute may have new groups matching them.
+func (h *handler) enqueuePatternRules(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	rules, err := h.ruleCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing group membership rules: %w", err)
+	}
+	var keys []relatedresource.Key
+	for _, rule := range rules {
+		if len(rule.Spec.GroupPrincipalPatterns) > 0 {
+			keys = append(keys, relatedresource.Key{Name: rule.Name})
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,165 @@
+package groupmembership
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	ldapDevs = "openldap_group://cn=devs,ou=groups,dc=example,dc=com"
+	ldapOps  = "openldap_group://cn=ops,ou=groups,dc=example,dc=com"
+)
+
+func TestGroups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	userAttributeCache.EXPECT().List(labels.Everything()).Return([]*v3.UserAttribute{
+		{
+			GroupPrincipals: map[string]v3.Principals{
+				"openldap": {Items: []v3.Principal{
+					{ObjectMeta: metav1.ObjectMeta{Name: ldapDevs}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "openldap_group://cn=admins,ou=groups,dc=example,dc=com"}},
+				}},
+			},
+		},
+		{
+			GroupPrincipals: map[string]v3.Principals{
+				"openldap": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: ldapDevs}}}},
+			},
+		},
+	}, nil)
+
+	h := &handler{userAttributeCache: userAttributeCache}
+	groups, err := h.groups(&v3.GroupMembershipRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "rule"},
+		Spec: v3.GroupMembershipRuleSpec{
+			GroupPrincipals:        []string{ldapOps},
+			GroupPrincipalPatterns: []string{"^openldap_group://cn=dev", "(invalid"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{ldapDevs, ldapOps}, groups)
+}
+
+func TestGroupsWithoutPatterns(t *testing.T) {
+	// The user attributes aren't listed without patterns.
+	h := &handler{}
+	groups, err := h.groups(&v3.GroupMembershipRule{
+		Spec: v3.GroupMembershipRuleSpec{GroupPrincipals: []string{ldapOps, ldapDevs, ldapOps}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{ldapDevs, ldapOps}, groups)
+}
+
+func TestOnChange(t *testing.T) {
+	rule := &v3.GroupMembershipRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "devs", UID: "rule-uid"},
+		Spec: v3.GroupMembershipRuleSpec{
+			GroupPrincipals: []string{ldapDevs},
+			ClusterRoles: []v3.GroupMembershipClusterRole{{
+				RoleTemplateName: "cluster-member",
+				ClusterSelector:  metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+			}},
+			ProjectRoles: []v3.GroupMembershipProjectRole{{
+				RoleTemplateName: "project-owner",
+				ClusterSelector:  metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+				ProjectSelector:  metav1.LabelSelector{MatchLabels: map[string]string{"team": "devs"}},
+			}},
+		},
+	}
+	ruleSelector := labels.SelectorFromSet(labels.Set{RuleLabel: "devs"})
+	devSelector := labels.SelectorFromSet(labels.Set{"env": "dev"})
+	teamSelector := labels.SelectorFromSet(labels.Set{"team": "devs"})
+
+	ctrl := gomock.NewController(t)
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	clusterCache.EXPECT().List(devSelector).Return([]*v3.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-abc"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-old", DeletionTimestamp: &metav1.Time{}}},
+	}, nil).Times(2)
+	projectCache := fake.NewMockCacheInterface[*v3.Project](ctrl)
+	projectCache.EXPECT().List("c-abc", teamSelector).Return([]*v3.Project{
+		{ObjectMeta: metav1.ObjectMeta{Name: "p-new", Namespace: "c-abc"}},
+	}, nil)
+
+	// The binding of the cluster role exists, and a binding of a project not matching the rule anymore is stale.
+	crtbMeta := bindingMeta(rule, "c-abc", ldapDevs, "cluster-member")
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("", ruleSelector).Return([]*v3.ClusterRoleTemplateBinding{{ObjectMeta: crtbMeta}}, nil)
+	crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+
+	staleMeta := bindingMeta(rule, "p-moved", ldapDevs, "project-owner")
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", ruleSelector).Return([]*v3.ProjectRoleTemplateBinding{{ObjectMeta: staleMeta}}, nil)
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	prtbs.EXPECT().Delete("p-moved", staleMeta.Name, gomock.Any()).Return(nil)
+	prtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(prtb *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+		assert.Equal(t, "p-new", prtb.Namespace)
+		assert.Equal(t, "c-abc:p-new", prtb.ProjectName)
+		assert.Equal(t, ldapDevs, prtb.GroupPrincipalName)
+		assert.Equal(t, "project-owner", prtb.RoleTemplateName)
+		assert.Equal(t, "devs", prtb.Labels[RuleLabel])
+		assert.Equal(t, provenance.SourceGroupRule, prtb.Labels[provenance.SourceLabel])
+		assert.Equal(t, "groupmembershiprule/devs", prtb.Annotations[provenance.CreatorAnnotation])
+		require.Len(t, prtb.OwnerReferences, 1)
+		assert.Equal(t, "GroupMembershipRule", prtb.OwnerReferences[0].Kind)
+		assert.Equal(t, rule.UID, prtb.OwnerReferences[0].UID)
+		return prtb, nil
+	})
+
+	h := &handler{
+		clusterCache: clusterCache,
+		projectCache: projectCache,
+		crtbs:        crtbs,
+		crtbCache:    crtbCache,
+		prtbs:        prtbs,
+		prtbCache:    prtbCache,
+	}
+	obj, err := h.OnChange("", rule)
+	require.NoError(t, err)
+	assert.Equal(t, rule, obj)
+}
+
+func TestOnChangeDeleting(t *testing.T) {
+	h := &handler{}
+	rule := &v3.GroupMembershipRule{ObjectMeta: metav1.ObjectMeta{Name: "devs", DeletionTimestamp: &metav1.Time{}}}
+	obj, err := h.OnChange("", rule)
+	require.NoError(t, err)
+	assert.Equal(t, rule, obj)
+
+	obj, err = h.OnChange("", nil)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+}
+
+func TestBindingMeta(t *testing.T) {
+	rule := &v3.GroupMembershipRule{ObjectMeta: metav1.ObjectMeta{Name: "devs"}}
+	meta := bindingMeta(rule, "c-abc", ldapDevs, "cluster-member")
+	assert.Equal(t, meta.Name, bindingMeta(rule, "c-abc", ldapDevs, "cluster-member").Name)
+	assert.NotEqual(t, meta.Name, bindingMeta(rule, "c-abc", ldapOps, "cluster-member").Name)
+	assert.NotEqual(t, meta.Name, bindingMeta(rule, "c-abc", ldapDevs, "cluster-owner").Name)
+	assert.LessOrEqual(t, len(meta.Name), 63)
+}
+
+func TestEnqueuePatternRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ruleCache := fake.NewMockNonNamespacedCacheInterface[*v3.GroupMembershipRule](ctrl)
+	ruleCache.EXPECT().List(labels.Everything()).Return([]*v3.GroupMembershipRule{
+		{ObjectMeta: metav1.ObjectMeta{Name: "explicit"}, Spec: v3.GroupMembershipRuleSpec{GroupPrincipals: []string{ldapOps}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "patterns"}, Spec: v3.GroupMembershipRuleSpec{GroupPrincipalPatterns: []string{"cn=dev"}}},
+	}, nil)
+
+	h := &handler{ruleCache: ruleCache}
+	keys, err := h.enqueuePatternRules("", "u-abc", &v3.UserAttribute{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "patterns", keys[0].Name)
+}
@@ -0,0 +1,257 @@
+package groupmembership
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/wrangler"
+	k8srbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/component-helpers/auth/rbac/validation"
+)
+
+const (
+	// localClusterPrefix is the prefix of the requests to the Kubernetes API of the local cluster through its proxy.
+	localClusterPrefix = "/k8s/clusters/local"
+	// maxRuleSize is the size of the largest rule validated.
+	maxRuleSize = 3 * 1024 * 1024
+	// allRoleTemplates stands for every role template, when the role templates of a rule can't be known.
+	allRoleTemplates = ""
+)
+
+var (
+	rulesResource = v3.Resource(v3.GroupMembershipRuleResourceName)
+
+	// validatedVerbs are the verbs setting the role templates of the rules.
+	validatedVerbs = sets.New("create", "update", "patch")
+
+	kubeRequestInfoFactory = &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("apis", "api"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}
+)
+
+type permissionsResolver interface {
+	Resolve(userID string) (*rbac.GlobalPermissions, error)
+}
+
+// Validator refuses the creations and updates of the group membership rules binding role templates their caller
+// couldn't bind: the caller must be allowed to bind each role template of the rule, or hold it in every cluster through
+// its global roles, as the rules apply to the clusters and projects created later too. The rules can only be written
+// with the Kubernetes API, the Steve API not allowing it.
+type Validator struct {
+	ruleCache            mgmtcontrollers.GroupMembershipRuleCache
+	rtCache              mgmtcontrollers.RoleTemplateCache
+	clusterRoleCache     k8srbacv1.ClusterRoleCache
+	globalPermissions    permissionsResolver
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewValidator returns the validator of the group membership rules.
+func NewValidator(wContext *wrangler.Context) *Validator {
+	mgmt := wContext.Mgmt
+	return &Validator{
+		ruleCache:        mgmt.GroupMembershipRule().Cache(),
+		rtCache:          mgmt.RoleTemplate().Cache(),
+		clusterRoleCache: wContext.RBAC.ClusterRole().Cache(),
+		globalPermissions: rbac.NewGlobalPermissionsResolver(
+			mgmt.GlobalRoleBinding().Cache(),
+			mgmt.GlobalRole().Cache(),
+			mgmt.ClusterRoleTemplateBinding().Cache(),
+			mgmt.ProjectRoleTemplateBinding().Cache(),
+			mgmt.UserAttribute().Cache(),
+			mgmt.RoleTemplate().Cache(),
+			wContext.RBAC.ClusterRole().Cache(),
+		),
+		subjectAccessReviews: wContext.K8s.AuthorizationV1().SubjectAccessReviews(),
+	}
+}
+
+// Middleware validates the writes of the group membership rules. It must be chained after the authentication filter,
+// as it reads the user from the request context.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		info, ok := ruleWrite(req)
+		userInfo, authenticated := request.UserFrom(req.Context())
+		if !ok || !authenticated {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxRuleSize+1))
+		if err != nil {
+			writeStatus(rw, apierrors.NewBadRequest(fmt.Sprintf("failed to read the request: %v", err)))
+			return
+		}
+		if len(body) > maxRuleSize {
+			writeStatus(rw, apierrors.NewRequestEntityTooLargeError(fmt.Sprintf("limit is %d bytes", maxRuleSize)))
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		roleTemplates, err := v.roleTemplates(info, req.Header.Get("Content-Type"), body)
+		if err != nil {
+			writeStatus(rw, err)
+			return
+		}
+		for _, name := range roleTemplates {
+			if err := v.checkRole(req.Context(), userInfo, info.Name, name); err != nil {
+				writeStatus(rw, err)
+				return
+			}
+		}
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// ruleWrite returns the information of the request if it creates or updates a rule.
+func ruleWrite(req *http.Request) (*request.RequestInfo, bool) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return nil, false
+	}
+	if path, ok := strings.CutPrefix(req.URL.Path, localClusterPrefix); ok {
+		u := *req.URL
+		u.Path = path
+		req = req.WithContext(req.Context())
+		req.URL = &u
+	}
+	info, err := kubeRequestInfoFactory.NewRequestInfo(req)
+	if err != nil || !info.IsResourceRequest || info.APIGroup != rulesResource.Group ||
+		info.Resource != rulesResource.Resource || info.Subresource != "" || !validatedVerbs.Has(info.Verb) {
+		return nil, false
+	}
+	return info, true
+}
+
+// roleTemplates returns the role templates of the rule the request writes. For the patches which can't be applied
+// here, like the server-side applies, all the role templates are returned.
+func (v *Validator) roleTemplates(info *request.RequestInfo, contentType string, body []byte) ([]string, error) {
+	content := body
+	if info.Verb == "patch" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		patchType := types.PatchType(mediaType)
+		if patchType != types.MergePatchType && patchType != types.JSONPatchType {
+			return []string{allRoleTemplates}, nil
+		}
+		existing, err := v.ruleCache.Get(info.Name)
+		if apierrors.IsNotFound(err) {
+			// There's nothing to patch.
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if content, err = applyPatch(existing, patchType, body); err != nil {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("failed to apply the patch: %v", err))
+		}
+	}
+
+	var rule v3.GroupMembershipRule
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096).Decode(&rule); err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("failed to decode the group membership rule: %v", err))
+	}
+	names := sets.New[string]()
+	for _, clusterRole := range rule.Spec.ClusterRoles {
+		names.Insert(clusterRole.RoleTemplateName)
+	}
+	for _, projectRole := range rule.Spec.ProjectRoles {
+		names.Insert(projectRole.RoleTemplateName)
+	}
+	return sets.List(names), nil
+}
+
+func applyPatch(rule *v3.GroupMembershipRule, patchType types.PatchType, patch []byte) ([]byte, error) {
+	current, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	if patchType == types.MergePatchType {
+		return jsonpatch.MergePatch(current, patch)
+	}
+	decoded, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return decoded.Apply(current)
+}
+
+// checkRole returns an error unless the user is allowed to bind the role template, or holds it in every cluster.
+func (v *Validator) checkRole(ctx context.Context, userInfo user.Info, ruleName, name string) error {
+	allowed, err := util.Authorize(ctx, v.subjectAccessReviews, userInfo, authzv1.ResourceAttributes{
+		Group:    rulesResource.Group,
+		Resource: v3.RoleTemplateResourceName,
+		Verb:     "bind",
+		Name:     name,
+	})
+	if err != nil {
+		return err
+	}
+	if allowed {
+		return nil
+	}
+	if name == allRoleTemplates {
+		return apierrors.NewForbidden(rulesResource, ruleName, fmt.Errorf("not allowed to bind all role templates"))
+	}
+	holds, err := v.holds(userInfo.GetName(), name)
+	if err != nil {
+		return err
+	}
+	if !holds {
+		return apierrors.NewForbidden(rulesResource, ruleName, fmt.Errorf("not allowed to bind role template %s", name))
+	}
+	return nil
+}
+
+// holds tells whether the global roles of the user grant the rules of the role template in every cluster.
+func (v *Validator) holds(userID, name string) (bool, error) {
+	rt, err := v.rtCache.Get(name)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	rules, err := rbac.RulesFromTemplate(v.clusterRoleCache, v.rtCache, rt)
+	if err != nil {
+		return false, err
+	}
+	permissions, err := v.globalPermissions.Resolve(userID)
+	if err != nil {
+		return false, err
+	}
+	covered, _ := validation.Covers(permissions.ClusterRules, rules)
+	return covered, nil
+}
+
+// writeStatus writes the error as the Status response of the Kubernetes API.
+func writeStatus(rw http.ResponseWriter, err error) {
+	var statusErr *apierrors.StatusError
+	if !errors.As(err, &statusErr) {
+		statusErr = apierrors.NewInternalError(err)
+	}
+	status := statusErr.ErrStatus
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	util.WriteJSON(rw, int(status.Code), status)
+}
@@ -0,0 +1,216 @@
+package groupmembership
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	authzv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// fakeSubjectAccessReviews allows the users to bind the role templates, "" standing for all of them.
+type fakeSubjectAccessReviews struct {
+	authv1.SubjectAccessReviewInterface
+	bindable map[string][]string
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authzv1.SubjectAccessReview, _ metav1.CreateOptions) (*authzv1.SubjectAccessReview, error) {
+	attributes := sar.Spec.ResourceAttributes
+	if attributes.Verb == "bind" && attributes.Resource == "roletemplates" {
+		for _, name := range f.bindable[sar.Spec.User] {
+			if name == "" || name == attributes.Name {
+				sar.Status.Allowed = true
+			}
+		}
+	}
+	return sar, nil
+}
+
+type fakePermissions map[string]*rbac.GlobalPermissions
+
+func (f fakePermissions) Resolve(userID string) (*rbac.GlobalPermissions, error) {
+	if permissions, ok := f[userID]; ok {
+		return permissions, nil
+	}
+	return &rbac.GlobalPermissions{}, nil
+}
+
+func TestValidator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ruleCache := fake.NewMockNonNamespacedCacheInterface[*v3.GroupMembershipRule](ctrl)
+	ruleCache.EXPECT().Get("devs").Return(&v3.GroupMembershipRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "devs"},
+		Spec: v3.GroupMembershipRuleSpec{
+			GroupPrincipals: []string{ldapDevs},
+			ProjectRoles:    []v3.GroupMembershipProjectRole{{RoleTemplateName: "project-member"}},
+		},
+	}, nil).AnyTimes()
+	ruleCache.EXPECT().Get(gomock.Any()).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "")).AnyTimes()
+	rtCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	rtCache.EXPECT().Get("cluster-owner").Return(&v3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-owner"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+	}, nil).AnyTimes()
+	rtCache.EXPECT().Get("project-member").Return(&v3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-member"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}},
+	}, nil).AnyTimes()
+
+	v := &Validator{
+		ruleCache: ruleCache,
+		rtCache:   rtCache,
+		globalPermissions: fakePermissions{
+			"u-owner": {ClusterRules: []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}}},
+		},
+		subjectAccessReviews: &fakeSubjectAccessReviews{bindable: map[string][]string{
+			"u-admin":   {""},
+			"u-binder":  {"project-member"},
+			"u-no-bind": nil,
+		}},
+	}
+
+	ownerRule := `{"apiVersion":"management.cattle.io/v3","kind":"GroupMembershipRule","metadata":{"name":"owners"},` +
+		`"spec":{"groupPrincipals":["` + ldapOps + `"],"clusterRoles":[{"roleTemplateName":"cluster-owner"}]}}`
+	memberRule := `{"apiVersion":"management.cattle.io/v3","kind":"GroupMembershipRule","metadata":{"name":"devs"},` +
+		`"spec":{"groupPrincipals":["` + ldapDevs + `"],"projectRoles":[{"roleTemplateName":"project-member"}]}}`
+	ownerPatch := `{"spec":{"clusterRoles":[{"roleTemplateName":"cluster-owner"}]}}`
+
+	tests := []struct {
+		desc        string
+		user        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{
+			desc:       "role template allowed to bind",
+			user:       "u-binder",
+			method:     http.MethodPost,
+			path:       "/apis/management.cattle.io/v3/groupmembershiprules",
+			body:       memberRule,
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "role template not allowed to bind",
+			user:       "u-binder",
+			method:     http.MethodPost,
+			path:       "/apis/management.cattle.io/v3/groupmembershiprules",
+			body:       ownerRule,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc:       "through the proxy of the local cluster",
+			user:       "u-binder",
+			method:     http.MethodPost,
+			path:       "/k8s/clusters/local/apis/management.cattle.io/v3/groupmembershiprules",
+			body:       ownerRule,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc:       "role template held in every cluster",
+			user:       "u-owner",
+			method:     http.MethodPut,
+			path:       "/apis/management.cattle.io/v3/groupmembershiprules/owners",
+			body:       ownerRule,
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "update of the groups of a rule",
+			user:       "u-no-bind",
+			method:     http.MethodPut,
+			path:       "/apis/management.cattle.io/v3/groupmembershiprules/devs",
+			body:       memberRule,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc:        "merge patch adding a role template",
+			user:        "u-binder",
+			method:      http.MethodPatch,
+			path:        "/apis/management.cattle.io/v3/groupmembershiprules/devs",
+			contentType: string(types.MergePatchType),
+			body:        ownerPatch,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			desc:        "json patch of the groups",
+			user:        "u-binder",
+			method:      http.MethodPatch,
+			path:        "/apis/management.cattle.io/v3/groupmembershiprules/devs",
+			contentType: string(types.JSONPatchType),
+			body:        `[{"op":"add","path":"/spec/groupPrincipals/-","value":"` + ldapOps + `"}]`,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			desc:        "server-side apply without binding all role templates",
+			user:        "u-binder",
+			method:      http.MethodPatch,
+			path:        "/apis/management.cattle.io/v3/groupmembershiprules/devs",
+			contentType: string(types.ApplyYAMLPatchType),
+			body:        memberRule,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			desc:        "server-side apply binding all role templates",
+			user:        "u-admin",
+			method:      http.MethodPatch,
+			path:        "/apis/management.cattle.io/v3/groupmembershiprules/devs",
+			contentType: string(types.ApplyYAMLPatchType),
+			body:        ownerRule,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			desc:       "deletion",
+			user:       "u-no-bind",
+			method:     http.MethodDelete,
+			path:       "/apis/management.cattle.io/v3/groupmembershiprules/devs",
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:       "other resource",
+			user:       "u-no-bind",
+			method:     http.MethodPost,
+			path:       "/apis/management.cattle.io/v3/groups",
+			body:       ownerRule,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// The validated body is passed on.
+				body, err := io.ReadAll(req.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+				rw.WriteHeader(http.StatusOK)
+			})
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: tt.user}))
+			rec := httptest.NewRecorder()
+
+			v.Middleware(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
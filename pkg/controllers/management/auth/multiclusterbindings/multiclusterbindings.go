@@ -0,0 +1,206 @@
+// Package multiclusterbindings reconciles the MultiClusterRoleTemplateBindings, binding a role template to a group in
+// all the clusters matching a label selector. The clusters are reconciled as they're created, relabeled or deleted.
+package multiclusterbindings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	bindingHandler  = "mgmt-multi-cluster-binding-handler"
+	clusterEnqueuer = "mgmt-multi-cluster-binding-cluster"
+
+	// BindingLabel is set on the ClusterRoleTemplateBindings of the MultiClusterRoleTemplateBindings to the name of
+	// their MultiClusterRoleTemplateBinding.
+	BindingLabel = "auth.cattle.io/multi-cluster-binding"
+)
+
+type handler struct {
+	bindings          mgmtcontrollers.MultiClusterRoleTemplateBindingController
+	bindingCache      mgmtcontrollers.MultiClusterRoleTemplateBindingCache
+	clusterCache      mgmtcontrollers.ClusterCache
+	roleTemplateCache mgmtcontrollers.RoleTemplateCache
+	crtbs             mgmtcontrollers.ClusterRoleTemplateBindingClient
+	crtbCache         mgmtcontrollers.ClusterRoleTemplateBindingCache
+}
+
+// Register registers the handler of the MultiClusterRoleTemplateBindings, and enqueues them on the changes of the
+// clusters.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	mgmt := management.Wrangler.Mgmt
+	h := &handler{
+		bindings:          mgmt.MultiClusterRoleTemplateBinding(),
+		bindingCache:      mgmt.MultiClusterRoleTemplateBinding().Cache(),
+		clusterCache:      mgmt.Cluster().Cache(),
+		roleTemplateCache: mgmt.RoleTemplate().Cache(),
+		crtbs:             mgmt.ClusterRoleTemplateBinding(),
+		crtbCache:         mgmt.ClusterRoleTemplateBinding().Cache(),
+	}
+	bindings := mgmt.MultiClusterRoleTemplateBinding()
+	bindings.OnChange(ctx, bindingHandler, h.OnChange)
+	relatedresource.WatchClusterScoped(ctx, clusterEnqueuer, h.enqueueBindings, bindings, mgmt.Cluster())
+}
+
+// OnChange binds the role template to the group in each cluster matching the selector, and deletes the bindings of the
+// clusters which don't match anymore. The ClusterRoleTemplateBindings are owned by the MultiClusterRoleTemplateBinding,
+// and deleted along with it. They're left as they are while the role template can't be bound.
+func (h *handler) OnChange(_ string, binding *v3.MultiClusterRoleTemplateBinding) (*v3.MultiClusterRoleTemplateBinding, error) {
+	if binding == nil || binding.DeletionTimestamp != nil {
+		return binding, nil
+	}
+
+	status := v3.MultiClusterRoleTemplateBindingStatus{ObservedGeneration: binding.Generation}
+	var reconcileErr error
+	clusters, err := h.clusters(binding)
+	if err != nil {
+		status.Error = err.Error()
+	} else {
+		status.ClusterCount = len(clusters)
+		status.Clusters = clusters
+		if err := h.validate(binding); err != nil {
+			status.Error = err.Error()
+		} else if err := h.reconcileCRTBs(binding, clusters); err != nil {
+			reconcileErr = fmt.Errorf("reconciling the ClusterRoleTemplateBindings of MultiClusterRoleTemplateBinding %s: %w", binding.Name, err)
+			status.Error = reconcileErr.Error()
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(binding.Status, status) {
+		binding = binding.DeepCopy()
+		binding.Status = status
+		updated, err := h.bindings.UpdateStatus(binding)
+		if err != nil {
+			return binding, fmt.Errorf("updating the status of MultiClusterRoleTemplateBinding %s: %w", binding.Name, err)
+		}
+		binding = updated
+	}
+	return binding, reconcileErr
+}
+
+// clusters returns the names of the existing clusters matching the selector of the binding, sorted.
+func (h *handler) clusters(binding *v3.MultiClusterRoleTemplateBinding) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&binding.Spec.ClusterSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+	clusters, err := h.clusterCache.List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing clusters: %w", err)
+	}
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.DeletionTimestamp == nil {
+			names = append(names, cluster.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// validate returns an error if the binding doesn't bind a group to an existing cluster role template which isn't
+// locked.
+func (h *handler) validate(binding *v3.MultiClusterRoleTemplateBinding) error {
+	if binding.Spec.GroupPrincipalName == "" {
+		return fmt.Errorf("groupPrincipalName must be set")
+	}
+	rt, err := h.roleTemplateCache.Get(binding.Spec.RoleTemplateName)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("role template %s doesn't exist", binding.Spec.RoleTemplateName)
+	}
+	if err != nil {
+		return fmt.Errorf("getting role template %s: %w", binding.Spec.RoleTemplateName, err)
+	}
+	if rt.Context != "cluster" {
+		return fmt.Errorf("role template %s isn't a cluster role template", rt.Name)
+	}
+	if rt.Locked {
+		return fmt.Errorf("role template %s is locked", rt.Name)
+	}
+	return nil
+}
+
+func (h *handler) reconcileCRTBs(binding *v3.MultiClusterRoleTemplateBinding, clusters []string) error {
+	desired := map[string]*v3.ClusterRoleTemplateBinding{}
+	for _, cluster := range clusters {
+		crtb := &v3.ClusterRoleTemplateBinding{
+			ObjectMeta:         crtbMeta(binding, cluster),
+			ClusterName:        cluster,
+			GroupPrincipalName: binding.Spec.GroupPrincipalName,
+			RoleTemplateName:   binding.Spec.RoleTemplateName,
+		}
+		desired[crtb.Namespace+"/"+crtb.Name] = crtb
+	}
+
+	existing, err := h.crtbCache.List("", labels.SelectorFromSet(labels.Set{BindingLabel: binding.Name}))
+	if err != nil {
+		return err
+	}
+	for _, crtb := range existing {
+		if _, ok := desired[crtb.Namespace+"/"+crtb.Name]; ok {
+			delete(desired, crtb.Namespace+"/"+crtb.Name)
+			continue
+		}
+		if crtb.DeletionTimestamp != nil || !provenance.Owns(crtb, provenance.SourceMultiClusterBinding) {
+			continue
+		}
+		if err := h.crtbs.Delete(crtb.Namespace, crtb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		logrus.Infof("multiclusterbindings: MultiClusterRoleTemplateBinding %s unbound role template %s from %s in cluster %s", binding.Name, crtb.RoleTemplateName, crtb.GroupPrincipalName, crtb.ClusterName)
+	}
+	for _, crtb := range desired {
+		if _, err := h.crtbs.Create(crtb); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		logrus.Infof("multiclusterbindings: MultiClusterRoleTemplateBinding %s bound role template %s to %s in cluster %s", binding.Name, crtb.RoleTemplateName, crtb.GroupPrincipalName, crtb.ClusterName)
+	}
+	return nil
+}
+
+// crtbMeta returns the metadata of a ClusterRoleTemplateBinding of the binding. Its name is derived from the group and
+// the role template, which are immutable on the ClusterRoleTemplateBindings, so that changing them replaces the
+// ClusterRoleTemplateBindings.
+func crtbMeta(binding *v3.MultiClusterRoleTemplateBinding, clusterName string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:      name.SafeConcatName("mcrtb", binding.Name, name.Hex(binding.Spec.GroupPrincipalName+"/"+binding.Spec.RoleTemplateName, 10)),
+		Namespace: clusterName,
+		Labels:    map[string]string{BindingLabel: binding.Name},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: v3.SchemeGroupVersion.String(),
+			Kind:       "MultiClusterRoleTemplateBinding",
+			Name:       binding.Name,
+			UID:        binding.UID,
+		}},
+	}
+	provenance.Set(&meta, provenance.SourceMultiClusterBinding, provenance.Creator("MultiClusterRoleTemplateBinding", binding.Name))
+	return meta
+}
+
+// enqueueBindings enqueues all the MultiClusterRoleTemplateBindings, as the changed cluster may now match their
+// selector, or not anymore.
+func (h *handler) enqueueBindings(_, _ string, _ runtime.Object) ([]relatedresource.Key, error) {
+	bindings, err := h.bindingCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing MultiClusterRoleTemplateBindings: %w", err)
+	}
+	keys := make([]relatedresource.Key, 0, len(bindings))
+	for _, binding := range bindings {
+		keys = append(keys, relatedresource.Key{Name: binding.Name})
+	}
+	return keys, nil
+}
@@ -0,0 +1,192 @@
+package multiclusterbindings
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const ldapPlatform = "openldap_group://cn=platform,ou=groups,dc=example,dc=com"
+
+func newBinding() *v3.MultiClusterRoleTemplateBinding {
+	return &v3.MultiClusterRoleTemplateBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform-prod", UID: "mcrtb-uid", Generation: 2},
+		Spec: v3.MultiClusterRoleTemplateBindingSpec{
+			GroupPrincipalName: ldapPlatform,
+			RoleTemplateName:   "cluster-member",
+			ClusterSelector:    metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		},
+	}
+}
+
+func roleTemplateCache(ctrl *gomock.Controller) *fake.MockNonNamespacedCacheInterface[*v3.RoleTemplate] {
+	cache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	cache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.RoleTemplate, error) {
+		switch name {
+		case "cluster-member":
+			return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}, Context: "cluster"}, nil
+		case "project-member":
+			return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}, Context: "project"}, nil
+		case "locked":
+			return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}, Context: "cluster", Locked: true}, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "roletemplates"}, name)
+	}).AnyTimes()
+	return cache
+}
+
+func TestOnChange(t *testing.T) {
+	binding := newBinding()
+
+	ctrl := gomock.NewController(t)
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	clusterCache.EXPECT().List(labels.SelectorFromSet(labels.Set{"env": "prod"})).Return([]*v3.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-new"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-abc"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-old", DeletionTimestamp: &metav1.Time{}}},
+	}, nil)
+
+	// The binding of c-abc exists, the cluster c-relabeled doesn't match anymore, and the binding of c-taken was
+	// relabeled by another source.
+	existingMeta := crtbMeta(binding, "c-abc")
+	relabeledMeta := crtbMeta(binding, "c-relabeled")
+	takenMeta := crtbMeta(binding, "c-taken")
+	provenance.Set(&takenMeta, provenance.SourceTerraform, "")
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("", labels.SelectorFromSet(labels.Set{BindingLabel: "platform-prod"})).Return([]*v3.ClusterRoleTemplateBinding{
+		{ObjectMeta: existingMeta},
+		{ObjectMeta: relabeledMeta},
+		{ObjectMeta: takenMeta},
+	}, nil)
+	crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	crtbs.EXPECT().Delete("c-relabeled", relabeledMeta.Name, gomock.Any()).Return(nil)
+	crtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		assert.Equal(t, "c-new", crtb.Namespace)
+		assert.Equal(t, "c-new", crtb.ClusterName)
+		assert.Equal(t, ldapPlatform, crtb.GroupPrincipalName)
+		assert.Equal(t, "cluster-member", crtb.RoleTemplateName)
+		assert.Equal(t, "platform-prod", crtb.Labels[BindingLabel])
+		assert.Equal(t, provenance.SourceMultiClusterBinding, crtb.Labels[provenance.SourceLabel])
+		assert.Equal(t, "multiclusterroletemplatebinding/platform-prod", crtb.Annotations[provenance.CreatorAnnotation])
+		require.Len(t, crtb.OwnerReferences, 1)
+		assert.Equal(t, "MultiClusterRoleTemplateBinding", crtb.OwnerReferences[0].Kind)
+		assert.Equal(t, binding.UID, crtb.OwnerReferences[0].UID)
+		return crtb, nil
+	})
+
+	bindings := fake.NewMockNonNamespacedControllerInterface[*v3.MultiClusterRoleTemplateBinding, *v3.MultiClusterRoleTemplateBindingList](ctrl)
+	bindings.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(binding *v3.MultiClusterRoleTemplateBinding) (*v3.MultiClusterRoleTemplateBinding, error) {
+		assert.Equal(t, v3.MultiClusterRoleTemplateBindingStatus{
+			ObservedGeneration: 2,
+			ClusterCount:       2,
+			Clusters:           []string{"c-abc", "c-new"},
+		}, binding.Status)
+		return binding, nil
+	})
+
+	h := &handler{
+		bindings:          bindings,
+		clusterCache:      clusterCache,
+		roleTemplateCache: roleTemplateCache(ctrl),
+		crtbs:             crtbs,
+		crtbCache:         crtbCache,
+	}
+	_, err := h.OnChange("", binding)
+	require.NoError(t, err)
+}
+
+func TestOnChangeInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*v3.MultiClusterRoleTemplateBinding)
+		error  string
+	}{
+		{
+			name:   "missing role template",
+			modify: func(b *v3.MultiClusterRoleTemplateBinding) { b.Spec.RoleTemplateName = "missing" },
+			error:  "role template missing doesn't exist",
+		},
+		{
+			name:   "project role template",
+			modify: func(b *v3.MultiClusterRoleTemplateBinding) { b.Spec.RoleTemplateName = "project-member" },
+			error:  "role template project-member isn't a cluster role template",
+		},
+		{
+			name:   "locked role template",
+			modify: func(b *v3.MultiClusterRoleTemplateBinding) { b.Spec.RoleTemplateName = "locked" },
+			error:  "role template locked is locked",
+		},
+		{
+			name:   "no group",
+			modify: func(b *v3.MultiClusterRoleTemplateBinding) { b.Spec.GroupPrincipalName = "" },
+			error:  "groupPrincipalName must be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binding := newBinding()
+			tt.modify(binding)
+
+			ctrl := gomock.NewController(t)
+			clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+			clusterCache.EXPECT().List(gomock.Any()).Return([]*v3.Cluster{{ObjectMeta: metav1.ObjectMeta{Name: "c-abc"}}}, nil)
+			// The existing ClusterRoleTemplateBindings are left as they are.
+			bindings := fake.NewMockNonNamespacedControllerInterface[*v3.MultiClusterRoleTemplateBinding, *v3.MultiClusterRoleTemplateBindingList](ctrl)
+			bindings.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(binding *v3.MultiClusterRoleTemplateBinding) (*v3.MultiClusterRoleTemplateBinding, error) {
+				assert.Equal(t, tt.error, binding.Status.Error)
+				assert.Equal(t, []string{"c-abc"}, binding.Status.Clusters)
+				return binding, nil
+			})
+
+			h := &handler{
+				bindings:          bindings,
+				clusterCache:      clusterCache,
+				roleTemplateCache: roleTemplateCache(ctrl),
+			}
+			_, err := h.OnChange("", binding)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOnChangeInvalidSelector(t *testing.T) {
+	binding := newBinding()
+	binding.Spec.ClusterSelector = metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Around"}}}
+
+	ctrl := gomock.NewController(t)
+	bindings := fake.NewMockNonNamespacedControllerInterface[*v3.MultiClusterRoleTemplateBinding, *v3.MultiClusterRoleTemplateBindingList](ctrl)
+	bindings.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(binding *v3.MultiClusterRoleTemplateBinding) (*v3.MultiClusterRoleTemplateBinding, error) {
+		assert.Contains(t, binding.Status.Error, "invalid cluster selector")
+		assert.Empty(t, binding.Status.Clusters)
+		return binding, nil
+	})
+
+	h := &handler{bindings: bindings}
+	_, err := h.OnChange("", binding)
+	require.NoError(t, err)
+}
+
+func TestEnqueueBindings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bindingCache := fake.NewMockNonNamespacedCacheInterface[*v3.MultiClusterRoleTemplateBinding](ctrl)
+	bindingCache.EXPECT().List(labels.Everything()).Return([]*v3.MultiClusterRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "platform-prod"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sre-all"}},
+	}, nil)
+
+	h := &handler{bindingCache: bindingCache}
+	keys, err := h.enqueueBindings("", "c-new", &v3.Cluster{})
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "platform-prod", keys[0].Name)
+	assert.Equal(t, "sre-all", keys[1].Name)
+}
@@ -0,0 +1,303 @@
+// Package organizations reconciles the organizations, which group clusters so that their administration can be
+// delegated. The admins of an organization are bound the organization-admin role template in each of its clusters,
+// including those created after it that match its cluster selector, and are granted read access to the organization.
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rancher/rancher/pkg/apis/management.cattle.io"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	rbaccontrollers "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	organizationHandler = "mgmt-organization-handler"
+	clusterEnqueuer     = "mgmt-organization-cluster"
+
+	// AdminRoleTemplateName is the role template bound to the admins of an organization in each of its clusters.
+	AdminRoleTemplateName = "organization-admin"
+
+	// OrganizationLabel is set on the ClusterRoleTemplateBindings of the organizations to the name of their organization.
+	OrganizationLabel = "auth.cattle.io/organization"
+)
+
+type handler struct {
+	organizations mgmtcontrollers.OrganizationController
+	orgCache      mgmtcontrollers.OrganizationCache
+	clusterCache  mgmtcontrollers.ClusterCache
+	crtbs         mgmtcontrollers.ClusterRoleTemplateBindingClient
+	crtbCache     mgmtcontrollers.ClusterRoleTemplateBindingCache
+	crs           rbaccontrollers.ClusterRoleClient
+	crCache       rbaccontrollers.ClusterRoleCache
+	crbs          rbaccontrollers.ClusterRoleBindingClient
+	crbCache      rbaccontrollers.ClusterRoleBindingCache
+}
+
+// Register registers the handler of the organizations, and enqueues them on the changes of the clusters.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	mgmt := management.Wrangler.Mgmt
+	rbac := management.Wrangler.RBAC
+	h := &handler{
+		organizations: mgmt.Organization(),
+		orgCache:      mgmt.Organization().Cache(),
+		clusterCache:  mgmt.Cluster().Cache(),
+		crtbs:         mgmt.ClusterRoleTemplateBinding(),
+		crtbCache:     mgmt.ClusterRoleTemplateBinding().Cache(),
+		crs:           rbac.ClusterRole(),
+		crCache:       rbac.ClusterRole().Cache(),
+		crbs:          rbac.ClusterRoleBinding(),
+		crbCache:      rbac.ClusterRoleBinding().Cache(),
+	}
+	organizations := mgmt.Organization()
+	organizations.OnChange(ctx, organizationHandler, h.OnChange)
+	relatedresource.WatchClusterScoped(ctx, clusterEnqueuer, h.enqueueOrganizations, organizations, mgmt.Cluster())
+}
+
+// OnChange binds the organization-admin role template to the admins of the organization in each of its clusters,
+// deletes the bindings the organization doesn't grant anymore, and grants the admins read access to the organization.
+// The bindings and the read access are owned by the organization, and deleted along with it.
+func (h *handler) OnChange(_ string, org *v3.Organization) (*v3.Organization, error) {
+	if org == nil || org.DeletionTimestamp != nil {
+		return org, nil
+	}
+	clusters, err := h.clusters(org)
+	if err != nil {
+		return org, err
+	}
+
+	var reconcileErr error
+	if err := h.reconcileCRTBs(org, clusters); err != nil {
+		reconcileErr = fmt.Errorf("reconciling the ClusterRoleTemplateBindings of organization %s: %w", org.Name, err)
+	} else if err := h.reconcileAccess(org); err != nil {
+		reconcileErr = fmt.Errorf("reconciling the access to organization %s: %w", org.Name, err)
+	}
+
+	status := v3.OrganizationStatus{
+		ObservedGeneration: org.Generation,
+		ClusterCount:       len(clusters),
+		Clusters:           clusters,
+	}
+	if reconcileErr != nil {
+		status.Error = reconcileErr.Error()
+	}
+	if !equality.Semantic.DeepEqual(org.Status, status) {
+		org = org.DeepCopy()
+		org.Status = status
+		updated, err := h.organizations.UpdateStatus(org)
+		if err != nil {
+			return org, fmt.Errorf("updating the status of organization %s: %w", org.Name, err)
+		}
+		org = updated
+	}
+	return org, reconcileErr
+}
+
+// clusters returns the names of the existing clusters of the organization, sorted. An invalid cluster selector
+// selects no cluster.
+func (h *handler) clusters(org *v3.Organization) ([]string, error) {
+	set := map[string]bool{}
+	for _, clusterName := range org.Spec.ClusterNames {
+		cluster, err := h.clusterCache.Get(clusterName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if cluster.DeletionTimestamp == nil {
+			set[cluster.Name] = true
+		}
+	}
+
+	if org.Spec.ClusterSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(org.Spec.ClusterSelector)
+		if err != nil {
+			logrus.Errorf("[%s] skipping invalid cluster selector of organization %s: %v", organizationHandler, org.Name, err)
+		} else {
+			clusters, err := h.clusterCache.List(selector)
+			if err != nil {
+				return nil, err
+			}
+			for _, cluster := range clusters {
+				if cluster.DeletionTimestamp == nil {
+					set[cluster.Name] = true
+				}
+			}
+		}
+	}
+
+	clusters := make([]string, 0, len(set))
+	for cluster := range set {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return clusters, nil
+}
+
+// admins returns the valid admins of the organization, which set exactly one of a user name and a group principal.
+func admins(org *v3.Organization) []v3.OrganizationAdmin {
+	var valid []v3.OrganizationAdmin
+	for _, admin := range org.Spec.Admins {
+		if (admin.UserName == "") == (admin.GroupPrincipalName == "") {
+			logrus.Errorf("[%s] skipping admin of organization %s which doesn't set exactly one of userName and groupPrincipalName", organizationHandler, org.Name)
+			continue
+		}
+		valid = append(valid, admin)
+	}
+	return valid
+}
+
+func (h *handler) reconcileCRTBs(org *v3.Organization, clusters []string) error {
+	desired := map[string]*v3.ClusterRoleTemplateBinding{}
+	for _, cluster := range clusters {
+		for _, admin := range admins(org) {
+			binding := &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:         bindingMeta(org, cluster, admin),
+				ClusterName:        cluster,
+				UserName:           admin.UserName,
+				GroupPrincipalName: admin.GroupPrincipalName,
+				RoleTemplateName:   AdminRoleTemplateName,
+			}
+			desired[binding.Namespace+"/"+binding.Name] = binding
+		}
+	}
+
+	existing, err := h.crtbCache.List("", labels.SelectorFromSet(labels.Set{OrganizationLabel: org.Name}))
+	if err != nil {
+		return err
+	}
+	for _, binding := range existing {
+		if _, ok := desired[binding.Namespace+"/"+binding.Name]; ok {
+			delete(desired, binding.Namespace+"/"+binding.Name)
+			continue
+		}
+		if !provenance.Owns(binding, provenance.SourceOrganization) {
+			continue
+		}
+		if err := h.crtbs.Delete(binding.Namespace, binding.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		logrus.Infof("organizations: organization %s revoked the admin role of %s%s in cluster %s", org.Name, binding.UserName, binding.GroupPrincipalName, binding.ClusterName)
+	}
+	for _, binding := range desired {
+		if _, err := h.crtbs.Create(binding); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		logrus.Infof("organizations: organization %s granted the admin role to %s%s in cluster %s", org.Name, binding.UserName, binding.GroupPrincipalName, binding.ClusterName)
+	}
+	return nil
+}
+
+// reconcileAccess ensures the ClusterRole granting read access to the organization, and its ClusterRoleBinding to the
+// admins of the organization.
+func (h *handler) reconcileAccess(org *v3.Organization) error {
+	accessName := name.SafeConcatName("organization", org.Name, "admin")
+
+	rules := []rbacv1.PolicyRule{{
+		APIGroups:     []string{management.GroupName},
+		Resources:     []string{v3.OrganizationResourceName},
+		ResourceNames: []string{org.Name},
+		Verbs:         []string{"get"},
+	}}
+	cr, err := h.crCache.Get(accessName)
+	if apierrors.IsNotFound(err) {
+		if _, err := h.crs.Create(&rbacv1.ClusterRole{ObjectMeta: accessMeta(org, accessName), Rules: rules}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("couldn't create ClusterRole: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("couldn't get ClusterRole: %w", err)
+	} else if !equality.Semantic.DeepEqual(cr.Rules, rules) {
+		// undo modifications if cr has changed
+		cr = cr.DeepCopy()
+		cr.Rules = rules
+		if _, err := h.crs.Update(cr); err != nil {
+			return fmt.Errorf("couldn't update ClusterRole: %w", err)
+		}
+	}
+
+	var subjects []rbacv1.Subject
+	for _, admin := range admins(org) {
+		if admin.UserName != "" {
+			subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: admin.UserName})
+		} else {
+			subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: admin.GroupPrincipalName})
+		}
+	}
+	crb, err := h.crbCache.Get(accessName)
+	if apierrors.IsNotFound(err) {
+		if _, err := h.crbs.Create(&rbacv1.ClusterRoleBinding{
+			ObjectMeta: accessMeta(org, accessName),
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: accessName},
+			Subjects:   subjects,
+		}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("couldn't create ClusterRoleBinding: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("couldn't get ClusterRoleBinding: %w", err)
+	} else if !equality.Semantic.DeepEqual(crb.Subjects, subjects) {
+		crb = crb.DeepCopy()
+		crb.Subjects = subjects
+		if _, err := h.crbs.Update(crb); err != nil {
+			return fmt.Errorf("couldn't update ClusterRoleBinding: %w", err)
+		}
+	}
+	return nil
+}
+
+// bindingMeta returns the metadata of a binding of the organization. Its name is deterministic, so that it's only
+// created once.
+func bindingMeta(org *v3.Organization, clusterName string, admin v3.OrganizationAdmin) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:            name.SafeConcatName("org", org.Name, name.Hex(admin.UserName+"/"+admin.GroupPrincipalName, 10)),
+		Namespace:       clusterName,
+		Labels:          map[string]string{OrganizationLabel: org.Name},
+		OwnerReferences: ownerReferences(org),
+	}
+	provenance.Set(&meta, provenance.SourceOrganization, provenance.Creator("Organization", org.Name))
+	return meta
+}
+
+func accessMeta(org *v3.Organization, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            name,
+		Labels:          map[string]string{OrganizationLabel: org.Name},
+		OwnerReferences: ownerReferences(org),
+	}
+}
+
+func ownerReferences(org *v3.Organization) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{
+		APIVersion: v3.SchemeGroupVersion.String(),
+		Kind:       "Organization",
+		Name:       org.Name,
+		UID:        org.UID,
+	}}
+}
+
+// enqueueOrganizations enqueues all the organizations, as the changed cluster may now be one of theirs, or not anymore.
+func (h *handler) enqueueOrganizations(_, _ string, _ runtime.Object) ([]relatedresource.Key, error) {
+	orgs, err := h.orgCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing organizations: %w", err)
+	}
+	keys := make([]relatedresource.Key, 0, len(orgs))
+	for _, org := range orgs {
+		keys = append(keys, relatedresource.Key{Name: org.Name})
+	}
+	return keys, nil
+}
@@ -0,0 +1,161 @@
+package organizations
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const ldapPlatform = "openldap_group://cn=platform,ou=groups,dc=example,dc=com"
+
+func TestClusters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	clusterCache.EXPECT().Get("c-named").Return(&v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-named"}}, nil)
+	clusterCache.EXPECT().Get("c-removed").Return(nil, apierrors.NewNotFound(schema.GroupResource{Resource: "clusters"}, "c-removed"))
+	clusterCache.EXPECT().List(labels.SelectorFromSet(labels.Set{"org": "acme"})).Return([]*v3.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-selected"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-named"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-old", DeletionTimestamp: &metav1.Time{}}},
+	}, nil)
+
+	h := &handler{clusterCache: clusterCache}
+	clusters, err := h.clusters(&v3.Organization{
+		ObjectMeta: metav1.ObjectMeta{Name: "acme"},
+		Spec: v3.OrganizationSpec{
+			ClusterNames:    []string{"c-named", "c-removed"},
+			ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"org": "acme"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c-named", "c-selected"}, clusters)
+}
+
+func TestClustersWithoutSelector(t *testing.T) {
+	// The clusters aren't listed without a selector.
+	h := &handler{}
+	clusters, err := h.clusters(&v3.Organization{})
+	require.NoError(t, err)
+	assert.Empty(t, clusters)
+}
+
+func TestAdmins(t *testing.T) {
+	org := &v3.Organization{Spec: v3.OrganizationSpec{Admins: []v3.OrganizationAdmin{
+		{UserName: "u-alice"},
+		{GroupPrincipalName: ldapPlatform},
+		{},
+		{UserName: "u-bob", GroupPrincipalName: ldapPlatform},
+	}}}
+	assert.Equal(t, []v3.OrganizationAdmin{{UserName: "u-alice"}, {GroupPrincipalName: ldapPlatform}}, admins(org))
+}
+
+func TestOnChange(t *testing.T) {
+	org := &v3.Organization{
+		ObjectMeta: metav1.ObjectMeta{Name: "acme", UID: "org-uid", Generation: 2},
+		Spec: v3.OrganizationSpec{
+			ClusterNames: []string{"c-abc"},
+			Admins:       []v3.OrganizationAdmin{{UserName: "u-alice"}, {GroupPrincipalName: ldapPlatform}},
+		},
+	}
+	notFound := apierrors.NewNotFound(schema.GroupResource{}, "")
+
+	ctrl := gomock.NewController(t)
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	clusterCache.EXPECT().Get("c-abc").Return(&v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-abc"}}, nil)
+
+	// The binding of alice exists, and a binding of a cluster removed from the organization is stale.
+	aliceMeta := bindingMeta(org, "c-abc", v3.OrganizationAdmin{UserName: "u-alice"})
+	staleMeta := bindingMeta(org, "c-moved", v3.OrganizationAdmin{UserName: "u-alice"})
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("", labels.SelectorFromSet(labels.Set{OrganizationLabel: "acme"})).Return([]*v3.ClusterRoleTemplateBinding{
+		{ObjectMeta: aliceMeta},
+		{ObjectMeta: staleMeta},
+	}, nil)
+	crtbs := fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+	crtbs.EXPECT().Delete("c-moved", staleMeta.Name, gomock.Any()).Return(nil)
+	crtbs.EXPECT().Create(gomock.Any()).DoAndReturn(func(crtb *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+		assert.Equal(t, "c-abc", crtb.Namespace)
+		assert.Equal(t, "c-abc", crtb.ClusterName)
+		assert.Equal(t, ldapPlatform, crtb.GroupPrincipalName)
+		assert.Equal(t, AdminRoleTemplateName, crtb.RoleTemplateName)
+		assert.Equal(t, "acme", crtb.Labels[OrganizationLabel])
+		require.Len(t, crtb.OwnerReferences, 1)
+		assert.Equal(t, "Organization", crtb.OwnerReferences[0].Kind)
+		assert.Equal(t, org.UID, crtb.OwnerReferences[0].UID)
+		return crtb, nil
+	})
+
+	crCache := fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRole](ctrl)
+	crCache.EXPECT().Get("organization-acme-admin").Return(nil, notFound)
+	crs := fake.NewMockNonNamespacedClientInterface[*rbacv1.ClusterRole, *rbacv1.ClusterRoleList](ctrl)
+	crs.EXPECT().Create(gomock.Any()).DoAndReturn(func(cr *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error) {
+		require.Len(t, cr.Rules, 1)
+		assert.Equal(t, []string{"organizations"}, cr.Rules[0].Resources)
+		assert.Equal(t, []string{"acme"}, cr.Rules[0].ResourceNames)
+		assert.Equal(t, []string{"get"}, cr.Rules[0].Verbs)
+		return cr, nil
+	})
+
+	// The admins of the binding are updated.
+	crbCache := fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRoleBinding](ctrl)
+	crbCache.EXPECT().Get("organization-acme-admin").Return(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "organization-acme-admin"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "u-bob"}},
+	}, nil)
+	crbs := fake.NewMockNonNamespacedClientInterface[*rbacv1.ClusterRoleBinding, *rbacv1.ClusterRoleBindingList](ctrl)
+	crbs.EXPECT().Update(gomock.Any()).DoAndReturn(func(crb *rbacv1.ClusterRoleBinding) (*rbacv1.ClusterRoleBinding, error) {
+		assert.Equal(t, []rbacv1.Subject{
+			{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "u-alice"},
+			{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: ldapPlatform},
+		}, crb.Subjects)
+		return crb, nil
+	})
+
+	organizations := fake.NewMockNonNamespacedControllerInterface[*v3.Organization, *v3.OrganizationList](ctrl)
+	organizations.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(org *v3.Organization) (*v3.Organization, error) {
+		return org, nil
+	})
+
+	h := &handler{
+		organizations: organizations,
+		clusterCache:  clusterCache,
+		crtbs:         crtbs,
+		crtbCache:     crtbCache,
+		crs:           crs,
+		crCache:       crCache,
+		crbs:          crbs,
+		crbCache:      crbCache,
+	}
+	obj, err := h.OnChange("", org)
+	require.NoError(t, err)
+	assert.Equal(t, v3.OrganizationStatus{ObservedGeneration: 2, ClusterCount: 1, Clusters: []string{"c-abc"}}, obj.Status)
+}
+
+func TestOnChangeDeleting(t *testing.T) {
+	h := &handler{}
+	org := &v3.Organization{ObjectMeta: metav1.ObjectMeta{Name: "acme", DeletionTimestamp: &metav1.Time{}}}
+	obj, err := h.OnChange("", org)
+	require.NoError(t, err)
+	assert.Equal(t, org, obj)
+
+	obj, err = h.OnChange("", nil)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+}
+
+func TestBindingMeta(t *testing.T) {
+	org := &v3.Organization{ObjectMeta: metav1.ObjectMeta{Name: "acme"}}
+	meta := bindingMeta(org, "c-abc", v3.OrganizationAdmin{UserName: "u-alice"})
+	assert.Equal(t, meta.Name, bindingMeta(org, "c-abc", v3.OrganizationAdmin{UserName: "u-alice"}).Name)
+	assert.NotEqual(t, meta.Name, bindingMeta(org, "c-abc", v3.OrganizationAdmin{GroupPrincipalName: ldapPlatform}).Name)
+	assert.LessOrEqual(t, len(meta.Name), 63)
+}
@@ -0,0 +1,342 @@
+// Package orphanedbindings checks the ClusterRoleTemplateBindings and ProjectRoleTemplateBindings of the clusters for
+// subjects which don't exist anymore, records them in the OrphanedBindingReport of their cluster, and removes those
+// the report selects.
+package orphanedbindings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/local"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	reportControllerName = "orphaned-binding-report"
+	checkControllerName  = "orphaned-binding-check"
+
+	// ReasonUserNotFound is the reason of the bindings whose user was deleted, ReasonPrincipalNotFound of those whose
+	// principal isn't linked to any user, ReasonProviderRemoved of those whose principal's auth provider doesn't exist
+	// and ReasonProviderDisabled of those whose principal's auth provider is disabled.
+	ReasonUserNotFound      = "UserNotFound"
+	ReasonPrincipalNotFound = "PrincipalNotFound"
+	ReasonProviderRemoved   = "ProviderRemoved"
+	ReasonProviderDisabled  = "ProviderDisabled"
+
+	crtbKind = "ClusterRoleTemplateBinding"
+	prtbKind = "ProjectRoleTemplateBinding"
+
+	defaultCheckInterval = time.Hour
+)
+
+// Register registers the controllers creating the OrphanedBindingReports of the clusters and checking them
+// periodically.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	c := newController(management)
+	mgmt := management.Wrangler.Mgmt
+	mgmt.Cluster().OnChange(ctx, reportControllerName, c.ensureReport)
+	mgmt.OrphanedBindingReport().OnChange(ctx, checkControllerName, c.sync)
+}
+
+type controller struct {
+	reports         mgmtcontrollers.OrphanedBindingReportController
+	reportCache     mgmtcontrollers.OrphanedBindingReportCache
+	crtbs           mgmtcontrollers.ClusterRoleTemplateBindingClient
+	crtbCache       mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbs           mgmtcontrollers.ProjectRoleTemplateBindingClient
+	prtbCache       mgmtcontrollers.ProjectRoleTemplateBindingCache
+	userCache       mgmtcontrollers.UserCache
+	authConfigCache mgmtcontrollers.AuthConfigCache
+	// autoCleanupAllowed returns whether the reports enabling autoCleanup may remove the orphaned bindings.
+	autoCleanupAllowed func() bool
+	now                func() time.Time
+}
+
+func newController(management *config.ManagementContext) *controller {
+	mgmt := management.Wrangler.Mgmt
+	return &controller{
+		reports:         mgmt.OrphanedBindingReport(),
+		reportCache:     mgmt.OrphanedBindingReport().Cache(),
+		crtbs:           mgmt.ClusterRoleTemplateBinding(),
+		crtbCache:       mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbs:           mgmt.ProjectRoleTemplateBinding(),
+		prtbCache:       mgmt.ProjectRoleTemplateBinding().Cache(),
+		userCache:       mgmt.User().Cache(),
+		authConfigCache: mgmt.AuthConfig().Cache(),
+		autoCleanupAllowed: func() bool {
+			return settings.OrphanedBindingAutoCleanupEnabled.Get() == "true"
+		},
+		now: time.Now,
+	}
+}
+
+// ensureReport creates the report of the cluster, owned by the cluster so that it's removed with it.
+func (c *controller) ensureReport(_ string, cluster *v3.Cluster) (*v3.Cluster, error) {
+	if cluster == nil || cluster.DeletionTimestamp != nil {
+		return cluster, nil
+	}
+	if _, err := c.reportCache.Get(cluster.Name); !apierrors.IsNotFound(err) {
+		return cluster, err
+	}
+	_, err := c.reports.Create(&v3.OrphanedBindingReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: cluster.Name,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v3.SchemeGroupVersion.String(),
+				Kind:       "Cluster",
+				Name:       cluster.Name,
+				UID:        cluster.UID,
+			}},
+		},
+		Spec: v3.OrphanedBindingReportSpec{ClusterName: cluster.Name},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return cluster, fmt.Errorf("creating the OrphanedBindingReport of cluster %s: %w", cluster.Name, err)
+	}
+	return cluster, nil
+}
+
+// sync checks the cluster of the report once the check interval has passed since the last check, or right away when
+// the report was edited since, and removes the orphaned bindings the report selects.
+func (c *controller) sync(_ string, report *v3.OrphanedBindingReport) (*v3.OrphanedBindingReport, error) {
+	if report == nil || report.DeletionTimestamp != nil || report.Spec.ClusterName == "" {
+		return report, nil
+	}
+	interval := time.Duration(settings.OrphanedBindingCheckIntervalMinutes.GetInt()) * time.Minute
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	if report.Status.LastCheckTime != nil && report.Status.ObservedGeneration == report.Generation {
+		if remaining := report.Status.LastCheckTime.Add(interval).Sub(c.now()); remaining > 0 {
+			c.reports.EnqueueAfter(report.Name, remaining)
+			return report, nil
+		}
+	}
+
+	report = report.DeepCopy()
+	report.Status.Error = ""
+	orphaned, err := c.check(report.Spec.ClusterName)
+	if err != nil {
+		logrus.Errorf("[%s] failed to check cluster %s: %v", checkControllerName, report.Spec.ClusterName, err)
+		report.Status.Error = err.Error()
+	} else {
+		c.cleanup(report, orphaned)
+		report.Status.Bindings = orphaned
+		report.Status.OrphanedCount = len(orphaned)
+		if len(orphaned) > 0 {
+			logrus.Infof("[%s] found %d orphaned role template bindings in cluster %s", checkControllerName, len(orphaned), report.Spec.ClusterName)
+		}
+	}
+	now := metav1.NewTime(c.now())
+	report.Status.LastCheckTime = &now
+	report.Status.ObservedGeneration = report.Generation
+	return c.reports.UpdateStatus(report)
+}
+
+// subjects are the users and auth providers the subjects of the bindings are resolved against.
+type subjects struct {
+	users      map[string]bool
+	principals map[string]bool
+	// providers are whether the auth configs are enabled, by name.
+	providers map[string]bool
+}
+
+// check returns the orphaned bindings of the cluster, sorted by kind and name.
+func (c *controller) check(clusterName string) ([]v3.OrphanedBinding, error) {
+	s, err := c.subjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphaned []v3.OrphanedBinding
+	crtbs, err := c.crtbCache.List(clusterName, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing the ClusterRoleTemplateBindings of cluster %s: %w", clusterName, err)
+	}
+	for _, crtb := range crtbs {
+		if crtb.DeletionTimestamp != nil || crtb.ClusterName != clusterName {
+			continue
+		}
+		if binding, ok := s.orphaned(crtbKind, crtb.ObjectMeta, crtb.RoleTemplateName, crtb.UserName, crtb.UserPrincipalName, crtb.GroupPrincipalName); ok {
+			orphaned = append(orphaned, binding)
+		}
+	}
+
+	prtbs, err := c.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ProjectRoleTemplateBindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		if prtb.DeletionTimestamp != nil || prtb.ServiceAccount != "" || !strings.HasPrefix(prtb.ProjectName, clusterName+":") {
+			continue
+		}
+		if binding, ok := s.orphaned(prtbKind, prtb.ObjectMeta, prtb.RoleTemplateName, prtb.UserName, prtb.UserPrincipalName, prtb.GroupPrincipalName); ok {
+			orphaned = append(orphaned, binding)
+		}
+	}
+
+	sort.Slice(orphaned, func(i, j int) bool {
+		if orphaned[i].Kind != orphaned[j].Kind {
+			return orphaned[i].Kind < orphaned[j].Kind
+		}
+		return orphaned[i].BindingName < orphaned[j].BindingName
+	})
+	return orphaned, nil
+}
+
+func (c *controller) subjects() (*subjects, error) {
+	s := &subjects{
+		users:      map[string]bool{},
+		principals: map[string]bool{},
+		providers:  map[string]bool{},
+	}
+	users, err := c.userCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	for _, user := range users {
+		s.users[user.Name] = true
+		for _, principal := range user.PrincipalIDs {
+			s.principals[principal] = true
+		}
+	}
+	authConfigs, err := c.authConfigCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing auth configs: %w", err)
+	}
+	for _, authConfig := range authConfigs {
+		s.providers[authConfig.Name] = authConfig.Enabled
+	}
+	return s, nil
+}
+
+// orphaned returns the report of a binding if its subject doesn't exist anymore. The bindings of users are orphaned
+// when the user was deleted, or when the user principal of the binding isn't linked to any user. The bindings of
+// principals are orphaned when their auth provider was removed or disabled. The bindings of Kubernetes groups and
+// service accounts are never orphaned, as they aren't managed by Rancher.
+func (s *subjects) orphaned(kind string, meta metav1.ObjectMeta, roleTemplateName, userName, userPrincipalName, groupPrincipalName string) (v3.OrphanedBinding, bool) {
+	binding := v3.OrphanedBinding{
+		Kind:             kind,
+		BindingName:      meta.Namespace + ":" + meta.Name,
+		RoleTemplateName: roleTemplateName,
+	}
+	switch {
+	case userName != "":
+		binding.Subject = userName
+		if !s.users[userName] {
+			binding.Reason = ReasonUserNotFound
+			binding.Message = fmt.Sprintf("user %s doesn't exist", userName)
+			return binding, true
+		}
+		if userPrincipalName != "" && !s.principals[userPrincipalName] {
+			binding.Reason = ReasonPrincipalNotFound
+			binding.Message = fmt.Sprintf("principal %s isn't linked to user %s", userPrincipalName, userName)
+			return binding, true
+		}
+	case userPrincipalName != "":
+		binding.Subject = userPrincipalName
+		if reason, message, ok := s.providerMissing(userPrincipalName); ok {
+			binding.Reason, binding.Message = reason, message
+			return binding, true
+		}
+		if !s.principals[userPrincipalName] {
+			binding.Reason = ReasonPrincipalNotFound
+			binding.Message = fmt.Sprintf("principal %s isn't linked to any user", userPrincipalName)
+			return binding, true
+		}
+	case groupPrincipalName != "":
+		binding.Subject = groupPrincipalName
+		if reason, message, ok := s.providerMissing(groupPrincipalName); ok {
+			binding.Reason, binding.Message = reason, message
+			return binding, true
+		}
+	}
+	return binding, false
+}
+
+// providerMissing returns the reason a principal can't be resolved anymore when its auth provider was removed or
+// disabled. The principals of local users, and those whose provider can't be told, can always be resolved.
+func (s *subjects) providerMissing(principal string) (string, string, bool) {
+	provider := providerName(principal)
+	if provider == "" || provider == local.Name {
+		return "", "", false
+	}
+	enabled, ok := s.providers[provider]
+	if !ok {
+		return ReasonProviderRemoved, fmt.Sprintf("auth provider %s of principal %s doesn't exist", provider, principal), true
+	}
+	if !enabled {
+		return ReasonProviderDisabled, fmt.Sprintf("auth provider %s of principal %s is disabled", provider, principal), true
+	}
+	return "", "", false
+}
+
+// providerName returns the name of the auth provider of a principal, the prefix of its scheme, such as github for
+// github_user://1234.
+func providerName(principal string) string {
+	scheme, _, ok := strings.Cut(principal, "://")
+	if !ok {
+		return ""
+	}
+	provider, _, ok := strings.Cut(scheme, "_")
+	if !ok {
+		return ""
+	}
+	return provider
+}
+
+// cleanup removes the orphaned bindings listed in the cleanupBindings of the report, or all of them when the report
+// enables autoCleanup and the orphaned-binding-auto-cleanup-enabled setting allows it. The bindings failing to be
+// removed are reported again by the next check.
+func (c *controller) cleanup(report *v3.OrphanedBindingReport, orphaned []v3.OrphanedBinding) {
+	selected := map[string]bool{}
+	for _, name := range report.Spec.CleanupBindings {
+		selected[name] = true
+	}
+	all := report.Spec.AutoCleanup && c.autoCleanupAllowed()
+	if report.Spec.AutoCleanup && !all {
+		logrus.Debugf("[%s] autoCleanup of report %s is ignored, as the %s setting is false", checkControllerName, report.Name, settings.OrphanedBindingAutoCleanupEnabled.Name)
+	}
+
+	for i := range orphaned {
+		binding := &orphaned[i]
+		if !all && !selected[binding.BindingName] {
+			continue
+		}
+		namespace, name, _ := strings.Cut(binding.BindingName, ":")
+		var err error
+		if binding.Kind == crtbKind {
+			err = c.crtbs.Delete(namespace, name, &metav1.DeleteOptions{})
+		} else {
+			err = c.prtbs.Delete(namespace, name, &metav1.DeleteOptions{})
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			logrus.Errorf("[%s] failed to remove orphaned %s %s: %v", checkControllerName, binding.Kind, binding.BindingName, err)
+			continue
+		}
+		binding.Removed = true
+		auditRemovedBinding(report.Spec.ClusterName, binding)
+	}
+}
+
+func auditRemovedBinding(clusterName string, binding *v3.OrphanedBinding) {
+	logrus.WithFields(logrus.Fields{
+		"event":            "OrphanedBindingRemoved",
+		"cluster":          clusterName,
+		"kind":             binding.Kind,
+		"bindingName":      binding.BindingName,
+		"roleTemplateName": binding.RoleTemplateName,
+		"subject":          binding.Subject,
+		"reason":           binding.Reason,
+	}).Info("orphaned-binding: audit")
+}
@@ -0,0 +1,225 @@
+package orphanedbindings
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type mocks struct {
+	reports *fake.MockNonNamespacedControllerInterface[*v3.OrphanedBindingReport, *v3.OrphanedBindingReportList]
+	crtbs   *fake.MockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList]
+	prtbs   *fake.MockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList]
+}
+
+// setup returns a controller where, for cluster c-abcde:
+// - crtb-deleted-user binds the deleted user u-gone,
+// - crtb-unlinked binds u-abc with a principal not linked to it anymore,
+// - prtb-removed-provider binds a group of the removed openldap provider,
+// - prtb-disabled-provider binds a user principal of the disabled github provider,
+// and the other bindings aren't orphaned.
+func setup(t *testing.T, now time.Time) (*controller, *mocks) {
+	ctrl := gomock.NewController(t)
+
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("c-abcde", gomock.Any()).Return([]*v3.ClusterRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-ok", Namespace: "c-abcde"}, ClusterName: "c-abcde", UserName: "u-abc", UserPrincipalName: "local://u-abc", RoleTemplateName: "cluster-member"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-deleted-user", Namespace: "c-abcde"}, ClusterName: "c-abcde", UserName: "u-gone", RoleTemplateName: "cluster-member"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-unlinked", Namespace: "c-abcde"}, ClusterName: "c-abcde", UserName: "u-abc", UserPrincipalName: "azuread_user://1234", RoleTemplateName: "cluster-owner"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-k8s-group", Namespace: "c-abcde"}, ClusterName: "c-abcde", GroupName: "system:authenticated", RoleTemplateName: "cluster-member"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-deleting", Namespace: "c-abcde", DeletionTimestamp: &metav1.Time{}}, ClusterName: "c-abcde", UserName: "u-gone", RoleTemplateName: "cluster-member"},
+	}, nil).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", gomock.Any()).Return([]*v3.ProjectRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-ok", Namespace: "p-xyz"}, ProjectName: "c-abcde:p-xyz", GroupPrincipalName: "azuread_group://devs", RoleTemplateName: "project-member"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-removed-provider", Namespace: "p-xyz"}, ProjectName: "c-abcde:p-xyz", GroupPrincipalName: "openldap_group://cn=devs", RoleTemplateName: "project-member"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-disabled-provider", Namespace: "p-xyz"}, ProjectName: "c-abcde:p-xyz", UserPrincipalName: "github_user://42", RoleTemplateName: "project-owner"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-other-cluster", Namespace: "p-other"}, ProjectName: "c-other:p-other", UserName: "u-gone", RoleTemplateName: "project-member"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-service-account", Namespace: "p-xyz"}, ProjectName: "c-abcde:p-xyz", ServiceAccount: "p-xyz:deployer", RoleTemplateName: "project-member"},
+	}, nil).AnyTimes()
+
+	userCache := fake.NewMockNonNamespacedCacheInterface[*v3.User](ctrl)
+	userCache.EXPECT().List(gomock.Any()).Return([]*v3.User{
+		{ObjectMeta: metav1.ObjectMeta{Name: "u-abc"}, PrincipalIDs: []string{"local://u-abc"}},
+	}, nil).AnyTimes()
+	authConfigCache := fake.NewMockNonNamespacedCacheInterface[*v3.AuthConfig](ctrl)
+	authConfigCache.EXPECT().List(gomock.Any()).Return([]*v3.AuthConfig{
+		{ObjectMeta: metav1.ObjectMeta{Name: "azuread"}, Enabled: true},
+		{ObjectMeta: metav1.ObjectMeta{Name: "github"}},
+	}, nil).AnyTimes()
+
+	m := &mocks{
+		reports: fake.NewMockNonNamespacedControllerInterface[*v3.OrphanedBindingReport, *v3.OrphanedBindingReportList](ctrl),
+		crtbs:   fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl),
+		prtbs:   fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl),
+	}
+	return &controller{
+		reports:            m.reports,
+		crtbs:              m.crtbs,
+		crtbCache:          crtbCache,
+		prtbs:              m.prtbs,
+		prtbCache:          prtbCache,
+		userCache:          userCache,
+		authConfigCache:    authConfigCache,
+		autoCleanupAllowed: func() bool { return false },
+		now:                func() time.Time { return now },
+	}, m
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	c, _ := setup(t, time.Now())
+
+	orphaned, err := c.check("c-abcde")
+	require.NoError(t, err)
+
+	assert.Equal(t, []v3.OrphanedBinding{
+		{
+			Kind:             crtbKind,
+			BindingName:      "c-abcde:crtb-deleted-user",
+			RoleTemplateName: "cluster-member",
+			Subject:          "u-gone",
+			Reason:           ReasonUserNotFound,
+			Message:          "user u-gone doesn't exist",
+		},
+		{
+			Kind:             crtbKind,
+			BindingName:      "c-abcde:crtb-unlinked",
+			RoleTemplateName: "cluster-owner",
+			Subject:          "u-abc",
+			Reason:           ReasonPrincipalNotFound,
+			Message:          "principal azuread_user://1234 isn't linked to user u-abc",
+		},
+		{
+			Kind:             prtbKind,
+			BindingName:      "p-xyz:prtb-disabled-provider",
+			RoleTemplateName: "project-owner",
+			Subject:          "github_user://42",
+			Reason:           ReasonProviderDisabled,
+			Message:          "auth provider github of principal github_user://42 is disabled",
+		},
+		{
+			Kind:             prtbKind,
+			BindingName:      "p-xyz:prtb-removed-provider",
+			RoleTemplateName: "project-member",
+			Subject:          "openldap_group://cn=devs",
+			Reason:           ReasonProviderRemoved,
+			Message:          "auth provider openldap of principal openldap_group://cn=devs doesn't exist",
+		},
+	}, orphaned)
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("removes the bindings selected for cleanup", func(t *testing.T) {
+		t.Parallel()
+		c, m := setup(t, now)
+		report := &v3.OrphanedBindingReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", Generation: 2},
+			Spec: v3.OrphanedBindingReportSpec{
+				ClusterName:     "c-abcde",
+				CleanupBindings: []string{"c-abcde:crtb-deleted-user", "c-abcde:crtb-ok", "p-xyz:prtb-removed-provider"},
+			},
+		}
+		m.crtbs.EXPECT().Delete("c-abcde", "crtb-deleted-user", gomock.Any()).Return(nil)
+		m.prtbs.EXPECT().Delete("p-xyz", "prtb-removed-provider", gomock.Any()).Return(nil)
+		m.reports.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(report *v3.OrphanedBindingReport) (*v3.OrphanedBindingReport, error) {
+			return report, nil
+		})
+
+		updated, err := c.sync("", report)
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, updated.Status.OrphanedCount)
+		assert.Equal(t, int64(2), updated.Status.ObservedGeneration)
+		assert.Equal(t, now, updated.Status.LastCheckTime.Time)
+		removed := map[string]bool{}
+		for _, binding := range updated.Status.Bindings {
+			removed[binding.BindingName] = binding.Removed
+		}
+		assert.Equal(t, map[string]bool{
+			"c-abcde:crtb-deleted-user":    true,
+			"c-abcde:crtb-unlinked":        false,
+			"p-xyz:prtb-disabled-provider": false,
+			"p-xyz:prtb-removed-provider":  true,
+		}, removed)
+	})
+
+	t.Run("auto cleanup is ignored unless the setting allows it", func(t *testing.T) {
+		t.Parallel()
+		c, m := setup(t, now)
+		report := &v3.OrphanedBindingReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "c-abcde"},
+			Spec:       v3.OrphanedBindingReportSpec{ClusterName: "c-abcde", AutoCleanup: true},
+		}
+		m.reports.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(report *v3.OrphanedBindingReport) (*v3.OrphanedBindingReport, error) {
+			return report, nil
+		})
+
+		updated, err := c.sync("", report)
+		require.NoError(t, err)
+		for _, binding := range updated.Status.Bindings {
+			assert.False(t, binding.Removed, binding.BindingName)
+		}
+	})
+
+	t.Run("auto cleanup removes all the orphaned bindings", func(t *testing.T) {
+		t.Parallel()
+		c, m := setup(t, now)
+		c.autoCleanupAllowed = func() bool { return true }
+		report := &v3.OrphanedBindingReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "c-abcde"},
+			Spec:       v3.OrphanedBindingReportSpec{ClusterName: "c-abcde", AutoCleanup: true},
+		}
+		m.crtbs.EXPECT().Delete("c-abcde", gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		m.prtbs.EXPECT().Delete("p-xyz", gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		m.reports.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(report *v3.OrphanedBindingReport) (*v3.OrphanedBindingReport, error) {
+			return report, nil
+		})
+
+		updated, err := c.sync("", report)
+		require.NoError(t, err)
+		require.Len(t, updated.Status.Bindings, 4)
+		for _, binding := range updated.Status.Bindings {
+			assert.True(t, binding.Removed, binding.BindingName)
+		}
+	})
+
+	t.Run("waits for the check interval", func(t *testing.T) {
+		t.Parallel()
+		c, m := setup(t, now)
+		checked := metav1.NewTime(now.Add(-10 * time.Minute))
+		report := &v3.OrphanedBindingReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", Generation: 1},
+			Spec:       v3.OrphanedBindingReportSpec{ClusterName: "c-abcde"},
+			Status:     v3.OrphanedBindingReportStatus{LastCheckTime: &checked, ObservedGeneration: 1},
+		}
+		m.reports.EXPECT().EnqueueAfter("c-abcde", 50*time.Minute)
+
+		_, err := c.sync("", report)
+		require.NoError(t, err)
+	})
+}
+
+func TestProviderName(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"github_user://1234":        "github",
+		"openldap_group://cn=devs":  "openldap",
+		"local://u-abc":             "",
+		"system://provisioning":     "",
+		"not-a-principal":           "",
+		"activedirectory_user://ab": "activedirectory",
+	}
+	for principal, want := range tests {
+		assert.Equal(t, want, providerName(principal), principal)
+	}
+}
@@ -0,0 +1,210 @@
+// Package projecthierarchy cascades the memberships of projects down to their child projects. The
+// ProjectRoleTemplateBindings of a parent project are copied into its children, and the copies are copied again into
+// the grandchildren, so that the members of a project are members of all its descendants.
+package projecthierarchy
+
+import (
+	"context"
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	pkgproject "github.com/rancher/rancher/pkg/project"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const (
+	projectHandler  = "mgmt-project-hierarchy-handler"
+	bindingEnqueuer = "mgmt-project-hierarchy-binding"
+	projectEnqueuer = "mgmt-project-hierarchy-project"
+
+	projectByNameIndex   = "management.cattle.io/project-by-name"
+	projectByParentIndex = "management.cattle.io/project-by-parent"
+
+	// InheritedFromLabel is set on the ProjectRoleTemplateBindings copied from a parent project to the name of the
+	// parent project.
+	InheritedFromLabel = "authz.management.cattle.io/inherited-from-project"
+	// InheritedFromAnnotation is set on the ProjectRoleTemplateBindings copied from a parent project to the namespaced
+	// name, as <namespace>:<name>, of the binding they were copied from.
+	InheritedFromAnnotation = "authz.management.cattle.io/inherited-from-binding"
+)
+
+type handler struct {
+	projectCache mgmtcontrollers.ProjectCache
+	prtbs        mgmtcontrollers.ProjectRoleTemplateBindingClient
+	prtbCache    mgmtcontrollers.ProjectRoleTemplateBindingCache
+}
+
+// Register registers the handler of the projects, and enqueues them on the changes of the bindings of their parent
+// and of their own, and on the changes of their parent.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	mgmt := management.Wrangler.Mgmt
+	mgmt.Project().Cache().AddIndexer(projectByNameIndex, projectByName)
+	mgmt.Project().Cache().AddIndexer(projectByParentIndex, projectByParent)
+	h := &handler{
+		projectCache: mgmt.Project().Cache(),
+		prtbs:        mgmt.ProjectRoleTemplateBinding(),
+		prtbCache:    mgmt.ProjectRoleTemplateBinding().Cache(),
+	}
+	projects := mgmt.Project()
+	projects.OnChange(ctx, projectHandler, h.OnChange)
+	relatedresource.Watch(ctx, bindingEnqueuer, h.enqueueBindingProjects, projects, mgmt.ProjectRoleTemplateBinding())
+	relatedresource.Watch(ctx, projectEnqueuer, h.enqueueChildren, projects, projects)
+}
+
+func projectByName(project *v3.Project) ([]string, error) {
+	return []string{project.Name}, nil
+}
+
+func projectByParent(project *v3.Project) ([]string, error) {
+	if project.Spec.ParentProject == "" {
+		return nil, nil
+	}
+	return []string{project.Spec.ParentProject}, nil
+}
+
+// OnChange copies the ProjectRoleTemplateBindings of the parent of the project into it, and deletes the copies of the
+// bindings which were removed from the parent or changed, or of a former parent. Projects with an invalid hierarchy,
+// whose ancestors are missing, too deep or include the project itself, don't inherit any binding.
+func (h *handler) OnChange(_ string, project *v3.Project) (*v3.Project, error) {
+	if project == nil || project.DeletionTimestamp != nil {
+		return project, nil
+	}
+	desired, err := h.desiredBindings(project)
+	if err != nil {
+		return project, err
+	}
+
+	inherited, err := labels.NewRequirement(InheritedFromLabel, selection.Exists, nil)
+	if err != nil {
+		return project, err
+	}
+	existing, err := h.prtbCache.List(project.Name, labels.NewSelector().Add(*inherited))
+	if err != nil {
+		return project, fmt.Errorf("listing the inherited ProjectRoleTemplateBindings of project %s: %w", project.Name, err)
+	}
+	for _, binding := range existing {
+		if want, ok := desired[binding.Name]; ok && sameBinding(binding, want) {
+			delete(desired, binding.Name)
+			continue
+		}
+		if binding.DeletionTimestamp != nil || !provenance.Owns(binding, provenance.SourceProjectHierarchy) {
+			continue
+		}
+		if err := h.prtbs.Delete(binding.Namespace, binding.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return project, fmt.Errorf("deleting inherited ProjectRoleTemplateBinding %s/%s: %w", binding.Namespace, binding.Name, err)
+		}
+		logrus.Infof("[%s] revoked role %s inherited from project %s in project %s", projectHandler, binding.RoleTemplateName, binding.Labels[InheritedFromLabel], binding.ProjectName)
+	}
+	for _, binding := range desired {
+		if _, err := h.prtbs.Create(binding); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// The binding being replaced is still being deleted, the project is enqueued again once it's removed.
+				continue
+			}
+			return project, fmt.Errorf("creating inherited ProjectRoleTemplateBinding %s/%s: %w", binding.Namespace, binding.Name, err)
+		}
+		logrus.Infof("[%s] granted role %s inherited from project %s in project %s", projectHandler, binding.RoleTemplateName, binding.Labels[InheritedFromLabel], binding.ProjectName)
+	}
+	return project, nil
+}
+
+// desiredBindings returns the copies of the bindings of the parent of the project, by name. The bindings of service
+// accounts aren't copied, as service accounts belong to the namespaces of their project.
+func (h *handler) desiredBindings(project *v3.Project) (map[string]*v3.ProjectRoleTemplateBinding, error) {
+	desired := map[string]*v3.ProjectRoleTemplateBinding{}
+	if project.Spec.ParentProject == "" {
+		return desired, nil
+	}
+	ancestors, err := pkgproject.Ancestors(project, h.projectCache)
+	if err != nil {
+		logrus.Warnf("[%s] project %s/%s doesn't inherit the members of its ancestors: %v", projectHandler, project.Namespace, project.Name, err)
+		return desired, nil
+	}
+	parent := ancestors[0]
+	if parent.DeletionTimestamp != nil {
+		return desired, nil
+	}
+
+	bindings, err := h.prtbCache.List(parent.Name, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing the ProjectRoleTemplateBindings of project %s: %w", parent.Name, err)
+	}
+	for _, source := range bindings {
+		if source.DeletionTimestamp != nil || source.ServiceAccount != "" {
+			continue
+		}
+		binding := &v3.ProjectRoleTemplateBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name.SafeConcatName("inherited", name.Hex(source.Namespace+"/"+source.Name, 10)),
+				Namespace:   project.Name,
+				Labels:      map[string]string{InheritedFromLabel: parent.Name},
+				Annotations: map[string]string{InheritedFromAnnotation: source.Namespace + ":" + source.Name},
+			},
+			ProjectName:        project.Namespace + ":" + project.Name,
+			UserName:           source.UserName,
+			UserPrincipalName:  source.UserPrincipalName,
+			GroupName:          source.GroupName,
+			GroupPrincipalName: source.GroupPrincipalName,
+			RoleTemplateName:   source.RoleTemplateName,
+			NamespaceSelector:  source.NamespaceSelector.DeepCopy(),
+		}
+		provenance.Set(&binding.ObjectMeta, provenance.SourceProjectHierarchy, provenance.Creator("Project", parent.Name))
+		desired[binding.Name] = binding
+	}
+	return desired, nil
+}
+
+// sameBinding returns whether an inherited binding grants what the desired one does. The user name and principal are
+// only compared when the desired binding sets them, as Rancher fills in the missing one.
+func sameBinding(existing, desired *v3.ProjectRoleTemplateBinding) bool {
+	return existing.Labels[InheritedFromLabel] == desired.Labels[InheritedFromLabel] &&
+		existing.RoleTemplateName == desired.RoleTemplateName &&
+		existing.GroupName == desired.GroupName &&
+		existing.GroupPrincipalName == desired.GroupPrincipalName &&
+		(desired.UserName == "" || existing.UserName == desired.UserName) &&
+		(desired.UserPrincipalName == "" || existing.UserPrincipalName == desired.UserPrincipalName) &&
+		equality.Semantic.DeepEqual(existing.NamespaceSelector, desired.NamespaceSelector)
+}
+
+// enqueueBindingProjects enqueues the children of the project of a changed binding, whose copies of the binding
+// change along, and the project itself, whose copies are restored when they're edited or deleted.
+func (h *handler) enqueueBindingProjects(namespace, _ string, _ runtime.Object) ([]relatedresource.Key, error) {
+	var keys []relatedresource.Key
+	for _, index := range []string{projectByNameIndex, projectByParentIndex} {
+		projects, err := h.projectCache.GetByIndex(index, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("getting the projects by index %s: %w", index, err)
+		}
+		for _, project := range projects {
+			keys = append(keys, relatedresource.Key{Namespace: project.Namespace, Name: project.Name})
+		}
+	}
+	return keys, nil
+}
+
+// enqueueChildren enqueues the children of a changed project, which inherit the bindings of their parent only while
+// it exists.
+func (h *handler) enqueueChildren(namespace, name string, _ runtime.Object) ([]relatedresource.Key, error) {
+	children, err := h.projectCache.GetByIndex(projectByParentIndex, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting the children of project %s: %w", name, err)
+	}
+	var keys []relatedresource.Key
+	for _, child := range children {
+		if child.Namespace == namespace {
+			keys = append(keys, relatedresource.Key{Namespace: child.Namespace, Name: child.Name})
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,159 @@
+package projecthierarchy
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/provenance"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	parentProject = &v3.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "p-parent", Namespace: "c-abcde"},
+		Spec:       v3.ProjectSpec{ClusterName: "c-abcde"},
+	}
+	childProject = &v3.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "p-child", Namespace: "c-abcde"},
+		Spec:       v3.ProjectSpec{ClusterName: "c-abcde", ParentProject: "p-parent"},
+	}
+	ownerBinding = &v3.ProjectRoleTemplateBinding{
+		ObjectMeta:       metav1.ObjectMeta{Name: "prtb-owner", Namespace: "p-parent"},
+		ProjectName:      "c-abcde:p-parent",
+		UserName:         "u-abc",
+		RoleTemplateName: "project-owner",
+	}
+	inheritedOwnerName = name.SafeConcatName("inherited", name.Hex("p-parent/prtb-owner", 10))
+)
+
+func newHandler(t *testing.T, parentBindings, childBindings []*v3.ProjectRoleTemplateBinding) (*handler, *fake.MockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList]) {
+	ctrl := gomock.NewController(t)
+	projectCache := fake.NewMockCacheInterface[*v3.Project](ctrl)
+	projectCache.EXPECT().Get("c-abcde", gomock.Any()).DoAndReturn(func(_, projectName string) (*v3.Project, error) {
+		if projectName == parentProject.Name {
+			return parentProject, nil
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "projects"}, projectName)
+	}).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("p-parent", gomock.Any()).Return(parentBindings, nil).AnyTimes()
+	prtbCache.EXPECT().List("p-child", gomock.Any()).Return(childBindings, nil).AnyTimes()
+	prtbs := fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	return &handler{
+		projectCache: projectCache,
+		prtbs:        prtbs,
+		prtbCache:    prtbCache,
+	}, prtbs
+}
+
+func TestOnChange(t *testing.T) {
+	t.Parallel()
+	inheritedOwner := func() *v3.ProjectRoleTemplateBinding {
+		return &v3.ProjectRoleTemplateBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        inheritedOwnerName,
+				Namespace:   "p-child",
+				Labels:      map[string]string{InheritedFromLabel: "p-parent", provenance.SourceLabel: provenance.SourceProjectHierarchy},
+				Annotations: map[string]string{InheritedFromAnnotation: "p-parent:prtb-owner", provenance.CreatorAnnotation: "project/p-parent"},
+			},
+			ProjectName:      "c-abcde:p-child",
+			UserName:         "u-abc",
+			RoleTemplateName: "project-owner",
+		}
+	}
+
+	t.Run("copies the bindings of the parent", func(t *testing.T) {
+		t.Parallel()
+		serviceAccountBinding := &v3.ProjectRoleTemplateBinding{
+			ObjectMeta:       metav1.ObjectMeta{Name: "prtb-sa", Namespace: "p-parent"},
+			ProjectName:      "c-abcde:p-parent",
+			ServiceAccount:   "ns-a:deployer",
+			RoleTemplateName: "project-member",
+		}
+		h, prtbs := newHandler(t, []*v3.ProjectRoleTemplateBinding{ownerBinding, serviceAccountBinding}, nil)
+		prtbs.EXPECT().Create(inheritedOwner()).Return(inheritedOwner(), nil)
+
+		_, err := h.OnChange("", childProject)
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps the bindings already copied", func(t *testing.T) {
+		t.Parallel()
+		existing := inheritedOwner()
+		existing.UserPrincipalName = "local://u-abc"
+		h, _ := newHandler(t, []*v3.ProjectRoleTemplateBinding{ownerBinding}, []*v3.ProjectRoleTemplateBinding{existing})
+
+		_, err := h.OnChange("", childProject)
+		require.NoError(t, err)
+	})
+
+	t.Run("replaces the copies of changed bindings", func(t *testing.T) {
+		t.Parallel()
+		existing := inheritedOwner()
+		existing.RoleTemplateName = "project-member"
+		h, prtbs := newHandler(t, []*v3.ProjectRoleTemplateBinding{ownerBinding}, []*v3.ProjectRoleTemplateBinding{existing})
+		gomock.InOrder(
+			prtbs.EXPECT().Delete("p-child", inheritedOwnerName, gomock.Any()).Return(nil),
+			prtbs.EXPECT().Create(inheritedOwner()).Return(nil, apierrors.NewAlreadyExists(schema.GroupResource{}, inheritedOwnerName)),
+		)
+
+		_, err := h.OnChange("", childProject)
+		require.NoError(t, err)
+	})
+
+	t.Run("deletes the copies when the project has no parent anymore", func(t *testing.T) {
+		t.Parallel()
+		h, prtbs := newHandler(t, []*v3.ProjectRoleTemplateBinding{ownerBinding}, []*v3.ProjectRoleTemplateBinding{inheritedOwner()})
+		prtbs.EXPECT().Delete("p-child", inheritedOwnerName, gomock.Any()).Return(nil)
+
+		project := childProject.DeepCopy()
+		project.Spec.ParentProject = ""
+		_, err := h.OnChange("", project)
+		require.NoError(t, err)
+	})
+
+	t.Run("deletes the copies when the parent is missing", func(t *testing.T) {
+		t.Parallel()
+		h, prtbs := newHandler(t, nil, []*v3.ProjectRoleTemplateBinding{inheritedOwner()})
+		prtbs.EXPECT().Delete("p-child", inheritedOwnerName, gomock.Any()).Return(nil)
+
+		project := childProject.DeepCopy()
+		project.Spec.ParentProject = "p-missing"
+		_, err := h.OnChange("", project)
+		require.NoError(t, err)
+	})
+
+	t.Run("leaves the copies taken over by another source", func(t *testing.T) {
+		t.Parallel()
+		existing := inheritedOwner()
+		existing.Labels[provenance.SourceLabel] = provenance.SourceTerraform
+		h, _ := newHandler(t, nil, []*v3.ProjectRoleTemplateBinding{existing})
+
+		project := childProject.DeepCopy()
+		project.Spec.ParentProject = ""
+		_, err := h.OnChange("", project)
+		require.NoError(t, err)
+	})
+}
+
+func TestEnqueueChildren(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	projectCache := fake.NewMockCacheInterface[*v3.Project](ctrl)
+	otherCluster := childProject.DeepCopy()
+	otherCluster.Namespace = "c-other"
+	projectCache.EXPECT().GetByIndex(projectByParentIndex, "p-parent").Return([]*v3.Project{childProject, otherCluster}, nil)
+	h := &handler{projectCache: projectCache}
+
+	keys, err := h.enqueueChildren("c-abcde", "p-parent", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []relatedresource.Key{{Namespace: "c-abcde", Name: "p-child"}}, keys)
+}
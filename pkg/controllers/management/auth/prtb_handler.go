@@ -4,10 +4,12 @@
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rancher/rancher/pkg/controllers/management/authprovisioningv2"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	typesrbacv1 "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1"
+	"github.com/rancher/rancher/pkg/metrics"
 	pkgrbac "github.com/rancher/rancher/pkg/rbac"
 	"github.com/rancher/rancher/pkg/user"
 	"github.com/sirupsen/logrus"
@@ -48,11 +50,15 @@ func (p *prtbLifecycle) Create(obj *v3.ProjectRoleTemplateBinding) (runtime.Obje
 	if obj.ServiceAccount != "" {
 		return obj, nil
 	}
+	started := time.Now()
+	clusterName, _, _ := strings.Cut(obj.ProjectName, ":")
 	obj, err := p.reconcileSubject(obj)
 	if err != nil {
+		metrics.ObserveRBACReconcile(ptrbMGMTController, clusterName, started, nil, err)
 		return nil, err
 	}
 	err = p.reconcileBindings(obj)
+	metrics.ObserveRBACReconcile(ptrbMGMTController, clusterName, started, nil, err)
 	return obj, err
 }
 
@@ -60,14 +66,19 @@ func (p *prtbLifecycle) Updated(obj *v3.ProjectRoleTemplateBinding) (runtime.Obj
 	if obj.ServiceAccount != "" {
 		return obj, nil
 	}
+	started := time.Now()
+	clusterName, _, _ := strings.Cut(obj.ProjectName, ":")
 	obj, err := p.reconcileSubject(obj)
 	if err != nil {
+		metrics.ObserveRBACReconcile(ptrbMGMTController, clusterName, started, nil, err)
 		return nil, err
 	}
 	if err := p.reconcileLabels(obj); err != nil {
+		metrics.ObserveRBACReconcile(ptrbMGMTController, clusterName, started, nil, err)
 		return nil, err
 	}
 	err = p.reconcileBindings(obj)
+	metrics.ObserveRBACReconcile(ptrbMGMTController, clusterName, started, nil, err)
 	return obj, err
 }
 
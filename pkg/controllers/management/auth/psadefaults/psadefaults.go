@@ -0,0 +1,207 @@
+// Package psadefaults applies the default Pod Security Admission templates of the GlobalRoles to the clusters created
+// by the users bound to them, so that the less privileged provisioners get hardened clusters. The clusters are
+// reverted to the template when they're changed to another one, so that their creators can't relax it.
+package psadefaults
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	provcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	clusterHandler              = "mgmt-psa-defaults-cluster-handler"
+	provisioningClusterHandler  = "mgmt-psa-defaults-provisioning-cluster-handler"
+	clusterEnqueuer             = "mgmt-psa-defaults-cluster-enqueuer"
+	provisioningClusterEnqueuer = "mgmt-psa-defaults-provisioning-cluster-enqueuer"
+
+	// creatorIDAnnotation is set on the clusters to the name of the user who created them.
+	creatorIDAnnotation = "field.cattle.io/creatorId"
+)
+
+type handler struct {
+	clusters           mgmtcontrollers.ClusterClient
+	clusterCache       mgmtcontrollers.ClusterCache
+	provClusters       provcontrollers.ClusterClient
+	provClusterCache   provcontrollers.ClusterCache
+	grCache            mgmtcontrollers.GlobalRoleCache
+	grbCache           mgmtcontrollers.GlobalRoleBindingCache
+	userAttributeCache mgmtcontrollers.UserAttributeCache
+}
+
+// Register registers the handlers of the management and provisioning clusters, and enqueues the clusters of the users
+// on the changes of their GlobalRoleBindings and GlobalRoles.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	mgmt := management.Wrangler.Mgmt
+	prov := management.Wrangler.Provisioning
+	h := &handler{
+		clusters:           mgmt.Cluster(),
+		clusterCache:       mgmt.Cluster().Cache(),
+		provClusters:       prov.Cluster(),
+		provClusterCache:   prov.Cluster().Cache(),
+		grCache:            mgmt.GlobalRole().Cache(),
+		grbCache:           mgmt.GlobalRoleBinding().Cache(),
+		userAttributeCache: mgmt.UserAttribute().Cache(),
+	}
+	mgmt.Cluster().OnChange(ctx, clusterHandler, h.OnCluster)
+	prov.Cluster().OnChange(ctx, provisioningClusterHandler, h.OnProvisioningCluster)
+	relatedresource.WatchClusterScoped(ctx, clusterEnqueuer, h.enqueueClusters, mgmt.Cluster(), mgmt.GlobalRoleBinding(), mgmt.GlobalRole())
+	relatedresource.Watch(ctx, provisioningClusterEnqueuer, h.enqueueProvisioningClusters, prov.Cluster(), mgmt.GlobalRoleBinding(), mgmt.GlobalRole())
+}
+
+// OnCluster applies the default template of the creator of the management cluster.
+func (h *handler) OnCluster(_ string, cluster *v3.Cluster) (*v3.Cluster, error) {
+	if cluster == nil || cluster.DeletionTimestamp != nil {
+		return cluster, nil
+	}
+	template, err := h.enforcedTemplate(cluster.Annotations[creatorIDAnnotation], cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName)
+	if err != nil || template == "" {
+		return cluster, err
+	}
+
+	logrus.Infof("[psadefaults] Applying the Pod Security Admission template %s of the roles of %s to cluster %s",
+		template, cluster.Annotations[creatorIDAnnotation], cluster.Name)
+	cluster = cluster.DeepCopy()
+	cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName = template
+	return h.clusters.Update(cluster)
+}
+
+// OnProvisioningCluster applies the default template of the creator of the provisioning cluster, which is copied to
+// its management cluster.
+func (h *handler) OnProvisioningCluster(_ string, cluster *provv1.Cluster) (*provv1.Cluster, error) {
+	if cluster == nil || cluster.DeletionTimestamp != nil {
+		return cluster, nil
+	}
+	template, err := h.enforcedTemplate(cluster.Annotations[creatorIDAnnotation], cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName)
+	if err != nil || template == "" {
+		return cluster, err
+	}
+
+	logrus.Infof("[psadefaults] Applying the Pod Security Admission template %s of the roles of %s to cluster %s/%s",
+		template, cluster.Annotations[creatorIDAnnotation], cluster.Namespace, cluster.Name)
+	cluster = cluster.DeepCopy()
+	cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName = template
+	return h.provClusters.Update(cluster)
+}
+
+// enforcedTemplate returns the template the cluster created by the user must be changed to, or an empty string if it
+// has one of the default templates of the GlobalRoles of the user, or if they have none. The template of the first
+// GlobalRole by name is applied.
+func (h *handler) enforcedTemplate(userName, current string) (string, error) {
+	if userName == "" {
+		return "", nil
+	}
+	templates, err := h.templates(userName)
+	if err != nil {
+		return "", err
+	}
+	if len(templates) == 0 || slices.Contains(templates, current) {
+		return "", nil
+	}
+	return templates[0], nil
+}
+
+// templates returns the default templates of the GlobalRoles bound to the user and to their groups, ordered by the
+// names of the GlobalRoles.
+func (h *handler) templates(userName string) ([]string, error) {
+	groups := map[string]bool{}
+	attribs, err := h.userAttributeCache.Get(userName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("getting the user attributes of %s: %w", userName, err)
+	}
+	if attribs != nil {
+		for _, principals := range attribs.GroupPrincipals {
+			for _, principal := range principals.Items {
+				groups[principal.Name] = true
+			}
+		}
+	}
+
+	grbs, err := h.grbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing global role bindings: %w", err)
+	}
+	var roleNames []string
+	for _, grb := range grbs {
+		if grb.UserName == userName || (grb.GroupPrincipalName != "" && groups[grb.GroupPrincipalName]) {
+			roleNames = append(roleNames, grb.GlobalRoleName)
+		}
+	}
+	sort.Strings(roleNames)
+
+	var templates []string
+	for _, roleName := range slices.Compact(roleNames) {
+		gr, err := h.grCache.Get(roleName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting global role %s: %w", roleName, err)
+		}
+		if template := gr.DefaultPodSecurityAdmissionConfigurationTemplateName; template != "" && !slices.Contains(templates, template) {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+// enqueueClusters enqueues the management clusters created by users on the changes of the GlobalRoleBindings, and of
+// the GlobalRoles with a default template.
+func (h *handler) enqueueClusters(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if !changesTemplates(obj) {
+		return nil, nil
+	}
+	clusters, err := h.clusterCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing clusters: %w", err)
+	}
+	var keys []relatedresource.Key
+	for _, cluster := range clusters {
+		if cluster.Annotations[creatorIDAnnotation] != "" {
+			keys = append(keys, relatedresource.Key{Name: cluster.Name})
+		}
+	}
+	return keys, nil
+}
+
+// enqueueProvisioningClusters enqueues the provisioning clusters created by users on the changes of the
+// GlobalRoleBindings, and of the GlobalRoles with a default template.
+func (h *handler) enqueueProvisioningClusters(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if !changesTemplates(obj) {
+		return nil, nil
+	}
+	clusters, err := h.provClusterCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing provisioning clusters: %w", err)
+	}
+	var keys []relatedresource.Key
+	for _, cluster := range clusters {
+		if cluster.Annotations[creatorIDAnnotation] != "" {
+			keys = append(keys, relatedresource.Key{Namespace: cluster.Namespace, Name: cluster.Name})
+		}
+	}
+	return keys, nil
+}
+
+// changesTemplates returns true if the object is a GlobalRoleBinding, or a GlobalRole with a default template.
+func changesTemplates(obj runtime.Object) bool {
+	switch o := obj.(type) {
+	case *v3.GlobalRoleBinding:
+		return true
+	case *v3.GlobalRole:
+		return o.DefaultPodSecurityAdmissionConfigurationTemplateName != ""
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package psadefaults
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const platform = "openldap_group://cn=platform,ou=groups,dc=example,dc=com"
+
+// newHandler returns a handler where alice is bound to the restricted and baseline provisioner roles, and bob to the
+// roles without a template.
+func newHandler(t *testing.T) (*handler, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	userAttributeCache.EXPECT().Get("u-alice").Return(&v3.UserAttribute{
+		GroupPrincipals: map[string]v3.Principals{
+			"openldap": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: platform}}}},
+		},
+	}, nil).AnyTimes()
+	userAttributeCache.EXPECT().Get("u-bob").Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "u-bob")).AnyTimes()
+
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl)
+	grbCache.EXPECT().List(labels.Everything()).Return([]*v3.GlobalRoleBinding{
+		{UserName: "u-alice", GlobalRoleName: "restricted-provisioner"},
+		{GroupPrincipalName: platform, GlobalRoleName: "baseline-provisioner"},
+		{UserName: "u-alice", GlobalRoleName: "user"},
+		{UserName: "u-bob", GlobalRoleName: "user"},
+		{UserName: "u-bob", GlobalRoleName: "removed"},
+	}, nil).AnyTimes()
+
+	grCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRole](ctrl)
+	grCache.EXPECT().Get("restricted-provisioner").Return(&v3.GlobalRole{DefaultPodSecurityAdmissionConfigurationTemplateName: "rancher-restricted"}, nil).AnyTimes()
+	grCache.EXPECT().Get("baseline-provisioner").Return(&v3.GlobalRole{DefaultPodSecurityAdmissionConfigurationTemplateName: "rancher-baseline"}, nil).AnyTimes()
+	grCache.EXPECT().Get("user").Return(&v3.GlobalRole{}, nil).AnyTimes()
+	grCache.EXPECT().Get("removed").Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "removed")).AnyTimes()
+
+	return &handler{
+		grCache:            grCache,
+		grbCache:           grbCache,
+		userAttributeCache: userAttributeCache,
+	}, ctrl
+}
+
+func TestTemplates(t *testing.T) {
+	h, _ := newHandler(t)
+
+	templates, err := h.templates("u-alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rancher-baseline", "rancher-restricted"}, templates)
+
+	templates, err = h.templates("u-bob")
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
+
+func TestEnforcedTemplate(t *testing.T) {
+	h, _ := newHandler(t)
+
+	for _, test := range []struct {
+		name    string
+		user    string
+		current string
+		want    string
+	}{
+		{name: "default", user: "u-alice", want: "rancher-baseline"},
+		{name: "relaxed", user: "u-alice", current: "rancher-privileged", want: "rancher-baseline"},
+		{name: "one of the templates of the roles", user: "u-alice", current: "rancher-restricted"},
+		{name: "roles without a template", user: "u-bob", current: "rancher-privileged"},
+		{name: "without creator", current: "rancher-privileged"},
+	} {
+		template, err := h.enforcedTemplate(test.user, test.current)
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.want, template, test.name)
+	}
+}
+
+func TestOnCluster(t *testing.T) {
+	h, ctrl := newHandler(t)
+	clusters := fake.NewMockNonNamespacedClientInterface[*v3.Cluster, *v3.ClusterList](ctrl)
+	clusters.EXPECT().Update(gomock.Any()).DoAndReturn(func(cluster *v3.Cluster) (*v3.Cluster, error) {
+		assert.Equal(t, "rancher-baseline", cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName)
+		return cluster, nil
+	})
+	h.clusters = clusters
+
+	cluster := &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", Annotations: map[string]string{creatorIDAnnotation: "u-alice"}}}
+	_, err := h.OnCluster("", cluster)
+	require.NoError(t, err)
+	assert.Empty(t, cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName, "the cached cluster is not modified")
+
+	// The clusters of the users without a template aren't updated.
+	bobs := &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-fghij", Annotations: map[string]string{creatorIDAnnotation: "u-bob"}}}
+	obj, err := h.OnCluster("", bobs)
+	require.NoError(t, err)
+	assert.Equal(t, bobs, obj)
+}
+
+func TestOnProvisioningCluster(t *testing.T) {
+	h, ctrl := newHandler(t)
+	provClusters := fake.NewMockClientInterface[*provv1.Cluster, *provv1.ClusterList](ctrl)
+	provClusters.EXPECT().Update(gomock.Any()).DoAndReturn(func(cluster *provv1.Cluster) (*provv1.Cluster, error) {
+		assert.Equal(t, "rancher-baseline", cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName)
+		return cluster, nil
+	})
+	h.provClusters = provClusters
+
+	cluster := &provv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "prod", Annotations: map[string]string{creatorIDAnnotation: "u-alice"}}}
+	cluster.Spec.DefaultPodSecurityAdmissionConfigurationTemplateName = "rancher-privileged"
+	_, err := h.OnProvisioningCluster("", cluster)
+	require.NoError(t, err)
+
+	obj, err := h.OnProvisioningCluster("", nil)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+}
+
+func TestEnqueueClusters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clusterCache := fake.NewMockNonNamespacedCacheInterface[*v3.Cluster](ctrl)
+	clusterCache.EXPECT().List(labels.Everything()).Return([]*v3.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "local"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", Annotations: map[string]string{creatorIDAnnotation: "u-alice"}}},
+	}, nil)
+	provClusterCache := fake.NewMockCacheInterface[*provv1.Cluster](ctrl)
+	provClusterCache.EXPECT().List("", labels.Everything()).Return([]*provv1.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "prod", Annotations: map[string]string{creatorIDAnnotation: "u-alice"}}},
+	}, nil)
+	h := &handler{clusterCache: clusterCache, provClusterCache: provClusterCache}
+
+	keys, err := h.enqueueClusters("", "grb-abcde", &v3.GlobalRoleBinding{})
+	require.NoError(t, err)
+	assert.Equal(t, []relatedresource.Key{{Name: "c-abcde"}}, keys)
+
+	keys, err = h.enqueueProvisioningClusters("", "restricted-provisioner", &v3.GlobalRole{DefaultPodSecurityAdmissionConfigurationTemplateName: "rancher-restricted"})
+	require.NoError(t, err)
+	assert.Equal(t, []relatedresource.Key{{Namespace: "fleet-default", Name: "prod"}}, keys)
+
+	// The changes of the roles without a template don't enqueue the clusters.
+	keys, err = h.enqueueClusters("", "user", &v3.GlobalRole{})
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
@@ -0,0 +1,99 @@
+// Package recertificationcampaigns closes the RecertificationCampaigns at their deadline, removing the bindings their
+// reviewers didn't approve.
+package recertificationcampaigns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/recertification"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const controllerName = "recertification-campaign-deadline"
+
+// Register registers the controller closing the RecertificationCampaigns at their deadline.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	c := newController(management)
+	management.Wrangler.Mgmt.RecertificationCampaign().OnChange(ctx, controllerName, c.sync)
+}
+
+type controller struct {
+	campaigns mgmtcontrollers.RecertificationCampaignController
+	crtbs     mgmtcontrollers.ClusterRoleTemplateBindingClient
+	prtbs     mgmtcontrollers.ProjectRoleTemplateBindingClient
+	now       func() time.Time
+}
+
+func newController(management *config.ManagementContext) *controller {
+	mgmt := management.Wrangler.Mgmt
+	return &controller{
+		campaigns: mgmt.RecertificationCampaign(),
+		crtbs:     mgmt.ClusterRoleTemplateBinding(),
+		prtbs:     mgmt.ProjectRoleTemplateBinding(),
+		now:       time.Now,
+	}
+}
+
+// sync closes the open campaign once its deadline has passed, and requeues it for its deadline otherwise. The bindings
+// which weren't decided on are removed and marked expired, and the revoked ones failing to be removed when they were
+// decided on are removed again. The campaign stays open, and is retried, until all of them are removed, the bindings
+// already removed being ignored.
+func (c *controller) sync(_ string, campaign *v3.RecertificationCampaign) (*v3.RecertificationCampaign, error) {
+	if campaign == nil || campaign.DeletionTimestamp != nil || campaign.Status.State != recertification.StateOpen {
+		return campaign, nil
+	}
+	if remaining := campaign.Spec.Deadline.Sub(c.now()); remaining > 0 {
+		c.campaigns.EnqueueAfter(campaign.Name, remaining)
+		return campaign, nil
+	}
+
+	campaign = campaign.DeepCopy()
+	now := metav1.NewTime(c.now())
+	var expired []*v3.RecertificationItem
+	var errs []error
+	for i := range campaign.Status.Items {
+		item := &campaign.Status.Items[i]
+		if item.Decision == recertification.DecisionApproved {
+			continue
+		}
+		if err := recertification.RemoveBinding(c.crtbs, c.prtbs, item); err != nil {
+			errs = append(errs, fmt.Errorf("removing %s %s: %w", item.Kind, item.BindingName, err))
+			continue
+		}
+		if item.Decision == "" {
+			item.Decision = recertification.DecisionExpired
+			item.DecidedAt = &now
+			expired = append(expired, item)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return campaign, fmt.Errorf("closing recertification campaign %s: %w", campaign.Name, err)
+	}
+
+	for _, item := range expired {
+		auditExpiredBinding(campaign, item)
+	}
+	campaign.Status.State = recertification.StateClosed
+	campaign.Status.ClosedAt = &now
+	logrus.Infof("[%s] closed recertification campaign %s of cluster %s", controllerName, campaign.Name, campaign.Spec.ClusterName)
+	return c.campaigns.UpdateStatus(campaign)
+}
+
+func auditExpiredBinding(campaign *v3.RecertificationCampaign, item *v3.RecertificationItem) {
+	logrus.WithFields(logrus.Fields{
+		"event":            "RecertificationExpired",
+		"campaign":         campaign.Name,
+		"clusterName":      campaign.Spec.ClusterName,
+		"kind":             item.Kind,
+		"bindingName":      item.BindingName,
+		"roleTemplateName": item.RoleTemplateName,
+		"subject":          item.Subject,
+	}).Info("recertification: audit")
+}
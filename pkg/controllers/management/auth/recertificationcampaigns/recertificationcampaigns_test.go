@@ -0,0 +1,128 @@
+package recertificationcampaigns
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/recertification"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type mocks struct {
+	campaigns *fake.MockNonNamespacedControllerInterface[*v3.RecertificationCampaign, *v3.RecertificationCampaignList]
+	crtbs     *fake.MockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList]
+	prtbs     *fake.MockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList]
+}
+
+func setup(t *testing.T, now time.Time) (*controller, *mocks) {
+	ctrl := gomock.NewController(t)
+	m := &mocks{
+		campaigns: fake.NewMockNonNamespacedControllerInterface[*v3.RecertificationCampaign, *v3.RecertificationCampaignList](ctrl),
+		crtbs:     fake.NewMockClientInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl),
+		prtbs:     fake.NewMockClientInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl),
+	}
+	return &controller{
+		campaigns: m.campaigns,
+		crtbs:     m.crtbs,
+		prtbs:     m.prtbs,
+		now:       func() time.Time { return now },
+	}, m
+}
+
+// newCampaign returns an open campaign of cluster c-abcde, due at deadline, where crtb-approved was approved,
+// prtb-revoked was revoked, and crtb-undecided and prtb-undecided weren't decided on.
+func newCampaign(deadline time.Time) *v3.RecertificationCampaign {
+	return &v3.RecertificationCampaign{
+		ObjectMeta: metav1.ObjectMeta{Name: "c-abcde-1"},
+		Spec: v3.RecertificationCampaignSpec{
+			ClusterName: "c-abcde",
+			Reviewers:   []string{"u-owner"},
+			Deadline:    metav1.NewTime(deadline),
+		},
+		Status: v3.RecertificationCampaignStatus{
+			State: recertification.StateOpen,
+			Items: []v3.RecertificationItem{
+				{Kind: recertification.CRTBKind, BindingName: "c-abcde:crtb-approved", RoleTemplateName: "cluster-member", Subject: "u-dev", Decision: recertification.DecisionApproved, DecidedBy: "u-owner"},
+				{Kind: recertification.CRTBKind, BindingName: "c-abcde:crtb-undecided", RoleTemplateName: "cluster-owner", Subject: "u-owner"},
+				{Kind: recertification.PRTBKind, BindingName: "p-fghij:prtb-revoked", ProjectName: "c-abcde:p-fghij", RoleTemplateName: "project-member", Subject: "u-gone", Decision: recertification.DecisionRevoked, DecidedBy: "u-owner"},
+				{Kind: recertification.PRTBKind, BindingName: "p-fghij:prtb-undecided", ProjectName: "c-abcde:p-fghij", RoleTemplateName: "project-member", Subject: "openldap_group://devs"},
+			},
+		},
+	}
+}
+
+func TestSyncBeforeDeadline(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c, m := setup(t, now)
+
+	m.campaigns.EXPECT().EnqueueAfter("c-abcde-1", 2*time.Hour)
+	_, err := c.sync("", newCampaign(now.Add(2*time.Hour)))
+	require.NoError(t, err)
+}
+
+func TestSyncAtDeadline(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c, m := setup(t, now)
+
+	m.crtbs.EXPECT().Delete("c-abcde", "crtb-undecided", gomock.Any()).Return(nil)
+	// The revoked bindings were already removed when they were decided on.
+	m.prtbs.EXPECT().Delete("p-fghij", "prtb-revoked", gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{Resource: "projectroletemplatebindings"}, "prtb-revoked"))
+	m.prtbs.EXPECT().Delete("p-fghij", "prtb-undecided", gomock.Any()).Return(nil)
+	var closed *v3.RecertificationCampaign
+	m.campaigns.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(campaign *v3.RecertificationCampaign) (*v3.RecertificationCampaign, error) {
+		closed = campaign
+		return campaign, nil
+	})
+
+	_, err := c.sync("", newCampaign(now))
+	require.NoError(t, err)
+
+	require.NotNil(t, closed)
+	assert.Equal(t, recertification.StateClosed, closed.Status.State)
+	assert.Equal(t, now, closed.Status.ClosedAt.Time)
+	decisions := map[string]string{}
+	for _, item := range closed.Status.Items {
+		decisions[item.BindingName] = item.Decision
+	}
+	assert.Equal(t, map[string]string{
+		"c-abcde:crtb-approved":  recertification.DecisionApproved,
+		"c-abcde:crtb-undecided": recertification.DecisionExpired,
+		"p-fghij:prtb-revoked":   recertification.DecisionRevoked,
+		"p-fghij:prtb-undecided": recertification.DecisionExpired,
+	}, decisions)
+}
+
+func TestSyncRetriesFailedRemovals(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c, m := setup(t, now)
+
+	m.crtbs.EXPECT().Delete("c-abcde", "crtb-undecided", gomock.Any()).Return(errors.New("unavailable"))
+	m.prtbs.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	_, err := c.sync("", newCampaign(now.Add(-time.Minute)))
+	assert.ErrorContains(t, err, "removing ClusterRoleTemplateBinding c-abcde:crtb-undecided: unavailable")
+}
+
+func TestSyncClosed(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c, _ := setup(t, now)
+
+	campaign := newCampaign(now.Add(-time.Hour))
+	campaign.Status.State = recertification.StateClosed
+	_, err := c.sync("", campaign)
+	require.NoError(t, err)
+
+	// The campaigns whose bindings aren't recorded yet are left to the generator.
+	campaign = newCampaign(now.Add(-time.Hour))
+	campaign.Status = v3.RecertificationCampaignStatus{}
+	_, err = c.sync("", campaign)
+	require.NoError(t, err)
+}
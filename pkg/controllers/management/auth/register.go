@@ -4,9 +4,18 @@
 	"context"
 
 	"github.com/rancher/rancher/pkg/clustermanager"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/bindingstatus"
 	"github.com/rancher/rancher/pkg/controllers/management/auth/globalroles"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/groupmembership"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/multiclusterbindings"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/organizations"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/orphanedbindings"
 	"github.com/rancher/rancher/pkg/controllers/management/auth/project_cluster"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/projecthierarchy"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/psadefaults"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/recertificationcampaigns"
 	"github.com/rancher/rancher/pkg/controllers/management/auth/roletemplates"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/roleusage"
 	"github.com/rancher/rancher/pkg/features"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/wrangler"
@@ -72,17 +81,29 @@ func RegisterEarly(ctx context.Context, management *config.ManagementContext, cl
 	grbLegacy := newLegacyGRBCleaner(management)
 	rtLegacy := newLegacyRTCleaner(management)
 	prtbServiceAccountFinder := newPRTBServiceAccountController(management)
+	rtbExpiration := newRTBExpirationController(management)
 
 	management.Management.Clusters("").AddHandler(ctx, project_cluster.ClusterCreateController, c.Sync)
 	management.Management.Projects("").AddHandler(ctx, project_cluster.ProjectCreateController, p.Sync)
 	management.Management.ProjectRoleTemplateBindings("").AddHandler(ctx, prtbServiceAccountControllerName, prtbServiceAccountFinder.sync)
+	management.Management.ClusterRoleTemplateBindings("").AddHandler(ctx, crtbExpirationControllerName, rtbExpiration.syncCRTB)
+	management.Management.ProjectRoleTemplateBindings("").AddHandler(ctx, prtbExpirationControllerName, rtbExpiration.syncPRTB)
 	management.Management.Tokens("").AddHandler(ctx, tokenController, n.sync)
 	management.Management.AuthConfigs("").AddHandler(ctx, authConfigControllerName, ac.sync)
 	management.Management.UserAttributes("").AddHandler(ctx, userAttributeController, ua.sync)
 	management.Management.Settings("").AddHandler(ctx, authSettingController, s.sync)
 	management.Management.GlobalRoleBindings("").AddHandler(ctx, "legacy-grb-cleaner", grbLegacy.sync)
 	management.Management.RoleTemplates("").AddHandler(ctx, "legacy-rt-cleaner", rtLegacy.sync)
+	bindingstatus.Register(ctx, management)
 	globalroles.Register(ctx, management, clusterManager)
+	groupmembership.Register(ctx, management)
+	multiclusterbindings.Register(ctx, management)
+	organizations.Register(ctx, management)
+	orphanedbindings.Register(ctx, management)
+	projecthierarchy.Register(ctx, management)
+	psadefaults.Register(ctx, management)
+	recertificationcampaigns.Register(ctx, management)
+	roleusage.Register(ctx, management)
 
 	// Only one set of CRTB/PRTB/RoleTemplate controllers should run at a time. Using aggregated cluster roles is currently experimental and only available via feature flags.
 	if features.AggregatedRoleTemplates.Enabled() {
@@ -0,0 +1,333 @@
+// Package roleusage checks which RoleTemplates and GlobalRoles are granted by bindings, and when the subjects of those
+// bindings were last active, and records it in the RoleUsageReport.
+package roleusage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	reportControllerName = "role-usage-report"
+	checkControllerName  = "role-usage-check"
+
+	// ReportName is the name of the RoleUsageReport created by Rancher.
+	ReportName = "default"
+
+	// StateUnbound is the state of the roles which aren't granted by any binding nor inherited by any role,
+	// StateIdle of those which weren't used for the idle days of the report, and StateActive of the others.
+	StateUnbound = "Unbound"
+	StateIdle    = "Idle"
+	StateActive  = "Active"
+
+	roleTemplateKind = "RoleTemplate"
+	globalRoleKind   = "GlobalRole"
+
+	defaultCheckInterval = time.Hour
+	defaultIdleDays      = 90
+)
+
+// Register registers the controllers creating the RoleUsageReport and checking it periodically.
+func Register(ctx context.Context, management *config.ManagementContext) {
+	c := newController(management)
+	mgmt := management.Wrangler.Mgmt
+	mgmt.RoleTemplate().OnChange(ctx, reportControllerName, c.ensureReport)
+	mgmt.RoleUsageReport().OnChange(ctx, checkControllerName, c.sync)
+}
+
+type controller struct {
+	reports           mgmtcontrollers.RoleUsageReportController
+	reportCache       mgmtcontrollers.RoleUsageReportCache
+	roleTemplateCache mgmtcontrollers.RoleTemplateCache
+	globalRoleCache   mgmtcontrollers.GlobalRoleCache
+	crtbCache         mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbCache         mgmtcontrollers.ProjectRoleTemplateBindingCache
+	grbCache          mgmtcontrollers.GlobalRoleBindingCache
+	tokenCache        mgmtcontrollers.TokenCache
+	now               func() time.Time
+}
+
+func newController(management *config.ManagementContext) *controller {
+	mgmt := management.Wrangler.Mgmt
+	return &controller{
+		reports:           mgmt.RoleUsageReport(),
+		reportCache:       mgmt.RoleUsageReport().Cache(),
+		roleTemplateCache: mgmt.RoleTemplate().Cache(),
+		globalRoleCache:   mgmt.GlobalRole().Cache(),
+		crtbCache:         mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbCache:         mgmt.ProjectRoleTemplateBinding().Cache(),
+		grbCache:          mgmt.GlobalRoleBinding().Cache(),
+		tokenCache:        mgmt.Token().Cache(),
+		now:               time.Now,
+	}
+}
+
+// ensureReport creates the RoleUsageReport if it doesn't exist. It's triggered by the RoleTemplates, as the builtin ones
+// always exist.
+func (c *controller) ensureReport(_ string, roleTemplate *v3.RoleTemplate) (*v3.RoleTemplate, error) {
+	if _, err := c.reportCache.Get(ReportName); !apierrors.IsNotFound(err) {
+		return roleTemplate, err
+	}
+	_, err := c.reports.Create(&v3.RoleUsageReport{
+		ObjectMeta: metav1.ObjectMeta{Name: ReportName},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return roleTemplate, fmt.Errorf("creating the RoleUsageReport: %w", err)
+	}
+	return roleTemplate, nil
+}
+
+// sync checks the usage of the roles once the check interval has passed since the last check, or right away when the
+// report was edited since.
+func (c *controller) sync(_ string, report *v3.RoleUsageReport) (*v3.RoleUsageReport, error) {
+	if report == nil || report.DeletionTimestamp != nil {
+		return report, nil
+	}
+	interval := time.Duration(settings.RoleUsageCheckIntervalMinutes.GetInt()) * time.Minute
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	if report.Status.LastCheckTime != nil && report.Status.ObservedGeneration == report.Generation {
+		if remaining := report.Status.LastCheckTime.Add(interval).Sub(c.now()); remaining > 0 {
+			c.reports.EnqueueAfter(report.Name, remaining)
+			return report, nil
+		}
+	}
+
+	report = report.DeepCopy()
+	report.Status.Error = ""
+	roles, err := c.check(report)
+	if err != nil {
+		logrus.Errorf("[%s] failed to check the usage of the roles: %v", checkControllerName, err)
+		report.Status.Error = err.Error()
+	} else {
+		report.Status.Roles = roles
+		report.Status.UnboundCount, report.Status.IdleCount = 0, 0
+		for _, role := range roles {
+			if role.Builtin {
+				continue
+			}
+			switch role.State {
+			case StateUnbound:
+				report.Status.UnboundCount++
+			case StateIdle:
+				report.Status.IdleCount++
+			}
+		}
+	}
+	now := metav1.NewTime(c.now())
+	report.Status.LastCheckTime = &now
+	report.Status.ObservedGeneration = report.Generation
+	return c.reports.UpdateStatus(report)
+}
+
+// check returns the usage of every role, sorted by kind and name.
+func (c *controller) check(report *v3.RoleUsageReport) ([]v3.RoleUsage, error) {
+	roles := map[string]*v3.RoleUsage{}
+	// inherits are the keys of the roles each role inherits.
+	inherits := map[string][]string{}
+
+	roleTemplates, err := c.roleTemplateCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing RoleTemplates: %w", err)
+	}
+	for _, roleTemplate := range roleTemplates {
+		key := roleKey(roleTemplateKind, roleTemplate.Name)
+		roles[key] = &v3.RoleUsage{
+			Kind:        roleTemplateKind,
+			Name:        roleTemplate.Name,
+			DisplayName: roleTemplate.DisplayName,
+			Builtin:     roleTemplate.Builtin,
+		}
+		for _, name := range roleTemplate.RoleTemplateNames {
+			inherits[key] = append(inherits[key], roleKey(roleTemplateKind, name))
+		}
+	}
+	globalRoles, err := c.globalRoleCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing GlobalRoles: %w", err)
+	}
+	for _, globalRole := range globalRoles {
+		key := roleKey(globalRoleKind, globalRole.Name)
+		roles[key] = &v3.RoleUsage{
+			Kind:        globalRoleKind,
+			Name:        globalRole.Name,
+			DisplayName: globalRole.DisplayName,
+			Builtin:     globalRole.Builtin,
+		}
+		for _, name := range globalRole.InheritedClusterRoles {
+			inherits[key] = append(inherits[key], roleKey(roleTemplateKind, name))
+		}
+	}
+	for key, inherited := range inherits {
+		for _, inheritedKey := range inherited {
+			if role, ok := roles[inheritedKey]; ok {
+				role.InheritedBy = append(role.InheritedBy, key)
+			}
+		}
+	}
+
+	a, err := c.activity()
+	if err != nil {
+		return nil, err
+	}
+	bind := func(key, userName, userPrincipalName, groupPrincipalName string) {
+		role, ok := roles[key]
+		if !ok {
+			return
+		}
+		role.BindingCount++
+		role.LastUsedTime = latest(role.LastUsedTime, a.of(userName, userPrincipalName, groupPrincipalName))
+	}
+	crtbs, err := c.crtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ClusterRoleTemplateBindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		if crtb.DeletionTimestamp == nil {
+			bind(roleKey(roleTemplateKind, crtb.RoleTemplateName), crtb.UserName, crtb.UserPrincipalName, crtb.GroupPrincipalName)
+		}
+	}
+	prtbs, err := c.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ProjectRoleTemplateBindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		if prtb.DeletionTimestamp == nil {
+			bind(roleKey(roleTemplateKind, prtb.RoleTemplateName), prtb.UserName, prtb.UserPrincipalName, prtb.GroupPrincipalName)
+		}
+	}
+	grbs, err := c.grbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing GlobalRoleBindings: %w", err)
+	}
+	for _, grb := range grbs {
+		if grb.DeletionTimestamp == nil {
+			bind(roleKey(globalRoleKind, grb.GlobalRoleName), grb.UserName, grb.UserPrincipalName, grb.GroupPrincipalName)
+		}
+	}
+
+	// The activity of the roles is passed on to the roles they inherit, through as many levels of inheritance as there
+	// are. Each pass goes one level deeper, so there can't be more passes than roles, even with cycles.
+	for i := 0; i < len(roles); i++ {
+		changed := false
+		for key, inherited := range inherits {
+			for _, inheritedKey := range inherited {
+				role, ok := roles[inheritedKey]
+				if !ok {
+					continue
+				}
+				if used := latest(role.LastUsedTime, roles[key].LastUsedTime); used != role.LastUsedTime {
+					role.LastUsedTime = used
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// The last use is kept from the previous checks, as the tokens it was found from may have been removed since.
+	for _, previous := range report.Status.Roles {
+		if role, ok := roles[roleKey(previous.Kind, previous.Name)]; ok {
+			role.LastUsedTime = latest(role.LastUsedTime, previous.LastUsedTime)
+		}
+	}
+
+	idleDays := report.Spec.IdleDays
+	if idleDays <= 0 {
+		idleDays = defaultIdleDays
+	}
+	idleSince := c.now().Add(-time.Duration(idleDays) * 24 * time.Hour)
+	usage := make([]v3.RoleUsage, 0, len(roles))
+	for _, role := range roles {
+		switch {
+		case role.BindingCount == 0 && len(role.InheritedBy) == 0:
+			role.State = StateUnbound
+		case role.LastUsedTime == nil || role.LastUsedTime.Time.Before(idleSince):
+			role.State = StateIdle
+		default:
+			role.State = StateActive
+		}
+		sort.Strings(role.InheritedBy)
+		usage = append(usage, *role)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Kind != usage[j].Kind {
+			return usage[i].Kind < usage[j].Kind
+		}
+		return usage[i].Name < usage[j].Name
+	})
+	return usage, nil
+}
+
+// activity is when the tokens of the users and principals were last used.
+type activity struct {
+	users      map[string]*metav1.Time
+	principals map[string]*metav1.Time
+}
+
+func (c *controller) activity() (*activity, error) {
+	a := &activity{
+		users:      map[string]*metav1.Time{},
+		principals: map[string]*metav1.Time{},
+	}
+	tokens, err := c.tokenCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing tokens: %w", err)
+	}
+	for _, token := range tokens {
+		used := latest(token.LastUsedAt, token.ActivityLastSeenAt)
+		if used == nil {
+			continue
+		}
+		a.users[token.UserID] = latest(a.users[token.UserID], used)
+		if name := token.UserPrincipal.Name; name != "" {
+			a.principals[name] = latest(a.principals[name], used)
+		}
+		for _, group := range token.GroupPrincipals {
+			if group.Name != "" {
+				a.principals[group.Name] = latest(a.principals[group.Name], used)
+			}
+		}
+	}
+	return a, nil
+}
+
+// of returns when the subject of a binding was last active, or nil if it never was. Groups are active when one of their
+// members was, as known from the group principals of the members' tokens.
+func (a *activity) of(userName, userPrincipalName, groupPrincipalName string) *metav1.Time {
+	switch {
+	case userName != "":
+		return latest(a.users[userName], a.principals[userPrincipalName])
+	case userPrincipalName != "":
+		return a.principals[userPrincipalName]
+	case groupPrincipalName != "":
+		return a.principals[groupPrincipalName]
+	}
+	return nil
+}
+
+func roleKey(kind, name string) string {
+	return kind + ":" + name
+}
+
+// latest returns the latest of two times, either of which can be nil.
+func latest(a, b *metav1.Time) *metav1.Time {
+	if a == nil || (b != nil && a.Before(b)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,160 @@
+package roleusage
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(days int) *metav1.Time {
+	return &metav1.Time{Time: now.Add(-time.Duration(days) * 24 * time.Hour)}
+}
+
+// setup returns a controller where:
+// - cluster-owner is bound to u-alice, active 2 days ago, and inherits auditor,
+// - project-member is bound to the azuread group devs, one of whose members was active 10 days ago,
+// - legacy-viewer is bound to u-bob, whose only token was used 200 days ago,
+// - unused is neither bound nor inherited,
+// - the restricted-admin GlobalRole is bound to a deleted binding only, and inherits nothing.
+func setup(t *testing.T) (*controller, *fake.MockNonNamespacedControllerInterface[*v3.RoleUsageReport, *v3.RoleUsageReportList]) {
+	ctrl := gomock.NewController(t)
+
+	roleTemplateCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	roleTemplateCache.EXPECT().List(gomock.Any()).Return([]*v3.RoleTemplate{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-owner"}, DisplayName: "Cluster Owner", Builtin: true, RoleTemplateNames: []string{"auditor"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "project-member"}, DisplayName: "Project Member", Builtin: true},
+		{ObjectMeta: metav1.ObjectMeta{Name: "auditor"}, DisplayName: "Auditor"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "legacy-viewer"}, DisplayName: "Legacy Viewer"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unused"}, DisplayName: "Unused"},
+	}, nil).AnyTimes()
+	globalRoleCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRole](ctrl)
+	globalRoleCache.EXPECT().List(gomock.Any()).Return([]*v3.GlobalRole{
+		{ObjectMeta: metav1.ObjectMeta{Name: "admin"}, DisplayName: "Admin", Builtin: true},
+		{ObjectMeta: metav1.ObjectMeta{Name: "restricted-admin"}, DisplayName: "Restricted Admin"},
+	}, nil).AnyTimes()
+
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("", gomock.Any()).Return([]*v3.ClusterRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-alice", Namespace: "c-abcde"}, UserName: "u-alice", RoleTemplateName: "cluster-owner"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-bob", Namespace: "c-abcde"}, UserName: "u-bob", RoleTemplateName: "legacy-viewer"},
+	}, nil).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", gomock.Any()).Return([]*v3.ProjectRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-devs", Namespace: "p-xyz"}, GroupPrincipalName: "azuread_group://devs", RoleTemplateName: "project-member"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-missing-role", Namespace: "p-xyz"}, UserName: "u-alice", RoleTemplateName: "removed"},
+	}, nil).AnyTimes()
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl)
+	grbCache.EXPECT().List(gomock.Any()).Return([]*v3.GlobalRoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-alice"}, UserName: "u-alice", GlobalRoleName: "admin"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "grb-deleting", DeletionTimestamp: &metav1.Time{}}, UserName: "u-bob", GlobalRoleName: "restricted-admin"},
+	}, nil).AnyTimes()
+
+	tokenCache := fake.NewMockNonNamespacedCacheInterface[*v3.Token](ctrl)
+	tokenCache.EXPECT().List(gomock.Any()).Return([]*v3.Token{
+		{ObjectMeta: metav1.ObjectMeta{Name: "token-alice-old"}, UserID: "u-alice", LastUsedAt: daysAgo(30)},
+		{ObjectMeta: metav1.ObjectMeta{Name: "token-alice"}, UserID: "u-alice", LastUsedAt: daysAgo(5), ActivityLastSeenAt: daysAgo(2)},
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "token-carol"},
+			UserID:          "u-carol",
+			UserPrincipal:   v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: "azuread_user://carol"}},
+			GroupPrincipals: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "azuread_group://devs"}}},
+			LastUsedAt:      daysAgo(10),
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "token-bob"}, UserID: "u-bob", LastUsedAt: daysAgo(200)},
+		{ObjectMeta: metav1.ObjectMeta{Name: "token-unused"}, UserID: "u-dave"},
+	}, nil).AnyTimes()
+
+	reports := fake.NewMockNonNamespacedControllerInterface[*v3.RoleUsageReport, *v3.RoleUsageReportList](ctrl)
+	return &controller{
+		reports:           reports,
+		roleTemplateCache: roleTemplateCache,
+		globalRoleCache:   globalRoleCache,
+		crtbCache:         crtbCache,
+		prtbCache:         prtbCache,
+		grbCache:          grbCache,
+		tokenCache:        tokenCache,
+		now:               func() time.Time { return now },
+	}, reports
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	c, _ := setup(t)
+
+	roles, err := c.check(&v3.RoleUsageReport{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []v3.RoleUsage{
+		{Kind: globalRoleKind, Name: "admin", DisplayName: "Admin", Builtin: true, BindingCount: 1, LastUsedTime: daysAgo(2), State: StateActive},
+		{Kind: globalRoleKind, Name: "restricted-admin", DisplayName: "Restricted Admin", State: StateUnbound},
+		{Kind: roleTemplateKind, Name: "auditor", DisplayName: "Auditor", InheritedBy: []string{"RoleTemplate:cluster-owner"}, LastUsedTime: daysAgo(2), State: StateActive},
+		{Kind: roleTemplateKind, Name: "cluster-owner", DisplayName: "Cluster Owner", Builtin: true, BindingCount: 1, LastUsedTime: daysAgo(2), State: StateActive},
+		{Kind: roleTemplateKind, Name: "legacy-viewer", DisplayName: "Legacy Viewer", BindingCount: 1, LastUsedTime: daysAgo(200), State: StateIdle},
+		{Kind: roleTemplateKind, Name: "project-member", DisplayName: "Project Member", Builtin: true, BindingCount: 1, LastUsedTime: daysAgo(10), State: StateActive},
+		{Kind: roleTemplateKind, Name: "unused", DisplayName: "Unused", State: StateUnbound},
+	}, roles)
+}
+
+func TestCheckIdleDaysAndPreviousUse(t *testing.T) {
+	t.Parallel()
+	c, _ := setup(t)
+
+	// The tokens of the members of devs were removed since they were last seen active a day ago.
+	roles, err := c.check(&v3.RoleUsageReport{
+		Spec: v3.RoleUsageReportSpec{IdleDays: 7},
+		Status: v3.RoleUsageReportStatus{Roles: []v3.RoleUsage{
+			{Kind: roleTemplateKind, Name: "project-member", LastUsedTime: daysAgo(1)},
+		}},
+	})
+	require.NoError(t, err)
+
+	states := map[string]string{}
+	for _, role := range roles {
+		states[role.Name] = role.State
+	}
+	assert.Equal(t, StateActive, states["cluster-owner"])
+	assert.Equal(t, StateActive, states["project-member"])
+	assert.Equal(t, StateIdle, states["legacy-viewer"])
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+	c, reports := setup(t)
+
+	var updated *v3.RoleUsageReport
+	reports.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(report *v3.RoleUsageReport) (*v3.RoleUsageReport, error) {
+		updated = report
+		return report, nil
+	})
+	_, err := c.sync(ReportName, &v3.RoleUsageReport{ObjectMeta: metav1.ObjectMeta{Name: ReportName, Generation: 1}})
+	require.NoError(t, err)
+
+	require.NotNil(t, updated)
+	// The builtin roles aren't counted.
+	assert.Equal(t, 2, updated.Status.UnboundCount)
+	assert.Equal(t, 1, updated.Status.IdleCount)
+	assert.Len(t, updated.Status.Roles, 7)
+	assert.Equal(t, int64(1), updated.Status.ObservedGeneration)
+	assert.Equal(t, now, updated.Status.LastCheckTime.Time)
+}
+
+func TestSyncWaitsForInterval(t *testing.T) {
+	t.Parallel()
+	c, reports := setup(t)
+
+	lastCheck := metav1.NewTime(now.Add(-10 * time.Minute))
+	reports.EXPECT().EnqueueAfter(ReportName, 50*time.Minute)
+	_, err := c.sync(ReportName, &v3.RoleUsageReport{
+		ObjectMeta: metav1.ObjectMeta{Name: ReportName, Generation: 1},
+		Status:     v3.RoleUsageReportStatus{LastCheckTime: &lastCheck, ObservedGeneration: 1},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	apiv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	wranglerv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	crtbExpirationControllerName = "crtb-expiration-controller"
+	prtbExpirationControllerName = "prtb-expiration-controller"
+)
+
+// rtbExpirationController removes the ClusterRoleTemplateBindings and ProjectRoleTemplateBindings granting temporary
+// access once they expire, per their expiresAt or ttl fields. Bindings without them never expire.
+type rtbExpirationController struct {
+	crtbs wranglerv3.ClusterRoleTemplateBindingController
+	prtbs wranglerv3.ProjectRoleTemplateBindingController
+	now   func() time.Time
+}
+
+func newRTBExpirationController(mgmt *config.ManagementContext) *rtbExpirationController {
+	return &rtbExpirationController{
+		crtbs: mgmt.Wrangler.Mgmt.ClusterRoleTemplateBinding(),
+		prtbs: mgmt.Wrangler.Mgmt.ProjectRoleTemplateBinding(),
+		now:   time.Now,
+	}
+}
+
+func (c *rtbExpirationController) syncCRTB(_ string, crtb *apiv3.ClusterRoleTemplateBinding) (runtime.Object, error) {
+	if crtb == nil || crtb.DeletionTimestamp != nil {
+		return crtb, nil
+	}
+	expiresAt, ok := c.expiration(crtb.ObjectMeta, crtb.ExpiresAt, crtb.TTL)
+	if !ok {
+		return crtb, nil
+	}
+	if remaining := expiresAt.Sub(c.now()); remaining > 0 {
+		c.crtbs.EnqueueAfter(crtb.Namespace, crtb.Name, remaining)
+		return crtb, nil
+	}
+
+	if err := c.crtbs.Delete(crtb.Namespace, crtb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("deleting expired ClusterRoleTemplateBinding %s/%s: %w", crtb.Namespace, crtb.Name, err)
+	}
+	auditExpiredBinding("ClusterRoleTemplateBinding", crtb.ObjectMeta, crtb.UserName, crtb.GroupName, crtb.GroupPrincipalName, crtb.RoleTemplateName, expiresAt)
+	return crtb, nil
+}
+
+func (c *rtbExpirationController) syncPRTB(_ string, prtb *apiv3.ProjectRoleTemplateBinding) (runtime.Object, error) {
+	if prtb == nil || prtb.DeletionTimestamp != nil {
+		return prtb, nil
+	}
+	expiresAt, ok := c.expiration(prtb.ObjectMeta, prtb.ExpiresAt, prtb.TTL)
+	if !ok {
+		return prtb, nil
+	}
+	if remaining := expiresAt.Sub(c.now()); remaining > 0 {
+		c.prtbs.EnqueueAfter(prtb.Namespace, prtb.Name, remaining)
+		return prtb, nil
+	}
+
+	if err := c.prtbs.Delete(prtb.Namespace, prtb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("deleting expired ProjectRoleTemplateBinding %s/%s: %w", prtb.Namespace, prtb.Name, err)
+	}
+	auditExpiredBinding("ProjectRoleTemplateBinding", prtb.ObjectMeta, prtb.UserName, prtb.GroupName, prtb.GroupPrincipalName, prtb.RoleTemplateName, expiresAt)
+	return prtb, nil
+}
+
+// expiration returns when the binding expires, the earliest of its expiresAt and its creation time plus its ttl.
+// An invalid ttl is ignored, it's rejected by the API on creation.
+func (c *rtbExpirationController) expiration(meta metav1.ObjectMeta, expiresAt *metav1.Time, ttl string) (time.Time, bool) {
+	var expiration time.Time
+	if expiresAt != nil && !expiresAt.IsZero() {
+		expiration = expiresAt.Time
+	}
+	if ttl != "" {
+		duration, err := time.ParseDuration(ttl)
+		if err != nil || duration <= 0 {
+			logrus.Warnf("[rtb-expiration] ignoring invalid ttl %q of binding %s/%s", ttl, meta.Namespace, meta.Name)
+		} else if fromTTL := meta.CreationTimestamp.Add(duration); expiration.IsZero() || fromTTL.Before(expiration) {
+			expiration = fromTTL
+		}
+	}
+	return expiration, !expiration.IsZero()
+}
+
+func auditExpiredBinding(kind string, meta metav1.ObjectMeta, userName, groupName, groupPrincipalName, roleTemplateName string, expiresAt time.Time) {
+	logrus.WithFields(logrus.Fields{
+		"event":              "RoleBindingExpired",
+		"kind":               kind,
+		"namespace":          meta.Namespace,
+		"name":               meta.Name,
+		"userName":           userName,
+		"groupName":          groupName,
+		"groupPrincipalName": groupPrincipalName,
+		"roleTemplateName":   roleTemplateName,
+		"expiresAt":          expiresAt.UTC().Format(time.RFC3339),
+	}).Info("rtb-expiration: audit")
+}
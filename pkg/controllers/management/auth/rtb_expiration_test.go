@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRTBExpirationCRTB(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-time.Hour))
+	at := func(d time.Duration) *metav1.Time {
+		expiresAt := metav1.NewTime(now.Add(d))
+		return &expiresAt
+	}
+
+	tests := []struct {
+		name         string
+		expiresAt    *metav1.Time
+		ttl          string
+		deleting     bool
+		wantDelete   bool
+		wantEnqueued time.Duration
+	}{
+		{
+			name: "no expiration",
+		},
+		{
+			name:         "expires later",
+			expiresAt:    at(30 * time.Minute),
+			wantEnqueued: 30 * time.Minute,
+		},
+		{
+			name:       "expired",
+			expiresAt:  at(-time.Second),
+			wantDelete: true,
+		},
+		{
+			name:         "ttl not elapsed",
+			ttl:          "2h",
+			wantEnqueued: time.Hour,
+		},
+		{
+			name:       "ttl elapsed",
+			ttl:        "30m",
+			wantDelete: true,
+		},
+		{
+			name:         "earliest of ttl and expiresAt",
+			expiresAt:    at(10 * time.Minute),
+			ttl:          "2h",
+			wantEnqueued: 10 * time.Minute,
+		},
+		{
+			name: "invalid ttl",
+			ttl:  "a while",
+		},
+		{
+			name:      "being deleted",
+			expiresAt: at(-time.Second),
+			deleting:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			crtbs := fake.NewMockControllerInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+			if tt.wantDelete {
+				crtbs.EXPECT().Delete("c-abc", "crtb-1", gomock.Any()).Return(nil)
+			}
+			if tt.wantEnqueued != 0 {
+				crtbs.EXPECT().EnqueueAfter("c-abc", "crtb-1", tt.wantEnqueued)
+			}
+
+			crtb := &v3.ClusterRoleTemplateBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "crtb-1",
+					Namespace:         "c-abc",
+					CreationTimestamp: created,
+				},
+				ClusterName:      "c-abc",
+				UserName:         "u-abc",
+				RoleTemplateName: "cluster-owner",
+				ExpiresAt:        tt.expiresAt,
+				TTL:              tt.ttl,
+			}
+			if tt.deleting {
+				crtb.DeletionTimestamp = &created
+			}
+
+			c := &rtbExpirationController{crtbs: crtbs, now: func() time.Time { return now }}
+			obj, err := c.syncCRTB("", crtb)
+			assert.NoError(t, err)
+			assert.Equal(t, crtb, obj)
+		})
+	}
+}
+
+func TestRTBExpirationPRTB(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	prtbs := fake.NewMockControllerInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl)
+	prtbs.EXPECT().Delete("p-xyz", "prtb-1", gomock.Any()).Return(nil)
+
+	prtb := &v3.ProjectRoleTemplateBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "prtb-1",
+			Namespace:         "p-xyz",
+			CreationTimestamp: metav1.NewTime(now.Add(-9 * time.Hour)),
+		},
+		ProjectName:      "c-abc:p-xyz",
+		GroupName:        "g-abc",
+		RoleTemplateName: "project-member",
+		TTL:              "8h",
+	}
+
+	c := &rtbExpirationController{prtbs: prtbs, now: func() time.Time { return now }}
+	_, err := c.syncPRTB("", prtb)
+	assert.NoError(t, err)
+}
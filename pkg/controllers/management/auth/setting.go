@@ -3,8 +3,15 @@
 import (
 	"context"
 
+	"github.com/rancher/rancher/pkg/auth/attributelabels"
+	"github.com/rancher/rancher/pkg/auth/attributemembership"
+	"github.com/rancher/rancher/pkg/auth/deprovisioning"
+	"github.com/rancher/rancher/pkg/auth/groupdisplaynames"
+	"github.com/rancher/rancher/pkg/auth/groupsync"
+	"github.com/rancher/rancher/pkg/auth/principalmetadata"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
 	"github.com/rancher/rancher/pkg/auth/providers/azure"
+	"github.com/rancher/rancher/pkg/auth/recertification"
 	"github.com/rancher/rancher/pkg/auth/userretention"
 	"github.com/rancher/rancher/pkg/crondaemon"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
@@ -18,17 +25,38 @@
 
 type SettingController struct {
 	ensureUserRetentionLabels func() error
+	ensureAttributeLabels     func() error
 	scheduleUserRetention     func(string) error
+	scheduleDeprovisioning    func(string) error
+	scheduleGroupRefresh      func(string) error
+	scheduleGroupSync         func(string) error
+	scheduleAttributeSync     func(string) error
+	schedulePrincipalMetadata func(string) error
+	scheduleRecertification   func(string) error
 }
 
 func newAuthSettingController(ctx context.Context, mgmt *config.ManagementContext) *SettingController {
 	userRetention := userretention.New(mgmt.Wrangler)
 	userRetentionDaemon := crondaemon.New(ctx, "userretention", userRetention.Run)
 	userRetentionLabeler := userretention.NewUserLabeler(ctx, mgmt.Wrangler)
+	attributeLabeler := attributelabels.NewLabeler(ctx, mgmt.Wrangler.Mgmt)
+	deprovisioningDaemon := crondaemon.New(ctx, "deprovisioning", deprovisioning.New(mgmt.Wrangler).Run)
+	groupRefreshDaemon := crondaemon.New(ctx, "groupdisplaynames", groupdisplaynames.New(mgmt.Wrangler).Run)
+	groupSyncDaemon := crondaemon.New(ctx, "groupsync", groupsync.New(mgmt.Wrangler).Run)
+	attributeSyncDaemon := crondaemon.New(ctx, "attributemembership", attributemembership.New(mgmt.Wrangler).Run)
+	principalMetadataDaemon := crondaemon.New(ctx, "principalmetadata", principalmetadata.New(mgmt.Wrangler).Run)
+	recertificationDaemon := crondaemon.New(ctx, "recertification", recertification.New(mgmt.Wrangler).Run)
 
 	return &SettingController{
 		ensureUserRetentionLabels: userRetentionLabeler.EnsureForAll,
+		ensureAttributeLabels:     attributeLabeler.EnsureForAll,
 		scheduleUserRetention:     userRetentionDaemon.Schedule,
+		scheduleDeprovisioning:    deprovisioningDaemon.Schedule,
+		scheduleGroupRefresh:      groupRefreshDaemon.Schedule,
+		scheduleGroupSync:         groupSyncDaemon.Schedule,
+		scheduleAttributeSync:     attributeSyncDaemon.Schedule,
+		schedulePrincipalMetadata: principalMetadataDaemon.Schedule,
+		scheduleRecertification:   recertificationDaemon.Schedule,
 	}
 }
 
@@ -49,12 +77,42 @@ func (c *SettingController) sync(key string, obj *v3.Setting) (runtime.Object, e
 		if err := c.scheduleUserRetention(obj.Value); err != nil {
 			logrus.Errorf("error scheduling user retention daemon: %v", err)
 		}
+	case settings.IdPDeprovisioningCron.Name:
+		if err := c.scheduleDeprovisioning(obj.Value); err != nil {
+			logrus.Errorf("error scheduling deprovisioning daemon: %v", err)
+		}
+	case settings.GroupDisplayNameRefreshCron.Name:
+		if err := c.scheduleGroupRefresh(obj.Value); err != nil {
+			logrus.Errorf("error scheduling group display name refresh daemon: %v", err)
+		}
+	case settings.GroupSyncCron.Name:
+		if err := c.scheduleGroupSync(obj.Value); err != nil {
+			logrus.Errorf("error scheduling group sync daemon: %v", err)
+		}
+	case settings.DirectoryAttributeMembershipSyncCron.Name:
+		if err := c.scheduleAttributeSync(obj.Value); err != nil {
+			logrus.Errorf("error scheduling directory attribute membership sync daemon: %v", err)
+		}
+	case settings.PrincipalMetadataSyncCron.Name:
+		if err := c.schedulePrincipalMetadata(obj.Value); err != nil {
+			logrus.Errorf("error scheduling principal metadata sync daemon: %v", err)
+		}
+	case settings.RecertificationCampaignCron.Name:
+		if err := c.scheduleRecertification(obj.Value); err != nil {
+			logrus.Errorf("error scheduling recertification campaign daemon: %v", err)
+		}
 	case settings.DisableInactiveUserAfter.Name,
 		settings.DeleteInactiveUserAfter.Name,
-		settings.UserLastLoginDefault.Name:
+		settings.UserLastLoginDefault.Name,
+		settings.FlagInactiveUserAfter.Name,
+		settings.UserRetentionExclusions.Name:
 		if err := c.ensureUserRetentionLabels(); err != nil {
 			logrus.Errorf("error updating retention labels for users: %v", err)
 		}
+	case settings.UserAttributeLabels.Name:
+		if err := c.ensureAttributeLabels(); err != nil {
+			logrus.Errorf("error updating attribute labels for users: %v", err)
+		}
 	}
 	return nil, nil
 }
@@ -38,6 +38,29 @@ func TestSettingsSyncEnsureUserRetentionLabels(t *testing.T) {
 	}
 }
 
+func TestSettingsSyncEnsureAttributeLabels(t *testing.T) {
+	var ensureLabelsCalledTimes int
+	controller := &SettingController{
+		ensureAttributeLabels: func() error {
+			ensureLabelsCalledTimes++
+			return nil
+		},
+	}
+
+	name := settings.UserAttributeLabels.Name
+	_, err := controller.sync(name, &v3.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Value:      "department=example.com/department",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, ensureLabelsCalledTimes; want != got {
+		t.Fatalf("Expected ensureLabelsCalledTimes: %d got %d", want, got)
+	}
+}
+
 func TestSettingsSyncScheduleUserRetention(t *testing.T) {
 	var scheduleRetentionCalledTimes int
 	controller := &SettingController{
@@ -60,3 +83,141 @@ func TestSettingsSyncScheduleUserRetention(t *testing.T) {
 		t.Fatalf("Expected scheduleRetentionCalledTimes: %d got %d", want, got)
 	}
 }
+
+func TestSettingsSyncScheduleDeprovisioning(t *testing.T) {
+	var scheduleDeprovisioningCalledTimes int
+	controller := &SettingController{
+		scheduleDeprovisioning: func(_ string) error {
+			scheduleDeprovisioningCalledTimes++
+			return nil
+		},
+	}
+
+	name := settings.IdPDeprovisioningCron.Name
+	_, err := controller.sync(name, &v3.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Value:      "0 * * * *",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, scheduleDeprovisioningCalledTimes; want != got {
+		t.Fatalf("Expected scheduleDeprovisioningCalledTimes: %d got %d", want, got)
+	}
+}
+
+func TestSettingsSyncScheduleGroupRefresh(t *testing.T) {
+	var scheduleGroupRefreshCalledTimes int
+	controller := &SettingController{
+		scheduleGroupRefresh: func(_ string) error {
+			scheduleGroupRefreshCalledTimes++
+			return nil
+		},
+	}
+
+	name := settings.GroupDisplayNameRefreshCron.Name
+	_, err := controller.sync(name, &v3.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Value:      "0 * * * *",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, scheduleGroupRefreshCalledTimes; want != got {
+		t.Fatalf("Expected scheduleGroupRefreshCalledTimes: %d got %d", want, got)
+	}
+}
+
+func TestSettingsSyncScheduleGroupSync(t *testing.T) {
+	var scheduleGroupSyncCalledTimes int
+	controller := &SettingController{
+		scheduleGroupSync: func(_ string) error {
+			scheduleGroupSyncCalledTimes++
+			return nil
+		},
+	}
+
+	name := settings.GroupSyncCron.Name
+	_, err := controller.sync(name, &v3.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Value:      "0 * * * *",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, scheduleGroupSyncCalledTimes; want != got {
+		t.Fatalf("Expected scheduleGroupSyncCalledTimes: %d got %d", want, got)
+	}
+}
+
+func TestSettingsSyncScheduleAttributeSync(t *testing.T) {
+	var scheduleAttributeSyncCalledTimes int
+	controller := &SettingController{
+		scheduleAttributeSync: func(_ string) error {
+			scheduleAttributeSyncCalledTimes++
+			return nil
+		},
+	}
+
+	name := settings.DirectoryAttributeMembershipSyncCron.Name
+	_, err := controller.sync(name, &v3.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Value:      "0 * * * *",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, scheduleAttributeSyncCalledTimes; want != got {
+		t.Fatalf("Expected scheduleAttributeSyncCalledTimes: %d got %d", want, got)
+	}
+}
+
+func TestSettingsSyncSchedulePrincipalMetadata(t *testing.T) {
+	var schedulePrincipalMetadataCalledTimes int
+	controller := &SettingController{
+		schedulePrincipalMetadata: func(_ string) error {
+			schedulePrincipalMetadataCalledTimes++
+			return nil
+		},
+	}
+
+	name := settings.PrincipalMetadataSyncCron.Name
+	_, err := controller.sync(name, &v3.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Value:      "*/30 * * * *",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, schedulePrincipalMetadataCalledTimes; want != got {
+		t.Fatalf("Expected schedulePrincipalMetadataCalledTimes: %d got %d", want, got)
+	}
+}
+
+func TestSettingsSyncScheduleRecertification(t *testing.T) {
+	var scheduleRecertificationCalledTimes int
+	controller := &SettingController{
+		scheduleRecertification: func(_ string) error {
+			scheduleRecertificationCalledTimes++
+			return nil
+		},
+	}
+
+	name := settings.RecertificationCampaignCron.Name
+	_, err := controller.sync(name, &v3.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Value:      "0 0 1 */3 *",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, scheduleRecertificationCalledTimes; want != got {
+		t.Fatalf("Expected scheduleRecertificationCalledTimes: %d got %d", want, got)
+	}
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"time"
+
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	tokenUtil "github.com/rancher/rancher/pkg/auth/tokens"
 	"github.com/rancher/rancher/pkg/features"
@@ -84,6 +86,10 @@ func (t *TokenController) sync(key string, obj *v3.Token) (runtime.Object, error
 	if !features.TokenHashing.Enabled() {
 		return obj, nil
 	}
+	// the tokens are hashed in batches by the token hashing migration during its transition
+	if tokenUtil.InHashingTransition(time.Now()) {
+		return obj, nil
+	}
 
 	if obj.Annotations[tokenUtil.TokenHashed] != "true" {
 		newObj := obj.DeepCopy()
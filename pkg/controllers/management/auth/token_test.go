@@ -9,6 +9,7 @@
 	tokens2 "github.com/rancher/rancher/pkg/auth/tokens"
 	"github.com/rancher/rancher/pkg/auth/tokens/hashers"
 	"github.com/rancher/rancher/pkg/features"
+	"github.com/rancher/rancher/pkg/settings"
 	wranglerfake "github.com/rancher/wrangler/v3/pkg/generic/fake"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -27,6 +28,10 @@ type tokenTestCase struct {
 }
 
 func TestSync(t *testing.T) {
+	// the controller hashes the tokens once the transition of the token hashing migration ended
+	assert.NoError(t, settings.AuthTokenHashingStartedAt.Set("2020-01-01T00:00:00Z"))
+	defer settings.AuthTokenHashingStartedAt.Set("")
+
 	tokens := make(map[string]*v3.Token)
 	userAttributes := make(map[string]*v3.UserAttribute)
 
@@ -7,6 +7,7 @@
 	"errors"
 	"fmt"
 
+	"github.com/rancher/rancher/pkg/auth/attributelabels"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
 	"github.com/rancher/rancher/pkg/auth/userretention"
 	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
@@ -27,15 +28,18 @@ type UserAttributeController struct {
 	userAttributes            mgmtcontrollers.UserAttributeClient
 	providerRefresh           func(attribs *v3.UserAttribute) (*v3.UserAttribute, error)
 	ensureUserRetentionLabels func(attribs *v3.UserAttribute) error
+	ensureAttributeLabels     func(attribs *v3.UserAttribute) error
 }
 
 func newUserAttributeController(mgmt *config.ManagementContext) *UserAttributeController {
 	userretentionLabeler := userretention.NewUserLabeler(context.Background(), mgmt.Wrangler)
+	attributeLabeler := attributelabels.NewLabeler(context.Background(), mgmt.Wrangler.Mgmt)
 
 	return &UserAttributeController{
 		userAttributes:            mgmt.Wrangler.Mgmt.UserAttribute(),
 		providerRefresh:           providerrefresh.RefreshAttributes,
 		ensureUserRetentionLabels: userretentionLabeler.EnsureForAttributes,
+		ensureAttributeLabels:     attributeLabeler.EnsureForAttributes,
 	}
 }
 
@@ -53,6 +57,11 @@ func (c *UserAttributeController) sync(key string, attribs *v3.UserAttribute) (r
 		return nil, fmt.Errorf("error setting user retention labels for user %s: %w", name, err)
 	}
 
+	err = c.ensureAttributeLabels(attribs)
+	if err != nil {
+		return nil, fmt.Errorf("error setting attribute labels for user %s: %w", name, err)
+	}
+
 	if !attribs.NeedsRefresh {
 		return attribs, nil
 	}
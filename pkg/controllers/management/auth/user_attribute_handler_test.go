@@ -32,6 +32,7 @@ func TestSyncEnsureUserRetentionLabels(t *testing.T) {
 			ensureLabelsCalledTimes++
 			return nil
 		},
+		ensureAttributeLabels: func(attribs *v3.UserAttribute) error { return nil },
 	}
 
 	newAttribs := func(lastLogin metav1.Time) *v3.UserAttribute {
@@ -83,6 +84,7 @@ func TestSyncProviderRefreshNoConflict(t *testing.T) {
 	controller := UserAttributeController{
 		userAttributes:            userAttributeClient,
 		ensureUserRetentionLabels: func(attribs *v3.UserAttribute) error { return nil },
+		ensureAttributeLabels:     func(attribs *v3.UserAttribute) error { return nil },
 		providerRefresh: func(attribs *v3.UserAttribute) (*v3.UserAttribute, error) {
 			providerRefreshCalledTimes++
 			a := attribs.DeepCopy()
@@ -160,6 +162,7 @@ func TestSyncProviderRefreshConflict(t *testing.T) {
 	controller := UserAttributeController{
 		userAttributes:            userAttributeClient,
 		ensureUserRetentionLabels: func(attribs *v3.UserAttribute) error { return nil },
+		ensureAttributeLabels:     func(attribs *v3.UserAttribute) error { return nil },
 		providerRefresh: func(attribs *v3.UserAttribute) (*v3.UserAttribute, error) {
 			providerRefreshCalledTimes++
 			a := attribs.DeepCopy()
@@ -227,6 +230,7 @@ func TestSyncProviderRefreshUpdateNonConflictError(t *testing.T) {
 	controller := UserAttributeController{
 		userAttributes:            userAttributeClient,
 		ensureUserRetentionLabels: func(attribs *v3.UserAttribute) error { return nil },
+		ensureAttributeLabels:     func(attribs *v3.UserAttribute) error { return nil },
 		providerRefresh: func(attribs *v3.UserAttribute) (*v3.UserAttribute, error) {
 			providerRefreshCalledTimes++
 			a := attribs.DeepCopy()
@@ -286,6 +290,7 @@ func TestSyncProviderRefreshErrorAfterHandlingConflict(t *testing.T) {
 	controller := UserAttributeController{
 		userAttributes:            userAttributeClient,
 		ensureUserRetentionLabels: func(attribs *v3.UserAttribute) error { return nil },
+		ensureAttributeLabels:     func(attribs *v3.UserAttribute) error { return nil },
 		providerRefresh: func(attribs *v3.UserAttribute) (*v3.UserAttribute, error) {
 			providerRefreshCalledTimes++
 			a := attribs.DeepCopy()
@@ -324,6 +329,7 @@ func TestSyncGetUserAttributeFails(t *testing.T) {
 	controller := UserAttributeController{
 		userAttributes:            userAttributeClient,
 		ensureUserRetentionLabels: func(attribs *v3.UserAttribute) error { return nil },
+		ensureAttributeLabels:     func(attribs *v3.UserAttribute) error { return nil },
 	}
 
 	_, err := controller.sync("", attribs)
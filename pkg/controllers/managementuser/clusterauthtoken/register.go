@@ -8,6 +8,7 @@
 	"github.com/rancher/rancher/pkg/controllers/managementuser/clusterauthtoken/common"
 	managementv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
 )
@@ -90,6 +91,11 @@ func registerDeferred(ctx context.Context, cluster *config.UserContext) {
 			userAttributeLister,
 		})
 
+	revocations := newRevocationPusher(namespace, clusterName, clusterAuthToken, clusterAuthTokenLister)
+	if err := revocations.start(ctx, tokenInformer); err != nil {
+		logrus.Errorf("[%s] Failed to push token revocations to cluster %s: %v", tokenController, clusterName, err)
+	}
+
 	cluster.Management.Management.Users("").AddHandler(ctx, userController, (&userHandler{
 		namespace,
 		clusterUserAttribute,
@@ -0,0 +1,114 @@
+package clusterauthtoken
+
+import (
+	"context"
+
+	clusterv3 "github.com/rancher/rancher/pkg/generated/norman/cluster.cattle.io/v3"
+	managementv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// revocationQueueSize bounds the revocations waiting to be pushed to a cluster. The ones which don't fit are left to the
+// token lifecycle.
+const revocationQueueSize = 1024
+
+// revocationPusher pushes the revocation of tokens to a downstream cluster through the cluster agent tunnel as soon as
+// they're deleted or disabled, by deleting their ClusterAuthTokens, so that the authorized cluster endpoint stops
+// accepting them within seconds. It doesn't wait for the token lifecycle, which may be busy syncing other tokens, and
+// which still cleans up after the pushes which failed.
+type revocationPusher struct {
+	namespace              string
+	clusterName            string
+	clusterAuthToken       clusterv3.ClusterAuthTokenInterface
+	clusterAuthTokenLister clusterv3.ClusterAuthTokenLister
+	revoked                chan string
+}
+
+func newRevocationPusher(namespace, clusterName string, clusterAuthToken clusterv3.ClusterAuthTokenInterface, clusterAuthTokenLister clusterv3.ClusterAuthTokenLister) *revocationPusher {
+	return &revocationPusher{
+		namespace:              namespace,
+		clusterName:            clusterName,
+		clusterAuthToken:       clusterAuthToken,
+		clusterAuthTokenLister: clusterAuthTokenLister,
+		revoked:                make(chan string, revocationQueueSize),
+	}
+}
+
+// start watches the tokens with the informer and pushes their revocations until the context is done.
+func (p *revocationPusher) start(ctx context.Context, tokenInformer cache.SharedIndexInformer) error {
+	registration, err := tokenInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: p.onUpdate,
+		DeleteFunc: p.onDelete,
+	})
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer func() {
+			if err := tokenInformer.RemoveEventHandler(registration); err != nil {
+				logrus.Warnf("[%s] Failed to stop watching token revocations for cluster %s: %v", tokenController, p.clusterName, err)
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case name := <-p.revoked:
+				p.push(name)
+			}
+		}
+	}()
+	return nil
+}
+
+func (p *revocationPusher) onUpdate(oldObj, newObj interface{}) {
+	oldToken, ok := oldObj.(*managementv3.Token)
+	if !ok {
+		return
+	}
+	newToken, ok := newObj.(*managementv3.Token)
+	if !ok {
+		return
+	}
+	if isRevoked(newToken) && !isRevoked(oldToken) {
+		p.enqueue(newToken.Name)
+	}
+}
+
+func (p *revocationPusher) onDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if token, ok := obj.(*managementv3.Token); ok {
+		p.enqueue(token.Name)
+	}
+}
+
+func (p *revocationPusher) enqueue(name string) {
+	select {
+	case p.revoked <- name:
+	default:
+		logrus.Debugf("[%s] Too many token revocations pending for cluster %s, leaving token %s to the token lifecycle", tokenController, p.clusterName, name)
+	}
+}
+
+// push deletes the ClusterAuthToken of the revoked token, if the cluster has one.
+func (p *revocationPusher) push(name string) {
+	if _, err := p.clusterAuthTokenLister.Get(p.namespace, name); errors.IsNotFound(err) {
+		return
+	}
+	err := p.clusterAuthToken.Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		logrus.Warnf("[%s] Failed to push the revocation of token %s to cluster %s, leaving it to the token lifecycle: %v", tokenController, name, p.clusterName, err)
+		return
+	}
+	logrus.Debugf("[%s] Pushed the revocation of token %s to cluster %s", tokenController, name, p.clusterName)
+}
+
+// isRevoked tells whether the token is being deleted or is disabled.
+func isRevoked(token *managementv3.Token) bool {
+	return token.DeletionTimestamp != nil || (token.Enabled != nil && !*token.Enabled)
+}
@@ -0,0 +1,133 @@
+package clusterauthtoken
+
+import (
+	"fmt"
+	"testing"
+
+	clusterv3 "github.com/rancher/rancher/pkg/apis/cluster.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/generated/norman/cluster.cattle.io/v3/fakes"
+	managementv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+)
+
+func TestRevocationPusherEnqueue(t *testing.T) {
+	enabled := &managementv3.Token{ObjectMeta: metav1.ObjectMeta{Name: "enabled"}, Enabled: pointer.Bool(true)}
+	disabled := enabled.DeepCopy()
+	disabled.Enabled = pointer.Bool(false)
+	deleting := enabled.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+
+	tests := []struct {
+		name        string
+		event       func(p *revocationPusher)
+		wantPending []string
+	}{
+		{
+			name:        "disabled",
+			event:       func(p *revocationPusher) { p.onUpdate(enabled, disabled) },
+			wantPending: []string{"enabled"},
+		},
+		{
+			name:        "being deleted",
+			event:       func(p *revocationPusher) { p.onUpdate(enabled, deleting) },
+			wantPending: []string{"enabled"},
+		},
+		{
+			name:  "already disabled",
+			event: func(p *revocationPusher) { p.onUpdate(disabled, deleting) },
+		},
+		{
+			name:  "updated",
+			event: func(p *revocationPusher) { p.onUpdate(enabled, enabled.DeepCopy()) },
+		},
+		{
+			name:        "deleted",
+			event:       func(p *revocationPusher) { p.onDelete(enabled) },
+			wantPending: []string{"enabled"},
+		},
+		{
+			name: "deleted while disconnected",
+			event: func(p *revocationPusher) {
+				p.onDelete(cache.DeletedFinalStateUnknown{Key: "enabled", Obj: enabled})
+			},
+			wantPending: []string{"enabled"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newRevocationPusher("cattle-system", "c-1", nil, nil)
+			test.event(p)
+			var pending []string
+			for len(p.revoked) > 0 {
+				pending = append(pending, <-p.revoked)
+			}
+			assert.Equal(t, test.wantPending, pending)
+		})
+	}
+
+	// the revocations which don't fit are left to the token lifecycle
+	p := newRevocationPusher("cattle-system", "c-1", nil, nil)
+	for i := 0; i < revocationQueueSize+1; i++ {
+		p.onDelete(enabled)
+	}
+	assert.Len(t, p.revoked, revocationQueueSize)
+}
+
+func TestRevocationPusherPush(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "cluster.cattle.io", Resource: "ClusterAuthToken"}, "token")
+
+	tests := []struct {
+		name       string
+		listerErr  error
+		deleteErr  error
+		wantDelete bool
+	}{
+		{
+			name:       "cluster auth token deleted",
+			wantDelete: true,
+		},
+		{
+			name:      "no cluster auth token",
+			listerErr: notFound,
+		},
+		{
+			name:       "delete failed",
+			deleteErr:  fmt.Errorf("tunnel disconnected"),
+			wantDelete: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lister := &fakes.ClusterAuthTokenListerMock{
+				GetFunc: func(namespace, name string) (*clusterv3.ClusterAuthToken, error) {
+					if test.listerErr != nil {
+						return nil, test.listerErr
+					}
+					return &clusterv3.ClusterAuthToken{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}, nil
+				},
+			}
+			clusterAuthTokens := &fakes.ClusterAuthTokenInterfaceMock{
+				DeleteFunc: func(name string, options *metav1.DeleteOptions) error {
+					return test.deleteErr
+				},
+			}
+
+			p := newRevocationPusher("cattle-system", "c-1", clusterAuthTokens, lister)
+			p.push("token")
+
+			if test.wantDelete {
+				calls := clusterAuthTokens.DeleteCalls()
+				assert.Len(t, calls, 1)
+				assert.Equal(t, "token", calls[0].Name)
+			} else {
+				assert.Empty(t, clusterAuthTokens.DeleteCalls())
+			}
+		})
+	}
+}
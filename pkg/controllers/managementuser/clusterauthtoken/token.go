@@ -42,14 +42,20 @@ func (h *tokenHandler) Create(token *managementv3.Token) (runtime.Object, error)
 	_, err := h.clusterAuthTokenLister.Get(h.namespace, token.Name)
 	if !errors.IsNotFound(err) {
 		return h.Updated(token)
-	} else if features.TokenHashing.Enabled() {
+	}
+	// the downstream clusters get the hash of the token, never its encrypted value
+	storedKey, err := tokens.StoredKey(token)
+	if err != nil {
+		return token, fmt.Errorf("unable to read the value of token [%s]: %w", token.Name, err)
+	}
+	if features.TokenHashing.Enabled() {
 		// we can sync tokens which are hashed by copying the hash downstream
 		if token.Annotations[tokens.TokenHashed] != "true" {
 			// re-enqueue until the token has been hashed
 			return token, fmt.Errorf("token [%s] has not been hashed yet, re-enqueing until has has completed", token.Name)
 		}
 		// token is hashed, we can safely attempt to sync downstream
-		hashVersion, err := hashers.GetHashVersion(token.Token)
+		hashVersion, err := hashers.GetHashVersion(storedKey)
 		if err != nil {
 			// the token hash is unlikely to change, re-enqueing would just produce a flood of errors
 			logrus.Errorf("unable to determine hash version of token [%s], will not sync token: %s", token.Name, err.Error())
@@ -57,7 +63,7 @@ func (h *tokenHandler) Create(token *managementv3.Token) (runtime.Object, error)
 		}
 		// we only sync tokens downstream that were created with SHA3
 		if hashVersion == hashers.SHA3Version {
-			return nil, h.createClusterAuthToken(token, token.Token)
+			return nil, h.createClusterAuthToken(token, storedKey)
 		}
 		// token is hashed, but we can't sync it since we don't have the raw value
 		logrus.Warnf("token [%s] will not be synced or useable for ACE because it uses an older hash version, generate a new token to use ACE", token.Name)
@@ -67,7 +73,7 @@ func (h *tokenHandler) Create(token *managementv3.Token) (runtime.Object, error)
 	}
 	// token isn't hashed, hash the value only for downstream
 	hasher := hashers.GetHasher()
-	hashedValue, err := hasher.CreateHash(token.Token)
+	hashedValue, err := hasher.CreateHash(storedKey)
 	if err != nil {
 		return nil, fmt.Errorf("unable to hash value for token [%s]: %w", token.Name, err)
 	}
@@ -120,7 +126,11 @@ func (h *tokenHandler) Updated(token *managementv3.Token) (runtime.Object, error
 
 	// if the token is hashed, compare its value to make sure the downstream has the latest hash
 	if token.Annotations[tokens.TokenHashed] == "true" {
-		hashVersion, err := hashers.GetHashVersion(token.Token)
+		storedKey, err := tokens.StoredKey(token)
+		if err != nil {
+			return token, fmt.Errorf("unable to read the value of token [%s]: %w", token.Name, err)
+		}
+		hashVersion, err := hashers.GetHashVersion(storedKey)
 		if err != nil {
 			logrus.Errorf("unable to determine hash version of token [%s], will not sync token: %s", token.Name, err.Error())
 			return token, generic.ErrSkip
@@ -128,7 +138,7 @@ func (h *tokenHandler) Updated(token *managementv3.Token) (runtime.Object, error
 		// we only sync tokens downstream that were created with SHA3
 		if hashVersion == hashers.SHA3Version {
 			// trigger the compare to compare the values of the tokens
-			current.value = token.Token
+			current.value = storedKey
 			old.value = clusterAuthToken.SecretKeyHash
 		}
 	}
@@ -10,6 +10,7 @@
 	controllersv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	typesrbacv1 "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1"
+	"github.com/rancher/rancher/pkg/metrics"
 	pkgrbac "github.com/rancher/rancher/pkg/rbac"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/sirupsen/logrus"
@@ -22,6 +23,8 @@
 )
 
 const (
+	crtbHandlerName = "cluster-crtb-sync"
+
 	clusterRolesExists                       = "ClusterRolesExists"
 	clusterRoleBindingsExists                = "ClusterRoleBindingsExists"
 	serviceAccountImpersonatorExists         = "ServiceAccountImpersonatorExists"
@@ -38,6 +41,7 @@
 	failedToListCRBs                         = "FailedToListCRBs"
 	failedToUpdateCRBs                       = "FailedToUpdateCRBs"
 	failedToDeleteClusterRoleBinding         = "FailedToDeleteClusterRoleBinding"
+	failedToDeleteRoleBinding                = "FailedToDeleteRoleBinding"
 	failedToDeleteServiceAccountImpersonator = "FailedToDeleteServiceAccountImpersonator"
 )
 
@@ -64,16 +68,22 @@ type crtbLifecycle struct {
 }
 
 func (c *crtbLifecycle) Create(obj *v3.ClusterRoleTemplateBinding) (runtime.Object, error) {
+	started := time.Now()
 	remoteConditions := []metav1.Condition{}
-	return obj, errors.Join(c.syncCRTB(obj, &remoteConditions),
+	err := errors.Join(c.syncCRTB(obj, &remoteConditions),
 		c.updateStatus(obj, remoteConditions))
+	metrics.ObserveRBACReconcile(crtbHandlerName, obj.ClusterName, started, remoteConditions, err)
+	return obj, err
 }
 
 func (c *crtbLifecycle) Updated(obj *v3.ClusterRoleTemplateBinding) (runtime.Object, error) {
+	started := time.Now()
 	remoteConditions := []metav1.Condition{}
-	return obj, errors.Join(c.reconcileCRTBUserClusterLabels(obj, &remoteConditions),
+	err := errors.Join(c.reconcileCRTBUserClusterLabels(obj, &remoteConditions),
 		c.syncCRTB(obj, &remoteConditions),
 		c.updateStatus(obj, remoteConditions))
+	metrics.ObserveRBACReconcile(crtbHandlerName, obj.ClusterName, started, remoteConditions, err)
+	return obj, err
 }
 
 func (c *crtbLifecycle) Remove(obj *v3.ClusterRoleTemplateBinding) (runtime.Object, error) {
@@ -120,7 +130,19 @@ func (c *crtbLifecycle) syncCRTB(binding *v3.ClusterRoleTemplateBinding, remoteC
 	c.s.AddCondition(remoteConditions, condition, clusterRolesExists, nil)
 
 	condition = metav1.Condition{Type: clusterRoleBindingsExists}
-	if err := c.m.ensureClusterBindings(roles, binding); err != nil {
+	if binding.NamespaceSelector != nil {
+		// the roles are bound in the selected namespaces only, so no cluster binding must remain
+		if err := c.m.ensureClusterBindings(map[string]*v3.RoleTemplate{}, binding); err != nil {
+			err = fmt.Errorf("couldn't remove cluster bindings %v: %w", binding.Name, err)
+			c.s.AddCondition(remoteConditions, condition, failedToCreateBindings, err)
+			return err
+		}
+		if err := c.m.ensureNamespaceSelectorBindings(roles, binding); err != nil {
+			err = fmt.Errorf("couldn't ensure namespace bindings %v: %w", binding.Name, err)
+			c.s.AddCondition(remoteConditions, condition, failedToCreateBindings, err)
+			return err
+		}
+	} else if err := c.m.ensureClusterBindings(roles, binding); err != nil {
 		err = fmt.Errorf("couldn't ensure cluster bindings %v: %w", binding.Name, err)
 		c.s.AddCondition(remoteConditions, condition, failedToCreateBindings, err)
 		return err
@@ -159,6 +181,13 @@ func (c *crtbLifecycle) ensureCRTBDelete(binding *v3.ClusterRoleTemplateBinding,
 		}
 	}
 
+	if binding.NamespaceSelector != nil {
+		if err := c.m.ensureNamespaceSelectorBindings(nil, binding); err != nil {
+			c.s.AddCondition(remoteConditions, condition, failedToDeleteRoleBinding, err)
+			return fmt.Errorf("error deleting namespace bindings: %w", err)
+		}
+	}
+
 	if err := c.m.deleteServiceAccountImpersonator(binding.UserName); err != nil {
 		c.s.AddCondition(remoteConditions, condition, failedToDeleteServiceAccountImpersonator, err)
 		return fmt.Errorf("error deleting service account impersonator: %w", err)
@@ -252,7 +281,8 @@ func (c *crtbLifecycle) updateStatus(crtb *v3.ClusterRoleTemplateBinding, remote
 		if err != nil {
 			return err
 		}
-		if status.CompareConditions(crtbFromCluster.Status.RemoteConditions, remoteConditions) {
+		if crtbFromCluster.Status.ObservedGenerationRemote == crtb.Generation &&
+			status.CompareConditions(crtbFromCluster.Status.RemoteConditions, remoteConditions) {
 			return nil
 		}
 
@@ -27,6 +27,13 @@
 		UserName:         "crtb-name",
 		RoleTemplateName: "",
 	}
+	namespaceSelectorCRTB = v3.ClusterRoleTemplateBinding{
+		UserName:         "crtb-name",
+		RoleTemplateName: "rt-name",
+		NamespaceSelector: &v1.LabelSelector{
+			MatchLabels: map[string]string{"platform": "true"},
+		},
+	}
 	noSubjectCRTB = v3.ClusterRoleTemplateBinding{
 		UserName:           "",
 		GroupName:          "",
@@ -266,6 +273,79 @@ func TestSyncCRTB(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "success with a namespace selector",
+			stateSetup: func(cts crtbTestState) {
+				cts.rtListerMock.GetFunc = func(namespace, name string) (*v3.RoleTemplate, error) {
+					return nil, nil
+				}
+				cts.managerMock.EXPECT().gatherRoles(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				cts.managerMock.EXPECT().ensureRoles(gomock.Any()).Return(nil)
+				cts.managerMock.EXPECT().ensureClusterBindings(map[string]*v3.RoleTemplate{}, gomock.Any()).Return(nil)
+				cts.managerMock.EXPECT().ensureNamespaceSelectorBindings(gomock.Any(), gomock.Any()).Return(nil)
+				cts.managerMock.EXPECT().ensureServiceAccountImpersonator(gomock.Any()).Return(nil)
+			},
+			crtb: namespaceSelectorCRTB.DeepCopy(),
+			wantConditions: []v1.Condition{
+				{
+					Type:   clusterRolesExists,
+					Status: v1.ConditionTrue,
+					Reason: clusterRolesExists,
+					LastTransitionTime: v1.Time{
+						Time: mockTime,
+					},
+				},
+				{
+					Type:   clusterRoleBindingsExists,
+					Status: v1.ConditionTrue,
+					Reason: clusterRoleBindingsExists,
+					LastTransitionTime: v1.Time{
+						Time: mockTime,
+					},
+				},
+				{
+					Type:   serviceAccountImpersonatorExists,
+					Status: v1.ConditionTrue,
+					Reason: serviceAccountImpersonatorExists,
+					LastTransitionTime: v1.Time{
+						Time: mockTime,
+					},
+				},
+			},
+		},
+		{
+			name: "error ensuring namespace selector bindings",
+			stateSetup: func(cts crtbTestState) {
+				cts.rtListerMock.GetFunc = func(namespace, name string) (*v3.RoleTemplate, error) {
+					return nil, nil
+				}
+				cts.managerMock.EXPECT().gatherRoles(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				cts.managerMock.EXPECT().ensureRoles(gomock.Any()).Return(nil)
+				cts.managerMock.EXPECT().ensureClusterBindings(map[string]*v3.RoleTemplate{}, gomock.Any()).Return(nil)
+				cts.managerMock.EXPECT().ensureNamespaceSelectorBindings(gomock.Any(), gomock.Any()).Return(e)
+			},
+			crtb:      namespaceSelectorCRTB.DeepCopy(),
+			wantError: true,
+			wantConditions: []v1.Condition{
+				{
+					Type:   clusterRolesExists,
+					Status: v1.ConditionTrue,
+					Reason: clusterRolesExists,
+					LastTransitionTime: v1.Time{
+						Time: mockTime,
+					},
+				},
+				{
+					Type:    clusterRoleBindingsExists,
+					Status:  v1.ConditionFalse,
+					Message: "couldn't ensure namespace bindings : " + e.Error(),
+					Reason:  failedToCreateBindings,
+					LastTransitionTime: v1.Time{
+						Time: mockTime,
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -401,6 +481,32 @@ func TestUpdateStatus(t *testing.T) {
 			},
 			remoteConditions: crtbClusterRoleBindingExists.Status.RemoteConditions,
 		},
+		"status updated when the generation wasn't observed": {
+			crtb: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta: v1.ObjectMeta{Generation: 2},
+				Status: v3.ClusterRoleTemplateBindingStatus{
+					RemoteConditions:         crtbClusterRolesExists.Status.RemoteConditions,
+					ObservedGenerationRemote: 1,
+					SummaryRemote:            status.SummaryCompleted,
+					LastUpdateTime:           mockTime.Format(time.RFC3339),
+				},
+			},
+			crtbClient: func(crtb *v3.ClusterRoleTemplateBinding) controllersv3.ClusterRoleTemplateBindingController {
+				mock := fake.NewMockControllerInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl)
+				mock.EXPECT().UpdateStatus(&v3.ClusterRoleTemplateBinding{
+					ObjectMeta: v1.ObjectMeta{Generation: 2},
+					Status: v3.ClusterRoleTemplateBindingStatus{
+						RemoteConditions:         crtbClusterRolesExists.Status.RemoteConditions,
+						ObservedGenerationRemote: 2,
+						SummaryRemote:            status.SummaryCompleted,
+						LastUpdateTime:           mockTime.Format(time.RFC3339),
+					},
+				})
+
+				return mock
+			},
+			remoteConditions: crtbClusterRolesExists.Status.RemoteConditions,
+		},
 		"set summary to complete when local is complete": {
 			crtb: crtbEmptyStatusLocalComplete.DeepCopy(),
 			crtbClient: func(crtb *v3.ClusterRoleTemplateBinding) controllersv3.ClusterRoleTemplateBindingController {
@@ -144,8 +144,8 @@ func Register(ctx context.Context, workload *config.UserContext) {
 	if features.AggregatedRoleTemplates.Enabled() {
 		roletemplates.Register(ctx, workload)
 	} else {
-		management.Management.ProjectRoleTemplateBindings("").AddClusterScopedLifecycle(ctx, "cluster-prtb-sync", workload.ClusterName, newPRTBLifecycle(r, management, nsInformer))
-		management.Management.ClusterRoleTemplateBindings("").AddClusterScopedLifecycle(ctx, "cluster-crtb-sync", workload.ClusterName, newCRTBLifecycle(r, management))
+		management.Management.ProjectRoleTemplateBindings("").AddClusterScopedLifecycle(ctx, prtbHandlerName, workload.ClusterName, newPRTBLifecycle(r, management, nsInformer))
+		management.Management.ClusterRoleTemplateBindings("").AddClusterScopedLifecycle(ctx, crtbHandlerName, workload.ClusterName, newCRTBLifecycle(r, management))
 		management.Management.RoleTemplates("").AddHandler(ctx, "cluster-roletemplate-sync", newRTLifecycle(r))
 	}
 }
@@ -154,6 +154,7 @@ type managerInterface interface {
 	gatherRoles(*v3.RoleTemplate, map[string]*v3.RoleTemplate, int) error
 	ensureRoles(map[string]*v3.RoleTemplate) error
 	ensureClusterBindings(map[string]*v3.RoleTemplate, *v3.ClusterRoleTemplateBinding) error
+	ensureNamespaceSelectorBindings(map[string]*v3.RoleTemplate, *v3.ClusterRoleTemplateBinding) error
 	ensureProjectRoleBindings(string, map[string]*v3.RoleTemplate, *v3.ProjectRoleTemplateBinding) error
 	ensureServiceAccountImpersonator(string) error
 	deleteServiceAccountImpersonator(string) error
@@ -291,6 +292,12 @@ func (m *manager) gatherRoles(rt *v3.RoleTemplate, roleTemplates map[string]*v3.
 }
 
 func (m *manager) gatherRolesRecurse(rt *v3.RoleTemplate, roleTemplates map[string]*v3.RoleTemplate, depthCounter int) error {
+	rt, err := pkgrbac.FlattenRoleTemplate(rt, func(name string) (*v3.RoleTemplate, error) {
+		return m.rtLister.Get("", name)
+	})
+	if err != nil {
+		return err
+	}
 	roleTemplates[rt.Name] = rt
 	depthCounter++
 
@@ -11,11 +11,13 @@
 	"github.com/rancher/rancher/pkg/apis/management.cattle.io"
 	apisV3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/resourcequota"
+	"github.com/rancher/rancher/pkg/features"
 	fleetconst "github.com/rancher/rancher/pkg/fleet"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	namespaceutil "github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/project"
 	projectpkg "github.com/rancher/rancher/pkg/project"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -126,6 +128,13 @@ func (n *nsLifecycle) syncNS(obj *v1.Namespace) (bool, error) {
 		return false, err
 	}
 
+	// with aggregated role templates, the bindings of namespace selectors aren't synced by this controller
+	if !features.AggregatedRoleTemplates.Enabled() {
+		if err := n.m.ensureNamespaceSelectorBindingsForNamespace(obj); err != nil {
+			return false, errors.Wrapf(err, "couldn't ensure namespace selector bindings in %s", obj.Name)
+		}
+	}
+
 	return hasPRTBs, nil
 }
 
@@ -237,6 +246,19 @@ func (n *nsLifecycle) ensurePRTBAddToNamespace(ns *v1.Namespace) (bool, error) {
 			continue
 		}
 
+		selected, err := pkgrbac.NamespaceSelected(prtb.NamespaceSelector, ns.Labels)
+		if err != nil {
+			logrus.Warnf("ProjectRoleTemplateBinding %v: %v. Skipping.", prtb.Name, err)
+			continue
+		}
+		if !selected {
+			// the labels of the namespace may have changed since the binding selected it
+			if err := n.m.ensureProjectRoleBindings(ns.Name, map[string]*v3.RoleTemplate{}, prtb); err != nil {
+				return false, errors.Wrapf(err, "couldn't remove binding %v from %v", prtb.Name, ns.Name)
+			}
+			continue
+		}
+
 		rt, err := n.m.rtLister.Get("", prtb.RoleTemplateName)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
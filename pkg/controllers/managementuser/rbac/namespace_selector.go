@@ -0,0 +1,166 @@
+package rbac
+
+import (
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/cache"
+)
+
+// crtbNamespaceOwnerLabel labels the RoleBindings of a ClusterRoleTemplateBinding scoped to namespaces by a namespace
+// selector. It's distinct from rtbOwnerLabel so that these RoleBindings aren't mistaken for those of PRTBs.
+const crtbNamespaceOwnerLabel = "authz.cluster.cattle.io/crtb-namespace-owner"
+
+// ensureNamespaceSelectorBindings binds the roles of a ClusterRoleTemplateBinding with a namespace selector by a
+// RoleBinding in each namespace matching the selector, and deletes its RoleBindings in the other namespaces.
+// All its RoleBindings are deleted when there are no roles.
+func (m *manager) ensureNamespaceSelectorBindings(roles map[string]*v3.RoleTemplate, binding *v3.ClusterRoleTemplateBinding) error {
+	desiredRBs := map[string]*rbacv1.RoleBinding{}
+	if len(roles) > 0 {
+		selector, err := metav1.LabelSelectorAsSelector(binding.NamespaceSelector)
+		if err != nil {
+			return fmt.Errorf("invalid namespace selector: %w", err)
+		}
+		namespaces, err := m.nsLister.List("", selector)
+		if err != nil {
+			return fmt.Errorf("couldn't list namespaces matching %s: %w", selector, err)
+		}
+		for _, ns := range namespaces {
+			if ns.DeletionTimestamp != nil {
+				continue
+			}
+			if err := addNamespaceSelectorBindings(desiredRBs, ns.Name, roles, binding); err != nil {
+				return err
+			}
+		}
+	}
+
+	set := labels.Set{crtbNamespaceOwnerLabel: pkgrbac.GetRTBLabel(binding.ObjectMeta)}
+	currentRBs, err := m.rbLister.List("", set.AsSelector())
+	if err != nil {
+		return fmt.Errorf("couldn't list rolebindings with selector %s: %w", set.AsSelector(), err)
+	}
+	return m.reconcileNamespaceSelectorBindings(desiredRBs, currentRBs)
+}
+
+// ensureNamespaceSelectorBindingsForNamespace reconciles the RoleBindings in a namespace of the ClusterRoleTemplateBindings
+// of the cluster with a namespace selector, so that they follow the creation of namespaces and changes of their labels.
+func (m *manager) ensureNamespaceSelectorBindingsForNamespace(ns *v1.Namespace) error {
+	desiredRBs := map[string]*rbacv1.RoleBinding{}
+	if ns.DeletionTimestamp == nil {
+		objs, err := m.crtbIndexer.ByIndex(cache.NamespaceIndex, m.clusterName)
+		if err != nil {
+			return fmt.Errorf("couldn't get cluster role template bindings of cluster %s: %w", m.clusterName, err)
+		}
+		for _, obj := range objs {
+			binding, ok := obj.(*v3.ClusterRoleTemplateBinding)
+			if !ok || !namespaceSelectorMatches(binding, ns) {
+				continue
+			}
+			rt, err := m.rtLister.Get("", binding.RoleTemplateName)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("couldn't get role template %s: %w", binding.RoleTemplateName, err)
+			}
+			roles := map[string]*v3.RoleTemplate{}
+			if err := m.gatherRoles(rt, roles, 0); err != nil {
+				return err
+			}
+			if err := m.ensureRoles(roles); err != nil {
+				return fmt.Errorf("couldn't ensure roles: %w", err)
+			}
+			if err := addNamespaceSelectorBindings(desiredRBs, ns.Name, roles, binding); err != nil {
+				return err
+			}
+		}
+	}
+
+	owned, err := labels.NewRequirement(crtbNamespaceOwnerLabel, selection.Exists, nil)
+	if err != nil {
+		return err
+	}
+	currentRBs, err := m.rbLister.List(ns.Name, labels.NewSelector().Add(*owned))
+	if err != nil {
+		return fmt.Errorf("couldn't list rolebindings in %s: %w", ns.Name, err)
+	}
+	return m.reconcileNamespaceSelectorBindings(desiredRBs, currentRBs)
+}
+
+// namespaceSelectorMatches returns whether the namespace matches the namespace selector of a binding that can be synced.
+func namespaceSelectorMatches(binding *v3.ClusterRoleTemplateBinding, ns *v1.Namespace) bool {
+	if binding.NamespaceSelector == nil || binding.DeletionTimestamp != nil || binding.RoleTemplateName == "" ||
+		(binding.UserName == "" && binding.GroupPrincipalName == "" && binding.GroupName == "") {
+		return false
+	}
+	selected, err := pkgrbac.NamespaceSelected(binding.NamespaceSelector, ns.Labels)
+	if err != nil {
+		logrus.Warnf("ClusterRoleTemplateBinding %s/%s: %v", binding.Namespace, binding.Name, err)
+	}
+	return selected
+}
+
+// addNamespaceSelectorBindings adds the RoleBindings of the roles of a binding in a namespace to the desired ones,
+// keyed by namespace and name.
+func addNamespaceSelectorBindings(desiredRBs map[string]*rbacv1.RoleBinding, namespace string, roles map[string]*v3.RoleTemplate, binding *v3.ClusterRoleTemplateBinding) error {
+	subject, err := pkgrbac.BuildSubjectFromRTB(binding)
+	if err != nil {
+		return err
+	}
+	for roleName := range roles {
+		rb := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.SafeConcatName("crtb", binding.Name, roleName),
+				Namespace: namespace,
+				Labels:    map[string]string{crtbNamespaceOwnerLabel: pkgrbac.GetRTBLabel(binding.ObjectMeta)},
+			},
+			Subjects: []rbacv1.Subject{subject},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     roleName,
+			},
+		}
+		desiredRBs[rb.Namespace+"/"+rb.Name] = rb
+	}
+	return nil
+}
+
+// reconcileNamespaceSelectorBindings creates the desired RoleBindings missing from the current ones, and deletes the
+// current ones which aren't desired. A RoleBinding whose role or subjects differ is deleted and created again, as its
+// role can't be updated.
+func (m *manager) reconcileNamespaceSelectorBindings(desiredRBs map[string]*rbacv1.RoleBinding, currentRBs []*rbacv1.RoleBinding) error {
+	for _, rb := range currentRBs {
+		key := rb.Namespace + "/" + rb.Name
+		desired, ok := desiredRBs[key]
+		if ok && desired.RoleRef.Name == rb.RoleRef.Name && desired.RoleRef.Kind == rb.RoleRef.Kind &&
+			equality.Semantic.DeepEqual(desired.Subjects, rb.Subjects) &&
+			desired.Labels[crtbNamespaceOwnerLabel] == rb.Labels[crtbNamespaceOwnerLabel] {
+			delete(desiredRBs, key)
+			continue
+		}
+		logrus.Infof("Deleting roleBinding %s in %s", rb.Name, rb.Namespace)
+		if err := m.workload.RBAC.RoleBindings(rb.Namespace).Delete(rb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("couldn't delete rolebinding %s in %s: %w", rb.Name, rb.Namespace, err)
+		}
+	}
+
+	for _, rb := range desiredRBs {
+		logrus.Infof("Creating roleBinding %s in %s", rb.Name, rb.Namespace)
+		if _, err := m.workload.RBAC.RoleBindings(rb.Namespace).Create(rb); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("couldn't create rolebinding %s in %s: %w", rb.Name, rb.Namespace, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package rbac
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespaceSelectorMatches(t *testing.T) {
+	t.Parallel()
+	platformNS := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "kube-system",
+			Labels: map[string]string{"platform": "true"},
+		},
+	}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"platform": "true"}}
+
+	tests := []struct {
+		name    string
+		binding *v3.ClusterRoleTemplateBinding
+		ns      *corev1.Namespace
+		want    bool
+	}{
+		{
+			name:    "matching namespace",
+			binding: &v3.ClusterRoleTemplateBinding{UserName: "u-1", RoleTemplateName: "view", NamespaceSelector: selector},
+			ns:      platformNS,
+			want:    true,
+		},
+		{
+			name:    "namespace not matching",
+			binding: &v3.ClusterRoleTemplateBinding{UserName: "u-1", RoleTemplateName: "view", NamespaceSelector: selector},
+			ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		},
+		{
+			name:    "empty selector matches every namespace",
+			binding: &v3.ClusterRoleTemplateBinding{UserName: "u-1", RoleTemplateName: "view", NamespaceSelector: &metav1.LabelSelector{}},
+			ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			want:    true,
+		},
+		{
+			name:    "no selector",
+			binding: &v3.ClusterRoleTemplateBinding{UserName: "u-1", RoleTemplateName: "view"},
+			ns:      platformNS,
+		},
+		{
+			name:    "no subject",
+			binding: &v3.ClusterRoleTemplateBinding{RoleTemplateName: "view", NamespaceSelector: selector},
+			ns:      platformNS,
+		},
+		{
+			name: "deleting binding",
+			binding: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:        metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{}},
+				UserName:          "u-1",
+				RoleTemplateName:  "view",
+				NamespaceSelector: selector,
+			},
+			ns: platformNS,
+		},
+		{
+			name: "invalid selector",
+			binding: &v3.ClusterRoleTemplateBinding{
+				UserName:         "u-1",
+				RoleTemplateName: "view",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "platform", Operator: "Bogus"}},
+				},
+			},
+			ns: platformNS,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, namespaceSelectorMatches(tt.binding, tt.ns))
+		})
+	}
+}
+
+func TestAddNamespaceSelectorBindings(t *testing.T) {
+	t.Parallel()
+	binding := &v3.ClusterRoleTemplateBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crtb-grb-abcde",
+			Namespace: "c-abcde",
+		},
+		UserName:         "u-1",
+		RoleTemplateName: "view",
+	}
+	roles := map[string]*v3.RoleTemplate{"view": {}}
+
+	desiredRBs := map[string]*rbacv1.RoleBinding{}
+	require.NoError(t, addNamespaceSelectorBindings(desiredRBs, "kube-system", roles, binding))
+
+	want := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crtb-crtb-grb-abcde-view",
+			Namespace: "kube-system",
+			Labels:    map[string]string{crtbNamespaceOwnerLabel: "c-abcde_crtb-grb-abcde"},
+		},
+		Subjects: []rbacv1.Subject{
+			{Kind: "User", APIGroup: rbacv1.GroupName, Name: "u-1"},
+		},
+		RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+	}
+	assert.Equal(t, map[string]*rbacv1.RoleBinding{"kube-system/crtb-crtb-grb-abcde-view": want}, desiredRBs)
+}
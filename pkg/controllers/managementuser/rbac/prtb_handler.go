@@ -5,11 +5,15 @@
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/rancher/norman/types/convert"
 	"github.com/rancher/norman/types/slice"
+	"github.com/rancher/rancher/pkg/controllers/status"
+	controllersv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	typesrbacv1 "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1"
+	"github.com/rancher/rancher/pkg/metrics"
 	"github.com/rancher/rancher/pkg/namespace"
 	pkgrbac "github.com/rancher/rancher/pkg/rbac"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -26,7 +30,12 @@
 	"k8s.io/client-go/util/retry"
 )
 
-const owner = "owner-user"
+const (
+	owner           = "owner-user"
+	prtbHandlerName = "cluster-prtb-sync"
+
+	roleBindingsExists = "RoleBindingsExists"
+)
 
 // globalResourceRulesNeededInProjects is the set of PolicyRules that need to be present on *-promoted ClusterRoles
 // Binding a user to a *-promoted ClusterRole with these PolicyRules results in that user being granted access to these "global" resources.
@@ -70,6 +79,9 @@ func newPRTBLifecycle(m *manager, management *config.ManagementContext, nsInform
 		crbLister:  m.workload.RBAC.ClusterRoleBindings("").Controller().Lister(),
 		crbClient:  m.workload.RBAC.ClusterRoleBindings(""),
 		prtbClient: management.Management.ProjectRoleTemplateBindings(""),
+		prtbStatus: management.Wrangler.Mgmt.ProjectRoleTemplateBinding(),
+		prtbCache:  management.Wrangler.Mgmt.ProjectRoleTemplateBinding().Cache(),
+		s:          status.NewStatus(),
 	}
 }
 
@@ -82,18 +94,26 @@ type prtbLifecycle struct {
 	crbLister  typesrbacv1.ClusterRoleBindingLister
 	crbClient  typesrbacv1.ClusterRoleBindingInterface
 	prtbClient v3.ProjectRoleTemplateBindingInterface
+	prtbStatus controllersv3.ProjectRoleTemplateBindingController
+	prtbCache  controllersv3.ProjectRoleTemplateBindingCache
+	s          *status.Status
 }
 
 func (p *prtbLifecycle) Create(obj *v3.ProjectRoleTemplateBinding) (runtime.Object, error) {
-	err := p.syncPRTB(obj)
+	started := time.Now()
+	remoteConditions, err := p.syncPRTBWithStatus(obj)
+	metrics.ObserveRBACReconcile(prtbHandlerName, parseClusterName(obj.ProjectName), started, remoteConditions, err)
 	return obj, err
 }
 
 func (p *prtbLifecycle) Updated(obj *v3.ProjectRoleTemplateBinding) (runtime.Object, error) {
+	started := time.Now()
 	if err := p.reconcilePRTBUserClusterLabels(obj); err != nil {
+		metrics.ObserveRBACReconcile(prtbHandlerName, parseClusterName(obj.ProjectName), started, nil, err)
 		return obj, err
 	}
-	err := p.syncPRTB(obj)
+	remoteConditions, err := p.syncPRTBWithStatus(obj)
+	metrics.ObserveRBACReconcile(prtbHandlerName, parseClusterName(obj.ProjectName), started, remoteConditions, err)
 	return obj, err
 }
 
@@ -102,6 +122,45 @@ func (p *prtbLifecycle) Remove(obj *v3.ProjectRoleTemplateBinding) (runtime.Obje
 	return obj, err
 }
 
+// syncPRTBWithStatus syncs the binding and records the outcome in its status, so that whether the binding was rendered
+// in the downstream cluster shows.
+func (p *prtbLifecycle) syncPRTBWithStatus(binding *v3.ProjectRoleTemplateBinding) ([]metav1.Condition, error) {
+	remoteConditions := []metav1.Condition{}
+	err := p.syncPRTB(binding)
+	reason := roleBindingsExists
+	if err != nil {
+		reason = failedToCreateBindings
+	}
+	p.s.AddCondition(&remoteConditions, metav1.Condition{Type: roleBindingsExists}, reason, err)
+	return remoteConditions, errors.Join(err, p.updateStatus(binding, remoteConditions))
+}
+
+func (p *prtbLifecycle) updateStatus(prtb *v3.ProjectRoleTemplateBinding, remoteConditions []metav1.Condition) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		prtbFromCluster, err := p.prtbCache.Get(prtb.Namespace, prtb.Name)
+		if err != nil {
+			return err
+		}
+		if prtbFromCluster.Status.ObservedGenerationRemote == prtb.Generation &&
+			status.CompareConditions(prtbFromCluster.Status.RemoteConditions, remoteConditions) {
+			return nil
+		}
+
+		prtbFromCluster = prtbFromCluster.DeepCopy()
+		prtbFromCluster.Status.SummaryRemote = status.SummaryCompleted
+		for _, c := range remoteConditions {
+			if c.Status != metav1.ConditionTrue {
+				prtbFromCluster.Status.SummaryRemote = status.SummaryError
+				break
+			}
+		}
+		prtbFromCluster.Status.ObservedGenerationRemote = prtb.Generation
+		prtbFromCluster.Status.RemoteConditions = remoteConditions
+		_, err = p.prtbStatus.UpdateStatus(prtbFromCluster)
+		return err
+	})
+}
+
 func (p *prtbLifecycle) syncPRTB(binding *v3.ProjectRoleTemplateBinding) error {
 	if binding.RoleTemplateName == "" {
 		logrus.Warnf("ProjectRoleTemplateBinding %s has no role template set. Skipping.", binding.Name)
@@ -138,7 +197,16 @@ func (p *prtbLifecycle) syncPRTB(binding *v3.ProjectRoleTemplateBinding) error {
 		if !ns.DeletionTimestamp.IsZero() {
 			continue
 		}
-		if err := p.m.ensureProjectRoleBindings(ns.Name, roles, binding); err != nil {
+		selected, err := pkgrbac.NamespaceSelected(binding.NamespaceSelector, ns.Labels)
+		if err != nil {
+			return fmt.Errorf("couldn't select namespaces of binding %v: %w", binding.Name, err)
+		}
+		nsRoles := roles
+		if !selected {
+			// binding no role removes the bindings from the namespaces which aren't, or are no longer, selected
+			nsRoles = map[string]*v3.RoleTemplate{}
+		}
+		if err := p.m.ensureProjectRoleBindings(ns.Name, nsRoles, binding); err != nil {
 			return fmt.Errorf("couldn't ensure binding %v in %v: %w", binding.Name, ns.Name, err)
 		}
 	}
@@ -519,3 +587,8 @@ func parseProjectName(id string) string {
 	}
 	return parts[1]
 }
+
+func parseClusterName(id string) string {
+	clusterName, _, _ := strings.Cut(id, ":")
+	return clusterName
+}
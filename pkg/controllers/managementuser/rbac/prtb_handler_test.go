@@ -7,15 +7,19 @@
 	"testing"
 
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
 	typesrbacv1fakes "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1/fakes"
+	"github.com/rancher/rancher/pkg/namespace"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	v1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
 )
 
 type prtbTestState struct {
@@ -87,6 +91,49 @@ func TestReconcileProjectAccessToGlobalResources(t *testing.T) {
 	}
 }
 
+func TestSyncPRTBNamespaceSelector(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	managerMock := NewMockmanagerInterface(ctrl)
+
+	nsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{namespace.NsByProjectIndex: namespace.NsByProjectID})
+	for _, ns := range []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-a", Labels: map[string]string{"team": "a"}, Annotations: map[string]string{projectIDAnnotation: "c-abcde:p-fghij"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-b", Labels: map[string]string{"team": "b"}, Annotations: map[string]string{projectIDAnnotation: "c-abcde:p-fghij"}}},
+	} {
+		require.NoError(t, nsIndexer.Add(ns))
+	}
+
+	roles := map[string]*v3.RoleTemplate{"rt-name": {}}
+	managerMock.EXPECT().gatherRoles(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ *v3.RoleTemplate, rts map[string]*v3.RoleTemplate, _ int) error {
+		rts["rt-name"] = roles["rt-name"]
+		return nil
+	})
+	managerMock.EXPECT().ensureRoles(gomock.Any()).Return(nil)
+	managerMock.EXPECT().ensureProjectRoleBindings("ns-a", roles, gomock.Any()).Return(nil)
+	managerMock.EXPECT().ensureProjectRoleBindings("ns-b", map[string]*v3.RoleTemplate{}, gomock.Any()).Return(nil)
+	managerMock.EXPECT().ensureGlobalResourcesRolesForPRTB(gomock.Any(), gomock.Any()).Return(nil, nil)
+	managerMock.EXPECT().reconcileProjectAccessToGlobalResources(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	p := prtbLifecycle{
+		m: managerMock,
+		rtLister: &fakes.RoleTemplateListerMock{
+			GetFunc: func(_, name string) (*v3.RoleTemplate, error) {
+				return &v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+			},
+		},
+		nsIndexer: nsIndexer,
+	}
+	err := p.syncPRTB(&v3.ProjectRoleTemplateBinding{
+		ObjectMeta:         metav1.ObjectMeta{Name: "prtb-a", Namespace: "p-fghij"},
+		ProjectName:        "c-abcde:p-fghij",
+		GroupPrincipalName: "openldap_group://cn=team-a",
+		RoleTemplateName:   "rt-name",
+		NamespaceSelector:  &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+	})
+	require.NoError(t, err)
+}
+
 func setupPRTBTest(t *testing.T) prtbTestState {
 	ctrl := gomock.NewController(t)
 	managerMock := NewMockmanagerInterface(ctrl)
@@ -0,0 +1,201 @@
+package rbac
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/rancher/norman/types/slice"
+	"github.com/rancher/rancher/pkg/apis/management.cattle.io"
+	wranglerv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// RoleTemplateGetter returns the role template of the name.
+type RoleTemplateGetter func(name string) (*wranglerv3.RoleTemplate, error)
+
+// RenderRoleTemplate renders the ClusterRoles, ClusterRoleBindings and RoleBindings created in a downstream cluster by
+// the handlers of this package for a binding of the role template to the subject. The binding is a
+// ClusterRoleTemplateBinding when projectName is empty, and a ProjectRoleTemplateBinding of the project, in the
+// <cluster>:<project> format, otherwise, whose namespaces are given.
+// The downstream cluster isn't read: the rules of external role templates are their external rules, and a warning is
+// returned for those without any.
+func RenderRoleTemplate(getRoleTemplate RoleTemplateGetter, rt *wranglerv3.RoleTemplate, projectName string, namespaces []string, subject rbacv1.Subject) (*wranglerv3.RoleTemplateRenderOutput, error) {
+	roles := map[string]*wranglerv3.RoleTemplate{}
+	if err := gatherRolesWith(getRoleTemplate, rt, roles, 0); err != nil {
+		return nil, err
+	}
+	ToLowerRoleTemplates(roles)
+
+	output := &wranglerv3.RoleTemplateRenderOutput{
+		ClusterRoles:        []wranglerv3.RenderedRole{},
+		ClusterRoleBindings: []wranglerv3.RenderedBinding{},
+		RoleBindings:        []wranglerv3.RenderedBinding{},
+	}
+	roleNames := sets.List(sets.KeySet(roles))
+	for _, roleName := range roleNames {
+		role := roles[roleName]
+		if role.External {
+			if len(role.ExternalRules) == 0 {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("the rules of external role template %s are those of the existing ClusterRole %s of the cluster", role.Name, role.Name))
+			}
+			output.ClusterRoles = append(output.ClusterRoles, wranglerv3.RenderedRole{Name: role.Name, External: true, Rules: role.ExternalRules})
+			continue
+		}
+		output.ClusterRoles = append(output.ClusterRoles, wranglerv3.RenderedRole{Name: role.Name, Rules: role.Rules})
+	}
+
+	if projectName == "" {
+		for _, roleName := range roleNames {
+			output.ClusterRoleBindings = append(output.ClusterRoleBindings, renderedBinding("", roleName, subject))
+		}
+		return output, nil
+	}
+
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		for _, roleName := range roleNames {
+			output.RoleBindings = append(output.RoleBindings, renderedBinding(namespace, roleName, subject))
+		}
+	}
+
+	promoted, err := renderGlobalResourcesRoles(getRoleTemplate, parseProjectName(projectName), namespaces, roles)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range promoted {
+		output.ClusterRoles = append(output.ClusterRoles, role)
+		output.ClusterRoleBindings = append(output.ClusterRoleBindings, renderedBinding("", role.Name, subject))
+	}
+	return output, nil
+}
+
+// gatherRolesWith gathers the role template and those it inherits, like gatherRoles, with the getter. Role templates
+// are gathered once, so that the inherited role templates don't replace the rendered one.
+func gatherRolesWith(getRoleTemplate RoleTemplateGetter, rt *wranglerv3.RoleTemplate, roleTemplates map[string]*wranglerv3.RoleTemplate, depthCounter int) error {
+	if depthCounter >= rolesCircularHardLimit {
+		return fmt.Errorf("roletemplate '%s' has caused %d recursive function calls, possible circular dependency", rt.Name, rolesCircularHardLimit)
+	}
+	rt, err := pkgrbac.FlattenRoleTemplate(rt, getRoleTemplate)
+	if err != nil {
+		return err
+	}
+	roleTemplates[rt.Name] = rt
+	for _, rtName := range rt.RoleTemplateNames {
+		if _, ok := roleTemplates[rtName]; ok {
+			continue
+		}
+		subRT, err := getRoleTemplate(rtName)
+		if err != nil {
+			return fmt.Errorf("couldn't get RoleTemplate %s: %w", rtName, err)
+		}
+		if err := gatherRolesWith(getRoleTemplate, subRT, roleTemplates, depthCounter+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderGlobalResourcesRoles renders the ClusterRoles bound for a ProjectRoleTemplateBinding to access the namespaces
+// of its project and the global resources, like ensureGlobalResourcesRolesForPRTB. The rules of the promoted roles are
+// those the role templates grant, though the promoted role of a role template accumulates the rules granted for all
+// the bindings of the cluster.
+func renderGlobalResourcesRoles(getRoleTemplate RoleTemplateGetter, projectName string, namespaces []string, rts map[string]*wranglerv3.RoleTemplate) ([]wranglerv3.RenderedRole, error) {
+	if projectName == "" {
+		return nil, nil
+	}
+
+	var roles []wranglerv3.RenderedRole
+	roleVerb := "get"
+	for _, rt := range rts {
+		for _, rule := range rt.Rules {
+			hasNamespaceResources := slice.ContainsString(rule.Resources, "namespaces") || slice.ContainsString(rule.Resources, "*")
+			hasNamespaceGroup := slice.ContainsString(rule.APIGroups, "") || slice.ContainsString(rule.APIGroups, "*")
+			if hasNamespaceGroup && hasNamespaceResources && len(rule.ResourceNames) == 0 &&
+				(slice.ContainsString(rule.Verbs, "*") || slice.ContainsString(rule.Verbs, "create")) {
+				roleVerb = projectNSEditVerb
+			}
+		}
+	}
+	if roleVerb == projectNSEditVerb {
+		createNS, err := getRoleTemplate("create-ns")
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get RoleTemplate create-ns: %w", err)
+		}
+		createNSRoles := map[string]*wranglerv3.RoleTemplate{createNS.Name: createNS}
+		ToLowerRoleTemplates(createNSRoles)
+		roles = append(roles, wranglerv3.RenderedRole{Name: createNS.Name, Rules: createNSRoles[createNS.Name].Rules})
+	}
+
+	nsRole := wranglerv3.RenderedRole{Name: fmt.Sprintf(projectNSGetClusterRoleNameFmt, projectName, projectNSVerbToSuffix[roleVerb])}
+	if len(namespaces) > 0 {
+		nsRole.Rules = append(nsRole.Rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Verbs:         []string{roleVerb},
+			Resources:     []string{"namespaces"},
+			ResourceNames: namespaces,
+		})
+	}
+	if roleVerb == projectNSEditVerb {
+		nsRole.Rules = append(nsRole.Rules, rbacv1.PolicyRule{
+			APIGroups:     []string{management.GroupName},
+			Verbs:         []string{manageNSVerb},
+			Resources:     []string{wranglerv3.ProjectResourceName},
+			ResourceNames: []string{projectName},
+		})
+	}
+	roles = append(roles, nsRole)
+
+	resources := sets.List(sets.KeySet(globalResourceRulesNeededInProjects))
+	for _, rtName := range sets.List(sets.KeySet(rts)) {
+		rt := rts[rtName]
+		rules := rt.Rules
+		if rt.External {
+			rules = rt.ExternalRules
+		}
+		promoted := wranglerv3.RenderedRole{Name: rt.Name + "-promoted"}
+		for _, resource := range resources {
+			baseRule := globalResourceRulesNeededInProjects[resource]
+			if verbs := globalResourceVerbs(rules, resource, baseRule); len(verbs) > 0 {
+				promoted.Rules = append(promoted.Rules, buildRule(resource, verbs, baseRule))
+			}
+		}
+		if len(promoted.Rules) > 0 {
+			roles = append(roles, promoted)
+		}
+	}
+	return roles, nil
+}
+
+// globalResourceVerbs returns the verbs the rules grant on the global resource, like checkForGlobalResourceRules.
+func globalResourceVerbs(rules []rbacv1.PolicyRule, resource string, baseRule rbacv1.PolicyRule) sets.Set[string] {
+	verbs := sets.New[string]()
+	for _, rule := range rules {
+		if (slice.ContainsString(rule.Resources, resource) || slice.ContainsString(rule.Resources, "*")) &&
+			reflect.DeepEqual(rule.ResourceNames, baseRule.ResourceNames) && checkGroup(resource, rule) {
+			verbs.Insert(rule.Verbs...)
+		}
+	}
+	return verbs
+}
+
+// renderedBinding renders the binding of the ClusterRole to the subject, named like bindingParts names it.
+func renderedBinding(namespace, roleName string, subject rbacv1.Subject) wranglerv3.RenderedBinding {
+	roleRef := rbacv1.RoleRef{
+		Kind: "ClusterRole",
+		Name: roleName,
+	}
+	name := pkgrbac.NameForClusterRoleBinding(roleRef, subject)
+	if namespace != "" {
+		name = pkgrbac.NameForRoleBinding(namespace, roleRef, subject)
+	}
+	return wranglerv3.RenderedBinding{
+		Name:        name,
+		Namespace:   namespace,
+		RoleName:    roleName,
+		SubjectKind: subject.Kind,
+		SubjectName: subject.Name,
+	}
+}
@@ -0,0 +1,153 @@
+package rbac
+
+import (
+	"fmt"
+	"testing"
+
+	wranglerv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func roleTemplateGetter(rts ...*wranglerv3.RoleTemplate) RoleTemplateGetter {
+	return func(name string) (*wranglerv3.RoleTemplate, error) {
+		for _, rt := range rts {
+			if rt.Name == name {
+				return rt, nil
+			}
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "roletemplates"}, name)
+	}
+}
+
+func TestRenderRoleTemplateInCluster(t *testing.T) {
+	subject := rbacv1.Subject{Kind: "User", Name: "u-abc", APIGroup: rbacv1.GroupName}
+	external := &wranglerv3.RoleTemplate{
+		ObjectMeta:    metav1.ObjectMeta{Name: "external"},
+		External:      true,
+		ExternalRules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}},
+	}
+	externalWithoutRules := &wranglerv3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: "external-without-rules"}, External: true}
+	rt := &wranglerv3.RoleTemplate{
+		ObjectMeta:        metav1.ObjectMeta{Name: "cluster-viewer"},
+		Context:           "cluster",
+		Rules:             []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"Nodes"}, Verbs: []string{"GET", "list"}}},
+		RoleTemplateNames: []string{"external", "external-without-rules", "cluster-viewer"},
+	}
+
+	output, err := RenderRoleTemplate(roleTemplateGetter(external, externalWithoutRules), rt, "", nil, subject)
+	require.NoError(t, err)
+
+	assert.Equal(t, []wranglerv3.RenderedRole{
+		{Name: "cluster-viewer", Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list"}}}},
+		{Name: "external", External: true, Rules: external.ExternalRules},
+		{Name: "external-without-rules", External: true},
+	}, output.ClusterRoles)
+	require.Len(t, output.ClusterRoleBindings, 3)
+	assert.Equal(t, wranglerv3.RenderedBinding{
+		Name:        pkgrbac.NameForClusterRoleBinding(rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-viewer"}, subject),
+		RoleName:    "cluster-viewer",
+		SubjectKind: "User",
+		SubjectName: "u-abc",
+	}, output.ClusterRoleBindings[0])
+	assert.Empty(t, output.RoleBindings)
+	assert.Len(t, output.Warnings, 1)
+	assert.Contains(t, output.Warnings[0], "external-without-rules")
+}
+
+func TestRenderRoleTemplateInProject(t *testing.T) {
+	subject := rbacv1.Subject{Kind: "Group", Name: "openldap_group://cn=devs", APIGroup: rbacv1.GroupName}
+	createNS := &wranglerv3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "create-ns"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"create"}}},
+	}
+	rt := &wranglerv3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-owner"},
+		Context:    "project",
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"create"}},
+			{APIGroups: []string{""}, Resources: []string{"persistentvolumes"}, Verbs: []string{"get", "list"}},
+		},
+	}
+
+	output, err := RenderRoleTemplate(roleTemplateGetter(createNS), rt, "c-abc:p-xyz", []string{"ns-b", "ns-a"}, subject)
+	require.NoError(t, err)
+
+	var roleNames []string
+	for _, role := range output.ClusterRoles {
+		roleNames = append(roleNames, role.Name)
+	}
+	assert.Equal(t, []string{"project-owner", "create-ns", "p-xyz-namespaces-edit", "project-owner-promoted"}, roleNames)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Verbs: []string{"*"}, Resources: []string{"namespaces"}, ResourceNames: []string{"ns-a", "ns-b"}},
+		{APIGroups: []string{"management.cattle.io"}, Verbs: []string{"manage-namespaces"}, Resources: []string{"projects"}, ResourceNames: []string{"p-xyz"}},
+	}, output.ClusterRoles[2].Rules)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{"", "core"}, Verbs: []string{"get", "list"}, Resources: []string{"persistentvolumes"}},
+	}, output.ClusterRoles[3].Rules)
+
+	roleRef := rbacv1.RoleRef{Kind: "ClusterRole", Name: "project-owner"}
+	assert.Equal(t, []wranglerv3.RenderedBinding{
+		{Name: pkgrbac.NameForRoleBinding("ns-a", roleRef, subject), Namespace: "ns-a", RoleName: "project-owner", SubjectKind: "Group", SubjectName: subject.Name},
+		{Name: pkgrbac.NameForRoleBinding("ns-b", roleRef, subject), Namespace: "ns-b", RoleName: "project-owner", SubjectKind: "Group", SubjectName: subject.Name},
+	}, output.RoleBindings)
+	require.Len(t, output.ClusterRoleBindings, 3)
+	for i, binding := range output.ClusterRoleBindings {
+		assert.Equal(t, output.ClusterRoles[i+1].Name, binding.RoleName)
+		assert.Empty(t, binding.Namespace)
+	}
+	assert.Empty(t, output.Warnings)
+}
+
+func TestRenderRoleTemplateReadOnlyProject(t *testing.T) {
+	rt := &wranglerv3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "read-only"},
+		Context:    "project",
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}},
+	}
+
+	output, err := RenderRoleTemplate(roleTemplateGetter(), rt, "c-abc:p-xyz", nil, rbacv1.Subject{Kind: "User", Name: "u-abc"})
+	require.NoError(t, err)
+
+	require.Len(t, output.ClusterRoles, 2)
+	assert.Equal(t, wranglerv3.RenderedRole{Name: "p-xyz-namespaces-readonly"}, output.ClusterRoles[1])
+	assert.Empty(t, output.RoleBindings)
+}
+
+func TestRenderRoleTemplateWithExclusions(t *testing.T) {
+	subject := rbacv1.Subject{Kind: "User", Name: "u-abc", APIGroup: rbacv1.GroupName}
+	edit := &wranglerv3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "edit"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "delete"}}},
+	}
+	rt := &wranglerv3.RoleTemplate{
+		ObjectMeta:        metav1.ObjectMeta{Name: "edit-without-delete"},
+		Context:           "cluster",
+		RoleTemplateNames: []string{"edit"},
+		ExcludedRules:     []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"delete"}}},
+	}
+
+	output, err := RenderRoleTemplate(roleTemplateGetter(edit), rt, "", nil, subject)
+	require.NoError(t, err)
+
+	assert.Equal(t, []wranglerv3.RenderedRole{
+		{Name: "edit-without-delete", Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}},
+	}, output.ClusterRoles)
+	require.Len(t, output.ClusterRoleBindings, 1)
+	assert.Equal(t, "edit-without-delete", output.ClusterRoleBindings[0].RoleName)
+}
+
+func TestRenderRoleTemplateMissingInherited(t *testing.T) {
+	rt := &wranglerv3.RoleTemplate{
+		ObjectMeta:        metav1.ObjectMeta{Name: "rt"},
+		RoleTemplateNames: []string{"missing"},
+	}
+
+	_, err := RenderRoleTemplate(roleTemplateGetter(), rt, "", nil, rbacv1.Subject{Kind: "User", Name: "u-abc"})
+	assert.EqualError(t, err, fmt.Sprintf("couldn't get RoleTemplate missing: %v", apierrors.NewNotFound(schema.GroupResource{Resource: "roletemplates"}, "missing")))
+}
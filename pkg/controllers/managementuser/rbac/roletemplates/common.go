@@ -31,6 +31,7 @@
 	failureToDeleteClusterRoleBinding = "FailureToDeleteClusterRoleBinding"
 	failureToCreateClusterRoleBinding = "FailureToCreateClusterRoleBinding"
 	failureToGetRoleTemplate          = "FailureToGetRoleTemplate"
+	namespaceSelectorNotSupported     = "NamespaceSelectorNotSupported"
 )
 
 type impersonationHandler struct {
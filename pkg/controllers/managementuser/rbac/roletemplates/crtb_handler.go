@@ -16,6 +16,8 @@
 	"k8s.io/client-go/util/retry"
 )
 
+var errNamespaceSelectorNotSupported = errors.New("namespace selectors aren't supported with aggregated role templates, the binding grants no permission")
+
 type crtbHandler struct {
 	impersonationHandler *impersonationHandler
 	crbClient            wrbacv1.ClusterRoleBindingController
@@ -73,6 +75,24 @@ func (c *crtbHandler) OnChange(key string, crtb *v3.ClusterRoleTemplateBinding)
 func (c *crtbHandler) reconcileBindings(crtb *v3.ClusterRoleTemplateBinding, remoteConditions *[]metav1.Condition) error {
 	condition := metav1.Condition{Type: reconcileClusterRoleBindings}
 
+	// Rather than granting the permissions of the role template in the whole cluster, a CRTB scoped to namespaces has
+	// no ClusterRoleBinding, as namespace selectors are only supported without aggregated role templates.
+	if crtb.NamespaceSelector != nil {
+		currentCRBs, err := c.crbClient.List(metav1.ListOptions{LabelSelector: rbac.GetCRTBOwnerLabel(crtb.Name)})
+		if err != nil {
+			c.s.AddCondition(remoteConditions, condition, failureToListClusterRoleBindings, err)
+			return err
+		}
+		for _, currentCRB := range currentCRBs.Items {
+			if err := c.crbClient.Delete(currentCRB.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				c.s.AddCondition(remoteConditions, condition, failureToDeleteClusterRoleBinding, err)
+				return err
+			}
+		}
+		c.s.AddCondition(remoteConditions, condition, namespaceSelectorNotSupported, errNamespaceSelectorNotSupported)
+		return nil
+	}
+
 	isExternal, err := isRoleTemplateExternal(crtb.RoleTemplateName, c.rtClient)
 	if err != nil {
 		c.s.AddCondition(remoteConditions, condition, failureToGetRoleTemplate, err)
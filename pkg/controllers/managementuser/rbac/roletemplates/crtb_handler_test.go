@@ -95,6 +95,25 @@ func Test_reconcileBindings(t *testing.T) {
 				status: metav1.ConditionFalse,
 			},
 		},
+		{
+			name: "namespace selector deletes CRBs",
+			setupCRBController: func(c *fake.MockNonNamespacedControllerInterface[*rbacv1.ClusterRoleBinding, *rbacv1.ClusterRoleBindingList]) {
+				c.EXPECT().List(defaultListOption).Return(&rbacv1.ClusterRoleBindingList{
+					Items: []rbacv1.ClusterRoleBinding{defaultCRB},
+				}, nil)
+				c.EXPECT().Delete(defaultCRB.Name, &metav1.DeleteOptions{}).Return(nil)
+			},
+			crtb: &v3.ClusterRoleTemplateBinding{
+				ObjectMeta:        defaultCRTB.ObjectMeta,
+				UserName:          defaultCRTB.UserName,
+				RoleTemplateName:  defaultCRTB.RoleTemplateName,
+				NamespaceSelector: &metav1.LabelSelector{},
+			},
+			wantedCondition: &reducedCondition{
+				reason: "NamespaceSelectorNotSupported",
+				status: metav1.ConditionFalse,
+			},
+		},
 		{
 			name: "error on list CRB",
 			setupCRBController: func(c *fake.MockNonNamespacedControllerInterface[*rbacv1.ClusterRoleBinding, *rbacv1.ClusterRoleBindingList]) {
@@ -122,6 +122,17 @@ func (p *prtbHandler) reconcileBindings(prtb *v3.ProjectRoleTemplateBinding) err
 			continue
 		}
 
+		selected, err := rbac.NamespaceSelected(prtb.NamespaceSelector, namespace.Labels)
+		if err != nil {
+			return err
+		}
+		if !selected {
+			if err := p.deleteRoleBindings(namespace.Name, rbac.GetPRTBOwnerLabel(prtb.Name)); err != nil {
+				return err
+			}
+			continue
+		}
+
 		rb := &rbacv1.RoleBinding{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      rbac.NameForRoleBinding(namespace.Name, roleRef, subject),
@@ -349,6 +360,20 @@ func (p *prtbHandler) ensureOnlyDesiredRoleBindingExists(desiredRB *rbacv1.RoleB
 	return nil
 }
 
+// deleteRoleBindings removes the RoleBindings owned by the PRTB in a namespace it doesn't select.
+func (p *prtbHandler) deleteRoleBindings(namespace, prtbOwnerLabel string) error {
+	currentRBs, err := p.rbClient.List(namespace, metav1.ListOptions{LabelSelector: prtbOwnerLabel})
+	if err != nil {
+		return err
+	}
+	for _, currentRB := range currentRBs.Items {
+		if err := p.rbClient.Delete(namespace, currentRB.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 // areRoleBindingsSame compares the Subjects and RoleRef fields of two Role Bindings.
 func areRoleBindingsSame(rb1, rb2 *rbacv1.RoleBinding) bool {
 	return reflect.DeepEqual(rb1.Subjects, rb2.Subjects) &&
@@ -333,6 +333,50 @@ type controllers struct {
 				c.rbController.EXPECT().Create(rb2).Return(nil, nil)
 			},
 		},
+		{
+			name: "delete role binding in namespace not matching the namespace selector",
+			prtb: func() *v3.ProjectRoleTemplateBinding {
+				prtb := defaultPRTB.DeepCopy()
+				prtb.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+				return prtb
+			}(),
+			setupControllers: func(c controllers) {
+				c.rtController.EXPECT().Get(defaultPRTB.RoleTemplateName, metav1.GetOptions{}).Return(nil, errNotFound)
+				c.nsController.EXPECT().List(namespaceListOptions).Return(&corev1.NamespaceList{
+					Items: []corev1.Namespace{
+						{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: map[string]string{"team": "a"}}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}},
+					},
+				}, nil)
+				c.rbController.EXPECT().List("ns1", rbListOptions).Return(&rbacv1.RoleBindingList{}, nil)
+				rb1 := defaultRoleBinding.DeepCopy()
+				rb1.Namespace = "ns1"
+				c.rbController.EXPECT().Create(rb1).Return(nil, nil)
+				c.rbController.EXPECT().List("ns2", rbListOptions).Return(&rbacv1.RoleBindingList{
+					Items: []rbacv1.RoleBinding{{ObjectMeta: metav1.ObjectMeta{Name: "rb-x3nurktcw6", Namespace: "ns2"}}},
+				}, nil)
+				c.rbController.EXPECT().Delete("ns2", "rb-x3nurktcw6", &metav1.DeleteOptions{}).Return(nil)
+			},
+		},
+		{
+			name: "error with invalid namespace selector",
+			prtb: func() *v3.ProjectRoleTemplateBinding {
+				prtb := defaultPRTB.DeepCopy()
+				prtb.NamespaceSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Bogus"}},
+				}
+				return prtb
+			}(),
+			setupControllers: func(c controllers) {
+				c.rtController.EXPECT().Get(defaultPRTB.RoleTemplateName, metav1.GetOptions{}).Return(nil, errNotFound)
+				c.nsController.EXPECT().List(namespaceListOptions).Return(&corev1.NamespaceList{
+					Items: []corev1.Namespace{
+						{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}},
+					},
+				}, nil)
+			},
+			wantErr: true,
+		},
 	}
 	ctrl := gomock.NewController(t)
 	for _, tt := range tests {
@@ -10,6 +10,7 @@
 	"github.com/rancher/rancher/pkg/wrangler"
 	"github.com/rancher/wrangler/v3/pkg/generic"
 	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -22,13 +23,16 @@
 	prtbRemoveHandler   = "cluster-prtb-remove-handler"
 	prtbByUsernameIndex = "auth.management.cattle.io/prtb-by-username"
 
-	roleTemplateChangeHandler = "cluster-roletemplate-change-handler"
-	roleTemplateRemoveHandler = "cluster-roletemplate-remove-handler"
+	roleTemplateChangeHandler    = "cluster-roletemplate-change-handler"
+	roleTemplateRemoveHandler    = "cluster-roletemplate-remove-handler"
+	roleTemplateEnqueuer         = "cluster-roletemplate-excluding-enqueuer"
+	roleTemplateByInheritedIndex = "auth.management.cattle.io/roletemplate-by-inherited"
 )
 
 func RegisterIndexers(wranglerContext *wrangler.Context) {
 	wranglerContext.Mgmt.ClusterRoleTemplateBinding().Cache().AddIndexer(crtbByUsernameIndex, getCRTBByUsername)
 	wranglerContext.Mgmt.ProjectRoleTemplateBinding().Cache().AddIndexer(prtbByUsernameIndex, getPRTBByUsername)
+	wranglerContext.Mgmt.RoleTemplate().Cache().AddIndexer(roleTemplateByInheritedIndex, getRoleTemplatesByInherited)
 }
 
 func Register(ctx context.Context, workload *config.UserContext) {
@@ -45,6 +49,8 @@ func Register(ctx context.Context, workload *config.UserContext) {
 	rth := newRoleTemplateHandler(workload)
 	management.Wrangler.Mgmt.RoleTemplate().OnChange(ctx, roleTemplateChangeHandler, rth.OnChange)
 	management.Wrangler.Mgmt.RoleTemplate().OnRemove(ctx, roleTemplateRemoveHandler, rth.OnRemove)
+	relatedresource.WatchClusterScoped(ctx, roleTemplateEnqueuer, rth.enqueueExcludingDescendants,
+		management.Wrangler.Mgmt.RoleTemplate(), management.Wrangler.Mgmt.RoleTemplate())
 }
 
 // TODO(wrangler/v4): revert to use OnRemove when it supports options (https://github.com/rancher/wrangler/pull/472).
@@ -99,3 +105,10 @@ func getPRTBByUsername(prtb *v3.ProjectRoleTemplateBinding) ([]string, error) {
 	}
 	return []string{}, nil
 }
+
+func getRoleTemplatesByInherited(rt *v3.RoleTemplate) ([]string, error) {
+	if rt == nil {
+		return []string{}, nil
+	}
+	return rt.RoleTemplateNames, nil
+}
@@ -4,13 +4,16 @@
 	"errors"
 
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/rbac"
 	"github.com/rancher/rancher/pkg/types/config"
 	crbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	"github.com/rancher/wrangler/v3/pkg/slice"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
@@ -22,11 +25,13 @@
 func newRoleTemplateHandler(uc *config.UserContext) *roleTemplateHandler {
 	return &roleTemplateHandler{
 		crController: uc.RBACw.ClusterRole(),
+		rtCache:      uc.Management.Wrangler.Mgmt.RoleTemplate().Cache(),
 	}
 }
 
 type roleTemplateHandler struct {
 	crController crbacv1.ClusterRoleController
+	rtCache      mgmtv3.RoleTemplateCache
 }
 
 // OnChange ensures that the following Cluster Roles exist:
@@ -36,12 +41,20 @@ type roleTemplateHandler struct {
 // For RoleTemplates with the Context == "Project", the additional cluster roles are created:
 //  1. If the RoleTemplate has any rules for Global Resources, make a ClusterRole with those named "RoleTemplateName-promoted"
 //  2. An Aggregating ClusterRole that aggregates all inherited RoleTemplates' promoted Cluster Roles named "RoleTemplateName-promoted-aggregator"
+//
+// A RoleTemplate excluding rules aggregates no inherited RoleTemplate, its ClusterRole holds the inherited rules minus the
+// excluded ones.
 func (rth *roleTemplateHandler) OnChange(_ string, rt *v3.RoleTemplate) (*v3.RoleTemplate, error) {
 	if rt == nil || rt.DeletionTimestamp != nil {
 		return nil, nil
 	}
 
-	clusterRoles := clusterRolesForRoleTemplate(rt)
+	flattened, err := rbac.FlattenRoleTemplate(rt, rth.rtCache.Get)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterRoles := clusterRolesForRoleTemplate(flattened)
 	for _, cr := range clusterRoles {
 		if err := rbac.CreateOrUpdateResource(cr, rth.crController, rbac.AreClusterRolesSame); err != nil {
 			return nil, err
@@ -56,6 +69,33 @@ func (rth *roleTemplateHandler) OnChange(_ string, rt *v3.RoleTemplate) (*v3.Rol
 	return rt, nil
 }
 
+// enqueueExcludingDescendants returns the RoleTemplates excluding rules that inherit, directly or not, the changed
+// RoleTemplate. Their ClusterRoles hold a copy of its rules instead of aggregating its ClusterRole, so they are updated
+// with it.
+func (rth *roleTemplateHandler) enqueueExcludingDescendants(_, name string, _ runtime.Object) ([]relatedresource.Key, error) {
+	var keys []relatedresource.Key
+	seen := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		inheriting, err := rth.rtCache.GetByIndex(roleTemplateByInheritedIndex, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+		for _, rt := range inheriting {
+			if seen[rt.Name] {
+				continue
+			}
+			seen[rt.Name] = true
+			queue = append(queue, rt.Name)
+			if len(rt.ExcludedRules) > 0 && !rt.External {
+				keys = append(keys, relatedresource.Key{Name: rt.Name})
+			}
+		}
+	}
+	return keys, nil
+}
+
 // clusterRolesForRoleTemplate builds and returns all needed Cluster Roles for the RoleTemplate using the given rules.
 func clusterRolesForRoleTemplate(rt *v3.RoleTemplate) []*rbacv1.ClusterRole {
 	res := []*rbacv1.ClusterRole{}
@@ -193,6 +193,37 @@ func Test_clusterRolesForRoleTemplate(t *testing.T) {
 	}
 }
 
+func Test_enqueueExcludingDescendants(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	rtCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	excluded := []rbacv1.PolicyRule{sampleRule}
+	inheriting := map[string][]*v3.RoleTemplate{
+		"base": {
+			{ObjectMeta: metav1.ObjectMeta{Name: "excluding"}, RoleTemplateNames: []string{"base"}, ExcludedRules: excluded},
+			{ObjectMeta: metav1.ObjectMeta{Name: "aggregating"}, RoleTemplateNames: []string{"base"}},
+		},
+		"aggregating": {
+			{ObjectMeta: metav1.ObjectMeta{Name: "excluding-aggregating"}, RoleTemplateNames: []string{"aggregating"}, ExcludedRules: excluded},
+		},
+		"excluding": {
+			{ObjectMeta: metav1.ObjectMeta{Name: "base"}, RoleTemplateNames: []string{"excluding"}},
+		},
+	}
+	rtCache.EXPECT().GetByIndex(roleTemplateByInheritedIndex, gomock.Any()).DoAndReturn(func(_, name string) ([]*v3.RoleTemplate, error) {
+		return inheriting[name], nil
+	}).AnyTimes()
+
+	rth := &roleTemplateHandler{rtCache: rtCache}
+	keys, err := rth.enqueueExcludingDescendants("", "base", nil)
+	assert.NoError(t, err)
+	var names []string
+	for _, key := range keys {
+		names = append(names, key.Name)
+	}
+	assert.ElementsMatch(t, []string{"excluding", "excluding-aggregating"}, names)
+}
+
 func Test_extractPromotedRules(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,85 @@
+package rbac
+
+import (
+	"strings"
+
+	wranglerv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// RoleTemplateRules returns the rules granted in a downstream cluster by a binding of the role template, including
+// those of its inherited role templates. The rules of external role templates are their external rules.
+func RoleTemplateRules(getRoleTemplate RoleTemplateGetter, rt *wranglerv3.RoleTemplate) ([]rbacv1.PolicyRule, error) {
+	roles := map[string]*wranglerv3.RoleTemplate{}
+	if err := gatherRolesWith(getRoleTemplate, rt, roles, 0); err != nil {
+		return nil, err
+	}
+	ToLowerRoleTemplates(roles)
+
+	var rules []rbacv1.PolicyRule
+	for _, role := range roles {
+		if role.External {
+			rules = append(rules, role.ExternalRules...)
+			continue
+		}
+		rules = append(rules, role.Rules...)
+	}
+	return rules, nil
+}
+
+// RuleDelta returns the rules granted by after but not by before, and those granted by before but not by after. The
+// rules are compared one API group, resource, resource name, or non-resource URL, and verb at a time, and returned that
+// way, sorted. Wildcards are compared as is: replacing the verbs of a rule by * adds the * verb and removes the others.
+func RuleDelta(before, after []rbacv1.PolicyRule) (added, removed []rbacv1.PolicyRule) {
+	beforeRules := atomicRules(before)
+	afterRules := atomicRules(after)
+	for _, key := range sets.List(sets.KeySet(afterRules)) {
+		if _, ok := beforeRules[key]; !ok {
+			added = append(added, afterRules[key])
+		}
+	}
+	for _, key := range sets.List(sets.KeySet(beforeRules)) {
+		if _, ok := afterRules[key]; !ok {
+			removed = append(removed, beforeRules[key])
+		}
+	}
+	return added, removed
+}
+
+// atomicRules splits the rules into rules of a single API group, resource, resource name, or non-resource URL, and
+// verb, by a key identifying them.
+func atomicRules(rules []rbacv1.PolicyRule) map[string]rbacv1.PolicyRule {
+	atomic := map[string]rbacv1.PolicyRule{}
+	for _, rule := range rules {
+		for _, verb := range rule.Verbs {
+			for _, url := range rule.NonResourceURLs {
+				atomic[strings.Join([]string{"url", url, verb}, "|")] = rbacv1.PolicyRule{
+					Verbs:           []string{verb},
+					NonResourceURLs: []string{url},
+				}
+			}
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					if len(rule.ResourceNames) == 0 {
+						atomic[strings.Join([]string{"resource", group, resource, "", verb}, "|")] = rbacv1.PolicyRule{
+							Verbs:     []string{verb},
+							APIGroups: []string{group},
+							Resources: []string{resource},
+						}
+						continue
+					}
+					for _, name := range rule.ResourceNames {
+						atomic[strings.Join([]string{"resource", group, resource, name, verb}, "|")] = rbacv1.PolicyRule{
+							Verbs:         []string{verb},
+							APIGroups:     []string{group},
+							Resources:     []string{resource},
+							ResourceNames: []string{name},
+						}
+					}
+				}
+			}
+		}
+	}
+	return atomic
+}
@@ -0,0 +1,64 @@
+package rbac
+
+import (
+	"testing"
+
+	wranglerv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRoleTemplateRules(t *testing.T) {
+	external := &wranglerv3.RoleTemplate{
+		ObjectMeta:    metav1.ObjectMeta{Name: "external"},
+		External:      true,
+		ExternalRules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}},
+	}
+	rt := &wranglerv3.RoleTemplate{
+		ObjectMeta:        metav1.ObjectMeta{Name: "cluster-viewer"},
+		Rules:             []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"Nodes"}, Verbs: []string{"GET"}}},
+		RoleTemplateNames: []string{"external"},
+	}
+
+	rules, err := RoleTemplateRules(roleTemplateGetter(external), rt)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}},
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+	}, rules)
+
+	_, err = RoleTemplateRules(roleTemplateGetter(), rt)
+	assert.Error(t, err, "the inherited role template doesn't exist")
+}
+
+func TestRuleDelta(t *testing.T) {
+	before := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods", "services"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"a", "b"}, Verbs: []string{"get"}},
+		{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+	}
+	after := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "delete"}},
+		{APIGroups: []string{""}, Resources: []string{"services"}, Verbs: []string{"list", "get"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"a"}, Verbs: []string{"get"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}},
+	}
+
+	added, removed := RuleDelta(before, after)
+
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"delete"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}},
+	}, added)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"b"}, Verbs: []string{"get"}},
+		{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+	}, removed)
+
+	added, removed = RuleDelta(before, before)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
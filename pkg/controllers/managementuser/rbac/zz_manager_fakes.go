@@ -83,6 +83,20 @@ func (mr *MockmanagerInterfaceMockRecorder) ensureGlobalResourcesRolesForPRTB(ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ensureGlobalResourcesRolesForPRTB", reflect.TypeOf((*MockmanagerInterface)(nil).ensureGlobalResourcesRolesForPRTB), arg0, arg1)
 }
 
+// ensureNamespaceSelectorBindings mocks base method.
+func (m *MockmanagerInterface) ensureNamespaceSelectorBindings(arg0 map[string]*v3.RoleTemplate, arg1 *v3.ClusterRoleTemplateBinding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ensureNamespaceSelectorBindings", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ensureNamespaceSelectorBindings indicates an expected call of ensureNamespaceSelectorBindings.
+func (mr *MockmanagerInterfaceMockRecorder) ensureNamespaceSelectorBindings(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ensureNamespaceSelectorBindings", reflect.TypeOf((*MockmanagerInterface)(nil).ensureNamespaceSelectorBindings), arg0, arg1)
+}
+
 // ensureProjectRoleBindings mocks base method.
 func (m *MockmanagerInterface) ensureProjectRoleBindings(arg0 string, arg1 map[string]*v3.RoleTemplate, arg2 *v3.ProjectRoleTemplateBinding) error {
 	m.ctrl.T.Helper()
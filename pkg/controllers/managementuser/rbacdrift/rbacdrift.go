@@ -0,0 +1,417 @@
+// Package rbacdrift checks the ClusterRoles, ClusterRoleBindings and RoleBindings Rancher manages in a downstream
+// cluster for the ClusterRoleTemplateBindings and ProjectRoleTemplateBindings against those the bindings render to, and
+// records the objects edited or deleted in the cluster in the RBACDriftReport of the cluster.
+package rbacdrift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/rbac"
+	"github.com/rancher/rancher/pkg/features"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/project"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	wcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	wrbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	reportControllerName = "rbac-drift-report"
+	checkControllerName  = "rbac-drift-check"
+
+	// DriftMissing is the type of the drift of the objects deleted from the cluster, and DriftModified of those edited.
+	DriftMissing  = "Missing"
+	DriftModified = "Modified"
+
+	clusterRoleKind        = "ClusterRole"
+	clusterRoleBindingKind = "ClusterRoleBinding"
+	roleBindingKind        = "RoleBinding"
+
+	defaultCheckInterval = time.Hour
+)
+
+// Register registers the controllers creating the RBACDriftReport of the cluster and checking it periodically.
+// The objects created for the bindings when the AggregatedRoleTemplates feature is enabled aren't checked.
+func Register(ctx context.Context, workload *config.UserContext) {
+	if features.AggregatedRoleTemplates.Enabled() {
+		return
+	}
+	management := workload.Management.WithAgent("rbac-drift")
+	c := newController(workload)
+	management.Wrangler.Mgmt.Cluster().OnChange(ctx, reportControllerName, c.ensureReport)
+	management.Wrangler.Mgmt.RBACDriftReport().OnChange(ctx, checkControllerName, c.sync)
+}
+
+type controller struct {
+	clusterName      string
+	reports          mgmtcontrollers.RBACDriftReportController
+	reportCache      mgmtcontrollers.RBACDriftReportCache
+	crtbs            mgmtcontrollers.ClusterRoleTemplateBindingController
+	crtbCache        mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbs            mgmtcontrollers.ProjectRoleTemplateBindingController
+	prtbCache        mgmtcontrollers.ProjectRoleTemplateBindingCache
+	rtCache          mgmtcontrollers.RoleTemplateCache
+	namespaceCache   wcorev1.NamespaceCache
+	clusterRoleCache wrbacv1.ClusterRoleCache
+	crbs             wrbacv1.ClusterRoleBindingClient
+	crbCache         wrbacv1.ClusterRoleBindingCache
+	rbs              wrbacv1.RoleBindingClient
+	rbCache          wrbacv1.RoleBindingCache
+	now              func() time.Time
+}
+
+func newController(workload *config.UserContext) *controller {
+	mgmt := workload.Management.Wrangler.Mgmt
+	return &controller{
+		clusterName:      workload.ClusterName,
+		reports:          mgmt.RBACDriftReport(),
+		reportCache:      mgmt.RBACDriftReport().Cache(),
+		crtbs:            mgmt.ClusterRoleTemplateBinding(),
+		crtbCache:        mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbs:            mgmt.ProjectRoleTemplateBinding(),
+		prtbCache:        mgmt.ProjectRoleTemplateBinding().Cache(),
+		rtCache:          mgmt.RoleTemplate().Cache(),
+		namespaceCache:   workload.Corew.Namespace().Cache(),
+		clusterRoleCache: workload.RBACw.ClusterRole().Cache(),
+		crbs:             workload.RBACw.ClusterRoleBinding(),
+		crbCache:         workload.RBACw.ClusterRoleBinding().Cache(),
+		rbs:              workload.RBACw.RoleBinding(),
+		rbCache:          workload.RBACw.RoleBinding().Cache(),
+		now:              time.Now,
+	}
+}
+
+// ensureReport creates the report of the cluster, owned by the cluster so that it's removed with it.
+func (c *controller) ensureReport(_ string, cluster *v3.Cluster) (*v3.Cluster, error) {
+	if cluster == nil || cluster.DeletionTimestamp != nil || cluster.Name != c.clusterName {
+		return cluster, nil
+	}
+	if _, err := c.reportCache.Get(cluster.Name); !apierrors.IsNotFound(err) {
+		return cluster, err
+	}
+	_, err := c.reports.Create(&v3.RBACDriftReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: cluster.Name,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v3.SchemeGroupVersion.String(),
+				Kind:       "Cluster",
+				Name:       cluster.Name,
+				UID:        cluster.UID,
+			}},
+		},
+		Spec: v3.RBACDriftReportSpec{ClusterName: cluster.Name},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return cluster, fmt.Errorf("creating the RBACDriftReport of cluster %s: %w", cluster.Name, err)
+	}
+	return cluster, nil
+}
+
+// sync checks the cluster once the check interval has passed since the last check, or right away when the report was
+// edited since, and repairs the drift found if the report enables it.
+func (c *controller) sync(_ string, report *v3.RBACDriftReport) (*v3.RBACDriftReport, error) {
+	if report == nil || report.DeletionTimestamp != nil || report.Name != c.clusterName {
+		return report, nil
+	}
+	interval := time.Duration(settings.RBACDriftCheckIntervalMinutes.GetInt()) * time.Minute
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	if report.Status.LastCheckTime != nil && report.Status.ObservedGeneration == report.Generation {
+		if remaining := report.Status.LastCheckTime.Add(interval).Sub(c.now()); remaining > 0 {
+			c.reports.EnqueueAfter(report.Name, remaining)
+			return report, nil
+		}
+	}
+
+	report = report.DeepCopy()
+	report.Status.Error = ""
+	drifted, err := c.check()
+	if err != nil {
+		logrus.Errorf("[rbac-drift] failed to check cluster %s: %v", c.clusterName, err)
+		report.Status.Error = err.Error()
+	} else {
+		if report.Spec.AutoRepair {
+			c.repair(drifted)
+		}
+		report.Status.Drifts = make([]v3.RBACDrift, 0, len(drifted))
+		for _, object := range drifted {
+			report.Status.Drifts = append(report.Status.Drifts, object.drift)
+		}
+		report.Status.DriftCount = len(drifted)
+		if len(drifted) > 0 {
+			logrus.Infof("[rbac-drift] found %d drifted RBAC objects in cluster %s", len(drifted), c.clusterName)
+		}
+	}
+	now := metav1.NewTime(c.now())
+	report.Status.LastCheckTime = &now
+	report.Status.ObservedGeneration = report.Generation
+	return c.reports.UpdateStatus(report)
+}
+
+// desiredObject is an object Rancher manages in the cluster for a role template binding.
+type desiredObject struct {
+	drift v3.RBACDrift
+	// rules are the rules of the ClusterRoles of role templates. The rules of the other ClusterRoles accumulate those
+	// of several bindings, and aren't compared.
+	rules      []rbacv1.PolicyRule
+	checkRules bool
+	roleName   string
+	subject    rbacv1.Subject
+	// enqueue reconciles the owning binding again.
+	enqueue func()
+}
+
+// check returns the objects of the cluster which differ from those the role template bindings render to, sorted by
+// kind, namespace and name.
+func (c *controller) check() ([]desiredObject, error) {
+	desired, err := c.desiredObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var drifted []desiredObject
+	for _, object := range desired {
+		driftType, message, err := c.compare(object)
+		if err != nil {
+			return nil, err
+		}
+		if driftType == "" {
+			continue
+		}
+		object.drift.Type = driftType
+		object.drift.Message = message
+		drifted = append(drifted, object)
+	}
+	sort.Slice(drifted, func(i, j int) bool {
+		a, b := drifted[i].drift, drifted[j].drift
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return drifted, nil
+}
+
+// desiredObjects renders the role template bindings of the cluster, like the handlers of the rbac package reconcile
+// them. An object rendered for several bindings is reported for the first one, by namespace and name.
+func (c *controller) desiredObjects() (map[string]desiredObject, error) {
+	desired := map[string]desiredObject{}
+
+	crtbs, err := c.crtbCache.List(c.clusterName, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ClusterRoleTemplateBindings: %w", err)
+	}
+	sort.Slice(crtbs, func(i, j int) bool { return crtbs[i].Name < crtbs[j].Name })
+	for _, crtb := range crtbs {
+		if crtb.DeletionTimestamp != nil || crtb.ClusterName != c.clusterName || crtb.RoleTemplateName == "" ||
+			(crtb.UserName == "" && crtb.GroupPrincipalName == "" && crtb.GroupName == "") {
+			continue
+		}
+		// The RoleBindings of bindings scoped to namespaces aren't checked.
+		if crtb.NamespaceSelector != nil {
+			continue
+		}
+		enqueue := func() { c.crtbs.Enqueue(crtb.Namespace, crtb.Name) }
+		if err := c.addRendered(desired, crtb, crtb.RoleTemplateName, "", nil, enqueue); err != nil {
+			return nil, err
+		}
+	}
+
+	prtbs, err := c.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ProjectRoleTemplateBindings: %w", err)
+	}
+	sort.Slice(prtbs, func(i, j int) bool {
+		if prtbs[i].Namespace != prtbs[j].Namespace {
+			return prtbs[i].Namespace < prtbs[j].Namespace
+		}
+		return prtbs[i].Name < prtbs[j].Name
+	})
+	var projectNamespaces map[string][]string
+	for _, prtb := range prtbs {
+		if prtb.DeletionTimestamp != nil || !strings.HasPrefix(prtb.ProjectName, c.clusterName+":") || prtb.RoleTemplateName == "" ||
+			(prtb.UserName == "" && prtb.GroupPrincipalName == "" && prtb.GroupName == "") {
+			continue
+		}
+		if projectNamespaces == nil {
+			if projectNamespaces, err = c.projectNamespaces(); err != nil {
+				return nil, err
+			}
+		}
+		enqueue := func() { c.prtbs.Enqueue(prtb.Namespace, prtb.Name) }
+		if err := c.addRendered(desired, prtb, prtb.RoleTemplateName, prtb.ProjectName, projectNamespaces[prtb.ProjectName], enqueue); err != nil {
+			return nil, err
+		}
+	}
+	return desired, nil
+}
+
+// addRendered adds the objects the binding renders to. Bindings of missing or invalid role templates are skipped, the
+// handlers don't create any object for them either.
+func (c *controller) addRendered(desired map[string]desiredObject, binding metav1.Object, roleTemplateName, projectName string, namespaces []string, enqueue func()) error {
+	bindingName := binding.GetNamespace() + ":" + binding.GetName()
+	subject, err := pkgrbac.BuildSubjectFromRTB(binding)
+	if err != nil {
+		logrus.Debugf("[rbac-drift] skipping binding %s: %v", bindingName, err)
+		return nil
+	}
+	rt, err := c.rtCache.Get(roleTemplateName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting role template %s: %w", roleTemplateName, err)
+	}
+	output, err := rbac.RenderRoleTemplate(c.rtCache.Get, rt, projectName, namespaces, subject)
+	if err != nil {
+		logrus.Debugf("[rbac-drift] skipping binding %s: %v", bindingName, err)
+		return nil
+	}
+
+	add := func(object desiredObject) {
+		key := object.drift.Kind + "/" + object.drift.Namespace + "/" + object.drift.Name
+		if _, ok := desired[key]; ok {
+			return
+		}
+		object.drift.BindingName = bindingName
+		object.enqueue = enqueue
+		desired[key] = object
+	}
+	for _, role := range output.ClusterRoles {
+		// The ClusterRoles of external role templates aren't managed by Rancher.
+		if role.External {
+			continue
+		}
+		_, err := c.rtCache.Get(role.Name)
+		add(desiredObject{
+			drift:      v3.RBACDrift{Kind: clusterRoleKind, Name: role.Name},
+			rules:      role.Rules,
+			checkRules: err == nil,
+		})
+	}
+	for _, binding := range output.ClusterRoleBindings {
+		add(desiredObject{
+			drift:    v3.RBACDrift{Kind: clusterRoleBindingKind, Name: binding.Name},
+			roleName: binding.RoleName,
+			subject:  subject,
+		})
+	}
+	for _, binding := range output.RoleBindings {
+		add(desiredObject{
+			drift:    v3.RBACDrift{Kind: roleBindingKind, Name: binding.Name, Namespace: binding.Namespace},
+			roleName: binding.RoleName,
+			subject:  subject,
+		})
+	}
+	return nil
+}
+
+// projectNamespaces returns the names of the namespaces of the cluster by project, in the <cluster>:<project> format.
+func (c *controller) projectNamespaces() (map[string][]string, error) {
+	namespaces, err := c.namespaceCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing namespaces: %w", err)
+	}
+	byProject := map[string][]string{}
+	for _, namespace := range namespaces {
+		if projectID := namespace.Annotations[project.ProjectIDAnnotation]; projectID != "" && namespace.DeletionTimestamp == nil {
+			byProject[projectID] = append(byProject[projectID], namespace.Name)
+		}
+	}
+	return byProject, nil
+}
+
+// compare returns the type of the drift of the object of the cluster and how it differs from the desired one, or an
+// empty type if it doesn't.
+func (c *controller) compare(object desiredObject) (string, string, error) {
+	drift := object.drift
+	switch drift.Kind {
+	case clusterRoleKind:
+		clusterRole, err := c.clusterRoleCache.Get(drift.Name)
+		if apierrors.IsNotFound(err) {
+			return DriftMissing, fmt.Sprintf("ClusterRole %s is missing", drift.Name), nil
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("getting ClusterRole %s: %w", drift.Name, err)
+		}
+		if object.checkRules && !equality.Semantic.DeepEqual(clusterRole.Rules, object.rules) {
+			return DriftModified, fmt.Sprintf("the rules of ClusterRole %s differ from those of role template %s", drift.Name, drift.Name), nil
+		}
+	case clusterRoleBindingKind:
+		crb, err := c.crbCache.Get(drift.Name)
+		if apierrors.IsNotFound(err) {
+			return DriftMissing, fmt.Sprintf("ClusterRoleBinding %s is missing", drift.Name), nil
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("getting ClusterRoleBinding %s: %w", drift.Name, err)
+		}
+		if message := bindingDifference(clusterRoleBindingKind, drift.Name, crb.RoleRef, crb.Subjects, object); message != "" {
+			return DriftModified, message, nil
+		}
+	case roleBindingKind:
+		rb, err := c.rbCache.Get(drift.Namespace, drift.Name)
+		if apierrors.IsNotFound(err) {
+			return DriftMissing, fmt.Sprintf("RoleBinding %s/%s is missing", drift.Namespace, drift.Name), nil
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("getting RoleBinding %s/%s: %w", drift.Namespace, drift.Name, err)
+		}
+		if message := bindingDifference(roleBindingKind, drift.Namespace+"/"+drift.Name, rb.RoleRef, rb.Subjects, object); message != "" {
+			return DriftModified, message, nil
+		}
+	}
+	return "", "", nil
+}
+
+// bindingDifference returns how the binding differs from the desired one, which binds the ClusterRole to its subject
+// alone.
+func bindingDifference(kind, name string, roleRef rbacv1.RoleRef, subjects []rbacv1.Subject, object desiredObject) string {
+	if roleRef.Kind != clusterRoleKind || roleRef.Name != object.roleName {
+		return fmt.Sprintf("%s %s binds %s %s instead of ClusterRole %s", kind, name, roleRef.Kind, roleRef.Name, object.roleName)
+	}
+	if len(subjects) != 1 || subjects[0].Kind != object.subject.Kind || subjects[0].Name != object.subject.Name ||
+		subjects[0].Namespace != object.subject.Namespace {
+		return fmt.Sprintf("the subjects of %s %s differ from %s %s", kind, name, object.subject.Kind, object.subject.Name)
+	}
+	return ""
+}
+
+// repair deletes the modified bindings, whose role can't be updated, and reconciles the owning role template bindings
+// again, which recreates the missing objects and updates the rules of the ClusterRoles.
+func (c *controller) repair(drifted []desiredObject) {
+	for i := range drifted {
+		drift := &drifted[i].drift
+		if drift.Type == DriftModified {
+			var err error
+			switch drift.Kind {
+			case clusterRoleBindingKind:
+				err = c.crbs.Delete(drift.Name, &metav1.DeleteOptions{})
+			case roleBindingKind:
+				err = c.rbs.Delete(drift.Namespace, drift.Name, &metav1.DeleteOptions{})
+			}
+			if err != nil && !apierrors.IsNotFound(err) {
+				logrus.Errorf("[rbac-drift] failed to delete %s %s in cluster %s: %v", drift.Kind, drift.Name, c.clusterName, err)
+				continue
+			}
+		}
+		drifted[i].enqueue()
+		drift.Repaired = true
+	}
+}
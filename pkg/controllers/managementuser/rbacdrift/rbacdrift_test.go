@@ -0,0 +1,238 @@
+package rbacdrift
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/project"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	user       = rbacv1.Subject{Kind: "User", Name: "u-abc", APIGroup: rbacv1.GroupName}
+	group      = rbacv1.Subject{Kind: "Group", Name: "openldap_group://cn=devs", APIGroup: rbacv1.GroupName}
+	nodeReader = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}}
+	podReader  = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}
+
+	clusterViewerCRB = pkgrbac.NameForClusterRoleBinding(rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-viewer"}, user)
+	namespacesCRB    = pkgrbac.NameForClusterRoleBinding(rbacv1.RoleRef{Kind: "ClusterRole", Name: "p-xyz-namespaces-readonly"}, group)
+	projectViewerRB  = pkgrbac.NameForRoleBinding("ns-a", rbacv1.RoleRef{Kind: "ClusterRole", Name: "project-viewer"}, group)
+)
+
+type mocks struct {
+	reports *fake.MockNonNamespacedControllerInterface[*v3.RBACDriftReport, *v3.RBACDriftReportList]
+	crtbs   *fake.MockControllerInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList]
+	prtbs   *fake.MockControllerInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList]
+	rbs     *fake.MockClientInterface[*rbacv1.RoleBinding, *rbacv1.RoleBindingList]
+}
+
+// setup returns a controller of cluster c-abcde, where:
+// - the rules of the ClusterRole of the cluster-viewer role template were edited,
+// - the ClusterRoleBinding of the ClusterRoleTemplateBinding was deleted,
+// - the subject of the RoleBinding of the ProjectRoleTemplateBinding was edited.
+func setup(t *testing.T, now time.Time) (*controller, *mocks) {
+	ctrl := gomock.NewController(t)
+	notFound := func(resource, name string) error {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: resource}, name)
+	}
+
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	crtbCache.EXPECT().List("c-abcde", gomock.Any()).Return([]*v3.ClusterRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-1", Namespace: "c-abcde"}, ClusterName: "c-abcde", UserName: "u-abc", RoleTemplateName: "cluster-viewer"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "crtb-2", Namespace: "c-abcde"}, ClusterName: "c-abcde", UserName: "u-abc", RoleTemplateName: "missing"},
+	}, nil).AnyTimes()
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	prtbCache.EXPECT().List("", gomock.Any()).Return([]*v3.ProjectRoleTemplateBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-1", Namespace: "p-xyz"}, ProjectName: "c-abcde:p-xyz", GroupPrincipalName: group.Name, RoleTemplateName: "project-viewer"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prtb-2", Namespace: "p-other"}, ProjectName: "c-other:p-other", UserName: "u-abc", RoleTemplateName: "project-viewer"},
+	}, nil).AnyTimes()
+
+	roleTemplates := map[string]*v3.RoleTemplate{
+		"cluster-viewer": {ObjectMeta: metav1.ObjectMeta{Name: "cluster-viewer"}, Context: "cluster", Rules: []rbacv1.PolicyRule{nodeReader}},
+		"project-viewer": {ObjectMeta: metav1.ObjectMeta{Name: "project-viewer"}, Context: "project", Rules: []rbacv1.PolicyRule{podReader}},
+	}
+	rtCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	rtCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.RoleTemplate, error) {
+		if rt, ok := roleTemplates[name]; ok {
+			return rt, nil
+		}
+		return nil, notFound("roletemplates", name)
+	}).AnyTimes()
+
+	namespaceCache := fake.NewMockNonNamespacedCacheInterface[*corev1.Namespace](ctrl)
+	namespaceCache.EXPECT().List(gomock.Any()).Return([]*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-a", Annotations: map[string]string{project.ProjectIDAnnotation: "c-abcde:p-xyz"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-b"}},
+	}, nil).AnyTimes()
+
+	clusterRoles := map[string]*rbacv1.ClusterRole{
+		"cluster-viewer":            {ObjectMeta: metav1.ObjectMeta{Name: "cluster-viewer"}, Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"*"}}}},
+		"project-viewer":            {ObjectMeta: metav1.ObjectMeta{Name: "project-viewer"}, Rules: []rbacv1.PolicyRule{podReader}},
+		"p-xyz-namespaces-readonly": {ObjectMeta: metav1.ObjectMeta{Name: "p-xyz-namespaces-readonly"}},
+	}
+	clusterRoleCache := fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRole](ctrl)
+	clusterRoleCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*rbacv1.ClusterRole, error) {
+		if clusterRole, ok := clusterRoles[name]; ok {
+			return clusterRole, nil
+		}
+		return nil, notFound("clusterroles", name)
+	}).AnyTimes()
+	crbCache := fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRoleBinding](ctrl)
+	crbCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*rbacv1.ClusterRoleBinding, error) {
+		if name == namespacesCRB {
+			return &rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "p-xyz-namespaces-readonly"},
+				Subjects:   []rbacv1.Subject{group},
+			}, nil
+		}
+		return nil, notFound("clusterrolebindings", name)
+	}).AnyTimes()
+	rbCache := fake.NewMockCacheInterface[*rbacv1.RoleBinding](ctrl)
+	rbCache.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(namespace, name string) (*rbacv1.RoleBinding, error) {
+		if namespace == "ns-a" && name == projectViewerRB {
+			return &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "project-viewer"},
+				Subjects:   []rbacv1.Subject{{Kind: "User", Name: "u-other", APIGroup: rbacv1.GroupName}},
+			}, nil
+		}
+		return nil, notFound("rolebindings", name)
+	}).AnyTimes()
+
+	m := &mocks{
+		reports: fake.NewMockNonNamespacedControllerInterface[*v3.RBACDriftReport, *v3.RBACDriftReportList](ctrl),
+		crtbs:   fake.NewMockControllerInterface[*v3.ClusterRoleTemplateBinding, *v3.ClusterRoleTemplateBindingList](ctrl),
+		prtbs:   fake.NewMockControllerInterface[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](ctrl),
+		rbs:     fake.NewMockClientInterface[*rbacv1.RoleBinding, *rbacv1.RoleBindingList](ctrl),
+	}
+	c := &controller{
+		clusterName:      "c-abcde",
+		reports:          m.reports,
+		reportCache:      fake.NewMockNonNamespacedCacheInterface[*v3.RBACDriftReport](ctrl),
+		crtbs:            m.crtbs,
+		crtbCache:        crtbCache,
+		prtbs:            m.prtbs,
+		prtbCache:        prtbCache,
+		rtCache:          rtCache,
+		namespaceCache:   namespaceCache,
+		clusterRoleCache: clusterRoleCache,
+		crbs:             fake.NewMockNonNamespacedClientInterface[*rbacv1.ClusterRoleBinding, *rbacv1.ClusterRoleBindingList](ctrl),
+		crbCache:         crbCache,
+		rbs:              m.rbs,
+		rbCache:          rbCache,
+		now:              func() time.Time { return now },
+	}
+	return c, m
+}
+
+func expectUpdateStatus(m *mocks) *v3.RBACDriftReport {
+	updated := &v3.RBACDriftReport{}
+	m.reports.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(report *v3.RBACDriftReport) (*v3.RBACDriftReport, error) {
+		report.DeepCopyInto(updated)
+		return report, nil
+	})
+	return updated
+}
+
+func TestSyncReportsDrift(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c, m := setup(t, now)
+	updated := expectUpdateStatus(m)
+
+	report := &v3.RBACDriftReport{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", Generation: 1}, Spec: v3.RBACDriftReportSpec{ClusterName: "c-abcde"}}
+	_, err := c.sync("c-abcde", report)
+	require.NoError(t, err)
+
+	assert.Equal(t, []v3.RBACDrift{
+		{Kind: "ClusterRole", Name: "cluster-viewer", Type: DriftModified, Message: "the rules of ClusterRole cluster-viewer differ from those of role template cluster-viewer", BindingName: "c-abcde:crtb-1"},
+		{Kind: "ClusterRoleBinding", Name: clusterViewerCRB, Type: DriftMissing, Message: "ClusterRoleBinding " + clusterViewerCRB + " is missing", BindingName: "c-abcde:crtb-1"},
+		{Kind: "RoleBinding", Name: projectViewerRB, Namespace: "ns-a", Type: DriftModified, Message: "the subjects of RoleBinding ns-a/" + projectViewerRB + " differ from Group " + group.Name, BindingName: "p-xyz:prtb-1"},
+	}, updated.Status.Drifts)
+	assert.Equal(t, 3, updated.Status.DriftCount)
+	assert.Equal(t, now, updated.Status.LastCheckTime.Time)
+	assert.Equal(t, int64(1), updated.Status.ObservedGeneration)
+	assert.Empty(t, updated.Status.Error)
+}
+
+func TestSyncRepairsDrift(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c, m := setup(t, now)
+	updated := expectUpdateStatus(m)
+	m.rbs.EXPECT().Delete("ns-a", projectViewerRB, gomock.Any()).Return(nil)
+	m.crtbs.EXPECT().Enqueue("c-abcde", "crtb-1").Times(2)
+	m.prtbs.EXPECT().Enqueue("p-xyz", "prtb-1")
+
+	report := &v3.RBACDriftReport{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde"}, Spec: v3.RBACDriftReportSpec{ClusterName: "c-abcde", AutoRepair: true}}
+	_, err := c.sync("c-abcde", report)
+	require.NoError(t, err)
+
+	require.Len(t, updated.Status.Drifts, 3)
+	for _, drift := range updated.Status.Drifts {
+		assert.True(t, drift.Repaired, drift.Name)
+	}
+}
+
+func TestSyncWaitsForInterval(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c, m := setup(t, now)
+	lastCheck := metav1.NewTime(now.Add(-20 * time.Minute))
+	report := &v3.RBACDriftReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", Generation: 2},
+		Spec:       v3.RBACDriftReportSpec{ClusterName: "c-abcde"},
+		Status:     v3.RBACDriftReportStatus{LastCheckTime: &lastCheck, ObservedGeneration: 2},
+	}
+
+	m.reports.EXPECT().EnqueueAfter("c-abcde", 40*time.Minute)
+	_, err := c.sync("c-abcde", report)
+	require.NoError(t, err)
+
+	// Editing the report checks the cluster right away.
+	report.Generation = 3
+	updated := expectUpdateStatus(m)
+	_, err = c.sync("c-abcde", report)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated.Status.ObservedGeneration)
+	assert.Equal(t, 3, updated.Status.DriftCount)
+
+	// The reports of the other clusters are ignored.
+	_, err = c.sync("c-other", &v3.RBACDriftReport{ObjectMeta: metav1.ObjectMeta{Name: "c-other"}})
+	require.NoError(t, err)
+}
+
+func TestEnsureReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reports := fake.NewMockNonNamespacedControllerInterface[*v3.RBACDriftReport, *v3.RBACDriftReportList](ctrl)
+	reportCache := fake.NewMockNonNamespacedCacheInterface[*v3.RBACDriftReport](ctrl)
+	c := &controller{clusterName: "c-abcde", reports: reports, reportCache: reportCache}
+	cluster := &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde", UID: "uid-1"}}
+
+	reportCache.EXPECT().Get("c-abcde").Return(nil, apierrors.NewNotFound(schema.GroupResource{Resource: "rbacdriftreports"}, "c-abcde"))
+	reports.EXPECT().Create(gomock.Any()).DoAndReturn(func(report *v3.RBACDriftReport) (*v3.RBACDriftReport, error) {
+		assert.Equal(t, "c-abcde", report.Name)
+		assert.Equal(t, "c-abcde", report.Spec.ClusterName)
+		require.Len(t, report.OwnerReferences, 1)
+		assert.Equal(t, "Cluster", report.OwnerReferences[0].Kind)
+		assert.Equal(t, cluster.UID, report.OwnerReferences[0].UID)
+		return report, nil
+	})
+	_, err := c.ensureReport("c-abcde", cluster)
+	require.NoError(t, err)
+
+	reportCache.EXPECT().Get("c-abcde").Return(&v3.RBACDriftReport{ObjectMeta: metav1.ObjectMeta{Name: "c-abcde"}}, nil)
+	_, err = c.ensureReport("c-abcde", cluster)
+	require.NoError(t, err)
+
+	_, err = c.ensureReport("c-other", &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c-other"}})
+	require.NoError(t, err)
+}
@@ -52,8 +52,9 @@ func registerDeferred(ctx context.Context, cluster *config.UserContext) {
 	cluster.Core.Namespaces("").AddHandler(ctx, "resourceQuotaSyncController", sync.syncResourceQuota)
 
 	reconcile := &reconcileController{
-		namespaces: cluster.Core.Namespaces(""),
-		nsIndexer:  nsInformer.GetIndexer(),
+		namespaces:    cluster.Core.Namespaces(""),
+		nsIndexer:     nsInformer.GetIndexer(),
+		projectLister: cluster.Management.Management.Projects(cluster.ClusterName).Controller().Lister(),
 	}
 
 	cluster.Management.Management.Projects(cluster.ClusterName).AddHandler(ctx, "resourceQuotaNamespacesReconcileController", reconcile.reconcileNamespaces)
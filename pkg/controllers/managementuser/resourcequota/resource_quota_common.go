@@ -6,7 +6,9 @@
 	"github.com/rancher/norman/types/convert"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	pkgproject "github.com/rancher/rancher/pkg/project"
 	"github.com/rancher/rancher/pkg/ref"
+	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -191,6 +193,13 @@ func getProjectNamespaceDefaultQuota(ns *corev1.Namespace, projectLister v3.Proj
 		}
 		return nil, err
 	}
+	if project.Spec.NamespaceDefaultResourceQuota == nil {
+		if ancestor := nearestAncestor(project, projectLister, func(p *v32.Project) bool {
+			return p.Spec.NamespaceDefaultResourceQuota != nil
+		}); ancestor != nil {
+			return ancestor.Spec.NamespaceDefaultResourceQuota, nil
+		}
+	}
 	return project.Spec.NamespaceDefaultResourceQuota, nil
 }
 
@@ -212,9 +221,35 @@ func getProjectContainerDefaultLimit(ns *corev1.Namespace, projectLister v3.Proj
 		}
 		return nil, err
 	}
+	if project.Spec.ContainerDefaultResourceLimit == nil {
+		if ancestor := nearestAncestor(project, projectLister, func(p *v32.Project) bool {
+			return p.Spec.ContainerDefaultResourceLimit != nil
+		}); ancestor != nil {
+			return ancestor.Spec.ContainerDefaultResourceLimit, nil
+		}
+	}
 	return project.Spec.ContainerDefaultResourceLimit, nil
 }
 
+// nearestAncestor returns the nearest ancestor of the project with the default it inherits, or nil if none has it.
+// Projects whose hierarchy is invalid don't inherit any default.
+func nearestAncestor(project *v32.Project, projectLister v3.ProjectLister, hasDefault func(*v32.Project) bool) *v32.Project {
+	if project.Spec.ParentProject == "" {
+		return nil
+	}
+	ancestors, err := pkgproject.Ancestors(project, projectLister)
+	if err != nil {
+		logrus.Debugf("resourcequota: project %s/%s doesn't inherit defaults: %v", project.Namespace, project.Name, err)
+		return nil
+	}
+	for _, ancestor := range ancestors {
+		if hasDefault(ancestor) {
+			return ancestor
+		}
+	}
+	return nil
+}
+
 func getNamespaceResourceQuotaLimit(ns *corev1.Namespace) (*v32.ResourceQuotaLimit, error) {
 	value := getNamespaceResourceQuota(ns)
 	if value == "" {
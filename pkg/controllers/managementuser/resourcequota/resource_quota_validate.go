@@ -6,32 +6,65 @@
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientcache "k8s.io/client-go/tools/cache"
 )
 
 /*
 reconcile controller listens on project updates, and enqueues the namespaces of the project
-so they get a chance to reconcile the resource quotas
+and of its descendants, which inherit its defaults, so they get a chance to reconcile the resource quotas
 */
 type reconcileController struct {
-	namespaces v1.NamespaceInterface
-	nsIndexer  clientcache.Indexer
+	namespaces    v1.NamespaceInterface
+	nsIndexer     clientcache.Indexer
+	projectLister v3.ProjectLister
 }
 
 func (r *reconcileController) reconcileNamespaces(key string, p *v3.Project) (runtime.Object, error) {
 	if p == nil || p.DeletionTimestamp != nil {
 		return nil, nil
 	}
-	projectID := fmt.Sprintf("%s:%s", p.Namespace, p.Name)
-	namespaces, err := r.nsIndexer.ByIndex(nsByProjectIndex, projectID)
+	projectNames, err := r.withDescendants(p)
 	if err != nil {
 		return nil, err
 	}
+	for _, projectName := range projectNames {
+		projectID := fmt.Sprintf("%s:%s", p.Namespace, projectName)
+		namespaces, err := r.nsIndexer.ByIndex(nsByProjectIndex, projectID)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, n := range namespaces {
-		ns := n.(*corev1.Namespace)
-		r.namespaces.Controller().Enqueue("", ns.Name)
+		for _, n := range namespaces {
+			ns := n.(*corev1.Namespace)
+			r.namespaces.Controller().Enqueue("", ns.Name)
+		}
 	}
 	return nil, nil
 }
+
+// withDescendants returns the name of the project and of its descendants.
+func (r *reconcileController) withDescendants(p *v3.Project) ([]string, error) {
+	names := []string{p.Name}
+	projects, err := r.projectLister.List(p.Namespace, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	children := map[string][]string{}
+	for _, project := range projects {
+		if project.Spec.ParentProject != "" {
+			children[project.Spec.ParentProject] = append(children[project.Spec.ParentProject], project.Name)
+		}
+	}
+	seen := map[string]bool{p.Name: true}
+	for i := 0; i < len(names); i++ {
+		for _, child := range children[names[i]] {
+			if !seen[child] {
+				seen[child] = true
+				names = append(names, child)
+			}
+		}
+	}
+	return names, nil
+}
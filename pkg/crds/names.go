@@ -39,6 +39,7 @@ func BasicCRDs() []string {
 		"clusterrepos.catalog.cattle.io",
 		"operations.catalog.cattle.io",
 		"apiservices.management.cattle.io",
+		"auditpolicies.management.cattle.io",
 		"clusters.management.cattle.io",
 		"clusterregistrationtokens.management.cattle.io",
 		"features.management.cattle.io",
@@ -107,6 +108,17 @@ func AuthCRDs() []string {
 		"users.management.cattle.io",
 		"userattributes.management.cattle.io",
 		"clusterproxyconfigs.management.cattle.io",
+		"jitprovisioningpolicies.management.cattle.io",
+		"accessrequests.management.cattle.io",
+		"groupmembershiprules.management.cattle.io",
+		"rbacdriftreports.management.cattle.io",
+		"orphanedbindingreports.management.cattle.io",
+		"breakglassaccounts.management.cattle.io",
+		"roleusagereports.management.cattle.io",
+		"organizations.management.cattle.io",
+		"principalmetadatas.management.cattle.io",
+		"authevents.management.cattle.io",
+		"securityevents.management.cattle.io",
 	}
 }
 
@@ -172,14 +184,18 @@ func UIPluginsCRD() []string {
 
 // MigratedResources map list of resource that have been migrated after all resource have a CRD this can be removed.
 var MigratedResources = map[string]bool{
+	"accessrequests.management.cattle.io":                             true,
 	"activedirectoryproviders.management.cattle.io":                   false,
 	"apiservices.management.cattle.io":                                false,
 	"apps.catalog.cattle.io":                                          false,
+	"auditpolicies.management.cattle.io":                              true,
 	"authconfigs.management.cattle.io":                                false,
+	"authevents.management.cattle.io":                                 true,
 	"authproviders.management.cattle.io":                              false,
 	"authtokens.management.cattle.io":                                 false,
 	"azureadproviders.management.cattle.io":                           false,
 	"basicauths.project.cattle.io":                                    false,
+	"breakglassaccounts.management.cattle.io":                         true,
 	"certificates.project.cattle.io":                                  false,
 	"cloudcredentials.management.cattle.io":                           false,
 	"clusterauthtokens.cluster.cattle.io":                             false,
@@ -210,8 +226,10 @@ func UIPluginsCRD() []string {
 	"globalroles.management.cattle.io":                                true,
 	"googleoauthproviders.management.cattle.io":                       false,
 	"groupmembers.management.cattle.io":                               false,
+	"groupmembershiprules.management.cattle.io":                       true,
 	"groups.management.cattle.io":                                     false,
 	"ipaddressclaims.ipam.cluster.x-k8s.io":                           false,
+	"jitprovisioningpolicies.management.cattle.io":                    true,
 	"kontainerdrivers.management.cattle.io":                           false,
 	"localproviders.management.cattle.io":                             false,
 	"machinedeployments.cluster.x-k8s.io":                             false,
@@ -220,6 +238,7 @@ func UIPluginsCRD() []string {
 	"machinesets.cluster.x-k8s.io":                                    false,
 	"managedcharts.management.cattle.io":                              false,
 	"monitormetrics.management.cattle.io":                             false,
+	"multiclusterroletemplatebindings.management.cattle.io":           true,
 	"navlinks.ui.cattle.io":                                           false,
 	"nodedrivers.management.cattle.io":                                false,
 	"nodepools.management.cattle.io":                                  false,
@@ -228,13 +247,18 @@ func UIPluginsCRD() []string {
 	"oidcproviders.management.cattle.io":                              false,
 	"openldapproviders.management.cattle.io":                          false,
 	"operations.catalog.cattle.io":                                    false,
+	"organizations.management.cattle.io":                              true,
+	"orphanedbindingreports.management.cattle.io":                     true,
 	"podsecurityadmissionconfigurationtemplates.management.cattle.io": false,
 	"preferences.management.cattle.io":                                false,
+	"principalmetadatas.management.cattle.io":                         true,
 	"principals.management.cattle.io":                                 false,
 	"projectnetworkpolicies.management.cattle.io":                     false,
 	"projectroletemplatebindings.management.cattle.io":                true,
 	"projects.management.cattle.io":                                   true,
 	"rancherusernotifications.management.cattle.io":                   false,
+	"recertificationcampaigns.management.cattle.io":                   true,
+	"rbacdriftreports.management.cattle.io":                           true,
 	"rkeaddons.management.cattle.io":                                  false,
 	"rkebootstraps.rke.cattle.io":                                     false,
 	"rkebootstraptemplates.rke.cattle.io":                             false,
@@ -243,8 +267,10 @@ func UIPluginsCRD() []string {
 	"rkek8sserviceoptions.management.cattle.io":                       false,
 	"rkek8ssystemimages.management.cattle.io":                         false,
 	"roletemplates.management.cattle.io":                              true,
+	"roleusagereports.management.cattle.io":                           true,
 	"samlproviders.management.cattle.io":                              false,
 	"samltokens.management.cattle.io":                                 false,
+	"securityevents.management.cattle.io":                             true,
 	"serviceaccounttokens.project.cattle.io":                          false,
 	"settings.management.cattle.io":                                   false,
 	"sshauths.project.cattle.io":                                      false,
@@ -22,7 +22,8 @@ func addUnauthenticatedRoles(apply apply.Apply) error {
 						Resources: []string{"settings"},
 						ResourceNames: []string{
 							"first-login", "ui-pl", "ui-banners", "ui-brand", "ui-favicon", "ui-login-background-light", "ui-login-background-dark", "ui-primary-color", "ui-link-color",
-							"ui-banner-header", "ui-banner-footer", "ui-banner-login-consent"},
+							"ui-banner-header", "ui-banner-footer", "ui-banner-login-consent", "auth-login-captcha-provider", "auth-login-captcha-site-key",
+							"password-reset-enabled"},
 					},
 				},
 			},
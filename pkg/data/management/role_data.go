@@ -6,12 +6,12 @@
 	"sync"
 
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/passwordhash"
 	"github.com/rancher/rancher/pkg/features"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/wrangler"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -33,6 +33,38 @@
 	adminCreateLock   sync.Mutex
 )
 
+// addAuthnAdminRole adds the global role operating authentication without access to the clusters. The users can't be
+// bound roles nor logged in as, and the tokens of other users can only be revoked: issuing them, or changing the
+// credentials of users holding global permissions the caller isn't granted, would make the role as privileged as
+// admin. For the same reason, the user attributes, whose group principals are the groups of the requests, and the
+// group membership rules, which bind cluster and project roles, can't be written. The v3 tokens can only be deleted,
+// as they hold their keys when token hashing is off.
+func addAuthnAdminRole(rb *roleBuilder) *roleBuilder {
+	role := rb.addRole("Authentication Admin", "authn-admin")
+	role.addRule().apiGroups("management.cattle.io").resources("authconfigs").verbs("get", "list", "watch", "create", "update", "patch").
+		addRule().apiGroups("management.cattle.io").resources("users", "groups", "groupmembers").
+		verbs("get", "list", "watch", "create", "update", "patch", "delete", "deletecollection").
+		addRule().apiGroups("management.cattle.io").resources("userattributes", "groupmembershiprules").
+		verbs("get", "list", "watch", "delete", "deletecollection").
+		addRule().apiGroups("management.cattle.io").resources("tokens").verbs("delete").
+		addRule().apiGroups("management.cattle.io").resources("principals").verbs("get", "list", "watch", "search").
+		addRule().apiGroups("management.cattle.io").resources("principalmetadatas", "authevents").verbs("get", "list", "watch").
+		addRule().apiGroups("ext.cattle.io").resources("tokens").verbs("get", "list", "watch", "delete", "revoke")
+	return role
+}
+
+// addTokensRevokeRole adds the global role revoking the tokens of any user. The v3 tokens can only be deleted, as they
+// hold their keys when token hashing is off: they are listed through the sessions endpoint, which doesn't return the
+// keys. Seeing and deleting the ext tokens of other users, which are always hashed, is authorized with the custom
+// revoke verb on tokens.
+func addTokensRevokeRole(rb *roleBuilder) *roleBuilder {
+	role := rb.addRole("Revoke Tokens", "tokens-revoke")
+	role.addRule().apiGroups("management.cattle.io").resources("tokens").verbs("delete").
+		addRule().apiGroups("ext.cattle.io").resources("tokens").verbs("get", "list", "delete", "revoke").
+		addRule().apiGroups("management.cattle.io").resources("users").verbs("get", "list", "watch")
+	return role
+}
+
 func addRoles(wrangler *wrangler.Context, management *config.ManagementContext) (string, error) {
 	rb := newRoleBuilder()
 
@@ -72,6 +104,18 @@ func addRoles(wrangler *wrangler.Context, management *config.ManagementContext)
 		addRule().apiGroups("management.cattle.io").resources("clustertemplaterevisions").verbs("create")
 	rb.addRole("View Rancher Metrics", "view-rancher-metrics").
 		addRule().apiGroups("management.cattle.io").resources("ranchermetrics").verbs("get")
+	// impersonation of users, their groups and extras is checked with subject access reviews on these resources
+	// logging in as users is authorized with the custom loginas verb on users
+	rb.addRole("Log In As Users", "users-loginas").
+		addRule().apiGroups("management.cattle.io").resources("users").verbs("get", "list", "watch", "loginas")
+	rb.addRole("Impersonate Users", "users-impersonate").
+		addRule().apiGroups("").resources("users", "groups", "userextras/*").verbs("impersonate")
+	addTokensRevokeRole(rb)
+	// searching principals, when the principal-search-requires-permission setting is on, is authorized with the custom
+	// search verb on principals
+	rb.addRole("Search Principals", "principals-search").
+		addRule().apiGroups("management.cattle.io").resources("principals").verbs("get", "list", "watch", "search")
+	addAuthnAdminRole(rb)
 
 	rb.addRole("Admin", "admin").
 		addRule().apiGroups("*").resources("*").verbs("*").
@@ -204,6 +248,10 @@ func addRoles(wrangler *wrangler.Context, management *config.ManagementContext)
 	rb.addRoleTemplate("Manage Navlinks", "navlinks-manage", "cluster", false, false, false).
 		addRule().apiGroups("ui.cattle.io").resources("navlinks").verbs("*")
 
+	// Bound by the organizations to their admins in each of their clusters.
+	rb.addRoleTemplate("Organization Admin", "organization-admin", "cluster", false, false, true).
+		setRoleTemplateNames("cluster-owner")
+
 	// Project roles
 	rb.addRoleTemplate("Project Owner", "project-owner", "project", false, false, false).
 		addRule().apiGroups("ui.cattle.io").resources("navlinks").verbs("get", "list", "watch").
@@ -466,7 +514,7 @@ func BootstrapAdmin(management *wrangler.Context) (string, error) {
 			return "", fmt.Errorf("failed to retrieve bootstrap password: %w", err)
 		}
 
-		bootstrapPasswordHash, _ := bcrypt.GenerateFromPassword([]byte(bootstrapPassword), bcrypt.DefaultCost)
+		bootstrapPasswordHash, _ := passwordhash.Hash(bootstrapPassword)
 
 		admin, err := management.Mgmt.User().Create(&v3.User{
 			ObjectMeta: v1.ObjectMeta{
@@ -0,0 +1,90 @@
+package management
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac"
+)
+
+func TestAuthnAdminRole(t *testing.T) {
+	rules := addAuthnAdminRole(newRoleBuilder()).policyRules()
+
+	tests := []struct {
+		verb     string
+		apiGroup string
+		resource string
+		want     bool
+	}{
+		// Issuing tokens, including for the admins, isn't allowed. They can only be revoked.
+		{verb: "create", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "update", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "patch", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "delete", apiGroup: "management.cattle.io", resource: "tokens", want: true},
+		// The v3 tokens hold their keys when token hashing is off.
+		{verb: "get", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "list", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "watch", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "create", apiGroup: "ext.cattle.io", resource: "tokens", want: false},
+		{verb: "update", apiGroup: "ext.cattle.io", resource: "tokens", want: false},
+		{verb: "patch", apiGroup: "ext.cattle.io", resource: "tokens", want: false},
+		{verb: "revoke", apiGroup: "ext.cattle.io", resource: "tokens", want: true},
+		// The users are managed, the changes to those with more permissions are refused by the user store.
+		{verb: "update", apiGroup: "management.cattle.io", resource: "users", want: true},
+		// The group principals of the user attributes are the groups of the requests, and the group membership rules
+		// bind cluster and project roles.
+		{verb: "get", apiGroup: "management.cattle.io", resource: "userattributes", want: true},
+		{verb: "create", apiGroup: "management.cattle.io", resource: "userattributes", want: false},
+		{verb: "update", apiGroup: "management.cattle.io", resource: "userattributes", want: false},
+		{verb: "patch", apiGroup: "management.cattle.io", resource: "userattributes", want: false},
+		{verb: "delete", apiGroup: "management.cattle.io", resource: "groupmembershiprules", want: true},
+		{verb: "create", apiGroup: "management.cattle.io", resource: "groupmembershiprules", want: false},
+		{verb: "update", apiGroup: "management.cattle.io", resource: "groupmembershiprules", want: false},
+		{verb: "patch", apiGroup: "management.cattle.io", resource: "groupmembershiprules", want: false},
+		{verb: "create", apiGroup: "management.cattle.io", resource: "globalrolebindings", want: false},
+		{verb: "get", apiGroup: "management.cattle.io", resource: "clusters", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.verb+" "+tt.apiGroup+"/"+tt.resource, func(t *testing.T) {
+			allowed := rbac.RulesAllow(authorizer.AttributesRecord{
+				Verb:            tt.verb,
+				APIGroup:        tt.apiGroup,
+				Resource:        tt.resource,
+				ResourceRequest: true,
+			}, rules...)
+			assert.Equal(t, tt.want, allowed)
+		})
+	}
+}
+
+func TestTokensRevokeRole(t *testing.T) {
+	rules := addTokensRevokeRole(newRoleBuilder()).policyRules()
+
+	tests := []struct {
+		verb     string
+		apiGroup string
+		resource string
+		want     bool
+	}{
+		// The v3 tokens hold their keys when token hashing is off, so they can only be deleted.
+		{verb: "get", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "list", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "watch", apiGroup: "management.cattle.io", resource: "tokens", want: false},
+		{verb: "delete", apiGroup: "management.cattle.io", resource: "tokens", want: true},
+		{verb: "list", apiGroup: "ext.cattle.io", resource: "tokens", want: true},
+		{verb: "revoke", apiGroup: "ext.cattle.io", resource: "tokens", want: true},
+		{verb: "create", apiGroup: "ext.cattle.io", resource: "tokens", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.verb+" "+tt.apiGroup+"/"+tt.resource, func(t *testing.T) {
+			allowed := rbac.RulesAllow(authorizer.AttributesRecord{
+				Verb:            tt.verb,
+				APIGroup:        tt.apiGroup,
+				Resource:        tt.resource,
+				ResourceRequest: true,
+			}, rules...)
+			assert.Equal(t, tt.want, allowed)
+		})
+	}
+}
@@ -6,6 +6,7 @@
 	"strings"
 	"time"
 
+	"github.com/rancher/rancher/pkg/auth/servicekeys"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/systemaccount"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -94,6 +95,18 @@ func (u *userCleanup) cleanup() error {
 
 func (u *userCleanup) checkClusterOrProjectExistsForSystemUser(user *v3.User, clusters []*v3.Cluster) error {
 	displayName := user.DisplayName
+	if projectID := servicekeys.ProjectID(user); projectID != "" {
+		// the service keys of a project are deleted along with it
+		clusterName, projectName, _ := strings.Cut(projectID, ":")
+		_, err := u.projectLister.Get(clusterName, projectName)
+		if errors.IsNotFound(err) {
+			return u.deleteSystemUser(user.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("error finding project %v of service key %v during system account users cleanup: %v", projectID, user.Name, err)
+		}
+		return nil
+	}
 	if strings.HasPrefix(user.DisplayName, systemaccount.ClusterSystemAccountPrefix) {
 		clusterID := strings.TrimPrefix(displayName, systemaccount.ClusterSystemAccountPrefix)
 		// check if this cluster exists, if not, delete this user
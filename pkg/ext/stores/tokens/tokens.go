@@ -14,6 +14,7 @@
 
 	ext "github.com/rancher/rancher/pkg/apis/ext.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/auth/accessor"
+	"github.com/rancher/rancher/pkg/auth/authevents"
 	"github.com/rancher/rancher/pkg/auth/providers/common"
 	"github.com/rancher/rancher/pkg/auth/tokens/hashers"
 	v3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
@@ -480,10 +481,29 @@ func (t *Store) delete(ctx context.Context, token *ext.Token, options *metav1.De
 		return err
 	}
 	if !isAdmin && (!isRancherUser || !userMatch(user, token)) {
-		return apierrors.NewNotFound(GVR.GroupResource(), token.Name)
+		canRevoke, err := t.auth.CanRevoke(ctx, &t.SystemStore)
+		if err != nil {
+			return err
+		}
+		if !canRevoke {
+			return apierrors.NewNotFound(GVR.GroupResource(), token.Name)
+		}
 	}
 
-	return t.SystemStore.Delete(token.Name, options)
+	if err := t.SystemStore.Delete(token.Name, options); err != nil {
+		return err
+	}
+	if !userMatch(user, token) {
+		// revocations of the tokens of other users are recorded in the audit log for the incident responders
+		authevents.Record(authevents.Event{
+			Type:     authevents.TokenRevoked,
+			UserName: token.Spec.UserID,
+			Provider: token.GetAuthProvider(),
+			Actor:    user,
+			Target:   token.Name,
+		})
+	}
+	return nil
 }
 
 func (t *SystemStore) Delete(name string, options *metav1.DeleteOptions) error {
@@ -514,7 +534,13 @@ func (t *Store) get(ctx context.Context, name string, options *metav1.GetOptions
 		return token, nil
 	}
 	if !userMatch(userName, token) {
-		return nil, apierrors.NewNotFound(GVR.GroupResource(), name)
+		canRevoke, err := t.auth.CanRevoke(ctx, &t.SystemStore)
+		if err != nil {
+			return nil, err
+		}
+		if !canRevoke {
+			return nil, apierrors.NewNotFound(GVR.GroupResource(), name)
+		}
 	}
 
 	return token, nil
@@ -553,6 +579,12 @@ func (t *Store) list(ctx context.Context, options *metav1.ListOptions) (*ext.Tok
 	if err != nil {
 		return nil, err
 	}
+	if !isAdmin {
+		// the revokers see the tokens of all users, to find the ones to revoke
+		if isAdmin, err = t.auth.CanRevoke(ctx, &t.SystemStore); err != nil {
+			return nil, err
+		}
+	}
 
 	return t.SystemStore.list(isAdmin, userName, t.auth.SessionID(ctx), options)
 }
@@ -955,6 +987,7 @@ type hashHandler interface {
 type authHandler interface {
 	SessionID(ctx context.Context) string
 	UserName(ctx context.Context, store *SystemStore) (string, bool, bool, error)
+	CanRevoke(ctx context.Context, store *SystemStore) (bool, error)
 }
 
 // Standard implementations for the above interfaces.
@@ -1036,6 +1069,28 @@ func (tp *tokenAuth) UserName(ctx context.Context, store *SystemStore) (string,
 	return userName, isAdmin, isRancherUser, nil
 }
 
+// CanRevoke hides the details of checking whether the user of the request
+// context is allowed to see and delete the tokens of other users, with the
+// custom revoke verb on tokens. It doesn't allow to create or update them.
+func (tp *tokenAuth) CanRevoke(ctx context.Context, store *SystemStore) (bool, error) {
+	userInfo, ok := request.UserFrom(ctx)
+	if !ok {
+		return false, apierrors.NewInternalError(fmt.Errorf("context has no user info"))
+	}
+
+	decision, _, err := store.authorizer.Authorize(ctx, &authorizer.AttributesRecord{
+		User:            userInfo,
+		Verb:            "revoke",
+		APIGroup:        GVR.Group,
+		Resource:        GVR.Resource,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return false, err
+	}
+	return decision == authorizer.DecisionAllow, nil
+}
+
 // SessionID hides the details of extracting the name of the authenticated token
 // governing the current session from the request context, for a a store. It
 // exists purely to allow unit testing to intercept and mock responses.  It also
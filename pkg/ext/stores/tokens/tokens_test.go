@@ -251,6 +251,7 @@ func Test_Store_Delete(t *testing.T) {
 		auth.EXPECT().SessionID(gomock.Any()).Return("")
 		auth.EXPECT().UserName(gomock.Any(), gomock.Any()).
 			Return("lkajdl/ksjlkds", false, true, nil)
+		auth.EXPECT().CanRevoke(gomock.Any(), gomock.Any()).Return(false, nil)
 		users.EXPECT().Cache().Return(nil)
 		secrets.EXPECT().Cache().Return(scache)
 		scache.EXPECT().
@@ -264,6 +265,33 @@ func Test_Store_Delete(t *testing.T) {
 		assert.Equal(t, bogusNotFoundError, err)
 	})
 
+	t.Run("not owned, revoker, ok", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+		scache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+		users := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+		auth := NewMockauthHandler(ctrl)
+
+		auth.EXPECT().SessionID(gomock.Any()).Return("")
+		auth.EXPECT().UserName(gomock.Any(), gomock.Any()).
+			Return("lkajdl/ksjlkds", false, true, nil).Times(2)
+		auth.EXPECT().CanRevoke(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+		users.EXPECT().Cache().Return(nil)
+		secrets.EXPECT().Cache().Return(scache)
+		scache.EXPECT().
+			Get("cattle-tokens", "bogus").
+			Return(&properSecret, nil)
+		secrets.EXPECT().
+			Delete("cattle-tokens", "bogus", gomock.Any()).
+			Return(nil)
+
+		store := New(nil, nil, secrets, users, nil, nil, nil, auth)
+		_, ok, err := store.Delete(context.TODO(), "bogus", nil, &metav1.DeleteOptions{})
+
+		assert.True(t, ok)
+		assert.Nil(t, err)
+	})
+
 	t.Run("ok", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		secrets := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
@@ -305,6 +333,7 @@ func Test_Store_Get(t *testing.T) {
 		auth.EXPECT().SessionID(gomock.Any()).Return("")
 		auth.EXPECT().UserName(gomock.Any(), gomock.Any()).
 			Return("lkajdl/ksjlkds", false, true, nil)
+		auth.EXPECT().CanRevoke(gomock.Any(), gomock.Any()).Return(false, nil)
 		users.EXPECT().Cache().Return(nil)
 		secrets.EXPECT().Cache().Return(scache)
 		scache.EXPECT().
@@ -318,6 +347,30 @@ func Test_Store_Get(t *testing.T) {
 		assert.Nil(t, tok)
 	})
 
+	t.Run("not owned, revoker, ok", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
+		scache := fake.NewMockCacheInterface[*corev1.Secret](ctrl)
+		users := fake.NewMockNonNamespacedControllerInterface[*v3.User, *v3.UserList](ctrl)
+		auth := NewMockauthHandler(ctrl)
+
+		auth.EXPECT().SessionID(gomock.Any()).Return("")
+		auth.EXPECT().UserName(gomock.Any(), gomock.Any()).
+			Return("lkajdl/ksjlkds", false, true, nil)
+		auth.EXPECT().CanRevoke(gomock.Any(), gomock.Any()).Return(true, nil)
+		users.EXPECT().Cache().Return(nil)
+		secrets.EXPECT().Cache().Return(scache)
+		scache.EXPECT().
+			Get("cattle-tokens", "bogus").
+			Return(&properSecret, nil)
+
+		store := New(nil, nil, secrets, users, nil, nil, nil, auth)
+		tok, err := store.Get(context.TODO(), "bogus", &metav1.GetOptions{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, &properToken, tok)
+	})
+
 	t.Run("ok, not current", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		secrets := fake.NewMockControllerInterface[*corev1.Secret, *corev1.SecretList](ctrl)
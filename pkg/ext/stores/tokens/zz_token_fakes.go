@@ -115,6 +115,21 @@ func (m *MockauthHandler) EXPECT() *MockauthHandlerMockRecorder {
 	return m.recorder
 }
 
+// CanRevoke mocks base method.
+func (m *MockauthHandler) CanRevoke(ctx context.Context, store *SystemStore) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanRevoke", ctx, store)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanRevoke indicates an expected call of CanRevoke.
+func (mr *MockauthHandlerMockRecorder) CanRevoke(ctx, store any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanRevoke", reflect.TypeOf((*MockauthHandler)(nil).CanRevoke), ctx, store)
+}
+
 // SessionID mocks base method.
 func (m *MockauthHandler) SessionID(ctx context.Context) string {
 	m.ctrl.T.Helper()
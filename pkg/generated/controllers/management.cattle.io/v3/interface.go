@@ -1,5 +1,5 @@
 /*
-Copyright 2025 Rancher Labs, Inc.
+Copyright 2026 Rancher Labs, Inc.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -32,11 +32,17 @@ func init() {
 
 type Interface interface {
 	APIService() APIServiceController
+	AccessRequest() AccessRequestController
 	ActiveDirectoryProvider() ActiveDirectoryProviderController
+	AuditPolicy() AuditPolicyController
 	AuthConfig() AuthConfigController
+	AuthEvent() AuthEventController
 	AuthProvider() AuthProviderController
 	AuthToken() AuthTokenController
 	AzureADProvider() AzureADProviderController
+	BreakGlassAccount() BreakGlassAccountController
+	CASProvider() CASProviderController
+	ClientCertProvider() ClientCertProviderController
 	CloudCredential() CloudCredentialController
 	Cluster() ClusterController
 	ClusterProxyConfig() ClusterProxyConfigController
@@ -47,6 +53,7 @@ type Interface interface {
 	ComposeConfig() ComposeConfigController
 	DynamicSchema() DynamicSchemaController
 	EtcdBackup() EtcdBackupController
+	ExternalAuthProvider() ExternalAuthProviderController
 	Feature() FeatureController
 	FleetWorkspace() FleetWorkspaceController
 	FreeIpaProvider() FreeIpaProviderController
@@ -57,28 +64,39 @@ type Interface interface {
 	GoogleOAuthProvider() GoogleOAuthProviderController
 	Group() GroupController
 	GroupMember() GroupMemberController
+	GroupMembershipRule() GroupMembershipRuleController
+	JITProvisioningPolicy() JITProvisioningPolicyController
+	KerberosProvider() KerberosProviderController
 	KontainerDriver() KontainerDriverController
 	LocalProvider() LocalProviderController
 	ManagedChart() ManagedChartController
+	MultiClusterRoleTemplateBinding() MultiClusterRoleTemplateBindingController
 	Node() NodeController
 	NodeDriver() NodeDriverController
 	NodePool() NodePoolController
 	NodeTemplate() NodeTemplateController
 	OIDCProvider() OIDCProviderController
 	OpenLdapProvider() OpenLdapProviderController
+	Organization() OrganizationController
+	OrphanedBindingReport() OrphanedBindingReportController
 	PodSecurityAdmissionConfigurationTemplate() PodSecurityAdmissionConfigurationTemplateController
 	Preference() PreferenceController
 	Principal() PrincipalController
+	PrincipalMetadata() PrincipalMetadataController
 	Project() ProjectController
 	ProjectNetworkPolicy() ProjectNetworkPolicyController
 	ProjectRoleTemplateBinding() ProjectRoleTemplateBindingController
+	RBACDriftReport() RBACDriftReportController
 	RancherUserNotification() RancherUserNotificationController
+	RecertificationCampaign() RecertificationCampaignController
 	RkeAddon() RkeAddonController
 	RkeK8sServiceOption() RkeK8sServiceOptionController
 	RkeK8sSystemImage() RkeK8sSystemImageController
 	RoleTemplate() RoleTemplateController
+	RoleUsageReport() RoleUsageReportController
 	SamlProvider() SamlProviderController
 	SamlToken() SamlTokenController
+	SecurityEvent() SecurityEventController
 	Setting() SettingController
 	Token() TokenController
 	User() UserController
@@ -99,14 +117,26 @@ func (v *version) APIService() APIServiceController {
 	return generic.NewNonNamespacedController[*v3.APIService, *v3.APIServiceList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "APIService"}, "apiservices", v.controllerFactory)
 }
 
+func (v *version) AccessRequest() AccessRequestController {
+	return generic.NewNonNamespacedController[*v3.AccessRequest, *v3.AccessRequestList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AccessRequest"}, "accessrequests", v.controllerFactory)
+}
+
 func (v *version) ActiveDirectoryProvider() ActiveDirectoryProviderController {
 	return generic.NewNonNamespacedController[*v3.ActiveDirectoryProvider, *v3.ActiveDirectoryProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ActiveDirectoryProvider"}, "activedirectoryproviders", v.controllerFactory)
 }
 
+func (v *version) AuditPolicy() AuditPolicyController {
+	return generic.NewNonNamespacedController[*v3.AuditPolicy, *v3.AuditPolicyList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AuditPolicy"}, "auditpolicies", v.controllerFactory)
+}
+
 func (v *version) AuthConfig() AuthConfigController {
 	return generic.NewNonNamespacedController[*v3.AuthConfig, *v3.AuthConfigList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AuthConfig"}, "authconfigs", v.controllerFactory)
 }
 
+func (v *version) AuthEvent() AuthEventController {
+	return generic.NewNonNamespacedController[*v3.AuthEvent, *v3.AuthEventList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AuthEvent"}, "authevents", v.controllerFactory)
+}
+
 func (v *version) AuthProvider() AuthProviderController {
 	return generic.NewNonNamespacedController[*v3.AuthProvider, *v3.AuthProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AuthProvider"}, "authproviders", v.controllerFactory)
 }
@@ -119,6 +149,18 @@ func (v *version) AzureADProvider() AzureADProviderController {
 	return generic.NewNonNamespacedController[*v3.AzureADProvider, *v3.AzureADProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AzureADProvider"}, "azureadproviders", v.controllerFactory)
 }
 
+func (v *version) BreakGlassAccount() BreakGlassAccountController {
+	return generic.NewNonNamespacedController[*v3.BreakGlassAccount, *v3.BreakGlassAccountList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "BreakGlassAccount"}, "breakglassaccounts", v.controllerFactory)
+}
+
+func (v *version) CASProvider() CASProviderController {
+	return generic.NewNonNamespacedController[*v3.CASProvider, *v3.CASProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "CASProvider"}, "casproviders", v.controllerFactory)
+}
+
+func (v *version) ClientCertProvider() ClientCertProviderController {
+	return generic.NewNonNamespacedController[*v3.ClientCertProvider, *v3.ClientCertProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ClientCertProvider"}, "clientcertproviders", v.controllerFactory)
+}
+
 func (v *version) CloudCredential() CloudCredentialController {
 	return generic.NewController[*v3.CloudCredential, *v3.CloudCredentialList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "CloudCredential"}, "cloudcredentials", true, v.controllerFactory)
 }
@@ -159,6 +201,10 @@ func (v *version) EtcdBackup() EtcdBackupController {
 	return generic.NewController[*v3.EtcdBackup, *v3.EtcdBackupList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "EtcdBackup"}, "etcdbackups", true, v.controllerFactory)
 }
 
+func (v *version) ExternalAuthProvider() ExternalAuthProviderController {
+	return generic.NewNonNamespacedController[*v3.ExternalAuthProvider, *v3.ExternalAuthProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ExternalAuthProvider"}, "externalauthproviders", v.controllerFactory)
+}
+
 func (v *version) Feature() FeatureController {
 	return generic.NewNonNamespacedController[*v3.Feature, *v3.FeatureList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Feature"}, "features", v.controllerFactory)
 }
@@ -199,6 +245,18 @@ func (v *version) GroupMember() GroupMemberController {
 	return generic.NewNonNamespacedController[*v3.GroupMember, *v3.GroupMemberList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "GroupMember"}, "groupmembers", v.controllerFactory)
 }
 
+func (v *version) GroupMembershipRule() GroupMembershipRuleController {
+	return generic.NewNonNamespacedController[*v3.GroupMembershipRule, *v3.GroupMembershipRuleList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "GroupMembershipRule"}, "groupmembershiprules", v.controllerFactory)
+}
+
+func (v *version) JITProvisioningPolicy() JITProvisioningPolicyController {
+	return generic.NewNonNamespacedController[*v3.JITProvisioningPolicy, *v3.JITProvisioningPolicyList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "JITProvisioningPolicy"}, "jitprovisioningpolicies", v.controllerFactory)
+}
+
+func (v *version) KerberosProvider() KerberosProviderController {
+	return generic.NewNonNamespacedController[*v3.KerberosProvider, *v3.KerberosProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "KerberosProvider"}, "kerberosproviders", v.controllerFactory)
+}
+
 func (v *version) KontainerDriver() KontainerDriverController {
 	return generic.NewNonNamespacedController[*v3.KontainerDriver, *v3.KontainerDriverList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "KontainerDriver"}, "kontainerdrivers", v.controllerFactory)
 }
@@ -211,6 +269,10 @@ func (v *version) ManagedChart() ManagedChartController {
 	return generic.NewController[*v3.ManagedChart, *v3.ManagedChartList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ManagedChart"}, "managedcharts", true, v.controllerFactory)
 }
 
+func (v *version) MultiClusterRoleTemplateBinding() MultiClusterRoleTemplateBindingController {
+	return generic.NewNonNamespacedController[*v3.MultiClusterRoleTemplateBinding, *v3.MultiClusterRoleTemplateBindingList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "MultiClusterRoleTemplateBinding"}, "multiclusterroletemplatebindings", v.controllerFactory)
+}
+
 func (v *version) Node() NodeController {
 	return generic.NewController[*v3.Node, *v3.NodeList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Node"}, "nodes", true, v.controllerFactory)
 }
@@ -235,6 +297,14 @@ func (v *version) OpenLdapProvider() OpenLdapProviderController {
 	return generic.NewNonNamespacedController[*v3.OpenLdapProvider, *v3.OpenLdapProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "OpenLdapProvider"}, "openldapproviders", v.controllerFactory)
 }
 
+func (v *version) Organization() OrganizationController {
+	return generic.NewNonNamespacedController[*v3.Organization, *v3.OrganizationList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Organization"}, "organizations", v.controllerFactory)
+}
+
+func (v *version) OrphanedBindingReport() OrphanedBindingReportController {
+	return generic.NewNonNamespacedController[*v3.OrphanedBindingReport, *v3.OrphanedBindingReportList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "OrphanedBindingReport"}, "orphanedbindingreports", v.controllerFactory)
+}
+
 func (v *version) PodSecurityAdmissionConfigurationTemplate() PodSecurityAdmissionConfigurationTemplateController {
 	return generic.NewNonNamespacedController[*v3.PodSecurityAdmissionConfigurationTemplate, *v3.PodSecurityAdmissionConfigurationTemplateList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "PodSecurityAdmissionConfigurationTemplate"}, "podsecurityadmissionconfigurationtemplates", v.controllerFactory)
 }
@@ -247,6 +317,10 @@ func (v *version) Principal() PrincipalController {
 	return generic.NewNonNamespacedController[*v3.Principal, *v3.PrincipalList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Principal"}, "principals", v.controllerFactory)
 }
 
+func (v *version) PrincipalMetadata() PrincipalMetadataController {
+	return generic.NewNonNamespacedController[*v3.PrincipalMetadata, *v3.PrincipalMetadataList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "PrincipalMetadata"}, "principalmetadatas", v.controllerFactory)
+}
+
 func (v *version) Project() ProjectController {
 	return generic.NewController[*v3.Project, *v3.ProjectList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Project"}, "projects", true, v.controllerFactory)
 }
@@ -259,10 +333,18 @@ func (v *version) ProjectRoleTemplateBinding() ProjectRoleTemplateBindingControl
 	return generic.NewController[*v3.ProjectRoleTemplateBinding, *v3.ProjectRoleTemplateBindingList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ProjectRoleTemplateBinding"}, "projectroletemplatebindings", true, v.controllerFactory)
 }
 
+func (v *version) RBACDriftReport() RBACDriftReportController {
+	return generic.NewNonNamespacedController[*v3.RBACDriftReport, *v3.RBACDriftReportList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RBACDriftReport"}, "rbacdriftreports", v.controllerFactory)
+}
+
 func (v *version) RancherUserNotification() RancherUserNotificationController {
 	return generic.NewNonNamespacedController[*v3.RancherUserNotification, *v3.RancherUserNotificationList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RancherUserNotification"}, "rancherusernotifications", v.controllerFactory)
 }
 
+func (v *version) RecertificationCampaign() RecertificationCampaignController {
+	return generic.NewNonNamespacedController[*v3.RecertificationCampaign, *v3.RecertificationCampaignList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RecertificationCampaign"}, "recertificationcampaigns", v.controllerFactory)
+}
+
 func (v *version) RkeAddon() RkeAddonController {
 	return generic.NewController[*v3.RkeAddon, *v3.RkeAddonList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RkeAddon"}, "rkeaddons", true, v.controllerFactory)
 }
@@ -279,6 +361,10 @@ func (v *version) RoleTemplate() RoleTemplateController {
 	return generic.NewNonNamespacedController[*v3.RoleTemplate, *v3.RoleTemplateList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RoleTemplate"}, "roletemplates", v.controllerFactory)
 }
 
+func (v *version) RoleUsageReport() RoleUsageReportController {
+	return generic.NewNonNamespacedController[*v3.RoleUsageReport, *v3.RoleUsageReportList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RoleUsageReport"}, "roleusagereports", v.controllerFactory)
+}
+
 func (v *version) SamlProvider() SamlProviderController {
 	return generic.NewNonNamespacedController[*v3.SamlProvider, *v3.SamlProviderList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "SamlProvider"}, "samlproviders", v.controllerFactory)
 }
@@ -287,6 +373,10 @@ func (v *version) SamlToken() SamlTokenController {
 	return generic.NewNonNamespacedController[*v3.SamlToken, *v3.SamlTokenList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "SamlToken"}, "samltokens", v.controllerFactory)
 }
 
+func (v *version) SecurityEvent() SecurityEventController {
+	return generic.NewNonNamespacedController[*v3.SecurityEvent, *v3.SecurityEventList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "SecurityEvent"}, "securityevents", v.controllerFactory)
+}
+
 func (v *version) Setting() SettingController {
 	return generic.NewNonNamespacedController[*v3.Setting, *v3.SettingList](schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "Setting"}, "settings", v.controllerFactory)
 }
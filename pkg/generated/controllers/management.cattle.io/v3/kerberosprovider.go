@@ -0,0 +1,39 @@
+/*
+Copyright 2026 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by main. DO NOT EDIT.
+
+package v3
+
+import (
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic"
+)
+
+// KerberosProviderController interface for managing KerberosProvider resources.
+type KerberosProviderController interface {
+	generic.NonNamespacedControllerInterface[*v3.KerberosProvider, *v3.KerberosProviderList]
+}
+
+// KerberosProviderClient interface for managing KerberosProvider resources in Kubernetes.
+type KerberosProviderClient interface {
+	generic.NonNamespacedClientInterface[*v3.KerberosProvider, *v3.KerberosProviderList]
+}
+
+// KerberosProviderCache interface for retrieving KerberosProvider resources in memory.
+type KerberosProviderCache interface {
+	generic.NonNamespacedCacheInterface[*v3.KerberosProvider]
+}
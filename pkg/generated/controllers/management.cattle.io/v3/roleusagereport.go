@@ -0,0 +1,208 @@
+/*
+Copyright 2026 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by main. DO NOT EDIT.
+
+package v3
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/apply"
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	"github.com/rancher/wrangler/v3/pkg/kv"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RoleUsageReportController interface for managing RoleUsageReport resources.
+type RoleUsageReportController interface {
+	generic.NonNamespacedControllerInterface[*v3.RoleUsageReport, *v3.RoleUsageReportList]
+}
+
+// RoleUsageReportClient interface for managing RoleUsageReport resources in Kubernetes.
+type RoleUsageReportClient interface {
+	generic.NonNamespacedClientInterface[*v3.RoleUsageReport, *v3.RoleUsageReportList]
+}
+
+// RoleUsageReportCache interface for retrieving RoleUsageReport resources in memory.
+type RoleUsageReportCache interface {
+	generic.NonNamespacedCacheInterface[*v3.RoleUsageReport]
+}
+
+// RoleUsageReportStatusHandler is executed for every added or modified RoleUsageReport. Should return the new status to be updated
+type RoleUsageReportStatusHandler func(obj *v3.RoleUsageReport, status v3.RoleUsageReportStatus) (v3.RoleUsageReportStatus, error)
+
+// RoleUsageReportGeneratingHandler is the top-level handler that is executed for every RoleUsageReport event. It extends RoleUsageReportStatusHandler by a returning a slice of child objects to be passed to apply.Apply
+type RoleUsageReportGeneratingHandler func(obj *v3.RoleUsageReport, status v3.RoleUsageReportStatus) ([]runtime.Object, v3.RoleUsageReportStatus, error)
+
+// RegisterRoleUsageReportStatusHandler configures a RoleUsageReportController to execute a RoleUsageReportStatusHandler for every events observed.
+// If a non-empty condition is provided, it will be updated in the status conditions for every handler execution
+func RegisterRoleUsageReportStatusHandler(ctx context.Context, controller RoleUsageReportController, condition condition.Cond, name string, handler RoleUsageReportStatusHandler) {
+	statusHandler := &roleUsageReportStatusHandler{
+		client:    controller,
+		condition: condition,
+		handler:   handler,
+	}
+	controller.AddGenericHandler(ctx, name, generic.FromObjectHandlerToHandler(statusHandler.sync))
+}
+
+// RegisterRoleUsageReportGeneratingHandler configures a RoleUsageReportController to execute a RoleUsageReportGeneratingHandler for every events observed, passing the returned objects to the provided apply.Apply.
+// If a non-empty condition is provided, it will be updated in the status conditions for every handler execution
+func RegisterRoleUsageReportGeneratingHandler(ctx context.Context, controller RoleUsageReportController, apply apply.Apply,
+	condition condition.Cond, name string, handler RoleUsageReportGeneratingHandler, opts *generic.GeneratingHandlerOptions) {
+	statusHandler := &roleUsageReportGeneratingHandler{
+		RoleUsageReportGeneratingHandler: handler,
+		apply:                            apply,
+		name:                             name,
+		gvk:                              controller.GroupVersionKind(),
+	}
+	if opts != nil {
+		statusHandler.opts = *opts
+	}
+	controller.OnChange(ctx, name, statusHandler.Remove)
+	RegisterRoleUsageReportStatusHandler(ctx, controller, condition, name, statusHandler.Handle)
+}
+
+type roleUsageReportStatusHandler struct {
+	client    RoleUsageReportClient
+	condition condition.Cond
+	handler   RoleUsageReportStatusHandler
+}
+
+// sync is executed on every resource addition or modification. Executes the configured handlers and sends the updated status to the Kubernetes API
+func (a *roleUsageReportStatusHandler) sync(key string, obj *v3.RoleUsageReport) (*v3.RoleUsageReport, error) {
+	if obj == nil {
+		return obj, nil
+	}
+
+	origStatus := obj.Status.DeepCopy()
+	obj = obj.DeepCopy()
+	newStatus, err := a.handler(obj, obj.Status)
+	if err != nil {
+		// Revert to old status on error
+		newStatus = *origStatus.DeepCopy()
+	}
+
+	if a.condition != "" {
+		if errors.IsConflict(err) {
+			a.condition.SetError(&newStatus, "", nil)
+		} else {
+			a.condition.SetError(&newStatus, "", err)
+		}
+	}
+	if !equality.Semantic.DeepEqual(origStatus, &newStatus) {
+		if a.condition != "" {
+			// Since status has changed, update the lastUpdatedTime
+			a.condition.LastUpdated(&newStatus, time.Now().UTC().Format(time.RFC3339))
+		}
+
+		var newErr error
+		obj.Status = newStatus
+		newObj, newErr := a.client.UpdateStatus(obj)
+		if err == nil {
+			err = newErr
+		}
+		if newErr == nil {
+			obj = newObj
+		}
+	}
+	return obj, err
+}
+
+type roleUsageReportGeneratingHandler struct {
+	RoleUsageReportGeneratingHandler
+	apply apply.Apply
+	opts  generic.GeneratingHandlerOptions
+	gvk   schema.GroupVersionKind
+	name  string
+	seen  sync.Map
+}
+
+// Remove handles the observed deletion of a resource, cascade deleting every associated resource previously applied
+func (a *roleUsageReportGeneratingHandler) Remove(key string, obj *v3.RoleUsageReport) (*v3.RoleUsageReport, error) {
+	if obj != nil {
+		return obj, nil
+	}
+
+	obj = &v3.RoleUsageReport{}
+	obj.Namespace, obj.Name = kv.RSplit(key, "/")
+	obj.SetGroupVersionKind(a.gvk)
+
+	if a.opts.UniqueApplyForResourceVersion {
+		a.seen.Delete(key)
+	}
+
+	return nil, generic.ConfigureApplyForObject(a.apply, obj, &a.opts).
+		WithOwner(obj).
+		WithSetID(a.name).
+		ApplyObjects()
+}
+
+// Handle executes the configured RoleUsageReportGeneratingHandler and pass the resulting objects to apply.Apply, finally returning the new status of the resource
+func (a *roleUsageReportGeneratingHandler) Handle(obj *v3.RoleUsageReport, status v3.RoleUsageReportStatus) (v3.RoleUsageReportStatus, error) {
+	if !obj.DeletionTimestamp.IsZero() {
+		return status, nil
+	}
+
+	objs, newStatus, err := a.RoleUsageReportGeneratingHandler(obj, status)
+	if err != nil {
+		return newStatus, err
+	}
+	if !a.isNewResourceVersion(obj) {
+		return newStatus, nil
+	}
+
+	err = generic.ConfigureApplyForObject(a.apply, obj, &a.opts).
+		WithOwner(obj).
+		WithSetID(a.name).
+		ApplyObjects(objs...)
+	if err != nil {
+		return newStatus, err
+	}
+	a.storeResourceVersion(obj)
+	return newStatus, nil
+}
+
+// isNewResourceVersion detects if a specific resource version was already successfully processed.
+// Only used if UniqueApplyForResourceVersion is set in generic.GeneratingHandlerOptions
+func (a *roleUsageReportGeneratingHandler) isNewResourceVersion(obj *v3.RoleUsageReport) bool {
+	if !a.opts.UniqueApplyForResourceVersion {
+		return true
+	}
+
+	// Apply once per resource version
+	key := obj.Namespace + "/" + obj.Name
+	previous, ok := a.seen.Load(key)
+	return !ok || previous != obj.ResourceVersion
+}
+
+// storeResourceVersion keeps track of the latest resource version of an object for which Apply was executed
+// Only used if UniqueApplyForResourceVersion is set in generic.GeneratingHandlerOptions
+func (a *roleUsageReportGeneratingHandler) storeResourceVersion(obj *v3.RoleUsageReport) {
+	if !a.opts.UniqueApplyForResourceVersion {
+		return
+	}
+
+	key := obj.Namespace + "/" + obj.Name
+	a.seen.Store(key, obj.ResourceVersion)
+}
@@ -53,6 +53,7 @@
 		"cf-ray":                  true,
 		"impersonate-user":        true,
 		"impersonate-group":       true,
+		"impersonate-uid":         true,
 	}
 )
 
@@ -168,7 +169,7 @@ func (p *proxy) proxy(req *http.Request) error {
 	auth := req.Header.Get(APIAuth)
 	cAuth := req.Header.Get(CattleAuth)
 	for name, value := range req.Header {
-		if badHeaders[strings.ToLower(name)] {
+		if isBadHeader(name) {
 			continue
 		}
 
@@ -309,6 +310,13 @@ func (p *proxy) checkAccessToV3ClusterWithID(req *http.Request, user user.Info,
 	return decision, err
 }
 
+// isBadHeader returns true if the header must not be forwarded to the destination,
+// including the impersonation headers, extras included, which only apply to Rancher.
+func isBadHeader(name string) bool {
+	name = strings.ToLower(name)
+	return badHeaders[name] || strings.HasPrefix(name, "impersonate-extra-")
+}
+
 func replaceCookies(req *http.Request) {
 	// Do not forward rancher cookies to third parties
 	req.Header.Del(Cookie)
@@ -222,3 +222,24 @@ type test struct {
 			))
 	}
 }
+
+func TestIsBadHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "Impersonate-User", want: true},
+		{name: "Impersonate-Group", want: true},
+		{name: "Impersonate-Uid", want: true},
+		{name: "Impersonate-Extra-Principalid", want: true},
+		{name: "impersonate-extra-username", want: true},
+		{name: "X-Api-Auth-Header", want: true},
+		{name: "Accept", want: false},
+		{name: "Authorization", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isBadHeader(tt.name))
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Package mail sends the emails of Rancher through the SMTP server of the smtp-server setting, secured as set by
+// smtp-tls. The credentials are only sent over TLS, unless the server is local. The messages are rendered from
+// templates, which can be overridden with smtp-templates.
+package mail
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	"github.com/rancher/rancher/pkg/settings"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// CredentialsSecretName is the name of the secret of the cattle-global-data namespace holding the credentials of
+	// the SMTP server.
+	CredentialsSecretName = "smtp-credentials"
+	// UsernameField and PasswordField are the fields of the secret holding the credentials.
+	UsernameField = "username"
+	PasswordField = "password"
+
+	// The values of smtp-tls.
+	startTLS         = "starttls"
+	startTLSRequired = "starttls-required"
+	implicitTLS      = "tls"
+
+	// timeout bounds the whole exchange with the SMTP server.
+	timeout = 30 * time.Second
+)
+
+var (
+	// ErrNotConfigured is returned when the SMTP server or the sender address isn't set.
+	ErrNotConfigured = errors.New("smtp-server and smtp-from must be set to send emails")
+
+	errNoSTARTTLS = errors.New("the SMTP server doesn't support STARTTLS")
+)
+
+// Sender sends emails with the SMTP server of the settings.
+type Sender struct {
+	secretLister v1.SecretLister
+	sendMail     func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	now          func() time.Time
+}
+
+// NewSender returns a Sender reading the credentials of the SMTP server with the lister.
+func NewSender(secretLister v1.SecretLister) *Sender {
+	s := &Sender{
+		secretLister: secretLister,
+		now:          time.Now,
+	}
+	s.sendMail = s.send
+	return s
+}
+
+// Configured tells whether the SMTP server and the sender address are set.
+func Configured() bool {
+	return settings.SMTPServer.Get() != "" && settings.SMTPFrom.Get() != ""
+}
+
+// SendMessage renders the message with the data and sends it to the address.
+func (s *Sender) SendMessage(to, name string, data interface{}) error {
+	subject, body, err := Render(name, data)
+	if err != nil {
+		return err
+	}
+	return s.Send(to, subject, body)
+}
+
+// Send sends a plain text email to the address.
+func (s *Sender) Send(to, subject, body string) error {
+	if !Configured() {
+		return ErrNotConfigured
+	}
+	server, from := settings.SMTPServer.Get(), settings.SMTPFrom.Get()
+	sender, err := netmail.ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("invalid smtp-from address: %w", err)
+	}
+	recipient, err := netmail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+	auth, err := s.auth(server)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendMail(server, auth, sender.Address, []string{recipient.Address}, s.message(sender, recipient, subject, body)); err != nil {
+		return fmt.Errorf("sending an email with %s: %w", server, err)
+	}
+	return nil
+}
+
+// auth returns the authentication of the SMTP server, nil if the credentials secret doesn't exist.
+func (s *Sender) auth(server string) (smtp.Auth, error) {
+	secret, err := s.secretLister.Get(common.SecretsNamespace, CredentialsSecretName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting the SMTP credentials: %w", err)
+	}
+	username := string(secret.Data[UsernameField])
+	if username == "" {
+		return nil, nil
+	}
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp-server %q: %w", server, err)
+	}
+	return smtp.PlainAuth("", username, string(secret.Data[PasswordField]), host), nil
+}
+
+// send sends the message with the SMTP server, over a connection secured as set by smtp-tls.
+func (s *Sender) send(server string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	mode := settings.SMTPTLS.Get()
+	if mode != startTLS && mode != startTLSRequired && mode != implicitTLS {
+		return fmt.Errorf("invalid smtp-tls %q", mode)
+	}
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return fmt.Errorf("invalid smtp-server %q: %w", server, err)
+	}
+	tlsConfig, err := tlsConfig(host)
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	if mode == implicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", server, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", server)
+	}
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(s.now().Add(timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if mode != implicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		} else if mode == startTLSRequired {
+			return errNoSTARTTLS
+		}
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// tlsConfig returns the TLS configuration verifying the SMTP server with the system CAs and those of smtp-ca-certs.
+func tlsConfig(host string) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+	if caCerts := settings.SMTPCACerts.Get(); caCerts != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(caCerts)) {
+			return nil, errors.New("invalid smtp-ca-certs: no PEM encoded certificate")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// message formats the email. The addresses are formatted by net/mail and the subject is encoded, so that their values
+// can't add headers.
+func (s *Sender) message(from, to *netmail.Address, subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from.String() + "\r\n")
+	b.WriteString("To: " + to.String() + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + s.now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
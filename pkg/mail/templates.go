@@ -0,0 +1,235 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/rancher/rancher/pkg/settings"
+)
+
+// The names of the messages, by which their templates are overridden in smtp-templates.
+const (
+	PasswordResetMessage       = "password-reset"
+	EmailVerificationMessage   = "email-verification"
+	NewDeviceLoginMessage      = "new-device-login"
+	MFAChangeMessage           = "mfa-change"
+	TokenExpiringMessage       = "token-expiring"
+	BreakGlassActivatedMessage = "break-glass-activated"
+	BreakGlassEndedMessage     = "break-glass-ended"
+	LoginAnomalyMessage        = "login-anomaly"
+	FailedLoginsMessage        = "failed-logins"
+)
+
+// Template holds the subject and body templates of a message.
+type Template struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// PasswordResetData is the data of the password-reset message.
+type PasswordResetData struct {
+	Username  string
+	Link      string
+	ExpiresAt string
+}
+
+// EmailVerificationData is the data of the email-verification message.
+type EmailVerificationData struct {
+	Username  string
+	Link      string
+	ExpiresAt string
+}
+
+// NewDeviceLoginData is the data of the new-device-login message.
+type NewDeviceLoginData struct {
+	Username  string
+	Provider  string
+	Time      string
+	Address   string
+	UserAgent string
+}
+
+// MFAChangeData is the data of the mfa-change message.
+type MFAChangeData struct {
+	Username string
+	// Change describes what changed, e.g. "A security key was registered".
+	Change string
+	Time   string
+}
+
+// TokenExpiringData is the data of the token-expiring message.
+type TokenExpiringData struct {
+	Username    string
+	TokenName   string
+	Description string
+	ExpiresAt   string
+}
+
+// BreakGlassData is the data of the break-glass-activated and break-glass-ended messages.
+type BreakGlassData struct {
+	Account   string
+	Username  string
+	Role      string
+	Reason    string
+	Address   string
+	Time      string
+	ExpiresAt string
+}
+
+// LoginAnomalyData is the data of the login-anomaly message.
+type LoginAnomalyData struct {
+	Username string
+	Provider string
+	Time     string
+	Address  string
+	// Anomaly describes what is unusual about the login, e.g. "It's the first login from country FR".
+	Anomaly   string
+	UserAgent string
+}
+
+// FailedLoginsData is the data of the failed-logins message.
+type FailedLoginsData struct {
+	Username string
+	// Count is how many logins failed since the previous successful login.
+	Count    int
+	LastTime string
+	Address  string
+}
+
+var defaultTemplates = map[string]Template{
+	PasswordResetMessage: {
+		Subject: "Reset your Rancher password",
+		Body: `A password reset was requested for your Rancher user {{.Username}}.
+
+Open the following link to choose a new password. It expires at {{.ExpiresAt}}.
+
+{{.Link}}
+
+If you didn't request it, you can ignore this email, your password is unchanged.
+`,
+	},
+	EmailVerificationMessage: {
+		Subject: "Verify your email address for Rancher",
+		Body: `This email address was set for the Rancher user {{.Username}}.
+
+Open the following link to verify it. It expires at {{.ExpiresAt}}.
+
+{{.Link}}
+
+If you don't know this user, you can ignore this email.
+`,
+	},
+	NewDeviceLoginMessage: {
+		Subject: "New login to your Rancher account",
+		Body: `Your Rancher user {{.Username}} logged in from a new device at {{.Time}}.
+
+Provider: {{.Provider}}
+Address: {{.Address}}
+Browser or client: {{.UserAgent}}
+
+If it wasn't you, change your password and revoke your sessions.
+`,
+	},
+	MFAChangeMessage: {
+		Subject: "The second factor of your Rancher account changed",
+		Body: `{{.Change}} for your Rancher user {{.Username}} at {{.Time}}.
+
+If it wasn't you, contact your Rancher administrator.
+`,
+	},
+	TokenExpiringMessage: {
+		Subject: "Your Rancher API key {{.TokenName}} expires soon",
+		Body: `The API key {{.TokenName}}{{with .Description}} ({{.}}){{end}} of your Rancher user {{.Username}} expires at {{.ExpiresAt}}.
+
+Create a new API key to replace it before then.
+`,
+	},
+	BreakGlassActivatedMessage: {
+		Subject: "Break glass account {{.Account}} of Rancher activated",
+		Body: `The break glass account {{.Account}} was activated at {{.Time}}, from {{.Address}}.
+
+Its user {{.Username}} is granted the global role {{.Role}} until {{.ExpiresAt}}.
+
+Reason: {{.Reason}}
+
+If the activation isn't expected, end it and investigate.
+`,
+	},
+	BreakGlassEndedMessage: {
+		Subject: "Break glass account {{.Account}} of Rancher ended",
+		Body: `The activation of the break glass account {{.Account}} ended at {{.Time}}.
+
+Its user {{.Username}} is disabled and no longer granted the global role {{.Role}}. Seal a new credential for the account.
+
+Reason of the activation: {{.Reason}}
+`,
+	},
+	LoginAnomalyMessage: {
+		Subject: "Unusual login to your Rancher account",
+		Body: `Your Rancher user {{.Username}} logged in at {{.Time}} in an unusual way. {{.Anomaly}}.
+
+Provider: {{.Provider}}
+Address: {{.Address}}
+Browser or client: {{.UserAgent}}
+
+If it wasn't you, change your password and revoke your sessions.
+`,
+	},
+	FailedLoginsMessage: {
+		Subject: "Failed logins to your Rancher account",
+		Body: `Your Rancher user {{.Username}} just logged in. There were {{.Count}} failed login attempts since its previous login, the last one at {{.LastTime}}.
+
+Address of the last attempt: {{.Address}}
+
+If they weren't you, someone may be trying to guess your password. Change it if it's weak or reused.
+`,
+	},
+}
+
+// Render returns the subject and body of the message with the data, from its template of smtp-templates or its
+// default template.
+func Render(name string, data interface{}) (string, string, error) {
+	tmpl, ok := defaultTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown message %s", name)
+	}
+	if value := strings.TrimSpace(settings.SMTPTemplates.Get()); value != "" {
+		var overrides map[string]Template
+		if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+			return "", "", fmt.Errorf("invalid smtp-templates: %w", err)
+		}
+		if override, ok := overrides[name]; ok {
+			if override.Subject != "" {
+				tmpl.Subject = override.Subject
+			}
+			if override.Body != "" {
+				tmpl.Body = override.Body
+			}
+		}
+	}
+
+	subject, err := execute(name+" subject", tmpl.Subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := execute(name+" body", tmpl.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+	// The subject is a single header line.
+	return strings.Join(strings.Fields(subject), " "), body, nil
+}
+
+func execute(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template of the %s: %w", name, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("rendering the %s: %w", name, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	data := TokenExpiringData{
+		Username:    "alice",
+		TokenName:   "token-abc",
+		Description: "ci",
+		ExpiresAt:   "Thu, 02 May 2024 12:00:00 UTC",
+	}
+	subject, body, err := Render(TokenExpiringMessage, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Your Rancher API key token-abc expires soon", subject)
+	assert.Contains(t, body, "The API key token-abc (ci) of your Rancher user alice expires at Thu, 02 May 2024 12:00:00 UTC.")
+
+	_, _, err = Render("unknown", data)
+	assert.Error(t, err)
+
+	defer settings.SMTPTemplates.Set(settings.SMTPTemplates.Default)
+
+	// The overrides replace the subject or the body of the default templates.
+	require.NoError(t, settings.SMTPTemplates.Set(`{"token-expiring": {"subject": "Expiring:\n{{.TokenName}}"}}`))
+	subject, body, err = Render(TokenExpiringMessage, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Expiring: token-abc", subject)
+	assert.Contains(t, body, "The API key token-abc (ci)")
+
+	require.NoError(t, settings.SMTPTemplates.Set(`{"token-expiring": {"body": "{{.Unknown}}"}}`))
+	_, _, err = Render(TokenExpiringMessage, data)
+	assert.Error(t, err)
+
+	require.NoError(t, settings.SMTPTemplates.Set(`{"token-expiring": {"body": "{{.TokenName"}}`))
+	_, _, err = Render(TokenExpiringMessage, data)
+	assert.Error(t, err)
+
+	require.NoError(t, settings.SMTPTemplates.Set(`not json`))
+	_, _, err = Render(TokenExpiringMessage, data)
+	assert.Error(t, err)
+}
+
+func TestDefaultTemplates(t *testing.T) {
+	for name, data := range map[string]interface{}{
+		PasswordResetMessage:       PasswordResetData{},
+		EmailVerificationMessage:   EmailVerificationData{},
+		NewDeviceLoginMessage:      NewDeviceLoginData{},
+		MFAChangeMessage:           MFAChangeData{},
+		TokenExpiringMessage:       TokenExpiringData{},
+		BreakGlassActivatedMessage: BreakGlassData{},
+		BreakGlassEndedMessage:     BreakGlassData{},
+		LoginAnomalyMessage:        LoginAnomalyData{},
+		FailedLoginsMessage:        FailedLoginsData{},
+	} {
+		_, _, err := Render(name, data)
+		assert.NoError(t, err, name)
+	}
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	apiRateLimited = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "api",
+			Name:      "rate_limited_total",
+			Help:      "Number of requests rejected because the user or the token exceeded its API rate limit",
+		},
+		[]string{authScopeLabel},
+	)
+
+	apiRateLimitBuckets = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "api",
+			Name:      "rate_limit_buckets",
+			Help:      "Number of users or tokens whose API rate limit is tracked",
+		},
+	)
+)
+
+// IncAPIRateLimited counts a request rejected because of the rate limit of the scope.
+func IncAPIRateLimited(scope string) {
+	if prometheusMetrics {
+		apiRateLimited.With(prometheus.Labels{authScopeLabel: scope}).Inc()
+	}
+}
+
+// SetAPIRateLimitBuckets sets the number of users or tokens whose rate limit is tracked.
+func SetAPIRateLimitBuckets(count int) {
+	if prometheusMetrics {
+		apiRateLimitBuckets.Set(float64(count))
+	}
+}
@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	authProviderLabel  = "provider"
+	authScopeLabel     = "scope"
+	authOutcomeLabel   = "outcome"
+	authTypeLabel      = "type"
+	authResultLabel    = "result"
+	authCacheLabel     = "cache"
+	authOperationLabel = "operation"
+
+	// The results of the token validations, cache lookups and provider requests.
+	authResultValid   = "valid"
+	authResultInvalid = "invalid"
+	authResultHit     = "hit"
+	authResultMiss    = "miss"
+	authResultSuccess = "success"
+	authResultError   = "error"
+
+	authLogPrefix = "[prometheus-auth-metrics]"
+)
+
+var (
+	loginFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "auth",
+			Name:      "login_failures_total",
+			Help:      "Number of failed logins with a username and password",
+		},
+		[]string{authProviderLabel},
+	)
+
+	loginLockouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "auth",
+			Name:      "login_lockouts_total",
+			Help:      "Number of usernames and client addresses locked out after too many failed logins",
+		},
+		[]string{authScopeLabel},
+	)
+
+	loginThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "auth",
+			Name:      "login_throttled_total",
+			Help:      "Number of logins rejected because the username or the client address must wait or is locked out",
+		},
+		[]string{authScopeLabel},
+	)
+
+	logins = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "auth",
+			Name:      "logins_total",
+			Help:      "Number of logins, by provider and outcome: Succeeded, or the reason of the failure",
+		},
+		[]string{authProviderLabel, authOutcomeLabel},
+	)
+
+	tokenValidations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "auth",
+			Name:      "token_validations_total",
+			Help:      "Number of tokens of the requests validated, by type of token and result",
+		},
+		[]string{authTypeLabel, authResultLabel},
+	)
+
+	cacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "auth",
+			Name:      "cache_lookups_total",
+			Help:      "Number of lookups in the caches of the auth stack, by cache and result: hit or miss",
+		},
+		[]string{authCacheLabel, authResultLabel},
+	)
+
+	sessions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "auth",
+			Name:      "sessions",
+			Help:      "Number of enabled and unexpired login sessions, by provider",
+		},
+		[]string{authProviderLabel},
+	)
+
+	providerRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: "auth",
+			Name:      "provider_request_duration_seconds",
+			Help:      "Time taken by the requests to the auth providers, by provider, operation and result",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{authProviderLabel, authOperationLabel, authResultLabel},
+	)
+)
+
+// IncLoginFailures counts a failed login with the provider.
+func IncLoginFailures(provider string) {
+	if prometheusMetrics {
+		loginFailures.With(prometheus.Labels{authProviderLabel: provider}).Inc()
+	}
+}
+
+// IncLoginLockouts counts a lockout of a username or a client address, the scope.
+func IncLoginLockouts(scope string) {
+	if prometheusMetrics {
+		loginLockouts.With(prometheus.Labels{authScopeLabel: scope}).Inc()
+	}
+}
+
+// IncLoginThrottled counts a login rejected because of the delay or lockout of the scope.
+func IncLoginThrottled(scope string) {
+	if prometheusMetrics {
+		loginThrottled.With(prometheus.Labels{authScopeLabel: scope}).Inc()
+	}
+}
+
+// IncLogins counts a login with the provider, with its outcome: Succeeded, or the reason of the failure.
+func IncLogins(provider, outcome string) {
+	if prometheusMetrics {
+		logins.With(prometheus.Labels{authProviderLabel: provider, authOutcomeLabel: outcome}).Inc()
+	}
+}
+
+// IncTokenValidations counts a validation of the token of a request, of the type.
+func IncTokenValidations(tokenType string, valid bool) {
+	if !prometheusMetrics {
+		return
+	}
+	result := authResultValid
+	if !valid {
+		result = authResultInvalid
+	}
+	tokenValidations.With(prometheus.Labels{authTypeLabel: tokenType, authResultLabel: result}).Inc()
+}
+
+// IncAuthCacheLookups counts a lookup in the cache, a hit or a miss.
+func IncAuthCacheLookups(cache string, hit bool) {
+	if !prometheusMetrics {
+		return
+	}
+	result := authResultHit
+	if !hit {
+		result = authResultMiss
+	}
+	cacheLookups.With(prometheus.Labels{authCacheLabel: cache, authResultLabel: result}).Inc()
+}
+
+// ObserveAuthProviderRequest records a request to the provider for the operation, started at started.
+func ObserveAuthProviderRequest(provider, operation string, started time.Time, err error) {
+	if !prometheusMetrics {
+		return
+	}
+	result := authResultSuccess
+	if err != nil {
+		result = authResultError
+	}
+	providerRequestDuration.With(prometheus.Labels{
+		authProviderLabel:  provider,
+		authOperationLabel: operation,
+		authResultLabel:    result,
+	}).Observe(time.Since(started).Seconds())
+}
+
+type authMetrics struct {
+	tokenCache mgmtcontrollers.TokenCache
+}
+
+// collect periodically counts the login sessions: the enabled and unexpired tokens which aren't derived, e.g. API
+// keys.
+func (m *authMetrics) collect(ctx context.Context) {
+	for range ticker.Context(ctx, reportInterval) {
+		tokens, err := m.tokenCache.List(labels.Everything())
+		if err != nil {
+			logrus.Errorf("%s couldn't list Tokens: %v", authLogPrefix, err)
+			continue
+		}
+
+		now := time.Now()
+		counts := map[string]int{}
+		for _, token := range tokens {
+			if token.IsDerived || !token.GetIsEnabled() {
+				continue
+			}
+			if token.TTLMillis != 0 && !now.Before(token.CreationTimestamp.Add(time.Duration(token.TTLMillis)*time.Millisecond)) {
+				continue
+			}
+			counts[token.AuthProvider]++
+		}
+
+		sessions.Reset()
+		for provider, count := range counts {
+			sessions.With(prometheus.Labels{authProviderLabel: provider}).Set(float64(count))
+		}
+	}
+
+	logrus.Debugf("%s context cancelled, exiting", authLogPrefix)
+}
@@ -49,6 +49,28 @@ func Register(ctx context.Context, scaledContext *config.ScaledContext) {
 	prometheus.MustRegister(numNodes)
 	prometheus.MustRegister(numCores)
 
+	// login protection metrics
+	prometheus.MustRegister(loginFailures)
+	prometheus.MustRegister(loginLockouts)
+	prometheus.MustRegister(loginThrottled)
+
+	// auth stack metrics
+	prometheus.MustRegister(logins)
+	prometheus.MustRegister(tokenValidations)
+	prometheus.MustRegister(cacheLookups)
+	prometheus.MustRegister(sessions)
+	prometheus.MustRegister(providerRequestDuration)
+
+	// API rate limit metrics
+	prometheus.MustRegister(apiRateLimited)
+	prometheus.MustRegister(apiRateLimitBuckets)
+
+	// RBAC reconciliation metrics
+	prometheus.MustRegister(rbacBindingsReconciled)
+	prometheus.MustRegister(rbacReconcileDuration)
+	prometheus.MustRegister(rbacReconcileFailures)
+	prometheus.MustRegister(rbacPendingBindings)
+
 	gc := metricGarbageCollector{
 		clusterLister:  scaledContext.Management.Clusters("").Controller().Lister(),
 		nodeLister:     scaledContext.Management.Nodes("").Controller().Lister(),
@@ -66,7 +88,17 @@ func Register(ctx context.Context, scaledContext *config.ScaledContext) {
 		}
 	}(ctx)
 
+	rm := &rbacMetrics{
+		crtbCache: scaledContext.Wrangler.Mgmt.ClusterRoleTemplateBinding().Cache(),
+	}
+
+	am := &authMetrics{
+		tokenCache: scaledContext.Wrangler.Mgmt.Token().Cache(),
+	}
+
 	go nm.collect(ctx)
+	go rm.collect(ctx)
+	go am.collect(ctx)
 }
 
 func SetClusterOwner(id, clusterID string) {
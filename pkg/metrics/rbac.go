@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	rbacControllerLabel = "controller"
+	rbacClusterLabel    = "cluster"
+	rbacReasonLabel     = "reason"
+	rbacStageLabel      = "stage"
+
+	// rbacStageLocal counts the bindings waiting for the management cluster controller, and rbacStageRemote those
+	// waiting for the controller of their downstream cluster.
+	rbacStageLocal  = "local"
+	rbacStageRemote = "remote"
+
+	// unknownReason is the failure reason of the errors without a condition or an API reason.
+	unknownReason = "Unknown"
+
+	rbacLogPrefix = "[prometheus-rbac-metrics]"
+)
+
+var (
+	rbacBindingsReconciled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "rbac",
+			Name:      "bindings_reconciled_total",
+			Help:      "Number of role template bindings reconciled successfully, by controller and cluster",
+		},
+		[]string{rbacControllerLabel, rbacClusterLabel},
+	)
+
+	rbacReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: "rbac",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Time taken to reconcile a role template binding, by controller",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{rbacControllerLabel},
+	)
+
+	rbacReconcileFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "rbac",
+			Name:      "reconcile_failures_total",
+			Help:      "Number of failed reconciliations of role template bindings, by controller and reason",
+		},
+		[]string{rbacControllerLabel, rbacReasonLabel},
+	)
+
+	rbacPendingBindings = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "rbac",
+			Name:      "pending_bindings",
+			Help:      "Number of cluster role template bindings whose current generation wasn't reconciled yet, by cluster and stage",
+		},
+		[]string{rbacClusterLabel, rbacStageLabel},
+	)
+)
+
+// ObserveRBACReconcile records a reconciliation of a role template binding of the cluster by the controller, started
+// at started. A failed reconciliation is counted with the reason of its first failed condition, or the API reason of
+// its error.
+func ObserveRBACReconcile(controller, cluster string, started time.Time, conditions []metav1.Condition, err error) {
+	if !prometheusMetrics {
+		return
+	}
+	rbacReconcileDuration.With(prometheus.Labels{rbacControllerLabel: controller}).Observe(time.Since(started).Seconds())
+	if err == nil {
+		rbacBindingsReconciled.With(prometheus.Labels{rbacControllerLabel: controller, rbacClusterLabel: cluster}).Inc()
+		return
+	}
+	rbacReconcileFailures.With(prometheus.Labels{rbacControllerLabel: controller, rbacReasonLabel: failureReason(conditions, err)}).Inc()
+}
+
+func failureReason(conditions []metav1.Condition, err error) string {
+	for _, condition := range conditions {
+		if condition.Status == metav1.ConditionFalse && condition.Reason != "" {
+			return condition.Reason
+		}
+	}
+	if reason := apierrors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+	return unknownReason
+}
+
+type rbacMetrics struct {
+	crtbCache mgmtcontrollers.ClusterRoleTemplateBindingCache
+}
+
+// collect periodically counts the cluster role template bindings whose current generation wasn't observed by the
+// management cluster controller, or by the controller of their downstream cluster, so that the propagation of the
+// bindings lagging behind or stuck shows.
+func (m *rbacMetrics) collect(ctx context.Context) {
+	for range ticker.Context(ctx, reportInterval) {
+		crtbs, err := m.crtbCache.List("", labels.Everything())
+		if err != nil {
+			logrus.Errorf("%s couldn't list ClusterRoleTemplateBindings: %v", rbacLogPrefix, err)
+			continue
+		}
+
+		pending := map[[2]string]int{}
+		for _, crtb := range crtbs {
+			if crtb.DeletionTimestamp != nil {
+				continue
+			}
+			if crtb.Status.ObservedGenerationLocal < crtb.Generation {
+				pending[[2]string{crtb.ClusterName, rbacStageLocal}]++
+			}
+			if crtb.Status.ObservedGenerationRemote < crtb.Generation {
+				pending[[2]string{crtb.ClusterName, rbacStageRemote}]++
+			}
+		}
+
+		rbacPendingBindings.Reset()
+		for key, count := range pending {
+			rbacPendingBindings.With(prometheus.Labels{rbacClusterLabel: key[0], rbacStageLabel: key[1]}).Set(float64(count))
+		}
+	}
+
+	logrus.Debugf("%s context cancelled, exiting", rbacLogPrefix)
+}
@@ -15,11 +15,30 @@
 	"github.com/rancher/rancher/pkg/api/norman/customization/vsphere"
 	managementapi "github.com/rancher/rancher/pkg/api/norman/server"
 	"github.com/rancher/rancher/pkg/api/steve/supportconfigs"
+	"github.com/rancher/rancher/pkg/auth/accessrequests"
+	"github.com/rancher/rancher/pkg/auth/breakglass"
+	"github.com/rancher/rancher/pkg/auth/bulkbindings"
+	"github.com/rancher/rancher/pkg/auth/devicecode"
+	"github.com/rancher/rancher/pkg/auth/effectivepermissions"
+	"github.com/rancher/rancher/pkg/auth/emailverification"
+	"github.com/rancher/rancher/pkg/auth/jwttokens"
+	"github.com/rancher/rancher/pkg/auth/mfa"
+	"github.com/rancher/rancher/pkg/auth/passwordpolicy"
+	"github.com/rancher/rancher/pkg/auth/passwordreset"
+	"github.com/rancher/rancher/pkg/auth/providers"
 	"github.com/rancher/rancher/pkg/auth/providers/publicapi"
 	"github.com/rancher/rancher/pkg/auth/providers/saml"
+	"github.com/rancher/rancher/pkg/auth/ratelimit"
+	"github.com/rancher/rancher/pkg/auth/rbacbundle"
+	"github.com/rancher/rancher/pkg/auth/recertification"
+	"github.com/rancher/rancher/pkg/auth/refreshtokens"
 	"github.com/rancher/rancher/pkg/auth/requests"
 	"github.com/rancher/rancher/pkg/auth/requests/sar"
+	"github.com/rancher/rancher/pkg/auth/servicekeys"
+	"github.com/rancher/rancher/pkg/auth/sessions"
+	"github.com/rancher/rancher/pkg/auth/tokenexchange"
 	"github.com/rancher/rancher/pkg/auth/tokens"
+	"github.com/rancher/rancher/pkg/auth/webauthn"
 	"github.com/rancher/rancher/pkg/auth/webhook"
 	"github.com/rancher/rancher/pkg/channelserver"
 	"github.com/rancher/rancher/pkg/clustermanager"
@@ -67,6 +86,7 @@ func router(ctx context.Context, localClusterEnabled bool, tunnelAuthorizer *mcm
 	channelserver := channelserver.NewHandler(ctx)
 
 	supportConfigGenerator := supportconfigs.NewHandler(scaledContext)
+	jwtHandler := jwttokens.NewHandler(scaledContext)
 	// Unauthenticated routes
 	unauthed := mux.NewRouter()
 	unauthed.UseEncodedPath()
@@ -87,6 +107,17 @@ func router(ctx context.Context, localClusterEnabled bool, tunnelAuthorizer *mcm
 	unauthed.PathPrefix("/v1-{prefix}-release/channel").Handler(channelserver)
 	unauthed.PathPrefix("/v1-{prefix}-release/release").Handler(channelserver)
 	unauthed.PathPrefix("/v1-saml").Handler(saml.AuthHandler())
+	unauthed.Path(providers.BackchannelLogoutPath).Handler(providers.NewBackchannelLogoutHandler(ctx, scaledContext))
+	unauthed.PathPrefix("/v1-device").Handler(devicecode.NewHandler(ctx, scaledContext))
+	unauthed.Path(tokenexchange.TokenPath).Handler(tokenexchange.NewHandler(ctx, scaledContext))
+	unauthed.Path(jwttokens.JWKSPath).Handler(jwtHandler)
+	unauthed.Path(jwttokens.DiscoveryPath).Handler(jwtHandler)
+	unauthed.PathPrefix("/v1-token").Handler(refreshtokens.NewHandler(ctx, scaledContext))
+	unauthed.Path(webauthn.LoginPath).Handler(webauthn.NewLoginHandler(scaledContext))
+	unauthed.Path(passwordpolicy.PolicyPath).Handler(passwordpolicy.NewHandler())
+	unauthed.Path(passwordreset.BasePath).Handler(passwordreset.NewHandler(scaledContext))
+	unauthed.Path(emailverification.BasePath).Handler(emailverification.NewHandler(scaledContext))
+	unauthed.Path(breakglass.BasePath).Handler(breakglass.NewActivationHandler(ctx, scaledContext))
 	unauthed.PathPrefix("/v3-public").Handler(publicAPI)
 
 	// Authenticated routes
@@ -104,6 +135,7 @@ func router(ctx context.Context, localClusterEnabled bool, tunnelAuthorizer *mcm
 	authed := mux.NewRouter()
 	authed.UseEncodedPath()
 
+	authed.Use(ratelimit.NewLimiter().Middleware)
 	authed.Use(impersonatingAuth.ImpersonationMiddleware)
 	authed.Use(mux.MiddlewareFunc(accessControlHandler))
 	authed.Use(requests.NewAuthenticatedFilter)
@@ -117,6 +149,16 @@ func router(ctx context.Context, localClusterEnabled bool, tunnelAuthorizer *mcm
 	authed.PathPrefix("/meta/proxy").Handler(metaProxy)
 	authed.PathPrefix("/v3/identit").Handler(tokenAPI)
 	authed.PathPrefix("/v3/token").Handler(tokenAPI)
+	authed.PathPrefix("/v1-sessions").Handler(sessions.NewHandler(ctx, scaledContext))
+	authed.PathPrefix(servicekeys.BasePath).Handler(servicekeys.NewHandler(ctx, scaledContext))
+	authed.PathPrefix(accessrequests.BasePath).Handler(accessrequests.NewHandler(scaledContext))
+	authed.PathPrefix(breakglass.BasePath + "/").Handler(breakglass.NewHandler(ctx, scaledContext))
+	authed.PathPrefix(effectivepermissions.BasePath).Handler(effectivepermissions.NewHandler(scaledContext))
+	authed.PathPrefix(rbacbundle.BasePath).Handler(rbacbundle.NewHandler(scaledContext))
+	authed.PathPrefix(recertification.BasePath).Handler(recertification.NewHandler(scaledContext))
+	authed.PathPrefix(bulkbindings.BasePath).Handler(bulkbindings.NewHandler(scaledContext))
+	authed.PathPrefix(mfa.BasePath).Handler(mfa.NewHandler(scaledContext))
+	authed.PathPrefix(webauthn.BasePath + "/").Handler(webauthn.NewHandler(scaledContext))
 	authed.PathPrefix("/v3").Handler(managementAPI)
 
 	// Metrics authenticated route
@@ -1,6 +1,9 @@
 package project
 
 import (
+	"fmt"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 
 	"github.com/pkg/errors"
@@ -17,6 +20,9 @@
 	ProjectIDAnnotation          = "field.cattle.io/projectId"
 )
 
+// MaxHierarchyDepth is how many ancestors a project can have.
+const MaxHierarchyDepth = 5
+
 var (
 	SystemProjectLabel = map[string]string{"authz.management.cattle.io/system-project": "true"}
 )
@@ -33,3 +39,32 @@ func GetSystemProject(clusterName string, projectLister mgmtv3.ProjectLister) (*
 
 	return projects[0], nil
 }
+
+// Getter gets projects, such as the listers and caches of projects.
+type Getter interface {
+	Get(namespace, name string) (*v3.Project, error)
+}
+
+// Ancestors returns the ancestors of a project, from its parent up to the root of its hierarchy. It fails when an
+// ancestor doesn't exist, when the project is its own ancestor, or when it has more than MaxHierarchyDepth ancestors.
+func Ancestors(project *v3.Project, projects Getter) ([]*v3.Project, error) {
+	var ancestors []*v3.Project
+	seen := map[string]bool{project.Name: true}
+	for current := project; current.Spec.ParentProject != ""; {
+		parentName := current.Spec.ParentProject
+		if seen[parentName] {
+			return nil, fmt.Errorf("project %s is its own ancestor", project.Name)
+		}
+		if len(ancestors) == MaxHierarchyDepth {
+			return nil, fmt.Errorf("project %s has more than %d ancestors", project.Name, MaxHierarchyDepth)
+		}
+		parent, err := projects.Get(project.Namespace, parentName)
+		if err != nil {
+			return nil, fmt.Errorf("getting parent project %s of project %s: %w", parentName, current.Name, err)
+		}
+		seen[parentName] = true
+		ancestors = append(ancestors, parent)
+		current = parent
+	}
+	return ancestors, nil
+}
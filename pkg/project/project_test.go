@@ -0,0 +1,108 @@
+package project
+
+import (
+	"fmt"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type projectMap map[string]*v3.Project
+
+func (m projectMap) Get(namespace, name string) (*v3.Project, error) {
+	if project, ok := m[namespace+"/"+name]; ok {
+		return project, nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "projects"}, name)
+}
+
+func newProject(name, parent string) *v3.Project {
+	return &v3.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "c-abcde"},
+		Spec:       v3.ProjectSpec{ClusterName: "c-abcde", ParentProject: parent},
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	t.Parallel()
+	projects := projectMap{}
+	for _, project := range []*v3.Project{
+		newProject("p-root", ""),
+		newProject("p-team", "p-root"),
+		newProject("p-app", "p-team"),
+		newProject("p-loop-a", "p-loop-b"),
+		newProject("p-loop-b", "p-loop-a"),
+		newProject("p-orphan", "p-missing"),
+	} {
+		projects[project.Namespace+"/"+project.Name] = project
+	}
+	for i := 0; i <= MaxHierarchyDepth; i++ {
+		parent := ""
+		if i > 0 {
+			parent = fmt.Sprintf("p-deep-%d", i-1)
+		}
+		project := newProject(fmt.Sprintf("p-deep-%d", i), parent)
+		projects[project.Namespace+"/"+project.Name] = project
+	}
+
+	tests := []struct {
+		name    string
+		project string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "root project",
+			project: "p-root",
+		},
+		{
+			name:    "nested project",
+			project: "p-app",
+			want:    []string{"p-team", "p-root"},
+		},
+		{
+			name:    "cycle",
+			project: "p-loop-a",
+			wantErr: true,
+		},
+		{
+			name:    "missing parent",
+			project: "p-orphan",
+			wantErr: true,
+		},
+		{
+			name:    "deepest allowed project",
+			project: fmt.Sprintf("p-deep-%d", MaxHierarchyDepth),
+			want:    []string{"p-deep-4", "p-deep-3", "p-deep-2", "p-deep-1", "p-deep-0"},
+		},
+		{
+			name:    "too deep",
+			project: "p-too-deep",
+			wantErr: true,
+		},
+	}
+	tooDeep := newProject("p-too-deep", fmt.Sprintf("p-deep-%d", MaxHierarchyDepth))
+	projects[tooDeep.Namespace+"/"+tooDeep.Name] = tooDeep
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ancestors, err := Ancestors(projects["c-abcde/"+tt.project], projects)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, ancestor := range ancestors {
+				names = append(names, ancestor.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -15,7 +15,6 @@
 	v1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/auth/tokens"
 	"github.com/rancher/rancher/pkg/auth/tokens/hashers"
-	"github.com/rancher/rancher/pkg/features"
 	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/wrangler"
@@ -208,11 +207,8 @@ func (m *Manager) createUserToken(userName string) (string, error) {
 		Token:        tokenValue,
 	}
 
-	if features.TokenHashing.Enabled() {
-		err := tokens.ConvertTokenKeyToHash(token)
-		if err != nil {
-			return "", fmt.Errorf("unable to hash token: %w", err)
-		}
+	if err := tokens.ConvertTokenKeyToHash(token); err != nil {
+		return "", fmt.Errorf("unable to hash token: %w", err)
 	}
 
 	_, err = m.tokens.Create(token)
@@ -277,16 +273,21 @@ func (m *Manager) kubeConfigValid(kcData []byte, cluster *v1.Cluster, currentSer
 		return true, false
 	}
 
-	tokenMatches := fmt.Sprintf("%s:%s", userName, token.Token) == kc.AuthInfos["user"].Token
+	storedKey, err := tokens.StoredKey(token)
+	if err != nil {
+		logrus.Errorf("[kubeconfigmanager] error when reading the value of the token, %s", err.Error())
+		return true, false
+	}
+	tokenMatches := fmt.Sprintf("%s:%s", userName, storedKey) == kc.AuthInfos["user"].Token
 	if token.Annotations[tokens.TokenHashed] == "true" {
 		// if tokenHashing is enabled, the stored token will be hashed. So we instead make sure it's up-to-date by checking if the token is valid for the hash
-		hasher, err := hashers.GetHasherForHash(token.Token)
+		hasher, err := hashers.GetHasherForHash(storedKey)
 		if err != nil {
 			logrus.Errorf("[kubeconfigmanager] error when retrieving hasher for token hash, %s", err.Error())
 			return true, false
 		}
 		_, tokenKey := tokens.SplitTokenParts(kc.AuthInfos["user"].Token)
-		err = hasher.VerifyHash(token.Token, tokenKey)
+		err = hasher.VerifyHash(storedKey, tokenKey)
 		tokenMatches = err == nil
 	}
 
@@ -18,13 +18,16 @@
 	"github.com/rancher/rancher/pkg/api/steve/proxy"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/auth"
+	"github.com/rancher/rancher/pkg/auth/activity"
 	"github.com/rancher/rancher/pkg/auth/audit"
 	"github.com/rancher/rancher/pkg/auth/requests"
+	"github.com/rancher/rancher/pkg/auth/tokens"
 	"github.com/rancher/rancher/pkg/controllers/dashboard"
 	"github.com/rancher/rancher/pkg/controllers/dashboard/apiservice"
 	"github.com/rancher/rancher/pkg/controllers/dashboard/plugin"
 	"github.com/rancher/rancher/pkg/controllers/dashboardapi"
 	managementauth "github.com/rancher/rancher/pkg/controllers/management/auth"
+	"github.com/rancher/rancher/pkg/controllers/management/auth/groupmembership"
 	"github.com/rancher/rancher/pkg/controllers/nodedriver"
 	provisioningv2 "github.com/rancher/rancher/pkg/controllers/provisioningv2/cluster"
 	"github.com/rancher/rancher/pkg/crds"
@@ -242,6 +245,9 @@ func New(ctx context.Context, clientConfg clientcmd.ClientConfig, opts *Options)
 	if err != nil {
 		return nil, err
 	}
+	if auditLogWriter != nil {
+		audit.WatchAuditPolicies(ctx, wranglerContext.Mgmt.AuditPolicy())
+	}
 	aggregationMiddleware := aggregation.NewMiddleware(ctx, wranglerContext.Mgmt.APIService(), wranglerContext.TunnelServer)
 
 	wranglerContext.OnLeader(func(ctx context.Context) error {
@@ -255,7 +261,9 @@ func New(ctx context.Context, clientConfg clientcmd.ClientConfig, opts *Options)
 
 	return &Rancher{
 		Auth: authServer.Authenticator.Chain(
-			auditFilter),
+			auditFilter).Chain(activity.NewMiddleware(requests.ClientIP)).
+			Chain(tokens.NewRevocationAuditMiddleware(wranglerContext.Mgmt.Token().Cache())).
+			Chain(groupmembership.NewValidator(wranglerContext).Middleware),
 		Handler: responsewriter.Chain{
 			auth.SetXAPICattleAuthHeader,
 			responsewriter.ContentTypeOptions,
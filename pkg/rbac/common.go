@@ -24,6 +24,7 @@
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -192,6 +193,19 @@ func GetRTBLabel(objMeta metav1.ObjectMeta) string {
 	return wranglerName.SafeConcatName(objMeta.Namespace + "_" + objMeta.Name)
 }
 
+// NamespaceSelected returns whether a namespace with the labels is selected by the namespace selector of a binding. A
+// nil selector selects every namespace.
+func NamespaceSelected(selector *metav1.LabelSelector, namespaceLabels map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector: %w", err)
+	}
+	return s.Matches(labels.Set(namespaceLabels)), nil
+}
+
 // NameForRoleBinding returns a deterministic name for a RoleBinding with the provided namespace, roleName, and subject
 func NameForRoleBinding(namespace string, role rbacv1.RoleRef, subject rbacv1.Subject) string {
 	var name strings.Builder
@@ -245,6 +259,17 @@ func RulesFromTemplate(clusterRoles k8srbacv1.ClusterRoleCache, roleTemplates v3
 
 // gatherRules appends the rules from current template and does a recursive call to get all inherited roles referenced
 func gatherRules(clusterRoles k8srbacv1.ClusterRoleCache, roleTemplates v32.RoleTemplateCache, rt *v3.RoleTemplate, rules []rbacv1.PolicyRule, seen map[string]bool) ([]rbacv1.PolicyRule, error) {
+	if len(rt.ExcludedRules) > 0 && !rt.External {
+		// The exclusions apply to the rules of the inherited role templates too, so they are gathered apart.
+		withoutExclusions := rt.DeepCopy()
+		withoutExclusions.ExcludedRules = nil
+		granted, err := gatherRules(clusterRoles, roleTemplates, withoutExclusions, nil, seen)
+		if err != nil {
+			return nil, err
+		}
+		return append(rules, SubtractRules(granted, rt.ExcludedRules)...), nil
+	}
+
 	seen[rt.Name] = true
 
 	if rt.External {
@@ -310,10 +335,14 @@ func IsAdminGlobalRole(rtName string, grLister normanv3.GlobalRoleLister) (bool,
 	if err != nil {
 		return false, err
 	}
+	return isAdminGlobalRole(gr), nil
+}
 
+// isAdminGlobalRole detects whether a GlobalRole is the builtin admin role, or grants the same rules.
+func isAdminGlobalRole(gr *v3.GlobalRole) bool {
 	// global role is builtin admin role
 	if gr.Builtin && gr.Name == GlobalAdmin {
-		return true, nil
+		return true
 	}
 
 	var hasResourceRule, hasNonResourceRule bool
@@ -329,11 +358,7 @@ func IsAdminGlobalRole(rtName string, grLister normanv3.GlobalRoleLister) (bool,
 	}
 
 	// global role has an admin resource rule, and admin nonResourceURLs rule
-	if hasResourceRule && hasNonResourceRule {
-		return true, nil
-	}
-
-	return false, nil
+	return hasResourceRule && hasNonResourceRule
 }
 
 // CreateOrUpdateResource creates or updates the given resource
@@ -312,6 +312,61 @@ func TestGetRTBLabel(t *testing.T) {
 	}
 }
 
+func TestNamespaceSelected(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		selector *metav1.LabelSelector
+		labels   map[string]string
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name:   "nil selector selects every namespace",
+			labels: map[string]string{"team": "a"},
+			want:   true,
+		},
+		{
+			name:     "matching labels",
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			labels:   map[string]string{"team": "a", "env": "dev"},
+			want:     true,
+		},
+		{
+			name:     "labels not matching",
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			labels:   map[string]string{"team": "b"},
+		},
+		{
+			name: "matching expression",
+			selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "env", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"prod"}},
+			}},
+			labels: map[string]string{"env": "dev"},
+			want:   true,
+		},
+		{
+			name: "invalid selector",
+			selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "env", Operator: "Bogus"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NamespaceSelected(tt.selector, tt.labels)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 type grbTestState struct {
 	grListerMock *fakes.GlobalRoleListerMock
 }
@@ -0,0 +1,232 @@
+package rbac
+
+import (
+	"fmt"
+	"strings"
+
+	v32 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	k8srbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/component-helpers/auth/rbac/validation"
+)
+
+// allRules are the rules of the admins of a cluster.
+var allRules = []rbacv1.PolicyRule{
+	{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+	{NonResourceURLs: []string{"*"}, Verbs: []string{"*"}},
+}
+
+// GlobalPermissions are the permissions the GlobalRoles bound to a user, or to its groups, grant, along with those of
+// the cluster and project role bindings of the user.
+type GlobalPermissions struct {
+	// Rules are the rules granted in the local cluster.
+	Rules []rbacv1.PolicyRule
+	// NamespacedRules are the rules granted in the namespaces of the local cluster.
+	NamespacedRules map[string][]rbacv1.PolicyRule
+	// ClusterRules are the rules granted in the downstream clusters, by the inherited cluster roles.
+	ClusterRules []rbacv1.PolicyRule
+	// ClusterMemberRules are the rules granted in each cluster by the ClusterRoleTemplateBindings.
+	ClusterMemberRules map[string][]rbacv1.PolicyRule
+	// ProjectMemberRules are the rules granted in each project, named <cluster>:<project>, by the
+	// ProjectRoleTemplateBindings.
+	ProjectMemberRules map[string][]rbacv1.PolicyRule
+}
+
+// GlobalPermissionsResolver resolves the global permissions of the users, from their GlobalRoleBindings,
+// ClusterRoleTemplateBindings and ProjectRoleTemplateBindings, and those of the group principals their auth providers
+// reported on their last login.
+type GlobalPermissionsResolver struct {
+	grbCache           v32.GlobalRoleBindingCache
+	grCache            v32.GlobalRoleCache
+	crtbCache          v32.ClusterRoleTemplateBindingCache
+	prtbCache          v32.ProjectRoleTemplateBindingCache
+	userAttributeCache v32.UserAttributeCache
+	rtCache            v32.RoleTemplateCache
+	clusterRoleCache   k8srbacv1.ClusterRoleCache
+}
+
+// NewGlobalPermissionsResolver returns a resolver of the global permissions of the users.
+func NewGlobalPermissionsResolver(grbCache v32.GlobalRoleBindingCache, grCache v32.GlobalRoleCache,
+	crtbCache v32.ClusterRoleTemplateBindingCache, prtbCache v32.ProjectRoleTemplateBindingCache,
+	userAttributeCache v32.UserAttributeCache, rtCache v32.RoleTemplateCache, clusterRoleCache k8srbacv1.ClusterRoleCache) *GlobalPermissionsResolver {
+	return &GlobalPermissionsResolver{
+		grbCache:           grbCache,
+		grCache:            grCache,
+		crtbCache:          crtbCache,
+		prtbCache:          prtbCache,
+		userAttributeCache: userAttributeCache,
+		rtCache:            rtCache,
+		clusterRoleCache:   clusterRoleCache,
+	}
+}
+
+// Resolve returns the global permissions of the user. The admin and restricted admin global roles grant everything in
+// the downstream clusters.
+func (r *GlobalPermissionsResolver) Resolve(userID string) (*GlobalPermissions, error) {
+	groups, err := r.groups(userID)
+	if err != nil {
+		return nil, err
+	}
+	grbs, err := r.grbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing GlobalRoleBindings: %w", err)
+	}
+
+	permissions := &GlobalPermissions{
+		NamespacedRules:    map[string][]rbacv1.PolicyRule{},
+		ClusterMemberRules: map[string][]rbacv1.PolicyRule{},
+		ProjectMemberRules: map[string][]rbacv1.PolicyRule{},
+	}
+	seen := map[string]bool{}
+	for _, grb := range grbs {
+		if grb.DeletionTimestamp != nil || seen[grb.GlobalRoleName] {
+			continue
+		}
+		if grb.UserName != userID && (grb.GroupPrincipalName == "" || !groups[grb.GroupPrincipalName]) {
+			continue
+		}
+		seen[grb.GlobalRoleName] = true
+
+		gr, err := r.grCache.Get(grb.GlobalRoleName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting GlobalRole %s: %w", grb.GlobalRoleName, err)
+		}
+		permissions.Rules = append(permissions.Rules, gr.Rules...)
+		for namespace, rules := range gr.NamespacedRules {
+			permissions.NamespacedRules[namespace] = append(permissions.NamespacedRules[namespace], rules...)
+		}
+		if isAdminGlobalRole(gr) || gr.Name == GlobalRestrictedAdmin {
+			permissions.ClusterRules = append(permissions.ClusterRules, allRules...)
+			continue
+		}
+		for _, rtName := range gr.InheritedClusterRoles {
+			rt, err := r.rtCache.Get(rtName)
+			if err != nil {
+				return nil, fmt.Errorf("getting RoleTemplate %s inherited by GlobalRole %s: %w", rtName, gr.Name, err)
+			}
+			rules, err := RulesFromTemplate(r.clusterRoleCache, r.rtCache, rt)
+			if err != nil {
+				return nil, err
+			}
+			permissions.ClusterRules = append(permissions.ClusterRules, rules...)
+		}
+	}
+
+	crtbs, err := r.crtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ClusterRoleTemplateBindings: %w", err)
+	}
+	for _, crtb := range crtbs {
+		if crtb.DeletionTimestamp != nil || !isSubject(userID, groups, crtb.UserName, crtb.GroupPrincipalName) {
+			continue
+		}
+		rules, err := r.templateRules(crtb.RoleTemplateName)
+		if err != nil {
+			return nil, err
+		}
+		permissions.ClusterMemberRules[crtb.ClusterName] = append(permissions.ClusterMemberRules[crtb.ClusterName], rules...)
+	}
+
+	prtbs, err := r.prtbCache.List("", labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing ProjectRoleTemplateBindings: %w", err)
+	}
+	for _, prtb := range prtbs {
+		if prtb.DeletionTimestamp != nil || !isSubject(userID, groups, prtb.UserName, prtb.GroupPrincipalName) {
+			continue
+		}
+		rules, err := r.templateRules(prtb.RoleTemplateName)
+		if err != nil {
+			return nil, err
+		}
+		permissions.ProjectMemberRules[prtb.ProjectName] = append(permissions.ProjectMemberRules[prtb.ProjectName], rules...)
+	}
+	return permissions, nil
+}
+
+// templateRules returns the rules of the RoleTemplate, none if it doesn't exist anymore.
+func (r *GlobalPermissionsResolver) templateRules(name string) ([]rbacv1.PolicyRule, error) {
+	rt, err := r.rtCache.Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting RoleTemplate %s: %w", name, err)
+	}
+	return RulesFromTemplate(r.clusterRoleCache, r.rtCache, rt)
+}
+
+// isSubject tells whether the binding of the user or group principal applies to the user with the groups.
+func isSubject(userID string, groups map[string]bool, userName, groupPrincipalName string) bool {
+	return userName == userID || (groupPrincipalName != "" && groups[groupPrincipalName])
+}
+
+// Covers returns whether the global permissions of the user callerID include all those of the user targetID, including
+// those of its cluster and project role bindings. Acting on behalf of a user whose permissions aren't covered, or
+// changing its credentials, would escalate privileges.
+func (r *GlobalPermissionsResolver) Covers(callerID, targetID string) (bool, error) {
+	caller, err := r.Resolve(callerID)
+	if err != nil {
+		return false, err
+	}
+	target, err := r.Resolve(targetID)
+	if err != nil {
+		return false, err
+	}
+	return caller.Covers(target), nil
+}
+
+// Covers returns whether the permissions include all those of other.
+func (p *GlobalPermissions) Covers(other *GlobalPermissions) bool {
+	if covered, _ := validation.Covers(p.Rules, other.Rules); !covered {
+		return false
+	}
+	if covered, _ := validation.Covers(p.ClusterRules, other.ClusterRules); !covered {
+		return false
+	}
+	for namespace, rules := range other.NamespacedRules {
+		owned := append(append([]rbacv1.PolicyRule{}, p.Rules...), p.NamespacedRules[namespace]...)
+		if covered, _ := validation.Covers(owned, rules); !covered {
+			return false
+		}
+	}
+	// The rules of the clusters apply to their projects too.
+	for cluster, rules := range other.ClusterMemberRules {
+		owned := append(append([]rbacv1.PolicyRule{}, p.ClusterRules...), p.ClusterMemberRules[cluster]...)
+		if covered, _ := validation.Covers(owned, rules); !covered {
+			return false
+		}
+	}
+	for project, rules := range other.ProjectMemberRules {
+		cluster, _, _ := strings.Cut(project, ":")
+		owned := append(append([]rbacv1.PolicyRule{}, p.ClusterRules...), p.ClusterMemberRules[cluster]...)
+		owned = append(owned, p.ProjectMemberRules[project]...)
+		if covered, _ := validation.Covers(owned, rules); !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// groups returns the group principals of the user, as reported by its auth providers on its last login.
+func (r *GlobalPermissionsResolver) groups(userID string) (map[string]bool, error) {
+	groups := map[string]bool{}
+	attribute, err := r.userAttributeCache.Get(userID)
+	if apierrors.IsNotFound(err) {
+		return groups, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting the user attribute of user %s: %w", userID, err)
+	}
+	for _, principals := range attribute.GroupPrincipals {
+		for _, principal := range principals.Items {
+			groups[principal.Name] = true
+		}
+	}
+	return groups, nil
+}
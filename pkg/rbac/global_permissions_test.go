@@ -0,0 +1,133 @@
+package rbac
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/generic/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGlobalPermissionsCovers(t *testing.T) {
+	globalRoles := map[string]*v3.GlobalRole{
+		GlobalAdmin: {
+			ObjectMeta: metav1.ObjectMeta{Name: GlobalAdmin},
+			Builtin:    true,
+			Rules:      allRules,
+		},
+		"authn-admin": {
+			ObjectMeta: metav1.ObjectMeta{Name: "authn-admin"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"management.cattle.io"}, Resources: []string{"users", "authconfigs"}, Verbs: []string{"*"}},
+			},
+		},
+		"users-manage": {
+			ObjectMeta: metav1.ObjectMeta{Name: "users-manage"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"management.cattle.io"}, Resources: []string{"users"}, Verbs: []string{"get", "list", "update"}},
+			},
+		},
+		"wildcard": {
+			ObjectMeta: metav1.ObjectMeta{Name: "wildcard"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			},
+		},
+		"cluster-member": {
+			ObjectMeta:            metav1.ObjectMeta{Name: "cluster-member"},
+			InheritedClusterRoles: []string{"cluster-member"},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	grbCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRoleBinding](ctrl)
+	grCache := fake.NewMockNonNamespacedCacheInterface[*v3.GlobalRole](ctrl)
+	crtbCache := fake.NewMockCacheInterface[*v3.ClusterRoleTemplateBinding](ctrl)
+	prtbCache := fake.NewMockCacheInterface[*v3.ProjectRoleTemplateBinding](ctrl)
+	userAttributeCache := fake.NewMockNonNamespacedCacheInterface[*v3.UserAttribute](ctrl)
+	rtCache := fake.NewMockNonNamespacedCacheInterface[*v3.RoleTemplate](ctrl)
+	clusterRoleCache := fake.NewMockNonNamespacedCacheInterface[*rbacv1.ClusterRole](ctrl)
+
+	grbCache.EXPECT().List(labels.Everything()).Return([]*v3.GlobalRoleBinding{
+		{UserName: "u-admin", GlobalRoleName: GlobalAdmin},
+		{UserName: "u-authn", GlobalRoleName: "authn-admin"},
+		{UserName: "u-manager", GlobalRoleName: "users-manage"},
+		{GroupPrincipalName: "openldap_group://admins", GlobalRoleName: "wildcard"},
+		{UserName: "u-member", GlobalRoleName: "cluster-member"},
+		{UserName: "u-wildcard", GlobalRoleName: "wildcard"},
+	}, nil).AnyTimes()
+	grCache.EXPECT().Get(gomock.Any()).DoAndReturn(func(name string) (*v3.GlobalRole, error) {
+		return globalRoles[name], nil
+	}).AnyTimes()
+	crtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ClusterRoleTemplateBinding{
+		{ClusterName: "c-12345", UserName: "u-owner", RoleTemplateName: "cluster-owner"},
+		{ClusterName: "c-12345", UserName: "u-co-owner", RoleTemplateName: "cluster-owner"},
+		{ClusterName: "c-67890", UserName: "u-other-owner", RoleTemplateName: "cluster-owner"},
+		{ClusterName: "c-67890", GroupPrincipalName: "openldap_group://admins", RoleTemplateName: "cluster-owner"},
+	}, nil).AnyTimes()
+	prtbCache.EXPECT().List("", labels.Everything()).Return([]*v3.ProjectRoleTemplateBinding{
+		{ProjectName: "c-12345:p-12345", UserName: "u-project-member", RoleTemplateName: "project-member"},
+	}, nil).AnyTimes()
+	userAttributeCache.EXPECT().Get("u-ldap").Return(&v3.UserAttribute{
+		GroupPrincipals: map[string]v3.Principals{
+			"openldap": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "openldap_group://admins"}}}},
+		},
+	}, nil).AnyTimes()
+	userAttributeCache.EXPECT().Get(gomock.Any()).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "")).AnyTimes()
+	rtCache.EXPECT().Get("cluster-member").Return(&v3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-member"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"management.cattle.io"}, Resources: []string{"clusters"}, Verbs: []string{"get"}},
+		},
+	}, nil).AnyTimes()
+	rtCache.EXPECT().Get("cluster-owner").Return(&v3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-owner"},
+		Rules:      allRules,
+	}, nil).AnyTimes()
+	rtCache.EXPECT().Get("project-member").Return(&v3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-member"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+		},
+	}, nil).AnyTimes()
+
+	resolver := NewGlobalPermissionsResolver(grbCache, grCache, crtbCache, prtbCache, userAttributeCache, rtCache, clusterRoleCache)
+
+	tests := []struct {
+		name   string
+		caller string
+		target string
+		want   bool
+	}{
+		{name: "admin covers everyone", caller: "u-admin", target: "u-ldap", want: true},
+		{name: "authn admin doesn't cover admin", caller: "u-authn", target: "u-admin", want: false},
+		{name: "authn admin covers users managers", caller: "u-authn", target: "u-manager", want: true},
+		{name: "users manager doesn't cover authn admin", caller: "u-manager", target: "u-authn", want: false},
+		{name: "roles of the groups are compared", caller: "u-authn", target: "u-ldap", want: false},
+		{name: "inherited cluster roles are compared", caller: "u-authn", target: "u-member", want: false},
+		{name: "admin grants everything in the clusters", caller: "u-admin", target: "u-member", want: true},
+		{name: "wildcard rules don't grant the clusters", caller: "u-ldap", target: "u-member", want: false},
+		{name: "users without roles are covered", caller: "u-manager", target: "u-new", want: true},
+		{name: "cluster bindings are compared", caller: "u-authn", target: "u-owner", want: false},
+		{name: "admin covers cluster owners", caller: "u-admin", target: "u-owner", want: true},
+		{name: "owners of the same cluster cover each other", caller: "u-co-owner", target: "u-owner", want: true},
+		{name: "bindings in other clusters don't cover", caller: "u-other-owner", target: "u-owner", want: false},
+		{name: "cluster bindings of the groups are compared", caller: "u-wildcard", target: "u-ldap", want: false},
+		{name: "project bindings are compared", caller: "u-authn", target: "u-project-member", want: false},
+		{name: "cluster owners cover the members of their projects", caller: "u-owner", target: "u-project-member", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			covered, err := resolver.Covers(tt.caller, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, covered)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package rbac
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// standardVerbs are the verbs a wildcard verb stands for when some verbs are excluded from it.
+var standardVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"}
+
+// FlattenRoleTemplate returns a copy of the role template granting its rules and those of the role templates it
+// inherits, minus its excluded rules, and inheriting no role template, so that the exclusions apply to the inherited
+// rules. The role template is returned as is when it excludes no rule, or when it's external.
+func FlattenRoleTemplate(rt *v3.RoleTemplate, getRoleTemplate func(name string) (*v3.RoleTemplate, error)) (*v3.RoleTemplate, error) {
+	if len(rt.ExcludedRules) == 0 || rt.External {
+		return rt, nil
+	}
+	rules, err := grantedRules(rt, getRoleTemplate, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	flattened := rt.DeepCopy()
+	flattened.Rules = rules
+	flattened.RoleTemplateNames = nil
+	return flattened, nil
+}
+
+// CheckRuleExclusions returns an error when an excluded rule of the role template matches only part of what a rule it
+// grants, or inherits, stands for through a wildcard, or the lack of resource names. SubtractRules would keep such a
+// rule as is, granting what the role template means to exclude.
+func CheckRuleExclusions(rt *v3.RoleTemplate, getRoleTemplate func(name string) (*v3.RoleTemplate, error)) error {
+	if len(rt.ExcludedRules) == 0 || rt.External {
+		return nil
+	}
+	withoutExclusions := rt.DeepCopy()
+	withoutExclusions.ExcludedRules = nil
+	rules, err := grantedRules(withoutExclusions, getRoleTemplate, map[string]bool{})
+	if err != nil {
+		return err
+	}
+	for _, exclusion := range rt.ExcludedRules {
+		var remaining []rbacv1.PolicyRule
+		for _, rule := range rules {
+			subtracted := subtractRule(rule, exclusion)
+			if overlaps(rule, exclusion) && len(subtracted) == 1 && reflect.DeepEqual(subtracted[0], rule) {
+				return fmt.Errorf("excluded rule %+v only matches part of what granted rule %+v stands for, which can't be narrowed", exclusion, rule)
+			}
+			remaining = append(remaining, subtracted...)
+		}
+		rules = remaining
+	}
+	return nil
+}
+
+// grantedRules returns the rules of the role template and of those it inherits, minus the excluded ones of each.
+func grantedRules(rt *v3.RoleTemplate, getRoleTemplate func(name string) (*v3.RoleTemplate, error), seen map[string]bool) ([]rbacv1.PolicyRule, error) {
+	seen[rt.Name] = true
+
+	var rules []rbacv1.PolicyRule
+	if rt.External {
+		if rt.ExternalRules == nil {
+			return nil, fmt.Errorf("the rules of external role template %s are unknown, it has no external rules", rt.Name)
+		}
+		rules = append(rules, rt.ExternalRules...)
+	}
+	rules = append(rules, rt.Rules...)
+
+	for _, rtName := range rt.RoleTemplateNames {
+		if seen[rtName] {
+			continue
+		}
+		inherited, err := getRoleTemplate(rtName)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get RoleTemplate %s: %w", rtName, err)
+		}
+		inheritedRules, err := grantedRules(inherited, getRoleTemplate, seen)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, inheritedRules...)
+	}
+
+	if rt.External {
+		return rules, nil
+	}
+	return SubtractRules(rules, rt.ExcludedRules), nil
+}
+
+// SubtractRules returns rules granting what the rules grant, except what the excluded rules grant.
+// A rule with a wildcard verb is expanded to the standard verbs to exclude some of them, but a wildcard API group,
+// resource or non-resource URL, or a rule without resource names, can't be narrowed: an exclusion matching only part
+// of what they stand for leaves the rule as is.
+func SubtractRules(rules, excluded []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	for _, exclusion := range excluded {
+		var remaining []rbacv1.PolicyRule
+		for _, rule := range rules {
+			remaining = append(remaining, subtractRule(rule, exclusion)...)
+		}
+		rules = remaining
+	}
+	return rules
+}
+
+// subtractRule returns the rules granting what the rule grants, except what the exclusion grants. The rule is split
+// along each of its fields into the part the exclusion matches, which the next field splits further, and the others,
+// which are kept.
+func subtractRule(rule, exclusion rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	unchanged := []rbacv1.PolicyRule{rule}
+	if len(rule.NonResourceURLs) > 0 || len(exclusion.NonResourceURLs) > 0 {
+		if len(rule.NonResourceURLs) == 0 || len(exclusion.NonResourceURLs) == 0 {
+			return unchanged
+		}
+		urls, otherURLs, ok := splitValues(rule.NonResourceURLs, exclusion.NonResourceURLs)
+		if !ok || len(urls) == 0 {
+			return unchanged
+		}
+		verbs, otherVerbs, ok := splitValues(expandVerbs(rule.Verbs, exclusion.Verbs), exclusion.Verbs)
+		if !ok || len(verbs) == 0 {
+			return unchanged
+		}
+		var rules []rbacv1.PolicyRule
+		if len(otherURLs) > 0 {
+			rules = append(rules, rbacv1.PolicyRule{NonResourceURLs: otherURLs, Verbs: slices.Clone(rule.Verbs)})
+		}
+		if len(otherVerbs) > 0 {
+			rules = append(rules, rbacv1.PolicyRule{NonResourceURLs: urls, Verbs: otherVerbs})
+		}
+		return rules
+	}
+
+	groups, otherGroups, ok := splitValues(rule.APIGroups, exclusion.APIGroups)
+	if !ok || len(groups) == 0 {
+		return unchanged
+	}
+	resources, otherResources, ok := splitValues(rule.Resources, exclusion.Resources)
+	if !ok || len(resources) == 0 {
+		return unchanged
+	}
+	names, otherNames, ok := splitResourceNames(rule.ResourceNames, exclusion.ResourceNames)
+	if !ok {
+		return unchanged
+	}
+	verbs, otherVerbs, ok := splitValues(expandVerbs(rule.Verbs, exclusion.Verbs), exclusion.Verbs)
+	if !ok || len(verbs) == 0 {
+		return unchanged
+	}
+
+	var rules []rbacv1.PolicyRule
+	if len(otherGroups) > 0 {
+		rules = append(rules, resourceRule(otherGroups, rule.Resources, rule.ResourceNames, rule.Verbs))
+	}
+	if len(otherResources) > 0 {
+		rules = append(rules, resourceRule(groups, otherResources, rule.ResourceNames, rule.Verbs))
+	}
+	if len(otherNames) > 0 {
+		rules = append(rules, resourceRule(groups, resources, otherNames, rule.Verbs))
+	}
+	if len(otherVerbs) > 0 {
+		rules = append(rules, resourceRule(groups, resources, names, otherVerbs))
+	}
+	return rules
+}
+
+// splitValues splits the values of a field of a rule into those the values of the exclusion match and the others.
+// ok is false when the exclusion matches only part of what a wildcard value stands for.
+func splitValues(values, excluded []string) (matched, others []string, ok bool) {
+	if slices.Contains(excluded, rbacv1.ResourceAll) {
+		return slices.Clone(values), nil, true
+	}
+	if slices.Contains(values, rbacv1.ResourceAll) {
+		return nil, nil, false
+	}
+	for _, value := range values {
+		if slices.Contains(excluded, value) {
+			matched = append(matched, value)
+		} else {
+			others = append(others, value)
+		}
+	}
+	return matched, others, true
+}
+
+// splitResourceNames splits the resource names of a rule like splitValues, where no resource names stand for all of
+// them. ok is false when the exclusion has no resource name in common with the rule, or excludes some resource names
+// from a rule without any.
+func splitResourceNames(names, excluded []string) (matched, others []string, ok bool) {
+	if len(excluded) == 0 {
+		return slices.Clone(names), nil, true
+	}
+	if len(names) == 0 {
+		return nil, nil, false
+	}
+	matched, others, _ = splitValues(names, excluded)
+	return matched, others, len(matched) > 0
+}
+
+// expandVerbs replaces the wildcard verb with the standard verbs, unless the exclusion excludes every verb too.
+func expandVerbs(verbs, excluded []string) []string {
+	if !slices.Contains(verbs, rbacv1.VerbAll) || slices.Contains(excluded, rbacv1.VerbAll) {
+		return verbs
+	}
+	expanded := slices.Clone(standardVerbs)
+	for _, verb := range verbs {
+		if verb != rbacv1.VerbAll && !slices.Contains(expanded, verb) {
+			expanded = append(expanded, verb)
+		}
+	}
+	return expanded
+}
+
+// overlaps returns whether the exclusion matches some of what the rule grants.
+func overlaps(rule, exclusion rbacv1.PolicyRule) bool {
+	if len(rule.NonResourceURLs) > 0 || len(exclusion.NonResourceURLs) > 0 {
+		return len(rule.NonResourceURLs) > 0 && len(exclusion.NonResourceURLs) > 0 &&
+			valuesOverlap(rule.NonResourceURLs, exclusion.NonResourceURLs) && valuesOverlap(rule.Verbs, exclusion.Verbs)
+	}
+	namesOverlap := len(rule.ResourceNames) == 0 || len(exclusion.ResourceNames) == 0 ||
+		valuesOverlap(rule.ResourceNames, exclusion.ResourceNames)
+	return namesOverlap && valuesOverlap(rule.APIGroups, exclusion.APIGroups) &&
+		valuesOverlap(rule.Resources, exclusion.Resources) && valuesOverlap(rule.Verbs, exclusion.Verbs)
+}
+
+// valuesOverlap returns whether the values of a field of a rule and of an exclusion have a value in common, where the
+// wildcard stands for every value.
+func valuesOverlap(values, excluded []string) bool {
+	if slices.Contains(values, rbacv1.ResourceAll) || slices.Contains(excluded, rbacv1.ResourceAll) {
+		return true
+	}
+	return slices.ContainsFunc(values, func(value string) bool { return slices.Contains(excluded, value) })
+}
+
+func resourceRule(groups, resources, names, verbs []string) rbacv1.PolicyRule {
+	return rbacv1.PolicyRule{
+		APIGroups:     slices.Clone(groups),
+		Resources:     slices.Clone(resources),
+		ResourceNames: slices.Clone(names),
+		Verbs:         slices.Clone(verbs),
+	}
+}
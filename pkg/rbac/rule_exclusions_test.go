@@ -0,0 +1,363 @@
+package rbac
+
+import (
+	"fmt"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSubtractRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []rbacv1.PolicyRule
+		excluded []rbacv1.PolicyRule
+		want     []rbacv1.PolicyRule
+	}{
+		{
+			name: "no exclusion",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "excluded verb",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "delete"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"delete"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}},
+			},
+		},
+		{
+			name: "wildcard verb narrowed to the standard verbs",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"*"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"delete", "deletecollection"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch", "create", "update", "patch"}},
+			},
+		},
+		{
+			name: "excluded resource with every verb",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods", "secrets"}, Verbs: []string{"get", "list"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}},
+			},
+		},
+		{
+			name: "wildcard resource isn't narrowed",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"*"}, Verbs: []string{"get"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"*"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "excluded resource name",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"a", "b"}, Verbs: []string{"get"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"a"}, Verbs: []string{"get"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"b"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "resource name isn't excluded from a rule without resource names",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"a"}, Verbs: []string{"get"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "rule of another API group",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"delete"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"*"}, Verbs: []string{"delete"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"delete"}},
+			},
+		},
+		{
+			name: "rule split along API groups, resources and verbs",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"", "apps"}, Resources: []string{"pods", "deployments"}, Verbs: []string{"get", "delete"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"delete"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{"apps"}, Resources: []string{"pods", "deployments"}, Verbs: []string{"get", "delete"}},
+				{APIGroups: []string{""}, Resources: []string{"deployments"}, Verbs: []string{"get", "delete"}},
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "excluded non-resource URL",
+			rules: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/healthz", "/metrics"}, Verbs: []string{"get"}},
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "rule fully excluded",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+			},
+			excluded: []rbacv1.PolicyRule{
+				{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SubtractRules(tt.rules, tt.excluded))
+		})
+	}
+}
+
+func TestFlattenRoleTemplate(t *testing.T) {
+	roleTemplates := map[string]*v3.RoleTemplate{
+		"base": {
+			ObjectMeta: metav1.ObjectMeta{Name: "base"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"*"}},
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+			},
+		},
+		"external": {
+			ObjectMeta: metav1.ObjectMeta{Name: "external"},
+			External:   true,
+		},
+	}
+	getRoleTemplate := func(name string) (*v3.RoleTemplate, error) {
+		rt, ok := roleTemplates[name]
+		if !ok {
+			return nil, fmt.Errorf("roletemplate %s not found", name)
+		}
+		return rt, nil
+	}
+
+	tests := []struct {
+		name    string
+		rt      *v3.RoleTemplate
+		want    *v3.RoleTemplate
+		wantErr bool
+	}{
+		{
+			name: "no exclusion",
+			rt: &v3.RoleTemplate{
+				ObjectMeta:        metav1.ObjectMeta{Name: "rt"},
+				RoleTemplateNames: []string{"base"},
+			},
+			want: &v3.RoleTemplate{
+				ObjectMeta:        metav1.ObjectMeta{Name: "rt"},
+				RoleTemplateNames: []string{"base"},
+			},
+		},
+		{
+			name: "exclusions applied to the inherited rules",
+			rt: &v3.RoleTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "rt"},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "delete"}},
+				},
+				RoleTemplateNames: []string{"base"},
+				ExcludedRules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+					{APIGroups: []string{""}, Resources: []string{"*"}, Verbs: []string{"delete", "deletecollection"}},
+				},
+			},
+			want: &v3.RoleTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "rt"},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+					{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch", "create", "update", "patch"}},
+				},
+				ExcludedRules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+					{APIGroups: []string{""}, Resources: []string{"*"}, Verbs: []string{"delete", "deletecollection"}},
+				},
+			},
+		},
+		{
+			name: "exclusions of an external role template are ignored",
+			rt: &v3.RoleTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "rt"},
+				External:   true,
+				ExcludedRules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+				},
+			},
+			want: &v3.RoleTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "rt"},
+				External:   true,
+				ExcludedRules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+				},
+			},
+		},
+		{
+			name: "inherited external role template without external rules",
+			rt: &v3.RoleTemplate{
+				ObjectMeta:        metav1.ObjectMeta{Name: "rt"},
+				RoleTemplateNames: []string{"external"},
+				ExcludedRules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing inherited role template",
+			rt: &v3.RoleTemplate{
+				ObjectMeta:        metav1.ObjectMeta{Name: "rt"},
+				RoleTemplateNames: []string{"missing"},
+				ExcludedRules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FlattenRoleTemplate(tt.rt, getRoleTemplate)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckRuleExclusions(t *testing.T) {
+	roleTemplates := map[string]*v3.RoleTemplate{
+		"wildcard": {
+			ObjectMeta: metav1.ObjectMeta{Name: "wildcard"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			},
+		},
+		"pods": {
+			ObjectMeta: metav1.ObjectMeta{Name: "pods"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"*"}},
+				{NonResourceURLs: []string{"*"}, Verbs: []string{"get"}},
+			},
+		},
+	}
+	getRoleTemplate := func(name string) (*v3.RoleTemplate, error) {
+		return roleTemplates[name], nil
+	}
+
+	tests := []struct {
+		name      string
+		inherited string
+		excluded  rbacv1.PolicyRule
+		wantErr   bool
+	}{
+		{
+			name:      "resource of a wildcard resource",
+			inherited: "wildcard",
+			excluded:  rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"*"}},
+			wantErr:   true,
+		},
+		{
+			name:      "API group of a wildcard API group",
+			inherited: "wildcard",
+			excluded:  rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			wantErr:   true,
+		},
+		{
+			name:      "every verb of every resource",
+			inherited: "wildcard",
+			excluded:  rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"delete"}},
+		},
+		{
+			name:      "resource name of a rule without resource names",
+			inherited: "pods",
+			excluded:  rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, ResourceNames: []string{"etcd"}, Verbs: []string{"*"}},
+			wantErr:   true,
+		},
+		{
+			name:      "verb not standing for a standard verb",
+			inherited: "pods",
+			excluded:  rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"escalate"}},
+			wantErr:   true,
+		},
+		{
+			name:      "non-resource URL of a wildcard URL",
+			inherited: "pods",
+			excluded:  rbacv1.PolicyRule{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+			wantErr:   true,
+		},
+		{
+			name:      "resource and verb",
+			inherited: "pods",
+			excluded:  rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods/log"}, Verbs: []string{"get"}},
+		},
+		{
+			name:      "resource not granted",
+			inherited: "pods",
+			excluded:  rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"token"}, Verbs: []string{"*"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &v3.RoleTemplate{
+				ObjectMeta:        metav1.ObjectMeta{Name: "rt"},
+				RoleTemplateNames: []string{tt.inherited},
+				ExcludedRules:     []rbacv1.PolicyRule{tt.excluded},
+			}
+			err := CheckRuleExclusions(rt, getRoleTemplate)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -275,7 +275,22 @@ func authzTypes(schemas *types.Schemas) *types.Schemas {
 			})
 		}).
 		MustImport(&Version, v3.GlobalRoleBinding{}).
-		MustImport(&Version, v3.RoleTemplate{}).
+		MustImport(&Version, v3.RoleTemplateRenderInput{}).
+		MustImport(&Version, v3.RoleTemplateRenderOutput{}).
+		MustImport(&Version, v3.RoleTemplateSimulateInput{}).
+		MustImport(&Version, v3.RoleTemplateSimulateOutput{}).
+		MustImportAndCustomize(&Version, v3.RoleTemplate{}, func(schema *types.Schema) {
+			schema.ResourceActions = map[string]types.Action{
+				"render": {
+					Input:  "roleTemplateRenderInput",
+					Output: "roleTemplateRenderOutput",
+				},
+				"simulate": {
+					Input:  "roleTemplateSimulateInput",
+					Output: "roleTemplateSimulateOutput",
+				},
+			}
+		}).
 		MustImport(&Version, v3.ClusterRoleTemplateBinding{}).
 		MustImport(&Version, v3.ProjectRoleTemplateBinding{}).
 		MustImport(&Version, v3.GlobalRoleBinding{})
@@ -377,7 +392,16 @@ func authnTypes(schemas *types.Schemas) *types.Schemas {
 		AddMapperForType(&Version, v3.User{}, m.DisplayName{},
 			&m.Embed{Field: "status"}).
 		AddMapperForType(&Version, v3.Group{}, m.DisplayName{}).
-		MustImport(&Version, v3.Group{}).
+		MustImport(&Version, v3.GroupMembersInput{}).
+		MustImport(&Version, v3.GroupMembersOutput{}).
+		MustImportAndCustomize(&Version, v3.Group{}, func(schema *types.Schema) {
+			schema.CollectionActions = map[string]types.Action{
+				"members": {
+					Input:  "groupMembersInput",
+					Output: "groupMembersOutput",
+				},
+			}
+		}).
 		MustImport(&Version, v3.GroupMember{}).
 		MustImport(&Version, v3.SamlToken{}).
 		AddMapperForType(&Version, v3.Principal{}, m.DisplayName{}).
@@ -394,6 +418,16 @@ func authnTypes(schemas *types.Schemas) *types.Schemas {
 		MustImport(&Version, v3.SearchPrincipalsInput{}).
 		MustImport(&Version, v3.ChangePasswordInput{}).
 		MustImport(&Version, v3.SetPasswordInput{}).
+		MustImport(&Version, v3.LinkPrincipalInput{}).
+		MustImport(&Version, v3.UnlinkPrincipalInput{}).
+		MustImport(&Version, v3.DetectDuplicateUsersOutput{}).
+		MustImport(&Version, v3.MergeUsersInput{}).
+		MustImport(&Version, v3.MergeUsersOutput{}).
+		MustImport(&Version, v3.UserDataExport{}).
+		MustImport(&Version, v3.EraseUserDataInput{}).
+		MustImport(&Version, v3.EraseUserDataOutput{}).
+		MustImport(&Version, v3.LoginAsInput{}).
+		MustImport(&Version, v3.LoginAsOutput{}).
 		MustImportAndCustomize(&Version, v3.User{}, func(schema *types.Schema) {
 			schema.ResourceActions = map[string]types.Action{
 				"setpassword": {
@@ -401,17 +435,43 @@ func authnTypes(schemas *types.Schemas) *types.Schemas {
 					Output: "user",
 				},
 				"refreshauthprovideraccess": {},
+				"exportdata": {
+					Output: "userDataExport",
+				},
+				"erasedata": {
+					Input:  "eraseUserDataInput",
+					Output: "eraseUserDataOutput",
+				},
+				"loginas": {
+					Input:  "loginAsInput",
+					Output: "loginAsOutput",
+				},
 			}
 			schema.CollectionActions = map[string]types.Action{
 				"changepassword": {
 					Input: "changePasswordInput",
 				},
+				"linkprincipal": {
+					Input: "linkPrincipalInput",
+				},
+				"unlinkprincipal": {
+					Input: "unlinkPrincipalInput",
+				},
+				"detectduplicates": {
+					Output: "detectDuplicateUsersOutput",
+				},
+				"mergeusers": {
+					Input:  "mergeUsersInput",
+					Output: "mergeUsersOutput",
+				},
 				"refreshauthprovideraccess": {},
 			}
 		}).
 		MustImportAndCustomize(&Version, v3.AuthConfig{}, func(schema *types.Schema) {
 			schema.CollectionMethods = []string{http.MethodGet}
 		}).
+		MustImport(&Version, v3.AuthConfigRevisionsOutput{}).
+		MustImport(&Version, v3.AuthConfigRollbackInput{}).
 		// Local Config
 		MustImportAndCustomize(&Version, v3.LocalConfig{}, func(schema *types.Schema) {
 			schema.BaseType = "authConfig"
@@ -576,6 +636,63 @@ func authnTypes(schemas *types.Schemas) *types.Schemas {
 		}).
 		MustImport(&Version, v3.GenericOIDCApplyInput{}).
 		MustImport(&Version, v3.GenericOIDCTestOutput{}).
+		// External auth Config
+		MustImportAndCustomize(&Version, v3.ExternalAuthConfig{}, func(schema *types.Schema) {
+			schema.BaseType = "authConfig"
+			schema.ResourceActions = map[string]types.Action{
+				"disable": {},
+				"testAndApply": {
+					Input: "externalAuthTestAndApplyInput",
+				},
+			}
+			schema.CollectionMethods = []string{}
+			schema.ResourceMethods = []string{http.MethodGet, http.MethodPut}
+		}).
+		MustImport(&Version, v3.ExternalAuthTestAndApplyInput{}).
+		// Kerberos Config
+		MustImportAndCustomize(&Version, v3.KerberosConfig{}, func(schema *types.Schema) {
+			schema.BaseType = "authConfig"
+			schema.ResourceActions = map[string]types.Action{
+				"disable": {},
+				"testAndApply": {
+					Input: "kerberosTestAndApplyInput",
+				},
+			}
+			schema.CollectionMethods = []string{}
+			schema.ResourceMethods = []string{http.MethodGet, http.MethodPut}
+		}).
+		MustImport(&Version, v3.KerberosTestAndApplyInput{}).
+		// Client Certificate Config
+		MustImportAndCustomize(&Version, v3.ClientCertConfig{}, func(schema *types.Schema) {
+			schema.BaseType = "authConfig"
+			schema.ResourceActions = map[string]types.Action{
+				"disable": {},
+				"testAndApply": {
+					Input: "clientCertTestAndApplyInput",
+				},
+			}
+			schema.CollectionMethods = []string{}
+			schema.ResourceMethods = []string{http.MethodGet, http.MethodPut}
+		}).
+		MustImport(&Version, v3.ClientCertTestAndApplyInput{}).
+		// CAS Config
+		MustImportAndCustomize(&Version, v3.CASConfig{}, func(schema *types.Schema) {
+			schema.BaseType = "authConfig"
+			schema.ResourceActions = map[string]types.Action{
+				"disable": {},
+				"configureTest": {
+					Input:  "casConfig",
+					Output: "casConfigTestOutput",
+				},
+				"testAndApply": {
+					Input: "casConfigApplyInput",
+				},
+			}
+			schema.CollectionMethods = []string{}
+			schema.ResourceMethods = []string{http.MethodGet, http.MethodPut}
+		}).
+		MustImport(&Version, v3.CASConfigTestOutput{}).
+		MustImport(&Version, v3.CASConfigApplyInput{}).
 		//KeyCloakOIDC Config
 		MustImportAndCustomize(&Version, v3.KeyCloakOIDCConfig{}, func(schema *types.Schema) {
 			schema.BaseType = "authConfig"
@@ -143,6 +143,156 @@
 	Rke2DefaultVersion = NewSetting("rke2-default-version", "")
 	K3sDefaultVersion  = NewSetting("k3s-default-version", "")
 
+	// TLSRequestClientCertificates makes Rancher request client certificates in the TLS handshake, so that users can
+	// log in with the client certificate provider. The certificates are only verified by that provider.
+	// Changes take effect on restart.
+	TLSRequestClientCertificates = NewSetting("tls-request-client-certificates", "false")
+
+	// AuthProviderFallbackOrder is a comma separated list of auth providers ordered by priority, e.g. activedirectory,local.
+	// While the health probes of the first providers fail, logins are allowed with the first healthy one, even if it isn't enabled.
+	AuthProviderFallbackOrder = NewSetting("auth-provider-fallback-order", "")
+
+	// AuthProviderSLOTarget is the percentage of the requests to each auth provider which must meet the service level
+	// objective, over auth-provider-slo-window-minutes: succeed, or fail on the client's side, within
+	// auth-provider-slo-latency-ms. The compliance is published in the status of the auth configs. 0 disables it.
+	AuthProviderSLOTarget = NewSetting("auth-provider-slo-target", "99")
+
+	// AuthProviderSLOLatencyMs is the latency objective of the requests to the auth providers, in milliseconds.
+	AuthProviderSLOLatencyMs = NewSetting("auth-provider-slo-latency-ms", "2000")
+
+	// AuthProviderSLOWindowMinutes is the rolling window the compliance with the auth provider SLO is evaluated over.
+	AuthProviderSLOWindowMinutes = NewSetting("auth-provider-slo-window-minutes", "60")
+
+	// AuthProviderSLOBreachMinutes is how long the compliance of an auth provider must stay below the target before
+	// its SLO is reported breached, so that short blips aren't.
+	AuthProviderSLOBreachMinutes = NewSetting("auth-provider-slo-breach-minutes", "15")
+
+	// AuthProviderSLOWebhookURL is the URL the breaches and recoveries of the SLOs of the auth providers are posted to,
+	// as JSON. They're also recorded as AuthEvents.
+	AuthProviderSLOWebhookURL = NewSetting("auth-provider-slo-webhook-url", "")
+
+	// PrincipalSearchScopes restricts the principals the users who aren't admins can search and add as project members.
+	// It's a JSON object mapping the names of auth providers to an object with the groupPrincipals and searchBases of
+	// their designated groups and organizational units. The memberSearchScope of a project overrides it.
+	PrincipalSearchScopes = NewSetting("principal-search-scopes", "")
+
+	// PrincipalSearchRequiresPermission restricts searching principals, for the users who aren't admins, to those searching
+	// them to add members to a cluster or a project they can add members to, and to those allowed the search verb on
+	// principals, e.g. with the principals-search global role.
+	PrincipalSearchRequiresPermission = NewSetting("principal-search-requires-permission", "false")
+
+	// PrincipalSearchBreadth restricts how broad the principal searches of the users who aren't admins are. It's a JSON
+	// object mapping the names of auth providers to "own-groups", to find only their own groups and the members of them,
+	// "same-ou", to find only the principals of their organizational unit, or "directory", the default, to find any
+	// principal.
+	PrincipalSearchBreadth = NewSetting("principal-search-breadth", "")
+
+	// BindingPolicyURL is the URL of the external policy endpoint which allows or denies the ClusterRoleTemplateBindings,
+	// ProjectRoleTemplateBindings and GlobalRoleBindings created with the API. An empty string means the bindings aren't
+	// checked.
+	BindingPolicyURL = NewSetting("binding-policy-url", "")
+
+	// BindingPolicyFormat is the format of binding-policy-url: "http" for the endpoints responding with {"allowed", "reasons"},
+	// or "opa" for the data API of an Open Policy Agent, e.g. http://opa:8181/v1/data/rancher/bindings.
+	BindingPolicyFormat = NewSetting("binding-policy-format", "http")
+
+	// BindingPolicyFailOpen allows the bindings when binding-policy-url can't be queried, when set to "true". They're
+	// denied otherwise.
+	BindingPolicyFailOpen = NewSetting("binding-policy-fail-open", "false")
+
+	// AuthConfigRevisionHistoryLimit is how many revisions of each auth config are kept for rolling back changes.
+	AuthConfigRevisionHistoryLimit = NewSetting("auth-config-revision-history-limit", "20")
+
+	// AuthExternalSecretCacheTTLSeconds is how long credentials of auth providers resolved from external secret stores are cached.
+	AuthExternalSecretCacheTTLSeconds = NewSetting("auth-external-secret-cache-ttl-seconds", "300") // 5 minutes
+
+	// AuthLDAPServerDiscoveryRefreshSeconds is how long the LDAP servers discovered in DNS SRV records are used before being resolved again.
+	AuthLDAPServerDiscoveryRefreshSeconds = NewSetting("auth-ldap-server-discovery-refresh-seconds", "300") // 5 minutes
+
+	// AuthOIDCJWKSRefreshIntervalMinutes is how often the signing keys of OIDC providers are refreshed in the background.
+	AuthOIDCJWKSRefreshIntervalMinutes = NewSetting("auth-oidc-jwks-refresh-interval-minutes", "60") // 1 hour
+
+	// AuthOIDCJWKSStaleKeyGraceMinutes is how long signing keys removed by an OIDC provider are still accepted,
+	// so that tokens signed just before a key rotation remain valid.
+	AuthOIDCJWKSStaleKeyGraceMinutes = NewSetting("auth-oidc-jwks-stale-key-grace-minutes", "60") // 1 hour
+
+	// AuthOIDCClockSkewLeewaySeconds is how long after their expiry tokens of OIDC providers are accepted, to tolerate clock skew.
+	AuthOIDCClockSkewLeewaySeconds = NewSetting("auth-oidc-clock-skew-leeway-seconds", "60")
+
+	// AuthAccessTokenTTLMinutes is the time to live of the tokens issued with a refresh token, in minutes.
+	AuthAccessTokenTTLMinutes = NewSetting("auth-access-token-ttl-minutes", "15")
+
+	// AuthRefreshTokenTTLMinutes is how long a refresh token family can be used for, in minutes. Rotating the refresh token doesn't extend it.
+	AuthRefreshTokenTTLMinutes = NewSetting("auth-refresh-token-ttl-minutes", "10080") // 7 days
+
+	// AuthTokenExchangeMaxTTLMinutes is the longest time to live of the tokens issued by the token exchange endpoint, in minutes.
+	// It's also their time to live when the client doesn't ask for one.
+	AuthTokenExchangeMaxTTLMinutes = NewSetting("auth-token-exchange-max-ttl-minutes", "60")
+
+	// AuthTokenFederation is a JSON array of the external OIDC issuers, like the ones of CI systems, whose tokens the
+	// token exchange endpoint trades for Rancher tokens of a user. Each entry has a name, the issuer, the audience of
+	// the tokens, the claims they must have, the userId of the Rancher user, and optionally the scope and the
+	// maxTTLMinutes of the issued tokens. An empty string means no issuer is trusted.
+	AuthTokenFederation = NewSetting("auth-token-federation", "")
+
+	// AuthJWTTokensEnabled allows the token exchange endpoint to issue tokens as JWTs signed by Rancher, which services can
+	// validate offline with the keys published at /.well-known/jwks.json.
+	AuthJWTTokensEnabled = NewSetting("auth-jwt-tokens-enabled", "false")
+
+	// AuthJWTSigningKeyRotationDays is how long a key signs the JWTs before being replaced, in days. Replaced keys are
+	// published for as long again, which JWTs can't outlive.
+	AuthJWTSigningKeyRotationDays = NewSetting("auth-jwt-signing-key-rotation-days", "30")
+
+	// AuthTokenEncryptionConfigFile is the path of the file configuring the key service encrypting the stored token material,
+	// like the hashes of tokens and the access tokens of auth providers. Token material isn't encrypted when it's empty.
+	AuthTokenEncryptionConfigFile = NewSetting("auth-token-encryption-config-file", "")
+
+	// AuthTokenHashingTransitionHours is how long after token hashing is enabled the tokens which aren't hashed yet are
+	// still accepted, while they're hashed in batches. The remaining ones are hashed at once when it ends.
+	AuthTokenHashingTransitionHours = NewSetting("auth-token-hashing-transition-hours", "24")
+
+	// AuthTokenHashingBatchSize is how many tokens are hashed every minute during the token hashing transition.
+	AuthTokenHashingBatchSize = NewSetting("auth-token-hashing-batch-size", "500")
+
+	// AuthTokenHashingStartedAt is when the tokens started being hashed, in RFC 3339 format. It's set by Rancher when
+	// token hashing is enabled.
+	AuthTokenHashingStartedAt = NewSetting("auth-token-hashing-started-at", "")
+
+	// AuthTrustedProxyCIDRs is a comma separated list of the CIDRs of the proxies in front of Rancher. The client address
+	// they set in the X-Forwarded-For header is the one checked against the allowed CIDRs of tokens.
+	AuthTrustedProxyCIDRs = NewSetting("auth-trusted-proxy-cidrs", "")
+
+	// AuthTokenUsageHistoryMaxEvents is how many usage events are kept per token. 0 disables the usage history.
+	AuthTokenUsageHistoryMaxEvents = NewSetting("auth-token-usage-history-max-events", "50")
+
+	// AuthTokenUsageHistoryRetentionHours is how long the usage events of tokens are kept, in hours.
+	AuthTokenUsageHistoryRetentionHours = NewSetting("auth-token-usage-history-retention-hours", "720") // 30 days
+
+	// AuthEventRetentionHours is how long the AuthEvents recording the authentication events are kept, in hours.
+	// 0 keeps them forever.
+	AuthEventRetentionHours = NewSetting("auth-event-retention-hours", "720") // 30 days
+
+	// SecurityEventRetentionHours is how long the SecurityEvents recording the high-signal security findings are kept,
+	// in hours. 0 keeps them forever.
+	SecurityEventRetentionHours = NewSetting("security-event-retention-hours", "2160") // 90 days
+
+	// UserActivityMaxEntries is how many activities are kept in the activity feed of each user: their auth events,
+	// notable writes and cluster accesses. 0 disables the feeds.
+	UserActivityMaxEntries = NewSetting("user-activity-max-entries", "200")
+
+	// UserActivityRetentionHours is how long the activities of the users are kept in their feeds, in hours.
+	UserActivityRetentionHours = NewSetting("user-activity-retention-hours", "720") // 30 days
+
+	// AuditSinks is a JSON list of the sinks the auth events, the security events and the API audit log are exported
+	// to, e.g. a SIEM: syslog servers over TLS, HTTP webhooks, or Kafka topics through a Kafka REST proxy. Their
+	// credentials are read from the audit-sink-<name> secrets of the cattle-global-data namespace, if they exist.
+	AuditSinks = NewSetting("audit-sinks", "")
+
+	// AuditLogRedactionPolicy is a JSON policy of the headers, body fields and query parameters redacted or dropped from
+	// the API audit log, in addition to the built-in ones, with rules that can be limited to some endpoints. Changes
+	// apply to the next requests, without restarting.
+	AuditLogRedactionPolicy = NewSetting("audit-log-redaction-policy", "")
+
 	// AuthTokenMaxTTLMinutes is the max allowable time to live for tokens. Excluding those created for UI sessions which is controlled by AuthUserSessionTTLMinutes.
 	AuthTokenMaxTTLMinutes = NewSetting("auth-token-max-ttl-minutes", "129600") // 90 days
 
@@ -153,11 +303,240 @@
 	AuthUserSessionTTLMinutes = NewSetting("auth-user-session-ttl-minutes", "960") // 16 hours
 
 	// AuthUserSessionIdleTTLMinutes represents the time to live without user activity for tokens controlling a login session, in minutes.
+	// Any request of the session other than a watch is activity. 0 disables the idle timeout.
 	// By default, the value for auth-user-session-idle-ttl-minutes should be set
 	// to the same value as auth-user-session-ttl-minutes (for backward compatibility reasons),
 	// and it must never be greater than this value.
 	AuthUserSessionIdleTTLMinutes = NewSetting("auth-user-session-idle-ttl-minutes", "960") // 16 hours
 
+	// AuthLoginAsMaxTTLMinutes is the maximum time to live, in minutes, of the tokens issued to administrators to log in as
+	// another user. It's also the time to live of the tokens requested without one.
+	AuthLoginAsMaxTTLMinutes = NewSetting("auth-login-as-max-ttl-minutes", "15")
+
+	// AccessRequestMaxDurationHours is the longest duration, in hours, users can request temporary access to a cluster or a
+	// project for with an access request.
+	AccessRequestMaxDurationHours = NewSetting("access-request-max-duration-hours", "24")
+
+	// RecertificationCampaignCron determines how often a recertification campaign is generated for each cluster, reviewing
+	// its role template bindings and those of its projects. Clusters with an open campaign are skipped.
+	// The value should be a valid cron expression e.g. "0 0 1 */3 *" (quarterly). An empty string means the feature is disabled.
+	RecertificationCampaignCron = NewSetting("recertification-campaign-cron", "")
+
+	// RecertificationCampaignDurationHours is how long, in hours, the reviewers of a recertification campaign have to
+	// approve its bindings before those which weren't approved are removed.
+	RecertificationCampaignDurationHours = NewSetting("recertification-campaign-duration-hours", "336")
+
+	// RBACDriftCheckIntervalMinutes is how often the RBAC objects Rancher manages in the downstream clusters are checked
+	// for drift. The check of a cluster is also made when its RBACDriftReport is edited.
+	RBACDriftCheckIntervalMinutes = NewSetting("rbac-drift-check-interval-minutes", "60")
+
+	// OrphanedBindingCheckIntervalMinutes is how often the role template bindings of the clusters are checked for
+	// subjects which don't exist anymore. The check of a cluster is also made when its OrphanedBindingReport is edited.
+	OrphanedBindingCheckIntervalMinutes = NewSetting("orphaned-binding-check-interval-minutes", "60")
+
+	// OrphanedBindingAutoCleanupEnabled allows the OrphanedBindingReports enabling autoCleanup to remove the orphaned
+	// bindings they find. When false, orphaned bindings are only removed when listed in the cleanupBindings of a report.
+	OrphanedBindingAutoCleanupEnabled = NewSetting("orphaned-binding-auto-cleanup-enabled", "false")
+
+	// RoleUsageCheckIntervalMinutes is how often the usage of the RoleTemplates and GlobalRoles is checked. The check is
+	// also made when the RoleUsageReport is edited.
+	RoleUsageCheckIntervalMinutes = NewSetting("role-usage-check-interval-minutes", "60")
+
+	// AuthUserMaxSessions is how many login sessions a user can hold at the same time. 0 means no limit.
+	AuthUserMaxSessions = NewSetting("auth-user-max-sessions", "0")
+
+	// AuthUserMaxSessionsPolicy is what happens to a login past auth-user-max-sessions: "reject" fails the login,
+	// "evict-oldest" ends the oldest sessions of the user to make room for the new one.
+	AuthUserMaxSessionsPolicy = NewSetting("auth-user-max-sessions-policy", "reject")
+
+	// AuthMFARequiredProviders is a comma separated list of the auth providers whose users must log in with a TOTP
+	// second factor. Only the local, activedirectory, openldap and freeipa providers support it.
+	AuthMFARequiredProviders = NewSetting("auth-mfa-required-providers", "")
+
+	// AuthWebAuthnRPID is the relying party ID of the security keys and passkeys of local users. It defaults to the
+	// host of server-url, and can be set to a parent domain of it.
+	AuthWebAuthnRPID = NewSetting("auth-webauthn-rp-id", "")
+
+	// AuthWebAuthnAttestationPolicy is which authenticators can be registered: "none" accepts any authenticator,
+	// "direct" requires a packed attestation signed by a certificate of auth-webauthn-attestation-ca-certs.
+	AuthWebAuthnAttestationPolicy = NewSetting("auth-webauthn-attestation-policy", "none")
+
+	// AuthWebAuthnAttestationCACerts is the PEM bundle of the CAs of the authenticator vendors trusted by the direct
+	// attestation policy.
+	AuthWebAuthnAttestationCACerts = NewSetting("auth-webauthn-attestation-ca-certs", "")
+
+	// AuthWebAuthnAllowedAAGUIDs is a comma separated list of the AAGUIDs of the authenticator models that can be
+	// registered. Empty allows all models. The AAGUIDs are only vouched for by the direct attestation policy.
+	AuthWebAuthnAllowedAAGUIDs = NewSetting("auth-webauthn-allowed-aaguids", "")
+
+	// AuthLoginMaxFailuresPerUsername is how many failed logins with a username and password lock the username out
+	// for auth-login-lockout-minutes. Each failure also delays the next attempt exponentially. 0 disables it.
+	AuthLoginMaxFailuresPerUsername = NewSetting("auth-login-max-failures-per-username", "10")
+
+	// AuthLoginMaxFailuresPerSource is how many failed logins with a username and password lock the client address
+	// out for auth-login-lockout-minutes. Each failure also delays the next attempt exponentially. 0 disables it.
+	AuthLoginMaxFailuresPerSource = NewSetting("auth-login-max-failures-per-source", "100")
+
+	// AuthLoginLockoutMinutes is how long the usernames and client addresses with too many failed logins are locked
+	// out, and how long the failures are remembered.
+	AuthLoginLockoutMinutes = NewSetting("auth-login-lockout-minutes", "15")
+
+	// APIRateLimitRequestsPerSecond is how many requests per second each user, or each token, can make to the
+	// authenticated APIs, past its burst. 0 disables the rate limit.
+	APIRateLimitRequestsPerSecond = NewSetting("api-rate-limit-requests-per-second", "0")
+
+	// APIRateLimitBurst is how many requests each user, or each token, can make at once before api-rate-limit-requests-per-second
+	// applies.
+	APIRateLimitBurst = NewSetting("api-rate-limit-burst", "100")
+
+	// APIRateLimitScope is what the API rate limit is kept for: "user" shares it between the tokens of a user, "token"
+	// keeps one for each token.
+	APIRateLimitScope = NewSetting("api-rate-limit-scope", "user")
+
+	// AuthLoginCaptchaProvider is the backend verifying the challenges required after repeated failed logins:
+	// "hcaptcha", "recaptcha" or "turnstile". Its secret key is read from the secretKey field of the login-captcha
+	// secret of the cattle-global-data namespace. Empty disables the challenges.
+	AuthLoginCaptchaProvider = NewSetting("auth-login-captcha-provider", "")
+
+	// AuthLoginCaptchaSiteKey is the public site key of auth-login-captcha-provider, for the login page to show the
+	// challenge.
+	AuthLoginCaptchaSiteKey = NewSetting("auth-login-captcha-site-key", "")
+
+	// AuthLoginCaptchaAfterFailures is how many failed logins of a username or a client address require a challenge
+	// for their next logins.
+	AuthLoginCaptchaAfterFailures = NewSetting("auth-login-captcha-after-failures", "3")
+
+	// AuthLoginCaptchaAuthProviders is a comma separated list of the auth providers whose logins require a challenge
+	// after repeated failures. Only the local, activedirectory, openldap and freeipa providers support it.
+	AuthLoginCaptchaAuthProviders = NewSetting("auth-login-captcha-auth-providers", "local,activedirectory,openldap,freeipa")
+
+	// PasswordMinCharacterClasses is how many of the character classes of the passwords of the local users must
+	// contain: uppercase letters, lowercase letters, digits and symbols. 0 disables it.
+	PasswordMinCharacterClasses = NewSetting("password-min-character-classes", "0")
+
+	// PasswordHistorySize is how many of their last passwords, the current one included, the local users can't reuse.
+	// 0 disables it.
+	PasswordHistorySize = NewSetting("password-history-size", "0")
+
+	// PasswordMaxAgeDays is how many days the passwords of the local users can be used before they must change them
+	// at their next login. 0 disables it.
+	PasswordMaxAgeDays = NewSetting("password-max-age-days", "0")
+
+	// PasswordDictionaryCheck rejects the passwords of the local users which are common passwords, or contain their
+	// username, when set to "true".
+	PasswordDictionaryCheck = NewSetting("password-dictionary-check", "false")
+
+	// PasswordBreachCheck rejects the passwords of the local users which are known to have been breached, when set to
+	// "true". The passwords are looked up by the first 5 characters of their SHA-1 hash with the range API of
+	// password-breach-check-url, so that they aren't disclosed. The passwords are accepted when the API is unavailable.
+	PasswordBreachCheck = NewSetting("password-breach-check", "false")
+
+	// PasswordBreachCheckURL is the URL of the Have I Been Pwned compatible range API looked up by password-breach-check,
+	// to which the hash prefixes are appended. It can be set to a self-hosted corpus.
+	PasswordBreachCheckURL = NewSetting("password-breach-check-url", "https://api.pwnedpasswords.com/range/")
+
+	// PasswordHashMemoryKiB is the memory in KiB used by argon2id to hash the passwords of the local users. The
+	// passwords hashed with other parameters are rehashed at the next login of their users.
+	PasswordHashMemoryKiB = NewSetting("password-hash-memory-kib", "19456")
+
+	// PasswordHashIterations is the number of passes of argon2id over its memory to hash the passwords of the local
+	// users.
+	PasswordHashIterations = NewSetting("password-hash-iterations", "2")
+
+	// PasswordHashParallelism is the number of threads used by argon2id to hash the passwords of the local users.
+	PasswordHashParallelism = NewSetting("password-hash-parallelism", "1")
+
+	// PasswordResetEnabled lets the local users with an email address reset their forgotten password with a link sent
+	// to them, when set to "true". It requires smtp-server and smtp-from.
+	PasswordResetEnabled = NewSetting("password-reset-enabled", "false")
+
+	// PasswordResetTokenTTLMinutes is how long the password reset links can be used.
+	PasswordResetTokenTTLMinutes = NewSetting("password-reset-token-ttl-minutes", "30")
+
+	// PasswordResetMaxRequestsPerHour is how many password resets can be requested per hour for a username, and from
+	// a client address.
+	PasswordResetMaxRequestsPerHour = NewSetting("password-reset-max-requests-per-hour", "5")
+
+	// SMTPServer is the host:port of the SMTP server sending the emails of Rancher. Its credentials are read from the
+	// username and password fields of the smtp-credentials secret of the cattle-global-data namespace, if it exists.
+	SMTPServer = NewSetting("smtp-server", "")
+
+	// SMTPFrom is the sender address of the emails of Rancher.
+	SMTPFrom = NewSetting("smtp-from", "")
+
+	// SMTPTLS is how the connections to smtp-server are secured: "starttls" upgrades them with STARTTLS when the
+	// server supports it, "starttls-required" fails when it doesn't, and "tls" connects with TLS from the start,
+	// usually on port 465.
+	SMTPTLS = NewSetting("smtp-tls", "starttls")
+
+	// SMTPCACerts are the PEM encoded CA certificates trusted for smtp-server, in addition to the system ones.
+	SMTPCACerts = NewSetting("smtp-ca-certs", "")
+
+	// SMTPTemplates overrides the templates of the emails of Rancher. It's a JSON object mapping the names of the
+	// messages, password-reset, email-verification, new-device-login, mfa-change, token-expiring, break-glass-activated
+	// and break-glass-ended, to an object with the subject and body templates, in the text/template syntax.
+	SMTPTemplates = NewSetting("smtp-templates", "")
+
+	// EmailNotifications is the comma separated list of the auth events the users with an email address are notified
+	// of: new-device-login, mfa-change, token-expiring, login-anomaly and failed-logins. It requires smtp-server and
+	// smtp-from.
+	EmailNotifications = NewSetting("email-notifications", "new-device-login,mfa-change,token-expiring,login-anomaly")
+
+	// LoginAnomalyDetection enables the detection of the unusual logins: the first login of a user from a country, and
+	// the logins of a user from distant networks within login-anomaly-window-minutes. They are recorded as auth events
+	// and notified to the user with login-anomaly.
+	LoginAnomalyDetection = NewSetting("login-anomaly-detection", "false")
+
+	// LoginAnomalyWindowMinutes is how close two logins of a user from distant networks must be, in minutes, to be
+	// unusual.
+	LoginAnomalyWindowMinutes = NewSetting("login-anomaly-window-minutes", "30")
+
+	// LoginAnomalyDistanceKm is how far apart, in kilometers, the networks of two logins must be to be distant. The
+	// networks whose coordinates are unknown are distant when they are in different countries.
+	LoginAnomalyDistanceKm = NewSetting("login-anomaly-distance-km", "500")
+
+	// LoginGeoLookupURL is the URL of the service locating the addresses of the logins for the detection of the
+	// unusual logins, with {ip} replaced by the address, e.g. https://geo.example.com/json/{ip}. It must respond a JSON
+	// object with the country, latitude and longitude fields. The logins aren't located if it's empty.
+	LoginGeoLookupURL = NewSetting("login-geo-lookup-url", "")
+
+	// AuthTracingOTLPEndpoint is the host:port of the OTLP gRPC collector the traces of the logins and token
+	// validations are exported to, e.g. otel-collector.cattle-monitoring-system:4317. They aren't traced if it's empty.
+	AuthTracingOTLPEndpoint = NewSetting("auth-tracing-otlp-endpoint", "")
+
+	// AuthTracingOTLPInsecure exports the traces to the OTLP collector without TLS.
+	AuthTracingOTLPInsecure = NewSetting("auth-tracing-otlp-insecure", "false")
+
+	// AuthTracingSampleRatio is the ratio, between 0 and 1, of the logins and token validations traced, unless the
+	// caller's trace context says otherwise.
+	AuthTracingSampleRatio = NewSetting("auth-tracing-sample-ratio", "1")
+
+	// BreakGlassNotificationRecipients is the comma separated list of the email addresses notified of the activations of
+	// the break glass accounts and of their end. It requires smtp-server and smtp-from.
+	BreakGlassNotificationRecipients = NewSetting("break-glass-notification-recipients", "")
+
+	// BreakGlassAllowedGlobalRoles is the comma separated list of the global roles the break glass accounts can grant.
+	BreakGlassAllowedGlobalRoles = NewSetting("break-glass-allowed-global-roles", "admin")
+
+	// TokenExpiryNoticeHours is how many hours before their API keys expire the users are notified of it.
+	TokenExpiryNoticeHours = NewSetting("token-expiry-notice-hours", "72")
+
+	// EmailVerificationRequired requires the local users created with an email address to verify it with a link sent
+	// to them before they can log in, when set to "true". Only the verified addresses are then used by the password
+	// resets and the notifications. It requires smtp-server and smtp-from.
+	EmailVerificationRequired = NewSetting("email-verification-required", "false")
+
+	// EmailVerificationTokenTTLHours is how long the email verification links can be used.
+	EmailVerificationTokenTTLHours = NewSetting("email-verification-token-ttl-hours", "24")
+
+	// ServiceKeyRotationDays is the default and maximum number of days after which the project service keys must be
+	// rotated. The tokens of the keys which aren't rotated in time expire service-key-rotation-grace-hours later.
+	ServiceKeyRotationDays = NewSetting("service-key-rotation-days", "90")
+
+	// ServiceKeyRotationGraceHours is how long the previous token of a project service key keeps working once the key
+	// is rotated, so that automation can switch to the new one.
+	ServiceKeyRotationGraceHours = NewSetting("service-key-rotation-grace-hours", "24")
+
 	// ChartDefaultURL represents the default URL for the system charts repo. It should only be set for test or
 	// debug purposes.
 	ChartDefaultURL = NewSetting("chart-default-url", "https://git.rancher.io/")
@@ -186,6 +565,51 @@
 	// The value should be a valid cron expression e.g. "0 * * * *" (every hour)
 	UserRetentionCron = NewSetting("user-retention-cron", "")
 
+	// FlagInactiveUserAfter is the duration a user can be inactive after which it's flagged by the user retention process,
+	// with the cattle.io/inactive label, ahead of being disabled or deleted.
+	// The value should be expressed in valid time.Duration units e.g. "720h". An empty string or a zero value means users aren't flagged.
+	FlagInactiveUserAfter = NewSetting("flag-inactive-user-after", "")
+
+	// UserRetentionExclusions is a comma separated list of the users which aren't subject to the user retention process.
+	// Users are matched by name, username or principal ID, and by the principal IDs of their groups.
+	UserRetentionExclusions = NewSetting("user-retention-exclusions", "")
+
+	// UserAttributeLabels is a comma separated list of auth provider attributes to propagate as labels of the users
+	// e.g. "department=example.com/department,costCenter=example.com/cost-center". Each entry maps the name of
+	// the attribute, as reported by the provider, to a label key. An empty string means the feature is disabled.
+	UserAttributeLabels = NewSetting("user-attribute-labels", "")
+
+	// IdPDeprovisioningCron determines how often users are reconciled against the identity providers that can look them up.
+	// The value should be a valid cron expression e.g. "0 * * * *" (every hour). An empty string means the feature is disabled.
+	IdPDeprovisioningCron = NewSetting("idp-deprovisioning-cron", "")
+
+	// IdPDeprovisioningAction is the action applied to users the identity provider no longer knows.
+	// Valid values are "warn", "deactivate" and "delete". An empty string means "warn".
+	IdPDeprovisioningAction = NewSetting("idp-deprovisioning-action", "warn")
+
+	// IdPDeprovisioningGracePeriod is the duration a user must be unknown to the identity provider before being deactivated or deleted.
+	// The value should be expressed in valid time.Duration units e.g. "72h". An empty string means no grace period.
+	IdPDeprovisioningGracePeriod = NewSetting("idp-deprovisioning-grace-period", "")
+
+	// GroupDisplayNameRefreshCron determines how often the display names of group principals bound to roles are refreshed
+	// from the identity providers that can look them up.
+	// The value should be a valid cron expression e.g. "0 * * * *" (every hour). An empty string means the feature is disabled.
+	GroupDisplayNameRefreshCron = NewSetting("group-display-name-refresh-cron", "")
+
+	// GroupSyncCron determines how often Group objects are synced from the group principals of the users and role bindings.
+	// The value should be a valid cron expression e.g. "0 * * * *" (every hour). An empty string means the feature is disabled.
+	GroupSyncCron = NewSetting("group-sync-cron", "")
+
+	// DirectoryAttributeMembershipSyncCron determines how often the project memberships granted by the directory attribute
+	// rules of the projects are synced with the users of the identity providers.
+	// The value should be a valid cron expression e.g. "0 * * * *" (every hour). An empty string means the feature is disabled.
+	DirectoryAttributeMembershipSyncCron = NewSetting("directory-attribute-membership-sync-cron", "")
+
+	// PrincipalMetadataSyncCron determines how often the cached display metadata of the principals bound to roles, which
+	// the principals of the binding listings are served from, is synced with the identity providers.
+	// The value should be a valid cron expression e.g. "*/30 * * * *" (every 30 minutes). An empty string means the feature is disabled.
+	PrincipalMetadataSyncCron = NewSetting("principal-metadata-sync-cron", "")
+
 	// ConfigMapName name of the configmap that stores rancher configuration information.
 	// Deprecated: to be removed in 2.8.0
 	ConfigMapName = NewSetting("config-map-name", "rancher-config")
@@ -231,6 +231,11 @@ func readConfig(secrets corev1controllers.SecretController, acmeDomains []string
 	if err != nil {
 		return "", noCACerts, nil, err
 	}
+	if settings.TLSRequestClientCertificates.Get() == "true" {
+		// Client certificates are verified by the client certificate auth provider, not in the handshake, so that
+		// requests without one or with one of another CA aren't rejected.
+		tlsConfig.ClientAuth = tls.RequestClientCert
+	}
 
 	expiration, err := strconv.Atoi(settings.RotateCertsIfExpiringInDays.Get())
 	if err != nil {